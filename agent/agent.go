@@ -78,6 +78,25 @@ func (s APIHostPortsSetter) SetAPIHostPorts(servers []network.HostPorts) error {
 	})
 }
 
+// CACertSetter trivially wraps an Agent to implement
+// worker/cacertupdater/CACertSetter.
+type CACertSetter struct {
+	Agent
+}
+
+// CACert is part of the CACertSetter interface.
+func (s CACertSetter) CACert() string {
+	return s.CurrentConfig().CACert()
+}
+
+// SetCACert is part of the CACertSetter interface.
+func (s CACertSetter) SetCACert(caCert string) error {
+	return s.ChangeConfig(func(c ConfigSetter) error {
+		c.SetCACert(caCert)
+		return nil
+	})
+}
+
 // Paths holds the directory paths used by the agent.
 type Paths struct {
 	// DataDir is the data directory where each agent has a subdirectory
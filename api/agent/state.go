@@ -12,11 +12,13 @@ import (
 	"github.com/juju/juju/api/base"
 	"github.com/juju/juju/api/common"
 	"github.com/juju/juju/api/common/cloudspec"
+	apiwatcher "github.com/juju/juju/api/watcher"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/controller"
 	"github.com/juju/juju/core/instance"
 	"github.com/juju/juju/core/life"
 	"github.com/juju/juju/core/model"
+	"github.com/juju/juju/core/watcher"
 )
 
 // State provides access to an agent's view of the state.
@@ -79,6 +81,23 @@ func (st *State) StateServingInfo() (controller.StateServingInfo, error) {
 	}, nil
 }
 
+// WatchControllerConfig returns a NotifyWatcher that notifies when the
+// controller's configuration changes.
+func (st *State) WatchControllerConfig() (watcher.NotifyWatcher, error) {
+	if st.facade.BestAPIVersion() < 3 {
+		return nil, errors.NotSupportedf("watching controller config")
+	}
+	var result params.NotifyWatchResult
+	err := st.facade.FacadeCall("WatchControllerConfig", nil, &result)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return apiwatcher.NewNotifyWatcher(st.facade.RawAPICaller(), result), nil
+}
+
 // IsMaster reports whether the connected machine
 // agent lives at the same network address as the primary
 // mongo server for the replica set.
@@ -73,6 +73,14 @@ type state struct {
 	conn   jsoncodec.JSONConn
 	clock  clock.Clock
 
+	// codec holds the codec used by client. It is nil if the
+	// connection was not made by Open.
+	codec *jsoncodec.Codec
+
+	// codecs holds the message encodings other than JSON that
+	// will be requested at login.
+	codecs []string
+
 	// addr is the address used to connect to the API server.
 	addr string
 
@@ -134,12 +142,18 @@ type state struct {
 	// access it safely.
 	loggedIn int32
 
-	// tag, password, macaroons and nonce hold the cached login
-	// credentials. These are only valid if loggedIn is 1.
+	// tag, password, macaroons, nonce, idToken and token hold the
+	// cached login credentials. These are only valid if loggedIn is 1.
 	tag       string
 	password  string
 	macaroons []macaroon.Slice
 	nonce     string
+	idToken   string
+	token     string
+
+	// oidcLogin is used to obtain an ID token when the controller
+	// requires an OpenID Connect login.
+	oidcLogin OIDCLoginFunc
 
 	// serverRootAddress holds the cached API server address and port used
 	// to login.
@@ -213,7 +227,8 @@ func Open(info *Info, opts DialOpts) (Connection, error) {
 		return nil, errors.Trace(err)
 	}
 
-	client := rpc.NewConn(jsoncodec.New(dialResult.conn), nil)
+	codec := jsoncodec.New(dialResult.conn)
+	client := rpc.NewConn(codec, nil)
 	client.Start(ctx)
 
 	bakeryClient := opts.BakeryClient
@@ -241,6 +256,8 @@ func Open(info *Info, opts DialOpts) (Connection, error) {
 		client: client,
 		conn:   dialResult.conn,
 		clock:  opts.Clock,
+		codec:  codec,
+		codecs: opts.Codecs,
 		addr:   dialResult.addr,
 		ipAddr: dialResult.ipAddr,
 		cookieURL: &url.URL{
@@ -259,6 +276,9 @@ func Open(info *Info, opts DialOpts) (Connection, error) {
 		password:     info.Password,
 		macaroons:    info.Macaroons,
 		nonce:        info.Nonce,
+		idToken:      info.IDToken,
+		token:        info.Token,
+		oidcLogin:    opts.OIDCLogin,
 		tlsConfig:    dialResult.tlsConfig,
 		bakeryClient: bakeryClient,
 		modelTag:     info.ModelTag,
@@ -416,6 +436,9 @@ func (st *state) connectStream(path string, attrs url.Values, extraHeaders http.
 		requestHeader = utils.BasicAuthHeader(st.tag, st.password)
 	} else {
 		requestHeader = make(http.Header)
+		if bearer := bearerToken(st.token, st.idToken); bearer != "" {
+			requestHeader.Set("Authorization", "Bearer "+bearer)
+		}
 	}
 	requestHeader.Set("Origin", "http://localhost/")
 	if st.nonce != "" {
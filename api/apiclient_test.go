@@ -18,6 +18,7 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/gorilla/websocket"
 	"github.com/juju/clock"
 	"github.com/juju/clock/testclock"
 	"github.com/juju/errors"
@@ -1234,6 +1235,233 @@ func (s *apiclientSuite) TestLoginCapturesCLIArgs(c *gc.C) {
 	c.Assert(request.CLIArgs, gc.Equals, `this is "the test" command`)
 }
 
+type oidcLoginSuite struct {
+	jtesting.BaseSuite
+}
+
+var _ = gc.Suite(&oidcLoginSuite{})
+
+var oidcLoginRequired = &params.LoginResult{
+	OIDCLoginRequired: &params.OIDCLoginInfo{
+		Issuer:   "https://issuer.example.com",
+		ClientID: "juju",
+		Scopes:   []string{"profile", "email"},
+		Reason:   "OpenID Connect login required",
+	},
+}
+
+var oidcLoginSucceeded = &params.LoginResult{
+	ControllerTag: "controller-" + jtesting.ControllerTag.Id(),
+	ServerVersion: "2.8.0",
+	UserInfo: &params.AuthUserInfo{
+		Identity:         "user-bob@example.com",
+		ControllerAccess: "login",
+	},
+}
+
+func (s *oidcLoginSuite) newState(conn *loginSequenceConnection, login api.OIDCLoginFunc) api.Connection {
+	broken := make(chan struct{})
+	close(broken)
+	return api.NewTestingState(api.TestingStateParams{
+		RPCConnection: conn,
+		Clock:         &fakeClock{},
+		Address:       "localhost:1234",
+		Broken:        broken,
+		Closed:        make(chan struct{}),
+		OIDCLogin:     login,
+	})
+}
+
+func (s *oidcLoginSuite) TestLoginObtainsIDToken(c *gc.C) {
+	conn := &loginSequenceConnection{
+		responses: []*params.LoginResult{oidcLoginRequired, oidcLoginSucceeded},
+	}
+	var loginInfo params.OIDCLoginInfo
+	st := s.newState(conn, func(ctx context.Context, info params.OIDCLoginInfo) (string, error) {
+		loginInfo = info
+		return "id-token", nil
+	})
+	err := st.Login(nil, "", "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(loginInfo, jc.DeepEquals, *oidcLoginRequired.OIDCLoginRequired)
+	c.Assert(st.AuthTag(), gc.Equals, names.NewUserTag("bob@example.com"))
+
+	c.Assert(conn.requests, gc.HasLen, 2)
+	c.Assert(conn.requests[0].IDToken, gc.Equals, "")
+	c.Assert(conn.requests[1].IDToken, gc.Equals, "id-token")
+}
+
+func (s *oidcLoginSuite) TestLoginWithoutOIDCLogin(c *gc.C) {
+	conn := &loginSequenceConnection{
+		responses: []*params.LoginResult{oidcLoginRequired},
+	}
+	st := s.newState(conn, nil)
+	err := st.Login(nil, "", "", nil)
+	c.Assert(err, gc.ErrorMatches, `OpenID Connect login required \(use "juju login" to log in\)`)
+	c.Assert(err, jc.Satisfies, errors.IsUnauthorized)
+}
+
+func (s *oidcLoginSuite) TestLoginOIDCLoginFails(c *gc.C) {
+	conn := &loginSequenceConnection{
+		responses: []*params.LoginResult{oidcLoginRequired},
+	}
+	st := s.newState(conn, func(ctx context.Context, info params.OIDCLoginInfo) (string, error) {
+		return "", errors.New("device login was denied")
+	})
+	err := st.Login(nil, "", "", nil)
+	c.Assert(err, gc.ErrorMatches, `cannot log in with OpenID Connect provider "https://issuer.example.com": device login was denied`)
+}
+
+func (s *oidcLoginSuite) TestLoginIDTokenRejected(c *gc.C) {
+	conn := &loginSequenceConnection{
+		responses: []*params.LoginResult{oidcLoginRequired, oidcLoginRequired},
+	}
+	st := s.newState(conn, func(ctx context.Context, info params.OIDCLoginInfo) (string, error) {
+		return "id-token", nil
+	})
+	err := st.Login(nil, "", "", nil)
+	c.Assert(err, gc.ErrorMatches, "login with ID token failed: OpenID Connect login required")
+}
+
+// loginSequenceConnection is an RPC connection which responds to
+// successive login requests with the given results.
+type loginSequenceConnection struct {
+	fakeRPCConnection
+	requests  []params.LoginRequest
+	responses []*params.LoginResult
+}
+
+func (f *loginSequenceConnection) Call(req rpc.Request, args, response interface{}) error {
+	f.requests = append(f.requests, *args.(*params.LoginRequest))
+	if len(f.responses) == 0 {
+		return errors.New("unexpected login")
+	}
+	*response.(*params.LoginResult) = *f.responses[0]
+	f.responses = f.responses[1:]
+	return nil
+}
+
+type codecSuite struct {
+	jtesting.BaseSuite
+}
+
+var _ = gc.Suite(&codecSuite{})
+
+func (s *codecSuite) TestOpenNegotiatesCodec(c *gc.C) {
+	for i, test := range []struct {
+		about        string
+		requested    []string
+		chosen       string
+		expectBinary bool
+		expectError  string
+	}{{
+		about: "no codecs requested",
+	}, {
+		about:     "codec not supported by the controller",
+		requested: []string{"cbor"},
+	}, {
+		about:        "codec chosen by the controller",
+		requested:    []string{"unknown", "cbor"},
+		chosen:       "cbor",
+		expectBinary: true,
+	}, {
+		about:       "unrequested codec",
+		chosen:      "cbor",
+		expectError: `controller chose unrequested codec "cbor"`,
+	}} {
+		c.Logf("test %d: %s", i, test.about)
+		srv := apiservertesting.NewAPIServer(func(modelUUID string) interface{} {
+			return &codecAPI{chosen: test.chosen}
+		})
+		var sent int32
+		conn, err := api.Open(&api.Info{
+			Addrs:    srv.Addrs,
+			CACert:   jtesting.CACert,
+			ModelTag: jtesting.ModelTag,
+		}, api.DialOpts{
+			Codecs:        test.requested,
+			DialWebsocket: countingDialWebsocket(&sent),
+		})
+		if test.expectError != "" {
+			c.Check(err, gc.ErrorMatches, test.expectError)
+			srv.Close()
+			continue
+		}
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(atomic.LoadInt32(&sent), gc.Equals, int32(0))
+
+		args := params.Entities{Entities: []params.Entity{{Tag: "machine-0"}}}
+		var result params.Entities
+		err = conn.APICall("Echo", 1, "", "Echo", args, &result)
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(result, jc.DeepEquals, args)
+		if test.expectBinary {
+			c.Check(atomic.LoadInt32(&sent), gc.Equals, int32(1))
+		} else {
+			c.Check(atomic.LoadInt32(&sent), gc.Equals, int32(0))
+		}
+		conn.Close()
+		srv.Close()
+	}
+}
+
+// countingDialWebsocket returns a DialOpts.DialWebsocket function which
+// counts the binary messages sent on its connections.
+func countingDialWebsocket(sent *int32) func(context.Context, string, *tls.Config, string) (jsoncodec.JSONConn, error) {
+	return func(ctx context.Context, urlStr string, tlsConfig *tls.Config, ipAddr string) (jsoncodec.JSONConn, error) {
+		dialer := &websocket.Dialer{TLSClientConfig: tlsConfig}
+		conn, _, err := dialer.DialContext(ctx, urlStr, nil)
+		if err != nil {
+			return nil, err
+		}
+		return &countingConn{
+			BinaryConn: jsoncodec.NewWebsocketConn(conn).(jsoncodec.BinaryConn),
+			sent:       sent,
+		}, nil
+	}
+}
+
+type countingConn struct {
+	jsoncodec.BinaryConn
+	sent *int32
+}
+
+func (c *countingConn) SendBinary(data []byte) error {
+	atomic.AddInt32(c.sent, 1)
+	return c.BinaryConn.SendBinary(data)
+}
+
+type codecAPI struct {
+	chosen string
+}
+
+func (r *codecAPI) Admin(id string) (*codecAPIAdmin, error) {
+	return &codecAPIAdmin{r}, nil
+}
+
+func (r *codecAPI) Echo(id string) (*codecAPIEcho, error) {
+	return &codecAPIEcho{}, nil
+}
+
+type codecAPIAdmin struct {
+	r *codecAPI
+}
+
+func (a *codecAPIAdmin) Login(req params.LoginRequest) (params.LoginResult, error) {
+	return params.LoginResult{
+		ControllerTag: jtesting.ControllerTag.String(),
+		ModelTag:      jtesting.ModelTag.String(),
+		ServerVersion: "2.9.0",
+		Codec:         a.r.chosen,
+	}, nil
+}
+
+type codecAPIEcho struct{}
+
+func (*codecAPIEcho) Echo(args params.Entities) params.Entities {
+	return args
+}
+
 type clientDNSNameSuite struct {
 	jjtesting.JujuConnSuite
 }
@@ -0,0 +1,134 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package apitokens provides access to the APITokens facade, which
+// mints, lists and revokes the API tokens automation users log in
+// with.
+package apitokens
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/permission"
+)
+
+const apiTokensFacade = "APITokens"
+
+// Token describes an API token.
+type Token struct {
+	ID        string
+	Owner     string
+	ModelUUID string
+	Access    permission.Access
+	CreatedBy string
+	Created   time.Time
+	Expires   time.Time
+}
+
+// Client allows access to the API tokens API end point.
+type Client struct {
+	base.ClientFacade
+	facade base.FacadeCaller
+}
+
+// NewClient creates a new client for accessing the API tokens API.
+func NewClient(st base.APICallCloser) *Client {
+	frontend, backend := base.NewClientFacade(st, apiTokensFacade)
+	return &Client{
+		ClientFacade: frontend,
+		facade:       backend,
+	}
+}
+
+// AddToken mints an API token allowing the user to log in to the model
+// with the given UUID, with at most the given access, until the token
+// expires. If user is empty, the token is owned by the caller. The
+// returned secret cannot be retrieved again.
+func (c *Client) AddToken(modelUUID, user string, access permission.Access, expires time.Time) (Token, string, error) {
+	if !names.IsValidModel(modelUUID) {
+		return Token{}, "", errors.NotValidf("model UUID %q", modelUUID)
+	}
+	arg := params.AddAPIToken{
+		ModelTag: names.NewModelTag(modelUUID).String(),
+		Access:   string(access),
+		Expires:  expires,
+	}
+	if user != "" {
+		if !names.IsValidUser(user) {
+			return Token{}, "", errors.NotValidf("user name %q", user)
+		}
+		arg.UserTag = names.NewUserTag(user).String()
+	}
+	args := params.AddAPITokens{Tokens: []params.AddAPIToken{arg}}
+	var results params.AddAPITokenResults
+	if err := c.facade.FacadeCall("AddAPITokens", args, &results); err != nil {
+		return Token{}, "", errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return Token{}, "", errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return Token{}, "", errors.Trace(result.Error)
+	}
+	token, err := tokenFromParams(result.Token)
+	if err != nil {
+		return Token{}, "", errors.Trace(err)
+	}
+	return token, result.Secret, nil
+}
+
+// ListTokens returns the API tokens the caller may manage.
+func (c *Client) ListTokens() ([]Token, error) {
+	var result params.APITokens
+	if err := c.facade.FacadeCall("ListAPITokens", nil, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	tokens := make([]Token, len(result.Tokens))
+	for i, token := range result.Tokens {
+		var err error
+		if tokens[i], err = tokenFromParams(token); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	return tokens, nil
+}
+
+// RevokeToken revokes the API token with the given ID.
+func (c *Client) RevokeToken(id string) error {
+	args := params.APITokenIDs{IDs: []string{id}}
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall("RevokeAPITokens", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
+
+func tokenFromParams(token params.APIToken) (Token, error) {
+	owner, err := names.ParseUserTag(token.OwnerTag)
+	if err != nil {
+		return Token{}, errors.Trace(err)
+	}
+	model, err := names.ParseModelTag(token.ModelTag)
+	if err != nil {
+		return Token{}, errors.Trace(err)
+	}
+	createdBy, err := names.ParseUserTag(token.CreatedByTag)
+	if err != nil {
+		return Token{}, errors.Trace(err)
+	}
+	return Token{
+		ID:        token.ID,
+		Owner:     owner.Id(),
+		ModelUUID: model.Id(),
+		Access:    permission.Access(token.Access),
+		CreatedBy: createdBy.Id(),
+		Created:   token.Created,
+		Expires:   token.Expires,
+	}, nil
+}
@@ -0,0 +1,121 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apitokens_test
+
+import (
+	"time"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api/apitokens"
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/permission"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type apiTokensSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&apiTokensSuite{})
+
+var (
+	expires     = time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	tokenParams = params.APIToken{
+		ID:           "0a1b",
+		OwnerTag:     "user-bot",
+		ModelTag:     coretesting.ModelTag.String(),
+		Access:       "read",
+		CreatedByTag: "user-admin",
+		Expires:      expires,
+	}
+	token = apitokens.Token{
+		ID:        "0a1b",
+		Owner:     "bot",
+		ModelUUID: coretesting.ModelTag.Id(),
+		Access:    permission.ReadAccess,
+		CreatedBy: "admin",
+		Expires:   expires,
+	}
+)
+
+func (s *apiTokensSuite) TestAddToken(c *gc.C) {
+	var called bool
+	client := apitokens.NewClient(basetesting.APICallerFunc(
+		func(objType string, version int, id, request string, a, result interface{}) error {
+			called = true
+			c.Check(objType, gc.Equals, "APITokens")
+			c.Check(request, gc.Equals, "AddAPITokens")
+			c.Check(a, jc.DeepEquals, params.AddAPITokens{
+				Tokens: []params.AddAPIToken{{
+					ModelTag: coretesting.ModelTag.String(),
+					UserTag:  "user-bot",
+					Access:   "read",
+					Expires:  expires,
+				}},
+			})
+			*(result.(*params.AddAPITokenResults)) = params.AddAPITokenResults{
+				Results: []params.AddAPITokenResult{{
+					Token:  tokenParams,
+					Secret: "jujutoken-0a1b.key",
+				}},
+			}
+			return nil
+		},
+	))
+	result, secret, err := client.AddToken(coretesting.ModelTag.Id(), "bot", permission.ReadAccess, expires)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+	c.Assert(result, jc.DeepEquals, token)
+	c.Assert(secret, gc.Equals, "jujutoken-0a1b.key")
+}
+
+func (s *apiTokensSuite) TestAddTokenError(c *gc.C) {
+	client := apitokens.NewClient(basetesting.APICallerFunc(
+		func(objType string, version int, id, request string, a, result interface{}) error {
+			*(result.(*params.AddAPITokenResults)) = params.AddAPITokenResults{
+				Results: []params.AddAPITokenResult{{Error: &params.Error{Message: "boom"}}},
+			}
+			return nil
+		},
+	))
+	_, _, err := client.AddToken(coretesting.ModelTag.Id(), "", permission.ReadAccess, expires)
+	c.Assert(err, gc.ErrorMatches, "boom")
+
+	_, _, err = client.AddToken("foo", "", permission.ReadAccess, expires)
+	c.Assert(err, gc.ErrorMatches, `model UUID "foo" not valid`)
+	_, _, err = client.AddToken(coretesting.ModelTag.Id(), "bot!", permission.ReadAccess, expires)
+	c.Assert(err, gc.ErrorMatches, `user name "bot!" not valid`)
+}
+
+func (s *apiTokensSuite) TestListTokens(c *gc.C) {
+	client := apitokens.NewClient(basetesting.APICallerFunc(
+		func(objType string, version int, id, request string, a, result interface{}) error {
+			c.Check(request, gc.Equals, "ListAPITokens")
+			*(result.(*params.APITokens)) = params.APITokens{
+				Tokens: []params.APIToken{tokenParams},
+			}
+			return nil
+		},
+	))
+	result, err := client.ListTokens()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, []apitokens.Token{token})
+}
+
+func (s *apiTokensSuite) TestRevokeToken(c *gc.C) {
+	client := apitokens.NewClient(basetesting.APICallerFunc(
+		func(objType string, version int, id, request string, a, result interface{}) error {
+			c.Check(request, gc.Equals, "RevokeAPITokens")
+			c.Check(a, jc.DeepEquals, params.APITokenIDs{IDs: []string{"0a1b"}})
+			*(result.(*params.ErrorResults)) = params.ErrorResults{Results: []params.ErrorResult{{}}}
+			return nil
+		},
+	))
+	err := client.RevokeToken("0a1b")
+	c.Assert(err, jc.ErrorIsNil)
+}
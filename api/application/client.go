@@ -725,6 +725,42 @@ func (c *Client) ScaleApplication(in ScaleApplicationParams) (params.ScaleApplic
 	return results.Results[0], nil
 }
 
+// AdoptWorkloadParams contains parameters for the AdoptWorkload API method.
+type AdoptWorkloadParams struct {
+	// ApplicationName is the application adopting the workload.
+	ApplicationName string
+
+	// DeploymentType is the type of the existing workload,
+	// either "stateless" or "stateful".
+	DeploymentType string
+
+	// WorkloadName is the name of the existing workload.
+	WorkloadName string
+}
+
+// AdoptWorkload brings an existing workload, which was not created by Juju,
+// under the management of the specified application.
+func (c *Client) AdoptWorkload(in AdoptWorkloadParams) error {
+	if c.BestAPIVersion() < 12 {
+		return errors.NotSupportedf("AdoptWorkloads not supported by this version of Juju")
+	}
+	if !names.IsValidApplication(in.ApplicationName) {
+		return errors.NotValidf("application %q", in.ApplicationName)
+	}
+	args := params.AdoptWorkloadsArgs{
+		Workloads: []params.AdoptWorkloadArg{{
+			ApplicationTag: names.NewApplicationTag(in.ApplicationName).String(),
+			DeploymentType: in.DeploymentType,
+			WorkloadName:   in.WorkloadName,
+		}},
+	}
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall("AdoptWorkloads", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
+
 // GetConstraints returns the constraints for the given applications.
 func (c *Client) GetConstraints(applications ...string) ([]constraints.Value, error) {
 	var allConstraints []constraints.Value
@@ -770,6 +806,21 @@ func (c *Client) SetConstraints(application string, constraints constraints.Valu
 	return c.facade.FacadeCall("SetConstraints", args, nil)
 }
 
+// SetBranchConstraints specifies the constraints for the given application
+// under the given branch. Machines provisioned for the application take
+// these constraints once the branch is committed.
+func (c *Client) SetBranchConstraints(branchName, application string, constraints constraints.Value) error {
+	if c.BestAPIVersion() < 13 {
+		return errors.New("this controller does not support setting constraints in a branch")
+	}
+	args := params.SetConstraints{
+		ApplicationName: application,
+		Constraints:     constraints,
+		Generation:      branchName,
+	}
+	return c.facade.FacadeCall("SetConstraints", args, nil)
+}
+
 // Expose changes the juju-managed firewall to expose any ports that
 // were also explicitly marked by units as open.
 func (c *Client) Expose(application string) error {
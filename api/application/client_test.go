@@ -306,6 +306,35 @@ func (s *applicationSuite) TestSetCharm(c *gc.C) {
 	c.Assert(called, jc.IsTrue)
 }
 
+func (s *applicationSuite) TestSetBranchConstraints(c *gc.C) {
+	var called bool
+	client := application.NewClient(basetesting.BestVersionCaller{
+		APICallerFunc: func(objType string, version int, id, request string, a, response interface{}) error {
+			called = true
+			c.Assert(request, gc.Equals, "SetConstraints")
+			c.Assert(a, jc.DeepEquals, params.SetConstraints{
+				ApplicationName: "application",
+				Constraints:     constraints.MustParse("mem=8G"),
+				Generation:      newBranchName,
+			})
+			return nil
+		},
+		BestVersion: 13,
+	})
+	err := client.SetBranchConstraints(newBranchName, "application", constraints.MustParse("mem=8G"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+}
+
+func (s *applicationSuite) TestSetBranchConstraintsNotSupported(c *gc.C) {
+	client := newClient(func(objType string, version int, id, request string, a, response interface{}) error {
+		c.Fatalf("unexpected call to %s", request)
+		return nil
+	})
+	err := client.SetBranchConstraints(newBranchName, "application", constraints.MustParse("mem=8G"))
+	c.Assert(err, gc.ErrorMatches, "this controller does not support setting constraints in a branch")
+}
+
 func (s *applicationSuite) TestDestroyDeprecated(c *gc.C) {
 	var called bool
 	client := newClient(func(objType string, version int, id, request string, a, response interface{}) error {
@@ -1332,6 +1361,51 @@ func (s *applicationSuite) TestChangeScaleApplication(c *gc.C) {
 	})
 }
 
+func (s *applicationSuite) TestAdoptWorkload(c *gc.C) {
+	called := false
+	apiCaller := basetesting.BestVersionCaller{
+		APICallerFunc: func(objType string, version int, id, request string, a, response interface{}) error {
+			called = true
+			c.Assert(request, gc.Equals, "AdoptWorkloads")
+			args, ok := a.(params.AdoptWorkloadsArgs)
+			c.Assert(ok, jc.IsTrue)
+			c.Assert(args, jc.DeepEquals, params.AdoptWorkloadsArgs{
+				Workloads: []params.AdoptWorkloadArg{{
+					ApplicationTag: "application-foo",
+					DeploymentType: "stateful",
+					WorkloadName:   "mariadb",
+				}}})
+
+			result, ok := response.(*params.ErrorResults)
+			c.Assert(ok, jc.IsTrue)
+			result.Results = []params.ErrorResult{{}}
+			return nil
+		},
+		BestVersion: 12,
+	}
+	client := application.NewClient(apiCaller)
+	err := client.AdoptWorkload(application.AdoptWorkloadParams{
+		ApplicationName: "foo",
+		DeploymentType:  "stateful",
+		WorkloadName:    "mariadb",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+}
+
+func (s *applicationSuite) TestAdoptWorkloadNotSupported(c *gc.C) {
+	client := newClient(func(objType string, version int, id, request string, a, response interface{}) error {
+		c.Fatalf("unexpected API call %q", request)
+		return nil
+	})
+	err := client.AdoptWorkload(application.AdoptWorkloadParams{
+		ApplicationName: "foo",
+		DeploymentType:  "stateful",
+		WorkloadName:    "mariadb",
+	})
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
 func (s *applicationSuite) TestScaleApplicationArity(c *gc.C) {
 	apiCaller := basetesting.APICallerFunc(
 		func(objType string, version int, id, request string, a, response interface{}) error {
@@ -33,6 +33,14 @@ func NewClient(st base.APICallCloser) *Client {
 
 // Offer prepares application's endpoints for consumption.
 func (c *Client) Offer(modelUUID, application string, endpoints []string, offerName string, desc string) ([]params.ErrorResult, error) {
+	return c.OfferWithLimits(modelUUID, application, endpoints, offerName, desc, crossmodel.OfferLimits{})
+}
+
+// OfferWithLimits prepares application's endpoints for consumption,
+// restricting how the offer may be consumed according to limits.
+func (c *Client) OfferWithLimits(
+	modelUUID, application string, endpoints []string, offerName string, desc string, limits crossmodel.OfferLimits,
+) ([]params.ErrorResult, error) {
 	// TODO(wallyworld) - support endpoint aliases
 	ep := make(map[string]string)
 	for _, name := range endpoints {
@@ -47,6 +55,16 @@ func (c *Client) Offer(modelUUID, application string, endpoints []string, offerN
 			OfferName:              offerName,
 		},
 	}
+	if !limits.IsZero() {
+		if c.BestAPIVersion() < 3 {
+			return nil, errors.NotSupportedf("offer limits on this version of Juju")
+		}
+		offers[0].Limits = &params.OfferLimits{
+			MaxConnections:           limits.MaxConnections,
+			AllowedConsumerModels:    limits.AllowedConsumerModels,
+			SettingsChangesPerMinute: limits.SettingsChangesPerMinute,
+		}
+	}
 	out := params.ErrorResults{}
 	if err := c.facade.FacadeCall("Offer", params.AddApplicationOffers{Offers: offers}, &out); err != nil {
 		return nil, errors.Trace(err)
@@ -118,6 +136,13 @@ func offerParamsToDetails(offer params.ApplicationOfferAdminDetails) (*crossmode
 		OfferURL:               offer.OfferURL,
 		Endpoints:              eps,
 	}
+	if offer.Limits != nil {
+		result.Limits = crossmodel.OfferLimits{
+			MaxConnections:           offer.Limits.MaxConnections,
+			AllowedConsumerModels:    offer.Limits.AllowedConsumerModels,
+			SettingsChangesPerMinute: offer.Limits.SettingsChangesPerMinute,
+		}
+	}
 	for _, oc := range offer.Connections {
 		modelTag, err := names.ParseModelTag(oc.SourceModelTag)
 		if err != nil {
@@ -78,6 +78,53 @@ func (s *crossmodelMockSuite) TestOffer(c *gc.C) {
 		})
 }
 
+func (s *crossmodelMockSuite) TestOfferWithLimits(c *gc.C) {
+	var called bool
+	apiCaller := basetesting.BestVersionCaller{
+		APICallerFunc: basetesting.APICallerFunc(
+			func(objType string,
+				version int,
+				id, request string,
+				a, result interface{},
+			) error {
+				called = true
+				c.Check(request, gc.Equals, "Offer")
+				args, ok := a.(params.AddApplicationOffers)
+				c.Assert(ok, jc.IsTrue)
+				c.Assert(args.Offers, gc.HasLen, 1)
+				c.Assert(args.Offers[0].Limits, jc.DeepEquals, &params.OfferLimits{
+					MaxConnections:           2,
+					SettingsChangesPerMinute: 10,
+				})
+				*(result.(*params.ErrorResults)) = params.ErrorResults{Results: []params.ErrorResult{{}}}
+				return nil
+			}),
+		BestVersion: 3,
+	}
+	client := applicationoffers.NewClient(apiCaller)
+	limits := jujucrossmodel.OfferLimits{MaxConnections: 2, SettingsChangesPerMinute: 10}
+	results, err := client.OfferWithLimits("uuid", "shared", []string{"db"}, "offer", "", limits)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(called, jc.IsTrue)
+}
+
+func (s *crossmodelMockSuite) TestOfferWithLimitsNotSupported(c *gc.C) {
+	apiCaller := basetesting.BestVersionCaller{
+		APICallerFunc: basetesting.APICallerFunc(
+			func(objType string, version int, id, request string, a, result interface{}) error {
+				c.Fatalf("unexpected API call")
+				return nil
+			}),
+		BestVersion: 2,
+	}
+	client := applicationoffers.NewClient(apiCaller)
+	limits := jujucrossmodel.OfferLimits{MaxConnections: 2}
+	_, err := client.OfferWithLimits("uuid", "shared", []string{"db"}, "offer", "", limits)
+	c.Assert(err, gc.ErrorMatches, "offer limits on this version of Juju not supported")
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
 func (s *crossmodelMockSuite) TestOfferFacadeCallError(c *gc.C) {
 	msg := "facade failure"
 	apiCaller := basetesting.APICallerFunc(
@@ -287,6 +334,7 @@ func (s *crossmodelMockSuite) TestShow(c *gc.C) {
 								IngressSubnets: []string{"10.0.0.0/8"},
 							},
 						},
+						Limits: &params.OfferLimits{MaxConnections: 2},
 					}},
 				}
 			}
@@ -315,6 +363,7 @@ func (s *crossmodelMockSuite) TestShow(c *gc.C) {
 				IngressSubnets: []string{"10.0.0.0/8"},
 			},
 		},
+		Limits: jujucrossmodel.OfferLimits{MaxConnections: 2},
 	})
 }
 
@@ -13,6 +13,8 @@ import (
 	"gopkg.in/httprequest.v1"
 	"gopkg.in/macaroon-bakery.v2/httpbakery"
 	"gopkg.in/macaroon-bakery.v2/httpbakery/form"
+
+	"github.com/juju/juju/apiserver/params"
 )
 
 const authMethod = "juju_userpass"
@@ -23,13 +25,23 @@ const authMethod = "juju_userpass"
 type Interactor struct {
 	username    string
 	getPassword func(string) (string, error)
+	getMFACode  func(string) (string, error)
 }
 
 // NewInteractor returns a new Interactor.
 func NewInteractor(username string, getPassword func(string) (string, error)) httpbakery.Interactor {
+	return NewMFAInteractor(username, getPassword, nil)
+}
+
+// NewMFAInteractor returns a new Interactor that, if the controller
+// requires a multi-factor authentication code for the user, calls
+// getMFACode to obtain one and logs in again. If getMFACode is nil,
+// users enrolled for multi-factor authentication cannot log in.
+func NewMFAInteractor(username string, getPassword, getMFACode func(string) (string, error)) httpbakery.Interactor {
 	return &Interactor{
 		username:    username,
 		getPassword: getPassword,
+		getMFACode:  getMFACode,
 	}
 }
 
@@ -67,7 +79,16 @@ func (i Interactor) Interact(ctx context.Context, client *httpbakery.Client, loc
 		},
 	}
 	var lresp form.LoginResponse
-	if err := httpReqClient.CallURL(ctx, schemaURL.String(), &lr, &lresp); err != nil {
+	err = httpReqClient.CallURL(ctx, schemaURL.String(), &lr, &lresp)
+	if isMFARequired(err) && i.getMFACode != nil {
+		code, codeErr := i.getMFACode(i.username)
+		if codeErr != nil {
+			return nil, errors.Trace(codeErr)
+		}
+		lr.Body.Form["mfa-code"] = code
+		err = httpReqClient.CallURL(ctx, schemaURL.String(), &lr, &lresp)
+	}
+	if err != nil {
 		return nil, errors.Annotate(err, "cannot submit form")
 	}
 	if lresp.Token == nil {
@@ -76,6 +97,13 @@ func (i Interactor) Interact(ctx context.Context, client *httpbakery.Client, loc
 	return lresp.Token, nil
 }
 
+// isMFARequired reports whether the error returned by the login form
+// says that a multi-factor authentication code is required.
+func isMFARequired(err error) bool {
+	remoteErr, ok := errors.Cause(err).(*httprequest.RemoteError)
+	return ok && remoteErr.Code == params.CodeMFARequired
+}
+
 // relativeURL returns newPath relative to an original URL.
 func relativeURL(base, new string) (*url.URL, error) {
 	if new == "" {
@@ -100,10 +128,24 @@ func (i *Interactor) LegacyInteract(ctx context.Context, client *httpbakery.Clie
 	}
 
 	// POST to the URL with username and password.
-	resp, err := client.PostForm(methodURL.String(), url.Values{
+	values := url.Values{
 		"user":     {i.username},
 		"password": {password},
-	})
+	}
+	err = legacyPostForm(client, methodURL, values)
+	if jsonError, ok := err.(*httpbakery.Error); ok && jsonError.Code == params.CodeMFARequired && i.getMFACode != nil {
+		code, codeErr := i.getMFACode(i.username)
+		if codeErr != nil {
+			return errors.Trace(codeErr)
+		}
+		values.Set("mfa-code", code)
+		err = legacyPostForm(client, methodURL, values)
+	}
+	return err
+}
+
+func legacyPostForm(client *httpbakery.Client, methodURL *url.URL, values url.Values) error {
+	resp, err := client.PostForm(methodURL.String(), values)
 	if err != nil {
 		return err
 	}
@@ -19,6 +19,7 @@ import (
 	"gopkg.in/macaroon-bakery.v2/httpbakery/form"
 
 	"github.com/juju/juju/api/authentication"
+	"github.com/juju/juju/apiserver/params"
 )
 
 type InteractorSuite struct {
@@ -152,6 +153,99 @@ func (s *InteractorSuite) TestInteractErrorResult(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, ".*bleh.*")
 }
 
+func (s *InteractorSuite) TestInteractMFACode(c *gc.C) {
+	v := authentication.NewMFAInteractor("bob", func(username string) (string, error) {
+		return "hunter2", nil
+	}, func(username string) (string, error) {
+		c.Assert(username, gc.Equals, "bob")
+		return "123456", nil
+	})
+	var codes []interface{}
+	s.handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reqParams := httprequest.Params{
+			Response: w,
+			Request:  r,
+			Context:  context.TODO(),
+		}
+		loginRequest := form.LoginRequest{}
+		err := httprequest.Unmarshal(reqParams, &loginRequest)
+		c.Assert(err, jc.ErrorIsNil)
+		code, ok := loginRequest.Body.Form["mfa-code"]
+		codes = append(codes, code)
+		if !ok {
+			httpbakery.WriteError(context.TODO(), w, &httpbakery.Error{
+				Code:    params.CodeMFARequired,
+				Message: "multi-factor authentication code required",
+			})
+			return
+		}
+		httprequest.WriteJSON(w, http.StatusOK, form.LoginResponse{
+			Token: &httpbakery.DischargeToken{
+				Kind:  "juju_userpass",
+				Value: []byte("token"),
+			},
+		})
+	})
+	info := form.InteractionInfo{
+		URL: s.server.URL,
+	}
+	infoData, err := json.Marshal(info)
+	c.Assert(err, jc.ErrorIsNil)
+	msgData := json.RawMessage(infoData)
+	token, err := v.Interact(context.TODO(), s.client, "", &httpbakery.Error{
+		Code: httpbakery.ErrInteractionRequired,
+		Info: &httpbakery.ErrorInfo{
+			InteractionMethods: map[string]*json.RawMessage{
+				"juju_userpass": &msgData,
+			},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(codes, jc.DeepEquals, []interface{}{nil, "123456"})
+	c.Assert(string(token.Value), gc.Equals, "token")
+}
+
+func (s *InteractorSuite) TestLegacyInteractMFACode(c *gc.C) {
+	v := authentication.NewMFAInteractor("bob", func(username string) (string, error) {
+		return "hunter2", nil
+	}, func(username string) (string, error) {
+		return "123456", nil
+	})
+	lv, ok := v.(httpbakery.LegacyInteractor)
+	c.Assert(ok, jc.IsTrue)
+	var codes []string
+	s.handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		code := r.Form.Get("mfa-code")
+		codes = append(codes, code)
+		if code == "" {
+			httpbakery.WriteError(context.TODO(), w, &httpbakery.Error{
+				Code:    params.CodeMFARequired,
+				Message: "multi-factor authentication code required",
+			})
+		}
+	})
+	err := lv.LegacyInteract(context.TODO(), s.client, "", mustParseURL(s.server.URL))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(codes, jc.DeepEquals, []string{"", "123456"})
+}
+
+func (s *InteractorSuite) TestInteractMFACodeNotSupported(c *gc.C) {
+	v := authentication.NewInteractor("bob", func(username string) (string, error) {
+		return "hunter2", nil
+	})
+	lv, ok := v.(httpbakery.LegacyInteractor)
+	c.Assert(ok, jc.IsTrue)
+	s.handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		httpbakery.WriteError(context.TODO(), w, &httpbakery.Error{
+			Code:    params.CodeMFARequired,
+			Message: "multi-factor authentication code required",
+		})
+	})
+	err := lv.LegacyInteract(context.TODO(), s.client, "", mustParseURL(s.server.URL))
+	c.Assert(err, gc.ErrorMatches, "multi-factor authentication code required")
+}
+
 func mustParseURL(s string) *url.URL {
 	u, err := url.Parse(s)
 	if err != nil {
@@ -0,0 +1,66 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package authentication
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/juju/clock"
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/oidc"
+)
+
+// OIDCDeviceLogin obtains ID tokens from a controller's OpenID Connect
+// provider using the device authorization grant, asking the user to
+// approve the login in a web browser, possibly on another device.
+type OIDCDeviceLogin struct {
+	// Writer is where the instructions for the user are written.
+	Writer io.Writer
+
+	// HTTPClient is used to talk to the provider. If it is nil,
+	// http.DefaultClient is used.
+	HTTPClient *http.Client
+
+	// Clock is used to wait between polls of the provider. If it
+	// is nil, the wall clock is used.
+	Clock clock.Clock
+}
+
+// Login implements api.OIDCLoginFunc.
+func (l *OIDCDeviceLogin) Login(ctx context.Context, info params.OIDCLoginInfo) (string, error) {
+	clk := l.Clock
+	if clk == nil {
+		clk = clock.WallClock
+	}
+	client := l.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	token, err := oidc.DeviceLogin(ctx, oidc.DeviceLoginParams{
+		Issuer:     info.Issuer,
+		ClientID:   info.ClientID,
+		Scopes:     info.Scopes,
+		HTTPClient: client,
+		Clock:      clk,
+		Prompt: func(auth *oidc.DeviceAuthorization) error {
+			if auth.VerificationURIComplete != "" {
+				_, err := fmt.Fprintf(l.Writer, "Please visit %s\nand confirm the code %s to log in.\n",
+					auth.VerificationURIComplete, auth.UserCode)
+				return errors.Trace(err)
+			}
+			_, err := fmt.Fprintf(l.Writer, "Please visit %s\nand enter the code %s to log in.\n",
+				auth.VerificationURI, auth.UserCode)
+			return errors.Trace(err)
+		},
+	})
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return token.IDToken, nil
+}
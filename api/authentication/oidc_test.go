@@ -0,0 +1,72 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package authentication_test
+
+import (
+	"bytes"
+	"context"
+	"time"
+
+	"github.com/juju/clock/testclock"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api/authentication"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/oidc/oidctesting"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type OIDCDeviceLoginSuite struct{}
+
+var _ = gc.Suite(&OIDCDeviceLoginSuite{})
+
+func (s *OIDCDeviceLoginSuite) TestLogin(c *gc.C) {
+	issuer := oidctesting.NewIssuer("juju")
+	defer issuer.Close()
+
+	// Approve the login as soon as the user has been prompted.
+	var buf bytes.Buffer
+	w := writerFunc(func(p []byte) (int, error) {
+		issuer.Approve("USER-0001", map[string]interface{}{
+			"sub":   "1234",
+			"email": "bob@example.com",
+		})
+		return buf.Write(p)
+	})
+	clock := testclock.NewClock(time.Now())
+	login := &authentication.OIDCDeviceLogin{
+		Writer:     w,
+		HTTPClient: issuer.Client(),
+		Clock:      clock,
+	}
+
+	result := make(chan error, 1)
+	var idToken string
+	go func() {
+		var err error
+		idToken, err = login.Login(context.Background(), params.OIDCLoginInfo{
+			Issuer:   issuer.URL,
+			ClientID: "juju",
+			Scopes:   []string{"email"},
+		})
+		result <- err
+	}()
+	err := clock.WaitAdvance(time.Second, coretesting.LongWait, 2)
+	c.Assert(err, jc.ErrorIsNil)
+	select {
+	case err := <-result:
+		c.Assert(err, jc.ErrorIsNil)
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for login")
+	}
+	c.Assert(idToken, gc.Not(gc.Equals), "")
+	c.Assert(buf.String(), gc.Equals, "Please visit "+issuer.URL+"/activate?user_code=USER-0001\nand confirm the code USER-0001 to log in.\n")
+}
+
+type writerFunc func([]byte) (int, error)
+
+func (f writerFunc) Write(p []byte) (int, error) {
+	return f(p)
+}
@@ -9,6 +9,7 @@ package bundle
 import (
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
+	"github.com/juju/names/v4"
 
 	"github.com/juju/juju/api/base"
 	"github.com/juju/juju/apiserver/params"
@@ -78,3 +79,21 @@ func (c *Client) ExportBundle() (string, error) {
 
 	return result.Result, nil
 }
+
+// DiffModels compares the bundles exported from two models, and
+// optionally a base bundle, returning the structured differences.
+func (c *Client) DiffModels(fromModelUUID, toModelUUID, baseBundleDataYAML string, includeAnnotations bool) (params.BundleDiff, error) {
+	var result params.BundleDiff
+	if bestVer := c.BestAPIVersion(); bestVer < 5 {
+		return result, errors.Errorf("this controller version does not support comparing models as bundles.")
+	}
+	if err := c.facade.FacadeCall("DiffModels", params.BundleDiffModelsParams{
+		FromModelTag:       names.NewModelTag(fromModelUUID).String(),
+		ToModelTag:         names.NewModelTag(toModelUUID).String(),
+		BaseBundleDataYAML: baseBundleDataYAML,
+		IncludeAnnotations: includeAnnotations,
+	}, &result); err != nil {
+		return result, errors.Trace(err)
+	}
+	return result, nil
+}
@@ -361,3 +361,49 @@ func (s *bundleMockSuite) TestExportBundleFailNoParamsErrorv2(c *gc.C) {
 	c.Assert(result, jc.DeepEquals, "")
 	c.Check(err.Error(), gc.Matches, "foo")
 }
+
+func (s *bundleMockSuite) TestDiffModels(c *gc.C) {
+	expected := params.BundleDiff{
+		Applications: map[string]params.ApplicationBundleDiff{
+			"mysql": {Missing: []string{"to"}},
+		},
+	}
+	client := newClient(
+		func(objType string, version int,
+			id,
+			request string,
+			args,
+			response interface{},
+		) error {
+			c.Check(objType, gc.Equals, "Bundle")
+			c.Check(request, gc.Equals, "DiffModels")
+			c.Check(args, jc.DeepEquals, params.BundleDiffModelsParams{
+				FromModelTag:       "model-" + coretesting.ModelTag.Id(),
+				ToModelTag:         "model-f47ac10b-58cc-4372-a567-0e02b2c3d479",
+				BaseBundleDataYAML: "applications: {}",
+				IncludeAnnotations: true,
+			})
+			*(response.(*params.BundleDiff)) = expected
+			return nil
+		}, 5,
+	)
+	result, err := client.DiffModels(coretesting.ModelTag.Id(), "f47ac10b-58cc-4372-a567-0e02b2c3d479", "applications: {}", true)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, expected)
+}
+
+func (s *bundleMockSuite) TestDiffModelsNotSupported(c *gc.C) {
+	client := newClient(
+		func(objType string, version int,
+			id,
+			request string,
+			args,
+			response interface{},
+		) error {
+			c.Fatalf("unexpected API call")
+			return nil
+		}, 4,
+	)
+	_, err := client.DiffModels("from", "to", "", false)
+	c.Assert(err, gc.ErrorMatches, "this controller version does not support comparing models as bundles.")
+}
@@ -14,22 +14,30 @@ import (
 	"github.com/juju/utils/cert"
 
 	"github.com/juju/juju/core/paths"
+	"github.com/juju/juju/pki"
 )
 
 var certDir = filepath.FromSlash(paths.MustSucceed(paths.CertDir(series.MustHostSeries())))
 
-// CreateCertPool creates a new x509.CertPool and adds in the caCert passed
-// in.  All certs from the cert directory (/etc/juju/cert.d on ubuntu) are
-// also added.
+// CreateCertPool creates a new x509.CertPool and adds in the certificates
+// in the caCert passed in. All certs from the cert directory
+// (/etc/juju/cert.d on ubuntu) are also added.
 func CreateCertPool(caCert string) (*x509.CertPool, error) {
 
 	pool := x509.NewCertPool()
 	if caCert != "" {
-		xcert, err := cert.ParseCert(caCert)
+		// The CA certificate may be a bundle of several,
+		// such as while the controller's CA is rotated.
+		xcerts, _, err := pki.UnmarshalPemData([]byte(caCert))
+		if err == nil && len(xcerts) == 0 {
+			err = errors.New("no certificates found")
+		}
 		if err != nil {
 			return nil, errors.Annotatef(err, "cannot parse certificate %q", caCert)
 		}
-		pool.AddCert(xcert)
+		for _, xcert := range xcerts {
+			pool.AddCert(xcert)
+		}
 	}
 
 	count := processCertDir(pool)
@@ -44,6 +44,17 @@ func (*certPoolSuite) TestCreateCertPoolTestCert(c *gc.C) {
 	c.Assert(pool.Subjects(), gc.HasLen, 1)
 }
 
+func (*certPoolSuite) TestCreateCertPoolBundle(c *gc.C) {
+	pool, err := api.CreateCertPool(testing.CACert + testing.OtherCACert)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pool.Subjects(), gc.HasLen, 2)
+}
+
+func (*certPoolSuite) TestCreateCertPoolBadCert(c *gc.C) {
+	_, err := api.CreateCertPool("not a certificate")
+	c.Assert(err, gc.ErrorMatches, `cannot parse certificate "not a certificate": no certificates found`)
+}
+
 func (s *certPoolSuite) TestCreateCertPoolNoDir(c *gc.C) {
 	certDir := filepath.Join(c.MkDir(), "missing")
 	s.PatchValue(api.CertDir, certDir)
@@ -3,7 +3,17 @@
 
 package charmhub
 
-import "github.com/juju/juju/api/base"
+import (
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
 
 const charmHubFacade = "CharmHub"
 
@@ -21,3 +31,50 @@ func NewClient(st base.APICallCloser) *Client {
 		facade:       backend,
 	}
 }
+
+// Info returns information about the named charm or bundle.
+func (c *Client) Info(name string) (params.InfoResponse, error) {
+	args := params.Info{Name: name}
+	var result params.CharmHubEntityInfoResult
+	if err := c.facade.FacadeCall("Info", args, &result); err != nil {
+		return params.InfoResponse{}, errors.Trace(err)
+	}
+	if result.Error != nil {
+		return params.InfoResponse{}, errors.Trace(result.Error)
+	}
+	return result.Result, nil
+}
+
+// Find returns the charms and bundles matching the query.
+func (c *Client) Find(query string) ([]params.FindResponse, error) {
+	args := params.Query{Query: query}
+	var result params.CharmHubEntityFindResult
+	if err := c.facade.FacadeCall("Find", args, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if result.Error != nil {
+		return nil, errors.Trace(result.Error)
+	}
+	return result.Results, nil
+}
+
+// Download returns the content of the given revision of the named charm
+// or bundle, which must be released to the given channel. The controller
+// downloads it from the store and verifies its hash before sending it.
+// The caller is responsible for closing the returned reader.
+func (c *Client) Download(name, channel string, revision int) (io.ReadCloser, error) {
+	httpClient, err := c.facade.RawAPICaller().HTTPClient()
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot retrieve HTTP client")
+	}
+	query := url.Values{
+		"name":     {name},
+		"channel":  {channel},
+		"revision": {strconv.Itoa(revision)},
+	}
+	var resp *http.Response
+	if err := httpClient.Get(c.facade.RawAPICaller().Context(), "/charmhub/download?"+query.Encode(), &resp); err != nil {
+		return nil, errors.Annotatef(err, "cannot download %q", name)
+	}
+	return resp.Body, nil
+}
@@ -0,0 +1,71 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package charmhub_test
+
+import (
+	"github.com/golang/mock/gomock"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	basemocks "github.com/juju/juju/api/base/mocks"
+	"github.com/juju/juju/api/charmhub"
+	"github.com/juju/juju/apiserver/params"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type charmHubSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&charmHubSuite{})
+
+func (s *charmHubSuite) TestInfo(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	mockFacadeCaller := basemocks.NewMockFacadeCaller(ctrl)
+	args := params.Info{Name: "wordpress"}
+	result := params.CharmHubEntityInfoResult{
+		Result: params.InfoResponse{Name: "wordpress", DefaultChannel: "latest/stable"},
+	}
+	mockFacadeCaller.EXPECT().FacadeCall("Info", args, gomock.Any()).SetArg(2, result).Return(nil)
+
+	client := charmhub.NewClientWithFacade(mockFacadeCaller)
+	info, err := client.Info("wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info, jc.DeepEquals, result.Result)
+}
+
+func (s *charmHubSuite) TestInfoError(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	mockFacadeCaller := basemocks.NewMockFacadeCaller(ctrl)
+	args := params.Info{Name: "ghost"}
+	result := params.CharmHubEntityInfoResult{
+		Error: &params.Error{Code: params.CodeNotFound, Message: "No charm or bundle with name 'ghost'."},
+	}
+	mockFacadeCaller.EXPECT().FacadeCall("Info", args, gomock.Any()).SetArg(2, result).Return(nil)
+
+	client := charmhub.NewClientWithFacade(mockFacadeCaller)
+	_, err := client.Info("ghost")
+	c.Assert(err, jc.Satisfies, params.IsCodeNotFound)
+}
+
+func (s *charmHubSuite) TestFind(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	mockFacadeCaller := basemocks.NewMockFacadeCaller(ctrl)
+	args := params.Query{Query: "wordpress"}
+	result := params.CharmHubEntityFindResult{
+		Results: []params.FindResponse{{Name: "wordpress", Version: "1.0.3"}},
+	}
+	mockFacadeCaller.EXPECT().FacadeCall("Find", args, gomock.Any()).SetArg(2, result).Return(nil)
+
+	client := charmhub.NewClientWithFacade(mockFacadeCaller)
+	found, err := client.Find("wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(found, jc.DeepEquals, result.Results)
+}
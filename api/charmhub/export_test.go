@@ -0,0 +1,10 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package charmhub
+
+import "github.com/juju/juju/api/base"
+
+func NewClientWithFacade(facade base.FacadeCaller) *Client {
+	return &Client{facade: facade}
+}
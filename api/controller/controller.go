@@ -220,6 +220,44 @@ func (c *Client) RevokeController(user, access string) error {
 	return c.modifyControllerUser(params.RevokeControllerAccess, user, access)
 }
 
+// GrantGroupController grants the members of a group access to the
+// controller.
+func (c *Client) GrantGroupController(group, access string) error {
+	return c.modifyControllerGroup(params.GrantControllerAccess, group, access)
+}
+
+// RevokeGroupController revokes the access of a group's members to the
+// controller.
+func (c *Client) RevokeGroupController(group, access string) error {
+	return c.modifyControllerGroup(params.RevokeControllerAccess, group, access)
+}
+
+func (c *Client) modifyControllerGroup(action params.ControllerAction, group, access string) error {
+	if c.BestAPIVersion() < 11 {
+		return errors.NotSupportedf("controller access for groups")
+	}
+	if group == "" {
+		return errors.New("empty group name")
+	}
+	args := params.ModifyControllerAccessRequest{
+		Changes: []params.ModifyControllerAccess{{
+			GroupName: group,
+			Action:    action,
+			Access:    access,
+		}},
+	}
+
+	var result params.ErrorResults
+	err := c.facade.FacadeCall("ModifyControllerAccess", args, &result)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(result.Results) != len(args.Changes) {
+		return errors.Errorf("expected %d results, got %d", len(args.Changes), len(result.Results))
+	}
+	return result.Combine()
+}
+
 func (c *Client) modifyControllerUser(action params.ControllerAction, user, access string) error {
 	var args params.ModifyControllerAccessRequest
 
@@ -278,6 +316,41 @@ func (c *Client) ConfigSet(values map[string]interface{}) error {
 	)
 }
 
+// ControllerCARotation returns the state of any rotation of the
+// controller's CA that is in progress.
+func (c *Client) ControllerCARotation() (params.ControllerCARotation, error) {
+	var result params.ControllerCARotation
+	if c.BestAPIVersion() < 12 {
+		return result, errors.NotSupportedf("controller CA rotation")
+	}
+	err := c.facade.FacadeCall("ControllerCARotation", nil, &result)
+	return result, errors.Trace(err)
+}
+
+// RotateControllerCA performs the given step ("start", "reissue",
+// "finish" or "abort") of a rotation of the controller's CA, returning
+// the resulting state of the rotation.
+func (c *Client) RotateControllerCA(step string) (params.ControllerCARotation, error) {
+	var result params.ControllerCARotation
+	if c.BestAPIVersion() < 12 {
+		return result, errors.NotSupportedf("controller CA rotation")
+	}
+	args := params.RotateControllerCAArgs{Step: step}
+	err := c.facade.FacadeCall("RotateControllerCA", args, &result)
+	return result, errors.Trace(err)
+}
+
+// AutocertStatus returns the status of the certificate obtained for
+// the controller's autocert DNS name.
+func (c *Client) AutocertStatus() (params.AutocertStatus, error) {
+	var result params.AutocertStatus
+	if c.BestAPIVersion() < 13 {
+		return result, errors.NotSupportedf("autocert status")
+	}
+	err := c.facade.FacadeCall("AutocertStatus", nil, &result)
+	return result, errors.Trace(err)
+}
+
 // MigrationSpec holds the details required to start the migration of
 // a single model.
 type MigrationSpec struct {
@@ -319,16 +392,79 @@ func (s *MigrationSpec) Validate() error {
 // but we don't need that at the client side yet (and may never) so
 // this call just supports starting one migration at a time.
 func (c *Client) InitiateMigration(spec MigrationSpec) (string, error) {
+	args, err := initiateMigrationArgs(spec)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	response := params.InitiateMigrationResults{}
+	if err := c.facade.FacadeCall("InitiateMigration", args, &response); err != nil {
+		return "", errors.Trace(err)
+	}
+	if len(response.Results) != 1 {
+		return "", errors.New("unexpected number of results returned")
+	}
+	result := response.Results[0]
+	if result.Error != nil {
+		return "", errors.Trace(result.Error)
+	}
+	return result.MigrationId, nil
+}
+
+// MigrationDryRunResult reports the problems that would prevent
+// a model being migrated.
+type MigrationDryRunResult struct {
+	// Problems describes each problem found.
+	Problems []string
+
+	// Upconversions describes the conversions the target controller
+	// makes to the format of the exported model.
+	Upconversions []string
+
+	// Notes describes checks that could not be fully performed.
+	Notes []string
+}
+
+// MigrationDryRun checks whether the model in the spec could be migrated,
+// without starting the migration. All of the migration prechecks are run
+// and the model is imported into the target controller as a throwaway
+// model, which is then removed. Every problem found is reported.
+func (c *Client) MigrationDryRun(spec MigrationSpec) (MigrationDryRunResult, error) {
+	if c.BestAPIVersion() < 10 {
+		return MigrationDryRunResult{}, errors.NotSupportedf("migration dry runs")
+	}
+	args, err := initiateMigrationArgs(spec)
+	if err != nil {
+		return MigrationDryRunResult{}, errors.Trace(err)
+	}
+	response := params.MigrationDryRunResults{}
+	if err := c.facade.FacadeCall("MigrationDryRun", args, &response); err != nil {
+		return MigrationDryRunResult{}, errors.Trace(err)
+	}
+	if len(response.Results) != 1 {
+		return MigrationDryRunResult{}, errors.New("unexpected number of results returned")
+	}
+	result := response.Results[0]
+	if result.Error != nil {
+		return MigrationDryRunResult{}, errors.Trace(result.Error)
+	}
+	return MigrationDryRunResult{
+		Problems:      result.Problems,
+		Upconversions: result.Upconversions,
+		Notes:         result.Notes,
+	}, nil
+}
+
+func initiateMigrationArgs(spec MigrationSpec) (params.InitiateMigrationArgs, error) {
 	if err := spec.Validate(); err != nil {
-		return "", errors.Annotatef(err, "client-side validation failed")
+		return params.InitiateMigrationArgs{}, errors.Annotatef(err, "client-side validation failed")
 	}
 
 	macsJSON, err := macaroonsToJSON(spec.TargetMacaroons)
 	if err != nil {
-		return "", errors.Annotatef(err, "client-side validation failed")
+		return params.InitiateMigrationArgs{}, errors.Annotatef(err, "client-side validation failed")
 	}
 
-	args := params.InitiateMigrationArgs{
+	return params.InitiateMigrationArgs{
 		Specs: []params.MigrationSpec{{
 			ModelTag: names.NewModelTag(spec.ModelUUID).String(),
 			TargetInfo: params.MigrationTargetInfo{
@@ -341,19 +477,7 @@ func (c *Client) InitiateMigration(spec MigrationSpec) (string, error) {
 				Macaroons:       macsJSON,
 			},
 		}},
-	}
-	response := params.InitiateMigrationResults{}
-	if err := c.facade.FacadeCall("InitiateMigration", args, &response); err != nil {
-		return "", errors.Trace(err)
-	}
-	if len(response.Results) != 1 {
-		return "", errors.New("unexpected number of results returned")
-	}
-	result := response.Results[0]
-	if result.Error != nil {
-		return "", errors.Trace(result.Error)
-	}
-	return result.MigrationId, nil
+	}, nil
 }
 
 func macaroonsToJSON(macs []macaroon.Slice) (string, error) {
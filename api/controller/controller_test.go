@@ -172,6 +172,92 @@ func (s *Suite) TestInitiateMigrationValidationError(c *gc.C) {
 	c.Check(stub.Calls(), gc.HasLen, 0) // API call shouldn't have happened
 }
 
+func (s *Suite) TestMigrationDryRun(c *gc.C) {
+	var stub jujutesting.Stub
+	apiCaller := apitesting.BestVersionCaller{
+		APICallerFunc: func(objType string, version int, id, request string, arg, result interface{}) error {
+			stub.AddCall(objType+"."+request, arg)
+			*(result.(*params.MigrationDryRunResults)) = params.MigrationDryRunResults{
+				Results: []params.MigrationDryRunResult{{
+					Problems:      []string{"source prechecks: cleanup needed"},
+					Upconversions: []string{"model upconverted from version 7 to 8"},
+					Notes:         []string{"target controller does not support import dry runs"},
+				}},
+			}
+			return nil
+		},
+		BestVersion: 10,
+	}
+	client := controller.NewClient(apiCaller)
+	spec := makeSpec()
+	result, err := client.MigrationDryRun(spec)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, controller.MigrationDryRunResult{
+		Problems:      []string{"source prechecks: cleanup needed"},
+		Upconversions: []string{"model upconverted from version 7 to 8"},
+		Notes:         []string{"target controller does not support import dry runs"},
+	})
+	stub.CheckCalls(c, []jujutesting.StubCall{
+		{"Controller.MigrationDryRun", []interface{}{specToArgs(spec)}},
+	})
+}
+
+func (s *Suite) TestMigrationDryRunError(c *gc.C) {
+	apiCaller := apitesting.BestVersionCaller{
+		APICallerFunc: func(objType string, version int, id, request string, arg, result interface{}) error {
+			*(result.(*params.MigrationDryRunResults)) = params.MigrationDryRunResults{
+				Results: []params.MigrationDryRunResult{{
+					Error: common.ServerError(errors.New("boom")),
+				}},
+			}
+			return nil
+		},
+		BestVersion: 10,
+	}
+	client := controller.NewClient(apiCaller)
+	_, err := client.MigrationDryRun(makeSpec())
+	c.Check(err, gc.ErrorMatches, "boom")
+}
+
+func (s *Suite) TestMigrationDryRunNotSupported(c *gc.C) {
+	client, stub := makeInitiateMigrationClient(params.InitiateMigrationResults{})
+	_, err := client.MigrationDryRun(makeSpec())
+	c.Check(err, jc.Satisfies, errors.IsNotSupported)
+	c.Check(stub.Calls(), gc.HasLen, 0)
+}
+
+func (s *Suite) TestGrantGroupController(c *gc.C) {
+	var stub jujutesting.Stub
+	apiCaller := apitesting.BestVersionCaller{
+		APICallerFunc: func(objType string, version int, id, request string, arg, result interface{}) error {
+			stub.AddCall(objType+"."+request, arg)
+			*(result.(*params.ErrorResults)) = params.ErrorResults{
+				Results: []params.ErrorResult{{}},
+			}
+			return nil
+		},
+		BestVersion: 11,
+	}
+	client := controller.NewClient(apiCaller)
+	err := client.GrantGroupController("ops", "superuser")
+	c.Assert(err, jc.ErrorIsNil)
+	stub.CheckCalls(c, []jujutesting.StubCall{
+		{"Controller.ModifyControllerAccess", []interface{}{params.ModifyControllerAccessRequest{
+			Changes: []params.ModifyControllerAccess{{
+				GroupName: "ops",
+				Action:    params.GrantControllerAccess,
+				Access:    "superuser",
+			}},
+		}}},
+	})
+}
+
+func (s *Suite) TestRevokeGroupControllerNotSupported(c *gc.C) {
+	client := controller.NewClient(apitesting.BestVersionCaller{BestVersion: 10})
+	err := client.RevokeGroupController("ops", "login")
+	c.Check(err, jc.Satisfies, errors.IsNotSupported)
+}
+
 func (s *Suite) TestHostedModelConfigs_CallError(c *gc.C) {
 	apiCaller := apitesting.APICallerFunc(func(string, int, string, string, interface{}, interface{}) error {
 		return errors.New("boom")
@@ -384,6 +470,70 @@ func (s *Suite) TestConfigSetAgainstOlderAPIVersion(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "this controller version doesn't support updating controller config")
 }
 
+func (s *Suite) TestRotateControllerCA(c *gc.C) {
+	apiCaller := apitesting.BestVersionCaller{
+		BestVersion: 12,
+		APICallerFunc: func(objType string, version int, id, request string, args, result interface{}) error {
+			c.Assert(objType, gc.Equals, "Controller")
+			c.Assert(version, gc.Equals, 12)
+			c.Assert(request, gc.Equals, "RotateControllerCA")
+			c.Assert(args, jc.DeepEquals, params.RotateControllerCAArgs{Step: "start"})
+			*(result.(*params.ControllerCARotation)) = params.ControllerCARotation{
+				Phase:  "trusting",
+				CACert: "bundle",
+			}
+			return nil
+		},
+	}
+	client := controller.NewClient(apiCaller)
+	result, err := client.RotateControllerCA("start")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.ControllerCARotation{
+		Phase:  "trusting",
+		CACert: "bundle",
+	})
+}
+
+func (s *Suite) TestRotateControllerCAAgainstOlderAPIVersion(c *gc.C) {
+	apiCaller := apitesting.BestVersionCaller{BestVersion: 11}
+	client := controller.NewClient(apiCaller)
+	_, err := client.RotateControllerCA("start")
+	c.Assert(err, gc.ErrorMatches, "controller CA rotation not supported")
+	_, err = client.ControllerCARotation()
+	c.Assert(err, gc.ErrorMatches, "controller CA rotation not supported")
+}
+
+func (s *Suite) TestAutocertStatus(c *gc.C) {
+	apiCaller := apitesting.BestVersionCaller{
+		BestVersion: 13,
+		APICallerFunc: func(objType string, version int, id, request string, args, result interface{}) error {
+			c.Assert(objType, gc.Equals, "Controller")
+			c.Assert(version, gc.Equals, 13)
+			c.Assert(request, gc.Equals, "AutocertStatus")
+			c.Assert(args, gc.IsNil)
+			*(result.(*params.AutocertStatus)) = params.AutocertStatus{
+				DNSName: "controller.example",
+				Error:   "boom",
+			}
+			return nil
+		},
+	}
+	client := controller.NewClient(apiCaller)
+	result, err := client.AutocertStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.AutocertStatus{
+		DNSName: "controller.example",
+		Error:   "boom",
+	})
+}
+
+func (s *Suite) TestAutocertStatusAgainstOlderAPIVersion(c *gc.C) {
+	apiCaller := apitesting.BestVersionCaller{BestVersion: 12}
+	client := controller.NewClient(apiCaller)
+	_, err := client.AutocertStatus()
+	c.Assert(err, gc.ErrorMatches, "autocert status not supported")
+}
+
 func (s *Suite) TestWatchModelSummaries(c *gc.C) {
 	apiCaller := apitesting.BestVersionCaller{
 		BestVersion: 9,
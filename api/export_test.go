@@ -73,6 +73,7 @@ type TestingStateParams struct {
 	RPCConnection  RPCConnection
 	Clock          clock.Clock
 	Broken, Closed chan struct{}
+	OIDCLogin      OIDCLoginFunc
 }
 
 // NewTestingState creates an api.State object that can be used for testing. It
@@ -88,6 +89,7 @@ func NewTestingState(params TestingStateParams) Connection {
 		modelTag = t
 	}
 	st := &state{
+		ctx:               context.Background(),
 		client:            params.RPCConnection,
 		clock:             params.Clock,
 		addr:              params.Address,
@@ -98,6 +100,13 @@ func NewTestingState(params TestingStateParams) Connection {
 		serverRootAddress: params.ServerRoot,
 		broken:            params.Broken,
 		closed:            params.Closed,
+		oidcLogin:         params.OIDCLogin,
+		bakeryClient:      httpbakery.NewClient(),
+		cookieURL: &url.URL{
+			Scheme: "https",
+			Host:   params.Address,
+			Path:   "/",
+		},
 	}
 	return st
 }
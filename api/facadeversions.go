@@ -13,17 +13,18 @@ package api
 var facadeVersions = map[string]int{
 	"Action":                       6,
 	"ActionPruner":                 1,
-	"Agent":                        2,
+	"Agent":                        3,
 	"AgentTools":                   1,
 	"AllModelWatcher":              2,
 	"AllWatcher":                   1,
 	"Annotations":                  2,
-	"Application":                  11,
-	"ApplicationOffers":            2,
+	"APITokens":                    1,
+	"Application":                  13,
+	"ApplicationOffers":            3,
 	"ApplicationScaler":            1,
 	"Backups":                      2,
 	"Block":                        2,
-	"Bundle":                       4,
+	"Bundle":                       5,
 	"CAASAgent":                    1,
 	"CAASAdmission":                1,
 	"CAASFirewaller":               1,
@@ -32,12 +33,13 @@ var facadeVersions = map[string]int{
 	"CAASOperatorProvisioner":      1,
 	"CAASOperatorUpgrader":         1,
 	"CAASUnitProvisioner":          1,
+	"CharmHub":                     1,
 	"CharmRevisionUpdater":         2,
 	"Charms":                       2,
 	"Cleaner":                      2,
 	"Client":                       2,
 	"Cloud":                        7,
-	"Controller":                   9,
+	"Controller":                   13,
 	"CredentialManager":            1,
 	"CredentialValidator":          2,
 	"CrossController":              1,
@@ -64,7 +66,7 @@ var facadeVersions = map[string]int{
 	"LogForwarding":                1,
 	"Logger":                       1,
 	"MachineActions":               1,
-	"MachineManager":               6,
+	"MachineManager":               9,
 	"MachineUndertaker":            1,
 	"Machiner":                     3,
 	"MeterStatus":                  2,
@@ -75,10 +77,10 @@ var facadeVersions = map[string]int{
 	"MigrationMaster":              2,
 	"MigrationMinion":              1,
 	"MigrationStatusWatcher":       1,
-	"MigrationTarget":              1,
+	"MigrationTarget":              2,
 	"ModelConfig":                  2,
-	"ModelGeneration":              4,
-	"ModelManager":                 8,
+	"ModelGeneration":              5,
+	"ModelManager":                 9,
 	"ModelSummaryWatcher":          1,
 	"ModelUpgrader":                1,
 	"NotifyWatcher":                1,
@@ -97,6 +99,7 @@ var facadeVersions = map[string]int{
 	"ResourcesHookContext":         1,
 	"Resumer":                      2,
 	"RetryStrategy":                1,
+	"Roles":                        1,
 	"Singular":                     2,
 	"Spaces":                       6,
 	"SSHClient":                    2,
@@ -111,7 +114,7 @@ var facadeVersions = map[string]int{
 	"Upgrader":                     1,
 	"UpgradeSeries":                1,
 	"UpgradeSteps":                 2,
-	"UserManager":                  2,
+	"UserManager":                  3,
 	"VolumeAttachmentsWatcher":     2,
 	"VolumeAttachmentPlansWatcher": 1,
 }
@@ -71,6 +71,7 @@ func (doer httpRequestDoer) Do(req *http.Request) (*http.Response, error) {
 		doer.st.password,
 		doer.st.nonce,
 		doer.st.macaroons,
+		bearerToken(doer.st.token, doer.st.idToken),
 	); err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -86,22 +87,35 @@ func (doer httpRequestDoer) Do(req *http.Request) (*http.Response, error) {
 	})
 }
 
-// AuthHTTPRequest adds Juju auth info (username, password, nonce, macaroons)
-// to the given HTTP request, suitable for sending to a Juju API server.
+// AuthHTTPRequest adds Juju auth info (username, password, nonce, macaroons,
+// API or ID token) to the given HTTP request, suitable for sending to a Juju
+// API server.
 func AuthHTTPRequest(req *http.Request, info *Info) error {
 	var tag string
 	if info.Tag != nil {
 		tag = info.Tag.String()
 	}
-	return authHTTPRequest(req, tag, info.Password, info.Nonce, info.Macaroons)
+	return authHTTPRequest(req, tag, info.Password, info.Nonce, info.Macaroons, bearerToken(info.Token, info.IDToken))
 }
 
-func authHTTPRequest(req *http.Request, tag, password, nonce string, macaroons []macaroon.Slice) error {
+// bearerToken returns the credential presented as a bearer token: the
+// API token if there is one, otherwise the ID token. The API server
+// tells them apart by the API token prefix.
+func bearerToken(token, idToken string) string {
+	if token != "" {
+		return token
+	}
+	return idToken
+}
+
+func authHTTPRequest(req *http.Request, tag, password, nonce string, macaroons []macaroon.Slice, bearer string) error {
 	if tag != "" {
 		// Note that password may be empty here; we still
 		// want to pass the tag along. An empty password
 		// indicates that we're using macaroon authentication.
 		req.SetBasicAuth(tag, password)
+	} else if bearer != "" {
+		req.Header.Set("Authorization", "Bearer "+bearer)
 	}
 	if nonce != "" {
 		req.Header.Set(params.MachineNonceHeader, nonce)
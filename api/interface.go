@@ -14,6 +14,7 @@ import (
 	"github.com/juju/clock"
 	"github.com/juju/collections/set"
 	"github.com/juju/errors"
+	"github.com/juju/featureflag"
 	"github.com/juju/names/v4"
 	"github.com/juju/version"
 	"gopkg.in/macaroon-bakery.v2/httpbakery"
@@ -25,7 +26,10 @@ import (
 	"github.com/juju/juju/api/unitassigner"
 	"github.com/juju/juju/api/uniter"
 	"github.com/juju/juju/api/upgrader"
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/core/network"
+	"github.com/juju/juju/feature"
+	"github.com/juju/juju/rpc/cborcodec"
 	"github.com/juju/juju/rpc/jsoncodec"
 )
 
@@ -77,11 +81,25 @@ type Info struct {
 	// authenticate with the API server.
 	Macaroons []macaroon.Slice `yaml:",omitempty"`
 
+	// IDToken holds an OpenID Connect ID token that may be used to
+	// authenticate an external user with the API server. It is only
+	// used if Tag is nil.
+	IDToken string `yaml:"id-token,omitempty"`
+
+	// Token holds the secret of an API token that may be used to
+	// authenticate a user with the API server. It is only used if
+	// Tag is nil, and takes precedence over IDToken.
+	Token string `yaml:"token,omitempty"`
+
 	// Nonce holds the nonce used when provisioning the machine. Used
 	// only by the machine agent.
 	Nonce string `yaml:",omitempty"`
 }
 
+// OIDCLoginFunc obtains an ID token from the OpenID Connect provider
+// described by the given info.
+type OIDCLoginFunc func(ctx context.Context, info params.OIDCLoginInfo) (string, error)
+
 // Ports returns the unique ports for the api addresses.
 func (info *Info) Ports() []int {
 	ports := set.NewInts()
@@ -119,6 +137,12 @@ func (info *Info) Validate() error {
 		if len(info.Macaroons) > 0 {
 			return errors.NotValidf("specifying Macaroons and SkipLogin")
 		}
+		if info.IDToken != "" {
+			return errors.NotValidf("specifying IDToken and SkipLogin")
+		}
+		if info.Token != "" {
+			return errors.NotValidf("specifying Token and SkipLogin")
+		}
 	}
 	return nil
 }
@@ -151,6 +175,12 @@ type DialOpts struct {
 	// the HTTP client is ignored.
 	BakeryClient *httpbakery.Client
 
+	// OIDCLogin, if non-nil, is called when the controller requires
+	// the user to log in with its OpenID Connect provider. It should
+	// return an ID token obtained from the provider described by the
+	// given info.
+	OIDCLogin OIDCLoginFunc
+
 	// InsecureSkipVerify skips TLS certificate verification
 	// when connecting to the controller. This should only
 	// be used in tests, or when verification cannot be
@@ -184,6 +214,13 @@ type DialOpts struct {
 	// automatically verified. If the callback returns a non-nil error then
 	// the connection attempt will be aborted.
 	VerifyCA func(host, endpoint string, caCert *x509.Certificate) error
+
+	// Codecs holds, in order of preference, the message encodings
+	// other than JSON to request at login. If the controller agrees
+	// to one of them, it is used for all subsequent requests on the
+	// connection. Only cborcodec.Name is currently supported, and
+	// only on connections made with the default DialWebsocket.
+	Codecs []string
 }
 
 // IPAddrResolver implements a resolved from host name to the
@@ -206,11 +243,15 @@ type DNSCache interface {
 // DefaultDialOpts returns a DialOpts representing the default
 // parameters for contacting a controller.
 func DefaultDialOpts() DialOpts {
-	return DialOpts{
+	opts := DialOpts{
 		DialAddressInterval: 50 * time.Millisecond,
 		Timeout:             10 * time.Minute,
 		RetryDelay:          2 * time.Second,
 	}
+	if featureflag.Enabled(feature.BinaryAPICodec) {
+		opts.Codecs = []string{cborcodec.Name}
+	}
+	return opts
 }
 
 // OpenFunc is the usual form of a function that opens an API connection.
@@ -13,6 +13,7 @@ import (
 	apiwatcher "github.com/juju/juju/api/watcher"
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/instance"
 	"github.com/juju/juju/core/watcher"
 )
 
@@ -48,6 +49,26 @@ func (client *Client) AddMachines(machineParams []params.AddMachineParams) ([]pa
 	return results.Machines, err
 }
 
+// PrecheckMachines checks, without adding anything to the model, whether
+// machines could be added with the supplied parameters. The results are
+// in the same order as the parameters.
+func (client *Client) PrecheckMachines(machineParams []params.AddMachineParams) ([]params.PrecheckMachineResult, error) {
+	if client.BestAPIVersion() < 9 {
+		return nil, errors.NotSupportedf("prechecking machines")
+	}
+	args := params.AddMachines{
+		MachineParams: machineParams,
+	}
+	var results params.PrecheckMachinesResults
+	if err := client.facade.FacadeCall("PrecheckMachines", args, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != len(machineParams) {
+		return nil, errors.Errorf("expected %d result, got %d", len(machineParams), len(results.Results))
+	}
+	return results.Results, nil
+}
+
 // DestroyMachines removes a given set of machines.
 func (client *Client) DestroyMachines(machines ...string) ([]params.DestroyMachineResult, error) {
 	return client.destroyMachines("DestroyMachine", machines)
@@ -133,6 +154,60 @@ func (client *Client) destroyMachines(method string, machines []string) ([]param
 	return allResults, nil
 }
 
+// ReenlistMachine prepares the manually provisioned machine with the given
+// ID to be provisioned again on its reinstalled host, which has the given
+// series and hardware. The agent installed on the host must log in with
+// the supplied nonce.
+func (client *Client) ReenlistMachine(machineId, nonce, series string, hc instance.HardwareCharacteristics) error {
+	if client.BestAPIVersion() < 7 {
+		return errors.NotSupportedf("re-enlisting machines")
+	}
+	if !names.IsValidMachine(machineId) {
+		return errors.NotValidf("machine ID %q", machineId)
+	}
+	args := params.ReenlistMachinesArgs{
+		Machines: []params.ReenlistMachineArg{{
+			MachineTag:              names.NewMachineTag(machineId).String(),
+			Nonce:                   nonce,
+			Series:                  series,
+			HardwareCharacteristics: hc,
+		}},
+	}
+	var results params.ErrorResults
+	if err := client.facade.FacadeCall("ReenlistMachines", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
+
+// MachineUserData returns the cloud-init user data used when provisioning
+// the machine with the given ID, with any overlays matching the machine's
+// constraints merged in, and a preview of the cloud-config it produces.
+func (client *Client) MachineUserData(machineId string) (params.MachineUserDataResult, error) {
+	var empty params.MachineUserDataResult
+	if client.BestAPIVersion() < 8 {
+		return empty, errors.NotSupportedf("showing machine user data")
+	}
+	if !names.IsValidMachine(machineId) {
+		return empty, errors.NotValidf("machine ID %q", machineId)
+	}
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: names.NewMachineTag(machineId).String()}},
+	}
+	var results params.MachineUserDataResults
+	if err := client.facade.FacadeCall("MachineUserData", args, &results); err != nil {
+		return empty, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return empty, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return empty, result.Error
+	}
+	return result, nil
+}
+
 // UpgradeSeriesPrepare notifies the controller that a series upgrade is taking
 // place for a given machine and as such the machine is guarded against
 // operations that would impede, fail, or interfere with the upgrade process.
@@ -24,6 +24,8 @@ import (
 	"github.com/juju/juju/api/base/mocks"
 	"github.com/juju/juju/api/machinemanager"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/constraints"
+	"github.com/juju/juju/core/instance"
 	jujutesting "github.com/juju/juju/testing"
 )
 
@@ -89,13 +91,117 @@ func (s *NewMachineManagerSuite) TestUpgradeSeriesPrepareAlreadyInProgress(c *gc
 	c.Assert(errors.IsAlreadyExists(err), jc.IsTrue)
 }
 
+func (s *NewMachineManagerSuite) TestReenlistMachine(c *gc.C) {
+	defer s.setupVersion(c, 7).Finish()
+
+	arch := "amd64"
+	hc := instance.HardwareCharacteristics{Arch: &arch}
+	args := params.ReenlistMachinesArgs{
+		Machines: []params.ReenlistMachineArg{{
+			MachineTag:              s.tag.String(),
+			Nonce:                   "manual:10.0.0.1:deadbeef",
+			Series:                  "bionic",
+			HardwareCharacteristics: hc,
+		}},
+	}
+	results := params.ErrorResults{Results: []params.ErrorResult{{}}}
+	s.facade.EXPECT().FacadeCall("ReenlistMachines", args, gomock.Any()).SetArg(2, results)
+
+	err := s.client.ReenlistMachine(s.tag.Id(), "manual:10.0.0.1:deadbeef", "bionic", hc)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *NewMachineManagerSuite) TestReenlistMachineError(c *gc.C) {
+	defer s.setupVersion(c, 7).Finish()
+
+	results := params.ErrorResults{Results: []params.ErrorResult{{
+		Error: &params.Error{Message: "machine-0 is a controller and cannot be re-enlisted"},
+	}}}
+	s.facade.EXPECT().FacadeCall("ReenlistMachines", gomock.Any(), gomock.Any()).SetArg(2, results)
+
+	err := s.client.ReenlistMachine(s.tag.Id(), "manual:10.0.0.1:deadbeef", "bionic", instance.HardwareCharacteristics{})
+	c.Assert(err, gc.ErrorMatches, "machine-0 is a controller and cannot be re-enlisted")
+}
+
+func (s *NewMachineManagerSuite) TestReenlistMachineNotSupported(c *gc.C) {
+	defer s.setup(c).Finish()
+
+	err := s.client.ReenlistMachine(s.tag.Id(), "manual:10.0.0.1:deadbeef", "bionic", instance.HardwareCharacteristics{})
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *NewMachineManagerSuite) TestMachineUserData(c *gc.C) {
+	defer s.setupVersion(c, 8).Finish()
+
+	args := params.Entities{Entities: []params.Entity{{Tag: s.tag.String()}}}
+	result := params.MachineUserDataResult{
+		UserData:        "packages:\n- htop\n",
+		MatchedOverlays: []string{"arch=arm64"},
+		Preview:         "#cloud-config\npackages:\n- htop\n",
+	}
+	results := params.MachineUserDataResults{Results: []params.MachineUserDataResult{result}}
+	s.facade.EXPECT().FacadeCall("MachineUserData", args, gomock.Any()).SetArg(2, results)
+
+	obtained, err := s.client.MachineUserData(s.tag.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(obtained, jc.DeepEquals, result)
+}
+
+func (s *NewMachineManagerSuite) TestMachineUserDataError(c *gc.C) {
+	defer s.setupVersion(c, 8).Finish()
+
+	results := params.MachineUserDataResults{Results: []params.MachineUserDataResult{{
+		Error: &params.Error{Message: "machine 0 not found", Code: params.CodeNotFound},
+	}}}
+	s.facade.EXPECT().FacadeCall("MachineUserData", gomock.Any(), gomock.Any()).SetArg(2, results)
+
+	_, err := s.client.MachineUserData(s.tag.Id())
+	c.Assert(err, jc.Satisfies, params.IsCodeNotFound)
+}
+
+func (s *NewMachineManagerSuite) TestMachineUserDataNotSupported(c *gc.C) {
+	defer s.setupVersion(c, 7).Finish()
+
+	_, err := s.client.MachineUserData(s.tag.Id())
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *NewMachineManagerSuite) TestPrecheckMachines(c *gc.C) {
+	defer s.setupVersion(c, 9).Finish()
+
+	machineParams := []params.AddMachineParams{{
+		Series:      "focal",
+		Constraints: constraints.MustParse("instance-type=huge"),
+	}}
+	args := params.AddMachines{MachineParams: machineParams}
+	results := params.PrecheckMachinesResults{Results: []params.PrecheckMachineResult{{
+		Error: &params.Error{Message: `invalid instance type "huge"`},
+	}}}
+	s.facade.EXPECT().FacadeCall("PrecheckMachines", args, gomock.Any()).SetArg(2, results)
+
+	obtained, err := s.client.PrecheckMachines(machineParams)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(obtained, jc.DeepEquals, results.Results)
+}
+
+func (s *NewMachineManagerSuite) TestPrecheckMachinesNotSupported(c *gc.C) {
+	defer s.setupVersion(c, 8).Finish()
+
+	_, err := s.client.PrecheckMachines([]params.AddMachineParams{{Series: "focal"}})
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
 func (s *NewMachineManagerSuite) setup(c *gc.C) *gomock.Controller {
+	return s.setupVersion(c, 5)
+}
+
+func (s *NewMachineManagerSuite) setupVersion(c *gc.C, version int) *gomock.Controller {
 	ctrl := gomock.NewController(c)
 
 	s.clientFacade = mocks.NewMockClientFacade(ctrl)
 	s.facade = mocks.NewMockFacadeCaller(ctrl)
 
-	s.clientFacade.EXPECT().BestAPIVersion().Return(5)
+	s.clientFacade.EXPECT().BestAPIVersion().Return(version)
 
 	s.client = machinemanager.ConstructClient(s.clientFacade, s.facade)
 
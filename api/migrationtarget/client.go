@@ -43,14 +43,59 @@ type Client struct {
 }
 
 func (c *Client) Prechecks(model coremigration.ModelInfo) error {
-	args := params.MigrationModelInfo{
+	args := migrationModelInfo(model)
+	return errors.Trace(c.caller.FacadeCall("Prechecks", args, nil))
+}
+
+// PrecheckProblems runs the target controller's migration prechecks,
+// returning every problem found rather than only the first. Controllers
+// that don't support this return a NotSupported error.
+func (c *Client) PrecheckProblems(model coremigration.ModelInfo) ([]error, error) {
+	if c.caller.BestAPIVersion() < 2 {
+		return nil, errors.NotSupportedf("reporting all precheck problems")
+	}
+	args := migrationModelInfo(model)
+	var results params.ErrorResults
+	if err := c.caller.FacadeCall("PrecheckProblems", args, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	var problems []error
+	for _, result := range results.Results {
+		if result.Error != nil {
+			problems = append(problems, result.Error)
+		}
+	}
+	return problems, nil
+}
+
+func migrationModelInfo(model coremigration.ModelInfo) params.MigrationModelInfo {
+	return params.MigrationModelInfo{
 		UUID:                   model.UUID,
 		Name:                   model.Name,
 		OwnerTag:               model.Owner.String(),
 		AgentVersion:           model.AgentVersion,
 		ControllerAgentVersion: model.ControllerAgentVersion,
 	}
-	return errors.Trace(c.caller.FacadeCall("Prechecks", args, nil))
+}
+
+// ImportDryRun asks the target controller to import the serialized
+// model as a throwaway model, which it removes again, to check that the
+// model can be migrated. Any conversions the target controller makes to
+// the model's description format are returned. Controllers that don't
+// support this return a NotSupported error.
+func (c *Client) ImportDryRun(bytes []byte) ([]string, error) {
+	if c.caller.BestAPIVersion() < 2 {
+		return nil, errors.NotSupportedf("import dry runs")
+	}
+	serialized := params.SerializedModel{Bytes: bytes}
+	var result params.MigrationPrecheckImportResult
+	if err := c.caller.FacadeCall("ImportDryRun", serialized, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if result.Error != nil {
+		return nil, errors.Trace(result.Error)
+	}
+	return result.Upconversions, nil
 }
 
 // Import takes a serialized model and imports it into the target
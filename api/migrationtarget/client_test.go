@@ -78,6 +78,74 @@ func (s *ClientSuite) TestPrechecks(c *gc.C) {
 	})
 }
 
+func (s *ClientSuite) TestPrecheckProblems(c *gc.C) {
+	var stub jujutesting.Stub
+	apiCaller := apitesting.BestVersionCaller{
+		APICallerFunc: func(objType string, version int, id, request string, arg, result interface{}) error {
+			stub.AddCall(objType+"."+request, id, arg)
+			*(result.(*params.ErrorResults)) = params.ErrorResults{Results: []params.ErrorResult{
+				{Error: &params.Error{Message: "upgrade in progress"}},
+				{Error: &params.Error{Message: `model named "name" already exists`}},
+			}}
+			return nil
+		},
+		BestVersion: 2,
+	}
+	client := migrationtarget.NewClient(apiCaller)
+	ownerTag := names.NewUserTag("owner")
+	problems, err := client.PrecheckProblems(coremigration.ModelInfo{
+		UUID:  "uuid",
+		Owner: ownerTag,
+		Name:  "name",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(problems, gc.HasLen, 2)
+	c.Check(problems[0], gc.ErrorMatches, "upgrade in progress")
+	c.Check(problems[1], gc.ErrorMatches, `model named "name" already exists`)
+	stub.CheckCalls(c, []jujutesting.StubCall{
+		{"MigrationTarget.PrecheckProblems", []interface{}{"", params.MigrationModelInfo{
+			UUID:     "uuid",
+			Name:     "name",
+			OwnerTag: ownerTag.String(),
+		}}},
+	})
+}
+
+func (s *ClientSuite) TestPrecheckProblemsNotSupported(c *gc.C) {
+	client, stub := s.getClientAndStub(c)
+	_, err := client.PrecheckProblems(coremigration.ModelInfo{})
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	stub.CheckNoCalls(c)
+}
+
+func (s *ClientSuite) TestImportDryRun(c *gc.C) {
+	var stub jujutesting.Stub
+	apiCaller := apitesting.BestVersionCaller{
+		APICallerFunc: func(objType string, version int, id, request string, arg, result interface{}) error {
+			stub.AddCall(objType+"."+request, id, arg)
+			*(result.(*params.MigrationPrecheckImportResult)) = params.MigrationPrecheckImportResult{
+				Upconversions: []string{"model upconverted from version 7 to 8"},
+			}
+			return nil
+		},
+		BestVersion: 2,
+	}
+	client := migrationtarget.NewClient(apiCaller)
+	upconversions, err := client.ImportDryRun([]byte("foo"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(upconversions, jc.DeepEquals, []string{"model upconverted from version 7 to 8"})
+	stub.CheckCalls(c, []jujutesting.StubCall{
+		{"MigrationTarget.ImportDryRun", []interface{}{"", params.SerializedModel{Bytes: []byte("foo")}}},
+	})
+}
+
+func (s *ClientSuite) TestImportDryRunNotSupported(c *gc.C) {
+	client, stub := s.getClientAndStub(c)
+	_, err := client.ImportDryRun([]byte("foo"))
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	stub.CheckNoCalls(c)
+}
+
 func (s *ClientSuite) TestImport(c *gc.C) {
 	client, stub := s.getClientAndStub(c)
 
@@ -69,6 +69,30 @@ func (c *Client) CommitBranch(branchName string) (int, error) {
 	return result.Result, nil
 }
 
+// SetPromotionPolicy sets the branch to be automatically committed once all
+// of the units tracking it have been healthy for the input soak time. If
+// they are not healthy once the soak time has elapsed, the units are
+// reverted and the branch is aborted.
+// A zero soak time cancels automatic promotion.
+func (c *Client) SetPromotionPolicy(branchName string, soakTime time.Duration) error {
+	if c.facade.BestAPIVersion() < 5 {
+		return errors.NotSupportedf("automatic branch promotion on this version of Juju")
+	}
+	arg := params.BranchPromotionArg{
+		BranchName: branchName,
+		SoakTime:   soakTime,
+	}
+	var result params.ErrorResult
+	err := c.facade.FacadeCall("SetPromotionPolicy", arg, &result)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if result.Error != nil {
+		return errors.Trace(result.Error)
+	}
+	return nil
+}
+
 // ListCommits returns the details of all committed model branches.
 func (c *Client) ListCommits() (model.GenerationCommits, error) {
 	var result params.BranchResults
@@ -184,6 +208,10 @@ func generationInfoFromResult(
 				ApplicationName: a.ApplicationName,
 				UnitProgress:    a.UnitProgress,
 				ConfigChanges:   a.ConfigChanges,
+				CharmURL:        a.CharmURL,
+			}
+			if a.Constraints != nil {
+				bApp.Constraints = a.Constraints.String()
 			}
 			if detailed {
 				bApp.UnitDetail = &model.GenerationUnits{
@@ -193,11 +221,15 @@ func generationInfoFromResult(
 			}
 			appDeltas[i] = bApp
 		}
-		summaries[res.BranchName] = model.Generation{
+		gen := model.Generation{
 			Created:      formatTime(time.Unix(res.Created, 0)),
 			CreatedBy:    res.CreatedBy,
 			Applications: appDeltas,
 		}
+		if res.PromoteAt != 0 {
+			gen.PromoteAt = formatTime(time.Unix(res.PromoteAt, 0))
+		}
+		summaries[res.BranchName] = gen
 	}
 	return summaries
 }
@@ -222,8 +254,12 @@ func generationCommitFromResult(result params.GenerationResult) model.Generation
 		app := model.GenerationApplication{
 			ApplicationName: a.ApplicationName,
 			ConfigChanges:   a.ConfigChanges,
+			CharmURL:        a.CharmURL,
 			UnitDetail:      &model.GenerationUnits{UnitsTracking: a.UnitsTracking},
 		}
+		if a.Constraints != nil {
+			app.Constraints = a.Constraints.String()
+		}
 		appChanges[i] = app
 	}
 	modelCommit := model.GenerationCommit{
@@ -7,12 +7,14 @@ import (
 	"time"
 
 	"github.com/golang/mock/gomock"
+	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/api/base/mocks"
 	"github.com/juju/juju/api/modelgeneration"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/constraints"
 	"github.com/juju/juju/core/model"
 )
 
@@ -111,6 +113,29 @@ func (s *modelGenerationSuite) TestCommitBranch(c *gc.C) {
 	c.Check(newGenID, gc.Equals, 2)
 }
 
+func (s *modelGenerationSuite) TestSetPromotionPolicy(c *gc.C) {
+	defer s.setUpMocks(c).Finish()
+
+	resultSource := params.ErrorResult{}
+	arg := params.BranchPromotionArg{BranchName: s.branchName, SoakTime: time.Hour}
+	s.fCaller.EXPECT().BestAPIVersion().Return(5)
+	s.fCaller.EXPECT().FacadeCall("SetPromotionPolicy", arg, gomock.Any()).SetArg(2, resultSource).Return(nil)
+
+	api := modelgeneration.NewStateFromCaller(s.fCaller)
+	err := api.SetPromotionPolicy(s.branchName, time.Hour)
+	c.Assert(err, gc.IsNil)
+}
+
+func (s *modelGenerationSuite) TestSetPromotionPolicyNotSupported(c *gc.C) {
+	defer s.setUpMocks(c).Finish()
+
+	s.fCaller.EXPECT().BestAPIVersion().Return(4)
+
+	api := modelgeneration.NewStateFromCaller(s.fCaller)
+	err := api.SetPromotionPolicy(s.branchName, time.Hour)
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
 func (s *modelGenerationSuite) TestHasActiveBranch(c *gc.C) {
 	defer s.setUpMocks(c).Finish()
 
@@ -127,10 +152,13 @@ func (s *modelGenerationSuite) TestHasActiveBranch(c *gc.C) {
 func (s *modelGenerationSuite) TestBranchInfo(c *gc.C) {
 	defer s.setUpMocks(c).Finish()
 
+	cons := constraints.MustParse("mem=4G")
+
 	resultSource := params.BranchResults{Generations: []params.Generation{{
 		BranchName: "new-branch",
 		Created:    time.Time{}.Unix(),
 		CreatedBy:  "test-user",
+		PromoteAt:  time.Time{}.Add(time.Hour).Unix(),
 		Applications: []params.GenerationApplication{
 			{
 				ApplicationName: "redis",
@@ -138,6 +166,8 @@ func (s *modelGenerationSuite) TestBranchInfo(c *gc.C) {
 				UnitsTracking:   []string{"redis/0"},
 				UnitsPending:    []string{"redis/1"},
 				ConfigChanges:   map[string]interface{}{"databases": 8},
+				CharmURL:        "cs:redis-2",
+				Constraints:     &cons,
 			},
 		},
 	}}}
@@ -160,6 +190,7 @@ func (s *modelGenerationSuite) TestBranchInfo(c *gc.C) {
 		s.branchName: {
 			Created:   "0001-01-01 00:00:00",
 			CreatedBy: "test-user",
+			PromoteAt: "0001-01-01 01:00:00",
 			Applications: []model.GenerationApplication{{
 				ApplicationName: "redis",
 				UnitProgress:    "1/2",
@@ -168,6 +199,8 @@ func (s *modelGenerationSuite) TestBranchInfo(c *gc.C) {
 					UnitsPending:  []string{"redis/1"},
 				},
 				ConfigChanges: map[string]interface{}{"databases": 8},
+				CharmURL:      "cs:redis-2",
+				Constraints:   "mem=4096M",
 			}},
 		},
 	})
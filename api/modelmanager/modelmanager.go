@@ -436,6 +436,53 @@ func (c *Client) RevokeModel(user, access string, modelUUIDs ...string) error {
 	return c.modifyModelUser(params.RevokeModelAccess, user, access, modelUUIDs)
 }
 
+// GrantGroupModel grants the members of a group access to the
+// specified models.
+func (c *Client) GrantGroupModel(group, access string, modelUUIDs ...string) error {
+	return c.modifyModelGroup(params.GrantModelAccess, group, access, modelUUIDs)
+}
+
+// RevokeGroupModel revokes the access of a group's members to the
+// specified models.
+func (c *Client) RevokeGroupModel(group, access string, modelUUIDs ...string) error {
+	return c.modifyModelGroup(params.RevokeModelAccess, group, access, modelUUIDs)
+}
+
+func (c *Client) modifyModelGroup(action params.ModelAction, group, access string, modelUUIDs []string) error {
+	if c.BestAPIVersion() < 9 {
+		return errors.NotSupportedf("model access for groups")
+	}
+	if group == "" {
+		return errors.New("empty group name")
+	}
+	modelAccess := permission.Access(access)
+	if err := permission.ValidateModelAccess(modelAccess); err != nil {
+		return errors.Trace(err)
+	}
+	var args params.ModifyModelAccessRequest
+	for _, m := range modelUUIDs {
+		if !names.IsValidModel(m) {
+			return errors.Errorf("invalid model: %q", m)
+		}
+		args.Changes = append(args.Changes, params.ModifyModelAccess{
+			GroupName: group,
+			Action:    action,
+			Access:    params.UserAccessPermission(modelAccess),
+			ModelTag:  names.NewModelTag(m).String(),
+		})
+	}
+
+	var result params.ErrorResults
+	err := c.facade.FacadeCall("ModifyModelAccess", args, &result)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(result.Results) != len(args.Changes) {
+		return errors.Errorf("expected %d results, got %d", len(args.Changes), len(result.Results))
+	}
+	return result.Combine()
+}
+
 func (c *Client) modifyModelUser(action params.ModelAction, user, access string, modelUUIDs []string) error {
 	var args params.ModifyModelAccessRequest
 
@@ -711,6 +711,37 @@ func (s *modelmanagerSuite) TestChangeModelCredentialV4(c *gc.C) {
 	c.Assert(called, jc.IsFalse)
 }
 
+func (s *modelmanagerSuite) TestGrantGroupModel(c *gc.C) {
+	apiCaller := basetesting.BestVersionCaller{
+		APICallerFunc: func(objType string, version int, id, request string, arg, result interface{}) error {
+			c.Check(objType, gc.Equals, "ModelManager")
+			c.Check(request, gc.Equals, "ModifyModelAccess")
+			c.Check(arg, jc.DeepEquals, params.ModifyModelAccessRequest{
+				Changes: []params.ModifyModelAccess{{
+					GroupName: "ops",
+					Action:    params.GrantModelAccess,
+					Access:    params.ModelAdminAccess,
+					ModelTag:  coretesting.ModelTag.String(),
+				}},
+			})
+			*(result.(*params.ErrorResults)) = params.ErrorResults{
+				Results: []params.ErrorResult{{}},
+			}
+			return nil
+		},
+		BestVersion: 9,
+	}
+	client := modelmanager.NewClient(apiCaller)
+	err := client.GrantGroupModel("ops", "admin", coretesting.ModelTag.Id())
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *modelmanagerSuite) TestRevokeGroupModelNotSupported(c *gc.C) {
+	client := modelmanager.NewClient(basetesting.BestVersionCaller{BestVersion: 8})
+	err := client.RevokeGroupModel("ops", "read", coretesting.ModelTag.Id())
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
 type dumpModelSuite struct {
 	coretesting.BaseSuite
 }
@@ -0,0 +1,98 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package roles provides access to the Roles facade, which manages
+// custom roles and their assignment to model users.
+package roles
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/permission"
+)
+
+const rolesFacade = "Roles"
+
+// Client allows access to the roles API end point.
+type Client struct {
+	base.ClientFacade
+	facade base.FacadeCaller
+}
+
+// NewClient creates a new client for accessing the roles API.
+func NewClient(st base.APICallCloser) *Client {
+	frontend, backend := base.NewClientFacade(st, rolesFacade)
+	return &Client{
+		ClientFacade: frontend,
+		facade:       backend,
+	}
+}
+
+// AddRole adds a custom role.
+func (c *Client) AddRole(role permission.Role) error {
+	args := params.Roles{
+		Roles: []params.Role{{
+			Name:       role.Name,
+			Access:     string(role.Access),
+			Operations: role.Operations,
+		}},
+	}
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall("AddRoles", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
+
+// ListRoles returns the custom roles defined in the controller.
+func (c *Client) ListRoles() ([]permission.Role, error) {
+	var result params.Roles
+	if err := c.facade.FacadeCall("ListRoles", nil, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	roles := make([]permission.Role, len(result.Roles))
+	for i, role := range result.Roles {
+		roles[i] = permission.Role{
+			Name:       role.Name,
+			Access:     permission.Access(role.Access),
+			Operations: role.Operations,
+		}
+	}
+	return roles, nil
+}
+
+// RemoveRole removes the named custom role.
+func (c *Client) RemoveRole(name string) error {
+	args := params.RoleNames{Names: []string{name}}
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall("RemoveRoles", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
+
+// SetModelUserRole assigns the named custom role to the user on the
+// model with the given UUID. An empty role removes the user's role.
+func (c *Client) SetModelUserRole(modelUUID, user, role string) error {
+	if !names.IsValidModel(modelUUID) {
+		return errors.NotValidf("model UUID %q", modelUUID)
+	}
+	if !names.IsValidUser(user) {
+		return errors.NotValidf("user name %q", user)
+	}
+	args := params.ModelUserRoles{
+		Roles: []params.ModelUserRole{{
+			ModelTag: names.NewModelTag(modelUUID).String(),
+			UserTag:  names.NewUserTag(user).String(),
+			Role:     role,
+		}},
+	}
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall("SetModelUserRoles", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
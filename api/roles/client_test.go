@@ -0,0 +1,104 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package roles_test
+
+import (
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/roles"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/permission"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type rolesSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&rolesSuite{})
+
+func (s *rolesSuite) TestAddRole(c *gc.C) {
+	var called bool
+	client := roles.NewClient(basetesting.APICallerFunc(
+		func(objType string, version int, id, request string, a, result interface{}) error {
+			called = true
+			c.Check(objType, gc.Equals, "Roles")
+			c.Check(request, gc.Equals, "AddRoles")
+			c.Check(a, jc.DeepEquals, params.Roles{
+				Roles: []params.Role{{Name: "app-team", Access: "read", Operations: []string{"run-action", "config"}}},
+			})
+			*(result.(*params.ErrorResults)) = params.ErrorResults{
+				Results: []params.ErrorResult{{Error: &params.Error{Message: "boom"}}},
+			}
+			return nil
+		},
+	))
+	err := client.AddRole(permission.Role{
+		Name:       "app-team",
+		Access:     permission.ReadAccess,
+		Operations: []string{"run-action", "config"},
+	})
+	c.Check(err, gc.ErrorMatches, "boom")
+	c.Check(called, jc.IsTrue)
+}
+
+func (s *rolesSuite) TestListRoles(c *gc.C) {
+	client := roles.NewClient(basetesting.APICallerFunc(
+		func(objType string, version int, id, request string, a, result interface{}) error {
+			c.Check(request, gc.Equals, "ListRoles")
+			*(result.(*params.Roles)) = params.Roles{
+				Roles: []params.Role{{Name: "app-team", Access: "write", Operations: []string{"run-action"}}},
+			}
+			return nil
+		},
+	))
+	result, err := client.ListRoles()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, []permission.Role{{
+		Name:       "app-team",
+		Access:     permission.WriteAccess,
+		Operations: []string{"run-action"},
+	}})
+}
+
+func (s *rolesSuite) TestRemoveRole(c *gc.C) {
+	client := roles.NewClient(basetesting.APICallerFunc(
+		func(objType string, version int, id, request string, a, result interface{}) error {
+			c.Check(request, gc.Equals, "RemoveRoles")
+			c.Check(a, jc.DeepEquals, params.RoleNames{Names: []string{"app-team"}})
+			*(result.(*params.ErrorResults)) = params.ErrorResults{Results: []params.ErrorResult{{}}}
+			return nil
+		},
+	))
+	err := client.RemoveRole("app-team")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *rolesSuite) TestSetModelUserRole(c *gc.C) {
+	modelUUID := coretesting.ModelTag.Id()
+	client := roles.NewClient(basetesting.APICallerFunc(
+		func(objType string, version int, id, request string, a, result interface{}) error {
+			c.Check(request, gc.Equals, "SetModelUserRoles")
+			c.Check(a, jc.DeepEquals, params.ModelUserRoles{
+				Roles: []params.ModelUserRole{{
+					ModelTag: coretesting.ModelTag.String(),
+					UserTag:  "user-bob",
+					Role:     "app-team",
+				}},
+			})
+			*(result.(*params.ErrorResults)) = params.ErrorResults{Results: []params.ErrorResult{{}}}
+			return nil
+		},
+	))
+	err := client.SetModelUserRole(modelUUID, "bob", "app-team")
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = client.SetModelUserRole("foo", "bob", "app-team")
+	c.Assert(err, gc.ErrorMatches, `model UUID "foo" not valid`)
+	err = client.SetModelUserRole(modelUUID, "bob!", "app-team")
+	c.Assert(err, gc.ErrorMatches, `user name "bob!" not valid`)
+}
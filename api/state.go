@@ -30,6 +30,7 @@ import (
 	"github.com/juju/juju/core/network"
 	"github.com/juju/juju/feature"
 	"github.com/juju/juju/rpc"
+	"github.com/juju/juju/rpc/cborcodec"
 )
 
 // Login authenticates as the entity with the given name and password
@@ -52,7 +53,17 @@ func (st *state) Login(tag names.Tag, password, nonce string, macaroons []macaro
 	if featureflag.Enabled(feature.DeveloperMode) {
 		request.UserData = string(debug.Stack())
 	}
+	if st.codec != nil && st.codec.SupportsBinary() {
+		request.Codecs = st.codecs
+	}
 
+	if tag == nil {
+		// External users may present an ID token obtained from
+		// the controller's OpenID Connect provider, and automation
+		// users an API token.
+		request.IDToken = st.idToken
+		request.Token = st.token
+	}
 	if password == "" {
 		// Add any macaroons from the cookie jar that might work for
 		// authenticating the login request.
@@ -145,6 +156,28 @@ func (st *state) Login(tag names.Tag, password, nonce string, macaroons []macaro
 			return errors.Errorf("login with discharged macaroons failed: %s", result.DischargeRequiredReason)
 		}
 	}
+	if result.OIDCLoginRequired != nil {
+		// The controller requires an ID token from its OpenID
+		// Connect provider. Obtain one and retry the login.
+		info := *result.OIDCLoginRequired
+		if st.oidcLogin == nil {
+			return errors.Unauthorizedf("%s (use \"juju login\" to log in)", info.Reason)
+		}
+		idToken, err := st.oidcLogin(st.ctx, info)
+		if err != nil {
+			return errors.Annotatef(err, "cannot log in with OpenID Connect provider %q", info.Issuer)
+		}
+		st.idToken = idToken
+		request.IDToken = idToken
+		result = params.LoginResult{} // zero result
+		err = st.APICall("Admin", 3, "", "Login", request, &result)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if result.OIDCLoginRequired != nil {
+			return errors.Errorf("login with ID token failed: %s", result.OIDCLoginRequired.Reason)
+		}
+	}
 
 	var controllerAccess string
 	var modelAccess string
@@ -173,6 +206,28 @@ func (st *state) Login(tag names.Tag, password, nonce string, macaroons []macaro
 	if err != nil {
 		return errors.Trace(err)
 	}
+	if result.Codec != "" {
+		if err := st.useCodec(result.Codec, request.Codecs); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// useCodec switches the connection to the message encoding
+// chosen by the controller at login from those requested.
+func (st *state) useCodec(name string, requested []string) error {
+	found := false
+	for _, codec := range requested {
+		found = found || codec == name
+	}
+	if !found || name != cborcodec.Name {
+		return errors.Errorf("controller chose unrequested codec %q", name)
+	}
+	if err := st.codec.EnableBinary(); err != nil {
+		return errors.Trace(err)
+	}
+	logger.Debugf("using %s encoding for API messages", name)
 	return nil
 }
 
@@ -71,6 +71,21 @@ func (api *API) ListSubnets(spaceTag *names.SpaceTag, zone string) ([]params.Sub
 	return response.Results, nil
 }
 
+// AllZones returns all availability zones known to the model,
+// along with whether each zone is available.
+func (api *API) AllZones() ([]params.ZoneResult, error) {
+	var response params.ZoneResults
+	if err := api.facade.FacadeCall("AllZones", nil, &response); err != nil {
+		return nil, errors.Trace(err)
+	}
+	for _, result := range response.Results {
+		if result.Error != nil {
+			return nil, errors.Trace(result.Error)
+		}
+	}
+	return response.Results, nil
+}
+
 // SubnetsByCIDR returns the collection of subnets matching each CIDR in the
 // input.
 func (api *API) SubnetsByCIDR(cidrs []string) ([]params.SubnetsResult, error) {
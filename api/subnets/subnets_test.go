@@ -246,3 +246,31 @@ func (s *SubnetsSuite) TestSubnetsByCIDRWithResults(c *gc.C) {
 		}},
 	}}, nil, "")
 }
+
+func (s *SubnetsSuite) TestAllZones(c *gc.C) {
+	zones := []params.ZoneResult{
+		{Name: "node01", Available: true},
+		{Name: "node02", Available: false},
+	}
+	s.prepareAPICall(c, apitesting.APICall{
+		Facade:  "Subnets",
+		Method:  "AllZones",
+		Results: params.ZoneResults{Results: zones},
+	})
+	results, err := s.api.AllZones()
+	c.Assert(s.apiCaller.CallCount, gc.Equals, 1)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, zones)
+}
+
+func (s *SubnetsSuite) TestAllZonesFails(c *gc.C) {
+	s.prepareAPICall(c, apitesting.APICall{
+		Facade: "Subnets",
+		Method: "AllZones",
+		Error:  errors.New("bang"),
+	})
+	results, err := s.api.AllZones()
+	c.Assert(s.apiCaller.CallCount, gc.Equals, 1)
+	c.Assert(err, gc.ErrorMatches, "bang")
+	c.Assert(results, gc.IsNil)
+}
@@ -164,6 +164,13 @@ func (c *Client) UserInfo(usernames []string, all IncludeDisabled) ([]params.Use
 
 // SetPassword changes the password for the specified user.
 func (c *Client) SetPassword(username, password string) error {
+	return c.SetPasswordWithMFACode(username, password, "")
+}
+
+// SetPasswordWithMFACode changes the password for the specified user,
+// presenting a multi-factor authentication code for the current user,
+// which the controller requires if they are enrolled.
+func (c *Client) SetPasswordWithMFACode(username, password, code string) error {
 	if !names.IsValidUser(username) {
 		return errors.Errorf("%q is not a valid username", username)
 	}
@@ -171,7 +178,9 @@ func (c *Client) SetPassword(username, password string) error {
 	args := params.EntityPasswords{
 		Changes: []params.EntityPassword{{
 			Tag:      tag.String(),
-			Password: password}},
+			Password: password,
+			MFACode:  code,
+		}},
 	}
 	var results params.ErrorResults
 	err := c.facade.FacadeCall("SetPassword", args, &results)
@@ -207,3 +216,49 @@ func (c *Client) ResetPassword(username string) ([]byte, error) {
 	}
 	return result.SecretKey, nil
 }
+
+// BeginMFAEnrolment starts enrolling the current user for multi-factor
+// authentication. It returns the shared secret for their authenticator
+// app, and the otpauth URI for it.
+func (c *Client) BeginMFAEnrolment() (secret, uri string, _ error) {
+	if c.BestAPIVersion() < 3 {
+		return "", "", errors.NotSupportedf("multi-factor authentication on this controller")
+	}
+	var result params.MFAEnrolment
+	if err := c.facade.FacadeCall("BeginMFAEnrolment", nil, &result); err != nil {
+		return "", "", errors.Trace(err)
+	}
+	return result.Secret, result.URI, nil
+}
+
+// ConfirmMFAEnrolment completes the current user's enrolment for
+// multi-factor authentication with a code from their authenticator
+// app, and returns their recovery codes.
+func (c *Client) ConfirmMFAEnrolment(code string) ([]string, error) {
+	if c.BestAPIVersion() < 3 {
+		return nil, errors.NotSupportedf("multi-factor authentication on this controller")
+	}
+	var result params.MFARecoveryCodes
+	if err := c.facade.FacadeCall("ConfirmMFAEnrolment", params.MFACode{Code: code}, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return result.Codes, nil
+}
+
+// DisableMFA removes the current user's multi-factor authentication
+// enrolment, given a current code.
+func (c *Client) DisableMFA(code string) error {
+	if c.BestAPIVersion() < 3 {
+		return errors.NotSupportedf("multi-factor authentication on this controller")
+	}
+	return errors.Trace(c.facade.FacadeCall("DisableMFA", params.MFACode{Code: code}, nil))
+}
+
+// ResetMFA removes the multi-factor authentication enrolment of the
+// specified user.
+func (c *Client) ResetMFA(username string) error {
+	if c.BestAPIVersion() < 3 {
+		return errors.NotSupportedf("multi-factor authentication on this controller")
+	}
+	return c.userCall(username, "ResetMFA")
+}
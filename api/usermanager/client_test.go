@@ -4,6 +4,8 @@
 package usermanager_test
 
 import (
+	"time"
+
 	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
@@ -11,7 +13,9 @@ import (
 	apitesting "github.com/juju/juju/api/base/testing"
 	"github.com/juju/juju/api/usermanager"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/totp"
 	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
 	"github.com/juju/juju/testing/factory"
 )
 
@@ -270,3 +274,54 @@ func (s *usermanagerSuite) TestResetPasswordResultCount(c *gc.C) {
 	_, err := client.ResetPassword("foobar")
 	c.Assert(err, gc.ErrorMatches, "expected 1 result, got 2")
 }
+
+func (s *usermanagerSuite) TestMFAEnrolment(c *gc.C) {
+	secret, uri, err := s.usermanager.BeginMFAEnrolment()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(uri, gc.Matches, "otpauth://totp/.*secret="+secret)
+
+	code, err := totp.Code(secret, time.Now())
+	c.Assert(err, jc.ErrorIsNil)
+	recovery, err := s.usermanager.ConfirmMFAEnrolment(code)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(recovery, gc.HasLen, state.MFARecoveryCodeCount)
+
+	user, err := s.State.User(s.AdminUserTag(c))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(user.MFAEnabled(), jc.IsTrue)
+
+	// Changing passwords now requires a code.
+	s.Factory.MakeUser(c, &factory.UserParams{Name: "foobar"})
+	err = s.usermanager.SetPassword("foobar", "new-password")
+	c.Assert(err, jc.Satisfies, params.IsCodeMFARequired)
+	err = s.usermanager.SetPasswordWithMFACode("foobar", "new-password", recovery[0])
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.usermanager.DisableMFA(recovery[1])
+	c.Assert(err, jc.ErrorIsNil)
+	err = user.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(user.MFAEnabled(), jc.IsFalse)
+}
+
+func (s *usermanagerSuite) TestResetMFA(c *gc.C) {
+	err := s.usermanager.ResetMFA(s.AdminUserTag(c).Id())
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	err = s.usermanager.ResetMFA("not/valid")
+	c.Assert(err, gc.ErrorMatches, `"not/valid" is not a valid username`)
+}
+
+func (s *usermanagerSuite) TestMFANotSupported(c *gc.C) {
+	apiCaller := apitesting.BestVersionCaller{
+		APICallerFunc: func(string, int, string, string, interface{}, interface{}) error {
+			c.Fatalf("unexpected call")
+			return nil
+		},
+		BestVersion: 2,
+	}
+	client := usermanager.NewClient(apiCaller)
+	_, _, err := client.BeginMFAEnrolment()
+	c.Assert(err, gc.ErrorMatches, "multi-factor authentication on this controller not supported")
+	err = client.ResetMFA("foobar")
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/juju/juju/core/network"
 	"github.com/juju/juju/core/permission"
 	"github.com/juju/juju/rpc"
+	"github.com/juju/juju/rpc/cborcodec"
 	"github.com/juju/juju/state"
 	jujuversion "github.com/juju/juju/version"
 )
@@ -95,6 +96,17 @@ func (a *admin) login(ctx context.Context, req params.LoginRequest, loginVersion
 		logger.Infof("login failed with discharge-required error: %v", err)
 		return loginResult, nil
 	}
+	if err, ok := errors.Cause(err).(*common.OIDCLoginRequiredError); ok {
+		logger.Infof("login failed with OIDC-login-required error: %v", err)
+		return params.LoginResult{
+			OIDCLoginRequired: &params.OIDCLoginInfo{
+				Issuer:   err.Issuer,
+				ClientID: err.ClientID,
+				Scopes:   err.Scopes,
+				Reason:   err.Error(),
+			},
+		}, nil
+	}
 	if err != nil {
 		return fail, errors.Trace(err)
 	}
@@ -160,6 +172,7 @@ func (a *admin) login(ctx context.Context, req params.LoginRequest, loginVersion
 		a.apiObserver, auditRecorder, auditConfig.CaptureAPIArgs,
 	)
 	a.root.rpcConn.ServeRoot(apiRoot, recorderFactory, serverError)
+	codec := a.negotiateCodec(req.Codecs)
 	return params.LoginResult{
 		Servers:       params.FromHostsPorts(pServers),
 		ControllerTag: a.root.model.ControllerTag().String(),
@@ -168,32 +181,63 @@ func (a *admin) login(ctx context.Context, req params.LoginRequest, loginVersion
 		PublicDNSName: a.srv.publicDNSName(),
 		ModelTag:      modelTag,
 		Facades:       filterFacades(a.srv.facades, facadeFilters...),
+		Codec:         codec,
 	}, nil
 }
 
+// negotiateCodec returns the first of the requested message encodings
+// that the server supports, or "" if the connection should continue
+// to use only JSON. The connection only accepts CBOR messages once it
+// has been chosen here, which is before the login result is sent.
+func (a *admin) negotiateCodec(requested []string) string {
+	codec := a.root.codec
+	if codec == nil || !codec.SupportsBinary() {
+		return ""
+	}
+	for _, name := range requested {
+		if name != cborcodec.Name {
+			continue
+		}
+		if err := codec.EnableBinary(); err != nil {
+			logger.Warningf("cannot use %s encoding: %v", name, err)
+			return ""
+		}
+		return name
+	}
+	return ""
+}
+
 func (a *admin) getAuditRecorder(req params.LoginRequest, authResult *authResult, cfg auditlog.Config) (*auditlog.Recorder, error) {
 	if !authResult.userLogin || !cfg.Enabled {
 		return nil, nil
 	}
+	result, err := newAuditRecorder(a.srv, cfg, auditlog.ConversationArgs{
+		Who:          a.root.entity.Tag().Id(),
+		What:         req.CLIArgs,
+		ModelName:    a.root.model.Name(),
+		ModelUUID:    a.root.model.UUID(),
+		ConnectionID: a.root.connectionID,
+		TokenID:      a.root.tokenID,
+	})
+	if err != nil {
+		logger.Errorf("couldn't add login to audit log: %+v", err)
+		return nil, errors.Trace(err)
+	}
+	return result, nil
+}
+
+// newAuditRecorder returns a recorder that adds a conversation and
+// its requests to the audit log.
+func newAuditRecorder(srv *Server, cfg auditlog.Config, args auditlog.ConversationArgs) (*auditlog.Recorder, error) {
 	// Wrap the audit logger in a filter that prevents us from logging
 	// lots of readonly conversations (like "juju status" requests).
 	filter := observer.MakeInterestingRequestFilter(cfg.ExcludeMethods)
 	result, err := auditlog.NewRecorder(
 		observer.NewAuditLogFilter(cfg.Target, filter),
-		a.srv.clock,
-		auditlog.ConversationArgs{
-			Who:          a.root.entity.Tag().Id(),
-			What:         req.CLIArgs,
-			ModelName:    a.root.model.Name(),
-			ModelUUID:    a.root.model.UUID(),
-			ConnectionID: a.root.connectionID,
-		},
+		srv.clock,
+		args,
 	)
-	if err != nil {
-		logger.Errorf("couldn't add login to audit log: %+v", err)
-		return nil, errors.Trace(err)
-	}
-	return result, nil
+	return result, errors.Trace(err)
 }
 
 type authResult struct {
@@ -202,6 +246,7 @@ type authResult struct {
 	userLogin              bool // false if anonymous user
 	controllerOnlyLogin    bool
 	controllerMachineLogin bool
+	passwordExpired        bool
 	userInfo               *params.AuthUserInfo
 }
 
@@ -248,7 +293,7 @@ func (a *admin) authenticate(ctx context.Context, req params.LoginRequest) (*aut
 
 	switch result.tag.(type) {
 	case nil:
-		// Macaroon logins are always for users.
+		// Macaroon and ID token logins are always for users.
 	case names.UserTag:
 		if result.tag.Id() == api.AnonymousUsername && len(req.Macaroons) == 0 {
 			result.anonymousLogin = true
@@ -271,6 +316,7 @@ func (a *admin) authenticate(ctx context.Context, req params.LoginRequest) (*aut
 		}
 
 		result.controllerMachineLogin = authInfo.Controller
+		result.passwordExpired = authInfo.PasswordExpired
 		// controllerConn is used to indicate a connection from
 		// the controller to a non-controller model.
 		controllerConn := false
@@ -283,6 +329,8 @@ func (a *admin) authenticate(ctx context.Context, req params.LoginRequest) (*aut
 
 		// TODO(wallyworld) - we can't yet observe anonymous logins as entity must be non-nil
 		a.root.entity = authInfo.Entity
+		a.root.tokenID = authInfo.TokenID
+		a.root.tokenAccess = authInfo.TokenAccess
 		a.apiObserver.Login(authInfo.Entity.Tag(), a.root.model.ModelTag(), controllerConn, req.UserData)
 	} else if a.root.model == nil {
 		// Anonymous login to unknown model.
@@ -358,6 +406,9 @@ func (a *admin) handleAuthError(err error) error {
 	if err, ok := errors.Cause(err).(*common.DischargeRequiredError); ok {
 		return err
 	}
+	if err, ok := errors.Cause(err).(*common.OIDCLoginRequiredError); ok {
+		return err
+	}
 	if a.maintenanceInProgress() {
 		// An upgrade, restore or similar operation is in
 		// progress. It is possible for logins to fail until this
@@ -423,14 +474,31 @@ func (a *admin) checkUserPermissions(userTag names.UserTag, controllerOnlyLogin
 	} else {
 		return nil, errors.Annotatef(err, "obtaining ControllerUser for logged in user %s", userTag.Id())
 	}
+
+	// Users are also granted the access given to the groups they
+	// belong to.
+	groups := a.root.UserGroups(userTag)
+	groupControllerAccess, err := common.GroupPermission(a.root.state, groups, a.root.state.ControllerTag())
+	if err != nil {
+		return nil, errors.Annotatef(err, "obtaining controller access for groups of %s", userTag.Id())
+	}
+	if groupControllerAccess.GreaterControllerAccessThan(controllerAccess) {
+		controllerAccess = groupControllerAccess
+	}
+
 	if !controllerOnlyLogin {
 		// Only grab modelUser permissions if this is not a controller only
 		// login. In all situations, if the model user is not found, they have
 		// no authorisation to access this model, unless the user is controller
-		// admin.
-
-		var err error
+		// admin or a member of a group with access to the model.
+		groupModelAccess, err := common.GroupPermission(a.root.state, groups, a.root.model.ModelTag())
+		if err != nil {
+			return nil, errors.Annotatef(err, "obtaining model access for groups of %s", userTag.Id())
+		}
 		modelAccess, err = a.root.state.UserPermission(userTag, a.root.model.ModelTag())
+		if (err == nil || errors.IsNotFound(err)) && groupModelAccess.GreaterModelAccessThan(modelAccess) {
+			modelAccess, err = groupModelAccess, nil
+		}
 		if err != nil && controllerAccess != permission.SuperuserAccess {
 			return nil, errors.Wrap(err, common.ErrPerm)
 		}
@@ -444,6 +512,15 @@ func (a *admin) checkUserPermissions(userTag names.UserTag, controllerOnlyLogin
 	if everyoneGroupAccess.GreaterControllerAccessThan(controllerAccess) {
 		controllerAccess = everyoneGroupAccess
 	}
+	// Logins with API tokens are limited to the access of the token.
+	if a.root.tokenID != "" {
+		if controllerAccess.GreaterControllerAccessThan(permission.LoginAccess) {
+			controllerAccess = permission.LoginAccess
+		}
+		if modelAccess.GreaterModelAccessThan(a.root.tokenAccess) {
+			modelAccess = a.root.tokenAccess
+		}
+	}
 	if controllerOnlyLogin || !a.srv.allowModelAccess {
 		// We're either explicitly logging into the controller or
 		// we must check that the user has access to the controller
@@ -47,6 +47,7 @@ import (
 	"github.com/juju/juju/testing"
 	coretesting "github.com/juju/juju/testing"
 	"github.com/juju/juju/testing/factory"
+	jujuversion "github.com/juju/juju/version"
 )
 
 type baseLoginSuite struct {
@@ -668,6 +669,45 @@ func (s *loginSuite) TestAnonymousControllerLogin(c *gc.C) {
 	})
 }
 
+func (s *loginSuite) TestLoginNegotiatesCodec(c *gc.C) {
+	info := s.newServer(c)
+	for i, test := range []struct {
+		requested []string
+		expect    string
+	}{
+		{requested: nil, expect: ""},
+		{requested: []string{"unknown"}, expect: ""},
+		{requested: []string{"unknown", "cbor"}, expect: "cbor"},
+	} {
+		c.Logf("test %d: %v", i, test.requested)
+		conn := s.openAPIWithoutLogin(c, info)
+		var result params.LoginResult
+		request := &params.LoginRequest{
+			AuthTag: names.NewUserTag(api.AnonymousUsername).String(),
+			Codecs:  test.requested,
+		}
+		err := conn.APICall("Admin", 3, "", "Login", request, &result)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(result.Codec, gc.Equals, test.expect)
+	}
+}
+
+func (s *loginSuite) TestLoginWithCBOR(c *gc.C) {
+	info := s.newServer(c)
+	info.Tag = s.Owner
+	info.Password = s.AdminPassword
+	opts := fastDialOpts
+	opts.Codecs = []string{"cbor"}
+	conn, err := api.Open(info, opts)
+	c.Assert(err, jc.ErrorIsNil)
+	defer conn.Close()
+
+	// Requests after login are sent, and answered, as CBOR.
+	v, err := conn.Client().AgentVersion()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(v, gc.Equals, jujuversion.Current)
+}
+
 func (s *loginSuite) TestControllerModel(c *gc.C) {
 	info := s.newServer(c)
 	st := s.openAPIWithoutLogin(c, info)
@@ -23,6 +23,7 @@ import (
 	"github.com/juju/pubsub"
 	"github.com/juju/ratelimit"
 	"github.com/juju/utils"
+	"github.com/juju/worker/v2"
 	"github.com/juju/worker/v2/dependency"
 	"github.com/prometheus/client_golang/prometheus"
 	"gopkg.in/tomb.v2"
@@ -84,6 +85,7 @@ type Server struct {
 	restoreStatus          func() state.RestoreStatus
 	mux                    *apiserverhttp.Mux
 	metricsCollector       *Collector
+	gatewayRoots           *gatewayRootCache
 
 	// mu guards the fields below it.
 	mu sync.Mutex
@@ -329,6 +331,7 @@ func newServer(cfg ServerConfig) (_ *Server, err error) {
 				return
 			}
 			srv.updateAgentRateLimiter(data.Config)
+			srv.updateAuthenticatorConfig(data.Config)
 		})
 	if err != nil {
 		logger.Criticalf("programming error in subscribe function: %v", err)
@@ -364,6 +367,13 @@ func newServer(cfg ServerConfig) (_ *Server, err error) {
 		return nil, errors.Annotate(err, "unable to subscribe to restart message")
 	}
 
+	srv.gatewayRoots, err = newGatewayRootCache(srv.clock)
+	if err != nil {
+		unsubscribe()
+		unsubscribeControllerConfig()
+		return nil, errors.Trace(err)
+	}
+
 	ready := make(chan struct{})
 	srv.tomb.Go(func() error {
 		defer srv.dbloggers.dispose()
@@ -371,6 +381,9 @@ func newServer(cfg ServerConfig) (_ *Server, err error) {
 		defer srv.shared.Close()
 		defer unsubscribe()
 		defer unsubscribeControllerConfig()
+		// The roots cached by the REST gateway are released once
+		// the requests using them have completed.
+		defer worker.Stop(srv.gatewayRoots)
 		return srv.loop(ready)
 	})
 
@@ -434,6 +447,19 @@ func (srv *Server) updateAgentRateLimiter(cfg controller.Config) {
 	}
 }
 
+// controllerConfigUpdater is implemented by authenticators that are
+// configured by controller config, so that they can be told when it
+// changes rather than reading it on every login.
+type controllerConfigUpdater interface {
+	UpdateControllerConfig(controller.Config)
+}
+
+func (srv *Server) updateAuthenticatorConfig(cfg controller.Config) {
+	if updater, ok := srv.authenticator.(controllerConfigUpdater); ok {
+		updater.UpdateControllerConfig(cfg)
+	}
+}
+
 type rateClock struct {
 	clock.Clock
 }
@@ -612,6 +638,9 @@ func (srv *Server) endpoints() []apihttp.Endpoint {
 	modelRestServer := &RestHTTPHandler{
 		GetHandler: modelRestHandler.ServeGet,
 	}
+	modelRESTGatewayHandler := &restGatewayHandler{ctxt: httpCtxt, roots: srv.gatewayRoots}
+	controllerRESTGatewayHandler := &restGatewayHandler{ctxt: httpCtxt, controllerOnly: true, roots: srv.gatewayRoots}
+	restGatewayOpenAPIHandler := &restGatewayOpenAPIHandler{ctxt: httpCtxt}
 	modelCharmsHandler := &charmsHandler{
 		ctxt:          httpCtxt,
 		dataDir:       srv.dataDir,
@@ -621,15 +650,16 @@ func (srv *Server) endpoints() []apihttp.Endpoint {
 		PostHandler: modelCharmsHandler.ServePost,
 		GetHandler:  modelCharmsHandler.ServeGet,
 	}
-	modelCharmsUploadAuthorizer := tagKindAuthorizer{names.UserTagKind}
+	modelCharmsUploadAuthorizer := modelWriteAuthorizer{tagKindAuthorizer{names.UserTagKind}}
 	modelToolsUploadHandler := &toolsUploadHandler{
 		ctxt:          httpCtxt,
 		stateAuthFunc: httpCtxt.stateForRequestAuthenticatedUser,
 	}
-	modelToolsUploadAuthorizer := tagKindAuthorizer{names.UserTagKind}
+	modelToolsUploadAuthorizer := modelWriteAuthorizer{tagKindAuthorizer{names.UserTagKind}}
 	modelToolsDownloadHandler := &toolsDownloadHandler{
 		ctxt: httpCtxt,
 	}
+	charmHubDownloadHandler := &charmHubDownloadHandler{ctxt: httpCtxt}
 	resourcesHandler := &ResourcesHandler{
 		StateAuthFunc: func(req *http.Request, tagKinds ...string) (ResourcesBackend, state.PoolHelper, names.Tag, error) {
 			st, entity, err := httpCtxt.stateForRequestAuthenticatedTag(req, tagKinds...)
@@ -643,6 +673,13 @@ func (srv *Server) endpoints() []apihttp.Endpoint {
 			return rst, st, entity.Tag(), nil
 		},
 		ChangeAllowedFunc: func(req *http.Request) error {
+			authInfo, ok := httpcontext.RequestAuthInfo(req)
+			if !ok {
+				return common.ErrPerm
+			}
+			if err := checkTokenWriteAccess(authInfo); err != nil {
+				return errors.Trace(err)
+			}
 			st, err := httpCtxt.stateForRequestUnauthenticated(req)
 			if err != nil {
 				return errors.Trace(err)
@@ -732,6 +769,13 @@ func (srv *Server) endpoints() []apihttp.Endpoint {
 	}, {
 		pattern: modelRoutePrefix + "/rest/1.0/:entity/:name/:attribute",
 		handler: modelRestServer,
+	}, {
+		// The REST gateway is only served when enabled in
+		// controller config.
+		pattern:    modelRoutePrefix + "/gateway/:facade/:version/:method",
+		methods:    []string{"GET", "POST"},
+		handler:    modelRESTGatewayHandler,
+		authorizer: tagKindAuthorizer{names.UserTagKind},
 	}, {
 		// GET /charms has no authorizer
 		pattern: modelRoutePrefix + "/charms",
@@ -750,6 +794,11 @@ func (srv *Server) endpoints() []apihttp.Endpoint {
 		pattern:         modelRoutePrefix + "/tools/:version",
 		handler:         modelToolsDownloadHandler,
 		unauthenticated: true,
+	}, {
+		pattern:    modelRoutePrefix + "/charmhub/download",
+		methods:    []string{"GET"},
+		handler:    charmHubDownloadHandler,
+		authorizer: tagKindAuthorizer{names.UserTagKind},
 	}, {
 		pattern: modelRoutePrefix + "/applications/:application/resources/:resource",
 		handler: resourcesHandler,
@@ -758,7 +807,13 @@ func (srv *Server) endpoints() []apihttp.Endpoint {
 		handler: unitResourcesHandler,
 	}, {
 		pattern: modelRoutePrefix + "/backups",
+		methods: []string{"GET"},
 		handler: backupHandler,
+	}, {
+		pattern:    modelRoutePrefix + "/backups",
+		methods:    []string{"PUT"},
+		handler:    backupHandler,
+		authorizer: modelWriteAuthorizer{},
 	}, {
 		pattern:    "/migrate/charms",
 		handler:    migrateCharmsHTTPHandler,
@@ -797,6 +852,17 @@ func (srv *Server) endpoints() []apihttp.Endpoint {
 		handler:         healthHandler,
 		unauthenticated: true,
 		noModelUUID:     true,
+	}, {
+		pattern:    "/gateway/:facade/:version/:method",
+		methods:    []string{"GET", "POST"},
+		handler:    controllerRESTGatewayHandler,
+		authorizer: tagKindAuthorizer{names.UserTagKind},
+	}, {
+		pattern:         "/gateway/openapi.json",
+		methods:         []string{"GET"},
+		handler:         restGatewayOpenAPIHandler,
+		unauthenticated: true,
+		noModelUUID:     true,
 	}, {
 		pattern:         "/register",
 		handler:         registerHandler,
@@ -827,9 +893,10 @@ func (srv *Server) endpoints() []apihttp.Endpoint {
 		handler:    modelCharmsHTTPHandler,
 		authorizer: modelCharmsUploadAuthorizer,
 	}, {
-		pattern: "/gui-archive",
-		methods: []string{"POST"},
-		handler: guiArchiveHandler,
+		pattern:    "/gui-archive",
+		methods:    []string{"POST"},
+		handler:    guiArchiveHandler,
+		authorizer: modelWriteAuthorizer{},
 	}, {
 		pattern:         "/gui-archive",
 		methods:         []string{"GET"},
@@ -971,6 +1038,9 @@ func (srv *Server) serveConn(
 	if err == nil {
 		defer st.Release()
 		h, err = newAPIHandler(srv, st.State, conn, modelUUID, connectionID, host)
+		if err == nil {
+			h.codec = codec
+		}
 	}
 	if errors.IsNotFound(err) {
 		err = errors.Wrap(err, common.UnknownModelError(resolvedModelUUID))
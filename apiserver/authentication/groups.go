@@ -0,0 +1,122 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package authentication
+
+import (
+	"crypto/tls"
+	"sync"
+	"time"
+
+	"github.com/juju/clock"
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+
+	"github.com/juju/juju/controller"
+	"github.com/juju/juju/ldap"
+)
+
+// failureTTL is the time for which a failure to look up groups in the
+// directory is remembered, so that a directory outage does not delay
+// every request by the time taken to reach the directory.
+const failureTTL = 30 * time.Second
+
+// GroupResolver resolves the groups that users belong to, using the
+// LDAP directory configured in controller config. It is safe for
+// concurrent use.
+type GroupResolver struct {
+	clock     clock.Clock
+	tlsConfig *tls.Config
+
+	mu          sync.Mutex
+	directory   *ldap.Directory
+	failure     error
+	failedUntil time.Time
+}
+
+// NewGroupResolver returns a new GroupResolver that uses the given
+// clock to expire cached group memberships, and the given TLS config
+// to connect to the directory. If tlsConfig is nil, the system's root
+// certificates are trusted.
+func NewGroupResolver(clock clock.Clock, tlsConfig *tls.Config) *GroupResolver {
+	return &GroupResolver{clock: clock, tlsConfig: tlsConfig}
+}
+
+// UserGroups returns the groups that the user belongs to according to
+// the LDAP directory configured in cfg. It returns no groups if no
+// directory is configured, or if the user is not in the configured
+// user domain. A failure to look up groups is returned again, without
+// contacting the directory, for a short time afterwards.
+func (r *GroupResolver) UserGroups(cfg controller.Config, user names.UserTag) ([]string, error) {
+	if cfg.LDAPURL() == "" {
+		return nil, nil
+	}
+	if domain := cfg.LDAPUserDomain(); domain == "" && !user.IsLocal() || domain != "" && user.Domain() != domain {
+		return nil, nil
+	}
+	directory, err := r.directoryFor(cfg)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := r.recentFailure(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	groups, err := directory.UserGroups(user.Name())
+	if err != nil {
+		r.recordFailure(err)
+		return nil, errors.Trace(err)
+	}
+	return groups, nil
+}
+
+// recentFailure returns the last failure to look up groups, if it
+// happened less than failureTTL ago.
+func (r *GroupResolver) recentFailure() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.failure != nil && r.clock.Now().Before(r.failedUntil) {
+		return r.failure
+	}
+	return nil
+}
+
+func (r *GroupResolver) recordFailure(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.failure = err
+	r.failedUntil = r.clock.Now().Add(failureTTL)
+}
+
+// directoryFor returns the directory described by cfg, replacing the
+// resolver's directory, and so its cache and any remembered failure,
+// if the config has changed.
+func (r *GroupResolver) directoryFor(cfg controller.Config) (*ldap.Directory, error) {
+	config := ldap.DirectoryConfig{
+		URL:             cfg.LDAPURL(),
+		BindDN:          cfg.LDAPBindDN(),
+		BindPassword:    cfg.LDAPBindPassword(),
+		StartTLS:        cfg.LDAPStartTLS(),
+		TLSConfig:       r.tlsConfig,
+		UserDNTemplate:  cfg.LDAPUserDNTemplate(),
+		GroupBaseDN:     cfg.LDAPGroupBaseDN(),
+		MemberAttribute: cfg.LDAPMemberAttribute(),
+		NameAttribute:   cfg.LDAPGroupNameAttribute(),
+		Clock:           r.clock,
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.directory != nil {
+		current := r.directory.Config()
+		current.CacheTTL = 0
+		if current == config {
+			return r.directory, nil
+		}
+	}
+	directory, err := ldap.NewDirectory(config)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	r.directory = directory
+	r.failure = nil
+	return directory, nil
+}
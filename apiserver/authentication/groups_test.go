@@ -0,0 +1,123 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package authentication_test
+
+import (
+	"time"
+
+	"github.com/juju/clock/testclock"
+	"github.com/juju/names/v4"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/authentication"
+	"github.com/juju/juju/controller"
+	"github.com/juju/juju/ldap/ldaptesting"
+)
+
+type groupResolverSuite struct {
+	clock    *testclock.Clock
+	server   *ldaptesting.Server
+	resolver *authentication.GroupResolver
+	config   controller.Config
+}
+
+var _ = gc.Suite(&groupResolverSuite{})
+
+func (s *groupResolverSuite) SetUpTest(c *gc.C) {
+	s.server = ldaptesting.NewServer("ou=groups,dc=example,dc=com")
+	s.server.SetGroup("ops", "uid=bob,ou=people,dc=example,dc=com")
+	s.clock = testclock.NewClock(time.Now())
+	s.resolver = authentication.NewGroupResolver(s.clock, s.server.ClientTLSConfig())
+	s.config = controller.Config{
+		controller.LDAPURL:            s.server.URL,
+		controller.LDAPUserDNTemplate: "uid={user},ou=people,dc=example,dc=com",
+		controller.LDAPGroupBaseDN:    "ou=groups,dc=example,dc=com",
+	}
+}
+
+func (s *groupResolverSuite) TearDownTest(c *gc.C) {
+	s.server.Close()
+}
+
+func (s *groupResolverSuite) TestUserGroups(c *gc.C) {
+	groups, err := s.resolver.UserGroups(s.config, names.NewUserTag("bob"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(groups, jc.DeepEquals, []string{"ops"})
+
+	groups, err = s.resolver.UserGroups(s.config, names.NewUserTag("mary"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(groups, gc.HasLen, 0)
+}
+
+func (s *groupResolverSuite) TestNoDirectoryConfigured(c *gc.C) {
+	delete(s.config, controller.LDAPURL)
+	groups, err := s.resolver.UserGroups(s.config, names.NewUserTag("bob"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(groups, gc.HasLen, 0)
+	c.Assert(s.server.Searches(), gc.Equals, 0)
+}
+
+func (s *groupResolverSuite) TestUserDomain(c *gc.C) {
+	groups, err := s.resolver.UserGroups(s.config, names.NewUserTag("bob@corp"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(groups, gc.HasLen, 0)
+	c.Assert(s.server.Searches(), gc.Equals, 0)
+
+	s.config[controller.LDAPUserDomain] = "corp"
+	groups, err = s.resolver.UserGroups(s.config, names.NewUserTag("bob@corp"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(groups, jc.DeepEquals, []string{"ops"})
+
+	groups, err = s.resolver.UserGroups(s.config, names.NewUserTag("bob"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(groups, gc.HasLen, 0)
+}
+
+func (s *groupResolverSuite) TestConfigChangeResetsCache(c *gc.C) {
+	_, err := s.resolver.UserGroups(s.config, names.NewUserTag("bob"))
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.resolver.UserGroups(s.config, names.NewUserTag("bob"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.server.Searches(), gc.Equals, 1)
+
+	s.server.SetBindCredentials("cn=juju,dc=example,dc=com", "secret")
+	s.config[controller.LDAPBindDN] = "cn=juju,dc=example,dc=com"
+	s.config[controller.LDAPBindPassword] = "secret"
+	s.config[controller.LDAPStartTLS] = true
+	groups, err := s.resolver.UserGroups(s.config, names.NewUserTag("bob"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(groups, jc.DeepEquals, []string{"ops"})
+	c.Assert(s.server.Searches(), gc.Equals, 2)
+}
+
+func (s *groupResolverSuite) TestBindPasswordRequiresStartTLS(c *gc.C) {
+	s.config[controller.LDAPBindDN] = "cn=juju,dc=example,dc=com"
+	s.config[controller.LDAPBindPassword] = "secret"
+	_, err := s.resolver.UserGroups(s.config, names.NewUserTag("bob"))
+	c.Assert(err, gc.ErrorMatches, "bind password over ldap:// without StartTLS not valid")
+	c.Assert(s.server.Searches(), gc.Equals, 0)
+}
+
+func (s *groupResolverSuite) TestFailureRemembered(c *gc.C) {
+	s.server.SetBindCredentials("cn=juju,dc=example,dc=com", "secret")
+	s.config[controller.LDAPBindDN] = "cn=juju,dc=example,dc=com"
+	s.config[controller.LDAPBindPassword] = "wrong"
+	s.config[controller.LDAPStartTLS] = true
+	_, err := s.resolver.UserGroups(s.config, names.NewUserTag("bob"))
+	c.Assert(err, gc.NotNil)
+
+	// The directory is not asked again until the failure expires,
+	// even for other users.
+	s.server.SetBindCredentials("cn=juju,dc=example,dc=com", "wrong")
+	_, err = s.resolver.UserGroups(s.config, names.NewUserTag("mary"))
+	c.Assert(err, gc.NotNil)
+	c.Assert(s.server.Searches(), gc.Equals, 0)
+
+	s.clock.Advance(time.Minute)
+	groups, err := s.resolver.UserGroups(s.config, names.NewUserTag("bob"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(groups, jc.DeepEquals, []string{"ops"})
+	c.Assert(s.server.Searches(), gc.Equals, 1)
+}
@@ -0,0 +1,131 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package authentication
+
+import (
+	"context"
+
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/permission"
+	"github.com/juju/juju/oidc"
+	"github.com/juju/juju/state"
+)
+
+// OIDCAuthenticator performs authentication for external users using
+// ID tokens issued by an OpenID Connect provider. If no ID token is
+// provided, or the ID token has expired, it will return a
+// *common.OIDCLoginRequiredError describing where to obtain one.
+type OIDCAuthenticator struct {
+	// Verifier verifies the ID tokens presented at login.
+	Verifier *oidc.Verifier
+
+	// UsernameClaim is the ID token claim holding the user name.
+	UsernameClaim string
+
+	// GroupsClaim is the ID token claim holding the user's groups.
+	GroupsClaim string
+
+	// GroupAccess holds the controller access granted to members
+	// of each provider group.
+	GroupAccess map[string]permission.Access
+
+	// ProvisionUser, if non-nil, is called to grant a user who is not
+	// yet known to the controller the controller access their groups
+	// grant them. It is only called on a user's first login, and not
+	// at all if their groups grant no access; access is afterwards
+	// managed with grant and revoke like any other user's.
+	ProvisionUser func(names.UserTag, permission.Access) error
+}
+
+var _ EntityAuthenticator = (*OIDCAuthenticator)(nil)
+
+// oidcLoginScopes holds the scopes, in addition to "openid", that
+// clients should request so that ID tokens carry the user's name.
+var oidcLoginScopes = []string{"profile", "email"}
+
+// Authenticate authenticates the user identified by the ID token in
+// the login request.
+func (a *OIDCAuthenticator) Authenticate(ctx context.Context, entityFinder EntityFinder, _ names.Tag, req params.LoginRequest) (state.Entity, error) {
+	if req.IDToken == "" {
+		return nil, a.loginRequired(errors.New("OpenID Connect login required"))
+	}
+	token, err := a.Verifier.Verify(ctx, req.IDToken)
+	if errors.Cause(err) == oidc.ErrTokenExpired {
+		return nil, a.loginRequired(err)
+	}
+	if err != nil {
+		logger.Debugf("ID token verification failed: %v", err)
+		return nil, errors.Trace(common.ErrBadCreds)
+	}
+	tag, err := a.userTag(token)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	entity, err := entityFinder.FindEntity(tag)
+	if errors.IsNotFound(err) && a.ProvisionUser != nil {
+		if access := a.groupAccess(token); access != permission.NoAccess {
+			if err := a.ProvisionUser(tag, access); err != nil {
+				return nil, errors.Annotatef(err, "provisioning %q", tag.Id())
+			}
+			entity, err = entityFinder.FindEntity(tag)
+		}
+	}
+	if errors.IsNotFound(err) {
+		return nil, errors.Trace(common.ErrBadCreds)
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return entity, nil
+}
+
+func (a *OIDCAuthenticator) loginRequired(cause error) error {
+	return &common.OIDCLoginRequiredError{
+		Cause:    cause,
+		Issuer:   a.Verifier.Issuer(),
+		ClientID: a.Verifier.ClientID(),
+		Scopes:   oidcLoginScopes,
+	}
+}
+
+// userTag returns the tag of the external user identified by the
+// token. As with macaroon logins, names without a domain are placed
+// in the "external" domain. Email addresses are only trusted once the
+// provider has verified them, as many providers let users set any
+// address they like.
+func (a *OIDCAuthenticator) userTag(token *oidc.IDToken) (names.UserTag, error) {
+	username, ok := token.StringClaim(a.UsernameClaim)
+	if !ok {
+		return names.UserTag{}, errors.Errorf("ID token has no %q claim", a.UsernameClaim)
+	}
+	if a.UsernameClaim == "email" && !token.BoolClaim("email_verified") {
+		return names.UserTag{}, errors.Unauthorizedf("email address %q has not been verified", username)
+	}
+	if names.IsValidUserName(username) {
+		return names.NewLocalUserTag(username).WithDomain("external"), nil
+	}
+	if !names.IsValidUser(username) {
+		return names.UserTag{}, errors.Errorf("%q is an invalid user name", username)
+	}
+	tag := names.NewUserTag(username)
+	if tag.IsLocal() {
+		return names.UserTag{}, errors.Errorf("OpenID provider has provided ostensibly local name %q", username)
+	}
+	return tag, nil
+}
+
+// groupAccess returns the highest controller access granted to any
+// of the groups in the token.
+func (a *OIDCAuthenticator) groupAccess(token *oidc.IDToken) permission.Access {
+	access := permission.NoAccess
+	for _, group := range token.StringsClaim(a.GroupsClaim) {
+		if groupAccess, ok := a.GroupAccess[group]; ok && groupAccess.GreaterControllerAccessThan(access) {
+			access = groupAccess
+		}
+	}
+	return access
+}
@@ -0,0 +1,236 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package authentication_test
+
+import (
+	"context"
+	"time"
+
+	"github.com/juju/clock/testclock"
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/authentication"
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/permission"
+	"github.com/juju/juju/oidc"
+	"github.com/juju/juju/oidc/oidctesting"
+	"github.com/juju/juju/state"
+)
+
+type oidcAuthenticatorSuite struct {
+	issuer *oidctesting.Issuer
+	clock  *testclock.Clock
+	access map[names.UserTag]permission.Access
+	auth   *authentication.OIDCAuthenticator
+}
+
+var _ = gc.Suite(&oidcAuthenticatorSuite{})
+
+func (s *oidcAuthenticatorSuite) SetUpTest(c *gc.C) {
+	s.issuer = oidctesting.NewIssuer("juju")
+	s.clock = testclock.NewClock(time.Now())
+	s.access = make(map[names.UserTag]permission.Access)
+	s.auth = &authentication.OIDCAuthenticator{
+		Verifier: oidc.NewVerifier(oidc.VerifierConfig{
+			Issuer:     s.issuer.URL,
+			ClientID:   "juju",
+			HTTPClient: s.issuer.Client(),
+			Clock:      s.clock,
+		}),
+		UsernameClaim: "email",
+		GroupsClaim:   "groups",
+		GroupAccess: map[string]permission.Access{
+			"devs":   permission.LoginAccess,
+			"admins": permission.SuperuserAccess,
+		},
+		ProvisionUser: func(tag names.UserTag, access permission.Access) error {
+			s.access[tag] = access
+			return nil
+		},
+	}
+}
+
+func (s *oidcAuthenticatorSuite) TearDownTest(c *gc.C) {
+	s.issuer.Close()
+}
+
+func (s *oidcAuthenticatorSuite) idToken(claims map[string]interface{}) string {
+	return s.issuer.IDToken(claims, s.clock.Now().Add(time.Hour))
+}
+
+func (s *oidcAuthenticatorSuite) TestAuthenticate(c *gc.C) {
+	token := s.idToken(map[string]interface{}{
+		"sub":            "1234",
+		"email":          "bob@example.com",
+		"email_verified": true,
+		"groups":         []string{"devs", "admins", "other"},
+	})
+	entity, err := s.auth.Authenticate(context.Background(), provisionedFinder(s.access), nil, params.LoginRequest{
+		IDToken: token,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	bob := names.NewUserTag("bob@example.com")
+	c.Assert(entity.Tag(), gc.Equals, bob)
+	c.Assert(s.access, jc.DeepEquals, map[names.UserTag]permission.Access{
+		bob: permission.SuperuserAccess,
+	})
+}
+
+func (s *oidcAuthenticatorSuite) TestAuthenticateKnownUserNotProvisioned(c *gc.C) {
+	bob := names.NewUserTag("bob@example.com")
+	s.access[bob] = permission.LoginAccess
+	token := s.idToken(map[string]interface{}{
+		"sub":            "1234",
+		"email":          "bob@example.com",
+		"email_verified": true,
+		"groups":         []string{"admins"},
+	})
+	s.auth.ProvisionUser = func(names.UserTag, permission.Access) error {
+		c.Fatalf("known user provisioned")
+		return nil
+	}
+	entity, err := s.auth.Authenticate(context.Background(), provisionedFinder(s.access), nil, params.LoginRequest{
+		IDToken: token,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(entity.Tag(), gc.Equals, bob)
+	c.Assert(s.access[bob], gc.Equals, permission.LoginAccess)
+}
+
+func (s *oidcAuthenticatorSuite) TestAuthenticateNoGroupAccess(c *gc.C) {
+	token := s.idToken(map[string]interface{}{
+		"sub":            "1234",
+		"email":          "bob@example.com",
+		"email_verified": true,
+		"groups":         []string{"other"},
+	})
+	_, err := s.auth.Authenticate(context.Background(), provisionedFinder(s.access), nil, params.LoginRequest{
+		IDToken: token,
+	})
+	c.Assert(errors.Cause(err), gc.Equals, common.ErrBadCreds)
+	c.Assert(s.access, gc.HasLen, 0)
+}
+
+func (s *oidcAuthenticatorSuite) TestAuthenticateEmailNotVerified(c *gc.C) {
+	for _, verified := range []interface{}{nil, false, "true"} {
+		claims := map[string]interface{}{
+			"sub":    "1234",
+			"email":  "bob@example.com",
+			"groups": []string{"admins"},
+		}
+		if verified != nil {
+			claims["email_verified"] = verified
+		}
+		_, err := s.auth.Authenticate(context.Background(), provisionedFinder(s.access), nil, params.LoginRequest{
+			IDToken: s.idToken(claims),
+		})
+		c.Assert(err, gc.ErrorMatches, `email address "bob@example.com" has not been verified`)
+		c.Assert(err, jc.Satisfies, errors.IsUnauthorized)
+		c.Assert(s.access, gc.HasLen, 0)
+	}
+}
+
+func (s *oidcAuthenticatorSuite) TestAuthenticateNameWithoutDomain(c *gc.C) {
+	s.auth.UsernameClaim = "preferred_username"
+	token := s.idToken(map[string]interface{}{
+		"sub":                "1234",
+		"preferred_username": "bob",
+	})
+	entity, err := s.auth.Authenticate(context.Background(), entityFinder{}, nil, params.LoginRequest{
+		IDToken: token,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(entity.Tag(), gc.Equals, names.NewUserTag("bob@external"))
+	c.Assert(s.access, gc.HasLen, 0)
+}
+
+func (s *oidcAuthenticatorSuite) TestAuthenticateLocalName(c *gc.C) {
+	token := s.idToken(map[string]interface{}{
+		"sub":            "1234",
+		"email":          "bob@local",
+		"email_verified": true,
+	})
+	_, err := s.auth.Authenticate(context.Background(), entityFinder{}, nil, params.LoginRequest{
+		IDToken: token,
+	})
+	c.Assert(err, gc.ErrorMatches, `OpenID provider has provided ostensibly local name "bob@local"`)
+}
+
+func (s *oidcAuthenticatorSuite) TestAuthenticateNoToken(c *gc.C) {
+	_, err := s.auth.Authenticate(context.Background(), entityFinder{}, nil, params.LoginRequest{})
+	c.Assert(err, gc.ErrorMatches, "OpenID Connect login required")
+	loginErr, ok := err.(*common.OIDCLoginRequiredError)
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(loginErr.Issuer, gc.Equals, s.issuer.URL)
+	c.Assert(loginErr.ClientID, gc.Equals, "juju")
+	c.Assert(loginErr.Scopes, jc.DeepEquals, []string{"profile", "email"})
+}
+
+func (s *oidcAuthenticatorSuite) TestAuthenticateExpiredToken(c *gc.C) {
+	token := s.idToken(map[string]interface{}{
+		"sub":            "1234",
+		"email":          "bob@example.com",
+		"email_verified": true,
+	})
+	s.clock.Advance(2 * time.Hour)
+	_, err := s.auth.Authenticate(context.Background(), entityFinder{}, nil, params.LoginRequest{
+		IDToken: token,
+	})
+	c.Assert(common.IsOIDCLoginRequiredError(err), jc.IsTrue)
+	c.Assert(err, gc.ErrorMatches, "token expired at .*: ID token expired")
+}
+
+func (s *oidcAuthenticatorSuite) TestAuthenticateBadToken(c *gc.C) {
+	_, err := s.auth.Authenticate(context.Background(), entityFinder{}, nil, params.LoginRequest{
+		IDToken: "not.a.token",
+	})
+	c.Assert(errors.Cause(err), gc.Equals, common.ErrBadCreds)
+}
+
+func (s *oidcAuthenticatorSuite) TestAuthenticateUnknownUser(c *gc.C) {
+	token := s.idToken(map[string]interface{}{
+		"sub":            "1234",
+		"email":          "bob@example.com",
+		"email_verified": true,
+	})
+	_, err := s.auth.Authenticate(context.Background(), entityFinder{
+		err: errors.NotFoundf("user"),
+	}, nil, params.LoginRequest{
+		IDToken: token,
+	})
+	c.Assert(errors.Cause(err), gc.Equals, common.ErrBadCreds)
+}
+
+type entityFinder struct {
+	err error
+}
+
+func (f entityFinder) FindEntity(tag names.Tag) (state.Entity, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return entity{tag}, nil
+}
+
+// provisionedFinder finds the users that have been granted access.
+type provisionedFinder map[names.UserTag]permission.Access
+
+func (f provisionedFinder) FindEntity(tag names.Tag) (state.Entity, error) {
+	if _, ok := f[tag.(names.UserTag)]; !ok {
+		return nil, errors.NotFoundf("user %q", tag.Id())
+	}
+	return entity{tag}, nil
+}
+
+type entity struct {
+	tag names.Tag
+}
+
+func (e entity) Tag() names.Tag {
+	return e.tag
+}
@@ -0,0 +1,72 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package authentication
+
+import (
+	"context"
+
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+// APITokenChecker checks the secrets of API tokens.
+type APITokenChecker interface {
+	// CheckAPIToken returns the API token with the given secret, or
+	// an error if there is no such token or it has expired.
+	CheckAPIToken(secret string) (state.APIToken, error)
+}
+
+// TokenAuthenticator performs authentication for users logging in with
+// API tokens. A token may only be used to log in to the model it was
+// minted for.
+type TokenAuthenticator struct {
+	// Tokens checks the API tokens presented at login.
+	Tokens APITokenChecker
+
+	// ModelUUID is the UUID of the model being logged in to.
+	ModelUUID string
+}
+
+var _ EntityAuthenticator = (*TokenAuthenticator)(nil)
+
+// TokenEntity is the entity authenticated by an API token. Token
+// holds the token, which limits what the entity may do.
+type TokenEntity struct {
+	state.Entity
+	Token state.APIToken
+}
+
+// Authenticate authenticates the owner of the API token in the login
+// request.
+func (a *TokenAuthenticator) Authenticate(ctx context.Context, entityFinder EntityFinder, _ names.Tag, req params.LoginRequest) (state.Entity, error) {
+	if req.Token == "" {
+		return nil, errors.Trace(common.ErrNoCreds)
+	}
+	token, err := a.Tokens.CheckAPIToken(req.Token)
+	if err != nil {
+		logger.Debugf("API token check failed: %v", err)
+		return nil, errors.Trace(common.ErrBadCreds)
+	}
+	if token.Model.Id() != a.ModelUUID {
+		logger.Debugf("API token %q used to log in to model %q", token.ID, a.ModelUUID)
+		return nil, errors.Trace(common.ErrPerm)
+	}
+	entity, err := entityFinder.FindEntity(token.Owner)
+	if errors.IsNotFound(err) {
+		return nil, errors.Trace(common.ErrBadCreds)
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	type withIsDisabled interface {
+		IsDisabled() bool
+	}
+	if entity, ok := entity.(withIsDisabled); ok && entity.IsDisabled() {
+		return nil, errors.Trace(common.ErrBadCreds)
+	}
+	return &TokenEntity{Entity: entity, Token: token}, nil
+}
@@ -0,0 +1,91 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package authentication_test
+
+import (
+	"context"
+
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/authentication"
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/permission"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type tokenAuthenticatorSuite struct {
+	token state.APIToken
+	auth  *authentication.TokenAuthenticator
+}
+
+var _ = gc.Suite(&tokenAuthenticatorSuite{})
+
+func (s *tokenAuthenticatorSuite) SetUpTest(c *gc.C) {
+	s.token = state.APIToken{
+		ID:     "0a1b",
+		Owner:  names.NewUserTag("bot"),
+		Model:  coretesting.ModelTag,
+		Access: permission.ReadAccess,
+	}
+	s.auth = &authentication.TokenAuthenticator{
+		Tokens:    tokenChecker{s.token},
+		ModelUUID: coretesting.ModelTag.Id(),
+	}
+}
+
+func (s *tokenAuthenticatorSuite) TestAuthenticate(c *gc.C) {
+	result, err := s.auth.Authenticate(context.Background(), entityFinder{}, nil, params.LoginRequest{
+		Token: "jujutoken-0a1b.key",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, &authentication.TokenEntity{
+		Entity: entity{names.NewUserTag("bot")},
+		Token:  s.token,
+	})
+}
+
+func (s *tokenAuthenticatorSuite) TestAuthenticateNoToken(c *gc.C) {
+	_, err := s.auth.Authenticate(context.Background(), entityFinder{}, nil, params.LoginRequest{})
+	c.Assert(errors.Cause(err), gc.Equals, common.ErrNoCreds)
+}
+
+func (s *tokenAuthenticatorSuite) TestAuthenticateBadToken(c *gc.C) {
+	_, err := s.auth.Authenticate(context.Background(), entityFinder{}, nil, params.LoginRequest{
+		Token: "jujutoken-0a1b.wrong",
+	})
+	c.Assert(errors.Cause(err), gc.Equals, common.ErrBadCreds)
+}
+
+func (s *tokenAuthenticatorSuite) TestAuthenticateOtherModel(c *gc.C) {
+	s.auth.ModelUUID = "c0ffee00-0bad-400d-8000-4b1d0d06f00d"
+	_, err := s.auth.Authenticate(context.Background(), entityFinder{}, nil, params.LoginRequest{
+		Token: "jujutoken-0a1b.key",
+	})
+	c.Assert(errors.Cause(err), gc.Equals, common.ErrPerm)
+}
+
+func (s *tokenAuthenticatorSuite) TestAuthenticateUserNotFound(c *gc.C) {
+	_, err := s.auth.Authenticate(context.Background(), entityFinder{
+		err: errors.NotFoundf("user"),
+	}, nil, params.LoginRequest{
+		Token: "jujutoken-0a1b.key",
+	})
+	c.Assert(errors.Cause(err), gc.Equals, common.ErrBadCreds)
+}
+
+type tokenChecker struct {
+	token state.APIToken
+}
+
+func (t tokenChecker) CheckAPIToken(secret string) (state.APIToken, error) {
+	if secret != state.APITokenPrefix+t.token.ID+".key" {
+		return state.APIToken{}, errors.Unauthorizedf("invalid key")
+	}
+	return t.token, nil
+}
@@ -66,7 +66,10 @@ var _ EntityAuthenticator = (*UserAuthenticator)(nil)
 // error on authentication failure.
 //
 // If and only if no password is supplied, then Authenticate will check for any
-// valid macaroons. Otherwise, password authentication will be performed.
+// valid macaroons. Otherwise, password authentication will be performed, and
+// users enrolled for multi-factor authentication must also supply a code.
+// Local users who fail to log in too many times are locked out, and those
+// whose passwords have expired are returned as a PasswordExpiredEntity.
 func (u *UserAuthenticator) Authenticate(
 	ctx context.Context, entityFinder EntityFinder, tag names.Tag, req params.LoginRequest,
 ) (state.Entity, error) {
@@ -77,7 +80,117 @@ func (u *UserAuthenticator) Authenticate(
 	if req.Credentials == "" && userTag.IsLocal() {
 		return u.authenticateMacaroons(ctx, entityFinder, userTag, req)
 	}
-	return u.AgentAuthenticator.Authenticate(ctx, entityFinder, tag, req)
+	entity, err := entityFinder.FindEntity(tag)
+	if errors.IsNotFound(err) {
+		return nil, errors.Trace(common.ErrBadCreds)
+	}
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	user, lockable := entity.(lockoutEntity)
+	if lockable {
+		// Locked out users are refused whether or not
+		// they present the right password.
+		if _, locked := user.LockedUntil(); locked {
+			return nil, errors.Trace(common.ErrLoginLockedOut)
+		}
+	}
+	if err := u.authenticatePassword(ctx, entity, tag, req); err != nil {
+		if cause := errors.Cause(err); lockable && (cause == common.ErrBadCreds || cause == common.ErrBadMFACode) {
+			if err := user.RecordFailedLogin(); err != nil {
+				logger.Warningf("cannot record failed login for %s: %v", tag, err)
+			}
+		}
+		return nil, errors.Trace(err)
+	}
+	if lockable {
+		if err := user.ResetFailedLogins(); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	return checkPasswordExpired(entity)
+}
+
+// authenticatePassword checks the password, and if needed the
+// multi-factor authentication code, in a login request for the entity.
+func (u *UserAuthenticator) authenticatePassword(
+	ctx context.Context, entity state.Entity, tag names.Tag, req params.LoginRequest,
+) error {
+	if _, err := u.AgentAuthenticator.Authenticate(ctx, foundEntity{entity}, tag, req); err != nil {
+		return err
+	}
+	return checkMFACode(entity, req.MFACode)
+}
+
+// PasswordExpiredEntity is a local user who has authenticated, but whose
+// password has expired. The user may do nothing but change it.
+type PasswordExpiredEntity struct {
+	state.Entity
+}
+
+// checkPasswordExpired returns the authenticated entity, wrapped in a
+// PasswordExpiredEntity if it is a local user whose password has
+// expired.
+func checkPasswordExpired(entity state.Entity) (state.Entity, error) {
+	user, ok := entity.(lockoutEntity)
+	if !ok {
+		return entity, nil
+	}
+	expired, err := user.PasswordExpired()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if expired {
+		return &PasswordExpiredEntity{Entity: entity}, nil
+	}
+	return entity, nil
+}
+
+// lockoutEntity is implemented by local users, who are subject to the
+// controller's password policy.
+type lockoutEntity interface {
+	LockedUntil() (time.Time, bool)
+	RecordFailedLogin() error
+	ResetFailedLogins() error
+	PasswordExpired() (bool, error)
+}
+
+// foundEntity is an EntityFinder that returns an entity that has
+// already been found.
+type foundEntity struct {
+	state.Entity
+}
+
+// FindEntity implements EntityFinder.
+func (e foundEntity) FindEntity(names.Tag) (state.Entity, error) {
+	return e.Entity, nil
+}
+
+// mfaEntity is implemented by local users, who may be enrolled for
+// multi-factor authentication.
+type mfaEntity interface {
+	MFAEnabled() bool
+	CheckMFACode(code string) error
+}
+
+// checkMFACode checks the multi-factor authentication code supplied
+// with a password login, if the entity is enrolled.
+func checkMFACode(entity state.Entity, code string) error {
+	user, ok := entity.(mfaEntity)
+	if !ok || !user.MFAEnabled() {
+		return nil
+	}
+	if code == "" {
+		return common.ErrMFARequired
+	}
+	if err := user.CheckMFACode(code); err != nil {
+		if errors.IsUnauthorized(err) {
+			logger.Debugf("multi-factor authentication for %s failed: %v", entity.Tag(), err)
+			return common.ErrBadMFACode
+		}
+		return errors.Trace(err)
+	}
+	return nil
 }
 
 // CreateLocalLoginMacaroon creates a macaroon that may be provided to a
@@ -211,7 +324,8 @@ func (u *UserAuthenticator) authenticateMacaroons(
 	} else if err != nil {
 		return nil, errors.Trace(err)
 	}
-	return entity, nil
+	// Macaroons outlive the password they were obtained with.
+	return checkPasswordExpired(entity)
 }
 
 // ExternalMacaroonAuthenticator performs authentication for external users using
@@ -393,3 +393,202 @@ type simpleEntity struct {
 func (e *simpleEntity) Tag() names.Tag {
 	return e.tag
 }
+
+type mfaAuthenticatorSuite struct {
+	testing.IsolationSuite
+	user *mfaUser
+}
+
+var _ = gc.Suite(&mfaAuthenticatorSuite{})
+
+func (s *mfaAuthenticatorSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.user = &mfaUser{tag: names.NewUserTag("bob"), password: "hunter2", code: "123456"}
+}
+
+func (s *mfaAuthenticatorSuite) authenticate(req params.LoginRequest) error {
+	authenticator := &authentication.UserAuthenticator{}
+	_, err := authenticator.Authenticate(context.TODO(), mfaEntityFinder{s.user}, s.user.tag, req)
+	return err
+}
+
+func (s *mfaAuthenticatorSuite) TestNotEnrolled(c *gc.C) {
+	s.user.code = ""
+	err := s.authenticate(params.LoginRequest{Credentials: "hunter2"})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *mfaAuthenticatorSuite) TestCodeRequired(c *gc.C) {
+	err := s.authenticate(params.LoginRequest{Credentials: "hunter2"})
+	c.Assert(errors.Cause(err), gc.Equals, common.ErrMFARequired)
+
+	// The password is checked before asking for a code.
+	err = s.authenticate(params.LoginRequest{Credentials: "wrong"})
+	c.Assert(errors.Cause(err), gc.Equals, common.ErrBadCreds)
+}
+
+func (s *mfaAuthenticatorSuite) TestValidCode(c *gc.C) {
+	err := s.authenticate(params.LoginRequest{Credentials: "hunter2", MFACode: "123456"})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *mfaAuthenticatorSuite) TestInvalidCode(c *gc.C) {
+	err := s.authenticate(params.LoginRequest{Credentials: "hunter2", MFACode: "654321"})
+	c.Assert(errors.Cause(err), gc.Equals, common.ErrBadMFACode)
+}
+
+type mfaEntityFinder struct {
+	user *mfaUser
+}
+
+func (f mfaEntityFinder) FindEntity(tag names.Tag) (state.Entity, error) {
+	return f.user, nil
+}
+
+type mfaUser struct {
+	tag      names.UserTag
+	password string
+	code     string
+}
+
+func (u *mfaUser) Tag() names.Tag                     { return u.tag }
+func (u *mfaUser) Refresh() error                     { return nil }
+func (u *mfaUser) SetPassword(string) error           { return nil }
+func (u *mfaUser) PasswordValid(password string) bool { return password == u.password }
+func (u *mfaUser) MFAEnabled() bool                   { return u.code != "" }
+
+func (u *mfaUser) CheckMFACode(code string) error {
+	if code != u.code {
+		return errors.Unauthorizedf("invalid multi-factor authentication code")
+	}
+	return nil
+}
+
+type lockoutAuthenticatorSuite struct {
+	testing.IsolationSuite
+	user *lockoutUser
+}
+
+var _ = gc.Suite(&lockoutAuthenticatorSuite{})
+
+func (s *lockoutAuthenticatorSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.user = &lockoutUser{
+		mfaUser:     mfaUser{tag: names.NewUserTag("bob"), password: "hunter2"},
+		maxAttempts: 2,
+	}
+}
+
+func (s *lockoutAuthenticatorSuite) authenticate(req params.LoginRequest) error {
+	authenticator := &authentication.UserAuthenticator{}
+	_, err := authenticator.Authenticate(context.TODO(), lockoutEntityFinder{s.user}, s.user.tag, req)
+	return err
+}
+
+func (s *lockoutAuthenticatorSuite) TestFailedLoginsLockOut(c *gc.C) {
+	err := s.authenticate(params.LoginRequest{Credentials: "wrong"})
+	c.Assert(errors.Cause(err), gc.Equals, common.ErrBadCreds)
+	c.Assert(s.user.failed, gc.Equals, 1)
+	err = s.authenticate(params.LoginRequest{Credentials: "wrong"})
+	c.Assert(errors.Cause(err), gc.Equals, common.ErrBadCreds)
+	c.Assert(s.user.failed, gc.Equals, 2)
+
+	// Locked out users are refused even with the right password.
+	err = s.authenticate(params.LoginRequest{Credentials: "hunter2"})
+	c.Assert(errors.Cause(err), gc.Equals, common.ErrLoginLockedOut)
+	c.Assert(s.user.failed, gc.Equals, 2)
+}
+
+func (s *lockoutAuthenticatorSuite) TestSuccessfulLoginResets(c *gc.C) {
+	err := s.authenticate(params.LoginRequest{Credentials: "wrong"})
+	c.Assert(errors.Cause(err), gc.Equals, common.ErrBadCreds)
+	err = s.authenticate(params.LoginRequest{Credentials: "hunter2"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.user.failed, gc.Equals, 0)
+}
+
+func (s *lockoutAuthenticatorSuite) TestBadMFACodeIsFailedLogin(c *gc.C) {
+	s.user.code = "123456"
+	err := s.authenticate(params.LoginRequest{Credentials: "hunter2"})
+	c.Assert(errors.Cause(err), gc.Equals, common.ErrMFARequired)
+	c.Assert(s.user.failed, gc.Equals, 0)
+	err = s.authenticate(params.LoginRequest{Credentials: "hunter2", MFACode: "654321"})
+	c.Assert(errors.Cause(err), gc.Equals, common.ErrBadMFACode)
+	c.Assert(s.user.failed, gc.Equals, 1)
+}
+
+func (s *lockoutAuthenticatorSuite) TestPasswordExpired(c *gc.C) {
+	s.user.expired = true
+	authenticator := &authentication.UserAuthenticator{}
+	entity, err := authenticator.Authenticate(
+		context.TODO(), lockoutEntityFinder{s.user}, s.user.tag,
+		params.LoginRequest{Credentials: "hunter2"},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(entity, jc.DeepEquals, &authentication.PasswordExpiredEntity{Entity: s.user})
+
+	// Expiry is only revealed to users who know the password.
+	err = s.authenticate(params.LoginRequest{Credentials: "wrong"})
+	c.Assert(errors.Cause(err), gc.Equals, common.ErrBadCreds)
+}
+
+func (s *lockoutAuthenticatorSuite) TestPasswordExpiredMacaroonLogin(c *gc.C) {
+	s.user.expired = true
+	mac, err := macaroon.New(nil, nil, "", macaroon.LatestVersion)
+	c.Assert(err, jc.ErrorIsNil)
+	err = mac.AddFirstPartyCaveat([]byte("declared username bob"))
+	c.Assert(err, jc.ErrorIsNil)
+	authenticator := &authentication.UserAuthenticator{
+		Bakery: &mockBakeryService{},
+		Clock:  testclock.NewClock(time.Time{}),
+	}
+	entity, err := authenticator.Authenticate(
+		context.TODO(), lockoutEntityFinder{s.user}, s.user.tag,
+		params.LoginRequest{Macaroons: []macaroon.Slice{{mac}}},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(entity, jc.DeepEquals, &authentication.PasswordExpiredEntity{Entity: s.user})
+}
+
+func (s *lockoutAuthenticatorSuite) TestPasswordNotExpired(c *gc.C) {
+	authenticator := &authentication.UserAuthenticator{}
+	entity, err := authenticator.Authenticate(
+		context.TODO(), lockoutEntityFinder{s.user}, s.user.tag,
+		params.LoginRequest{Credentials: "hunter2"},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(entity, gc.Equals, state.Entity(s.user))
+}
+
+type lockoutEntityFinder struct {
+	user *lockoutUser
+}
+
+func (f lockoutEntityFinder) FindEntity(tag names.Tag) (state.Entity, error) {
+	return f.user, nil
+}
+
+type lockoutUser struct {
+	mfaUser
+	maxAttempts int
+	failed      int
+	expired     bool
+}
+
+func (u *lockoutUser) LockedUntil() (time.Time, bool) {
+	return time.Time{}, u.failed >= u.maxAttempts
+}
+
+func (u *lockoutUser) RecordFailedLogin() error {
+	u.failed++
+	return nil
+}
+
+func (u *lockoutUser) ResetFailedLogins() error {
+	u.failed = 0
+	return nil
+}
+
+func (u *lockoutUser) PasswordExpired() (bool, error) {
+	return u.expired, nil
+}
@@ -0,0 +1,161 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/httpcontext"
+	"github.com/juju/juju/charmhub"
+	"github.com/juju/juju/core/permission"
+)
+
+// charmHubDownloadHandler downloads a revision of a charm or bundle
+// from the charmhub-compatible store configured for the model, and
+// sends it to the client once its hash has been verified. Clients
+// download through the controller so that its proxy settings are
+// honoured.
+type charmHubDownloadHandler struct {
+	ctxt httpContext
+}
+
+// ServeHTTP implements http.Handler.
+func (h *charmHubDownloadHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := h.serve(w, r); err != nil {
+		if err := sendError(w, errors.Trace(err)); err != nil {
+			logger.Errorf("%v", errors.Annotate(err, "cannot return error to user"))
+		}
+	}
+}
+
+func (h *charmHubDownloadHandler) serve(w http.ResponseWriter, r *http.Request) error {
+	if r.Method != "GET" {
+		return errors.Trace(emitUnsupportedMethodErr(r.Method))
+	}
+	query := r.URL.Query()
+	name, channel := query.Get("name"), query.Get("channel")
+	if name == "" || channel == "" {
+		return errors.BadRequestf("expected name and channel")
+	}
+	revision, err := strconv.Atoi(query.Get("revision"))
+	if err != nil {
+		return errors.BadRequestf("invalid revision %q", query.Get("revision"))
+	}
+
+	st, err := h.ctxt.stateForRequestAuthenticatedUser(r)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer st.Release()
+	authInfo, ok := httpcontext.RequestAuthInfo(r)
+	if !ok {
+		return common.ErrPerm
+	}
+	model, err := st.Model()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	userTag := authInfo.Entity.Tag().(names.UserTag)
+	canRead, err := common.HasPermission(st.UserPermission, userTag, permission.ReadAccess, model.ModelTag())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !canRead {
+		isAdmin, err := common.HasPermission(st.UserPermission, userTag, permission.SuperuserAccess, model.ControllerTag())
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if !isAdmin {
+			return common.ErrPerm
+		}
+	}
+
+	cfg, err := model.ModelConfig()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	client, err := charmhub.NewClient(charmhub.Config{URL: cfg.CharmHubURL()})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	info, err := client.Info(r.Context(), name)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	download, err := charmHubRevisionDownload(info, channel, revision)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	tmp, err := ioutil.TempFile("", "charmhub-download-")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer func() {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+	}()
+	if err := downloadVerified(r.Context(), client, download, tmp); err != nil {
+		return errors.Trace(err)
+	}
+	size, err := tmp.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return errors.Trace(err)
+	}
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Length", fmt.Sprint(size))
+	w.WriteHeader(http.StatusOK)
+	if _, err := io.Copy(w, tmp); err != nil {
+		logger.Errorf("cannot send %q revision %d: %v", name, revision, err)
+	}
+	return nil
+}
+
+// charmHubRevisionDownload returns where to download the given revision
+// of the charm or bundle described by info, which must still be released
+// to the given channel.
+func charmHubRevisionDownload(info charmhub.InfoResponse, channel string, revision int) (charmhub.Download, error) {
+	for _, release := range info.ChannelMap {
+		if release.Channel.String() != channel {
+			continue
+		}
+		if release.Revision.Revision != revision {
+			return charmhub.Download{}, errors.NotFoundf(
+				"%q revision %d in channel %q, which now has revision %d",
+				info.Name, revision, channel, release.Revision.Revision)
+		}
+		if release.Revision.Download.HashSHA256 == "" {
+			return charmhub.Download{}, errors.Errorf("no SHA256 hash for %q revision %d", info.Name, revision)
+		}
+		return release.Revision.Download, nil
+	}
+	return charmhub.Download{}, errors.NotFoundf("%q channel %q", info.Name, channel)
+}
+
+// downloadVerified writes the download to w, returning an error if its
+// content does not have the expected hash.
+func downloadVerified(ctx context.Context, client *charmhub.Client, download charmhub.Download, w io.Writer) error {
+	hash := sha256.New()
+	if err := client.Download(ctx, download.URL, io.MultiWriter(w, hash)); err != nil {
+		return errors.Trace(err)
+	}
+	if got := fmt.Sprintf("%x", hash.Sum(nil)); got != download.HashSHA256 {
+		return errors.Errorf("downloaded content has SHA256 hash %q, expected %q", got, download.HashSHA256)
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	apitesting "github.com/juju/juju/apiserver/testing"
+	charmhubtesting "github.com/juju/juju/charmhub/testing"
+	"github.com/juju/juju/testing/factory"
+)
+
+type charmHubDownloadSuite struct {
+	apiserverBaseSuite
+
+	store *charmhubtesting.Server
+}
+
+var _ = gc.Suite(&charmHubDownloadSuite{})
+
+func (s *charmHubDownloadSuite) SetUpTest(c *gc.C) {
+	s.apiserverBaseSuite.SetUpTest(c)
+	s.store = charmhubtesting.NewServer(charmhubtesting.Entity{
+		Name: "wordpress",
+		Releases: []charmhubtesting.Release{{
+			Track:    "latest",
+			Risk:     "stable",
+			Revision: 16,
+			Content:  []byte("wordpress-16"),
+		}},
+	})
+	s.AddCleanup(func(*gc.C) { s.store.Close() })
+	err := s.Model.UpdateModelConfig(map[string]interface{}{
+		"charmhub-url": s.store.URL,
+	}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *charmHubDownloadSuite) downloadURI(name, channel string, revision int) string {
+	query := url.Values{
+		"name":     {name},
+		"channel":  {channel},
+		"revision": {fmt.Sprint(revision)},
+	}
+	return s.URL(fmt.Sprintf("/model/%s/charmhub/download", s.State.ModelUUID()), query).String()
+}
+
+func (s *charmHubDownloadSuite) assertError(c *gc.C, resp *http.Response, expStatus int, expError string) {
+	body := apitesting.AssertResponse(c, resp, expStatus, params.ContentTypeJSON)
+	var result params.ErrorResult
+	err := json.Unmarshal(body, &result)
+	c.Assert(err, jc.ErrorIsNil, gc.Commentf("body: %s", body))
+	c.Assert(result.Error, gc.NotNil)
+	c.Check(result.Error.Message, gc.Matches, expError)
+}
+
+func (s *charmHubDownloadSuite) TestRequiresAuth(c *gc.C) {
+	resp := apitesting.SendHTTPRequest(c, apitesting.HTTPRequestParams{
+		Method: "GET",
+		URL:    s.downloadURI("wordpress", "latest/stable", 16),
+	})
+	body := apitesting.AssertResponse(c, resp, http.StatusUnauthorized, "text/plain; charset=utf-8")
+	c.Assert(string(body), gc.Equals, "authentication failed: no credentials provided\n")
+}
+
+func (s *charmHubDownloadSuite) TestDownload(c *gc.C) {
+	resp := s.sendHTTPRequest(c, apitesting.HTTPRequestParams{
+		Method: "GET",
+		URL:    s.downloadURI("wordpress", "latest/stable", 16),
+	})
+	body := apitesting.AssertResponse(c, resp, http.StatusOK, "application/octet-stream")
+	c.Assert(string(body), gc.Equals, "wordpress-16")
+}
+
+func (s *charmHubDownloadSuite) TestDownloadRevisionChanged(c *gc.C) {
+	resp := s.sendHTTPRequest(c, apitesting.HTTPRequestParams{
+		Method: "GET",
+		URL:    s.downloadURI("wordpress", "latest/stable", 15),
+	})
+	s.assertError(c, resp, http.StatusNotFound, `"wordpress" revision 15 in channel "latest/stable", which now has revision 16 not found`)
+}
+
+func (s *charmHubDownloadSuite) TestDownloadUnknownChannel(c *gc.C) {
+	resp := s.sendHTTPRequest(c, apitesting.HTTPRequestParams{
+		Method: "GET",
+		URL:    s.downloadURI("wordpress", "latest/edge", 16),
+	})
+	s.assertError(c, resp, http.StatusNotFound, `"wordpress" channel "latest/edge" not found`)
+}
+
+func (s *charmHubDownloadSuite) TestDownloadBadRevision(c *gc.C) {
+	resp := s.sendHTTPRequest(c, apitesting.HTTPRequestParams{
+		Method: "GET",
+		URL: s.URL(fmt.Sprintf("/model/%s/charmhub/download", s.State.ModelUUID()), url.Values{
+			"name":     {"wordpress"},
+			"channel":  {"latest/stable"},
+			"revision": {"latest"},
+		}).String(),
+	})
+	s.assertError(c, resp, http.StatusBadRequest, `invalid revision "latest"`)
+}
+
+func (s *charmHubDownloadSuite) TestUserWithoutModelAccess(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{
+		Password:    "hunter2",
+		NoModelUser: true,
+	})
+	resp := apitesting.SendHTTPRequest(c, apitesting.HTTPRequestParams{
+		Method:   "GET",
+		URL:      s.downloadURI("wordpress", "latest/stable", 16),
+		Tag:      user.Tag().String(),
+		Password: "hunter2",
+	})
+	s.assertError(c, resp, http.StatusUnauthorized, `permission denied`)
+	c.Check(s.store.Requests(), gc.HasLen, 0)
+}
@@ -35,18 +35,23 @@ var _ = gc.Suite(&controllerConfigSuite{})
 
 type fakeControllerAccessor struct {
 	controllerConfigError error
+	extraConfig           map[string]interface{}
 }
 
 func (f *fakeControllerAccessor) ControllerConfig() (controller.Config, error) {
 	if f.controllerConfigError != nil {
 		return nil, f.controllerConfigError
 	}
-	return map[string]interface{}{
+	cfg := map[string]interface{}{
 		controller.ControllerUUIDKey: testing.ControllerTag.Id(),
 		controller.CACertKey:         testing.CACert,
 		controller.APIPort:           4321,
 		controller.StatePort:         1234,
-	}, nil
+	}
+	for k, v := range f.extraConfig {
+		cfg[k] = v
+	}
+	return cfg, nil
 }
 
 func (f *fakeControllerAccessor) ControllerInfo(modelUUID string) ([]string, string, error) {
@@ -75,6 +80,32 @@ func (*controllerConfigSuite) TestControllerConfigSuccess(c *gc.C) {
 	})
 }
 
+func (*controllerConfigSuite) TestControllerConfigWithoutSecrets(c *gc.C) {
+	cc := common.NewControllerConfig(
+		&fakeControllerAccessor{
+			extraConfig: map[string]interface{}{
+				controller.LDAPBindDN:            "cn=juju,dc=example,dc=com",
+				controller.LDAPBindPassword:      "secret",
+				controller.AutocertEABKeyIDKey:   "kid",
+				controller.AutocertEABHMACKeyKey: "c2VjcmV0",
+				controller.AutocertDNSConfigKey:  []interface{}{"token=secret"},
+			},
+		},
+	)
+	result, err := cc.ControllerConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Config[controller.LDAPBindDN], gc.Equals, "cn=juju,dc=example,dc=com")
+	c.Assert(result.Config[controller.AutocertEABKeyIDKey], gc.Equals, "kid")
+	for _, key := range []string{
+		controller.LDAPBindPassword,
+		controller.AutocertEABHMACKeyKey,
+		controller.AutocertDNSConfigKey,
+	} {
+		_, ok := result.Config[key]
+		c.Check(ok, jc.IsFalse, gc.Commentf("%s", key))
+	}
+}
+
 func (*controllerConfigSuite) TestControllerConfigFetchError(c *gc.C) {
 	cc := common.NewControllerConfig(
 		&fakeControllerAccessor{
@@ -28,6 +28,11 @@ const (
 	sourcemodelKey = "source-model-uuid"
 	relationKey    = "relation-key"
 
+	// consumerModelKey is declared by offer macaroons to bind them to
+	// the model consuming the offer. Consume macaroons declare it empty,
+	// as they are issued before the consuming model is known.
+	consumerModelKey = "consumer-model-uuid"
+
 	offerPermissionCaveat = "has-offer-permission"
 
 	// localOfferPermissionExpiryTime is used to expire offer macaroons.
@@ -120,6 +125,9 @@ func (a *AuthContext) CheckOfferAccessCaveat(caveat string) (*offerPermissionChe
 	if !names.IsValidModel(details.SourceModelUUID) {
 		return nil, errors.NotValidf("source-model-uuid %q", details.SourceModelUUID)
 	}
+	if details.ConsumerModelUUID != "" && !names.IsValidModel(details.ConsumerModelUUID) {
+		return nil, errors.NotValidf("consumer-model-uuid %q", details.ConsumerModelUUID)
+	}
 	if !names.IsValidUser(details.User) {
 		return nil, errors.NotValidf("username %q", details.User)
 	}
@@ -154,6 +162,9 @@ func (a *AuthContext) CheckLocalAccessRequest(details *offerPermissionCheck) ([]
 	if details.Relation != "" {
 		firstPartyCaveats = append(firstPartyCaveats, checkers.DeclaredCaveat(relationKey, details.Relation))
 	}
+	if details.ConsumerModelUUID != "" {
+		firstPartyCaveats = append(firstPartyCaveats, checkers.DeclaredCaveat(consumerModelKey, details.ConsumerModelUUID))
+	}
 	return firstPartyCaveats, nil
 }
 
@@ -199,13 +210,14 @@ func (api *AuthContext) hasModelAdminAccess(st Backend, userTag names.UserTag) (
 	return isAdmin, err
 }
 
-func (a *AuthContext) offerPermissionYaml(sourceModelUUID, username, offerURL, relationKey string, permission permission.Access) (string, error) {
+func (a *AuthContext) offerPermissionYaml(sourceModelUUID, consumerModelUUID, username, offerURL, relationKey string, permission permission.Access) (string, error) {
 	out, err := yaml.Marshal(offerPermissionCheck{
-		SourceModelUUID: sourceModelUUID,
-		User:            username,
-		OfferUUID:       offerURL,
-		Relation:        relationKey,
-		Permission:      string(permission),
+		SourceModelUUID:   sourceModelUUID,
+		ConsumerModelUUID: consumerModelUUID,
+		User:              username,
+		OfferUUID:         offerURL,
+		Relation:          relationKey,
+		Permission:        string(permission),
 	})
 	if err != nil {
 		return "", err
@@ -233,6 +245,15 @@ func (a *AuthContext) CreateConsumeOfferMacaroon(ctx context.Context, offer *par
 			checkers.DeclaredCaveat(sourcemodelKey, sourceModelTag.Id()),
 			checkers.DeclaredCaveat(offeruuidKey, offer.OfferUUID),
 			checkers.DeclaredCaveat(usernameKey, username),
+			// The consumer model must be declared, even though it is
+			// empty. InferDeclared drops keys declared with conflicting
+			// values, so combining this macaroon with one declaring
+			// another consumer model leaves no consumer model declared
+			// at all. Without this caveat the other macaroon's model
+			// would be taken as declared; with it the consumer has to
+			// get a discharge that re-declares the model it consumes
+			// from.
+			checkers.DeclaredCaveat(consumerModelKey, ""),
 		}, crossModelConsumeOp(offer.OfferUUID))
 }
 
@@ -259,11 +280,12 @@ func (a *AuthContext) CreateRemoteRelationMacaroon(ctx context.Context, sourceMo
 }
 
 type offerPermissionCheck struct {
-	SourceModelUUID string `yaml:"source-model-uuid"`
-	User            string `yaml:"username"`
-	OfferUUID       string `yaml:"offer-uuid"`
-	Relation        string `yaml:"relation-key"`
-	Permission      string `yaml:"permission"`
+	SourceModelUUID   string `yaml:"source-model-uuid"`
+	ConsumerModelUUID string `yaml:"consumer-model-uuid,omitempty"`
+	User              string `yaml:"username"`
+	OfferUUID         string `yaml:"offer-uuid"`
+	Relation          string `yaml:"relation-key"`
+	Permission        string `yaml:"permission"`
 }
 
 type authenticator struct {
@@ -357,7 +379,13 @@ func (a *authenticator) checkMacaroons(
 	auth := a.bakery.Auth(mac)
 	ai, err := auth.Allow(ctx, op)
 	if err == nil && len(ai.Conditions()) > 0 {
-		if err = a.checkMacaroonCaveats(op, relation, offer); err == nil {
+		err = a.checkMacaroonCaveats(op, relation, offer)
+		if consumerModel, ok := requiredValues[consumerModelKey]; ok && err == nil && declared[consumerModelKey] != consumerModel {
+			// The macaroon isn't bound to the consuming model, so
+			// a discharge is needed to bind it.
+			err = &bakery.VerificationError{Reason: errors.Errorf("macaroon not issued for consumer model %q", consumerModel)}
+		}
+		if err == nil {
 			logger.Debugf("ok macaroon check ok, attr: %v, conditions: %v", declared, ai.Conditions())
 			return declared, nil
 		}
@@ -373,7 +401,8 @@ func (a *authenticator) checkMacaroons(
 	logger.Debugf("generating discharge macaroon because: %v", cause)
 
 	requiredRelation := requiredValues[relationKey]
-	authYaml, err := a.ctxt.offerPermissionYaml(a.sourceModelUUID, username, a.offerUUID, requiredRelation, permission.ConsumeAccess)
+	requiredConsumerModel := requiredValues[consumerModelKey]
+	authYaml, err := a.ctxt.offerPermissionYaml(a.sourceModelUUID, requiredConsumerModel, username, a.offerUUID, requiredRelation, permission.ConsumeAccess)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -420,6 +449,20 @@ func (a *authenticator) CheckOfferMacaroons(ctx context.Context, offerUUID strin
 	return a.checkMacaroons(ctx, mac, version, requiredValues, crossModelConsumeOp(offerUUID))
 }
 
+// CheckOfferMacaroonsForConsumer verifies that the specified macaroons
+// allow the consumer model to access the offer. The macaroons must
+// declare the consumer model, which a consume macaroon doesn't, so a
+// discharge binding the macaroon to the model is required the first
+// time.
+func (a *authenticator) CheckOfferMacaroonsForConsumer(ctx context.Context, offerUUID, consumerModelUUID string, mac macaroon.Slice, version bakery.Version) (map[string]string, error) {
+	requiredValues := map[string]string{
+		sourcemodelKey:   a.sourceModelUUID,
+		offeruuidKey:     offerUUID,
+		consumerModelKey: consumerModelUUID,
+	}
+	return a.checkMacaroons(ctx, mac, version, requiredValues, crossModelConsumeOp(offerUUID))
+}
+
 // CheckRelationMacaroons verifies that the specified macaroons allow access to the relation.
 func (a *authenticator) CheckRelationMacaroons(ctx context.Context, relationTag names.Tag, mac macaroon.Slice, version bakery.Version) error {
 	requiredValues := map[string]string{
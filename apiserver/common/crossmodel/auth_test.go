@@ -141,6 +141,30 @@ permission: consume
 	c.Assert(cav[4].Condition, gc.Equals, "declared relation-key mediawiki:db mysql:server")
 }
 
+func (s *authSuite) TestCheckLocalAccessRequestConsumerModel(c *gc.C) {
+	uuid := utils.MustNewUUID()
+	st := &mockState{
+		tag: names.NewModelTag(uuid.String()),
+		permissions: map[string]permission.Access{
+			"mysql-uuid:mary": permission.ConsumeAccess,
+		},
+	}
+	s.mockStatePool.st[uuid.String()] = st
+	permCheckDetails := fmt.Sprintf(`
+source-model-uuid: %v
+consumer-model-uuid: deadbeef-0bad-400d-8000-4b1d0d06f00e
+username: mary
+offer-uuid: mysql-uuid
+permission: consume
+`[1:], uuid)
+	opc, err := s.authContext.CheckOfferAccessCaveat("has-offer-permission " + permCheckDetails)
+	c.Assert(err, jc.ErrorIsNil)
+	cav, err := s.authContext.CheckLocalAccessRequest(opc)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cav, gc.HasLen, 5)
+	c.Assert(cav[4].Condition, gc.Equals, "declared consumer-model-uuid deadbeef-0bad-400d-8000-4b1d0d06f00e")
+}
+
 func (s *authSuite) TestCheckLocalAccessRequestControllerAdmin(c *gc.C) {
 	uuid := utils.MustNewUUID()
 	st := &mockState{
@@ -213,11 +237,12 @@ func (s *authSuite) TestCreateConsumeOfferMacaroon(c *gc.C) {
 	mac, err := s.authContext.CreateConsumeOfferMacaroon(context.TODO(), offer, "mary", bakery.LatestVersion)
 	c.Assert(err, jc.ErrorIsNil)
 	cav := mac.M().Caveats()
-	c.Assert(cav, gc.HasLen, 4)
+	c.Assert(cav, gc.HasLen, 5)
 	c.Assert(bytes.HasPrefix(cav[0].Id, []byte("time-before")), jc.IsTrue)
 	c.Assert(cav[1].Id, jc.DeepEquals, []byte("declared source-model-uuid "+coretesting.ModelTag.Id()))
 	c.Assert(cav[2].Id, jc.DeepEquals, []byte("declared offer-uuid mysql-uuid"))
 	c.Assert(cav[3].Id, jc.DeepEquals, []byte("declared username mary"))
+	c.Assert(cav[4].Id, jc.DeepEquals, []byte("declared consumer-model-uuid "))
 }
 
 func (s *authSuite) TestCreateRemoteRelationMacaroon(c *gc.C) {
@@ -330,6 +355,55 @@ func (s *authSuite) TestCheckOfferMacaroonsDischargeRequired(c *gc.C) {
 	c.Assert(cav[0].Location, gc.Equals, "http://thirdparty")
 }
 
+func (s *authSuite) TestCheckOfferMacaroonsForConsumer(c *gc.C) {
+	mac, err := s.bakery.NewMacaroon(
+		context.TODO(),
+		bakery.LatestVersion,
+		[]checkers.Caveat{
+			checkers.DeclaredCaveat("username", "mary"),
+			checkers.DeclaredCaveat("offer-uuid", "mysql-uuid"),
+			checkers.DeclaredCaveat("source-model-uuid", coretesting.ModelTag.Id()),
+			checkers.DeclaredCaveat("consumer-model-uuid", "deadbeef-0bad-400d-8000-4b1d0d06f00e"),
+		}, bakery.Op{"consume", "mysql-uuid"})
+
+	c.Assert(err, jc.ErrorIsNil)
+	attr, err := s.authContext.Authenticator(
+		coretesting.ModelTag.Id(), "mysql-uuid").CheckOfferMacaroonsForConsumer(
+		context.TODO(),
+		"mysql-uuid",
+		"deadbeef-0bad-400d-8000-4b1d0d06f00e",
+		macaroon.Slice{mac.M()},
+		bakery.LatestVersion,
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(attr["consumer-model-uuid"], gc.Equals, "deadbeef-0bad-400d-8000-4b1d0d06f00e")
+}
+
+func (s *authSuite) TestCheckOfferMacaroonsForConsumerDischargeRequired(c *gc.C) {
+	authContext := s.authContext.WithDischargeURL("http://thirdparty")
+	offer := &params.ApplicationOfferDetails{
+		SourceModelTag: coretesting.ModelTag.String(),
+		OfferUUID:      "mysql-uuid",
+	}
+	mac, err := authContext.CreateConsumeOfferMacaroon(context.TODO(), offer, "mary", bakery.LatestVersion)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// A consume macaroon isn't bound to any consuming model.
+	_, err = authContext.Authenticator(
+		coretesting.ModelTag.Id(), "mysql-uuid").CheckOfferMacaroonsForConsumer(
+		context.TODO(),
+		"mysql-uuid",
+		"deadbeef-0bad-400d-8000-4b1d0d06f00e",
+		macaroon.Slice{mac.M()},
+		bakery.LatestVersion,
+	)
+	dischargeErr, ok := err.(*common.DischargeRequiredError)
+	c.Assert(ok, jc.IsTrue)
+	cav := dischargeErr.LegacyMacaroon.Caveats()
+	c.Assert(cav, gc.HasLen, 2)
+	c.Assert(cav[0].Location, gc.Equals, "http://thirdparty")
+}
+
 func (s *authSuite) TestCheckRelationMacaroons(c *gc.C) {
 	relationTag := names.NewRelationTag("mediawiki:db mysql:server")
 	mac, err := s.bakery.NewMacaroon(
@@ -80,6 +80,28 @@ func IsDischargeRequiredError(err error) bool {
 	return ok
 }
 
+// OIDCLoginRequiredError is the error returned when a user must obtain
+// an ID token from an OpenID Connect provider to complete
+// authentication.
+type OIDCLoginRequiredError struct {
+	Cause    error
+	Issuer   string
+	ClientID string
+	Scopes   []string
+}
+
+// Error implements the error interface.
+func (e *OIDCLoginRequiredError) Error() string {
+	return e.Cause.Error()
+}
+
+// IsOIDCLoginRequiredError reports whether the cause
+// of the error is a *OIDCLoginRequiredError.
+func IsOIDCLoginRequiredError(err error) bool {
+	_, ok := errors.Cause(err).(*OIDCLoginRequiredError)
+	return ok
+}
+
 // IsUpgradeInProgress returns true if this error is caused
 // by an upgrade in progress.
 func IsUpgradeInProgressError(err error) bool {
@@ -125,6 +147,10 @@ var (
 	ErrBadCreds           = errors.New("invalid entity name or password")
 	ErrNoCreds            = errors.New("no credentials provided")
 	ErrLoginExpired       = errors.New("login expired")
+	ErrMFARequired        = errors.New("multi-factor authentication code required")
+	ErrBadMFACode         = errors.New("invalid multi-factor authentication code")
+	ErrLoginLockedOut     = errors.New("too many failed login attempts, try again later")
+	ErrPasswordExpired    = errors.New("password has expired and must be changed")
 	ErrPerm               = errors.New("permission denied")
 	ErrNotLoggedIn        = errors.New("not logged in")
 	ErrUnknownWatcher     = errors.New("unknown watcher id")
@@ -159,6 +185,10 @@ var singletonErrorCodes = map[error]string{
 	ErrBadCreds:                  params.CodeUnauthorized,
 	ErrNoCreds:                   params.CodeNoCreds,
 	ErrLoginExpired:              params.CodeLoginExpired,
+	ErrMFARequired:               params.CodeMFARequired,
+	ErrBadMFACode:                params.CodeUnauthorized,
+	ErrLoginLockedOut:            params.CodeUnauthorized,
+	ErrPasswordExpired:           params.CodeUnauthorized,
 	ErrPerm:                      params.CodeUnauthorized,
 	ErrNotLoggedIn:               params.CodeUnauthorized,
 	ErrUnknownWatcher:            params.CodeNotFound,
@@ -198,7 +228,7 @@ func ServerErrorAndStatus(err error) (*params.Error, int) {
 	}
 	status := http.StatusInternalServerError
 	switch err1.Code {
-	case params.CodeUnauthorized:
+	case params.CodeUnauthorized, params.CodeMFARequired:
 		status = http.StatusUnauthorized
 	case params.CodeNotFound,
 		params.CodeUserNotFound,
@@ -208,6 +238,8 @@ func ServerErrorAndStatus(err error) (*params.Error, int) {
 		status = http.StatusBadRequest
 	case params.CodeMethodNotAllowed:
 		status = http.StatusMethodNotAllowed
+	case params.CodeUnsupportedMediaType:
+		status = http.StatusUnsupportedMediaType
 	case params.CodeOperationBlocked:
 		// This should really be http.StatusForbidden but earlier versions
 		// of juju clients rely on the 400 status, so we leave it like that.
@@ -294,6 +326,8 @@ func ServerError(err error) *params.Error {
 			// One macaroon fits all.
 			MacaroonPath: "/",
 		}.AsMap()
+	case IsOIDCLoginRequiredError(err):
+		code = params.CodeUnauthorized
 	case IsRedirectError(err):
 		redirErr := errors.Cause(err).(*RedirectError)
 		code = params.CodeRedirect
@@ -91,6 +91,26 @@ var errorTransformTests = []struct {
 	code:       params.CodeUnauthorized,
 	status:     http.StatusUnauthorized,
 	helperFunc: params.IsCodeUnauthorized,
+}, {
+	err:        common.ErrMFARequired,
+	code:       params.CodeMFARequired,
+	status:     http.StatusUnauthorized,
+	helperFunc: params.IsCodeMFARequired,
+}, {
+	err:        common.ErrBadMFACode,
+	code:       params.CodeUnauthorized,
+	status:     http.StatusUnauthorized,
+	helperFunc: params.IsCodeUnauthorized,
+}, {
+	err:        common.ErrLoginLockedOut,
+	code:       params.CodeUnauthorized,
+	status:     http.StatusUnauthorized,
+	helperFunc: params.IsCodeUnauthorized,
+}, {
+	err:        common.ErrPasswordExpired,
+	code:       params.CodeUnauthorized,
+	status:     http.StatusUnauthorized,
+	helperFunc: params.IsCodeUnauthorized,
 }, {
 	err:        common.ErrPerm,
 	code:       params.CodeUnauthorized,
@@ -166,6 +186,14 @@ var errorTransformTests = []struct {
 	code:       params.CodeMethodNotAllowed,
 	status:     http.StatusMethodNotAllowed,
 	helperFunc: params.IsMethodNotAllowed,
+}, {
+	err: &params.Error{
+		Message: "unsupported content type",
+		Code:    params.CodeUnsupportedMediaType,
+	},
+	code:       params.CodeUnsupportedMediaType,
+	status:     http.StatusUnsupportedMediaType,
+	helperFunc: params.IsCodeUnsupportedMediaType,
 }, {
 	err:    stderrors.New("an error"),
 	status: http.StatusInternalServerError,
@@ -32,9 +32,9 @@ type ModelManagerBackend interface {
 	state.CloudAccessor
 
 	ModelUUID() string
-	ModelUUIDsForUser(names.UserTag) ([]string, error)
-	ModelBasicInfoForUser(user names.UserTag) ([]state.ModelAccessInfo, error)
-	ModelSummariesForUser(user names.UserTag, all bool) ([]state.ModelSummary, error)
+	ModelUUIDsForUser(user names.UserTag, groups []string) ([]string, error)
+	ModelBasicInfoForUser(user names.UserTag, groups []string) ([]state.ModelAccessInfo, error)
+	ModelSummariesForUser(user names.UserTag, groups []string, all bool) ([]state.ModelSummary, error)
 	IsControllerAdmin(user names.UserTag) (bool, error)
 	NewModel(state.ModelArgs) (Model, ModelManagerBackend, error)
 	Model() (Model, error)
@@ -67,6 +67,10 @@ type ModelManagerBackend interface {
 	Export() (description.Model, error)
 	ExportPartial(state.ExportConfig) (description.Model, error)
 	SetUserAccess(subject names.UserTag, target names.Tag, access permission.Access) (permission.UserAccess, error)
+	CreateGroupAccess(group string, target names.Tag, access permission.Access) error
+	GroupAccess(group string, target names.Tag) (permission.Access, error)
+	UpdateGroupAccess(group string, target names.Tag, access permission.Access) error
+	RemoveGroupAccess(group string, target names.Tag) error
 	SetModelMeterStatus(string, string) error
 	AllSpaces() ([]*state.Space, error)
 	AddSpace(string, network.Id, []string, bool) (*state.Space, error)
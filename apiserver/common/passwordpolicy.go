@@ -0,0 +1,48 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package common
+
+import (
+	"fmt"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/controller"
+)
+
+// ValidatePassword checks that a new password for a local user meets
+// the controller's password policy, returning an error satisfying
+// errors.IsNotValid if it does not.
+func ValidatePassword(cfg controller.Config, password string) error {
+	if min := cfg.PasswordMinLength(); utf8.RuneCountInString(password) < min {
+		return errors.NewNotValid(nil, fmt.Sprintf("password must be at least %d characters long", min))
+	}
+	if min := cfg.PasswordMinCharClasses(); passwordCharClasses(password) < min {
+		return errors.NewNotValid(nil, fmt.Sprintf(
+			"password must contain at least %d of: lower case letters, upper case letters, digits and other characters", min,
+		))
+	}
+	return nil
+}
+
+// passwordCharClasses returns the number of character classes (lower
+// case letters, upper case letters, digits and others) in the password.
+func passwordCharClasses(password string) int {
+	var lower, upper, digit, other int
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = 1
+		case unicode.IsUpper(r):
+			upper = 1
+		case unicode.IsDigit(r):
+			digit = 1
+		default:
+			other = 1
+		}
+	}
+	return lower + upper + digit + other
+}
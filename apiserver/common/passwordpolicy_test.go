@@ -0,0 +1,60 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package common_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/controller"
+)
+
+type passwordPolicySuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&passwordPolicySuite{})
+
+func (*passwordPolicySuite) TestNoPolicy(c *gc.C) {
+	c.Assert(common.ValidatePassword(controller.Config{}, "x"), jc.ErrorIsNil)
+}
+
+func (*passwordPolicySuite) TestValidatePassword(c *gc.C) {
+	cfg := controller.Config{
+		controller.PasswordMinLength:      10,
+		controller.PasswordMinCharClasses: 3,
+	}
+	for i, test := range []struct {
+		password string
+		err      string
+	}{{
+		password: "Sh0rt!",
+		err:      "password must be at least 10 characters long",
+	}, {
+		password: "longlowercase",
+		err:      "password must contain at least 3 of: .*",
+	}, {
+		password: "LongMixedCase",
+		err:      "password must contain at least 3 of: .*",
+	}, {
+		password: "LongMixedCase1",
+	}, {
+		password: "long lowercase 1",
+	}, {
+		password: "ünïcödé-pässwörd",
+		err:      "password must contain at least 3 of: .*",
+	}} {
+		c.Logf("test %d: %q", i, test.password)
+		err := common.ValidatePassword(cfg, test.password)
+		if test.err == "" {
+			c.Check(err, jc.ErrorIsNil)
+			continue
+		}
+		c.Check(err, gc.ErrorMatches, test.err)
+		c.Check(err, jc.Satisfies, errors.IsNotValid)
+	}
+}
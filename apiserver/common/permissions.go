@@ -4,6 +4,8 @@
 package common
 
 import (
+	"strings"
+
 	"github.com/juju/errors"
 	"github.com/juju/names/v4"
 
@@ -65,6 +67,22 @@ func HasPermission(
 	return true, nil
 }
 
+// HasDirectOrGroupPermission returns true if the specified user has the
+// specified permission on target, according to accessGetter or, only
+// when that does not suffice, according to groupAccessGetter. It saves
+// looking up the user's groups when the access granted to the user
+// directly is enough.
+func HasDirectOrGroupPermission(
+	accessGetter, groupAccessGetter userAccessFunc, utag names.Tag,
+	requestedPermission permission.Access, target names.Tag,
+) (bool, error) {
+	hasPermission, err := HasPermission(accessGetter, utag, requestedPermission, target)
+	if err != nil || hasPermission {
+		return hasPermission, errors.Trace(err)
+	}
+	return HasPermission(groupAccessGetter, utag, requestedPermission, target)
+}
+
 // GetPermission returns the permission a user has on the specified target.
 func GetPermission(accessGetter userAccessFunc, userTag names.UserTag, target names.Tag) (permission.Access, error) {
 	userAccess, err := accessGetter(userTag, target)
@@ -90,6 +108,127 @@ func GetPermission(accessGetter userAccessFunc, userTag names.UserTag, target na
 	return userAccess, nil
 }
 
+// GroupAccessGetter provides the access granted to groups on a model
+// or controller.
+type GroupAccessGetter interface {
+	// GroupsAccess returns the access granted on the target, keyed
+	// by lower-cased group name.
+	GroupsAccess(target names.Tag) (map[string]permission.Access, error)
+}
+
+// GroupPermission returns the greatest access granted on the model or
+// controller target to any of the named groups.
+func GroupPermission(getter GroupAccessGetter, groups []string, target names.Tag) (permission.Access, error) {
+	if kind := target.Kind(); len(groups) == 0 || kind != names.ModelTagKind && kind != names.ControllerTagKind {
+		return permission.NoAccess, nil
+	}
+	groupsAccess, err := getter.GroupsAccess(target)
+	if err != nil {
+		return permission.NoAccess, errors.Trace(err)
+	}
+	return greatestGroupAccess(groupsAccess, groups, target), nil
+}
+
+// greatestGroupAccess returns the greatest of the access levels in
+// groupsAccess granted to any of the named groups.
+func greatestGroupAccess(groupsAccess map[string]permission.Access, groups []string, target names.Tag) permission.Access {
+	var greater func(a, b permission.Access) bool
+	switch target.Kind() {
+	case names.ModelTagKind:
+		greater = permission.Access.GreaterModelAccessThan
+	case names.ControllerTagKind:
+		greater = permission.Access.GreaterControllerAccessThan
+	default:
+		return permission.NoAccess
+	}
+	access := permission.NoAccess
+	for _, group := range groups {
+		if groupAccess, ok := groupsAccess[strings.ToLower(group)]; ok && greater(groupAccess, access) {
+			access = groupAccess
+		}
+	}
+	return access
+}
+
+// WithGroupAccess returns an access getter that raises the access
+// returned by accessGetter to the greatest access granted to the
+// groups the user belongs to, as reported by userGroups. The user's
+// groups are not looked up unless some group has been granted access
+// to the target. The result is suitable for passing to HasPermission.
+func WithGroupAccess(
+	accessGetter userAccessFunc,
+	getter GroupAccessGetter,
+	userGroups func(names.UserTag) ([]string, error),
+) func(names.UserTag, names.Tag) (permission.Access, error) {
+	return func(userTag names.UserTag, target names.Tag) (permission.Access, error) {
+		userAccess, err := accessGetter(userTag, target)
+		if err != nil && !errors.IsNotFound(err) {
+			return permission.NoAccess, errors.Trace(err)
+		}
+		kind := target.Kind()
+		if userTag.Id() == EveryoneTagName || (kind != names.ModelTagKind && kind != names.ControllerTagKind) {
+			return userAccess, errors.Trace(err)
+		}
+		groupsAccess, groupErr := getter.GroupsAccess(target)
+		if groupErr != nil {
+			return permission.NoAccess, errors.Trace(groupErr)
+		}
+		if len(groupsAccess) == 0 {
+			return userAccess, errors.Trace(err)
+		}
+		groups, groupsErr := userGroups(userTag)
+		if groupsErr != nil {
+			// A directory outage must not take away access
+			// that was granted to the user directly.
+			logger.Warningf("cannot obtain groups for %s: %v", userTag.Id(), groupsErr)
+			return userAccess, errors.Trace(err)
+		}
+		groupAccess := greatestGroupAccess(groupsAccess, groups, target)
+		if groupAccess == permission.NoAccess {
+			return userAccess, errors.Trace(err)
+		}
+		if err != nil ||
+			(kind == names.ModelTagKind && groupAccess.GreaterModelAccessThan(userAccess)) ||
+			(kind == names.ControllerTagKind && groupAccess.GreaterControllerAccessThan(userAccess)) {
+			return groupAccess, nil
+		}
+		return userAccess, nil
+	}
+}
+
+// WithTokenScope returns an access getter that limits the access
+// returned by accessGetter for the owner of an API token to the scope
+// of the token: at most tokenAccess to the token's model, login access
+// to the controller, and no access to anything else. The access of
+// other users is not limited. The result is suitable for passing to
+// HasPermission.
+func WithTokenScope(
+	accessGetter userAccessFunc,
+	owner names.UserTag,
+	model names.ModelTag,
+	tokenAccess permission.Access,
+) func(names.UserTag, names.Tag) (permission.Access, error) {
+	return func(userTag names.UserTag, target names.Tag) (permission.Access, error) {
+		userAccess, err := accessGetter(userTag, target)
+		if err != nil || userTag.Id() != owner.Id() {
+			return userAccess, errors.Trace(err)
+		}
+		switch {
+		case target.String() == model.String():
+			if userAccess.GreaterModelAccessThan(tokenAccess) {
+				return tokenAccess, nil
+			}
+			return userAccess, nil
+		case target.Kind() == names.ControllerTagKind:
+			if userAccess.GreaterControllerAccessThan(permission.LoginAccess) {
+				return permission.LoginAccess, nil
+			}
+			return userAccess, nil
+		}
+		return permission.NoAccess, nil
+	}
+}
+
 // HasModelAdmin reports whether or not a user has admin access to the
 // specified model. A user has model access if they are a controller
 // superuser, or if they have been explicitly granted admin access to the
@@ -237,3 +237,143 @@ func (r *PermissionSuite) TestEveryoneAtExternal(c *gc.C) {
 		c.Assert(hasPermission, gc.Equals, t.expected)
 	}
 }
+
+type fakeGroupAccess map[string]permission.Access
+
+func (f fakeGroupAccess) GroupsAccess(target names.Tag) (map[string]permission.Access, error) {
+	return f, nil
+}
+
+func (r *PermissionSuite) TestGroupPermission(c *gc.C) {
+	groups := fakeGroupAccess{
+		"ops": permission.AdminAccess,
+		"dev": permission.WriteAccess,
+	}
+	target := names.NewModelTag("beef1beef2-0000-0000-000011112222")
+	access, err := common.GroupPermission(groups, []string{"Dev", "qa"}, target)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(access, gc.Equals, permission.WriteAccess)
+
+	access, err = common.GroupPermission(groups, []string{"dev", "ops"}, target)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(access, gc.Equals, permission.AdminAccess)
+
+	access, err = common.GroupPermission(groups, nil, target)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(access, gc.Equals, permission.NoAccess)
+
+	access, err = common.GroupPermission(groups, []string{"ops"}, names.NewCloudTag("mycloud"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(access, gc.Equals, permission.NoAccess)
+}
+
+func (r *PermissionSuite) TestWithGroupAccess(c *gc.C) {
+	groups := fakeGroupAccess{"ops": permission.AdminAccess}
+	userGroups := func(user names.UserTag) ([]string, error) {
+		if user.Name() == "bob" {
+			return []string{"ops"}, nil
+		}
+		return nil, nil
+	}
+	userGetter := &fakeUserAccess{
+		access: permission.NoAccess,
+		err:    errors.NotFoundf("a user"),
+	}
+	accessGetter := common.WithGroupAccess(userGetter.call, groups, userGroups)
+	target := names.NewModelTag("beef1beef2-0000-0000-000011112222")
+
+	hasPermission, err := common.HasPermission(accessGetter, names.NewUserTag("bob"), permission.AdminAccess, target)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(hasPermission, jc.IsTrue)
+
+	hasPermission, err = common.HasPermission(accessGetter, names.NewUserTag("mary"), permission.ReadAccess, target)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(hasPermission, jc.IsFalse)
+
+	// Group access does not lower access granted directly.
+	userGetter.access, userGetter.err = permission.AdminAccess, nil
+	groups["ops"] = permission.ReadAccess
+	access, err := accessGetter(names.NewUserTag("bob"), target)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(access, gc.Equals, permission.AdminAccess)
+}
+
+func (r *PermissionSuite) TestWithTokenScope(c *gc.C) {
+	owner := names.NewUserTag("bot")
+	model := names.NewModelTag("beef1beef2-0000-0000-000011112222")
+	userGetter := &fakeUserAccess{access: permission.AdminAccess}
+	accessGetter := common.WithTokenScope(userGetter.call, owner, model, permission.ReadAccess)
+
+	access, err := accessGetter(owner, model)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(access, gc.Equals, permission.ReadAccess)
+
+	userGetter.access = permission.SuperuserAccess
+	access, err = accessGetter(owner, names.NewControllerTag("beef1beef2-0000-0000-000011113333"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(access, gc.Equals, permission.LoginAccess)
+
+	access, err = accessGetter(owner, names.NewModelTag("beef1beef2-0000-0000-000011114444"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(access, gc.Equals, permission.NoAccess)
+
+	// The access of other users is not limited.
+	userGetter.access = permission.AdminAccess
+	access, err = accessGetter(names.NewUserTag("mary"), model)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(access, gc.Equals, permission.AdminAccess)
+
+	// Access lower than the token's is not raised.
+	userGetter.access = permission.NoAccess
+	hasPermission, err := common.HasPermission(accessGetter, owner, permission.ReadAccess, model)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(hasPermission, jc.IsFalse)
+}
+
+func (r *PermissionSuite) TestWithGroupAccessDirectoryError(c *gc.C) {
+	userGroups := func(names.UserTag) ([]string, error) {
+		return nil, errors.New("directory unavailable")
+	}
+	userGetter := &fakeUserAccess{access: permission.WriteAccess}
+	groups := fakeGroupAccess{"ops": permission.AdminAccess}
+	accessGetter := common.WithGroupAccess(userGetter.call, groups, userGroups)
+	access, err := accessGetter(names.NewUserTag("bob"), names.NewModelTag("beef1beef2-0000-0000-000011112222"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(access, gc.Equals, permission.WriteAccess)
+}
+
+func (r *PermissionSuite) TestWithGroupAccessNoGroupGrants(c *gc.C) {
+	userGroups := func(names.UserTag) ([]string, error) {
+		c.Fatalf("groups looked up with no group granted access")
+		return nil, nil
+	}
+	userGetter := &fakeUserAccess{access: permission.ReadAccess}
+	accessGetter := common.WithGroupAccess(userGetter.call, fakeGroupAccess{}, userGroups)
+	access, err := accessGetter(names.NewUserTag("bob"), names.NewModelTag("beef1beef2-0000-0000-000011112222"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(access, gc.Equals, permission.ReadAccess)
+}
+
+func (r *PermissionSuite) TestHasDirectOrGroupPermission(c *gc.C) {
+	var lookups int
+	userGroups := func(names.UserTag) ([]string, error) {
+		lookups++
+		return []string{"ops"}, nil
+	}
+	userGetter := &fakeUserAccess{access: permission.WriteAccess}
+	groups := fakeGroupAccess{"ops": permission.AdminAccess}
+	groupAccessGetter := common.WithGroupAccess(userGetter.call, groups, userGroups)
+	user := names.NewUserTag("bob")
+	target := names.NewModelTag("beef1beef2-0000-0000-000011112222")
+
+	// Direct access suffices, so the groups are not looked up.
+	hasPermission, err := common.HasDirectOrGroupPermission(userGetter.call, groupAccessGetter, user, permission.WriteAccess, target)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(hasPermission, jc.IsTrue)
+	c.Assert(lookups, gc.Equals, 0)
+
+	hasPermission, err = common.HasDirectOrGroupPermission(userGetter.call, groupAccessGetter, user, permission.AdminAccess, target)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(hasPermission, jc.IsTrue)
+	c.Assert(lookups, gc.Equals, 1)
+}
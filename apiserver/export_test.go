@@ -86,6 +86,13 @@ func TestingUpgradingRoot() rpc.Root {
 	return restrictRoot(r, upgradeMethodsOnly)
 }
 
+// TestingPasswordExpiredRoot returns a restricted srvRoot for a user
+// whose password has expired.
+func TestingPasswordExpiredRoot() rpc.Root {
+	r := TestingAPIRoot(AllFacades())
+	return restrictRoot(r, passwordChangeMethodsOnly)
+}
+
 // TestingMigratingRoot returns a resricted srvRoot in a migration
 // scenario.
 func TestingMigratingRoot() rpc.Root {
@@ -167,3 +174,25 @@ func AssertHasPermission(c *gc.C, handler *apiHandler, access permission.Access,
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(hasPermission, gc.Equals, expect)
 }
+
+// NewRoleAuthorizer returns the authorizer used for calls to the given
+// facade method, with the role lookup replaced by role.
+func NewRoleAuthorizer(
+	auth facade.Authorizer, modelTag names.ModelTag, facadeName, methodName string,
+	role func() (permission.Role, bool, error),
+) facade.Authorizer {
+	return &roleAuthorizer{
+		Authorizer: auth,
+		modelTag:   modelTag,
+		facadeName: facadeName,
+		methodName: methodName,
+		role:       role,
+	}
+}
+
+// NewRoleCache returns a cache of a user's custom role, which loads
+// the role with load and loads it again after each change.
+func NewRoleCache(changes <-chan struct{}, load func() (permission.Role, error)) func() (permission.Role, bool, error) {
+	cache := &roleCache{changes: changes, load: load}
+	return cache.get
+}
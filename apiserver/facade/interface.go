@@ -171,6 +171,10 @@ type Authorizer interface {
 	// target by the given user.
 	UserHasPermission(user names.UserTag, operation permission.Access, target names.Tag) (bool, error)
 
+	// UserGroups returns the groups the given user belongs to, whose
+	// access is also granted to the user.
+	UserGroups(user names.UserTag) []string
+
 	// ConnectedModel returns the UUID of the model to which the API
 	// connection was made.
 	ConnectedModel() string
@@ -241,6 +241,20 @@ func (mr *MockAuthorizerMockRecorder) HasPermission(arg0, arg1 interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasPermission", reflect.TypeOf((*MockAuthorizer)(nil).HasPermission), arg0, arg1)
 }
 
+// UserGroups mocks base method
+func (m *MockAuthorizer) UserGroups(arg0 names.UserTag) []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UserGroups", arg0)
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// UserGroups indicates an expected call of UserGroups
+func (mr *MockAuthorizerMockRecorder) UserGroups(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UserGroups", reflect.TypeOf((*MockAuthorizer)(nil).UserGroups), arg0)
+}
+
 // UserHasPermission mocks base method
 func (m *MockAuthorizer) UserHasPermission(arg0 names.UserTag, arg1 permission.Access, arg2 names.Tag) (bool, error) {
 	m.ctrl.T.Helper()
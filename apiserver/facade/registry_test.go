@@ -237,7 +237,7 @@ func (*RegistrySuite) TestWrapNewFacadeCallsWithRightParams(c *gc.C) {
 	asResult := val.(*myResult)
 	c.Check(asResult.st, gc.IsNil)
 	c.Check(asResult.resources, gc.Equals, resources)
-	c.Check(asResult.auth, gc.Equals, authorizer)
+	c.Check(asResult.auth, jc.DeepEquals, authorizer)
 }
 
 func (s *RegistrySuite) TestRegisterStandard(c *gc.C) {
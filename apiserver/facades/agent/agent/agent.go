@@ -41,6 +41,10 @@ func NewAgentAPIV2(st *state.State, resources facade.Resources, auth facade.Auth
 	if !auth.AuthMachineAgent() && !auth.AuthUnitAgent() {
 		return nil, common.ErrPerm
 	}
+	return newAgentAPIV2(st, resources, auth)
+}
+
+func newAgentAPIV2(st *state.State, resources facade.Resources, auth facade.Authorizer) (*AgentAPIV2, error) {
 	getCanChange := func() (common.AuthFunc, error) {
 		return auth.AuthOwner, nil
 	}
@@ -68,6 +72,44 @@ func NewAgentAPIV2(st *state.State, resources facade.Resources, auth facade.Auth
 	}, nil
 }
 
+// AgentAPIV3 implements the version 3 of the API provided to an agent,
+// which adds WatchControllerConfig.
+type AgentAPIV3 struct {
+	*AgentAPIV2
+}
+
+// NewAgentAPIV3 returns an object implementing version 3 of the Agent API
+// with the given authorizer representing the currently logged in client.
+func NewAgentAPIV3(st *state.State, resources facade.Resources, auth facade.Authorizer) (*AgentAPIV3, error) {
+	// CAAS operators also need to follow rotations of the
+	// controller's CA.
+	if !auth.AuthMachineAgent() && !auth.AuthUnitAgent() && !auth.AuthApplicationAgent() && !auth.AuthModelAgent() {
+		return nil, common.ErrPerm
+	}
+	v2, err := newAgentAPIV2(st, resources, auth)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &AgentAPIV3{v2}, nil
+}
+
+// WatchControllerConfig returns a NotifyWatcher that notifies when the
+// controller's configuration changes, so that agents can pick up changes
+// to the CA certificates they should trust.
+func (api *AgentAPIV3) WatchControllerConfig() (params.NotifyWatchResult, error) {
+	result := params.NotifyWatchResult{}
+	watch := api.st.WatchControllerConfig()
+	// Consume the initial event. Technically, API calls to Watch
+	// 'transmit' the initial event in the Watch response. But
+	// NotifyWatchers have no state to transmit.
+	if _, ok := <-watch.Changes(); ok {
+		result.NotifyWatcherId = api.resources.Register(watch)
+	} else {
+		return result, watcher.EnsureErr(watch)
+	}
+	return result, nil
+}
+
 func (api *AgentAPIV2) GetEntities(args params.Entities) params.AgentGetEntitiesResults {
 	results := params.AgentGetEntitiesResults{
 		Entities: make([]params.AgentGetEntitiesResult, len(args.Entities)),
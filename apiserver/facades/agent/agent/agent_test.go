@@ -83,6 +83,20 @@ func (s *agentSuite) TestAgentSucceedsWithUnitAgent(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 }
 
+func (s *agentSuite) TestAgentV3SucceedsWithOperators(c *gc.C) {
+	auth := s.authorizer
+	for _, tag := range []names.Tag{
+		names.NewApplicationTag("gitlab"),
+		s.Model.ModelTag(),
+	} {
+		auth.Tag = tag
+		_, err := agent.NewAgentAPIV2(s.State, s.resources, auth)
+		c.Check(err, gc.ErrorMatches, "permission denied")
+		_, err = agent.NewAgentAPIV3(s.State, s.resources, auth)
+		c.Check(err, jc.ErrorIsNil)
+	}
+}
+
 func (s *agentSuite) TestGetEntities(c *gc.C) {
 	err := s.container.Destroy()
 	c.Assert(err, jc.ErrorIsNil)
@@ -276,3 +290,26 @@ func (s *agentSuite) TestWatchAuthError(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "permission denied")
 	c.Assert(s.resources.Count(), gc.Equals, 0)
 }
+
+func (s *agentSuite) TestWatchControllerConfig(c *gc.C) {
+	authorizer := apiservertesting.FakeAuthorizer{
+		Tag: names.NewMachineTag("1"),
+	}
+	api, err := agent.NewAgentAPIV3(s.State, s.resources, authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	result, err := api.WatchControllerConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.NotifyWatchResult{NotifyWatcherId: "1"})
+	c.Assert(s.resources.Count(), gc.Equals, 1)
+
+	w := s.resources.Get("1")
+	defer statetesting.AssertStop(c, w)
+
+	// Check that the Watch has consumed the initial events ("returned" in the Watch call)
+	wc := statetesting.NewNotifyWatcherC(c, s.State, w.(state.NotifyWatcher))
+	wc.AssertNoChange()
+
+	err = s.State.UpdateControllerConfig(map[string]interface{}{"auditing-enabled": true}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+}
@@ -127,14 +127,14 @@ func (api *ProvisionerAPI) getProvisioningInfoBase(
 	var err error
 
 	result := params.ProvisioningInfoBase{
-		Series:            m.Series(),
-		Placement:         m.Placement(),
-		CloudInitUserData: env.Config().CloudInitUserData(),
+		Series:    m.Series(),
+		Placement: m.Placement(),
 	}
 
 	if result.Constraints, err = m.Constraints(); err != nil {
 		return result, errors.Trace(err)
 	}
+	result.CloudInitUserData = env.Config().CloudInitUserDataFor(result.Constraints)
 
 	if result.Volumes, result.VolumeAttachments, err = api.machineVolumeParams(m, env); err != nil {
 		return result, errors.Trace(err)
@@ -832,6 +832,11 @@ func (u *UniterAPI) CharmURL(args params.Entities) (params.StringBoolResults, er
 					CharmURL() (*charm.URL, bool)
 				})
 				curl, ok := charmURLer.CharmURL()
+				if _, isApp := unitOrApplication.(*state.Application); isApp {
+					// A unit tracking a branch that upgrades the charm
+					// runs the branch charm, rather than the application's.
+					curl, err = u.branchCharmURL(curl)
+				}
 				if curl != nil {
 					result.Results[i].Result = curl.String()
 					result.Results[i].Ok = ok
@@ -843,6 +848,21 @@ func (u *UniterAPI) CharmURL(args params.Entities) (params.StringBoolResults, er
 	return result, nil
 }
 
+// branchCharmURL returns the charm URL of the branch tracked by the
+// authenticated unit, if the branch upgrades its application's charm.
+// Otherwise the input application charm URL is returned.
+func (u *UniterAPI) branchCharmURL(appCharmURL *charm.URL) (*charm.URL, error) {
+	unitTag, ok := u.auth.GetAuthTag().(names.UnitTag)
+	if !ok {
+		return appCharmURL, nil
+	}
+	curl, err := u.st.BranchCharmURL(unitTag.Id())
+	if errors.IsNotFound(err) {
+		return appCharmURL, nil
+	}
+	return curl, errors.Trace(err)
+}
+
 // SetCharmURL sets the charm URL for each given unit. An error will
 // be returned if a unit is dead, or the charm URL is not known.
 func (u *UniterAPI) SetCharmURL(args params.EntitiesCharmURL) (params.ErrorResults, error) {
@@ -861,6 +861,31 @@ func (s *uniterSuite) TestDestroyAllSubordinates(c *gc.C) {
 	c.Assert(monitoringSub.Life(), gc.Equals, state.Dying)
 }
 
+func (s *uniterSuite) TestCharmURLBranch(c *gc.C) {
+	newCharm := s.Factory.MakeCharm(c, &factory.CharmParams{
+		Name: "wordpress",
+		URL:  "cs:quantal/wordpress-4",
+	})
+	c.Assert(s.Model.AddBranch("canary", "testuser"), jc.ErrorIsNil)
+	branch, err := s.Model.Branch("canary")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(branch.AssignUnit(s.wordpressUnit.Name()), jc.ErrorIsNil)
+	c.Assert(branch.Refresh(), jc.ErrorIsNil)
+	c.Assert(branch.UpdateCharmURL(s.wordpress.Name(), newCharm.URL(), nil), jc.ErrorIsNil)
+
+	// The unit tracking the branch is told to run the branch charm.
+	args := params.Entities{Entities: []params.Entity{
+		{Tag: "application-wordpress"},
+	}}
+	result, err := s.uniter.CharmURL(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.StringBoolResults{
+		Results: []params.StringBoolResult{
+			{Result: newCharm.String()},
+		},
+	})
+}
+
 func (s *uniterSuite) TestCharmURL(c *gc.C) {
 	// Set wordpressUnit's charm URL first.
 	err := s.wordpressUnit.SetCharmURL(s.wpCharm.URL())
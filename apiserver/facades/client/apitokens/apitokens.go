@@ -0,0 +1,205 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package apitokens provides the APITokens facade, which mints, lists
+// and revokes the API tokens automation users log in with.
+package apitokens
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/permission"
+	"github.com/juju/juju/state"
+)
+
+// Backend describes the state methods used by the APITokens facade.
+type Backend interface {
+	ControllerTag() names.ControllerTag
+	AddAPIToken(state.AddAPITokenArgs) (state.APIToken, string, error)
+	APIToken(id string) (state.APIToken, error)
+	AllAPITokens() ([]state.APIToken, error)
+	RemoveAPIToken(id string) error
+}
+
+var _ Backend = (*state.State)(nil)
+
+// APITokensAPI implements the APITokens facade.
+type APITokensAPI struct {
+	backend    Backend
+	authorizer facade.Authorizer
+	apiUser    names.UserTag
+}
+
+// NewFacade creates a new APITokensAPI facade.
+func NewFacade(ctx facade.Context) (*APITokensAPI, error) {
+	return NewAPITokensAPI(ctx.State(), ctx.Auth())
+}
+
+// NewAPITokensAPI creates a new APITokensAPI using the given backend.
+func NewAPITokensAPI(backend Backend, authorizer facade.Authorizer) (*APITokensAPI, error) {
+	if !authorizer.AuthClient() {
+		return nil, common.ErrPerm
+	}
+	apiUser, _ := authorizer.GetAuthTag().(names.UserTag)
+	return &APITokensAPI{
+		backend:    backend,
+		authorizer: authorizer,
+		apiUser:    apiUser,
+	}, nil
+}
+
+func (api *APITokensAPI) isSuperuser() (bool, error) {
+	return api.authorizer.HasPermission(permission.SuperuserAccess, api.backend.ControllerTag())
+}
+
+// checkCanAdmin checks that the caller is a controller superuser or
+// an admin of the model.
+func (api *APITokensAPI) checkCanAdmin(model names.ModelTag) error {
+	isSuperuser, err := api.isSuperuser()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if isSuperuser {
+		return nil
+	}
+	isAdmin, err := api.authorizer.HasPermission(permission.AdminAccess, model)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !isAdmin {
+		return common.ErrPerm
+	}
+	return nil
+}
+
+// AddAPITokens mints API tokens. Only model admins may mint tokens for
+// their models, and only for users who have access to the model.
+func (api *APITokensAPI) AddAPITokens(args params.AddAPITokens) (params.AddAPITokenResults, error) {
+	results := params.AddAPITokenResults{
+		Results: make([]params.AddAPITokenResult, len(args.Tokens)),
+	}
+	for i, arg := range args.Tokens {
+		token, secret, err := api.addAPIToken(arg)
+		if err != nil {
+			results.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		results.Results[i] = params.AddAPITokenResult{
+			Token:  apiTokenParams(token),
+			Secret: secret,
+		}
+	}
+	return results, nil
+}
+
+func (api *APITokensAPI) addAPIToken(arg params.AddAPIToken) (state.APIToken, string, error) {
+	modelTag, err := names.ParseModelTag(arg.ModelTag)
+	if err != nil {
+		return state.APIToken{}, "", errors.Trace(err)
+	}
+	owner := api.apiUser
+	if arg.UserTag != "" {
+		if owner, err = names.ParseUserTag(arg.UserTag); err != nil {
+			return state.APIToken{}, "", errors.Trace(err)
+		}
+	}
+	if err := api.checkCanAdmin(modelTag); err != nil {
+		return state.APIToken{}, "", err
+	}
+	// The owner may have access to the model through their groups.
+	if ok, err := api.authorizer.UserHasPermission(owner, permission.ReadAccess, modelTag); err != nil {
+		return state.APIToken{}, "", errors.Trace(err)
+	} else if !ok {
+		return state.APIToken{}, "", errors.Errorf("user %q does not have access to the model", owner.Id())
+	}
+	token, secret, err := api.backend.AddAPIToken(state.AddAPITokenArgs{
+		Owner:     owner,
+		Model:     modelTag,
+		Access:    permission.Access(arg.Access),
+		CreatedBy: api.apiUser,
+		Expires:   arg.Expires,
+	})
+	return token, secret, errors.Trace(err)
+}
+
+// ListAPITokens returns the API tokens the caller may see: all of them
+// for controller superusers, otherwise those for models the caller
+// administers and those the caller owns.
+func (api *APITokensAPI) ListAPITokens() (params.APITokens, error) {
+	isSuperuser, err := api.isSuperuser()
+	if err != nil {
+		return params.APITokens{}, errors.Trace(err)
+	}
+	tokens, err := api.backend.AllAPITokens()
+	if err != nil {
+		return params.APITokens{}, errors.Trace(err)
+	}
+	result := params.APITokens{Tokens: []params.APIToken{}}
+	for _, token := range tokens {
+		if !isSuperuser {
+			if canSee, err := api.canManage(token); err != nil {
+				return params.APITokens{}, errors.Trace(err)
+			} else if !canSee {
+				continue
+			}
+		}
+		result.Tokens = append(result.Tokens, apiTokenParams(token))
+	}
+	return result, nil
+}
+
+// canManage reports whether the caller owns the token or administers
+// its model.
+func (api *APITokensAPI) canManage(token state.APIToken) (bool, error) {
+	if token.Owner.Id() == api.apiUser.Id() {
+		return true, nil
+	}
+	return api.authorizer.HasPermission(permission.AdminAccess, token.Model)
+}
+
+// RevokeAPITokens revokes API tokens. Tokens may be revoked by their
+// owners, by admins of their models and by controller superusers.
+func (api *APITokensAPI) RevokeAPITokens(args params.APITokenIDs) (params.ErrorResults, error) {
+	isSuperuser, err := api.isSuperuser()
+	if err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.IDs)),
+	}
+	for i, id := range args.IDs {
+		results.Results[i].Error = common.ServerError(api.revokeAPIToken(id, isSuperuser))
+	}
+	return results, nil
+}
+
+func (api *APITokensAPI) revokeAPIToken(id string, isSuperuser bool) error {
+	token, err := api.backend.APIToken(id)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !isSuperuser {
+		if canManage, err := api.canManage(token); err != nil {
+			return errors.Trace(err)
+		} else if !canManage {
+			return common.ErrPerm
+		}
+	}
+	return errors.Trace(api.backend.RemoveAPIToken(id))
+}
+
+func apiTokenParams(token state.APIToken) params.APIToken {
+	return params.APIToken{
+		ID:           token.ID,
+		OwnerTag:     token.Owner.String(),
+		ModelTag:     token.Model.String(),
+		Access:       string(token.Access),
+		CreatedByTag: token.CreatedBy.String(),
+		Created:      token.Created,
+		Expires:      token.Expires,
+	}
+}
@@ -0,0 +1,238 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apitokens_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+	jtesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facades/client/apitokens"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/core/permission"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type apiTokensSuite struct {
+	backend  *mockBackend
+	modelTag names.ModelTag
+	expires  time.Time
+}
+
+var _ = gc.Suite(&apiTokensSuite{})
+
+func (s *apiTokensSuite) SetUpTest(c *gc.C) {
+	s.modelTag = names.NewModelTag(coretesting.ModelTag.Id())
+	s.expires = time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	s.backend = &mockBackend{
+		tokens: []state.APIToken{{
+			ID:        "0a1b",
+			Owner:     names.NewUserTag("bot"),
+			Model:     s.modelTag,
+			Access:    permission.ReadAccess,
+			CreatedBy: names.NewUserTag("admin"),
+			Expires:   s.expires,
+		}, {
+			ID:        "2c3d",
+			Owner:     names.NewUserTag("other"),
+			Model:     names.NewModelTag("c0ffee00-0bad-400d-8000-4b1d0d06f00d"),
+			Access:    permission.WriteAccess,
+			CreatedBy: names.NewUserTag("admin"),
+			Expires:   s.expires,
+		}},
+	}
+}
+
+func (s *apiTokensSuite) newAPI(c *gc.C, user string) *apitokens.APITokensAPI {
+	api, err := apitokens.NewAPITokensAPI(s.backend, apiservertesting.FakeAuthorizer{
+		Tag: names.NewUserTag(user),
+		// Only bot, and the caller, have access to the model.
+		AdminTag: names.NewUserTag("bot"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	return api
+}
+
+func (s *apiTokensSuite) TestNewAPITokensAPINotClient(c *gc.C) {
+	_, err := apitokens.NewAPITokensAPI(s.backend, apiservertesting.FakeAuthorizer{
+		Tag: names.NewMachineTag("0"),
+	})
+	c.Assert(err, gc.Equals, common.ErrPerm)
+}
+
+func (s *apiTokensSuite) TestAddAPITokens(c *gc.C) {
+	admin := "admin" + s.modelTag.String()
+	results, err := s.newAPI(c, admin).AddAPITokens(params.AddAPITokens{
+		Tokens: []params.AddAPIToken{{
+			ModelTag: s.modelTag.String(),
+			UserTag:  "user-bot",
+			Access:   "read",
+			Expires:  s.expires,
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, jc.DeepEquals, []params.AddAPITokenResult{{
+		Token: params.APIToken{
+			ID:           "4e5f",
+			OwnerTag:     "user-bot",
+			ModelTag:     s.modelTag.String(),
+			Access:       "read",
+			CreatedByTag: names.NewUserTag(admin).String(),
+			Expires:      s.expires,
+		},
+		Secret: "jujutoken-4e5f.secret",
+	}})
+	s.backend.CheckCalls(c, []jtesting.StubCall{
+		{"AddAPIToken", []interface{}{state.AddAPITokenArgs{
+			Owner:     names.NewUserTag("bot"),
+			Model:     s.modelTag,
+			Access:    permission.ReadAccess,
+			CreatedBy: names.NewUserTag(admin),
+			Expires:   s.expires,
+		}}},
+	})
+}
+
+func (s *apiTokensSuite) TestAddAPITokensDefaultsToCaller(c *gc.C) {
+	results, err := s.newAPI(c, "superuser").AddAPITokens(params.AddAPITokens{
+		Tokens: []params.AddAPIToken{{
+			ModelTag: s.modelTag.String(),
+			Access:   "write",
+			Expires:  s.expires,
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[0].Token.OwnerTag, gc.Equals, "user-superuser")
+}
+
+func (s *apiTokensSuite) TestAddAPITokensNoModelAccess(c *gc.C) {
+	results, err := s.newAPI(c, "superuser").AddAPITokens(params.AddAPITokens{
+		Tokens: []params.AddAPIToken{{
+			ModelTag: s.modelTag.String(),
+			UserTag:  "user-mary",
+			Access:   "read",
+			Expires:  s.expires,
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results[0].Error, gc.ErrorMatches, `user "mary" does not have access to the model`)
+	s.backend.CheckNoCalls(c)
+}
+
+func (s *apiTokensSuite) TestAddAPITokensNotAdmin(c *gc.C) {
+	results, err := s.newAPI(c, "write"+s.modelTag.String()).AddAPITokens(params.AddAPITokens{
+		Tokens: []params.AddAPIToken{{
+			ModelTag: s.modelTag.String(),
+			Access:   "read",
+			Expires:  s.expires,
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results[0].Error, gc.ErrorMatches, "permission denied")
+	s.backend.CheckNoCalls(c)
+}
+
+func (s *apiTokensSuite) TestListAPITokens(c *gc.C) {
+	result, err := s.newAPI(c, "superuser").ListAPITokens()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Tokens, gc.HasLen, 2)
+
+	result, err = s.newAPI(c, "admin"+s.modelTag.String()).ListAPITokens()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Tokens, jc.DeepEquals, []params.APIToken{{
+		ID:           "0a1b",
+		OwnerTag:     "user-bot",
+		ModelTag:     s.modelTag.String(),
+		Access:       "read",
+		CreatedByTag: "user-admin",
+		Expires:      s.expires,
+	}})
+
+	result, err = s.newAPI(c, "other").ListAPITokens()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Tokens, gc.HasLen, 1)
+	c.Assert(result.Tokens[0].ID, gc.Equals, "2c3d")
+
+	result, err = s.newAPI(c, "bob").ListAPITokens()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Tokens, gc.HasLen, 0)
+}
+
+func (s *apiTokensSuite) TestRevokeAPITokens(c *gc.C) {
+	results, err := s.newAPI(c, "admin"+s.modelTag.String()).RevokeAPITokens(params.APITokenIDs{
+		IDs: []string{"0a1b", "2c3d"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, "permission denied")
+	s.backend.CheckCalls(c, []jtesting.StubCall{
+		{"APIToken", []interface{}{"0a1b"}},
+		{"RemoveAPIToken", []interface{}{"0a1b"}},
+		{"APIToken", []interface{}{"2c3d"}},
+	})
+}
+
+func (s *apiTokensSuite) TestRevokeAPITokensOwner(c *gc.C) {
+	results, err := s.newAPI(c, "other").RevokeAPITokens(params.APITokenIDs{
+		IDs: []string{"2c3d", "missing"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, `API token "missing" not found`)
+}
+
+type mockBackend struct {
+	jtesting.Stub
+	tokens []state.APIToken
+}
+
+func (b *mockBackend) ControllerTag() names.ControllerTag {
+	return coretesting.ControllerTag
+}
+
+func (b *mockBackend) AddAPIToken(args state.AddAPITokenArgs) (state.APIToken, string, error) {
+	b.MethodCall(b, "AddAPIToken", args)
+	if err := b.NextErr(); err != nil {
+		return state.APIToken{}, "", err
+	}
+	return state.APIToken{
+		ID:        "4e5f",
+		Owner:     args.Owner,
+		Model:     args.Model,
+		Access:    args.Access,
+		CreatedBy: args.CreatedBy,
+		Expires:   args.Expires,
+	}, "jujutoken-4e5f.secret", nil
+}
+
+func (b *mockBackend) APIToken(id string) (state.APIToken, error) {
+	b.MethodCall(b, "APIToken", id)
+	if err := b.NextErr(); err != nil {
+		return state.APIToken{}, err
+	}
+	for _, token := range b.tokens {
+		if token.ID == id {
+			return token, nil
+		}
+	}
+	return state.APIToken{}, errors.NotFoundf("API token %q", id)
+}
+
+func (b *mockBackend) AllAPITokens() ([]state.APIToken, error) {
+	b.MethodCall(b, "AllAPITokens")
+	return b.tokens, b.NextErr()
+}
+
+func (b *mockBackend) RemoveAPIToken(id string) error {
+	b.MethodCall(b, "RemoveAPIToken", id)
+	return b.NextErr()
+}
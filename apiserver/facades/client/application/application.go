@@ -92,6 +92,21 @@ type APIv10 struct {
 // The Get call also returns the current endpoint bindings while the SetCharm
 // call access a map of operator-defined bindings.
 type APIv11 struct {
+	*APIv12
+}
+
+// APIv12 provides the Application API facade for version 12.
+// It adds AdoptWorkloads for bringing existing k8s workloads under
+// the management of a Juju application.
+type APIv12 struct {
+	*APIv13
+}
+
+// APIv13 provides the Application API facade for version 13.
+// SetCharm, GetCharmURL and SetConstraints honour the branch (generation)
+// they are given, so that charm upgrades and constraints can be tracked
+// in a branch before being committed.
+type APIv13 struct {
 	*APIBase
 }
 
@@ -190,13 +205,29 @@ func NewFacadeV10(ctx facade.Context) (*APIv10, error) {
 }
 
 func NewFacadeV11(ctx facade.Context) (*APIv11, error) {
-	api, err := newFacadeBase(ctx)
+	api, err := NewFacadeV12(ctx)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
 	return &APIv11{api}, nil
 }
 
+func NewFacadeV12(ctx facade.Context) (*APIv12, error) {
+	api, err := NewFacadeV13(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &APIv12{api}, nil
+}
+
+func NewFacadeV13(ctx facade.Context) (*APIv13, error) {
+	api, err := newFacadeBase(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &APIv13{api}, nil
+}
+
 type caasBrokerInterface interface {
 	ValidateStorageClass(config map[string]interface{}) error
 	Version() (*version.Number, error)
@@ -822,6 +853,7 @@ func parseSettingsCompatible(charmConfig *charm.Config, settings map[string]stri
 type setCharmParams struct {
 	AppName               string
 	Application           Application
+	Generation            string
 	Channel               csparams.Channel
 	ConfigSettingsStrings map[string]string
 	ConfigSettingsYAML    string
@@ -993,11 +1025,19 @@ func (api *APIBase) SetCharm(args params.ApplicationSetCharm) error {
 	if err != nil {
 		return errors.Trace(err)
 	}
+	if args.Generation != "" && args.Generation != model.GenerationMaster {
+		// Only the charm and its resources are tracked by a branch.
+		if args.ConfigSettingsYAML != "" || len(args.ConfigSettings) > 0 ||
+			len(args.StorageConstraints) > 0 || len(args.EndpointBindings) > 0 {
+			return errors.NotSupportedf("changing config, storage or bindings when upgrading a charm in a branch")
+		}
+	}
 	channel := csparams.Channel(args.Channel)
 	return api.setCharmWithAgentValidation(
 		setCharmParams{
 			AppName:               args.ApplicationName,
 			Application:           oneApplication,
+			Generation:            args.Generation,
 			Channel:               channel,
 			ConfigSettingsStrings: args.ConfigSettings,
 			ConfigSettingsYAML:    args.ConfigSettingsYAML,
@@ -1014,6 +1054,13 @@ func (api *APIBase) SetCharm(args params.ApplicationSetCharm) error {
 	)
 }
 
+// SetCharm for versions prior to 13 ignores the branch, and always
+// upgrades the charm of the master generation.
+func (api *APIv12) SetCharm(args params.ApplicationSetCharm) error {
+	args.Generation = ""
+	return api.APIv13.SetCharm(args)
+}
+
 var (
 	deploymentInfoUpgradeMessage = `
 Juju on k8s does not support updating deployment info for services.
@@ -1071,7 +1118,7 @@ func (api *APIBase) setCharmWithAgentValidation(
 		if unsupportedReason != "" {
 			return errors.NotSupportedf(unsupportedReason)
 		}
-		return api.applicationSetCharm(params, newCharm)
+		return api.setCharmForGeneration(params, curl, newCharm)
 	}
 
 	// Check if the controller agent tools version is greater than the
@@ -1098,6 +1145,15 @@ func (api *APIBase) setCharmWithAgentValidation(
 		}
 	}
 
+	return api.setCharmForGeneration(params, curl, newCharm)
+}
+
+// setCharmForGeneration sets the charm for the application, or records
+// the upgrade against a branch if one other than master is given.
+func (api *APIBase) setCharmForGeneration(params setCharmParams, curl *charm.URL, newCharm Charm) error {
+	if params.Generation != "" && params.Generation != model.GenerationMaster {
+		return api.branchSetCharm(params, curl)
+	}
 	return api.applicationSetCharm(params, newCharm)
 }
 
@@ -1145,6 +1201,21 @@ func (api *APIBase) applicationSetCharm(
 	return params.Application.SetCharm(cfg)
 }
 
+// branchSetCharm records the charm upgrade against the given branch.
+// Units tracking the branch run the new charm, and the rest of the
+// application is upgraded when the branch is committed.
+func (api *APIBase) branchSetCharm(params setCharmParams, curl *charm.URL) error {
+	if err := api.addAppToBranch(params.Generation, params.AppName); err != nil {
+		return errors.Trace(err)
+	}
+	gen, err := api.backend.Branch(params.Generation)
+	if err != nil {
+		return errors.Annotate(err, "retrieving next generation")
+	}
+	err = gen.UpdateCharmURL(params.AppName, curl, params.ResourceIDs)
+	return errors.Annotatef(err, "upgrading %q in branch %q", params.AppName, params.Generation)
+}
+
 // charmConfigFromGetYaml will parse a yaml produced by juju get and generate
 // charm.Settings from it that can then be sent to the application.
 func charmConfigFromGetYaml(yamlContents map[string]interface{}) (charm.Settings, error) {
@@ -1214,10 +1285,29 @@ func (api *APIBase) GetCharmURL(args params.ApplicationGet) (params.StringResult
 	if err != nil {
 		return params.StringResult{}, errors.Trace(err)
 	}
+	if args.BranchName != "" && args.BranchName != model.GenerationMaster {
+		gen, err := api.backend.Branch(args.BranchName)
+		if err != nil {
+			return params.StringResult{}, errors.Trace(err)
+		}
+		charmURL, err := gen.CharmURL(args.ApplicationName)
+		if err == nil {
+			return params.StringResult{Result: charmURL.String()}, nil
+		} else if !errors.IsNotFound(err) {
+			return params.StringResult{}, errors.Trace(err)
+		}
+	}
 	charmURL, _ := oneApplication.CharmURL()
 	return params.StringResult{Result: charmURL.String()}, nil
 }
 
+// GetCharmURL for versions prior to 13 ignores the branch, and always
+// returns the charm URL of the master generation.
+func (api *APIv12) GetCharmURL(args params.ApplicationGet) (params.StringResult, error) {
+	args.BranchName = ""
+	return api.APIv13.GetCharmURL(args)
+}
+
 // Set implements the server side of Application.Set.
 // It does not unset values that are set to an empty string.
 // Unset should be used for that.
@@ -1787,6 +1877,67 @@ func (api *APIBase) ScaleApplications(args params.ScaleApplicationsParams) (para
 	return params.ScaleApplicationResults{results}, nil
 }
 
+// AdoptWorkloads isn't on the v11 API.
+func (u *APIv11) AdoptWorkloads(_, _ struct{}) {}
+
+// AdoptWorkloads brings existing workloads, which were not created by Juju,
+// under the management of the specified applications so that their pods
+// are tracked as units.
+func (api *APIBase) AdoptWorkloads(args params.AdoptWorkloadsArgs) (params.ErrorResults, error) {
+	if api.modelType != state.ModelTypeCAAS {
+		return params.ErrorResults{}, errors.NotSupportedf("adopting workloads on a non-container model")
+	}
+	if err := api.checkCanWrite(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	if err := api.check.ChangeAllowed(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	adopter, ok := api.caasBroker.(caas.WorkloadAdopter)
+	if !ok {
+		return params.ErrorResults{}, errors.NotSupportedf("adopting workloads on this cloud")
+	}
+	results := make([]params.ErrorResult, len(args.Workloads))
+	for i, arg := range args.Workloads {
+		if err := api.adoptWorkload(adopter, arg); err != nil {
+			results[i].Error = common.ServerError(err)
+		}
+	}
+	return params.ErrorResults{results}, nil
+}
+
+func (api *APIBase) adoptWorkload(adopter caas.WorkloadAdopter, arg params.AdoptWorkloadArg) error {
+	appTag, err := names.ParseApplicationTag(arg.ApplicationTag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	name := appTag.Id()
+	app, err := api.backend.Application(name)
+	if errors.IsNotFound(err) {
+		return errors.Errorf("application %q does not exist", name)
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	ch, _, err := app.Charm()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if ch.Meta().Deployment != nil && ch.Meta().Deployment.DeploymentMode == charm.ModeOperator {
+		return errors.NotSupportedf("adopt a workload for an %q application", charm.ModeOperator)
+	}
+	deploymentType := caas.DeploymentType(arg.DeploymentType)
+	if deploymentType == "" {
+		return errors.NotValidf("empty deployment type")
+	}
+	if err := deploymentType.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+	if arg.WorkloadName == "" {
+		return errors.NotValidf("empty workload name")
+	}
+	return errors.Trace(adopter.AdoptWorkload(name, deploymentType, arg.WorkloadName))
+}
+
 // GetConstraints returns the constraints for a given application.
 func (api *APIBase) GetConstraints(args params.Entities) (params.ApplicationGetConstraintsResults, error) {
 	if err := api.checkCanRead(); err != nil {
@@ -1832,9 +1983,26 @@ func (api *APIBase) SetConstraints(args params.SetConstraints) error {
 	if err != nil {
 		return err
 	}
+	if args.Generation != "" && args.Generation != model.GenerationMaster {
+		if err := api.addAppToBranch(args.Generation, args.ApplicationName); err != nil {
+			return errors.Trace(err)
+		}
+		gen, err := api.backend.Branch(args.Generation)
+		if err != nil {
+			return errors.Annotate(err, "retrieving next generation")
+		}
+		return errors.Trace(gen.UpdateConstraints(args.ApplicationName, args.Constraints))
+	}
 	return app.SetConstraints(args.Constraints)
 }
 
+// SetConstraints for versions prior to 13 always sets the constraints
+// of the master generation.
+func (api *APIv12) SetConstraints(args params.SetConstraints) error {
+	args.Generation = ""
+	return api.APIv13.SetConstraints(args)
+}
+
 // AddRelation adds a relation between the specified endpoints and returns the relation info.
 func (api *APIBase) AddRelation(args params.AddRelation) (_ params.AddRelationResults, err error) {
 	var rel Relation
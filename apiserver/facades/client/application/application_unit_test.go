@@ -56,7 +56,7 @@ type ApplicationSuite struct {
 	env          environs.Environ
 	blockChecker mockBlockChecker
 	authorizer   apiservertesting.FakeAuthorizer
-	api          *application.APIv11
+	api          *application.APIv13
 	deployParams map[string]application.DeployApplicationParams
 }
 
@@ -86,7 +86,7 @@ func (s *ApplicationSuite) setAPIUser(c *gc.C, user names.UserTag) {
 		s.caasBroker,
 	)
 	c.Assert(err, jc.ErrorIsNil)
-	s.api = &application.APIv11{api}
+	s.api = &application.APIv13{api}
 }
 
 func (s *ApplicationSuite) SetUpTest(c *gc.C) {
@@ -314,6 +314,87 @@ func (s *ApplicationSuite) TestSetCharmStorageConstraints(c *gc.C) {
 	})
 }
 
+func (s *ApplicationSuite) TestSetCharmBranch(c *gc.C) {
+	err := s.api.SetCharm(params.ApplicationSetCharm{
+		ApplicationName: "postgresql",
+		CharmURL:        "cs:postgresql",
+		ResourceIDs:     map[string]string{"data": "data-id"},
+		Generation:      "new-branch",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.backend.CheckCallNames(c, "Application", "Charm")
+	app := s.backend.applications["postgresql"]
+	app.CheckCallNames(c, "Charm", "AgentTools")
+	s.backend.generation.CheckCallNames(c, "AssignApplication", "UpdateCharmURL")
+	s.backend.generation.CheckCall(c, 1, "UpdateCharmURL",
+		"postgresql", charm.MustParseURL("cs:postgresql"), map[string]string{"data": "data-id"})
+}
+
+func (s *ApplicationSuite) TestSetCharmBranchConfigNotSupported(c *gc.C) {
+	err := s.api.SetCharm(params.ApplicationSetCharm{
+		ApplicationName: "postgresql",
+		CharmURL:        "cs:postgresql",
+		ConfigSettings:  map[string]string{"stringOption": "value"},
+		Generation:      "new-branch",
+	})
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	s.backend.CheckCallNames(c, "Application")
+}
+
+func (s *ApplicationSuite) TestSetCharmBranchV12(c *gc.C) {
+	api := &application.APIv12{s.api}
+	err := api.SetCharm(params.ApplicationSetCharm{
+		ApplicationName: "postgresql",
+		CharmURL:        "cs:postgresql",
+		Generation:      "new-branch",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.backend.CheckCallNames(c, "Application", "Charm")
+	app := s.backend.applications["postgresql"]
+	app.CheckCall(c, 2, "SetCharm", state.SetCharmConfig{
+		Charm: &state.Charm{},
+	})
+	c.Assert(s.backend.generation, gc.IsNil)
+}
+
+func (s *ApplicationSuite) TestGetCharmURLBranch(c *gc.C) {
+	s.backend.generation = &mockGeneration{
+		charmURLs: map[string]*charm.URL{"postgresql": charm.MustParseURL("cs:postgresql-42")},
+	}
+	result, err := s.api.GetCharmURL(params.ApplicationGet{
+		ApplicationName: "postgresql",
+		BranchName:      "new-branch",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Result, gc.Equals, "cs:postgresql-42")
+	s.backend.generation.CheckCall(c, 0, "CharmURL", "postgresql")
+}
+
+func (s *ApplicationSuite) TestSetConstraintsBranch(c *gc.C) {
+	cons := constraints.MustParse("mem=8G")
+	err := s.api.SetConstraints(params.SetConstraints{
+		ApplicationName: "postgresql",
+		Constraints:     cons,
+		Generation:      "new-branch",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.backend.CheckCallNames(c, "Application")
+	s.backend.applications["postgresql"].CheckNoCalls(c)
+	s.backend.generation.CheckCall(c, 0, "AssignApplication", "postgresql")
+	s.backend.generation.CheckCall(c, 1, "UpdateConstraints", "postgresql", cons)
+}
+
+func (s *ApplicationSuite) TestSetConstraintsMaster(c *gc.C) {
+	cons := constraints.MustParse("mem=8G")
+	err := s.api.SetConstraints(params.SetConstraints{
+		ApplicationName: "postgresql",
+		Constraints:     cons,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.backend.CheckCallNames(c, "Application")
+	s.backend.applications["postgresql"].CheckCall(c, 0, "SetConstraints", cons)
+}
+
 func (s *ApplicationSuite) TestSetCAASCharmInvalid(c *gc.C) {
 	s.model.modelType = state.ModelTypeCAAS
 	s.setAPIUser(c, names.NewUserTag("admin"))
@@ -1139,6 +1220,77 @@ func (s *ApplicationSuite) TestScaleApplicationsIAASModel(c *gc.C) {
 	app.CheckNoCalls(c)
 }
 
+func (s *ApplicationSuite) TestAdoptWorkloadsCAASModel(c *gc.C) {
+	application.SetModelType(s.api, state.ModelTypeCAAS)
+	results, err := s.api.AdoptWorkloads(params.AdoptWorkloadsArgs{
+		Workloads: []params.AdoptWorkloadArg{{
+			ApplicationTag: "application-postgresql",
+			DeploymentType: "stateful",
+			WorkloadName:   "pg",
+		}, {
+			ApplicationTag: "application-postgresql",
+			DeploymentType: "daemon-ish",
+			WorkloadName:   "pg",
+		}, {
+			ApplicationTag: "application-foo",
+			DeploymentType: "stateless",
+			WorkloadName:   "foo",
+		}}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 3)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, `deployment type "daemon-ish" not supported`)
+	c.Assert(results.Results[2].Error, gc.ErrorMatches, `application "foo" does not exist`)
+	s.caasBroker.CheckCalls(c, []testing.StubCall{
+		{"AdoptWorkload", []interface{}{"postgresql", caas.DeploymentStateful, "pg"}},
+	})
+}
+
+func (s *ApplicationSuite) TestAdoptWorkloadsNotAllowedForOperator(c *gc.C) {
+	application.SetModelType(s.api, state.ModelTypeCAAS)
+	s.backend.applications["postgresql"].charm = &mockCharm{
+		meta: &charm.Meta{
+			Deployment: &charm.Deployment{
+				DeploymentMode: charm.ModeOperator,
+			},
+		},
+	}
+	results, err := s.api.AdoptWorkloads(params.AdoptWorkloadsArgs{
+		Workloads: []params.AdoptWorkloadArg{{
+			ApplicationTag: "application-postgresql",
+			DeploymentType: "stateful",
+			WorkloadName:   "pg",
+		}}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.ErrorMatches, `adopt a workload for an "operator" application not supported`)
+	s.caasBroker.CheckNoCalls(c)
+}
+
+func (s *ApplicationSuite) TestAdoptWorkloadsBlocked(c *gc.C) {
+	application.SetModelType(s.api, state.ModelTypeCAAS)
+	s.blockChecker.SetErrors(common.ServerError(common.OperationBlockedError("test block")))
+	_, err := s.api.AdoptWorkloads(params.AdoptWorkloadsArgs{
+		Workloads: []params.AdoptWorkloadArg{{
+			ApplicationTag: "application-postgresql",
+			DeploymentType: "stateful",
+			WorkloadName:   "pg",
+		}}})
+	c.Assert(err, gc.ErrorMatches, "test block")
+	c.Assert(err, jc.Satisfies, params.IsCodeOperationBlocked)
+}
+
+func (s *ApplicationSuite) TestAdoptWorkloadsIAASModel(c *gc.C) {
+	_, err := s.api.AdoptWorkloads(params.AdoptWorkloadsArgs{
+		Workloads: []params.AdoptWorkloadArg{{
+			ApplicationTag: "application-postgresql",
+			DeploymentType: "stateful",
+			WorkloadName:   "pg",
+		}}})
+	c.Assert(err, gc.ErrorMatches, "adopting workloads on a non-container model not supported")
+	s.caasBroker.CheckNoCalls(c)
+}
+
 func (s *ApplicationSuite) TestAddUnitsAttachStorage(c *gc.C) {
 	_, err := s.api.AddUnits(params.AddApplicationUnits{
 		ApplicationName: "postgresql",
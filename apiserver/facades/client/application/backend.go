@@ -180,8 +180,14 @@ type Resources interface {
 	RemovePendingAppResources(string, map[string]string) error
 }
 
+// Generation defines a subset of the functionality provided by the
+// state.Generation type, as required by the application facade. See
+// the state.Generation type for details on the methods.
 type Generation interface {
 	AssignApplication(string) error
+	CharmURL(string) (*charm.URL, error)
+	UpdateCharmURL(string, *charm.URL, map[string]string) error
+	UpdateConstraints(string, constraints.Value) error
 }
 
 type stateShim struct {
@@ -22,6 +22,7 @@ import (
 	"gopkg.in/macaroon.v2"
 
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/charmrepository"
 	"github.com/juju/juju/charmstore"
 	"github.com/juju/juju/controller"
 	"github.com/juju/juju/core/lxdprofile"
@@ -191,9 +192,14 @@ func AddCharmWithAuthorization(st State, args params.AddCharmWithAuthorization,
 		if err != nil {
 			return nil, err
 		}
+		repositoryURL, err := charmRepositoryURL(st)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
 
 		return openCSRepo(OpenCSRepoParams{
 			CSURL:              controllerCfg.CharmStoreURL(),
+			RepositoryURL:      repositoryURL,
 			Channel:            args.Channel,
 			CharmStoreMacaroon: args.CharmStoreMacaroon,
 		})
@@ -203,12 +209,22 @@ func AddCharmWithAuthorization(st State, args params.AddCharmWithAuthorization,
 type OpenCSRepoFunc func(args OpenCSRepoParams) (charmrepo.Interface, error)
 
 type OpenCSRepoParams struct {
-	CSURL              string
+	CSURL string
+	// RepositoryURL, if set, holds the location of the model's charm
+	// repository, which is used in place of the charm store.
+	RepositoryURL      string
 	Channel            string
 	CharmStoreMacaroon *macaroon.Macaroon
 }
 
 var OpenCSRepo = func(args OpenCSRepoParams) (charmrepo.Interface, error) {
+	if args.RepositoryURL != "" {
+		repo, err := charmrepository.Open(args.RepositoryURL)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return repo, nil
+	}
 	csClient, err := openCSClient(args)
 	if err != nil {
 		return nil, err
@@ -217,6 +233,21 @@ var OpenCSRepo = func(args OpenCSRepoParams) (charmrepo.Interface, error) {
 	return repo, nil
 }
 
+// charmRepositoryURL returns the location of the model's charm
+// repository, or "" if charms are fetched from the charm store.
+func charmRepositoryURL(st ModelState) (string, error) {
+	model, err := st.Model()
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	cfg, err := model.ModelConfig()
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	repositoryURL, _ := cfg.CharmRepositoryURL()
+	return repositoryURL, nil
+}
+
 func openCSClient(args OpenCSRepoParams) (*csclient.Client, error) {
 	csURL, err := url.Parse(args.CSURL)
 	if err != nil {
@@ -353,8 +384,13 @@ func ResolveCharms(st State, args params.ResolveCharms, openCSRepo OpenCSRepoFun
 	if err != nil {
 		return params.ResolveCharmResults{}, errors.Trace(err)
 	}
+	repositoryURL, err := charmRepositoryURL(st)
+	if err != nil {
+		return params.ResolveCharmResults{}, errors.Trace(err)
+	}
 	repo, err := openCSRepo(OpenCSRepoParams{
-		CSURL: controllerCfg.CharmStoreURL(),
+		CSURL:         controllerCfg.CharmStoreURL(),
+		RepositoryURL: repositoryURL,
 	})
 	if err != nil {
 		return params.ResolveCharmResults{}, errors.Trace(err)
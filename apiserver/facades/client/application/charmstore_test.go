@@ -9,6 +9,7 @@ import (
 	"github.com/golang/mock/gomock"
 	"github.com/juju/charm/v7"
 	"github.com/juju/charmrepo/v5"
+	"github.com/juju/errors"
 	gc "gopkg.in/check.v1"
 	"gopkg.in/mgo.v2"
 
@@ -17,9 +18,11 @@ import (
 	"github.com/juju/juju/apiserver/facades/client/application"
 	"github.com/juju/juju/apiserver/facades/client/application/mocks"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/storage"
 	"github.com/juju/juju/testcharms"
+	coretesting "github.com/juju/juju/testing"
 )
 
 type CharmStoreSuite struct {
@@ -271,3 +274,43 @@ func (m charmVersionMatcher) Matches(x interface{}) bool {
 func (m charmVersionMatcher) String() string {
 	return fmt.Sprintf("state.CharmInfo.Version == %q", m.expVersion)
 }
+
+func (s *CharmStoreSuite) TestAddCharmWithAuthorizationUsesModelCharmRepository(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	url := "cs:bionic/wordpress-16"
+	charmURL, err := charm.ParseURL(url)
+	c.Assert(err, gc.IsNil)
+
+	cfg, err := coretesting.ModelConfig(c).Apply(map[string]interface{}{
+		config.CharmRepositoryURLKey: "https://charms.example.com/repo",
+	})
+	c.Assert(err, gc.IsNil)
+
+	mockState := mocks.NewMockState(ctrl)
+	mockStateCharm := mocks.NewMockStateCharm(ctrl)
+	mockModel := mocks.NewMockStateModel(ctrl)
+
+	sExp := mockState.EXPECT()
+	sExp.PrepareStoreCharmUpload(charmURL).Return(mockStateCharm, nil)
+	sExp.ControllerConfig().Return(coretesting.FakeControllerConfig(), nil)
+	sExp.Model().Return(mockModel, nil)
+	mockModel.EXPECT().ModelConfig().Return(cfg, nil)
+	mockStateCharm.EXPECT().IsUploaded().Return(false)
+
+	var openArgs application.OpenCSRepoParams
+	err = application.AddCharmWithAuthorization(mockState, params.AddCharmWithAuthorization{
+		URL:     url,
+		Channel: "edge",
+	}, func(args application.OpenCSRepoParams) (charmrepo.Interface, error) {
+		openArgs = args
+		return nil, errors.New("boom")
+	})
+	c.Assert(err, gc.ErrorMatches, "boom")
+	c.Assert(openArgs, gc.DeepEquals, application.OpenCSRepoParams{
+		CSURL:         "https://api.jujucharms.com/charmstore",
+		RepositoryURL: "https://charms.example.com/repo",
+		Channel:       "edge",
+	})
+}
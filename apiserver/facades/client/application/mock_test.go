@@ -131,6 +131,11 @@ func (m *mockApplication) CharmConfig(branchName string) (charm.Settings, error)
 	return m.charm.config.DefaultSettings(), m.NextErr()
 }
 
+func (m *mockApplication) SetConstraints(cons constraints.Value) error {
+	m.MethodCall(m, "SetConstraints", cons)
+	return m.NextErr()
+}
+
 func (m *mockApplication) Constraints() (constraints.Value, error) {
 	m.MethodCall(m, "Constraints")
 	return m.constraints, nil
@@ -974,8 +979,14 @@ func (m *mockCaasBroker) Version() (*version.Number, error) {
 	return &ver, nil
 }
 
+func (m *mockCaasBroker) AdoptWorkload(appName string, deploymentType caas.DeploymentType, workloadName string) error {
+	m.MethodCall(m, "AdoptWorkload", appName, deploymentType, workloadName)
+	return m.NextErr()
+}
+
 type mockGeneration struct {
 	jtesting.Stub
+	charmURLs map[string]*charm.URL
 }
 
 func (g *mockGeneration) AssignApplication(appName string) error {
@@ -983,6 +994,25 @@ func (g *mockGeneration) AssignApplication(appName string) error {
 	return g.NextErr()
 }
 
+func (g *mockGeneration) CharmURL(appName string) (*charm.URL, error) {
+	g.MethodCall(g, "CharmURL", appName)
+	curl, ok := g.charmURLs[appName]
+	if !ok {
+		return nil, errors.NotFoundf("charm upgrade for application %q", appName)
+	}
+	return curl, nil
+}
+
+func (g *mockGeneration) UpdateCharmURL(appName string, curl *charm.URL, resourceIDs map[string]string) error {
+	g.MethodCall(g, "UpdateCharmURL", appName, curl, resourceIDs)
+	return g.NextErr()
+}
+
+func (g *mockGeneration) UpdateConstraints(appName string, cons constraints.Value) error {
+	g.MethodCall(g, "UpdateConstraints", appName, cons)
+	return g.NextErr()
+}
+
 type mockRepo struct {
 	charmrepo.Interface
 	*jtesting.CallMocker
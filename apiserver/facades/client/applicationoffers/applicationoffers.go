@@ -39,6 +39,12 @@ type OffersAPIV2 struct {
 	*OffersAPI
 }
 
+// OffersAPIV3 implements the cross model interface V3.
+// It adds support for offer limits.
+type OffersAPIV3 struct {
+	*OffersAPIV2
+}
+
 // createAPI returns a new application offers OffersAPI facade.
 func createOffersAPI(
 	getApplicationOffers func(interface{}) jujucrossmodel.ApplicationOffers,
@@ -118,11 +124,34 @@ func NewOffersAPIV2(ctx facade.Context) (*OffersAPIV2, error) {
 	return &OffersAPIV2{OffersAPI: apiV1}, nil
 }
 
+// NewOffersAPIV3 returns a new application offers OffersAPIV3 facade.
+func NewOffersAPIV3(ctx facade.Context) (*OffersAPIV3, error) {
+	apiV2, err := NewOffersAPIV2(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &OffersAPIV3{OffersAPIV2: apiV2}, nil
+}
+
 // Offer makes application endpoints available for consumption at a specified URL.
+// Offer limits are ignored prior to V3 of the API.
 func (api *OffersAPI) Offer(all params.AddApplicationOffers) (params.ErrorResults, error) {
+	return api.offer(all, false)
+}
+
+// Offer makes application endpoints available for consumption at a specified URL,
+// restricting how the offer may be consumed according to any supplied limits.
+func (api *OffersAPIV3) Offer(all params.AddApplicationOffers) (params.ErrorResults, error) {
+	return api.offer(all, true)
+}
+
+func (api *OffersAPI) offer(all params.AddApplicationOffers, withLimits bool) (params.ErrorResults, error) {
 	result := make([]params.ErrorResult, len(all.Offers))
 
 	for i, one := range all.Offers {
+		if !withLimits {
+			one.Limits = nil
+		}
 		modelTag, err := names.ParseModelTag(one.ModelTag)
 		if err != nil {
 			result[i].Error = common.ServerError(err)
@@ -160,6 +189,9 @@ func (api *OffersAPI) makeAddOfferArgsFromParams(backend Backend, addOfferParams
 		Owner:                  api.Authorizer.GetAuthTag().Id(),
 		HasRead:                []string{common.EveryoneTagName},
 	}
+	if addOfferParams.Limits != nil {
+		result.Limits = offerLimitsFromParams(*addOfferParams.Limits)
+	}
 	if result.OfferName == "" {
 		result.OfferName = result.ApplicationName
 	}
@@ -29,7 +29,7 @@ import (
 
 type applicationOffersSuite struct {
 	baseSuite
-	api *applicationoffers.OffersAPIV2
+	api *applicationoffers.OffersAPIV3
 }
 
 var _ = gc.Suite(&applicationOffersSuite{})
@@ -57,7 +57,9 @@ func (s *applicationOffersSuite) SetUpTest(c *gc.C) {
 		s.mockState, s.mockStatePool, s.authorizer, resources, s.authContext,
 	)
 	c.Assert(err, jc.ErrorIsNil)
-	s.api = &applicationoffers.OffersAPIV2{OffersAPI: apiV1}
+	s.api = &applicationoffers.OffersAPIV3{
+		OffersAPIV2: &applicationoffers.OffersAPIV2{OffersAPI: apiV1},
+	}
 }
 
 func (s *applicationOffersSuite) assertOffer(c *gc.C, expectedErr error) {
@@ -123,6 +125,47 @@ func (s *applicationOffersSuite) TestOfferPermission(c *gc.C) {
 	s.assertOffer(c, common.ErrPerm)
 }
 
+func (s *applicationOffersSuite) TestOfferWithLimits(c *gc.C) {
+	s.authorizer.Tag = names.NewUserTag("admin")
+	s.addApplication(c, "test")
+	limits := params.OfferLimits{
+		MaxConnections:           2,
+		AllowedConsumerModels:    []string{"deadbeef-0bad-400d-8000-4b1d0d06f00d"},
+		SettingsChangesPerMinute: 10,
+	}
+	one := params.AddApplicationOffer{
+		ModelTag:        testing.ModelTag.String(),
+		OfferName:       "offer-test",
+		ApplicationName: "test",
+		Endpoints:       map[string]string{"db": "db"},
+		Limits:          &limits,
+	}
+	var added jujucrossmodel.AddApplicationOfferArgs
+	s.applicationOffers.addOffer = func(offer jujucrossmodel.AddApplicationOfferArgs) (*jujucrossmodel.ApplicationOffer, error) {
+		added = offer
+		return &jujucrossmodel.ApplicationOffer{}, nil
+	}
+	ch := &mockCharm{meta: &charm.Meta{Description: "A pretty popular blog engine"}}
+	s.mockState.applications = map[string]crossmodel.Application{
+		"test": &mockApplication{charm: ch, bindings: map[string]string{"db": "myspace"}},
+	}
+
+	errs, err := s.api.Offer(params.AddApplicationOffers{Offers: []params.AddApplicationOffer{one}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(errs.Combine(), jc.ErrorIsNil)
+	c.Assert(added.Limits, jc.DeepEquals, jujucrossmodel.OfferLimits{
+		MaxConnections:           2,
+		AllowedConsumerModels:    []string{"deadbeef-0bad-400d-8000-4b1d0d06f00d"},
+		SettingsChangesPerMinute: 10,
+	})
+
+	// Prior to V3, limits are ignored.
+	errs, err = s.api.OffersAPIV2.Offer(params.AddApplicationOffers{Offers: []params.AddApplicationOffer{one}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(errs.Combine(), jc.ErrorIsNil)
+	c.Assert(added.Limits.IsZero(), jc.IsTrue)
+}
+
 func (s *applicationOffersSuite) TestOfferSomeFail(c *gc.C) {
 	s.authorizer.Tag = names.NewUserTag("admin")
 	s.addApplication(c, "one")
@@ -425,6 +468,39 @@ func (s *applicationOffersSuite) TestShow(c *gc.C) {
 	s.assertShow(c, "prod.hosted-db2", expected)
 }
 
+func (s *applicationOffersSuite) showOfferLimits(c *gc.C) *params.OfferLimits {
+	s.setupOffers(c, "", false)
+	listOffers := s.applicationOffers.listOffers
+	s.applicationOffers.listOffers = func(filters ...jujucrossmodel.ApplicationOfferFilter) ([]jujucrossmodel.ApplicationOffer, error) {
+		offers, err := listOffers(filters...)
+		for i := range offers {
+			offers[i].Limits = jujucrossmodel.OfferLimits{MaxConnections: 5, SettingsChangesPerMinute: 10}
+		}
+		return offers, err
+	}
+	filter := params.OfferURLs{[]string{"fred/prod.hosted-db2"}, bakery.LatestVersion}
+	found, err := s.api.ApplicationOffers(filter)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(found.Results, gc.HasLen, 1)
+	c.Assert(found.Results[0].Error, gc.IsNil)
+	return found.Results[0].Result.Limits
+}
+
+func (s *applicationOffersSuite) TestShowLimits(c *gc.C) {
+	s.authorizer.Tag = names.NewUserTag("admin")
+	limits := s.showOfferLimits(c)
+	c.Assert(limits, jc.DeepEquals, &params.OfferLimits{MaxConnections: 5, SettingsChangesPerMinute: 10})
+}
+
+func (s *applicationOffersSuite) TestShowLimitsNotAdmin(c *gc.C) {
+	user := names.NewUserTag("someone")
+	s.authorizer.Tag = user
+	s.mockState.users[user.Name()] = &mockUser{user.Name()}
+	s.mockState.CreateOfferAccess(names.NewApplicationOfferTag("hosted-db2"), user, permission.ReadAccess)
+	limits := s.showOfferLimits(c)
+	c.Assert(limits, gc.IsNil)
+}
+
 func (s *applicationOffersSuite) TestShowNoPermission(c *gc.C) {
 	s.mockState.users["someone"] = &mockUser{"someone"}
 	user := names.NewUserTag("someone")
@@ -1155,11 +1231,12 @@ func (s *consumeSuite) TestConsumeDetailsWithPermission(c *gc.C) {
 	})
 	c.Assert(results.Results[0].Macaroon.Id(), jc.DeepEquals, []byte("id"))
 	cav := s.bakery.caveats[string(results.Results[0].Macaroon.Id())]
-	c.Check(cav, gc.HasLen, 4)
+	c.Check(cav, gc.HasLen, 5)
 	c.Check(strings.HasPrefix(cav[0].Condition, "time-before "), jc.IsTrue)
 	c.Check(cav[1].Condition, gc.Equals, "declared source-model-uuid deadbeef-0bad-400d-8000-4b1d0d06f00d")
 	c.Check(cav[2].Condition, gc.Equals, "declared offer-uuid hosted-mysql-uuid")
 	c.Check(cav[3].Condition, gc.Equals, "declared username someone")
+	c.Check(cav[4].Condition, gc.Equals, "declared consumer-model-uuid ")
 }
 
 func (s *consumeSuite) TestConsumeDetailsDefaultEndpoint(c *gc.C) {
@@ -7,6 +7,7 @@ import (
 	"github.com/juju/errors"
 
 	"github.com/juju/juju/apiserver/params"
+	jujucrossmodel "github.com/juju/juju/core/crossmodel"
 	"github.com/juju/juju/core/network"
 	"github.com/juju/juju/environs"
 )
@@ -82,3 +83,26 @@ func spaceInfoFromState(space Space) (*network.SpaceInfo, error) {
 	}
 	return result, nil
 }
+
+// offerLimitsFromParams converts a params.OfferLimits into the
+// equivalent crossmodel.OfferLimits.
+func offerLimitsFromParams(limits params.OfferLimits) jujucrossmodel.OfferLimits {
+	return jujucrossmodel.OfferLimits{
+		MaxConnections:           limits.MaxConnections,
+		AllowedConsumerModels:    limits.AllowedConsumerModels,
+		SettingsChangesPerMinute: limits.SettingsChangesPerMinute,
+	}
+}
+
+// paramsFromOfferLimits converts a crossmodel.OfferLimits into the
+// equivalent params.OfferLimits, or nil if there are no limits.
+func paramsFromOfferLimits(limits jujucrossmodel.OfferLimits) *params.OfferLimits {
+	if limits.IsZero() {
+		return nil
+	}
+	return &params.OfferLimits{
+		MaxConnections:           limits.MaxConnections,
+		AllowedConsumerModels:    limits.AllowedConsumerModels,
+		SettingsChangesPerMinute: limits.SettingsChangesPerMinute,
+	}
+}
@@ -59,12 +59,23 @@ type APIv4 struct {
 	*BundleAPI
 }
 
+// APIv5 provides the Bundle API facade for version 5. It is otherwise
+// identical to V4 with the exception that the V5 adds DiffModels.
+type APIv5 struct {
+	*BundleAPI
+}
+
+// ModelBackendFunc returns the Backend for the model with the given
+// tag, and a function to release it once it is no longer needed.
+type ModelBackendFunc func(names.ModelTag) (Backend, func(), error)
+
 // BundleAPI implements the Bundle interface and is the concrete implementation
 // of the API end point.
 type BundleAPI struct {
-	backend    Backend
-	authorizer facade.Authorizer
-	modelTag   names.ModelTag
+	backend      Backend
+	authorizer   facade.Authorizer
+	modelTag     names.ModelTag
+	modelBackend ModelBackendFunc
 }
 
 // NewFacadeV1 provides the signature required for facade registration
@@ -108,6 +119,24 @@ func NewFacadeV4(ctx facade.Context) (*APIv4, error) {
 	return &APIv4{api}, nil
 }
 
+// NewFacadeV5 provides the signature required for facade registration
+// for version 5.
+func NewFacadeV5(ctx facade.Context) (*APIv5, error) {
+	api, err := newFacade(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	pool := ctx.StatePool()
+	api.modelBackend = func(tag names.ModelTag) (Backend, func(), error) {
+		st, err := pool.Get(tag.Id())
+		if err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+		return NewStateShim(st.State), func() { st.Release() }, nil
+	}
+	return &APIv5{api}, nil
+}
+
 // NewFacade provides the required signature for facade registration.
 func newFacade(ctx facade.Context) (*BundleAPI, error) {
 	authorizer := ctx.Auth()
@@ -150,8 +179,28 @@ func NewBundleAPIv1(
 	return &APIv1{&APIv2{api}}, nil
 }
 
+// NewBundleAPIv5 returns the new Bundle APIv5 facade, using modelBackend
+// to access models other than the one identified by tag.
+func NewBundleAPIv5(
+	st Backend,
+	auth facade.Authorizer,
+	tag names.ModelTag,
+	modelBackend ModelBackendFunc,
+) (*APIv5, error) {
+	api, err := NewBundleAPI(st, auth, tag)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	api.modelBackend = modelBackend
+	return &APIv5{api}, nil
+}
+
 func (b *BundleAPI) checkCanRead() error {
-	canRead, err := b.authorizer.HasPermission(permission.ReadAccess, b.modelTag)
+	return b.checkCanReadModel(b.modelTag)
+}
+
+func (b *BundleAPI) checkCanReadModel(modelTag names.ModelTag) error {
+	canRead, err := b.authorizer.HasPermission(permission.ReadAccess, modelTag)
 	if err != nil {
 		return errors.Trace(err)
 	}
@@ -445,6 +494,85 @@ func bundleOutputFromBundleData(bd *charm.BundleData) *bundleOutput {
 	}
 }
 
+// DiffModels compares the bundles exported from two models and, when a
+// base bundle is supplied, that bundle, and returns their differences.
+// The caller must have read access to both models.
+func (b *BundleAPI) DiffModels(args params.BundleDiffModelsParams) (params.BundleDiff, error) {
+	fail := func(failErr error) (params.BundleDiff, error) {
+		return params.BundleDiff{}, common.ServerError(failErr)
+	}
+
+	var sides []bundleSide
+	if args.BaseBundleDataYAML != "" {
+		data, err := charm.ReadBundleData(strings.NewReader(args.BaseBundleDataYAML))
+		if err != nil {
+			return fail(errors.Annotate(err, "cannot read base bundle YAML"))
+		}
+		sides = append(sides, bundleSide{name: baseSide, data: data})
+	}
+	for _, model := range []struct {
+		side string
+		tag  string
+	}{
+		{side: fromSide, tag: args.FromModelTag},
+		{side: toSide, tag: args.ToModelTag},
+	} {
+		tag, err := names.ParseModelTag(model.tag)
+		if err != nil {
+			return fail(errors.Trace(err))
+		}
+		data, err := b.exportModelBundleData(tag)
+		if err != nil {
+			return fail(errors.Annotatef(err, "exporting %q model", model.side))
+		}
+		sides = append(sides, bundleSide{name: model.side, data: data})
+	}
+	return diffBundles(sides, args.IncludeAnnotations), nil
+}
+
+// exportModelBundleData exports the given model as bundle data. Unlike
+// ExportBundle, a model without applications exports an empty bundle.
+func (b *BundleAPI) exportModelBundleData(modelTag names.ModelTag) (*charm.BundleData, error) {
+	if err := b.checkCanReadModel(modelTag); err != nil {
+		return nil, errors.Trace(err)
+	}
+	api := b
+	if modelTag != b.modelTag {
+		if b.modelBackend == nil {
+			return nil, errors.NotSupportedf("exporting other models")
+		}
+		backend, release, err := b.modelBackend(modelTag)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		defer release()
+		api = &BundleAPI{
+			backend:    backend,
+			authorizer: b.authorizer,
+			modelTag:   modelTag,
+		}
+	}
+
+	model, err := api.backend.ExportPartial(api.backend.GetExportConfig())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(model.Applications()) == 0 {
+		return &charm.BundleData{}, nil
+	}
+	return api.fillBundleData(model)
+}
+
+// DiffModels is not in V4 API or less.
+// Mask the new method from V4 API or less.
+func (u *APIv4) DiffModels() (_, _ struct{}) { return }
+
+// DiffModels is not in V3 API or less.
+func (u *APIv3) DiffModels() (_, _ struct{}) { return }
+
+// DiffModels is not in V2 API or less.
+func (u *APIv2) DiffModels() (_, _ struct{}) { return }
+
 // ExportBundle is not in V1 API.
 // Mask the new method from V1 API.
 func (u *APIv1) ExportBundle() (_, _ struct{}) { return }
@@ -0,0 +1,371 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package bundle
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/juju/charm/v7"
+	"github.com/juju/collections/set"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/constraints"
+)
+
+// Names of the sides of a bundle comparison.
+const (
+	baseSide = "base"
+	fromSide = "from"
+	toSide   = "to"
+)
+
+// bundleSide is one of the bundles being compared.
+type bundleSide struct {
+	name string
+	data *charm.BundleData
+}
+
+// diffBundles compares the given bundles and returns their differences.
+// Attributes of an application or machine are only compared between
+// the sides it is present in.
+func diffBundles(sides []bundleSide, includeAnnotations bool) params.BundleDiff {
+	var diff params.BundleDiff
+
+	appNames := set.NewStrings()
+	machineIds := set.NewStrings()
+	saasNames := set.NewStrings()
+	for _, side := range sides {
+		for name := range side.data.Applications {
+			appNames.Add(name)
+		}
+		for id := range side.data.Machines {
+			machineIds.Add(id)
+		}
+		for name := range side.data.Saas {
+			saasNames.Add(name)
+		}
+	}
+
+	for _, name := range appNames.SortedValues() {
+		var present []bundleSide
+		var apps []*charm.ApplicationSpec
+		var missing []string
+		for _, side := range sides {
+			if app, ok := side.data.Applications[name]; ok {
+				present = append(present, side)
+				apps = append(apps, app)
+			} else {
+				missing = append(missing, side.name)
+			}
+		}
+		appDiff := diffApplications(present, apps, includeAnnotations)
+		appDiff.Missing = missing
+		if !reflect.DeepEqual(appDiff, params.ApplicationBundleDiff{}) {
+			if diff.Applications == nil {
+				diff.Applications = make(map[string]params.ApplicationBundleDiff)
+			}
+			diff.Applications[name] = appDiff
+		}
+	}
+
+	for _, id := range machineIds.SortedValues() {
+		var present []bundleSide
+		var machines []*charm.MachineSpec
+		var missing []string
+		for _, side := range sides {
+			if machine, ok := side.data.Machines[id]; ok {
+				present = append(present, side)
+				if machine == nil {
+					machine = &charm.MachineSpec{}
+				}
+				machines = append(machines, machine)
+			} else {
+				missing = append(missing, side.name)
+			}
+		}
+		machineDiff := params.MachineBundleDiff{
+			Missing: missing,
+			Series: diffValue(present, func(i int) interface{} {
+				return defaultString(machines[i].Series, present[i].data.Series)
+			}),
+			Constraints: diffValue(present, func(i int) interface{} {
+				return normaliseConstraints(machines[i].Constraints)
+			}),
+		}
+		if includeAnnotations {
+			machineDiff.Annotations = diffStringMaps(present, func(i int) map[string]string {
+				return machines[i].Annotations
+			})
+		}
+		if !reflect.DeepEqual(machineDiff, params.MachineBundleDiff{}) {
+			if diff.Machines == nil {
+				diff.Machines = make(map[string]params.MachineBundleDiff)
+			}
+			diff.Machines[id] = machineDiff
+		}
+	}
+
+	for _, name := range saasNames.SortedValues() {
+		var present []bundleSide
+		var urls []string
+		var missing []string
+		for _, side := range sides {
+			if saas, ok := side.data.Saas[name]; ok {
+				present = append(present, side)
+				url := ""
+				if saas != nil {
+					url = saas.URL
+				}
+				urls = append(urls, url)
+			} else {
+				missing = append(missing, side.name)
+			}
+		}
+		saasDiff := params.SaasBundleDiff{
+			Missing: missing,
+			URL:     diffValue(present, func(i int) interface{} { return urls[i] }),
+		}
+		if !reflect.DeepEqual(saasDiff, params.SaasBundleDiff{}) {
+			if diff.Saas == nil {
+				diff.Saas = make(map[string]params.SaasBundleDiff)
+			}
+			diff.Saas[name] = saasDiff
+		}
+	}
+
+	diff.Relations = diffRelations(sides)
+	return diff
+}
+
+func diffApplications(sides []bundleSide, apps []*charm.ApplicationSpec, includeAnnotations bool) params.ApplicationBundleDiff {
+	attr := func(f func(*charm.ApplicationSpec) interface{}) *params.BundleDiffValue {
+		return diffValue(sides, func(i int) interface{} { return f(apps[i]) })
+	}
+	stringMaps := func(f func(*charm.ApplicationSpec) map[string]string) map[string]params.BundleDiffValue {
+		return diffStringMaps(sides, func(i int) map[string]string { return f(apps[i]) })
+	}
+	appDiff := params.ApplicationBundleDiff{
+		Charm:   attr(func(app *charm.ApplicationSpec) interface{} { return app.Charm }),
+		Channel: attr(func(app *charm.ApplicationSpec) interface{} { return app.Channel }),
+		Series: diffValue(sides, func(i int) interface{} {
+			return defaultString(apps[i].Series, sides[i].data.Series)
+		}),
+		NumUnits:  attr(func(app *charm.ApplicationSpec) interface{} { return app.NumUnits }),
+		Scale:     attr(func(app *charm.ApplicationSpec) interface{} { return app.Scale_ }),
+		Placement: attr(func(app *charm.ApplicationSpec) interface{} { return app.To }),
+		Expose:    attr(func(app *charm.ApplicationSpec) interface{} { return app.Expose }),
+		Trust:     attr(func(app *charm.ApplicationSpec) interface{} { return app.RequiresTrust }),
+		Constraints: attr(func(app *charm.ApplicationSpec) interface{} {
+			return normaliseConstraints(app.Constraints)
+		}),
+		Bindings: stringMaps(func(app *charm.ApplicationSpec) map[string]string { return app.EndpointBindings }),
+	}
+	if includeAnnotations {
+		appDiff.Annotations = stringMaps(func(app *charm.ApplicationSpec) map[string]string { return app.Annotations })
+	}
+
+	optionKeys := set.NewStrings()
+	offerNames := set.NewStrings()
+	for _, app := range apps {
+		for key := range app.Options {
+			optionKeys.Add(key)
+		}
+		for name := range app.Offers {
+			offerNames.Add(name)
+		}
+	}
+	for _, key := range optionKeys.SortedValues() {
+		value := diffValue(sides, func(i int) interface{} { return apps[i].Options[key] })
+		if value != nil {
+			if appDiff.Options == nil {
+				appDiff.Options = make(map[string]params.BundleDiffValue)
+			}
+			appDiff.Options[key] = *value
+		}
+	}
+	for _, name := range offerNames.SortedValues() {
+		value := diffValue(sides, func(i int) interface{} {
+			offer := apps[i].Offers[name]
+			if offer == nil {
+				return nil
+			}
+			endpoints := append([]string(nil), offer.Endpoints...)
+			sort.Strings(endpoints)
+			return map[string]interface{}{
+				"endpoints": endpoints,
+				"acl":       offer.ACL,
+			}
+		})
+		if value != nil {
+			if appDiff.Offers == nil {
+				appDiff.Offers = make(map[string]params.BundleDiffValue)
+			}
+			appDiff.Offers[name] = *value
+		}
+	}
+	return appDiff
+}
+
+func diffRelations(sides []bundleSide) []params.RelationBundleDiff {
+	relations := make(map[string][]string)
+	presentIn := make(map[string]set.Strings)
+	for _, side := range sides {
+		for _, relation := range side.data.Relations {
+			endpoints := append([]string(nil), relation...)
+			sort.Strings(endpoints)
+			key := strings.Join(endpoints, " ")
+			relations[key] = endpoints
+			if presentIn[key] == nil {
+				presentIn[key] = set.NewStrings()
+			}
+			presentIn[key].Add(side.name)
+		}
+	}
+	keys := make([]string, 0, len(relations))
+	for key := range relations {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var result []params.RelationBundleDiff
+	for _, key := range keys {
+		var missing []string
+		for _, side := range sides {
+			if !presentIn[key].Contains(side.name) {
+				missing = append(missing, side.name)
+			}
+		}
+		if len(missing) > 0 {
+			result = append(result, params.RelationBundleDiff{
+				Endpoints: relations[key],
+				Missing:   missing,
+			})
+		}
+	}
+	return result
+}
+
+// diffValue returns the value of an attribute on each side, or nil if
+// the values are all equal.
+func diffValue(sides []bundleSide, value func(int) interface{}) *params.BundleDiffValue {
+	values := make([]interface{}, len(sides))
+	equal := true
+	for i := range sides {
+		values[i] = value(i)
+		if i > 0 && !reflect.DeepEqual(normaliseValue(values[i]), normaliseValue(values[0])) {
+			equal = false
+		}
+	}
+	if equal {
+		return nil
+	}
+	var result params.BundleDiffValue
+	for i, side := range sides {
+		switch side.name {
+		case baseSide:
+			result.Base = displayValue(values[i])
+		case fromSide:
+			result.From = displayValue(values[i])
+		case toSide:
+			result.To = displayValue(values[i])
+		}
+	}
+	return &result
+}
+
+func diffStringMaps(sides []bundleSide, value func(int) map[string]string) map[string]params.BundleDiffValue {
+	keys := set.NewStrings()
+	for i := range sides {
+		for key := range value(i) {
+			keys.Add(key)
+		}
+	}
+	var result map[string]params.BundleDiffValue
+	for _, key := range keys.SortedValues() {
+		diff := diffValue(sides, func(i int) interface{} { return value(i)[key] })
+		if diff != nil {
+			if result == nil {
+				result = make(map[string]params.BundleDiffValue)
+			}
+			result[key] = *diff
+		}
+	}
+	return result
+}
+
+// normaliseValue returns the value in a form that can be compared,
+// treating empty values as absent and integers of any type alike.
+func normaliseValue(value interface{}) interface{} {
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Invalid:
+		return nil
+	case reflect.String, reflect.Slice, reflect.Map:
+		if v.Len() == 0 {
+			return nil
+		}
+	case reflect.Bool:
+		if !v.Bool() {
+			return nil
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if v.Int() == 0 {
+			return nil
+		}
+		return v.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if v.Uint() == 0 {
+			return nil
+		}
+		return int64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		f := v.Float()
+		if f == 0 {
+			return nil
+		}
+		if f == float64(int64(f)) {
+			return int64(f)
+		}
+		return f
+	}
+	return value
+}
+
+// displayValue returns the value as reported in a diff. Unlike
+// normaliseValue it keeps false and zero numbers, so that a change
+// such as expose false to true shows both sides.
+func displayValue(value interface{}) interface{} {
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Bool:
+		return v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		if normalised := normaliseValue(value); normalised != nil {
+			return normalised
+		}
+		return int64(0)
+	}
+	return normaliseValue(value)
+}
+
+// normaliseConstraints returns the constraints in canonical form, so
+// that equivalent constraints compare equal.
+func normaliseConstraints(cons string) string {
+	value, err := constraints.Parse(cons)
+	if err != nil {
+		return cons
+	}
+	return value.String()
+}
+
+func defaultString(value, defaultValue string) string {
+	if value == "" {
+		return defaultValue
+	}
+	return value
+}
@@ -0,0 +1,248 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package bundle_test
+
+import (
+	"github.com/juju/description/v2"
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/facades/client/bundle"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type diffSuite struct {
+	coretesting.BaseSuite
+
+	auth    *apiservertesting.FakeAuthorizer
+	fromTag names.ModelTag
+	toTag   names.ModelTag
+	from    *mockState
+	to      *mockState
+
+	released []names.ModelTag
+}
+
+var _ = gc.Suite(&diffSuite{})
+
+func (s *diffSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.auth = &apiservertesting.FakeAuthorizer{
+		Tag: names.NewUserTag("read"),
+	}
+	s.fromTag = coretesting.ModelTag
+	s.toTag = names.NewModelTag("f47ac10b-58cc-4372-a567-0e02b2c3d479")
+	s.from = newMockState()
+	s.to = newMockState()
+	s.released = nil
+}
+
+func (s *diffSuite) makeAPI(c *gc.C) *bundle.APIv5 {
+	api, err := bundle.NewBundleAPIv5(s.from, s.auth, s.fromTag,
+		func(tag names.ModelTag) (bundle.Backend, func(), error) {
+			if tag != s.toTag {
+				return nil, nil, errors.NotFoundf("model %q", tag.Id())
+			}
+			return s.to, func() { s.released = append(s.released, tag) }, nil
+		},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	return api
+}
+
+func newDiffModel(series string) description.Model {
+	return description.NewModel(description.ModelArgs{
+		Owner: names.NewUserTag("magic"),
+		Config: map[string]interface{}{
+			"name":           "awesome",
+			"uuid":           "some-uuid",
+			"default-series": series,
+		},
+		CloudRegion: "some-region",
+	})
+}
+
+func addDiffApplication(model description.Model, name string, units int, config map[string]interface{}, cons string) description.Application {
+	app := model.AddApplication(description.ApplicationArgs{
+		Tag:         names.NewApplicationTag(name),
+		Series:      "bionic",
+		Type:        description.IAAS,
+		CharmURL:    "cs:bionic/" + name + "-1",
+		CharmConfig: config,
+	})
+	if cons != "" {
+		app.SetConstraints(description.ConstraintsArgs{Memory: 4096})
+	}
+	for i := 0; i < units; i++ {
+		app.AddUnit(description.UnitArgs{
+			Tag:     names.NewUnitTag(name + "/" + string(rune('0'+i))),
+			Machine: names.NewMachineTag(string(rune('0' + i))),
+		})
+	}
+	return app
+}
+
+func addDiffRelation(model description.Model, id int, app1, ep1, app2, ep2 string) {
+	rel := model.AddRelation(description.RelationArgs{Id: id})
+	rel.AddEndpoint(description.EndpointArgs{ApplicationName: app1, Name: ep1})
+	rel.AddEndpoint(description.EndpointArgs{ApplicationName: app2, Name: ep2})
+}
+
+func (s *diffSuite) setUpModels() {
+	s.from.model = newDiffModel("bionic")
+	addDiffApplication(s.from.model, "wordpress", 2, map[string]interface{}{"blog-title": "staging"}, "")
+	addDiffApplication(s.from.model, "mysql", 1, nil, "")
+	addDiffRelation(s.from.model, 0, "wordpress", "db", "mysql", "mysql")
+	app := addDiffApplication(s.from.model, "haproxy", 1, nil, "")
+	app.AddOffer(description.ApplicationOfferArgs{
+		OfferName: "lb",
+		Endpoints: map[string]string{"website": "website"},
+	})
+
+	s.to.model = newDiffModel("bionic")
+	addDiffApplication(s.to.model, "wordpress", 3, map[string]interface{}{"blog-title": "production"}, "mem=4G")
+	addDiffApplication(s.to.model, "mysql", 1, nil, "")
+	s.to.model.AddRemoteApplication(description.RemoteApplicationArgs{
+		Tag: names.NewApplicationTag("logging"),
+		URL: "prod/admin.logging",
+	})
+}
+
+func (s *diffSuite) TestDiffModels(c *gc.C) {
+	s.setUpModels()
+
+	result, err := s.makeAPI(c).DiffModels(params.BundleDiffModelsParams{
+		FromModelTag: s.fromTag.String(),
+		ToModelTag:   s.toTag.String(),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.BundleDiff{
+		Applications: map[string]params.ApplicationBundleDiff{
+			"haproxy": {Missing: []string{"to"}},
+			"wordpress": {
+				NumUnits:    &params.BundleDiffValue{From: int64(2), To: int64(3)},
+				Placement:   &params.BundleDiffValue{From: []string{"0", "1"}, To: []string{"0", "1", "2"}},
+				Constraints: &params.BundleDiffValue{To: "mem=4096M"},
+				Options: map[string]params.BundleDiffValue{
+					"blog-title": {From: "staging", To: "production"},
+				},
+			},
+		},
+		Saas: map[string]params.SaasBundleDiff{
+			"logging": {Missing: []string{"from"}},
+		},
+		Relations: []params.RelationBundleDiff{{
+			Endpoints: []string{"mysql:mysql", "wordpress:db"},
+			Missing:   []string{"to"},
+		}},
+	})
+	c.Assert(s.released, jc.DeepEquals, []names.ModelTag{s.toTag})
+}
+
+func (s *diffSuite) TestDiffModelsThreeWay(c *gc.C) {
+	s.setUpModels()
+
+	result, err := s.makeAPI(c).DiffModels(params.BundleDiffModelsParams{
+		FromModelTag: s.fromTag.String(),
+		ToModelTag:   s.toTag.String(),
+		BaseBundleDataYAML: `
+series: bionic
+applications:
+  wordpress:
+    charm: cs:bionic/wordpress-1
+    num_units: 2
+    to: ["0", "1"]
+    options:
+      blog-title: staging
+  mysql:
+    charm: cs:bionic/mysql-1
+    num_units: 1
+    to: ["0"]
+relations:
+- [wordpress:db, mysql:mysql]
+`,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Applications, jc.DeepEquals, map[string]params.ApplicationBundleDiff{
+		"haproxy": {Missing: []string{"base", "to"}},
+		"wordpress": {
+			NumUnits:    &params.BundleDiffValue{Base: int64(2), From: int64(2), To: int64(3)},
+			Placement:   &params.BundleDiffValue{Base: []string{"0", "1"}, From: []string{"0", "1"}, To: []string{"0", "1", "2"}},
+			Constraints: &params.BundleDiffValue{To: "mem=4096M"},
+			Options: map[string]params.BundleDiffValue{
+				"blog-title": {Base: "staging", From: "staging", To: "production"},
+			},
+		},
+	})
+	c.Assert(result.Relations, jc.DeepEquals, []params.RelationBundleDiff{{
+		Endpoints: []string{"mysql:mysql", "wordpress:db"},
+		Missing:   []string{"to"},
+	}})
+}
+
+func (s *diffSuite) TestDiffModelsZeroValues(c *gc.C) {
+	s.from.model = newDiffModel("bionic")
+	addDiffApplication(s.from.model, "wordpress", 0, nil, "")
+	s.to.model = newDiffModel("bionic")
+	s.to.model.AddApplication(description.ApplicationArgs{
+		Tag:      names.NewApplicationTag("wordpress"),
+		Series:   "bionic",
+		Type:     description.IAAS,
+		CharmURL: "cs:bionic/wordpress-1",
+		Exposed:  true,
+	})
+	for i := 0; i < 3; i++ {
+		s.to.model.Applications()[0].AddUnit(description.UnitArgs{
+			Tag:     names.NewUnitTag("wordpress/" + string(rune('0'+i))),
+			Machine: names.NewMachineTag(string(rune('0' + i))),
+		})
+	}
+
+	result, err := s.makeAPI(c).DiffModels(params.BundleDiffModelsParams{
+		FromModelTag: s.fromTag.String(),
+		ToModelTag:   s.toTag.String(),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	wordpress := result.Applications["wordpress"]
+	c.Assert(wordpress.Expose, jc.DeepEquals, &params.BundleDiffValue{From: false, To: true})
+	c.Assert(wordpress.NumUnits, jc.DeepEquals, &params.BundleDiffValue{From: int64(0), To: int64(3)})
+}
+
+func (s *diffSuite) TestDiffModelsEmptyModel(c *gc.C) {
+	s.setUpModels()
+	s.to.model = newDiffModel("bionic")
+
+	result, err := s.makeAPI(c).DiffModels(params.BundleDiffModelsParams{
+		FromModelTag: s.fromTag.String(),
+		ToModelTag:   s.toTag.String(),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Applications, gc.HasLen, 3)
+	c.Assert(result.Applications["mysql"], jc.DeepEquals, params.ApplicationBundleDiff{Missing: []string{"to"}})
+}
+
+func (s *diffSuite) TestDiffModelsPermissionDenied(c *gc.C) {
+	s.setUpModels()
+	s.auth.Tag = names.NewUserTag("read-" + s.fromTag.String())
+
+	_, err := s.makeAPI(c).DiffModels(params.BundleDiffModelsParams{
+		FromModelTag: s.fromTag.String(),
+		ToModelTag:   s.toTag.String(),
+	})
+	c.Assert(err, gc.ErrorMatches, `exporting "to" model: permission denied`)
+	c.Assert(s.released, gc.HasLen, 0)
+}
+
+func (s *diffSuite) TestDiffModelsInvalidBaseBundle(c *gc.C) {
+	_, err := s.makeAPI(c).DiffModels(params.BundleDiffModelsParams{
+		FromModelTag:       s.fromTag.String(),
+		ToModelTag:         s.toTag.String(),
+		BaseBundleDataYAML: ":",
+	})
+	c.Assert(err, gc.ErrorMatches, `cannot read base bundle YAML: .*`)
+}
@@ -0,0 +1,80 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package charmhub
+
+import (
+	"context"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/charmhub"
+)
+
+var logger = loggo.GetLogger("juju.apiserver.charmhub")
+
+// Client describes the methods of the charmhub client used by the facade.
+type Client interface {
+	Info(ctx context.Context, name string) (charmhub.InfoResponse, error)
+	Find(ctx context.Context, query string) ([]charmhub.FindResponse, error)
+}
+
+// CharmHubAPI queries the charmhub-compatible store configured for the
+// model on behalf of clients, so that the controller's proxy settings
+// are honoured.
+type CharmHubAPI struct {
+	auth   facade.Authorizer
+	client Client
+}
+
+// NewFacade creates a new CharmHubAPI facade.
+func NewFacade(ctx facade.Context) (*CharmHubAPI, error) {
+	m, err := ctx.State().Model()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	cfg, err := m.ModelConfig()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	client, err := charmhub.NewClient(charmhub.Config{URL: cfg.CharmHubURL()})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return NewCharmHubAPI(ctx.Auth(), client)
+}
+
+// NewCharmHubAPI creates a new CharmHubAPI using the given client.
+func NewCharmHubAPI(authorizer facade.Authorizer, client Client) (*CharmHubAPI, error) {
+	if !authorizer.AuthClient() {
+		return nil, common.ErrPerm
+	}
+	return &CharmHubAPI{
+		auth:   authorizer,
+		client: client,
+	}, nil
+}
+
+// Info returns information about the named charm or bundle.
+func (api *CharmHubAPI) Info(arg params.Info) (params.CharmHubEntityInfoResult, error) {
+	logger.Tracef("Info(%v)", arg.Name)
+	info, err := api.client.Info(context.TODO(), arg.Name)
+	if err != nil {
+		return params.CharmHubEntityInfoResult{Error: common.ServerError(err)}, nil
+	}
+	return params.CharmHubEntityInfoResult{Result: convertInfoResponse(info)}, nil
+}
+
+// Find returns the charms and bundles matching the query.
+func (api *CharmHubAPI) Find(arg params.Query) (params.CharmHubEntityFindResult, error) {
+	logger.Tracef("Find(%v)", arg.Query)
+	results, err := api.client.Find(context.TODO(), arg.Query)
+	if err != nil {
+		return params.CharmHubEntityFindResult{Error: common.ServerError(err)}, nil
+	}
+	return params.CharmHubEntityFindResult{Results: convertFindResponses(results)}, nil
+}
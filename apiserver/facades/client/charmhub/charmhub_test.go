@@ -0,0 +1,136 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package charmhub_test
+
+import (
+	"github.com/juju/names/v4"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facades/client/charmhub"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	charmhubclient "github.com/juju/juju/charmhub"
+	charmhubtesting "github.com/juju/juju/charmhub/testing"
+)
+
+type charmHubAPISuite struct {
+	testing.IsolationSuite
+
+	server     *charmhubtesting.Server
+	authorizer apiservertesting.FakeAuthorizer
+	api        *charmhub.CharmHubAPI
+}
+
+var _ = gc.Suite(&charmHubAPISuite{})
+
+func (s *charmHubAPISuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.server = charmhubtesting.NewServer(charmhubtesting.Entity{
+		Name:        "wordpress",
+		Publisher:   "WordPress Charmers",
+		Summary:     "WordPress is a full featured web blogging tool.",
+		Description: "This will install and setup WordPress.",
+		Releases: []charmhubtesting.Release{{
+			Track:    "latest",
+			Risk:     "stable",
+			Series:   "focal",
+			Revision: 16,
+			Version:  "1.0.3",
+			Content:  []byte("wordpress-16"),
+		}, {
+			Track:    "2.0",
+			Risk:     "edge",
+			Series:   "bionic",
+			Revision: 17,
+			Version:  "2.0.0",
+			Content:  []byte("wordpress-17"),
+		}},
+	})
+	s.AddCleanup(func(*gc.C) { s.server.Close() })
+
+	client, err := charmhubclient.NewClient(charmhubclient.Config{URL: s.server.URL})
+	c.Assert(err, jc.ErrorIsNil)
+	s.authorizer = apiservertesting.FakeAuthorizer{Tag: names.NewUserTag("admin")}
+	s.api, err = charmhub.NewCharmHubAPI(s.authorizer, client)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *charmHubAPISuite) TestNewCharmHubAPIRefusesNonClient(c *gc.C) {
+	authorizer := apiservertesting.FakeAuthorizer{Tag: names.NewMachineTag("0")}
+	_, err := charmhub.NewCharmHubAPI(authorizer, nil)
+	c.Assert(err, gc.Equals, common.ErrPerm)
+}
+
+func (s *charmHubAPISuite) TestInfo(c *gc.C) {
+	result, err := s.api.Info(params.Info{Name: "wordpress"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Error, gc.IsNil)
+	c.Assert(result.Result, jc.DeepEquals, params.InfoResponse{
+		Type:           "charm",
+		ID:             "wordpress-id",
+		Name:           "wordpress",
+		Description:    "This will install and setup WordPress.",
+		Publisher:      "WordPress Charmers",
+		Summary:        "WordPress is a full featured web blogging tool.",
+		StoreURL:       s.server.URL + "/wordpress",
+		DefaultChannel: "latest/stable",
+		Tracks:         []string{"latest", "2.0"},
+		Channels: map[string]params.Channel{
+			"latest/stable": {
+				Track:       "latest",
+				Risk:        "stable",
+				Revision:    16,
+				Size:        12,
+				Version:     "1.0.3",
+				Series:      []string{"focal"},
+				DownloadURL: s.server.URL + "/download/wordpress_16.charm",
+				HashSHA256:  "273735fa49d42695f443eec110f30708747e8f35ba7a67aa6314f2c28a6ecdd7",
+			},
+			"2.0/edge": {
+				Track:       "2.0",
+				Risk:        "edge",
+				Revision:    17,
+				Size:        12,
+				Version:     "2.0.0",
+				Series:      []string{"bionic"},
+				DownloadURL: s.server.URL + "/download/wordpress_17.charm",
+				HashSHA256:  "68f48b9e93cd9a962ab45883fdf2a6b51fa14fa63ab4d77a7efd1f3c4375535b",
+			},
+		},
+	})
+}
+
+func (s *charmHubAPISuite) TestInfoNotFound(c *gc.C) {
+	result, err := s.api.Info(params.Info{Name: "ghost"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Error, gc.NotNil)
+	c.Check(result.Error, jc.Satisfies, params.IsCodeNotFound)
+	c.Check(result.Error.Message, gc.Equals, "No charm or bundle with name 'ghost'.")
+}
+
+func (s *charmHubAPISuite) TestFind(c *gc.C) {
+	result, err := s.api.Find(params.Query{Query: "blog"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Error, gc.IsNil)
+	c.Assert(result.Results, jc.DeepEquals, []params.FindResponse{{
+		Type:      "charm",
+		ID:        "wordpress-id",
+		Name:      "wordpress",
+		Publisher: "WordPress Charmers",
+		Summary:   "WordPress is a full featured web blogging tool.",
+		Version:   "1.0.3",
+		Series:    []string{"focal"},
+		StoreURL:  s.server.URL + "/wordpress",
+	}})
+}
+
+func (s *charmHubAPISuite) TestFindNoResults(c *gc.C) {
+	result, err := s.api.Find(params.Query{Query: "database"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Error, gc.IsNil)
+	c.Assert(result.Results, gc.HasLen, 0)
+}
@@ -0,0 +1,85 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package charmhub
+
+import (
+	"github.com/juju/collections/set"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/charmhub"
+)
+
+func convertInfoResponse(info charmhub.InfoResponse) params.InfoResponse {
+	result := params.InfoResponse{
+		Type:        info.Type,
+		ID:          info.ID,
+		Name:        info.Name,
+		Description: info.Entity.Description,
+		Publisher:   info.Entity.Publisher.DisplayName,
+		Summary:     info.Entity.Summary,
+		StoreURL:    info.Entity.StoreURL,
+		Channels:    make(map[string]params.Channel),
+	}
+	if info.DefaultRelease.Channel.Risk != "" {
+		result.DefaultChannel = info.DefaultRelease.Channel.String()
+	}
+	tracks := set.NewStrings()
+	for _, m := range info.ChannelMap {
+		if !tracks.Contains(m.Channel.Track) {
+			tracks.Add(m.Channel.Track)
+			result.Tracks = append(result.Tracks, m.Channel.Track)
+		}
+		name := m.Channel.String()
+		if _, ok := result.Channels[name]; ok {
+			// Only the first platform released to a channel is
+			// reported.
+			continue
+		}
+		result.Channels[name] = params.Channel{
+			ReleasedAt:  m.Channel.ReleasedAt,
+			Track:       m.Channel.Track,
+			Risk:        m.Channel.Risk,
+			Revision:    m.Revision.Revision,
+			Size:        m.Revision.Download.Size,
+			Version:     m.Revision.Version,
+			Series:      series(m),
+			DownloadURL: m.Revision.Download.URL,
+			HashSHA256:  m.Revision.Download.HashSHA256,
+		}
+	}
+	return result
+}
+
+func convertFindResponses(responses []charmhub.FindResponse) []params.FindResponse {
+	results := make([]params.FindResponse, len(responses))
+	for i, resp := range responses {
+		results[i] = params.FindResponse{
+			Type:      resp.Type,
+			ID:        resp.ID,
+			Name:      resp.Name,
+			Publisher: resp.Entity.Publisher.DisplayName,
+			Summary:   resp.Entity.Summary,
+			Version:   resp.DefaultRelease.Revision.Version,
+			Series:    series(resp.DefaultRelease),
+			StoreURL:  resp.Entity.StoreURL,
+		}
+	}
+	return results
+}
+
+// series returns the series supported by the revision released to a
+// channel, in the order they were listed.
+func series(m charmhub.ChannelMap) []string {
+	platforms := append([]charmhub.Platform{m.Channel.Platform}, m.Revision.Platforms...)
+	seen := set.NewStrings()
+	var result []string
+	for _, p := range platforms {
+		if p.Series == "" || seen.Contains(p.Series) {
+			continue
+		}
+		seen.Add(p.Series)
+		result = append(result, p.Series)
+	}
+	return result
+}
@@ -6,7 +6,9 @@
 package controller
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"sort"
 	"strings"
 
@@ -25,12 +27,14 @@ import (
 	"github.com/juju/juju/apiserver/common/cloudspec"
 	"github.com/juju/juju/apiserver/facade"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/autocert"
 	corecontroller "github.com/juju/juju/controller"
 	"github.com/juju/juju/core/cache"
 	coremigration "github.com/juju/juju/core/migration"
 	"github.com/juju/juju/core/multiwatcher"
 	"github.com/juju/juju/core/permission"
 	"github.com/juju/juju/migration"
+	"github.com/juju/juju/pki"
 	"github.com/juju/juju/pubsub/controller"
 	"github.com/juju/juju/state"
 	jujuversion "github.com/juju/juju/version"
@@ -56,10 +60,37 @@ type ControllerAPI struct {
 	multiwatcherFactory multiwatcher.Factory
 }
 
+// ControllerAPIv12 provides the v12 Controller API. The only difference
+// between this and v13 is that v12 doesn't have the AutocertStatus
+// method.
+type ControllerAPIv12 struct {
+	*ControllerAPI
+}
+
+// ControllerAPIv11 provides the v11 Controller API. The only difference
+// between this and v12 is that v11 doesn't support rotating the
+// controller's CA.
+type ControllerAPIv11 struct {
+	*ControllerAPIv12
+}
+
+// ControllerAPIv10 provides the v10 Controller API. The only difference
+// between this and v11 is that v10 doesn't support granting controller
+// access to groups.
+type ControllerAPIv10 struct {
+	*ControllerAPIv11
+}
+
+// ControllerAPIv9 provides the v9 Controller API. The only difference
+// between this and v10 is that v9 doesn't have the MigrationDryRun method.
+type ControllerAPIv9 struct {
+	*ControllerAPIv10
+}
+
 // ControllerAPIv8 provides the v8 Controller API. The only difference
 // between this and v9 is that v8 doesn't have the model summary watchers.
 type ControllerAPIv8 struct {
-	*ControllerAPI
+	*ControllerAPIv9
 }
 
 // ControllerAPIv7 provides the v7 Controller API. The only difference
@@ -94,10 +125,10 @@ type ControllerAPIv3 struct {
 
 // LatestAPI is used for testing purposes to create the latest
 // controller API.
-var LatestAPI = NewControllerAPIv9
+var LatestAPI = NewControllerAPIv13
 
-// NewControllerAPIv9 creates a new ControllerAPIv9.
-func NewControllerAPIv9(ctx facade.Context) (*ControllerAPI, error) {
+// NewControllerAPIv13 creates a new ControllerAPIv13.
+func NewControllerAPIv13(ctx facade.Context) (*ControllerAPI, error) {
 	st := ctx.State()
 	authorizer := ctx.Auth()
 	pool := ctx.StatePool()
@@ -119,6 +150,42 @@ func NewControllerAPIv9(ctx facade.Context) (*ControllerAPI, error) {
 	)
 }
 
+// NewControllerAPIv12 creates a new ControllerAPIv12.
+func NewControllerAPIv12(ctx facade.Context) (*ControllerAPIv12, error) {
+	v13, err := NewControllerAPIv13(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &ControllerAPIv12{v13}, nil
+}
+
+// NewControllerAPIv11 creates a new ControllerAPIv11.
+func NewControllerAPIv11(ctx facade.Context) (*ControllerAPIv11, error) {
+	v12, err := NewControllerAPIv12(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &ControllerAPIv11{v12}, nil
+}
+
+// NewControllerAPIv10 creates a new ControllerAPIv10.
+func NewControllerAPIv10(ctx facade.Context) (*ControllerAPIv10, error) {
+	v11, err := NewControllerAPIv11(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &ControllerAPIv10{v11}, nil
+}
+
+// NewControllerAPIv9 creates a new ControllerAPIv9.
+func NewControllerAPIv9(ctx facade.Context) (*ControllerAPIv9, error) {
+	v10, err := NewControllerAPIv10(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &ControllerAPIv9{v10}, nil
+}
+
 // NewControllerAPIv8 creates a new ControllerAPIv8.
 func NewControllerAPIv8(ctx facade.Context) (*ControllerAPIv8, error) {
 	v9, err := NewControllerAPIv9(ctx)
@@ -550,6 +617,12 @@ func (c *ControllerAPI) GetControllerAccess(req params.Entities) (params.UserAcc
 		return results, errors.Trace(err)
 	}
 
+	// Report the access granted to the users' groups too.
+	userPermission := common.WithGroupAccess(c.state.UserPermission, c.state,
+		func(user names.UserTag) ([]string, error) {
+			return c.authorizer.UserGroups(user), nil
+		},
+	)
 	users := req.Entities
 	results.Results = make([]params.UserAccessResult, len(users))
 	for i, user := range users {
@@ -562,7 +635,7 @@ func (c *ControllerAPI) GetControllerAccess(req params.Entities) (params.UserAcc
 			results.Results[i].Error = common.ServerError(common.ErrPerm)
 			continue
 		}
-		access, err := c.state.UserPermission(userTag, c.state.ControllerTag())
+		access, err := userPermission(userTag, c.state.ControllerTag())
 		if err != nil {
 			results.Results[i].Error = common.ServerError(err)
 			continue
@@ -600,41 +673,61 @@ func (c *ControllerAPI) InitiateMigration(reqArgs params.InitiateMigrationArgs)
 }
 
 func (c *ControllerAPI) initiateOneMigration(spec params.MigrationSpec) (string, error) {
-	modelTag, err := names.ParseModelTag(spec.ModelTag)
+	hostedState, targetInfo, err := c.migrationSpecState(spec)
 	if err != nil {
-		return "", errors.Annotate(err, "model tag")
+		return "", errors.Trace(err)
 	}
+	defer hostedState.Release()
 
-	// Ensure the model exists.
-	if modelExists, err := c.state.ModelExists(modelTag.Id()); err != nil {
-		return "", errors.Annotate(err, "reading model")
-	} else if !modelExists {
-		return "", errors.NotFoundf("model")
+	// Check if the migration is likely to succeed.
+	if err := runMigrationPrechecks(hostedState.State, c.statePool.SystemState(), &targetInfo, c.presence); err != nil {
+		return "", errors.Trace(err)
 	}
 
-	hostedState, err := c.statePool.Get(modelTag.Id())
+	// Trigger the migration.
+	mig, err := hostedState.CreateMigration(state.MigrationSpec{
+		InitiatedBy: c.apiUser,
+		TargetInfo:  targetInfo,
+	})
 	if err != nil {
 		return "", errors.Trace(err)
 	}
-	defer hostedState.Release()
+	return mig.Id(), nil
+}
+
+// migrationSpecState returns the state of the model to be migrated by
+// the spec, along with the migration's target.
+func (c *ControllerAPI) migrationSpecState(spec params.MigrationSpec) (*state.PooledState, coremigration.TargetInfo, error) {
+	var targetInfo coremigration.TargetInfo
+	modelTag, err := names.ParseModelTag(spec.ModelTag)
+	if err != nil {
+		return nil, targetInfo, errors.Annotate(err, "model tag")
+	}
+
+	// Ensure the model exists.
+	if modelExists, err := c.state.ModelExists(modelTag.Id()); err != nil {
+		return nil, targetInfo, errors.Annotate(err, "reading model")
+	} else if !modelExists {
+		return nil, targetInfo, errors.NotFoundf("model")
+	}
 
 	// Construct target info.
 	specTarget := spec.TargetInfo
 	controllerTag, err := names.ParseControllerTag(specTarget.ControllerTag)
 	if err != nil {
-		return "", errors.Annotate(err, "controller tag")
+		return nil, targetInfo, errors.Annotate(err, "controller tag")
 	}
 	authTag, err := names.ParseUserTag(specTarget.AuthTag)
 	if err != nil {
-		return "", errors.Annotate(err, "auth tag")
+		return nil, targetInfo, errors.Annotate(err, "auth tag")
 	}
 	var macs []macaroon.Slice
 	if specTarget.Macaroons != "" {
 		if err := json.Unmarshal([]byte(specTarget.Macaroons), &macs); err != nil {
-			return "", errors.Annotate(err, "invalid macaroons")
+			return nil, targetInfo, errors.Annotate(err, "invalid macaroons")
 		}
 	}
-	targetInfo := coremigration.TargetInfo{
+	targetInfo = coremigration.TargetInfo{
 		ControllerTag:   controllerTag,
 		ControllerAlias: specTarget.ControllerAlias,
 		Addrs:           specTarget.Addrs,
@@ -644,23 +737,66 @@ func (c *ControllerAPI) initiateOneMigration(spec params.MigrationSpec) (string,
 		Macaroons:       macs,
 	}
 
-	// Check if the migration is likely to succeed.
-	if err := runMigrationPrechecks(hostedState.State, c.statePool.SystemState(), &targetInfo, c.presence); err != nil {
-		return "", errors.Trace(err)
+	hostedState, err := c.statePool.Get(modelTag.Id())
+	if err != nil {
+		return nil, targetInfo, errors.Trace(err)
 	}
+	return hostedState, targetInfo, nil
+}
 
-	// Trigger the migration.
-	mig, err := hostedState.CreateMigration(state.MigrationSpec{
-		InitiatedBy: c.apiUser,
-		TargetInfo:  targetInfo,
-	})
+// MigrationDryRun isn't on the v9 API.
+func (c *ControllerAPIv9) MigrationDryRun(_, _ struct{}) {}
+
+// MigrationDryRun checks whether models could be migrated to another
+// controller, without starting their migrations. All of the source and
+// target prechecks are run, and each model is imported into the target
+// controller as a throwaway model, which is removed again. Every problem
+// found is reported.
+func (c *ControllerAPI) MigrationDryRun(reqArgs params.InitiateMigrationArgs) (
+	params.MigrationDryRunResults, error,
+) {
+	out := params.MigrationDryRunResults{
+		Results: make([]params.MigrationDryRunResult, len(reqArgs.Specs)),
+	}
+	if err := c.checkIsSuperUser(); err != nil {
+		return out, errors.Trace(err)
+	}
+
+	for i, spec := range reqArgs.Specs {
+		result, err := c.dryRunOneMigration(spec)
+		if err != nil {
+			result.Error = common.ServerError(err)
+		}
+		result.ModelTag = spec.ModelTag
+		out.Results[i] = result
+	}
+	return out, nil
+}
+
+func (c *ControllerAPI) dryRunOneMigration(spec params.MigrationSpec) (params.MigrationDryRunResult, error) {
+	hostedState, targetInfo, err := c.migrationSpecState(spec)
 	if err != nil {
-		return "", errors.Trace(err)
+		return params.MigrationDryRunResult{}, errors.Trace(err)
 	}
-	return mig.Id(), nil
+	defer hostedState.Release()
+
+	result, err := runMigrationDryRun(hostedState.State, c.statePool.SystemState(), &targetInfo, c.presence)
+	return result, errors.Trace(err)
+}
+
+// ModifyControllerAccess changes the controller access granted to users.
+// Version 10 does not support granting access to groups.
+func (c *ControllerAPIv10) ModifyControllerAccess(args params.ModifyControllerAccessRequest) (params.ErrorResults, error) {
+	for _, arg := range args.Changes {
+		if arg.GroupName != "" {
+			return params.ErrorResults{}, errors.NotSupportedf("modifying controller access for groups")
+		}
+	}
+	return c.ControllerAPI.ModifyControllerAccess(args)
 }
 
-// ModifyControllerAccess changes the model access granted to users.
+// ModifyControllerAccess changes the controller access granted to users
+// or groups.
 func (c *ControllerAPI) ModifyControllerAccess(args params.ModifyControllerAccessRequest) (params.ErrorResults, error) {
 	result := params.ErrorResults{
 		Results: make([]params.ErrorResult, len(args.Changes)),
@@ -690,6 +826,12 @@ func (c *ControllerAPI) ModifyControllerAccess(args params.ModifyControllerAcces
 			}
 		}
 
+		if arg.GroupName != "" {
+			result.Results[i].Error = common.ServerError(
+				changeControllerGroupAccess(c.state, arg.GroupName, arg.Action, controllerAccess))
+			continue
+		}
+
 		targetUserTag, err := names.ParseUserTag(arg.UserTag)
 		if err != nil {
 			result.Results[i].Error = common.ServerError(errors.Annotate(err, "could not modify controller access"))
@@ -734,6 +876,176 @@ func (c *ControllerAPI) ConfigSet(args params.ControllerConfigSet) error {
 // ConfigSet isn't on the v4 API.
 func (c *ControllerAPIv4) ConfigSet(_, _ struct{}) {}
 
+// ControllerCARotation returns the state of any rotation of the
+// controller's CA that is in progress, along with the CA certificates
+// that should currently be trusted.
+func (c *ControllerAPI) ControllerCARotation() (params.ControllerCARotation, error) {
+	if err := c.checkIsSuperUser(); err != nil {
+		return params.ControllerCARotation{}, errors.Trace(err)
+	}
+	return c.caRotationResult()
+}
+
+// ControllerCARotation isn't on the v11 API.
+func (c *ControllerAPIv11) ControllerCARotation(_, _ struct{}) {}
+
+// RotateControllerCA performs a step of a rotation of the controller's
+// CA. Rotation is started by creating a new CA, cross-signed by the
+// current one, which agents are told to trust alongside the current CA.
+// Once the agents trust both, the controllers' certificates are reissued
+// by the new CA; finally, once every controller has reissued its
+// certificate and agents have had time to trust the new CA, the old CA
+// is retired. A rotation can be aborted until certificates have been
+// reissued.
+func (c *ControllerAPI) RotateControllerCA(args params.RotateControllerCAArgs) (params.ControllerCARotation, error) {
+	if err := c.checkIsSuperUser(); err != nil {
+		return params.ControllerCARotation{}, errors.Trace(err)
+	}
+	var err error
+	switch args.Step {
+	case "start":
+		err = c.startCARotation()
+	case "reissue":
+		err = c.state.ReissueCARotation()
+	case "finish":
+		err = c.state.FinishCARotation()
+	case "abort":
+		err = c.state.AbortCARotation()
+	default:
+		err = errors.NotValidf("CA rotation step %q", args.Step)
+	}
+	if err != nil {
+		return params.ControllerCARotation{}, errors.Trace(err)
+	}
+	cfg, err := c.state.ControllerConfig()
+	if err != nil {
+		return params.ControllerCARotation{}, errors.Trace(err)
+	}
+	if _, err := c.hub.Publish(
+		controller.ConfigChanged,
+		controller.ConfigChangedMessage{cfg}); err != nil {
+		return params.ControllerCARotation{}, errors.Trace(err)
+	}
+	return c.caRotationResult()
+}
+
+// RotateControllerCA isn't on the v11 API.
+func (c *ControllerAPIv11) RotateControllerCA(_, _ struct{}) {}
+
+// AutocertStatus returns the status of the certificate obtained for
+// the controller's autocert DNS name, and the last error, if any,
+// encountered obtaining one.
+func (c *ControllerAPI) AutocertStatus() (params.AutocertStatus, error) {
+	if err := c.checkIsSuperUser(); err != nil {
+		return params.AutocertStatus{}, errors.Trace(err)
+	}
+	cfg, err := c.state.ControllerConfig()
+	if err != nil {
+		return params.AutocertStatus{}, errors.Trace(err)
+	}
+	result := params.AutocertStatus{
+		DNSName: cfg.AutocertDNSName(),
+	}
+	if result.DNSName == "" {
+		return result, nil
+	}
+	status, err := autocert.CertificateStatus(context.Background(), c.state.AutocertCache(), result.DNSName)
+	switch {
+	case err == nil:
+		result.Issuer = status.Issuer
+		result.NotBefore = &status.NotBefore
+		result.NotAfter = &status.NotAfter
+		result.RenewAfter = &status.RenewAfter
+	case !errors.IsNotFound(err):
+		return params.AutocertStatus{}, errors.Trace(err)
+	}
+	failure, err := c.state.AutocertFailure()
+	switch {
+	case err == nil:
+		result.Error = failure.Message
+		result.ErrorTime = &failure.Time
+	case !errors.IsNotFound(err):
+		return params.AutocertStatus{}, errors.Trace(err)
+	}
+	return result, nil
+}
+
+// AutocertStatus isn't on the v12 API.
+func (c *ControllerAPIv12) AutocertStatus(_, _ struct{}) {}
+
+func (c *ControllerAPI) startCARotation() error {
+	// Avoid generating a new CA if there's already a rotation.
+	if _, err := c.state.CARotation(); err == nil {
+		return errors.AlreadyExistsf("CA rotation")
+	} else if !errors.IsNotFound(err) {
+		return errors.Trace(err)
+	}
+	cfg, err := c.state.ControllerConfig()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	info, err := c.state.StateServingInfo()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	caCert, _ := cfg.CACert()
+	authority, err := pki.NewDefaultAuthorityPemCAKey([]byte(caCert), []byte(info.CAPrivateKey))
+	if err != nil {
+		return errors.Annotate(err, "loading current CA")
+	}
+
+	signer, err := pki.DefaultKeyProfile()
+	if err != nil {
+		return errors.Annotate(err, "generating key for new CA")
+	}
+	newCA, err := pki.NewCA("juju-ca", signer)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	crossSigned, err := pki.CrossSignCA(newCA, authority.Certificate(), authority.Signer())
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	newCACert, err := pki.CertificateToPemString(pki.DefaultPemHeaders, newCA)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	newCAKey, err := pki.SignerToPemString(signer)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	crossSignedCert, err := pki.CertificateToPemString(pki.DefaultPemHeaders, crossSigned)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return c.state.StartCARotation(newCACert, newCAKey, crossSignedCert)
+}
+
+func (c *ControllerAPI) caRotationResult() (params.ControllerCARotation, error) {
+	cfg, err := c.state.ControllerConfig()
+	if err != nil {
+		return params.ControllerCARotation{}, errors.Trace(err)
+	}
+	caCert, _ := cfg.CACert()
+	result := params.ControllerCARotation{CACert: caCert}
+
+	rotation, err := c.state.CARotation()
+	if errors.IsNotFound(err) {
+		return result, nil
+	} else if err != nil {
+		return params.ControllerCARotation{}, errors.Trace(err)
+	}
+	started := rotation.Started()
+	result.Phase = string(rotation.Phase())
+	result.NewCACert = rotation.NewCACert()
+	result.Started = &started
+	if reissued := rotation.Reissued(); !reissued.IsZero() {
+		result.Reissued = &reissued
+	}
+	return result, nil
+}
+
 // runMigrationPrechecks runs prechecks on the migration and updates
 // information in targetInfo as needed based on information
 // retrieved from the target controller.
@@ -782,6 +1094,76 @@ var runMigrationPrechecks = func(st, ctlrSt *state.State, targetInfo *coremigrat
 	return errors.Annotate(err, "target prechecks failed")
 }
 
+// runMigrationDryRun runs all of the migration prechecks, and imports
+// the model into the target controller as a throwaway model, collecting
+// every problem found.
+var runMigrationDryRun = func(st, ctlrSt *state.State, targetInfo *coremigration.TargetInfo, presence facade.Presence) (params.MigrationDryRunResult, error) {
+	var result params.MigrationDryRunResult
+	addProblem := func(stage string, err error) {
+		result.Problems = append(result.Problems, fmt.Sprintf("%s: %v", stage, err))
+	}
+
+	// Check model and source controller.
+	backend, err := migration.PrecheckShim(st, ctlrSt)
+	if err != nil {
+		return result, errors.Annotate(err, "creating backend")
+	}
+	modelPresence := presence.ModelPresence(st.ModelUUID())
+	controllerPresence := presence.ModelPresence(ctlrSt.ModelUUID())
+	for _, problem := range migration.SourcePrecheckProblems(backend, modelPresence, controllerPresence) {
+		addProblem("source prechecks", problem)
+	}
+
+	// Check target controller.
+	conn, err := api.Open(targetToAPIInfo(targetInfo), migration.ControllerDialOpts())
+	if err != nil {
+		addProblem("connect to target controller", err)
+		return result, nil
+	}
+	defer conn.Close()
+	modelInfo, srcUserList, err := makeModelInfo(st, ctlrSt)
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	dstUserList, err := getTargetControllerUsers(conn)
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	if err := srcUserList.checkCompatibilityWith(dstUserList); err != nil {
+		addProblem("users", err)
+	}
+	client := migrationtarget.NewClient(conn)
+	targetProblems, err := client.PrecheckProblems(modelInfo)
+	if errors.IsNotSupported(err) {
+		// Older target controllers only report the first problem.
+		result.Notes = append(result.Notes, "target controller only reports the first precheck problem")
+		targetProblems = nil
+		if err := client.Prechecks(modelInfo); err != nil {
+			targetProblems = append(targetProblems, err)
+		}
+	} else if err != nil {
+		return result, errors.Annotate(err, "target prechecks")
+	}
+	for _, problem := range targetProblems {
+		addProblem("target prechecks", problem)
+	}
+
+	// Check that the model exports, and imports into the target.
+	bytes, err := migration.ExportModel(st)
+	if err != nil {
+		addProblem("model export", err)
+		return result, nil
+	}
+	upconversions, err := client.ImportDryRun(bytes)
+	if errors.IsNotSupported(err) {
+		result.Notes = append(result.Notes, "target controller does not support import dry runs")
+	} else if err != nil {
+		addProblem("model import", err)
+	}
+	result.Upconversions = upconversions
+	return result, nil
+}
+
 // userList encapsulates information about the users who have been granted
 // access to a model or the users known to a particular controller.
 type userList struct {
@@ -1033,6 +1415,57 @@ func revokeControllerAccess(accessor *state.State, targetUserTag, apiUser names.
 	}
 }
 
+// changeControllerGroupAccess performs the requested access grant or
+// revoke action for the named group on the controller.
+func changeControllerGroupAccess(accessor *state.State, group string, action params.ControllerAction, access permission.Access) error {
+	if err := permission.ValidateControllerAccess(access); err != nil {
+		return errors.Trace(err)
+	}
+	controllerTag := accessor.ControllerTag()
+	switch action {
+	case params.GrantControllerAccess:
+		err := accessor.CreateGroupAccess(group, controllerTag, access)
+		if errors.IsAlreadyExists(err) {
+			groupAccess, err := accessor.GroupAccess(group, controllerTag)
+			if errors.IsNotFound(err) {
+				// Conflicts with prior check, must be inconsistent state.
+				err = txn.ErrExcessiveContention
+			}
+			if err != nil {
+				return errors.Annotate(err, "could not look up controller access for group")
+			}
+
+			// Only set access if greater access is being granted.
+			if groupAccess.EqualOrGreaterControllerAccessThan(access) {
+				return errors.Errorf("group already has %q access or greater", access)
+			}
+			err = accessor.UpdateGroupAccess(group, controllerTag, access)
+			return errors.Annotate(err, "could not set controller access for group")
+		}
+		return errors.Annotate(err, "could not grant controller access")
+
+	case params.RevokeControllerAccess:
+		switch access {
+		case permission.LoginAccess:
+			// Revoking login access removes all access.
+			err := accessor.RemoveGroupAccess(group, controllerTag)
+			return errors.Annotate(err, "could not revoke controller access")
+		case permission.SuperuserAccess:
+			// Revoking superuser sets login.
+			if _, err := accessor.GroupAccess(group, controllerTag); err != nil {
+				return errors.Annotate(err, "could not look up controller access for group")
+			}
+			err := accessor.UpdateGroupAccess(group, controllerTag, permission.LoginAccess)
+			return errors.Annotate(err, "could not set controller access to login")
+		default:
+			return errors.Errorf("don't know how to revoke %q access", access)
+		}
+
+	default:
+		return errors.Errorf("unknown action %q", action)
+	}
+}
+
 // ChangeControllerAccess performs the requested access grant or revoke action for the
 // specified user on the controller.
 func ChangeControllerAccess(accessor *state.State, apiUser, targetUserTag names.UserTag, action params.ControllerAction, access permission.Access) error {
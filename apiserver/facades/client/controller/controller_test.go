@@ -6,6 +6,7 @@ package controller_test
 import (
 	"encoding/json"
 	"regexp"
+	"strings"
 	"time"
 
 	"github.com/juju/errors"
@@ -614,6 +615,77 @@ func (s *controllerSuite) TestInitiateMigrationPrecheckFail(c *gc.C) {
 	c.Check(active, jc.IsFalse)
 }
 
+func (s *controllerSuite) TestMigrationDryRun(c *gc.C) {
+	st := s.Factory.MakeModel(c, nil)
+	defer st.Close()
+
+	controller.SetDryRunResult(s, params.MigrationDryRunResult{
+		Problems:      []string{"source prechecks: cleanup needed"},
+		Upconversions: []string{"model upconverted from version 7 to 8"},
+	}, nil)
+
+	m, err := st.Model()
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.InitiateMigrationArgs{
+		Specs: []params.MigrationSpec{{
+			ModelTag: m.ModelTag().String(),
+			TargetInfo: params.MigrationTargetInfo{
+				ControllerTag: randomControllerTag(),
+				Addrs:         []string{"1.1.1.1:1111"},
+				CACert:        "cert1",
+				AuthTag:       names.NewUserTag("admin1").String(),
+				Password:      "secret1",
+			},
+		}, {
+			ModelTag: randomModelTag(), // Doesn't exist.
+		}},
+	}
+	out, err := s.controller.MigrationDryRun(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out.Results, jc.DeepEquals, []params.MigrationDryRunResult{{
+		ModelTag:      m.ModelTag().String(),
+		Problems:      []string{"source prechecks: cleanup needed"},
+		Upconversions: []string{"model upconverted from version 7 to 8"},
+	}, {
+		ModelTag: args.Specs[1].ModelTag,
+		Error:    &params.Error{Message: "model not found", Code: params.CodeNotFound},
+	}})
+
+	// No migration is started.
+	active, err := st.IsMigrationActive()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(active, jc.IsFalse)
+}
+
+func (s *controllerSuite) TestMigrationDryRunError(c *gc.C) {
+	st := s.Factory.MakeModel(c, nil)
+	defer st.Close()
+
+	controller.SetDryRunResult(s, params.MigrationDryRunResult{}, errors.New("boom"))
+
+	m, err := st.Model()
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.InitiateMigrationArgs{
+		Specs: []params.MigrationSpec{{
+			ModelTag: m.ModelTag().String(),
+			TargetInfo: params.MigrationTargetInfo{
+				ControllerTag: randomControllerTag(),
+				Addrs:         []string{"1.1.1.1:1111"},
+				CACert:        "cert1",
+				AuthTag:       names.NewUserTag("admin1").String(),
+				Password:      "secret1",
+			},
+		}},
+	}
+	out, err := s.controller.MigrationDryRun(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out.Results, gc.HasLen, 1)
+	c.Check(out.Results[0].ModelTag, gc.Equals, m.ModelTag().String())
+	c.Check(out.Results[0].Error, gc.ErrorMatches, "boom")
+}
+
 func randomControllerTag() string {
 	uuid := utils.MustNewUUID().String()
 	return names.NewControllerTag(uuid).String()
@@ -809,6 +881,55 @@ func (s *controllerSuite) TestGrantControllerInvalidUserTag(c *gc.C) {
 	}
 }
 
+func (s *controllerSuite) modifyGroupAccess(c *gc.C, action params.ControllerAction, access permission.Access) error {
+	result, err := s.controller.ModifyControllerAccess(params.ModifyControllerAccessRequest{
+		Changes: []params.ModifyControllerAccess{{
+			GroupName: "ops",
+			Action:    action,
+			Access:    string(access),
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	return result.OneError()
+}
+
+func (s *controllerSuite) TestGrantAndRevokeGroupControllerAccess(c *gc.C) {
+	ctag := names.NewControllerTag(s.State.ControllerUUID())
+	err := s.modifyGroupAccess(c, params.GrantControllerAccess, permission.LoginAccess)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.modifyGroupAccess(c, params.GrantControllerAccess, permission.SuperuserAccess)
+	c.Assert(err, jc.ErrorIsNil)
+	access, err := s.State.GroupAccess("ops", ctag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(access, gc.Equals, permission.SuperuserAccess)
+
+	err = s.modifyGroupAccess(c, params.GrantControllerAccess, permission.LoginAccess)
+	c.Assert(err, gc.ErrorMatches, `could not grant controller access: group already has "login" access or greater`)
+
+	err = s.modifyGroupAccess(c, params.RevokeControllerAccess, permission.SuperuserAccess)
+	c.Assert(err, jc.ErrorIsNil)
+	access, err = s.State.GroupAccess("ops", ctag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(access, gc.Equals, permission.LoginAccess)
+
+	err = s.modifyGroupAccess(c, params.RevokeControllerAccess, permission.LoginAccess)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.GroupAccess("ops", ctag)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *controllerSuite) TestModifyGroupControllerAccessV10NotSupported(c *gc.C) {
+	api := &controller.ControllerAPIv10{&controller.ControllerAPIv11{&controller.ControllerAPIv12{s.controller}}}
+	_, err := api.ModifyControllerAccess(params.ModifyControllerAccessRequest{
+		Changes: []params.ModifyControllerAccess{{
+			GroupName: "ops",
+			Action:    params.GrantControllerAccess,
+			Access:    string(permission.LoginAccess),
+		}},
+	})
+	c.Assert(err, gc.ErrorMatches, "modifying controller access for groups not supported")
+}
+
 func (s *controllerSuite) TestModifyControllerAccessEmptyArgs(c *gc.C) {
 	args := params.ModifyControllerAccessRequest{Changes: []params.ModifyControllerAccess{{}}}
 
@@ -1021,6 +1142,130 @@ func (s *controllerSuite) TestConfigSetPublishesEvent(c *gc.C) {
 	c.Assert(config.Features().SortedValues(), jc.DeepEquals, []string{"bar", "foo"})
 }
 
+func (s *controllerSuite) TestControllerCARotationNone(c *gc.C) {
+	result, err := s.controller.ControllerCARotation()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Phase, gc.Equals, "")
+	c.Assert(result.CACert, gc.Equals, testing.CACert)
+	c.Assert(result.Started, gc.IsNil)
+}
+
+func (s *controllerSuite) TestRotateControllerCA(c *gc.C) {
+	err := s.State.SetStateServingInfo(corecontroller.StateServingInfo{
+		PrivateKey:   testing.ServerKey,
+		Cert:         testing.ServerCert,
+		CAPrivateKey: testing.CAKey,
+		SharedSecret: "really, really secret",
+		APIPort:      1234,
+		StatePort:    4321,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	result, err := s.controller.RotateControllerCA(params.RotateControllerCAArgs{Step: "start"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Phase, gc.Equals, "trusting")
+	c.Assert(result.Started, gc.NotNil)
+	c.Assert(result.Reissued, gc.IsNil)
+	c.Assert(strings.HasPrefix(result.CACert, strings.TrimSpace(testing.CACert)), jc.IsTrue)
+	c.Assert(strings.Contains(result.CACert, strings.TrimSpace(result.NewCACert)), jc.IsTrue)
+
+	result, err = s.controller.RotateControllerCA(params.RotateControllerCAArgs{Step: "reissue"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Phase, gc.Equals, "reissued")
+	c.Assert(result.Reissued, gc.NotNil)
+	newCACert := result.NewCACert
+	c.Assert(strings.HasPrefix(result.CACert, strings.TrimSpace(newCACert)), jc.IsTrue)
+
+	// Agents are given time to trust the new CA before the old
+	// one is retired.
+	_, err = s.controller.RotateControllerCA(params.RotateControllerCAArgs{Step: "finish"})
+	c.Assert(err, gc.ErrorMatches, "cannot finish CA rotation before .*, to give agents time to trust the new CA")
+	rotation, err := s.controller.ControllerCARotation()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rotation.Phase, gc.Equals, "reissued")
+}
+
+func (s *controllerSuite) TestRotateControllerCABadStep(c *gc.C) {
+	_, err := s.controller.RotateControllerCA(params.RotateControllerCAArgs{Step: "sideways"})
+	c.Assert(err, gc.ErrorMatches, `CA rotation step "sideways" not valid`)
+}
+
+func (s *controllerSuite) TestRotateControllerCARequiresSuperUser(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{
+		Access: permission.ReadAccess,
+	})
+	endpoint, err := controller.LatestAPI(
+		facadetest.Context{
+			State_:     s.State,
+			Resources_: s.resources,
+			Auth_:      apiservertesting.FakeAuthorizer{Tag: user.Tag()},
+		})
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = endpoint.RotateControllerCA(params.RotateControllerCAArgs{Step: "start"})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *controllerSuite) TestAutocertStatusNotConfigured(c *gc.C) {
+	result, err := s.controller.AutocertStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.AutocertStatus{})
+}
+
+func (s *controllerSuite) TestAutocertStatusRequiresSuperUser(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{
+		Access: permission.ReadAccess,
+	})
+	endpoint, err := controller.LatestAPI(
+		facadetest.Context{
+			State_:     s.State,
+			Resources_: s.resources,
+			Auth_:      apiservertesting.FakeAuthorizer{Tag: user.Tag()},
+		})
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = endpoint.AutocertStatus()
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *controllerSuite) TestAutocertStatus(c *gc.C) {
+	// Preserve default controller config as we will be mutating it just
+	// for this test
+	defer func(orig map[string]interface{}) {
+		s.ControllerConfig = orig
+	}(s.ControllerConfig)
+
+	// The autocert DNS name cannot be changed after bootstrap; we need
+	// to spin up another controller with it pre-configured.
+	s.TearDownTest(c)
+	s.ControllerConfig = map[string]interface{}{
+		corecontroller.AutocertDNSNameKey: "controller.example",
+	}
+	s.SetUpTest(c)
+
+	result, err := s.controller.AutocertStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.AutocertStatus{
+		DNSName: "controller.example",
+	})
+
+	err = s.State.SetAutocertFailure("registering ACME account: boom")
+	c.Assert(err, jc.ErrorIsNil)
+	result, err = s.controller.AutocertStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.DNSName, gc.Equals, "controller.example")
+	c.Assert(result.NotAfter, gc.IsNil)
+	c.Assert(result.Error, gc.Equals, "registering ACME account: boom")
+	c.Assert(result.ErrorTime, gc.NotNil)
+
+	err = s.State.ClearAutocertFailure()
+	c.Assert(err, jc.ErrorIsNil)
+	result, err = s.controller.AutocertStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Error, gc.Equals, "")
+	c.Assert(result.ErrorTime, gc.IsNil)
+}
+
 func (s *controllerSuite) TestMongoVersion(c *gc.C) {
 	result, err := s.controller.MongoVersion()
 	c.Assert(err, jc.ErrorIsNil)
@@ -144,6 +144,8 @@ func (m *mockEnviron) InstanceTypes(ctx context.ProviderCallContext, c constrain
 
 type mockModel struct {
 	machinemanager.Model
+
+	attrs map[string]interface{}
 }
 
 func (mockModel) CloudCredentialTag() (names.CloudCredentialTag, bool) {
@@ -154,8 +156,8 @@ func (mockModel) ModelTag() names.ModelTag {
 	return names.NewModelTag("beef1beef1-0000-0000-000011112222")
 }
 
-func (*mockModel) Config() (*config.Config, error) {
-	return config.New(config.UseDefaults, dummy.SampleConfig())
+func (m *mockModel) Config() (*config.Config, error) {
+	return config.New(config.UseDefaults, dummy.SampleConfig().Merge(m.attrs))
 }
 
 func (*mockModel) CloudName() string {
@@ -71,6 +71,24 @@ type MachineManagerAPIV5 struct {
 // Version 6 of Machine Manager API.
 // Changes input parameters to DestroyMachineWithParams and ForceDestroyMachine.
 type MachineManagerAPIV6 struct {
+	*MachineManagerAPIV7
+}
+
+// Version 7 of Machine Manager API.
+// Adds ReenlistMachines.
+type MachineManagerAPIV7 struct {
+	*MachineManagerAPIV8
+}
+
+// Version 8 of Machine Manager API.
+// Adds MachineUserData.
+type MachineManagerAPIV8 struct {
+	*MachineManagerAPIV9
+}
+
+// Version 9 of Machine Manager API.
+// Adds PrecheckMachines.
+type MachineManagerAPIV9 struct {
 	*MachineManagerAPI
 }
 
@@ -94,11 +112,38 @@ func NewFacadeV5(ctx facade.Context) (*MachineManagerAPIV5, error) {
 
 // NewFacadeV6 creates a new server-side MachineManager API facade.
 func NewFacadeV6(ctx facade.Context) (*MachineManagerAPIV6, error) {
+	machineManagerAPIv7, err := NewFacadeV7(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &MachineManagerAPIV6{machineManagerAPIv7}, nil
+}
+
+// NewFacadeV7 creates a new server-side MachineManager API facade.
+func NewFacadeV7(ctx facade.Context) (*MachineManagerAPIV7, error) {
+	machineManagerAPIv8, err := NewFacadeV8(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &MachineManagerAPIV7{machineManagerAPIv8}, nil
+}
+
+// NewFacadeV8 creates a new server-side MachineManager API facade.
+func NewFacadeV8(ctx facade.Context) (*MachineManagerAPIV8, error) {
+	machineManagerAPIv9, err := NewFacadeV9(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &MachineManagerAPIV8{machineManagerAPIv9}, nil
+}
+
+// NewFacadeV9 creates a new server-side MachineManager API facade.
+func NewFacadeV9(ctx facade.Context) (*MachineManagerAPIV9, error) {
 	machineManagerAPI, err := NewFacade(ctx)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	return &MachineManagerAPIV6{machineManagerAPI}, nil
+	return &MachineManagerAPIV9{machineManagerAPI}, nil
 }
 
 // NewMachineManagerAPI creates a new server-side MachineManager API facade.
@@ -167,11 +212,64 @@ func (mm *MachineManagerAPI) AddMachines(args params.AddMachines) (params.AddMac
 }
 
 func (mm *MachineManagerAPI) addOneMachine(p params.AddMachineParams) (*state.Machine, error) {
+	p, template, err := mm.machineTemplate(p)
+	if err != nil {
+		return nil, err
+	}
+	if p.ContainerType == "" {
+		return mm.st.AddOneMachine(template)
+	}
+	if p.ParentId != "" {
+		return mm.st.AddMachineInsideMachine(template, p.ParentId, p.ContainerType)
+	}
+	return mm.st.AddMachineInsideNewMachine(template, template, p.ContainerType)
+}
+
+// PrecheckMachines checks, without adding anything to the model, whether
+// machines could be added with the supplied parameters. The provider is
+// asked to precheck each new instance, so problems such as unknown
+// instance types or exhausted zones are reported up front.
+func (mm *MachineManagerAPI) PrecheckMachines(args params.AddMachines) (params.PrecheckMachinesResults, error) {
+	results := params.PrecheckMachinesResults{
+		Results: make([]params.PrecheckMachineResult, len(args.MachineParams)),
+	}
+	if err := mm.checkCanRead(); err != nil {
+		return results, err
+	}
+	for i, p := range args.MachineParams {
+		unsupported, err := mm.precheckOneMachine(p)
+		results.Results[i].Unsupported = unsupported
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+func (mm *MachineManagerAPI) precheckOneMachine(p params.AddMachineParams) ([]string, error) {
+	p, template, err := mm.machineTemplate(p)
+	if err != nil {
+		return nil, err
+	}
+	if p.ContainerType != "" && p.ParentId != "" {
+		// The container goes on an existing machine, so there is no
+		// new instance for the provider to check.
+		_, err := mm.st.Machine(p.ParentId)
+		return nil, errors.Trace(err)
+	}
+	// A container in a new machine is hosted by an instance created
+	// from the same template.
+	return mm.st.PrecheckMachine(template)
+}
+
+// machineTemplate validates the given machine parameters and converts
+// them to a state machine template. The parameters are returned with any
+// container placement directive resolved.
+func (mm *MachineManagerAPI) machineTemplate(p params.AddMachineParams) (params.AddMachineParams, state.MachineTemplate, error) {
+	var none state.MachineTemplate
 	if p.ParentId != "" && p.ContainerType == "" {
-		return nil, fmt.Errorf("parent machine specified without container type")
+		return p, none, fmt.Errorf("parent machine specified without container type")
 	}
 	if p.ContainerType != "" && p.Placement != nil {
-		return nil, fmt.Errorf("container type and placement are mutually exclusive")
+		return p, none, fmt.Errorf("container type and placement are mutually exclusive")
 	}
 	if p.Placement != nil {
 		// Extract container type and parent from container placement directives.
@@ -196,11 +294,11 @@ func (mm *MachineManagerAPI) addOneMachine(p params.AddMachineParams) (*state.Ma
 	if p.Series == "" {
 		model, err := mm.st.Model()
 		if err != nil {
-			return nil, errors.Trace(err)
+			return p, none, errors.Trace(err)
 		}
 		conf, err := model.Config()
 		if err != nil {
-			return nil, errors.Trace(err)
+			return p, none, errors.Trace(err)
 		}
 		p.Series = config.PreferredSeries(conf)
 	}
@@ -209,12 +307,12 @@ func (mm *MachineManagerAPI) addOneMachine(p params.AddMachineParams) (*state.Ma
 	if p.Placement != nil {
 		model, err := mm.st.Model()
 		if err != nil {
-			return nil, errors.Trace(err)
+			return p, none, errors.Trace(err)
 		}
 		// For 1.21 we should support both UUID and name, and with 1.22
 		// just support UUID
 		if p.Placement.Scope != model.Name() && p.Placement.Scope != model.UUID() {
-			return nil, fmt.Errorf("invalid model name %q", p.Placement.Scope)
+			return p, none, fmt.Errorf("invalid model name %q", p.Placement.Scope)
 		}
 		placementDirective = p.Placement.Directive
 	}
@@ -222,7 +320,7 @@ func (mm *MachineManagerAPI) addOneMachine(p params.AddMachineParams) (*state.Ma
 	volumes := make([]state.HostVolumeParams, 0, len(p.Disks))
 	for _, cons := range p.Disks {
 		if cons.Count == 0 {
-			return nil, errors.Errorf("invalid volume params: count not specified")
+			return p, none, errors.Errorf("invalid volume params: count not specified")
 		}
 		// Pool and Size are validated by AddMachineX.
 		volumeParams := state.VolumeParams{
@@ -241,12 +339,12 @@ func (mm *MachineManagerAPI) addOneMachine(p params.AddMachineParams) (*state.Ma
 	// space addresses by looking up the spaces.
 	sAddrs, err := params.ToProviderAddresses(p.Addrs...).ToSpaceAddresses(mm.st)
 	if err != nil {
-		return nil, errors.Trace(err)
+		return p, none, errors.Trace(err)
 	}
 
 	jobs, err := common.StateJobs(p.Jobs)
 	if err != nil {
-		return nil, errors.Trace(err)
+		return p, none, errors.Trace(err)
 	}
 	template := state.MachineTemplate{
 		Series:                  p.Series,
@@ -259,13 +357,7 @@ func (mm *MachineManagerAPI) addOneMachine(p params.AddMachineParams) (*state.Ma
 		Addresses:               sAddrs,
 		Placement:               placementDirective,
 	}
-	if p.ContainerType == "" {
-		return mm.st.AddOneMachine(template)
-	}
-	if p.ParentId != "" {
-		return mm.st.AddMachineInsideMachine(template, p.ParentId, p.ContainerType)
-	}
-	return mm.st.AddMachineInsideNewMachine(template, template, p.ContainerType)
+	return p, template, nil
 }
 
 // DestroyMachine removes a set of machines from the model.
@@ -664,6 +756,56 @@ func isSeriesLessThan(series1, series2 string) (bool, error) {
 	return version2 > version1, nil
 }
 
+// ReenlistMachines prepares manually provisioned machines, whose hosts
+// have been reinstalled, to be provisioned again under their existing
+// machine IDs. The nonce of each machine is replaced by the supplied one,
+// so that only an agent installed with the new nonce can log in; units
+// assigned to the machine are then redeployed by that agent.
+func (mm *MachineManagerAPI) ReenlistMachines(args params.ReenlistMachinesArgs) (params.ErrorResults, error) {
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Machines)),
+	}
+	if err := mm.checkCanWrite(); err != nil {
+		return results, err
+	}
+	if err := mm.check.ChangeAllowed(); err != nil {
+		return results, errors.Trace(err)
+	}
+	for i, arg := range args.Machines {
+		results.Results[i].Error = common.ServerError(mm.reenlistMachine(arg))
+	}
+	return results, nil
+}
+
+func (mm *MachineManagerAPI) reenlistMachine(arg params.ReenlistMachineArg) error {
+	machine, err := mm.machineFromTag(arg.MachineTag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if machine.IsManager() {
+		return errors.Errorf("%s is a controller and cannot be re-enlisted", arg.MachineTag)
+	}
+	manual, err := machine.IsManual()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !manual {
+		return errors.Errorf("%s was not manually provisioned and cannot be re-enlisted", arg.MachineTag)
+	}
+	if machineSeries := machine.Series(); arg.Series != machineSeries {
+		return errors.Errorf("%s has series %q, but its host is running %q", arg.MachineTag, machineSeries, arg.Series)
+	}
+	hc, err := machine.HardwareCharacteristics()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if hc.Arch != nil && arg.HardwareCharacteristics.Arch != nil && *hc.Arch != *arg.HardwareCharacteristics.Arch {
+		return errors.Errorf("%s has architecture %q, but its host is %q",
+			arg.MachineTag, *hc.Arch, *arg.HardwareCharacteristics.Arch)
+	}
+	return errors.Trace(machine.ResetManualNonce(arg.Nonce))
+}
+
 // DEPRECATED: UpdateMachineSeries returns an error.
 func (mm *MachineManagerAPIV4) UpdateMachineSeries(_ params.UpdateSeriesArgs) (params.ErrorResults, error) {
 	return params.ErrorResults{
@@ -673,6 +815,15 @@ func (mm *MachineManagerAPIV4) UpdateMachineSeries(_ params.UpdateSeriesArgs) (p
 	}, nil
 }
 
+// ReenlistMachines isn't on the V6 API.
+func (*MachineManagerAPIV6) ReenlistMachines(_, _ struct{}) {}
+
+// MachineUserData isn't on the V7 API.
+func (*MachineManagerAPIV7) MachineUserData(_, _ struct{}) {}
+
+// PrecheckMachines isn't on the V8 API.
+func (*MachineManagerAPIV8) PrecheckMachines(_, _ struct{}) {}
+
 func (mm *MachineManagerAPI) validateSeries(argumentSeries, currentSeries string, machineTag string) error {
 	if argumentSeries == "" {
 		return &params.Error{
@@ -21,6 +21,8 @@ import (
 	"github.com/juju/juju/apiserver/params"
 	apiservertesting "github.com/juju/juju/apiserver/testing"
 	"github.com/juju/juju/cloud"
+	"github.com/juju/juju/core/constraints"
+	"github.com/juju/juju/core/instance"
 	"github.com/juju/juju/core/model"
 	"github.com/juju/juju/core/status"
 	"github.com/juju/juju/environs/context"
@@ -120,6 +122,63 @@ func (s *MachineManagerSuite) TestAddMachines(c *gc.C) {
 	})
 }
 
+func (s *MachineManagerSuite) TestPrecheckMachines(c *gc.C) {
+	s.st.machines["0"] = &mockMachine{}
+	s.st.SetErrors(nil, errors.New("no instance type for you"))
+	results, err := s.api.PrecheckMachines(params.AddMachines{MachineParams: []params.AddMachineParams{{
+		Series:      "trusty",
+		Constraints: constraints.MustParse("virt-type=kvm"),
+		Jobs:        []model.MachineJob{model.JobHostUnits},
+	}, {
+		Series:      "trusty",
+		Constraints: constraints.MustParse("instance-type=huge"),
+		Jobs:        []model.MachineJob{model.JobHostUnits},
+	}, {
+		Series:        "trusty",
+		ContainerType: instance.LXD,
+		ParentId:      "0",
+		Jobs:          []model.MachineJob{model.JobHostUnits},
+	}, {
+		Series:        "trusty",
+		ContainerType: instance.LXD,
+		ParentId:      "42",
+		Jobs:          []model.MachineJob{model.JobHostUnits},
+	}, {
+		ParentId: "0",
+	}}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.PrecheckMachinesResults{
+		Results: []params.PrecheckMachineResult{
+			{Unsupported: []string{"virt-type"}},
+			{Error: &params.Error{Message: "no instance type for you"}},
+			{},
+			{Error: &params.Error{Message: "machine 42 not found", Code: params.CodeNotFound}},
+			{Error: &params.Error{Message: "parent machine specified without container type"}},
+		},
+	})
+	c.Assert(s.st.machineTemplates, jc.DeepEquals, []state.MachineTemplate{{
+		Series:      "trusty",
+		Constraints: constraints.MustParse("virt-type=kvm"),
+		Jobs:        []state.MachineJob{state.JobHostUnits},
+		Volumes:     []state.HostVolumeParams{},
+	}, {
+		Series:      "trusty",
+		Constraints: constraints.MustParse("instance-type=huge"),
+		Jobs:        []state.MachineJob{state.JobHostUnits},
+		Volumes:     []state.HostVolumeParams{},
+	}})
+	c.Assert(s.st.calls, gc.Equals, 0)
+}
+
+func (s *MachineManagerSuite) TestPrecheckMachinesPermissionDenied(c *gc.C) {
+	s.setAPIUser(c, names.NewUserTag("fred"))
+	_, err := s.api.PrecheckMachines(params.AddMachines{MachineParams: []params.AddMachineParams{{
+		Series: "trusty",
+		Jobs:   []model.MachineJob{model.JobHostUnits},
+	}}})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
 func (s *MachineManagerSuite) TestNewMachineManagerAPINonClient(c *gc.C) {
 	tag := names.NewUnitTag("mysql/0")
 	s.authorizer = &apiservertesting.FakeAuthorizer{Tag: tag}
@@ -450,7 +509,13 @@ func (s *MachineManagerSuite) setupUpgradeSeries(c *gc.C) {
 }
 
 func (s *MachineManagerSuite) apiV5() machinemanager.MachineManagerAPIV5 {
-	return machinemanager.MachineManagerAPIV5{MachineManagerAPIV6: &machinemanager.MachineManagerAPIV6{s.api}}
+	return machinemanager.MachineManagerAPIV5{
+		MachineManagerAPIV6: &machinemanager.MachineManagerAPIV6{
+			MachineManagerAPIV7: &machinemanager.MachineManagerAPIV7{
+				MachineManagerAPIV8: &machinemanager.MachineManagerAPIV8{&machinemanager.MachineManagerAPIV9{s.api}},
+			},
+		},
+	}
 }
 
 func (s *MachineManagerSuite) TestUpgradeSeriesValidateOK(c *gc.C) {
@@ -781,6 +846,135 @@ func (s *MachineManagerSuite) TestIsSeriesLessThan(c *gc.C) {
 	}
 }
 
+func (s *MachineManagerSuite) setupReenlist(c *gc.C) {
+	amd64 := "amd64"
+	s.st.machines = map[string]*mockMachine{
+		"0": {series: "bionic", isManager: true},
+		"1": {series: "bionic", isManual: true, hc: &instance.HardwareCharacteristics{Arch: &amd64}},
+		"2": {series: "bionic", hc: &instance.HardwareCharacteristics{Arch: &amd64}},
+	}
+}
+
+func reenlistArgs(machineId, series, arch string) params.ReenlistMachinesArgs {
+	return params.ReenlistMachinesArgs{
+		Machines: []params.ReenlistMachineArg{{
+			MachineTag: names.NewMachineTag(machineId).String(),
+			Nonce:      "manual:10.0.0.1:deadbeef",
+			Series:     series,
+			HardwareCharacteristics: instance.HardwareCharacteristics{
+				Arch: &arch,
+			},
+		}},
+	}
+}
+
+func (s *MachineManagerSuite) TestReenlistMachines(c *gc.C) {
+	s.setupReenlist(c)
+	results, err := s.api.ReenlistMachines(reenlistArgs("1", "bionic", "amd64"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{{}},
+	})
+	mach := s.st.machines["1"]
+	mach.CheckCallNames(c, "IsManager", "IsManual", "Series", "HardwareCharacteristics", "ResetManualNonce")
+	mach.CheckCall(c, 4, "ResetManualNonce", "manual:10.0.0.1:deadbeef")
+}
+
+func (s *MachineManagerSuite) TestReenlistMachinesErrors(c *gc.C) {
+	s.setupReenlist(c)
+	args := params.ReenlistMachinesArgs{}
+	for _, a := range []params.ReenlistMachinesArgs{
+		reenlistArgs("0", "bionic", "amd64"),
+		reenlistArgs("2", "bionic", "amd64"),
+		reenlistArgs("1", "focal", "amd64"),
+		reenlistArgs("1", "bionic", "arm64"),
+		reenlistArgs("42", "bionic", "amd64"),
+	} {
+		args.Machines = append(args.Machines, a.Machines...)
+	}
+	results, err := s.api.ReenlistMachines(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 5)
+	c.Check(results.Results[0].Error, gc.ErrorMatches, "machine-0 is a controller and cannot be re-enlisted")
+	c.Check(results.Results[1].Error, gc.ErrorMatches, "machine-2 was not manually provisioned and cannot be re-enlisted")
+	c.Check(results.Results[2].Error, gc.ErrorMatches, `machine-1 has series "bionic", but its host is running "focal"`)
+	c.Check(results.Results[3].Error, gc.ErrorMatches, `machine-1 has architecture "amd64", but its host is "arm64"`)
+	c.Check(results.Results[4].Error, gc.ErrorMatches, "machine 42 not found")
+	s.st.machines["1"].CheckCallNames(c,
+		"IsManager", "IsManual", "Series",
+		"IsManager", "IsManual", "Series", "HardwareCharacteristics",
+	)
+}
+
+func (s *MachineManagerSuite) TestReenlistMachinesPermissionDenied(c *gc.C) {
+	s.setupReenlist(c)
+	s.setAPIUser(c, names.NewUserTag("fred"))
+	_, err := s.api.ReenlistMachines(reenlistArgs("1", "bionic", "amd64"))
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *MachineManagerSuite) TestReenlistMachinesBlockedChanges(c *gc.C) {
+	s.setupReenlist(c)
+	s.st.blockMsg = "TestReenlistMachinesBlockedChanges"
+	s.st.block = state.ChangeBlock
+	_, err := s.api.ReenlistMachines(reenlistArgs("1", "bionic", "amd64"))
+	c.Assert(params.IsCodeOperationBlocked(err), jc.IsTrue, gc.Commentf("error: %#v", err))
+}
+
+func (s *MachineManagerSuite) setupUserData(c *gc.C) {
+	s.st.machines = map[string]*mockMachine{
+		"0": {series: "bionic"},
+		"1": {series: "bionic", cons: constraints.MustParse("arch=arm64 mem=8G")},
+		"2": {series: "win2012r2", cons: constraints.MustParse("arch=arm64")},
+	}
+	s.st.modelAttrs = map[string]interface{}{
+		"cloudinit-userdata": "packages: [htop]\npreruncmd: [echo pre]\n",
+		"cloudinit-userdata-overlays": `
+- match: arch=arm64
+  userdata:
+    packages: [arm-tools]
+    postruncmd: [echo post]
+- match: mem=16G
+  userdata:
+    packages: [big-tools]
+`[1:],
+	}
+}
+
+func (s *MachineManagerSuite) TestMachineUserData(c *gc.C) {
+	s.setupUserData(c)
+	results, err := s.api.MachineUserData(params.Entities{Entities: []params.Entity{
+		{Tag: "machine-0"}, {Tag: "machine-1"}, {Tag: "machine-2"}, {Tag: "machine-42"}, {Tag: "unit-foo-0"},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 5)
+
+	c.Check(results.Results[0].Error, gc.IsNil)
+	c.Check(results.Results[0].MatchedOverlays, gc.HasLen, 0)
+	c.Check(results.Results[0].UserData, gc.Equals, "packages:\n- htop\npreruncmd:\n- echo pre\n")
+	c.Check(results.Results[0].Preview, jc.Contains, "echo pre")
+
+	c.Check(results.Results[1].Error, gc.IsNil)
+	c.Check(results.Results[1].MatchedOverlays, jc.DeepEquals, []string{"arch=arm64"})
+	c.Check(results.Results[1].UserData, gc.Equals, "packages:\n- htop\n- arm-tools\npostruncmd:\n- echo post\npreruncmd:\n- echo pre\n")
+	c.Check(results.Results[1].Preview, gc.Matches, `(?s).*runcmd:\n- echo pre\n- '# juju: install and start the machine agent'\n- echo post\n.*`)
+
+	// Previews are not supported for windows.
+	c.Check(results.Results[2].Error, gc.IsNil)
+	c.Check(results.Results[2].MatchedOverlays, jc.DeepEquals, []string{"arch=arm64"})
+	c.Check(results.Results[2].Preview, gc.Equals, "")
+
+	c.Check(results.Results[3].Error, gc.ErrorMatches, "machine 42 not found")
+	c.Check(results.Results[4].Error, gc.ErrorMatches, `"unit-foo-0" is not a valid machine tag`)
+}
+
+func (s *MachineManagerSuite) TestMachineUserDataPermissionDenied(c *gc.C) {
+	s.setupUserData(c)
+	s.setAPIUser(c, names.NewUserTag("fred"))
+	_, err := s.api.MachineUserData(params.Entities{Entities: []params.Entity{{Tag: "machine-0"}}})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
 type mockState struct {
 	jtesting.Stub
 	machinemanager.Backend
@@ -790,6 +984,7 @@ type mockState struct {
 	err              error
 	blockMsg         string
 	block            state.BlockType
+	modelAttrs       map[string]interface{}
 
 	unitStorageAttachmentsF func(tag names.UnitTag) ([]state.StorageAttachment, error)
 }
@@ -840,6 +1035,18 @@ func (st *mockState) AddOneMachine(template state.MachineTemplate) (*state.Machi
 	return &m, st.err
 }
 
+func (st *mockState) PrecheckMachine(template state.MachineTemplate) ([]string, error) {
+	st.MethodCall(st, "PrecheckMachine", template)
+	st.machineTemplates = append(st.machineTemplates, template)
+	if err := st.NextErr(); err != nil {
+		return nil, err
+	}
+	if template.Constraints.HasVirtType() {
+		return []string{"virt-type"}, nil
+	}
+	return nil, nil
+}
+
 func (st *mockState) GetBlockForType(t state.BlockType) (state.Block, bool, error) {
 	st.MethodCall(st, "GetBlockForType", t)
 	if st.block == t {
@@ -856,7 +1063,7 @@ func (st *mockState) ModelTag() names.ModelTag {
 
 func (st *mockState) Model() (machinemanager.Model, error) {
 	st.MethodCall(st, "Model")
-	return &mockModel{}, nil
+	return &mockModel{attrs: st.modelAttrs}, nil
 }
 
 func (st *mockState) CloudCredential(tag names.CloudCredentialTag) (state.Credential, error) {
@@ -927,6 +1134,9 @@ type mockMachine struct {
 	unitAgentState status.Status
 	unitState      status.Status
 	isManager      bool
+	isManual       bool
+	hc             *instance.HardwareCharacteristics
+	cons           constraints.Value
 
 	unitsF func() ([]machinemanager.Unit, error)
 }
@@ -1002,6 +1212,26 @@ func (m *mockMachine) IsManager() bool {
 	return m.isManager
 }
 
+func (m *mockMachine) IsManual() (bool, error) {
+	m.MethodCall(m, "IsManual")
+	return m.isManual, m.NextErr()
+}
+
+func (m *mockMachine) HardwareCharacteristics() (*instance.HardwareCharacteristics, error) {
+	m.MethodCall(m, "HardwareCharacteristics")
+	return m.hc, m.NextErr()
+}
+
+func (m *mockMachine) ResetManualNonce(nonce string) error {
+	m.MethodCall(m, "ResetManualNonce", nonce)
+	return m.NextErr()
+}
+
+func (m *mockMachine) Constraints() (constraints.Value, error) {
+	m.MethodCall(m, "Constraints")
+	return m.cons, m.NextErr()
+}
+
 type mockUnit struct {
 	tag         names.UnitTag
 	agentStatus status.Status
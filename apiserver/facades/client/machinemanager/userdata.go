@@ -0,0 +1,77 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machinemanager
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/yaml.v2"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cloudconfig"
+	"github.com/juju/juju/environs/config"
+)
+
+// MachineUserData returns the cloud-init user data used when provisioning
+// each of the given machines: the model's cloudinit-userdata with the
+// overlays matching the machine's constraints merged in, along with a
+// preview of the cloud-config it produces.
+func (mm *MachineManagerAPI) MachineUserData(args params.Entities) (params.MachineUserDataResults, error) {
+	results := params.MachineUserDataResults{
+		Results: make([]params.MachineUserDataResult, len(args.Entities)),
+	}
+	if err := mm.checkCanRead(); err != nil {
+		return results, err
+	}
+	model, err := mm.st.Model()
+	if err != nil {
+		return results, errors.Trace(err)
+	}
+	cfg, err := model.Config()
+	if err != nil {
+		return results, errors.Trace(err)
+	}
+	for i, entity := range args.Entities {
+		result, err := mm.machineUserData(cfg, entity.Tag)
+		if err != nil {
+			results.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		results.Results[i] = result
+	}
+	return results, nil
+}
+
+func (mm *MachineManagerAPI) machineUserData(cfg *config.Config, tag string) (params.MachineUserDataResult, error) {
+	var result params.MachineUserDataResult
+	machine, err := mm.machineFromTag(tag)
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	cons, err := machine.Constraints()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	for _, overlay := range cfg.CloudInitUserDataOverlays() {
+		if overlay.Matches(cons) {
+			result.MatchedOverlays = append(result.MatchedOverlays, overlay.Match.String())
+		}
+	}
+
+	userData := cfg.CloudInitUserDataFor(cons)
+	if len(userData) > 0 {
+		out, err := yaml.Marshal(userData)
+		if err != nil {
+			return result, errors.Trace(err)
+		}
+		result.UserData = string(out)
+	}
+
+	preview, err := cloudconfig.PreviewUserData(machine.Series(), userData)
+	if err != nil && !errors.IsNotSupported(err) {
+		return result, errors.Annotate(err, "previewing user data")
+	}
+	result.Preview = string(preview)
+	return result, nil
+}
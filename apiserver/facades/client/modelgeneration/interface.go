@@ -4,11 +4,15 @@
 package modelgeneration
 
 import (
+	"time"
+
 	"github.com/juju/charm/v7"
 	"github.com/juju/names/v4"
 
 	"github.com/juju/juju/core/cache"
+	"github.com/juju/juju/core/constraints"
 	"github.com/juju/juju/core/settings"
+	"github.com/juju/juju/state"
 )
 
 //go:generate go run github.com/golang/mock/mockgen -package mocks -destination mocks/package_mock.go github.com/juju/juju/apiserver/facades/client/modelgeneration State,Model,Generation,Application,ModelCache
@@ -48,7 +52,11 @@ type Generation interface {
 	AssignedUnits() map[string][]string
 	Commit(string) (int, error)
 	Abort(string) error
+	SetPromotionPolicy(time.Duration, string) error
+	PromotionPolicy() (state.PromotionPolicy, bool)
 	Config() map[string]settings.ItemChanges
+	CharmURLs() map[string]string
+	Constraints() map[string]constraints.Value
 	GenerationId() int
 }
 
@@ -6,12 +6,15 @@ package mocks
 
 import (
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 	charm_v6 "github.com/juju/charm/v7"
 	modelgeneration "github.com/juju/juju/apiserver/facades/client/modelgeneration"
 	cache "github.com/juju/juju/core/cache"
+	constraints "github.com/juju/juju/core/constraints"
 	settings "github.com/juju/juju/core/settings"
+	state "github.com/juju/juju/state"
 	names_v3 "github.com/juju/names/v4"
 )
 
@@ -300,6 +303,20 @@ func (mr *MockGenerationMockRecorder) BranchName() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BranchName", reflect.TypeOf((*MockGeneration)(nil).BranchName))
 }
 
+// CharmURLs mocks base method
+func (m *MockGeneration) CharmURLs() map[string]string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CharmURLs")
+	ret0, _ := ret[0].(map[string]string)
+	return ret0
+}
+
+// CharmURLs indicates an expected call of CharmURLs
+func (mr *MockGenerationMockRecorder) CharmURLs() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CharmURLs", reflect.TypeOf((*MockGeneration)(nil).CharmURLs))
+}
+
 // Commit mocks base method
 func (m *MockGeneration) Commit(arg0 string) (int, error) {
 	m.ctrl.T.Helper()
@@ -357,6 +374,20 @@ func (mr *MockGenerationMockRecorder) Config() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Config", reflect.TypeOf((*MockGeneration)(nil).Config))
 }
 
+// Constraints mocks base method
+func (m *MockGeneration) Constraints() map[string]constraints.Value {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Constraints")
+	ret0, _ := ret[0].(map[string]constraints.Value)
+	return ret0
+}
+
+// Constraints indicates an expected call of Constraints
+func (mr *MockGenerationMockRecorder) Constraints() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Constraints", reflect.TypeOf((*MockGeneration)(nil).Constraints))
+}
+
 // Created mocks base method
 func (m *MockGeneration) Created() int64 {
 	m.ctrl.T.Helper()
@@ -399,6 +430,35 @@ func (mr *MockGenerationMockRecorder) GenerationId() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerationId", reflect.TypeOf((*MockGeneration)(nil).GenerationId))
 }
 
+// PromotionPolicy mocks base method
+func (m *MockGeneration) PromotionPolicy() (state.PromotionPolicy, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PromotionPolicy")
+	ret0, _ := ret[0].(state.PromotionPolicy)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// PromotionPolicy indicates an expected call of PromotionPolicy
+func (mr *MockGenerationMockRecorder) PromotionPolicy() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PromotionPolicy", reflect.TypeOf((*MockGeneration)(nil).PromotionPolicy))
+}
+
+// SetPromotionPolicy mocks base method
+func (m *MockGeneration) SetPromotionPolicy(arg0 time.Duration, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetPromotionPolicy", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetPromotionPolicy indicates an expected call of SetPromotionPolicy
+func (mr *MockGenerationMockRecorder) SetPromotionPolicy(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPromotionPolicy", reflect.TypeOf((*MockGeneration)(nil).SetPromotionPolicy), arg0, arg1)
+}
+
 // MockApplication is a mock of Application interface
 type MockApplication struct {
 	ctrl     *gomock.Controller
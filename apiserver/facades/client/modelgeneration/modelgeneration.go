@@ -31,10 +31,14 @@ type API struct {
 	modelCache        ModelCache
 }
 
-type APIV3 struct {
+type APIV4 struct {
 	*API
 }
 
+type APIV3 struct {
+	*APIV4
+}
+
 type APIV2 struct {
 	*APIV3
 }
@@ -43,8 +47,8 @@ type APIV1 struct {
 	*APIV2
 }
 
-// NewModelGenerationFacadeV4 provides the signature required for facade registration.
-func NewModelGenerationFacadeV4(ctx facade.Context) (*API, error) {
+// NewModelGenerationFacadeV5 provides the signature required for facade registration.
+func NewModelGenerationFacadeV5(ctx facade.Context) (*API, error) {
 	authorizer := ctx.Auth()
 	st := &stateShim{State: ctx.State()}
 	m, err := st.Model()
@@ -58,6 +62,15 @@ func NewModelGenerationFacadeV4(ctx facade.Context) (*API, error) {
 	return NewModelGenerationAPI(st, authorizer, m, &modelCacheShim{Model: mc})
 }
 
+// NewModelGenerationFacadeV4 provides the signature required for facade registration.
+func NewModelGenerationFacadeV4(ctx facade.Context) (*APIV4, error) {
+	v5, err := NewModelGenerationFacadeV5(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &APIV4{v5}, nil
+}
+
 // NewModelGenerationFacadeV3 provides the signature required for facade registration.
 func NewModelGenerationFacadeV3(ctx facade.Context) (*APIV3, error) {
 	v4, err := NewModelGenerationFacadeV4(ctx)
@@ -357,6 +370,8 @@ func (api *API) ListCommits() (params.BranchResults, error) {
 
 func (api *API) oneBranchInfo(branch Generation, detailed bool) (params.Generation, error) {
 	deltas := branch.Config()
+	charmURLs := branch.CharmURLs()
+	appConstraints := branch.Constraints()
 
 	var apps []params.GenerationApplication
 	for appName, tracking := range branch.AssignedUnits() {
@@ -381,9 +396,10 @@ func (api *API) oneBranchInfo(branch Generation, detailed bool) (params.Generati
 		}
 		branchApp.ConfigChanges = deltas[appName].EffectiveChanges(defaults)
 
-		// TODO (manadart 2019-04-12): Charm URL.
-
-		// TODO (manadart 2019-04-12): Resources.
+		branchApp.CharmURL = charmURLs[appName]
+		if cons, ok := appConstraints[appName]; ok {
+			branchApp.Constraints = &cons
+		}
 
 		// Only include unit names if detailed info was requested.
 		if detailed {
@@ -395,10 +411,16 @@ func (api *API) oneBranchInfo(branch Generation, detailed bool) (params.Generati
 		apps = append(apps, branchApp)
 	}
 
+	var promoteAt int64
+	if policy, ok := branch.PromotionPolicy(); ok {
+		promoteAt = policy.Deadline().Unix()
+	}
+
 	return params.Generation{
 		BranchName:   branch.BranchName(),
 		Created:      branch.Created(),
 		CreatedBy:    branch.CreatedBy(),
+		PromoteAt:    promoteAt,
 		Applications: apps,
 	}, nil
 }
@@ -419,6 +441,37 @@ func (api *API) getGenerationCommit(branch Generation) (params.Generation, error
 	}, nil
 }
 
+// SetPromotionPolicy sets the policy for automatically promoting the input
+// branch. The branch is committed once all of the units tracking it have
+// been healthy for the soak time, or aborted with the units reverted if
+// they are not healthy once the soak time has elapsed. A zero soak time
+// cancels the policy.
+func (api *API) SetPromotionPolicy(arg params.BranchPromotionArg) (params.ErrorResult, error) {
+	result := params.ErrorResult{}
+
+	isModelAdmin, err := api.hasAdminAccess()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	if !isModelAdmin && !api.isControllerAdmin {
+		return result, common.ErrPerm
+	}
+
+	branch, err := api.model.Branch(arg.BranchName)
+	if err != nil {
+		result.Error = common.ServerError(err)
+		return result, nil
+	}
+
+	if err := branch.SetPromotionPolicy(arg.SoakTime, api.apiUser.Name()); err != nil {
+		result.Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// SetPromotionPolicy isn't on the V4 API.
+func (*APIV4) SetPromotionPolicy(_, _ struct{}) {}
+
 // HasActiveBranch returns a true result if the input model has an "in-flight"
 // branch matching the input name.
 func (api *API) HasActiveBranch(arg params.BranchArg) (params.BoolResult, error) {
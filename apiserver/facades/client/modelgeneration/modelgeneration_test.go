@@ -4,6 +4,8 @@
 package modelgeneration_test
 
 import (
+	"time"
+
 	"github.com/golang/mock/gomock"
 	"github.com/juju/errors"
 	"github.com/juju/juju/core/cache"
@@ -15,8 +17,10 @@ import (
 	"github.com/juju/juju/apiserver/facades/client/modelgeneration"
 	"github.com/juju/juju/apiserver/facades/client/modelgeneration/mocks"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/constraints"
 	"github.com/juju/juju/core/model"
 	"github.com/juju/juju/core/settings"
+	"github.com/juju/juju/state"
 )
 
 type modelGenerationSuite struct {
@@ -146,6 +150,29 @@ func (s *modelGenerationSuite) TestAbortBranchSuccess(c *gc.C) {
 	c.Assert(result, gc.DeepEquals, params.ErrorResult{Error: nil})
 }
 
+func (s *modelGenerationSuite) TestSetPromotionPolicySuccess(c *gc.C) {
+	defer s.setupModelGenerationAPI(c).Finish()
+	s.expectBranch()
+	s.mockGen.EXPECT().SetPromotionPolicy(time.Hour, s.apiUser).Return(nil)
+
+	result, err := s.api.SetPromotionPolicy(params.BranchPromotionArg{
+		BranchName: s.newBranchName,
+		SoakTime:   time.Hour,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.ErrorResult{Error: nil})
+}
+
+func (s *modelGenerationSuite) TestSetPromotionPolicyError(c *gc.C) {
+	defer s.setupModelGenerationAPI(c).Finish()
+	s.expectBranch()
+	s.mockGen.EXPECT().SetPromotionPolicy(time.Duration(0), s.apiUser).Return(errors.New("branch was already committed"))
+
+	result, err := s.api.SetPromotionPolicy(params.BranchPromotionArg{BranchName: s.newBranchName})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Error, gc.ErrorMatches, "branch was already committed")
+}
+
 func (s *modelGenerationSuite) TestHasActiveBranchTrue(c *gc.C) {
 	defer s.setupModelGenerationAPI(c).Finish()
 	s.expectHasActiveBranch(nil)
@@ -181,10 +208,13 @@ func (s *modelGenerationSuite) testBranchInfo(c *gc.C, branchNames []string, det
 	units := []string{"redis/0", "redis/1", "redis/2"}
 
 	s.expectConfig()
+	s.expectCharmURLs()
+	s.expectConstraints()
 	s.expectBranchName()
 	s.expectAssignedUnits(units[:2])
 	s.expectCreated()
 	s.expectCreatedBy()
+	s.expectPromotionPolicy()
 
 	// Flex the code path based on whether we are getting all branches
 	// or a sub-set.
@@ -208,6 +238,7 @@ func (s *modelGenerationSuite) testBranchInfo(c *gc.C, branchNames []string, det
 	c.Assert(gen.BranchName, gc.Equals, s.newBranchName)
 	c.Assert(gen.Created, gc.Equals, int64(666))
 	c.Assert(gen.CreatedBy, gc.Equals, s.apiUser)
+	c.Assert(gen.PromoteAt, gc.Equals, int64(4266))
 	c.Assert(gen.Applications, gc.HasLen, 1)
 
 	genApp := gen.Applications[0]
@@ -218,6 +249,8 @@ func (s *modelGenerationSuite) testBranchInfo(c *gc.C, branchNames []string, det
 		"databases": 16,
 		"port":      8000,
 	})
+	c.Check(genApp.CharmURL, gc.Equals, "cs:redis-2")
+	c.Check(genApp.Constraints, gc.DeepEquals, &redisCons)
 
 	// Unit lists are only populated when detailed is true.
 	if detailed {
@@ -311,6 +344,24 @@ func (s *modelGenerationSuite) expectCreatedBy() {
 	s.mockGen.EXPECT().CreatedBy().Return(s.apiUser)
 }
 
+func (s *modelGenerationSuite) expectPromotionPolicy() {
+	s.mockGen.EXPECT().PromotionPolicy().Return(state.PromotionPolicy{
+		SoakTime: time.Hour,
+		Started:  time.Unix(666, 0),
+		SetBy:    s.apiUser,
+	}, true)
+}
+
+var redisCons = constraints.MustParse("mem=4G")
+
+func (s *modelGenerationSuite) expectCharmURLs() {
+	s.mockGen.EXPECT().CharmURLs().Return(map[string]string{"redis": "cs:redis-2"})
+}
+
+func (s *modelGenerationSuite) expectConstraints() {
+	s.mockGen.EXPECT().Constraints().Return(map[string]constraints.Value{"redis": redisCons})
+}
+
 func (s *modelGenerationSuite) expectConfig() {
 	s.mockGen.EXPECT().Config().Return(map[string]settings.ItemChanges{"redis": {
 		settings.MakeAddition("password", "added-pass"),
@@ -182,7 +182,7 @@ func (s *modelInfoSuite) setAPIUser(c *gc.C, user names.UserTag) {
 }
 
 func (s *modelInfoSuite) TestModelInfoV7(c *gc.C) {
-	api := &modelmanager.ModelManagerAPIV7{s.modelmanager}
+	api := &modelmanager.ModelManagerAPIV7{&modelmanager.ModelManagerAPIV8{s.modelmanager}}
 
 	results, err := api.ModelInfo(params.Entities{
 		Entities: []params.Entity{{
@@ -647,6 +647,7 @@ type mockState struct {
 	block           state.BlockType
 	migration       *mockMigration
 	modelConfig     *config.Config
+	groupAccess     permission.Access
 
 	modelDetailsForUser func() ([]state.ModelSummary, error)
 }
@@ -702,8 +703,8 @@ func (st *mockState) GetModel(modelUUID string) (common.Model, func() bool, erro
 	return st.model, func() bool { return true }, st.NextErr()
 }
 
-func (st *mockState) ModelUUIDsForUser(user names.UserTag) ([]string, error) {
-	st.MethodCall(st, "ModelUUIDsForUser", user)
+func (st *mockState) ModelUUIDsForUser(user names.UserTag, groups []string) ([]string, error) {
+	st.MethodCall(st, "ModelUUIDsForUser", user, groups)
 	return nil, st.NextErr()
 }
 
@@ -854,13 +855,13 @@ func (st *mockState) UserAccess(tag names.UserTag, target names.Tag) (permission
 	return permission.UserAccess{}, st.NextErr()
 }
 
-func (st *mockState) ModelSummariesForUser(user names.UserTag, all bool) ([]state.ModelSummary, error) {
-	st.MethodCall(st, "ModelSummariesForUser", user, all)
+func (st *mockState) ModelSummariesForUser(user names.UserTag, groups []string, all bool) ([]state.ModelSummary, error) {
+	st.MethodCall(st, "ModelSummariesForUser", user, groups, all)
 	return st.modelDetailsForUser()
 }
 
-func (st *mockState) ModelBasicInfoForUser(user names.UserTag) ([]state.ModelAccessInfo, error) {
-	st.MethodCall(st, "ModelBasicInfoForUser", user)
+func (st *mockState) ModelBasicInfoForUser(user names.UserTag, groups []string) ([]state.ModelAccessInfo, error) {
+	st.MethodCall(st, "ModelBasicInfoForUser", user, groups)
 	return []state.ModelAccessInfo{}, st.NextErr()
 }
 
@@ -874,6 +875,26 @@ func (st *mockState) SetUserAccess(subject names.UserTag, target names.Tag, acce
 	return permission.UserAccess{}, st.NextErr()
 }
 
+func (st *mockState) CreateGroupAccess(group string, target names.Tag, access permission.Access) error {
+	st.MethodCall(st, "CreateGroupAccess", group, target, access)
+	return st.NextErr()
+}
+
+func (st *mockState) GroupAccess(group string, target names.Tag) (permission.Access, error) {
+	st.MethodCall(st, "GroupAccess", group, target)
+	return st.groupAccess, st.NextErr()
+}
+
+func (st *mockState) UpdateGroupAccess(group string, target names.Tag, access permission.Access) error {
+	st.MethodCall(st, "UpdateGroupAccess", group, target, access)
+	return st.NextErr()
+}
+
+func (st *mockState) RemoveGroupAccess(group string, target names.Tag) error {
+	st.MethodCall(st, "RemoveGroupAccess", group, target)
+	return st.NextErr()
+}
+
 func (st *mockState) ModelConfigDefaultValues(cloud string) (config.ModelDefaultAttributes, error) {
 	st.MethodCall(st, "ModelConfigDefaultValues", cloud)
 	return st.cfgDefaults, nil
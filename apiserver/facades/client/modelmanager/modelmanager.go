@@ -40,6 +40,12 @@ import (
 
 var logger = loggo.GetLogger("juju.apiserver.modelmanager")
 
+// ModelManagerV9 defines the methods on the version 9 facade for the
+// modelmanager API endpoint.
+type ModelManagerV9 interface {
+	ModelManagerV8
+}
+
 // ModelManagerV8 defines the methods on the version 8 facade for the
 // modelmanager API endpoint.
 type ModelManagerV8 interface {
@@ -129,10 +135,16 @@ type ModelManagerAPI struct {
 	callContext context.ProviderCallContext
 }
 
+// ModelManagerAPIV8 provides a way to wrap the different calls between
+// version 9 and version 8 of the model manager API
+type ModelManagerAPIV8 struct {
+	*ModelManagerAPI
+}
+
 // ModelManagerAPIV7 provides a way to wrap the different calls between
 // version 8 and version 7 of the model manager API
 type ModelManagerAPIV7 struct {
-	*ModelManagerAPI
+	*ModelManagerAPIV8
 }
 
 // ModelManagerAPIV6 provides a way to wrap the different calls between
@@ -166,7 +178,8 @@ type ModelManagerAPIV2 struct {
 }
 
 var (
-	_ ModelManagerV8 = (*ModelManagerAPI)(nil)
+	_ ModelManagerV9 = (*ModelManagerAPI)(nil)
+	_ ModelManagerV8 = (*ModelManagerAPIV8)(nil)
 	_ ModelManagerV7 = (*ModelManagerAPIV7)(nil)
 	_ ModelManagerV6 = (*ModelManagerAPIV6)(nil)
 	_ ModelManagerV5 = (*ModelManagerAPIV5)(nil)
@@ -175,8 +188,8 @@ var (
 	_ ModelManagerV2 = (*ModelManagerAPIV2)(nil)
 )
 
-// NewFacadeV8 is used for API registration.
-func NewFacadeV8(ctx facade.Context) (*ModelManagerAPI, error) {
+// NewFacadeV9 is used for API registration.
+func NewFacadeV9(ctx facade.Context) (*ModelManagerAPI, error) {
 	st := ctx.State()
 	pool := ctx.StatePool()
 	ctlrSt := pool.SystemState()
@@ -213,6 +226,15 @@ func NewFacadeV8(ctx facade.Context) (*ModelManagerAPI, error) {
 	)
 }
 
+// NewFacadeV8 is used for API registration.
+func NewFacadeV8(ctx facade.Context) (*ModelManagerAPIV8, error) {
+	v9, err := NewFacadeV9(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &ModelManagerAPIV8{v9}, nil
+}
+
 // NewFacadeV7 is used for API registration.
 func NewFacadeV7(ctx facade.Context) (*ModelManagerAPIV7, error) {
 	v8, err := NewFacadeV8(ctx)
@@ -868,7 +890,7 @@ func (m *ModelManagerAPI) ListModelSummaries(req params.ModelSummariesRequest) (
 		return result, errors.Trace(err)
 	}
 
-	modelInfos, err := m.state.ModelSummariesForUser(userTag, req.All)
+	modelInfos, err := m.state.ModelSummariesForUser(userTag, m.authorizer.UserGroups(userTag), req.All)
 	if err != nil {
 		return result, errors.Trace(err)
 	}
@@ -957,7 +979,7 @@ func (m *ModelManagerAPI) ListModels(user params.Entity) (params.UserModelList,
 		return result, errors.Trace(err)
 	}
 
-	modelInfos, err := m.state.ModelBasicInfoForUser(userTag)
+	modelInfos, err := m.state.ModelBasicInfoForUser(userTag, m.authorizer.UserGroups(userTag))
 	if err != nil {
 		return result, errors.Trace(err)
 	}
@@ -1243,6 +1265,17 @@ func (m *ModelManagerAPI) getModelInfo(tag names.ModelTag) (params.ModelInfo, er
 }
 
 // ModifyModelAccess changes the model access granted to users.
+// Version 8 does not support granting access to groups.
+func (m *ModelManagerAPIV8) ModifyModelAccess(args params.ModifyModelAccessRequest) (result params.ErrorResults, _ error) {
+	for _, arg := range args.Changes {
+		if arg.GroupName != "" {
+			return result, errors.NotSupportedf("modifying model access for groups")
+		}
+	}
+	return m.ModelManagerAPI.ModifyModelAccess(args)
+}
+
+// ModifyModelAccess changes the model access granted to users or groups.
 func (m *ModelManagerAPI) ModifyModelAccess(args params.ModifyModelAccessRequest) (result params.ErrorResults, _ error) {
 	result = params.ErrorResults{
 		Results: make([]params.ErrorResult, len(args.Changes)),
@@ -1280,6 +1313,12 @@ func (m *ModelManagerAPI) ModifyModelAccess(args params.ModifyModelAccessRequest
 			continue
 		}
 
+		if arg.GroupName != "" {
+			result.Results[i].Error = common.ServerError(
+				changeModelGroupAccess(m.state, modelTag, arg.GroupName, arg.Action, modelAccess, canModify))
+			continue
+		}
+
 		targetUserTag, err := names.ParseUserTag(arg.UserTag)
 		if err != nil {
 			result.Results[i].Error = common.ServerError(errors.Annotate(err, "could not modify model access"))
@@ -1287,47 +1326,33 @@ func (m *ModelManagerAPI) ModifyModelAccess(args params.ModifyModelAccessRequest
 		}
 
 		result.Results[i].Error = common.ServerError(
-			changeModelAccess(m.state, modelTag, m.apiUser, targetUserTag, arg.Action, modelAccess, m.isAdmin))
+			changeModelAccess(m.state, modelTag, m.apiUser, targetUserTag, arg.Action, modelAccess, canModify))
 	}
 	return result, nil
 }
 
-func userAuthorizedToChangeAccess(st common.ModelManagerBackend, userIsAdmin bool, userTag names.UserTag) error {
-	if userIsAdmin {
-		// Just confirm that the model that has been given is a valid model.
-		_, err := st.Model()
-		if err != nil {
-			return errors.Trace(err)
-		}
-		return nil
-	}
-
-	// Get the current user's ModelUser for the Model to see if the user has
-	// permission to grant or revoke permissions on the model.
-	currentUser, err := st.UserAccess(userTag, st.ModelTag())
-	if err != nil {
-		if errors.IsNotFound(err) {
-			// No, this user doesn't have permission.
-			return common.ErrPerm
-		}
-		return errors.Annotate(err, "could not retrieve user")
-	}
-	if currentUser.Access != permission.AdminAccess {
+// userAuthorizedToChangeAccess checks that the model exists and that
+// the user may change access to it. Whether they may is decided by the
+// authorizer, which also considers the access granted to their groups.
+func userAuthorizedToChangeAccess(st common.ModelManagerBackend, canModify bool) error {
+	if !canModify {
 		return common.ErrPerm
 	}
-	return nil
+	// Just confirm that the model that has been given is a valid model.
+	_, err := st.Model()
+	return errors.Trace(err)
 }
 
 // changeModelAccess performs the requested access grant or revoke action for the
 // specified user on the specified model.
-func changeModelAccess(accessor common.ModelManagerBackend, modelTag names.ModelTag, apiUser, targetUserTag names.UserTag, action params.ModelAction, access permission.Access, userIsAdmin bool) error {
+func changeModelAccess(accessor common.ModelManagerBackend, modelTag names.ModelTag, apiUser, targetUserTag names.UserTag, action params.ModelAction, access permission.Access, canModify bool) error {
 	st, release, err := accessor.GetBackend(modelTag.Id())
 	if err != nil {
 		return errors.Annotate(err, "could not lookup model")
 	}
 	defer release()
 
-	if err := userAuthorizedToChangeAccess(st, userIsAdmin, apiUser); err != nil {
+	if err := userAuthorizedToChangeAccess(st, canModify); err != nil {
 		return errors.Trace(err)
 	}
 
@@ -1392,6 +1417,68 @@ func changeModelAccess(accessor common.ModelManagerBackend, modelTag names.Model
 	}
 }
 
+// changeModelGroupAccess performs the requested access grant or revoke
+// action for the named group on the specified model.
+func changeModelGroupAccess(accessor common.ModelManagerBackend, modelTag names.ModelTag, group string, action params.ModelAction, access permission.Access, canModify bool) error {
+	st, release, err := accessor.GetBackend(modelTag.Id())
+	if err != nil {
+		return errors.Annotate(err, "could not lookup model")
+	}
+	defer release()
+
+	if err := userAuthorizedToChangeAccess(st, canModify); err != nil {
+		return errors.Trace(err)
+	}
+
+	switch action {
+	case params.GrantModelAccess:
+		err := st.CreateGroupAccess(group, modelTag, access)
+		if errors.IsAlreadyExists(err) {
+			groupAccess, err := st.GroupAccess(group, modelTag)
+			if errors.IsNotFound(err) {
+				// Conflicts with prior check, must be inconsistent state.
+				err = txn.ErrExcessiveContention
+			}
+			if err != nil {
+				return errors.Annotate(err, "could not look up model access for group")
+			}
+
+			// Only set access if greater access is being granted.
+			if groupAccess.EqualOrGreaterModelAccessThan(access) {
+				return errors.Errorf("group already has %q access or greater", access)
+			}
+			err = st.UpdateGroupAccess(group, modelTag, access)
+			return errors.Annotate(err, "could not set model access for group")
+		}
+		return errors.Annotate(err, "could not grant model access")
+
+	case params.RevokeModelAccess:
+		var lowered permission.Access
+		switch access {
+		case permission.ReadAccess:
+			// Revoking read access removes all access.
+			err := st.RemoveGroupAccess(group, modelTag)
+			return errors.Annotate(err, "could not revoke model access")
+		case permission.WriteAccess:
+			// Revoking write access sets read-only.
+			lowered = permission.ReadAccess
+		case permission.AdminAccess:
+			// Revoking admin access sets read-write.
+			lowered = permission.WriteAccess
+		default:
+			return errors.Errorf("don't know how to revoke %q access", access)
+		}
+		if _, err := st.GroupAccess(group, modelTag); err != nil {
+			return errors.Annotate(err, "could not look up model access for group")
+		}
+		err := st.UpdateGroupAccess(group, modelTag, lowered)
+		return errors.Annotatef(err, "could not set model access to %s", lowered)
+
+	default:
+		return errors.Errorf("unknown action %q", action)
+	}
+}
+
 // ModelDefaults returns the default config values for the specified clouds.
 func (m *ModelManagerAPI) ModelDefaultsForClouds(args params.Entities) (params.ModelDefaultsResults, error) {
 	result := params.ModelDefaultsResults{}
@@ -690,7 +690,9 @@ func (s *modelManagerSuite) TestDumpModelV2(c *gc.C) {
 				&modelmanager.ModelManagerAPIV5{
 					&modelmanager.ModelManagerAPIV6{
 						&modelmanager.ModelManagerAPIV7{
-							s.api,
+							&modelmanager.ModelManagerAPIV8{
+								s.api,
+							},
 						},
 					},
 				},
@@ -860,7 +862,9 @@ func (s *modelManagerSuite) TestDestroyModelsV3(c *gc.C) {
 			&modelmanager.ModelManagerAPIV5{
 				&modelmanager.ModelManagerAPIV6{
 					&modelmanager.ModelManagerAPIV7{
-						s.api,
+						&modelmanager.ModelManagerAPIV8{
+							s.api,
+						},
 					},
 				},
 			},
@@ -1710,7 +1714,9 @@ func (s *modelManagerSuite) TestModelStatusV2(c *gc.C) {
 				&modelmanager.ModelManagerAPIV5{
 					&modelmanager.ModelManagerAPIV6{
 						&modelmanager.ModelManagerAPIV7{
-							s.api,
+							&modelmanager.ModelManagerAPIV8{
+								s.api,
+							},
 						},
 					},
 				},
@@ -1749,7 +1755,9 @@ func (s *modelManagerSuite) TestModelStatusV3(c *gc.C) {
 			&modelmanager.ModelManagerAPIV5{
 				&modelmanager.ModelManagerAPIV6{
 					&modelmanager.ModelManagerAPIV7{
-						s.api,
+						&modelmanager.ModelManagerAPIV8{
+							s.api,
+						},
 					},
 				},
 			},
@@ -1910,3 +1918,63 @@ func (*fakeProvider) PrepareForCreateEnvironment(controllerUUID string, cfg *con
 func init() {
 	environs.RegisterProvider("fake", &fakeProvider{})
 }
+
+func (s *modelManagerSuite) modifyGroupAccess(c *gc.C, action params.ModelAction, access params.UserAccessPermission) error {
+	s.st.ResetCalls()
+	result, err := s.api.ModifyModelAccess(params.ModifyModelAccessRequest{
+		Changes: []params.ModifyModelAccess{{
+			GroupName: "ops",
+			Action:    action,
+			Access:    access,
+			ModelTag:  coretesting.ModelTag.String(),
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	return result.OneError()
+}
+
+func (s *modelManagerSuite) TestGrantGroupModelAccess(c *gc.C) {
+	err := s.modifyGroupAccess(c, params.GrantModelAccess, params.ModelWriteAccess)
+	c.Assert(err, jc.ErrorIsNil)
+	s.st.CheckCallNames(c, "ControllerTag", "GetBackend", "Model", "CreateGroupAccess")
+	s.st.CheckCall(c, 3, "CreateGroupAccess", "ops", coretesting.ModelTag, permission.WriteAccess)
+}
+
+func (s *modelManagerSuite) TestGrantGroupModelAccessRaisesAccess(c *gc.C) {
+	s.st.groupAccess = permission.ReadAccess
+	s.st.SetErrors(nil, nil, errors.AlreadyExistsf("permission"))
+	err := s.modifyGroupAccess(c, params.GrantModelAccess, params.ModelAdminAccess)
+	c.Assert(err, jc.ErrorIsNil)
+	s.st.CheckCall(c, 5, "UpdateGroupAccess", "ops", coretesting.ModelTag, permission.AdminAccess)
+}
+
+func (s *modelManagerSuite) TestGrantGroupModelAccessAlreadyGranted(c *gc.C) {
+	s.st.groupAccess = permission.AdminAccess
+	s.st.SetErrors(nil, nil, errors.AlreadyExistsf("permission"))
+	err := s.modifyGroupAccess(c, params.GrantModelAccess, params.ModelWriteAccess)
+	c.Assert(err, gc.ErrorMatches, `group already has "write" access or greater`)
+}
+
+func (s *modelManagerSuite) TestRevokeGroupModelAccess(c *gc.C) {
+	s.st.groupAccess = permission.AdminAccess
+	err := s.modifyGroupAccess(c, params.RevokeModelAccess, params.ModelAdminAccess)
+	c.Assert(err, jc.ErrorIsNil)
+	s.st.CheckCall(c, 4, "UpdateGroupAccess", "ops", coretesting.ModelTag, permission.WriteAccess)
+
+	err = s.modifyGroupAccess(c, params.RevokeModelAccess, params.ModelReadAccess)
+	c.Assert(err, jc.ErrorIsNil)
+	s.st.CheckCall(c, 3, "RemoveGroupAccess", "ops", coretesting.ModelTag)
+}
+
+func (s *modelManagerSuite) TestModifyGroupModelAccessV8NotSupported(c *gc.C) {
+	api := &modelmanager.ModelManagerAPIV8{s.api}
+	_, err := api.ModifyModelAccess(params.ModifyModelAccessRequest{
+		Changes: []params.ModifyModelAccess{{
+			GroupName: "ops",
+			Action:    params.GrantModelAccess,
+			Access:    params.ModelReadAccess,
+			ModelTag:  coretesting.ModelTag.String(),
+		}},
+	})
+	c.Assert(err, gc.ErrorMatches, "modifying model access for groups not supported")
+}
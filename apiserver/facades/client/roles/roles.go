@@ -0,0 +1,166 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package roles provides the Roles facade, which manages custom roles
+// and their assignment to model users.
+package roles
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/permission"
+	"github.com/juju/juju/state"
+)
+
+// Backend describes the state methods used by the Roles facade.
+type Backend interface {
+	ControllerTag() names.ControllerTag
+	AddRole(permission.Role) error
+	AllRoles() ([]permission.Role, error)
+	RemoveRole(name string) error
+	SetModelUserRole(names.ModelTag, names.UserTag, string) error
+	RemoveModelUserRole(names.ModelTag, names.UserTag) error
+}
+
+var _ Backend = (*state.State)(nil)
+
+// RolesAPI implements the Roles facade.
+type RolesAPI struct {
+	backend    Backend
+	authorizer facade.Authorizer
+}
+
+// NewFacade creates a new RolesAPI facade.
+func NewFacade(ctx facade.Context) (*RolesAPI, error) {
+	return NewRolesAPI(ctx.State(), ctx.Auth())
+}
+
+// NewRolesAPI creates a new RolesAPI using the given backend.
+func NewRolesAPI(backend Backend, authorizer facade.Authorizer) (*RolesAPI, error) {
+	if !authorizer.AuthClient() {
+		return nil, common.ErrPerm
+	}
+	return &RolesAPI{
+		backend:    backend,
+		authorizer: authorizer,
+	}, nil
+}
+
+func (api *RolesAPI) checkCanAdmin(target names.Tag) error {
+	isSuperuser, err := api.authorizer.HasPermission(permission.SuperuserAccess, api.backend.ControllerTag())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if isSuperuser {
+		return nil
+	}
+	if target.Kind() == names.ModelTagKind {
+		isAdmin, err := api.authorizer.HasPermission(permission.AdminAccess, target)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if isAdmin {
+			return nil
+		}
+	}
+	return common.ErrPerm
+}
+
+// AddRoles adds custom roles to the controller. Only controller
+// superusers may add roles.
+func (api *RolesAPI) AddRoles(args params.Roles) (params.ErrorResults, error) {
+	if err := api.checkCanAdmin(api.backend.ControllerTag()); err != nil {
+		return params.ErrorResults{}, err
+	}
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Roles)),
+	}
+	for i, arg := range args.Roles {
+		access := permission.Access(arg.Access)
+		if access == "" {
+			access = permission.WriteAccess
+		}
+		err := api.backend.AddRole(permission.Role{
+			Name:       arg.Name,
+			Access:     access,
+			Operations: arg.Operations,
+		})
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+// ListRoles returns the custom roles defined in the controller.
+func (api *RolesAPI) ListRoles() (params.Roles, error) {
+	roles, err := api.backend.AllRoles()
+	if err != nil {
+		return params.Roles{}, errors.Trace(err)
+	}
+	result := params.Roles{
+		Roles: make([]params.Role, len(roles)),
+	}
+	for i, role := range roles {
+		result.Roles[i] = params.Role{
+			Name:       role.Name,
+			Access:     string(role.Access),
+			Operations: role.Operations,
+		}
+	}
+	return result, nil
+}
+
+// RemoveRoles removes custom roles from the controller. Only
+// controller superusers may remove roles.
+func (api *RolesAPI) RemoveRoles(args params.RoleNames) (params.ErrorResults, error) {
+	if err := api.checkCanAdmin(api.backend.ControllerTag()); err != nil {
+		return params.ErrorResults{}, err
+	}
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Names)),
+	}
+	for i, name := range args.Names {
+		results.Results[i].Error = common.ServerError(api.backend.RemoveRole(name))
+	}
+	return results, nil
+}
+
+// SetModelUserRoles assigns custom roles to model users, or removes
+// them if no role is given. Only model admins may assign roles, and
+// only to users who have access to the model.
+func (api *RolesAPI) SetModelUserRoles(args params.ModelUserRoles) (params.ErrorResults, error) {
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Roles)),
+	}
+	for i, arg := range args.Roles {
+		results.Results[i].Error = common.ServerError(api.setModelUserRole(arg))
+	}
+	return results, nil
+}
+
+func (api *RolesAPI) setModelUserRole(arg params.ModelUserRole) error {
+	modelTag, err := names.ParseModelTag(arg.ModelTag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	userTag, err := names.ParseUserTag(arg.UserTag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := api.checkCanAdmin(modelTag); err != nil {
+		return err
+	}
+	if arg.Role == "" {
+		return errors.Trace(api.backend.RemoveModelUserRole(modelTag, userTag))
+	}
+	// The user may have access to the model through their groups.
+	if ok, err := api.authorizer.UserHasPermission(userTag, permission.ReadAccess, modelTag); err != nil {
+		return errors.Trace(err)
+	} else if !ok {
+		return errors.Errorf("user %q does not have access to the model", userTag.Id())
+	}
+	return errors.Trace(api.backend.SetModelUserRole(modelTag, userTag, arg.Role))
+}
@@ -0,0 +1,178 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package roles_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+	jtesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facades/client/roles"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/core/permission"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type rolesSuite struct {
+	backend  *mockBackend
+	modelTag names.ModelTag
+}
+
+var _ = gc.Suite(&rolesSuite{})
+
+func (s *rolesSuite) SetUpTest(c *gc.C) {
+	s.backend = &mockBackend{
+		roles: []permission.Role{{
+			Name:       "app-team",
+			Access:     permission.WriteAccess,
+			Operations: []string{"run-action"},
+		}},
+	}
+	s.modelTag = names.NewModelTag(coretesting.ModelTag.Id())
+}
+
+func (s *rolesSuite) newAPI(c *gc.C, user string) *roles.RolesAPI {
+	api, err := roles.NewRolesAPI(s.backend, apiservertesting.FakeAuthorizer{
+		Tag: names.NewUserTag(user),
+		// Only fred, and the caller, have access to the model.
+		AdminTag: names.NewUserTag("fred"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	return api
+}
+
+func (s *rolesSuite) TestNewRolesAPINotClient(c *gc.C) {
+	_, err := roles.NewRolesAPI(s.backend, apiservertesting.FakeAuthorizer{
+		Tag: names.NewMachineTag("0"),
+	})
+	c.Assert(err, gc.Equals, common.ErrPerm)
+}
+
+func (s *rolesSuite) TestAddRoles(c *gc.C) {
+	s.backend.SetErrors(nil, errors.AlreadyExistsf("role %q", "app-team"))
+	results, err := s.newAPI(c, "superuser").AddRoles(params.Roles{
+		Roles: []params.Role{
+			{Name: "ops", Access: "read", Operations: []string{"exec", "remove"}},
+			// Older clients do not send an access level.
+			{Name: "app-team", Operations: []string{"run-action"}},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 2)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, `role "app-team" already exists`)
+	s.backend.CheckCalls(c, []jtesting.StubCall{
+		{"AddRole", []interface{}{permission.Role{Name: "ops", Access: permission.ReadAccess, Operations: []string{"exec", "remove"}}}},
+		{"AddRole", []interface{}{permission.Role{Name: "app-team", Access: permission.WriteAccess, Operations: []string{"run-action"}}}},
+	})
+}
+
+func (s *rolesSuite) TestAddRolesNotSuperuser(c *gc.C) {
+	_, err := s.newAPI(c, "admin"+s.modelTag.String()).AddRoles(params.Roles{
+		Roles: []params.Role{{Name: "ops", Operations: []string{"exec"}}},
+	})
+	c.Assert(err, gc.Equals, common.ErrPerm)
+	s.backend.CheckNoCalls(c)
+}
+
+func (s *rolesSuite) TestListRoles(c *gc.C) {
+	result, err := s.newAPI(c, "bob").ListRoles()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.Roles{
+		Roles: []params.Role{{Name: "app-team", Access: "write", Operations: []string{"run-action"}}},
+	})
+}
+
+func (s *rolesSuite) TestRemoveRoles(c *gc.C) {
+	results, err := s.newAPI(c, "superuser").RemoveRoles(params.RoleNames{Names: []string{"app-team"}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.OneError(), jc.ErrorIsNil)
+	s.backend.CheckCalls(c, []jtesting.StubCall{{"RemoveRole", []interface{}{"app-team"}}})
+
+	_, err = s.newAPI(c, "bob").RemoveRoles(params.RoleNames{Names: []string{"app-team"}})
+	c.Assert(err, gc.Equals, common.ErrPerm)
+}
+
+func (s *rolesSuite) TestSetModelUserRoles(c *gc.C) {
+	results, err := s.newAPI(c, "admin"+s.modelTag.String()).SetModelUserRoles(params.ModelUserRoles{
+		Roles: []params.ModelUserRole{{
+			ModelTag: s.modelTag.String(),
+			UserTag:  "user-fred",
+			Role:     "app-team",
+		}, {
+			ModelTag: s.modelTag.String(),
+			UserTag:  "user-mary",
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Combine(), jc.ErrorIsNil)
+	s.backend.CheckCalls(c, []jtesting.StubCall{
+		{"SetModelUserRole", []interface{}{s.modelTag, names.NewUserTag("fred"), "app-team"}},
+		{"RemoveModelUserRole", []interface{}{s.modelTag, names.NewUserTag("mary")}},
+	})
+}
+
+func (s *rolesSuite) TestSetModelUserRolesNoModelAccess(c *gc.C) {
+	results, err := s.newAPI(c, "superuser").SetModelUserRoles(params.ModelUserRoles{
+		Roles: []params.ModelUserRole{{
+			ModelTag: s.modelTag.String(),
+			UserTag:  "user-mary",
+			Role:     "app-team",
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.OneError(), gc.ErrorMatches, `user "mary" does not have access to the model`)
+	s.backend.CheckNoCalls(c)
+}
+
+func (s *rolesSuite) TestSetModelUserRolesNotAdmin(c *gc.C) {
+	results, err := s.newAPI(c, "write"+s.modelTag.String()).SetModelUserRoles(params.ModelUserRoles{
+		Roles: []params.ModelUserRole{{
+			ModelTag: s.modelTag.String(),
+			UserTag:  "user-bob",
+			Role:     "app-team",
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.OneError(), gc.ErrorMatches, "permission denied")
+	s.backend.CheckNoCalls(c)
+}
+
+type mockBackend struct {
+	jtesting.Stub
+	roles []permission.Role
+}
+
+func (b *mockBackend) ControllerTag() names.ControllerTag {
+	return coretesting.ControllerTag
+}
+
+func (b *mockBackend) AddRole(role permission.Role) error {
+	b.MethodCall(b, "AddRole", role)
+	return b.NextErr()
+}
+
+func (b *mockBackend) AllRoles() ([]permission.Role, error) {
+	b.MethodCall(b, "AllRoles")
+	return b.roles, b.NextErr()
+}
+
+func (b *mockBackend) RemoveRole(name string) error {
+	b.MethodCall(b, "RemoveRole", name)
+	return b.NextErr()
+}
+
+func (b *mockBackend) SetModelUserRole(model names.ModelTag, user names.UserTag, role string) error {
+	b.MethodCall(b, "SetModelUserRole", model, user, role)
+	return b.NextErr()
+}
+
+func (b *mockBackend) RemoveModelUserRole(model names.ModelTag, user names.UserTag) error {
+	b.MethodCall(b, "RemoveModelUserRole", model, user)
+	return b.NextErr()
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/juju/juju/apiserver/facade"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/core/permission"
+	"github.com/juju/juju/core/totp"
 	"github.com/juju/juju/state"
 )
 
@@ -58,6 +59,26 @@ func NewUserManagerAPI(
 	}, nil
 }
 
+// UserManagerAPIV2 implements the user manager interface, version 2,
+// which does not support multi-factor authentication enrolment.
+type UserManagerAPIV2 struct {
+	*UserManagerAPI
+}
+
+// NewUserManagerAPIV2 provides the signature required for facade
+// registration of versions 1 and 2.
+func NewUserManagerAPIV2(
+	st *state.State,
+	resources facade.Resources,
+	authorizer facade.Authorizer,
+) (*UserManagerAPIV2, error) {
+	api, err := NewUserManagerAPI(st, resources, authorizer)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &UserManagerAPIV2{api}, nil
+}
+
 func (api *UserManagerAPI) hasControllerAdminAccess() (bool, error) {
 	isAdmin, err := api.authorizer.HasPermission(permission.SuperuserAccess, api.state.ControllerTag())
 	if errors.IsNotFound(err) {
@@ -90,10 +111,19 @@ func (api *UserManagerAPI) AddUser(args params.AddUsers) (params.AddUserResults,
 		return result, common.ErrPerm
 	}
 
+	controllerConfig, err := api.state.ControllerConfig()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+
 	for i, arg := range args.Users {
 		var user *state.User
 		var err error
 		if arg.Password != "" {
+			if err := common.ValidatePassword(controllerConfig, arg.Password); err != nil {
+				result.Results[i].Error = common.ServerError(err)
+				continue
+			}
 			user, err = api.state.AddUser(arg.Username, arg.DisplayName, arg.Password, api.apiUser.Id())
 		} else {
 			user, err = api.state.AddUserWithSecretKey(arg.Username, arg.DisplayName, api.apiUser.Id())
@@ -287,6 +317,19 @@ func (api *UserManagerAPI) UserInfo(request params.UserInfoRequest) (params.User
 				Disabled:       user.IsDisabled(),
 			},
 		}
+		if user.MFAEnabled() {
+			result.Result.MFAEnabled = true
+			result.Result.MFARecoveryCodes = user.MFARecoveryCodesRemaining()
+		}
+		result.Result.FailedLogins = user.FailedLogins()
+		if lockedUntil, locked := user.LockedUntil(); locked {
+			result.Result.LockedUntil = &lockedUntil
+		}
+		if expires, ok, err := user.PasswordExpires(); err != nil {
+			logger.Debugf("error getting password expiry: %v", err)
+		} else if ok {
+			result.Result.PasswordExpires = &expires
+		}
 		if user.IsDisabled() {
 			// disabled users have no access to the controller.
 			result.Result.Access = string(permission.NoAccess)
@@ -356,17 +399,29 @@ func (api *UserManagerAPI) SetPassword(args params.EntityPasswords) (params.Erro
 		return result, nil
 	}
 
+	// Codes are only accepted once, so remember the outcome of
+	// checking each one for the rest of the changes.
+	checked := make(map[string]error)
+	checkMFACode := func(code string) error {
+		err, ok := checked[code]
+		if !ok {
+			err = api.checkMFACode(code)
+			checked[code] = err
+		}
+		return err
+	}
+
 	// Create the results list to populate.
 	result.Results = make([]params.ErrorResult, len(args.Changes))
 	for i, arg := range args.Changes {
-		if err := api.setPassword(arg); err != nil {
+		if err := api.setPassword(arg, checkMFACode); err != nil {
 			result.Results[i].Error = common.ServerError(err)
 		}
 	}
 	return result, nil
 }
 
-func (api *UserManagerAPI) setPassword(arg params.EntityPassword) error {
+func (api *UserManagerAPI) setPassword(arg params.EntityPassword, checkMFACode func(string) error) error {
 	user, err := api.getUser(arg.Tag)
 	if err != nil {
 		return errors.Trace(err)
@@ -383,6 +438,16 @@ func (api *UserManagerAPI) setPassword(arg params.EntityPassword) error {
 	if arg.Password == "" {
 		return errors.New("cannot use an empty password")
 	}
+	controllerConfig, err := api.state.ControllerConfig()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := common.ValidatePassword(controllerConfig, arg.Password); err != nil {
+		return errors.Trace(err)
+	}
+	if err := checkMFACode(arg.MFACode); err != nil {
+		return errors.Trace(err)
+	}
 	if err := user.SetPassword(arg.Password); err != nil {
 		return errors.Annotate(err, "failed to set password")
 	}
@@ -430,3 +495,152 @@ func (api *UserManagerAPI) ResetPassword(args params.Entities) (params.AddUserRe
 	}
 	return result, nil
 }
+
+// checkMFACode checks the multi-factor authentication code given by
+// the authenticated user, if they are enrolled.
+func (api *UserManagerAPI) checkMFACode(code string) error {
+	if !api.apiUser.IsLocal() {
+		return nil
+	}
+	user, err := api.state.User(api.apiUser)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !user.MFAEnabled() {
+		return nil
+	}
+	if code == "" {
+		return common.ErrMFARequired
+	}
+	if err := user.CheckMFACode(code); err != nil {
+		if errors.IsUnauthorized(err) {
+			return common.ErrBadMFACode
+		}
+		return errors.Trace(err)
+	}
+	return nil
+}
+
+// authUser returns the authenticated user, who must be local.
+func (api *UserManagerAPI) authUser() (*state.User, error) {
+	if !api.apiUser.IsLocal() {
+		return nil, errors.NotSupportedf("multi-factor authentication for external user %q", api.apiUser.Id())
+	}
+	user, err := api.state.User(api.apiUser)
+	return user, errors.Trace(err)
+}
+
+// BeginMFAEnrolment generates a new shared secret for the authenticated
+// user's authenticator app. Multi-factor authentication is enforced once
+// the user confirms the enrolment with ConfirmMFAEnrolment.
+func (api *UserManagerAPI) BeginMFAEnrolment() (params.MFAEnrolment, error) {
+	var result params.MFAEnrolment
+	if err := api.check.ChangeAllowed(); err != nil {
+		return result, errors.Trace(err)
+	}
+	user, err := api.authUser()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	controllerConfig, err := api.state.ControllerConfig()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	secret, err := user.BeginMFAEnrolment()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	issuer := "Juju"
+	if name := controllerConfig.ControllerName(); name != "" {
+		issuer += " " + name
+	}
+	return params.MFAEnrolment{
+		Secret: secret,
+		URI:    totp.URI(issuer, user.Name(), secret),
+	}, nil
+}
+
+// ConfirmMFAEnrolment checks a code generated by the authenticated
+// user's authenticator app from the secret returned by
+// BeginMFAEnrolment, and enables multi-factor authentication for them.
+// It returns the user's recovery codes, which cannot be retrieved again.
+func (api *UserManagerAPI) ConfirmMFAEnrolment(arg params.MFACode) (params.MFARecoveryCodes, error) {
+	var result params.MFARecoveryCodes
+	if err := api.check.ChangeAllowed(); err != nil {
+		return result, errors.Trace(err)
+	}
+	user, err := api.authUser()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	codes, err := user.ConfirmMFAEnrolment(arg.Code)
+	if errors.IsUnauthorized(err) {
+		return result, common.ErrBadMFACode
+	} else if err != nil {
+		return result, errors.Trace(err)
+	}
+	result.Codes = codes
+	return result, nil
+}
+
+// DisableMFA removes the authenticated user's multi-factor
+// authentication enrolment. They must present a current code.
+func (api *UserManagerAPI) DisableMFA(arg params.MFACode) error {
+	if err := api.check.ChangeAllowed(); err != nil {
+		return errors.Trace(err)
+	}
+	user, err := api.authUser()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !user.MFAEnabled() {
+		return errors.NotFoundf("multi-factor authentication enrolment for user %q", user.Name())
+	}
+	if err := api.checkMFACode(arg.Code); err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(user.ResetMFA())
+}
+
+// ResetMFA removes the multi-factor authentication enrolment of the
+// given users, for when they have lost both their authenticator and
+// their recovery codes. Only controller superusers may reset other
+// users; users cannot reset themselves.
+func (api *UserManagerAPI) ResetMFA(args params.Entities) (params.ErrorResults, error) {
+	var result params.ErrorResults
+	if err := api.check.ChangeAllowed(); err != nil {
+		return result, errors.Trace(err)
+	}
+	isSuperUser, err := api.hasControllerAdminAccess()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	result.Results = make([]params.ErrorResult, len(args.Entities))
+	for i, arg := range args.Entities {
+		user, err := api.getUser(arg.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		if !isSuperUser || api.apiUser == user.UserTag() {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		if err := user.ResetMFA(); err != nil {
+			result.Results[i].Error = common.ServerError(err)
+		}
+	}
+	return result, nil
+}
+
+// BeginMFAEnrolment isn't on the v2 API.
+func (*UserManagerAPIV2) BeginMFAEnrolment(_, _ struct{}) {}
+
+// ConfirmMFAEnrolment isn't on the v2 API.
+func (*UserManagerAPIV2) ConfirmMFAEnrolment(_, _ struct{}) {}
+
+// DisableMFA isn't on the v2 API.
+func (*UserManagerAPIV2) DisableMFA(_, _ struct{}) {}
+
+// ResetMFA isn't on the v2 API.
+func (*UserManagerAPIV2) ResetMFA(_, _ struct{}) {}
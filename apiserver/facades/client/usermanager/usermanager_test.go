@@ -19,6 +19,7 @@ import (
 	"github.com/juju/juju/apiserver/params"
 	apiservertesting "github.com/juju/juju/apiserver/testing"
 	"github.com/juju/juju/core/permission"
+	"github.com/juju/juju/core/totp"
 	jujutesting "github.com/juju/juju/juju/testing"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/testing/factory"
@@ -564,6 +565,59 @@ func (s *userManagerSuite) TestSetPassword(c *gc.C) {
 	c.Assert(alex.PasswordValid("new-password"), jc.IsTrue)
 }
 
+func (s *userManagerSuite) TestSetPasswordPolicy(c *gc.C) {
+	err := s.State.UpdateControllerConfig(map[string]interface{}{
+		"password-min-length": 12,
+	}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	alex := s.Factory.MakeUser(c, &factory.UserParams{Name: "alex", NoModelUser: true})
+
+	args := params.EntityPasswords{
+		Changes: []params.EntityPassword{{
+			Tag:      alex.Tag().String(),
+			Password: "too-short",
+		}, {
+			Tag:      alex.Tag().String(),
+			Password: "long-enough-password",
+		}}}
+	results, err := s.usermanager.SetPassword(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 2)
+	c.Assert(results.Results[0].Error, gc.ErrorMatches, "password must be at least 12 characters long")
+	c.Assert(results.Results[1].Error, gc.IsNil)
+
+	err = alex.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(alex.PasswordValid("long-enough-password"), jc.IsTrue)
+}
+
+func (s *userManagerSuite) TestUserInfoLockedOut(c *gc.C) {
+	err := s.State.UpdateControllerConfig(map[string]interface{}{
+		"login-max-failed-attempts": 1,
+		"password-max-age":          "720h",
+	}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	userFoo := s.Factory.MakeUser(c, &factory.UserParams{Name: "foobar"})
+	c.Assert(userFoo.RecordFailedLogin(), jc.ErrorIsNil)
+	lockedUntil, locked := userFoo.LockedUntil()
+	c.Assert(locked, jc.IsTrue)
+	expires, _, err := userFoo.PasswordExpires()
+	c.Assert(err, jc.ErrorIsNil)
+
+	results, err := s.usermanager.UserInfo(params.UserInfoRequest{
+		Entities: []params.Entity{{Tag: userFoo.Tag().String()}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	info := results.Results[0].Result
+	c.Assert(info, gc.NotNil)
+	c.Assert(info.FailedLogins, gc.Equals, 1)
+	c.Assert(info.LockedUntil, gc.NotNil)
+	c.Assert(*info.LockedUntil, gc.Equals, lockedUntil)
+	c.Assert(info.PasswordExpires, gc.NotNil)
+	c.Assert(*info.PasswordExpires, gc.Equals, expires)
+}
+
 func (s *userManagerSuite) TestBlockSetPassword(c *gc.C) {
 	alex := s.Factory.MakeUser(c, &factory.UserParams{Name: "alex", NoModelUser: true})
 
@@ -992,3 +1046,123 @@ func (s *userManagerSuite) TestResetPasswordEmpty(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(results.Results, gc.HasLen, 0)
 }
+
+func (s *userManagerSuite) enrolMFA(c *gc.C, api *usermanager.UserManagerAPI) (string, []string) {
+	enrolment, err := api.BeginMFAEnrolment()
+	c.Assert(err, jc.ErrorIsNil)
+	code, err := totp.Code(enrolment.Secret, time.Now())
+	c.Assert(err, jc.ErrorIsNil)
+	recovery, err := api.ConfirmMFAEnrolment(params.MFACode{Code: code})
+	c.Assert(err, jc.ErrorIsNil)
+	return enrolment.Secret, recovery.Codes
+}
+
+// nextMFACode returns a code that has not been used yet, as the code
+// used to confirm enrolment is not accepted again.
+func nextMFACode(c *gc.C, secret string) string {
+	code, err := totp.Code(secret, time.Now().Add(totp.Period))
+	c.Assert(err, jc.ErrorIsNil)
+	return code
+}
+
+func (s *userManagerSuite) TestMFAEnrolment(c *gc.C) {
+	alex := s.Factory.MakeUser(c, &factory.UserParams{Name: "alex", NoModelUser: true})
+	api, err := usermanager.NewUserManagerAPI(
+		s.State, s.resources, apiservertesting.FakeAuthorizer{Tag: alex.Tag()})
+	c.Assert(err, jc.ErrorIsNil)
+
+	enrolment, err := api.BeginMFAEnrolment()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(enrolment.URI, gc.Matches, "otpauth://totp/Juju.*:alex\\?issuer=.*&secret="+enrolment.Secret)
+
+	_, err = api.ConfirmMFAEnrolment(params.MFACode{Code: "not-a-code"})
+	c.Assert(err, gc.Equals, common.ErrBadMFACode)
+
+	code, err := totp.Code(enrolment.Secret, time.Now())
+	c.Assert(err, jc.ErrorIsNil)
+	recovery, err := api.ConfirmMFAEnrolment(params.MFACode{Code: code})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(recovery.Codes, gc.HasLen, state.MFARecoveryCodeCount)
+
+	results, err := api.UserInfo(params.UserInfoRequest{
+		Entities: []params.Entity{{Tag: alex.Tag().String()}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results[0].Result.MFAEnabled, jc.IsTrue)
+	c.Assert(results.Results[0].Result.MFARecoveryCodes, gc.Equals, state.MFARecoveryCodeCount)
+}
+
+func (s *userManagerSuite) TestSetPasswordRequiresMFACode(c *gc.C) {
+	alex := s.Factory.MakeUser(c, &factory.UserParams{Name: "alex", NoModelUser: true})
+	api, err := usermanager.NewUserManagerAPI(
+		s.State, s.resources, apiservertesting.FakeAuthorizer{Tag: alex.Tag()})
+	c.Assert(err, jc.ErrorIsNil)
+	secret, _ := s.enrolMFA(c, api)
+
+	change := params.EntityPassword{
+		Tag:      alex.Tag().String(),
+		Password: "new-password",
+	}
+	results, err := api.SetPassword(params.EntityPasswords{Changes: []params.EntityPassword{change}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.OneError(), jc.Satisfies, params.IsCodeMFARequired)
+
+	change.MFACode = "123"
+	results, err = api.SetPassword(params.EntityPasswords{Changes: []params.EntityPassword{change}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.OneError(), gc.ErrorMatches, "invalid multi-factor authentication code")
+
+	change.MFACode = nextMFACode(c, secret)
+	results, err = api.SetPassword(params.EntityPasswords{Changes: []params.EntityPassword{change}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.OneError(), jc.ErrorIsNil)
+
+	err = alex.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(alex.PasswordValid("new-password"), jc.IsTrue)
+}
+
+func (s *userManagerSuite) TestDisableMFA(c *gc.C) {
+	alex := s.Factory.MakeUser(c, &factory.UserParams{Name: "alex", NoModelUser: true})
+	api, err := usermanager.NewUserManagerAPI(
+		s.State, s.resources, apiservertesting.FakeAuthorizer{Tag: alex.Tag()})
+	c.Assert(err, jc.ErrorIsNil)
+	_, recovery := s.enrolMFA(c, api)
+
+	err = api.DisableMFA(params.MFACode{})
+	c.Assert(err, gc.Equals, common.ErrMFARequired)
+
+	err = api.DisableMFA(params.MFACode{Code: recovery[0]})
+	c.Assert(err, jc.ErrorIsNil)
+	err = alex.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(alex.MFAEnabled(), jc.IsFalse)
+
+	err = api.DisableMFA(params.MFACode{Code: recovery[1]})
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *userManagerSuite) TestResetMFA(c *gc.C) {
+	alex := s.Factory.MakeUser(c, &factory.UserParams{Name: "alex", NoModelUser: true})
+	api, err := usermanager.NewUserManagerAPI(
+		s.State, s.resources, apiservertesting.FakeAuthorizer{Tag: alex.Tag()})
+	c.Assert(err, jc.ErrorIsNil)
+	s.enrolMFA(c, api)
+
+	// Users cannot reset themselves, even if they are superusers.
+	results, err := api.ResetMFA(params.Entities{Entities: []params.Entity{{Tag: alex.Tag().String()}}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.OneError(), gc.ErrorMatches, "permission denied")
+
+	results, err = s.usermanager.ResetMFA(params.Entities{Entities: []params.Entity{
+		{Tag: alex.Tag().String()},
+		{Tag: s.AdminUserTag(c).String()},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, "permission denied")
+
+	err = alex.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(alex.MFAEnabled(), jc.IsFalse)
+}
@@ -0,0 +1,68 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package charmrevisionupdater_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"time"
+
+	"github.com/juju/charm/v7"
+	csparams "github.com/juju/charmrepo/v5/csclient/params"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/facades/controller/charmrevisionupdater"
+	"github.com/juju/juju/charmrepository"
+	"github.com/juju/juju/charmstore"
+)
+
+type repositorySuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&repositorySuite{})
+
+func (s *repositorySuite) TestLatestRepositoryCharmInfo(c *gc.C) {
+	dir := c.MkDir()
+	err := ioutil.WriteFile(filepath.Join(dir, charmrepository.IndexFile), []byte(`
+charms:
+  wordpress:
+    channels:
+      stable: 16
+      edge: 17
+    revisions:
+      16:
+        series: [bionic]
+        archive: wordpress-16.charm
+      17:
+        series: [bionic]
+        archive: wordpress-17.charm
+`), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	repo, err := charmrepository.Open(dir)
+	c.Assert(err, jc.ErrorIsNil)
+
+	now := time.Now().UTC()
+	results := charmrevisionupdater.LatestRepositoryCharmInfo(repo, []charmstore.CharmID{{
+		URL: charm.MustParseURL("cs:bionic/wordpress-10"),
+	}, {
+		URL:     charm.MustParseURL("cs:bionic/wordpress-16"),
+		Channel: csparams.EdgeChannel,
+	}, {
+		URL: charm.MustParseURL("cs:bionic/mysql-1"),
+	}}, now)
+	c.Assert(results, gc.HasLen, 3)
+
+	c.Check(results[0].Error, jc.ErrorIsNil)
+	c.Check(results[0].CharmInfo, jc.DeepEquals, charmstore.CharmInfo{
+		OriginalURL:    charm.MustParseURL("cs:bionic/wordpress-10"),
+		Timestamp:      now,
+		LatestRevision: 16,
+	})
+	c.Check(results[1].Error, jc.ErrorIsNil)
+	c.Check(results[1].LatestURL(), jc.DeepEquals, charm.MustParseURL("cs:bionic/wordpress-17"))
+	c.Check(results[2].Error, gc.ErrorMatches, `charm "mysql" in charm repository not found`)
+}
@@ -6,7 +6,10 @@ package charmrevisionupdater
 import (
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/juju/charm/v7"
+	csparams "github.com/juju/charmrepo/v5/csclient/params"
 	"github.com/juju/collections/set"
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
@@ -14,6 +17,7 @@ import (
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/facade"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/charmrepository"
 	"github.com/juju/juju/charmstore"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/version"
@@ -119,11 +123,6 @@ func retrieveLatestCharmInfo(st *state.State) ([]latestCharmInfo, error) {
 		return nil, err
 	}
 
-	client, err := NewCharmStoreClient(st)
-	if err != nil {
-		return nil, errors.Trace(err)
-	}
-
 	var charms []charmstore.CharmID
 	var resultsIndexedApps []*state.Application
 	for _, application := range applications {
@@ -149,6 +148,57 @@ func retrieveLatestCharmInfo(st *state.State) ([]latestCharmInfo, error) {
 		resultsIndexedApps = append(resultsIndexedApps, application)
 	}
 
+	results, err := latestCharmStoreInfo(st, model, charms)
+	if err != nil {
+		return nil, err
+	}
+
+	var latest []latestCharmInfo
+	for i, result := range results {
+		if result.Error != nil {
+			logger.Errorf("retrieving charm info for %s: %v", charms[i].URL, result.Error)
+			continue
+		}
+		application := resultsIndexedApps[i]
+		latest = append(latest, latestCharmInfo{
+			CharmInfo:   result.CharmInfo,
+			application: application,
+		})
+	}
+	return latest, nil
+}
+
+// NewCharmRepository opens the charm repository at the given location.
+// Exported so we can change it during testing.
+var NewCharmRepository = func(location string) (CharmResolver, error) {
+	return charmrepository.Open(location)
+}
+
+// CharmResolver resolves charm URLs against a charm repository.
+type CharmResolver interface {
+	ResolveWithPreferredChannel(*charm.URL, csparams.Channel) (*charm.URL, csparams.Channel, []string, error)
+}
+
+// latestCharmStoreInfo returns the latest revision information for the
+// given charms, from the model's charm repository if one is configured
+// and from the charm store otherwise.
+func latestCharmStoreInfo(st *state.State, model *state.Model, charms []charmstore.CharmID) ([]charmstore.CharmInfoResult, error) {
+	cfg, err := model.ModelConfig()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if location, ok := cfg.CharmRepositoryURL(); ok {
+		repo, err := NewCharmRepository(location)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return latestRepositoryCharmInfo(repo, charms, time.Now().UTC()), nil
+	}
+
+	client, err := NewCharmStoreClient(st)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
 	metadata := map[string]string{
 		"environment_uuid":   model.UUID(),
 		"model_uuid":         model.UUID(),
@@ -164,24 +214,26 @@ func retrieveLatestCharmInfo(st *state.State) ([]latestCharmInfo, error) {
 	} else {
 		metadata["provider"] = cloud.Type
 	}
-	results, err := charmstore.LatestCharmInfo(client, charms, metadata)
-	if err != nil {
-		return nil, err
-	}
+	return charmstore.LatestCharmInfo(client, charms, metadata)
+}
 
-	var latest []latestCharmInfo
-	for i, result := range results {
-		if result.Error != nil {
-			logger.Errorf("retrieving charm info for %s: %v", charms[i].URL, result.Error)
+// latestRepositoryCharmInfo returns the latest revision of each of the
+// given charms released to its channel in the charm repository.
+// Resources are not served by charm repositories, so none are reported.
+func latestRepositoryCharmInfo(repo CharmResolver, charms []charmstore.CharmID, now time.Time) []charmstore.CharmInfoResult {
+	logger.Infof("retrieving revision information for %d charms from charm repository", len(charms))
+	results := make([]charmstore.CharmInfoResult, len(charms))
+	for i, ch := range charms {
+		results[i].OriginalURL = ch.URL
+		results[i].Timestamp = now
+		latestURL, _, _, err := repo.ResolveWithPreferredChannel(ch.URL.WithRevision(-1), ch.Channel)
+		if err != nil {
+			results[i].Error = errors.Trace(err)
 			continue
 		}
-		application := resultsIndexedApps[i]
-		latest = append(latest, latestCharmInfo{
-			CharmInfo:   result.CharmInfo,
-			application: application,
-		})
+		results[i].LatestRevision = latestURL.Revision
 	}
-	return latest, nil
+	return results
 }
 
 func deployedArchs(app *state.Application) ([]string, error) {
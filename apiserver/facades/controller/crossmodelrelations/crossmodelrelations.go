@@ -20,6 +20,7 @@ import (
 	"github.com/juju/juju/apiserver/common/firewall"
 	"github.com/juju/juju/apiserver/facade"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/crossmodel"
 	"github.com/juju/juju/core/life"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/watcher"
@@ -111,22 +112,73 @@ func NewCrossModelRelationsAPI(
 	}, nil
 }
 
-func (api *CrossModelRelationsAPI) checkMacaroonsForRelation(relationTag names.Tag, mac macaroon.Slice, version bakery.Version) error {
+func (api *CrossModelRelationsAPI) offerUUIDForRelation(relationTag names.Tag) (string, error) {
 	api.mu.Lock()
 	defer api.mu.Unlock()
 
-	offerUUID, ok := api.relationToOffer[relationTag.Id()]
-	if !ok {
-		oc, err := api.st.OfferConnectionForRelation(relationTag.Id())
-		if err != nil {
-			return errors.Trace(err)
-		}
-		offerUUID = oc.OfferUUID()
+	if offerUUID, ok := api.relationToOffer[relationTag.Id()]; ok {
+		return offerUUID, nil
+	}
+	oc, err := api.st.OfferConnectionForRelation(relationTag.Id())
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return oc.OfferUUID(), nil
+}
+
+func (api *CrossModelRelationsAPI) checkMacaroonsForRelation(relationTag names.Tag, mac macaroon.Slice, version bakery.Version) error {
+	offerUUID, err := api.offerUUIDForRelation(relationTag)
+	if err != nil {
+		return errors.Trace(err)
 	}
 	auth := api.authCtxt.Authenticator(api.st.ModelUUID(), offerUUID)
 	return auth.CheckRelationMacaroons(api.ctx, relationTag, mac, version)
 }
 
+// checkSettingsRateLimit returns an error satisfying params.IsCodeTryAgain
+// if publishing the change would exceed the rate of relation settings
+// changes allowed by the offer, so that the caller backs off and tries
+// again later. Changes which don't carry settings, and changes to
+// relations which are no longer alive, are always allowed.
+func (api *CrossModelRelationsAPI) checkSettingsRateLimit(relationTag names.Tag, change params.RemoteRelationChangeEvent) error {
+	if change.Life != life.Alive {
+		return nil
+	}
+	if len(change.ChangedUnits) == 0 && len(change.ApplicationSettings) == 0 {
+		return nil
+	}
+	offerUUID, err := api.offerUUIDForRelation(relationTag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	offer, err := api.st.ApplicationOfferForUUID(offerUUID)
+	if errors.IsNotFound(err) {
+		// The offer has been force removed; there's nothing to enforce.
+		return nil
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	perMinute := offer.Limits.SettingsChangesPerMinute
+	if perMinute <= 0 {
+		return nil
+	}
+	oc, err := api.st.OfferConnectionForRelation(relationTag.Id())
+	if errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	allowed, err := oc.RecordSettingsChange(perMinute)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !allowed {
+		return errors.Annotatef(common.ErrTryAgain,
+			"relation %v settings changes exceed the offer limit of %d per minute", relationTag.Id(), perMinute)
+	}
+	return nil
+}
+
 // PublishRelationChanges publishes relation changes to the
 // model hosting the remote application involved in the relation.
 func (api *CrossModelRelationsAPI) PublishRelationChanges(
@@ -150,6 +202,10 @@ func (api *CrossModelRelationsAPI) PublishRelationChanges(
 			results.Results[i].Error = common.ServerError(err)
 			continue
 		}
+		if err := api.checkSettingsRateLimit(relationTag, change); err != nil {
+			results.Results[i].Error = common.ServerError(err)
+			continue
+		}
 		if err := commoncrossmodel.PublishRelationChange(api.st, relationTag, change); err != nil {
 			results.Results[i].Error = common.ServerError(err)
 			continue
@@ -188,9 +244,25 @@ func (api *CrossModelRelationsAPI) registerRemoteRelation(relation params.Regist
 		return nil, errors.Trace(err)
 	}
 
-	// Check that the supplied macaroon allows access.
+	sourceModelTag, err := names.ParseModelTag(relation.SourceModelTag)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	// Check that the supplied macaroon allows access. If the offer is
+	// restricted to particular consuming models, the macaroon must also
+	// be bound to the model claiming to consume the offer.
 	auth := api.authCtxt.Authenticator(api.st.ModelUUID(), appOffer.OfferUUID)
-	attr, err := auth.CheckOfferMacaroons(api.ctx, appOffer.OfferUUID, relation.Macaroons, relation.BakeryVersion)
+	var attr map[string]string
+	if len(appOffer.Limits.AllowedConsumerModels) > 0 {
+		attr, err = auth.CheckOfferMacaroonsForConsumer(
+			api.ctx, appOffer.OfferUUID, sourceModelTag.Id(), relation.Macaroons, relation.BakeryVersion)
+		if err == nil && attr["consumer-model-uuid"] != sourceModelTag.Id() {
+			err = common.ErrPerm
+		}
+	} else {
+		attr, err = auth.CheckOfferMacaroons(api.ctx, appOffer.OfferUUID, relation.Macaroons, relation.BakeryVersion)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -241,8 +313,8 @@ func (api *CrossModelRelationsAPI) registerRemoteRelation(relation params.Regist
 		},
 	}
 
-	sourceModelTag, err := names.ParseModelTag(relation.SourceModelTag)
-	if err != nil {
+	if err := api.checkOfferLimits(appOffer, sourceModelTag, *localEndpoint, remoteEndpoint); err != nil {
+		logger.Infof("rejecting relation from model %v to offer %v: %v", sourceModelTag.Id(), appOffer.OfferName, err)
 		return nil, errors.Trace(err)
 	}
 	_, err = api.st.AddRemoteApplication(state.AddRemoteApplicationParams{
@@ -264,20 +336,33 @@ func (api *CrossModelRelationsAPI) registerRemoteRelation(relation params.Regist
 	if err != nil && !errors.IsNotFound(err) {
 		return nil, errors.Trace(err)
 	}
+	addedRelation := false
 	if err != nil { // not found
 		localRel, err = api.st.AddRelation(*localEndpoint, remoteEndpoint)
 		// Again, if it already exists, that's fine.
 		if err != nil && !errors.IsAlreadyExists(err) {
 			return nil, errors.Annotate(err, "adding remote relation")
 		}
+		addedRelation = err == nil
 		logger.Debugf("added relation %v to model %v", localRel.Tag().Id(), api.st.ModelUUID())
 	}
+	// The offer's connection limit is enforced when the connection
+	// is added; checkOfferLimits above only catches the common case
+	// early, without racing other registrations.
 	_, err = api.st.AddOfferConnection(state.AddOfferConnectionParams{
 		SourceModelUUID: sourceModelTag.Id(), Username: username,
 		OfferUUID:   appOffer.OfferUUID,
 		RelationId:  localRel.Id(),
 		RelationKey: localRel.Tag().Id(),
 	})
+	if errors.IsQuotaLimitExceeded(err) && addedRelation {
+		if destroyErr := localRel.Destroy(); destroyErr != nil {
+			logger.Warningf("cannot remove relation %v over offer limit: %v", localRel.Tag().Id(), destroyErr)
+		}
+	}
+	if errors.IsQuotaLimitExceeded(err) {
+		return nil, errors.Trace(err)
+	}
 	if err != nil && !errors.IsAlreadyExists(err) {
 		return nil, errors.Annotate(err, "adding offer connection details")
 	}
@@ -327,6 +412,36 @@ func (api *CrossModelRelationsAPI) registerRemoteRelation(relation params.Regist
 	}, nil
 }
 
+// checkOfferLimits returns an error if the source model may not relate
+// to the offer, or if doing so would exceed the offer's connection limit.
+// Registering a relation which already exists is always allowed, since
+// registration is idempotent.
+func (api *CrossModelRelationsAPI) checkOfferLimits(
+	offer *crossmodel.ApplicationOffer,
+	sourceModelTag names.ModelTag,
+	localEndpoint, remoteEndpoint state.Endpoint,
+) error {
+	limits := offer.Limits
+	if limits.IsZero() {
+		return nil
+	}
+	_, err := api.st.EndpointsRelation(localEndpoint, remoteEndpoint)
+	if err == nil {
+		return nil
+	} else if !errors.IsNotFound(err) {
+		return errors.Trace(err)
+	}
+
+	if err := limits.CheckConsumer(sourceModelTag.Id()); err != nil {
+		return errors.Trace(err)
+	}
+	conns, err := api.st.OfferConnections(offer.OfferUUID)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(limits.CheckConnections(len(conns)))
+}
+
 // WatchRelationUnits starts a RelationUnitsWatcher for watching the
 // relation units involved in each specified relation, and returns the
 // watcher IDs and initial values, or an error if the relation units
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/juju/charm/v7"
+	"github.com/juju/clock/testclock"
 	"github.com/juju/names/v4"
 	"github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
@@ -47,6 +48,7 @@ type crossmodelRelationsSuite struct {
 	bakery        *mockBakeryService
 	authContext   *commoncrossmodel.AuthContext
 	api           *crossmodelrelations.CrossModelRelationsAPI
+	clock         *testclock.Clock
 
 	watchedRelations params.Entities
 	watchedOffers    []string
@@ -90,6 +92,7 @@ func (s *crossmodelRelationsSuite) SetUpTest(c *gc.C) {
 	thirdPartyKey := bakery.MustGenerateKey()
 	s.authContext, err = commoncrossmodel.NewAuthContext(s.mockStatePool, thirdPartyKey, s.bakery)
 	c.Assert(err, jc.ErrorIsNil)
+	s.clock = testclock.NewClock(time.Now())
 	api, err := crossmodelrelations.NewCrossModelRelationsAPI(
 		s.st, fw, s.resources, s.authorizer, s.authContext, egressAddressWatcher, relationStatusWatcher, offerStatusWatcher)
 	c.Assert(err, jc.ErrorIsNil)
@@ -110,6 +113,7 @@ func (s *crossmodelRelationsSuite) assertPublishRelationsChanges(c *gc.C, lifeVa
 		sourcemodelUUID: "source-model-uuid",
 		relationKey:     "db2:db django:db",
 		relationId:      1,
+		clock:           s.clock,
 	}
 	s.st.remoteEntities[names.NewRelationTag("db2:db django:db")] = "token-db2:db django:db"
 	mac, err := s.bakery.NewMacaroon(
@@ -195,6 +199,167 @@ func (s *crossmodelRelationsSuite) TestPublishRelationsChangesDyingForceCleanup(
 	s.assertPublishRelationsChanges(c, life.Dying, "", true)
 }
 
+func (s *crossmodelRelationsSuite) publishSettingsChange(c *gc.C, limits crossmodel.OfferLimits) func() error {
+	s.st.remoteApplications["db2"] = &mockRemoteApplication{}
+	s.st.remoteEntities[names.NewApplicationTag("db2")] = "token-db2"
+	rel := newMockRelation(1)
+	rel.units["db2/1"] = newMockRelationUnit()
+	s.st.relations["db2:db django:db"] = rel
+	s.st.offers["hosted-db2-uuid"] = &crossmodel.ApplicationOffer{
+		OfferUUID: "hosted-db2-uuid",
+		OfferName: "hosted-db2",
+		Limits:    limits,
+	}
+	s.st.offerConnectionsByKey["db2:db django:db"] = &mockOfferConnection{
+		offerUUID:       "hosted-db2-uuid",
+		sourcemodelUUID: "source-model-uuid",
+		relationKey:     "db2:db django:db",
+		relationId:      1,
+		clock:           s.clock,
+	}
+	s.st.remoteEntities[names.NewRelationTag("db2:db django:db")] = "token-db2:db django:db"
+	mac, err := s.bakery.NewMacaroon(
+		context.TODO(),
+		bakery.LatestVersion,
+		[]checkers.Caveat{
+			checkers.DeclaredCaveat("source-model-uuid", s.st.ModelUUID()),
+			checkers.DeclaredCaveat("relation-key", "db2:db django:db"),
+			checkers.DeclaredCaveat("username", "mary"),
+		}, bakery.Op{"db2:db django:db", "relate"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	return func() error {
+		results, err := s.api.PublishRelationChanges(params.RemoteRelationsChanges{
+			Changes: []params.RemoteRelationChangeEvent{{
+				Life:             life.Alive,
+				ApplicationToken: "token-db2",
+				RelationToken:    "token-db2:db django:db",
+				ChangedUnits: []params.RemoteRelationUnitChange{{
+					UnitId:   1,
+					Settings: map[string]interface{}{"foo": "bar"},
+				}},
+				Macaroons: macaroon.Slice{mac.M()},
+			}},
+		})
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(results.Results, gc.HasLen, 1)
+		if err := results.Results[0].Error; err != nil {
+			return err
+		}
+		return nil
+	}
+}
+
+func (s *crossmodelRelationsSuite) TestPublishRelationsChangesRateLimited(c *gc.C) {
+	publish := s.publishSettingsChange(c, crossmodel.OfferLimits{SettingsChangesPerMinute: 2})
+	c.Assert(publish(), jc.ErrorIsNil)
+	c.Assert(publish(), jc.ErrorIsNil)
+	err := publish()
+	c.Assert(err, gc.ErrorMatches, `relation db2:db django:db settings changes exceed the offer limit of 2 per minute: try again`)
+	c.Assert(err, jc.Satisfies, params.IsCodeTryAgain)
+
+	s.clock.Advance(time.Minute)
+	c.Assert(publish(), jc.ErrorIsNil)
+}
+
+func (s *crossmodelRelationsSuite) TestPublishRelationsChangesNoRateLimit(c *gc.C) {
+	publish := s.publishSettingsChange(c, crossmodel.OfferLimits{})
+	for i := 0; i < 10; i++ {
+		c.Assert(publish(), jc.ErrorIsNil)
+	}
+}
+
+func (s *crossmodelRelationsSuite) registerRemoteRelation(
+	c *gc.C, limits crossmodel.OfferLimits, consumerModelUUID string,
+) params.RegisterRemoteRelationResult {
+	app := &mockApplication{}
+	app.eps = []state.Endpoint{{
+		ApplicationName: "offeredapp",
+		Relation:        charm.Relation{Name: "local"},
+	}}
+	s.st.applications["offeredapp"] = app
+	s.st.offers = map[string]*crossmodel.ApplicationOffer{
+		"offer-uuid": {
+			OfferUUID:       "offer-uuid",
+			OfferName:       "offered",
+			ApplicationName: "offeredapp",
+			Limits:          limits,
+		}}
+	caveats := []checkers.Caveat{
+		checkers.DeclaredCaveat("source-model-uuid", s.st.ModelUUID()),
+		checkers.DeclaredCaveat("offer-uuid", "offer-uuid"),
+		checkers.DeclaredCaveat("username", "mary"),
+	}
+	if consumerModelUUID != "" {
+		caveats = append(caveats, checkers.DeclaredCaveat("consumer-model-uuid", consumerModelUUID))
+	}
+	mac, err := s.bakery.NewMacaroon(
+		context.TODO(), bakery.LatestVersion, caveats, bakery.Op{"offer-uuid", "consume"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	results, err := s.api.RegisterRemoteRelations(params.RegisterRemoteRelationArgs{
+		Relations: []params.RegisterRemoteRelationArg{{
+			ApplicationToken:  "app-token",
+			SourceModelTag:    coretesting.ModelTag.String(),
+			RelationToken:     "rel-token",
+			RemoteEndpoint:    params.RemoteEndpoint{Name: "remote"},
+			OfferUUID:         "offer-uuid",
+			LocalEndpointName: "local",
+			Macaroons:         macaroon.Slice{mac.M()},
+		}}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	return results.Results[0]
+}
+
+func (s *crossmodelRelationsSuite) TestRegisterRemoteRelationsMaxConnections(c *gc.C) {
+	s.st.offerConnections[5] = &mockOfferConnection{offerUUID: "offer-uuid", relationId: 5}
+	result := s.registerRemoteRelation(c, crossmodel.OfferLimits{MaxConnections: 1}, "")
+	c.Assert(result.Error, gc.ErrorMatches, `offer already has the maximum of 1 connection\(s\)`)
+	c.Assert(result.Error.Code, gc.Equals, params.CodeQuotaLimitExceeded)
+	c.Assert(s.st.remoteApplications, gc.HasLen, 0)
+	c.Assert(s.st.relations, gc.HasLen, 0)
+}
+
+func (s *crossmodelRelationsSuite) TestRegisterRemoteRelationsBelowMaxConnections(c *gc.C) {
+	s.st.offerConnections[5] = &mockOfferConnection{offerUUID: "other-offer-uuid", relationId: 5}
+	result := s.registerRemoteRelation(c, crossmodel.OfferLimits{MaxConnections: 1}, "")
+	c.Assert(result.Error, gc.IsNil)
+
+	// Registering the same relation again is allowed.
+	result = s.registerRemoteRelation(c, crossmodel.OfferLimits{MaxConnections: 1}, "")
+	c.Assert(result.Error, gc.IsNil)
+}
+
+func (s *crossmodelRelationsSuite) TestRegisterRemoteRelationsModelNotAllowed(c *gc.C) {
+	limits := crossmodel.OfferLimits{AllowedConsumerModels: []string{"deadbeef-0bad-400d-8000-4b1d0d06f00e"}}
+	result := s.registerRemoteRelation(c, limits, coretesting.ModelTag.Id())
+	c.Assert(result.Error, gc.ErrorMatches, `model "deadbeef-0bad-400d-8000-4b1d0d06f00d" may not consume the offer`)
+	c.Assert(result.Error.Code, gc.Equals, params.CodeUnauthorized)
+}
+
+func (s *crossmodelRelationsSuite) TestRegisterRemoteRelationsAllowedModel(c *gc.C) {
+	limits := crossmodel.OfferLimits{AllowedConsumerModels: []string{coretesting.ModelTag.Id()}}
+	result := s.registerRemoteRelation(c, limits, coretesting.ModelTag.Id())
+	c.Assert(result.Error, gc.IsNil)
+}
+
+func (s *crossmodelRelationsSuite) TestRegisterRemoteRelationsConsumerModelNotDeclared(c *gc.C) {
+	limits := crossmodel.OfferLimits{AllowedConsumerModels: []string{coretesting.ModelTag.Id()}}
+	result := s.registerRemoteRelation(c, limits, "")
+	c.Assert(result.Error, gc.NotNil)
+	c.Assert(result.Error.Code, gc.Equals, params.CodeDischargeRequired)
+	c.Assert(s.st.remoteApplications, gc.HasLen, 0)
+}
+
+func (s *crossmodelRelationsSuite) TestRegisterRemoteRelationsConsumerModelMismatch(c *gc.C) {
+	limits := crossmodel.OfferLimits{AllowedConsumerModels: []string{coretesting.ModelTag.Id()}}
+	result := s.registerRemoteRelation(c, limits, "deadbeef-0bad-400d-8000-4b1d0d06f00e")
+	c.Assert(result.Error, gc.NotNil)
+	c.Assert(result.Error.Code, gc.Equals, params.CodeDischargeRequired)
+	c.Assert(s.st.remoteApplications, gc.HasLen, 0)
+}
+
 func (s *crossmodelRelationsSuite) assertRegisterRemoteRelations(c *gc.C) {
 	app := &mockApplication{}
 	app.eps = []state.Endpoint{{
@@ -295,6 +460,7 @@ func (s *crossmodelRelationsSuite) TestRelationUnitSettings(c *gc.C) {
 		sourcemodelUUID: "source-model-uuid",
 		relationKey:     "db2:db django:db",
 		relationId:      1,
+		clock:           s.clock,
 	}
 	s.st.remoteEntities[names.NewRelationTag("db2:db django:db")] = "token-db2"
 	mac, err := s.bakery.NewMacaroon(
@@ -622,6 +788,7 @@ func (s *crossmodelRelationsSuite) TestPublishChangesWithApplicationSettings(c *
 		sourcemodelUUID: "source-model-uuid",
 		relationKey:     "db2:db django:db",
 		relationId:      1,
+		clock:           s.clock,
 	}
 	s.st.remoteEntities[names.NewRelationTag("db2:db django:db")] = "token-db2:db django:db"
 	mac, err := s.bakery.NewMacaroon(
@@ -826,6 +993,7 @@ func (s *crossmodelRelationsSuite) TestWatchRelationUnitsOnV1(c *gc.C) {
 		sourcemodelUUID: "source-model-uuid",
 		relationKey:     "db2:db django:db",
 		relationId:      1,
+		clock:           s.clock,
 	}
 	s.st.remoteEntities[names.NewRelationTag("db2:db django:db")] = "token-db2:db django:db"
 	mac, err := s.bakery.NewMacaroon(
@@ -9,6 +9,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/juju/clock"
 	"github.com/juju/errors"
 	"github.com/juju/names/v4"
 	"github.com/juju/testing"
@@ -145,6 +146,16 @@ func (st *mockState) OfferConnectionForRelation(relationKey string) (crossmodelr
 	return oc, nil
 }
 
+func (st *mockState) OfferConnections(offerUUID string) ([]crossmodelrelations.OfferConnection, error) {
+	var result []crossmodelrelations.OfferConnection
+	for _, oc := range st.offerConnections {
+		if oc.offerUUID == offerUUID {
+			result = append(result, oc)
+		}
+	}
+	return result, nil
+}
+
 func (st *mockState) EndpointsRelation(eps ...state.Endpoint) (commoncrossmodel.Relation, error) {
 	key := fmt.Sprintf("%v:%v %v:%v", eps[0].ApplicationName, eps[0].Name, eps[1].ApplicationName, eps[1].Name)
 	if rel, ok := st.relations[key]; ok {
@@ -520,12 +531,29 @@ type mockOfferConnection struct {
 	relationKey     string
 	username        string
 	offerUUID       string
+
+	clock       clock.Clock
+	windowStart time.Time
+	windowCount int
 }
 
 func (m *mockOfferConnection) OfferUUID() string {
 	return m.offerUUID
 }
 
+func (m *mockOfferConnection) RecordSettingsChange(perMinute int) (bool, error) {
+	windowStart := m.clock.Now().Truncate(time.Minute)
+	if !windowStart.Equal(m.windowStart) {
+		m.windowStart = windowStart
+		m.windowCount = 0
+	}
+	if m.windowCount >= perMinute {
+		return false, nil
+	}
+	m.windowCount++
+	return true, nil
+}
+
 type mockRelationUnit struct {
 	commoncrossmodel.RelationUnit
 	testing.Stub
@@ -27,6 +27,9 @@ type CrossModelRelationsState interface {
 	// OfferConnectionForRelation returns the offer connection details for the given relation key.
 	OfferConnectionForRelation(string) (OfferConnection, error)
 
+	// OfferConnections returns the offer connections for the given offer UUID.
+	OfferConnections(string) ([]OfferConnection, error)
+
 	// IsMigrationActive returns true if the current model is
 	// in the process of being migrated to another controller.
 	IsMigrationActive() (bool, error)
@@ -52,6 +55,18 @@ func (st stateShim) OfferConnectionForRelation(relationKey string) (OfferConnect
 	return st.st.OfferConnectionForRelation(relationKey)
 }
 
+func (st stateShim) OfferConnections(offerUUID string) ([]OfferConnection, error) {
+	conns, err := st.st.OfferConnections(offerUUID)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	result := make([]OfferConnection, len(conns))
+	for i, oc := range conns {
+		result[i] = oc
+	}
+	return result, nil
+}
+
 // IsMigrationActive returns true if the current model is
 // in the process of being migrated to another controller.
 func (st stateShim) IsMigrationActive() (bool, error) {
@@ -70,4 +85,9 @@ func (st stateShim) Model() (Model, error) {
 
 type OfferConnection interface {
 	OfferUUID() string
+
+	// RecordSettingsChange records that a relation settings change is
+	// being published over the connection, and reports whether the
+	// change keeps within the limit of perMinute changes a minute.
+	RecordSettingsChange(perMinute int) (bool, error)
 }
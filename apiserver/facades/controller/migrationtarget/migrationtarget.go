@@ -37,8 +37,22 @@ type API struct {
 	getCAASBroker stateenvirons.NewCAASBrokerFunc
 }
 
-// NewFacade is used for API registration.
-func NewFacade(ctx facade.Context) (*API, error) {
+// APIV1 implements the V1 API, which does not include ImportDryRun.
+type APIV1 struct {
+	*API
+}
+
+// NewFacadeV1 is used for API registration.
+func NewFacadeV1(ctx facade.Context) (*APIV1, error) {
+	api, err := NewFacadeV2(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &APIV1{api}, nil
+}
+
+// NewFacadeV2 is used for API registration.
+func NewFacadeV2(ctx facade.Context) (*API, error) {
 	return NewAPI(
 		ctx,
 		stateenvirons.GetNewEnvironFunc(environs.New),
@@ -82,6 +96,41 @@ func checkAuth(authorizer facade.Authorizer, st *state.State) error {
 // Prechecks ensure that the target controller is ready to accept a
 // model migration.
 func (api *API) Prechecks(model params.MigrationModelInfo) error {
+	return api.runPrechecks(model, migration.TargetPrecheck)
+}
+
+// PrecheckProblems runs the same checks as Prechecks, reporting every
+// problem that would prevent the model being migrated to this
+// controller rather than only the first.
+func (api *API) PrecheckProblems(model params.MigrationModelInfo) (params.ErrorResults, error) {
+	var problems []error
+	err := api.runPrechecks(model, func(
+		backend migration.PrecheckBackend,
+		pool migration.Pool,
+		modelInfo coremigration.ModelInfo,
+		presence migration.ModelPresence,
+	) error {
+		problems = migration.TargetPrecheckProblems(backend, pool, modelInfo, presence)
+		return nil
+	})
+	if err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(problems)),
+	}
+	for i, problem := range problems {
+		results.Results[i].Error = common.ServerError(problem)
+	}
+	return results, nil
+}
+
+// PrecheckProblems isn't on the V1 API.
+func (*APIV1) PrecheckProblems(_, _ struct{}) {}
+
+type targetPrecheckFunc func(migration.PrecheckBackend, migration.Pool, coremigration.ModelInfo, migration.ModelPresence) error
+
+func (api *API) runPrechecks(model params.MigrationModelInfo, precheck targetPrecheckFunc) error {
 	ownerTag, err := names.ParseUserTag(model.OwnerTag)
 	if err != nil {
 		return errors.Trace(err)
@@ -95,7 +144,7 @@ func (api *API) Prechecks(model params.MigrationModelInfo) error {
 	if err != nil {
 		return errors.Annotate(err, "creating backend")
 	}
-	return migration.TargetPrecheck(
+	return precheck(
 		backend,
 		migration.PoolShim(api.pool),
 		coremigration.ModelInfo{
@@ -109,6 +158,37 @@ func (api *API) Prechecks(model params.MigrationModelInfo) error {
 	)
 }
 
+// ImportDryRun checks that the serialized model can be imported into
+// this controller by importing it as a throwaway model, with a new UUID
+// and name, which is removed again before returning. Any conversions of
+// the model's description format are reported.
+func (api *API) ImportDryRun(serialized params.SerializedModel) (params.MigrationPrecheckImportResult, error) {
+	upconversions, err := api.importDryRun(serialized.Bytes)
+	if err != nil {
+		return params.MigrationPrecheckImportResult{Error: common.ServerError(err)}, nil
+	}
+	var result params.MigrationPrecheckImportResult
+	for _, u := range upconversions {
+		result.Upconversions = append(result.Upconversions, u.String())
+	}
+	return result, nil
+}
+
+// ImportDryRun isn't on the V1 API.
+func (*APIV1) ImportDryRun(_, _ struct{}) {}
+
+func (api *API) importDryRun(bytes []byte) ([]migration.Upconversion, error) {
+	model, upconversions, err := migration.UpconvertDescription(bytes)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	controller := state.NewController(api.pool)
+	if err := controller.ImportDryRun(model); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return upconversions, nil
+}
+
 // Import takes a serialized Juju model, deserializes it, and
 // recreates it in the receiving controller.
 func (api *API) Import(serialized params.SerializedModel) error {
@@ -5,6 +5,7 @@ package migrationtarget_test
 
 import (
 	"io/ioutil"
+	"strings"
 	"time"
 
 	"github.com/juju/description/v2"
@@ -75,7 +76,7 @@ func (s *Suite) SetUpTest(c *gc.C) {
 }
 
 func (s *Suite) TestFacadeRegistered(c *gc.C) {
-	aFactory, err := apiserver.AllFacades().GetFactory("MigrationTarget", 1)
+	aFactory, err := apiserver.AllFacades().GetFactory("MigrationTarget", 2)
 	c.Assert(err, jc.ErrorIsNil)
 
 	api, err := aFactory(&facadetest.Context{
@@ -87,6 +88,19 @@ func (s *Suite) TestFacadeRegistered(c *gc.C) {
 	c.Assert(api, gc.FitsTypeOf, new(migrationtarget.API))
 }
 
+func (s *Suite) TestFacadeRegisteredV1(c *gc.C) {
+	aFactory, err := apiserver.AllFacades().GetFactory("MigrationTarget", 1)
+	c.Assert(err, jc.ErrorIsNil)
+
+	api, err := aFactory(&facadetest.Context{
+		State_:     s.State,
+		Resources_: s.resources,
+		Auth_:      s.authorizer,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(api, gc.FitsTypeOf, new(migrationtarget.APIV1))
+}
+
 func (s *Suite) TestNotUser(c *gc.C) {
 	s.authorizer.Tag = names.NewMachineTag("0")
 	_, err := s.newAPI(nil, nil)
@@ -152,6 +166,93 @@ func (s *Suite) TestImport(c *gc.C) {
 	c.Assert(model.MigrationMode(), gc.Equals, state.MigrationModeImporting)
 }
 
+func (s *Suite) TestPrecheckProblems(c *gc.C) {
+	api := s.mustNewAPI(c)
+	args := params.MigrationModelInfo{
+		UUID:                   "uuid",
+		Name:                   "some-model",
+		OwnerTag:               names.NewUserTag("someone").String(),
+		AgentVersion:           s.controllerVersion(c),
+		ControllerAgentVersion: s.controllerVersion(c),
+	}
+	results, err := api.PrecheckProblems(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 0)
+}
+
+func (s *Suite) TestPrecheckProblemsFail(c *gc.C) {
+	controllerVersion := s.controllerVersion(c)
+
+	// Set the model and source controller versions ahead of the controller.
+	modelVersion := controllerVersion
+	modelVersion.Minor++
+
+	api := s.mustNewAPI(c)
+	args := params.MigrationModelInfo{
+		UUID:                   "uuid",
+		Name:                   "some-model",
+		OwnerTag:               names.NewUserTag("someone").String(),
+		AgentVersion:           modelVersion,
+		ControllerAgentVersion: modelVersion,
+	}
+	results, err := api.PrecheckProblems(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.ErrorMatches, "model has higher version than target controller .*")
+}
+
+func (s *Suite) TestImportDryRun(c *gc.C) {
+	api := s.mustNewAPI(c)
+	uuid, bytes := s.makeExportedModel(c)
+	result, err := api.ImportDryRun(params.SerializedModel{Bytes: bytes})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.MigrationPrecheckImportResult{})
+
+	// Neither the model nor the throwaway copy remain.
+	models, err := s.State.AllModelUUIDs()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(models, jc.DeepEquals, []string{s.State.ModelUUID()})
+	_, _, err = s.StatePool.GetModel(uuid)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *Suite) TestImportDryRunFailure(c *gc.C) {
+	api := s.mustNewAPI(c)
+	result, err := api.ImportDryRun(params.SerializedModel{Bytes: []byte("version: 99")})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Error, gc.ErrorMatches, "cannot import model description: .*")
+}
+
+func (s *Suite) TestImportOlderFormat(c *gc.C) {
+	api := s.mustNewAPI(c)
+	uuid, bytes := s.makeExportedModel(c)
+	// Rewrite the model version as an older controller would export it.
+	serialized := string(bytes)
+	c.Assert(strings.HasPrefix(serialized, "version: 8\n"), jc.IsTrue)
+	serialized = "version: 7\n" + strings.TrimPrefix(serialized, "version: 8\n")
+
+	err := api.Import(params.SerializedModel{Bytes: []byte(serialized)})
+	c.Assert(err, jc.ErrorIsNil)
+	model, ph, err := s.StatePool.GetModel(uuid)
+	c.Assert(err, jc.ErrorIsNil)
+	defer ph.Release()
+	c.Assert(model.Name(), gc.Equals, "some-model")
+}
+
+func (s *Suite) TestImportDryRunOlderFormat(c *gc.C) {
+	api := s.mustNewAPI(c)
+	_, bytes := s.makeExportedModel(c)
+	serialized := string(bytes)
+	c.Assert(strings.HasPrefix(serialized, "version: 8\n"), jc.IsTrue)
+	serialized = "version: 7\n" + strings.TrimPrefix(serialized, "version: 8\n")
+
+	result, err := api.ImportDryRun(params.SerializedModel{Bytes: []byte(serialized)})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.MigrationPrecheckImportResult{
+		Upconversions: []string{"model upconverted from version 7 to 8"},
+	})
+}
+
 func (s *Suite) TestImportLeadership(c *gc.C) {
 	application := s.Factory.MakeApplication(c, &factory.ApplicationParams{
 		Charm: s.Factory.MakeCharm(c, &factory.CharmParams{
@@ -509,7 +509,9 @@ func modelInfoFromPath(path string, st *state.State, pool *state.StatePool) (uui
 		return "", "", ""
 	}
 	user, modelName = parts[1], parts[2]
-	modelUUIDs, err := st.ModelUUIDsForUser(names.NewUserTag(user))
+	// Only the models the user has been granted access to directly are
+	// used to resolve the path: the GUI does not know their groups.
+	modelUUIDs, err := st.ModelUUIDsForUser(names.NewUserTag(user), nil)
 	if err != nil {
 		return "", "", ""
 	}
@@ -15,6 +15,7 @@ import (
 	"github.com/juju/juju/apiserver/httpcontext"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/apiserver/stateauthenticator"
+	"github.com/juju/juju/core/permission"
 	"github.com/juju/juju/state"
 )
 
@@ -204,6 +205,35 @@ func (a tagKindAuthorizer) Authorize(authInfo httpcontext.AuthInfo) error {
 	return errors.NotValidf("tag kind %v", tagKind)
 }
 
+// modelWriteAuthorizer authorizes requests that change a model. Users
+// that logged in with an API token are refused unless the token grants
+// write access to the model. Any other checks are left to the wrapped
+// authorizer, if there is one.
+type modelWriteAuthorizer struct {
+	httpcontext.Authorizer
+}
+
+// Authorize is part of the httpcontext.Authorizer interface.
+func (a modelWriteAuthorizer) Authorize(authInfo httpcontext.AuthInfo) error {
+	if err := checkTokenWriteAccess(authInfo); err != nil {
+		return errors.Trace(err)
+	}
+	if a.Authorizer == nil {
+		return nil
+	}
+	return a.Authorizer.Authorize(authInfo)
+}
+
+// checkTokenWriteAccess returns an error if the request was
+// authenticated with an API token that does not grant write access
+// to the model.
+func checkTokenWriteAccess(authInfo httpcontext.AuthInfo) error {
+	if authInfo.TokenID == "" || authInfo.TokenAccess.EqualOrGreaterModelAccessThan(permission.WriteAccess) {
+		return nil
+	}
+	return errors.Forbiddenf("API token %q does not grant write access", authInfo.TokenID)
+}
+
 type controllerAuthorizer struct{}
 
 // Authorize is part of the httpcontext.Authorizer interface.
@@ -224,6 +254,9 @@ func (a controllerAdminAuthorizer) Authorize(authInfo httpcontext.AuthInfo) erro
 	if !ok {
 		return errors.Errorf("%s is not a user", names.ReadableString(authInfo.Entity.Tag()))
 	}
+	if authInfo.TokenID != "" {
+		return errors.Errorf("API token %q does not grant controller admin access", authInfo.TokenID)
+	}
 	admin, err := a.st.IsControllerAdmin(userTag)
 	if err != nil {
 		return errors.Trace(err)
@@ -18,6 +18,7 @@ import (
 
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/permission"
 )
 
 var logger = loggo.GetLogger("juju.apiserver.httpcontext")
@@ -95,6 +96,19 @@ type AuthInfo struct {
 	// Controller reports whether or not the authenticated
 	// entity is a controller agent.
 	Controller bool
+
+	// TokenID holds the ID of the API token the entity logged in
+	// with, if it logged in with one.
+	TokenID string
+
+	// TokenAccess holds the highest model access granted to logins
+	// made with the API token, if the entity logged in with one.
+	TokenAccess permission.Access
+
+	// PasswordExpired reports whether the entity is a local user
+	// whose password has expired, and who may do nothing but
+	// change it.
+	PasswordExpired bool
 }
 
 // BasicAuthHandler is an http.Handler that authenticates requests that
@@ -0,0 +1,48 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/httpcontext"
+	"github.com/juju/juju/core/permission"
+)
+
+type httpAuthorizerSuite struct{}
+
+var _ = gc.Suite(&httpAuthorizerSuite{})
+
+type tagEntity struct {
+	tag names.Tag
+}
+
+func (e tagEntity) Tag() names.Tag {
+	return e.tag
+}
+
+func (*httpAuthorizerSuite) TestModelWriteAuthorizer(c *gc.C) {
+	user := tagEntity{names.NewUserTag("bob")}
+	machine := tagEntity{names.NewMachineTag("0")}
+
+	auth := modelWriteAuthorizer{tagKindAuthorizer{names.UserTagKind}}
+	c.Check(auth.Authorize(httpcontext.AuthInfo{Entity: user}), jc.ErrorIsNil)
+	c.Check(auth.Authorize(httpcontext.AuthInfo{Entity: machine}), gc.ErrorMatches, `tag kind machine not valid`)
+
+	for _, access := range []permission.Access{permission.WriteAccess, permission.AdminAccess} {
+		err := auth.Authorize(httpcontext.AuthInfo{Entity: user, TokenID: "deadbeef", TokenAccess: access})
+		c.Check(err, jc.ErrorIsNil)
+	}
+	err := auth.Authorize(httpcontext.AuthInfo{Entity: user, TokenID: "deadbeef", TokenAccess: permission.ReadAccess})
+	c.Check(err, gc.ErrorMatches, `API token "deadbeef" does not grant write access`)
+	c.Check(err, jc.Satisfies, errors.IsForbidden)
+
+	// Without a wrapped authorizer, only the token is checked.
+	c.Check(modelWriteAuthorizer{}.Authorize(httpcontext.AuthInfo{Entity: machine}), jc.ErrorIsNil)
+	err = modelWriteAuthorizer{}.Authorize(httpcontext.AuthInfo{Entity: user, TokenID: "deadbeef", TokenAccess: permission.ReadAccess})
+	c.Check(err, jc.Satisfies, errors.IsForbidden)
+}
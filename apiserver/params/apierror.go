@@ -186,6 +186,8 @@ const (
 	CodeCloudRegionRequired       = "cloud region required"
 	CodeIncompatibleClouds        = "incompatible clouds"
 	CodeQuotaLimitExceeded        = "quota limit exceeded"
+	CodeMFARequired               = "multi-factor authentication code required"
+	CodeUnsupportedMediaType      = "unsupported media type"
 )
 
 // ErrCode returns the error code associated with
@@ -235,6 +237,10 @@ func IsCodeLoginExpired(err error) bool {
 	return ErrCode(err) == CodeLoginExpired
 }
 
+func IsCodeMFARequired(err error) bool {
+	return ErrCode(err) == CodeMFARequired
+}
+
 // IsCodeNotFoundOrCodeUnauthorized is used in API clients which,
 // pre-API, used errors.IsNotFound; this is because an API client is
 // not necessarily privileged to know about the existence or otherwise
@@ -365,3 +371,9 @@ func IsCodeCloudRegionRequired(err error) bool {
 func IsCodeQuotaLimitExceeded(err error) bool {
 	return ErrCode(err) == CodeQuotaLimitExceeded
 }
+
+// IsCodeUnsupportedMediaType returns true if err includes an
+// UnsupportedMediaType error code.
+func IsCodeUnsupportedMediaType(err error) bool {
+	return ErrCode(err) == CodeUnsupportedMediaType
+}
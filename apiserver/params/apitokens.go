@@ -0,0 +1,56 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package params
+
+import "time"
+
+// AddAPIToken holds the arguments for minting an API token, which
+// allows its owner to log in to a single model without a password.
+// If UserTag is empty, the token is owned by the caller.
+type AddAPIToken struct {
+	ModelTag string    `json:"model-tag"`
+	UserTag  string    `json:"user-tag,omitempty"`
+	Access   string    `json:"access"`
+	Expires  time.Time `json:"expires"`
+}
+
+// AddAPITokens holds the arguments for minting API tokens.
+type AddAPITokens struct {
+	Tokens []AddAPIToken `json:"tokens"`
+}
+
+// APIToken describes an API token. The secret of the token is never
+// included.
+type APIToken struct {
+	ID           string    `json:"id"`
+	OwnerTag     string    `json:"owner-tag"`
+	ModelTag     string    `json:"model-tag"`
+	Access       string    `json:"access"`
+	CreatedByTag string    `json:"created-by-tag"`
+	Created      time.Time `json:"created"`
+	Expires      time.Time `json:"expires"`
+}
+
+// APITokens holds API tokens.
+type APITokens struct {
+	Tokens []APIToken `json:"tokens"`
+}
+
+// AddAPITokenResult holds a newly minted API token and its secret,
+// which is only ever returned here.
+type AddAPITokenResult struct {
+	Token  APIToken `json:"token"`
+	Secret string   `json:"secret,omitempty"`
+	Error  *Error   `json:"error,omitempty"`
+}
+
+// AddAPITokenResults holds the results of minting API tokens.
+type AddAPITokenResults struct {
+	Results []AddAPITokenResult `json:"results"`
+}
+
+// APITokenIDs holds the IDs of API tokens.
+type APITokenIDs struct {
+	IDs []string `json:"ids"`
+}
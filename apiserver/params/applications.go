@@ -422,6 +422,25 @@ type ScaleApplicationInfo struct {
 	Scale int `json:"num-units"`
 }
 
+// AdoptWorkloadsArgs holds bulk parameters for the Application.AdoptWorkloads call.
+type AdoptWorkloadsArgs struct {
+	Workloads []AdoptWorkloadArg `json:"workloads"`
+}
+
+// AdoptWorkloadArg holds parameters for adopting an existing workload
+// into a Juju application.
+type AdoptWorkloadArg struct {
+	// ApplicationTag holds the tag of the application adopting the workload.
+	ApplicationTag string `json:"application-tag"`
+
+	// DeploymentType is the type of the workload, either "stateless"
+	// for a deployment or "stateful" for a stateful set.
+	DeploymentType string `json:"deployment-type"`
+
+	// WorkloadName is the name of the existing workload.
+	WorkloadName string `json:"workload-name"`
+}
+
 // ApplicationResult holds an application info.
 // NOTE: we should look to combine ApplicationResult and ApplicationInfo.
 type ApplicationResult struct {
@@ -0,0 +1,74 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package params
+
+// Info holds the name of a charm or bundle to return information about.
+type Info struct {
+	Name string `json:"name"`
+}
+
+// Query holds the query used to find charms and bundles.
+type Query struct {
+	Query string `json:"query"`
+}
+
+// CharmHubEntityInfoResult holds the result of a CharmHub Info call.
+type CharmHubEntityInfoResult struct {
+	Result InfoResponse `json:"result"`
+	Error  *Error       `json:"error,omitempty"`
+}
+
+// CharmHubEntityFindResult holds the result of a CharmHub Find call.
+type CharmHubEntityFindResult struct {
+	Results []FindResponse `json:"result"`
+	Error   *Error         `json:"error,omitempty"`
+}
+
+// InfoResponse holds information about a charm or bundle in CharmHub.
+type InfoResponse struct {
+	Type        string `json:"type"`
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Publisher   string `json:"publisher"`
+	Summary     string `json:"summary"`
+	StoreURL    string `json:"store-url"`
+
+	// DefaultChannel is the name of the channel from which the charm
+	// or bundle is deployed when no channel is specified.
+	DefaultChannel string `json:"default-channel,omitempty"`
+
+	// Tracks holds the tracks of the charm or bundle, in the order
+	// they were first released to.
+	Tracks []string `json:"tracks"`
+
+	// Channels holds the revision released to each channel, keyed by
+	// channel name.
+	Channels map[string]Channel `json:"channel-map"`
+}
+
+// FindResponse holds a single charm or bundle found in CharmHub.
+type FindResponse struct {
+	Type      string   `json:"type"`
+	ID        string   `json:"id"`
+	Name      string   `json:"name"`
+	Publisher string   `json:"publisher"`
+	Summary   string   `json:"summary"`
+	Version   string   `json:"version"`
+	Series    []string `json:"series,omitempty"`
+	StoreURL  string   `json:"store-url"`
+}
+
+// Channel holds the revision of a charm or bundle released to a channel.
+type Channel struct {
+	ReleasedAt  string   `json:"released-at"`
+	Track       string   `json:"track"`
+	Risk        string   `json:"risk"`
+	Revision    int      `json:"revision"`
+	Size        int      `json:"size"`
+	Version     string   `json:"version"`
+	Series      []string `json:"series,omitempty"`
+	DownloadURL string   `json:"download-url"`
+	HashSHA256  string   `json:"hash-sha-256"`
+}
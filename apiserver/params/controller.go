@@ -3,7 +3,11 @@
 
 package params
 
-import "github.com/juju/juju/core/life"
+import (
+	"time"
+
+	"github.com/juju/juju/core/life"
+)
 
 // DestroyControllerArgs holds the arguments for destroying a controller.
 type DestroyControllerArgs struct {
@@ -72,6 +76,10 @@ type ModifyControllerAccess struct {
 	UserTag string           `json:"user-tag"`
 	Action  ControllerAction `json:"action"`
 	Access  string           `json:"access"`
+
+	// GroupName names the group whose access is changed,
+	// in which case UserTag is empty.
+	GroupName string `json:"group-name,omitempty"`
 }
 
 // UserAccess holds the level of access a user
@@ -100,6 +108,62 @@ type ControllerConfigSet struct {
 	Config map[string]interface{} `json:"config"`
 }
 
+// RotateControllerCAArgs holds the step of a controller CA rotation
+// to perform with Controller.RotateControllerCA.
+type RotateControllerCAArgs struct {
+	// Step is one of "start", "reissue", "finish" or "abort".
+	Step string `json:"step"`
+}
+
+// ControllerCARotation describes the state of a rotation of the
+// controller's CA.
+type ControllerCARotation struct {
+	// Phase is the phase the rotation has reached, or empty if
+	// there is no rotation in progress.
+	Phase string `json:"phase,omitempty"`
+
+	// CACert holds the PEM encoded CA certificates that clients
+	// and agents should currently trust.
+	CACert string `json:"ca-cert"`
+
+	// NewCACert holds the PEM encoded certificate of the
+	// replacement CA.
+	NewCACert string `json:"new-ca-cert,omitempty"`
+
+	// Started holds when the rotation was started.
+	Started *time.Time `json:"started,omitempty"`
+
+	// Reissued holds when certificates started to be issued by
+	// the replacement CA.
+	Reissued *time.Time `json:"reissued,omitempty"`
+}
+
+// AutocertStatus holds the status of the certificate obtained for the
+// controller's autocert DNS name.
+type AutocertStatus struct {
+	// DNSName is the autocert DNS name, or empty if autocert
+	// isn't configured.
+	DNSName string `json:"dns-name,omitempty"`
+
+	// Issuer is the subject of the CA that issued the certificate,
+	// or empty if no certificate has been obtained.
+	Issuer string `json:"issuer,omitempty"`
+
+	// NotBefore and NotAfter bound the certificate's validity.
+	NotBefore *time.Time `json:"not-before,omitempty"`
+	NotAfter  *time.Time `json:"not-after,omitempty"`
+
+	// RenewAfter holds when the certificate will be renewed.
+	RenewAfter *time.Time `json:"renew-after,omitempty"`
+
+	// Error holds the last error encountered obtaining a
+	// certificate, or empty if the last attempt succeeded.
+	Error string `json:"error,omitempty"`
+
+	// ErrorTime holds when Error was encountered.
+	ErrorTime *time.Time `json:"error-time,omitempty"`
+}
+
 // ControllerAction is an action that can be performed on a model.
 type ControllerAction string
 
@@ -94,6 +94,14 @@ type ApplicationOfferAdminDetails struct {
 	ApplicationName string            `json:"application-name"`
 	CharmURL        string            `json:"charm-url"`
 	Connections     []OfferConnection `json:"connections,omitempty"`
+	Limits          *OfferLimits      `json:"limits,omitempty"`
+}
+
+// OfferLimits holds the restrictions placed on consumers of an offer.
+type OfferLimits struct {
+	MaxConnections           int      `json:"max-connections,omitempty"`
+	AllowedConsumerModels    []string `json:"allowed-consumer-models,omitempty"`
+	SettingsChangesPerMinute int      `json:"settings-changes-per-minute,omitempty"`
 }
 
 // OfferConnection holds details about a connection to an offer.
@@ -124,6 +132,7 @@ type AddApplicationOffer struct {
 	ApplicationName        string            `json:"application-name"`
 	ApplicationDescription string            `json:"application-description"`
 	Endpoints              map[string]string `json:"endpoints"`
+	Limits                 *OfferLimits      `json:"limits,omitempty"`
 }
 
 // DestroyApplicationOffers holds parameters for the DestroyOffers call.
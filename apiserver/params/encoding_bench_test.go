@@ -0,0 +1,236 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package params_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/instance"
+	"github.com/juju/juju/core/life"
+	"github.com/juju/juju/core/model"
+	"github.com/juju/juju/core/status"
+	"github.com/juju/juju/rpc/cborcodec"
+)
+
+// EncodingBenchSuite compares the cost of sending the largest API
+// results as JSON and as CBOR. Run the benchmarks with
+//
+//	go test ./apiserver/params -check.b -check.f EncodingBenchSuite -check.bmem
+type EncodingBenchSuite struct{}
+
+var _ = gc.Suite(&EncodingBenchSuite{})
+
+type encoding struct {
+	marshal   func(interface{}) ([]byte, error)
+	unmarshal func([]byte, interface{}) error
+}
+
+var (
+	jsonEncoding = encoding{json.Marshal, json.Unmarshal}
+	cborEncoding = encoding{cborcodec.Marshal, cborcodec.Unmarshal}
+)
+
+func (s *EncodingBenchSuite) TestCBORIsSmaller(c *gc.C) {
+	for _, v := range []interface{}{
+		bigFullStatus(),
+		bigAllWatcherResults(),
+	} {
+		jsonData, err := json.Marshal(v)
+		c.Assert(err, jc.ErrorIsNil)
+		cborData, err := cborcodec.Marshal(v)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Logf("%T: %d bytes as JSON, %d bytes as CBOR", v, len(jsonData), len(cborData))
+		c.Assert(len(cborData) < len(jsonData), jc.IsTrue)
+	}
+}
+
+func (s *EncodingBenchSuite) BenchmarkMarshalFullStatusJSON(c *gc.C) {
+	benchmarkMarshal(c, jsonEncoding, bigFullStatus())
+}
+
+func (s *EncodingBenchSuite) BenchmarkMarshalFullStatusCBOR(c *gc.C) {
+	benchmarkMarshal(c, cborEncoding, bigFullStatus())
+}
+
+func (s *EncodingBenchSuite) BenchmarkUnmarshalFullStatusJSON(c *gc.C) {
+	benchmarkUnmarshal(c, jsonEncoding, bigFullStatus())
+}
+
+func (s *EncodingBenchSuite) BenchmarkUnmarshalFullStatusCBOR(c *gc.C) {
+	benchmarkUnmarshal(c, cborEncoding, bigFullStatus())
+}
+
+func (s *EncodingBenchSuite) BenchmarkMarshalDeltasJSON(c *gc.C) {
+	benchmarkMarshal(c, jsonEncoding, bigAllWatcherResults())
+}
+
+func (s *EncodingBenchSuite) BenchmarkMarshalDeltasCBOR(c *gc.C) {
+	benchmarkMarshal(c, cborEncoding, bigAllWatcherResults())
+}
+
+func (s *EncodingBenchSuite) BenchmarkUnmarshalDeltasJSON(c *gc.C) {
+	benchmarkUnmarshal(c, jsonEncoding, bigAllWatcherResults())
+}
+
+func (s *EncodingBenchSuite) BenchmarkUnmarshalDeltasCBOR(c *gc.C) {
+	benchmarkUnmarshal(c, cborEncoding, bigAllWatcherResults())
+}
+
+// benchmarkMarshal measures encoding v, reporting the throughput
+// in terms of the encoded size so that the bandwidth used by each
+// encoding can be compared.
+func benchmarkMarshal(c *gc.C, enc encoding, v interface{}) {
+	data, err := enc.marshal(v)
+	c.Assert(err, jc.ErrorIsNil)
+	c.SetBytes(int64(len(data)))
+	c.ResetTimer()
+	for i := 0; i < c.N; i++ {
+		if _, err := enc.marshal(v); err != nil {
+			c.Fatal(err)
+		}
+	}
+}
+
+// benchmarkUnmarshal measures decoding the encoding of v into
+// a new value of the same type.
+func benchmarkUnmarshal(c *gc.C, enc encoding, v interface{}) {
+	data, err := enc.marshal(v)
+	c.Assert(err, jc.ErrorIsNil)
+	c.SetBytes(int64(len(data)))
+	c.ResetTimer()
+	for i := 0; i < c.N; i++ {
+		var err error
+		switch v.(type) {
+		case *params.FullStatus:
+			err = enc.unmarshal(data, new(params.FullStatus))
+		case *params.AllWatcherNextResults:
+			err = enc.unmarshal(data, new(params.AllWatcherNextResults))
+		}
+		if err != nil {
+			c.Fatal(err)
+		}
+	}
+}
+
+var benchTime = time.Date(2020, 9, 1, 12, 0, 0, 0, time.UTC)
+
+func benchDetailedStatus(s string) params.DetailedStatus {
+	return params.DetailedStatus{
+		Status:  s,
+		Info:    "ready",
+		Since:   &benchTime,
+		Version: "2.9.0",
+		Life:    life.Alive,
+	}
+}
+
+// bigFullStatus returns the status of a model with 100 machines
+// and 20 applications of 5 units each.
+func bigFullStatus() *params.FullStatus {
+	fs := &params.FullStatus{
+		Model: params.ModelStatusInfo{
+			Name:        "bench",
+			CloudTag:    "cloud-aws",
+			CloudRegion: "us-east-1",
+			Version:     "2.9.0",
+			ModelStatus: benchDetailedStatus("available"),
+		},
+		Machines:            make(map[string]params.MachineStatus),
+		Applications:        make(map[string]params.ApplicationStatus),
+		ControllerTimestamp: &benchTime,
+	}
+	for m := 0; m < 100; m++ {
+		id := fmt.Sprint(m)
+		fs.Machines[id] = params.MachineStatus{
+			AgentStatus:    benchDetailedStatus("started"),
+			InstanceStatus: benchDetailedStatus("running"),
+			DNSName:        fmt.Sprintf("10.0.%d.%d", m/256, m%256),
+			IPAddresses:    []string{fmt.Sprintf("10.0.%d.%d", m/256, m%256)},
+			InstanceId:     instance.Id("i-0123456789abcdef" + id),
+			Series:         "focal",
+			Id:             id,
+			Hardware:       "arch=amd64 cores=2 mem=8192M root-disk=16384M",
+			Jobs:           []model.MachineJob{model.JobHostUnits},
+		}
+	}
+	for a := 0; a < 20; a++ {
+		name := fmt.Sprintf("app%d", a)
+		app := params.ApplicationStatus{
+			Charm:     "cs:" + name + "-42",
+			Series:    "focal",
+			Life:      life.Alive,
+			Relations: map[string][]string{"db": {"postgresql"}},
+			Units:     make(map[string]params.UnitStatus),
+			Status:    benchDetailedStatus("active"),
+		}
+		for u := 0; u < 5; u++ {
+			machine := fmt.Sprint((a*5 + u) % 100)
+			app.Units[fmt.Sprintf("%s/%d", name, u)] = params.UnitStatus{
+				AgentStatus:    benchDetailedStatus("idle"),
+				WorkloadStatus: benchDetailedStatus("active"),
+				Machine:        machine,
+				OpenedPorts:    []string{"80/tcp", "443/tcp"},
+				PublicAddress:  fs.Machines[machine].DNSName,
+				Charm:          app.Charm,
+				Leader:         u == 0,
+			}
+		}
+		fs.Applications[name] = app
+	}
+	return fs
+}
+
+// bigAllWatcherResults returns the deltas sent to a client that
+// starts watching a model with 100 machines and 100 units.
+func bigAllWatcherResults() *params.AllWatcherNextResults {
+	var deltas []params.Delta
+	statusInfo := func(s status.Status) params.StatusInfo {
+		return params.StatusInfo{
+			Current: s,
+			Message: "ready",
+			Since:   &benchTime,
+			Version: "2.9.0",
+		}
+	}
+	for m := 0; m < 100; m++ {
+		deltas = append(deltas, params.Delta{
+			Entity: &params.MachineInfo{
+				ModelUUID:      "deadbeef-0bad-400d-8000-4b1d0d06f00d",
+				Id:             fmt.Sprint(m),
+				InstanceId:     fmt.Sprintf("i-0123456789abcdef%d", m),
+				AgentStatus:    statusInfo(status.Started),
+				InstanceStatus: statusInfo(status.Running),
+				Life:           life.Alive,
+				Series:         "focal",
+				Jobs:           []model.MachineJob{model.JobHostUnits},
+			},
+		})
+	}
+	for u := 0; u < 100; u++ {
+		deltas = append(deltas, params.Delta{
+			Entity: &params.UnitInfo{
+				ModelUUID:      "deadbeef-0bad-400d-8000-4b1d0d06f00d",
+				Name:           fmt.Sprintf("app%d/%d", u/5, u%5),
+				Application:    fmt.Sprintf("app%d", u/5),
+				Series:         "focal",
+				CharmURL:       fmt.Sprintf("cs:app%d-42", u/5),
+				Life:           life.Alive,
+				PublicAddress:  fmt.Sprintf("10.0.0.%d", u),
+				PrivateAddress: fmt.Sprintf("10.0.0.%d", u),
+				MachineId:      fmt.Sprint(u),
+				Ports:          []params.Port{{Protocol: "tcp", Number: 80}},
+				PortRanges:     []params.PortRange{{FromPort: 80, ToPort: 80, Protocol: "tcp"}},
+				WorkloadStatus: statusInfo(status.Active),
+				AgentStatus:    statusInfo(status.Idle),
+			},
+		})
+	}
+	return &params.AllWatcherNextResults{Deltas: deltas}
+}
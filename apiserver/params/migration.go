@@ -53,6 +53,22 @@ type InitiateMigrationResult struct {
 	MigrationId string `json:"migration-id"`
 }
 
+// MigrationDryRunResults is used to return the result of checking
+// whether one or more models could be migrated.
+type MigrationDryRunResults struct {
+	Results []MigrationDryRunResult `json:"results"`
+}
+
+// MigrationDryRunResult is used to return the problems that would
+// prevent the migration of one model.
+type MigrationDryRunResult struct {
+	ModelTag      string   `json:"model-tag"`
+	Problems      []string `json:"problems,omitempty"`
+	Upconversions []string `json:"upconversions,omitempty"`
+	Notes         []string `json:"notes,omitempty"`
+	Error         *Error   `json:"error,omitempty"`
+}
+
 // SetMigrationPhaseArgs provides a migration phase to the
 // migrationmaster.SetPhase API method.
 type SetMigrationPhaseArgs struct {
@@ -74,6 +90,13 @@ type SerializedModel struct {
 	Resources []SerializedModelResource `json:"resources"`
 }
 
+// MigrationPrecheckImportResult holds the conversions the target
+// controller must make to import a serialized model.
+type MigrationPrecheckImportResult struct {
+	Upconversions []string `json:"upconversions,omitempty"`
+	Error         *Error   `json:"error,omitempty"`
+}
+
 // SerializedModelTools holds the version and URI for a given tools
 // version.
 type SerializedModelTools struct {
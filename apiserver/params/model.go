@@ -369,6 +369,10 @@ type ModifyModelAccess struct {
 	Action   ModelAction          `json:"action"`
 	Access   UserAccessPermission `json:"access"`
 	ModelTag string               `json:"model-tag"`
+
+	// GroupName names the group whose access is changed,
+	// in which case UserTag is empty.
+	GroupName string `json:"group-name,omitempty"`
 }
 
 // ModelAction is an action that can be performed on a model.
@@ -17,6 +17,7 @@ import (
 	"github.com/juju/juju/core/life"
 	"github.com/juju/juju/core/model"
 	"github.com/juju/juju/core/status"
+	"github.com/juju/juju/rpc/cborcodec"
 )
 
 // EntityInfo is implemented by all entity Info types.
@@ -84,33 +85,84 @@ func (d *Delta) UnmarshalJSON(data []byte) error {
 	} else if operation != "change" {
 		return fmt.Errorf("Unexpected operation %q", operation)
 	}
-	switch entityKind {
+	entity, err := newEntityInfo(entityKind)
+	if err != nil {
+		return err
+	}
+	d.Entity = entity
+	return json.Unmarshal(elements[2], &d.Entity)
+}
+
+// MarshalCBOR implements cborcodec.Marshaler, encoding the delta
+// in the same form as MarshalJSON.
+func (d *Delta) MarshalCBOR() ([]byte, error) {
+	c := "change"
+	if d.Removed {
+		c = "remove"
+	}
+	return cborcodec.Marshal([]interface{}{d.Entity.EntityId().Kind, c, d.Entity})
+}
+
+// UnmarshalCBOR implements cborcodec.Unmarshaler.
+func (d *Delta) UnmarshalCBOR(data []byte) error {
+	var elements []cborcodec.RawMessage
+	if err := cborcodec.Unmarshal(data, &elements); err != nil {
+		return err
+	}
+	if len(elements) != 3 {
+		return fmt.Errorf(
+			"Expected 3 elements in top-level of CBOR but got %d",
+			len(elements))
+	}
+	var entityKind, operation string
+	if err := cborcodec.Unmarshal(elements[0], &entityKind); err != nil {
+		return err
+	}
+	if err := cborcodec.Unmarshal(elements[1], &operation); err != nil {
+		return err
+	}
+	if operation == "remove" {
+		d.Removed = true
+	} else if operation != "change" {
+		return fmt.Errorf("Unexpected operation %q", operation)
+	}
+	entity, err := newEntityInfo(entityKind)
+	if err != nil {
+		return err
+	}
+	d.Entity = entity
+	return cborcodec.Unmarshal(elements[2], d.Entity)
+}
+
+// newEntityInfo returns a new value of the EntityInfo type
+// with the given kind.
+func newEntityInfo(kind string) (EntityInfo, error) {
+	switch kind {
 	case "model":
-		d.Entity = new(ModelUpdate)
+		return new(ModelUpdate), nil
 	case "machine":
-		d.Entity = new(MachineInfo)
+		return new(MachineInfo), nil
 	case "application":
-		d.Entity = new(ApplicationInfo)
+		return new(ApplicationInfo), nil
 	case "remoteApplication":
-		d.Entity = new(RemoteApplicationUpdate)
+		return new(RemoteApplicationUpdate), nil
 	case "unit":
-		d.Entity = new(UnitInfo)
+		return new(UnitInfo), nil
 	case "relation":
-		d.Entity = new(RelationInfo)
+		return new(RelationInfo), nil
 	case "annotation":
-		d.Entity = new(AnnotationInfo)
+		return new(AnnotationInfo), nil
 	case "block":
-		d.Entity = new(BlockInfo)
+		return new(BlockInfo), nil
 	case "action":
-		d.Entity = new(ActionInfo)
+		return new(ActionInfo), nil
 	case "charm":
-		d.Entity = new(CharmInfo)
+		return new(CharmInfo), nil
 	case "branch":
-		d.Entity = new(BranchInfo)
+		return new(BranchInfo), nil
 	default:
-		return errors.Errorf("Unexpected entity name %q", entityKind)
+		return nil, errors.Errorf("Unexpected entity name %q", kind)
 	}
-	return json.Unmarshal(elements[2], &d.Entity)
 }
 
 // MachineInfo holds the information about a machine
@@ -67,6 +67,10 @@ type EntityPasswords struct {
 type EntityPassword struct {
 	Tag      string `json:"tag"`
 	Password string `json:"password"`
+
+	// MFACode holds a multi-factor authentication code for the user
+	// making the change, which is required if they are enrolled.
+	MFACode string `json:"mfa-code,omitempty"`
 }
 
 // ErrorResults holds the results of calling a bulk operation which
@@ -246,6 +250,20 @@ type AddMachinesResult struct {
 	Error   *Error `json:"error,omitempty"`
 }
 
+// PrecheckMachinesResults holds the results of a PrecheckMachines call,
+// in the same order as the requested machines.
+type PrecheckMachinesResults struct {
+	Results []PrecheckMachineResult `json:"results"`
+}
+
+// PrecheckMachineResult holds the outcome of prechecking a single
+// machine. Unsupported lists any constraint attributes the provider
+// would ignore.
+type PrecheckMachineResult struct {
+	Unsupported []string `json:"unsupported,omitempty"`
+	Error       *Error   `json:"error,omitempty"`
+}
+
 // DestroyMachines holds parameters for the DestroyMachines call.
 // This is the legacy params struct used with the client facade.
 // TODO(wallyworld) - remove in Juju 3.0
@@ -527,6 +545,32 @@ type LoginRequest struct {
 	BakeryVersion bakery.Version   `json:"bakery-version,omitempty"`
 	CLIArgs       string           `json:"cli-args,omitempty"`
 	UserData      string           `json:"user-data"`
+
+	// IDToken holds an OpenID Connect ID token identifying the user.
+	// It is only used when AuthTag is empty.
+	IDToken string `json:"id-token,omitempty"`
+
+	// Token holds the secret of an API token minted for the user.
+	// It is only used when AuthTag is empty.
+	Token string `json:"token,omitempty"`
+
+	// MFACode holds a one-time or recovery code for users enrolled
+	// for multi-factor authentication, who log in with a password.
+	MFACode string `json:"mfa-code,omitempty"`
+
+	// Codecs holds, in order of preference, the message encodings
+	// other than JSON that the client can use for the rest of the
+	// connection.
+	Codecs []string `json:"codecs,omitempty"`
+}
+
+// OIDCLoginInfo describes the OpenID Connect provider that a
+// controller accepts ID tokens from.
+type OIDCLoginInfo struct {
+	Issuer   string   `json:"issuer"`
+	ClientID string   `json:"client-id"`
+	Scopes   []string `json:"scopes,omitempty"`
+	Reason   string   `json:"reason,omitempty"`
 }
 
 // LoginRequestCompat holds credentials for identifying an entity to the Login v1
@@ -561,6 +605,9 @@ type GetConstraintsResults struct {
 type SetConstraints struct {
 	ApplicationName string            `json:"application"` //optional, if empty, model constraints are set.
 	Constraints     constraints.Value `json:"constraints"`
+	// Generation is the branch the constraints are set under.
+	// If empty, the constraints are set for the master generation.
+	Generation string `json:"generation,omitempty"`
 }
 
 // ResolveCharms stores charm references for a ResolveCharms call.
@@ -701,6 +748,54 @@ type ProvisioningScriptResult struct {
 	Script string `json:"script"`
 }
 
+// ReenlistMachinesArgs holds the arguments for the ReenlistMachines
+// MachineManager API call.
+type ReenlistMachinesArgs struct {
+	Machines []ReenlistMachineArg `json:"machines"`
+}
+
+// ReenlistMachineArg describes the reinstalled host of a manually
+// provisioned machine, which is to be provisioned again under the
+// machine's existing ID.
+type ReenlistMachineArg struct {
+	MachineTag string `json:"machine-tag"`
+
+	// Nonce is the nonce the agent installed on the host will
+	// use to log in. It must have the same form as the nonces of
+	// manually provisioned machines.
+	Nonce string `json:"nonce"`
+
+	// Series and HardwareCharacteristics are detected on the host,
+	// and must be compatible with those recorded for the machine.
+	Series                  string                           `json:"series"`
+	HardwareCharacteristics instance.HardwareCharacteristics `json:"hardware-characteristics"`
+}
+
+// MachineUserDataResults holds the results of a MachineUserData
+// MachineManager API call.
+type MachineUserDataResults struct {
+	Results []MachineUserDataResult `json:"results"`
+}
+
+// MachineUserDataResult holds the cloud-init user data that is used
+// when provisioning a machine.
+type MachineUserDataResult struct {
+	// UserData is the model's cloudinit-userdata, with the overlays
+	// matching the machine's constraints merged in, as YAML.
+	UserData string `json:"userdata,omitempty"`
+
+	// MatchedOverlays holds the match constraints of the overlays
+	// that were merged, in the order they were merged.
+	MatchedOverlays []string `json:"matched-overlays,omitempty"`
+
+	// Preview is the cloud-config produced by merging the user data
+	// with Juju's own sections. It is empty if previews are not
+	// supported for the machine's series.
+	Preview string `json:"preview,omitempty"`
+
+	Error *Error `json:"error,omitempty"`
+}
+
 // DeployerConnectionValues containers the result of deployer.ConnectionInfo
 // API call.
 type DeployerConnectionValues struct {
@@ -802,6 +897,12 @@ type LoginResult struct {
 	// required.
 	DischargeRequiredReason string `json:"discharge-required-error,omitempty"`
 
+	// OIDCLoginRequired implies that the login request has failed, and
+	// none of the other fields are populated. It describes where to
+	// obtain an ID token which will grant access on a subsequent call
+	// to Login.
+	OIDCLoginRequired *OIDCLoginInfo `json:"oidc-login-required,omitempty"`
+
 	// Servers is the list of API server addresses.
 	Servers [][]HostPort `json:"servers,omitempty"`
 
@@ -827,6 +928,11 @@ type LoginResult struct {
 	// ServerVersion is the string representation of the server version
 	// if the server supports it.
 	ServerVersion string `json:"server-version,omitempty"`
+
+	// Codec holds the encoding, chosen from those requested by the
+	// client, which the client may use for subsequent requests. If
+	// it is empty, only JSON may be used.
+	Codec string `json:"codec,omitempty"`
 }
 
 // ControllersServersSpec contains arguments for
@@ -987,6 +1093,88 @@ type BundleChange struct {
 	Requires []string `json:"requires"`
 }
 
+// BundleDiffModelsParams holds parameters for making Bundle.DiffModels
+// calls.
+type BundleDiffModelsParams struct {
+	// FromModelTag and ToModelTag identify the models whose exported
+	// bundles are compared.
+	FromModelTag string `json:"from-model-tag"`
+	ToModelTag   string `json:"to-model-tag"`
+
+	// BaseBundleDataYAML optionally holds YAML-encoded bundle data used
+	// as the common base of a three-way comparison.
+	BaseBundleDataYAML string `json:"base-bundle-yaml,omitempty"`
+
+	// IncludeAnnotations indicates whether differences in annotations
+	// are reported.
+	IncludeAnnotations bool `json:"include-annotations,omitempty"`
+}
+
+// BundleDiff holds the differences between the bundles exported from
+// two models and, for a three-way comparison, a base bundle. Sides are
+// named "base", "from" and "to".
+type BundleDiff struct {
+	Applications map[string]ApplicationBundleDiff `json:"applications,omitempty"`
+	Machines     map[string]MachineBundleDiff     `json:"machines,omitempty"`
+	Saas         map[string]SaasBundleDiff        `json:"saas,omitempty"`
+	Relations    []RelationBundleDiff             `json:"relations,omitempty"`
+}
+
+// BundleDiffValue holds the values of an attribute that differs
+// between the compared bundles.
+type BundleDiffValue struct {
+	Base interface{} `json:"base,omitempty"`
+	From interface{} `json:"from,omitempty"`
+	To   interface{} `json:"to,omitempty"`
+}
+
+// ApplicationBundleDiff holds the differences in an application.
+type ApplicationBundleDiff struct {
+	// Missing lists the sides the application is absent from.
+	Missing []string `json:"missing,omitempty"`
+
+	Charm       *BundleDiffValue           `json:"charm,omitempty"`
+	Channel     *BundleDiffValue           `json:"channel,omitempty"`
+	Series      *BundleDiffValue           `json:"series,omitempty"`
+	NumUnits    *BundleDiffValue           `json:"num-units,omitempty"`
+	Scale       *BundleDiffValue           `json:"scale,omitempty"`
+	Placement   *BundleDiffValue           `json:"placement,omitempty"`
+	Expose      *BundleDiffValue           `json:"expose,omitempty"`
+	Trust       *BundleDiffValue           `json:"trust,omitempty"`
+	Constraints *BundleDiffValue           `json:"constraints,omitempty"`
+	Options     map[string]BundleDiffValue `json:"options,omitempty"`
+	Annotations map[string]BundleDiffValue `json:"annotations,omitempty"`
+	Bindings    map[string]BundleDiffValue `json:"bindings,omitempty"`
+	Offers      map[string]BundleDiffValue `json:"offers,omitempty"`
+}
+
+// MachineBundleDiff holds the differences in a machine.
+type MachineBundleDiff struct {
+	// Missing lists the sides the machine is absent from.
+	Missing []string `json:"missing,omitempty"`
+
+	Series      *BundleDiffValue           `json:"series,omitempty"`
+	Constraints *BundleDiffValue           `json:"constraints,omitempty"`
+	Annotations map[string]BundleDiffValue `json:"annotations,omitempty"`
+}
+
+// SaasBundleDiff holds the differences in a consumed offer.
+type SaasBundleDiff struct {
+	// Missing lists the sides the offer is not consumed by.
+	Missing []string `json:"missing,omitempty"`
+
+	URL *BundleDiffValue `json:"url,omitempty"`
+}
+
+// RelationBundleDiff identifies a relation that is absent from some of
+// the compared bundles.
+type RelationBundleDiff struct {
+	Endpoints []string `json:"endpoints"`
+
+	// Missing lists the sides the relation is absent from.
+	Missing []string `json:"missing"`
+}
+
 // BundleChangesMapArgsResults holds results of the Bundle.GetChanges call.
 type BundleChangesMapArgsResults struct {
 	// Changes holds the list of changes required to deploy the bundle.
@@ -1214,6 +1402,16 @@ type BranchArg struct {
 	BranchName string `json:"branch"`
 }
 
+// BranchPromotionArg sets the promotion policy for an in-flight branch.
+type BranchPromotionArg struct {
+	BranchName string `json:"branch"`
+
+	// SoakTime is how long the units tracking the branch must be
+	// healthy before it is automatically committed. Zero cancels
+	// the policy.
+	SoakTime time.Duration `json:"soak-time"`
+}
+
 // GenerationId represents an GenerationId from a branch.
 type GenerationId struct {
 	GenerationId int `json:"generation-id"`
@@ -1257,6 +1455,14 @@ type GenerationApplication struct {
 	// Config changes are the effective new configuration values resulting from
 	// changes made under this branch.
 	ConfigChanges map[string]interface{} `json:"config"`
+
+	// CharmURL is the charm the application is upgraded to under this
+	// branch, if any.
+	CharmURL string `json:"charm-url,omitempty"`
+
+	// Constraints are the application constraints set under this
+	// branch, if any.
+	Constraints *constraints.Value `json:"constraints,omitempty"`
 }
 
 // Generation represents a model generation's details including config changes.
@@ -1279,6 +1485,10 @@ type Generation struct {
 	// GenerationId is the id .
 	GenerationId int `json:"generation-id,omitempty"`
 
+	// PromoteAt is the Unix timestamp at which the branch is due to be
+	// automatically committed or aborted. Zero indicates no policy.
+	PromoteAt int64 `json:"promote-at,omitempty"`
+
 	// Applications holds the collection of application changes
 	// made under this generation.
 	Applications []GenerationApplication `json:"applications"`
@@ -17,6 +17,7 @@ import (
 	"github.com/juju/juju/core/life"
 	"github.com/juju/juju/core/model"
 	"github.com/juju/juju/core/status"
+	"github.com/juju/juju/rpc/cborcodec"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/testing"
 )
@@ -227,6 +228,49 @@ func (s *MarshalSuite) TestDeltaMarshalJSONUnknownEntity(c *gc.C) {
 	c.Check(err, gc.ErrorMatches, `Unexpected entity name "qwan"`)
 }
 
+func (s *MarshalSuite) TestDeltaMarshalCBOR(c *gc.C) {
+	for i, t := range marshalTestCases {
+		c.Logf("test %d. %s", i, t.about)
+		output, err := cborcodec.Marshal(&t.value)
+		c.Check(err, jc.ErrorIsNil)
+		// The CBOR encoding decodes to the same value as the JSON.
+		var unmarshalledOutput interface{}
+		err = cborcodec.Unmarshal(output, &unmarshalledOutput)
+		c.Check(err, jc.ErrorIsNil)
+		var expected interface{}
+		err = json.Unmarshal([]byte(t.json), &expected)
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(unmarshalledOutput, jc.DeepEquals, expected)
+
+		var unmarshalled params.Delta
+		err = cborcodec.Unmarshal(output, &unmarshalled)
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(unmarshalled, jc.DeepEquals, t.value)
+	}
+}
+
+func (s *MarshalSuite) TestDeltaUnmarshalCBORErrors(c *gc.C) {
+	for i, test := range []struct {
+		value  []interface{}
+		expect string
+	}{{
+		value:  []interface{}{1, 2},
+		expect: "Expected 3 elements in top-level of CBOR but got 2",
+	}, {
+		value:  []interface{}{"relation", "masticate", map[string]interface{}{}},
+		expect: `Unexpected operation "masticate"`,
+	}, {
+		value:  []interface{}{"qwan", "change", map[string]interface{}{}},
+		expect: `Unexpected entity name "qwan"`,
+	}} {
+		c.Logf("test %d. %v", i, test.value)
+		data, err := cborcodec.Marshal(test.value)
+		c.Assert(err, jc.ErrorIsNil)
+		err = cborcodec.Unmarshal(data, new(params.Delta))
+		c.Check(err, gc.ErrorMatches, test.expect)
+	}
+}
+
 type ErrorResultsSuite struct{}
 
 var _ = gc.Suite(&ErrorResultsSuite{})
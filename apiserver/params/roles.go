@@ -0,0 +1,37 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package params
+
+// Role holds a custom role: a named set of operation classes and
+// facade methods that may be assigned to model users, and the greatest
+// level of model access the role grants. Clients that do not send an
+// access level get write access.
+type Role struct {
+	Name       string   `json:"name"`
+	Access     string   `json:"access,omitempty"`
+	Operations []string `json:"operations"`
+}
+
+// Roles holds custom roles.
+type Roles struct {
+	Roles []Role `json:"roles"`
+}
+
+// RoleNames holds the names of custom roles.
+type RoleNames struct {
+	Names []string `json:"names"`
+}
+
+// ModelUserRole holds the custom role assigned to a user on a model.
+// An empty Role removes the user's role.
+type ModelUserRole struct {
+	ModelTag string `json:"model-tag"`
+	UserTag  string `json:"user-tag"`
+	Role     string `json:"role,omitempty"`
+}
+
+// ModelUserRoles holds the custom roles assigned to users on models.
+type ModelUserRoles struct {
+	Roles []ModelUserRole `json:"roles"`
+}
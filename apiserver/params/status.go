@@ -13,6 +13,7 @@ import (
 	"github.com/juju/juju/core/life"
 	"github.com/juju/juju/core/model"
 	"github.com/juju/juju/core/relation"
+	"github.com/juju/juju/rpc/cborcodec"
 )
 
 // StatusParams holds parameters for the Status call.
@@ -175,6 +176,19 @@ func (as ApplicationStatus) MarshalJSON() ([]byte, error) {
 	})
 }
 
+// MarshalCBOR marshals a status in the same way as MarshalJSON,
+// without going through JSON.
+func (as ApplicationStatus) MarshalCBOR() ([]byte, error) {
+	type Alias ApplicationStatus
+	return cborcodec.Marshal(&struct {
+		LegacyCharmVersion string `json:"charm-verion"`
+		Alias
+	}{
+		LegacyCharmVersion: as.CharmVersion,
+		Alias:              Alias(as),
+	})
+}
+
 // RemoteApplicationStatus holds status info about a remote application.
 type RemoteApplicationStatus struct {
 	Err       *Error              `json:"err,omitempty"`
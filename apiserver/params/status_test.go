@@ -11,6 +11,7 @@ import (
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/rpc/cborcodec"
 )
 
 type StatusSuite struct{}
@@ -29,3 +30,21 @@ func (s *StatusSuite) TestMarshallApplicationStatusCharmVersion(c *gc.C) {
 "version":"","life":""},"workload-version":"","charm-version":"666",
 "charm-profile":"","endpoint-bindings":null,"public-address":""}`, "\n", "", -1))
 }
+
+func (s *StatusSuite) TestMarshalApplicationStatusCBOR(c *gc.C) {
+	as := params.ApplicationStatus{
+		CharmVersion: "666",
+	}
+	jsonData, err := json.Marshal(as)
+	c.Assert(err, jc.ErrorIsNil)
+	var viaJSON interface{}
+	err = json.Unmarshal(jsonData, &viaJSON)
+	c.Assert(err, jc.ErrorIsNil)
+
+	cborData, err := as.MarshalCBOR()
+	c.Assert(err, jc.ErrorIsNil)
+	var viaCBOR interface{}
+	err = cborcodec.Unmarshal(cborData, &viaCBOR)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(viaCBOR, jc.DeepEquals, viaJSON)
+}
@@ -16,6 +16,19 @@ type UserInfo struct {
 	DateCreated    time.Time  `json:"date-created"`
 	LastConnection *time.Time `json:"last-connection,omitempty"`
 	Disabled       bool       `json:"disabled"`
+
+	// MFAEnabled is set if the user is enrolled for multi-factor
+	// authentication, in which case MFARecoveryCodes holds the
+	// number of unused recovery codes they have.
+	MFAEnabled       bool `json:"mfa-enabled,omitempty"`
+	MFARecoveryCodes int  `json:"mfa-recovery-codes,omitempty"`
+
+	// FailedLogins holds the number of consecutive failed logins for
+	// the user. LockedUntil is set if they are locked out as a result,
+	// and PasswordExpires if their password expires.
+	FailedLogins    int        `json:"failed-logins,omitempty"`
+	LockedUntil     *time.Time `json:"locked-until,omitempty"`
+	PasswordExpires *time.Time `json:"password-expires,omitempty"`
 }
 
 // UserInfoResult holds the result of a UserInfo call.
@@ -67,3 +80,25 @@ type AddUserResult struct {
 	SecretKey []byte `json:"secret-key,omitempty"`
 	Error     *Error `json:"error,omitempty"`
 }
+
+// MFAEnrolment holds the shared secret for a user's authenticator app,
+// returned when they begin enrolling for multi-factor authentication.
+type MFAEnrolment struct {
+	Secret string `json:"secret"`
+
+	// URI holds the otpauth URI for the secret, which authenticator
+	// apps accept as a QR code.
+	URI string `json:"uri"`
+}
+
+// MFACode holds a one-time code from a user's authenticator, or one of
+// their recovery codes.
+type MFACode struct {
+	Code string `json:"code"`
+}
+
+// MFARecoveryCodes holds the recovery codes issued when a user
+// completes multi-factor authentication enrolment.
+type MFARecoveryCodes struct {
+	Codes []string `json:"codes"`
+}
@@ -15,6 +15,7 @@ import (
 	"gopkg.in/macaroon-bakery.v2/httpbakery"
 	"gopkg.in/macaroon.v2"
 
+	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/charmstore"
 	"github.com/juju/juju/state"
@@ -150,6 +151,13 @@ func (h *registerUserHandler) processPost(req *http.Request, st *state.State) (
 	if err := json.Unmarshal(payloadBytes, &requestPayload); err != nil {
 		return failure(errors.Annotate(err, "cannot unmarshal payload"))
 	}
+	controllerConfig, err := st.ControllerConfig()
+	if err != nil {
+		return failure(errors.Trace(err))
+	}
+	if err := common.ValidatePassword(controllerConfig, requestPayload.Password); err != nil {
+		return failure(errors.Trace(err))
+	}
 	if err := user.SetPassword(requestPayload.Password); err != nil {
 		return failure(errors.Annotate(err, "setting new password"))
 	}
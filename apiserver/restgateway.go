@@ -0,0 +1,601 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/juju/clock"
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+	"github.com/juju/rpcreflect"
+	"github.com/juju/worker/v2/catacomb"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/apiserver/httpcontext"
+	"github.com/juju/juju/apiserver/observer"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/auditlog"
+	"github.com/juju/juju/core/permission"
+	"github.com/juju/juju/rpc"
+	"github.com/juju/juju/state"
+)
+
+// gatewayMethod describes a client facade method served by the REST
+// gateway.
+type gatewayMethod struct {
+	facade string
+	method string
+
+	// readOnly is set for methods that don't change anything. These
+	// may be called with GET, with the zero value as their argument,
+	// as well as with POST.
+	readOnly bool
+}
+
+// gatewayMethods holds the client facade methods served by the REST
+// gateway. Every version of a facade that has the method is served.
+// Methods of the controller facades are served at the root of the
+// API server and all others under the model they apply to.
+var gatewayMethods = []gatewayMethod{
+	{"Action", "Actions", true},
+	{"Action", "ApplicationsCharmsActions", true},
+	{"Action", "EnqueueOperation", false},
+	{"Action", "ListOperations", true},
+	{"Action", "Operations", true},
+	{"Annotations", "Get", true},
+	{"Annotations", "Set", false},
+	{"Application", "AddUnits", false},
+	{"Application", "ApplicationsInfo", true},
+	{"Application", "CharmConfig", true},
+	{"Application", "Expose", false},
+	{"Application", "Get", true},
+	{"Application", "GetConstraints", true},
+	{"Application", "ScaleApplications", false},
+	{"Application", "SetApplicationsConfig", false},
+	{"Application", "Unexpose", false},
+	{"Client", "FullStatus", true},
+	{"Client", "StatusHistory", true},
+	{"Cloud", "Cloud", true},
+	{"Cloud", "Clouds", true},
+	{"Controller", "AllModels", true},
+	{"Controller", "ControllerVersion", true},
+	{"Controller", "ModelStatus", true},
+	{"ModelConfig", "ModelGet", true},
+	{"ModelConfig", "ModelSet", false},
+	{"ModelManager", "ListModelSummaries", true},
+	{"ModelManager", "ListModels", true},
+	{"ModelManager", "ModelInfo", true},
+}
+
+// maxGatewayRequestSize limits the size of the JSON arguments
+// accepted by the REST gateway.
+const maxGatewayRequestSize = 4 * 1024 * 1024
+
+// gatewayControllerFacade reports whether the REST gateway serves
+// the given facade at the root of the API server rather than under
+// a model.
+func gatewayControllerFacade(facadeName string) bool {
+	return controllerFacadeNames.Contains(facadeName)
+}
+
+// lookupGatewayMethod returns the REST gateway method with the given
+// facade and method names.
+func lookupGatewayMethod(facadeName, methodName string) (gatewayMethod, bool) {
+	for _, m := range gatewayMethods {
+		if m.facade == facadeName && m.method == methodName {
+			return m, true
+		}
+	}
+	return gatewayMethod{}, false
+}
+
+// gatewayMethodType returns the type of the given version of the
+// REST gateway method, or a not found error if that version of its
+// facade does not have the method.
+func gatewayMethodType(registry *facade.Registry, m gatewayMethod, version int) (rpcreflect.ObjMethod, error) {
+	goType, err := registry.GetType(m.facade, version)
+	if err != nil {
+		return rpcreflect.ObjMethod{}, errors.NotFoundf("%s facade version %d", m.facade, version)
+	}
+	method, err := rpcreflect.ObjTypeOf(goType).Method(m.method)
+	if err != nil {
+		return rpcreflect.ObjMethod{}, errors.NotFoundf("method %s(%d).%s", m.facade, version, m.method)
+	}
+	return method, nil
+}
+
+// parseGatewayVersion parses a facade version in the form used in
+// REST gateway URLs, for example "v2".
+func parseGatewayVersion(s string) (int, error) {
+	if !strings.HasPrefix(s, "v") {
+		return 0, errors.BadRequestf("invalid facade version %q", s)
+	}
+	version, err := strconv.Atoi(s[1:])
+	if err != nil || version < 0 {
+		return 0, errors.BadRequestf("invalid facade version %q", s)
+	}
+	return version, nil
+}
+
+// restGatewayHandler serves calls to client facade methods made
+// with plain HTTP requests, taking the method's arguments as a JSON
+// request body and sending its result as the JSON response body.
+type restGatewayHandler struct {
+	ctxt httpContext
+
+	// controllerOnly is set for the handler serving the controller
+	// facades at the root of the API server.
+	controllerOnly bool
+
+	roots *gatewayRootCache
+}
+
+// ServeHTTP implements http.Handler.
+func (h *restGatewayHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := h.serve(w, r); err != nil {
+		if err := sendError(w, errors.Trace(err)); err != nil {
+			logger.Errorf("%v", errors.Annotate(err, "cannot return error to user"))
+		}
+	}
+}
+
+func (h *restGatewayHandler) serve(w http.ResponseWriter, r *http.Request) error {
+	if !h.ctxt.srv.shared.restGatewayEnabled() {
+		return errors.NotFoundf("REST gateway")
+	}
+	query := r.URL.Query()
+	facadeName, methodName := query.Get(":facade"), query.Get(":method")
+	m, ok := lookupGatewayMethod(facadeName, methodName)
+	if !ok || gatewayControllerFacade(facadeName) != h.controllerOnly {
+		return errors.NotFoundf("method %s.%s", facadeName, methodName)
+	}
+	version, err := parseGatewayVersion(query.Get(":version"))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if _, err := gatewayMethodType(h.ctxt.srv.facades, m, version); err != nil {
+		return errors.Trace(err)
+	}
+	switch r.Method {
+	case "POST":
+	case "GET":
+		if !m.readOnly {
+			return errors.MethodNotAllowedf("method %s.%s must be called with POST", facadeName, methodName)
+		}
+	default:
+		return errors.Trace(emitUnsupportedMethodErr(r.Method))
+	}
+
+	st, err := h.ctxt.stateForRequestAuthenticatedUser(r)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer st.Release()
+	authInfo, ok := httpcontext.RequestAuthInfo(r)
+	if !ok {
+		return common.ErrPerm
+	}
+	if !m.readOnly {
+		// The facade would refuse the change anyway, but refusing
+		// it here means a read-only token never reaches a method
+		// that can change the model.
+		if err := checkTokenWriteAccess(authInfo); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	model, err := st.Model()
+	if errors.IsNotFound(err) {
+		return common.UnknownModelError(st.ModelUUID())
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+
+	// Each call is observed and audited as if it were made on its
+	// own API connection.
+	srv := h.ctxt.srv
+	connectionID := atomic.AddUint64(&srv.lastConnectionID, 1)
+	apiObserver := srv.newObserver()
+	apiObserver.Join(r, connectionID)
+	defer apiObserver.Leave()
+
+	root, release, err := h.apiRoot(r, st.State, model, authInfo, connectionID)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer release()
+	apiObserver.Login(authInfo.Entity.Tag(), model.ModelTag(), false, "")
+
+	caller, err := root.FindMethod(facadeName, version, methodName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	var arg reflect.Value
+	var body interface{} = struct{}{}
+	if paramsType := caller.ParamsType(); paramsType != nil {
+		argp := reflect.New(paramsType)
+		if r.Method == "POST" {
+			if err := decodeGatewayArg(w, r, argp.Interface()); err != nil {
+				return errors.Trace(err)
+			}
+		}
+		arg = argp.Elem()
+		body = arg.Interface()
+	}
+
+	auditConfig := srv.GetAuditConfig()
+	var auditRecorder *auditlog.Recorder
+	if auditConfig.Enabled {
+		auditRecorder, err = newAuditRecorder(srv, auditConfig, auditlog.ConversationArgs{
+			Who:          authInfo.Entity.Tag().Id(),
+			What:         r.Method + " " + r.URL.Path,
+			ModelName:    model.Name(),
+			ModelUUID:    model.UUID(),
+			ConnectionID: connectionID,
+			TokenID:      authInfo.TokenID,
+		})
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	recorder := observer.NewRecorderFactory(apiObserver, auditRecorder, auditConfig.CaptureAPIArgs)()
+	hdr := &rpc.Header{
+		RequestId: 1,
+		Request: rpc.Request{
+			Type:    facadeName,
+			Version: version,
+			Action:  methodName,
+		},
+		Version: 1,
+	}
+	// As for websocket API requests, a call that can't be
+	// recorded isn't made.
+	if err := recorder.HandleRequest(hdr, body); err != nil {
+		return errors.Annotate(err, "cannot record request")
+	}
+
+	logger.Debugf("REST gateway call %s(%d).%s by %s", facadeName, version, methodName, authInfo.Entity.Tag())
+	result, err := caller.Call(r.Context(), "", arg)
+	replyHdr := &rpc.Header{
+		RequestId: hdr.RequestId,
+		Version:   hdr.Version,
+	}
+	var response interface{} = struct{}{}
+	if err != nil {
+		perr := common.ServerError(err)
+		replyHdr.Error, replyHdr.ErrorCode = perr.Message, perr.Code
+	} else if caller.ResultType() != nil {
+		response = result.Interface()
+	}
+	if err := recorder.HandleReply(hdr.Request, replyHdr, response); err != nil {
+		logger.Errorf("error recording reply %+v: %T %+v", replyHdr, err, err)
+	}
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(sendStatusAndJSON(w, http.StatusOK, response))
+}
+
+// apiRoot returns the root used to serve a call made by the given
+// user to the given model, restricted in the same way as the root of
+// a websocket API connection logged in as that user, and a function
+// to call when the call has finished. The user's access is checked
+// for every call, but the unrestricted root is shared by the calls a
+// user makes to a model within gatewayRootExpiry.
+func (h *restGatewayHandler) apiRoot(
+	r *http.Request,
+	st *state.State,
+	model *state.Model,
+	authInfo httpcontext.AuthInfo,
+	connectionID uint64,
+) (rpc.Root, func(), error) {
+	srv := h.ctxt.srv
+	key := gatewayRootKey{
+		modelUUID:      st.ModelUUID(),
+		controllerOnly: h.controllerOnly,
+		user:           authInfo.Entity.Tag().String(),
+		tokenID:        authInfo.TokenID,
+		tokenAccess:    authInfo.TokenAccess,
+	}
+	cached := h.roots.get(key, st)
+	if cached == nil {
+		handler, apiRoot, err := h.newAPIRoot(r, st, authInfo, connectionID)
+		if err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+		cached = &gatewayRoot{
+			handler: handler,
+			root:    apiRoot,
+			st:      st,
+			expires: srv.clock.Now().Add(gatewayRootExpiry),
+		}
+		h.roots.add(key, cached)
+	}
+	release := func() {
+		h.roots.release(cached)
+	}
+
+	// Callers need the same access as they would need to log in.
+	userTag := authInfo.Entity.Tag().(names.UserTag)
+	a := &admin{srv: srv, root: cached.handler}
+	if _, err := a.checkUserPermissions(userTag, h.controllerOnly); err != nil {
+		release()
+		return nil, nil, errors.Trace(err)
+	}
+	root, err := restrictAPIRoot(srv, cached.root, model, authResult{
+		tag:                 userTag,
+		userLogin:           true,
+		controllerOnlyLogin: h.controllerOnly,
+	})
+	if err != nil {
+		release()
+		return nil, nil, errors.Trace(err)
+	}
+	return root, release, nil
+}
+
+// newAPIRoot returns a new unrestricted root for calls made by the
+// given user, along with the handler holding its resources.
+func (h *restGatewayHandler) newAPIRoot(
+	r *http.Request,
+	st *state.State,
+	authInfo httpcontext.AuthInfo,
+	connectionID uint64,
+) (*apiHandler, rpc.Root, error) {
+	srv := h.ctxt.srv
+	modelUUID := st.ModelUUID()
+	if h.controllerOnly {
+		modelUUID = ""
+	}
+	handler, err := newAPIHandler(srv, st, nil, modelUUID, connectionID, r.Host)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	if handler.model == nil {
+		handler.Kill()
+		return nil, nil, common.UnknownModelError(st.ModelUUID())
+	}
+	handler.entity = authInfo.Entity
+	handler.tokenID = authInfo.TokenID
+	handler.tokenAccess = authInfo.TokenAccess
+	apiRoot, err := newAPIRoot(srv.clock, st, srv.shared, srv.facades, handler.resources, handler)
+	if err != nil {
+		handler.Kill()
+		return nil, nil, errors.Trace(err)
+	}
+	return handler, apiRoot, nil
+}
+
+// gatewayRootExpiry is how long the REST gateway keeps the root it
+// built for a user's calls to a model.
+const gatewayRootExpiry = time.Minute
+
+// gatewayRootEvictionInterval is how often the REST gateway looks for
+// expired roots to release.
+const gatewayRootEvictionInterval = 10 * time.Second
+
+// gatewayRootKey identifies the calls that can share a root.
+type gatewayRootKey struct {
+	modelUUID      string
+	controllerOnly bool
+	user           string
+	tokenID        string
+	tokenAccess    permission.Access
+}
+
+// gatewayRoot holds a root cached by the REST gateway.
+type gatewayRoot struct {
+	handler *apiHandler
+	root    rpc.Root
+
+	// st holds the state the root was built with. The root isn't
+	// used after the state pool has replaced it.
+	st      *state.State
+	expires time.Time
+
+	// calls counts the calls using the root. The root's resources
+	// are released once it has been evicted and no calls are using
+	// it.
+	calls   int
+	evicted bool
+}
+
+// gatewayRootCache holds the roots built by the REST gateway, so
+// that a client making a series of calls doesn't need a new root
+// for each of them. It is a worker that evicts roots as they expire,
+// and evicts every root when it is killed.
+type gatewayRootCache struct {
+	catacomb catacomb.Catacomb
+	clock    clock.Clock
+
+	mu    sync.Mutex
+	roots map[gatewayRootKey]*gatewayRoot
+	dead  bool
+}
+
+// newGatewayRootCache returns a new gatewayRootCache that uses the
+// given clock to expire roots.
+func newGatewayRootCache(clock clock.Clock) (*gatewayRootCache, error) {
+	c := &gatewayRootCache{
+		clock: clock,
+		roots: make(map[gatewayRootKey]*gatewayRoot),
+	}
+	err := catacomb.Invoke(catacomb.Plan{
+		Site: &c.catacomb,
+		Work: c.loop,
+	})
+	return c, errors.Trace(err)
+}
+
+// Kill implements worker.Worker.
+func (c *gatewayRootCache) Kill() {
+	c.catacomb.Kill(nil)
+}
+
+// Wait implements worker.Worker.
+func (c *gatewayRootCache) Wait() error {
+	return c.catacomb.Wait()
+}
+
+func (c *gatewayRootCache) loop() error {
+	defer c.evictAll()
+	for {
+		select {
+		case <-c.catacomb.Dying():
+			return c.catacomb.ErrDying()
+		case <-c.clock.After(gatewayRootEvictionInterval):
+			c.evictExpired()
+		}
+	}
+}
+
+// evictExpired evicts the roots that have expired.
+func (c *gatewayRootCache) evictExpired() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.clock.Now()
+	for key, root := range c.roots {
+		if !now.Before(root.expires) {
+			c.evict(key, root)
+		}
+	}
+}
+
+// evictAll evicts every root, including those added later, when the
+// cache has stopped.
+func (c *gatewayRootCache) evictAll() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key, root := range c.roots {
+		c.evict(key, root)
+	}
+	c.dead = true
+}
+
+// get returns the root cached for the given key and state, or nil if
+// there is none. A returned root must be released when the call using
+// it has finished.
+func (c *gatewayRootCache) get(key gatewayRootKey, st *state.State) *gatewayRoot {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	root, ok := c.roots[key]
+	if !ok {
+		return nil
+	}
+	if root.st != st || !c.clock.Now().Before(root.expires) {
+		c.evict(key, root)
+		return nil
+	}
+	root.calls++
+	return root
+}
+
+// add caches the given root, replacing any cached for the same key.
+// The root must be released when the call using it has finished.
+func (c *gatewayRootCache) add(key gatewayRootKey, root *gatewayRoot) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	root.calls++
+	if c.dead {
+		root.evicted = true
+		return
+	}
+	if old, ok := c.roots[key]; ok {
+		c.evict(key, old)
+	}
+	c.roots[key] = root
+}
+
+// release records that a call using the given root has finished.
+func (c *gatewayRootCache) release(root *gatewayRoot) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	root.calls--
+	if root.evicted && root.calls == 0 {
+		root.handler.Kill()
+	}
+}
+
+func (c *gatewayRootCache) evict(key gatewayRootKey, root *gatewayRoot) {
+	delete(c.roots, key)
+	root.evicted = true
+	if root.calls == 0 {
+		root.handler.Kill()
+	}
+}
+
+// decodeGatewayArg decodes the JSON request body into argp. An empty
+// body leaves argp unchanged. A body of any other content type is
+// refused.
+func decodeGatewayArg(w http.ResponseWriter, r *http.Request, argp interface{}) error {
+	ctype := r.Header.Get("Content-Type")
+	if ctype == "" && r.ContentLength == 0 {
+		return nil
+	}
+	if mediaType, _, err := mime.ParseMediaType(ctype); err != nil || mediaType != params.ContentTypeJSON {
+		return &params.Error{
+			Code:    params.CodeUnsupportedMediaType,
+			Message: fmt.Sprintf("unsupported content type %q: expected %q", ctype, params.ContentTypeJSON),
+		}
+	}
+	body := http.MaxBytesReader(w, r.Body, maxGatewayRequestSize)
+	if err := json.NewDecoder(body).Decode(argp); err != nil && err != io.EOF {
+		return errors.BadRequestf("cannot decode arguments: %v", err)
+	}
+	return nil
+}
+
+// restGatewayOpenAPIHandler serves the OpenAPI document describing the
+// methods served by the REST gateway.
+type restGatewayOpenAPIHandler struct {
+	ctxt httpContext
+
+	once     sync.Once
+	document []byte
+	err      error
+}
+
+// ServeHTTP implements http.Handler.
+func (h *restGatewayOpenAPIHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := h.serve(w, r); err != nil {
+		if err := sendError(w, errors.Trace(err)); err != nil {
+			logger.Errorf("%v", errors.Annotate(err, "cannot return error to user"))
+		}
+	}
+}
+
+func (h *restGatewayOpenAPIHandler) serve(w http.ResponseWriter, r *http.Request) error {
+	if !h.ctxt.srv.shared.restGatewayEnabled() {
+		return errors.NotFoundf("REST gateway")
+	}
+	if r.Method != "GET" {
+		return errors.Trace(emitUnsupportedMethodErr(r.Method))
+	}
+	// The facades served by the gateway can't change while the
+	// server is running, so the document is only generated once.
+	h.once.Do(func() {
+		h.document, h.err = json.Marshal(gatewayOpenAPI(h.ctxt.srv.facades))
+	})
+	if h.err != nil {
+		return errors.Annotate(h.err, "cannot generate OpenAPI document")
+	}
+	w.Header().Set("Content-Type", params.ContentTypeJSON)
+	w.Header().Set("Content-Length", fmt.Sprint(len(h.document)))
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(h.document); err != nil {
+		logger.Errorf("cannot write OpenAPI document: %v", err)
+	}
+	return nil
+}
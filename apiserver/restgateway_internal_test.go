@@ -0,0 +1,208 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/juju/clock/testclock"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/worker/v2/workertest"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type gatewayRootCacheSuite struct {
+	coretesting.BaseSuite
+
+	clock *testclock.Clock
+	cache *gatewayRootCache
+}
+
+var _ = gc.Suite(&gatewayRootCacheSuite{})
+
+func (s *gatewayRootCacheSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.clock = testclock.NewClock(time.Now())
+	cache, err := newGatewayRootCache(s.clock)
+	c.Assert(err, jc.ErrorIsNil)
+	s.cache = cache
+	s.AddCleanup(func(c *gc.C) { workertest.DirtyKill(c, cache) })
+}
+
+// stopRecorder is a resource that records whether it has been
+// stopped.
+type stopRecorder chan struct{}
+
+func (r stopRecorder) Stop() error {
+	close(r)
+	return nil
+}
+
+func (r stopRecorder) isStopped() bool {
+	select {
+	case <-r:
+		return true
+	default:
+		return false
+	}
+}
+
+// newGatewayRoot returns a root built with the given state that
+// expires at the given time, and a record of whether its resources
+// have been released.
+func newGatewayRoot(st *state.State, expires time.Time) (*gatewayRoot, stopRecorder) {
+	handler := &apiHandler{resources: common.NewResources()}
+	stopped := make(stopRecorder)
+	handler.resources.Register(stopped)
+	return &gatewayRoot{
+		handler: handler,
+		st:      st,
+		expires: expires,
+	}, stopped
+}
+
+func (s *gatewayRootCacheSuite) TestRootShared(c *gc.C) {
+	key := gatewayRootKey{modelUUID: "uuid", user: "user-bob"}
+	st := &state.State{}
+	root, stopped := newGatewayRoot(st, s.clock.Now().Add(time.Minute))
+	c.Assert(s.cache.get(key, st), gc.IsNil)
+	s.cache.add(key, root)
+	s.cache.release(root)
+
+	c.Assert(s.cache.get(key, st), gc.Equals, root)
+	s.cache.release(root)
+	c.Assert(s.cache.get(gatewayRootKey{modelUUID: "uuid", user: "user-alice"}, st), gc.IsNil)
+	c.Assert(s.cache.get(gatewayRootKey{modelUUID: "uuid", controllerOnly: true, user: "user-bob"}, st), gc.IsNil)
+	c.Assert(stopped.isStopped(), jc.IsFalse)
+}
+
+func (s *gatewayRootCacheSuite) TestRootExpires(c *gc.C) {
+	key := gatewayRootKey{modelUUID: "uuid", user: "user-bob"}
+	st := &state.State{}
+	root, stopped := newGatewayRoot(st, s.clock.Now().Add(time.Minute))
+	s.cache.add(key, root)
+	s.cache.release(root)
+
+	// The root is released when it expires, without waiting for
+	// another call.
+	err := s.clock.WaitAdvance(time.Minute, coretesting.LongWait, 1)
+	c.Assert(err, jc.ErrorIsNil)
+	select {
+	case <-stopped:
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("expired root not released")
+	}
+	c.Assert(s.cache.get(key, st), gc.IsNil)
+}
+
+func (s *gatewayRootCacheSuite) TestExpiredRootNotUsed(c *gc.C) {
+	key := gatewayRootKey{modelUUID: "uuid", user: "user-bob"}
+	st := &state.State{}
+	root, stopped := newGatewayRoot(st, s.clock.Now().Add(-time.Second))
+	s.cache.add(key, root)
+	s.cache.release(root)
+
+	c.Assert(s.cache.get(key, st), gc.IsNil)
+	c.Assert(stopped.isStopped(), jc.IsTrue)
+}
+
+func (s *gatewayRootCacheSuite) TestRootNotUsedWithNewState(c *gc.C) {
+	key := gatewayRootKey{modelUUID: "uuid", user: "user-bob"}
+	root, stopped := newGatewayRoot(&state.State{}, s.clock.Now().Add(time.Minute))
+	s.cache.add(key, root)
+	s.cache.release(root)
+
+	c.Assert(s.cache.get(key, &state.State{}), gc.IsNil)
+	c.Assert(stopped.isStopped(), jc.IsTrue)
+}
+
+func (s *gatewayRootCacheSuite) TestRootReleasedAfterLastCall(c *gc.C) {
+	key := gatewayRootKey{modelUUID: "uuid", user: "user-bob"}
+	st := &state.State{}
+	root, stopped := newGatewayRoot(st, s.clock.Now().Add(time.Minute))
+	s.cache.add(key, root)
+
+	// Replacing the root evicts it, but it isn't released while
+	// a call is still using it.
+	newRoot, _ := newGatewayRoot(st, s.clock.Now().Add(time.Minute))
+	s.cache.add(key, newRoot)
+	c.Assert(stopped.isStopped(), jc.IsFalse)
+	c.Assert(s.cache.get(key, st), gc.Equals, newRoot)
+
+	s.cache.release(root)
+	c.Assert(stopped.isStopped(), jc.IsTrue)
+}
+
+func (s *gatewayRootCacheSuite) TestRootsReleasedWhenKilled(c *gc.C) {
+	st := &state.State{}
+	expires := s.clock.Now().Add(time.Minute)
+	idle, idleStopped := newGatewayRoot(st, expires)
+	s.cache.add(gatewayRootKey{modelUUID: "uuid", user: "user-bob"}, idle)
+	s.cache.release(idle)
+	busy, busyStopped := newGatewayRoot(st, expires)
+	s.cache.add(gatewayRootKey{modelUUID: "uuid", user: "user-alice"}, busy)
+
+	workertest.CleanKill(c, s.cache)
+	c.Assert(idleStopped.isStopped(), jc.IsTrue)
+	c.Assert(busyStopped.isStopped(), jc.IsFalse)
+	s.cache.release(busy)
+	c.Assert(busyStopped.isStopped(), jc.IsTrue)
+
+	// A root added by a call that was still being set up is
+	// released when the call finishes.
+	late, lateStopped := newGatewayRoot(st, expires)
+	key := gatewayRootKey{modelUUID: "uuid", user: "user-carol"}
+	s.cache.add(key, late)
+	c.Assert(s.cache.get(key, st), gc.IsNil)
+	s.cache.release(late)
+	c.Assert(lateStopped.isStopped(), jc.IsTrue)
+}
+
+type decodeGatewayArgSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&decodeGatewayArgSuite{})
+
+func (s *decodeGatewayArgSuite) decode(c *gc.C, ctype, body string) (params.ModelSet, error) {
+	r := httptest.NewRequest("POST", "/", strings.NewReader(body))
+	if ctype != "" {
+		r.Header.Set("Content-Type", ctype)
+	}
+	var arg params.ModelSet
+	err := decodeGatewayArg(httptest.NewRecorder(), r, &arg)
+	return arg, err
+}
+
+func (s *decodeGatewayArgSuite) TestJSON(c *gc.C) {
+	for _, ctype := range []string{"application/json", "application/json; charset=utf-8"} {
+		arg, err := s.decode(c, ctype, `{"config":{"ftp-proxy":"http://proxy.example.com"}}`)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(arg.Config, jc.DeepEquals, map[string]interface{}{"ftp-proxy": "http://proxy.example.com"})
+	}
+}
+
+func (s *decodeGatewayArgSuite) TestEmptyBody(c *gc.C) {
+	arg, err := s.decode(c, "", "")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(arg.Config, gc.IsNil)
+}
+
+func (s *decodeGatewayArgSuite) TestUnsupportedContentType(c *gc.C) {
+	for _, ctype := range []string{"", "text/plain", "application/x-www-form-urlencoded", "application/json+junk", ";"} {
+		c.Logf("content type %q", ctype)
+		_, err := s.decode(c, ctype, `{"config":{}}`)
+		c.Check(err, gc.ErrorMatches, `unsupported content type ".*": expected "application/json"`)
+		_, status := common.ServerErrorAndStatus(err)
+		c.Check(status, gc.Equals, http.StatusUnsupportedMediaType)
+	}
+}
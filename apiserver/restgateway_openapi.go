@@ -0,0 +1,305 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/juju/jsonschema-gen"
+
+	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/apiserver/params"
+	jujuversion "github.com/juju/juju/version"
+)
+
+// The openAPI types hold the parts of an OpenAPI 3.0 document used
+// to describe the REST gateway.
+type openAPIDocument struct {
+	OpenAPI    string                     `json:"openapi"`
+	Info       openAPIInfo                `json:"info"`
+	Paths      map[string]*openAPIPath    `json:"paths"`
+	Components openAPIComponents          `json:"components"`
+	Security   []map[string][]interface{} `json:"security"`
+}
+
+type openAPIInfo struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+type openAPIPath struct {
+	Parameters []openAPIParameter `json:"parameters,omitempty"`
+	Get        *openAPIOperation  `json:"get,omitempty"`
+	Post       *openAPIOperation  `json:"post,omitempty"`
+}
+
+type openAPIParameter struct {
+	Name     string           `json:"name"`
+	In       string           `json:"in"`
+	Required bool             `json:"required"`
+	Schema   *jsonschema.Type `json:"schema"`
+}
+
+type openAPIOperation struct {
+	OperationID string                     `json:"operationId"`
+	Summary     string                     `json:"summary,omitempty"`
+	Tags        []string                   `json:"tags,omitempty"`
+	RequestBody *openAPIRequestBody        `json:"requestBody,omitempty"`
+	Responses   map[string]openAPIResponse `json:"responses"`
+}
+
+type openAPIRequestBody struct {
+	Content map[string]openAPIMediaType `json:"content"`
+}
+
+type openAPIResponse struct {
+	Description string                      `json:"description"`
+	Content     map[string]openAPIMediaType `json:"content,omitempty"`
+}
+
+type openAPIMediaType struct {
+	Schema *jsonschema.Type `json:"schema"`
+}
+
+type openAPIComponents struct {
+	Schemas         map[string]*jsonschema.Type      `json:"schemas"`
+	SecuritySchemes map[string]openAPISecurityScheme `json:"securitySchemes"`
+}
+
+type openAPISecurityScheme struct {
+	Type        string `json:"type"`
+	Scheme      string `json:"scheme"`
+	Description string `json:"description,omitempty"`
+}
+
+const (
+	jsonSchemaRefPrefix = "#/definitions/"
+	openAPIRefPrefix    = "#/components/schemas/"
+)
+
+// gatewayOpenAPI returns the OpenAPI document describing the methods
+// served by the REST gateway. The schemas of their arguments and
+// results are derived from the params structs, as they are for the
+// API schema generated by generate/schemagen.
+func gatewayOpenAPI(registry *facade.Registry) *openAPIDocument {
+	doc := &openAPIDocument{
+		OpenAPI: "3.0.3",
+		Info: openAPIInfo{
+			Title:       "Juju REST gateway",
+			Description: "Selected client API methods served over HTTP.",
+			Version:     jujuversion.Current.String(),
+		},
+		Paths: make(map[string]*openAPIPath),
+		Components: openAPIComponents{
+			Schemas: make(map[string]*jsonschema.Type),
+			SecuritySchemes: map[string]openAPISecurityScheme{
+				"basic": {
+					Type:        "http",
+					Scheme:      "basic",
+					Description: "A user tag, such as user-admin, and its password.",
+				},
+				"bearer": {
+					Type:        "http",
+					Scheme:      "bearer",
+					Description: "An API token or, if configured, an OpenID Connect ID token.",
+				},
+			},
+		},
+		Security: []map[string][]interface{}{
+			{"basic": {}},
+			{"bearer": {}},
+		},
+	}
+	schemas := &openAPISchemas{
+		schemas: doc.Components.Schemas,
+		raw:     make(map[string]string),
+	}
+	errorSchema := schemas.add(jsonschema.ReflectFromType(reflect.TypeOf(params.ErrorResult{})), "")
+
+	for _, desc := range registry.List() {
+		suffix := func(version int) string {
+			return fmt.Sprintf("%s.v%d", desc.Name, version)
+		}
+		for _, m := range gatewayMethods {
+			if m.facade != desc.Name {
+				continue
+			}
+			for _, version := range desc.Versions {
+				method, err := gatewayMethodType(registry, m, version)
+				if err != nil {
+					continue
+				}
+				var paramsSchema *jsonschema.Type
+				if method.Params != nil {
+					paramsSchema = schemas.add(jsonschema.ReflectFromType(method.Params), suffix(version))
+				}
+				resultSchema := &jsonschema.Type{Type: "object"}
+				if method.Result != nil {
+					resultSchema = schemas.add(jsonschema.ReflectFromType(method.Result), suffix(version))
+				}
+				urlPath, path := gatewayOpenAPIPath(m, version)
+				path.Post = gatewayOpenAPIOperation(m, version, "post", paramsSchema, resultSchema, errorSchema)
+				if m.readOnly {
+					path.Get = gatewayOpenAPIOperation(m, version, "get", nil, resultSchema, errorSchema)
+				}
+				doc.Paths[urlPath] = path
+			}
+		}
+	}
+	return doc
+}
+
+// gatewayOpenAPIPath returns the URL path of the given version of a
+// REST gateway method, and the OpenAPI description of its parameters.
+func gatewayOpenAPIPath(m gatewayMethod, version int) (string, *openAPIPath) {
+	urlPath := fmt.Sprintf("/gateway/%s/v%d/%s", m.facade, version, m.method)
+	if gatewayControllerFacade(m.facade) {
+		return urlPath, &openAPIPath{}
+	}
+	return "/model/{modeluuid}" + urlPath, &openAPIPath{
+		Parameters: []openAPIParameter{{
+			Name:     "modeluuid",
+			In:       "path",
+			Required: true,
+			Schema:   &jsonschema.Type{Type: "string", Format: "uuid"},
+		}},
+	}
+}
+
+// gatewayOpenAPIOperation returns the OpenAPI description of calling
+// the given version of a REST gateway method with an HTTP request
+// using httpMethod.
+func gatewayOpenAPIOperation(
+	m gatewayMethod, version int, httpMethod string,
+	paramsSchema, resultSchema, errorSchema *jsonschema.Type,
+) *openAPIOperation {
+	op := &openAPIOperation{
+		OperationID: fmt.Sprintf("%s.v%d.%s.%s", m.facade, version, m.method, httpMethod),
+		Summary:     fmt.Sprintf("Calls %s on version %d of the %s facade.", m.method, version, m.facade),
+		Tags:        []string{m.facade},
+		Responses: map[string]openAPIResponse{
+			"200": {
+				Description: "The result of the call.",
+				Content:     jsonContent(resultSchema),
+			},
+			"default": {
+				Description: "The call failed.",
+				Content:     jsonContent(errorSchema),
+			},
+		},
+	}
+	if paramsSchema != nil {
+		op.RequestBody = &openAPIRequestBody{
+			Content: jsonContent(paramsSchema),
+		}
+	}
+	return op
+}
+
+func jsonContent(schema *jsonschema.Type) map[string]openAPIMediaType {
+	return map[string]openAPIMediaType{
+		params.ContentTypeJSON: {Schema: schema},
+	}
+}
+
+// openAPISchemas collects the definitions of JSON schemas generated
+// for different facades into the schemas of an OpenAPI document.
+type openAPISchemas struct {
+	schemas map[string]*jsonschema.Type
+
+	// raw holds the JSON encoding of each schema as generated, before
+	// its references were rewritten, so that definitions with the
+	// same name from different facades can be compared.
+	raw map[string]string
+}
+
+// add adds the definitions of the given schema to the collected
+// schemas and returns its type, with references rewritten to refer
+// to the collected schemas. A definition that differs from one of
+// the same name that is already collected, or that refers to such
+// a definition, is added under its name followed by suffix.
+func (s *openAPISchemas) add(schema *jsonschema.Schema, suffix string) *jsonschema.Type {
+	raw := make(map[string]string)
+	for name, def := range schema.Definitions {
+		data, _ := json.Marshal(def)
+		raw[name] = string(data)
+	}
+	// Work out which definitions need renaming, including those
+	// that refer to a renamed definition.
+	names := make(map[string]string)
+	for name := range schema.Definitions {
+		if existing, ok := s.raw[name]; ok && existing != raw[name] {
+			names[name] = name + "." + suffix
+		}
+	}
+	for changed := true; changed; {
+		changed = false
+		for name, def := range schema.Definitions {
+			if _, ok := names[name]; ok {
+				continue
+			}
+			walkJSONSchema(def, func(t *jsonschema.Type) {
+				if _, ok := names[strings.TrimPrefix(t.Ref, jsonSchemaRefPrefix)]; ok && t.Ref != "" {
+					names[name] = name + "." + suffix
+					changed = true
+				}
+			})
+		}
+	}
+	rename := func(name string) string {
+		if newName, ok := names[name]; ok {
+			return newName
+		}
+		return name
+	}
+	for name, def := range schema.Definitions {
+		newName := rename(name)
+		if _, ok := s.schemas[newName]; ok {
+			continue
+		}
+		s.schemas[newName] = toOpenAPISchema(def, rename)
+		s.raw[newName] = raw[name]
+	}
+	return toOpenAPISchema(schema.Type, rename)
+}
+
+// toOpenAPISchema converts t, which was generated as a JSON schema,
+// to the form of schema used in OpenAPI documents, with references
+// to definitions renamed by rename.
+func toOpenAPISchema(t *jsonschema.Type, rename func(string) string) *jsonschema.Type {
+	walkJSONSchema(t, func(t *jsonschema.Type) {
+		if t.Ref != "" {
+			t.Ref = openAPIRefPrefix + rename(strings.TrimPrefix(t.Ref, jsonSchemaRefPrefix))
+		}
+		// OpenAPI does not support patternProperties, but the
+		// schema generator only uses them to describe maps,
+		// which may have any keys.
+		if elem, ok := t.PatternProperties[".*"]; ok {
+			data, _ := json.Marshal(elem)
+			t.AdditionalProperties = data
+			t.PatternProperties = nil
+		}
+	})
+	return t
+}
+
+// walkJSONSchema calls f for t and each of the schemas nested in
+// it, after their own nested schemas.
+func walkJSONSchema(t *jsonschema.Type, f func(*jsonschema.Type)) {
+	if t == nil {
+		return
+	}
+	walkJSONSchema(t.Items, f)
+	for _, p := range t.Properties {
+		walkJSONSchema(p, f)
+	}
+	for _, p := range t.PatternProperties {
+		walkJSONSchema(p, f)
+	}
+	f(t)
+}
@@ -0,0 +1,126 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/juju/jsonschema-gen"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type restGatewayOpenAPISuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&restGatewayOpenAPISuite{})
+
+func (s *restGatewayOpenAPISuite) TestGatewayMethodsExist(c *gc.C) {
+	registry := AllFacades()
+	versions := make(map[string][]int)
+	for _, desc := range registry.List() {
+		versions[desc.Name] = desc.Versions
+	}
+	for _, m := range gatewayMethods {
+		found := false
+		for _, version := range versions[m.facade] {
+			if _, err := gatewayMethodType(registry, m, version); err == nil {
+				found = true
+			}
+		}
+		c.Check(found, jc.IsTrue, gc.Commentf("%s.%s", m.facade, m.method))
+	}
+}
+
+func (s *restGatewayOpenAPISuite) TestDocument(c *gc.C) {
+	doc := gatewayOpenAPI(AllFacades())
+	c.Assert(doc.OpenAPI, gc.Equals, "3.0.3")
+
+	status := doc.Paths["/model/{modeluuid}/gateway/Client/v2/FullStatus"]
+	c.Assert(status, gc.NotNil)
+	c.Check(status.Parameters, gc.HasLen, 1)
+	c.Check(status.Get, gc.NotNil)
+	c.Check(status.Get.RequestBody, gc.IsNil)
+	c.Assert(status.Post, gc.NotNil)
+	c.Check(status.Post.OperationID, gc.Equals, "Client.v2.FullStatus.post")
+	c.Check(status.Post.RequestBody.Content[params.ContentTypeJSON].Schema.Ref, gc.Equals, "#/components/schemas/StatusParams")
+	c.Check(status.Post.Responses["200"].Content[params.ContentTypeJSON].Schema.Ref, gc.Equals, "#/components/schemas/FullStatus")
+	c.Check(status.Post.Responses["default"].Content[params.ContentTypeJSON].Schema.Ref, gc.Equals, "#/components/schemas/ErrorResult")
+
+	modelSet := doc.Paths["/model/{modeluuid}/gateway/ModelConfig/v2/ModelSet"]
+	c.Assert(modelSet, gc.NotNil)
+	c.Check(modelSet.Get, gc.IsNil)
+	c.Check(modelSet.Post, gc.NotNil)
+
+	listModels := doc.Paths["/gateway/ModelManager/v9/ListModels"]
+	c.Assert(listModels, gc.NotNil)
+	c.Check(listModels.Parameters, gc.HasLen, 0)
+
+	// Methods are only described for the versions that have them.
+	c.Check(doc.Paths["/model/{modeluuid}/gateway/Application/v1/CharmConfig"], gc.IsNil)
+	c.Check(doc.Paths["/model/{modeluuid}/gateway/Application/v6/CharmConfig"], gc.NotNil)
+	// Methods not served by the gateway are not described.
+	c.Check(doc.Paths["/model/{modeluuid}/gateway/Application/v13/Deploy"], gc.IsNil)
+}
+
+func (s *restGatewayOpenAPISuite) TestSchemasAreValidOpenAPI(c *gc.C) {
+	doc := gatewayOpenAPI(AllFacades())
+	check := func(t *jsonschema.Type) {
+		walkJSONSchema(t, func(t *jsonschema.Type) {
+			c.Check(t.PatternProperties, gc.IsNil)
+			if t.Ref == "" {
+				return
+			}
+			c.Assert(strings.HasPrefix(t.Ref, openAPIRefPrefix), jc.IsTrue, gc.Commentf("ref %q", t.Ref))
+			_, ok := doc.Components.Schemas[strings.TrimPrefix(t.Ref, openAPIRefPrefix)]
+			c.Check(ok, jc.IsTrue, gc.Commentf("ref %q", t.Ref))
+		})
+	}
+	for _, schema := range doc.Components.Schemas {
+		check(schema)
+	}
+	for _, path := range doc.Paths {
+		for _, op := range []*openAPIOperation{path.Get, path.Post} {
+			if op == nil {
+				continue
+			}
+			if op.RequestBody != nil {
+				check(op.RequestBody.Content[params.ContentTypeJSON].Schema)
+			}
+			for _, response := range op.Responses {
+				check(response.Content[params.ContentTypeJSON].Schema)
+			}
+		}
+	}
+}
+
+func (s *restGatewayOpenAPISuite) TestSchemasWithSameName(c *gc.C) {
+	// Error has the same name as params.Error, but differs. ErrorResult
+	// has the same encoding as params.ErrorResult, but refers to Error.
+	type Error struct {
+		Text string `json:"text"`
+	}
+	type ErrorResult struct {
+		Error *Error `json:"error,omitempty"`
+	}
+	schemas := &openAPISchemas{
+		schemas: make(map[string]*jsonschema.Type),
+		raw:     make(map[string]string),
+	}
+	t := schemas.add(jsonschema.ReflectFromType(reflect.TypeOf(params.ErrorResult{})), "Params.v1")
+	c.Check(t.Ref, gc.Equals, "#/components/schemas/ErrorResult")
+	t = schemas.add(jsonschema.ReflectFromType(reflect.TypeOf(params.ErrorResult{})), "Params.v2")
+	c.Check(t.Ref, gc.Equals, "#/components/schemas/ErrorResult")
+	t = schemas.add(jsonschema.ReflectFromType(reflect.TypeOf(ErrorResult{})), "Test.v1")
+	c.Check(t.Ref, gc.Equals, "#/components/schemas/ErrorResult.Test.v1")
+
+	c.Check(schemas.schemas["ErrorResult"].Properties["error"].Ref, gc.Equals, "#/components/schemas/Error")
+	c.Check(schemas.schemas["ErrorResult.Test.v1"].Properties["error"].Ref, gc.Equals, "#/components/schemas/Error.Test.v1")
+	c.Check(schemas.schemas["Error.Test.v1"].Properties["text"].Type, gc.Equals, "string")
+}
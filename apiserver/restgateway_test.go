@@ -0,0 +1,337 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/juju/collections/set"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	apitesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/controller"
+	"github.com/juju/juju/core/auditlog"
+	"github.com/juju/juju/core/permission"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing/factory"
+)
+
+type restGatewaySuite struct {
+	apiserverBaseSuite
+}
+
+var _ = gc.Suite(&restGatewaySuite{})
+
+func (s *restGatewaySuite) SetUpSuite(c *gc.C) {
+	if runtime.GOOS != "linux" {
+		c.Skip("apiservers only run on linux")
+	}
+	s.apiserverBaseSuite.SetUpSuite(c)
+}
+
+func (s *restGatewaySuite) SetUpTest(c *gc.C) {
+	s.ControllerConfig = map[string]interface{}{
+		controller.RESTGatewayKey: true,
+	}
+	s.apiserverBaseSuite.SetUpTest(c)
+}
+
+func (s *restGatewaySuite) modelURI(path string) string {
+	return s.URL(fmt.Sprintf("/model/%s/gateway/%s", s.State.ModelUUID(), path), nil).String()
+}
+
+func (s *restGatewaySuite) assertError(c *gc.C, resp *http.Response, expStatus int, expError string) {
+	body := apitesting.AssertResponse(c, resp, expStatus, params.ContentTypeJSON)
+	var result params.ErrorResult
+	err := json.Unmarshal(body, &result)
+	c.Assert(err, jc.ErrorIsNil, gc.Commentf("body: %s", body))
+	c.Assert(result.Error, gc.NotNil)
+	c.Check(result.Error.Message, gc.Matches, expError)
+}
+
+func (s *restGatewaySuite) TestOpenAPIDocument(c *gc.C) {
+	resp := apitesting.SendHTTPRequest(c, apitesting.HTTPRequestParams{
+		Method: "GET",
+		URL:    s.URL("/gateway/openapi.json", nil).String(),
+	})
+	body := apitesting.AssertResponse(c, resp, http.StatusOK, params.ContentTypeJSON)
+	var doc struct {
+		OpenAPI string                            `json:"openapi"`
+		Paths   map[string]map[string]interface{} `json:"paths"`
+	}
+	err := json.Unmarshal(body, &doc)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(doc.OpenAPI, gc.Equals, "3.0.3")
+	c.Check(doc.Paths["/model/{modeluuid}/gateway/Client/v2/FullStatus"], gc.NotNil)
+	c.Check(doc.Paths["/gateway/ModelManager/v9/ListModels"], gc.NotNil)
+}
+
+func (s *restGatewaySuite) TestRequiresAuth(c *gc.C) {
+	resp := apitesting.SendHTTPRequest(c, apitesting.HTTPRequestParams{
+		Method: "GET",
+		URL:    s.modelURI("Client/v2/FullStatus"),
+	})
+	body := apitesting.AssertResponse(c, resp, http.StatusUnauthorized, "text/plain; charset=utf-8")
+	c.Assert(string(body), gc.Equals, "authentication failed: no credentials provided\n")
+}
+
+func (s *restGatewaySuite) TestReadOnlyMethodWithGET(c *gc.C) {
+	resp := s.sendHTTPRequest(c, apitesting.HTTPRequestParams{
+		Method: "GET",
+		URL:    s.modelURI("Client/v2/FullStatus"),
+	})
+	body := apitesting.AssertResponse(c, resp, http.StatusOK, params.ContentTypeJSON)
+	var status params.FullStatus
+	err := json.Unmarshal(body, &status)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(status.Model.Name, gc.Equals, s.Model.Name())
+}
+
+func (s *restGatewaySuite) TestWriteMethodWithPOST(c *gc.C) {
+	resp := s.sendHTTPRequest(c, apitesting.HTTPRequestParams{
+		Method: "POST",
+		URL:    s.modelURI("ModelConfig/v2/ModelSet"),
+		JSONBody: params.ModelSet{Config: map[string]interface{}{
+			"ftp-proxy": "http://proxy.example.com",
+		}},
+	})
+	apitesting.AssertResponse(c, resp, http.StatusOK, params.ContentTypeJSON)
+	cfg, err := s.Model.ModelConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cfg.FTPProxy(), gc.Equals, "http://proxy.example.com")
+}
+
+func (s *restGatewaySuite) TestWriteMethodRequiresPOST(c *gc.C) {
+	resp := s.sendHTTPRequest(c, apitesting.HTTPRequestParams{
+		Method: "GET",
+		URL:    s.modelURI("ModelConfig/v2/ModelSet"),
+	})
+	s.assertError(c, resp, http.StatusMethodNotAllowed, `method ModelConfig.ModelSet must be called with POST`)
+}
+
+func (s *restGatewaySuite) TestInvalidArguments(c *gc.C) {
+	resp := s.sendHTTPRequest(c, apitesting.HTTPRequestParams{
+		Method:   "POST",
+		URL:      s.modelURI("ModelConfig/v2/ModelSet"),
+		JSONBody: []string{"foo"},
+	})
+	s.assertError(c, resp, http.StatusBadRequest, `cannot decode arguments: .*`)
+}
+
+func (s *restGatewaySuite) TestUnsupportedContentType(c *gc.C) {
+	resp := s.sendHTTPRequest(c, apitesting.HTTPRequestParams{
+		Method:      "POST",
+		URL:         s.modelURI("ModelConfig/v2/ModelSet"),
+		ContentType: "application/x-www-form-urlencoded",
+		Body:        strings.NewReader("config=foo"),
+	})
+	s.assertError(c, resp, http.StatusUnsupportedMediaType, `unsupported content type "application/x-www-form-urlencoded": expected "application/json"`)
+}
+
+func (s *restGatewaySuite) TestMethodNotServed(c *gc.C) {
+	resp := s.sendHTTPRequest(c, apitesting.HTTPRequestParams{
+		Method: "POST",
+		URL:    s.modelURI("Application/v13/Deploy"),
+	})
+	s.assertError(c, resp, http.StatusNotFound, `method Application.Deploy not found`)
+}
+
+func (s *restGatewaySuite) TestUnknownVersion(c *gc.C) {
+	resp := s.sendHTTPRequest(c, apitesting.HTTPRequestParams{
+		Method: "GET",
+		URL:    s.modelURI("Client/v99/FullStatus"),
+	})
+	s.assertError(c, resp, http.StatusNotFound, `Client facade version 99 not found`)
+}
+
+func (s *restGatewaySuite) TestMethodNotInVersion(c *gc.C) {
+	resp := s.sendHTTPRequest(c, apitesting.HTTPRequestParams{
+		Method: "GET",
+		URL:    s.modelURI("Application/v1/CharmConfig"),
+	})
+	s.assertError(c, resp, http.StatusNotFound, `method Application\(1\).CharmConfig not found`)
+}
+
+func (s *restGatewaySuite) TestControllerFacadeNotServedForModel(c *gc.C) {
+	resp := s.sendHTTPRequest(c, apitesting.HTTPRequestParams{
+		Method: "GET",
+		URL:    s.modelURI("ModelManager/v9/ListModels"),
+	})
+	s.assertError(c, resp, http.StatusNotFound, `method ModelManager.ListModels not found`)
+}
+
+func (s *restGatewaySuite) TestControllerFacade(c *gc.C) {
+	resp := s.sendHTTPRequest(c, apitesting.HTTPRequestParams{
+		Method:   "POST",
+		URL:      s.URL("/gateway/ModelManager/v9/ListModels", nil).String(),
+		JSONBody: params.Entity{Tag: s.Owner.String()},
+	})
+	body := apitesting.AssertResponse(c, resp, http.StatusOK, params.ContentTypeJSON)
+	var models params.UserModelList
+	err := json.Unmarshal(body, &models)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(models.UserModels, gc.HasLen, 1)
+	c.Check(models.UserModels[0].UUID, gc.Equals, s.State.ModelUUID())
+}
+
+func (s *restGatewaySuite) TestUserWithoutModelAccess(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{
+		Password:    "hunter2",
+		NoModelUser: true,
+	})
+	resp := apitesting.SendHTTPRequest(c, apitesting.HTTPRequestParams{
+		Method:   "GET",
+		URL:      s.modelURI("Client/v2/FullStatus"),
+		Tag:      user.Tag().String(),
+		Password: "hunter2",
+	})
+	s.assertError(c, resp, http.StatusUnauthorized, `permission denied`)
+}
+
+func (s *restGatewaySuite) TestAgentsNotAllowed(c *gc.C) {
+	machine, password := s.Factory.MakeMachineReturningPassword(c, &factory.MachineParams{
+		Nonce: "fake_nonce",
+	})
+	resp := apitesting.SendHTTPRequest(c, apitesting.HTTPRequestParams{
+		Method:   "GET",
+		URL:      s.modelURI("Client/v2/FullStatus"),
+		Tag:      machine.Tag().String(),
+		Password: password,
+		Nonce:    "fake_nonce",
+	})
+	body := apitesting.AssertResponse(c, resp, http.StatusForbidden, "text/plain; charset=utf-8")
+	c.Assert(string(body), gc.Equals, "authorization failed: tag kind machine not valid\n")
+}
+
+func (s *restGatewaySuite) TestReadOnlyTokenCannotWrite(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "bot", Access: permission.WriteAccess})
+	_, secret, err := s.State.AddAPIToken(state.AddAPITokenArgs{
+		Owner:     user.UserTag(),
+		Model:     s.Model.ModelTag(),
+		Access:    permission.ReadAccess,
+		CreatedBy: s.Owner,
+		Expires:   s.Clock.Now().Add(time.Hour),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	headers := map[string]string{"Authorization": "Bearer " + secret}
+
+	resp := apitesting.SendHTTPRequest(c, apitesting.HTTPRequestParams{
+		Method:       "GET",
+		URL:          s.modelURI("Client/v2/FullStatus"),
+		ExtraHeaders: headers,
+	})
+	apitesting.AssertResponse(c, resp, http.StatusOK, params.ContentTypeJSON)
+
+	resp = apitesting.SendHTTPRequest(c, apitesting.HTTPRequestParams{
+		Method:       "POST",
+		URL:          s.modelURI("ModelConfig/v2/ModelSet"),
+		ExtraHeaders: headers,
+		JSONBody: params.ModelSet{Config: map[string]interface{}{
+			"ftp-proxy": "http://proxy.example.com",
+		}},
+	})
+	s.assertError(c, resp, http.StatusForbidden, `API token ".*" does not grant write access`)
+	cfg, err := s.Model.ModelConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cfg.FTPProxy(), gc.Equals, "")
+}
+
+func (s *restGatewaySuite) auditedServer(c *gc.C) *apitesting.FakeAuditLog {
+	log := &apitesting.FakeAuditLog{}
+	config := s.config
+	config.GetAuditConfig = func() auditlog.Config {
+		return auditlog.Config{
+			Enabled:        true,
+			Target:         log,
+			ExcludeMethods: set.NewStrings(controller.ReadOnlyMethodsWildcard),
+		}
+	}
+	s.newServer(c, config)
+	return log
+}
+
+func (s *restGatewaySuite) TestWriteMethodAudited(c *gc.C) {
+	log := s.auditedServer(c)
+	resp := s.sendHTTPRequest(c, apitesting.HTTPRequestParams{
+		Method: "POST",
+		URL:    s.modelURI("ModelConfig/v2/ModelSet"),
+		JSONBody: params.ModelSet{Config: map[string]interface{}{
+			"ftp-proxy": "http://proxy.example.com",
+		}},
+	})
+	apitesting.AssertResponse(c, resp, http.StatusOK, params.ContentTypeJSON)
+
+	log.CheckCallNames(c, "AddConversation", "AddRequest", "AddResponse")
+	convo := log.Calls()[0].Args[0].(auditlog.Conversation)
+	c.Check(convo.Who, gc.Equals, s.Owner.Id())
+	c.Check(convo.What, gc.Equals, fmt.Sprintf("POST /model/%s/gateway/ModelConfig/v2/ModelSet", s.State.ModelUUID()))
+	c.Check(convo.ModelUUID, gc.Equals, s.State.ModelUUID())
+	req := log.Calls()[1].Args[0].(auditlog.Request)
+	c.Check(req.Facade, gc.Equals, "ModelConfig")
+	c.Check(req.Method, gc.Equals, "ModelSet")
+	c.Check(req.Version, gc.Equals, 2)
+}
+
+func (s *restGatewaySuite) TestReadOnlyMethodNotAudited(c *gc.C) {
+	log := s.auditedServer(c)
+	resp := s.sendHTTPRequest(c, apitesting.HTTPRequestParams{
+		Method: "GET",
+		URL:    s.modelURI("Client/v2/FullStatus"),
+	})
+	apitesting.AssertResponse(c, resp, http.StatusOK, params.ContentTypeJSON)
+	log.CheckCallNames(c)
+}
+
+func (s *restGatewaySuite) TestAccessCheckedForEachCall(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{Password: "hunter2"})
+	request := apitesting.HTTPRequestParams{
+		Method:   "GET",
+		URL:      s.modelURI("Client/v2/FullStatus"),
+		Tag:      user.Tag().String(),
+		Password: "hunter2",
+	}
+	resp := apitesting.SendHTTPRequest(c, request)
+	apitesting.AssertResponse(c, resp, http.StatusOK, params.ContentTypeJSON)
+
+	// Removing the user's access applies to the next call, even
+	// though the root built for the first call is reused.
+	err := s.State.RemoveUserAccess(user.UserTag(), s.Model.ModelTag())
+	c.Assert(err, jc.ErrorIsNil)
+	resp = apitesting.SendHTTPRequest(c, request)
+	s.assertError(c, resp, http.StatusUnauthorized, `permission denied`)
+}
+
+type restGatewayDisabledSuite struct {
+	apiserverBaseSuite
+}
+
+var _ = gc.Suite(&restGatewayDisabledSuite{})
+
+func (s *restGatewayDisabledSuite) SetUpSuite(c *gc.C) {
+	if runtime.GOOS != "linux" {
+		c.Skip("apiservers only run on linux")
+	}
+	s.apiserverBaseSuite.SetUpSuite(c)
+}
+
+func (s *restGatewayDisabledSuite) TestNotServed(c *gc.C) {
+	for _, path := range []string{
+		"/gateway/openapi.json",
+		fmt.Sprintf("/model/%s/gateway/Client/v2/FullStatus", s.State.ModelUUID()),
+	} {
+		resp := s.sendHTTPRequest(c, apitesting.HTTPRequestParams{
+			Method: "GET",
+			URL:    s.URL(path, nil).String(),
+		})
+		body := apitesting.AssertResponse(c, resp, http.StatusNotFound, params.ContentTypeJSON)
+		c.Check(string(body), jc.Contains, "REST gateway not found")
+	}
+}
@@ -15,6 +15,7 @@ import (
 // independently of individual models.
 var controllerFacadeNames = set.NewStrings(
 	"AllModelWatcher",
+	"APITokens",
 	"ApplicationOffers",
 	"Cloud",
 	"Controller",
@@ -22,6 +23,7 @@ var controllerFacadeNames = set.NewStrings(
 	"MigrationTarget",
 	"ModelManager",
 	"ModelSummaryWatcher",
+	"Roles",
 	"UserManager",
 )
 
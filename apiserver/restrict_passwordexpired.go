@@ -0,0 +1,39 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"github.com/juju/collections/set"
+
+	"github.com/juju/juju/apiserver/common"
+)
+
+func passwordChangeMethodsOnly(facadeName, methodName string) error {
+	if !IsMethodAllowedWithExpiredPassword(facadeName, methodName) {
+		return common.ErrPasswordExpired
+	}
+	return nil
+}
+
+// IsMethodAllowedWithExpiredPassword reports whether the given method
+// may be called by a user whose password has expired.
+func IsMethodAllowedWithExpiredPassword(facadeName, methodName string) bool {
+	methods, ok := allowedMethodsWithExpiredPassword[facadeName]
+	if !ok {
+		return false
+	}
+	return methods.Contains(methodName)
+}
+
+// allowedMethodsWithExpiredPassword stores the api calls that users
+// whose passwords have expired may make, so that they can set a new
+// password.
+var allowedMethodsWithExpiredPassword = map[string]set.Strings{
+	"UserManager": set.NewStrings(
+		"SetPassword",
+	),
+	"Pinger": set.NewStrings(
+		"Ping",
+	),
+}
@@ -0,0 +1,41 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver"
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/testing"
+)
+
+type restrictPasswordExpiredSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&restrictPasswordExpiredSuite{})
+
+func (r *restrictPasswordExpiredSuite) TestAllowedMethods(c *gc.C) {
+	root := apiserver.TestingPasswordExpiredRoot()
+	checkAllowed := func(facade, method string, version int) {
+		caller, err := root.FindMethod(facade, version, method)
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(caller, gc.NotNil)
+	}
+	checkAllowed("UserManager", "SetPassword", 3)
+	checkAllowed("Pinger", "Ping", 1)
+}
+
+func (r *restrictPasswordExpiredSuite) TestFindDisallowedMethod(c *gc.C) {
+	root := apiserver.TestingPasswordExpiredRoot()
+	caller, err := root.FindMethod("UserManager", 3, "AddUser")
+	c.Assert(errors.Cause(err), gc.Equals, common.ErrPasswordExpired)
+	c.Assert(caller, gc.IsNil)
+	caller, err = root.FindMethod("Client", 1, "FullStatus")
+	c.Assert(errors.Cause(err), gc.Equals, common.ErrPasswordExpired)
+	c.Assert(caller, gc.IsNil)
+}
@@ -40,6 +40,10 @@ type objectKey struct {
 	name    string
 	version int
 	objId   string
+
+	// method is set for facades created to serve calls to a single
+	// method, which are allowed by the caller's custom role.
+	method string
 }
 
 // apiHandler represents a single client's connection to the state
@@ -49,6 +53,7 @@ type apiHandler struct {
 	state     *state.State
 	model     *state.Model
 	rpcConn   *rpc.Conn
+	codec     binaryCodec
 	resources *common.Resources
 	shared    *sharedServerContext
 	entity    state.Entity
@@ -66,10 +71,22 @@ type apiHandler struct {
 	// serverHost is the host:port of the API server that the client
 	// connected to.
 	serverHost string
+
+	// tokenID and tokenAccess are set if the user logged in with an
+	// API token, which limits their access to that of the token.
+	tokenID     string
+	tokenAccess permission.Access
 }
 
 var _ = (*apiHandler)(nil)
 
+// binaryCodec is implemented by the codecs of connections that may
+// switch to CBOR encoded messages at login.
+type binaryCodec interface {
+	SupportsBinary() bool
+	EnableBinary() error
+}
+
 // newAPIHandler returns a new apiHandler.
 func newAPIHandler(srv *Server, st *state.State, rpcConn *rpc.Conn, modelUUID string, connectionID uint64, serverHost string) (*apiHandler, error) {
 	m, err := st.Model()
@@ -181,6 +198,11 @@ type apiRoot struct {
 	authorizer  facade.Authorizer
 	objectMutex sync.RWMutex
 	objectCache map[objectKey]reflect.Value
+
+	// roles caches the caller's custom role on the model, if the
+	// caller is a user.
+	roleMutex sync.Mutex
+	roles     *roleCache
 }
 
 // newAPIRoot returns a new apiRoot.
@@ -236,6 +258,10 @@ func restrictAPIRoot(
 			apiRoot = restrictRoot(apiRoot, caasModelFacadesOnly)
 		}
 	}
+	if auth.passwordExpired {
+		// Users whose passwords have expired may only change them.
+		apiRoot = restrictRoot(apiRoot, passwordChangeMethodsOnly)
+	}
 	return apiRoot, nil
 }
 
@@ -314,9 +340,16 @@ func (r *apiRoot) FindMethod(rootName string, version int, methodName string) (r
 	if err != nil {
 		return nil, err
 	}
+	authorizer, err := r.methodAuthorizer(rootName, methodName)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
 
 	creator := func(id string) (reflect.Value, error) {
 		objKey := objectKey{name: rootName, version: version, objId: id}
+		if _, ok := authorizer.(*roleAuthorizer); ok {
+			objKey.method = methodName
+		}
 		r.objectMutex.RLock()
 		objValue, ok := r.objectCache[objKey]
 		r.objectMutex.RUnlock()
@@ -337,7 +370,9 @@ func (r *apiRoot) FindMethod(rootName string, version int, methodName string) (r
 			// check.
 			return reflect.Value{}, err
 		}
-		obj, err := factory(r.facadeContext(objKey))
+		ctx := r.facadeContext(objKey)
+		ctx.auth = authorizer
+		obj, err := factory(ctx)
 		if err != nil {
 			return reflect.Value{}, err
 		}
@@ -366,6 +401,126 @@ func (r *apiRoot) FindMethod(rootName string, version int, methodName string) (r
 	}, nil
 }
 
+// methodAuthorizer returns the authorizer for facades serving calls to
+// the given facade method. If the caller is a user whose custom role on
+// the model allows the method, the authorizer grants them access to the
+// model, up to the role's access level, for the call; otherwise it is
+// the root's authorizer.
+func (r *apiRoot) methodAuthorizer(rootName, methodName string) (facade.Authorizer, error) {
+	if r.state == nil || r.authorizer == nil {
+		return r.authorizer, nil
+	}
+	user, ok := r.authorizer.GetAuthTag().(names.UserTag)
+	if !ok {
+		return r.authorizer, nil
+	}
+	if handler, ok := r.authorizer.(*apiHandler); ok && handler.tokenID != "" {
+		// Custom roles do not extend the access of API tokens.
+		return r.authorizer, nil
+	}
+	roles := r.userRoleCache(user)
+	role, ok, err := roles.get()
+	if err != nil || !ok || !role.Allows(rootName, methodName) {
+		return r.authorizer, errors.Trace(err)
+	}
+	return &roleAuthorizer{
+		Authorizer: r.authorizer,
+		modelTag:   names.NewModelTag(r.state.ModelUUID()),
+		facadeName: rootName,
+		methodName: methodName,
+		role:       roles.get,
+	}, nil
+}
+
+// userRoleCache returns the cache of the user's custom role on the
+// model, creating it the first time it is needed. The cache lives as
+// long as the connection.
+func (r *apiRoot) userRoleCache(user names.UserTag) *roleCache {
+	r.roleMutex.Lock()
+	defer r.roleMutex.Unlock()
+	if r.roles != nil {
+		return r.roles
+	}
+	modelTag := names.NewModelTag(r.state.ModelUUID())
+	w := r.state.WatchModelUserRole(modelTag, user)
+	r.resources.Register(w)
+	r.roles = &roleCache{
+		changes: w.Changes(),
+		load: func() (permission.Role, error) {
+			return r.state.ModelUserRole(modelTag, user)
+		},
+	}
+	return r.roles
+}
+
+// roleCache holds the custom role assigned to a connection's user on
+// the model. The role is loaded when first needed, and loaded again
+// after the assignment changes.
+type roleCache struct {
+	mu      sync.Mutex
+	changes <-chan struct{}
+	load    func() (permission.Role, error)
+	loaded  bool
+	role    permission.Role
+	found   bool
+}
+
+// get returns the user's role, and whether they have one.
+func (c *roleCache) get() (permission.Role, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	select {
+	case <-c.changes:
+		// Either the assignment changed or the watcher has been
+		// stopped; in both cases, the role must be loaded again.
+		c.loaded = false
+	default:
+	}
+	if c.loaded {
+		return c.role, c.found, nil
+	}
+	role, err := c.load()
+	if errors.IsNotFound(err) {
+		role, c.found = permission.Role{}, false
+	} else if err != nil {
+		return permission.Role{}, false, errors.Trace(err)
+	} else {
+		c.found = true
+	}
+	c.role, c.loaded = role, true
+	return c.role, c.found, nil
+}
+
+// roleAuthorizer is a facade.Authorizer for calls to a facade method
+// that the caller's custom role allows. The role is looked up each
+// time permission is sought, so that changes to it take effect in
+// facades that have already been created.
+type roleAuthorizer struct {
+	facade.Authorizer
+	modelTag   names.ModelTag
+	facadeName string
+	methodName string
+	role       func() (permission.Role, bool, error)
+}
+
+// HasPermission is part of the facade.Authorizer interface. If the
+// role still allows the call, it grants access to the model up to the
+// role's access level.
+func (a *roleAuthorizer) HasPermission(operation permission.Access, target names.Tag) (bool, error) {
+	has, err := a.Authorizer.HasPermission(operation, target)
+	if err != nil || has {
+		return has, err
+	}
+	if target.String() != a.modelTag.String() || permission.ValidateModelAccess(operation) != nil {
+		return false, nil
+	}
+	role, ok, err := a.role()
+	if err != nil || !ok {
+		return false, errors.Trace(err)
+	}
+	return role.Allows(a.facadeName, a.methodName) && role.Access.EqualOrGreaterModelAccessThan(operation), nil
+}
+
 func (r *apiRoot) lookupMethod(rootName string, version int, methodName string) (reflect.Type, rpcreflect.ObjMethod, error) {
 	noMethod := rpcreflect.ObjMethod{}
 	goType, err := r.facades.GetType(rootName, version)
@@ -417,15 +572,17 @@ func (r *apiRoot) cachedModel(uuid string) (*cache.Model, error) {
 
 func (r *apiRoot) facadeContext(key objectKey) *facadeContext {
 	return &facadeContext{
-		r:   r,
-		key: key,
+		r:    r,
+		key:  key,
+		auth: r.authorizer,
 	}
 }
 
 // facadeContext implements facade.Context
 type facadeContext struct {
-	r   *apiRoot
-	key objectKey
+	r    *apiRoot
+	key  objectKey
+	auth facade.Authorizer
 }
 
 // Cancel is part of the facade.Context interface.
@@ -435,7 +592,7 @@ func (ctx *facadeContext) Cancel() <-chan struct{} {
 
 // Auth is part of the facade.Context interface.
 func (ctx *facadeContext) Auth() facade.Authorizer {
-	return ctx.r.authorizer
+	return ctx.auth
 }
 
 // Dispose is part of the facade.Context interface.
@@ -663,12 +820,51 @@ func (r *apiHandler) ConnectedModel() string {
 
 // HasPermission returns true if the logged in user can perform <operation> on <target>.
 func (r *apiHandler) HasPermission(operation permission.Access, target names.Tag) (bool, error) {
-	return common.HasPermission(r.state.UserPermission, r.entity.Tag(), operation, target)
+	return r.hasPermission(r.entity.Tag(), operation, target)
 }
 
 // UserHasPermission returns true if the passed in user can perform <operation> on <target>.
 func (r *apiHandler) UserHasPermission(user names.UserTag, operation permission.Access, target names.Tag) (bool, error) {
-	return common.HasPermission(r.state.UserPermission, user, operation, target)
+	return r.hasPermission(user, operation, target)
+}
+
+// hasPermission returns true if the entity can perform <operation> on
+// <target>. The user's groups are only looked up if the access granted
+// to the user directly does not suffice.
+func (r *apiHandler) hasPermission(entity names.Tag, operation permission.Access, target names.Tag) (bool, error) {
+	return common.HasDirectOrGroupPermission(
+		r.userPermission(false), r.userPermission(true), entity, operation, target,
+	)
+}
+
+// UserGroups returns the groups the user belongs to. Failure to reach
+// the directory is logged rather than returned, so that it does not
+// take away access granted to the user directly.
+func (r *apiHandler) UserGroups(user names.UserTag) []string {
+	if r.shared == nil || r.shared.groups == nil {
+		return nil
+	}
+	groups, err := r.shared.userGroups(user)
+	if err != nil {
+		logger.Warningf("cannot obtain groups for %s: %v", user.Id(), err)
+	}
+	return groups
+}
+
+// userPermission returns a function that returns the access a user
+// has on a target, including access granted to the user's groups if
+// withGroups is true.
+func (r *apiHandler) userPermission(withGroups bool) func(names.UserTag, names.Tag) (permission.Access, error) {
+	userPermission := r.state.UserPermission
+	if withGroups && r.shared != nil && r.shared.groups != nil {
+		userPermission = common.WithGroupAccess(userPermission, r.state, r.shared.userGroups)
+	}
+	if r.tokenID != "" {
+		userPermission = common.WithTokenScope(
+			userPermission, r.entity.Tag().(names.UserTag), names.NewModelTag(r.modelUUID), r.tokenAccess,
+		)
+	}
+	return userPermission
 }
 
 // DescribeFacades returns the list of available Facades and their Versions
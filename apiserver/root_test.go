@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/juju/clock/testclock"
+	"github.com/juju/errors"
 	"github.com/juju/names/v4"
 	"github.com/juju/rpcreflect"
 	jc "github.com/juju/testing/checkers"
@@ -18,6 +19,8 @@ import (
 
 	"github.com/juju/juju/apiserver"
 	"github.com/juju/juju/apiserver/facade"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/core/permission"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/testing"
 )
@@ -405,3 +408,101 @@ func (r *rootSuite) TestAuthOwner(c *gc.C) {
 
 	c.Check(authorized, jc.IsFalse)
 }
+
+func (r *rootSuite) TestRoleAuthorizer(c *gc.C) {
+	modelTag := names.NewModelTag("deadbeef-0bad-400d-8000-4b1d0d06f00d")
+	role := permission.Role{
+		Name:       "app-team",
+		Access:     permission.WriteAccess,
+		Operations: []string{permission.RunActionOperation},
+	}
+	found := true
+	auth := apiserver.NewRoleAuthorizer(
+		apiservertesting.FakeAuthorizer{Tag: names.NewUserTag("read")},
+		modelTag, "Action", "Enqueue",
+		func() (permission.Role, bool, error) { return role, found, nil },
+	)
+
+	for _, access := range []permission.Access{permission.ReadAccess, permission.WriteAccess} {
+		has, err := auth.HasPermission(access, modelTag)
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(has, jc.IsTrue)
+	}
+
+	// Access is capped at the role's access level.
+	has, err := auth.HasPermission(permission.AdminAccess, modelTag)
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(has, jc.IsFalse)
+
+	// Only access to the model is granted.
+	has, err = auth.HasPermission(permission.SuperuserAccess, names.NewControllerTag("deadbeef-0bad-400d-8000-4b1d0d06f00e"))
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(has, jc.IsFalse)
+	has, err = auth.HasPermission(permission.WriteAccess, names.NewModelTag("deadbeef-0bad-400d-8000-4b1d0d06f00e"))
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(has, jc.IsFalse)
+
+	// The role is looked up each time.
+	role.Operations = []string{permission.ConfigOperation}
+	has, err = auth.HasPermission(permission.WriteAccess, modelTag)
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(has, jc.IsFalse)
+
+	role.Operations = []string{permission.RunActionOperation}
+	found = false
+	has, err = auth.HasPermission(permission.WriteAccess, modelTag)
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(has, jc.IsFalse)
+	has, err = auth.HasPermission(permission.ReadAccess, modelTag)
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(has, jc.IsTrue)
+}
+
+func (r *rootSuite) TestRoleCache(c *gc.C) {
+	changes := make(chan struct{}, 1)
+	loads := 0
+	var loadErr error
+	get := apiserver.NewRoleCache(changes, func() (permission.Role, error) {
+		loads++
+		if loadErr != nil {
+			return permission.Role{}, loadErr
+		}
+		return permission.Role{Name: "app-team"}, nil
+	})
+
+	role, ok, err := get()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(role.Name, gc.Equals, "app-team")
+	_, _, err = get()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(loads, gc.Equals, 1)
+
+	// A change causes the role to be loaded again.
+	loadErr = errors.NotFoundf("role")
+	changes <- struct{}{}
+	_, ok, err = get()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ok, jc.IsFalse)
+	_, ok, err = get()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ok, jc.IsFalse)
+	c.Assert(loads, gc.Equals, 2)
+
+	// Errors are not cached.
+	loadErr = errors.New("boom")
+	changes <- struct{}{}
+	_, _, err = get()
+	c.Assert(err, gc.ErrorMatches, "boom")
+	loadErr = nil
+	_, ok, err = get()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(loads, gc.Equals, 4)
+
+	// Once the watcher has stopped, the role is always loaded.
+	close(changes)
+	get()
+	get()
+	c.Assert(loads, gc.Equals, 6)
+}
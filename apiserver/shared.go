@@ -7,10 +7,13 @@ import (
 	"sync"
 	"time"
 
+	"github.com/juju/clock"
 	"github.com/juju/collections/set"
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
+	"github.com/juju/names/v4"
 
+	"github.com/juju/juju/apiserver/authentication"
 	jujucontroller "github.com/juju/juju/controller"
 	"github.com/juju/juju/core/cache"
 	"github.com/juju/juju/core/lease"
@@ -49,6 +52,9 @@ type sharedServerContext struct {
 	controllerConfig jujucontroller.Config
 	features         set.Strings
 
+	// groups resolves the directory groups users belong to.
+	groups *authentication.GroupResolver
+
 	unsubscribe func()
 }
 
@@ -101,6 +107,7 @@ func newSharedServerContext(config sharedServerConfig) (*sharedServerContext, er
 		leaseManager:        config.leaseManager,
 		logger:              config.logger,
 		controllerConfig:    config.controllerConfig,
+		groups:              authentication.NewGroupResolver(clock.WallClock, nil),
 	}
 	ctx.features = config.controllerConfig.Features()
 	// We are able to get the current controller config before subscribing to changes
@@ -147,8 +154,24 @@ func (c *sharedServerContext) featureEnabled(flag string) bool {
 	return c.features.Contains(flag)
 }
 
+// userGroups returns the groups the user belongs to in the directory
+// configured in controller config.
+func (c *sharedServerContext) userGroups(user names.UserTag) ([]string, error) {
+	c.configMutex.RLock()
+	controllerConfig := c.controllerConfig
+	c.configMutex.RUnlock()
+	groups, err := c.groups.UserGroups(controllerConfig, user)
+	return groups, errors.Trace(err)
+}
+
 func (c *sharedServerContext) maxDebugLogDuration() time.Duration {
 	c.configMutex.RLock()
 	defer c.configMutex.RUnlock()
 	return c.controllerConfig.MaxDebugLogDuration()
 }
+
+func (c *sharedServerContext) restGatewayEnabled() bool {
+	c.configMutex.RLock()
+	defer c.configMutex.RUnlock()
+	return c.controllerConfig.RESTGatewayEnabled()
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/httpcontext"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/controller"
 	"github.com/juju/juju/state"
 )
 
@@ -70,6 +71,12 @@ func (a *Authenticator) Maintain(done <-chan struct{}) {
 	}
 }
 
+// UpdateControllerConfig updates the parts of the authenticator that
+// are configured by controller config when that config changes.
+func (a *Authenticator) UpdateControllerConfig(cfg controller.Config) {
+	a.authContext.updateOIDCConfig(cfg)
+}
+
 // CreateLocalLoginMacaroon is part of the
 // httpcontext.LocalMacaroonAuthenticator interface.
 func (a *Authenticator) CreateLocalLoginMacaroon(ctx context.Context, tag names.UserTag, version bakery.Version) (*macaroon.Macaroon, error) {
@@ -101,7 +108,15 @@ func (a *Authenticator) Authenticate(req *http.Request) (httpcontext.AuthInfo, e
 	if err != nil {
 		return httpcontext.AuthInfo{}, errors.Trace(err)
 	}
-	return a.AuthenticateLoginRequest(req.Context(), req.Host, modelUUID, loginRequest)
+	authInfo, err := a.AuthenticateLoginRequest(req.Context(), req.Host, modelUUID, loginRequest)
+	if err != nil {
+		return httpcontext.AuthInfo{}, errors.Trace(err)
+	}
+	if authInfo.PasswordExpired {
+		// Passwords can only be changed over the API.
+		return httpcontext.AuthInfo{}, errors.NewUnauthorized(common.ErrPasswordExpired, "")
+	}
+	return authInfo, nil
 }
 
 // AuthenticateLoginRequest authenticates a LoginRequest.
@@ -129,9 +144,10 @@ func (a *Authenticator) AuthenticateLoginRequest(
 	defer st.Release()
 
 	authenticator := a.authContext.authenticator(serverHost)
+	authenticator.modelUUID = modelUUID
 	authInfo, err := a.checkCreds(ctx, st.State, req, authTag, true, authenticator)
 	if err != nil {
-		if common.IsDischargeRequiredError(err) || errors.IsNotProvisioned(err) {
+		if common.IsDischargeRequiredError(err) || common.IsOIDCLoginRequiredError(err) || errors.IsNotProvisioned(err) {
 			// TODO(axw) move out of common?
 			return httpcontext.AuthInfo{}, errors.Trace(err)
 		}
@@ -169,7 +185,7 @@ func (a *Authenticator) checkCreds(
 		// When looking up model users, use a custom
 		// entity finder that looks up both the local user (if the user
 		// tag is in the local domain) and the model user.
-		entityFinder = modelUserEntityFinder{st}
+		entityFinder = modelUserEntityFinder{st: st, groups: a.authContext.groups}
 	}
 	entity, err := authenticator.Authenticate(ctx, entityFinder, authTag, req)
 	if err != nil {
@@ -177,6 +193,21 @@ func (a *Authenticator) checkCreds(
 	}
 
 	authInfo := httpcontext.AuthInfo{Entity: entity}
+	if expiredEntity, ok := entity.(*authentication.PasswordExpiredEntity); ok {
+		entity = expiredEntity.Entity
+		authInfo = httpcontext.AuthInfo{
+			Entity:          entity,
+			PasswordExpired: true,
+		}
+	}
+	if tokenEntity, ok := entity.(*authentication.TokenEntity); ok {
+		entity = tokenEntity.Entity
+		authInfo = httpcontext.AuthInfo{
+			Entity:      entity,
+			TokenID:     tokenEntity.Token.ID,
+			TokenAccess: tokenEntity.Token.Access,
+		}
+	}
 	type withIsManager interface {
 		IsManager() bool
 	}
@@ -215,6 +246,21 @@ func LoginRequest(req *http.Request) (params.LoginRequest, error) {
 	}
 
 	parts := strings.Fields(authHeader)
+	if len(parts) == 2 && parts[0] == "Bearer" && state.IsAPIToken(parts[1]) {
+		// Automation users present API tokens as bearer tokens.
+		return params.LoginRequest{
+			Macaroons: macaroons,
+			Token:     parts[1],
+		}, nil
+	}
+	if len(parts) == 2 && parts[0] == "Bearer" {
+		// Users authenticated by an OpenID Connect provider
+		// present their ID token as a bearer token.
+		return params.LoginRequest{
+			Macaroons: macaroons,
+			IDToken:   parts[1],
+		}, nil
+	}
 	if len(parts) != 2 || parts[0] != "Basic" {
 		// Invalid header format or no header provided.
 		return params.LoginRequest{}, errors.NotValidf("request format")
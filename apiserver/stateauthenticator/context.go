@@ -22,6 +22,9 @@ import (
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/charmstore"
+	"github.com/juju/juju/controller"
+	"github.com/juju/juju/core/permission"
+	"github.com/juju/juju/oidc"
 	"github.com/juju/juju/state"
 )
 
@@ -57,6 +60,20 @@ type authContext struct {
 	macaroonAuthOnce   sync.Once
 	_macaroonAuth      *authentication.ExternalMacaroonAuthenticator
 	_macaroonAuthError error
+
+	// oidcHTTPClient is used to talk to the OpenID Connect provider.
+	oidcHTTPClient *http.Client
+
+	// oidcMu guards the fields below it. The OpenID Connect
+	// authenticator is built from controller config the first time
+	// it is needed, and rebuilt when that config changes.
+	oidcMu         sync.Mutex
+	oidcConfigured bool
+	_oidcAuth      *authentication.OIDCAuthenticator
+
+	// groups resolves the directory groups users belong to, so that
+	// users are allowed to log in with access granted to their groups.
+	groups *authentication.GroupResolver
 }
 
 // OpenAuthorizer authorises any login operation presented to it.
@@ -81,6 +98,8 @@ func newAuthContext(
 		st:                    st,
 		clock:                 clock,
 		localUserInteractions: authentication.NewInteractions(),
+		oidcHTTPClient:        http.DefaultClient,
+		groups:                authentication.NewGroupResolver(clock, nil),
 	}
 
 	// Create a bakery for discharging third-party caveats for
@@ -172,6 +191,10 @@ func (ctxt *authContext) authenticator(serverHost string) authenticator {
 type authenticator struct {
 	ctxt       *authContext
 	serverHost string
+
+	// modelUUID is the UUID of the model being logged in to, if
+	// any. API tokens are only accepted for logins to their model.
+	modelUUID string
 }
 
 // Authenticate implements authentication.EntityAuthenticator
@@ -183,6 +206,29 @@ func (a authenticator) Authenticate(
 	tag names.Tag,
 	req params.LoginRequest,
 ) (state.Entity, error) {
+	if req.Token != "" {
+		if tag != nil {
+			return nil, errors.Annotatef(common.ErrBadRequest, "unexpected login entity tag with API token")
+		}
+		auth := &authentication.TokenAuthenticator{
+			Tokens:    a.ctxt.st,
+			ModelUUID: a.modelUUID,
+		}
+		return auth.Authenticate(ctx, entityFinder, tag, req)
+	}
+	if req.IDToken != "" {
+		if tag != nil {
+			return nil, errors.Annotatef(common.ErrBadRequest, "unexpected login entity tag with ID token")
+		}
+		auth, err := a.ctxt.oidcAuth()
+		if errors.Cause(err) == errOIDCAuthNotConfigured {
+			err = errors.Trace(common.ErrNoCreds)
+		}
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return auth.Authenticate(ctx, entityFinder, tag, req)
+	}
 	auth, err := a.authenticatorForTag(tag)
 	if err != nil {
 		return nil, errors.Trace(err)
@@ -196,6 +242,13 @@ func (a authenticator) authenticatorForTag(tag names.Tag) (authentication.Entity
 	if tag == nil {
 		auth, err := a.ctxt.externalMacaroonAuth(nil)
 		if errors.Cause(err) == errMacaroonAuthNotConfigured {
+			// Users of a controller configured for OpenID Connect
+			// are told where to obtain an ID token.
+			if oidcAuth, oidcErr := a.ctxt.oidcAuth(); oidcErr == nil {
+				return oidcAuth, nil
+			} else if errors.Cause(oidcErr) != errOIDCAuthNotConfigured {
+				return nil, errors.Trace(oidcErr)
+			}
 			err = errors.Trace(common.ErrNoCreds)
 		}
 		if err != nil {
@@ -303,3 +356,93 @@ func newExternalMacaroonAuth(st *state.State, clock clock.Clock, identClient ide
 	}
 	return &auth, nil
 }
+
+var errOIDCAuthNotConfigured = errors.New("OpenID Connect authentication is not configured")
+
+// oidcGrantor is recorded as having granted the controller access given
+// to OpenID Connect users when they are provisioned, so that it can be
+// told apart from access granted explicitly.
+var oidcGrantor = names.NewUserTag("oidc@external")
+
+// oidcAuth returns an authenticator that can authenticate logins for
+// external users with OpenID Connect ID tokens, as configured by the
+// controller config.
+func (ctxt *authContext) oidcAuth() (*authentication.OIDCAuthenticator, error) {
+	ctxt.oidcMu.Lock()
+	defer ctxt.oidcMu.Unlock()
+	if !ctxt.oidcConfigured {
+		controllerCfg, err := ctxt.st.ControllerConfig()
+		if err != nil {
+			return nil, errors.Annotate(err, "cannot get controller config")
+		}
+		ctxt.configureOIDC(controllerCfg)
+	}
+	if ctxt._oidcAuth == nil {
+		return nil, errOIDCAuthNotConfigured
+	}
+	return ctxt._oidcAuth, nil
+}
+
+// updateOIDCConfig rebuilds the OpenID Connect authenticator from
+// changed controller config.
+func (ctxt *authContext) updateOIDCConfig(controllerCfg controller.Config) {
+	ctxt.oidcMu.Lock()
+	defer ctxt.oidcMu.Unlock()
+	ctxt.configureOIDC(controllerCfg)
+}
+
+// configureOIDC builds the OpenID Connect authenticator from the
+// controller config. The verifier, and with it the provider's keys,
+// is kept while the issuer and client ID stay the same.
+// It must be called with oidcMu held.
+func (ctxt *authContext) configureOIDC(controllerCfg controller.Config) {
+	ctxt.oidcConfigured = true
+	issuer := controllerCfg.OIDCIssuerURL()
+	if issuer == "" {
+		ctxt._oidcAuth = nil
+		return
+	}
+	clientID := controllerCfg.OIDCClientID()
+
+	var verifier *oidc.Verifier
+	if current := ctxt._oidcAuth; current != nil &&
+		current.Verifier.Issuer() == issuer && current.Verifier.ClientID() == clientID {
+		verifier = current.Verifier
+	} else {
+		verifier = oidc.NewVerifier(oidc.VerifierConfig{
+			Issuer:     issuer,
+			ClientID:   clientID,
+			HTTPClient: ctxt.oidcHTTPClient,
+			Clock:      ctxt.clock,
+		})
+	}
+
+	groupAccess := make(map[string]permission.Access)
+	for group, access := range controllerCfg.OIDCGroupAccess() {
+		groupAccess[group] = permission.Access(access)
+	}
+	ctxt._oidcAuth = &authentication.OIDCAuthenticator{
+		Verifier:      verifier,
+		UsernameClaim: controllerCfg.OIDCUsernameClaim(),
+		GroupsClaim:   controllerCfg.OIDCGroupsClaim(),
+		GroupAccess:   groupAccess,
+		ProvisionUser: func(user names.UserTag, access permission.Access) error {
+			return provisionOIDCUser(ctxt.st, user, access)
+		},
+	}
+}
+
+// provisionOIDCUser grants an OpenID Connect user logging in for the
+// first time the controller access their groups grant them.
+func provisionOIDCUser(st *state.State, user names.UserTag, access permission.Access) error {
+	_, err := st.AddControllerUser(state.UserAccessSpec{
+		User:      user,
+		CreatedBy: oidcGrantor,
+		Access:    access,
+	})
+	if errors.IsAlreadyExists(err) {
+		// Another login provisioned the user first.
+		return nil
+	}
+	return errors.Trace(err)
+}
@@ -5,9 +5,11 @@ package stateauthenticator_test
 
 import (
 	"context"
+	"time"
 
 	"github.com/juju/clock"
 	"github.com/juju/errors"
+	"github.com/juju/names/v4"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 	"gopkg.in/macaroon-bakery.v2/bakery"
@@ -17,8 +19,13 @@ import (
 	"gopkg.in/macaroon-bakery.v2/httpbakery"
 	"gopkg.in/macaroon.v2"
 
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/apiserver/stateauthenticator"
 	"github.com/juju/juju/controller"
+	"github.com/juju/juju/core/permission"
+	"github.com/juju/juju/oidc/oidctesting"
+	"github.com/juju/juju/state"
 	statetesting "github.com/juju/juju/state/testing"
 )
 
@@ -170,3 +177,142 @@ func (s *macaroonNoURLSuite) TestNoBakeryWhenNoIdentityURL(c *gc.C) {
 	_, err := stateauthenticator.ServerBakery(s.authenticator, nil)
 	c.Assert(err, gc.ErrorMatches, "macaroon authentication is not configured")
 }
+
+type oidcAuthSuite struct {
+	macaroonCommonSuite
+	issuer *oidctesting.Issuer
+}
+
+var _ = gc.Suite(&oidcAuthSuite{})
+
+func (s *oidcAuthSuite) SetUpTest(c *gc.C) {
+	s.issuer = oidctesting.NewIssuer("juju")
+	s.ControllerConfig = map[string]interface{}{
+		controller.OIDCIssuerURL:   s.issuer.URL,
+		controller.OIDCClientID:    "juju",
+		controller.OIDCGroupAccess: []interface{}{"devs=login", "admins=superuser"},
+	}
+	s.macaroonCommonSuite.SetUpTest(c)
+	stateauthenticator.SetOIDCHTTPClient(s.authenticator, s.issuer.Client())
+}
+
+func (s *oidcAuthSuite) TearDownTest(c *gc.C) {
+	s.macaroonCommonSuite.TearDownTest(c)
+	s.issuer.Close()
+}
+
+func (s *oidcAuthSuite) login(groups ...string) error {
+	token := s.issuer.IDToken(map[string]interface{}{
+		"sub":            "1234",
+		"email":          "bob@example.com",
+		"email_verified": true,
+		"groups":         groups,
+	}, time.Now().Add(time.Hour))
+	_, err := s.authenticator.AuthenticateLoginRequest(context.Background(), "testing.invalid:1234", s.State.ModelUUID(), params.LoginRequest{
+		IDToken: token,
+	})
+	return err
+}
+
+func (s *oidcAuthSuite) TestLoginRequired(c *gc.C) {
+	_, err := s.authenticator.AuthenticateLoginRequest(context.Background(), "testing.invalid:1234", s.State.ModelUUID(), params.LoginRequest{})
+	c.Assert(err, jc.Satisfies, common.IsOIDCLoginRequiredError)
+}
+
+func (s *oidcAuthSuite) TestGroupAccess(c *gc.C) {
+	bob := names.NewUserTag("bob@example.com")
+	err := s.login()
+	c.Assert(err, jc.Satisfies, errors.IsUnauthorized)
+	_, err = s.State.UserAccess(bob, s.State.ControllerTag())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	err = s.login("admins")
+	c.Assert(err, jc.ErrorIsNil)
+	access, err := s.State.UserAccess(bob, s.State.ControllerTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(access.Access, gc.Equals, permission.SuperuserAccess)
+	c.Assert(access.CreatedBy, gc.Equals, names.NewUserTag("oidc@external"))
+
+	// Access is only granted when the user is provisioned; later
+	// logins leave it alone.
+	err = s.login("devs")
+	c.Assert(err, jc.ErrorIsNil)
+	access, err = s.State.UserAccess(bob, s.State.ControllerTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(access.Access, gc.Equals, permission.SuperuserAccess)
+}
+
+func (s *oidcAuthSuite) TestConfigChange(c *gc.C) {
+	err := s.login("admins")
+	c.Assert(err, jc.ErrorIsNil)
+
+	controllerCfg, err := s.State.ControllerConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	controllerCfg[controller.OIDCIssuerURL] = ""
+	s.authenticator.UpdateControllerConfig(controllerCfg)
+	err = s.login("admins")
+	c.Assert(errors.Cause(err), gc.Equals, common.ErrNoCreds)
+}
+
+func (s *oidcAuthSuite) TestExplicitAccessNotLowered(c *gc.C) {
+	bob := names.NewUserTag("bob@example.com")
+	_, err := s.State.AddControllerUser(state.UserAccessSpec{
+		User:      bob,
+		CreatedBy: s.Owner,
+		Access:    permission.SuperuserAccess,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.login("devs")
+	c.Assert(err, jc.ErrorIsNil)
+	access, err := s.State.UserAccess(bob, s.State.ControllerTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(access.Access, gc.Equals, permission.SuperuserAccess)
+}
+
+type apiTokenAuthSuite struct {
+	macaroonCommonSuite
+}
+
+var _ = gc.Suite(&apiTokenAuthSuite{})
+
+func (s *apiTokenAuthSuite) addToken(c *gc.C) (state.APIToken, string) {
+	token, secret, err := s.State.AddAPIToken(state.AddAPITokenArgs{
+		Owner:     s.Owner,
+		Model:     names.NewModelTag(s.State.ModelUUID()),
+		Access:    permission.ReadAccess,
+		CreatedBy: s.Owner,
+		Expires:   time.Now().Add(time.Hour),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	return token, secret
+}
+
+func (s *apiTokenAuthSuite) TestLogin(c *gc.C) {
+	token, secret := s.addToken(c)
+	authInfo, err := s.authenticator.AuthenticateLoginRequest(context.Background(), "testing.invalid:1234", s.State.ModelUUID(), params.LoginRequest{
+		Token: secret,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(authInfo.Entity.Tag(), gc.Equals, s.Owner)
+	c.Assert(authInfo.TokenID, gc.Equals, token.ID)
+	c.Assert(authInfo.TokenAccess, gc.Equals, permission.ReadAccess)
+}
+
+func (s *apiTokenAuthSuite) TestLoginRevoked(c *gc.C) {
+	token, secret := s.addToken(c)
+	err := s.State.RemoveAPIToken(token.ID)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.authenticator.AuthenticateLoginRequest(context.Background(), "testing.invalid:1234", s.State.ModelUUID(), params.LoginRequest{
+		Token: secret,
+	})
+	c.Assert(err, jc.Satisfies, errors.IsUnauthorized)
+}
+
+func (s *apiTokenAuthSuite) TestLoginWithTag(c *gc.C) {
+	_, secret := s.addToken(c)
+	_, err := s.authenticator.AuthenticateLoginRequest(context.Background(), "testing.invalid:1234", s.State.ModelUUID(), params.LoginRequest{
+		AuthTag: s.Owner.String(),
+		Token:   secret,
+	})
+	c.Assert(err, gc.ErrorMatches, "unexpected login entity tag with API token: .*")
+}
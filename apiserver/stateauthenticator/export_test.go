@@ -4,6 +4,8 @@
 package stateauthenticator
 
 import (
+	"net/http"
+
 	"gopkg.in/macaroon-bakery.v2/bakery/identchecker"
 
 	"github.com/juju/juju/apiserver/authentication"
@@ -23,3 +25,7 @@ func ServerBakery(a *Authenticator, identClient identchecker.IdentityClient) (*i
 	}
 	return auth.(*authentication.ExternalMacaroonAuthenticator).Bakery, nil
 }
+
+func SetOIDCHTTPClient(a *Authenticator, client *http.Client) {
+	a.authContext.oidcHTTPClient = client
+}
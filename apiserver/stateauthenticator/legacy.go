@@ -16,6 +16,7 @@ import (
 	"gopkg.in/macaroon.v2"
 
 	"github.com/juju/juju/apiserver/apiserverhttp"
+	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/charmstore"
 )
@@ -68,6 +69,7 @@ func (h *localLoginHandlers) serveLoginPost(response http.ResponseWriter, req *h
 	}
 	username := req.Form.Get("user")
 	password := req.Form.Get("password")
+	mfaCode := req.Form.Get("mfa-code")
 	if !names.IsValidUser(username) {
 		return nil, errors.NotValidf("username %q", username)
 	}
@@ -79,7 +81,13 @@ func (h *localLoginHandlers) serveLoginPost(response http.ResponseWriter, req *h
 	authenticator := h.authCtxt.authenticator(req.Host)
 	if _, err := authenticator.Authenticate(req.Context(), h.finder, userTag, params.LoginRequest{
 		Credentials: password,
+		MFACode:     mfaCode,
 	}); err != nil {
+		if errors.Cause(err) == common.ErrMFARequired {
+			// Leave the interaction pending so that the
+			// client may retry with a code.
+			return nil, mfaError(err)
+		}
 		// Mark the interaction as done (but failed),
 		// unblocking a pending "/auth/wait" request.
 		if err := h.authCtxt.localUserInteractions.Done(waitId, userTag, err); err != nil {
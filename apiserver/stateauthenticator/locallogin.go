@@ -20,6 +20,7 @@ import (
 
 	"github.com/juju/juju/apiserver/apiserverhttp"
 	"github.com/juju/juju/apiserver/authentication"
+	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/state"
 )
@@ -79,6 +80,7 @@ func (h *localLoginHandlers) formHandler(w http.ResponseWriter, req *http.Reques
 
 		username := loginRequest.Body.Form["user"].(string)
 		password := loginRequest.Body.Form["password"].(string)
+		mfaCode, _ := loginRequest.Body.Form["mfa-code"].(string)
 		userTag := names.NewUserTag(username)
 		if !userTag.IsLocal() {
 			h.bakeryError(w, errors.NotValidf("non-local username %q", username))
@@ -88,8 +90,9 @@ func (h *localLoginHandlers) formHandler(w http.ResponseWriter, req *http.Reques
 		authenticator := h.authCtxt.authenticator(req.Host)
 		if _, err := authenticator.Authenticate(ctx, h.finder, userTag, params.LoginRequest{
 			Credentials: password,
+			MFACode:     mfaCode,
 		}); err != nil {
-			h.bakeryError(w, err)
+			h.bakeryError(w, mfaError(err))
 			return
 		}
 
@@ -112,6 +115,19 @@ func (h *localLoginHandlers) formHandler(w http.ResponseWriter, req *http.Reques
 	}
 }
 
+// mfaError converts the error returned when a user enrolled for
+// multi-factor authentication logs in without a code into one that
+// login clients recognise, so they can prompt for the code and retry.
+func mfaError(err error) error {
+	if errors.Cause(err) == common.ErrMFARequired {
+		return &httpbakery.Error{
+			Code:    httpbakery.ErrorCode(params.CodeMFARequired),
+			Message: err.Error(),
+		}
+	}
+	return err
+}
+
 func newId() (string, error) {
 	var id [12]byte
 	if _, err := rand.Read(id[:]); err != nil {
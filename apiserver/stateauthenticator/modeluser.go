@@ -9,6 +9,7 @@ import (
 	"github.com/juju/errors"
 	"github.com/juju/names/v4"
 
+	"github.com/juju/juju/apiserver/authentication"
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/core/permission"
 	"github.com/juju/juju/state"
@@ -27,7 +28,8 @@ type loginEntity interface {
 // an Entity value for model users, ensuring that the user exists in
 // the state's current model, while also supporting external users.
 type modelUserEntityFinder struct {
-	st *state.State
+	st     *state.State
+	groups *authentication.GroupResolver
 }
 
 // FindEntity implements state.EntityFinder.
@@ -66,7 +68,15 @@ func (f modelUserEntityFinder) FindEntity(tag names.Tag) (state.Entity, error) {
 			}
 		}
 		if permission.IsEmptyUserAccess(controllerUser) {
-			return nil, errors.NotFoundf("model or controller user")
+			// Users may also be granted access through the
+			// groups they belong to.
+			hasGroupAccess, err := f.hasGroupAccess(utag, model.ModelTag())
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			if !hasGroupAccess {
+				return nil, errors.NotFoundf("model or controller user")
+			}
 		}
 	}
 
@@ -85,6 +95,47 @@ func (f modelUserEntityFinder) FindEntity(tag names.Tag) (state.Entity, error) {
 	return u, nil
 }
 
+// hasGroupAccess reports whether any group the user belongs to has been
+// granted access to the model or the controller. The user's groups are
+// not looked up unless some group has been granted access.
+func (f modelUserEntityFinder) hasGroupAccess(user names.UserTag, modelTag names.ModelTag) (bool, error) {
+	if f.groups == nil {
+		return false, nil
+	}
+	var targets []names.Tag
+	for _, target := range []names.Tag{modelTag, f.st.ControllerTag()} {
+		groupsAccess, err := f.st.GroupsAccess(target)
+		if err != nil {
+			return false, errors.Trace(err)
+		}
+		if len(groupsAccess) > 0 {
+			targets = append(targets, target)
+		}
+	}
+	if len(targets) == 0 {
+		return false, nil
+	}
+	controllerCfg, err := f.st.ControllerConfig()
+	if err != nil {
+		return false, errors.Annotate(err, "cannot get controller config")
+	}
+	groups, err := f.groups.UserGroups(controllerCfg, user)
+	if err != nil {
+		logger.Warningf("cannot obtain groups for %s: %v", user.Id(), err)
+		return false, nil
+	}
+	for _, target := range targets {
+		access, err := common.GroupPermission(f.st, groups, target)
+		if err != nil {
+			return false, errors.Trace(err)
+		}
+		if access != permission.NoAccess {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 // modelUserEntity encapsulates an model user
 // and, if the user is local, the local state user
 // as well. This enables us to implement FindEntity
@@ -123,6 +174,60 @@ func (u *modelUserEntity) PasswordValid(pass string) bool {
 	return u.user.PasswordValid(pass)
 }
 
+// IsDisabled reports whether the local user has been disabled.
+func (u *modelUserEntity) IsDisabled() bool {
+	return u.user != nil && u.user.IsDisabled()
+}
+
+// MFAEnabled returns whether the local user is enrolled for
+// multi-factor authentication.
+func (u *modelUserEntity) MFAEnabled() bool {
+	return u.user != nil && u.user.MFAEnabled()
+}
+
+// CheckMFACode checks a multi-factor authentication code for the local
+// user.
+func (u *modelUserEntity) CheckMFACode(code string) error {
+	if u.user == nil {
+		return errors.New("cannot check multi-factor authentication code for external user")
+	}
+	return u.user.CheckMFACode(code)
+}
+
+// LockedUntil returns the time at which the local user's lockout, due
+// to failed logins, ends, and whether they are currently locked out.
+func (u *modelUserEntity) LockedUntil() (time.Time, bool) {
+	if u.user == nil {
+		return time.Time{}, false
+	}
+	return u.user.LockedUntil()
+}
+
+// RecordFailedLogin records a failed password login for the local user.
+func (u *modelUserEntity) RecordFailedLogin() error {
+	if u.user == nil {
+		return nil
+	}
+	return u.user.RecordFailedLogin()
+}
+
+// ResetFailedLogins clears the local user's failed logins.
+func (u *modelUserEntity) ResetFailedLogins() error {
+	if u.user == nil {
+		return nil
+	}
+	return u.user.ResetFailedLogins()
+}
+
+// PasswordExpired reports whether the local user's password has
+// expired.
+func (u *modelUserEntity) PasswordExpired() (bool, error) {
+	if u.user == nil {
+		return false, nil
+	}
+	return u.user.PasswordExpired()
+}
+
 // Tag implements state.Entity.Tag.
 func (u *modelUserEntity) Tag() names.Tag {
 	return u.tag
@@ -82,6 +82,12 @@ func (srv *Server) serveAPI(w http.ResponseWriter, req *http.Request) {
 
 func (srv *Server) serveConn(ctx context.Context, wsConn *websocket.Conn, modelUUID string) {
 	codec := jsoncodec.NewWebsocket(wsConn)
+	// Accept CBOR messages without waiting for them to be
+	// negotiated at login, so that the fake API can choose
+	// any codec.
+	if err := codec.EnableBinary(); err != nil {
+		panic(err)
+	}
 	conn := rpc.NewConn(codec, observer.NewRecorderFactory(
 		&fakeobserver.Instance{}, nil, observer.NoCaptureArgs))
 
@@ -17,6 +17,7 @@ type FakeAuthorizer struct {
 	ModelUUID   string
 	AdminTag    names.UserTag
 	HasWriteTag names.UserTag
+	Groups      []string
 }
 
 func (fa FakeAuthorizer) AuthOwner(tag names.Tag) bool {
@@ -147,3 +148,8 @@ func (fa FakeAuthorizer) UserHasPermission(user names.UserTag, operation permiss
 	}
 	return false, nil
 }
+
+// UserGroups returns the pre-set groups, whatever the user.
+func (fa FakeAuthorizer) UserGroups(user names.UserTag) []string {
+	return fa.Groups
+}
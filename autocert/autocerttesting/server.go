@@ -0,0 +1,780 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package autocerttesting provides a local ACME server for testing code
+// that obtains certificates.
+package autocerttesting
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/acme"
+)
+
+// ServerConfig holds the configuration for a Server.
+type ServerConfig struct {
+	// EABKeyID and EABKey, if set, require accounts to be registered
+	// with an external account binding using this MAC key.
+	EABKeyID string
+	EABKey   []byte
+
+	// LookupTXT returns the TXT records with the given fully
+	// qualified name (which ends in a dot). It is used to check
+	// dns-01 challenges, which fail if it is nil.
+	LookupTXT func(fqdn string) []string
+
+	// TLSALPNAddr is the address connected to when checking
+	// tls-alpn-01 challenges, which fail if it is empty.
+	TLSALPNAddr string
+
+	// Validity is how long issued certificates are valid for. It
+	// defaults to 90 days.
+	Validity time.Duration
+
+	// Now returns the current time. It defaults to time.Now.
+	Now func() time.Time
+}
+
+// Server is an ACME server implementing enough of RFC 8555 to issue
+// certificates to golang.org/x/crypto/acme clients. Requests are
+// not authenticated beyond checking any external account binding.
+type Server struct {
+	config ServerConfig
+	server *httptest.Server
+
+	// CACert is the certificate of the CA that issues certificates.
+	CACert *x509.Certificate
+	caKey  crypto.Signer
+
+	mu       sync.Mutex
+	nonce    int
+	accounts map[string]*account
+	orders   []*order
+	authzs   []*authz
+	issued   []*x509.Certificate
+}
+
+type account struct {
+	url        string
+	thumbprint string
+}
+
+type order struct {
+	id     int
+	authzs []*authz
+	names  []string
+	cert   []byte
+}
+
+type authz struct {
+	id         int
+	account    *account
+	name       string
+	status     string
+	challenges []*challenge
+}
+
+type challenge struct {
+	typ    string
+	token  string
+	status string
+	err    string
+}
+
+// NewServer starts and returns a new Server, which should be closed
+// when no longer needed.
+func NewServer(config ServerConfig) *Server {
+	if config.Validity == 0 {
+		config.Validity = 90 * 24 * time.Hour
+	}
+	if config.Now == nil {
+		config.Now = time.Now
+	}
+	s := &Server{
+		config:   config,
+		accounts: make(map[string]*account),
+	}
+	s.newCA()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/directory", s.serveDirectory)
+	mux.HandleFunc("/new-nonce", s.serveNonce)
+	mux.HandleFunc("/new-account", s.post(s.serveNewAccount))
+	mux.HandleFunc("/new-order", s.post(s.serveNewOrder))
+	mux.HandleFunc("/order/", s.post(s.serveOrder))
+	mux.HandleFunc("/authz/", s.post(s.serveAuthz))
+	mux.HandleFunc("/challenge/", s.post(s.serveChallenge))
+	mux.HandleFunc("/finalize/", s.post(s.serveFinalize))
+	mux.HandleFunc("/cert/", s.post(s.serveCert))
+	s.server = httptest.NewTLSServer(mux)
+	return s
+}
+
+// Close shuts the server down.
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// DirectoryURL returns the URL of the server's ACME directory.
+func (s *Server) DirectoryURL() string {
+	return s.server.URL + "/directory"
+}
+
+// Client returns an HTTP client that trusts the server.
+func (s *Server) Client() *http.Client {
+	return s.server.Client()
+}
+
+// Issued returns the certificates issued by the server.
+func (s *Server) Issued() []*x509.Certificate {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*x509.Certificate(nil), s.issued...)
+}
+
+func (s *Server) newCA() {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+	now := s.config.Now()
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "autocerttesting CA"},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(10 * 365 * 24 * time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		panic(err)
+	}
+	s.CACert, err = x509.ParseCertificate(der)
+	if err != nil {
+		panic(err)
+	}
+	s.caKey = key
+}
+
+func (s *Server) url(path string, id int) string {
+	return fmt.Sprintf("%s/%s/%d", s.server.URL, path, id)
+}
+
+func (s *Server) addNonce(w http.ResponseWriter) {
+	s.mu.Lock()
+	s.nonce++
+	nonce := strconv.Itoa(s.nonce)
+	s.mu.Unlock()
+	w.Header().Set("Replay-Nonce", nonce)
+	w.Header().Set("Cache-Control", "no-store")
+}
+
+func (s *Server) serveDirectory(w http.ResponseWriter, r *http.Request) {
+	s.addNonce(w)
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"newNonce":   s.server.URL + "/new-nonce",
+		"newAccount": s.server.URL + "/new-account",
+		"newOrder":   s.server.URL + "/new-order",
+		"meta": map[string]interface{}{
+			"externalAccountRequired": s.config.EABKeyID != "",
+		},
+	})
+}
+
+func (s *Server) serveNonce(w http.ResponseWriter, r *http.Request) {
+	s.addNonce(w)
+	w.WriteHeader(http.StatusOK)
+}
+
+// request holds the contents of a JWS encoded request.
+type request struct {
+	path    string
+	jwk     json.RawMessage
+	account *account
+	id      int
+	payload []byte
+}
+
+// acmeError is an ACME problem document.
+type acmeError struct {
+	status int
+	typ    string
+	detail string
+}
+
+func (e *acmeError) Error() string {
+	return e.detail
+}
+
+func newError(status int, typ, format string, args ...interface{}) *acmeError {
+	return &acmeError{
+		status: status,
+		typ:    "urn:ietf:params:acme:error:" + typ,
+		detail: fmt.Sprintf(format, args...),
+	}
+}
+
+// post wraps a handler for JWS encoded POST requests.
+func (s *Server) post(handle func(w http.ResponseWriter, req *request) error) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.addNonce(w)
+		req, err := s.parseRequest(r)
+		if err == nil {
+			err = handle(w, req)
+		}
+		if err != nil {
+			e, ok := err.(*acmeError)
+			if !ok {
+				e = newError(http.StatusInternalServerError, "serverInternal", "%v", err)
+			}
+			w.Header().Set("Content-Type", "application/problem+json")
+			writeJSON(w, e.status, map[string]interface{}{
+				"type":   e.typ,
+				"detail": e.detail,
+			})
+		}
+	}
+}
+
+func (s *Server) parseRequest(r *http.Request) (*request, error) {
+	if r.Method != "POST" {
+		return nil, newError(http.StatusMethodNotAllowed, "malformed", "method %s not allowed", r.Method)
+	}
+	var jws struct {
+		Protected string `json:"protected"`
+		Payload   string `json:"payload"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&jws); err != nil {
+		return nil, newError(http.StatusBadRequest, "malformed", "%v", err)
+	}
+	var header struct {
+		JWK json.RawMessage `json:"jwk"`
+		KID string          `json:"kid"`
+	}
+	if err := decodeSegment(jws.Protected, &header); err != nil {
+		return nil, newError(http.StatusBadRequest, "malformed", "%v", err)
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(jws.Payload)
+	if err != nil {
+		return nil, newError(http.StatusBadRequest, "malformed", "%v", err)
+	}
+	req := &request{path: r.URL.Path, jwk: header.JWK, payload: payload}
+	if header.KID != "" {
+		s.mu.Lock()
+		for _, a := range s.accounts {
+			if a.url == header.KID {
+				req.account = a
+			}
+		}
+		s.mu.Unlock()
+		if req.account == nil {
+			return nil, newError(http.StatusBadRequest, "accountDoesNotExist", "unknown account %q", header.KID)
+		}
+	}
+	if i := strings.LastIndex(r.URL.Path, "/"); i > 0 {
+		req.id, _ = strconv.Atoi(r.URL.Path[i+1:])
+	}
+	return req, nil
+}
+
+func (s *Server) serveNewAccount(w http.ResponseWriter, req *request) error {
+	if req.jwk == nil {
+		return newError(http.StatusBadRequest, "malformed", "new account request without jwk")
+	}
+	thumbprint, err := jwkThumbprint(req.jwk)
+	if err != nil {
+		return newError(http.StatusBadRequest, "badPublicKey", "%v", err)
+	}
+	var args struct {
+		OnlyReturnExisting     bool            `json:"onlyReturnExisting"`
+		ExternalAccountBinding json.RawMessage `json:"externalAccountBinding"`
+	}
+	if err := json.Unmarshal(req.payload, &args); err != nil {
+		return newError(http.StatusBadRequest, "malformed", "%v", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if a := s.accounts[thumbprint]; a != nil {
+		w.Header().Set("Location", a.url)
+		writeJSON(w, http.StatusOK, map[string]string{"status": acme.StatusValid})
+		return nil
+	}
+	if args.OnlyReturnExisting {
+		return newError(http.StatusBadRequest, "accountDoesNotExist", "no account for key")
+	}
+	if s.config.EABKeyID != "" {
+		if args.ExternalAccountBinding == nil {
+			return newError(http.StatusBadRequest, "externalAccountRequired", "external account binding required")
+		}
+		if err := s.checkEAB(args.ExternalAccountBinding, thumbprint); err != nil {
+			return newError(http.StatusUnauthorized, "unauthorized", "%v", err)
+		}
+	}
+	a := &account{
+		url:        s.url("account", len(s.accounts)+1),
+		thumbprint: thumbprint,
+	}
+	s.accounts[thumbprint] = a
+	w.Header().Set("Location", a.url)
+	writeJSON(w, http.StatusCreated, map[string]string{"status": acme.StatusValid})
+	return nil
+}
+
+// checkEAB checks that the external account binding is signed with
+// the configured MAC key, and binds the account's key.
+func (s *Server) checkEAB(data json.RawMessage, thumbprint string) error {
+	var jws struct {
+		Protected string `json:"protected"`
+		Payload   string `json:"payload"`
+		Signature string `json:"signature"`
+	}
+	if err := json.Unmarshal(data, &jws); err != nil {
+		return err
+	}
+	var header struct {
+		Alg string `json:"alg"`
+		KID string `json:"kid"`
+	}
+	if err := decodeSegment(jws.Protected, &header); err != nil {
+		return err
+	}
+	if header.Alg != "HS256" || header.KID != s.config.EABKeyID {
+		return fmt.Errorf("unexpected external account binding key %q (%s)", header.KID, header.Alg)
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(jws.Signature)
+	if err != nil {
+		return err
+	}
+	mac := hmac.New(sha256.New, s.config.EABKey)
+	mac.Write([]byte(jws.Protected + "." + jws.Payload))
+	if !hmac.Equal(sig, mac.Sum(nil)) {
+		return fmt.Errorf("invalid external account binding signature")
+	}
+	var jwk json.RawMessage
+	if err := decodeSegment(jws.Payload, &jwk); err != nil {
+		return err
+	}
+	if bound, err := jwkThumbprint(jwk); err != nil || bound != thumbprint {
+		return fmt.Errorf("external account binding is for a different key")
+	}
+	return nil
+}
+
+func (s *Server) serveNewOrder(w http.ResponseWriter, req *request) error {
+	if req.account == nil {
+		return newError(http.StatusUnauthorized, "unauthorized", "no account")
+	}
+	var args struct {
+		Identifiers []struct {
+			Type  string `json:"type"`
+			Value string `json:"value"`
+		} `json:"identifiers"`
+	}
+	if err := json.Unmarshal(req.payload, &args); err != nil {
+		return newError(http.StatusBadRequest, "malformed", "%v", err)
+	}
+	if len(args.Identifiers) == 0 {
+		return newError(http.StatusBadRequest, "malformed", "no identifiers")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	o := &order{id: len(s.orders) + 1}
+	for _, id := range args.Identifiers {
+		if id.Type != "dns" {
+			return newError(http.StatusBadRequest, "unsupportedIdentifier", "identifier type %q", id.Type)
+		}
+		z := &authz{
+			id:      len(s.authzs) + 1,
+			account: req.account,
+			name:    id.Value,
+			status:  acme.StatusPending,
+		}
+		for _, typ := range []string{"tls-alpn-01", "dns-01"} {
+			z.challenges = append(z.challenges, &challenge{
+				typ:    typ,
+				token:  newToken(),
+				status: acme.StatusPending,
+			})
+		}
+		s.authzs = append(s.authzs, z)
+		o.authzs = append(o.authzs, z)
+		o.names = append(o.names, id.Value)
+	}
+	s.orders = append(s.orders, o)
+	s.writeOrder(w, http.StatusCreated, o)
+	return nil
+}
+
+func (s *Server) serveOrder(w http.ResponseWriter, req *request) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	o, err := s.order(req.id)
+	if err != nil {
+		return err
+	}
+	s.writeOrder(w, http.StatusOK, o)
+	return nil
+}
+
+func (s *Server) order(id int) (*order, error) {
+	if id < 1 || id > len(s.orders) {
+		return nil, newError(http.StatusNotFound, "malformed", "order %d not found", id)
+	}
+	return s.orders[id-1], nil
+}
+
+func (s *Server) orderStatus(o *order) string {
+	if o.cert != nil {
+		return acme.StatusValid
+	}
+	status := acme.StatusReady
+	for _, z := range o.authzs {
+		switch z.status {
+		case acme.StatusInvalid:
+			return acme.StatusInvalid
+		case acme.StatusPending:
+			status = acme.StatusPending
+		}
+	}
+	return status
+}
+
+func (s *Server) writeOrder(w http.ResponseWriter, code int, o *order) {
+	var authzURLs, identifiers []interface{}
+	for _, z := range o.authzs {
+		authzURLs = append(authzURLs, s.url("authz", z.id))
+		identifiers = append(identifiers, map[string]string{"type": "dns", "value": z.name})
+	}
+	body := map[string]interface{}{
+		"status":         s.orderStatus(o),
+		"identifiers":    identifiers,
+		"authorizations": authzURLs,
+		"finalize":       s.url("finalize", o.id),
+	}
+	if o.cert != nil {
+		body["certificate"] = s.url("cert", o.id)
+	}
+	w.Header().Set("Location", s.url("order", o.id))
+	writeJSON(w, code, body)
+}
+
+func (s *Server) serveAuthz(w http.ResponseWriter, req *request) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	z, err := s.authz(req.id)
+	if err != nil {
+		return err
+	}
+	var challenges []interface{}
+	for _, c := range z.challenges {
+		challenges = append(challenges, s.challengeJSON(z, c))
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"identifier": map[string]string{"type": "dns", "value": z.name},
+		"status":     z.status,
+		"challenges": challenges,
+	})
+	return nil
+}
+
+func (s *Server) authz(id int) (*authz, error) {
+	if id < 1 || id > len(s.authzs) {
+		return nil, newError(http.StatusNotFound, "malformed", "authorization %d not found", id)
+	}
+	return s.authzs[id-1], nil
+}
+
+func (s *Server) challengeJSON(z *authz, c *challenge) map[string]interface{} {
+	body := map[string]interface{}{
+		"type":   c.typ,
+		"url":    fmt.Sprintf("%s/challenge/%s/%d", s.server.URL, c.typ, z.id),
+		"token":  c.token,
+		"status": c.status,
+	}
+	if c.err != "" {
+		body["error"] = map[string]string{
+			"type":   "urn:ietf:params:acme:error:incorrectResponse",
+			"detail": c.err,
+		}
+	}
+	return body
+}
+
+func (s *Server) serveChallenge(w http.ResponseWriter, req *request) error {
+	s.mu.Lock()
+	z, err := s.authz(req.id)
+	s.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	// Challenge URLs are of the form /challenge/<type>/<authz id>.
+	var c *challenge
+	for _, zc := range z.challenges {
+		if strings.HasPrefix(req.path, "/challenge/"+zc.typ+"/") {
+			c = zc
+		}
+	}
+	if c == nil {
+		return newError(http.StatusNotFound, "malformed", "challenge not found")
+	}
+
+	// Validate synchronously, without holding the lock, as the
+	// client may need to call back into the server.
+	keyAuth := c.token + "." + z.account.thumbprint
+	var verr error
+	switch c.typ {
+	case "dns-01":
+		verr = s.checkDNS01(z.name, keyAuth)
+	case "tls-alpn-01":
+		verr = s.checkTLSALPN01(z.name, keyAuth)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if verr != nil {
+		c.status = acme.StatusInvalid
+		c.err = verr.Error()
+		z.status = acme.StatusInvalid
+	} else {
+		c.status = acme.StatusValid
+		z.status = acme.StatusValid
+	}
+	writeJSON(w, http.StatusOK, s.challengeJSON(z, c))
+	return nil
+}
+
+func (s *Server) checkDNS01(name, keyAuth string) error {
+	if s.config.LookupTXT == nil {
+		return fmt.Errorf("dns-01 challenges not supported")
+	}
+	sum := sha256.Sum256([]byte(keyAuth))
+	want := base64.RawURLEncoding.EncodeToString(sum[:])
+	fqdn := "_acme-challenge." + name + "."
+	for _, value := range s.config.LookupTXT(fqdn) {
+		if value == want {
+			return nil
+		}
+	}
+	return fmt.Errorf("no TXT record %q found for %s", want, fqdn)
+}
+
+// idPeACMEIdentifier is the OID of the certificate extension holding
+// the tls-alpn-01 key authorization digest.
+var idPeACMEIdentifier = asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 1, 31}
+
+func (s *Server) checkTLSALPN01(name, keyAuth string) error {
+	if s.config.TLSALPNAddr == "" {
+		return fmt.Errorf("tls-alpn-01 challenges not supported")
+	}
+	conn, err := tls.Dial("tcp", s.config.TLSALPNAddr, &tls.Config{
+		ServerName:         name,
+		NextProtos:         []string{acme.ALPNProto},
+		InsecureSkipVerify: true,
+	})
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	state := conn.ConnectionState()
+	if state.NegotiatedProtocol != acme.ALPNProto {
+		return fmt.Errorf("negotiated protocol %q, expected %q", state.NegotiatedProtocol, acme.ALPNProto)
+	}
+	if len(state.PeerCertificates) == 0 {
+		return fmt.Errorf("no certificate presented")
+	}
+	cert := state.PeerCertificates[0]
+	if err := cert.VerifyHostname(name); err != nil {
+		return err
+	}
+	sum := sha256.Sum256([]byte(keyAuth))
+	for _, ext := range cert.Extensions {
+		if !ext.Id.Equal(idPeACMEIdentifier) {
+			continue
+		}
+		var digest []byte
+		if _, err := asn1.Unmarshal(ext.Value, &digest); err != nil {
+			return err
+		}
+		if !bytes.Equal(digest, sum[:]) {
+			return fmt.Errorf("incorrect key authorization digest")
+		}
+		return nil
+	}
+	return fmt.Errorf("no acmeIdentifier extension in certificate")
+}
+
+func (s *Server) serveFinalize(w http.ResponseWriter, req *request) error {
+	var args struct {
+		CSR string `json:"csr"`
+	}
+	if err := json.Unmarshal(req.payload, &args); err != nil {
+		return newError(http.StatusBadRequest, "malformed", "%v", err)
+	}
+	der, err := base64.RawURLEncoding.DecodeString(args.CSR)
+	if err != nil {
+		return newError(http.StatusBadRequest, "badCSR", "%v", err)
+	}
+	csr, err := x509.ParseCertificateRequest(der)
+	if err == nil {
+		err = csr.CheckSignature()
+	}
+	if err != nil {
+		return newError(http.StatusBadRequest, "badCSR", "%v", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	o, err := s.order(req.id)
+	if err != nil {
+		return err
+	}
+	if status := s.orderStatus(o); status != acme.StatusReady {
+		return newError(http.StatusForbidden, "orderNotReady", "order is %s", status)
+	}
+	if strings.Join(csr.DNSNames, ",") != strings.Join(o.names, ",") {
+		return newError(http.StatusBadRequest, "badCSR", "CSR names %v do not match order %v", csr.DNSNames, o.names)
+	}
+	now := s.config.Now()
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 64))
+	if err != nil {
+		return err
+	}
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: o.names[0]},
+		DNSNames:     o.names,
+		NotBefore:    now.Add(-time.Minute),
+		NotAfter:     now.Add(s.config.Validity),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	o.cert, err = x509.CreateCertificate(rand.Reader, template, s.CACert, csr.PublicKey, s.caKey)
+	if err != nil {
+		return err
+	}
+	cert, err := x509.ParseCertificate(o.cert)
+	if err != nil {
+		return err
+	}
+	s.issued = append(s.issued, cert)
+	s.writeOrder(w, http.StatusOK, o)
+	return nil
+}
+
+func (s *Server) serveCert(w http.ResponseWriter, req *request) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	o, err := s.order(req.id)
+	if err != nil {
+		return err
+	}
+	if o.cert == nil {
+		return newError(http.StatusNotFound, "malformed", "certificate not issued")
+	}
+	w.Header().Set("Content-Type", "application/pem-certificate-chain")
+	w.WriteHeader(http.StatusOK)
+	pem.Encode(w, &pem.Block{Type: "CERTIFICATE", Bytes: o.cert})
+	pem.Encode(w, &pem.Block{Type: "CERTIFICATE", Bytes: s.CACert.Raw})
+	return nil
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	if w.Header().Get("Content-Type") == "" {
+		w.Header().Set("Content-Type", "application/json")
+	}
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
+
+func decodeSegment(segment string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+func newToken() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// jwkThumbprint returns the RFC 7638 thumbprint of a JSON web key.
+func jwkThumbprint(data json.RawMessage) (string, error) {
+	var jwk struct {
+		Kty string `json:"kty"`
+		Crv string `json:"crv"`
+		X   string `json:"x"`
+		Y   string `json:"y"`
+		N   string `json:"n"`
+		E   string `json:"e"`
+	}
+	if err := json.Unmarshal(data, &jwk); err != nil {
+		return "", err
+	}
+	var pub crypto.PublicKey
+	switch jwk.Kty {
+	case "EC":
+		if jwk.Crv != "P-256" {
+			return "", fmt.Errorf("unsupported curve %q", jwk.Crv)
+		}
+		x, err := decodeInt(jwk.X)
+		if err != nil {
+			return "", err
+		}
+		y, err := decodeInt(jwk.Y)
+		if err != nil {
+			return "", err
+		}
+		pub = &ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y}
+	case "RSA":
+		n, err := decodeInt(jwk.N)
+		if err != nil {
+			return "", err
+		}
+		e, err := decodeInt(jwk.E)
+		if err != nil {
+			return "", err
+		}
+		pub = &rsa.PublicKey{N: n, E: int(e.Int64())}
+	default:
+		return "", fmt.Errorf("unsupported key type %q", jwk.Kty)
+	}
+	return acme.JWKThumbprint(pub)
+}
+
+func decodeInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
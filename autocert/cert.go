@@ -0,0 +1,165 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package autocert
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"time"
+
+	"github.com/juju/errors"
+	acmeautocert "golang.org/x/crypto/acme/autocert"
+)
+
+// Status describes the certificate held for a DNS name.
+type Status struct {
+	// DNSName is the name the certificate is for.
+	DNSName string
+
+	// Issuer is the subject of the CA that issued the certificate.
+	Issuer string
+
+	// NotBefore and NotAfter bound the certificate's validity.
+	NotBefore time.Time
+	NotAfter  time.Time
+
+	// RenewAfter is when a replacement certificate will be obtained.
+	RenewAfter time.Time
+}
+
+// CertificateStatus returns the status of the certificate for dnsName
+// held in the cache, which may have been obtained by a Manager or by
+// golang.org/x/crypto/acme/autocert. It returns an error satisfying
+// errors.IsNotFound if there is no certificate.
+func CertificateStatus(ctx context.Context, cache Cache, dnsName string) (Status, error) {
+	cert, err := getCertificate(ctx, cache, dnsName)
+	if err != nil {
+		return Status{}, errors.Trace(err)
+	}
+	return Status{
+		DNSName:    dnsName,
+		Issuer:     cert.Leaf.Issuer.String(),
+		NotBefore:  cert.Leaf.NotBefore,
+		NotAfter:   cert.Leaf.NotAfter,
+		RenewAfter: cert.Leaf.NotAfter.Add(-RenewBefore),
+	}, nil
+}
+
+// getCertificate returns the certificate for dnsName held in the cache.
+// The autocert package stores certificates with RSA keys separately
+// from those with ECDSA keys, so both are looked for.
+func getCertificate(ctx context.Context, cache Cache, dnsName string) (*tls.Certificate, error) {
+	for _, name := range []string{dnsName, dnsName + "+rsa"} {
+		data, err := cache.Get(ctx, name)
+		if err == acmeautocert.ErrCacheMiss {
+			continue
+		}
+		if err != nil {
+			return nil, errors.Annotatef(err, "getting certificate for %q", dnsName)
+		}
+		cert, err := decodeCertificate(data)
+		return cert, errors.Annotatef(err, "decoding certificate for %q", dnsName)
+	}
+	return nil, errors.NotFoundf("certificate for %q", dnsName)
+}
+
+// newCertificate returns a certificate with the given key and DER
+// encoded chain, starting with the leaf.
+func newCertificate(key crypto.Signer, der [][]byte) (*tls.Certificate, error) {
+	if len(der) == 0 {
+		return nil, errors.New("empty certificate chain")
+	}
+	leaf, err := x509.ParseCertificate(der[0])
+	if err != nil {
+		return nil, errors.Annotate(err, "parsing certificate")
+	}
+	return &tls.Certificate{
+		Certificate: der,
+		PrivateKey:  key,
+		Leaf:        leaf,
+	}, nil
+}
+
+// encodeCertificate encodes the certificate's key followed by its
+// chain as PEM, as the autocert package does.
+func encodeCertificate(cert *tls.Certificate) []byte {
+	var buf bytes.Buffer
+	// The key was generated by us, so is known to be encodable.
+	key, _ := encodeKey(cert.PrivateKey.(crypto.Signer))
+	buf.Write(key)
+	for _, der := range cert.Certificate {
+		pem.Encode(&buf, &pem.Block{Type: "CERTIFICATE", Bytes: der})
+	}
+	return buf.Bytes()
+}
+
+func decodeCertificate(data []byte) (*tls.Certificate, error) {
+	key, rest, err := decodeKey(data)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var der [][]byte
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type == "CERTIFICATE" {
+			der = append(der, block.Bytes)
+		}
+	}
+	return newCertificate(key, der)
+}
+
+func encodeKey(key crypto.Signer) ([]byte, error) {
+	var block *pem.Block
+	switch key := key.(type) {
+	case *ecdsa.PrivateKey:
+		b, err := x509.MarshalECPrivateKey(key)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		block = &pem.Block{Type: "EC PRIVATE KEY", Bytes: b}
+	case *rsa.PrivateKey:
+		block = &pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}
+	default:
+		return nil, errors.NotSupportedf("key type %T", key)
+	}
+	return pem.EncodeToMemory(block), nil
+}
+
+// decodeKey decodes the PEM encoded private key at the start of data,
+// returning the remaining data.
+func decodeKey(data []byte) (crypto.Signer, []byte, error) {
+	block, rest := pem.Decode(data)
+	if block == nil {
+		return nil, nil, errors.New("no PEM data found")
+	}
+	switch block.Type {
+	case "EC PRIVATE KEY":
+		key, err := x509.ParseECPrivateKey(block.Bytes)
+		return key, rest, errors.Trace(err)
+	case "RSA PRIVATE KEY":
+		key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+		return key, rest, errors.Trace(err)
+	case "PRIVATE KEY":
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+		signer, ok := key.(crypto.Signer)
+		if !ok {
+			return nil, nil, errors.NotSupportedf("key type %T", key)
+		}
+		return signer, rest, nil
+	}
+	return nil, nil, errors.Errorf("unexpected PEM block %q", block.Type)
+}
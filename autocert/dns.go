@@ -0,0 +1,176 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package autocert
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+)
+
+// DNSProvider publishes the TXT records used to answer dns-01
+// challenges.
+type DNSProvider interface {
+	// Present creates a TXT record with the given fully qualified
+	// name and value. It should return only once the record can be
+	// seen by the ACME server.
+	Present(ctx context.Context, fqdn, value string) error
+
+	// CleanUp removes a TXT record created by Present.
+	CleanUp(ctx context.Context, fqdn, value string) error
+}
+
+// NewDNSProviderFunc creates a DNSProvider from the controller agent's
+// data directory and the provider's configuration attributes.
+type NewDNSProviderFunc func(dataDir string, attrs map[string]string) (DNSProvider, error)
+
+var (
+	dnsProvidersMu sync.Mutex
+	dnsProviders   = map[string]NewDNSProviderFunc{
+		"exec": newExecProvider,
+	}
+)
+
+// RegisterDNSProvider makes a DNS provider available under the given
+// name, which may be used as the value of the autocert-dns-provider
+// controller setting. It panics if a provider is already registered
+// with the name. The returned function unregisters the provider.
+func RegisterDNSProvider(name string, newProvider NewDNSProviderFunc) (unregister func()) {
+	dnsProvidersMu.Lock()
+	defer dnsProvidersMu.Unlock()
+	if _, ok := dnsProviders[name]; ok {
+		panic(fmt.Errorf("juju: duplicate DNS provider name %q", name))
+	}
+	dnsProviders[name] = newProvider
+	return func() {
+		dnsProvidersMu.Lock()
+		defer dnsProvidersMu.Unlock()
+		delete(dnsProviders, name)
+	}
+}
+
+// RegisteredDNSProviders returns the names of the registered DNS
+// providers in sorted order.
+func RegisteredDNSProviders() []string {
+	dnsProvidersMu.Lock()
+	defer dnsProvidersMu.Unlock()
+	names := make([]string, 0, len(dnsProviders))
+	for name := range dnsProviders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NewDNSProvider returns the registered DNS provider with the given
+// name, configured with the controller agent's data directory and the
+// given attributes.
+func NewDNSProvider(name, dataDir string, attrs map[string]string) (DNSProvider, error) {
+	dnsProvidersMu.Lock()
+	newProvider, ok := dnsProviders[name]
+	dnsProvidersMu.Unlock()
+	if !ok {
+		return nil, errors.NotFoundf("DNS provider %q", name)
+	}
+	p, err := newProvider(dataDir, attrs)
+	if err != nil {
+		return nil, errors.Annotatef(err, "configuring DNS provider %q", name)
+	}
+	return p, nil
+}
+
+// ParseDNSProviderConfig parses a list of "key=value" entries, as held
+// in the autocert-dns-provider-config controller setting.
+func ParseDNSProviderConfig(entries []string) (map[string]string, error) {
+	attrs := make(map[string]string)
+	for _, entry := range entries {
+		parts := strings.SplitN(entry, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, errors.Errorf("expected key=value, got %q", entry)
+		}
+		attrs[parts[0]] = parts[1]
+	}
+	return attrs, nil
+}
+
+// defaultExecTimeout is how long the command run by the exec provider
+// may take, unless configured otherwise.
+const defaultExecTimeout = 5 * time.Minute
+
+// ExecHookPath returns the path of the command run by the exec DNS
+// provider, given the controller agent's data directory.
+func ExecHookPath(dataDir string) string {
+	return filepath.Join(dataDir, "autocert", "dns-hook")
+}
+
+// execProvider is a DNSProvider that runs a command to manage records,
+// so that any DNS service can be used without Juju knowing about it.
+// The command is run with the arguments "present" or "cleanup", then
+// the fully qualified record name and the record value.
+//
+// The command runs as the controller agent's user, normally root, on
+// every controller. So that controller config cannot be used to run
+// arbitrary commands, it is always the one at ExecHookPath, which must
+// be installed by the operator and must not be writable by group or
+// others.
+type execProvider struct {
+	command string
+	timeout time.Duration
+}
+
+func newExecProvider(dataDir string, attrs map[string]string) (DNSProvider, error) {
+	p := &execProvider{
+		command: ExecHookPath(dataDir),
+		timeout: defaultExecTimeout,
+	}
+	if _, ok := attrs["command"]; ok {
+		return nil, errors.Errorf("command cannot be configured, the exec provider runs %s", p.command)
+	}
+	if v := attrs["timeout"]; v != "" {
+		timeout, err := time.ParseDuration(v)
+		if err != nil {
+			return nil, errors.NotValidf("timeout %q", v)
+		}
+		p.timeout = timeout
+	}
+	return p, nil
+}
+
+// Present is part of the DNSProvider interface.
+func (p *execProvider) Present(ctx context.Context, fqdn, value string) error {
+	return errors.Trace(p.run(ctx, "present", fqdn, value))
+}
+
+// CleanUp is part of the DNSProvider interface.
+func (p *execProvider) CleanUp(ctx context.Context, fqdn, value string) error {
+	return errors.Trace(p.run(ctx, "cleanup", fqdn, value))
+}
+
+func (p *execProvider) run(ctx context.Context, args ...string) error {
+	info, err := os.Stat(p.command)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if info.Mode()&0022 != 0 {
+		return errors.Errorf("%s must not be writable by group or others", p.command)
+	}
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, p.command, args...).CombinedOutput()
+	if err != nil {
+		if len(out) > 0 {
+			return errors.Errorf("running %s %s: %v (%s)", p.command, args[0], err, strings.TrimSpace(string(out)))
+		}
+		return errors.Annotatef(err, "running %s %s", p.command, args[0])
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package autocert_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/autocert"
+)
+
+type dnsSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&dnsSuite{})
+
+func (s *dnsSuite) TestRegisteredDNSProviders(c *gc.C) {
+	c.Assert(autocert.RegisteredDNSProviders(), jc.DeepEquals, []string{"exec"})
+
+	unregister := autocert.RegisterDNSProvider("test", func(dataDir string, attrs map[string]string) (autocert.DNSProvider, error) {
+		return nil, errors.New("not configured")
+	})
+	c.Assert(autocert.RegisteredDNSProviders(), jc.DeepEquals, []string{"exec", "test"})
+	_, err := autocert.NewDNSProvider("test", c.MkDir(), nil)
+	c.Assert(err, gc.ErrorMatches, `configuring DNS provider "test": not configured`)
+	c.Assert(func() {
+		autocert.RegisterDNSProvider("test", nil)
+	}, gc.PanicMatches, `juju: duplicate DNS provider name "test"`)
+
+	unregister()
+	c.Assert(autocert.RegisteredDNSProviders(), jc.DeepEquals, []string{"exec"})
+}
+
+func (s *dnsSuite) TestUnknownDNSProvider(c *gc.C) {
+	_, err := autocert.NewDNSProvider("bogus", c.MkDir(), nil)
+	c.Assert(err, gc.ErrorMatches, `DNS provider "bogus" not found`)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *dnsSuite) TestParseDNSProviderConfig(c *gc.C) {
+	attrs, err := autocert.ParseDNSProviderConfig([]string{"command=/bin/dns-hook", "timeout=1m=x"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(attrs, jc.DeepEquals, map[string]string{
+		"command": "/bin/dns-hook",
+		"timeout": "1m=x",
+	})
+	_, err = autocert.ParseDNSProviderConfig([]string{"command"})
+	c.Assert(err, gc.ErrorMatches, `expected key=value, got "command"`)
+}
+
+func (s *dnsSuite) TestExecProviderConfig(c *gc.C) {
+	_, err := autocert.NewDNSProvider("exec", "/var/lib/juju", map[string]string{
+		"command": "/bin/true",
+	})
+	c.Assert(err, gc.ErrorMatches, `configuring DNS provider "exec": command cannot be configured, the exec provider runs /var/lib/juju/autocert/dns-hook`)
+	_, err = autocert.NewDNSProvider("exec", "/var/lib/juju", map[string]string{
+		"timeout": "soon",
+	})
+	c.Assert(err, gc.ErrorMatches, `configuring DNS provider "exec": timeout "soon" not valid`)
+}
+
+// writeHook writes the exec provider's hook under a new data
+// directory, and returns the data directory.
+func writeHook(c *gc.C, script string, perm os.FileMode) string {
+	dataDir := c.MkDir()
+	hook := autocert.ExecHookPath(dataDir)
+	err := os.MkdirAll(filepath.Dir(hook), 0755)
+	c.Assert(err, jc.ErrorIsNil)
+	err = ioutil.WriteFile(hook, []byte(script), perm)
+	c.Assert(err, jc.ErrorIsNil)
+	err = os.Chmod(hook, perm)
+	c.Assert(err, jc.ErrorIsNil)
+	return dataDir
+}
+
+func (s *dnsSuite) TestExecProvider(c *gc.C) {
+	if runtime.GOOS == "windows" {
+		c.Skip("exec provider test uses a shell script")
+	}
+	log := filepath.Join(c.MkDir(), "log")
+	dataDir := writeHook(c, "#!/bin/sh\necho \"$@\" >> "+log+"\n", 0755)
+
+	p, err := autocert.NewDNSProvider("exec", dataDir, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = p.Present(context.Background(), "_acme-challenge.example.com.", "value")
+	c.Assert(err, jc.ErrorIsNil)
+	err = p.CleanUp(context.Background(), "_acme-challenge.example.com.", "value")
+	c.Assert(err, jc.ErrorIsNil)
+
+	data, err := ioutil.ReadFile(log)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), gc.Equals, ""+
+		"present _acme-challenge.example.com. value\n"+
+		"cleanup _acme-challenge.example.com. value\n")
+}
+
+func (s *dnsSuite) TestExecProviderFailure(c *gc.C) {
+	if runtime.GOOS == "windows" {
+		c.Skip("exec provider test uses a shell script")
+	}
+	dataDir := writeHook(c, "#!/bin/sh\necho no zone >&2\nexit 1\n", 0755)
+
+	p, err := autocert.NewDNSProvider("exec", dataDir, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = p.Present(context.Background(), "_acme-challenge.example.com.", "value")
+	c.Assert(err, gc.ErrorMatches, `running .*dns-hook present: exit status 1 \(no zone\)`)
+}
+
+func (s *dnsSuite) TestExecProviderWritableHook(c *gc.C) {
+	if runtime.GOOS == "windows" {
+		c.Skip("exec provider test uses a shell script")
+	}
+	dataDir := writeHook(c, "#!/bin/sh\n", 0775)
+
+	p, err := autocert.NewDNSProvider("exec", dataDir, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = p.Present(context.Background(), "_acme-challenge.example.com.", "value")
+	c.Assert(err, gc.ErrorMatches, `.*dns-hook must not be writable by group or others`)
+}
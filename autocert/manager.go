@@ -0,0 +1,463 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package autocert obtains and renews TLS certificates for the
+// controller's public DNS name from an ACME server, such as Let's
+// Encrypt or an internal CA.
+//
+// Unlike golang.org/x/crypto/acme/autocert, it can register accounts
+// with an external account binding, as required by many internal ACME
+// servers, and can answer dns-01 challenges using a pluggable DNS
+// provider, for controllers that cannot be reached from the ACME
+// server. Certificates are stored in the same form as that package
+// stores them, so the two can share a cache.
+package autocert
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/juju/clock"
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"golang.org/x/crypto/acme"
+	acmeautocert "golang.org/x/crypto/acme/autocert"
+)
+
+var logger = loggo.GetLogger("juju.autocert")
+
+const (
+	// ChallengeTLSALPN01 is the tls-alpn-01 challenge type, answered
+	// by presenting a special certificate when the ACME server
+	// connects to the controller's API port.
+	ChallengeTLSALPN01 = "tls-alpn-01"
+
+	// ChallengeDNS01 is the dns-01 challenge type, answered by
+	// publishing a TXT record with a DNSProvider.
+	ChallengeDNS01 = "dns-01"
+)
+
+// RenewBefore is how long before a certificate expires that a
+// replacement is obtained. It is the same as the default used by
+// golang.org/x/crypto/acme/autocert.
+const RenewBefore = 30 * 24 * time.Hour
+
+const (
+	// retryDelay is how long to wait after failing to obtain a
+	// certificate before trying again.
+	retryDelay = time.Minute
+
+	// issueTimeout is how long obtaining a certificate may take.
+	issueTimeout = 5 * time.Minute
+
+	// accountKeyName is the cache entry holding the ACME account key.
+	accountKeyName = "juju_acme_account+key"
+)
+
+// Cache is used to store the ACME account key and certificates.
+// The cache returned by state.State.AutocertCache satisfies it.
+type Cache = acmeautocert.Cache
+
+// Config holds the configuration for a Manager.
+type Config struct {
+	// DNSName is the name to obtain a certificate for.
+	DNSName string
+
+	// DirectoryURL is the URL of the ACME server's directory. It
+	// defaults to Let's Encrypt.
+	DirectoryURL string
+
+	// ExternalAccountBinding, if set, binds the ACME account to an
+	// account already known to the ACME server.
+	ExternalAccountBinding *acme.ExternalAccountBinding
+
+	// ChallengeType is the type of challenge used to prove control of
+	// DNSName, either ChallengeTLSALPN01 or ChallengeDNS01.
+	ChallengeType string
+
+	// DNSProvider publishes the records for dns-01 challenges.
+	DNSProvider DNSProvider
+
+	// Cache stores the account key and certificate.
+	Cache Cache
+
+	// Clock is used to decide when to renew certificates.
+	Clock clock.Clock
+
+	// HTTPClient, if set, is used to talk to the ACME server.
+	HTTPClient *http.Client
+
+	// ReportResult, if set, is called after each attempt to obtain a
+	// certificate with the error encountered, or nil on success.
+	ReportResult func(error)
+}
+
+// Validate checks that the configuration is usable.
+func (config Config) Validate() error {
+	if config.DNSName == "" {
+		return errors.NotValidf("empty DNSName")
+	}
+	if config.Cache == nil {
+		return errors.NotValidf("nil Cache")
+	}
+	if config.Clock == nil {
+		return errors.NotValidf("nil Clock")
+	}
+	switch config.ChallengeType {
+	case ChallengeTLSALPN01:
+	case ChallengeDNS01:
+		if config.DNSProvider == nil {
+			return errors.NotValidf("nil DNSProvider with %s challenges", ChallengeDNS01)
+		}
+	default:
+		return errors.NotValidf("challenge type %q", config.ChallengeType)
+	}
+	return nil
+}
+
+// Manager obtains a certificate for a DNS name when it is first
+// needed, and renews it when it is close to expiring.
+type Manager struct {
+	config Config
+
+	// issueMu is held while a certificate is being obtained.
+	issueMu sync.Mutex
+	client  *acme.Client
+
+	mu             sync.Mutex
+	cert           *tls.Certificate
+	renewing       bool
+	lastAttempt    time.Time
+	lastErr        error
+	challengeCerts map[string]*tls.Certificate
+}
+
+// NewManager returns a Manager with the given configuration.
+func NewManager(config Config) (*Manager, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &Manager{
+		config:         config,
+		challengeCerts: make(map[string]*tls.Certificate),
+	}, nil
+}
+
+// GetCertificate returns the certificate for the server name requested
+// in the TLS handshake, obtaining it if necessary. It is suitable for
+// use as tls.Config.GetCertificate.
+func (m *Manager) GetCertificate(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	name := strings.TrimSuffix(strings.ToLower(hello.ServerName), ".")
+	if name == "" {
+		return nil, errors.New("missing server name")
+	}
+	if name != m.config.DNSName {
+		return nil, errors.Errorf("host %q not configured for autocert", name)
+	}
+	if isChallengeHello(hello) {
+		m.mu.Lock()
+		cert := m.challengeCerts[name]
+		m.mu.Unlock()
+		if cert == nil {
+			return nil, errors.Errorf("no challenge certificate for %q", name)
+		}
+		return cert, nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), issueTimeout)
+	defer cancel()
+	return m.certificate(ctx)
+}
+
+// isChallengeHello reports whether the handshake is the ACME server
+// verifying a tls-alpn-01 challenge.
+func isChallengeHello(hello *tls.ClientHelloInfo) bool {
+	return len(hello.SupportedProtos) == 1 && hello.SupportedProtos[0] == acme.ALPNProto
+}
+
+func (m *Manager) certificate(ctx context.Context) (*tls.Certificate, error) {
+	m.mu.Lock()
+	if cert := m.cert; cert != nil {
+		m.maybeRenewLocked()
+		m.mu.Unlock()
+		return cert, nil
+	}
+	m.mu.Unlock()
+
+	m.issueMu.Lock()
+	defer m.issueMu.Unlock()
+
+	m.mu.Lock()
+	cert := m.cert
+	canRetry := m.canRetryLocked()
+	lastErr := m.lastErr
+	m.mu.Unlock()
+	if cert != nil {
+		// Obtained while we waited.
+		return cert, nil
+	}
+
+	cert, err := m.cachedCertificate(ctx)
+	if err != nil && !errors.IsNotFound(err) {
+		logger.Warningf("ignoring cached certificate for %q: %v", m.config.DNSName, err)
+	}
+	if cert == nil {
+		if !canRetry {
+			return nil, lastErr
+		}
+		if cert, err = m.obtain(ctx); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.cert = cert
+	m.maybeRenewLocked()
+	return cert, nil
+}
+
+// canRetryLocked reports whether enough time has passed since a failed
+// attempt to obtain a certificate to try again.
+func (m *Manager) canRetryLocked() bool {
+	return m.lastErr == nil || !m.config.Clock.Now().Before(m.lastAttempt.Add(retryDelay))
+}
+
+// maybeRenewLocked starts renewing the current certificate in the
+// background if it is due for renewal.
+func (m *Manager) maybeRenewLocked() {
+	if m.renewing || !m.canRetryLocked() {
+		return
+	}
+	if m.config.Clock.Now().Before(m.cert.Leaf.NotAfter.Add(-RenewBefore)) {
+		return
+	}
+	m.renewing = true
+	go m.renew()
+}
+
+func (m *Manager) renew() {
+	ctx, cancel := context.WithTimeout(context.Background(), issueTimeout)
+	defer cancel()
+
+	m.issueMu.Lock()
+	cert, err := m.obtain(ctx)
+	m.issueMu.Unlock()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.renewing = false
+	if err != nil {
+		logger.Errorf("cannot renew certificate for %q: %v", m.config.DNSName, err)
+		return
+	}
+	m.cert = cert
+}
+
+// obtain gets a new certificate from the ACME server, recording the
+// outcome. It must be called with issueMu held.
+func (m *Manager) obtain(ctx context.Context) (*tls.Certificate, error) {
+	logger.Infof("obtaining certificate for %q", m.config.DNSName)
+	cert, err := m.issue(ctx)
+	m.mu.Lock()
+	m.lastAttempt = m.config.Clock.Now()
+	m.lastErr = err
+	m.mu.Unlock()
+	if m.config.ReportResult != nil {
+		m.config.ReportResult(err)
+	}
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	logger.Infof("obtained certificate for %q, valid until %s", m.config.DNSName, cert.Leaf.NotAfter)
+	return cert, nil
+}
+
+func (m *Manager) issue(ctx context.Context) (*tls.Certificate, error) {
+	client, err := m.acmeClient(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	name := m.config.DNSName
+	order, err := client.AuthorizeOrder(ctx, acme.DomainIDs(name))
+	if err != nil {
+		return nil, errors.Annotate(err, "creating order")
+	}
+	for _, u := range order.AuthzURLs {
+		z, err := client.GetAuthorization(ctx, u)
+		if err != nil {
+			return nil, errors.Annotate(err, "getting authorization")
+		}
+		if z.Status != acme.StatusPending {
+			continue
+		}
+		if err := m.authorize(ctx, client, z); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	if order, err = client.WaitOrder(ctx, order.URI); err != nil {
+		return nil, errors.Annotate(err, "waiting for order")
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	csr, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject:  pkix.Name{CommonName: name},
+		DNSNames: []string{name},
+	}, key)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	der, _, err := client.CreateOrderCert(ctx, order.FinalizeURL, csr, true)
+	if err != nil {
+		return nil, errors.Annotate(err, "finalizing order")
+	}
+	cert, err := newCertificate(key, der)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := cert.Leaf.VerifyHostname(name); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := m.config.Cache.Put(ctx, name, encodeCertificate(cert)); err != nil {
+		// The certificate is still usable, it will just have to be
+		// obtained again when the controller restarts.
+		logger.Warningf("cannot cache certificate for %q: %v", name, err)
+	}
+	return cert, nil
+}
+
+// authorize proves control of the authorization's identifier using
+// the configured challenge type.
+func (m *Manager) authorize(ctx context.Context, client *acme.Client, z *acme.Authorization) error {
+	var chal *acme.Challenge
+	for _, c := range z.Challenges {
+		if c.Type == m.config.ChallengeType {
+			chal = c
+			break
+		}
+	}
+	if chal == nil {
+		return errors.Errorf("ACME server does not offer %s challenges for %q", m.config.ChallengeType, z.Identifier.Value)
+	}
+	cleanup, err := m.fulfil(ctx, client, chal, z.Identifier.Value)
+	if err != nil {
+		return errors.Annotatef(err, "preparing %s challenge", chal.Type)
+	}
+	defer cleanup()
+	if _, err := client.Accept(ctx, chal); err != nil {
+		return errors.Annotatef(err, "accepting %s challenge", chal.Type)
+	}
+	_, err = client.WaitAuthorization(ctx, z.URI)
+	return errors.Annotatef(err, "authorizing %q", z.Identifier.Value)
+}
+
+// fulfil prepares the response to a challenge, returning a function
+// that removes it again.
+func (m *Manager) fulfil(ctx context.Context, client *acme.Client, chal *acme.Challenge, domain string) (func(), error) {
+	switch chal.Type {
+	case ChallengeTLSALPN01:
+		cert, err := client.TLSALPN01ChallengeCert(chal.Token, domain)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		m.mu.Lock()
+		m.challengeCerts[domain] = &cert
+		m.mu.Unlock()
+		return func() {
+			m.mu.Lock()
+			delete(m.challengeCerts, domain)
+			m.mu.Unlock()
+		}, nil
+	case ChallengeDNS01:
+		value, err := client.DNS01ChallengeRecord(chal.Token)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		fqdn := "_acme-challenge." + domain + "."
+		if err := m.config.DNSProvider.Present(ctx, fqdn, value); err != nil {
+			return nil, errors.Trace(err)
+		}
+		return func() {
+			if err := m.config.DNSProvider.CleanUp(context.Background(), fqdn, value); err != nil {
+				logger.Warningf("cannot remove DNS record %q: %v", fqdn, err)
+			}
+		}, nil
+	}
+	return nil, errors.NotSupportedf("challenge type %q", chal.Type)
+}
+
+// acmeClient returns a client for the ACME server, registering an
+// account for it the first time.
+func (m *Manager) acmeClient(ctx context.Context) (*acme.Client, error) {
+	if m.client != nil {
+		return m.client, nil
+	}
+	key, err := m.accountKey(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	client := &acme.Client{
+		Key:          key,
+		DirectoryURL: m.config.DirectoryURL,
+		HTTPClient:   m.config.HTTPClient,
+		UserAgent:    "juju",
+	}
+	account := &acme.Account{
+		ExternalAccountBinding: m.config.ExternalAccountBinding,
+	}
+	if _, err := client.Register(ctx, account, acme.AcceptTOS); err != nil && err != acme.ErrAccountAlreadyExists {
+		return nil, errors.Annotate(err, "registering ACME account")
+	}
+	m.client = client
+	return client, nil
+}
+
+// accountKey returns the key identifying the ACME account, creating
+// it if there isn't one in the cache.
+func (m *Manager) accountKey(ctx context.Context) (crypto.Signer, error) {
+	data, err := m.config.Cache.Get(ctx, accountKeyName)
+	if err == nil {
+		key, _, err := decodeKey(data)
+		return key, errors.Annotate(err, "decoding ACME account key")
+	}
+	if err != acmeautocert.ErrCacheMiss {
+		return nil, errors.Annotate(err, "getting ACME account key")
+	}
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	data, err = encodeKey(key)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := m.config.Cache.Put(ctx, accountKeyName, data); err != nil {
+		return nil, errors.Annotate(err, "storing ACME account key")
+	}
+	return key, nil
+}
+
+// cachedCertificate returns the certificate held in the cache, if it
+// has not yet expired.
+func (m *Manager) cachedCertificate(ctx context.Context) (*tls.Certificate, error) {
+	cert, err := getCertificate(ctx, m.config.Cache, m.config.DNSName)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if !m.config.Clock.Now().Before(cert.Leaf.NotAfter) {
+		return nil, errors.NotFoundf("unexpired certificate")
+	}
+	return cert, nil
+}
@@ -0,0 +1,377 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package autocert_test
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/juju/clock/testclock"
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"golang.org/x/crypto/acme"
+	acmeautocert "golang.org/x/crypto/acme/autocert"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/autocert"
+	"github.com/juju/juju/autocert/autocerttesting"
+	coretesting "github.com/juju/juju/testing"
+)
+
+const dnsName = "controller.example.com"
+
+type managerSuite struct {
+	testing.IsolationSuite
+
+	cache *memCache
+	dns   *fakeDNS
+	clock *testclock.Clock
+}
+
+var _ = gc.Suite(&managerSuite{})
+
+func (s *managerSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.cache = &memCache{data: make(map[string][]byte)}
+	s.dns = &fakeDNS{records: make(map[string]string)}
+	s.clock = testclock.NewClock(time.Now())
+}
+
+func (s *managerSuite) newServer(c *gc.C, config autocerttesting.ServerConfig) *autocerttesting.Server {
+	if config.LookupTXT == nil {
+		config.LookupTXT = s.dns.lookup
+	}
+	server := autocerttesting.NewServer(config)
+	s.AddCleanup(func(*gc.C) { server.Close() })
+	return server
+}
+
+func (s *managerSuite) newManager(c *gc.C, server *autocerttesting.Server, f func(*autocert.Config)) *autocert.Manager {
+	config := autocert.Config{
+		DNSName:       dnsName,
+		DirectoryURL:  server.DirectoryURL(),
+		ChallengeType: autocert.ChallengeDNS01,
+		DNSProvider:   s.dns,
+		Cache:         s.cache,
+		Clock:         s.clock,
+		HTTPClient:    server.Client(),
+	}
+	if f != nil {
+		f(&config)
+	}
+	m, err := autocert.NewManager(config)
+	c.Assert(err, jc.ErrorIsNil)
+	return m
+}
+
+func (s *managerSuite) getCertificate(m *autocert.Manager, serverName string) (*tls.Certificate, error) {
+	return m.GetCertificate(&tls.ClientHelloInfo{ServerName: serverName})
+}
+
+func (s *managerSuite) checkIssuedBy(c *gc.C, cert *tls.Certificate, server *autocerttesting.Server) {
+	pool := x509.NewCertPool()
+	pool.AddCert(server.CACert)
+	_, err := cert.Leaf.Verify(x509.VerifyOptions{
+		DNSName:     dnsName,
+		Roots:       pool,
+		CurrentTime: s.clock.Now(),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *managerSuite) TestValidate(c *gc.C) {
+	for i, test := range []struct {
+		f      func(*autocert.Config)
+		expect string
+	}{{
+		f:      func(cfg *autocert.Config) { cfg.DNSName = "" },
+		expect: "empty DNSName not valid",
+	}, {
+		f:      func(cfg *autocert.Config) { cfg.Cache = nil },
+		expect: "nil Cache not valid",
+	}, {
+		f:      func(cfg *autocert.Config) { cfg.Clock = nil },
+		expect: "nil Clock not valid",
+	}, {
+		f:      func(cfg *autocert.Config) { cfg.ChallengeType = "http-01" },
+		expect: `challenge type "http-01" not valid`,
+	}, {
+		f:      func(cfg *autocert.Config) { cfg.DNSProvider = nil },
+		expect: "nil DNSProvider with dns-01 challenges not valid",
+	}} {
+		c.Logf("test %d: %s", i, test.expect)
+		config := autocert.Config{
+			DNSName:       dnsName,
+			ChallengeType: autocert.ChallengeDNS01,
+			DNSProvider:   s.dns,
+			Cache:         s.cache,
+			Clock:         s.clock,
+		}
+		test.f(&config)
+		_, err := autocert.NewManager(config)
+		c.Check(err, gc.ErrorMatches, test.expect)
+		c.Check(err, jc.Satisfies, errors.IsNotValid)
+	}
+}
+
+func (s *managerSuite) TestDNS01WithExternalAccountBinding(c *gc.C) {
+	server := s.newServer(c, autocerttesting.ServerConfig{
+		EABKeyID: "kid-1",
+		EABKey:   []byte("secret"),
+	})
+	var results []error
+	m := s.newManager(c, server, func(cfg *autocert.Config) {
+		cfg.ExternalAccountBinding = &acme.ExternalAccountBinding{
+			KID: "kid-1",
+			Key: []byte("secret"),
+		}
+		cfg.ReportResult = func(err error) { results = append(results, err) }
+	})
+
+	cert, err := s.getCertificate(m, dnsName)
+	c.Assert(err, jc.ErrorIsNil)
+	s.checkIssuedBy(c, cert, server)
+	c.Assert(server.Issued(), gc.HasLen, 1)
+	c.Assert(results, jc.DeepEquals, []error{nil})
+
+	// The challenge record was published, then removed again.
+	c.Assert(s.dns.presented, jc.DeepEquals, []string{"_acme-challenge." + dnsName + "."})
+	c.Assert(s.dns.records, gc.HasLen, 0)
+
+	// The certificate is remembered.
+	again, err := s.getCertificate(m, dnsName+".")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(again, gc.Equals, cert)
+	c.Assert(server.Issued(), gc.HasLen, 1)
+}
+
+func (s *managerSuite) TestExternalAccountBindingRequired(c *gc.C) {
+	server := s.newServer(c, autocerttesting.ServerConfig{
+		EABKeyID: "kid-1",
+		EABKey:   []byte("secret"),
+	})
+	m := s.newManager(c, server, nil)
+	_, err := s.getCertificate(m, dnsName)
+	c.Assert(err, gc.ErrorMatches, "registering ACME account: .*external account binding required")
+}
+
+func (s *managerSuite) TestExternalAccountBindingWrongKey(c *gc.C) {
+	server := s.newServer(c, autocerttesting.ServerConfig{
+		EABKeyID: "kid-1",
+		EABKey:   []byte("secret"),
+	})
+	m := s.newManager(c, server, func(cfg *autocert.Config) {
+		cfg.ExternalAccountBinding = &acme.ExternalAccountBinding{
+			KID: "kid-1",
+			Key: []byte("wrong"),
+		}
+	})
+	_, err := s.getCertificate(m, dnsName)
+	c.Assert(err, gc.ErrorMatches, "registering ACME account: .*invalid external account binding signature")
+}
+
+func (s *managerSuite) TestTLSALPN01(c *gc.C) {
+	var m *autocert.Manager
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{
+		GetCertificate: func(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+			return m.GetCertificate(hello)
+		},
+		NextProtos: []string{acme.ALPNProto},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	defer listener.Close()
+	go serveHandshakes(listener)
+
+	server := s.newServer(c, autocerttesting.ServerConfig{
+		TLSALPNAddr: listener.Addr().String(),
+	})
+	m = s.newManager(c, server, func(cfg *autocert.Config) {
+		cfg.ChallengeType = autocert.ChallengeTLSALPN01
+		cfg.DNSProvider = nil
+	})
+	cert, err := s.getCertificate(m, dnsName)
+	c.Assert(err, jc.ErrorIsNil)
+	s.checkIssuedBy(c, cert, server)
+	c.Assert(s.dns.presented, gc.HasLen, 0)
+}
+
+func serveHandshakes(listener net.Listener) {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		go func() {
+			defer conn.Close()
+			conn.(*tls.Conn).Handshake()
+		}()
+	}
+}
+
+func (s *managerSuite) TestHostNotConfigured(c *gc.C) {
+	server := s.newServer(c, autocerttesting.ServerConfig{})
+	m := s.newManager(c, server, nil)
+	_, err := s.getCertificate(m, "other.example.com")
+	c.Assert(err, gc.ErrorMatches, `host "other.example.com" not configured for autocert`)
+	_, err = s.getCertificate(m, "")
+	c.Assert(err, gc.ErrorMatches, "missing server name")
+	c.Assert(server.Issued(), gc.HasLen, 0)
+}
+
+func (s *managerSuite) TestCachedCertificate(c *gc.C) {
+	server := s.newServer(c, autocerttesting.ServerConfig{})
+	cert, err := s.getCertificate(s.newManager(c, server, nil), dnsName)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// A new manager, for instance after a restart, uses the
+	// certificate in the cache.
+	again, err := s.getCertificate(s.newManager(c, server, nil), dnsName)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(again.Certificate, jc.DeepEquals, cert.Certificate)
+	c.Assert(server.Issued(), gc.HasLen, 1)
+}
+
+func (s *managerSuite) TestRenewal(c *gc.C) {
+	server := s.newServer(c, autocerttesting.ServerConfig{})
+	m := s.newManager(c, server, nil)
+	cert, err := s.getCertificate(m, dnsName)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Not due for renewal yet.
+	s.clock.Advance(59 * 24 * time.Hour)
+	again, err := s.getCertificate(m, dnsName)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(again, gc.Equals, cert)
+	c.Assert(server.Issued(), gc.HasLen, 1)
+
+	// Within RenewBefore of expiry, the current certificate is
+	// used while a new one is obtained.
+	s.clock.Advance(2 * 24 * time.Hour)
+	again, err = s.getCertificate(m, dnsName)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(again, gc.Equals, cert)
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		again, err = s.getCertificate(m, dnsName)
+		c.Assert(err, jc.ErrorIsNil)
+		if again != cert {
+			break
+		}
+	}
+	c.Assert(again, gc.Not(gc.Equals), cert)
+	c.Assert(server.Issued(), gc.HasLen, 2)
+}
+
+func (s *managerSuite) TestRetryDelay(c *gc.C) {
+	server := s.newServer(c, autocerttesting.ServerConfig{})
+	s.dns.err = errors.New("DNS is down")
+	var results []error
+	m := s.newManager(c, server, func(cfg *autocert.Config) {
+		cfg.ReportResult = func(err error) { results = append(results, err) }
+	})
+
+	_, err := s.getCertificate(m, dnsName)
+	c.Assert(err, gc.ErrorMatches, "preparing dns-01 challenge: DNS is down")
+	c.Assert(results, gc.HasLen, 1)
+
+	// Failures aren't retried straight away.
+	_, err = s.getCertificate(m, dnsName)
+	c.Assert(err, gc.ErrorMatches, "preparing dns-01 challenge: DNS is down")
+	c.Assert(results, gc.HasLen, 1)
+
+	s.dns.err = nil
+	s.clock.Advance(time.Minute)
+	_, err = s.getCertificate(m, dnsName)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 2)
+	c.Assert(results[1], jc.ErrorIsNil)
+}
+
+func (s *managerSuite) TestCertificateStatus(c *gc.C) {
+	_, err := autocert.CertificateStatus(context.Background(), s.cache, dnsName)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	server := s.newServer(c, autocerttesting.ServerConfig{})
+	cert, err := s.getCertificate(s.newManager(c, server, nil), dnsName)
+	c.Assert(err, jc.ErrorIsNil)
+
+	status, err := autocert.CertificateStatus(context.Background(), s.cache, dnsName)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status, jc.DeepEquals, autocert.Status{
+		DNSName:    dnsName,
+		Issuer:     "CN=autocerttesting CA",
+		NotBefore:  cert.Leaf.NotBefore,
+		NotAfter:   cert.Leaf.NotAfter,
+		RenewAfter: cert.Leaf.NotAfter.Add(-autocert.RenewBefore),
+	})
+}
+
+// memCache is an in-memory autocert.Cache.
+type memCache struct {
+	mu   sync.Mutex
+	data map[string][]byte
+}
+
+func (c *memCache) Get(_ context.Context, name string) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	data, ok := c.data[name]
+	if !ok {
+		return nil, acmeautocert.ErrCacheMiss
+	}
+	return data, nil
+}
+
+func (c *memCache) Put(_ context.Context, name string, data []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.data[name] = data
+	return nil
+}
+
+func (c *memCache) Delete(_ context.Context, name string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.data, name)
+	return nil
+}
+
+// fakeDNS is a DNSProvider holding records in memory.
+type fakeDNS struct {
+	mu        sync.Mutex
+	records   map[string]string
+	presented []string
+	err       error
+}
+
+func (d *fakeDNS) Present(_ context.Context, fqdn, value string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.err != nil {
+		return d.err
+	}
+	d.records[fqdn] = value
+	d.presented = append(d.presented, fqdn)
+	return nil
+}
+
+func (d *fakeDNS) CleanUp(_ context.Context, fqdn, value string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.records, fqdn)
+	return nil
+}
+
+func (d *fakeDNS) lookup(fqdn string) []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if value, ok := d.records[fqdn]; ok {
+		return []string{value}
+	}
+	return nil
+}
@@ -269,6 +269,14 @@ type ClusterVersionGetter interface {
 	Version() (*version.Number, error)
 }
 
+// WorkloadAdopter provides the API to bring existing workloads,
+// not created by Juju, under the management of a Juju application.
+type WorkloadAdopter interface {
+	// AdoptWorkload labels the named workload of the given deployment type
+	// so that its pods are tracked as units of the specified application.
+	AdoptWorkload(appName string, deploymentType DeploymentType, workloadName string) error
+}
+
 // ServiceGetterSetter provides the API to get/set service.
 type ServiceGetterSetter interface {
 	// EnsureService creates or updates a service for pods with the given params.
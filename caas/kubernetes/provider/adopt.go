@@ -0,0 +1,79 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provider
+
+import (
+	"github.com/juju/errors"
+	core "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/juju/juju/caas"
+	k8sannotations "github.com/juju/juju/core/annotations"
+)
+
+// annotationAdoptedWorkload records the name of the workload which was
+// adopted by the Juju application.
+var annotationAdoptedWorkload = jujuAnnotationKey("adopted-workload")
+
+// AdoptWorkload is part of the caas.WorkloadAdopter interface.
+// The workload's metadata and pod template are labelled with the Juju
+// application labels so that its pods are tracked as units of the
+// application. Updating the pod template results in the workload's pods
+// being rolled by the cluster. The workload's selector is left unchanged.
+func (k *kubernetesClient) AdoptWorkload(appName string, deploymentType caas.DeploymentType, workloadName string) error {
+	switch deploymentType {
+	case caas.DeploymentStateless:
+		return errors.Trace(k.adoptDeployment(appName, workloadName))
+	case caas.DeploymentStateful:
+		return errors.Trace(k.adoptStatefulSet(appName, workloadName))
+	}
+	return errors.NotSupportedf("adopting workload of deployment type %q", deploymentType)
+}
+
+func (k *kubernetesClient) adoptDeployment(appName, name string) error {
+	deployment, err := k.getDeployment(name)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := k.labelAdoptedWorkload(appName, name, &deployment.ObjectMeta, &deployment.Spec.Template); err != nil {
+		return errors.Trace(err)
+	}
+	_, err = k.client().AppsV1().Deployments(k.namespace).Update(deployment)
+	if k8serrors.IsNotFound(err) {
+		return errors.NotFoundf("deployment %q", name)
+	}
+	return errors.Trace(err)
+}
+
+func (k *kubernetesClient) adoptStatefulSet(appName, name string) error {
+	statefulSet, err := k.getStatefulSet(name)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := k.labelAdoptedWorkload(appName, name, &statefulSet.ObjectMeta, &statefulSet.Spec.Template); err != nil {
+		return errors.Trace(err)
+	}
+	_, err = k.client().AppsV1().StatefulSets(k.namespace).Update(statefulSet)
+	if k8serrors.IsNotFound(err) {
+		return errors.NotFoundf("stateful set %q", name)
+	}
+	return errors.Trace(err)
+}
+
+func (k *kubernetesClient) labelAdoptedWorkload(
+	appName, workloadName string, meta *v1.ObjectMeta, template *core.PodTemplateSpec,
+) error {
+	if existing, ok := meta.Labels[labelApplication]; ok {
+		return errors.AlreadyExistsf("workload %q managed by application %q", workloadName, existing)
+	}
+	if existing, ok := meta.Labels[labelOperator]; ok {
+		return errors.NotValidf("adopting operator %q for application %q", workloadName, existing)
+	}
+	meta.Labels = AppendLabels(meta.Labels, LabelsForApp(appName))
+	meta.Annotations = k8sannotations.New(meta.Annotations).
+		Add(annotationAdoptedWorkload, workloadName).ToMap()
+	template.Labels = AppendLabels(template.Labels, LabelsForApp(appName))
+	return nil
+}
@@ -0,0 +1,131 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provider_test
+
+import (
+	"github.com/golang/mock/gomock"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	apps "k8s.io/api/apps/v1"
+	core "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/juju/juju/caas"
+)
+
+type adoptSuite struct {
+	BaseSuite
+}
+
+var _ = gc.Suite(&adoptSuite{})
+
+func (s *adoptSuite) TestAdoptDeployment(c *gc.C) {
+	ctrl := s.setupController(c)
+	defer ctrl.Finish()
+
+	deployment := &apps.Deployment{
+		ObjectMeta: v1.ObjectMeta{
+			Name:   "mariadb",
+			Labels: map[string]string{"app": "mariadb"},
+		},
+		Spec: apps.DeploymentSpec{
+			Template: core.PodTemplateSpec{
+				ObjectMeta: v1.ObjectMeta{
+					Labels: map[string]string{"app": "mariadb"},
+				},
+			},
+		},
+	}
+	adopted := &apps.Deployment{
+		ObjectMeta: v1.ObjectMeta{
+			Name:        "mariadb",
+			Labels:      map[string]string{"app": "mariadb", "juju-app": "db"},
+			Annotations: map[string]string{"juju.io/adopted-workload": "mariadb"},
+		},
+		Spec: apps.DeploymentSpec{
+			Template: core.PodTemplateSpec{
+				ObjectMeta: v1.ObjectMeta{
+					Labels: map[string]string{"app": "mariadb", "juju-app": "db"},
+				},
+			},
+		},
+	}
+
+	gomock.InOrder(
+		s.mockDeployments.EXPECT().Get("mariadb", v1.GetOptions{}).Return(deployment, nil),
+		s.mockDeployments.EXPECT().Update(adopted).Return(adopted, nil),
+	)
+
+	err := s.broker.AdoptWorkload("db", caas.DeploymentStateless, "mariadb")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *adoptSuite) TestAdoptStatefulSet(c *gc.C) {
+	ctrl := s.setupController(c)
+	defer ctrl.Finish()
+
+	statefulSet := &apps.StatefulSet{
+		ObjectMeta: v1.ObjectMeta{
+			Name: "mariadb",
+		},
+	}
+	adopted := &apps.StatefulSet{
+		ObjectMeta: v1.ObjectMeta{
+			Name:        "mariadb",
+			Labels:      map[string]string{"juju-app": "db"},
+			Annotations: map[string]string{"juju.io/adopted-workload": "mariadb"},
+		},
+		Spec: apps.StatefulSetSpec{
+			Template: core.PodTemplateSpec{
+				ObjectMeta: v1.ObjectMeta{
+					Labels: map[string]string{"juju-app": "db"},
+				},
+			},
+		},
+	}
+
+	gomock.InOrder(
+		s.mockStatefulSets.EXPECT().Get("mariadb", v1.GetOptions{}).Return(statefulSet, nil),
+		s.mockStatefulSets.EXPECT().Update(adopted).Return(adopted, nil),
+	)
+
+	err := s.broker.AdoptWorkload("db", caas.DeploymentStateful, "mariadb")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *adoptSuite) TestAdoptWorkloadNotFound(c *gc.C) {
+	ctrl := s.setupController(c)
+	defer ctrl.Finish()
+
+	s.mockDeployments.EXPECT().Get("mariadb", v1.GetOptions{}).Return(nil, s.k8sNotFoundError())
+
+	err := s.broker.AdoptWorkload("db", caas.DeploymentStateless, "mariadb")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *adoptSuite) TestAdoptWorkloadAlreadyManaged(c *gc.C) {
+	ctrl := s.setupController(c)
+	defer ctrl.Finish()
+
+	deployment := &apps.Deployment{
+		ObjectMeta: v1.ObjectMeta{
+			Name:   "mariadb",
+			Labels: map[string]string{"juju-app": "mariadb"},
+		},
+	}
+	s.mockDeployments.EXPECT().Get("mariadb", v1.GetOptions{}).Return(deployment, nil)
+
+	err := s.broker.AdoptWorkload("db", caas.DeploymentStateless, "mariadb")
+	c.Assert(err, jc.Satisfies, errors.IsAlreadyExists)
+	c.Assert(err, gc.ErrorMatches, `workload "mariadb" managed by application "mariadb" already exists`)
+}
+
+func (s *adoptSuite) TestAdoptWorkloadDaemonSetNotSupported(c *gc.C) {
+	ctrl := s.setupController(c)
+	defer ctrl.Finish()
+
+	err := s.broker.AdoptWorkload("db", caas.DeploymentDaemon, "mariadb")
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
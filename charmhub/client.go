@@ -0,0 +1,175 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package charmhub provides a client for querying a charmhub-compatible
+// store for charms and bundles.
+package charmhub
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+)
+
+var logger = loggo.GetLogger("juju.charmhub")
+
+const (
+	// apiVersion is the version of the charmhub API used by the client.
+	apiVersion = "v2"
+
+	// infoFields are the fields requested from the info endpoint.
+	infoFields = "channel-map,default-release,result.description,result.license,result.publisher,result.summary,result.store-url"
+
+	// findFields are the fields requested from the find endpoint.
+	findFields = "default-release,result.publisher,result.summary,result.store-url"
+)
+
+// HTTPClient defines the methods of an http.Client used by the Client.
+type HTTPClient interface {
+	Do(*http.Request) (*http.Response, error)
+}
+
+// Config holds the configuration of a Client.
+type Config struct {
+	// URL is the base URL of the charmhub-compatible store.
+	URL string
+
+	// HTTPClient is used to make requests of the store. If nil,
+	// http.DefaultClient is used, which honours the proxy settings
+	// installed in the default transport.
+	HTTPClient HTTPClient
+}
+
+// Client queries a charmhub-compatible store.
+type Client struct {
+	url    *url.URL
+	client HTTPClient
+}
+
+// NewClient returns a new Client for the store with the given config.
+func NewClient(config Config) (*Client, error) {
+	base, err := url.Parse(config.URL)
+	if err != nil {
+		return nil, errors.Annotate(err, "parsing charmhub url")
+	}
+	if base.Scheme == "" || base.Host == "" {
+		return nil, errors.NotValidf("charmhub url %q", config.URL)
+	}
+	client := config.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &Client{
+		url:    base,
+		client: client,
+	}, nil
+}
+
+// URL returns the base URL of the store.
+func (c *Client) URL() string {
+	return c.url.String()
+}
+
+// Info returns information about the named charm or bundle. An error
+// satisfying errors.IsNotFound is returned if there is no such entity.
+func (c *Client) Info(ctx context.Context, name string) (InfoResponse, error) {
+	if name == "" || strings.Contains(name, "/") {
+		return InfoResponse{}, errors.NotValidf("charm or bundle name %q", name)
+	}
+	query := url.Values{"fields": {infoFields}}
+	var resp InfoResponse
+	if err := c.get(ctx, c.endpoint(query, "charms", "info", name), &resp); err != nil {
+		return InfoResponse{}, errors.Trace(err)
+	}
+	return resp, nil
+}
+
+// Find returns the charms and bundles matching the query.
+func (c *Client) Find(ctx context.Context, query string) ([]FindResponse, error) {
+	values := url.Values{
+		"q":      {query},
+		"fields": {findFields},
+	}
+	var resp FindResponses
+	if err := c.get(ctx, c.endpoint(values, "charms", "find"), &resp); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return resp.Results, nil
+}
+
+// Download writes the contents found at the given download URL, as
+// returned in the revision of an InfoResponse, to w.
+func (c *Client) Download(ctx context.Context, downloadURL string, w io.Writer) error {
+	resp, err := c.do(ctx, downloadURL)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		return errors.Annotatef(err, "downloading %s", downloadURL)
+	}
+	return nil
+}
+
+func (c *Client) endpoint(query url.Values, elem ...string) string {
+	u := *c.url
+	u.Path = path.Join(append([]string{u.Path, apiVersion}, elem...)...)
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+func (c *Client) get(ctx context.Context, endpoint string, result interface{}) error {
+	resp, err := c.do(ctx, endpoint)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return errors.Annotatef(err, "decoding response from %s", endpoint)
+	}
+	return nil
+}
+
+// do makes a GET request of the given URL, returning the response if it
+// succeeded and an error describing the failure otherwise.
+func (c *Client) do(ctx context.Context, target string) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, target, nil)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Accept", "application/json")
+
+	logger.Tracef("GET %s", target)
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, errors.Annotate(err, "querying charmhub")
+	}
+	if resp.StatusCode == http.StatusOK {
+		return resp, nil
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	message := http.StatusText(resp.StatusCode)
+	var apiErrors ErrorResponse
+	if err := json.NewDecoder(resp.Body).Decode(&apiErrors); err == nil && len(apiErrors.ErrorList) > 0 {
+		messages := make([]string, len(apiErrors.ErrorList))
+		for i, apiErr := range apiErrors.ErrorList {
+			messages[i] = apiErr.Message
+		}
+		message = strings.Join(messages, "; ")
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, errors.NewNotFound(nil, message)
+	}
+	return nil, errors.Errorf("charmhub returned %s: %s", resp.Status, message)
+}
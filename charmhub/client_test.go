@@ -0,0 +1,122 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package charmhub_test
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/charmhub"
+	charmhubtesting "github.com/juju/juju/charmhub/testing"
+)
+
+type clientSuite struct {
+	testing.IsolationSuite
+
+	server *charmhubtesting.Server
+	client *charmhub.Client
+}
+
+var _ = gc.Suite(&clientSuite{})
+
+func (s *clientSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.server = charmhubtesting.NewServer(charmhubtesting.Entity{
+		Name:        "wordpress",
+		Publisher:   "WordPress Charmers",
+		Summary:     "WordPress is a full featured web blogging tool.",
+		Description: "This will install and setup WordPress.",
+		Releases: []charmhubtesting.Release{{
+			Track:    "latest",
+			Risk:     "stable",
+			Series:   "focal",
+			Revision: 16,
+			Version:  "1.0.3",
+			Content:  []byte("wordpress-16"),
+		}, {
+			Track:    "latest",
+			Risk:     "edge",
+			Series:   "focal",
+			Revision: 17,
+			Version:  "1.0.4",
+			Content:  []byte("wordpress-17"),
+		}},
+	}, charmhubtesting.Entity{
+		Name:    "mysql",
+		Summary: "MySQL database.",
+	})
+	s.AddCleanup(func(*gc.C) { s.server.Close() })
+
+	var err error
+	s.client, err = charmhub.NewClient(charmhub.Config{URL: s.server.URL})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *clientSuite) TestNewClientInvalidURL(c *gc.C) {
+	_, err := charmhub.NewClient(charmhub.Config{URL: "api.charmhub.io"})
+	c.Assert(err, gc.ErrorMatches, `charmhub url "api.charmhub.io" not valid`)
+}
+
+func (s *clientSuite) TestInfo(c *gc.C) {
+	info, err := s.client.Info(context.Background(), "wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(info.Name, gc.Equals, "wordpress")
+	c.Check(info.Type, gc.Equals, "charm")
+	c.Check(info.Entity.Publisher.DisplayName, gc.Equals, "WordPress Charmers")
+	c.Check(info.Entity.Summary, gc.Equals, "WordPress is a full featured web blogging tool.")
+	c.Check(info.DefaultRelease.Channel.String(), gc.Equals, "latest/stable")
+	c.Check(info.DefaultRelease.Revision.Revision, gc.Equals, 16)
+	c.Assert(info.ChannelMap, gc.HasLen, 2)
+	c.Check(info.ChannelMap[1].Channel.Risk, gc.Equals, "edge")
+	c.Check(info.ChannelMap[1].Revision.Download.URL, gc.Equals, s.server.URL+"/download/wordpress_17.charm")
+
+	c.Check(s.server.Requests(), gc.HasLen, 1)
+	c.Check(s.server.Requests()[0], gc.Matches, `/v2/charms/info/wordpress\?fields=.*`)
+}
+
+func (s *clientSuite) TestInfoNotFound(c *gc.C) {
+	_, err := s.client.Info(context.Background(), "ghost")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	c.Assert(err, gc.ErrorMatches, `No charm or bundle with name 'ghost'.`)
+}
+
+func (s *clientSuite) TestInfoInvalidName(c *gc.C) {
+	_, err := s.client.Info(context.Background(), "cs:wordpress/1")
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+	c.Check(s.server.Requests(), gc.HasLen, 0)
+}
+
+func (s *clientSuite) TestFind(c *gc.C) {
+	results, err := s.client.Find(context.Background(), "word")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Check(results[0].Name, gc.Equals, "wordpress")
+	c.Check(results[0].DefaultRelease.Revision.Version, gc.Equals, "1.0.3")
+}
+
+func (s *clientSuite) TestFindAll(c *gc.C) {
+	results, err := s.client.Find(context.Background(), "")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 2)
+	c.Check(results[0].Name, gc.Equals, "mysql")
+	c.Check(results[1].Name, gc.Equals, "wordpress")
+}
+
+func (s *clientSuite) TestDownload(c *gc.C) {
+	var buf bytes.Buffer
+	err := s.client.Download(context.Background(), s.server.URL+"/download/wordpress_16.charm", &buf)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(buf.String(), gc.Equals, "wordpress-16")
+}
+
+func (s *clientSuite) TestDownloadNotFound(c *gc.C) {
+	var buf bytes.Buffer
+	err := s.client.Download(context.Background(), s.server.URL+"/download/wordpress_1.charm", &buf)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
@@ -0,0 +1,220 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package testing
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/juju/juju/charmhub"
+)
+
+// Entity describes a charm or bundle served by a fake store.
+type Entity struct {
+	Name        string
+	Type        string
+	Publisher   string
+	Summary     string
+	Description string
+
+	// Releases holds the revisions released to each channel. The first
+	// release is the default release.
+	Releases []Release
+}
+
+// Release describes a revision of an entity released to a channel.
+type Release struct {
+	Track    string
+	Risk     string
+	Series   string
+	Revision int
+	Version  string
+	Content  []byte
+}
+
+// Server is a fake charmhub-compatible store, serving the info, find
+// and download endpoints used by the charmhub client.
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	entities map[string]Entity
+	requests []string
+}
+
+// NewServer starts and returns a new fake store serving the given
+// entities. The caller is responsible for closing the server.
+func NewServer(entities ...Entity) *Server {
+	s := &Server{
+		entities: make(map[string]Entity),
+	}
+	for _, e := range entities {
+		s.entities[e.Name] = e
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v2/charms/info/", s.serveInfo)
+	mux.HandleFunc("/v2/charms/find", s.serveFind)
+	mux.HandleFunc("/download/", s.serveDownload)
+	s.Server = httptest.NewServer(s.record(mux))
+	return s
+}
+
+// AddEntity adds an entity to the store, replacing any with the same name.
+func (s *Server) AddEntity(e Entity) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entities[e.Name] = e
+}
+
+// Requests returns the paths and queries of the requests made of the
+// store, in the order they were made.
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.requests...)
+}
+
+func (s *Server) record(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		s.mu.Lock()
+		s.requests = append(s.requests, req.URL.RequestURI())
+		s.mu.Unlock()
+		h.ServeHTTP(w, req)
+	})
+}
+
+func (s *Server) serveInfo(w http.ResponseWriter, req *http.Request) {
+	name := strings.TrimPrefix(req.URL.Path, "/v2/charms/info/")
+	s.mu.Lock()
+	e, ok := s.entities[name]
+	s.mu.Unlock()
+	if !ok {
+		writeError(w, http.StatusNotFound, "resource-not-found", fmt.Sprintf("No charm or bundle with name '%s'.", name))
+		return
+	}
+	resp := charmhub.InfoResponse{
+		Type:   entityType(e),
+		ID:     entityID(e),
+		Name:   e.Name,
+		Entity: s.entity(e),
+	}
+	for i, r := range e.Releases {
+		m := s.channelMap(e, r)
+		if i == 0 {
+			resp.DefaultRelease = m
+		}
+		resp.ChannelMap = append(resp.ChannelMap, m)
+	}
+	writeJSON(w, resp)
+}
+
+func (s *Server) serveFind(w http.ResponseWriter, req *http.Request) {
+	query := strings.ToLower(req.URL.Query().Get("q"))
+	s.mu.Lock()
+	var results []charmhub.FindResponse
+	for _, e := range s.entities {
+		if !strings.Contains(strings.ToLower(e.Name), query) && !strings.Contains(strings.ToLower(e.Summary), query) {
+			continue
+		}
+		result := charmhub.FindResponse{
+			Type:   entityType(e),
+			ID:     entityID(e),
+			Name:   e.Name,
+			Entity: s.entity(e),
+		}
+		if len(e.Releases) > 0 {
+			result.DefaultRelease = s.channelMap(e, e.Releases[0])
+		}
+		results = append(results, result)
+	}
+	s.mu.Unlock()
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Name < results[j].Name
+	})
+	writeJSON(w, charmhub.FindResponses{Results: results})
+}
+
+func (s *Server) serveDownload(w http.ResponseWriter, req *http.Request) {
+	file := strings.TrimPrefix(req.URL.Path, "/download/")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, e := range s.entities {
+		for _, r := range e.Releases {
+			if downloadFile(e, r) == file {
+				_, _ = w.Write(r.Content)
+				return
+			}
+		}
+	}
+	writeError(w, http.StatusNotFound, "resource-not-found", fmt.Sprintf("No download %q.", file))
+}
+
+func (s *Server) entity(e Entity) charmhub.Entity {
+	return charmhub.Entity{
+		Description: e.Description,
+		Publisher:   charmhub.Publisher{DisplayName: e.Publisher},
+		Summary:     e.Summary,
+		StoreURL:    fmt.Sprintf("%s/%s", s.URL, e.Name),
+	}
+}
+
+func (s *Server) channelMap(e Entity, r Release) charmhub.ChannelMap {
+	platform := charmhub.Platform{
+		Architecture: "all",
+		OS:           "ubuntu",
+		Series:       r.Series,
+	}
+	return charmhub.ChannelMap{
+		Channel: charmhub.Channel{
+			Name:     fmt.Sprintf("%s/%s", r.Track, r.Risk),
+			Track:    r.Track,
+			Risk:     r.Risk,
+			Platform: platform,
+		},
+		Revision: charmhub.Revision{
+			Revision: r.Revision,
+			Version:  r.Version,
+			Download: charmhub.Download{
+				HashSHA256: fmt.Sprintf("%x", sha256.Sum256(r.Content)),
+				Size:       len(r.Content),
+				URL:        fmt.Sprintf("%s/download/%s", s.URL, downloadFile(e, r)),
+			},
+			Platforms: []charmhub.Platform{platform},
+		},
+	}
+}
+
+func entityType(e Entity) string {
+	if e.Type == "" {
+		return "charm"
+	}
+	return e.Type
+}
+
+func entityID(e Entity) string {
+	return fmt.Sprintf("%s-id", e.Name)
+}
+
+func downloadFile(e Entity, r Release) string {
+	return fmt.Sprintf("%s_%d.charm", e.Name, r.Revision)
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(charmhub.ErrorResponse{
+		ErrorList: []charmhub.APIError{{Code: code, Message: message}},
+	})
+}
@@ -0,0 +1,100 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package charmhub
+
+// InfoResponse is the response of the charmhub info endpoint for a single
+// charm or bundle.
+type InfoResponse struct {
+	Type           string       `json:"type"`
+	ID             string       `json:"id"`
+	Name           string       `json:"name"`
+	Entity         Entity       `json:"result"`
+	DefaultRelease ChannelMap   `json:"default-release,omitempty"`
+	ChannelMap     []ChannelMap `json:"channel-map"`
+}
+
+// FindResponse is a single result of the charmhub find endpoint.
+type FindResponse struct {
+	Type           string     `json:"type"`
+	ID             string     `json:"id"`
+	Name           string     `json:"name"`
+	Entity         Entity     `json:"result"`
+	DefaultRelease ChannelMap `json:"default-release,omitempty"`
+}
+
+// FindResponses holds the results of the charmhub find endpoint.
+type FindResponses struct {
+	Results []FindResponse `json:"results"`
+}
+
+// Entity holds the metadata describing a charm or bundle.
+type Entity struct {
+	Description string    `json:"description"`
+	License     string    `json:"license"`
+	Publisher   Publisher `json:"publisher"`
+	Summary     string    `json:"summary"`
+	StoreURL    string    `json:"store-url"`
+}
+
+// Publisher describes the publisher of a charm or bundle.
+type Publisher struct {
+	DisplayName string `json:"display-name"`
+}
+
+// ChannelMap relates a channel to the revision released to it.
+type ChannelMap struct {
+	Channel  Channel  `json:"channel"`
+	Revision Revision `json:"revision"`
+}
+
+// Channel describes a channel of a charm or bundle.
+type Channel struct {
+	Name       string   `json:"name"`
+	Track      string   `json:"track"`
+	Risk       string   `json:"risk"`
+	Platform   Platform `json:"platform"`
+	ReleasedAt string   `json:"released-at"`
+}
+
+// String returns the name of the channel.
+func (c Channel) String() string {
+	if c.Name != "" {
+		return c.Name
+	}
+	return c.Track + "/" + c.Risk
+}
+
+// Platform describes the platform a channel is released for.
+type Platform struct {
+	Architecture string `json:"architecture"`
+	OS           string `json:"os"`
+	Series       string `json:"series"`
+}
+
+// Revision describes a single revision of a charm or bundle.
+type Revision struct {
+	Revision  int        `json:"revision"`
+	Version   string     `json:"version"`
+	CreatedAt string     `json:"created-at"`
+	Download  Download   `json:"download"`
+	Platforms []Platform `json:"platforms,omitempty"`
+}
+
+// Download describes where a revision can be downloaded from.
+type Download struct {
+	HashSHA256 string `json:"hash-sha-256"`
+	Size       int    `json:"size"`
+	URL        string `json:"url"`
+}
+
+// ErrorResponse is the body of a charmhub response reporting errors.
+type ErrorResponse struct {
+	ErrorList []APIError `json:"error-list"`
+}
+
+// APIError is a single error reported by charmhub.
+type APIError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
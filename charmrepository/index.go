@@ -0,0 +1,148 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package charmrepository
+
+import (
+	"io"
+	"io/ioutil"
+	"sort"
+
+	"github.com/juju/charm/v7"
+	csparams "github.com/juju/charmrepo/v5/csclient/params"
+	"github.com/juju/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// IndexFile is the name of the index file at the root of a repository.
+const IndexFile = "index.yaml"
+
+// Index describes the charms and bundles held in a repository. For
+// example:
+//
+//	charms:
+//	  wordpress:
+//	    channels:
+//	      stable: 16
+//	      edge: 17
+//	    revisions:
+//	      16:
+//	        series: [bionic, focal]
+//	        archive: wordpress-16.charm
+//	        sha256: 3b5e...
+//	      17:
+//	        series: [focal]
+//	        archive: archives/wordpress-17.charm
+//	bundles:
+//	  wordpress-simple:
+//	    channels:
+//	      stable: 3
+//	    revisions:
+//	      3:
+//	        archive: wordpress-simple-3.bundle
+type Index struct {
+	Charms  map[string]Entity `yaml:"charms,omitempty"`
+	Bundles map[string]Entity `yaml:"bundles,omitempty"`
+}
+
+// Entity describes the revisions of a charm or bundle held in a
+// repository, and the channels they are released to.
+type Entity struct {
+	// Channels maps each channel the entity is released to to the
+	// revision released to it.
+	Channels map[string]int `yaml:"channels,omitempty"`
+
+	// Revisions holds the revisions held in the repository.
+	Revisions map[int]Revision `yaml:"revisions"`
+}
+
+// Revision describes a single revision of a charm or bundle.
+type Revision struct {
+	// Series holds the series supported by a charm revision, with the
+	// preferred series first. It is not used for bundles.
+	Series []string `yaml:"series,omitempty"`
+
+	// Archive is the location of the revision's archive, either
+	// relative to the index or as an absolute URL with the same
+	// scheme and host as the repository.
+	Archive string `yaml:"archive"`
+
+	// SHA256 is the hex encoded SHA256 hash of the archive. If set,
+	// archives are checked against it when fetched.
+	SHA256 string `yaml:"sha256,omitempty"`
+}
+
+// ReadIndex reads and validates a repository index.
+func ReadIndex(r io.Reader) (*Index, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var index Index
+	if err := yaml.UnmarshalStrict(data, &index); err != nil {
+		return nil, errors.Annotate(err, "cannot parse index")
+	}
+	if err := index.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &index, nil
+}
+
+// Validate returns an error if the index is not valid.
+func (index *Index) Validate() error {
+	for _, name := range sortedNames(index.Charms) {
+		if err := validateEntity(name, index.Charms[name], true); err != nil {
+			return errors.Annotatef(err, "charm %q", name)
+		}
+	}
+	for _, name := range sortedNames(index.Bundles) {
+		if _, ok := index.Charms[name]; ok {
+			return errors.Errorf("%q is both a charm and a bundle", name)
+		}
+		if err := validateEntity(name, index.Bundles[name], false); err != nil {
+			return errors.Annotatef(err, "bundle %q", name)
+		}
+	}
+	return nil
+}
+
+func validateEntity(name string, entity Entity, isCharm bool) error {
+	if !charm.IsValidName(name) {
+		return errors.NotValidf("name")
+	}
+	if len(entity.Revisions) == 0 {
+		return errors.New("no revisions specified")
+	}
+	for rev, r := range entity.Revisions {
+		if rev < 0 {
+			return errors.NotValidf("revision %d", rev)
+		}
+		if r.Archive == "" {
+			return errors.Errorf("revision %d: no archive specified", rev)
+		}
+		if isCharm && len(r.Series) == 0 {
+			return errors.Errorf("revision %d: no series specified", rev)
+		}
+		if !isCharm && len(r.Series) > 0 {
+			return errors.Errorf("revision %d: series not valid for a bundle", rev)
+		}
+	}
+	for channel, rev := range entity.Channels {
+		if !csparams.ValidChannels[csparams.Channel(channel)] {
+			return errors.NotValidf("channel %q", channel)
+		}
+		if _, ok := entity.Revisions[rev]; !ok {
+			return errors.Errorf("channel %q refers to unknown revision %d", channel, rev)
+		}
+	}
+	return nil
+}
+
+func sortedNames(entities map[string]Entity) []string {
+	names := make([]string, 0, len(entities))
+	for name := range entities {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,113 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package charmrepository_test
+
+import (
+	"strings"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/charmrepository"
+)
+
+type indexSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&indexSuite{})
+
+func (s *indexSuite) TestReadIndex(c *gc.C) {
+	index, err := charmrepository.ReadIndex(strings.NewReader(`
+charms:
+  wordpress:
+    channels:
+      stable: 16
+      edge: 17
+    revisions:
+      16:
+        series: [bionic, focal]
+        archive: wordpress-16.charm
+        sha256: abcd
+      17:
+        series: [focal]
+        archive: https://mirror.example.com/wordpress-17.charm
+bundles:
+  wordpress-simple:
+    channels:
+      stable: 3
+    revisions:
+      3:
+        archive: wordpress-simple-3.bundle
+`))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(index, jc.DeepEquals, &charmrepository.Index{
+		Charms: map[string]charmrepository.Entity{
+			"wordpress": {
+				Channels: map[string]int{"stable": 16, "edge": 17},
+				Revisions: map[int]charmrepository.Revision{
+					16: {Series: []string{"bionic", "focal"}, Archive: "wordpress-16.charm", SHA256: "abcd"},
+					17: {Series: []string{"focal"}, Archive: "https://mirror.example.com/wordpress-17.charm"},
+				},
+			},
+		},
+		Bundles: map[string]charmrepository.Entity{
+			"wordpress-simple": {
+				Channels: map[string]int{"stable": 3},
+				Revisions: map[int]charmrepository.Revision{
+					3: {Archive: "wordpress-simple-3.bundle"},
+				},
+			},
+		},
+	})
+}
+
+func (s *indexSuite) TestReadIndexInvalid(c *gc.C) {
+	for i, test := range []struct {
+		about string
+		index string
+		err   string
+	}{{
+		about: "unknown field",
+		index: "charms: {}\nextra: 1\n",
+		err:   `(?s)cannot parse index: .*field extra not found.*`,
+	}, {
+		about: "invalid name",
+		index: "charms:\n  Bad_Name:\n    revisions: {1: {series: [focal], archive: a}}\n",
+		err:   `charm "Bad_Name": name not valid`,
+	}, {
+		about: "no revisions",
+		index: "charms:\n  wordpress:\n    channels: {stable: 1}\n",
+		err:   `charm "wordpress": no revisions specified`,
+	}, {
+		about: "no archive",
+		index: "charms:\n  wordpress:\n    revisions: {1: {series: [focal]}}\n",
+		err:   `charm "wordpress": revision 1: no archive specified`,
+	}, {
+		about: "charm without series",
+		index: "charms:\n  wordpress:\n    revisions: {1: {archive: a}}\n",
+		err:   `charm "wordpress": revision 1: no series specified`,
+	}, {
+		about: "bundle with series",
+		index: "bundles:\n  wordpress:\n    revisions: {1: {series: [focal], archive: a}}\n",
+		err:   `bundle "wordpress": revision 1: series not valid for a bundle`,
+	}, {
+		about: "invalid channel",
+		index: "charms:\n  wordpress:\n    channels: {latest: 1}\n    revisions: {1: {series: [focal], archive: a}}\n",
+		err:   `charm "wordpress": channel "latest" not valid`,
+	}, {
+		about: "unknown revision",
+		index: "charms:\n  wordpress:\n    channels: {stable: 2}\n    revisions: {1: {series: [focal], archive: a}}\n",
+		err:   `charm "wordpress": channel "stable" refers to unknown revision 2`,
+	}, {
+		about: "charm and bundle",
+		index: "charms:\n  wordpress:\n    revisions: {1: {series: [focal], archive: a}}\nbundles:\n  wordpress:\n    revisions: {1: {archive: b}}\n",
+		err:   `"wordpress" is both a charm and a bundle`,
+	}} {
+		c.Logf("test %d: %s", i, test.about)
+		_, err := charmrepository.ReadIndex(strings.NewReader(test.index))
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
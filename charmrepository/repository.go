@@ -0,0 +1,317 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package charmrepository implements a charm repository backed by an
+// index file, held either on a local file system or served over HTTP.
+// It allows vetted charms and bundles to be mirrored and deployed
+// without a full charm store.
+package charmrepository
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/juju/charm/v7"
+	"github.com/juju/charmrepo/v5"
+	csparams "github.com/juju/charmrepo/v5/csclient/params"
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+)
+
+var logger = loggo.GetLogger("juju.charmrepository")
+
+// Repository is a charm repository described by an index.
+type Repository struct {
+	location string
+	base     *url.URL
+	client   *http.Client
+	index    *Index
+}
+
+var _ charmrepo.Interface = (*Repository)(nil)
+
+// Open reads the index of the repository at the given location, which
+// may be a local directory, a file:// URL or an http:// or https:// URL.
+func Open(location string) (*Repository, error) {
+	return OpenWithClient(location, http.DefaultClient)
+}
+
+// OpenWithClient is like Open but uses the given client to fetch
+// resources served over HTTP.
+func OpenWithClient(location string, client *http.Client) (*Repository, error) {
+	base, err := parseLocation(location)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	repo := &Repository{
+		location: location,
+		base:     base,
+		client:   client,
+	}
+	r, err := repo.open(IndexFile)
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot read charm repository %q", location)
+	}
+	defer r.Close()
+	repo.index, err = ReadIndex(r)
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot read charm repository %q", location)
+	}
+	return repo, nil
+}
+
+// ValidateLocation returns an error if the given repository location
+// is not valid.
+func ValidateLocation(location string) error {
+	_, err := parseLocation(location)
+	return errors.Trace(err)
+}
+
+func parseLocation(location string) (*url.URL, error) {
+	if location == "" {
+		return nil, errors.NotValidf("empty charm repository location")
+	}
+	u, err := url.Parse(location)
+	if err != nil {
+		return nil, errors.NotValidf("charm repository location %q", location)
+	}
+	switch u.Scheme {
+	case "":
+		path, err := filepath.Abs(location)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		u = &url.URL{Scheme: "file", Path: filepath.ToSlash(path)}
+	case "file":
+	case "http", "https":
+		if u.Host == "" {
+			return nil, errors.NotValidf("charm repository location %q", location)
+		}
+	default:
+		return nil, errors.NotValidf("charm repository location %q with scheme %q", location, u.Scheme)
+	}
+	// Ensure the base refers to a directory, so that relative
+	// references resolve within it.
+	if !strings.HasSuffix(u.Path, "/") {
+		u.Path += "/"
+	}
+	return u, nil
+}
+
+// Location returns the location the repository was opened from.
+func (r *Repository) Location() string {
+	return r.location
+}
+
+// Index returns the repository's index.
+func (r *Repository) Index() *Index {
+	return r.index
+}
+
+// Resolve implements charmrepo.Interface.Resolve.
+func (r *Repository) Resolve(ref *charm.URL) (*charm.URL, []string, error) {
+	curl, _, supportedSeries, err := r.ResolveWithPreferredChannel(ref, csparams.NoChannel)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	return curl, supportedSeries, nil
+}
+
+// ResolveWithPreferredChannel resolves the given reference to a
+// specific revision of a charm or bundle in the repository. If a
+// channel is specified the revision released to that channel is used,
+// otherwise the revision released to the most stable channel is used.
+// It returns the resolved URL, the channel it was resolved from and
+// the series supported by a charm.
+func (r *Repository) ResolveWithPreferredChannel(ref *charm.URL, channel csparams.Channel) (*charm.URL, csparams.Channel, []string, error) {
+	if ref.Schema != "cs" || ref.User != "" {
+		return nil, csparams.NoChannel, nil, errors.NotValidf("charm URL %q for a charm repository", ref)
+	}
+	entity, isBundle, err := r.lookup(ref)
+	if err != nil {
+		return nil, csparams.NoChannel, nil, errors.Trace(err)
+	}
+	rev, channel, err := selectRevision(ref, entity, channel)
+	if err != nil {
+		return nil, csparams.NoChannel, nil, errors.Trace(err)
+	}
+	if isBundle {
+		return &charm.URL{
+			Schema:   "cs",
+			Name:     ref.Name,
+			Series:   "bundle",
+			Revision: rev,
+		}, channel, nil, nil
+	}
+	supportedSeries := entity.Revisions[rev].Series
+	if ref.Series != "" && !containsString(supportedSeries, ref.Series) {
+		return nil, csparams.NoChannel, nil, charm.NewUnsupportedSeriesError(ref.Series, supportedSeries)
+	}
+	return &charm.URL{
+		Schema:   "cs",
+		Name:     ref.Name,
+		Series:   ref.Series,
+		Revision: rev,
+	}, channel, supportedSeries, nil
+}
+
+// lookup returns the index entry for the given reference and whether
+// it is a bundle.
+func (r *Repository) lookup(ref *charm.URL) (Entity, bool, error) {
+	if ref.Series != "bundle" {
+		if entity, ok := r.index.Charms[ref.Name]; ok {
+			return entity, false, nil
+		}
+	}
+	if ref.Series == "" || ref.Series == "bundle" {
+		if entity, ok := r.index.Bundles[ref.Name]; ok {
+			return entity, true, nil
+		}
+	}
+	etype := "charm"
+	switch ref.Series {
+	case "bundle":
+		etype = "bundle"
+	case "":
+		etype = "charm or bundle"
+	}
+	return Entity{}, false, errors.NotFoundf("%s %q in charm repository", etype, ref.Name)
+}
+
+func selectRevision(ref *charm.URL, entity Entity, channel csparams.Channel) (int, csparams.Channel, error) {
+	if ref.Revision >= 0 {
+		if _, ok := entity.Revisions[ref.Revision]; !ok {
+			return 0, csparams.NoChannel, errors.NotFoundf("%q in charm repository", ref)
+		}
+		if channel != csparams.NoChannel && entity.Channels[string(channel)] == ref.Revision {
+			return ref.Revision, channel, nil
+		}
+		for _, ch := range csparams.OrderedChannels {
+			if rev, ok := entity.Channels[string(ch)]; ok && rev == ref.Revision {
+				return ref.Revision, ch, nil
+			}
+		}
+		return ref.Revision, csparams.NoChannel, nil
+	}
+	if channel != csparams.NoChannel {
+		rev, ok := entity.Channels[string(channel)]
+		if !ok {
+			return 0, csparams.NoChannel, errors.NotFoundf("%q in channel %q of charm repository", ref.Name, channel)
+		}
+		return rev, channel, nil
+	}
+	for _, ch := range csparams.OrderedChannels {
+		if rev, ok := entity.Channels[string(ch)]; ok {
+			return rev, ch, nil
+		}
+	}
+	return 0, csparams.NoChannel, errors.NotFoundf("%q released to any channel of charm repository", ref.Name)
+}
+
+// Get implements charmrepo.Interface.Get.
+func (r *Repository) Get(curl *charm.URL, archivePath string) (*charm.CharmArchive, error) {
+	if curl.Series == "bundle" {
+		return nil, errors.Errorf("expected a charm URL, got bundle URL %q", curl)
+	}
+	if err := r.fetch(r.index.Charms, curl, archivePath); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return charm.ReadCharmArchive(archivePath)
+}
+
+// GetBundle implements charmrepo.Interface.GetBundle.
+func (r *Repository) GetBundle(curl *charm.URL, archivePath string) (charm.Bundle, error) {
+	if curl.Series != "bundle" {
+		return nil, errors.Errorf("expected a bundle URL, got charm URL %q", curl)
+	}
+	if err := r.fetch(r.index.Bundles, curl, archivePath); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return charm.ReadBundleArchive(archivePath)
+}
+
+// fetch writes the archive of the given revision to archivePath,
+// verifying its hash when the index records one.
+func (r *Repository) fetch(entities map[string]Entity, curl *charm.URL, archivePath string) error {
+	if curl.Revision < 0 {
+		return errors.Errorf("charm URL %q has no revision", curl)
+	}
+	rev, ok := entities[curl.Name].Revisions[curl.Revision]
+	if !ok {
+		return errors.NotFoundf("%q in charm repository", curl)
+	}
+	logger.Debugf("fetching %q from %q", curl, rev.Archive)
+	src, err := r.open(rev.Archive)
+	if err != nil {
+		return errors.Annotatef(err, "cannot retrieve %q", curl)
+	}
+	defer src.Close()
+
+	f, err := os.Create(archivePath)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer f.Close()
+	hash := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(f, hash), src); err != nil {
+		return errors.Annotatef(err, "cannot retrieve %q", curl)
+	}
+	if rev.SHA256 != "" && fmt.Sprintf("%x", hash.Sum(nil)) != strings.ToLower(rev.SHA256) {
+		return errors.Errorf("cannot retrieve %q: hash mismatch", curl)
+	}
+	return nil
+}
+
+// open opens the resource at the given location, resolved relative to
+// the repository base. The resolved location must keep the scheme and
+// host of the base, so that an index served over HTTP cannot refer to
+// local files or to other hosts.
+func (r *Repository) open(location string) (io.ReadCloser, error) {
+	ref, err := url.Parse(location)
+	if err != nil {
+		return nil, errors.NotValidf("location %q", location)
+	}
+	u := r.base.ResolveReference(ref)
+	if u.Scheme != r.base.Scheme || u.Host != r.base.Host {
+		return nil, errors.NotValidf("location %q outside charm repository %q", location, r.location)
+	}
+	switch u.Scheme {
+	case "file":
+		f, err := os.Open(filepath.FromSlash(u.Path))
+		if os.IsNotExist(err) {
+			return nil, errors.NotFoundf("%q", u.Path)
+		}
+		return f, errors.Trace(err)
+	case "http", "https":
+		resp, err := r.client.Get(u.String())
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		switch resp.StatusCode {
+		case http.StatusOK:
+			return resp.Body, nil
+		case http.StatusNotFound:
+			resp.Body.Close()
+			return nil, errors.NotFoundf("%q", u)
+		default:
+			resp.Body.Close()
+			return nil, errors.Errorf("cannot get %q: %s", u, resp.Status)
+		}
+	}
+	return nil, errors.NotValidf("location %q with scheme %q", u, u.Scheme)
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
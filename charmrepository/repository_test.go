@@ -0,0 +1,269 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package charmrepository_test
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+
+	"github.com/juju/charm/v7"
+	csparams "github.com/juju/charmrepo/v5/csclient/params"
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/charmrepository"
+	"github.com/juju/juju/testcharms"
+)
+
+type repositorySuite struct {
+	testing.IsolationSuite
+
+	dir string
+}
+
+var _ = gc.Suite(&repositorySuite{})
+
+func (s *repositorySuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.dir = c.MkDir()
+	repo := testcharms.RepoWithSeries("bionic")
+	charmPath := repo.CharmArchivePath(s.dir, "wordpress")
+	bundlePath := repo.BundleArchivePath(s.dir, "wordpress-simple")
+
+	index := fmt.Sprintf(`
+charms:
+  wordpress:
+    channels:
+      stable: 16
+      edge: 17
+    revisions:
+      16:
+        series: [bionic, focal]
+        archive: %s
+        sha256: %s
+      17:
+        series: [focal]
+        archive: missing.charm
+bundles:
+  wordpress-simple:
+    channels:
+      candidate: 3
+    revisions:
+      3:
+        archive: %s
+`, filepath.Base(charmPath), fileSHA256(c, charmPath), filepath.Base(bundlePath))
+	err := ioutil.WriteFile(filepath.Join(s.dir, charmrepository.IndexFile), []byte(index), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func fileSHA256(c *gc.C, path string) string {
+	data, err := ioutil.ReadFile(path)
+	c.Assert(err, jc.ErrorIsNil)
+	return fmt.Sprintf("%x", sha256.Sum256(data))
+}
+
+func (s *repositorySuite) TestOpenInvalidLocation(c *gc.C) {
+	_, err := charmrepository.Open("ftp://example.com/charms")
+	c.Assert(err, gc.ErrorMatches, `charm repository location "ftp://example.com/charms" with scheme "ftp" not valid`)
+	_, err = charmrepository.Open("http:///charms")
+	c.Assert(err, gc.ErrorMatches, `charm repository location "http:///charms" not valid`)
+}
+
+func (s *repositorySuite) TestOpenMissingIndex(c *gc.C) {
+	dir := c.MkDir()
+	_, err := charmrepository.Open(dir)
+	c.Assert(err, gc.ErrorMatches, fmt.Sprintf(`cannot read charm repository %q: ".*index.yaml" not found`, dir))
+}
+
+func (s *repositorySuite) TestResolve(c *gc.C) {
+	repo, err := charmrepository.Open(s.dir)
+	c.Assert(err, jc.ErrorIsNil)
+
+	for i, test := range []struct {
+		ref             string
+		channel         csparams.Channel
+		url             string
+		resolvedChannel csparams.Channel
+		series          []string
+		err             string
+	}{{
+		ref:             "wordpress",
+		url:             "cs:wordpress-16",
+		resolvedChannel: csparams.StableChannel,
+		series:          []string{"bionic", "focal"},
+	}, {
+		ref:             "cs:bionic/wordpress",
+		url:             "cs:bionic/wordpress-16",
+		resolvedChannel: csparams.StableChannel,
+		series:          []string{"bionic", "focal"},
+	}, {
+		ref:             "wordpress",
+		channel:         csparams.EdgeChannel,
+		url:             "cs:wordpress-17",
+		resolvedChannel: csparams.EdgeChannel,
+		series:          []string{"focal"},
+	}, {
+		ref:             "wordpress-17",
+		url:             "cs:wordpress-17",
+		resolvedChannel: csparams.EdgeChannel,
+		series:          []string{"focal"},
+	}, {
+		ref:     "wordpress",
+		channel: csparams.BetaChannel,
+		err:     `"wordpress" in channel "beta" of charm repository not found`,
+	}, {
+		ref: "wordpress-1",
+		err: `"cs:wordpress-1" in charm repository not found`,
+	}, {
+		ref:     "cs:bionic/wordpress",
+		channel: csparams.EdgeChannel,
+		err:     `series "bionic" not supported by charm, supported series are: focal`,
+	}, {
+		ref:             "wordpress-simple",
+		url:             "cs:bundle/wordpress-simple-3",
+		resolvedChannel: csparams.CandidateChannel,
+	}, {
+		ref: "cs:bionic/wordpress-simple",
+		err: `charm "wordpress-simple" in charm repository not found`,
+	}, {
+		ref: "cs:bundle/wordpress",
+		err: `bundle "wordpress" in charm repository not found`,
+	}, {
+		ref: "cs:~bob/wordpress",
+		err: `charm URL "cs:~bob/wordpress" for a charm repository not valid`,
+	}} {
+		c.Logf("test %d: %s %q", i, test.ref, test.channel)
+		ref := charm.MustParseURL(test.ref)
+		curl, channel, series, err := repo.ResolveWithPreferredChannel(ref, test.channel)
+		if test.err != "" {
+			c.Check(err, gc.ErrorMatches, test.err)
+			continue
+		}
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(curl.String(), gc.Equals, test.url)
+		c.Check(channel, gc.Equals, test.resolvedChannel)
+		c.Check(series, jc.DeepEquals, test.series)
+	}
+}
+
+func (s *repositorySuite) TestResolveNotFound(c *gc.C) {
+	repo, err := charmrepository.Open(s.dir)
+	c.Assert(err, jc.ErrorIsNil)
+	_, _, err = repo.Resolve(charm.MustParseURL("mysql"))
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	c.Assert(err, gc.ErrorMatches, `charm or bundle "mysql" in charm repository not found`)
+}
+
+func (s *repositorySuite) TestGet(c *gc.C) {
+	repo, err := charmrepository.Open(s.dir)
+	c.Assert(err, jc.ErrorIsNil)
+
+	path := filepath.Join(c.MkDir(), "wordpress.charm")
+	ch, err := repo.Get(charm.MustParseURL("cs:wordpress-16"), path)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ch.Meta().Name, gc.Equals, "wordpress")
+	c.Assert(ch.Path, gc.Equals, path)
+}
+
+func (s *repositorySuite) TestGetHashMismatch(c *gc.C) {
+	err := ioutil.WriteFile(filepath.Join(s.dir, charmrepository.IndexFile), []byte(`
+charms:
+  wordpress:
+    revisions:
+      16:
+        series: [bionic]
+        archive: archive.charm
+        sha256: 0123
+`), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	repo, err := charmrepository.Open(s.dir)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = repo.Get(charm.MustParseURL("cs:wordpress-16"), filepath.Join(c.MkDir(), "wordpress.charm"))
+	c.Assert(err, gc.ErrorMatches, `cannot retrieve "cs:wordpress-16": hash mismatch`)
+}
+
+func (s *repositorySuite) TestGetMissingArchive(c *gc.C) {
+	repo, err := charmrepository.Open(s.dir)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = repo.Get(charm.MustParseURL("cs:wordpress-17"), filepath.Join(c.MkDir(), "wordpress.charm"))
+	c.Assert(err, gc.ErrorMatches, `cannot retrieve "cs:wordpress-17": ".*missing.charm" not found`)
+}
+
+func (s *repositorySuite) TestGetBundle(c *gc.C) {
+	repo, err := charmrepository.Open("file://" + filepath.ToSlash(s.dir))
+	c.Assert(err, jc.ErrorIsNil)
+
+	b, err := repo.GetBundle(charm.MustParseURL("cs:bundle/wordpress-simple-3"), filepath.Join(c.MkDir(), "bundle"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(b.Data().Applications, gc.HasLen, 2)
+
+	_, err = repo.GetBundle(charm.MustParseURL("cs:wordpress-16"), filepath.Join(c.MkDir(), "bundle"))
+	c.Assert(err, gc.ErrorMatches, `expected a bundle URL, got charm URL "cs:wordpress-16"`)
+}
+
+func (s *repositorySuite) TestHTTP(c *gc.C) {
+	var requests []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		requests = append(requests, req.URL.Path)
+		http.StripPrefix("/repo/", http.FileServer(http.Dir(s.dir))).ServeHTTP(w, req)
+	}))
+	defer srv.Close()
+
+	repo, err := charmrepository.Open(srv.URL + "/repo")
+	c.Assert(err, jc.ErrorIsNil)
+
+	curl, _, err := repo.Resolve(charm.MustParseURL("wordpress"))
+	c.Assert(err, jc.ErrorIsNil)
+	ch, err := repo.Get(curl, filepath.Join(c.MkDir(), "wordpress.charm"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ch.Meta().Name, gc.Equals, "wordpress")
+
+	_, err = repo.Get(charm.MustParseURL("cs:wordpress-17"), filepath.Join(c.MkDir(), "wordpress.charm"))
+	c.Assert(err, gc.ErrorMatches, `cannot retrieve "cs:wordpress-17": ".*/repo/missing.charm" not found`)
+	c.Assert(requests, jc.DeepEquals, []string{
+		"/repo/index.yaml",
+		"/repo/archive.charm",
+		"/repo/missing.charm",
+	})
+}
+
+func (s *repositorySuite) TestHTTPArchiveOutsideRepository(c *gc.C) {
+	localPath := filepath.Join(s.dir, "local.charm")
+	index := fmt.Sprintf(`
+charms:
+  local:
+    revisions:
+      1:
+        series: [bionic]
+        archive: file://%s
+  remote:
+    revisions:
+      1:
+        series: [bionic]
+        archive: http://example.com/remote.charm
+`, filepath.ToSlash(localPath))
+	var requests []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		requests = append(requests, req.URL.Path)
+		_, _ = w.Write([]byte(index))
+	}))
+	defer srv.Close()
+
+	repo, err := charmrepository.Open(srv.URL + "/repo")
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = repo.Get(charm.MustParseURL("cs:local-1"), filepath.Join(c.MkDir(), "local.charm"))
+	c.Assert(err, gc.ErrorMatches, `cannot retrieve "cs:local-1": location "file://.*/local.charm" outside charm repository ".*/repo" not valid`)
+	_, err = repo.Get(charm.MustParseURL("cs:remote-1"), filepath.Join(c.MkDir(), "remote.charm"))
+	c.Assert(err, gc.ErrorMatches, `cannot retrieve "cs:remote-1": location "http://example.com/remote.charm" outside charm repository ".*/repo" not valid`)
+	c.Assert(requests, jc.DeepEquals, []string{"/repo/index.yaml"})
+}
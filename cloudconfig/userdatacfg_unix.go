@@ -136,13 +136,7 @@ func (w *unixConfigure) Configure() error {
 // between image bringup and start of agent installation.
 func (w *unixConfigure) ConfigureBasic() error {
 	// Keep preruncmd at the beginning of any runcmd's that juju adds
-	if preruncmds, ok := w.icfg.CloudInitUserData["preruncmd"].([]interface{}); ok {
-		for i := len(preruncmds) - 1; i >= 0; i -= 1 {
-			if cmd, ok := preruncmds[i].(string); ok {
-				w.conf.PrependRunCmd(cmd)
-			}
-		}
-	}
+	addUserPreRunCmds(w.conf, w.icfg.CloudInitUserData)
 	w.conf.AddRunCmd(
 		"set -xe", // ensure we run all the scripts or abort.
 	)
@@ -243,11 +237,7 @@ func (w *unixConfigure) ConfigureJuju() error {
 
 	// To keep postruncmd at the end of any runcmd's that juju adds,
 	// this block must stay at the top.
-	if postruncmds, ok := w.icfg.CloudInitUserData["postruncmd"].([]interface{}); ok {
-		cmds := make([]string, len(postruncmds))
-		for i, v := range postruncmds {
-			cmds[i] = v.(string)
-		}
+	if cmds := userPostRunCmds(w.icfg.CloudInitUserData); len(cmds) > 0 {
 		defer w.conf.AddScripts(cmds...)
 	}
 
@@ -393,13 +383,7 @@ func (w *unixConfigure) ConfigureJuju() error {
 	}
 
 	// Append cloudinit-userdata packages to the end of the juju created ones.
-	if packagesToAdd, ok := w.icfg.CloudInitUserData["packages"].([]interface{}); ok {
-		for _, v := range packagesToAdd {
-			if pack, ok := v.(string); ok {
-				w.conf.AddPackage(pack)
-			}
-		}
-	}
+	addUserPackages(w.conf, w.icfg.CloudInitUserData)
 
 	w.conf.AddRunTextFile("/sbin/remove-juju-services", removeServicesScript, 0755)
 
@@ -416,6 +400,51 @@ func isAllowedOverrideAttr(attr string) bool {
 	return true
 }
 
+// addUserPreRunCmds prepends the cloudinit-userdata preruncmd entries
+// to the runcmds, in the order they were specified.
+func addUserPreRunCmds(conf cloudinit.CloudConfig, userData map[string]interface{}) {
+	if preruncmds, ok := userData["preruncmd"].([]interface{}); ok {
+		for i := len(preruncmds) - 1; i >= 0; i -= 1 {
+			if cmd, ok := preruncmds[i].(string); ok {
+				conf.PrependRunCmd(cmd)
+			}
+		}
+	}
+}
+
+// userPostRunCmds returns the cloudinit-userdata postruncmd entries.
+func userPostRunCmds(userData map[string]interface{}) []string {
+	postruncmds, _ := userData["postruncmd"].([]interface{})
+	cmds := make([]string, 0, len(postruncmds))
+	for _, v := range postruncmds {
+		if cmd, ok := v.(string); ok {
+			cmds = append(cmds, cmd)
+		}
+	}
+	return cmds
+}
+
+// addUserPackages adds the cloudinit-userdata packages.
+func addUserPackages(conf cloudinit.CloudConfig, userData map[string]interface{}) {
+	if packagesToAdd, ok := userData["packages"].([]interface{}); ok {
+		for _, v := range packagesToAdd {
+			if pack, ok := v.(string); ok {
+				conf.AddPackage(pack)
+			}
+		}
+	}
+}
+
+// addUserOverrides sets the cloudinit-userdata attributes that are not
+// merged with those added by juju.
+func addUserOverrides(conf cloudinit.CloudConfig, userData map[string]interface{}) {
+	for k, v := range userData {
+		if isAllowedOverrideAttr(k) {
+			conf.SetAttr(k, v)
+		}
+	}
+}
+
 func (w *unixConfigure) formatCurlProxyArguments() (proxyArgs string) {
 	tools := w.icfg.ToolsList()[0]
 	var proxySettings proxy.Settings
@@ -440,13 +469,9 @@ func (w *unixConfigure) formatCurlProxyArguments() (proxyArgs string) {
 
 // ConfigureCustomOverrides implements UserdataConfig.ConfigureCustomOverrides
 func (w *unixConfigure) ConfigureCustomOverrides() error {
-	for k, v := range w.icfg.CloudInitUserData {
-		// preruncmd was handled in ConfigureBasic()
-		// packages and postruncmd have been handled in ConfigureJuju()
-		if isAllowedOverrideAttr(k) {
-			w.conf.SetAttr(k, v)
-		}
-	}
+	// preruncmd was handled in ConfigureBasic()
+	// packages and postruncmd have been handled in ConfigureJuju()
+	addUserOverrides(w.conf, w.icfg.CloudInitUserData)
 	return nil
 }
 
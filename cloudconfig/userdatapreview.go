@@ -0,0 +1,43 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package cloudconfig
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/os"
+	"github.com/juju/os/series"
+
+	"github.com/juju/juju/cloudconfig/cloudinit"
+)
+
+// AgentInstallPlaceholder is the runcmd that stands in for the commands
+// juju adds to install and start the machine agent in a user data preview.
+const AgentInstallPlaceholder = "# juju: install and start the machine agent"
+
+// PreviewUserData renders the cloud-config produced for a machine of the
+// given series with the given cloudinit-userdata. The user data is merged
+// in the same way as when the machine is provisioned: preruncmd before
+// the juju agent installation, postruncmd and packages after it, and any
+// other attributes set as they are. The commands juju adds to install the
+// agent are replaced by AgentInstallPlaceholder, as they depend on the
+// machine's instance configuration.
+func PreviewUserData(machineSeries string, userData map[string]interface{}) ([]byte, error) {
+	seriesos, err := series.GetOSFromSeries(machineSeries)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if seriesos == os.Windows {
+		return nil, errors.NotSupportedf("previewing user data for series %q", machineSeries)
+	}
+	conf, err := cloudinit.New(machineSeries)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	addUserPreRunCmds(conf, userData)
+	conf.AddRunCmd(AgentInstallPlaceholder)
+	addUserPackages(conf, userData)
+	conf.AddScripts(userPostRunCmds(userData)...)
+	addUserOverrides(conf, userData)
+	return conf.RenderYAML()
+}
@@ -0,0 +1,53 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package cloudconfig_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/yaml.v2"
+
+	"github.com/juju/juju/cloudconfig"
+	"github.com/juju/juju/testing"
+)
+
+type previewSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&previewSuite{})
+
+func (s *previewSuite) TestPreviewUserData(c *gc.C) {
+	out, err := cloudconfig.PreviewUserData("bionic", map[string]interface{}{
+		"packages":        []interface{}{"python-keystoneclient"},
+		"preruncmd":       []interface{}{"mkdir /tmp/preruncmd", "mkdir /tmp/preruncmd2"},
+		"postruncmd":      []interface{}{"mkdir /tmp/postruncmd"},
+		"package_upgrade": false,
+		"write_files": []interface{}{
+			map[string]interface{}{"path": "/etc/gpu.conf", "content": "enabled"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	var rendered map[string]interface{}
+	err = yaml.Unmarshal(out, &rendered)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(rendered["runcmd"], jc.DeepEquals, []interface{}{
+		"mkdir /tmp/preruncmd",
+		"mkdir /tmp/preruncmd2",
+		cloudconfig.AgentInstallPlaceholder,
+		"mkdir /tmp/postruncmd",
+	})
+	c.Check(rendered["packages"], jc.DeepEquals, []interface{}{"python-keystoneclient"})
+	c.Check(rendered["package_upgrade"], jc.IsFalse)
+	c.Check(rendered["write_files"], jc.DeepEquals, []interface{}{
+		map[interface{}]interface{}{"path": "/etc/gpu.conf", "content": "enabled"},
+	})
+}
+
+func (s *previewSuite) TestPreviewUserDataWindows(c *gc.C) {
+	_, err := cloudconfig.PreviewUserData("win2012r2", nil)
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
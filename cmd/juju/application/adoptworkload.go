@@ -0,0 +1,246 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/juju/charm/v7"
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+	"gopkg.in/yaml.v2"
+
+	"github.com/juju/juju/api"
+	"github.com/juju/juju/api/application"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/caas"
+	"github.com/juju/juju/charmstore"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/cmd/modelcmd"
+)
+
+// placeholderCharmMinJujuVersion is the minimum Juju version set on
+// generated placeholder charms, which allows them to be deployed
+// without operator storage.
+const placeholderCharmMinJujuVersion = "2.8.0"
+
+// workloadKinds maps the supported Kubernetes workload kinds
+// to their Juju deployment types.
+var workloadKinds = map[string]caas.DeploymentType{
+	"deployment":  caas.DeploymentStateless,
+	"statefulset": caas.DeploymentStateful,
+}
+
+// NewAdoptWorkloadCommand returns a command which adopts an existing
+// k8s workload into a Juju application.
+func NewAdoptWorkloadCommand() modelcmd.ModelCommand {
+	cmd := &adoptWorkloadCommand{}
+	cmd.newAPIFunc = func() (adoptWorkloadAPI, error) {
+		root, err := cmd.NewAPIRoot()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return &adoptWorkloadClient{
+			Client:      application.NewClient(root),
+			charmClient: root.Client(),
+		}, nil
+	}
+	return modelcmd.Wrap(cmd)
+}
+
+// adoptWorkloadCommand is responsible for adopting an existing
+// k8s workload into a Juju application.
+type adoptWorkloadCommand struct {
+	modelcmd.ModelCommandBase
+	modelcmd.CAASOnlyCommand
+
+	newAPIFunc      func() (adoptWorkloadAPI, error)
+	deploymentType  caas.DeploymentType
+	workloadName    string
+	applicationName string
+}
+
+const adoptWorkloadDoc = `
+Adopt an existing Kubernetes deployment or stateful set, which was not
+created by Juju, into a new Juju application.
+
+A placeholder charm is generated and deployed as the application, and the
+workload is labelled so that its pods are tracked as units of that
+application. Updating the workload's pod template labels causes the cluster
+to roll the workload's pods.
+
+The placeholder charm has no hooks; it can later be replaced by a charm
+which manages the workload using "juju upgrade-charm".
+
+Examples:
+
+    juju adopt-k8s-workload deployment/mariadb mariadb
+    juju adopt-k8s-workload statefulset/redis cache
+
+See also:
+    upgrade-charm
+    remove-application
+`
+
+// Info implements cmd.Command.
+func (c *adoptWorkloadCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "adopt-k8s-workload",
+		Args:    "<kind>/<workload> <application>",
+		Purpose: "Adopt an existing k8s workload into a Juju application.",
+		Doc:     adoptWorkloadDoc,
+	})
+}
+
+// Init implements cmd.Command.
+func (c *adoptWorkloadCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.Errorf("no workload specified")
+	}
+	parts := strings.SplitN(args[0], "/", 2)
+	if len(parts) != 2 || parts[1] == "" {
+		return errors.Errorf("invalid workload %q, expected <kind>/<workload>", args[0])
+	}
+	deploymentType, ok := workloadKinds[strings.ToLower(parts[0])]
+	if !ok {
+		return errors.Errorf("workload kind %q not valid, expected deployment or statefulset", parts[0])
+	}
+	c.deploymentType = deploymentType
+	c.workloadName = parts[1]
+	if len(args) == 1 {
+		return errors.Errorf("no application specified")
+	}
+	c.applicationName = args[1]
+	if !names.IsValidApplication(c.applicationName) {
+		return errors.Errorf("invalid application name %q", c.applicationName)
+	}
+	return cmd.CheckEmpty(args[2:])
+}
+
+type adoptWorkloadAPI interface {
+	Close() error
+	BestAPIVersion() int
+	AddLocalCharm(*charm.URL, charm.Charm, bool) (*charm.URL, error)
+	Deploy(application.DeployArgs) error
+	AdoptWorkload(application.AdoptWorkloadParams) error
+	DestroyApplications(application.DestroyApplicationsParams) ([]params.DestroyApplicationResult, error)
+}
+
+// adoptWorkloadClient combines the application facade client with the
+// client facade used to upload the placeholder charm.
+type adoptWorkloadClient struct {
+	*application.Client
+	charmClient *api.Client
+}
+
+// AddLocalCharm is part of the adoptWorkloadAPI interface.
+func (c *adoptWorkloadClient) AddLocalCharm(curl *charm.URL, ch charm.Charm, force bool) (*charm.URL, error) {
+	return c.charmClient.AddLocalCharm(curl, ch, force)
+}
+
+// Run implements cmd.Command.
+func (c *adoptWorkloadCommand) Run(ctx *cmd.Context) error {
+	client, err := c.newAPIFunc()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if client.BestAPIVersion() < 12 {
+		return errors.New("adopting workloads is not supported by this controller")
+	}
+
+	dir, err := ioutil.TempDir("", "placeholder-charm")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer os.RemoveAll(dir)
+	ch, err := writePlaceholderCharm(dir, c.applicationName, c.workloadName)
+	if err != nil {
+		return errors.Annotate(err, "creating placeholder charm")
+	}
+	curl := &charm.URL{
+		Schema:   "local",
+		Name:     ch.Meta().Name,
+		Series:   "kubernetes",
+		Revision: ch.Revision(),
+	}
+	if curl, err = client.AddLocalCharm(curl, ch, false); err != nil {
+		return errors.Trace(err)
+	}
+	ctx.Infof("Deploying placeholder charm %q.", curl.String())
+	err = client.Deploy(application.DeployArgs{
+		CharmID:         charmstore.CharmID{URL: curl},
+		ApplicationName: c.applicationName,
+		Series:          curl.Series,
+	})
+	if err != nil {
+		return block.ProcessBlockedError(errors.Annotatef(err, "could not deploy application %q", c.applicationName), block.BlockChange)
+	}
+	err = client.AdoptWorkload(application.AdoptWorkloadParams{
+		ApplicationName: c.applicationName,
+		DeploymentType:  string(c.deploymentType),
+		WorkloadName:    c.workloadName,
+	})
+	if err != nil {
+		err = block.ProcessBlockedError(errors.Annotatef(err,
+			"could not adopt workload %q", c.workloadName,
+		), block.BlockChange)
+		// Don't leave the placeholder application behind.
+		ctx.Infof("Removing application %q.", c.applicationName)
+		if removeErr := c.removeApplication(client); removeErr != nil {
+			return errors.Errorf(
+				"%v\napplication %q was deployed but could not be removed: %v\nremove it with \"juju remove-application %s\"",
+				err, c.applicationName, removeErr, c.applicationName,
+			)
+		}
+		return err
+	}
+	ctx.Infof("Workload %q adopted by application %q.", c.workloadName, c.applicationName)
+	return nil
+}
+
+// removeApplication removes the placeholder application deployed for
+// a workload that could not be adopted.
+func (c *adoptWorkloadCommand) removeApplication(client adoptWorkloadAPI) error {
+	results, err := client.DestroyApplications(application.DestroyApplicationsParams{
+		Applications: []string{c.applicationName},
+	})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(results) != 1 {
+		return errors.Errorf("expected 1 result, got %d", len(results))
+	}
+	if results[0].Error != nil {
+		return results[0].Error
+	}
+	return nil
+}
+
+// writePlaceholderCharm writes a charm with no hooks, named after the
+// application, to the specified directory and returns it.
+func writePlaceholderCharm(dir, appName, workloadName string) (*charm.CharmDir, error) {
+	meta := map[string]interface{}{
+		"name":             appName,
+		"summary":          fmt.Sprintf("Placeholder for the adopted workload %q", workloadName),
+		"description":      "Tracks the pods of an existing workload as units until it is managed by a charm.",
+		"series":           []string{"kubernetes"},
+		"min-juju-version": placeholderCharmMinJujuVersion,
+	}
+	data, err := yaml.Marshal(meta)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "metadata.yaml"), data, 0644); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return charm.ReadCharmDir(dir)
+}
@@ -0,0 +1,177 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application
+
+import (
+	"strings"
+
+	"github.com/juju/charm/v7"
+	"github.com/juju/cmd"
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api/application"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/model"
+	"github.com/juju/juju/jujuclient"
+	"github.com/juju/juju/jujuclient/jujuclienttesting"
+)
+
+type AdoptWorkloadSuite struct {
+	testing.IsolationSuite
+
+	mockAPI *mockAdoptWorkloadAPI
+}
+
+var _ = gc.Suite(&AdoptWorkloadSuite{})
+
+type mockAdoptWorkloadAPI struct {
+	*testing.Stub
+	version int
+	meta    *charm.Meta
+}
+
+func (s *mockAdoptWorkloadAPI) Close() error {
+	s.MethodCall(s, "Close")
+	return s.NextErr()
+}
+
+func (s *mockAdoptWorkloadAPI) BestAPIVersion() int {
+	return s.version
+}
+
+func (s *mockAdoptWorkloadAPI) AddLocalCharm(curl *charm.URL, ch charm.Charm, force bool) (*charm.URL, error) {
+	s.MethodCall(s, "AddLocalCharm", curl.String(), force)
+	s.meta = ch.Meta()
+	return curl.WithRevision(1), s.NextErr()
+}
+
+func (s *mockAdoptWorkloadAPI) Deploy(args application.DeployArgs) error {
+	s.MethodCall(s, "Deploy", args.CharmID.URL.String(), args.ApplicationName, args.Series, args.NumUnits)
+	return s.NextErr()
+}
+
+func (s *mockAdoptWorkloadAPI) AdoptWorkload(args application.AdoptWorkloadParams) error {
+	s.MethodCall(s, "AdoptWorkload", args)
+	return s.NextErr()
+}
+
+func (s *mockAdoptWorkloadAPI) DestroyApplications(args application.DestroyApplicationsParams) ([]params.DestroyApplicationResult, error) {
+	s.MethodCall(s, "DestroyApplications", args.Applications)
+	if err := s.NextErr(); err != nil {
+		return nil, err
+	}
+	return []params.DestroyApplicationResult{{}}, nil
+}
+
+func (s *AdoptWorkloadSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.mockAPI = &mockAdoptWorkloadAPI{Stub: &testing.Stub{}, version: 12}
+}
+
+func (s *AdoptWorkloadSuite) runAdoptWorkload(c *gc.C, args ...string) (*cmd.Context, error) {
+	store := jujuclienttesting.MinimalStore()
+	store.Models["arthur"] = &jujuclient.ControllerModels{
+		CurrentModel: "king/sword",
+		Models: map[string]jujuclient.ModelDetails{"king/sword": {
+			ModelType: model.CAAS,
+		}},
+	}
+	return cmdtesting.RunCommand(c, NewAdoptWorkloadCommandForTest(s.mockAPI, store), args...)
+}
+
+func (s *AdoptWorkloadSuite) TestAdoptWorkload(c *gc.C) {
+	ctx, err := s.runAdoptWorkload(c, "statefulset/mariadb", "db")
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.mockAPI.CheckCalls(c, []testing.StubCall{
+		{"AddLocalCharm", []interface{}{"local:kubernetes/db-0", false}},
+		{"Deploy", []interface{}{"local:kubernetes/db-1", "db", "kubernetes", 0}},
+		{"AdoptWorkload", []interface{}{application.AdoptWorkloadParams{
+			ApplicationName: "db",
+			DeploymentType:  "stateful",
+			WorkloadName:    "mariadb",
+		}}},
+		{"Close", nil},
+	})
+	c.Assert(s.mockAPI.meta.Name, gc.Equals, "db")
+	c.Assert(s.mockAPI.meta.Series, jc.DeepEquals, []string{"kubernetes"})
+	c.Assert(s.mockAPI.meta.MinJujuVersion.String(), gc.Equals, "2.8.0")
+
+	stderr := cmdtesting.Stderr(ctx)
+	c.Assert(stderr, jc.Contains, `Workload "mariadb" adopted by application "db".`)
+}
+
+func (s *AdoptWorkloadSuite) TestAdoptWorkloadDeployment(c *gc.C) {
+	_, err := s.runAdoptWorkload(c, "Deployment/mariadb", "db")
+	c.Assert(err, jc.ErrorIsNil)
+	s.mockAPI.CheckCall(c, 2, "AdoptWorkload", application.AdoptWorkloadParams{
+		ApplicationName: "db",
+		DeploymentType:  "stateless",
+		WorkloadName:    "mariadb",
+	})
+}
+
+func (s *AdoptWorkloadSuite) TestAdoptWorkloadFailed(c *gc.C) {
+	s.mockAPI.SetErrors(nil, nil, errors.NotFoundf("deployment %q", "mariadb"))
+	_, err := s.runAdoptWorkload(c, "deployment/mariadb", "db")
+	c.Assert(err, gc.ErrorMatches, `could not adopt workload "mariadb": deployment "mariadb" not found`)
+	s.mockAPI.CheckCallNames(c, "AddLocalCharm", "Deploy", "AdoptWorkload", "DestroyApplications", "Close")
+	s.mockAPI.CheckCall(c, 3, "DestroyApplications", []string{"db"})
+}
+
+func (s *AdoptWorkloadSuite) TestAdoptWorkloadFailedRemoveFailed(c *gc.C) {
+	s.mockAPI.SetErrors(nil, nil, errors.NotFoundf("deployment %q", "mariadb"), errors.New("boom"))
+	_, err := s.runAdoptWorkload(c, "deployment/mariadb", "db")
+	c.Assert(err, gc.ErrorMatches, `could not adopt workload "mariadb": deployment "mariadb" not found
+application "db" was deployed but could not be removed: boom
+remove it with "juju remove-application db"`)
+}
+
+func (s *AdoptWorkloadSuite) TestAdoptWorkloadBlocked(c *gc.C) {
+	s.mockAPI.SetErrors(nil, &params.Error{Code: params.CodeOperationBlocked, Message: "nope"})
+	_, err := s.runAdoptWorkload(c, "deployment/mariadb", "db")
+	c.Assert(err.Error(), jc.Contains, `could not deploy application "db": nope`)
+	c.Assert(err.Error(), jc.Contains, `All operations that change model have been disabled for the current model.`)
+	s.mockAPI.CheckCallNames(c, "AddLocalCharm", "Deploy", "Close")
+}
+
+func (s *AdoptWorkloadSuite) TestAdoptWorkloadNotSupported(c *gc.C) {
+	s.mockAPI.version = 11
+	_, err := s.runAdoptWorkload(c, "deployment/mariadb", "db")
+	c.Assert(err, gc.ErrorMatches, "adopting workloads is not supported by this controller")
+	s.mockAPI.CheckCallNames(c, "Close")
+}
+
+func (s *AdoptWorkloadSuite) TestInvalidArgs(c *gc.C) {
+	for i, test := range []struct {
+		args []string
+		err  string
+	}{{
+		args: nil,
+		err:  "no workload specified",
+	}, {
+		args: []string{"mariadb"},
+		err:  `invalid workload "mariadb", expected <kind>/<workload>`,
+	}, {
+		args: []string{"daemonset/mariadb", "db"},
+		err:  `workload kind "daemonset" not valid, expected deployment or statefulset`,
+	}, {
+		args: []string{"deployment/mariadb"},
+		err:  "no application specified",
+	}, {
+		args: []string{"deployment/mariadb", "_db"},
+		err:  `invalid application name "_db"`,
+	}, {
+		args: []string{"deployment/mariadb", "db", "extra"},
+		err:  `unrecognized args: \["extra"\]`,
+	}} {
+		c.Logf("test %d: %s", i, strings.Join(test.args, " "))
+		_, err := s.runAdoptWorkload(c, test.args...)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
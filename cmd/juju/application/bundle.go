@@ -92,9 +92,10 @@ func verifyBundle(data *charm.BundleData, bundleDir string) error {
 type bundleDeploySpec struct {
 	ctx *cmd.Context
 
-	dryRun bool
-	force  bool
-	trust  bool
+	dryRun   bool
+	rollback bool
+	force    bool
+	trust    bool
 
 	bundleDataSource  charm.BundleDataSource
 	bundleDir         string
@@ -146,8 +147,8 @@ func composeAndVerifyBundle(base charm.BundleDataSource, pathToOverlays []string
 }
 
 // deployBundle deploys the given bundle data using the given API client and
-// charm store client. The deployment is not transactional, and its progress is
-// notified using the given deployment logger.
+// charm store client. The deployment is not transactional unless rollback is
+// requested, and its progress is notified using the given deployment logger.
 //
 // Note: deployBundle expects that spec.BundleData points to a verified bundle
 // that has all required external overlays applied.
@@ -171,9 +172,10 @@ func deployBundle(bundleData *charm.BundleData, spec bundleDeploySpec) (map[*cha
 
 // bundleHandler provides helpers and the state required to deploy a bundle.
 type bundleHandler struct {
-	dryRun bool
-	force  bool
-	trust  bool
+	dryRun   bool
+	rollback bool
+	force    bool
+	trust    bool
 
 	clock jujuclock.Clock
 
@@ -265,6 +267,11 @@ type bundleHandler struct {
 	// accountUser holds the user of the account associated with the
 	// current controller.
 	accountUser string
+
+	// applied holds the steps needed to reverse the changes applied so
+	// far, in the order the changes were applied. It is only recorded
+	// when rollback is requested.
+	applied []rollbackStep
 }
 
 func makeBundleHandler(bundleData *charm.BundleData, spec bundleDeploySpec) *bundleHandler {
@@ -277,6 +284,7 @@ func makeBundleHandler(bundleData *charm.BundleData, spec bundleDeploySpec) *bun
 		clock: jujuclock.WallClock,
 
 		dryRun:               spec.dryRun,
+		rollback:             spec.rollback,
 		force:                spec.force,
 		trust:                spec.trust,
 		bundleDir:            spec.bundleDir,
@@ -449,8 +457,16 @@ func (h *bundleHandler) handleChanges() error {
 		fmt.Fprintf(h.ctx.Stdout, "Executing changes:\n")
 	}
 
+	interrupted := h.notifyInterrupt()
+	defer h.stopInterrupt(interrupted)
+
 	// Deploy the bundle.
 	for i, change := range h.changes {
+		select {
+		case <-interrupted:
+			return errors.Trace(h.rollbackChanges(errors.New("bundle deployment interrupted")))
+		default:
+		}
 		fmt.Fprintf(h.ctx.Stdout, "- %s\n", change.Description())
 		logger.Tracef("%d: change %s", i, pretty.Sprint(change))
 		switch change := change.(type) {
@@ -483,17 +499,17 @@ func (h *bundleHandler) handleChanges() error {
 		case *bundlechanges.GrantOfferAccessChange:
 			err = h.grantOfferAccess(change)
 		default:
-			return errors.Errorf("unknown change type: %T", change)
+			err = errors.Errorf("unknown change type: %T", change)
 		}
 		if err != nil {
-			return errors.Trace(err)
+			return errors.Trace(h.rollbackChanges(err))
 		}
 	}
 
-	if !h.dryRun {
-		h.ctx.Infof("Deploy of bundle completed.")
+	if h.dryRun {
+		return errors.Trace(h.precheckChanges())
 	}
-
+	h.ctx.Infof("Deploy of bundle completed.")
 	return nil
 }
 
@@ -740,6 +756,9 @@ func (h *bundleHandler) addApplication(change *bundlechanges.AddApplicationChang
 	}); err != nil {
 		return errors.Annotatef(err, "cannot deploy application %q", p.Application)
 	}
+	h.recordUndo("remove application "+p.Application, func() error {
+		return h.destroyApplication(p.Application)
+	})
 	h.writeAddedResources(resNames2IDs)
 
 	return nil
@@ -865,6 +884,9 @@ func (h *bundleHandler) addMachine(change *bundlechanges.AddMachineChange) error
 		logger.Debugf("created %s container in machine %s for holding %s", machine, machineParams.ParentId, deployedApps())
 	}
 	h.results[change.Id()] = machine
+	h.recordUndo("remove machine "+machine, func() error {
+		return h.api.DestroyMachinesWithParams(true, false, machine)
+	})
 	return nil
 }
 
@@ -887,6 +909,9 @@ func (h *bundleHandler) addRelation(change *bundlechanges.AddRelationChange) err
 		return errors.Annotatef(err, "cannot add relation between %q and %q", ep1, ep2)
 
 	}
+	h.recordUndo(fmt.Sprintf("remove relation %s %s", ep1, ep2), func() error {
+		return h.api.DestroyRelation(nil, nil, ep1, ep2)
+	})
 	return nil
 }
 
@@ -949,6 +974,9 @@ func (h *bundleHandler) addUnit(change *bundlechanges.AddUnitChange) error {
 	// incomplete unit status. That's ok as the missing info is provided later
 	// when it is required.
 	h.unitStatus[unit] = targetMachine
+	h.recordUndo("remove unit "+unit, func() error {
+		return h.destroyUnit(unit, targetMachine == "")
+	})
 	return nil
 }
 
@@ -1120,6 +1148,10 @@ func (h *bundleHandler) createOffer(change *bundlechanges.CreateOfferChange) err
 	if err != nil {
 		return errors.Annotatef(err, "cannot create offer %s", p.OfferName)
 	}
+	offerURL := fmt.Sprintf("%s.%s", h.targetModelName, p.OfferName)
+	h.recordUndo("remove offer "+offerURL, func() error {
+		return h.api.DestroyOffers(false, offerURL)
+	})
 	return nil
 }
 
@@ -1192,6 +1224,9 @@ func (h *bundleHandler) consumeOffer(change *bundlechanges.ConsumeOfferChange) e
 		return errors.Trace(err)
 	}
 	h.results[change.Id()] = localName
+	h.recordUndo("remove consumed offer "+localName, func() error {
+		return h.destroyConsumedApplication(localName)
+	})
 	h.ctx.Infof("Added %s as %s", url.Path(), localName)
 	return nil
 }
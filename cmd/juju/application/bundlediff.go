@@ -18,6 +18,7 @@ import (
 	"github.com/juju/juju/api/annotations"
 	"github.com/juju/juju/api/application"
 	"github.com/juju/juju/api/base"
+	apibundle "github.com/juju/juju/api/bundle"
 	"github.com/juju/juju/api/modelconfig"
 	"github.com/juju/juju/apiserver/params"
 	jujucmd "github.com/juju/juju/cmd"
@@ -36,6 +37,13 @@ existing is always assumed, so it doesn't need to be specified.
 Config values for comparison are always source from the "current" model
 generation.
 
+When --from-model and --to-model are specified, the bundles exported from
+the two models are compared instead, which is useful for checking what a
+promotion between environments would change. Both models are exported on
+the controller. If a bundle is also given, it is used as the common base
+of a three-way comparison, and each difference reports the base, from and
+to values. The differences between models can be output as yaml or json.
+
 Examples:
     juju diff-bundle localbundle.yaml
     juju diff-bundle canonical-kubernetes
@@ -43,6 +51,9 @@ Examples:
     juju diff-bundle mongodb-cluster --channel beta
     juju diff-bundle canonical-kubernetes --overlay local-config.yaml --overlay extra.yaml
     juju diff-bundle localbundle.yaml --map-machines 3=4
+    juju diff-bundle --from-model staging --to-model prod
+    juju diff-bundle --from-model staging --to-model prod --format json
+    juju diff-bundle release.yaml --from-model staging --to-model prod
 
 See also:
     deploy
@@ -65,6 +76,10 @@ type bundleDiffCommand struct {
 	bundleMachines map[string]string
 	machineMap     string
 
+	fromModel string
+	toModel   string
+	out       cmd.Output
+
 	// These are set in tests to enable mocking out the API and the
 	// charm store.
 	_apiRoot    base.APICallCloser
@@ -81,7 +96,7 @@ func (c *bundleDiffCommand) AllowInterspersedFlags() bool { return true }
 func (c *bundleDiffCommand) Info() *cmd.Info {
 	return jujucmd.Info(&cmd.Info{
 		Name:    "diff-bundle",
-		Args:    "<bundle file or name>",
+		Args:    "[<bundle file or name>]",
 		Purpose: "Compare a bundle with a model and report any differences.",
 		Doc:     bundleDiffDoc,
 	})
@@ -94,10 +109,29 @@ func (c *bundleDiffCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.Var(cmd.NewAppendStringsValue(&c.bundleOverlays), "overlay", "Bundles to overlay on the primary bundle, applied in order")
 	f.StringVar(&c.machineMap, "map-machines", "", "Indicates how existing machines correspond to bundle machines")
 	f.BoolVar(&c.annotations, "annotations", false, "Include differences in annotations")
+	f.StringVar(&c.fromModel, "from-model", "", "Model to export as the source of a model comparison")
+	f.StringVar(&c.toModel, "to-model", "", "Model to export as the target of a model comparison")
+	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
+		"yaml": cmd.FormatYaml,
+		"json": cmd.FormatJson,
+	})
 }
 
 // Init is part of cmd.Command.
 func (c *bundleDiffCommand) Init(args []string) error {
+	if (c.fromModel == "") != (c.toModel == "") {
+		return errors.New("--from-model and --to-model must be specified together")
+	}
+	if c.compareModels() {
+		if c.machineMap != "" {
+			return errors.New("--map-machines cannot be used when comparing models")
+		}
+		if len(args) > 0 {
+			c.bundle = args[0]
+			args = args[1:]
+		}
+		return cmd.CheckEmpty(args)
+	}
 	if len(args) < 1 {
 		return errors.New("no bundle specified")
 	}
@@ -120,13 +154,11 @@ func (c *bundleDiffCommand) Run(ctx *cmd.Context) error {
 	}
 	defer apiRoot.Close()
 
-	// Load up the bundle data, with includes and overlays.
-	baseSrc, err := c.bundleDataSource(ctx)
-	if err != nil {
-		return errors.Trace(err)
+	if c.compareModels() {
+		return c.diffModels(ctx, apiRoot)
 	}
 
-	bundle, err := composeAndVerifyBundle(baseSrc, c.bundleOverlays)
+	bundle, err := c.readBundle(ctx)
 	if err != nil {
 		return errors.Trace(err)
 	}
@@ -147,14 +179,49 @@ func (c *bundleDiffCommand) Run(ctx *cmd.Context) error {
 	if err != nil {
 		return errors.Trace(err)
 	}
+	return c.out.Write(ctx, diff)
+}
+
+// compareModels reports whether the bundles exported from two models
+// are to be compared, rather than a bundle with the current model.
+func (c *bundleDiffCommand) compareModels() bool {
+	return c.fromModel != "" && c.toModel != ""
+}
 
-	encoder := yaml.NewEncoder(ctx.Stdout)
-	defer encoder.Close()
-	err = encoder.Encode(diff)
+// readBundle loads the bundle data, with includes and overlays.
+func (c *bundleDiffCommand) readBundle(ctx *cmd.Context) (*charm.BundleData, error) {
+	baseSrc, err := c.bundleDataSource(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	bundle, err := composeAndVerifyBundle(baseSrc, c.bundleOverlays)
+	return bundle, errors.Trace(err)
+}
+
+// diffModels compares the bundles exported from the from and to
+// models, using the specified bundle (if any) as the common base.
+func (c *bundleDiffCommand) diffModels(ctx *cmd.Context, apiRoot base.APICallCloser) error {
+	uuids, err := c.ModelUUIDs([]string{c.fromModel, c.toModel})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	var baseYAML string
+	if c.bundle != "" {
+		bundle, err := c.readBundle(ctx)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		data, err := yaml.Marshal(bundle)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		baseYAML = string(data)
+	}
+	result, err := apibundle.NewClient(apiRoot).DiffModels(uuids[0], uuids[1], baseYAML, c.annotations)
 	if err != nil {
 		return errors.Trace(err)
 	}
-	return nil
+	return c.out.Write(ctx, formatModelsDiff(result))
 }
 
 func (c *bundleDiffCommand) newAPIRoot() (base.APICallCloser, error) {
@@ -53,6 +53,14 @@ func (s *diffSuite) runDiffBundle(c *gc.C, args ...string) (*cmd.Context, error)
 			ModelType: model.IAAS,
 		}},
 	}
+	store.Models["arthur"].Models["king/staging"] = jujuclient.ModelDetails{
+		ModelUUID: testing.ModelTag.Id(),
+		ModelType: model.IAAS,
+	}
+	store.Models["arthur"].Models["king/prod"] = jujuclient.ModelDetails{
+		ModelUUID: "f47ac10b-58cc-4372-a567-0e02b2c3d479",
+		ModelType: model.IAAS,
+	}
 	command := application.NewBundleDiffCommandForTest(s.apiRoot, s.charmStore, store)
 	return cmdtesting.RunCommandInDir(c, command, args, s.dir)
 }
@@ -261,6 +269,125 @@ machines:
 `[1:])
 }
 
+func (s *diffSuite) TestModelsMustBeSpecifiedTogether(c *gc.C) {
+	_, err := s.runDiffBundle(c, "--from-model", "staging")
+	c.Assert(err, gc.ErrorMatches, "--from-model and --to-model must be specified together")
+}
+
+func (s *diffSuite) TestModelsWithMachineMap(c *gc.C) {
+	_, err := s.runDiffBundle(c, "--from-model", "staging", "--to-model", "prod", "--map-machines", "1=2")
+	c.Assert(err, gc.ErrorMatches, "--map-machines cannot be used when comparing models")
+}
+
+func (s *diffSuite) setModelsDiffResponse() {
+	s.apiRoot.responses["Bundle.DiffModels"] = params.BundleDiff{
+		Applications: map[string]params.ApplicationBundleDiff{
+			"grafana": {Missing: []string{"to"}},
+			"prometheus": {
+				NumUnits: &params.BundleDiffValue{From: int64(1), To: int64(3)},
+				Options: map[string]params.BundleDiffValue{
+					"ontology": {Base: "anselm", From: "kant", To: "anselm"},
+				},
+			},
+		},
+		Relations: []params.RelationBundleDiff{{
+			Endpoints: []string{"grafana:source", "prometheus:grafana-source"},
+			Missing:   []string{"to"},
+		}},
+	}
+}
+
+func (s *diffSuite) TestModels(c *gc.C) {
+	s.setModelsDiffResponse()
+	ctx, err := s.runDiffBundle(c, "--from-model", "staging", "--to-model", "prod")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, `
+applications:
+  grafana:
+    missing:
+    - to
+  prometheus:
+    num-units:
+      from: 1
+      to: 3
+    options:
+      ontology:
+        base: anselm
+        from: kant
+        to: anselm
+relations:
+- endpoints:
+  - grafana:source
+  - prometheus:grafana-source
+  missing:
+  - to
+`[1:])
+	s.apiRoot.stub.CheckCall(c, 1, "Bundle.DiffModels", 42, params.BundleDiffModelsParams{
+		FromModelTag: testing.ModelTag.String(),
+		ToModelTag:   "model-f47ac10b-58cc-4372-a567-0e02b2c3d479",
+	})
+}
+
+func (s *diffSuite) TestModelsJSON(c *gc.C) {
+	s.setModelsDiffResponse()
+	ctx, err := s.runDiffBundle(c, "--from-model", "staging", "--to-model", "prod", "--format", "json")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, `{"applications":{"grafana":{"missing":["to"]},"prometheus":{"num-units":{"from":1,"to":3},"options":{"ontology":{"base":"anselm","from":"kant","to":"anselm"}}}},"relations":[{"endpoints":["grafana:source","prometheus:grafana-source"],"missing":["to"]}]}
+`)
+}
+
+func (s *diffSuite) TestModelsZeroValues(c *gc.C) {
+	s.apiRoot.responses["Bundle.DiffModels"] = params.BundleDiff{
+		Applications: map[string]params.ApplicationBundleDiff{
+			"prometheus": {
+				NumUnits: &params.BundleDiffValue{From: int64(0), To: int64(3)},
+				Expose:   &params.BundleDiffValue{From: false, To: true},
+				Options: map[string]params.BundleDiffValue{
+					"verbose": {To: false},
+				},
+			},
+		},
+	}
+	ctx, err := s.runDiffBundle(c, "--from-model", "staging", "--to-model", "prod")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, `
+applications:
+  prometheus:
+    num-units:
+      from: 0
+      to: 3
+    expose:
+      from: false
+      to: true
+    options:
+      verbose:
+        to: false
+`[1:])
+
+	ctx, err = s.runDiffBundle(c, "--from-model", "staging", "--to-model", "prod", "--format", "json")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, `{"applications":{"prometheus":{"num-units":{"from":0,"to":3},"expose":{"from":false,"to":true},"options":{"verbose":{"to":false}}}}}
+`)
+}
+
+func (s *diffSuite) TestModelsWithBaseBundle(c *gc.C) {
+	s.setModelsDiffResponse()
+	_, err := s.runDiffBundle(c, s.writeLocalBundle(c, testBundle), "--from-model", "staging", "--to-model", "prod", "--annotations")
+	c.Assert(err, jc.ErrorIsNil)
+	calls := s.apiRoot.stub.Calls()
+	c.Assert(calls, gc.Not(gc.HasLen), 0)
+	var args params.BundleDiffModelsParams
+	for _, call := range calls {
+		if call.FuncName == "Bundle.DiffModels" {
+			args = call.Args[1].(params.BundleDiffModelsParams)
+		}
+	}
+	c.Assert(args.IncludeAnnotations, jc.IsTrue)
+	data, err := charm.ReadBundleData(strings.NewReader(args.BaseBundleDataYAML))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(data.Applications["prometheus"].Options, jc.DeepEquals, map[string]interface{}{"ontology": "anselm"})
+}
+
 func (s *diffSuite) writeLocalBundle(c *gc.C, content string) string {
 	return s.writeFile(c, "bundle.yaml", content)
 }
@@ -0,0 +1,134 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application
+
+import (
+	"github.com/juju/juju/apiserver/params"
+)
+
+// modelsDiff is the structured output of diff-bundle when comparing
+// the bundles exported from two models.
+type modelsDiff struct {
+	Applications map[string]*applicationModelsDiff `yaml:"applications,omitempty" json:"applications,omitempty"`
+	Machines     map[string]*machineModelsDiff     `yaml:"machines,omitempty" json:"machines,omitempty"`
+	Saas         map[string]*saasModelsDiff        `yaml:"saas,omitempty" json:"saas,omitempty"`
+	Relations    []relationModelsDiff              `yaml:"relations,omitempty" json:"relations,omitempty"`
+}
+
+// modelsDiffValue holds the differing values of an attribute. Sides on
+// which the value is unset (nil) are omitted, while zero values such as
+// false and 0 are shown.
+type modelsDiffValue struct {
+	Base interface{} `yaml:"base,omitempty" json:"base,omitempty"`
+	From interface{} `yaml:"from,omitempty" json:"from,omitempty"`
+	To   interface{} `yaml:"to,omitempty" json:"to,omitempty"`
+}
+
+type applicationModelsDiff struct {
+	Missing     []string                   `yaml:"missing,omitempty" json:"missing,omitempty"`
+	Charm       *modelsDiffValue           `yaml:"charm,omitempty" json:"charm,omitempty"`
+	Channel     *modelsDiffValue           `yaml:"channel,omitempty" json:"channel,omitempty"`
+	Series      *modelsDiffValue           `yaml:"series,omitempty" json:"series,omitempty"`
+	NumUnits    *modelsDiffValue           `yaml:"num-units,omitempty" json:"num-units,omitempty"`
+	Scale       *modelsDiffValue           `yaml:"scale,omitempty" json:"scale,omitempty"`
+	Placement   *modelsDiffValue           `yaml:"placement,omitempty" json:"placement,omitempty"`
+	Expose      *modelsDiffValue           `yaml:"expose,omitempty" json:"expose,omitempty"`
+	Trust       *modelsDiffValue           `yaml:"trust,omitempty" json:"trust,omitempty"`
+	Constraints *modelsDiffValue           `yaml:"constraints,omitempty" json:"constraints,omitempty"`
+	Options     map[string]modelsDiffValue `yaml:"options,omitempty" json:"options,omitempty"`
+	Annotations map[string]modelsDiffValue `yaml:"annotations,omitempty" json:"annotations,omitempty"`
+	Bindings    map[string]modelsDiffValue `yaml:"bindings,omitempty" json:"bindings,omitempty"`
+	Offers      map[string]modelsDiffValue `yaml:"offers,omitempty" json:"offers,omitempty"`
+}
+
+type machineModelsDiff struct {
+	Missing     []string                   `yaml:"missing,omitempty" json:"missing,omitempty"`
+	Series      *modelsDiffValue           `yaml:"series,omitempty" json:"series,omitempty"`
+	Constraints *modelsDiffValue           `yaml:"constraints,omitempty" json:"constraints,omitempty"`
+	Annotations map[string]modelsDiffValue `yaml:"annotations,omitempty" json:"annotations,omitempty"`
+}
+
+type saasModelsDiff struct {
+	Missing []string         `yaml:"missing,omitempty" json:"missing,omitempty"`
+	URL     *modelsDiffValue `yaml:"url,omitempty" json:"url,omitempty"`
+}
+
+type relationModelsDiff struct {
+	Endpoints []string `yaml:"endpoints" json:"endpoints"`
+	Missing   []string `yaml:"missing" json:"missing"`
+}
+
+func formatModelsDiff(diff params.BundleDiff) modelsDiff {
+	var result modelsDiff
+	if len(diff.Applications) > 0 {
+		result.Applications = make(map[string]*applicationModelsDiff)
+		for name, app := range diff.Applications {
+			result.Applications[name] = &applicationModelsDiff{
+				Missing:     app.Missing,
+				Charm:       formatDiffValue(app.Charm),
+				Channel:     formatDiffValue(app.Channel),
+				Series:      formatDiffValue(app.Series),
+				NumUnits:    formatDiffValue(app.NumUnits),
+				Scale:       formatDiffValue(app.Scale),
+				Placement:   formatDiffValue(app.Placement),
+				Expose:      formatDiffValue(app.Expose),
+				Trust:       formatDiffValue(app.Trust),
+				Constraints: formatDiffValue(app.Constraints),
+				Options:     formatDiffValues(app.Options),
+				Annotations: formatDiffValues(app.Annotations),
+				Bindings:    formatDiffValues(app.Bindings),
+				Offers:      formatDiffValues(app.Offers),
+			}
+		}
+	}
+	if len(diff.Machines) > 0 {
+		result.Machines = make(map[string]*machineModelsDiff)
+		for id, machine := range diff.Machines {
+			result.Machines[id] = &machineModelsDiff{
+				Missing:     machine.Missing,
+				Series:      formatDiffValue(machine.Series),
+				Constraints: formatDiffValue(machine.Constraints),
+				Annotations: formatDiffValues(machine.Annotations),
+			}
+		}
+	}
+	if len(diff.Saas) > 0 {
+		result.Saas = make(map[string]*saasModelsDiff)
+		for name, saas := range diff.Saas {
+			result.Saas[name] = &saasModelsDiff{
+				Missing: saas.Missing,
+				URL:     formatDiffValue(saas.URL),
+			}
+		}
+	}
+	for _, rel := range diff.Relations {
+		result.Relations = append(result.Relations, relationModelsDiff{
+			Endpoints: rel.Endpoints,
+			Missing:   rel.Missing,
+		})
+	}
+	return result
+}
+
+func formatDiffValue(value *params.BundleDiffValue) *modelsDiffValue {
+	if value == nil {
+		return nil
+	}
+	return &modelsDiffValue{
+		Base: value.Base,
+		From: value.From,
+		To:   value.To,
+	}
+}
+
+func formatDiffValues(values map[string]params.BundleDiffValue) map[string]modelsDiffValue {
+	if len(values) == 0 {
+		return nil
+	}
+	result := make(map[string]modelsDiffValue)
+	for key, value := range values {
+		result[key] = *formatDiffValue(&value)
+	}
+	return result
+}
@@ -0,0 +1,270 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/juju/bundlechanges"
+	"github.com/juju/collections/set"
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/constraints"
+	"github.com/juju/juju/core/instance"
+	"github.com/juju/juju/core/model"
+)
+
+// precheckChanges is used by a dry run to find, without changing the
+// model, the problems that would make applying the changes fail part
+// way through. Every machine that would be provisioned is prechecked by
+// the provider, and space bindings and constraints are checked against
+// the model's spaces. A summary of the resources that would be created
+// is also reported.
+func (h *bundleHandler) precheckChanges() error {
+	h.ctx.Infof("Resources to be created: %s", summariseChanges(h.changes))
+
+	problems, err := h.precheckSpaces()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	machineProblems, err := h.precheckMachines()
+	if errors.IsNotSupported(err) {
+		h.ctx.Warningf("this controller cannot precheck machines, so machine provisioning was not checked")
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	problems = append(problems, machineProblems...)
+	if len(problems) == 0 {
+		h.ctx.Infof("Precheck of bundle passed.")
+		return nil
+	}
+	h.ctx.Infof("Precheck of bundle found problems:")
+	for _, problem := range problems {
+		h.ctx.Infof("- %s", problem)
+	}
+	return errors.Errorf("bundle deployment would fail: %d problem(s) found", len(problems))
+}
+
+// summariseChanges returns a description of the resources that
+// applying the given changes would create.
+func summariseChanges(changes []bundlechanges.Change) string {
+	var charms, applications, machines, containers, units, relations, offers int
+	for _, change := range changes {
+		switch change := change.(type) {
+		case *bundlechanges.AddCharmChange:
+			charms++
+		case *bundlechanges.AddApplicationChange:
+			applications++
+		case *bundlechanges.AddMachineChange:
+			if change.Params.ContainerType == "" {
+				machines++
+			} else {
+				containers++
+			}
+		case *bundlechanges.AddUnitChange:
+			units++
+			if change.Params.To == "" {
+				// The unit is placed on a new machine.
+				machines++
+			}
+		case *bundlechanges.AddRelationChange:
+			relations++
+		case *bundlechanges.CreateOfferChange:
+			offers++
+		}
+	}
+	var summary []string
+	for _, count := range []struct {
+		n    int
+		noun string
+	}{
+		{charms, "charm"},
+		{applications, "application"},
+		{machines, "machine"},
+		{containers, "container"},
+		{units, "unit"},
+		{relations, "relation"},
+		{offers, "offer"},
+	} {
+		if count.n == 0 {
+			continue
+		}
+		noun := count.noun
+		if count.n != 1 {
+			noun += "s"
+		}
+		summary = append(summary, fmt.Sprintf("%d %s", count.n, noun))
+	}
+	if len(summary) == 0 {
+		return "none"
+	}
+	return strings.Join(summary, ", ")
+}
+
+// precheckSpaces checks that the spaces used by endpoint bindings and
+// constraints in the changes exist in the model.
+func (h *bundleHandler) precheckSpaces() ([]string, error) {
+	var problems []string
+	var knownSpaces set.Strings
+	checkSpace := func(change bundlechanges.Change, what, space string) error {
+		if knownSpaces == nil {
+			spaces, err := h.api.ListSpaces()
+			if err != nil {
+				return errors.Trace(err)
+			}
+			knownSpaces = set.NewStrings()
+			for _, space := range spaces {
+				knownSpaces.Add(space.Name)
+			}
+		}
+		if !knownSpaces.Contains(space) {
+			problems = append(problems, fmt.Sprintf("%s: %s uses unknown space %q", change.Description(), what, space))
+		}
+		return nil
+	}
+	checkConstraints := func(change bundlechanges.Change, value string) error {
+		cons, err := constraints.Parse(value)
+		if err != nil {
+			// This should never happen, as the bundle is already verified.
+			return errors.Trace(err)
+		}
+		for _, space := range cons.IncludeSpaces() {
+			if err := checkSpace(change, "constraint", space); err != nil {
+				return errors.Trace(err)
+			}
+		}
+		return nil
+	}
+
+	for _, change := range h.changes {
+		var err error
+		switch change := change.(type) {
+		case *bundlechanges.AddApplicationChange:
+			if err = checkConstraints(change, change.Params.Constraints); err != nil {
+				break
+			}
+			for _, endpoint := range set.NewStrings(mapKeys(change.Params.EndpointBindings)...).SortedValues() {
+				space := change.Params.EndpointBindings[endpoint]
+				if space == "" {
+					// The default space always exists.
+					continue
+				}
+				what := fmt.Sprintf("binding for endpoint %q", endpoint)
+				if endpoint == "" {
+					what = "default binding"
+				}
+				if err = checkSpace(change, what, space); err != nil {
+					break
+				}
+			}
+		case *bundlechanges.AddMachineChange:
+			err = checkConstraints(change, change.Params.Constraints)
+		}
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	return problems, nil
+}
+
+// precheckMachines asks the controller to precheck every machine the
+// changes would provision, returning a problem for each that would fail.
+func (h *bundleHandler) precheckMachines() ([]string, error) {
+	var (
+		machineParams []params.AddMachineParams
+		machineChange []bundlechanges.Change
+	)
+	addMachine := func(change bundlechanges.Change, p params.AddMachineParams) {
+		p.Jobs = []model.MachineJob{model.JobHostUnits}
+		machineParams = append(machineParams, p)
+		machineChange = append(machineChange, change)
+	}
+
+	applications := make(map[string]bundlechanges.AddApplicationParams)
+	for _, change := range h.changes {
+		switch change := change.(type) {
+		case *bundlechanges.AddApplicationChange:
+			applications["$"+change.Id()] = change.Params
+
+		case *bundlechanges.AddMachineChange:
+			p := change.Params
+			if strings.HasPrefix(p.ParentId, "$") {
+				// The container's host is new, and prechecked itself.
+				continue
+			}
+			cons, err := constraints.Parse(p.Constraints)
+			if err != nil {
+				// This should never happen, as the bundle is already verified.
+				return nil, errors.Annotate(err, "invalid constraints for machine")
+			}
+			machine := params.AddMachineParams{
+				Series:      p.Series,
+				Constraints: cons,
+				ParentId:    p.ParentId,
+			}
+			if ct := p.ContainerType; ct != "" {
+				// As for addMachine, lxc containers are deployed as lxd.
+				if ct == "lxc" {
+					ct = string(instance.LXD)
+				}
+				containerType, err := instance.ParseContainerType(ct)
+				if err != nil {
+					return nil, errors.Trace(err)
+				}
+				machine.ContainerType = containerType
+			}
+			addMachine(change, machine)
+
+		case *bundlechanges.AddUnitChange:
+			if change.Params.To != "" {
+				// The unit is placed on a machine that exists or is
+				// prechecked itself.
+				continue
+			}
+			var series, consValue string
+			if app, ok := applications[change.Params.Application]; ok {
+				series, consValue = app.Series, app.Constraints
+			} else if app := h.model.GetApplication(change.Params.Application); app != nil {
+				series, consValue = app.Series, app.Constraints
+			}
+			cons, err := constraints.Parse(consValue)
+			if err != nil {
+				return nil, errors.Annotate(err, "invalid constraints for application")
+			}
+			addMachine(change, params.AddMachineParams{
+				Series:      series,
+				Constraints: cons,
+			})
+		}
+	}
+	if len(machineParams) == 0 {
+		return nil, nil
+	}
+
+	results, err := h.api.PrecheckMachines(machineParams)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var problems []string
+	for i, result := range results {
+		change := machineChange[i]
+		if len(result.Unsupported) > 0 {
+			h.ctx.Warningf("%s: unsupported constraints: %s", change.Description(), strings.Join(result.Unsupported, ","))
+		}
+		if result.Error != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", change.Description(), result.Error))
+		}
+	}
+	return problems, nil
+}
+
+func mapKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	return keys
+}
@@ -0,0 +1,142 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application
+
+import (
+	"strings"
+
+	"github.com/juju/bundlechanges"
+	"github.com/juju/charm/v7"
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
+	jujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/constraints"
+	"github.com/juju/juju/core/model"
+)
+
+type bundlePrecheckSuite struct {
+	jujutesting.IsolationSuite
+}
+
+var _ = gc.Suite(&bundlePrecheckSuite{})
+
+const precheckBundle = `
+series: bionic
+applications:
+  mysql:
+    charm: cs:mysql-42
+    num_units: 1
+    constraints: instance-type=huge
+    bindings:
+      "": db
+      server: internal
+  wordpress:
+    charm: cs:wordpress-47
+    num_units: 1
+    to: ["0"]
+  haproxy:
+    charm: cs:haproxy-1
+    num_units: 1
+    to: ["lxd:0"]
+machines:
+  "0":
+    constraints: spaces=public
+relations:
+- [wordpress:db, mysql:server]
+`
+
+func (s *bundlePrecheckSuite) makeHandler(c *gc.C, api DeployAPI) *bundleHandler {
+	data, err := charm.ReadBundleData(strings.NewReader(precheckBundle))
+	c.Assert(err, jc.ErrorIsNil)
+	bundleModel := &bundlechanges.Model{}
+	changes, err := bundlechanges.FromData(bundlechanges.ChangesConfig{
+		Bundle: data,
+		Model:  bundleModel,
+		Logger: logger,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	return &bundleHandler{
+		dryRun:  true,
+		api:     api,
+		ctx:     cmdtesting.Context(c),
+		data:    data,
+		model:   bundleModel,
+		changes: changes,
+		results: make(map[string]string),
+	}
+}
+
+func precheckMachineParams() []params.AddMachineParams {
+	jobs := []model.MachineJob{model.JobHostUnits}
+	return []params.AddMachineParams{{
+		Series:      "bionic",
+		Constraints: constraints.MustParse("spaces=public"),
+		Jobs:        jobs,
+	}, {
+		Series:      "bionic",
+		Constraints: constraints.MustParse("instance-type=huge"),
+		Jobs:        jobs,
+	}}
+}
+
+func (s *bundlePrecheckSuite) TestSummariseChanges(c *gc.C) {
+	h := s.makeHandler(c, nil)
+	c.Assert(summariseChanges(h.changes), gc.Equals,
+		"3 charms, 3 applications, 2 machines, 1 container, 3 units, 1 relation")
+	c.Assert(summariseChanges(nil), gc.Equals, "none")
+}
+
+func (s *bundlePrecheckSuite) TestPrecheckPasses(c *gc.C) {
+	fakeAPI := vanillaFakeModelAPI(nil)
+	fakeAPI.Call("ListSpaces").Returns([]params.Space{
+		{Name: "db"}, {Name: "internal"}, {Name: "public"},
+	}, error(nil))
+	fakeAPI.Call("PrecheckMachines", precheckMachineParams()).Returns(
+		make([]params.PrecheckMachineResult, 2), error(nil),
+	)
+	h := s.makeHandler(c, fakeAPI)
+
+	err := h.precheckChanges()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stderr(h.ctx), gc.Equals, ""+
+		"Resources to be created: 3 charms, 3 applications, 2 machines, 1 container, 3 units, 1 relation\n"+
+		"Precheck of bundle passed.\n")
+}
+
+func (s *bundlePrecheckSuite) TestPrecheckReportsProblems(c *gc.C) {
+	fakeAPI := vanillaFakeModelAPI(nil)
+	fakeAPI.Call("ListSpaces").Returns([]params.Space{{Name: "db"}}, error(nil))
+	fakeAPI.Call("PrecheckMachines", precheckMachineParams()).Returns([]params.PrecheckMachineResult{
+		{Unsupported: []string{"virt-type"}},
+		{Error: &params.Error{Message: `invalid instance type "huge"`}},
+	}, error(nil))
+	h := s.makeHandler(c, fakeAPI)
+
+	err := h.precheckChanges()
+	c.Assert(err, gc.ErrorMatches, `bundle deployment would fail: 3 problem\(s\) found`)
+	stderr := cmdtesting.Stderr(h.ctx)
+	c.Check(stderr, jc.Contains, "Precheck of bundle found problems:\n")
+	c.Check(stderr, jc.Contains, `- deploy application mysql on bionic using cs:mysql-42: binding for endpoint "server" uses unknown space "internal"`+"\n")
+	c.Check(stderr, jc.Contains, `- add new machine 0: constraint uses unknown space "public"`+"\n")
+	c.Check(stderr, jc.Contains, `- add unit mysql/0 to new machine 1: invalid instance type "huge"`+"\n")
+}
+
+func (s *bundlePrecheckSuite) TestPrecheckNotSupported(c *gc.C) {
+	fakeAPI := vanillaFakeModelAPI(nil)
+	fakeAPI.Call("ListSpaces").Returns([]params.Space{
+		{Name: "db"}, {Name: "internal"}, {Name: "public"},
+	}, error(nil))
+	fakeAPI.Call("PrecheckMachines", precheckMachineParams()).Returns(
+		[]params.PrecheckMachineResult(nil), errors.NotSupportedf("prechecking machines"),
+	)
+	h := s.makeHandler(c, fakeAPI)
+
+	err := h.precheckChanges()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stderr(h.ctx), jc.Contains, "Precheck of bundle passed.\n")
+}
@@ -0,0 +1,133 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application
+
+import (
+	"os"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/api/application"
+)
+
+// rollbackStep reverses a single change applied by a bundle deployment.
+type rollbackStep struct {
+	description string
+	undo        func() error
+}
+
+// recordUndo records how to reverse a change that has just been applied,
+// so that it can be rolled back if a later change fails.
+func (h *bundleHandler) recordUndo(description string, undo func() error) {
+	if !h.rollback {
+		return
+	}
+	h.applied = append(h.applied, rollbackStep{
+		description: description,
+		undo:        undo,
+	})
+}
+
+// notifyInterrupt returns a channel that receives a value if the user
+// interrupts a deployment that can be rolled back. If rollback is not
+// requested the channel is nil, and interrupts are handled as usual.
+func (h *bundleHandler) notifyInterrupt() chan os.Signal {
+	if !h.rollback || h.dryRun {
+		return nil
+	}
+	interrupted := make(chan os.Signal, 1)
+	h.ctx.InterruptNotify(interrupted)
+	return interrupted
+}
+
+func (h *bundleHandler) stopInterrupt(interrupted chan os.Signal) {
+	if interrupted != nil {
+		h.ctx.StopInterruptNotify(interrupted)
+	}
+}
+
+// rollbackChanges reverses the changes applied so far, most recent
+// first, reporting each step. A step that fails is reported, and the
+// remaining steps are still attempted. The given cause of the rollback
+// is always returned.
+func (h *bundleHandler) rollbackChanges(cause error) error {
+	if len(h.applied) == 0 {
+		return cause
+	}
+	h.ctx.Infof("Rolling back changes after error: %v", cause)
+	var failed int
+	for i := len(h.applied) - 1; i >= 0; i-- {
+		step := h.applied[i]
+		if err := step.undo(); err != nil {
+			failed++
+			h.ctx.Infof("- %s: %v", step.description, err)
+			continue
+		}
+		h.ctx.Infof("- %s", step.description)
+	}
+	h.applied = nil
+	if failed > 0 {
+		h.ctx.Infof("Rollback of bundle incomplete: %d change(s) could not be reversed.", failed)
+	} else {
+		h.ctx.Infof("Rollback of bundle completed.")
+	}
+	return cause
+}
+
+// destroyApplication removes an application created by the deployment,
+// along with its units and their storage.
+func (h *bundleHandler) destroyApplication(name string) error {
+	results, err := h.api.DestroyApplications(application.DestroyApplicationsParams{
+		Applications:   []string{name},
+		DestroyStorage: true,
+	})
+	if err == nil && len(results) > 0 && results[0].Error != nil {
+		err = results[0].Error
+	}
+	return errors.Trace(err)
+}
+
+// destroyUnit removes a unit added by the deployment, along with its
+// storage. If the unit was placed on a new machine, that machine is
+// also removed.
+func (h *bundleHandler) destroyUnit(unit string, newMachine bool) error {
+	var machine string
+	if newMachine {
+		var err error
+		if machine, err = h.resolveMachine(unit); err != nil {
+			return errors.Annotatef(err, "cannot find machine for unit %s", unit)
+		}
+	}
+	results, err := h.api.DestroyUnits(application.DestroyUnitsParams{
+		Units:          []string{unit},
+		DestroyStorage: true,
+	})
+	if err == nil && len(results) > 0 && results[0].Error != nil {
+		err = results[0].Error
+	}
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if machine == "" {
+		return nil
+	}
+	// The unit is still being removed, so removal of its machine
+	// has to be forced.
+	return errors.Annotatef(
+		h.api.DestroyMachinesWithParams(true, false, machine),
+		"cannot remove machine %s", machine,
+	)
+}
+
+// destroyConsumedApplication removes a remote application added by
+// consuming an offer.
+func (h *bundleHandler) destroyConsumedApplication(name string) error {
+	results, err := h.api.DestroyConsumedApplication(application.DestroyConsumedApplicationParams{
+		SaasNames: []string{name},
+	})
+	if err == nil && len(results) > 0 && results[0].Error != nil {
+		err = results[0].Error
+	}
+	return errors.Trace(err)
+}
@@ -0,0 +1,250 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application
+
+import (
+	"strings"
+	"time"
+
+	"github.com/juju/bundlechanges"
+	"github.com/juju/charm/v7"
+	charmresource "github.com/juju/charm/v7/resource"
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
+	jujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/macaroon.v2"
+
+	"github.com/juju/juju/api/application"
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+	jjcharmstore "github.com/juju/juju/charmstore"
+	"github.com/juju/juju/core/constraints"
+	"github.com/juju/juju/core/instance"
+	"github.com/juju/juju/core/model"
+	"github.com/juju/juju/environs/config"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type bundleRollbackSuite struct {
+	jujutesting.IsolationSuite
+}
+
+var _ = gc.Suite(&bundleRollbackSuite{})
+
+const rollbackBundle = `
+series: bionic
+applications:
+  mysql:
+    charm: cs:mysql-42
+    num_units: 1
+    to: ["0"]
+  wordpress:
+    charm: cs:wordpress-47
+    num_units: 1
+machines:
+  "0": {}
+relations:
+- [wordpress:db, mysql:server]
+`
+
+// rollbackDeployAPI is the API used by the bundle handlers under test.
+type rollbackDeployAPI interface {
+	DeployAPI
+	BundleResolver
+}
+
+func (s *bundleRollbackSuite) makeHandler(c *gc.C, api rollbackDeployAPI, rollback bool) *bundleHandler {
+	data, err := charm.ReadBundleData(strings.NewReader(rollbackBundle))
+	c.Assert(err, jc.ErrorIsNil)
+	changes, err := bundlechanges.FromData(bundlechanges.ChangesConfig{
+		Bundle: data,
+		Model:  &bundlechanges.Model{},
+		Logger: logger,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	modelConfig, err := config.New(config.UseDefaults, coretesting.FakeConfig())
+	c.Assert(err, jc.ErrorIsNil)
+	h := makeBundleHandler(data, bundleDeploySpec{
+		ctx:            cmdtesting.Context(c),
+		rollback:       rollback,
+		apiRoot:        api,
+		bundleResolver: api,
+		deployResources: func(
+			string, jjcharmstore.CharmID, *macaroon.Macaroon, map[string]string,
+			map[string]charmresource.Meta, base.APICallCloser,
+		) (map[string]string, error) {
+			return nil, nil
+		},
+	})
+	h.changes = changes
+	h.modelConfig = modelConfig
+	return h
+}
+
+func (s *bundleRollbackSuite) fakeAPI() *fakeDeployAPI {
+	fakeAPI := vanillaFakeModelAPI(nil)
+	fakeAPI.Call("AddMachines", []params.AddMachineParams{{
+		Series:      "bionic",
+		Constraints: constraints.Value{},
+		Jobs:        []model.MachineJob{model.JobHostUnits},
+	}}).Returns([]params.AddMachinesResult{{Machine: "0"}}, error(nil))
+	fakeAPI.Call("AddRelation", []interface{}{"wordpress:db", "mysql:server"}, []interface{}{}).Returns(
+		&params.AddRelationResults{}, error(nil),
+	)
+	fakeAPI.Call("AddUnits", application.AddUnitsParams{
+		ApplicationName: "mysql",
+		NumUnits:        1,
+		Placement:       []*instance.Placement{{Scope: "#", Directive: "0"}},
+	}).Returns([]string{"mysql/0"}, error(nil))
+	fakeAPI.Call("AddUnits", application.AddUnitsParams{
+		ApplicationName: "wordpress",
+		NumUnits:        1,
+	}).Returns([]string{"wordpress/0"}, error(nil))
+	return fakeAPI
+}
+
+// deployAPI returns a fake API that deploys the bundle's applications,
+// machine and relation, and fails to add the wordpress unit.
+func (s *bundleRollbackSuite) deployAPI() *fakeDeployAPI {
+	fakeAPI := s.fakeAPI()
+	withAllWatcher(fakeAPI)
+	for _, name := range []string{"mysql-42", "wordpress-47"} {
+		url := charm.MustParseURL("cs:" + name)
+		withCharmRepoResolvable(fakeAPI, url)
+		withCharmDeployable(fakeAPI, charm.MustParseURL("cs:bionic/"+name), "bionic",
+			&charm.Meta{Name: url.Name, Series: []string{"bionic"}},
+			nil, false, false, 0, nil, nil,
+		)
+	}
+	fakeAPI.Call("AddUnits", application.AddUnitsParams{
+		ApplicationName: "wordpress",
+		NumUnits:        1,
+	}).Returns([]string(nil), errors.New("quota exceeded"))
+	fakeAPI.Call("DestroyUnits", application.DestroyUnitsParams{
+		Units:          []string{"mysql/0"},
+		DestroyStorage: true,
+	}).Returns(make([]params.DestroyUnitResult, 1), error(nil))
+	for _, app := range []string{"mysql", "wordpress"} {
+		fakeAPI.Call("DestroyApplications", application.DestroyApplicationsParams{
+			Applications:   []string{app},
+			DestroyStorage: true,
+		}).Returns(make([]params.DestroyApplicationResult, 1), error(nil))
+	}
+	fakeAPI.Call("DestroyRelation", (*bool)(nil), (*time.Duration)(nil), []interface{}{"wordpress:db", "mysql:server"}).Returns(error(nil))
+	fakeAPI.Call("DestroyMachinesWithParams", true, false, []interface{}{"0"}).Returns(error(nil))
+	return fakeAPI
+}
+
+// deployCalls returns the names of the calls that deploy the bundle or
+// roll it back, in the order they were made.
+func deployCalls(fakeAPI *fakeDeployAPI) []string {
+	var names []string
+	for _, call := range fakeAPI.Calls() {
+		switch call.FuncName {
+		case "Deploy", "AddMachines", "AddRelation", "AddUnits",
+			"DestroyUnits", "DestroyRelation", "DestroyMachinesWithParams", "DestroyApplications":
+			names = append(names, call.FuncName)
+		}
+	}
+	return names
+}
+
+func (s *bundleRollbackSuite) TestRollbackAfterFailedChange(c *gc.C) {
+	fakeAPI := s.deployAPI()
+	h := s.makeHandler(c, fakeAPI, true)
+
+	err := h.handleChanges()
+	c.Assert(err, gc.ErrorMatches, `cannot add unit for application "wordpress": quota exceeded`)
+	c.Assert(h.applied, gc.HasLen, 0)
+	c.Assert(deployCalls(fakeAPI), jc.DeepEquals, []string{
+		"Deploy", "Deploy", "AddMachines", "AddRelation", "AddUnits", "AddUnits",
+		"DestroyUnits", "DestroyRelation", "DestroyMachinesWithParams",
+		"DestroyApplications", "DestroyApplications",
+	})
+	var removed []string
+	for _, call := range fakeAPI.Calls() {
+		if call.FuncName == "DestroyApplications" {
+			removed = append(removed, call.Args[0].(application.DestroyApplicationsParams).Applications...)
+		}
+	}
+	c.Assert(removed, jc.DeepEquals, []string{"wordpress", "mysql"})
+	c.Assert(cmdtesting.Stderr(h.ctx), gc.Equals, ""+
+		"Rolling back changes after error: cannot add unit for application \"wordpress\": quota exceeded\n"+
+		"- remove unit mysql/0\n"+
+		"- remove relation wordpress:db mysql:server\n"+
+		"- remove machine 0\n"+
+		"- remove application wordpress\n"+
+		"- remove application mysql\n"+
+		"Rollback of bundle completed.\n")
+}
+
+func (s *bundleRollbackSuite) TestRollbackContinuesAfterFailure(c *gc.C) {
+	fakeAPI := s.fakeAPI()
+	fakeAPI.Call("DestroyUnits", application.DestroyUnitsParams{
+		Units:          []string{"mysql/0"},
+		DestroyStorage: true,
+	}).Returns([]params.DestroyUnitResult{{
+		Error: &params.Error{Message: "unit not found"},
+	}}, error(nil))
+	fakeAPI.Call("DestroyRelation", (*bool)(nil), (*time.Duration)(nil), []interface{}{"wordpress:db", "mysql:server"}).Returns(
+		errors.New("connection lost"),
+	)
+	fakeAPI.Call("DestroyMachinesWithParams", true, false, []interface{}{"0"}).Returns(error(nil))
+
+	h := s.makeHandler(c, fakeAPI, true)
+	for _, change := range h.changes {
+		switch change := change.(type) {
+		case *bundlechanges.AddApplicationChange:
+			h.results[change.Id()] = change.Params.Application
+		case *bundlechanges.AddMachineChange:
+			c.Assert(h.addMachine(change), jc.ErrorIsNil)
+		case *bundlechanges.AddRelationChange:
+			c.Assert(h.addRelation(change), jc.ErrorIsNil)
+		case *bundlechanges.AddUnitChange:
+			if change.Params.Application == "$deploy-1" {
+				c.Assert(h.addUnit(change), jc.ErrorIsNil)
+			}
+		}
+	}
+
+	err := h.rollbackChanges(errors.New("boom"))
+	c.Assert(err, gc.ErrorMatches, "boom")
+	stderr := cmdtesting.Stderr(h.ctx)
+	c.Check(stderr, jc.Contains, "- remove unit mysql/0: unit not found\n")
+	c.Check(stderr, jc.Contains, "- remove relation wordpress:db mysql:server: connection lost\n")
+	c.Check(stderr, jc.Contains, "- remove machine 0\n")
+	c.Check(stderr, jc.Contains, "Rollback of bundle incomplete: 2 change(s) could not be reversed.\n")
+}
+
+func (s *bundleRollbackSuite) TestNoRollbackRequested(c *gc.C) {
+	fakeAPI := s.deployAPI()
+	h := s.makeHandler(c, fakeAPI, false)
+
+	err := h.handleChanges()
+	c.Assert(err, gc.ErrorMatches, `cannot add unit for application "wordpress": quota exceeded`)
+	c.Assert(h.applied, gc.HasLen, 0)
+	c.Assert(deployCalls(fakeAPI), jc.DeepEquals, []string{
+		"Deploy", "Deploy", "AddMachines", "AddRelation", "AddUnits", "AddUnits",
+	})
+	c.Assert(cmdtesting.Stderr(h.ctx), gc.Equals, "")
+}
+
+func (s *bundleRollbackSuite) TestExistingRelationNotRolledBack(c *gc.C) {
+	fakeAPI := vanillaFakeModelAPI(nil)
+	fakeAPI.Call("AddRelation", []interface{}{"wordpress:db", "mysql:server"}, []interface{}{}).Returns(
+		(*params.AddRelationResults)(nil), &params.Error{Code: params.CodeAlreadyExists, Message: "relation exists"},
+	)
+	h := s.makeHandler(c, fakeAPI, true)
+	for _, change := range h.changes {
+		switch change := change.(type) {
+		case *bundlechanges.AddApplicationChange:
+			h.results[change.Id()] = change.Params.Application
+		case *bundlechanges.AddRelationChange:
+			c.Assert(h.addRelation(change), jc.ErrorIsNil)
+		}
+	}
+	c.Assert(h.applied, gc.HasLen, 0)
+}
@@ -0,0 +1,57 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// +build !windows
+
+package application
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/juju/cmd/cmdtesting"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+// interruptingDeployAPI interrupts the deployment when the relation is
+// added.
+type interruptingDeployAPI struct {
+	*fakeDeployAPI
+}
+
+func (f interruptingDeployAPI) AddRelation(endpoints, viaCIDRs []string) (*params.AddRelationResults, error) {
+	delivered := make(chan os.Signal, 1)
+	signal.Notify(delivered, os.Interrupt)
+	if err := syscall.Kill(os.Getpid(), syscall.SIGINT); err != nil {
+		return nil, err
+	}
+	<-delivered
+	// Stop waits for the signal to be delivered to every channel,
+	// including the deployment's.
+	signal.Stop(delivered)
+	return f.fakeDeployAPI.AddRelation(endpoints, viaCIDRs)
+}
+
+func (s *bundleRollbackSuite) TestRollbackAfterInterrupt(c *gc.C) {
+	fakeAPI := s.deployAPI()
+	h := s.makeHandler(c, interruptingDeployAPI{fakeAPI}, true)
+
+	err := h.handleChanges()
+	c.Assert(err, gc.ErrorMatches, "bundle deployment interrupted")
+	c.Assert(deployCalls(fakeAPI), jc.DeepEquals, []string{
+		"Deploy", "Deploy", "AddMachines", "AddRelation",
+		"DestroyRelation", "DestroyMachinesWithParams",
+		"DestroyApplications", "DestroyApplications",
+	})
+	c.Assert(cmdtesting.Stderr(h.ctx), gc.Equals, ""+
+		"Rolling back changes after error: bundle deployment interrupted\n"+
+		"- remove relation wordpress:db mysql:server\n"+
+		"- remove machine 0\n"+
+		"- remove application wordpress\n"+
+		"- remove application mysql\n"+
+		"Rollback of bundle completed.\n")
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
+	"github.com/juju/featureflag"
 	"github.com/juju/gnuflag"
 	"github.com/juju/names/v4"
 
@@ -17,6 +18,8 @@ import (
 	"github.com/juju/juju/cmd/juju/block"
 	"github.com/juju/juju/cmd/modelcmd"
 	"github.com/juju/juju/core/constraints"
+	"github.com/juju/juju/core/model"
+	"github.com/juju/juju/feature"
 )
 
 var usageGetConstraintsSummary = `
@@ -54,6 +57,9 @@ Application constraints are combined with model constraints, set with ` +
 provision machines for applications. Where model and application constraints
 overlap, the application constraints take precedence.
 Constraints for a specific model can be viewed with ` + "`juju get-model-\nconstraints`" + `.
+When the branches feature is enabled and a branch other than master is
+active, or one is given with '--branch', the constraints are set in that
+branch and apply to the application when the branch is committed.
 This command requires that the application to have at least one unit. To apply 
 constraints to
 the first unit set them at the model level or pass them as an argument
@@ -77,6 +83,7 @@ type applicationConstraintsAPI interface {
 	Close() error
 	GetConstraints(...string) ([]constraints.Value, error)
 	SetConstraints(string, constraints.Value) error
+	SetBranchConstraints(string, string, constraints.Value) error
 }
 
 type applicationConstraintsCommand struct {
@@ -153,6 +160,7 @@ func (c *applicationGetConstraintsCommand) Run(ctx *cmd.Context) error {
 type applicationSetConstraintsCommand struct {
 	applicationConstraintsCommand
 	Constraints constraints.Value
+	branchName  string
 }
 
 // NewApplicationSetConstraintsCommand returns a command which sets application constraints.
@@ -169,6 +177,13 @@ func (c *applicationSetConstraintsCommand) Info() *cmd.Info {
 	})
 }
 
+func (c *applicationSetConstraintsCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ModelCommandBase.SetFlags(f)
+	if featureflag.Enabled(feature.Branches) || featureflag.Enabled(feature.Generations) {
+		f.StringVar(&c.branchName, "branch", "", "Specifically target constraints for the supplied branch")
+	}
+}
+
 func (c *applicationSetConstraintsCommand) Init(args []string) (err error) {
 	if len(args) == 0 {
 		return errors.Errorf("no application name specified")
@@ -190,6 +205,16 @@ func (c *applicationSetConstraintsCommand) Run(_ *cmd.Context) (err error) {
 	}
 	defer apiclient.Close()
 
-	err = apiclient.SetConstraints(c.ApplicationName, c.Constraints)
+	branchName := c.branchName
+	if branchName == "" && (featureflag.Enabled(feature.Branches) || featureflag.Enabled(feature.Generations)) {
+		if branchName, err = c.ActiveBranch(); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	if branchName != "" && branchName != model.GenerationMaster {
+		err = apiclient.SetBranchConstraints(branchName, c.ApplicationName, c.Constraints)
+	} else {
+		err = apiclient.SetConstraints(c.ApplicationName, c.Constraints)
+	}
 	return block.ProcessBlockedError(err, block.BlockChange)
 }
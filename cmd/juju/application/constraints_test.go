@@ -5,10 +5,13 @@ package application_test
 
 import (
 	"github.com/juju/cmd/cmdtesting"
+	jujutesting "github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/cmd/juju/application"
+	"github.com/juju/juju/core/constraints"
+	"github.com/juju/juju/feature"
 	"github.com/juju/juju/jujuclient/jujuclienttesting"
 	"github.com/juju/juju/testing"
 )
@@ -83,3 +86,58 @@ func (s *ApplicationConstraintsCommandsSuite) TestGetInit(c *gc.C) {
 		}
 	}
 }
+
+type fakeConstraintsAPI struct {
+	jujutesting.Stub
+}
+
+func (f *fakeConstraintsAPI) Close() error {
+	return nil
+}
+
+func (f *fakeConstraintsAPI) GetConstraints(applications ...string) ([]constraints.Value, error) {
+	f.MethodCall(f, "GetConstraints", applications)
+	return make([]constraints.Value, len(applications)), f.NextErr()
+}
+
+func (f *fakeConstraintsAPI) SetConstraints(application string, cons constraints.Value) error {
+	f.MethodCall(f, "SetConstraints", application, cons)
+	return f.NextErr()
+}
+
+func (f *fakeConstraintsAPI) SetBranchConstraints(branchName, application string, cons constraints.Value) error {
+	f.MethodCall(f, "SetBranchConstraints", branchName, application, cons)
+	return f.NextErr()
+}
+
+func (s *ApplicationConstraintsCommandsSuite) TestSetConstraints(c *gc.C) {
+	api := &fakeConstraintsAPI{}
+	cmd := application.NewApplicationSetConstraintsCommandForTest(api, jujuclienttesting.MinimalStore())
+	_, err := cmdtesting.RunCommand(c, cmd, "mysql", "mem=8G")
+	c.Assert(err, jc.ErrorIsNil)
+	api.CheckCalls(c, []jujutesting.StubCall{{
+		FuncName: "SetConstraints",
+		Args:     []interface{}{"mysql", constraints.MustParse("mem=8G")},
+	}})
+}
+
+func (s *ApplicationConstraintsCommandsSuite) TestSetConstraintsBranch(c *gc.C) {
+	s.SetFeatureFlags(feature.Branches)
+	api := &fakeConstraintsAPI{}
+	cmd := application.NewApplicationSetConstraintsCommandForTest(api, jujuclienttesting.MinimalStore())
+	_, err := cmdtesting.RunCommand(c, cmd, "mysql", "mem=8G", "--branch", "canary")
+	c.Assert(err, jc.ErrorIsNil)
+	api.CheckCalls(c, []jujutesting.StubCall{{
+		FuncName: "SetBranchConstraints",
+		Args:     []interface{}{"canary", "mysql", constraints.MustParse("mem=8G")},
+	}})
+}
+
+func (s *ApplicationConstraintsCommandsSuite) TestSetConstraintsActiveBranchMaster(c *gc.C) {
+	s.SetFeatureFlags(feature.Branches)
+	api := &fakeConstraintsAPI{}
+	cmd := application.NewApplicationSetConstraintsCommandForTest(api, jujuclienttesting.MinimalStore())
+	_, err := cmdtesting.RunCommand(c, cmd, "mysql", "mem=8G")
+	c.Assert(err, jc.ErrorIsNil)
+	api.CheckCallNames(c, "SetConstraints")
+}
@@ -11,6 +11,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/juju/charm/v7"
 	"github.com/juju/charm/v7/resource"
@@ -32,10 +33,12 @@ import (
 	"github.com/juju/juju/api/applicationoffers"
 	apicharms "github.com/juju/juju/api/charms"
 	"github.com/juju/juju/api/controller"
+	"github.com/juju/juju/api/machinemanager"
 	"github.com/juju/juju/api/modelconfig"
 	"github.com/juju/juju/api/spaces"
 	app "github.com/juju/juju/apiserver/facades/client/application"
 	apiparams "github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/charmrepository"
 	"github.com/juju/juju/charmstore"
 	jujucmd "github.com/juju/juju/cmd"
 	"github.com/juju/juju/cmd/juju/block"
@@ -110,6 +113,23 @@ type SpacesAPI interface {
 	ListSpaces() ([]apiparams.Space, error)
 }
 
+// MachinePrecheckAPI represents the methods of the API the deploy
+// command needs to check machines could be added, without adding them.
+type MachinePrecheckAPI interface {
+	PrecheckMachines([]apiparams.AddMachineParams) ([]apiparams.PrecheckMachineResult, error)
+}
+
+// BundleRollbackAPI represents the methods of the API the deploy
+// command needs to reverse the changes made by a failed bundle deployment.
+type BundleRollbackAPI interface {
+	DestroyApplications(application.DestroyApplicationsParams) ([]apiparams.DestroyApplicationResult, error)
+	DestroyUnits(application.DestroyUnitsParams) ([]apiparams.DestroyUnitResult, error)
+	DestroyRelation(force *bool, maxWait *time.Duration, endpoints ...string) error
+	DestroyConsumedApplication(application.DestroyConsumedApplicationParams) ([]apiparams.ErrorResult, error)
+	DestroyMachinesWithParams(force, keep bool, machines ...string) error
+	DestroyOffers(force bool, offerURLs ...string) error
+}
+
 var supportedJujuSeries = series.WorkloadSeries
 
 // DeployAPI represents the methods of the API the deploy
@@ -125,6 +145,8 @@ type DeployAPI interface {
 	ModelAPI
 	OfferAPI
 	SpacesAPI
+	MachinePrecheckAPI
+	BundleRollbackAPI
 
 	// ApplicationClient
 	Deploy(application.DeployArgs) error
@@ -198,6 +220,8 @@ type deployAPIAdapter struct {
 	*plansClient
 	*offerClient
 	*spacesClient
+
+	machineManagerClient *machinemanager.Client
 }
 
 type charmStoreAdaptor struct {
@@ -237,6 +261,10 @@ func (a *charmStoreAdaptor) Get(url *charm.URL, path string) (*charm.CharmArchiv
 	return a.charmrepoForDeploy.Get(url, path)
 }
 
+func (a *deployAPIAdapter) PrecheckMachines(machineParams []apiparams.AddMachineParams) ([]apiparams.PrecheckMachineResult, error) {
+	return a.machineManagerClient.PrecheckMachines(machineParams)
+}
+
 func (a *deployAPIAdapter) SetAnnotation(annotations map[string]map[string]string) ([]apiparams.ErrorResult, error) {
 	return a.annotationsClient.Set(annotations)
 }
@@ -306,6 +334,8 @@ func newDeployCommand() *DeployCommand {
 			plansClient:       &plansClient{planURL: mURL},
 			offerClient:       &offerClient{Client: applicationoffers.NewClient(controllerAPIRoot)},
 			spacesClient:      &spacesClient{API: spaces.NewAPI(apiRoot)},
+
+			machineManagerClient: machinemanager.NewClient(apiRoot),
 		}, nil
 	}
 	deployCmd.NewConsumeDetailsAPI = func(url *charm.OfferURL) (ConsumeDetails, error) {
@@ -348,6 +378,10 @@ type DeployCommand struct {
 	// deployed but just output the changes.
 	DryRun bool
 
+	// Rollback is used to specify that the changes made by a bundle
+	// deployment should be reversed if it fails or is interrupted.
+	Rollback bool
+
 	ApplicationName string
 	ConfigOptions   common.ConfigFlag
 	ConstraintsStr  string
@@ -568,11 +602,29 @@ bundle.
 Only top level machines can be mapped in this way, just as only top level
 machines can be defined in the machines section of the bundle.
 
+Use the '--dry-run' option to show the changes a bundle deploy would make
+without making them. The dry run also asks the cloud provider to precheck
+every machine the bundle would add, checks the spaces used by bindings and
+constraints exist, and summarises the resources to be created. It fails if
+any of these checks would make the deployment fail.
+
+A bundle deploy is not transactional by default: if a change fails, the
+changes already made are left in the model. Use the '--rollback' option to
+have the applications, units, machines, relations, offers and consumed offers
+created by the deploy removed again, in reverse order, when a change fails or
+the deploy is interrupted. Charms, config, constraints, annotations and
+exposure applied to existing applications are not reverted.
+
 When charms that include LXD profiles are deployed the profiles are validated
 for security purposes by allowing only certain configurations and devices. Use
 the '--force' option to bypass this check. Doing so is not recommended as it
 can lead to unexpected behaviour.
 
+When the model's 'charm-repository-url' setting is set, charm store charms and
+bundles are resolved and fetched from the charm repository index at that
+location instead of the Charm Store. The location must be reachable from both
+the client and the controller.
+
 Further reading: https://jaas.ai/docs/deploying-applications
 
 Examples:
@@ -689,7 +741,7 @@ func (c *DeployCommand) Info() *cmd.Info {
 var (
 	// TODO(thumper): support dry-run for apps as well as bundles.
 	bundleOnlyFlags = []string{
-		"overlay", "dry-run", "map-machines",
+		"overlay", "dry-run", "map-machines", "rollback",
 	}
 )
 
@@ -719,7 +771,8 @@ func (c *DeployCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.Var(cmd.NewAppendStringsValue(&c.BundleOverlayFile), "overlay", "Bundles to overlay on the primary bundle, applied in order")
 	f.StringVar(&c.ConstraintsStr, "constraints", "", "Set application constraints")
 	f.StringVar(&c.Series, "series", "", "The series on which to deploy")
-	f.BoolVar(&c.DryRun, "dry-run", false, "Just show what the bundle deploy would do")
+	f.BoolVar(&c.DryRun, "dry-run", false, "Just show what the bundle deploy would do, and check it would succeed")
+	f.BoolVar(&c.Rollback, "rollback", false, "Reverse the changes already made if the bundle deploy fails or is interrupted")
 	f.BoolVar(&c.Force, "force", false, "Allow a charm/bundle to be deployed which bypasses checks such as supported series or LXD profile allow list")
 	f.Var(storageFlag{&c.Storage, &c.BundleStorage}, "storage", "Charm storage constraints")
 	f.Var(devicesFlag{&c.Devices, &c.BundleDevices}, "device", "Charm device constraints")
@@ -844,6 +897,42 @@ func parseMachineMap(value string) (bool, map[string]string, error) {
 	return useExisting, mapping, nil
 }
 
+// openCharmRepository opens the charm repository at the given location.
+// It is a variable so that it can be overridden in tests.
+var openCharmRepository = func(location string) (charmrepoForDeploy, error) {
+	return charmrepository.Open(location)
+}
+
+// modelCharmRepository returns the charm repository configured for the
+// model, or nil if charm store charms are fetched from the charm store.
+func modelCharmRepository(api ModelConfigGetter) (charmrepoForDeploy, error) {
+	modelCfg, err := getModelConfig(api)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	location, ok := modelCfg.CharmRepositoryURL()
+	if !ok {
+		return nil, nil
+	}
+	repo, err := openCharmRepository(location)
+	return repo, errors.Trace(err)
+}
+
+// useModelCharmRepository returns an adaptor that resolves and fetches
+// charm store charms from the model's charm repository, if one is
+// configured, and the given charm store adaptor otherwise.
+func useModelCharmRepository(api ModelConfigGetter, cstore *charmStoreAdaptor) (*charmStoreAdaptor, error) {
+	repo, err := modelCharmRepository(api)
+	if err != nil || repo == nil {
+		return cstore, errors.Trace(err)
+	}
+	adaptor := &charmStoreAdaptor{charmrepoForDeploy: repo}
+	if cstore != nil {
+		adaptor.macaroonGetter = cstore.macaroonGetter
+	}
+	return adaptor, nil
+}
+
 type ModelConfigGetter interface {
 	ModelGet() (map[string]interface{}, error)
 }
@@ -1157,6 +1246,11 @@ func (c *DeployCommand) Run(ctx *cmd.Context) error {
 	}
 	defer apiRoot.Close()
 
+	cstoreAPI, err = useModelCharmRepository(apiRoot, cstoreAPI)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
 	if err := c.parseBindFlag(apiRoot); err != nil {
 		return errors.Trace(err)
 	}
@@ -1355,6 +1449,7 @@ func (c *DeployCommand) maybeReadLocalBundle(ctx *cmd.Context) (deployFn, error)
 		return errors.Trace(c.deployBundle(bundleDeploySpec{
 			ctx:                 ctx,
 			dryRun:              c.DryRun,
+			rollback:            c.Rollback,
 			force:               c.Force,
 			trust:               c.Trust,
 			bundleDataSource:    ds,
@@ -1545,6 +1640,7 @@ func (c *DeployCommand) maybeReadCharmstoreBundleFn(cstore BundleResolver) func(
 			return errors.Trace(c.deployBundle(bundleDeploySpec{
 				ctx:                 ctx,
 				dryRun:              c.DryRun,
+				rollback:            c.Rollback,
 				force:               c.Force,
 				trust:               c.Trust,
 				bundleDataSource:    newResolvedBundle(bundle),
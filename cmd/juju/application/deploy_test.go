@@ -2104,6 +2104,47 @@ func (s *BundleDeployCharmStoreSuite) TestDeployBundle_OutputsCorrectMessage(c *
 	)
 }
 
+func (s *DeployUnitTestSuite) TestDeployFromModelCharmRepository(c *gc.C) {
+	repoDir := c.MkDir()
+	charmPath := testcharms.RepoWithSeries("bionic").CharmArchivePath(repoDir, "dummy")
+	index := fmt.Sprintf(`
+charms:
+  dummy:
+    channels:
+      stable: 3
+    revisions:
+      3:
+        series: [bionic]
+        archive: %s
+`, filepath.Base(charmPath))
+	err := ioutil.WriteFile(filepath.Join(repoDir, "index.yaml"), []byte(index), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+
+	cfgAttrs := s.cfgAttrs()
+	cfgAttrs["charm-repository-url"] = "file://" + repoDir
+	fakeAPI := vanillaFakeModelAPI(cfgAttrs)
+
+	ch, err := charm.ReadCharmArchive(charmPath)
+	c.Assert(err, jc.ErrorIsNil)
+	curl := charm.MustParseURL("cs:dummy-3")
+	fakeAPI.Call("AddCharm", curl, csclientparams.StableChannel, false).Returns(error(nil))
+	fakeAPI.Call("CharmInfo", curl.String()).Returns(
+		&charms.CharmInfo{URL: curl.String(), Meta: ch.Meta()},
+		error(nil),
+	)
+	fakeAPI.Call("Deploy", application.DeployArgs{
+		CharmID:         jjcharmstore.CharmID{URL: curl, Channel: csclientparams.StableChannel},
+		ApplicationName: "dummy",
+		Series:          "bionic",
+		NumUnits:        1,
+	}).Returns(error(nil))
+	fakeAPI.Call("IsMetered", curl.String()).Returns(false, error(nil))
+
+	ctx, err := s.runDeploy(c, fakeAPI, "dummy")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cmdtesting.Stderr(ctx), gc.Matches, `(?s)Located charm "cs:dummy-3".*`)
+}
+
 func (s *DeployUnitTestSuite) TestDeployAttachStorage(c *gc.C) {
 	charmsPath := c.MkDir()
 	charmDir := testcharms.RepoWithSeries("bionic").ClonedDir(charmsPath, "dummy")
@@ -2291,6 +2332,11 @@ func (f *fakeDeployAPI) ListSpaces() ([]params.Space, error) {
 	return results[0].([]params.Space), jujutesting.TypeAssertError(results[1])
 }
 
+func (f *fakeDeployAPI) PrecheckMachines(machineParams []params.AddMachineParams) ([]params.PrecheckMachineResult, error) {
+	results := f.MethodCall(f, "PrecheckMachines", machineParams)
+	return results[0].([]params.PrecheckMachineResult), jujutesting.TypeAssertError(results[1])
+}
+
 func (f *fakeDeployAPI) GetAnnotations(tags []string) ([]params.AnnotationsGetResult, error) {
 	return nil, nil
 }
@@ -2358,7 +2404,7 @@ func (f *fakeDeployAPI) SetConstraints(application string, constraints constrain
 
 func (f *fakeDeployAPI) AddMachines(machineParams []params.AddMachineParams) ([]params.AddMachinesResult, error) {
 	results := f.MethodCall(f, "AddMachines", machineParams)
-	return results[0].([]params.AddMachinesResult), jujutesting.TypeAssertError(results[0])
+	return results[0].([]params.AddMachinesResult), jujutesting.TypeAssertError(results[1])
 }
 
 func (f *fakeDeployAPI) PlanURL() string {
@@ -2391,6 +2437,36 @@ func (f *fakeDeployAPI) GrantOffer(user, access string, offerURLs ...string) err
 	return jujutesting.TypeAssertError(res[0])
 }
 
+func (f *fakeDeployAPI) DestroyApplications(args application.DestroyApplicationsParams) ([]params.DestroyApplicationResult, error) {
+	results := f.MethodCall(f, "DestroyApplications", args)
+	return results[0].([]params.DestroyApplicationResult), jujutesting.TypeAssertError(results[1])
+}
+
+func (f *fakeDeployAPI) DestroyUnits(args application.DestroyUnitsParams) ([]params.DestroyUnitResult, error) {
+	results := f.MethodCall(f, "DestroyUnits", args)
+	return results[0].([]params.DestroyUnitResult), jujutesting.TypeAssertError(results[1])
+}
+
+func (f *fakeDeployAPI) DestroyRelation(force *bool, maxWait *time.Duration, endpoints ...string) error {
+	results := f.MethodCall(f, "DestroyRelation", force, maxWait, stringToInterface(endpoints))
+	return jujutesting.TypeAssertError(results[0])
+}
+
+func (f *fakeDeployAPI) DestroyConsumedApplication(args application.DestroyConsumedApplicationParams) ([]params.ErrorResult, error) {
+	results := f.MethodCall(f, "DestroyConsumedApplication", args)
+	return results[0].([]params.ErrorResult), jujutesting.TypeAssertError(results[1])
+}
+
+func (f *fakeDeployAPI) DestroyMachinesWithParams(force, keep bool, machines ...string) error {
+	results := f.MethodCall(f, "DestroyMachinesWithParams", force, keep, stringToInterface(machines))
+	return jujutesting.TypeAssertError(results[0])
+}
+
+func (f *fakeDeployAPI) DestroyOffers(force bool, offerURLs ...string) error {
+	results := f.MethodCall(f, "DestroyOffers", force, stringToInterface(offerURLs))
+	return jujutesting.TypeAssertError(results[0])
+}
+
 func stringToInterface(args []string) []interface{} {
 	interfaceArgs := make([]interface{}, len(args))
 	for i, a := range args {
@@ -28,6 +28,16 @@ import (
 	"github.com/juju/juju/resource/resourceadapters"
 )
 
+// NewApplicationSetConstraintsCommandForTest returns a command to set
+// application constraints intended to be used only in tests.
+func NewApplicationSetConstraintsCommandForTest(api applicationConstraintsAPI, store jujuclient.ClientStore) modelcmd.ModelCommand {
+	c := modelcmd.Wrap(&applicationSetConstraintsCommand{
+		applicationConstraintsCommand: applicationConstraintsCommand{api: api},
+	})
+	c.SetClientStore(store)
+	return c
+}
+
 // NewDeployCommandForTest returns a command to deploy applications intended to be used only in tests.
 func NewDeployCommandForTest(fakeApi *fakeDeployAPI) modelcmd.ModelCommand {
 	deployCmd := &DeployCommand{
@@ -109,6 +119,7 @@ func NewUpgradeCharmCommandForTest(
 	newResourceLister func(base.APICallCloser) (ResourceLister, error),
 	charmStoreURLGetter func(base.APICallCloser) (string, error),
 	newSpacesClient func(base.APICallCloser) SpacesAPI,
+	newModelConfigGetter func(base.APICallCloser) ModelConfigGetter,
 ) cmd.Command {
 	cmd := &upgradeCharmCommand{
 		DeployResources:       deployResources,
@@ -119,6 +130,7 @@ func NewUpgradeCharmCommandForTest(
 		NewResourceLister:     newResourceLister,
 		CharmStoreURLGetter:   charmStoreURLGetter,
 		NewSpacesClient:       newSpacesClient,
+		NewModelConfigGetter:  newModelConfigGetter,
 		NewCharmStore:         newCharmStore,
 	}
 	cmd.SetClientStore(store)
@@ -263,6 +275,15 @@ func NewRemoveSaasCommandForTest(api RemoveSaasAPI, store jujuclient.ClientStore
 	return modelcmd.Wrap(cmd)
 }
 
+// NewAdoptWorkloadCommandForTest returns an AdoptWorkloadCommand with the api provided as specified.
+func NewAdoptWorkloadCommandForTest(api adoptWorkloadAPI, store jujuclient.ClientStore) modelcmd.ModelCommand {
+	cmd := &adoptWorkloadCommand{newAPIFunc: func() (adoptWorkloadAPI, error) {
+		return api, nil
+	}}
+	cmd.SetClientStore(store)
+	return modelcmd.Wrap(cmd)
+}
+
 // NewScaleCommandForTest returns a ScaleCommand with the api provided as specified.
 func NewScaleCommandForTest(api scaleApplicationAPI, store jujuclient.ClientStore) modelcmd.ModelCommand {
 	cmd := &scaleApplicationCommand{newAPIFunc: func() (scaleApplicationAPI, error) {
@@ -26,6 +26,7 @@ import (
 	"github.com/juju/juju/api/base"
 	"github.com/juju/juju/api/charms"
 	"github.com/juju/juju/api/controller"
+	"github.com/juju/juju/api/modelconfig"
 	"github.com/juju/juju/api/spaces"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/charmstore"
@@ -59,6 +60,9 @@ func newUpgradeCharmCommand() *upgradeCharmCommand {
 		NewSpacesClient: func(conn base.APICallCloser) SpacesAPI {
 			return spaces.NewAPI(conn)
 		},
+		NewModelConfigGetter: func(conn base.APICallCloser) ModelConfigGetter {
+			return modelconfig.NewClient(conn)
+		},
 		CharmStoreURLGetter: getCharmStoreAPIURL,
 		NewCharmStore: func(
 			bakeryClient *httpbakery.Client,
@@ -126,6 +130,7 @@ type upgradeCharmCommand struct {
 	NewCharmUpgradeClient func(base.APICallCloser) CharmAPIClient
 	NewResourceLister     func(base.APICallCloser) (ResourceLister, error)
 	NewSpacesClient       func(base.APICallCloser) SpacesAPI
+	NewModelConfigGetter  func(base.APICallCloser) ModelConfigGetter
 	CharmStoreURLGetter   func(base.APICallCloser) (string, error)
 
 	ApplicationName string
@@ -199,6 +204,11 @@ Charm settings may be added or updated at upgrade time by specifying the
 
   juju upgrade-charm foo --config config.yaml
 
+When the branches feature is enabled and a branch other than master is active,
+the upgrade is made in that branch. Only units tracking the branch run the new
+charm, and the rest of the application is upgraded when the branch is committed.
+Storage, config and bindings cannot be changed when upgrading in a branch.
+
 If the new version of a charm does not explicitly support the application's series, the
 upgrade is disallowed unless the --force-series option is used. This option should be
 used with caution since using a charm on a machine running an unsupported series may
@@ -363,9 +373,19 @@ func (c *upgradeCharmCommand) Run(ctx *cmd.Context) error {
 		c.Channel = csclientparams.Channel(applicationInfo.Channel)
 	}
 
+	// Charm store charms are resolved against the model's charm
+	// repository in place of the charm store when one is configured.
+	charmRepo, err := modelCharmRepository(c.NewModelConfigGetter(apiRoot))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if charmRepo == nil {
+		charmRepo = c.NewCharmStore(bakeryClient, csURL, c.Channel)
+	}
+
 	chID, csMac, err := c.addCharm(addCharmParams{
 		charmAdder:     c.NewCharmAdder(apiRoot),
-		charmRepo:      c.NewCharmStore(bakeryClient, csURL, c.Channel),
+		charmRepo:      charmRepo,
 		authorizer:     newCharmStoreClient(bakeryClient, csURL),
 		oldURL:         oldURL,
 		newCharmRef:    newRef,
@@ -205,6 +205,9 @@ func (s *BaseUpgradeCharmSuite) upgradeCommand() cmd.Command {
 			s.AddCall("NewSpacesClient", conn)
 			return &s.spacesClient
 		},
+		func(conn base.APICallCloser) ModelConfigGetter {
+			return &s.modelConfigGetter
+		},
 	)
 	return cmd
 }
@@ -239,6 +242,25 @@ func (s *UpgradeCharmSuite) TestUseConfiguredCharmStoreURL(c *gc.C) {
 	c.Assert(csURL, gc.Equals, "testing.api.charmstore")
 }
 
+func (s *UpgradeCharmSuite) TestUseModelCharmRepository(c *gc.C) {
+	s.modelConfigGetter.cfg["charm-repository-url"] = "https://charms.example.com/repo"
+	s.PatchValue(&openCharmRepository, func(location string) (charmrepoForDeploy, error) {
+		s.AddCall("OpenCharmRepository", location)
+		return s.fakeAPI, nil
+	})
+
+	_, err := s.runUpgradeCharm(c, "foo")
+	c.Assert(err, jc.ErrorIsNil)
+	var location string
+	for _, call := range s.Calls() {
+		c.Assert(call.FuncName, gc.Not(gc.Equals), "NewCharmStore")
+		if call.FuncName == "OpenCharmRepository" {
+			location = call.Args[0].(string)
+		}
+	}
+	c.Assert(location, gc.Equals, "https://charms.example.com/repo")
+}
+
 func (s *UpgradeCharmSuite) TestStorageConstraintsMinFacadeVersion(c *gc.C) {
 	s.apiConnection.bestFacadeVersion = 1
 	_, err := s.runUpgradeCharm(c, "foo", "--storage", "bar=baz")
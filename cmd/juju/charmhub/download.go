@@ -0,0 +1,191 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package charmhub
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+
+	"github.com/juju/juju/api/charmhub"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+)
+
+const (
+	downloadSummary = "Locates and then downloads a charm hub charm."
+	downloadDoc     = `
+Download a charm or bundle from the charm hub store configured for the
+model by the "charmhub-url" model config, so that it can be inspected or
+deployed locally. The revision released to the given channel is
+downloaded; if no channel is specified, the store's default channel is
+used. The controller downloads the revision, so that its proxy settings
+are honoured, and its SHA256 hash is checked once downloaded.
+
+Examples:
+    juju download postgresql
+    juju download postgresql --channel 12/edge --filepath ./pg.charm
+
+See also:
+    info
+    find
+`
+)
+
+// NewDownloadCommand wraps downloadCommand with sane model settings.
+func NewDownloadCommand() cmd.Command {
+	return modelcmd.Wrap(&downloadCommand{})
+}
+
+// downloadCommand supplies the "download" CLI command used to download
+// charms and bundles from the charm hub store.
+type downloadCommand struct {
+	modelcmd.ModelCommandBase
+
+	api DownloadCommandAPI
+
+	charmOrBundle string
+	channel       string
+	filePath      string
+}
+
+// Info returns help related info about the command, it implements
+// part of the cmd.Command interface.
+func (c *downloadCommand) Info() *cmd.Info {
+	info := &cmd.Info{
+		Name:    "download",
+		Args:    "[options] <charm>",
+		Purpose: downloadSummary,
+		Doc:     downloadDoc,
+	}
+	return jujucmd.Info(info)
+}
+
+// SetFlags defines flags which can be used with the download command.
+// It implements part of the cmd.Command interface.
+func (c *downloadCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ModelCommandBase.SetFlags(f)
+	f.StringVar(&c.channel, "channel", "", "The channel to download from, as <track>/<risk>")
+	f.StringVar(&c.filePath, "filepath", "", "The path to write the download to")
+}
+
+// Init initializes the download command, including validating the provided
+// flags. It implements part of the cmd.Command interface.
+func (c *downloadCommand) Init(args []string) error {
+	if len(args) != 1 {
+		return errors.Errorf("expected a charm or bundle name")
+	}
+	if err := validateCharmOrBundle(args[0]); err != nil {
+		return err
+	}
+	c.charmOrBundle = args[0]
+	return nil
+}
+
+// Run is the business logic of the download command.  It implements the
+// meaty part of the cmd.Command interface.
+func (c *downloadCommand) Run(ctx *cmd.Context) error {
+	client, err := c.getAPI()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	info, err := client.Info(c.charmOrBundle)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	channel := c.channel
+	if channel == "" {
+		channel = info.DefaultChannel
+	} else if !strings.Contains(channel, "/") {
+		// A bare risk refers to the latest track.
+		channel = "latest/" + channel
+	}
+	release, ok := info.Channels[channel]
+	if !ok {
+		available := make([]string, 0, len(info.Channels))
+		for name := range info.Channels {
+			available = append(available, name)
+		}
+		sort.Strings(available)
+		if len(available) == 0 {
+			return errors.Errorf("%q has no released revisions", c.charmOrBundle)
+		}
+		return errors.Errorf("%q has no revision released to channel %q, available channels: %s",
+			c.charmOrBundle, channel, strings.Join(available, ", "))
+	}
+
+	path := c.filePath
+	if path == "" {
+		path = fmt.Sprintf("%s_r%d.%s", info.Name, release.Revision, info.Type)
+	}
+	path = ctx.AbsPath(path)
+
+	if release.HashSHA256 == "" {
+		return errors.Errorf("no SHA256 hash is available for %s %q revision %d", info.Type, info.Name, release.Revision)
+	}
+
+	ctx.Infof("Fetching %s %q revision %d using %q channel", info.Type, info.Name, release.Revision, channel)
+	content, err := client.Download(info.Name, channel, release.Revision)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer func() { _ = content.Close() }()
+	if err := writeVerified(content, release.HashSHA256, path); err != nil {
+		return errors.Trace(err)
+	}
+
+	ctx.Infof(`Install the %q %s with:
+    juju deploy %s`, info.Name, info.Type, path)
+	return nil
+}
+
+// writeVerified writes the content to path, once it has been verified
+// against the expected SHA256 hash.
+func writeVerified(content io.Reader, expectedHash, path string) error {
+	tmp, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".tmp-")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer func() {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+	}()
+
+	hash := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(tmp, hash), content); err != nil {
+		return errors.Annotate(err, "downloading")
+	}
+	if got := fmt.Sprintf("%x", hash.Sum(nil)); got != expectedHash {
+		return errors.Errorf("downloaded content has SHA256 hash %q, expected %q", got, expectedHash)
+	}
+	if err := tmp.Close(); err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(os.Rename(tmp.Name(), path))
+}
+
+// getAPI returns the API that supplies methods
+// required to execute this command.
+func (c *downloadCommand) getAPI() (DownloadCommandAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	api, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Annotate(err, "opening API connection")
+	}
+	return charmhub.NewClient(api), nil
+}
@@ -0,0 +1,170 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package charmhub
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/golang/mock/gomock"
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/charmhub/mocks"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/jujuclient/jujuclienttesting"
+)
+
+type downloadSuite struct {
+	testing.IsolationSuite
+
+	api *mocks.MockDownloadCommandAPI
+	dir string
+}
+
+var _ = gc.Suite(&downloadSuite{})
+
+func (s *downloadSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.dir = c.MkDir()
+}
+
+func (s *downloadSuite) TestInitNoArgs(c *gc.C) {
+	cmd := &downloadCommand{}
+	err := cmd.Init([]string{})
+	c.Assert(err, gc.ErrorMatches, "expected a charm or bundle name")
+}
+
+func (s *downloadSuite) TestRunDefaultChannel(c *gc.C) {
+	defer s.setUpMocks(c).Finish()
+	s.api.EXPECT().Info("wordpress").Return(s.info(), nil)
+	s.api.EXPECT().Download("wordpress", "latest/stable", 16).Return(content("wordpress-16"), nil)
+
+	ctx, err := cmdtesting.RunCommandInDir(c, s.newCommand(), []string{"wordpress"}, s.dir)
+	c.Assert(err, jc.ErrorIsNil)
+
+	path := filepath.Join(s.dir, "wordpress_r16.charm")
+	s.assertContent(c, path, "wordpress-16")
+	c.Check(cmdtesting.Stderr(ctx), gc.Equals, `
+Fetching charm "wordpress" revision 16 using "latest/stable" channel
+Install the "wordpress" charm with:
+    juju deploy `[1:]+path+"\n")
+}
+
+func (s *downloadSuite) TestRunChannelAndFilePath(c *gc.C) {
+	defer s.setUpMocks(c).Finish()
+	s.api.EXPECT().Info("wordpress").Return(s.info(), nil)
+	s.api.EXPECT().Download("wordpress", "latest/edge", 17).Return(content("wordpress-17"), nil)
+
+	_, err := cmdtesting.RunCommandInDir(c, s.newCommand(), []string{"wordpress", "--channel", "edge", "--filepath", "wp.charm"}, s.dir)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertContent(c, filepath.Join(s.dir, "wp.charm"), "wordpress-17")
+}
+
+func (s *downloadSuite) TestRunUnknownChannel(c *gc.C) {
+	defer s.setUpMocks(c).Finish()
+	s.api.EXPECT().Info("wordpress").Return(s.info(), nil)
+
+	_, err := cmdtesting.RunCommandInDir(c, s.newCommand(), []string{"wordpress", "--channel", "2.0/beta"}, s.dir)
+	c.Assert(err, gc.ErrorMatches, `"wordpress" has no revision released to channel "2.0/beta", available channels: latest/edge, latest/stable`)
+}
+
+func (s *downloadSuite) TestRunHashMismatch(c *gc.C) {
+	defer s.setUpMocks(c).Finish()
+	info := s.info()
+	stable := info.Channels["latest/stable"]
+	stable.HashSHA256 = "0123"
+	info.Channels["latest/stable"] = stable
+	s.api.EXPECT().Info("wordpress").Return(info, nil)
+	s.api.EXPECT().Download("wordpress", "latest/stable", 16).Return(content("wordpress-16"), nil)
+
+	_, err := cmdtesting.RunCommandInDir(c, s.newCommand(), []string{"wordpress"}, s.dir)
+	c.Assert(err, gc.ErrorMatches, `downloaded content has SHA256 hash ".*", expected "0123"`)
+
+	files, err := ioutil.ReadDir(s.dir)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(files, gc.HasLen, 0)
+}
+
+func (s *downloadSuite) TestRunNoHash(c *gc.C) {
+	defer s.setUpMocks(c).Finish()
+	info := s.info()
+	stable := info.Channels["latest/stable"]
+	stable.HashSHA256 = ""
+	info.Channels["latest/stable"] = stable
+	s.api.EXPECT().Info("wordpress").Return(info, nil)
+
+	_, err := cmdtesting.RunCommandInDir(c, s.newCommand(), []string{"wordpress"}, s.dir)
+	c.Assert(err, gc.ErrorMatches, `no SHA256 hash is available for charm "wordpress" revision 16`)
+
+	files, err := ioutil.ReadDir(s.dir)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(files, gc.HasLen, 0)
+}
+
+func (s *downloadSuite) TestRunDownloadError(c *gc.C) {
+	defer s.setUpMocks(c).Finish()
+	s.api.EXPECT().Info("wordpress").Return(s.info(), nil)
+	s.api.EXPECT().Download("wordpress", "latest/stable", 16).Return(nil, errors.New("boom"))
+
+	_, err := cmdtesting.RunCommandInDir(c, s.newCommand(), []string{"wordpress"}, s.dir)
+	c.Assert(err, gc.ErrorMatches, `boom`)
+}
+
+// info returns the info reported for wordpress by the controller.
+func (s *downloadSuite) info() params.InfoResponse {
+	stable, edge := "latest/stable", "latest/edge"
+	return params.InfoResponse{
+		Type:           "charm",
+		Name:           "wordpress",
+		DefaultChannel: stable,
+		Channels: map[string]params.Channel{
+			stable: {
+				Track:       "latest",
+				Risk:        "stable",
+				Revision:    16,
+				DownloadURL: "https://api.example.com/download/wordpress_16.charm",
+				HashSHA256:  "273735fa49d42695f443eec110f30708747e8f35ba7a67aa6314f2c28a6ecdd7",
+			},
+			edge: {
+				Track:       "latest",
+				Risk:        "edge",
+				Revision:    17,
+				DownloadURL: "https://api.example.com/download/wordpress_17.charm",
+				HashSHA256:  "68f48b9e93cd9a962ab45883fdf2a6b51fa14fa63ab4d77a7efd1f3c4375535b",
+			},
+		},
+	}
+}
+
+// content returns a reader of the given content, as returned by the
+// controller.
+func content(data string) io.ReadCloser {
+	return ioutil.NopCloser(strings.NewReader(data))
+}
+
+func (s *downloadSuite) assertContent(c *gc.C, path, content string) {
+	data, err := ioutil.ReadFile(path)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), gc.Equals, content)
+}
+
+func (s *downloadSuite) newCommand() modelcmd.ModelCommand {
+	command := &downloadCommand{api: s.api}
+	command.SetClientStore(jujuclienttesting.MinimalStore())
+	return modelcmd.Wrap(command)
+}
+
+func (s *downloadSuite) setUpMocks(c *gc.C) *gomock.Controller {
+	ctrl := gomock.NewController(c)
+	s.api = mocks.NewMockDownloadCommandAPI(ctrl)
+	s.api.EXPECT().Close()
+	return ctrl
+}
@@ -0,0 +1,166 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package charmhub
+
+import (
+	"io"
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+
+	"github.com/juju/juju/api/charmhub"
+	"github.com/juju/juju/apiserver/params"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/cmd/output"
+)
+
+const (
+	findSummary = "Queries the charm hub store for available charms or bundles."
+	findDoc     = `
+The find command queries the charm hub store configured for the model by
+the "charmhub-url" model config for charms and bundles whose name or
+summary match the query. With no query, all charms and bundles are
+listed. The store is queried by the controller, so the controller's proxy
+settings apply.
+
+Examples:
+    juju find wordpress
+    juju find --format yaml database
+
+See also:
+    download
+    info
+`
+)
+
+// NewFindCommand wraps findCommand with sane model settings.
+func NewFindCommand() cmd.Command {
+	return modelcmd.Wrap(&findCommand{})
+}
+
+// findCommand supplies the "find" CLI command used to query the
+// charm hub store.
+type findCommand struct {
+	modelcmd.ModelCommandBase
+	out cmd.Output
+
+	api FindCommandAPI
+
+	query string
+}
+
+// Info returns help related info about the command, it implements
+// part of the cmd.Command interface.
+func (c *findCommand) Info() *cmd.Info {
+	info := &cmd.Info{
+		Name:    "find",
+		Args:    "[options] [<query>]",
+		Purpose: findSummary,
+		Doc:     findDoc,
+	}
+	return jujucmd.Info(info)
+}
+
+// SetFlags defines flags which can be used with the find command.
+// It implements part of the cmd.Command interface.
+func (c *findCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ModelCommandBase.SetFlags(f)
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatFindTabular,
+	})
+}
+
+// Init initializes the find command, including validating the provided
+// flags. It implements part of the cmd.Command interface.
+func (c *findCommand) Init(args []string) error {
+	if len(args) > 1 {
+		return errors.Errorf("expected at most one query")
+	}
+	if len(args) == 1 {
+		c.query = args[0]
+	}
+	return nil
+}
+
+// Run is the business logic of the find command.  It implements the meaty
+// part of the cmd.Command interface.
+func (c *findCommand) Run(ctx *cmd.Context) error {
+	client, err := c.getAPI()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	results, err := client.Find(c.query)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(results) == 0 {
+		ctx.Infof("No matching charms or bundles found for %q.", c.query)
+		return nil
+	}
+	return c.out.Write(ctx, convertFind(results))
+}
+
+// getAPI returns the API that supplies methods
+// required to execute this command.
+func (c *findCommand) getAPI() (FindCommandAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	api, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Annotate(err, "opening API connection")
+	}
+	return charmhub.NewClient(api), nil
+}
+
+// findOutput is a single result of the find command.
+type findOutput struct {
+	Type      string   `yaml:"type" json:"type"`
+	ID        string   `yaml:"id" json:"id"`
+	Name      string   `yaml:"name" json:"name"`
+	Publisher string   `yaml:"publisher,omitempty" json:"publisher,omitempty"`
+	Summary   string   `yaml:"summary,omitempty" json:"summary,omitempty"`
+	Version   string   `yaml:"version,omitempty" json:"version,omitempty"`
+	Series    []string `yaml:"series,omitempty" json:"series,omitempty"`
+	StoreURL  string   `yaml:"store-url,omitempty" json:"store-url,omitempty"`
+}
+
+func convertFind(results []params.FindResponse) []findOutput {
+	out := make([]findOutput, len(results))
+	for i, r := range results {
+		out[i] = findOutput{
+			Type:      r.Type,
+			ID:        r.ID,
+			Name:      r.Name,
+			Publisher: r.Publisher,
+			Summary:   r.Summary,
+			Version:   r.Version,
+			Series:    r.Series,
+			StoreURL:  r.StoreURL,
+		}
+	}
+	return out
+}
+
+func formatFindTabular(writer io.Writer, value interface{}) error {
+	results, ok := value.([]findOutput)
+	if !ok {
+		return errors.Errorf("expected value of type %T, got %T", results, value)
+	}
+
+	tw := output.TabWriter(writer)
+	w := output.Wrapper{tw}
+	w.Println("Name", "Type", "Version", "Series", "Publisher", "Summary")
+	for _, r := range results {
+		w.Println(r.Name, r.Type, r.Version, strings.Join(r.Series, ","), r.Publisher, r.Summary)
+	}
+	return tw.Flush()
+}
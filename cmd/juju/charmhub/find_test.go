@@ -0,0 +1,94 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package charmhub
+
+import (
+	"github.com/golang/mock/gomock"
+	"github.com/juju/cmd/cmdtesting"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/charmhub/mocks"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/jujuclient/jujuclienttesting"
+)
+
+type findSuite struct {
+	api *mocks.MockFindCommandAPI
+}
+
+var _ = gc.Suite(&findSuite{})
+
+func (s *findSuite) TestInitTooManyArgs(c *gc.C) {
+	cmd := &findCommand{}
+	err := cmd.Init([]string{"foo", "bar"})
+	c.Assert(err, gc.ErrorMatches, "expected at most one query")
+}
+
+func (s *findSuite) TestRun(c *gc.C) {
+	defer s.setUpMocks(c).Finish()
+	s.api.EXPECT().Find("word").Return([]params.FindResponse{{
+		Type:      "charm",
+		ID:        "wordpress-id",
+		Name:      "wordpress",
+		Publisher: "WordPress Charmers",
+		Summary:   "WordPress is a full featured web blogging tool.",
+		Version:   "1.0.3",
+		Series:    []string{"bionic", "focal"},
+	}, {
+		Type:      "bundle",
+		ID:        "wordpress-simple-id",
+		Name:      "wordpress-simple",
+		Publisher: "WordPress Charmers",
+		Summary:   "A simple WordPress deployment.",
+		Version:   "2",
+	}}, nil)
+
+	ctx, err := cmdtesting.RunCommand(c, s.newCommand(), "word")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, `
+Name              Type    Version  Series        Publisher           Summary
+wordpress         charm   1.0.3    bionic,focal  WordPress Charmers  WordPress is a full featured web blogging tool.
+wordpress-simple  bundle  2                      WordPress Charmers  A simple WordPress deployment.
+
+`[1:])
+}
+
+func (s *findSuite) TestRunJSON(c *gc.C) {
+	defer s.setUpMocks(c).Finish()
+	s.api.EXPECT().Find("").Return([]params.FindResponse{{
+		Type:    "charm",
+		ID:      "mysql-id",
+		Name:    "mysql",
+		Version: "8.0",
+	}}, nil)
+
+	ctx, err := cmdtesting.RunCommand(c, s.newCommand(), "--format", "json")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, `[{"type":"charm","id":"mysql-id","name":"mysql","version":"8.0"}]`+"\n")
+}
+
+func (s *findSuite) TestRunNoResults(c *gc.C) {
+	defer s.setUpMocks(c).Finish()
+	s.api.EXPECT().Find("ghost").Return(nil, nil)
+
+	ctx, err := cmdtesting.RunCommand(c, s.newCommand(), "ghost")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, "")
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, "No matching charms or bundles found for \"ghost\".\n")
+}
+
+func (s *findSuite) newCommand() modelcmd.ModelCommand {
+	command := &findCommand{api: s.api}
+	command.SetClientStore(jujuclienttesting.MinimalStore())
+	return modelcmd.Wrap(command)
+}
+
+func (s *findSuite) setUpMocks(c *gc.C) *gomock.Controller {
+	ctrl := gomock.NewController(c)
+	s.api = mocks.NewMockFindCommandAPI(ctrl)
+	s.api.EXPECT().Close()
+	return ctrl
+}
@@ -4,28 +4,38 @@
 package charmhub
 
 import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/juju/charm/v7"
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
 	"github.com/juju/gnuflag"
 
 	"github.com/juju/juju/api/charmhub"
+	"github.com/juju/juju/apiserver/params"
 	jujucmd "github.com/juju/juju/cmd"
 	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/cmd/output"
 )
 
 const (
 	infoSummary = "Displays detailed information about charm hub charms."
 	infoDoc     = `
-The charm can be specified by name or by path. Names are looked for both in the
-store and in the deployed charms.
+The charm or bundle is specified by name, and is looked up in the charm hub
+store configured for the model by the "charmhub-url" model config. The
+store is queried by the controller, so the controller's proxy settings
+apply.
 
 Examples:
     juju info postgresql
+    juju info postgresql --format yaml
 
 See also:
     download
     find
-    refresh
 `
 )
 
@@ -38,10 +48,10 @@ func NewInfoCommand() cmd.Command {
 // about charm snaps.
 type infoCommand struct {
 	modelcmd.ModelCommandBase
+	out cmd.Output
 
 	api InfoCommandAPI
 
-	verbose       bool
 	charmOrBundle string
 }
 
@@ -61,6 +71,11 @@ func (c *infoCommand) Info() *cmd.Info {
 // It implements part of the cmd.Command interface.
 func (c *infoCommand) SetFlags(f *gnuflag.FlagSet) {
 	c.ModelCommandBase.SetFlags(f)
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatInfoTabular,
+	})
 }
 
 // Init initializes the info command, including validating the provided
@@ -69,7 +84,7 @@ func (c *infoCommand) Init(args []string) error {
 	if len(args) != 1 {
 		return errors.Errorf("expected a charm or bundle name")
 	}
-	if err := c.validateCharmOrBundle(args[0]); err != nil {
+	if err := validateCharmOrBundle(args[0]); err != nil {
 		return err
 	}
 	c.charmOrBundle = args[0]
@@ -85,13 +100,19 @@ func (c *infoCommand) Run(ctx *cmd.Context) error {
 	}
 	defer func() { _ = client.Close() }()
 
-	return errors.NotImplementedf("business logic of info command")
+	info, err := client.Info(c.charmOrBundle)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return c.out.Write(ctx, convertInfo(info))
 }
 
-func (c *infoCommand) validateCharmOrBundle(_ string) error {
-	// TODO:
-	// Implement validation of charm hub charm names.  Exit for
-	// charmstore and local charms.
+// validateCharmOrBundle checks that the argument is the name of a charm
+// or bundle, rather than a charm store URL or a path to a local charm.
+func validateCharmOrBundle(name string) error {
+	if !charm.IsValidName(name) {
+		return errors.NotValidf("charm or bundle name %q", name)
+	}
 	return nil
 }
 
@@ -108,3 +129,142 @@ func (c *infoCommand) getAPI() (InfoCommandAPI, error) {
 	client := charmhub.NewClient(api)
 	return client, nil
 }
+
+// infoOutput is the formatted output of the info command.
+type infoOutput struct {
+	Type        string                   `yaml:"type" json:"type"`
+	ID          string                   `yaml:"id" json:"id"`
+	Name        string                   `yaml:"name" json:"name"`
+	Publisher   string                   `yaml:"publisher,omitempty" json:"publisher,omitempty"`
+	Summary     string                   `yaml:"summary,omitempty" json:"summary,omitempty"`
+	Description string                   `yaml:"description,omitempty" json:"description,omitempty"`
+	StoreURL    string                   `yaml:"store-url,omitempty" json:"store-url,omitempty"`
+	Default     string                   `yaml:"default-channel,omitempty" json:"default-channel,omitempty"`
+	Tracks      []string                 `yaml:"tracks,omitempty" json:"tracks,omitempty"`
+	Channels    map[string]channelOutput `yaml:"channels,omitempty" json:"channels,omitempty"`
+}
+
+type channelOutput struct {
+	Revision   int      `yaml:"revision" json:"revision"`
+	Version    string   `yaml:"version" json:"version"`
+	Size       int      `yaml:"size" json:"size"`
+	ReleasedAt string   `yaml:"released-at,omitempty" json:"released-at,omitempty"`
+	Series     []string `yaml:"series,omitempty" json:"series,omitempty"`
+}
+
+func convertInfo(info params.InfoResponse) infoOutput {
+	out := infoOutput{
+		Type:        info.Type,
+		ID:          info.ID,
+		Name:        info.Name,
+		Publisher:   info.Publisher,
+		Summary:     info.Summary,
+		Description: info.Description,
+		StoreURL:    info.StoreURL,
+		Default:     info.DefaultChannel,
+		Tracks:      info.Tracks,
+	}
+	if len(info.Channels) > 0 {
+		out.Channels = make(map[string]channelOutput)
+	}
+	for name, ch := range info.Channels {
+		out.Channels[name] = channelOutput{
+			Revision:   ch.Revision,
+			Version:    ch.Version,
+			Size:       ch.Size,
+			ReleasedAt: ch.ReleasedAt,
+			Series:     ch.Series,
+		}
+	}
+	return out
+}
+
+// riskOrder is the order channels of a track are listed in.
+var riskOrder = []string{"stable", "candidate", "beta", "edge"}
+
+func formatInfoTabular(writer io.Writer, value interface{}) error {
+	info, ok := value.(infoOutput)
+	if !ok {
+		return errors.Errorf("expected value of type %T, got %T", info, value)
+	}
+
+	fmt.Fprintf(writer, "name: %s\n", info.Name)
+	fmt.Fprintf(writer, "type: %s\n", info.Type)
+	if info.Publisher != "" {
+		fmt.Fprintf(writer, "publisher: %s\n", info.Publisher)
+	}
+	if info.Summary != "" {
+		fmt.Fprintf(writer, "summary: %s\n", info.Summary)
+	}
+	if info.StoreURL != "" {
+		fmt.Fprintf(writer, "store-url: %s\n", info.StoreURL)
+	}
+	if info.Description != "" {
+		fmt.Fprintln(writer, "description: |")
+		for _, line := range strings.Split(strings.TrimRight(info.Description, "\n"), "\n") {
+			fmt.Fprintf(writer, "  %s\n", line)
+		}
+	}
+	if len(info.Channels) == 0 {
+		return nil
+	}
+
+	fmt.Fprintln(writer)
+	tw := output.TabWriter(writer)
+	w := output.Wrapper{tw}
+	w.Println("Channel", "Version", "Revision", "Series", "Default")
+	for _, name := range sortedChannels(info) {
+		ch := info.Channels[name]
+		var isDefault string
+		if name == info.Default {
+			isDefault = "*"
+		}
+		w.Println(name, ch.Version, ch.Revision, strings.Join(ch.Series, ","), isDefault)
+	}
+	return tw.Flush()
+}
+
+// sortedChannels returns the channel names ordered by track, in the
+// order the tracks were listed, and then by decreasing stability.
+func sortedChannels(info infoOutput) []string {
+	trackIndex := make(map[string]int)
+	for i, track := range info.Tracks {
+		trackIndex[track] = i
+	}
+	riskIndex := make(map[string]int)
+	for i, risk := range riskOrder {
+		riskIndex[risk] = i
+	}
+	split := func(name string) (int, int) {
+		parts := strings.SplitN(name, "/", 2)
+		if len(parts) != 2 {
+			return len(info.Tracks), len(riskOrder)
+		}
+		t, ok := trackIndex[parts[0]]
+		if !ok {
+			t = len(info.Tracks)
+		}
+		r, ok := riskIndex[parts[1]]
+		if !ok {
+			r = len(riskOrder)
+		}
+		return t, r
+	}
+
+	names := make([]string, 0, len(info.Channels))
+	for name := range info.Channels {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		ti, ri := split(names[i])
+		tj, rj := split(names[j])
+		if ti != tj {
+			return ti < tj
+		}
+		if ri != rj {
+			return ri < rj
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
@@ -5,11 +5,15 @@ package charmhub
 
 import (
 	"github.com/golang/mock/gomock"
+	"github.com/juju/cmd/cmdtesting"
 	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/juju/charmhub/mocks"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/jujuclient/jujuclienttesting"
 )
 
 type infoSuite struct {
@@ -30,11 +34,84 @@ func (s *infoSuite) TestInitSuccess(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 }
 
-func (s *infoSuite) TestRunNotImplemented(c *gc.C) {
+func (s *infoSuite) TestInitCharmURL(c *gc.C) {
+	cmd := &infoCommand{}
+	err := cmd.Init([]string{"cs:test-1"})
+	c.Assert(err, gc.ErrorMatches, `charm or bundle name "cs:test-1" not valid`)
+}
+
+func (s *infoSuite) TestRun(c *gc.C) {
+	defer s.setUpMocks(c).Finish()
+	s.api.EXPECT().Info("wordpress").Return(wordpressInfo(), nil)
+
+	ctx, err := cmdtesting.RunCommand(c, s.newCommand(), "wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, `
+name: wordpress
+type: charm
+publisher: WordPress Charmers
+summary: WordPress is a full featured web blogging tool.
+store-url: https://charmhub.io/wordpress
+description: |
+  This will install and setup WordPress.
+  It is optimized for scale.
+
+Channel        Version  Revision  Series        Default
+latest/stable  1.0.3    16        bionic,focal  *
+latest/edge    1.0.4    17        focal         
+2.0/beta       2.0.0    20        focal         
+
+`[1:])
+}
+
+func (s *infoSuite) TestRunYAML(c *gc.C) {
 	defer s.setUpMocks(c).Finish()
-	cmd := &infoCommand{api: s.api}
-	err := cmd.Run(nil)
-	c.Assert(err, jc.Satisfies, errors.IsNotImplemented)
+	info := wordpressInfo()
+	info.Channels = map[string]params.Channel{
+		"latest/stable": info.Channels["latest/stable"],
+	}
+	info.Tracks = []string{"latest"}
+	s.api.EXPECT().Info("wordpress").Return(info, nil)
+
+	ctx, err := cmdtesting.RunCommand(c, s.newCommand(), "wordpress", "--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, `
+type: charm
+id: wordpress-id
+name: wordpress
+publisher: WordPress Charmers
+summary: WordPress is a full featured web blogging tool.
+description: |-
+  This will install and setup WordPress.
+  It is optimized for scale.
+store-url: https://charmhub.io/wordpress
+default-channel: latest/stable
+tracks:
+- latest
+channels:
+  latest/stable:
+    revision: 16
+    version: 1.0.3
+    size: 12
+    released-at: "2020-05-01T10:00:00Z"
+    series:
+    - bionic
+    - focal
+`[1:])
+}
+
+func (s *infoSuite) TestRunNotFound(c *gc.C) {
+	defer s.setUpMocks(c).Finish()
+	s.api.EXPECT().Info("ghost").Return(params.InfoResponse{}, errors.NotFoundf("charm %q", "ghost"))
+
+	_, err := cmdtesting.RunCommand(c, s.newCommand(), "ghost")
+	c.Assert(err, gc.ErrorMatches, `charm "ghost" not found`)
+}
+
+func (s *infoSuite) newCommand() modelcmd.ModelCommand {
+	command := &infoCommand{api: s.api}
+	command.SetClientStore(jujuclienttesting.MinimalStore())
+	return modelcmd.Wrap(command)
 }
 
 func (s *infoSuite) setUpMocks(c *gc.C) *gomock.Controller {
@@ -43,3 +120,44 @@ func (s *infoSuite) setUpMocks(c *gc.C) *gomock.Controller {
 	s.api.EXPECT().Close()
 	return ctrl
 }
+
+func wordpressInfo() params.InfoResponse {
+	return params.InfoResponse{
+		Type:           "charm",
+		ID:             "wordpress-id",
+		Name:           "wordpress",
+		Description:    "This will install and setup WordPress.\nIt is optimized for scale.",
+		Publisher:      "WordPress Charmers",
+		Summary:        "WordPress is a full featured web blogging tool.",
+		StoreURL:       "https://charmhub.io/wordpress",
+		DefaultChannel: "latest/stable",
+		Tracks:         []string{"latest", "2.0"},
+		Channels: map[string]params.Channel{
+			"latest/stable": {
+				ReleasedAt: "2020-05-01T10:00:00Z",
+				Track:      "latest",
+				Risk:       "stable",
+				Revision:   16,
+				Size:       12,
+				Version:    "1.0.3",
+				Series:     []string{"bionic", "focal"},
+			},
+			"latest/edge": {
+				Track:    "latest",
+				Risk:     "edge",
+				Revision: 17,
+				Size:     12,
+				Version:  "1.0.4",
+				Series:   []string{"focal"},
+			},
+			"2.0/beta": {
+				Track:    "2.0",
+				Risk:     "beta",
+				Revision: 20,
+				Size:     12,
+				Version:  "2.0.0",
+				Series:   []string{"focal"},
+			},
+		},
+	}
+}
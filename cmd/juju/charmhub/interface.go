@@ -3,8 +3,30 @@
 
 package charmhub
 
+import (
+	"io"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
 // InfoCommandAPI describes API methods required
 // to execute the info command.
 type InfoCommandAPI interface {
+	Info(string) (params.InfoResponse, error)
+	Close() error
+}
+
+// FindCommandAPI describes API methods required
+// to execute the find command.
+type FindCommandAPI interface {
+	Find(string) ([]params.FindResponse, error)
+	Close() error
+}
+
+// DownloadCommandAPI describes API methods required
+// to execute the download command.
+type DownloadCommandAPI interface {
+	Info(string) (params.InfoResponse, error)
+	Download(name, channel string, revision int) (io.ReadCloser, error)
 	Close() error
 }
@@ -1,47 +0,0 @@
-// Code generated by MockGen. DO NOT EDIT.
-// Source: github.com/juju/juju/cmd/juju/charmhub (interfaces: InfoCommandAPI)
-
-// Package mocks is a generated GoMock package.
-package mocks
-
-import (
-	gomock "github.com/golang/mock/gomock"
-	reflect "reflect"
-)
-
-// MockInfoCommandAPI is a mock of InfoCommandAPI interface
-type MockInfoCommandAPI struct {
-	ctrl     *gomock.Controller
-	recorder *MockInfoCommandAPIMockRecorder
-}
-
-// MockInfoCommandAPIMockRecorder is the mock recorder for MockInfoCommandAPI
-type MockInfoCommandAPIMockRecorder struct {
-	mock *MockInfoCommandAPI
-}
-
-// NewMockInfoCommandAPI creates a new mock instance
-func NewMockInfoCommandAPI(ctrl *gomock.Controller) *MockInfoCommandAPI {
-	mock := &MockInfoCommandAPI{ctrl: ctrl}
-	mock.recorder = &MockInfoCommandAPIMockRecorder{mock}
-	return mock
-}
-
-// EXPECT returns an object that allows the caller to indicate expected use
-func (m *MockInfoCommandAPI) EXPECT() *MockInfoCommandAPIMockRecorder {
-	return m.recorder
-}
-
-// Close mocks base method
-func (m *MockInfoCommandAPI) Close() error {
-	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Close")
-	ret0, _ := ret[0].(error)
-	return ret0
-}
-
-// Close indicates an expected call of Close
-func (mr *MockInfoCommandAPIMockRecorder) Close() *gomock.Call {
-	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockInfoCommandAPI)(nil).Close))
-}
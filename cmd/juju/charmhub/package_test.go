@@ -9,7 +9,7 @@ import (
 	gc "gopkg.in/check.v1"
 )
 
-//go:generate go run github.com/golang/mock/mockgen -package mocks -destination ./mocks/info_mock.go github.com/juju/juju/cmd/juju/charmhub InfoCommandAPI
+//go:generate go run github.com/golang/mock/mockgen -package mocks -destination ./mocks/api_mock.go github.com/juju/juju/cmd/juju/charmhub InfoCommandAPI,FindCommandAPI,DownloadCommandAPI
 
 func TestPackage(t *testing.T) {
 	gc.TestingT(t)
@@ -37,6 +37,7 @@ import (
 	"github.com/juju/juju/cmd/juju/crossmodel"
 	"github.com/juju/juju/cmd/juju/firewall"
 	"github.com/juju/juju/cmd/juju/gui"
+	"github.com/juju/juju/cmd/juju/lxd"
 	"github.com/juju/juju/cmd/juju/machine"
 	"github.com/juju/juju/cmd/juju/metricsdebug"
 	"github.com/juju/juju/cmd/juju/model"
@@ -358,6 +359,12 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	r.Register(user.NewLogoutCommand())
 	r.Register(user.NewRemoveCommand())
 	r.Register(user.NewWhoAmICommand())
+	r.Register(user.NewAddTokenCommand())
+	r.Register(user.NewTokensCommand())
+	r.Register(user.NewRevokeTokenCommand())
+	r.Register(user.NewEnableMFACommand())
+	r.Register(user.NewDisableMFACommand())
+	r.Register(user.NewResetMFACommand())
 
 	// Manage cached images
 	r.Register(cachedimages.NewRemoveCommand())
@@ -365,10 +372,16 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 
 	// Manage machines
 	r.Register(machine.NewAddCommand())
+	r.Register(machine.NewEnlistMachinesCommand())
 	r.Register(machine.NewRemoveCommand())
 	r.Register(machine.NewListMachinesCommand())
 	r.Register(machine.NewShowMachineCommand())
 	r.Register(machine.NewUpgradeSeriesCommand())
+	r.Register(machine.NewMachineUserDataCommand())
+
+	// Manage LXD clusters
+	r.Register(lxd.NewShowClusterCommand())
+	r.Register(lxd.NewRebalanceClusterCommand())
 
 	// Manage model
 	r.Register(model.NewConfigCommand())
@@ -377,6 +390,11 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	r.Register(model.NewDestroyCommand())
 	r.Register(model.NewGrantCommand())
 	r.Register(model.NewRevokeCommand())
+	r.Register(model.NewAddRoleCommand())
+	r.Register(model.NewRemoveRoleCommand())
+	r.Register(model.NewRolesCommand())
+	r.Register(model.NewAssignRoleCommand())
+	r.Register(model.NewUnassignRoleCommand())
 	r.Register(model.NewShowCommand())
 	r.Register(model.NewModelCredentialCommand())
 	if featureflag.Enabled(feature.Branches) || featureflag.Enabled(feature.Generations) {
@@ -386,6 +404,7 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 		r.Register(model.NewBranchCommand())
 		r.Register(model.NewDiffCommand())
 		r.Register(model.NewAbortCommand())
+		r.Register(model.NewAutoPromoteCommand())
 		r.Register(model.NewCommitsCommand())
 		r.Register(model.NewShowCommitCommand())
 	}
@@ -469,6 +488,7 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	r.Register(controller.NewEnableDestroyControllerCommand())
 	r.Register(controller.NewShowControllerCommand())
 	r.Register(controller.NewConfigCommand())
+	r.Register(controller.NewRotateControllerCACommand())
 
 	// Debug Metrics
 	r.Register(metricsdebug.New())
@@ -499,6 +519,7 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	r.Register(caas.NewUpdateCAASCommand(&cloudToCommandAdapter{}))
 	r.Register(caas.NewRemoveCAASCommand(&cloudToCommandAdapter{}))
 	r.Register(application.NewScaleApplicationCommand())
+	r.Register(application.NewAdoptWorkloadCommand())
 
 	// Manage Application Credential Access
 	r.Register(application.NewTrustCommand())
@@ -534,6 +555,8 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	// CharmHub related commands
 	if featureflag.Enabled(feature.CharmHubIntegration) {
 		r.Register(charmhub.NewInfoCommand())
+		r.Register(charmhub.NewFindCommand())
+		r.Register(charmhub.NewDownloadCommand())
 	}
 
 	// Commands registered elsewhere.
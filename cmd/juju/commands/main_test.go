@@ -471,14 +471,18 @@ var commandNames = []string{
 	"add-machine",
 	"add-model",
 	"add-relation",
+	"add-role",
 	"add-space",
 	"add-ssh-key",
 	"add-storage",
 	"add-subnet",
+	"add-token",
 	"add-unit",
 	"add-user",
+	"adopt-k8s-workload",
 	"agree",
 	"agreements",
+	"assign-role",
 	"attach",
 	"attach-resource",
 	"attach-storage",
@@ -515,13 +519,16 @@ var commandNames = []string{
 	"detach-storage",
 	"diff-bundle",
 	"disable-command",
+	"disable-mfa",
 	"disable-user",
 	"disabled-commands",
 	"download-backup",
 	"enable-command",
 	"enable-destroy-controller",
 	"enable-ha",
+	"enable-mfa",
 	"enable-user",
+	"enlist-machines",
 	"exec",
 	"export-bundle",
 	"expose",
@@ -556,15 +563,18 @@ var commandNames = []string{
 	"list-plans",
 	"list-regions",
 	"list-resources",
+	"list-roles",
 	"list-spaces",
 	"list-ssh-keys",
 	"list-storage",
 	"list-storage-pools",
 	"list-subnets",
+	"list-tokens",
 	"list-users",
 	"list-wallets",
 	"login",
 	"logout",
+	"machine-userdata",
 	"machines",
 	"metrics",
 	"migrate",
@@ -577,6 +587,7 @@ var commandNames = []string{
 	"offers",
 	"payloads",
 	"plans",
+	"rebalance-lxd-cluster",
 	"regions",
 	"register",
 	"relate", //alias for add-relation
@@ -591,6 +602,7 @@ var commandNames = []string{
 	"remove-machine",
 	"remove-offer",
 	"remove-relation",
+	"remove-role",
 	"remove-saas",
 	"remove-space",
 	"remove-ssh-key",
@@ -599,6 +611,7 @@ var commandNames = []string{
 	"remove-unit",
 	"remove-user",
 	"rename-space",
+	"reset-mfa",
 	"resolved",
 	"resolve",
 	"resources",
@@ -607,6 +620,9 @@ var commandNames = []string{
 	"retry-provisioning",
 	"revoke",
 	"revoke-cloud",
+	"revoke-token",
+	"roles",
+	"rotate-controller-ca",
 	"run",
 	"scale-application",
 	"scp",
@@ -627,6 +643,7 @@ var commandNames = []string{
 	"show-controller",
 	"show-credential",
 	"show-credentials",
+	"show-lxd-cluster",
 	"show-machine",
 	"show-model",
 	"show-offer",
@@ -648,7 +665,9 @@ var commandNames = []string{
 	"switch",
 	"sync-agent-binaries",
 	"sync-tools",
+	"tokens",
 	"trust",
+	"unassign-role",
 	"unexpose",
 	"unregister",
 	"update-cloud",
@@ -680,7 +699,7 @@ var optionalFeatures = []string{
 // These are the commands that are behind the `devFeatures`.
 var commandNamesBehindFlags = set.NewStrings(
 	"run", "show-task", "operations", "list-operations", "show-operation",
-	"info",
+	"info", "find", "download",
 )
 
 func (s *MainSuite) TestHelpCommands(c *gc.C) {
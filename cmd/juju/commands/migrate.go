@@ -9,6 +9,7 @@ import (
 	"github.com/juju/cmd"
 	"github.com/juju/collections/set"
 	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
 	"github.com/juju/names/v4"
 	"gopkg.in/macaroon-bakery.v2/httpbakery"
 	"gopkg.in/macaroon.v2"
@@ -33,6 +34,7 @@ func newMigrateCommand() modelcmd.ModelCommand {
 type migrateCommand struct {
 	modelcmd.ModelCommandBase
 	targetController string
+	dryRun           bool
 
 	// Overridden by tests
 	newAPIRoot func(jujuclient.ClientStore, string, string) (api.Connection, error)
@@ -43,6 +45,7 @@ type migrateCommand struct {
 
 type migrateAPI interface {
 	InitiateMigration(spec controller.MigrationSpec) (string, error)
+	MigrationDryRun(spec controller.MigrationSpec) (controller.MigrationDryRunResult, error)
 	IdentityProviderURL() (string, error)
 	Close() error
 }
@@ -80,6 +83,16 @@ This command only starts a model migration - it does not wait for its
 completion. The progress of a migration can be tracked using the
 "status" command and by consulting the logs.
 
+With --dry-run, the migration is checked but not started. All of the
+checks made before a migration are run, and the model is imported into
+the target controller as a temporary model which is then removed. Every
+problem that would prevent the migration is reported, along with any
+conversions the target controller would make to the model's data.
+
+Examples:
+    juju migrate mymodel target
+    juju migrate --dry-run mymodel target
+
 See also:
     login
     controllers
@@ -96,6 +109,12 @@ func (c *migrateCommand) Info() *cmd.Info {
 	})
 }
 
+// SetFlags implements cmd.Command.
+func (c *migrateCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ModelCommandBase.SetFlags(f)
+	f.BoolVar(&c.dryRun, "dry-run", false, "Check the migration, reporting every problem found, without starting it")
+}
+
 // Init implements cmd.Command.
 func (c *migrateCommand) Init(args []string) error {
 	if len(args) < 1 {
@@ -131,6 +150,11 @@ func (c *migrateCommand) Run(ctx *cmd.Context) error {
 		return errors.Trace(err)
 	}
 	spec.ModelUUID = uuids[0]
+	if c.dryRun {
+		// The users of the model are checked by the dry run,
+		// along with everything else.
+		return c.runDryRun(ctx, modelName, spec)
+	}
 	if err := c.checkMigrationFeasibility(spec); err != nil {
 		return errors.Trace(err)
 	}
@@ -151,6 +175,46 @@ func (c *migrateCommand) Run(ctx *cmd.Context) error {
 	return nil
 }
 
+// runDryRun checks the migration without starting it, reporting every
+// problem found.
+func (c *migrateCommand) runDryRun(ctx *cmd.Context, modelName string, spec *controller.MigrationSpec) error {
+	controllerName, err := c.ControllerName()
+	if err != nil {
+		return err
+	}
+	api, err := c.getMigrationAPI(controllerName)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = api.Close() }()
+	result, err := api.MigrationDryRun(*spec)
+	if errors.IsNotSupported(err) {
+		return errors.New("migration dry runs are not supported by this controller")
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+
+	ctx.Infof("Dry run of migration of model %q to controller %q:", modelName, c.targetController)
+	if len(result.Upconversions) > 0 {
+		ctx.Infof("The target controller would convert the model's data:")
+		for _, upconversion := range result.Upconversions {
+			ctx.Infof("- %s", upconversion)
+		}
+	}
+	for _, note := range result.Notes {
+		ctx.Infof("Note: %s", note)
+	}
+	if len(result.Problems) == 0 {
+		ctx.Infof("No problems found; the migration was not started.")
+		return nil
+	}
+	ctx.Infof("Problems found:")
+	for _, problem := range result.Problems {
+		ctx.Infof("- %s", problem)
+	}
+	return errors.Errorf("migration would fail: %d problem(s) found", len(result.Problems))
+}
+
 func (c *migrateCommand) getMigrationSpec() (*controller.MigrationSpec, error) {
 	store := c.ClientStore()
 
@@ -10,6 +10,7 @@ import (
 
 	"github.com/juju/cmd"
 	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
 	"github.com/juju/names/v4"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
@@ -262,6 +263,47 @@ func (s *MigrateSuite) TestSuccess(c *gc.C) {
 	})
 }
 
+func (s *MigrateSuite) TestDryRun(c *gc.C) {
+	s.api.dryRunResult = controller.MigrationDryRunResult{
+		Upconversions: []string{"model upconverted from version 7 to 8"},
+	}
+	ctx, err := s.makeAndRun(c, "--dry-run", "model", "target")
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(cmdtesting.Stderr(ctx), gc.Equals, ""+
+		"Dry run of migration of model \"model\" to controller \"target\":\n"+
+		"The target controller would convert the model's data:\n"+
+		"- model upconverted from version 7 to 8\n"+
+		"No problems found; the migration was not started.\n")
+	c.Check(s.api.dryRunSeen, jc.IsTrue)
+	c.Check(s.api.specSeen.ModelUUID, gc.Equals, modelUUID)
+}
+
+func (s *MigrateSuite) TestDryRunProblems(c *gc.C) {
+	s.api.dryRunResult = controller.MigrationDryRunResult{
+		Problems: []string{
+			"source prechecks: cleanup needed",
+			`target prechecks: model named "model" already exists`,
+		},
+		Notes: []string{"target controller does not support import dry runs"},
+	}
+	ctx, err := s.makeAndRun(c, "--dry-run", "model", "target")
+	c.Assert(err, gc.ErrorMatches, `migration would fail: 2 problem\(s\) found`)
+
+	c.Check(cmdtesting.Stderr(ctx), gc.Equals, ""+
+		"Dry run of migration of model \"model\" to controller \"target\":\n"+
+		"Note: target controller does not support import dry runs\n"+
+		"Problems found:\n"+
+		"- source prechecks: cleanup needed\n"+
+		"- target prechecks: model named \"model\" already exists\n")
+}
+
+func (s *MigrateSuite) TestDryRunNotSupported(c *gc.C) {
+	s.api.dryRunErr = errors.NotSupportedf("migration dry runs")
+	_, err := s.makeAndRun(c, "--dry-run", "model", "target")
+	c.Assert(err, gc.ErrorMatches, "migration dry runs are not supported by this controller")
+}
+
 func (s *MigrateSuite) TestSuccessMacaroons(c *gc.C) {
 	err := s.store.UpdateAccount("target", jujuclient.AccountDetails{
 		User:     "targetuser",
@@ -440,8 +482,11 @@ func (s *MigrateSuite) makeCommand() modelcmd.ModelCommand {
 }
 
 type fakeMigrateAPI struct {
-	specSeen    *controller.MigrationSpec
-	identityURL string
+	specSeen     *controller.MigrationSpec
+	identityURL  string
+	dryRunSeen   bool
+	dryRunResult controller.MigrationDryRunResult
+	dryRunErr    error
 }
 
 func (a *fakeMigrateAPI) InitiateMigration(spec controller.MigrationSpec) (string, error) {
@@ -449,6 +494,12 @@ func (a *fakeMigrateAPI) InitiateMigration(spec controller.MigrationSpec) (strin
 	return "uuid:0", nil
 }
 
+func (a *fakeMigrateAPI) MigrationDryRun(spec controller.MigrationSpec) (controller.MigrationDryRunResult, error) {
+	a.specSeen = &spec
+	a.dryRunSeen = true
+	return a.dryRunResult, a.dryRunErr
+}
+
 func (a *fakeMigrateAPI) IdentityProviderURL() (string, error) {
 	return a.identityURL, nil
 }
@@ -171,6 +171,14 @@ func NewConfigCommandForTest(api controllerAPI, store jujuclient.ClientStore) cm
 	return modelcmd.WrapController(c)
 }
 
+// NewRotateControllerCACommandForTest returns a rotateCACommand with
+// the api provided as specified.
+func NewRotateControllerCACommandForTest(api rotateCAAPI, store jujuclient.ClientStore) cmd.Command {
+	c := &rotateCACommand{api: api}
+	c.SetClientStore(store)
+	return modelcmd.WrapController(c)
+}
+
 type CtrData ctrData
 type ModelData modelData
 
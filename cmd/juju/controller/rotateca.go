@@ -0,0 +1,178 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package controller
+
+import (
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+)
+
+// NewRotateControllerCACommand returns a command that rotates the
+// controller's CA.
+func NewRotateControllerCACommand() cmd.Command {
+	return modelcmd.WrapController(&rotateCACommand{})
+}
+
+type rotateCACommand struct {
+	modelcmd.ControllerCommandBase
+	api rotateCAAPI
+	out cmd.Output
+
+	step string
+}
+
+type rotateCAAPI interface {
+	Close() error
+	ControllerCARotation() (params.ControllerCARotation, error)
+	RotateControllerCA(step string) (params.ControllerCARotation, error)
+}
+
+const rotateCADoc = `
+Replaces the CA that issues the controller's certificates, without agents
+or clients losing their connections to the controller. Rotation is
+performed in steps, and with no step specified the progress of any
+rotation in progress is shown.
+
+The "start" step creates a new CA, cross-signed by the current one, and
+tells all agents to trust both CAs. Once the agents have picked up the new
+CA (this happens without restarting them), the "reissue" step makes the
+controllers issue their certificates from the new CA; each controller
+agent restarts to pick up its new certificate. The "finish" step then
+retires the old CA. It is refused until every controller has reissued its
+certificate, and until a day has passed since the rotation started, so
+that agents that were offline have time to pick up the new CA. The
+database of each controller (the juju-db service) must be restarted
+before finishing, so that it also presents a certificate issued by the
+new CA. A rotation can be abandoned with the "abort" step until
+certificates have been reissued.
+
+The CA certificate recorded for the controller in the local client
+configuration is updated after each step.
+
+Examples:
+
+    juju rotate-controller-ca
+    juju rotate-controller-ca start
+    juju rotate-controller-ca reissue
+    juju rotate-controller-ca finish
+
+See also:
+    controller-config
+    show-controller
+`
+
+// Info implements Command.Info.
+func (c *rotateCACommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "rotate-controller-ca",
+		Args:    "[start|reissue|finish|abort]",
+		Purpose: "Rotates the CA of a controller.",
+		Doc:     rotateCADoc,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *rotateCACommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ControllerCommandBase.SetFlags(f)
+	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
+		"json": cmd.FormatJson,
+		"yaml": cmd.FormatYaml,
+	})
+}
+
+// Init implements Command.Init.
+func (c *rotateCACommand) Init(args []string) error {
+	if len(args) > 0 {
+		switch args[0] {
+		case "start", "reissue", "finish", "abort":
+			c.step, args = args[0], args[1:]
+		default:
+			return errors.Errorf("unknown CA rotation step %q", args[0])
+		}
+	}
+	return cmd.CheckEmpty(args)
+}
+
+func (c *rotateCACommand) getAPI() (rotateCAAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	return c.NewControllerAPIClient()
+}
+
+// caRotationStatus is the output of the rotate-controller-ca command.
+type caRotationStatus struct {
+	Phase    string     `yaml:"phase" json:"phase"`
+	Started  *time.Time `yaml:"started,omitempty" json:"started,omitempty"`
+	Reissued *time.Time `yaml:"reissued,omitempty" json:"reissued,omitempty"`
+	NextStep string     `yaml:"next-step" json:"next-step"`
+}
+
+// Run implements Command.Run.
+func (c *rotateCACommand) Run(ctx *cmd.Context) error {
+	client, err := c.getAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+
+	var rotation params.ControllerCARotation
+	if c.step == "" {
+		rotation, err = client.ControllerCARotation()
+	} else {
+		rotation, err = client.RotateControllerCA(c.step)
+	}
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := c.updateCACert(rotation.CACert); err != nil {
+		return errors.Annotate(err, "updating local controller CA certificate")
+	}
+
+	status := caRotationStatus{
+		Phase:    rotation.Phase,
+		Started:  rotation.Started,
+		Reissued: rotation.Reissued,
+	}
+	switch rotation.Phase {
+	case "":
+		status.Phase = "none"
+		status.NextStep = "start"
+	case "trusting":
+		status.NextStep = "reissue"
+	case "reissued":
+		status.NextStep = "finish"
+	}
+	return c.out.Write(ctx, status)
+}
+
+// updateCACert records the CA certificates the controller currently
+// expects to be trusted in the local client store, so that this client
+// can still connect once the old CA is retired.
+func (c *rotateCACommand) updateCACert(caCert string) error {
+	if caCert == "" {
+		return nil
+	}
+	controllerName, err := c.ControllerName()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	store := c.ClientStore()
+	details, err := store.ControllerByName(controllerName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if details.CACert == caCert {
+		return nil
+	}
+	details.CACert = caCert
+	return store.UpdateController(controllerName, *details)
+}
@@ -0,0 +1,113 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package controller_test
+
+import (
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/cmd/cmdtesting"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/controller"
+	"github.com/juju/juju/jujuclient"
+)
+
+type rotateCASuite struct {
+	baseControllerSuite
+	api   *fakeRotateCAAPI
+	store *jujuclient.MemStore
+}
+
+var _ = gc.Suite(&rotateCASuite{})
+
+func (s *rotateCASuite) SetUpTest(c *gc.C) {
+	s.baseControllerSuite.SetUpTest(c)
+
+	started := time.Date(2020, 5, 1, 10, 0, 0, 0, time.UTC)
+	s.api = &fakeRotateCAAPI{
+		result: params.ControllerCARotation{
+			Phase:   "trusting",
+			CACert:  "old-ca\nnew-ca\n",
+			Started: &started,
+		},
+	}
+	s.store = jujuclient.NewMemStore()
+	s.store.CurrentControllerName = "fake"
+	s.store.Controllers["fake"] = jujuclient.ControllerDetails{
+		ControllerUUID: "deadbeef-0bad-400d-8000-4b1d0d06f00d",
+		CACert:         "old-ca\n",
+	}
+}
+
+func (s *rotateCASuite) newCommand() cmd.Command {
+	return controller.NewRotateControllerCACommandForTest(s.api, s.store)
+}
+
+func (s *rotateCASuite) TestStatus(c *gc.C) {
+	ctx, err := cmdtesting.RunCommand(c, s.newCommand())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.api.step, gc.Equals, "")
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, `
+phase: trusting
+started: 2020-05-01T10:00:00Z
+next-step: reissue
+`[1:])
+}
+
+func (s *rotateCASuite) TestStatusNoRotation(c *gc.C) {
+	s.api.result = params.ControllerCARotation{CACert: "old-ca\n"}
+	ctx, err := cmdtesting.RunCommand(c, s.newCommand())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, `
+phase: none
+next-step: start
+`[1:])
+}
+
+func (s *rotateCASuite) TestStepUpdatesCACert(c *gc.C) {
+	_, err := cmdtesting.RunCommand(c, s.newCommand(), "start")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.api.step, gc.Equals, "start")
+	c.Assert(s.store.Controllers["fake"].CACert, gc.Equals, "old-ca\nnew-ca\n")
+}
+
+func (s *rotateCASuite) TestUnknownStep(c *gc.C) {
+	_, err := cmdtesting.RunCommand(c, s.newCommand(), "sideways")
+	c.Assert(err, gc.ErrorMatches, `unknown CA rotation step "sideways"`)
+}
+
+func (s *rotateCASuite) TestTooManyArgs(c *gc.C) {
+	_, err := cmdtesting.RunCommand(c, s.newCommand(), "start", "whoops")
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["whoops"\]`)
+}
+
+func (s *rotateCASuite) TestError(c *gc.C) {
+	s.api.err = common.ErrPerm
+	_, err := cmdtesting.RunCommand(c, s.newCommand(), "start")
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	c.Assert(s.store.Controllers["fake"].CACert, gc.Equals, "old-ca\n")
+}
+
+type fakeRotateCAAPI struct {
+	result params.ControllerCARotation
+	step   string
+	err    error
+}
+
+func (f *fakeRotateCAAPI) Close() error {
+	return nil
+}
+
+func (f *fakeRotateCAAPI) ControllerCARotation() (params.ControllerCARotation, error) {
+	return f.result, f.err
+}
+
+func (f *fakeRotateCAAPI) RotateControllerCA(step string) (params.ControllerCARotation, error) {
+	f.step = step
+	return f.result, f.err
+}
@@ -6,6 +6,7 @@ package controller
 import (
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
@@ -94,6 +95,7 @@ type ControllerAccessAPI interface {
 	MongoVersion() (string, error)
 	IdentityProviderURL() (string, error)
 	ControllerVersion() (controller.ControllerVersion, error)
+	AutocertStatus() (params.AutocertStatus, error)
 	Close() error
 }
 
@@ -188,6 +190,13 @@ func (c *showControllerCommand) Run(ctx *cmd.Context) error {
 				details.Errors = append(details.Errors, err.Error())
 				mongoVersion = "(error)"
 			}
+			// Fetch the autocert status if the apiserver supports it
+			autocertStatus, err := client.AutocertStatus()
+			if err != nil && !errors.IsNotSupported(err) {
+				details.Errors = append(details.Errors, err.Error())
+			} else if err == nil {
+				details.Autocert = convertAutocertForShow(autocertStatus)
+			}
 		}
 
 		// Fetch identityURL if the apiserver supports it
@@ -291,6 +300,10 @@ type ShowControllerDetails struct {
 	// Account is the account details for the user logged into this controller.
 	Account *AccountDetails `yaml:"account,omitempty" json:"account,omitempty"`
 
+	// Autocert holds details of the certificate obtained for the
+	// controller's autocert DNS name, if one is configured.
+	Autocert *AutocertDetails `yaml:"autocert,omitempty" json:"autocert,omitempty"`
+
 	// Errors is a collection of errors related to accessing this controller details.
 	Errors []string `yaml:"errors,omitempty" json:"errors,omitempty"`
 }
@@ -386,6 +399,42 @@ type AccountDetails struct {
 	Password string `yaml:"password,omitempty" json:"password,omitempty"`
 }
 
+// AutocertDetails holds details of the certificate obtained for the
+// controller's autocert DNS name.
+type AutocertDetails struct {
+	// DNSName is the name the certificate is obtained for.
+	DNSName string `yaml:"dns-name" json:"dns-name"`
+
+	// Issuer is the CA that issued the certificate.
+	Issuer string `yaml:"issuer,omitempty" json:"issuer,omitempty"`
+
+	// NotAfter is when the certificate expires.
+	NotAfter *time.Time `yaml:"not-after,omitempty" json:"not-after,omitempty"`
+
+	// RenewAfter is when the certificate will be renewed.
+	RenewAfter *time.Time `yaml:"renew-after,omitempty" json:"renew-after,omitempty"`
+
+	// LastError is the last error encountered obtaining a certificate.
+	LastError string `yaml:"last-error,omitempty" json:"last-error,omitempty"`
+
+	// LastErrorTime is when LastError was encountered.
+	LastErrorTime *time.Time `yaml:"last-error-time,omitempty" json:"last-error-time,omitempty"`
+}
+
+func convertAutocertForShow(status params.AutocertStatus) *AutocertDetails {
+	if status.DNSName == "" {
+		return nil
+	}
+	return &AutocertDetails{
+		DNSName:       status.DNSName,
+		Issuer:        status.Issuer,
+		NotAfter:      status.NotAfter,
+		RenewAfter:    status.RenewAfter,
+		LastError:     status.Error,
+		LastErrorTime: status.ErrorTime,
+	}
+}
+
 func (c *showControllerCommand) convertControllerForShow(
 	controller *ShowControllerDetails,
 	controllerName string,
@@ -5,6 +5,7 @@ package controller_test
 
 import (
 	"regexp"
+	"time"
 
 	"github.com/juju/cmd"
 	"github.com/juju/cmd/cmdtesting"
@@ -15,6 +16,7 @@ import (
 
 	"github.com/juju/juju/api/base"
 	apicontroller "github.com/juju/juju/api/controller"
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/juju/controller"
 	"github.com/juju/juju/core/model"
 	"github.com/juju/juju/core/permission"
@@ -527,6 +529,33 @@ func (s *ShowControllerSuite) TestShowControllerWithIdentityProvider(c *gc.C) {
 	c.Assert(cmdtesting.Stdout(ctx), jc.Contains, "identity-url: "+expURL)
 }
 
+func (s *ShowControllerSuite) TestShowControllerWithAutocert(c *gc.C) {
+	_ = s.createTestClientStore(c)
+	ctx, err := s.runShowController(c, "aws-test")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Not(jc.Contains), "autocert")
+
+	notAfter := time.Date(2020, 9, 1, 0, 0, 0, 0, time.UTC)
+	renewAfter := time.Date(2020, 8, 2, 0, 0, 0, 0, time.UTC)
+	s.fakeController.autocertStatus = params.AutocertStatus{
+		DNSName:    "controller.example",
+		Issuer:     "CN=Example CA",
+		NotAfter:   &notAfter,
+		RenewAfter: &renewAfter,
+		Error:      "registering ACME account: boom",
+	}
+	ctx, err = s.runShowController(c, "aws-test")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), jc.Contains, `
+  autocert:
+    dns-name: controller.example
+    issuer: CN=Example CA
+    not-after: 2020-09-01T00:00:00Z
+    renew-after: 2020-08-02T00:00:00Z
+    last-error: 'registering ACME account: boom'
+`)
+}
+
 func (s *ShowControllerSuite) TestShowControllerWithCAFingerprint(c *gc.C) {
 	s.controllersYaml = `controllers:
   mallards:
@@ -668,6 +697,7 @@ type fakeController struct {
 	bestAPIVersion    int
 	identityURL       string
 	controllerVersion apicontroller.ControllerVersion
+	autocertStatus    params.AutocertStatus
 }
 
 func (c *fakeController) GetControllerAccess(user string) (permission.Access, error) {
@@ -728,6 +758,10 @@ func (c *fakeController) ControllerVersion() (apicontroller.ControllerVersion, e
 	return c.controllerVersion, nil
 }
 
+func (c *fakeController) AutocertStatus() (params.AutocertStatus, error) {
+	return c.autocertStatus, nil
+}
+
 func (*fakeController) Close() error {
 	return nil
 }
@@ -32,6 +32,16 @@ $ juju offer mysql:db
 $ juju offer mymodel.mysql:db
 $ juju offer db2:db hosted-db2
 $ juju offer db2:db,log hosted-db2
+$ juju offer mysql:db --max-connections 5 --settings-rate-limit 60
+$ juju offer mysql:db --allow-models 4e5c1a6b-8b16-4a0f-8a56-2a8c79d5b7a1
+
+Offer owners may restrict how the offer is consumed. The --max-connections
+option limits how many relations may be made to the offer, and the
+--settings-rate-limit option limits how many relation settings changes
+per minute consumers may publish over each relation.
+The --allow-models option takes a comma separated list of model UUIDs;
+only consuming models in the list may relate to the offer.
+Limits are shown by 'juju show-offer'.
 
 See also:
     consume
@@ -66,6 +76,9 @@ type offerCommand struct {
 
 	// QualifiedModelName stores the name of the model hosting the offer.
 	QualifiedModelName string
+
+	// Limits stores the restrictions placed on consumers of the offer.
+	Limits jujucrossmodel.OfferLimits
 }
 
 // NewApplicationOffersAPI returns an application offers api for the root api endpoint
@@ -99,12 +112,18 @@ func (c *offerCommand) Init(args []string) error {
 		argCount = 2
 		c.OfferName = args[1]
 	}
+	if err := c.Limits.Validate(); err != nil {
+		return errors.Trace(err)
+	}
 	return cmd.CheckEmpty(args[argCount:])
 }
 
 // SetFlags implements Command.SetFlags.
 func (c *offerCommand) SetFlags(f *gnuflag.FlagSet) {
 	c.ControllerCommandBase.SetFlags(f)
+	f.IntVar(&c.Limits.MaxConnections, "max-connections", 0, "Maximum number of relations to the offer (0 for no limit)")
+	f.Var(cmd.NewStringsValue(nil, &c.Limits.AllowedConsumerModels), "allow-models", "Comma separated list of UUIDs of models allowed to relate to the offer")
+	f.IntVar(&c.Limits.SettingsChangesPerMinute, "settings-rate-limit", 0, "Maximum relation settings changes per minute for each relation (0 for no limit)")
 }
 
 // Run implements Command.Run.
@@ -151,7 +170,7 @@ func (c *offerCommand) Run(ctx *cmd.Context) error {
 		c.OfferName = c.Application
 	}
 	// TODO (anastasiamac 2015-11-16) Add a sensible way for user to specify long-ish (at times) description when offering
-	results, err := api.Offer(modelDetails.ModelUUID, c.Application, c.Endpoints, c.OfferName, "")
+	results, err := api.OfferWithLimits(modelDetails.ModelUUID, c.Application, c.Endpoints, c.OfferName, "", c.Limits)
 	if err != nil {
 		return err
 	}
@@ -172,7 +191,9 @@ func (c *offerCommand) Run(ctx *cmd.Context) error {
 // OfferAPI defines the API methods that the offer command uses.
 type OfferAPI interface {
 	Close() error
-	Offer(modelUUID, application string, endpoints []string, offerName string, desc string) ([]params.ErrorResult, error)
+	OfferWithLimits(
+		modelUUID, application string, endpoints []string, offerName string, desc string, limits jujucrossmodel.OfferLimits,
+	) ([]params.ErrorResult, error)
 }
 
 // applicationParse is used to split an application string
@@ -13,6 +13,7 @@ import (
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/juju/crossmodel"
+	jujucrossmodel "github.com/juju/juju/core/crossmodel"
 )
 
 type offerSuite struct {
@@ -107,6 +108,27 @@ func (s *offerSuite) TestOfferMultipleEndpoints(c *gc.C) {
 	s.assertOfferOutput(c, "test", "tst", "tst", []string{"db", "admin"})
 }
 
+func (s *offerSuite) TestOfferWithLimits(c *gc.C) {
+	s.args = []string{
+		"tst:db", "--max-connections", "5", "--settings-rate-limit", "60",
+		"--allow-models", "deadbeef-0bad-400d-8000-4b1d0d06f00d,deadbeef-0bad-400d-8000-4b1d0d06f00e",
+	}
+	s.assertOfferOutput(c, "test", "tst", "tst", []string{"db"})
+	c.Assert(s.mockAPI.limits["tst"], jc.DeepEquals, jujucrossmodel.OfferLimits{
+		MaxConnections:           5,
+		AllowedConsumerModels:    []string{"deadbeef-0bad-400d-8000-4b1d0d06f00d", "deadbeef-0bad-400d-8000-4b1d0d06f00e"},
+		SettingsChangesPerMinute: 60,
+	})
+}
+
+func (s *offerSuite) TestOfferInvalidLimits(c *gc.C) {
+	s.args = []string{"tst:db", "--max-connections", "-1"}
+	s.assertOfferErrorOutput(c, "negative max connections -1 not valid")
+
+	s.args = []string{"tst:db", "--allow-models", "foo"}
+	s.assertOfferErrorOutput(c, `allowed consumer model UUID "foo" not valid`)
+}
+
 func (s *offerSuite) assertOfferOutput(c *gc.C, expectedModel, expectedOffer, expectedApplication string, endpoints []string) {
 	_, err := s.runOffer(c, s.args...)
 	c.Assert(err, jc.ErrorIsNil)
@@ -119,6 +141,7 @@ type mockOfferAPI struct {
 	offers           map[string][]string
 	applications     map[string]string
 	descs            map[string]string
+	limits           map[string]jujucrossmodel.OfferLimits
 }
 
 func newMockOfferAPI() *mockOfferAPI {
@@ -126,6 +149,7 @@ func newMockOfferAPI() *mockOfferAPI {
 	mock.offers = make(map[string][]string)
 	mock.descs = make(map[string]string)
 	mock.applications = make(map[string]string)
+	mock.limits = make(map[string]jujucrossmodel.OfferLimits)
 	return mock
 }
 
@@ -133,7 +157,9 @@ func (s *mockOfferAPI) Close() error {
 	return nil
 }
 
-func (s *mockOfferAPI) Offer(modelUUID, application string, endpoints []string, offerName, desc string) ([]params.ErrorResult, error) {
+func (s *mockOfferAPI) OfferWithLimits(
+	modelUUID, application string, endpoints []string, offerName, desc string, limits jujucrossmodel.OfferLimits,
+) ([]params.ErrorResult, error) {
 	if s.errCall {
 		return nil, errors.New("aborted")
 	}
@@ -149,5 +175,6 @@ func (s *mockOfferAPI) Offer(modelUUID, application string, endpoints []string,
 	s.offers[offerName] = endpoints
 	s.applications[offerName] = application
 	s.descs[offerName] = desc
+	s.limits[offerName] = limits
 	return result, nil
 }
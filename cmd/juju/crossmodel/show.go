@@ -161,6 +161,17 @@ type ShowOfferedApplication struct {
 
 	// Users are the users who can access the offer.
 	Users map[string]OfferUser `yaml:"users,omitempty" json:"users,omitempty"`
+
+	// Limits are the restrictions placed on consumers of the offer.
+	Limits *OfferLimits `yaml:"limits,omitempty" json:"limits,omitempty"`
+}
+
+// OfferLimits defines the serialization behaviour of the restrictions
+// placed on consumers of an offer.
+type OfferLimits struct {
+	MaxConnections           int      `yaml:"max-connections,omitempty" json:"max-connections,omitempty"`
+	SettingsChangesPerMinute int      `yaml:"settings-changes-per-minute,omitempty" json:"settings-changes-per-minute,omitempty"`
+	AllowedModels            []string `yaml:"allowed-models,omitempty" json:"allowed-models,omitempty"`
 }
 
 // convertOffers takes any number of api-formatted remote applications and
@@ -182,6 +193,13 @@ func convertOffers(
 		if one.ApplicationDescription != "" {
 			app.Description = one.ApplicationDescription
 		}
+		if !one.Limits.IsZero() {
+			app.Limits = &OfferLimits{
+				MaxConnections:           one.Limits.MaxConnections,
+				SettingsChangesPerMinute: one.Limits.SettingsChangesPerMinute,
+				AllowedModels:            one.Limits.AllowedConsumerModels,
+			}
+		}
 		url, err := crossmodel.ParseOfferURL(one.OfferURL)
 		if err != nil {
 			return nil, err
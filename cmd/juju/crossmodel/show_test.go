@@ -91,6 +91,57 @@ test-master  fred/model.db2  consume  IBM DB2 Express Server Edition is an entry
 	)
 }
 
+func (s *showSuite) TestShowYamlLimits(c *gc.C) {
+	s.mockAPI.limits = jujucrossmodel.OfferLimits{
+		MaxConnections:        5,
+		AllowedConsumerModels: []string{"deadbeef-0bad-400d-8000-4b1d0d06f00d"},
+	}
+	s.assertShow(
+		c,
+		[]string{"fred/model.db2", "--format", "yaml"},
+		`
+test-master:fred/model.db2:
+  description: IBM DB2 Express Server Edition is an entry level database system
+  access: consume
+  endpoints:
+    db2:
+      interface: http
+      role: requirer
+    log:
+      interface: http
+      role: provider
+  users:
+    bob:
+      display-name: Bob
+      access: consume
+  limits:
+    max-connections: 5
+    allowed-models:
+    - deadbeef-0bad-400d-8000-4b1d0d06f00d
+`[1:],
+	)
+}
+
+func (s *showSuite) TestShowTabularLimits(c *gc.C) {
+	s.mockAPI.limits = jujucrossmodel.OfferLimits{
+		MaxConnections:           5,
+		SettingsChangesPerMinute: 60,
+	}
+	s.assertShow(
+		c,
+		[]string{"fred/model.db2", "--format", "tabular"},
+		`
+Store        URL             Access   Description                                 Endpoint  Interface  Role
+test-master  fred/model.db2  consume  IBM DB2 Express Server Edition is an entry  db2       http       requirer
+                                      level database system                       log       http       provider
+
+Offer           Max connections  Settings changes/min  Allowed models
+fred/model.db2  5                60                    any
+
+`[1:],
+	)
+}
+
 func (s *showSuite) TestShowDifferentController(c *gc.C) {
 	s.mockAPI.controllerName = "different"
 	s.assertShow(
@@ -155,6 +206,7 @@ func (s *showSuite) assertShowError(c *gc.C, args []string, expected string) {
 type mockShowAPI struct {
 	controllerName string
 	msg, desc      string
+	limits         jujucrossmodel.OfferLimits
 }
 
 func (s mockShowAPI) Close() error {
@@ -181,5 +233,6 @@ func (s mockShowAPI) ApplicationOffer(url string) (*jujucrossmodel.ApplicationOf
 		Users: []jujucrossmodel.OfferUserDetails{{
 			UserName: "bob", DisplayName: "Bob", Access: "consume",
 		}},
+		Limits: s.limits,
 	}, nil
 }
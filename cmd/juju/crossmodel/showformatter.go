@@ -77,10 +77,57 @@ func formatOfferedEndpointsTabular(writer io.Writer, all map[string]ShowOfferedA
 			offerAccess = ""
 		}
 	}
+	formatOfferLimitsTabular(w, all)
 	tw.Flush()
 	return nil
 }
 
+// formatOfferLimitsTabular writes a summary of any restrictions placed
+// on consumers of the offers.
+func formatOfferLimitsTabular(w output.Wrapper, all map[string]ShowOfferedApplication) {
+	var urls []string
+	for urlStr, one := range all {
+		if one.Limits != nil {
+			urls = append(urls, urlStr)
+		}
+	}
+	if len(urls) == 0 {
+		return
+	}
+	sort.Strings(urls)
+
+	w.Println()
+	w.Println("Offer", "Max connections", "Settings changes/min", "Allowed models")
+	for _, urlStr := range urls {
+		limits := all[urlStr].Limits
+		offerURL := urlStr
+		if url, err := crossmodel.ParseOfferURL(urlStr); err == nil {
+			url.Source = ""
+			offerURL = url.String()
+		}
+		w.Println(
+			offerURL,
+			limitString(limits.MaxConnections),
+			limitString(limits.SettingsChangesPerMinute),
+			allowedString(limits.AllowedModels),
+		)
+	}
+}
+
+func limitString(limit int) string {
+	if limit == 0 {
+		return "-"
+	}
+	return fmt.Sprint(limit)
+}
+
+func allowedString(allowed []string) string {
+	if len(allowed) == 0 {
+		return "any"
+	}
+	return strings.Join(allowed, ",")
+}
+
 func descAt(lines []string, i int) string {
 	if i < len(lines) {
 		return lines[i]
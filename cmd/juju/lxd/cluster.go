@@ -0,0 +1,235 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package lxd provides commands for inspecting and balancing the placement
+// of workloads across the members of an LXD cluster.
+package lxd
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+
+	"github.com/juju/juju/api"
+	cloudapi "github.com/juju/juju/api/cloud"
+	"github.com/juju/juju/api/subnets"
+	"github.com/juju/juju/apiserver/params"
+	jujucloud "github.com/juju/juju/cloud"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/core/instance"
+	"github.com/juju/juju/provider/lxd/lxdnames"
+)
+
+// ClusterAPI provides the methods needed to inspect the placement of
+// workloads across LXD cluster members.
+type ClusterAPI interface {
+	Status(patterns []string) (*params.FullStatus, error)
+	AllZones() ([]params.ZoneResult, error)
+	Cloud(names.CloudTag) (jujucloud.Cloud, error)
+	Close() error
+}
+
+// clusterAPI combines the facade clients used by the cluster commands.
+type clusterAPI struct {
+	*api.Client
+	zones  *subnets.API
+	clouds *cloudapi.Client
+}
+
+// AllZones is part of the ClusterAPI interface.
+func (c *clusterAPI) AllZones() ([]params.ZoneResult, error) {
+	return c.zones.AllZones()
+}
+
+// Cloud is part of the ClusterAPI interface.
+func (c *clusterAPI) Cloud(tag names.CloudTag) (jujucloud.Cloud, error) {
+	return c.clouds.Cloud(tag)
+}
+
+// clusterCommandBase is the base type for commands which report
+// on the members of an LXD cluster.
+type clusterCommandBase struct {
+	modelcmd.ModelCommandBase
+	modelcmd.IAASOnlyCommand
+
+	newAPIFunc func() (ClusterAPI, error)
+}
+
+func (c *clusterCommandBase) newAPI() (ClusterAPI, error) {
+	if c.newAPIFunc != nil {
+		return c.newAPIFunc()
+	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &clusterAPI{
+		Client: root.Client(),
+		zones:  subnets.NewAPI(root),
+		clouds: cloudapi.NewClient(root),
+	}, nil
+}
+
+// clusterLoad fetches the model status and cluster members, and returns
+// the workload placed on each member.
+func (c *clusterCommandBase) clusterLoad() (*clusterLoad, error) {
+	client, err := c.newAPI()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer client.Close()
+
+	status, err := client.Status(nil)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	cloudTag, err := names.ParseCloudTag(status.Model.CloudTag)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	cloud, err := client.Cloud(cloudTag)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if cloud.Type != lxdnames.ProviderType {
+		return nil, errors.Errorf("model %q is not on an LXD cloud (cloud %q is of type %q)",
+			status.Model.Name, cloudTag.Id(), cloud.Type)
+	}
+	zones, err := client.AllZones()
+	if err != nil {
+		return nil, errors.Annotate(err, "listing cluster members")
+	}
+	return newClusterLoad(zones, status), nil
+}
+
+// clusterMember describes the machines and application units placed
+// on an LXD cluster member.
+type clusterMember struct {
+	Name      string
+	Available bool
+
+	// Known is false when the member hosts machines but was not
+	// reported by the cloud, typically because it has been removed.
+	Known    bool
+	Machines []string
+	Units    map[string][]string
+}
+
+// unitCount returns the number of units placed on the member.
+func (m *clusterMember) unitCount() int {
+	count := 0
+	for _, units := range m.Units {
+		count += len(units)
+	}
+	return count
+}
+
+// clusterLoad holds the cluster members, sorted by name.
+type clusterLoad struct {
+	Members []*clusterMember
+}
+
+// member returns the named member, or nil if there is none.
+func (l *clusterLoad) member(name string) *clusterMember {
+	for _, m := range l.Members {
+		if m.Name == name {
+			return m
+		}
+	}
+	return nil
+}
+
+// newClusterLoad combines the cluster members with the model status
+// to determine which machines and principal units each member hosts.
+// Containers are considered to be on the same member as their host.
+func newClusterLoad(zones []params.ZoneResult, status *params.FullStatus) *clusterLoad {
+	load := &clusterLoad{}
+	for _, zone := range zones {
+		load.Members = append(load.Members, &clusterMember{
+			Name:      zone.Name,
+			Available: zone.Available,
+			Known:     true,
+			Units:     make(map[string][]string),
+		})
+	}
+	memberFor := func(name string) *clusterMember {
+		if m := load.member(name); m != nil {
+			return m
+		}
+		m := &clusterMember{Name: name, Units: make(map[string][]string)}
+		load.Members = append(load.Members, m)
+		return m
+	}
+
+	machineZones := make(map[string]string)
+	for id, machine := range status.Machines {
+		hw, err := instance.ParseHardware(machine.Hardware)
+		if err != nil || hw.AvailabilityZone == nil || *hw.AvailabilityZone == "" {
+			continue
+		}
+		zone := *hw.AvailabilityZone
+		machineZones[id] = zone
+		m := memberFor(zone)
+		m.Machines = append(m.Machines, id)
+	}
+
+	for appName, app := range status.Applications {
+		if len(app.SubordinateTo) > 0 {
+			continue
+		}
+		for unitName, unit := range app.Units {
+			zone, ok := machineZones[hostMachine(unit.Machine)]
+			if !ok {
+				continue
+			}
+			m := memberFor(zone)
+			m.Units[appName] = append(m.Units[appName], unitName)
+		}
+	}
+
+	sort.Slice(load.Members, func(i, j int) bool {
+		return load.Members[i].Name < load.Members[j].Name
+	})
+	for _, m := range load.Members {
+		sort.Sort(machineIds(m.Machines))
+		for _, units := range m.Units {
+			sort.Sort(unitNames(units))
+		}
+	}
+	return load
+}
+
+// hostMachine returns the top level machine hosting the
+// specified machine or container.
+func hostMachine(id string) string {
+	return strings.SplitN(id, "/", 2)[0]
+}
+
+// machineIds sorts top level machine ids numerically.
+type machineIds []string
+
+func (m machineIds) Len() int      { return len(m) }
+func (m machineIds) Swap(i, j int) { m[i], m[j] = m[j], m[i] }
+func (m machineIds) Less(i, j int) bool {
+	a, errA := strconv.Atoi(m[i])
+	b, errB := strconv.Atoi(m[j])
+	if errA != nil || errB != nil {
+		return m[i] < m[j]
+	}
+	return a < b
+}
+
+// unitNames sorts unit names of the same application by unit number.
+type unitNames []string
+
+func (u unitNames) Len() int           { return len(u) }
+func (u unitNames) Swap(i, j int)      { u[i], u[j] = u[j], u[i] }
+func (u unitNames) Less(i, j int) bool { return unitNumber(u[i]) < unitNumber(u[j]) }
+
+func unitNumber(name string) int {
+	n, _ := strconv.Atoi(name[strings.LastIndex(name, "/")+1:])
+	return n
+}
@@ -0,0 +1,130 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package lxd_test
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/names/v4"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	jujucloud "github.com/juju/juju/cloud"
+	"github.com/juju/juju/cmd/juju/lxd"
+	"github.com/juju/juju/jujuclient"
+	"github.com/juju/juju/jujuclient/jujuclienttesting"
+)
+
+type stubClusterAPI struct {
+	testing.Stub
+
+	status *params.FullStatus
+	zones  []params.ZoneResult
+	cloud  jujucloud.Cloud
+}
+
+func (s *stubClusterAPI) Status(patterns []string) (*params.FullStatus, error) {
+	s.MethodCall(s, "Status", patterns)
+	return s.status, s.NextErr()
+}
+
+func (s *stubClusterAPI) AllZones() ([]params.ZoneResult, error) {
+	s.MethodCall(s, "AllZones")
+	return s.zones, s.NextErr()
+}
+
+func (s *stubClusterAPI) Cloud(tag names.CloudTag) (jujucloud.Cloud, error) {
+	s.MethodCall(s, "Cloud", tag)
+	return s.cloud, s.NextErr()
+}
+
+func (s *stubClusterAPI) Close() error {
+	s.MethodCall(s, "Close")
+	return s.NextErr()
+}
+
+// newStubClusterAPI returns a stub API for a three member cluster, with
+// all units of mysql on node01 and wordpress on node02.
+func newStubClusterAPI() *stubClusterAPI {
+	return &stubClusterAPI{
+		status: &params.FullStatus{
+			Model: params.ModelStatusInfo{
+				Name:     "default",
+				CloudTag: "cloud-localhost",
+			},
+			Machines: map[string]params.MachineStatus{
+				"0": {Id: "0", Hardware: "arch=amd64 availability-zone=node01"},
+				"1": {Id: "1", Hardware: "arch=amd64 availability-zone=node01"},
+				"2": {Id: "2", Hardware: "arch=amd64 availability-zone=node02"},
+				"3": {Id: "3", Hardware: "arch=amd64"},
+			},
+			Applications: map[string]params.ApplicationStatus{
+				"mysql": {
+					Units: map[string]params.UnitStatus{
+						"mysql/0": {Machine: "0"},
+						"mysql/1": {Machine: "1"},
+						"mysql/2": {Machine: "0/lxd/0"},
+					},
+				},
+				"wordpress": {
+					Units: map[string]params.UnitStatus{
+						"wordpress/0": {Machine: "2"},
+						"wordpress/1": {Machine: "3"},
+					},
+				},
+				"logging": {
+					SubordinateTo: []string{"mysql"},
+				},
+			},
+		},
+		zones: []params.ZoneResult{
+			{Name: "node01", Available: true},
+			{Name: "node02", Available: true},
+			{Name: "node03", Available: true},
+		},
+		cloud: jujucloud.Cloud{Name: "localhost", Type: "lxd"},
+	}
+}
+
+func testStore() jujuclient.ClientStore {
+	return jujuclienttesting.MinimalStore()
+}
+
+type clusterSuite struct {
+	testing.IsolationSuite
+
+	api *stubClusterAPI
+}
+
+var _ = gc.Suite(&clusterSuite{})
+
+func (s *clusterSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.api = newStubClusterAPI()
+}
+
+func (s *clusterSuite) run(c *gc.C, args ...string) (*cmd.Context, error) {
+	return cmdtesting.RunCommand(c, lxd.NewShowClusterCommandForTest(s.api, testStore()), args...)
+}
+
+func (s *clusterSuite) TestNotLXDCloud(c *gc.C) {
+	s.api.cloud = jujucloud.Cloud{Name: "aws", Type: "ec2"}
+	s.api.status.Model.CloudTag = "cloud-aws"
+	_, err := s.run(c)
+	c.Assert(err, gc.ErrorMatches, `model "default" is not on an LXD cloud \(cloud "aws" is of type "ec2"\)`)
+	s.api.CheckCallNames(c, "Status", "Cloud", "Close")
+}
+
+func (s *clusterSuite) TestMemberOnlyInStatus(c *gc.C) {
+	s.api.zones = s.api.zones[1:]
+	ctx, err := s.run(c, "--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), jc.Contains, `
+  node01:
+    status: unknown
+`[1:])
+	s.api.CheckCall(c, 1, "Cloud", names.NewCloudTag("localhost"))
+}
@@ -0,0 +1,29 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package lxd
+
+import (
+	"github.com/juju/cmd"
+
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/jujuclient"
+)
+
+// NewShowClusterCommandForTest returns a show-lxd-cluster command
+// using the specified api and client store.
+func NewShowClusterCommandForTest(api ClusterAPI, store jujuclient.ClientStore) cmd.Command {
+	c := &showClusterCommand{}
+	c.newAPIFunc = func() (ClusterAPI, error) { return api, nil }
+	c.SetClientStore(store)
+	return modelcmd.Wrap(c)
+}
+
+// NewRebalanceClusterCommandForTest returns a rebalance-lxd-cluster command
+// using the specified api and client store.
+func NewRebalanceClusterCommandForTest(api ClusterAPI, store jujuclient.ClientStore) cmd.Command {
+	c := &rebalanceClusterCommand{}
+	c.newAPIFunc = func() (ClusterAPI, error) { return api, nil }
+	c.SetClientStore(store)
+	return modelcmd.Wrap(c)
+}
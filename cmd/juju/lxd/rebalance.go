@@ -0,0 +1,217 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package lxd
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/cmd/output"
+)
+
+const rebalanceClusterDoc = `
+Propose moves of application units between the members of the LXD cluster
+hosting the current model, so that the units of each application are spread
+as evenly as possible across the online members.
+
+Units on offline members are always proposed to be moved. No changes are
+made to the model; each proposed move can be applied by adding a unit to
+the target member and then removing the original unit, using the commands
+printed with the proposal.
+
+Examples:
+
+    juju rebalance-lxd-cluster
+    juju rebalance-lxd-cluster --format json
+
+See also:
+    show-lxd-cluster
+    add-unit
+    remove-unit
+`
+
+// NewRebalanceClusterCommand returns a command which proposes unit moves
+// to spread applications across the members of an LXD cluster.
+func NewRebalanceClusterCommand() cmd.Command {
+	return modelcmd.Wrap(&rebalanceClusterCommand{})
+}
+
+type rebalanceClusterCommand struct {
+	clusterCommandBase
+
+	out cmd.Output
+}
+
+// Info implements cmd.Command.
+func (c *rebalanceClusterCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "rebalance-lxd-cluster",
+		Purpose: "Proposes unit moves to spread applications across LXD cluster members.",
+		Doc:     rebalanceClusterDoc,
+	})
+}
+
+// SetFlags implements cmd.Command.
+func (c *rebalanceClusterCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.clusterCommandBase.SetFlags(f)
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatMovesTabular,
+	})
+}
+
+// Init implements cmd.Command.
+func (c *rebalanceClusterCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+// Run implements cmd.Command.
+func (c *rebalanceClusterCommand) Run(ctx *cmd.Context) error {
+	load, err := c.clusterLoad()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	moves := proposeMoves(load)
+	if len(moves) == 0 {
+		ctx.Infof("Application units are evenly spread across the online cluster members.")
+		return nil
+	}
+	return c.out.Write(ctx, moves)
+}
+
+// unitMove describes moving a unit from one cluster member to another.
+type unitMove struct {
+	Application string `yaml:"application" json:"application"`
+	Unit        string `yaml:"unit" json:"unit"`
+	From        string `yaml:"from" json:"from"`
+	To          string `yaml:"to" json:"to"`
+}
+
+// proposeMoves returns the unit moves which spread the units of each
+// application evenly across the available cluster members. Units on
+// unavailable members are moved first; then units are moved from the
+// member with the most units of the application to the one with the
+// fewest until they differ by at most one. Ties are broken by the total
+// number of units on a member, then by member name.
+func proposeMoves(load *clusterLoad) []unitMove {
+	var available []*clusterMember
+	memberLoad := make(map[string]int)
+	appNames := make(map[string]bool)
+	for _, m := range load.Members {
+		if m.Known && m.Available {
+			available = append(available, m)
+		}
+		memberLoad[m.Name] = m.unitCount()
+		for appName := range m.Units {
+			appNames[appName] = true
+		}
+	}
+	if len(available) == 0 {
+		return nil
+	}
+	sortedApps := make([]string, 0, len(appNames))
+	for appName := range appNames {
+		sortedApps = append(sortedApps, appName)
+	}
+	sort.Strings(sortedApps)
+
+	var moves []unitMove
+	for _, appName := range sortedApps {
+		placed := make(map[string][]string)
+		for _, m := range available {
+			placed[m.Name] = append([]string(nil), m.Units[appName]...)
+		}
+		leastLoaded := func() string {
+			best := available[0].Name
+			for _, m := range available[1:] {
+				a, b := len(placed[m.Name]), len(placed[best])
+				if a < b || (a == b && memberLoad[m.Name] < memberLoad[best]) {
+					best = m.Name
+				}
+			}
+			return best
+		}
+		mostLoaded := func() string {
+			best := available[0].Name
+			for _, m := range available[1:] {
+				a, b := len(placed[m.Name]), len(placed[best])
+				if a > b || (a == b && memberLoad[m.Name] > memberLoad[best]) {
+					best = m.Name
+				}
+			}
+			return best
+		}
+		moved := make(map[string]int)
+		move := func(unit, from, to string) {
+			// A unit moved more than once only needs a single move
+			// from its original member.
+			if i, ok := moved[unit]; ok {
+				moves[i].To = to
+			} else {
+				moved[unit] = len(moves)
+				moves = append(moves, unitMove{Application: appName, Unit: unit, From: from, To: to})
+			}
+			placed[to] = append(placed[to], unit)
+			memberLoad[from]--
+			memberLoad[to]++
+		}
+
+		for _, m := range load.Members {
+			if m.Known && m.Available {
+				continue
+			}
+			for _, unit := range m.Units[appName] {
+				move(unit, m.Name, leastLoaded())
+			}
+		}
+		for {
+			from, to := mostLoaded(), leastLoaded()
+			if len(placed[from])-len(placed[to]) <= 1 {
+				break
+			}
+			units := placed[from]
+			unit := units[len(units)-1]
+			placed[from] = units[:len(units)-1]
+			move(unit, from, to)
+		}
+	}
+	// A unit moved away and then back again stays where it is.
+	result := moves[:0]
+	for _, m := range moves {
+		if m.From != m.To {
+			result = append(result, m)
+		}
+	}
+	return result
+}
+
+func formatMovesTabular(writer io.Writer, value interface{}) error {
+	moves, ok := value.([]unitMove)
+	if !ok {
+		return errors.Errorf("expected value of type %T, got %T", moves, value)
+	}
+	tw := output.TabWriter(writer)
+	w := output.Wrapper{tw}
+	w.Println("Application", "Unit", "From", "To")
+	for _, m := range moves {
+		w.Println(m.Application, m.Unit, m.From, m.To)
+	}
+	if err := tw.Flush(); err != nil {
+		return errors.Trace(err)
+	}
+	fmt.Fprintln(writer, "\nTo apply the proposed moves, run:")
+	for _, m := range moves {
+		fmt.Fprintf(writer, "  juju add-unit %s --to zone=%s\n", m.Application, m.To)
+		fmt.Fprintf(writer, "  juju remove-unit %s\n", m.Unit)
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package lxd_test
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/lxd"
+)
+
+type rebalanceClusterSuite struct {
+	testing.IsolationSuite
+
+	api *stubClusterAPI
+}
+
+var _ = gc.Suite(&rebalanceClusterSuite{})
+
+func (s *rebalanceClusterSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.api = newStubClusterAPI()
+}
+
+func (s *rebalanceClusterSuite) run(c *gc.C, args ...string) (*cmd.Context, error) {
+	return cmdtesting.RunCommand(c, lxd.NewRebalanceClusterCommandForTest(s.api, testStore()), args...)
+}
+
+func (s *rebalanceClusterSuite) TestRebalance(c *gc.C) {
+	ctx, err := s.run(c)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, `
+Application  Unit     From    To
+mysql        mysql/2  node01  node03
+mysql        mysql/1  node01  node02
+
+To apply the proposed moves, run:
+  juju add-unit mysql --to zone=node03
+  juju remove-unit mysql/2
+  juju add-unit mysql --to zone=node02
+  juju remove-unit mysql/1
+
+`[1:])
+}
+
+func (s *rebalanceClusterSuite) TestRebalanceOfflineMember(c *gc.C) {
+	s.api.zones[1].Available = false
+	ctx, err := s.run(c, "--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, `
+- application: mysql
+  unit: mysql/2
+  from: node01
+  to: node03
+- application: wordpress
+  unit: wordpress/0
+  from: node02
+  to: node03
+`[1:])
+}
+
+func (s *rebalanceClusterSuite) TestRebalanceStrandedUnitMovedOnce(c *gc.C) {
+	s.api.status.Machines["4"] = params.MachineStatus{Id: "4", Hardware: "availability-zone=node03"}
+	s.api.status.Applications["mysql"].Units["mysql/3"] = params.UnitStatus{Machine: "4"}
+	s.api.zones[2].Available = false
+	ctx, err := s.run(c, "--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, `
+- application: mysql
+  unit: mysql/3
+  from: node03
+  to: node02
+- application: mysql
+  unit: mysql/2
+  from: node01
+  to: node02
+`[1:])
+}
+
+func (s *rebalanceClusterSuite) TestRebalanceBalanced(c *gc.C) {
+	s.api.status.Applications = map[string]params.ApplicationStatus{
+		"mysql": {Units: map[string]params.UnitStatus{
+			"mysql/0": {Machine: "0"},
+			"mysql/1": {Machine: "2"},
+		}},
+	}
+	ctx, err := s.run(c)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, "")
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, "Application units are evenly spread across the online cluster members.\n")
+}
@@ -0,0 +1,153 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package lxd
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/cmd/output"
+)
+
+const showClusterDoc = `
+Show the members of the LXD cluster hosting the current model, along with
+the machines and application units placed on each member.
+
+Containers are reported as being on the same member as their host machine,
+and subordinate units are not shown.
+
+Examples:
+
+    juju show-lxd-cluster
+    juju show-lxd-cluster --format yaml
+
+See also:
+    rebalance-lxd-cluster
+`
+
+// NewShowClusterCommand returns a command which shows the workload
+// placed on each member of an LXD cluster.
+func NewShowClusterCommand() cmd.Command {
+	return modelcmd.Wrap(&showClusterCommand{})
+}
+
+type showClusterCommand struct {
+	clusterCommandBase
+
+	out cmd.Output
+}
+
+// Info implements cmd.Command.
+func (c *showClusterCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "show-lxd-cluster",
+		Purpose: "Shows the workload placed on each LXD cluster member.",
+		Doc:     showClusterDoc,
+	})
+}
+
+// SetFlags implements cmd.Command.
+func (c *showClusterCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.clusterCommandBase.SetFlags(f)
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatClusterTabular,
+	})
+}
+
+// Init implements cmd.Command.
+func (c *showClusterCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+// Run implements cmd.Command.
+func (c *showClusterCommand) Run(ctx *cmd.Context) error {
+	load, err := c.clusterLoad()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return c.out.Write(ctx, formatCluster(load))
+}
+
+type formattedCluster struct {
+	Members map[string]formattedMember `yaml:"members" json:"members"`
+}
+
+type formattedMember struct {
+	Status   string              `yaml:"status" json:"status"`
+	Machines []string            `yaml:"machines,omitempty" json:"machines,omitempty"`
+	Units    map[string][]string `yaml:"units,omitempty" json:"units,omitempty"`
+}
+
+const (
+	memberOnline  = "online"
+	memberOffline = "offline"
+	memberUnknown = "unknown"
+)
+
+func formatCluster(load *clusterLoad) formattedCluster {
+	result := formattedCluster{Members: make(map[string]formattedMember)}
+	for _, m := range load.Members {
+		member := formattedMember{
+			Status:   memberStatus(m),
+			Machines: m.Machines,
+		}
+		if len(m.Units) > 0 {
+			member.Units = m.Units
+		}
+		result.Members[m.Name] = member
+	}
+	return result
+}
+
+func memberStatus(m *clusterMember) string {
+	switch {
+	case !m.Known:
+		return memberUnknown
+	case m.Available:
+		return memberOnline
+	}
+	return memberOffline
+}
+
+func formatClusterTabular(writer io.Writer, value interface{}) error {
+	cluster, ok := value.(formattedCluster)
+	if !ok {
+		return errors.Errorf("expected value of type %T, got %T", cluster, value)
+	}
+	tw := output.TabWriter(writer)
+	w := output.Wrapper{tw}
+	w.Println("Member", "Status", "Machines", "Units", "Applications")
+
+	memberNames := make([]string, 0, len(cluster.Members))
+	for name := range cluster.Members {
+		memberNames = append(memberNames, name)
+	}
+	sort.Strings(memberNames)
+	for _, name := range memberNames {
+		member := cluster.Members[name]
+		appNames := make([]string, 0, len(member.Units))
+		unitCount := 0
+		for appName, units := range member.Units {
+			appNames = append(appNames, appName)
+			unitCount += len(units)
+		}
+		sort.Strings(appNames)
+		apps := make([]string, len(appNames))
+		for i, appName := range appNames {
+			apps[i] = fmt.Sprintf("%s(%d)", appName, len(member.Units[appName]))
+		}
+		w.Println(name, member.Status, len(member.Machines), unitCount, strings.Join(apps, " "))
+	}
+	return errors.Trace(tw.Flush())
+}
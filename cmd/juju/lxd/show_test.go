@@ -0,0 +1,87 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package lxd_test
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/lxd"
+)
+
+type showClusterSuite struct {
+	testing.IsolationSuite
+
+	api *stubClusterAPI
+}
+
+var _ = gc.Suite(&showClusterSuite{})
+
+func (s *showClusterSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.api = newStubClusterAPI()
+}
+
+func (s *showClusterSuite) run(c *gc.C, args ...string) (*cmd.Context, error) {
+	return cmdtesting.RunCommand(c, lxd.NewShowClusterCommandForTest(s.api, testStore()), args...)
+}
+
+func (s *showClusterSuite) TestShowTabular(c *gc.C) {
+	s.api.zones[2].Available = false
+	ctx, err := s.run(c)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, `
+Member  Status   Machines  Units  Applications
+node01  online   2         3      mysql(3)
+node02  online   1         1      wordpress(1)
+node03  offline  0         0      
+
+`[1:])
+	s.api.CheckCallNames(c, "Status", "Cloud", "AllZones", "Close")
+}
+
+func (s *showClusterSuite) TestShowYAML(c *gc.C) {
+	ctx, err := s.run(c, "--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, `
+members:
+  node01:
+    status: online
+    machines:
+    - "0"
+    - "1"
+    units:
+      mysql:
+      - mysql/0
+      - mysql/1
+      - mysql/2
+  node02:
+    status: online
+    machines:
+    - "2"
+    units:
+      wordpress:
+      - wordpress/0
+  node03:
+    status: online
+`[1:])
+}
+
+func (s *showClusterSuite) TestShowNotClustered(c *gc.C) {
+	s.api.status.Machines = map[string]params.MachineStatus{
+		"0": {Id: "0", Hardware: "arch=amd64 availability-zone=lxd"},
+	}
+	s.api.status.Applications = map[string]params.ApplicationStatus{
+		"mysql": {Units: map[string]params.UnitStatus{"mysql/0": {Machine: "0"}}},
+	}
+	s.api.zones = []params.ZoneResult{{Name: "lxd", Available: true}}
+	ctx, err := s.run(c, "--format", "json")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals,
+		`{"members":{"lxd":{"status":"online","machines":["0"],"units":{"mysql":["mysql/0"]}}}}`+"\n")
+}
@@ -0,0 +1,371 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machine
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sync"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"github.com/juju/names/v4"
+	"gopkg.in/yaml.v2"
+
+	"github.com/juju/juju/api/machinemanager"
+	"github.com/juju/juju/api/modelconfig"
+	"github.com/juju/juju/apiserver/params"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/juju/common"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/environs/manual"
+	"github.com/juju/juju/environs/manual/sshprovisioner"
+)
+
+var enlistMachinesDoc = `
+Manually provision the hosts listed in an inventory file, using SSH, and
+bring them under Juju's management. Hosts are enlisted concurrently, and
+the progress of each host is reported with the host as a prefix.
+
+The inventory is a YAML file listing the hosts to enlist:
+
+    machines:
+      - host: 10.10.0.3
+      - host: 10.10.0.4
+        user: admin
+      - host: ubuntu@10.10.0.5
+      - host: 10.10.0.6
+        machine: "4"
+
+A host with a "machine" entry is re-enlisted: its host has been reinstalled,
+and it is provisioned again as the existing, manually provisioned machine
+with that ID. The machine keeps its ID and the units assigned to it, which
+are redeployed on the host. The series and architecture detected on the
+host must match those of the machine.
+
+As hosts are enlisted concurrently, there is no opportunity to answer
+password prompts; each host must accept SSH key authentication, and the
+login user must be able to use sudo without a password.
+
+The hardware of each host is detected in the same way as for
+"juju add-machine ssh:[user@]host".
+
+Examples:
+    juju enlist-machines inventory.yaml
+    juju enlist-machines inventory.yaml --parallel 10
+
+See also:
+    add-machine
+    remove-machine
+`
+
+// NewEnlistMachinesCommand returns a command that manually provisions
+// the hosts listed in an inventory file.
+func NewEnlistMachinesCommand() cmd.Command {
+	return modelcmd.Wrap(&enlistMachinesCommand{})
+}
+
+// ReenlistMachineAPI defines the API methods used to re-enlist
+// manually provisioned machines.
+type ReenlistMachineAPI interface {
+	manual.MachineReenlister
+	Close() error
+}
+
+// enlistMachinesCommand provisions the hosts listed in an inventory file.
+type enlistMachinesCommand struct {
+	baseMachinesCommand
+	api               AddMachineAPI
+	modelConfigAPI    ModelConfigAPI
+	machineManagerAPI ReenlistMachineAPI
+
+	inventoryFile string
+	parallel      int
+}
+
+// Info implements cmd.Command.
+func (c *enlistMachinesCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "enlist-machines",
+		Args:    "<inventory file>",
+		Purpose: "Manually provision the hosts listed in an inventory file.",
+		Doc:     enlistMachinesDoc,
+	})
+}
+
+// SetFlags implements cmd.Command.
+func (c *enlistMachinesCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ModelCommandBase.SetFlags(f)
+	f.IntVar(&c.parallel, "parallel", 4, "The number of hosts to enlist at the same time")
+}
+
+// Init implements cmd.Command.
+func (c *enlistMachinesCommand) Init(args []string) error {
+	switch len(args) {
+	case 0:
+		return errors.New("no inventory file specified")
+	case 1:
+		c.inventoryFile = args[0]
+	default:
+		return cmd.CheckEmpty(args[1:])
+	}
+	if c.parallel < 1 {
+		return errors.Errorf("--parallel must be at least 1, got %d", c.parallel)
+	}
+	return nil
+}
+
+// inventory describes the hosts to be enlisted.
+type inventory struct {
+	Machines []inventoryHost `yaml:"machines"`
+}
+
+// inventoryHost describes a host to be enlisted, and optionally
+// the existing machine it is to be re-enlisted as.
+type inventoryHost struct {
+	Host    string `yaml:"host"`
+	User    string `yaml:"user,omitempty"`
+	Machine string `yaml:"machine,omitempty"`
+}
+
+func readInventory(path string) ([]inventoryHost, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var inv inventory
+	if err := yaml.UnmarshalStrict(data, &inv); err != nil {
+		return nil, errors.Annotatef(err, "cannot parse inventory %q", path)
+	}
+	if len(inv.Machines) == 0 {
+		return nil, errors.Errorf("no hosts found in inventory %q", path)
+	}
+	hosts := make(map[string]bool)
+	machines := make(map[string]bool)
+	for i, h := range inv.Machines {
+		user, host := splitUserHost(h.Host)
+		if host == "" {
+			return nil, errors.Errorf("inventory entry %d: host not specified", i+1)
+		}
+		if user != "" && h.User != "" && user != h.User {
+			return nil, errors.Errorf("inventory entry %d: user %q conflicts with host %q", i+1, h.User, h.Host)
+		}
+		if user == "" {
+			user = h.User
+		}
+		if hosts[host] {
+			return nil, errors.Errorf("inventory entry %d: host %q listed more than once", i+1, host)
+		}
+		hosts[host] = true
+		if h.Machine != "" {
+			if !names.IsValidMachine(h.Machine) || names.IsContainerMachine(h.Machine) {
+				return nil, errors.Errorf("inventory entry %d: invalid machine ID %q", i+1, h.Machine)
+			}
+			if machines[h.Machine] {
+				return nil, errors.Errorf("inventory entry %d: machine %q listed more than once", i+1, h.Machine)
+			}
+			machines[h.Machine] = true
+		}
+		inv.Machines[i] = inventoryHost{Host: host, User: user, Machine: h.Machine}
+	}
+	return inv.Machines, nil
+}
+
+// hasReenlistings reports whether any of the hosts are to be
+// re-enlisted as existing machines.
+func hasReenlistings(hosts []inventoryHost) bool {
+	for _, h := range hosts {
+		if h.Machine != "" {
+			return true
+		}
+	}
+	return false
+}
+
+var sshReenlister = sshprovisioner.ReenlistMachine
+
+// Run implements cmd.Command.
+func (c *enlistMachinesCommand) Run(ctx *cmd.Context) error {
+	hosts, err := readInventory(ctx.AbsPath(c.inventoryFile))
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	client, err := c.getClientAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+
+	modelConfigClient, err := c.getModelConfigAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer modelConfigClient.Close()
+	configAttrs, err := modelConfigClient.ModelGet()
+	if err != nil {
+		if params.IsCodeUnauthorized(err) {
+			common.PermissionsMessage(ctx.Stderr, "add a machine to this model")
+		}
+		return errors.Trace(err)
+	}
+	cfg, err := config.New(config.NoDefaults, configAttrs)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	var reenlister ReenlistMachineAPI
+	if hasReenlistings(hosts) {
+		if reenlister, err = c.getMachineManagerAPI(); err != nil {
+			return errors.Trace(err)
+		}
+		defer reenlister.Close()
+	}
+
+	authKeys, err := common.ReadAuthorizedKeys(ctx, "")
+	if err != nil {
+		return errors.Annotatef(err, "cannot read authorized-keys")
+	}
+
+	var mu sync.Mutex
+	errs := make([]error, len(hosts))
+	sem := make(chan struct{}, c.parallel)
+	var wg sync.WaitGroup
+	for i, h := range hosts {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, h inventoryHost) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			stdout := &hostWriter{mu: &mu, out: ctx.Stdout, prefix: h.Host + ": "}
+			stderr := &hostWriter{mu: &mu, out: ctx.Stderr, prefix: h.Host + ": "}
+			defer stdout.Flush()
+			defer stderr.Flush()
+			args := manual.ProvisionMachineArgs{
+				Host:           h.Host,
+				User:           h.User,
+				Client:         client,
+				Stdout:         stdout,
+				Stderr:         stderr,
+				AuthorizedKeys: authKeys,
+				UpdateBehavior: &params.UpdateBehavior{
+					EnableOSRefreshUpdate: cfg.EnableOSRefreshUpdate(),
+					EnableOSUpgrade:       cfg.EnableOSUpgrade(),
+				},
+			}
+			errs[i] = enlistHost(args, h.Machine, reenlister, stderr)
+		}(i, h)
+	}
+	wg.Wait()
+
+	failed := 0
+	for _, err := range errs {
+		if err != nil {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return errors.Errorf("failed to enlist %d of %d hosts", failed, len(hosts))
+	}
+	return nil
+}
+
+// enlistHost provisions a single host, reporting its progress to w.
+// If machineId is not empty, the host is re-enlisted as that machine.
+func enlistHost(args manual.ProvisionMachineArgs, machineId string, reenlister manual.MachineReenlister, w *hostWriter) error {
+	var err error
+	outcome := "re-enlisted machine %v\n"
+	if machineId == "" {
+		fmt.Fprintln(w, "enlisting")
+		outcome = "created machine %v\n"
+		machineId, err = sshProvisioner(args)
+	} else {
+		fmt.Fprintf(w, "re-enlisting machine %v\n", machineId)
+		err = sshReenlister(manual.ReenlistMachineArgs{
+			ProvisionMachineArgs: args,
+			MachineId:            machineId,
+			Reenlister:           reenlister,
+		})
+	}
+	// Complete any progress output before reporting the outcome.
+	w.Flush()
+	if err != nil {
+		fmt.Fprintf(w, "failed: %v\n", err)
+		return errors.Trace(err)
+	}
+	fmt.Fprintf(w, outcome, machineId)
+	return nil
+}
+
+func (c *enlistMachinesCommand) getClientAPI() (AddMachineAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	return c.NewAPIClient()
+}
+
+func (c *enlistMachinesCommand) getModelConfigAPI() (ModelConfigAPI, error) {
+	if c.modelConfigAPI != nil {
+		return c.modelConfigAPI, nil
+	}
+	api, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Annotate(err, "opening API connection")
+	}
+	return modelconfig.NewClient(api), nil
+}
+
+func (c *enlistMachinesCommand) getMachineManagerAPI() (ReenlistMachineAPI, error) {
+	if c.machineManagerAPI != nil {
+		return c.machineManagerAPI, nil
+	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return machinemanager.NewClient(root), nil
+}
+
+// hostWriter prefixes each line written to it with the host being
+// enlisted, so that the output for concurrently enlisted hosts can
+// be told apart.
+type hostWriter struct {
+	mu     *sync.Mutex
+	out    io.Writer
+	prefix string
+	buf    []byte
+}
+
+// Write implements io.Writer.
+func (w *hostWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		w.writeLine(w.buf[:i+1])
+		w.buf = w.buf[i+1:]
+	}
+	return len(p), nil
+}
+
+// Flush writes out any incomplete line.
+func (w *hostWriter) Flush() {
+	if len(w.buf) > 0 {
+		w.writeLine(append(w.buf, '\n'))
+		w.buf = nil
+	}
+}
+
+func (w *hostWriter) writeLine(line []byte) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	fmt.Fprintf(w.out, "%s%s", w.prefix, line)
+}
@@ -0,0 +1,202 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machine_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/juju/cmd"
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/juju/machine"
+	"github.com/juju/juju/core/instance"
+	"github.com/juju/juju/environs/manual"
+	"github.com/juju/juju/testing"
+)
+
+type EnlistMachinesSuite struct {
+	testing.FakeJujuXDGDataHomeSuite
+	fakeAddMachine *fakeAddMachineAPI
+	fakeReenlister *fakeReenlistMachineAPI
+
+	mu          sync.Mutex
+	provisioned []manual.ProvisionMachineArgs
+	reenlisted  []manual.ReenlistMachineArgs
+}
+
+var _ = gc.Suite(&EnlistMachinesSuite{})
+
+func (s *EnlistMachinesSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuXDGDataHomeSuite.SetUpTest(c)
+	s.fakeAddMachine = &fakeAddMachineAPI{}
+	s.fakeReenlister = &fakeReenlistMachineAPI{}
+	s.provisioned = nil
+	s.reenlisted = nil
+	s.PatchValue(machine.SSHProvisioner, func(args manual.ProvisionMachineArgs) (string, error) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if args.Host == "10.0.0.99" {
+			fmt.Fprint(args.Stderr, "connecting\nno route to host")
+			return "", errors.New("cannot connect")
+		}
+		s.provisioned = append(s.provisioned, args)
+		return strings.TrimPrefix(args.Host, "10.0.0."), nil
+	})
+	s.PatchValue(machine.SSHReenlister, func(args manual.ReenlistMachineArgs) error {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.reenlisted = append(s.reenlisted, args)
+		return nil
+	})
+}
+
+func (s *EnlistMachinesSuite) writeInventory(c *gc.C, content string) string {
+	path := filepath.Join(c.MkDir(), "inventory.yaml")
+	err := ioutil.WriteFile(path, []byte(content), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	return path
+}
+
+func (s *EnlistMachinesSuite) run(c *gc.C, args ...string) (*cmd.Context, error) {
+	command := machine.NewEnlistMachinesCommandForTest(s.fakeAddMachine, s.fakeAddMachine, s.fakeReenlister)
+	return cmdtesting.RunCommand(c, command, args...)
+}
+
+// sortedLines returns the lines of output sorted, as hosts are
+// enlisted concurrently.
+func sortedLines(output string) []string {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	sort.Strings(lines)
+	return lines
+}
+
+func (s *EnlistMachinesSuite) TestInit(c *gc.C) {
+	for i, test := range []struct {
+		args        []string
+		errorString string
+	}{{
+		errorString: "no inventory file specified",
+	}, {
+		args:        []string{"a.yaml", "b.yaml"},
+		errorString: `unrecognized args: \["b.yaml"\]`,
+	}, {
+		args:        []string{"a.yaml", "--parallel", "0"},
+		errorString: "--parallel must be at least 1, got 0",
+	}, {
+		args: []string{"a.yaml", "--parallel", "2"},
+	}} {
+		c.Logf("test %d", i)
+		command := machine.NewEnlistMachinesCommandForTest(nil, nil, nil)
+		err := cmdtesting.InitCommand(command, test.args)
+		if test.errorString == "" {
+			c.Check(err, jc.ErrorIsNil)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.errorString)
+		}
+	}
+}
+
+func (s *EnlistMachinesSuite) TestEnlist(c *gc.C) {
+	path := s.writeInventory(c, `
+machines:
+  - host: 10.0.0.1
+  - host: admin@10.0.0.2
+  - host: 10.0.0.3
+    user: ubuntu
+    machine: "4"
+`)
+	ctx, err := s.run(c, path)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(sortedLines(cmdtesting.Stderr(ctx)), jc.DeepEquals, []string{
+		"10.0.0.1: created machine 1",
+		"10.0.0.1: enlisting",
+		"10.0.0.2: created machine 2",
+		"10.0.0.2: enlisting",
+		"10.0.0.3: re-enlisted machine 4",
+		"10.0.0.3: re-enlisting machine 4",
+	})
+
+	c.Assert(s.provisioned, gc.HasLen, 2)
+	users := map[string]string{}
+	for _, args := range s.provisioned {
+		users[args.Host] = args.User
+		c.Check(args.UpdateBehavior, gc.NotNil)
+	}
+	c.Assert(users, jc.DeepEquals, map[string]string{"10.0.0.1": "", "10.0.0.2": "admin"})
+
+	c.Assert(s.reenlisted, gc.HasLen, 1)
+	c.Check(s.reenlisted[0].Host, gc.Equals, "10.0.0.3")
+	c.Check(s.reenlisted[0].User, gc.Equals, "ubuntu")
+	c.Check(s.reenlisted[0].MachineId, gc.Equals, "4")
+	c.Check(s.reenlisted[0].Reenlister, gc.Equals, s.fakeReenlister)
+}
+
+func (s *EnlistMachinesSuite) TestEnlistFailures(c *gc.C) {
+	path := s.writeInventory(c, `
+machines:
+  - host: 10.0.0.1
+  - host: 10.0.0.99
+`)
+	ctx, err := s.run(c, path, "--parallel", "1")
+	c.Assert(err, gc.ErrorMatches, "failed to enlist 1 of 2 hosts")
+	c.Assert(sortedLines(cmdtesting.Stderr(ctx)), jc.DeepEquals, []string{
+		"10.0.0.1: created machine 1",
+		"10.0.0.1: enlisting",
+		"10.0.0.99: connecting",
+		"10.0.0.99: enlisting",
+		"10.0.0.99: failed: cannot connect",
+		"10.0.0.99: no route to host",
+	})
+}
+
+func (s *EnlistMachinesSuite) TestInvalidInventory(c *gc.C) {
+	for i, test := range []struct {
+		content     string
+		errorString string
+	}{{
+		content:     "machines: []",
+		errorString: `no hosts found in inventory ".*"`,
+	}, {
+		content:     "machines:\n  - user: ubuntu",
+		errorString: "inventory entry 1: host not specified",
+	}, {
+		content:     "machines:\n  - host: a@10.0.0.1\n    user: b",
+		errorString: `inventory entry 1: user "b" conflicts with host "a@10.0.0.1"`,
+	}, {
+		content:     "machines:\n  - host: 10.0.0.1\n  - host: ubuntu@10.0.0.1",
+		errorString: `inventory entry 2: host "10.0.0.1" listed more than once`,
+	}, {
+		content:     "machines:\n  - host: 10.0.0.1\n    machine: 0/lxd/0",
+		errorString: `inventory entry 1: invalid machine ID "0/lxd/0"`,
+	}, {
+		content:     "machines:\n  - host: 10.0.0.1\n    machine: \"1\"\n  - host: 10.0.0.2\n    machine: \"1\"",
+		errorString: `inventory entry 2: machine "1" listed more than once`,
+	}, {
+		content:     "machines:\n  - hostname: 10.0.0.1",
+		errorString: `(?s)cannot parse inventory ".*": .*field hostname not found.*`,
+	}} {
+		c.Logf("test %d", i)
+		_, err := s.run(c, s.writeInventory(c, test.content))
+		c.Check(err, gc.ErrorMatches, test.errorString)
+	}
+	c.Assert(s.provisioned, gc.HasLen, 0)
+}
+
+type fakeReenlistMachineAPI struct{}
+
+func (*fakeReenlistMachineAPI) ReenlistMachine(machineId, nonce, series string, hc instance.HardwareCharacteristics) error {
+	return errors.NotImplementedf("ReenlistMachine")
+}
+
+func (*fakeReenlistMachineAPI) Close() error {
+	return nil
+}
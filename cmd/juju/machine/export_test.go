@@ -15,6 +15,7 @@ import (
 
 var (
 	SSHProvisioner = &sshProvisioner
+	SSHReenlister  = &sshReenlister
 )
 
 type AddCommand struct {
@@ -32,6 +33,26 @@ func NewAddCommandForTest(api AddMachineAPI, mcAPI ModelConfigAPI, mmAPI Machine
 	return modelcmd.Wrap(command), &AddCommand{command}
 }
 
+// NewEnlistMachinesCommandForTest returns an enlist-machines command with
+// the APIs provided as specified.
+func NewEnlistMachinesCommandForTest(api AddMachineAPI, mcAPI ModelConfigAPI, mmAPI ReenlistMachineAPI) cmd.Command {
+	command := &enlistMachinesCommand{
+		api:               api,
+		modelConfigAPI:    mcAPI,
+		machineManagerAPI: mmAPI,
+	}
+	command.SetClientStore(jujuclienttesting.MinimalStore())
+	return modelcmd.Wrap(command)
+}
+
+// NewMachineUserDataCommandForTest returns a machine-userdata command
+// with the api provided as specified.
+func NewMachineUserDataCommandForTest(api MachineUserDataAPI) cmd.Command {
+	command := &machineUserDataCommand{api: api}
+	command.SetClientStore(jujuclienttesting.MinimalStore())
+	return modelcmd.Wrap(command)
+}
+
 // NewListCommandForTest returns a listMachineCommand with specified api
 func NewListCommandForTest(api statusAPI) cmd.Command {
 	command := newListMachinesCommand(api)
@@ -0,0 +1,169 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machine
+
+import (
+	"fmt"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"github.com/juju/names/v4"
+	"github.com/juju/utils"
+	"gopkg.in/yaml.v2"
+
+	"github.com/juju/juju/api/machinemanager"
+	"github.com/juju/juju/apiserver/params"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+)
+
+var machineUserDataDoc = `
+Show the cloud-init user data used when provisioning a machine.
+
+The user data is the model's "cloudinit-userdata", with each overlay in the
+model's "cloudinit-userdata-overlays" whose match constraints are satisfied
+by the machine's constraints merged in, in the order they are listed. For
+example, to install extra packages on arm64 machines with at least 16G of
+memory:
+
+    juju model-config cloudinit-userdata-overlays='
+    - match: arch=arm64 mem=16G
+      userdata:
+        packages: [arm-tools]
+        postruncmd: [systemctl restart arm-tools]
+    '
+
+String constraints in a match must be equal to the machine's, numeric
+constraints must be no more than the machine's, and list constraints must
+all be present in the machine's. The packages, preruncmd, postruncmd and
+write_files entries of a matching overlay are appended to those already
+present; any other entry replaces the existing value.
+
+With --preview, the cloud-config document produced by merging the user
+data with Juju's own sections is shown instead. The commands Juju adds to
+install the machine agent depend on the machine and are shown as a single
+placeholder command; preruncmd entries are run before them, postruncmd
+entries after them.
+
+Examples:
+    juju machine-userdata 1
+    juju machine-userdata 1 --preview
+
+See also:
+    model-config
+    show-machine
+`
+
+// NewMachineUserDataCommand returns a command that shows the cloud-init
+// user data used when provisioning a machine.
+func NewMachineUserDataCommand() cmd.Command {
+	return modelcmd.Wrap(&machineUserDataCommand{})
+}
+
+// MachineUserDataAPI defines the API methods used to show the
+// cloud-init user data of a machine.
+type MachineUserDataAPI interface {
+	MachineUserData(machineId string) (params.MachineUserDataResult, error)
+	Close() error
+}
+
+// machineUserDataCommand shows the cloud-init user data of a machine.
+type machineUserDataCommand struct {
+	modelcmd.ModelCommandBase
+	api MachineUserDataAPI
+	out cmd.Output
+
+	machineId string
+	preview   bool
+}
+
+// Info implements cmd.Command.
+func (c *machineUserDataCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "machine-userdata",
+		Args:    "<machine>",
+		Purpose: "Show the cloud-init user data used when provisioning a machine.",
+		Doc:     machineUserDataDoc,
+	})
+}
+
+// SetFlags implements cmd.Command.
+func (c *machineUserDataCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ModelCommandBase.SetFlags(f)
+	f.BoolVar(&c.preview, "preview", false, "Show the cloud-config document produced from the user data")
+	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
+		"yaml": cmd.FormatYaml,
+		"json": cmd.FormatJson,
+	})
+}
+
+// Init implements cmd.Command.
+func (c *machineUserDataCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no machine specified")
+	}
+	if !names.IsValidMachine(args[0]) {
+		return errors.NotValidf("machine ID %q", args[0])
+	}
+	c.machineId = args[0]
+	return cmd.CheckEmpty(args[1:])
+}
+
+// machineUserData is the formatted output of the command.
+type machineUserData struct {
+	Machine         string                 `yaml:"machine" json:"machine"`
+	MatchedOverlays []string               `yaml:"matched-overlays,omitempty" json:"matched-overlays,omitempty"`
+	UserData        map[string]interface{} `yaml:"userdata,omitempty" json:"userdata,omitempty"`
+}
+
+// Run implements cmd.Command.
+func (c *machineUserDataCommand) Run(ctx *cmd.Context) error {
+	client, err := c.getAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+
+	result, err := client.MachineUserData(c.machineId)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if c.preview {
+		if result.Preview == "" {
+			return errors.NotSupportedf("previewing the user data of machine %s", c.machineId)
+		}
+		_, err := fmt.Fprint(ctx.Stdout, result.Preview)
+		return errors.Trace(err)
+	}
+
+	out := machineUserData{
+		Machine:         c.machineId,
+		MatchedOverlays: result.MatchedOverlays,
+	}
+	if result.UserData != "" {
+		var userData map[string]interface{}
+		if err := yaml.Unmarshal([]byte(result.UserData), &userData); err != nil {
+			return errors.Annotate(err, "cannot parse user data")
+		}
+		// Nested maps must have string keys to be formatted as JSON.
+		conformed, err := utils.ConformYAML(userData)
+		if err != nil {
+			return errors.Annotate(err, "cannot parse user data")
+		}
+		out.UserData = conformed.(map[string]interface{})
+	}
+	return c.out.Write(ctx, out)
+}
+
+func (c *machineUserDataCommand) getAPI() (MachineUserDataAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return machinemanager.NewClient(root), nil
+}
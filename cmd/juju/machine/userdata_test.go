@@ -0,0 +1,127 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machine_test
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/machine"
+	"github.com/juju/juju/testing"
+)
+
+type MachineUserDataSuite struct {
+	testing.FakeJujuXDGDataHomeSuite
+	api *fakeMachineUserDataAPI
+}
+
+var _ = gc.Suite(&MachineUserDataSuite{})
+
+func (s *MachineUserDataSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuXDGDataHomeSuite.SetUpTest(c)
+	s.api = &fakeMachineUserDataAPI{
+		result: params.MachineUserDataResult{
+			UserData:        "packages:\n- htop\n- arm-tools\nwrite_files:\n- content: enabled\n  path: /etc/gpu.conf\n",
+			MatchedOverlays: []string{"arch=arm64"},
+			Preview:         "#cloud-config\npackages:\n- htop\n- arm-tools\n",
+		},
+	}
+}
+
+func (s *MachineUserDataSuite) run(c *gc.C, args ...string) (*cmd.Context, error) {
+	return cmdtesting.RunCommand(c, machine.NewMachineUserDataCommandForTest(s.api), args...)
+}
+
+func (s *MachineUserDataSuite) TestInit(c *gc.C) {
+	for i, test := range []struct {
+		args        []string
+		errorString string
+	}{{
+		errorString: "no machine specified",
+	}, {
+		args:        []string{"foo"},
+		errorString: `machine ID "foo" not valid`,
+	}, {
+		args:        []string{"1", "2"},
+		errorString: `unrecognized args: \["2"\]`,
+	}, {
+		args: []string{"0/lxd/1", "--preview"},
+	}} {
+		c.Logf("test %d", i)
+		err := cmdtesting.InitCommand(machine.NewMachineUserDataCommandForTest(nil), test.args)
+		if test.errorString == "" {
+			c.Check(err, jc.ErrorIsNil)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.errorString)
+		}
+	}
+}
+
+func (s *MachineUserDataSuite) TestShowYAML(c *gc.C) {
+	ctx, err := s.run(c, "1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, `
+machine: "1"
+matched-overlays:
+- arch=arm64
+userdata:
+  packages:
+  - htop
+  - arm-tools
+  write_files:
+  - content: enabled
+    path: /etc/gpu.conf
+`[1:])
+	c.Assert(s.api.machineId, gc.Equals, "1")
+}
+
+func (s *MachineUserDataSuite) TestShowJSON(c *gc.C) {
+	ctx, err := s.run(c, "1", "--format", "json")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, `{"machine":"1","matched-overlays":["arch=arm64"],"userdata":{"packages":["htop","arm-tools"],"write_files":[{"content":"enabled","path":"/etc/gpu.conf"}]}}`+"\n")
+}
+
+func (s *MachineUserDataSuite) TestShowNoUserData(c *gc.C) {
+	s.api.result = params.MachineUserDataResult{}
+	ctx, err := s.run(c, "1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, "machine: \"1\"\n")
+}
+
+func (s *MachineUserDataSuite) TestPreview(c *gc.C) {
+	ctx, err := s.run(c, "1", "--preview")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, "#cloud-config\npackages:\n- htop\n- arm-tools\n")
+}
+
+func (s *MachineUserDataSuite) TestPreviewNotSupported(c *gc.C) {
+	s.api.result.Preview = ""
+	_, err := s.run(c, "1", "--preview")
+	c.Assert(err, gc.ErrorMatches, "previewing the user data of machine 1 not supported")
+}
+
+func (s *MachineUserDataSuite) TestError(c *gc.C) {
+	s.api.err = errors.NotFoundf("machine 1")
+	_, err := s.run(c, "1")
+	c.Assert(err, gc.ErrorMatches, "machine 1 not found")
+}
+
+type fakeMachineUserDataAPI struct {
+	machineId string
+	result    params.MachineUserDataResult
+	err       error
+}
+
+func (f *fakeMachineUserDataAPI) MachineUserData(machineId string) (params.MachineUserDataResult, error) {
+	f.machineId = machineId
+	return f.result, f.err
+}
+
+func (*fakeMachineUserDataAPI) Close() error {
+	return nil
+}
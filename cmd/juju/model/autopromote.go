@@ -0,0 +1,168 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package model
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+
+	"github.com/juju/juju/api/modelgeneration"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/core/model"
+)
+
+const (
+	autoPromoteSummary = "Automatically commits or aborts a branch based on unit health."
+	autoPromoteDoc     = `
+Sets a branch to be completed automatically based on the health of the
+units tracking it. A unit is healthy unless its workload or agent reports
+a problem, such as an error, blocked or lost status; units in maintenance
+or running hooks are healthy. Once all of the units tracking the branch
+have been healthy for the soak time, the branch is committed. If they are
+not healthy once the soak time has elapsed from when this command is run,
+the units are reverted to the master generation and the branch is aborted.
+
+The soak time must be at least one second. Running the command again
+restarts the soak time.
+Use --cancel to stop the branch being completed automatically.
+
+Examples:
+    juju auto-promote upgrade-postgresql 30m
+    juju auto-promote upgrade-postgresql --cancel
+
+See also:
+    track
+    branch
+    commit
+    abort
+`
+)
+
+// NewAutoPromoteCommand wraps autoPromoteCommand with sane model settings.
+func NewAutoPromoteCommand() cmd.Command {
+	return modelcmd.Wrap(&autoPromoteCommand{})
+}
+
+// autoPromoteCommand supplies the "auto-promote" CLI command used to set
+// the promotion policy of a branch in the current model.
+type autoPromoteCommand struct {
+	modelcmd.ModelCommandBase
+
+	api AutoPromoteCommandAPI
+
+	branchName string
+	soakTime   time.Duration
+	cancel     bool
+}
+
+// AutoPromoteCommandAPI describes API methods required
+// to execute the auto-promote command.
+//go:generate go run github.com/golang/mock/mockgen -package mocks -destination ./mocks/autopromote_mock.go github.com/juju/juju/cmd/juju/model AutoPromoteCommandAPI
+type AutoPromoteCommandAPI interface {
+	Close() error
+
+	// SetPromotionPolicy sets the soak time after which the
+	// branch is automatically committed or aborted.
+	SetPromotionPolicy(branchName string, soakTime time.Duration) error
+
+	// HasActiveBranch returns true if the model has an
+	// "in-flight" branch with the input name.
+	HasActiveBranch(branchName string) (bool, error)
+}
+
+// Info implements part of the cmd.Command interface.
+func (c *autoPromoteCommand) Info() *cmd.Info {
+	info := &cmd.Info{
+		Name:    "auto-promote",
+		Args:    "<branch name> [<soak time>]",
+		Purpose: autoPromoteSummary,
+		Doc:     autoPromoteDoc,
+	}
+	return jujucmd.Info(info)
+}
+
+// SetFlags implements part of the cmd.Command interface.
+func (c *autoPromoteCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ModelCommandBase.SetFlags(f)
+	f.BoolVar(&c.cancel, "cancel", false, "Stop the branch being completed automatically")
+}
+
+// Init implements part of the cmd.Command interface.
+func (c *autoPromoteCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.Errorf("expected a branch name")
+	}
+	if err := model.ValidateBranchName(args[0]); err != nil {
+		return err
+	}
+	c.branchName = args[0]
+
+	if c.cancel {
+		if len(args) > 1 {
+			return errors.Errorf("soak time can not be specified with --cancel")
+		}
+		return nil
+	}
+	if len(args) != 2 {
+		return errors.Errorf("expected a branch name and soak time")
+	}
+	soakTime, err := time.ParseDuration(args[1])
+	if err != nil {
+		return errors.Annotate(err, "invalid soak time")
+	}
+	if soakTime < time.Second {
+		return errors.Errorf("soak time must be at least 1s")
+	}
+	c.soakTime = soakTime
+	return nil
+}
+
+// getAPI returns the API that supplies methods
+// required to execute this command.
+func (c *autoPromoteCommand) getAPI() (AutoPromoteCommandAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	api, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Annotate(err, "opening API connection")
+	}
+	client := modelgeneration.NewClient(api)
+	return client, nil
+}
+
+// Run implements the meaty part of the cmd.Command interface.
+func (c *autoPromoteCommand) Run(ctx *cmd.Context) error {
+	client, err := c.getAPI()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	hasBranch, err := client.HasActiveBranch(c.branchName)
+	if err != nil {
+		return err
+	}
+	if !hasBranch {
+		return errors.Errorf("this model has no active branch %q", c.branchName)
+	}
+
+	if err = client.SetPromotionPolicy(c.branchName, c.soakTime); err != nil {
+		return err
+	}
+
+	var msg string
+	if c.cancel {
+		msg = fmt.Sprintf("Branch %q will no longer be completed automatically.\n", c.branchName)
+	} else {
+		msg = fmt.Sprintf("Branch %q will be committed once all tracking units have been healthy for %v.\n", c.branchName, c.soakTime)
+	}
+	_, err = ctx.Stdout.Write([]byte(msg))
+	return err
+}
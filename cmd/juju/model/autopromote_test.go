@@ -0,0 +1,114 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package model_test
+
+import (
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/juju/cmd"
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/juju/model"
+	"github.com/juju/juju/cmd/juju/model/mocks"
+	coremodel "github.com/juju/juju/core/model"
+)
+
+type autoPromoteSuite struct {
+	generationBaseSuite
+}
+
+var _ = gc.Suite(&autoPromoteSuite{})
+
+func (s *autoPromoteSuite) TestInit(c *gc.C) {
+	err := s.runInit(s.branchName, "30m")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *autoPromoteSuite) TestInitCancel(c *gc.C) {
+	err := s.runInit(s.branchName, "--cancel")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *autoPromoteSuite) TestInitCancelWithSoakTime(c *gc.C) {
+	err := s.runInit(s.branchName, "30m", "--cancel")
+	c.Assert(err, gc.ErrorMatches, "soak time can not be specified with --cancel")
+}
+
+func (s *autoPromoteSuite) TestInitNoName(c *gc.C) {
+	err := s.runInit()
+	c.Assert(err, gc.ErrorMatches, "expected a branch name")
+}
+
+func (s *autoPromoteSuite) TestInitNoSoakTime(c *gc.C) {
+	err := s.runInit(s.branchName)
+	c.Assert(err, gc.ErrorMatches, "expected a branch name and soak time")
+}
+
+func (s *autoPromoteSuite) TestInitInvalidName(c *gc.C) {
+	err := s.runInit(coremodel.GenerationMaster, "30m")
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *autoPromoteSuite) TestInitInvalidSoakTime(c *gc.C) {
+	err := s.runInit(s.branchName, "soon")
+	c.Assert(err, gc.ErrorMatches, `invalid soak time: time: invalid duration "?soon"?`)
+
+	err = s.runInit(s.branchName, "10ms")
+	c.Assert(err, gc.ErrorMatches, "soak time must be at least 1s")
+}
+
+func (s *autoPromoteSuite) TestRunCommand(c *gc.C) {
+	ctrl, api := setUpAutoPromoteMocks(c)
+	defer ctrl.Finish()
+
+	api.EXPECT().HasActiveBranch(s.branchName).Return(true, nil)
+	api.EXPECT().SetPromotionPolicy(s.branchName, 30*time.Minute).Return(nil)
+
+	ctx, err := s.runCommand(c, api, s.branchName, "30m")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals,
+		"Branch \""+s.branchName+"\" will be committed once all tracking units have been healthy for 30m0s.\n")
+}
+
+func (s *autoPromoteSuite) TestRunCommandCancel(c *gc.C) {
+	ctrl, api := setUpAutoPromoteMocks(c)
+	defer ctrl.Finish()
+
+	api.EXPECT().HasActiveBranch(s.branchName).Return(true, nil)
+	api.EXPECT().SetPromotionPolicy(s.branchName, time.Duration(0)).Return(nil)
+
+	ctx, err := s.runCommand(c, api, s.branchName, "--cancel")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals,
+		"Branch \""+s.branchName+"\" will no longer be completed automatically.\n")
+}
+
+func (s *autoPromoteSuite) TestRunCommandFailHasActiveBranch(c *gc.C) {
+	ctrl, api := setUpAutoPromoteMocks(c)
+	defer ctrl.Finish()
+
+	api.EXPECT().HasActiveBranch(s.branchName).Return(false, nil)
+
+	_, err := s.runCommand(c, api, s.branchName, "30m")
+	c.Assert(err, gc.ErrorMatches, "this model has no active branch \""+s.branchName+"\"")
+}
+
+func (s *autoPromoteSuite) runInit(args ...string) error {
+	return cmdtesting.InitCommand(model.NewAutoPromoteCommandForTest(nil, s.store), args)
+}
+
+func (s *autoPromoteSuite) runCommand(c *gc.C, api model.AutoPromoteCommandAPI, args ...string) (*cmd.Context, error) {
+	return cmdtesting.RunCommand(c, model.NewAutoPromoteCommandForTest(api, s.store), args...)
+}
+
+func setUpAutoPromoteMocks(c *gc.C) (*gomock.Controller, *mocks.MockAutoPromoteCommandAPI) {
+	ctrl := gomock.NewController(c)
+	api := mocks.NewMockAutoPromoteCommandAPI(ctrl)
+	api.EXPECT().Close()
+	return ctrl, api
+}
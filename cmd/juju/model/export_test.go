@@ -107,20 +107,22 @@ type RevokeCommand struct {
 }
 
 // NewGrantCommandForTest returns a GrantCommand with the api provided as specified.
-func NewGrantCommandForTest(modelsApi GrantModelAPI, offersAPI GrantOfferAPI, store jujuclient.ClientStore) (cmd.Command, *GrantCommand) {
+func NewGrantCommandForTest(modelsApi GrantModelAPI, controllerAPI GrantControllerAPI, offersAPI GrantOfferAPI, store jujuclient.ClientStore) (cmd.Command, *GrantCommand) {
 	cmd := &grantCommand{
-		modelsApi: modelsApi,
-		offersApi: offersAPI,
+		modelsApi:     modelsApi,
+		controllerApi: controllerAPI,
+		offersApi:     offersAPI,
 	}
 	cmd.SetClientStore(store)
 	return modelcmd.WrapController(cmd), &GrantCommand{cmd}
 }
 
 // NewRevokeCommandForTest returns an revokeCommand with the api provided as specified.
-func NewRevokeCommandForTest(modelsApi RevokeModelAPI, offersAPI RevokeOfferAPI, store jujuclient.ClientStore) (cmd.Command, *RevokeCommand) {
+func NewRevokeCommandForTest(modelsApi RevokeModelAPI, controllerAPI RevokeControllerAPI, offersAPI RevokeOfferAPI, store jujuclient.ClientStore) (cmd.Command, *RevokeCommand) {
 	cmd := &revokeCommand{
-		modelsApi: modelsApi,
-		offersApi: offersAPI,
+		modelsApi:     modelsApi,
+		controllerApi: controllerAPI,
+		offersApi:     offersAPI,
 	}
 	cmd.SetClientStore(store)
 	return modelcmd.WrapController(cmd), &RevokeCommand{cmd}
@@ -197,6 +199,14 @@ func NewAbortCommandForTest(api AbortCommandAPI, store jujuclient.ClientStore) c
 	return modelcmd.Wrap(cmd)
 }
 
+func NewAutoPromoteCommandForTest(api AutoPromoteCommandAPI, store jujuclient.ClientStore) cmd.Command {
+	cmd := &autoPromoteCommand{
+		api: api,
+	}
+	cmd.SetClientStore(store)
+	return modelcmd.Wrap(cmd)
+}
+
 func NewCommitCommandForTest(api CommitCommandAPI, store jujuclient.ClientStore) cmd.Command {
 	cmd := &commitCommand{
 		api: api,
@@ -244,3 +254,38 @@ func NewShowCommitCommandForTest(api ShowCommitCommandAPI, store jujuclient.Clie
 	cmd.SetClientStore(store)
 	return modelcmd.Wrap(cmd)
 }
+
+// NewAddRoleCommandForTest returns an add-role command with the api provided.
+func NewAddRoleCommandForTest(api RolesAPI, store jujuclient.ClientStore) cmd.Command {
+	cmd := &addRoleCommand{api: api}
+	cmd.SetClientStore(store)
+	return modelcmd.WrapController(cmd)
+}
+
+// NewRemoveRoleCommandForTest returns a remove-role command with the api provided.
+func NewRemoveRoleCommandForTest(api RolesAPI, store jujuclient.ClientStore) cmd.Command {
+	cmd := &removeRoleCommand{api: api}
+	cmd.SetClientStore(store)
+	return modelcmd.WrapController(cmd)
+}
+
+// NewRolesCommandForTest returns a roles command with the api provided.
+func NewRolesCommandForTest(api RolesAPI, store jujuclient.ClientStore) cmd.Command {
+	cmd := &rolesCommand{api: api}
+	cmd.SetClientStore(store)
+	return modelcmd.WrapController(cmd)
+}
+
+// NewAssignRoleCommandForTest returns an assign-role command with the api provided.
+func NewAssignRoleCommandForTest(api RolesAPI, store jujuclient.ClientStore) cmd.Command {
+	cmd := &assignRoleCommand{api: api}
+	cmd.SetClientStore(store)
+	return modelcmd.Wrap(cmd)
+}
+
+// NewUnassignRoleCommandForTest returns an unassign-role command with the api provided.
+func NewUnassignRoleCommandForTest(api RolesAPI, store jujuclient.ClientStore) cmd.Command {
+	cmd := &unassignRoleCommand{api: api}
+	cmd.SetClientStore(store)
+	return modelcmd.Wrap(cmd)
+}
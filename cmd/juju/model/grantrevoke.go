@@ -4,6 +4,8 @@
 package model
 
 import (
+	"strings"
+
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
 	"github.com/juju/names/v4"
@@ -23,6 +25,11 @@ Grants access level to a Juju user for a model, controller, or application offer
 var usageGrantDetails = `
 By default, the controller is the current controller.
 
+Access to models and the controller may also be granted to a group, named
+as "group:<group name>". Members of the group, as listed in the LDAP
+directory configured by the controller's ldap-url setting, are given the
+access granted to the group in addition to any granted to them directly.
+
 Users with read access are limited in what they can do with models:
 ` + "`juju models`, `juju machines`, and `juju status`" + `.
 
@@ -65,6 +72,14 @@ Grant user 'sam' 'read' access to application offers 'fred/prod.hosted-mysql' an
 
     juju grant sam read fred/prod.hosted-mysql mary/test.hosted-mysql
 
+Grant members of group 'ops' 'admin' access to model 'mymodel':
+
+    juju grant group:ops admin mymodel
+
+Grant members of group 'ops' 'login' access to the controller:
+
+    juju grant group:ops login
+
 See also: 
     revoke
     add-user`[1:]
@@ -96,13 +111,23 @@ Revoke 'consume' access from user 'sam' for models 'fred/prod.hosted-mysql' and
 
     juju revoke sam consume fred/prod.hosted-mysql mary/test.hosted-mysql
 
+Revoke 'admin' access from members of group 'ops' for model 'mymodel',
+leaving them with 'write' access:
+
+    juju revoke group:ops admin mymodel
+
 See also: 
     grant`[1:]
 
+// groupPrefix marks a group, rather than a user, as the subject of a
+// grant or revoke.
+const groupPrefix = "group:"
+
 type accessCommand struct {
 	modelcmd.ControllerCommandBase
 
 	User       string
+	Group      string
 	ModelNames []string
 	OfferURLs  []*crossmodel.OfferURL
 	Access     string
@@ -118,7 +143,14 @@ func (c *accessCommand) Init(args []string) error {
 		return errors.New("no permission level specified")
 	}
 
-	c.User = args[0]
+	if group := strings.TrimPrefix(args[0], groupPrefix); group != args[0] {
+		if group == "" {
+			return errors.New("no group specified")
+		}
+		c.Group = group
+	} else {
+		c.User = args[0]
+	}
 	c.Access = args[1]
 	// The remaining args are either model names or offer names.
 	for _, arg := range args[2:] {
@@ -143,6 +175,9 @@ func (c *accessCommand) Init(args []string) error {
 	if len(c.ModelNames) > 0 && len(c.OfferURLs) > 0 {
 		return errors.New("either specify model names or offer URLs but not both")
 	}
+	if c.Group != "" && len(c.OfferURLs) > 0 {
+		return errors.New("access to offers cannot be granted to groups")
+	}
 
 	if len(c.ModelNames) > 0 || len(c.OfferURLs) > 0 {
 		if err := permission.ValidateControllerAccess(permission.Access(c.Access)); err == nil {
@@ -173,15 +208,16 @@ func NewGrantCommand() cmd.Command {
 // grantCommand represents the command to grant a user access to one or more models.
 type grantCommand struct {
 	accessCommand
-	modelsApi GrantModelAPI
-	offersApi GrantOfferAPI
+	modelsApi     GrantModelAPI
+	controllerApi GrantControllerAPI
+	offersApi     GrantOfferAPI
 }
 
 // Info implements Command.Info.
 func (c *grantCommand) Info() *cmd.Info {
 	return jujucmd.Info(&cmd.Info{
 		Name:    "grant",
-		Args:    "<user name> <permission> [<model name> ... | <offer url> ...]",
+		Args:    "<user name>|group:<group name> <permission> [<model name> ... | <offer url> ...]",
 		Purpose: usageGrantSummary,
 		Doc:     usageGrantDetails,
 	})
@@ -195,6 +231,9 @@ func (c *grantCommand) getModelAPI() (GrantModelAPI, error) {
 }
 
 func (c *grantCommand) getControllerAPI() (GrantControllerAPI, error) {
+	if c.controllerApi != nil {
+		return c.controllerApi, nil
+	}
 	return c.NewControllerAPIClient()
 }
 
@@ -213,12 +252,14 @@ func (c *grantCommand) getOfferAPI() (GrantOfferAPI, error) {
 type GrantModelAPI interface {
 	Close() error
 	GrantModel(user, access string, modelUUIDs ...string) error
+	GrantGroupModel(group, access string, modelUUIDs ...string) error
 }
 
 // GrantControllerAPI defines the API functions used by the grant command.
 type GrantControllerAPI interface {
 	Close() error
 	GrantController(user, access string) error
+	GrantGroupController(group, access string) error
 }
 
 // GrantOfferAPI defines the API functions used by the grant command.
@@ -248,6 +289,9 @@ func (c *grantCommand) runForController() error {
 	}
 	defer client.Close()
 
+	if c.Group != "" {
+		return block.ProcessBlockedError(client.GrantGroupController(c.Group, c.Access), block.BlockChange)
+	}
 	return block.ProcessBlockedError(client.GrantController(c.User, c.Access), block.BlockChange)
 }
 
@@ -262,6 +306,9 @@ func (c *grantCommand) runForModel() error {
 	if err != nil {
 		return err
 	}
+	if c.Group != "" {
+		return block.ProcessBlockedError(client.GrantGroupModel(c.Group, c.Access, models...), block.BlockChange)
+	}
 	return block.ProcessBlockedError(client.GrantModel(c.User, c.Access, models...), block.BlockChange)
 }
 
@@ -288,15 +335,16 @@ func NewRevokeCommand() cmd.Command {
 // revokeCommand revokes a user's access to models.
 type revokeCommand struct {
 	accessCommand
-	modelsApi RevokeModelAPI
-	offersApi RevokeOfferAPI
+	modelsApi     RevokeModelAPI
+	controllerApi RevokeControllerAPI
+	offersApi     RevokeOfferAPI
 }
 
 // Info implements cmd.Command.
 func (c *revokeCommand) Info() *cmd.Info {
 	return jujucmd.Info(&cmd.Info{
 		Name:    "revoke",
-		Args:    "<user name> <permission> [<model name> ... | <offer url> ...]",
+		Args:    "<user name>|group:<group name> <permission> [<model name> ... | <offer url> ...]",
 		Purpose: usageRevokeSummary,
 		Doc:     usageRevokeDetails,
 	})
@@ -310,6 +358,9 @@ func (c *revokeCommand) getModelAPI() (RevokeModelAPI, error) {
 }
 
 func (c *revokeCommand) getControllerAPI() (RevokeControllerAPI, error) {
+	if c.controllerApi != nil {
+		return c.controllerApi, nil
+	}
 	return c.NewControllerAPIClient()
 }
 
@@ -328,12 +379,14 @@ func (c *revokeCommand) getOfferAPI() (RevokeOfferAPI, error) {
 type RevokeModelAPI interface {
 	Close() error
 	RevokeModel(user, access string, modelUUIDs ...string) error
+	RevokeGroupModel(group, access string, modelUUIDs ...string) error
 }
 
 // RevokeControllerAPI defines the API functions used by the revoke command.
 type RevokeControllerAPI interface {
 	Close() error
 	RevokeController(user, access string) error
+	RevokeGroupController(group, access string) error
 }
 
 // RevokeOfferAPI defines the API functions used by the revoke command.
@@ -363,6 +416,9 @@ func (c *revokeCommand) runForController() error {
 	}
 	defer client.Close()
 
+	if c.Group != "" {
+		return block.ProcessBlockedError(client.RevokeGroupController(c.Group, c.Access), block.BlockChange)
+	}
 	return block.ProcessBlockedError(client.RevokeController(c.User, c.Access), block.BlockChange)
 }
 
@@ -377,6 +433,9 @@ func (c *revokeCommand) runForModel() error {
 	if err != nil {
 		return err
 	}
+	if c.Group != "" {
+		return block.ProcessBlockedError(client.RevokeGroupModel(c.Group, c.Access, models...), block.BlockChange)
+	}
 	return block.ProcessBlockedError(client.RevokeModel(c.User, c.Access, models...), block.BlockChange)
 }
 
@@ -21,10 +21,11 @@ import (
 
 type grantRevokeSuite struct {
 	testing.FakeJujuXDGDataHomeSuite
-	fakeModelAPI  *fakeModelGrantRevokeAPI
-	fakeOffersAPI *fakeOffersGrantRevokeAPI
-	cmdFactory    func(*fakeModelGrantRevokeAPI, *fakeOffersGrantRevokeAPI) cmd.Command
-	store         *jujuclient.MemStore
+	fakeModelAPI      *fakeModelGrantRevokeAPI
+	fakeControllerAPI *fakeControllerGrantRevokeAPI
+	fakeOffersAPI     *fakeOffersGrantRevokeAPI
+	cmdFactory        func(*fakeModelGrantRevokeAPI, *fakeControllerGrantRevokeAPI, *fakeOffersGrantRevokeAPI) cmd.Command
+	store             *jujuclient.MemStore
 }
 
 const (
@@ -38,6 +39,7 @@ const (
 func (s *grantRevokeSuite) SetUpTest(c *gc.C) {
 	s.FakeJujuXDGDataHomeSuite.SetUpTest(c)
 	s.fakeModelAPI = &fakeModelGrantRevokeAPI{}
+	s.fakeControllerAPI = &fakeControllerGrantRevokeAPI{}
 	s.fakeOffersAPI = &fakeOffersGrantRevokeAPI{}
 
 	// Set up the current controller, and write just enough info
@@ -64,7 +66,7 @@ func (s *grantRevokeSuite) SetUpTest(c *gc.C) {
 }
 
 func (s *grantRevokeSuite) run(c *gc.C, args ...string) (*cmd.Context, error) {
-	command := s.cmdFactory(s.fakeModelAPI, s.fakeOffersAPI)
+	command := s.cmdFactory(s.fakeModelAPI, s.fakeControllerAPI, s.fakeOffersAPI)
 	return cmdtesting.RunCommand(c, command, args...)
 }
 
@@ -105,6 +107,31 @@ func (s *grantRevokeSuite) TestModelAccess(c *gc.C) {
 	c.Assert(s.fakeModelAPI.access, gc.Equals, "write")
 }
 
+func (s *grantRevokeSuite) TestGroupModelAccess(c *gc.C) {
+	_, err := s.run(c, "group:ops", "admin", "model1", "model2")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fakeModelAPI.user, gc.Equals, "")
+	c.Assert(s.fakeModelAPI.group, gc.Equals, "ops")
+	c.Assert(s.fakeModelAPI.modelUUIDs, jc.DeepEquals, []string{model1ModelUUID, model2ModelUUID})
+	c.Assert(s.fakeModelAPI.access, gc.Equals, "admin")
+}
+
+func (s *grantRevokeSuite) TestGroupControllerAccess(c *gc.C) {
+	_, err := s.run(c, "group:ops", "superuser")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fakeControllerAPI.user, gc.Equals, "")
+	c.Assert(s.fakeControllerAPI.group, gc.Equals, "ops")
+	c.Assert(s.fakeControllerAPI.access, gc.Equals, "superuser")
+}
+
+func (s *grantRevokeSuite) TestUserControllerAccess(c *gc.C) {
+	_, err := s.run(c, "sam", "login")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fakeControllerAPI.user, gc.Equals, "sam")
+	c.Assert(s.fakeControllerAPI.group, gc.Equals, "")
+	c.Assert(s.fakeControllerAPI.access, gc.Equals, "login")
+}
+
 func (s *grantRevokeSuite) TestModelBlockGrant(c *gc.C) {
 	s.fakeModelAPI.err = common.OperationBlockedError("TestBlockGrant")
 	_, err := s.run(c, "sam", "read", "foo")
@@ -119,14 +146,14 @@ var _ = gc.Suite(&grantSuite{})
 
 func (s *grantSuite) SetUpTest(c *gc.C) {
 	s.grantRevokeSuite.SetUpTest(c)
-	s.cmdFactory = func(fakeModelAPI *fakeModelGrantRevokeAPI, fakeOfferAPI *fakeOffersGrantRevokeAPI) cmd.Command {
-		c, _ := model.NewGrantCommandForTest(fakeModelAPI, fakeOfferAPI, s.store)
+	s.cmdFactory = func(fakeModelAPI *fakeModelGrantRevokeAPI, fakeControllerAPI *fakeControllerGrantRevokeAPI, fakeOfferAPI *fakeOffersGrantRevokeAPI) cmd.Command {
+		c, _ := model.NewGrantCommandForTest(fakeModelAPI, fakeControllerAPI, fakeOfferAPI, s.store)
 		return c
 	}
 }
 
 func (s *grantSuite) TestInitModels(c *gc.C) {
-	wrappedCmd, grantCmd := model.NewGrantCommandForTest(nil, nil, s.store)
+	wrappedCmd, grantCmd := model.NewGrantCommandForTest(nil, nil, nil, s.store)
 	err := cmdtesting.InitCommand(wrappedCmd, []string{})
 	c.Assert(err, gc.ErrorMatches, "no user specified")
 
@@ -141,8 +168,24 @@ func (s *grantSuite) TestInitModels(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, `no user specified`)
 }
 
+func (s *grantSuite) TestInitGroup(c *gc.C) {
+	wrappedCmd, grantCmd := model.NewGrantCommandForTest(nil, nil, nil, s.store)
+	err := cmdtesting.InitCommand(wrappedCmd, []string{"group:ops", "read", "model1"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(grantCmd.User, gc.Equals, "")
+	c.Assert(grantCmd.Group, gc.Equals, "ops")
+	c.Assert(grantCmd.ModelNames, jc.DeepEquals, []string{"model1"})
+
+	err = cmdtesting.InitCommand(wrappedCmd, []string{"group:", "read", "model1"})
+	c.Assert(err, gc.ErrorMatches, "no group specified")
+
+	wrappedCmd, _ = model.NewGrantCommandForTest(nil, nil, nil, s.store)
+	err = cmdtesting.InitCommand(wrappedCmd, []string{"group:ops", "read", "fred/model.offer1"})
+	c.Assert(err, gc.ErrorMatches, "access to offers cannot be granted to groups")
+}
+
 func (s *grantSuite) TestInitOffers(c *gc.C) {
-	wrappedCmd, grantCmd := model.NewGrantCommandForTest(nil, nil, s.store)
+	wrappedCmd, grantCmd := model.NewGrantCommandForTest(nil, nil, nil, s.store)
 
 	err := cmdtesting.InitCommand(wrappedCmd, []string{"bob", "read", "fred/model.offer1", "mary/model.offer2"})
 	c.Assert(err, jc.ErrorIsNil)
@@ -164,14 +207,14 @@ var _ = gc.Suite(&revokeSuite{})
 
 func (s *revokeSuite) SetUpTest(c *gc.C) {
 	s.grantRevokeSuite.SetUpTest(c)
-	s.cmdFactory = func(fakeModelAPI *fakeModelGrantRevokeAPI, fakeOffersAPI *fakeOffersGrantRevokeAPI) cmd.Command {
-		c, _ := model.NewRevokeCommandForTest(fakeModelAPI, fakeOffersAPI, s.store)
+	s.cmdFactory = func(fakeModelAPI *fakeModelGrantRevokeAPI, fakeControllerAPI *fakeControllerGrantRevokeAPI, fakeOffersAPI *fakeOffersGrantRevokeAPI) cmd.Command {
+		c, _ := model.NewRevokeCommandForTest(fakeModelAPI, fakeControllerAPI, fakeOffersAPI, s.store)
 		return c
 	}
 }
 
 func (s *revokeSuite) TestInit(c *gc.C) {
-	wrappedCmd, revokeCmd := model.NewRevokeCommandForTest(nil, nil, s.store)
+	wrappedCmd, revokeCmd := model.NewRevokeCommandForTest(nil, nil, nil, s.store)
 	err := cmdtesting.InitCommand(wrappedCmd, []string{})
 	c.Assert(err, gc.ErrorMatches, "no user specified")
 
@@ -187,21 +230,21 @@ func (s *revokeSuite) TestInit(c *gc.C) {
 }
 
 func (s *grantSuite) TestModelAccessForController(c *gc.C) {
-	wrappedCmd, _ := model.NewRevokeCommandForTest(nil, nil, s.store)
+	wrappedCmd, _ := model.NewRevokeCommandForTest(nil, nil, nil, s.store)
 	err := cmdtesting.InitCommand(wrappedCmd, []string{"bob", "write"})
 	msg := strings.Replace(err.Error(), "\n", "", -1)
 	c.Check(msg, gc.Matches, `You have specified a model access permission "write".*`)
 }
 
 func (s *grantSuite) TestControllerAccessForModel(c *gc.C) {
-	wrappedCmd, _ := model.NewRevokeCommandForTest(nil, nil, s.store)
+	wrappedCmd, _ := model.NewRevokeCommandForTest(nil, nil, nil, s.store)
 	err := cmdtesting.InitCommand(wrappedCmd, []string{"bob", "superuser", "default"})
 	msg := strings.Replace(err.Error(), "\n", "", -1)
 	c.Check(msg, gc.Matches, `You have specified a controller access permission "superuser".*`)
 }
 
 func (s *grantSuite) TestControllerAccessForOffer(c *gc.C) {
-	wrappedCmd, _ := model.NewRevokeCommandForTest(nil, nil, s.store)
+	wrappedCmd, _ := model.NewRevokeCommandForTest(nil, nil, nil, s.store)
 	err := cmdtesting.InitCommand(wrappedCmd, []string{"bob", "superuser", "fred/default.mysql"})
 	msg := strings.Replace(err.Error(), "\n", "", -1)
 	c.Check(msg, gc.Matches, `You have specified a controller access permission "superuser".*`)
@@ -210,6 +253,7 @@ func (s *grantSuite) TestControllerAccessForOffer(c *gc.C) {
 type fakeModelGrantRevokeAPI struct {
 	err        error
 	user       string
+	group      string
 	access     string
 	modelUUIDs []string
 }
@@ -224,6 +268,16 @@ func (f *fakeModelGrantRevokeAPI) RevokeModel(user, access string, modelUUIDs ..
 	return f.fake(user, access, modelUUIDs...)
 }
 
+func (f *fakeModelGrantRevokeAPI) GrantGroupModel(group, access string, modelUUIDs ...string) error {
+	f.group = group
+	return f.fake("", access, modelUUIDs...)
+}
+
+func (f *fakeModelGrantRevokeAPI) RevokeGroupModel(group, access string, modelUUIDs ...string) error {
+	f.group = group
+	return f.fake("", access, modelUUIDs...)
+}
+
 func (f *fakeModelGrantRevokeAPI) fake(user, access string, modelUUIDs ...string) error {
 	f.user = user
 	f.access = access
@@ -231,6 +285,35 @@ func (f *fakeModelGrantRevokeAPI) fake(user, access string, modelUUIDs ...string
 	return f.err
 }
 
+type fakeControllerGrantRevokeAPI struct {
+	err    error
+	user   string
+	group  string
+	access string
+}
+
+func (f *fakeControllerGrantRevokeAPI) Close() error { return nil }
+
+func (f *fakeControllerGrantRevokeAPI) GrantController(user, access string) error {
+	f.user, f.access = user, access
+	return f.err
+}
+
+func (f *fakeControllerGrantRevokeAPI) RevokeController(user, access string) error {
+	f.user, f.access = user, access
+	return f.err
+}
+
+func (f *fakeControllerGrantRevokeAPI) GrantGroupController(group, access string) error {
+	f.group, f.access = group, access
+	return f.err
+}
+
+func (f *fakeControllerGrantRevokeAPI) RevokeGroupController(group, access string) error {
+	f.group, f.access = group, access
+	return f.err
+}
+
 type fakeOffersGrantRevokeAPI struct {
 	err       error
 	user      string
@@ -0,0 +1,472 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package model
+
+import (
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"github.com/juju/names/v4"
+
+	"github.com/juju/juju/api/roles"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/cmd/output"
+	"github.com/juju/juju/core/permission"
+)
+
+var usageAddRoleSummary = `
+Adds a custom role to the controller.`[1:]
+
+var usageAddRoleDetails = `
+A custom role is a named set of operations that can be assigned to a
+user of a model with the assign-role command. The role allows the user
+to make those changes to the model in addition to the changes their
+access level allows, so that, for example, a user with read access can
+be allowed to run actions without being able to remove anything.
+
+The --access option sets the greatest level of access to the model
+that the role grants for those operations: read, write or admin. It
+defaults to write. Operations that need more access than the role
+grants are refused.
+
+Operations are either operation classes, or individual API calls in
+the form Facade.Method. The operation classes are:
+    deploy      deploy, scale, relate and expose applications
+    config      change application and model config and constraints
+    run-action  run and cancel actions
+    exec        run commands on machines and units
+    remove      remove applications, units, relations and machines
+
+Only controller superusers can add roles.
+
+Examples:
+Allow a team to run actions and change config:
+
+    juju add-role app-team run-action config
+
+Allow a team to expose applications, and nothing else:
+
+    juju add-role exposers Application.Expose Application.Unexpose
+
+Allow a team to run actions that need no more than read access:
+
+    juju add-role --access read readers run-action
+
+See also:
+    roles
+    remove-role
+    assign-role`[1:]
+
+var usageRemoveRoleSummary = `
+Removes a custom role from the controller.`[1:]
+
+var usageRemoveRoleDetails = `
+A role that is assigned to any user of any model cannot be removed;
+use unassign-role first.
+
+Examples:
+    juju remove-role app-team
+
+See also:
+    add-role
+    unassign-role`[1:]
+
+var usageRolesSummary = `
+Lists the custom roles defined in the controller.`[1:]
+
+var usageRolesDetails = `
+Examples:
+    juju roles
+    juju roles --format yaml
+
+See also:
+    add-role
+    assign-role`[1:]
+
+var usageAssignRoleSummary = `
+Assigns a custom role to a user of a model.`[1:]
+
+var usageAssignRoleDetails = `
+The user must already have access to the model. The role allows the
+user to make the changes it lists, in addition to those allowed by
+their access level. A user has at most one role on each model;
+assigning a role replaces any role already assigned.
+
+Only model admins can assign roles.
+
+Examples:
+Allow joe, who has read access, to run actions in the current model:
+
+    juju assign-role joe app-team
+
+Assign the role on the model "mymodel":
+
+    juju assign-role -m mymodel joe app-team
+
+See also:
+    unassign-role
+    add-role
+    grant`[1:]
+
+var usageUnassignRoleSummary = `
+Removes the custom role assigned to a user of a model.`[1:]
+
+var usageUnassignRoleDetails = `
+The user keeps the access level they have been granted on the model.
+
+Examples:
+    juju unassign-role joe
+
+See also:
+    assign-role`[1:]
+
+// RolesAPI defines the API functions used by the role commands.
+type RolesAPI interface {
+	Close() error
+	AddRole(role permission.Role) error
+	ListRoles() ([]permission.Role, error)
+	RemoveRole(name string) error
+	SetModelUserRole(modelUUID, user, role string) error
+}
+
+// NewAddRoleCommand returns a new add-role command.
+func NewAddRoleCommand() cmd.Command {
+	return modelcmd.WrapController(&addRoleCommand{})
+}
+
+// addRoleCommand adds a custom role to the controller.
+type addRoleCommand struct {
+	modelcmd.ControllerCommandBase
+	api RolesAPI
+
+	Access string
+	Role   permission.Role
+}
+
+// Info implements cmd.Command.
+func (c *addRoleCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "add-role",
+		Args:    "<role name> <operation> ...",
+		Purpose: usageAddRoleSummary,
+		Doc:     usageAddRoleDetails,
+	})
+}
+
+// SetFlags implements cmd.Command.
+func (c *addRoleCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ControllerCommandBase.SetFlags(f)
+	f.StringVar(&c.Access, "access", string(permission.WriteAccess), "The greatest level of model access the role grants")
+}
+
+// Init implements cmd.Command.
+func (c *addRoleCommand) Init(args []string) error {
+	if len(args) < 1 {
+		return errors.New("no role name specified")
+	}
+	if len(args) < 2 {
+		return errors.New("no operations specified")
+	}
+	role := permission.Role{
+		Name:       args[0],
+		Access:     permission.Access(c.Access),
+		Operations: args[1:],
+	}
+	if err := role.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+	c.Role = role
+	return nil
+}
+
+// Run implements cmd.Command.
+func (c *addRoleCommand) Run(ctx *cmd.Context) error {
+	client, err := c.getAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+
+	return block.ProcessBlockedError(client.AddRole(c.Role), block.BlockChange)
+}
+
+func (c *addRoleCommand) getAPI() (RolesAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return roles.NewClient(root), nil
+}
+
+// NewRemoveRoleCommand returns a new remove-role command.
+func NewRemoveRoleCommand() cmd.Command {
+	return modelcmd.WrapController(&removeRoleCommand{})
+}
+
+// removeRoleCommand removes a custom role from the controller.
+type removeRoleCommand struct {
+	modelcmd.ControllerCommandBase
+	api RolesAPI
+
+	Name string
+}
+
+// Info implements cmd.Command.
+func (c *removeRoleCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "remove-role",
+		Args:    "<role name>",
+		Purpose: usageRemoveRoleSummary,
+		Doc:     usageRemoveRoleDetails,
+	})
+}
+
+// Init implements cmd.Command.
+func (c *removeRoleCommand) Init(args []string) error {
+	if len(args) < 1 {
+		return errors.New("no role name specified")
+	}
+	c.Name = args[0]
+	return cmd.CheckEmpty(args[1:])
+}
+
+// Run implements cmd.Command.
+func (c *removeRoleCommand) Run(ctx *cmd.Context) error {
+	client, err := c.getAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+
+	return block.ProcessBlockedError(client.RemoveRole(c.Name), block.BlockChange)
+}
+
+func (c *removeRoleCommand) getAPI() (RolesAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return roles.NewClient(root), nil
+}
+
+// NewRolesCommand returns a new roles command.
+func NewRolesCommand() cmd.Command {
+	return modelcmd.WrapController(&rolesCommand{})
+}
+
+// rolesCommand lists the custom roles defined in the controller.
+type rolesCommand struct {
+	modelcmd.ControllerCommandBase
+	api RolesAPI
+	out cmd.Output
+}
+
+// Info implements cmd.Command.
+func (c *rolesCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "roles",
+		Purpose: usageRolesSummary,
+		Doc:     usageRolesDetails,
+		Aliases: []string{"list-roles"},
+	})
+}
+
+// SetFlags implements cmd.Command.
+func (c *rolesCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ControllerCommandBase.SetFlags(f)
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatRolesTabular,
+	})
+}
+
+// Init implements cmd.Command.
+func (c *rolesCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+// Run implements cmd.Command.
+func (c *rolesCommand) Run(ctx *cmd.Context) error {
+	client, err := c.getAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+
+	result, err := client.ListRoles()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(result) == 0 && c.out.Name() == "tabular" {
+		ctx.Infof("No roles to list")
+		return nil
+	}
+	formatted := make(map[string]roleInfo)
+	for _, role := range result {
+		formatted[role.Name] = roleInfo{
+			Access:     string(role.Access),
+			Operations: role.Operations,
+		}
+	}
+	return errors.Trace(c.out.Write(ctx, formatted))
+}
+
+func (c *rolesCommand) getAPI() (RolesAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return roles.NewClient(root), nil
+}
+
+// roleInfo holds a custom role for output.
+type roleInfo struct {
+	Access     string   `yaml:"access" json:"access"`
+	Operations []string `yaml:"operations" json:"operations"`
+}
+
+func formatRolesTabular(writer io.Writer, value interface{}) error {
+	roles, ok := value.(map[string]roleInfo)
+	if !ok {
+		return errors.Errorf("expected value of type %T, got %T", roles, value)
+	}
+
+	tw := output.TabWriter(writer)
+	w := output.Wrapper{tw}
+
+	var names []string
+	for name := range roles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	w.Println("Role", "Access", "Operations")
+	for _, name := range names {
+		role := roles[name]
+		w.Println(name, role.Access, strings.Join(role.Operations, ", "))
+	}
+	return tw.Flush()
+}
+
+// NewAssignRoleCommand returns a new assign-role command.
+func NewAssignRoleCommand() cmd.Command {
+	return modelcmd.Wrap(&assignRoleCommand{})
+}
+
+// assignRoleCommand assigns a custom role to a user of a model.
+type assignRoleCommand struct {
+	modelcmd.ModelCommandBase
+	api RolesAPI
+
+	User string
+	Role string
+}
+
+// Info implements cmd.Command.
+func (c *assignRoleCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "assign-role",
+		Args:    "<user name> <role name>",
+		Purpose: usageAssignRoleSummary,
+		Doc:     usageAssignRoleDetails,
+	})
+}
+
+// Init implements cmd.Command.
+func (c *assignRoleCommand) Init(args []string) error {
+	if len(args) < 1 {
+		return errors.New("no user specified")
+	}
+	if len(args) < 2 {
+		return errors.New("no role specified")
+	}
+	if !names.IsValidUser(args[0]) {
+		return errors.NotValidf("user name %q", args[0])
+	}
+	if err := permission.ValidateRoleName(args[1]); err != nil {
+		return errors.Trace(err)
+	}
+	c.User, c.Role = args[0], args[1]
+	return cmd.CheckEmpty(args[2:])
+}
+
+// Run implements cmd.Command.
+func (c *assignRoleCommand) Run(ctx *cmd.Context) error {
+	return errors.Trace(setModelUserRole(&c.ModelCommandBase, c.api, c.User, c.Role))
+}
+
+// NewUnassignRoleCommand returns a new unassign-role command.
+func NewUnassignRoleCommand() cmd.Command {
+	return modelcmd.Wrap(&unassignRoleCommand{})
+}
+
+// unassignRoleCommand removes the custom role assigned to a user of
+// a model.
+type unassignRoleCommand struct {
+	modelcmd.ModelCommandBase
+	api RolesAPI
+
+	User string
+}
+
+// Info implements cmd.Command.
+func (c *unassignRoleCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "unassign-role",
+		Args:    "<user name>",
+		Purpose: usageUnassignRoleSummary,
+		Doc:     usageUnassignRoleDetails,
+	})
+}
+
+// Init implements cmd.Command.
+func (c *unassignRoleCommand) Init(args []string) error {
+	if len(args) < 1 {
+		return errors.New("no user specified")
+	}
+	if !names.IsValidUser(args[0]) {
+		return errors.NotValidf("user name %q", args[0])
+	}
+	c.User = args[0]
+	return cmd.CheckEmpty(args[1:])
+}
+
+// Run implements cmd.Command.
+func (c *unassignRoleCommand) Run(ctx *cmd.Context) error {
+	return errors.Trace(setModelUserRole(&c.ModelCommandBase, c.api, c.User, ""))
+}
+
+// setModelUserRole sets the role of the user on the command's model,
+// using the Roles facade on the controller.
+func setModelUserRole(c *modelcmd.ModelCommandBase, client RolesAPI, user, role string) error {
+	_, details, err := c.ModelDetails()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if client == nil {
+		root, err := c.NewControllerAPIRoot()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		client = roles.NewClient(root)
+	}
+	defer client.Close()
+
+	return block.ProcessBlockedError(client.SetModelUserRole(details.ModelUUID, user, role), block.BlockChange)
+}
@@ -0,0 +1,185 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package model_test
+
+import (
+	"github.com/juju/cmd/cmdtesting"
+	jtesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/juju/model"
+	"github.com/juju/juju/core/permission"
+	"github.com/juju/juju/jujuclient"
+	"github.com/juju/juju/jujuclient/jujuclienttesting"
+	"github.com/juju/juju/testing"
+)
+
+type rolesSuite struct {
+	testing.FakeJujuXDGDataHomeSuite
+	api   *fakeRolesAPI
+	store *jujuclient.MemStore
+}
+
+var _ = gc.Suite(&rolesSuite{})
+
+func (s *rolesSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuXDGDataHomeSuite.SetUpTest(c)
+	s.api = &fakeRolesAPI{}
+	s.store = jujuclienttesting.MinimalStore()
+	details := s.store.Models["arthur"].Models["king/sword"]
+	details.ModelUUID = testing.ModelTag.Id()
+	s.store.Models["arthur"].Models["king/sword"] = details
+}
+
+func (s *rolesSuite) TestAddRole(c *gc.C) {
+	_, err := cmdtesting.RunCommand(c, model.NewAddRoleCommandForTest(s.api, s.store), "app-team", "run-action", "Application.Expose")
+	c.Assert(err, jc.ErrorIsNil)
+	s.api.CheckCalls(c, []jtesting.StubCall{
+		{"AddRole", []interface{}{permission.Role{
+			Name:       "app-team",
+			Access:     permission.WriteAccess,
+			Operations: []string{"run-action", "Application.Expose"},
+		}}},
+		{"Close", nil},
+	})
+}
+
+func (s *rolesSuite) TestAddRoleAccess(c *gc.C) {
+	_, err := cmdtesting.RunCommand(c, model.NewAddRoleCommandForTest(s.api, s.store), "--access", "read", "readers", "run-action")
+	c.Assert(err, jc.ErrorIsNil)
+	s.api.CheckCalls(c, []jtesting.StubCall{
+		{"AddRole", []interface{}{permission.Role{
+			Name:       "readers",
+			Access:     permission.ReadAccess,
+			Operations: []string{"run-action"},
+		}}},
+		{"Close", nil},
+	})
+}
+
+func (s *rolesSuite) TestAddRoleInit(c *gc.C) {
+	for _, test := range []struct {
+		args []string
+		err  string
+	}{{
+		err: "no role name specified",
+	}, {
+		args: []string{"app-team"},
+		err:  "no operations specified",
+	}, {
+		args: []string{"App", "exec"},
+		err:  `role name "App" not valid`,
+	}, {
+		args: []string{"app-team", "destroy"},
+		err:  `operation "destroy" .* not valid`,
+	}, {
+		args: []string{"--access", "superuser", "app-team", "exec"},
+		err:  `role "app-team": "superuser" model access not valid`,
+	}} {
+		_, err := cmdtesting.RunCommand(c, model.NewAddRoleCommandForTest(s.api, s.store), test.args...)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+	s.api.CheckNoCalls(c)
+}
+
+func (s *rolesSuite) TestRemoveRole(c *gc.C) {
+	_, err := cmdtesting.RunCommand(c, model.NewRemoveRoleCommandForTest(s.api, s.store), "app-team")
+	c.Assert(err, jc.ErrorIsNil)
+	s.api.CheckCalls(c, []jtesting.StubCall{
+		{"RemoveRole", []interface{}{"app-team"}},
+		{"Close", nil},
+	})
+}
+
+func (s *rolesSuite) TestRoles(c *gc.C) {
+	s.api.roles = []permission.Role{
+		{Name: "app-team", Access: permission.WriteAccess, Operations: []string{"run-action", "config"}},
+		{Name: "ops", Access: permission.ReadAccess, Operations: []string{"exec"}},
+	}
+	ctx, err := cmdtesting.RunCommand(c, model.NewRolesCommandForTest(s.api, s.store))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, ""+
+		"Role      Access  Operations\n"+
+		"app-team  write   run-action, config\n"+
+		"ops       read    exec\n"+
+		"\n")
+
+	ctx, err = cmdtesting.RunCommand(c, model.NewRolesCommandForTest(s.api, s.store), "--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, ""+
+		"app-team:\n"+
+		"  access: write\n"+
+		"  operations:\n"+
+		"  - run-action\n"+
+		"  - config\n"+
+		"ops:\n"+
+		"  access: read\n"+
+		"  operations:\n"+
+		"  - exec\n")
+}
+
+func (s *rolesSuite) TestRolesNone(c *gc.C) {
+	ctx, err := cmdtesting.RunCommand(c, model.NewRolesCommandForTest(s.api, s.store))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, "")
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, "No roles to list\n")
+}
+
+func (s *rolesSuite) TestAssignRole(c *gc.C) {
+	_, err := cmdtesting.RunCommand(c, model.NewAssignRoleCommandForTest(s.api, s.store), "joe", "app-team")
+	c.Assert(err, jc.ErrorIsNil)
+	s.api.CheckCalls(c, []jtesting.StubCall{
+		{"SetModelUserRole", []interface{}{testing.ModelTag.Id(), "joe", "app-team"}},
+		{"Close", nil},
+	})
+}
+
+func (s *rolesSuite) TestAssignRoleInit(c *gc.C) {
+	_, err := cmdtesting.RunCommand(c, model.NewAssignRoleCommandForTest(s.api, s.store))
+	c.Assert(err, gc.ErrorMatches, "no user specified")
+	_, err = cmdtesting.RunCommand(c, model.NewAssignRoleCommandForTest(s.api, s.store), "joe")
+	c.Assert(err, gc.ErrorMatches, "no role specified")
+	_, err = cmdtesting.RunCommand(c, model.NewAssignRoleCommandForTest(s.api, s.store), "joe!", "app-team")
+	c.Assert(err, gc.ErrorMatches, `user name "joe!" not valid`)
+}
+
+func (s *rolesSuite) TestUnassignRole(c *gc.C) {
+	_, err := cmdtesting.RunCommand(c, model.NewUnassignRoleCommandForTest(s.api, s.store), "joe")
+	c.Assert(err, jc.ErrorIsNil)
+	s.api.CheckCalls(c, []jtesting.StubCall{
+		{"SetModelUserRole", []interface{}{testing.ModelTag.Id(), "joe", ""}},
+		{"Close", nil},
+	})
+}
+
+type fakeRolesAPI struct {
+	jtesting.Stub
+	roles []permission.Role
+}
+
+func (f *fakeRolesAPI) Close() error {
+	f.MethodCall(f, "Close")
+	return f.NextErr()
+}
+
+func (f *fakeRolesAPI) AddRole(role permission.Role) error {
+	f.MethodCall(f, "AddRole", role)
+	return f.NextErr()
+}
+
+func (f *fakeRolesAPI) ListRoles() ([]permission.Role, error) {
+	f.MethodCall(f, "ListRoles")
+	return f.roles, f.NextErr()
+}
+
+func (f *fakeRolesAPI) RemoveRole(name string) error {
+	f.MethodCall(f, "RemoveRole", name)
+	return f.NextErr()
+}
+
+func (f *fakeRolesAPI) SetModelUserRole(modelUUID, user, role string) error {
+	f.MethodCall(f, "SetModelUserRole", modelUUID, user, role)
+	return f.NextErr()
+}
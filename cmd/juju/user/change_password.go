@@ -14,6 +14,7 @@ import (
 	"github.com/juju/gnuflag"
 	"github.com/juju/juju/api"
 	"github.com/juju/juju/api/authentication"
+	"github.com/juju/juju/apiserver/params"
 	jujucmd "github.com/juju/juju/cmd"
 	"github.com/juju/juju/cmd/juju/block"
 	"github.com/juju/juju/cmd/modelcmd"
@@ -104,6 +105,7 @@ func (c *changePasswordCommand) Init(args []string) error {
 // password command uses.
 type ChangePasswordAPI interface {
 	SetPassword(username, password string) error
+	SetPasswordWithMFACode(username, password, code string) error
 	ResetPassword(username string) ([]byte, error)
 	BestAPIVersion() int
 	Close() error
@@ -207,7 +209,18 @@ func (c *changePasswordCommand) updateUserPassword(ctx *cmd.Context) error {
 		return errors.Trace(err)
 	}
 
-	if err := c.api.SetPassword(c.userTag.Id(), newPassword); err != nil {
+	err = c.api.SetPassword(c.userTag.Id(), newPassword)
+	mfaRequired := params.IsCodeMFARequired(err)
+	if mfaRequired {
+		// Users enrolled for multi-factor authentication must
+		// present a code to change their password.
+		code, codeErr := readMFACode(ctx)
+		if codeErr != nil {
+			return errors.Trace(codeErr)
+		}
+		err = c.api.SetPasswordWithMFACode(c.userTag.Id(), newPassword, code)
+	}
+	if err != nil {
 		return block.ProcessBlockedError(err, block.BlockChange)
 	}
 	if c.accountDetails == nil {
@@ -216,9 +229,13 @@ func (c *changePasswordCommand) updateUserPassword(ctx *cmd.Context) error {
 		if c.accountDetails.Password != "" {
 			// Log back in with macaroon authentication, so we can
 			// discard the password without having to log back in
-			// immediately.
-			if err := c.recordMacaroon(newPassword); err != nil {
-				return errors.Annotate(err, "recording macaroon")
+			// immediately. Logging in again would need another
+			// code, so users enrolled for multi-factor
+			// authentication are prompted by their next command.
+			if !mfaRequired {
+				if err := c.recordMacaroon(newPassword); err != nil {
+					return errors.Annotate(err, "recording macaroon")
+				}
 			}
 			// Wipe the password from disk. In the event of an
 			// error occurring after SetPassword and before the
@@ -283,6 +300,21 @@ func readAndConfirmPassword(ctx *cmd.Context) (string, error) {
 	return password, nil
 }
 
+// readMFACode prompts for a one-time code from the user's authenticator,
+// or one of their recovery codes.
+func readMFACode(ctx *cmd.Context) (string, error) {
+	fmt.Fprint(ctx.Stderr, "authentication code: ")
+	code, err := readLine(ctx.Stdin)
+	fmt.Fprint(ctx.Stderr, "\n")
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	if code == "" {
+		return "", errors.Errorf("you must enter an authentication code")
+	}
+	return code, nil
+}
+
 func readPassword(stdin io.Reader) (string, error) {
 	if f, ok := stdin.(*os.File); ok && terminal.IsTerminal(int(f.Fd())) {
 		password, err := terminal.ReadPassword(int(f.Fd()))
@@ -14,6 +14,7 @@ import (
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/api"
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/juju/user"
 	"github.com/juju/juju/juju"
 	"github.com/juju/juju/jujuclient"
@@ -113,6 +114,30 @@ func (s *ChangePasswordCommandSuite) TestChangePasswordFail(c *gc.C) {
 	s.assertAPICalls(c, "current-user", "sekrit")
 }
 
+func (s *ChangePasswordCommandSuite) TestChangePasswordMFACode(c *gc.C) {
+	s.mockAPI.SetErrors(&params.Error{Code: params.CodeMFARequired})
+	changePasswordCommand, _ := user.NewChangePasswordCommandForTest(nil, s.mockAPI, s.store)
+	ctx := cmdtesting.Context(c)
+	ctx.Stdin = strings.NewReader("sekrit\nsekrit\n123456\n")
+	err := cmdtesting.InitCommand(changePasswordCommand, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = changePasswordCommand.Run(ctx)
+	c.Assert(err, jc.ErrorIsNil)
+	s.mockAPI.CheckCalls(c, []testing.StubCall{
+		{"SetPassword", []interface{}{"current-user", "sekrit"}},
+		{"SetPasswordWithMFACode", []interface{}{"current-user", "sekrit", "123456"}},
+	})
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, `
+new password: 
+type new password again: 
+authentication code: 
+Your password has been changed.
+`[1:])
+	// The stored password is discarded without logging in again,
+	// which would need another code.
+	s.assertStorePassword(c, "current-user", "", "")
+}
+
 func (s *ChangePasswordCommandSuite) TestChangeOthersPassword(c *gc.C) {
 	// The checks for user existence and admin rights are tested
 	// at the apiserver level.
@@ -195,6 +220,11 @@ func (m *mockChangePasswordAPI) SetPassword(username, password string) error {
 	return m.NextErr()
 }
 
+func (m *mockChangePasswordAPI) SetPasswordWithMFACode(username, password, code string) error {
+	m.MethodCall(m, "SetPasswordWithMFACode", username, password, code)
+	return m.NextErr()
+}
+
 func (m *mockChangePasswordAPI) ResetPassword(username string) ([]byte, error) {
 	m.MethodCall(m, "ResetPassword", username)
 	return m.key, m.NextErr()
@@ -124,3 +124,39 @@ func NewWhoAmICommandForTest(store jujuclient.ClientStore) cmd.Command {
 	c := &whoAmICommand{store: store}
 	return c
 }
+
+func NewAddTokenCommandForTest(api APITokensAPI, store jujuclient.ClientStore, clock clock.Clock) cmd.Command {
+	c := &addTokenCommand{api: api, clock: clock}
+	c.SetClientStore(store)
+	return modelcmd.Wrap(c)
+}
+
+func NewTokensCommandForTest(api APITokensAPI, store jujuclient.ClientStore, clock clock.Clock) cmd.Command {
+	c := &tokensCommand{api: api, clock: clock}
+	c.SetClientStore(store)
+	return modelcmd.WrapController(c)
+}
+
+func NewRevokeTokenCommandForTest(api APITokensAPI, store jujuclient.ClientStore) cmd.Command {
+	c := &revokeTokenCommand{api: api}
+	c.SetClientStore(store)
+	return modelcmd.WrapController(c)
+}
+
+func NewEnableMFACommandForTest(api MFAAPI, store jujuclient.ClientStore) cmd.Command {
+	c := &enableMFACommand{mfaCommandBase{api: api}}
+	c.SetClientStore(store)
+	return modelcmd.WrapController(c)
+}
+
+func NewDisableMFACommandForTest(api MFAAPI, store jujuclient.ClientStore) cmd.Command {
+	c := &disableMFACommand{mfaCommandBase{api: api}}
+	c.SetClientStore(store)
+	return modelcmd.WrapController(c)
+}
+
+func NewResetMFACommandForTest(api MFAAPI, store jujuclient.ClientStore) cmd.Command {
+	c := &resetMFACommand{mfaCommandBase: mfaCommandBase{api: api}}
+	c.SetClientStore(store)
+	return modelcmd.WrapController(c)
+}
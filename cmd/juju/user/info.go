@@ -4,6 +4,8 @@
 package user
 
 import (
+	"time"
+
 	"github.com/juju/clock"
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
@@ -25,6 +27,11 @@ var helpDetails = `
 By default, the YAML format is used and the user name is the current
 user.
 
+Users who have failed to log in too many times in a row are shown as
+locked out until the time given by "locked-until". A controller
+administrator may unlock them with enable-user, or by resetting their
+password.
+
 
 Examples:
     juju show-user
@@ -78,6 +85,11 @@ type UserInfo struct {
 	DateCreated    string `yaml:"date-created,omitempty" json:"date-created,omitempty"`
 	LastConnection string `yaml:"last-connection,omitempty" json:"last-connection,omitempty"`
 	Disabled       bool   `yaml:"disabled,omitempty" json:"disabled,omitempty"`
+	MFAEnabled     bool   `yaml:"mfa-enabled,omitempty" json:"mfa-enabled,omitempty"`
+	RecoveryCodes  int    `yaml:"mfa-recovery-codes-remaining,omitempty" json:"mfa-recovery-codes-remaining,omitempty"`
+	FailedLogins   int    `yaml:"failed-logins,omitempty" json:"failed-logins,omitempty"`
+	LockedUntil    string `yaml:"locked-until,omitempty" json:"locked-until,omitempty"`
+	PasswordExpiry string `yaml:"password-expires,omitempty" json:"password-expires,omitempty"`
 }
 
 // Info implements Command.Info.
@@ -151,6 +163,15 @@ func (c *infoCommandBase) apiUsersToUserInfoSlice(users []params.UserInfo) []Use
 		// TODO(wallyworld) record login information about external users.
 		if names.NewUserTag(info.Username).IsLocal() {
 			outInfo.LastConnection = common.LastConnection(info.LastConnection, now, c.exactTime)
+			outInfo.MFAEnabled = info.MFAEnabled
+			outInfo.RecoveryCodes = info.MFARecoveryCodes
+			outInfo.FailedLogins = info.FailedLogins
+			if info.LockedUntil != nil {
+				outInfo.LockedUntil = c.formatTime(*info.LockedUntil)
+			}
+			if info.PasswordExpires != nil {
+				outInfo.PasswordExpiry = c.formatTime(*info.PasswordExpires)
+			}
 			if c.exactTime {
 				outInfo.DateCreated = info.DateCreated.String()
 			} else {
@@ -162,3 +183,12 @@ func (c *infoCommandBase) apiUsersToUserInfoSlice(users []params.UserInfo) []Use
 
 	return output
 }
+
+// formatTime formats a time, such as the end of a user's lockout, that
+// may be in the future.
+func (c *infoCommandBase) formatTime(t time.Time) string {
+	if c.exactTime {
+		return t.String()
+	}
+	return t.Local().Format("2006-01-02 15:04")
+}
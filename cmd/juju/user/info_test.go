@@ -32,6 +32,7 @@ var (
 	// Mock out timestamps
 	dateCreated    = time.Unix(352138205, 0).UTC()
 	lastConnection = time.Unix(1388534400, 0).UTC()
+	lockedUntil    = time.Unix(1388538000, 0).UTC()
 )
 
 func (s *UserInfoCommandSuite) NewShowUserCommand() cmd.Command {
@@ -58,6 +59,16 @@ func (*fakeUserInfoAPI) UserInfo(usernames []string, all usermanager.IncludeDisa
 		info.Username = "foobar"
 		info.DisplayName = "Foo Bar"
 		info.Access = "login"
+	case "secure":
+		info.Username = "secure"
+		info.Access = "login"
+		info.MFAEnabled = true
+		info.MFARecoveryCodes = 8
+	case "locked":
+		info.Username = "locked"
+		info.Access = "login"
+		info.FailedLogins = 6
+		info.LockedUntil = &lockedUntil
 	case "fred@external":
 		info.Username = "fred@external"
 		info.DisplayName = "Fred External"
@@ -99,6 +110,30 @@ last-connection: "2014-01-01"
 `)
 }
 
+func (s *UserInfoCommandSuite) TestUserInfoMFA(c *gc.C) {
+	context, err := cmdtesting.RunCommand(c, s.NewShowUserCommand(), "secure")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(context), gc.Equals, `user-name: secure
+access: login
+date-created: "1981-02-27"
+last-connection: "2014-01-01"
+mfa-enabled: true
+mfa-recovery-codes-remaining: 8
+`)
+}
+
+func (s *UserInfoCommandSuite) TestUserInfoLockedOut(c *gc.C) {
+	context, err := cmdtesting.RunCommand(c, s.NewShowUserCommand(), "locked", "--exact-time")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(context), gc.Equals, `user-name: locked
+access: login
+date-created: 1981-02-27 16:10:05 +0000 UTC
+last-connection: 2014-01-01 00:00:00 +0000 UTC
+failed-logins: 6
+locked-until: 2014-01-01 01:00:00 +0000 UTC
+`)
+}
+
 func (s *UserInfoCommandSuite) TestUserInfoExternalUser(c *gc.C) {
 	context, err := cmdtesting.RunCommand(c, s.NewShowUserCommand(), "fred@external")
 	c.Assert(err, jc.ErrorIsNil)
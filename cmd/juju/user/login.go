@@ -5,6 +5,7 @@ package user
 
 import (
 	"bytes"
+	"context"
 	"crypto/x509"
 	"encoding/pem"
 	"fmt"
@@ -56,6 +57,12 @@ After login, a token ("macaroon") will become active. It has an expiration
 time of 24 hours. Upon expiration, no further Juju commands can be issued
 and the user will be prompted to log in again.
 
+If the controller is configured to use an OpenID Connect provider (see
+the "oidc-issuer-url" controller configuration key), the juju login
+command will ask the user to visit a web page, possibly on another device,
+and enter a code there to approve the login. The resulting ID token is
+kept with the account details and used until it expires.
+
 Aliases
 -------
 
@@ -114,6 +121,10 @@ type loginCommand struct {
 	// onRunError is executed if non-nil if there is an error at the end
 	// of the Run method.
 	onRunError func()
+
+	// idToken holds the ID token obtained if the controller asked
+	// for an OpenID Connect login.
+	idToken string
 }
 
 // Info implements Command.Info.
@@ -284,6 +295,7 @@ func (c *loginCommand) existingControllerLogin(ctx *cmd.Context, store jujuclien
 		if err != nil {
 			return nil, errors.Trace(err)
 		}
+		args.DialOpts.OIDCLogin = c.captureIDToken(args.DialOpts.OIDCLogin)
 		return newAPIConnection(args)
 	}
 	return c.login(ctx, currentAccountDetails, dial)
@@ -332,6 +344,9 @@ func (c *loginCommand) publicControllerLogin(
 	dialOpts := api.DefaultDialOpts()
 	dialOpts.BakeryClient = bclient
 	dialOpts.VerifyCA = c.promptUserToTrustCA(ctx, ctrlDetails)
+	dialOpts.OIDCLogin = c.captureIDToken((&authentication.OIDCDeviceLogin{
+		Writer: ctx.Stderr,
+	}).Login)
 
 	// Keep track of existing interactors as the dial callback will create
 	// new ones each time it gets invoked.
@@ -349,22 +364,31 @@ func (c *loginCommand) publicControllerLogin(
 			tag = names.NewUserTag(d.User)
 		}
 		dialOpts.BakeryClient.InteractionMethods = []httpbakery.Interactor{
-			authentication.NewInteractor(d.User, func(string) (string, error) {
+			authentication.NewMFAInteractor(d.User, func(string) (string, error) {
 				// The visitor from the authentication package
 				// passes the username to the password getter
 				// func. As other password getters may rely on
 				// this we just provide a wrapper that calls
 				// pollster with the correct label.
 				return c.pollster.EnterPassword("password")
+			}, func(string) (string, error) {
+				// Users enrolled for multi-factor authentication
+				// are asked for a code after their password.
+				return c.pollster.Enter("authentication code")
 			})}
 		// Add in any default interactors from the base client.
 		for _, i := range existing {
 			dialOpts.BakeryClient.AddInteractor(i)
 		}
 
+		var idToken string
+		if tag == nil {
+			idToken = d.IDToken
+		}
 		return apiOpen(&c.CommandBase, &api.Info{
 			Tag:      tag,
 			Password: d.Password,
+			IDToken:  idToken,
 			Addrs:    []string{host},
 		}, dialOpts)
 	}
@@ -414,13 +438,22 @@ Run "juju logout" first before attempting to log in as a different user.`,
 		if err == nil {
 			return conn, accountDetails, nil
 		}
-		if !errors.IsUnauthorized(err) {
+		// Users enrolled for multi-factor authentication cannot
+		// log in with a stored password alone, so fall back to
+		// an interactive login, which prompts for a code.
+		if !errors.IsUnauthorized(err) && !params.IsCodeMFARequired(err) {
 			return nil, nil, errors.Trace(err)
 		}
 	}
 	if c.username == "" {
-		// No username specified, so try external-user login first.
-		conn, err := dial(&jujuclient.AccountDetails{})
+		// No username specified, so try external-user login first,
+		// reusing any ID token we already have.
+		external := &jujuclient.AccountDetails{}
+		if accountDetails != nil {
+			external.IDToken = accountDetails.IDToken
+			c.idToken = accountDetails.IDToken
+		}
+		conn, err := dial(external)
 		if err == nil {
 			user, ok := conn.AuthTag().(names.UserTag)
 			if !ok {
@@ -428,7 +461,8 @@ Run "juju logout" first before attempting to log in as a different user.`,
 				return nil, nil, errors.Errorf("logged in as %v, not a user", conn.AuthTag())
 			}
 			return conn, &jujuclient.AccountDetails{
-				User: user.Id(),
+				User:    user.Id(),
+				IDToken: c.idToken,
 			}, nil
 		}
 		if !params.IsCodeNoCreds(err) {
@@ -461,6 +495,22 @@ Run "juju logout" first before attempting to log in as a different user.`,
 	return conn, accountDetails, errors.Trace(err)
 }
 
+// captureIDToken returns an OIDC login function that records the ID
+// token obtained by login, so that it can be saved with the account
+// details.
+func (c *loginCommand) captureIDToken(login api.OIDCLoginFunc) api.OIDCLoginFunc {
+	if login == nil {
+		return nil
+	}
+	return func(ctx context.Context, info params.OIDCLoginInfo) (string, error) {
+		idToken, err := login(ctx, info)
+		if err == nil {
+			c.idToken = idToken
+		}
+		return idToken, err
+	}
+}
+
 const badCred = "invalid entity name or password"
 
 const noModelsMessage = `
@@ -0,0 +1,269 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package user
+
+import (
+	"fmt"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/cmd/modelcmd"
+)
+
+var usageEnableMFASummary = `
+Enrols the current user for multi-factor authentication.`[1:]
+
+var usageEnableMFADetails = `
+Once enrolled, logging in with a password, and changing the password,
+also needs a one-time code from an authenticator app, such as those
+implementing RFC 6238 (TOTP).
+
+The command shows a secret, and an otpauth:// URI that can be turned
+into a QR code, to add to the authenticator. Enrolment completes once a
+code from the authenticator is entered.
+
+A set of single-use recovery codes is then printed. Each can be used
+once instead of a code from the authenticator, for example if the
+authenticator is lost. Store them somewhere safe: they cannot be shown
+again. The number remaining is shown by ` + "`juju show-user`" + `.
+
+Only users local to the controller can enrol.
+
+Examples:
+    juju enable-mfa
+
+See also:
+    disable-mfa
+    reset-mfa
+    show-user`[1:]
+
+var usageDisableMFASummary = `
+Disables multi-factor authentication for the current user.`[1:]
+
+var usageDisableMFADetails = `
+A code from the user's authenticator, or a recovery code, is needed to
+disable multi-factor authentication.
+
+Examples:
+    juju disable-mfa
+
+See also:
+    enable-mfa
+    reset-mfa`[1:]
+
+var usageResetMFASummary = `
+Resets multi-factor authentication for a user.`[1:]
+
+var usageResetMFADetails = `
+Controller administrators can remove another user's multi-factor
+authentication enrolment, for example when the user has lost both their
+authenticator and their recovery codes. The user can then log in with
+their password alone, and enrol again.
+
+Examples:
+    juju reset-mfa bob
+
+See also:
+    enable-mfa
+    disable-mfa`[1:]
+
+// MFAAPI defines the usermanager API methods used by the multi-factor
+// authentication commands.
+type MFAAPI interface {
+	BeginMFAEnrolment() (secret, uri string, _ error)
+	ConfirmMFAEnrolment(code string) ([]string, error)
+	DisableMFA(code string) error
+	ResetMFA(username string) error
+	Close() error
+}
+
+// mfaCommandBase holds the API shared by the multi-factor
+// authentication commands.
+type mfaCommandBase struct {
+	modelcmd.ControllerCommandBase
+	api MFAAPI
+}
+
+func (c *mfaCommandBase) getMFAAPI() (MFAAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	return c.NewUserManagerAPIClient()
+}
+
+// NewEnableMFACommand returns a new enable-mfa command.
+func NewEnableMFACommand() cmd.Command {
+	return modelcmd.WrapController(&enableMFACommand{})
+}
+
+// enableMFACommand enrols the current user for multi-factor
+// authentication.
+type enableMFACommand struct {
+	mfaCommandBase
+}
+
+// Info implements cmd.Command.
+func (c *enableMFACommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "enable-mfa",
+		Purpose: usageEnableMFASummary,
+		Doc:     usageEnableMFADetails,
+	})
+}
+
+// Init implements cmd.Command.
+func (c *enableMFACommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+// Run implements cmd.Command.
+func (c *enableMFACommand) Run(ctx *cmd.Context) error {
+	client, err := c.getMFAAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+
+	secret, uri, err := client.BeginMFAEnrolment()
+	if err != nil {
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+	ctx.Infof("Add this secret to your authenticator app:\n    %s", secret)
+	ctx.Infof("or scan a QR code made from this URI:\n    %s", uri)
+	code, err := readMFACode(ctx)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	codes, err := client.ConfirmMFAEnrolment(code)
+	if err != nil {
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+	ctx.Infof("Multi-factor authentication is enabled. Each of these recovery codes\n" +
+		"can be used once instead of an authentication code. Store them somewhere\n" +
+		"safe: they cannot be shown again.")
+	for _, code := range codes {
+		fmt.Fprintln(ctx.Stdout, code)
+	}
+	return errors.Trace(c.forgetPassword())
+}
+
+// forgetPassword removes any password stored for the current account,
+// which can no longer be used to log in without a code. The next
+// command prompts for a password and code, and logs in with a macaroon.
+func (c *enableMFACommand) forgetPassword() error {
+	controllerName, err := c.ControllerName()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	store := c.ClientStore()
+	accountDetails, err := store.AccountDetails(controllerName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if accountDetails.Password == "" {
+		return nil
+	}
+	accountDetails.Password = ""
+	if err := store.UpdateAccount(controllerName, *accountDetails); err != nil {
+		return errors.Annotate(err, "failed to update client credentials")
+	}
+	return nil
+}
+
+// NewDisableMFACommand returns a new disable-mfa command.
+func NewDisableMFACommand() cmd.Command {
+	return modelcmd.WrapController(&disableMFACommand{})
+}
+
+// disableMFACommand removes the current user's multi-factor
+// authentication enrolment.
+type disableMFACommand struct {
+	mfaCommandBase
+}
+
+// Info implements cmd.Command.
+func (c *disableMFACommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "disable-mfa",
+		Purpose: usageDisableMFASummary,
+		Doc:     usageDisableMFADetails,
+	})
+}
+
+// Init implements cmd.Command.
+func (c *disableMFACommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+// Run implements cmd.Command.
+func (c *disableMFACommand) Run(ctx *cmd.Context) error {
+	client, err := c.getMFAAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+
+	code, err := readMFACode(ctx)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := client.DisableMFA(code); err != nil {
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+	ctx.Infof("Multi-factor authentication is disabled.")
+	return nil
+}
+
+// NewResetMFACommand returns a new reset-mfa command.
+func NewResetMFACommand() cmd.Command {
+	return modelcmd.WrapController(&resetMFACommand{})
+}
+
+// resetMFACommand removes another user's multi-factor authentication
+// enrolment.
+type resetMFACommand struct {
+	mfaCommandBase
+	User string
+}
+
+// Info implements cmd.Command.
+func (c *resetMFACommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "reset-mfa",
+		Args:    "<user name>",
+		Purpose: usageResetMFASummary,
+		Doc:     usageResetMFADetails,
+	})
+}
+
+// Init implements cmd.Command.
+func (c *resetMFACommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no username supplied")
+	}
+	c.User = args[0]
+	if !names.IsValidUserName(c.User) {
+		return errors.NotValidf("user name %q", c.User)
+	}
+	return cmd.CheckEmpty(args[1:])
+}
+
+// Run implements cmd.Command.
+func (c *resetMFACommand) Run(ctx *cmd.Context) error {
+	client, err := c.getMFAAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+
+	if err := client.ResetMFA(c.User); err != nil {
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+	ctx.Infof("Multi-factor authentication for %q has been reset.", c.User)
+	return nil
+}
@@ -0,0 +1,141 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package user_test
+
+import (
+	"strings"
+
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
+	jtesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/juju/user"
+)
+
+type MFACommandSuite struct {
+	BaseSuite
+	api *fakeMFAAPI
+}
+
+var _ = gc.Suite(&MFACommandSuite{})
+
+func (s *MFACommandSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.api = &fakeMFAAPI{}
+}
+
+func (s *MFACommandSuite) TestEnableMFA(c *gc.C) {
+	ctx := cmdtesting.Context(c)
+	ctx.Stdin = strings.NewReader("123456\n")
+	command := user.NewEnableMFACommandForTest(s.api, s.store)
+	c.Assert(cmdtesting.InitCommand(command, nil), jc.ErrorIsNil)
+	err := command.Run(ctx)
+	c.Assert(err, jc.ErrorIsNil)
+	s.api.CheckCalls(c, []jtesting.StubCall{
+		{"BeginMFAEnrolment", nil},
+		{"ConfirmMFAEnrolment", []interface{}{"123456"}},
+		{"Close", nil},
+	})
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, "0a1b2-3c4d5\n6e7f8-9a0b1\n")
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, `
+Add this secret to your authenticator app:
+    SECRET
+or scan a QR code made from this URI:
+    otpauth://totp/Juju:current-user?secret=SECRET
+authentication code: 
+Multi-factor authentication is enabled. Each of these recovery codes
+can be used once instead of an authentication code. Store them somewhere
+safe: they cannot be shown again.
+`[1:])
+	// The stored password can no longer be used on its own.
+	s.assertStorePassword(c, "current-user", "", "")
+}
+
+func (s *MFACommandSuite) TestEnableMFABadCode(c *gc.C) {
+	s.api.SetErrors(nil, errors.Unauthorizedf("invalid multi-factor authentication code"))
+	ctx := cmdtesting.Context(c)
+	ctx.Stdin = strings.NewReader("000000\n")
+	command := user.NewEnableMFACommandForTest(s.api, s.store)
+	c.Assert(cmdtesting.InitCommand(command, nil), jc.ErrorIsNil)
+	err := command.Run(ctx)
+	c.Assert(err, gc.ErrorMatches, "invalid multi-factor authentication code")
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, "")
+	s.assertStorePassword(c, "current-user", "old-password", "")
+}
+
+func (s *MFACommandSuite) TestDisableMFA(c *gc.C) {
+	ctx := cmdtesting.Context(c)
+	ctx.Stdin = strings.NewReader("0a1b2-3c4d5\n")
+	command := user.NewDisableMFACommandForTest(s.api, s.store)
+	c.Assert(cmdtesting.InitCommand(command, nil), jc.ErrorIsNil)
+	err := command.Run(ctx)
+	c.Assert(err, jc.ErrorIsNil)
+	s.api.CheckCalls(c, []jtesting.StubCall{
+		{"DisableMFA", []interface{}{"0a1b2-3c4d5"}},
+		{"Close", nil},
+	})
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, "authentication code: \nMulti-factor authentication is disabled.\n")
+}
+
+func (s *MFACommandSuite) TestDisableMFANoCode(c *gc.C) {
+	ctx := cmdtesting.Context(c)
+	ctx.Stdin = strings.NewReader("\n")
+	command := user.NewDisableMFACommandForTest(s.api, s.store)
+	c.Assert(cmdtesting.InitCommand(command, nil), jc.ErrorIsNil)
+	err := command.Run(ctx)
+	c.Assert(err, gc.ErrorMatches, "you must enter an authentication code")
+}
+
+func (s *MFACommandSuite) TestResetMFA(c *gc.C) {
+	ctx, err := cmdtesting.RunCommand(c, user.NewResetMFACommandForTest(s.api, s.store), "bob")
+	c.Assert(err, jc.ErrorIsNil)
+	s.api.CheckCalls(c, []jtesting.StubCall{
+		{"ResetMFA", []interface{}{"bob"}},
+		{"Close", nil},
+	})
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, "Multi-factor authentication for \"bob\" has been reset.\n")
+}
+
+func (s *MFACommandSuite) TestResetMFAInit(c *gc.C) {
+	_, err := cmdtesting.RunCommand(c, user.NewResetMFACommandForTest(s.api, s.store))
+	c.Assert(err, gc.ErrorMatches, "no username supplied")
+	_, err = cmdtesting.RunCommand(c, user.NewResetMFACommandForTest(s.api, s.store), "bob!")
+	c.Assert(err, gc.ErrorMatches, `user name "bob!" not valid`)
+	_, err = cmdtesting.RunCommand(c, user.NewResetMFACommandForTest(s.api, s.store), "bob", "alice")
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["alice"\]`)
+}
+
+type fakeMFAAPI struct {
+	jtesting.Stub
+}
+
+func (f *fakeMFAAPI) BeginMFAEnrolment() (string, string, error) {
+	f.MethodCall(f, "BeginMFAEnrolment")
+	return "SECRET", "otpauth://totp/Juju:current-user?secret=SECRET", f.NextErr()
+}
+
+func (f *fakeMFAAPI) ConfirmMFAEnrolment(code string) ([]string, error) {
+	f.MethodCall(f, "ConfirmMFAEnrolment", code)
+	if err := f.NextErr(); err != nil {
+		return nil, err
+	}
+	return []string{"0a1b2-3c4d5", "6e7f8-9a0b1"}, nil
+}
+
+func (f *fakeMFAAPI) DisableMFA(code string) error {
+	f.MethodCall(f, "DisableMFA", code)
+	return f.NextErr()
+}
+
+func (f *fakeMFAAPI) ResetMFA(username string) error {
+	f.MethodCall(f, "ResetMFA", username)
+	return f.NextErr()
+}
+
+func (f *fakeMFAAPI) Close() error {
+	f.MethodCall(f, "Close")
+	return nil
+}
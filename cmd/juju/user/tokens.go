@@ -0,0 +1,380 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package user
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/juju/clock"
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"github.com/juju/names/v4"
+
+	"github.com/juju/juju/api/apitokens"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/cmd/output"
+	"github.com/juju/juju/core/permission"
+	"github.com/juju/juju/juju/osenv"
+)
+
+var usageAddTokenSummary = `
+Mints an API token for logging in to a model without a password.`[1:]
+
+var usageAddTokenDetails = `
+API tokens allow automation, such as CI jobs, to log in to a single
+model as a user without a password or an interactive login. A token
+grants at most the access given by --role, which may be read, write or
+admin; the user's own access to the model may limit it further. The
+token stops working when it expires or is revoked.
+
+The token is owned by the current user, or by the user given with
+--user, who must have access to the model. Only model admins can mint
+tokens.
+
+The secret of the token is printed once, and cannot be shown again.
+Clients log in with it by setting the ` + osenv.JujuAPITokenEnvKey + ` environment
+variable, or the "token" field of the controller's account in
+accounts.yaml. Logins made with the token are recorded in the audit log
+with the token's ID.
+
+Expiry times are given in days (30d) or as a duration (12h, 90m).
+
+Examples:
+Mint a read-only token for the model "ci" that expires in 30 days:
+
+    juju add-token -m ci --role read --expires 30d
+
+Mint a token for the user "deployer":
+
+    juju add-token -m ci --user deployer --role write --expires 12h
+
+See also:
+    tokens
+    revoke-token`[1:]
+
+var usageTokensSummary = `
+Lists the API tokens you can manage.`[1:]
+
+var usageTokensDetails = `
+Controller superusers see all tokens; other users see the tokens they
+own and those for the models they administer. The secrets of tokens
+are never shown.
+
+Examples:
+    juju tokens
+    juju tokens --format yaml
+
+See also:
+    add-token
+    revoke-token`[1:]
+
+var usageRevokeTokenSummary = `
+Revokes an API token.`[1:]
+
+var usageRevokeTokenDetails = `
+A revoked token can no longer be used to log in. Connections already
+made with the token are not affected.
+
+Examples:
+    juju revoke-token 4b1d9e2c70a1f3d5
+
+See also:
+    add-token
+    tokens`[1:]
+
+// defaultTokenExpiry is how long API tokens last unless --expires is
+// given.
+const defaultTokenExpiry = "30d"
+
+// APITokensAPI defines the API functions used by the token commands.
+type APITokensAPI interface {
+	Close() error
+	AddToken(modelUUID, user string, access permission.Access, expires time.Time) (apitokens.Token, string, error)
+	ListTokens() ([]apitokens.Token, error)
+	RevokeToken(id string) error
+}
+
+// NewAddTokenCommand returns a new add-token command.
+func NewAddTokenCommand() cmd.Command {
+	return modelcmd.Wrap(&addTokenCommand{clock: clock.WallClock})
+}
+
+// addTokenCommand mints an API token for a model.
+type addTokenCommand struct {
+	modelcmd.ModelCommandBase
+	api   APITokensAPI
+	clock clock.Clock
+
+	User    string
+	Role    string
+	Expires string
+
+	expiry time.Duration
+}
+
+// Info implements cmd.Command.
+func (c *addTokenCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "add-token",
+		Purpose: usageAddTokenSummary,
+		Doc:     usageAddTokenDetails,
+	})
+}
+
+// SetFlags implements cmd.Command.
+func (c *addTokenCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ModelCommandBase.SetFlags(f)
+	f.StringVar(&c.User, "user", "", "The user the token logs in as (defaults to the current user)")
+	f.StringVar(&c.Role, "role", string(permission.ReadAccess), "The access to the model the token grants: read, write or admin")
+	f.StringVar(&c.Expires, "expires", defaultTokenExpiry, "How long until the token expires")
+}
+
+// Init implements cmd.Command.
+func (c *addTokenCommand) Init(args []string) error {
+	if c.User != "" && !names.IsValidUser(c.User) {
+		return errors.NotValidf("user name %q", c.User)
+	}
+	if err := permission.ValidateModelAccess(permission.Access(c.Role)); err != nil {
+		return errors.Errorf("--role %q not valid: expected read, write or admin", c.Role)
+	}
+	expiry, err := parseTokenExpiry(c.Expires)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	c.expiry = expiry
+	return cmd.CheckEmpty(args)
+}
+
+// parseTokenExpiry parses a token lifetime, given either in days, such
+// as "30d", or as a duration, such as "12h".
+func parseTokenExpiry(value string) (time.Duration, error) {
+	var expiry time.Duration
+	if days := strings.TrimSuffix(value, "d"); days != value {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, errors.NotValidf("--expires %q", value)
+		}
+		expiry = time.Duration(n) * 24 * time.Hour
+	} else {
+		var err error
+		if expiry, err = time.ParseDuration(value); err != nil {
+			return 0, errors.NotValidf("--expires %q", value)
+		}
+	}
+	if expiry <= 0 {
+		return 0, errors.Errorf("--expires %q must be positive", value)
+	}
+	return expiry, nil
+}
+
+// Run implements cmd.Command.
+func (c *addTokenCommand) Run(ctx *cmd.Context) error {
+	modelName, details, err := c.ModelDetails()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	client := c.api
+	if client == nil {
+		root, err := c.NewControllerAPIRoot()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		client = apitokens.NewClient(root)
+	}
+	defer client.Close()
+
+	expires := c.clock.Now().Add(c.expiry)
+	token, secret, err := client.AddToken(details.ModelUUID, c.User, permission.Access(c.Role), expires)
+	if err != nil {
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+	ctx.Infof("Added API token %s for %q with %s access to model %q, expiring %s.",
+		token.ID, token.Owner, token.Access, modelName, token.Expires.Format(time.RFC3339))
+	ctx.Infof("The token cannot be shown again. Log in with it by setting %s.", osenv.JujuAPITokenEnvKey)
+	fmt.Fprintln(ctx.Stdout, secret)
+	return nil
+}
+
+// NewTokensCommand returns a new tokens command.
+func NewTokensCommand() cmd.Command {
+	return modelcmd.WrapController(&tokensCommand{clock: clock.WallClock})
+}
+
+// tokensCommand lists the API tokens the user can manage.
+type tokensCommand struct {
+	modelcmd.ControllerCommandBase
+	api   APITokensAPI
+	clock clock.Clock
+	out   cmd.Output
+}
+
+// TokenInfo holds the details of an API token for output.
+type TokenInfo struct {
+	ID        string    `yaml:"id" json:"id"`
+	Owner     string    `yaml:"owner" json:"owner"`
+	Model     string    `yaml:"model" json:"model"`
+	Access    string    `yaml:"access" json:"access"`
+	CreatedBy string    `yaml:"created-by" json:"created-by"`
+	Created   time.Time `yaml:"created" json:"created"`
+	Expires   time.Time `yaml:"expires" json:"expires"`
+	Expired   bool      `yaml:"expired,omitempty" json:"expired,omitempty"`
+}
+
+// Info implements cmd.Command.
+func (c *tokensCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "tokens",
+		Purpose: usageTokensSummary,
+		Doc:     usageTokensDetails,
+		Aliases: []string{"list-tokens"},
+	})
+}
+
+// SetFlags implements cmd.Command.
+func (c *tokensCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ControllerCommandBase.SetFlags(f)
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatTokensTabular,
+	})
+}
+
+// Init implements cmd.Command.
+func (c *tokensCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+// Run implements cmd.Command.
+func (c *tokensCommand) Run(ctx *cmd.Context) error {
+	client := c.api
+	if client == nil {
+		root, err := c.NewAPIRoot()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		client = apitokens.NewClient(root)
+	}
+	defer client.Close()
+
+	tokens, err := client.ListTokens()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(tokens) == 0 && c.out.Name() == "tabular" {
+		ctx.Infof("No tokens to list")
+		return nil
+	}
+	modelNames := c.modelNames()
+	now := c.clock.Now()
+	formatted := make([]TokenInfo, len(tokens))
+	for i, token := range tokens {
+		model := token.ModelUUID
+		if name, ok := modelNames[model]; ok {
+			model = name
+		}
+		formatted[i] = TokenInfo{
+			ID:        token.ID,
+			Owner:     token.Owner,
+			Model:     model,
+			Access:    string(token.Access),
+			CreatedBy: token.CreatedBy,
+			Created:   token.Created,
+			Expires:   token.Expires,
+			Expired:   !now.Before(token.Expires),
+		}
+	}
+	return errors.Trace(c.out.Write(ctx, formatted))
+}
+
+// modelNames returns the names of the models known to the client,
+// keyed by model UUID.
+func (c *tokensCommand) modelNames() map[string]string {
+	result := make(map[string]string)
+	controllerName, err := c.ControllerName()
+	if err != nil {
+		return result
+	}
+	models, err := c.ClientStore().AllModels(controllerName)
+	if err != nil {
+		return result
+	}
+	for name, details := range models {
+		result[details.ModelUUID] = name
+	}
+	return result
+}
+
+func formatTokensTabular(writer io.Writer, value interface{}) error {
+	tokens, ok := value.([]TokenInfo)
+	if !ok {
+		return errors.Errorf("expected value of type %T, got %T", tokens, value)
+	}
+
+	tw := output.TabWriter(writer)
+	w := output.Wrapper{tw}
+	w.Println("ID", "Owner", "Model", "Access", "Expires")
+	for _, token := range tokens {
+		expires := token.Expires.Format(time.RFC3339)
+		if token.Expired {
+			expires += " (expired)"
+		}
+		w.Println(token.ID, token.Owner, token.Model, token.Access, expires)
+	}
+	return tw.Flush()
+}
+
+// NewRevokeTokenCommand returns a new revoke-token command.
+func NewRevokeTokenCommand() cmd.Command {
+	return modelcmd.WrapController(&revokeTokenCommand{})
+}
+
+// revokeTokenCommand revokes an API token.
+type revokeTokenCommand struct {
+	modelcmd.ControllerCommandBase
+	api APITokensAPI
+
+	ID string
+}
+
+// Info implements cmd.Command.
+func (c *revokeTokenCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "revoke-token",
+		Args:    "<token id>",
+		Purpose: usageRevokeTokenSummary,
+		Doc:     usageRevokeTokenDetails,
+	})
+}
+
+// Init implements cmd.Command.
+func (c *revokeTokenCommand) Init(args []string) error {
+	if len(args) < 1 {
+		return errors.New("no token ID specified")
+	}
+	c.ID = args[0]
+	return cmd.CheckEmpty(args[1:])
+}
+
+// Run implements cmd.Command.
+func (c *revokeTokenCommand) Run(ctx *cmd.Context) error {
+	client := c.api
+	if client == nil {
+		root, err := c.NewAPIRoot()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		client = apitokens.NewClient(root)
+	}
+	defer client.Close()
+
+	return block.ProcessBlockedError(client.RevokeToken(c.ID), block.BlockChange)
+}
@@ -0,0 +1,202 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package user_test
+
+import (
+	"time"
+
+	"github.com/juju/cmd/cmdtesting"
+	jtesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api/apitokens"
+	"github.com/juju/juju/cmd/juju/user"
+	coremodel "github.com/juju/juju/core/model"
+	"github.com/juju/juju/core/permission"
+	"github.com/juju/juju/jujuclient"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type TokensCommandSuite struct {
+	BaseSuite
+	api   *fakeAPITokensAPI
+	clock *fakeClock
+}
+
+var _ = gc.Suite(&TokensCommandSuite{})
+
+func (s *TokensCommandSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.store.Models["testing"] = &jujuclient.ControllerModels{
+		Models: map[string]jujuclient.ModelDetails{
+			"current-user/ci": {ModelUUID: coretesting.ModelTag.Id(), ModelType: coremodel.IAAS},
+		},
+		CurrentModel: "current-user/ci",
+	}
+	s.clock = &fakeClock{now: time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)}
+	s.api = &fakeAPITokensAPI{
+		tokens: []apitokens.Token{{
+			ID:        "0a1b",
+			Owner:     "bot",
+			ModelUUID: coretesting.ModelTag.Id(),
+			Access:    permission.ReadAccess,
+			CreatedBy: "current-user",
+			Created:   time.Date(2020, 5, 1, 0, 0, 0, 0, time.UTC),
+			Expires:   time.Date(2020, 7, 1, 0, 0, 0, 0, time.UTC),
+		}, {
+			ID:        "2c3d",
+			Owner:     "current-user",
+			ModelUUID: "c0ffee00-0bad-400d-8000-4b1d0d06f00d",
+			Access:    permission.AdminAccess,
+			CreatedBy: "current-user",
+			Created:   time.Date(2020, 4, 1, 0, 0, 0, 0, time.UTC),
+			Expires:   time.Date(2020, 5, 1, 0, 0, 0, 0, time.UTC),
+		}},
+	}
+}
+
+func (s *TokensCommandSuite) TestAddToken(c *gc.C) {
+	ctx, err := cmdtesting.RunCommand(c,
+		user.NewAddTokenCommandForTest(s.api, s.store, s.clock),
+		"--user", "bot", "--role", "write", "--expires", "7d")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, "jujutoken-4e5f.secret\n")
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, `
+Added API token 4e5f for "bot" with write access to model "current-user/ci", expiring 2020-06-08T12:00:00Z.
+The token cannot be shown again. Log in with it by setting JUJU_API_TOKEN.
+`[1:])
+	s.api.CheckCalls(c, []jtesting.StubCall{
+		{"AddToken", []interface{}{
+			coretesting.ModelTag.Id(), "bot", permission.WriteAccess,
+			time.Date(2020, 6, 8, 12, 0, 0, 0, time.UTC),
+		}},
+		{"Close", nil},
+	})
+}
+
+func (s *TokensCommandSuite) TestAddTokenDefaults(c *gc.C) {
+	_, err := cmdtesting.RunCommand(c, user.NewAddTokenCommandForTest(s.api, s.store, s.clock))
+	c.Assert(err, jc.ErrorIsNil)
+	s.api.CheckCall(c, 0, "AddToken",
+		coretesting.ModelTag.Id(), "", permission.ReadAccess,
+		time.Date(2020, 7, 1, 12, 0, 0, 0, time.UTC),
+	)
+}
+
+func (s *TokensCommandSuite) TestAddTokenInit(c *gc.C) {
+	for i, test := range []struct {
+		args []string
+		err  string
+	}{{
+		args: []string{"--role", "superuser"},
+		err:  `--role "superuser" not valid: expected read, write or admin`,
+	}, {
+		args: []string{"--expires", "soon"},
+		err:  `--expires "soon" not valid`,
+	}, {
+		args: []string{"--expires", "xd"},
+		err:  `--expires "xd" not valid`,
+	}, {
+		args: []string{"--expires", "-1h"},
+		err:  `--expires "-1h" must be positive`,
+	}, {
+		args: []string{"--user", "bot!"},
+		err:  `user name "bot!" not valid`,
+	}, {
+		args: []string{"extra"},
+		err:  `unrecognized args: \["extra"\]`,
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		_, err := cmdtesting.RunCommand(c, user.NewAddTokenCommandForTest(s.api, s.store, s.clock), test.args...)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *TokensCommandSuite) TestTokens(c *gc.C) {
+	ctx, err := cmdtesting.RunCommand(c, user.NewTokensCommandForTest(s.api, s.store, s.clock))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, `
+ID    Owner         Model                                 Access  Expires
+0a1b  bot           current-user/ci                       read    2020-07-01T00:00:00Z
+2c3d  current-user  c0ffee00-0bad-400d-8000-4b1d0d06f00d  admin   2020-05-01T00:00:00Z (expired)
+
+`[1:])
+}
+
+func (s *TokensCommandSuite) TestTokensYAML(c *gc.C) {
+	s.api.tokens = s.api.tokens[:1]
+	ctx, err := cmdtesting.RunCommand(c, user.NewTokensCommandForTest(s.api, s.store, s.clock), "--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, `
+- id: 0a1b
+  owner: bot
+  model: current-user/ci
+  access: read
+  created-by: current-user
+  created: 2020-05-01T00:00:00Z
+  expires: 2020-07-01T00:00:00Z
+`[1:])
+}
+
+func (s *TokensCommandSuite) TestTokensNone(c *gc.C) {
+	s.api.tokens = nil
+	ctx, err := cmdtesting.RunCommand(c, user.NewTokensCommandForTest(s.api, s.store, s.clock))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, "")
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, "No tokens to list\n")
+}
+
+func (s *TokensCommandSuite) TestRevokeToken(c *gc.C) {
+	_, err := cmdtesting.RunCommand(c, user.NewRevokeTokenCommandForTest(s.api, s.store), "0a1b")
+	c.Assert(err, jc.ErrorIsNil)
+	s.api.CheckCalls(c, []jtesting.StubCall{
+		{"RevokeToken", []interface{}{"0a1b"}},
+		{"Close", nil},
+	})
+}
+
+func (s *TokensCommandSuite) TestRevokeTokenInit(c *gc.C) {
+	_, err := cmdtesting.RunCommand(c, user.NewRevokeTokenCommandForTest(s.api, s.store))
+	c.Assert(err, gc.ErrorMatches, "no token ID specified")
+	_, err = cmdtesting.RunCommand(c, user.NewRevokeTokenCommandForTest(s.api, s.store), "0a1b", "2c3d")
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["2c3d"\]`)
+}
+
+type fakeAPITokensAPI struct {
+	jtesting.Stub
+	tokens []apitokens.Token
+}
+
+func (f *fakeAPITokensAPI) Close() error {
+	f.MethodCall(f, "Close")
+	return f.NextErr()
+}
+
+func (f *fakeAPITokensAPI) AddToken(modelUUID, owner string, access permission.Access, expires time.Time) (apitokens.Token, string, error) {
+	f.MethodCall(f, "AddToken", modelUUID, owner, access, expires)
+	if err := f.NextErr(); err != nil {
+		return apitokens.Token{}, "", err
+	}
+	if owner == "" {
+		owner = "current-user"
+	}
+	return apitokens.Token{
+		ID:        "4e5f",
+		Owner:     owner,
+		ModelUUID: modelUUID,
+		Access:    access,
+		Expires:   expires,
+	}, "jujutoken-4e5f.secret", nil
+}
+
+func (f *fakeAPITokensAPI) ListTokens() ([]apitokens.Token, error) {
+	f.MethodCall(f, "ListTokens")
+	return f.tokens, f.NextErr()
+}
+
+func (f *fakeAPITokensAPI) RevokeToken(id string) error {
+	f.MethodCall(f, "RevokeToken", id)
+	return f.NextErr()
+}
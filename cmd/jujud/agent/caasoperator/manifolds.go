@@ -32,6 +32,7 @@ import (
 	"github.com/juju/juju/worker/apiconfigwatcher"
 	"github.com/juju/juju/worker/caasoperator"
 	"github.com/juju/juju/worker/caasupgrader"
+	"github.com/juju/juju/worker/cacertupdater"
 	"github.com/juju/juju/worker/fortress"
 	"github.com/juju/juju/worker/gate"
 	"github.com/juju/juju/worker/introspection"
@@ -244,6 +245,14 @@ func Manifolds(config ManifoldsConfig) dependency.Manifolds {
 			Logger:        loggo.GetLogger("juju.worker.apiaddressupdater"),
 		})),
 
+		// The CA cert updater is a leaf worker that rewrites agent config
+		// as the controller's CA certificates change when the CA is rotated.
+		caCertUpdaterName: ifNotMigrating(cacertupdater.Manifold(cacertupdater.ManifoldConfig{
+			AgentName:     agentName,
+			APICallerName: apiCallerName,
+			Logger:        loggo.GetLogger("juju.worker.cacertupdater"),
+		})),
+
 		// The charmdir resource coordinates whether the charm directory is
 		// available or not; after 'start' hook and before 'stop' hook
 		// executes, and not during upgrades.
@@ -335,6 +344,7 @@ const (
 	proxyConfigUpdaterName   = "proxy-config-updater"
 	loggingConfigUpdaterName = "logging-config-updater"
 	apiAddressUpdaterName    = "api-address-updater"
+	caCertUpdaterName        = "ca-cert-updater"
 )
 
 type noopStatusSetter struct{}
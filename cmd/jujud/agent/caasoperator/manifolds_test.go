@@ -39,6 +39,7 @@ func (s *ManifoldsSuite) TestManifoldNames(c *gc.C) {
 		"api-address-updater",
 		"api-caller",
 		"api-config-watcher",
+		"ca-cert-updater",
 		"charm-dir",
 		"clock",
 		"hook-retry-strategy",
@@ -134,6 +135,15 @@ var expectedOperatorManifoldsWithDependencies = map[string][]string{
 
 	"api-config-watcher": {"agent"},
 
+	"ca-cert-updater": {
+		"agent",
+		"api-caller",
+		"api-config-watcher",
+		"migration-fortress",
+		"migration-inactive-flag",
+		"upgrade-steps-flag",
+		"upgrade-steps-gate"},
+
 	"charm-dir": {
 		"agent",
 		"api-caller",
@@ -433,10 +433,15 @@ func upgradeCertificateDNSNames(config agent.ConfigSetter) error {
 
 	// Validate the current certificate and private key pair, and then
 	// extract the current DNS names from the certificate. If the
-	// certificate validation fails, or it does not contain the DNS
-	// names we require, we will generate a new one.
+	// certificate validation fails, it does not contain the DNS
+	// names we require, or it was not issued by the current CA (as
+	// happens when the controller's CA is rotated), we will generate
+	// a new one.
 	leaf, err := authority.LeafGroupFromPemCertKey(pki.DefaultLeafGroup,
 		[]byte(si.Cert), []byte(si.PrivateKey))
+	if err == nil {
+		err = leaf.Certificate().CheckSignatureFrom(authority.Certificate())
+	}
 	if err != nil || !pki.LeafHasDNSNames(leaf, controller.DefaultDNSNames) {
 		logger.Infof("parsing certificate/key failed, will generate a new one: %v", err)
 		leaf, err = authority.LeafRequestForGroup(pki.DefaultLeafGroup).
@@ -43,7 +43,9 @@ import (
 	"github.com/juju/juju/worker/apiservercertwatcher"
 	"github.com/juju/juju/worker/auditconfigupdater"
 	"github.com/juju/juju/worker/authenticationworker"
+	"github.com/juju/juju/worker/branchpromoter"
 	"github.com/juju/juju/worker/caasupgrader"
+	"github.com/juju/juju/worker/cacertupdater"
 	"github.com/juju/juju/worker/centralhub"
 	"github.com/juju/juju/worker/certupdater"
 	"github.com/juju/juju/worker/common"
@@ -653,6 +655,20 @@ func commonManifolds(config ManifoldsConfig) dependency.Manifolds {
 			},
 		))),
 
+		// The branch promoter worker commits or aborts model branches
+		// with a promotion policy, according to how long the units
+		// tracking them have been healthy.
+		branchPromoterName: ifNotMigrating(ifPrimaryController(branchpromoter.Manifold(
+			branchpromoter.ManifoldConfig{
+				ClockName:      clockName,
+				ModelCacheName: modelCacheName,
+				StateName:      stateName,
+				Logger:         loggo.GetLogger("juju.worker.branchpromoter"),
+				Interval:       time.Minute,
+				NewWorker:      branchpromoter.NewWorker,
+			},
+		))),
+
 		httpServerArgsName: httpserverargs.Manifold(httpserverargs.ManifoldConfig{
 			ClockName:             clockName,
 			ControllerPortName:    controllerPortName,
@@ -686,6 +702,7 @@ func commonManifolds(config ManifoldsConfig) dependency.Manifolds {
 			Clock:                config.Clock,
 			MuxShutdownWait:      config.MuxShutdownWait,
 			LogDir:               agentConfig.LogDir(),
+			DataDir:              agentConfig.DataDir(),
 			GetControllerConfig:  httpserver.GetControllerConfig,
 			NewTLSConfig:         httpserver.NewTLSConfig,
 			NewWorker:            httpserver.NewWorkerShim,
@@ -912,6 +929,16 @@ func IAASManifolds(config ManifoldsConfig) dependency.Manifolds {
 			Logger:        loggo.GetLogger("juju.worker.apiaddressupdater"),
 		})),
 
+		// The CA cert updater is a leaf worker that rewrites agent config
+		// as the controller's CA certificates change when the CA is rotated.
+		// Controller agents update their CA certificates with the
+		// agent config updater instead.
+		caCertUpdaterName: ifNotMigrating(cacertupdater.Manifold(cacertupdater.ManifoldConfig{
+			AgentName:     agentName,
+			APICallerName: apiCallerName,
+			Logger:        loggo.GetLogger("juju.worker.cacertupdater"),
+		})),
+
 		machineActionName: ifNotMigrating(machineactions.Manifold(machineactions.ManifoldConfig{
 			AgentName:     agentName,
 			APICallerName: apiCallerName,
@@ -1111,6 +1138,7 @@ const (
 	diskManagerName               = "disk-manager"
 	proxyConfigUpdater            = "proxy-config-updater"
 	apiAddressUpdaterName         = "api-address-updater"
+	caCertUpdaterName             = "ca-cert-updater"
 	machinerName                  = "machiner"
 	logSenderName                 = "log-sender"
 	deployerName                  = "unit-agent-deployer"
@@ -1128,6 +1156,7 @@ const (
 	isControllerFlagName          = "is-controller-flag"
 	instanceMutaterName           = "instance-mutater"
 	txnPrunerName                 = "transaction-pruner"
+	branchPromoterName            = "branch-promoter"
 	certificateWatcherName        = "certificate-watcher"
 	modelCacheName                = "model-cache"
 	modelCacheInitializedFlagName = "model-cache-initialized-flag"
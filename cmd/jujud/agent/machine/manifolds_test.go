@@ -65,7 +65,9 @@ func (ms *ManifoldsSuite) TestManifoldNamesIAAS(c *gc.C) {
 			"api-config-watcher",
 			"api-server",
 			"audit-config-updater",
+			"branch-promoter",
 			"broker-tracker",
+			"ca-cert-updater",
 			"central-hub",
 			"certificate-updater",
 			"certificate-watcher",
@@ -144,6 +146,7 @@ func (ms *ManifoldsSuite) TestManifoldNamesCAAS(c *gc.C) {
 			"api-config-watcher",
 			"api-server",
 			"audit-config-updater",
+			"branch-promoter",
 			"central-hub",
 			"certificate-watcher",
 			"clock",
@@ -304,6 +307,7 @@ func (*ManifoldsSuite) TestSingularGuardsUsed(c *gc.C) {
 		"upgrade-database-runner",
 	)
 	primaryControllerWorkers := set.NewStrings(
+		"branch-promoter",
 		"external-controller-updater",
 		"transaction-pruner",
 	)
@@ -467,6 +471,41 @@ var expectedMachineManifoldsWithDependencies = map[string][]string{
 		"state-config-watcher",
 	},
 
+	"branch-promoter": {
+		"agent",
+		"api-caller",
+		"api-config-watcher",
+		"central-hub",
+		"clock",
+		"is-controller-flag",
+		"is-primary-controller-flag",
+		"migration-fortress",
+		"migration-inactive-flag",
+		"model-cache",
+		"model-cache-initialized-gate",
+		"multiwatcher",
+		"state",
+		"state-config-watcher",
+		"upgrade-check-flag",
+		"upgrade-check-gate",
+		"upgrade-database-flag",
+		"upgrade-database-gate",
+		"upgrade-steps-flag",
+		"upgrade-steps-gate",
+	},
+
+	"ca-cert-updater": {
+		"agent",
+		"api-caller",
+		"api-config-watcher",
+		"migration-fortress",
+		"migration-inactive-flag",
+		"upgrade-check-flag",
+		"upgrade-check-gate",
+		"upgrade-steps-flag",
+		"upgrade-steps-gate",
+	},
+
 	"central-hub": {"agent", "state-config-watcher"},
 
 	"certificate-updater": {
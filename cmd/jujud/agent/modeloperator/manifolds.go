@@ -18,6 +18,7 @@ import (
 	"github.com/juju/juju/worker/caasadmission"
 	"github.com/juju/juju/worker/caasbroker"
 	"github.com/juju/juju/worker/caasrbacmapper"
+	"github.com/juju/juju/worker/cacertupdater"
 	"github.com/juju/juju/worker/muxhttpserver"
 )
 
@@ -49,6 +50,14 @@ func Manifolds(config ManifoldConfig) dependency.Manifolds {
 			Logger:               loggo.GetLogger("juju.worker.apicaller"),
 		}),
 
+		// The CA cert updater is a leaf worker that rewrites agent config
+		// as the controller's CA certificates change when the CA is rotated.
+		caCertUpdaterName: cacertupdater.Manifold(cacertupdater.ManifoldConfig{
+			AgentName:     agentName,
+			APICallerName: apiCallerName,
+			Logger:        loggo.GetLogger("juju.worker.cacertupdater"),
+		}),
+
 		caasAdmissionName: caasadmission.Manifold(caasadmission.ManifoldConfig{
 			AgentName:      agentName,
 			AuthorityName:  certificateWatcherName,
@@ -92,6 +101,7 @@ const (
 	caasAdmissionName      = "caas-admission"
 	caasBrokerTrackerName  = "caas-broker-tracker"
 	caasRBACMapperName     = "caas-rbac-mapper"
+	caCertUpdaterName      = "ca-cert-updater"
 	certificateWatcherName = "certificate-watcher"
 	modelHTTPServerName    = "model-http-server"
 )
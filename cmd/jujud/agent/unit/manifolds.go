@@ -29,6 +29,7 @@ import (
 	"github.com/juju/juju/worker/apiaddressupdater"
 	"github.com/juju/juju/worker/apicaller"
 	"github.com/juju/juju/worker/apiconfigwatcher"
+	"github.com/juju/juju/worker/cacertupdater"
 	"github.com/juju/juju/worker/fortress"
 	"github.com/juju/juju/worker/gate"
 	"github.com/juju/juju/worker/leadership"
@@ -271,6 +272,14 @@ func Manifolds(config ManifoldsConfig) dependency.Manifolds {
 			Logger:        loggo.GetLogger("juju.worker.apiaddressupdater"),
 		})),
 
+		// The CA cert updater is a leaf worker that rewrites agent config
+		// as the controller's CA certificates change when the CA is rotated.
+		caCertUpdaterName: ifNotMigrating(cacertupdater.Manifold(cacertupdater.ManifoldConfig{
+			AgentName:     agentName,
+			APICallerName: apiCallerName,
+			Logger:        loggo.GetLogger("juju.worker.cacertupdater"),
+		})),
+
 		// The proxy config updater is a leaf worker that sets http/https/apt/etc
 		// proxy settings.
 		// TODO(fwereade): timing of this is suspicious. There was superstitious
@@ -398,6 +407,7 @@ const (
 	loggingConfigUpdaterName = "logging-config-updater"
 	proxyConfigUpdaterName   = "proxy-config-updater"
 	apiAddressUpdaterName    = "api-address-updater"
+	caCertUpdaterName        = "ca-cert-updater"
 
 	charmDirName          = "charm-dir"
 	leadershipTrackerName = "leadership-tracker"
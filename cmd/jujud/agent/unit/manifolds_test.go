@@ -46,6 +46,7 @@ func (s *ManifoldsSuite) TestManifoldNames(c *gc.C) {
 		"logging-config-updater",
 		"proxy-config-updater",
 		"api-address-updater",
+		"ca-cert-updater",
 		"charm-dir",
 		"leadership-tracker",
 		"hook-retry-strategy",
@@ -136,6 +137,17 @@ var expectedUnitManifoldsWithDependencies = map[string][]string{
 
 	"api-config-watcher": {"agent"},
 
+	"ca-cert-updater": {
+		"agent",
+		"api-caller",
+		"api-config-watcher",
+		"migration-fortress",
+		"migration-inactive-flag",
+		"upgrade-check-flag",
+		"upgrade-check-gate",
+		"upgrade-steps-flag",
+		"upgrade-steps-gate"},
+
 	"charm-dir": {
 		"agent",
 		"api-caller",
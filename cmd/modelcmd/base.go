@@ -29,6 +29,7 @@ import (
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/juju"
+	"github.com/juju/juju/juju/osenv"
 	"github.com/juju/juju/jujuclient"
 	"github.com/juju/juju/pki"
 )
@@ -205,6 +206,11 @@ func (c *CommandBase) NewAPIRoot(
 			accountDetails = &jujuclient.AccountDetails{}
 		}
 	}
+	// An API token in the environment takes the place of the
+	// recorded account.
+	if token := os.Getenv(osenv.JujuAPITokenEnvKey); token != "" {
+		accountDetails = &jujuclient.AccountDetails{Token: token}
+	}
 	param, err := c.NewAPIConnectionParams(
 		store, controllerName, modelName, accountDetails,
 	)
@@ -267,26 +273,42 @@ func (c *CommandBase) NewAPIConnectionParams(
 	if err != nil {
 		return juju.NewAPIConnectionParams{}, errors.Trace(err)
 	}
-	var getPassword func(username string) (string, error)
+	var getPassword, getMFACode func(username string) (string, error)
 	if c.cmdContext != nil {
 		getPassword = func(username string) (string, error) {
 			fmt.Fprintf(c.cmdContext.Stderr, "please enter password for %s on %s: ", username, controllerName)
 			defer fmt.Fprintln(c.cmdContext.Stderr)
 			return readPassword(c.cmdContext.Stdin)
 		}
+		getMFACode = func(username string) (string, error) {
+			fmt.Fprintf(c.cmdContext.Stderr, "please enter authentication code for %s on %s: ", username, controllerName)
+			return readLine(c.cmdContext.Stdin)
+		}
 	} else {
 		getPassword = func(username string) (string, error) {
 			return "", errors.New("no context to prompt for password")
 		}
 	}
 
-	return newAPIConnectionParams(
+	params, err := newAPIConnectionParams(
 		store, controllerName, modelName,
 		accountDetails,
 		bakeryClient,
 		c.apiOpen,
 		getPassword,
+		getMFACode,
 	)
+	if err != nil {
+		return juju.NewAPIConnectionParams{}, errors.Trace(err)
+	}
+	if c.cmdContext != nil {
+		// Controllers using an OpenID provider ask the user to
+		// approve the login in a web browser.
+		params.DialOpts.OIDCLogin = (&authentication.OIDCDeviceLogin{
+			Writer: c.cmdContext.Stderr,
+		}).Login
+	}
+	return params, nil
 }
 
 // HTTPClient returns an http.Client that contains the loaded
@@ -486,7 +508,7 @@ func newAPIConnectionParams(
 	accountDetails *jujuclient.AccountDetails,
 	bakery *httpbakery.Client,
 	apiOpen api.OpenFunc,
-	getPassword func(string) (string, error),
+	getPassword, getMFACode func(string) (string, error),
 ) (juju.NewAPIConnectionParams, error) {
 	if controllerName == "" {
 		return juju.NewAPIConnectionParams{}, errors.Trace(errNoNameSpecified)
@@ -504,7 +526,7 @@ func newAPIConnectionParams(
 
 	if accountDetails != nil {
 		bakery.InteractionMethods = []httpbakery.Interactor{
-			authentication.NewInteractor(accountDetails.User, getPassword),
+			authentication.NewMFAInteractor(accountDetails.User, getPassword, getMFACode),
 			httpbakery.WebBrowserInteractor{},
 		}
 	}
@@ -199,6 +199,17 @@ func matchHostArchTools(allTools coretools.List) (coretools.List, error) {
 
 var newMachineInitReader = cloudconfig.NewMachineInitReader
 
+// containerCloudInitData returns the cloud-init user data to use for a
+// new container. The provisioning info passed in the instance config
+// includes any overlays matching the container's constraints, so it is
+// preferred over the model's user data in the container config.
+func containerCloudInitData(icfg *instancecfg.InstanceConfig, config params.ContainerConfig) map[string]interface{} {
+	if icfg.CloudInitUserData != nil {
+		return icfg.CloudInitUserData
+	}
+	return config.CloudInitUserData
+}
+
 // combinedCloudInitData returns a combined map of the given cloudInitData
 // and instance cloud init properties provided.
 func combinedCloudInitData(
@@ -117,7 +117,7 @@ func (broker *kvmBroker) StartInstance(ctx context.ProviderCallContext, args env
 	}
 
 	cloudInitUserData, err := combinedCloudInitData(
-		config.CloudInitUserData,
+		containerCloudInitData(args.InstanceConfig, config),
 		config.ContainerInheritProperties,
 		series, kvmLogger)
 	if err != nil {
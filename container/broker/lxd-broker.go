@@ -116,7 +116,7 @@ func (broker *lxdBroker) StartInstance(ctx context.ProviderCallContext, args env
 	}
 
 	cloudInitUserData, err := combinedCloudInitData(
-		config.CloudInitUserData,
+		containerCloudInitData(args.InstanceConfig, config),
 		config.ContainerInheritProperties,
 		series, lxdLogger)
 	if err != nil {
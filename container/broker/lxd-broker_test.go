@@ -178,6 +178,32 @@ func (s *lxdBrokerSuite) TestStartInstanceWithCloudInitUserData(c *gc.C) {
 	}, c)
 }
 
+func (s *lxdBrokerSuite) TestStartInstanceWithProvisioningInfoCloudInitUserData(c *gc.C) {
+	broker, brokerErr := s.newLXDBroker(c)
+	c.Assert(brokerErr, jc.ErrorIsNil)
+
+	// The user data from the provisioning info includes any overlays
+	// matching the container's constraints, so it takes precedence
+	// over the model's user data.
+	instanceConfig := makeInstanceConfig(c, s, "1/lxd/0")
+	instanceConfig.CloudInitUserData = map[string]interface{}{
+		"packages": []interface{}{"python-keystoneclient", "nvidia-driver"},
+	}
+	_, err := broker.StartInstance(context.NewCloudCallContext(), environs.StartInstanceParams{
+		Tools:          makePossibleTools(),
+		InstanceConfig: instanceConfig,
+		StatusCallback: makeNoOpStatusCallback(),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.manager.CheckCallNames(c, "CreateContainer")
+	call := s.manager.Calls()[0]
+	c.Assert(call.Args[0], gc.FitsTypeOf, &instancecfg.InstanceConfig{})
+	assertCloudInitUserData(call.Args[0].(*instancecfg.InstanceConfig).CloudInitUserData, map[string]interface{}{
+		"packages": []interface{}{"python-keystoneclient", "nvidia-driver"},
+	}, c)
+}
+
 func (s *lxdBrokerSuite) TestStartInstanceWithContainerInheritProperties(c *gc.C) {
 	broker.PatchNewMachineInitReader(s, newFakeMachineInitReader)
 	s.api.fakeContainerConfig.ContainerInheritProperties = "ca-certs,apt-security"
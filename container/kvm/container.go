@@ -7,7 +7,6 @@ import (
 	"fmt"
 
 	"github.com/juju/errors"
-	"github.com/juju/utils/arch"
 
 	"github.com/juju/juju/container"
 	"github.com/juju/juju/container/kvm/libvirt"
@@ -25,8 +24,9 @@ type kvmContainer struct {
 	// value if we already know it (like in the list situation).
 	started *bool
 
-	pathfinder func(string) (string, error)
-	runCmd     runFunc
+	pathfinder   func(string) (string, error)
+	runCmd       runFunc
+	runCmdAsRoot runFunc
 }
 
 var _ Container = (*kvmContainer)(nil)
@@ -44,16 +44,11 @@ func (c *kvmContainer) EnsureCachedImage(params StartParams) error {
 			return imagedownloads.NewDataSource(params.ImageDownloadURL)
 		}
 	}
-	var fType = BIOSFType
-	if params.Arch == arch.ARM64 {
-		fType = UEFIFType
-	}
-
 	sp := syncParams{
 		arch:    params.Arch,
 		series:  params.Series,
 		stream:  params.Stream,
-		fType:   fType,
+		fType:   imageFType(params.Arch, params.Firmware),
 		srcFunc: srcFunc,
 	}
 	logger.Debugf("synchronise images for %s %s %s %s", sp.arch, sp.series, sp.stream, params.ImageDownloadURL)
@@ -102,6 +97,8 @@ func (c *kvmContainer) Start(params StartParams) error {
 		Memory:            params.Memory,
 		CpuCores:          params.CpuCores,
 		RootDisk:          params.RootDisk,
+		StoragePool:       params.StoragePool,
+		Firmware:          params.Firmware,
 		Interfaces:        interfaces,
 	}); err != nil {
 		return err
@@ -31,7 +31,7 @@ func NewEmptyKvmContainer() *kvmContainer {
 
 // NewTestContainer returns a new container for testing.
 func NewTestContainer(name string, runCmd runFunc, pathfinder func(string) (string, error)) *kvmContainer {
-	return &kvmContainer{name: name, runCmd: runCmd, pathfinder: pathfinder}
+	return &kvmContainer{name: name, runCmd: runCmd, runCmdAsRoot: runCmd, pathfinder: pathfinder}
 }
 
 // ContainerFromInstance extracts the inner container from input instance,
@@ -46,10 +46,17 @@ func NewRunStub(output string, err error) *runStub {
 	return &runStub{output: output, err: err}
 }
 
+// NewRunStubWithOutputs is a runStub that returns each of the outputs in
+// turn for successive calls, and the last of them once they are exhausted.
+func NewRunStubWithOutputs(outputs ...string) *runStub {
+	return &runStub{outputs: outputs}
+}
+
 type runStub struct {
-	output string
-	err    error
-	calls  []string
+	output  string
+	outputs []string
+	err     error
+	calls   []string
 }
 
 // Run fakes running commands, instead recording calls made for use in testing.
@@ -60,6 +67,10 @@ func (s *runStub) Run(dir, cmd string, args ...string) (string, error) {
 	if s.err != nil {
 		return s.err.Error(), s.err
 	}
+	if len(s.outputs) > 0 {
+		s.output = s.outputs[0]
+		s.outputs = s.outputs[1:]
+	}
 	return s.output, nil
 }
 
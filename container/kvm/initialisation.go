@@ -33,7 +33,7 @@ func (ci *containerInitialiser) Initialise() error {
 		return errors.Trace(err)
 	}
 
-	poolInfo, err := poolInfo(run)
+	poolInfo, err := poolInfo(run, poolName)
 	if err != nil {
 		return errors.Trace(err)
 	}
@@ -19,6 +19,8 @@ type StartParams struct {
 	Memory            uint64 // MB
 	CpuCores          uint64
 	RootDisk          uint64 // GB
+	StoragePool       string // libvirt storage pool for the root disk
+	Firmware          string // BIOSFirmware or UEFIFirmware
 	ImageDownloadURL  string
 	StatusCallback    func(status status.Status, info string, data map[string]interface{}) error
 }
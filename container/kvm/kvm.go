@@ -212,6 +212,9 @@ func (manager *containerManager) CreateContainer(
 	if err != nil {
 		return nil, nil, errors.Annotate(err, "failed to parse hardware")
 	}
+	if startParams.StoragePool != "" {
+		hardware.RootDiskSource = &startParams.StoragePool
+	}
 
 	_ = callback(status.Provisioning, "Creating container; it might take some time", nil)
 	logger.Tracef("create the container, constraints: %v", cons)
@@ -313,6 +316,12 @@ func ParseConstraintsToStartParams(cons constraints.Value) StartParams {
 			params.RootDisk = size
 		}
 	}
+	if cons.HasRootDiskSource() {
+		params.StoragePool = *cons.RootDiskSource
+	}
+	if cons.HasFirmware() {
+		params.Firmware = *cons.Firmware
+	}
 	if cons.Arch != nil {
 		logger.Infof("arch constraint of %q being ignored as not supported", *cons.Arch)
 	}
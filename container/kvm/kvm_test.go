@@ -304,6 +304,30 @@ func (s *ConstraintsSuite) TestDefaults(c *gc.C) {
 		infoLog: []string{
 			`tags constraint of "foo,bar" being ignored as not supported`,
 		},
+	}, {
+		cons: "root-disk-source=fast-pool",
+		expected: kvm.StartParams{
+			Memory:      kvm.DefaultMemory,
+			CpuCores:    kvm.DefaultCpu,
+			RootDisk:    kvm.DefaultDisk,
+			StoragePool: "fast-pool",
+		},
+	}, {
+		cons: "firmware=uefi",
+		expected: kvm.StartParams{
+			Memory:   kvm.DefaultMemory,
+			CpuCores: kvm.DefaultCpu,
+			RootDisk: kvm.DefaultDisk,
+			Firmware: kvm.UEFIFirmware,
+		},
+	}, {
+		cons: "firmware=bios",
+		expected: kvm.StartParams{
+			Memory:   kvm.DefaultMemory,
+			CpuCores: kvm.DefaultCpu,
+			RootDisk: kvm.DefaultDisk,
+			Firmware: kvm.BIOSFirmware,
+		},
 	}, {
 		cons: "mem=4G cores=4 root-disk=20G arch=armhf cpu-power=100 container=lxd tags=foo,bar",
 		expected: kvm.StartParams{
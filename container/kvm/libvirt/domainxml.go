@@ -44,7 +44,8 @@ type domainParams interface {
 	// Host returns the host name.
 	Host() string
 	// Loader returns the path to the EFI firmware blob to UEFI boot into an
-	// image. This is a read-only "pflash" drive.
+	// image. This is a read-only "pflash" drive. It is empty if the domain
+	// boots with BIOS.
 	Loader() string
 	// NetworkInfo contains the network interfaces to create in the domain.
 	NetworkInfo() []InterfaceInfo
@@ -151,7 +152,15 @@ func generateOSElement(p domainParams) OS {
 			},
 		}
 	default:
-		return OS{Type: OSType{Text: "hvm"}}
+		elem := OS{Type: OSType{Text: "hvm"}}
+		if loader := p.Loader(); loader != "" {
+			elem.Loader = &NVRAMCode{
+				Text:     loader,
+				ReadOnly: "yes",
+				Type:     "pflash",
+			}
+		}
+		return elem
 	}
 }
 
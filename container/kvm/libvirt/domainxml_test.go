@@ -58,6 +58,44 @@ var amd64DomainStr = `
     </devices>
 </domain>`[1:]
 
+var amd64UEFIDomainStr = `
+<domain type="kvm">
+    <name>juju-someid</name>
+    <vcpu>2</vcpu>
+    <currentMemory unit="MiB">1024</currentMemory>
+    <memory unit="MiB">1024</memory>
+    <os>
+        <type>hvm</type>
+        <loader readonly="yes" type="pflash">/shared/ovmf.fd</loader>
+    </os>
+    <devices>
+        <disk device="disk" type="file">
+            <driver type="qcow2" name="qemu"></driver>
+            <source file="/some/path"></source>
+            <target dev="vda"></target>
+        </disk>
+        <disk device="disk" type="file">
+            <driver type="raw" name="qemu"></driver>
+            <source file="/another/path"></source>
+            <target dev="vdb"></target>
+        </disk>
+        <interface type="bridge">
+            <mac address="00:00:00:00:00:00"></mac>
+            <model type="virtio"></model>
+            <source bridge="parent-dev"></source>
+            <guest dev="device-name"></guest>
+        </interface>
+        <serial type="pty">
+            <source path="/dev/pts/2"></source>
+            <target port="0"></target>
+        </serial>
+        <console type="pty" tty="/dev/pts/2">
+            <source path="/dev/pts/2"></source>
+            <target port="0"></target>
+        </console>
+    </devices>
+</domain>`[1:]
+
 var arm64DomainStr = `
 <domain type="kvm">
     <name>juju-someid</name>
@@ -104,10 +142,11 @@ var arm64DomainStr = `
 
 func (domainXMLSuite) TestNewDomain(c *gc.C) {
 	table := []struct {
-		arch, want string
+		arch, loader, want string
 	}{
-		{"amd64", amd64DomainStr},
-		{"arm64", arm64DomainStr},
+		{"amd64", "", amd64DomainStr},
+		{"amd64", "/shared/ovmf.fd", amd64UEFIDomainStr},
+		{"arm64", "/shared/readonly.fd", arm64DomainStr},
 	}
 	for i, test := range table {
 		c.Logf("TestNewDomain: test #%d for %s", i+1, test.arch)
@@ -120,11 +159,7 @@ func (domainXMLSuite) TestNewDomain(c *gc.C) {
 			dummyDisk{driver: "qcow2", source: "/some/path"},
 			dummyDisk{driver: "raw", source: "/another/path"},
 		}
-		params := dummyParams{ifaceInfo: ifaces, diskInfo: disks, memory: 1024, cpuCores: 2, hostname: "juju-someid", arch: test.arch}
-
-		if test.arch == "arm64" {
-			params.loader = "/shared/readonly.fd"
-		}
+		params := dummyParams{ifaceInfo: ifaces, diskInfo: disks, memory: 1024, cpuCores: 2, hostname: "juju-someid", arch: test.arch, loader: test.loader}
 
 		d, err := NewDomain(params)
 		c.Check(err, jc.ErrorIsNil)
@@ -18,6 +18,7 @@ import (
 	"github.com/juju/clock"
 	"github.com/juju/errors"
 	"github.com/juju/os/series"
+	jujuarch "github.com/juju/utils/arch"
 
 	"github.com/juju/juju/core/paths"
 	"github.com/juju/juju/environs/imagedownloads"
@@ -29,9 +30,21 @@ import (
 const BIOSFType = "disk1.img"
 
 // UEFIFType is the file type we want to fetch and use for kvm instances which
-// boot using UEFI. In our case this is ARM64.
+// boot using UEFI. This is always the case for ARM64, and for other
+// architectures when UEFI firmware is requested. Streams rarely publish
+// UEFI images for architectures other than ARM64, whose BIOS images can
+// also boot using UEFI, so those are used instead when there are none.
 const UEFIFType = "uefi1.img"
 
+// imageFType returns the image file type to use for instances of the
+// given architecture booting with the given firmware.
+func imageFType(arch, firmware string) string {
+	if arch == jujuarch.ARM64 || firmware == UEFIFirmware {
+		return UEFIFType
+	}
+	return BIOSFType
+}
+
 // Oner gets the one matching item from simplestreams.
 type Oner interface {
 	One() (*imagedownloads.Metadata, error)
@@ -41,6 +54,9 @@ type Oner interface {
 type syncParams struct {
 	arch, series, stream, fType string
 	srcFunc                     func() simplestreams.DataSource
+
+	// oneFunc is imagedownloads.One, unless overridden for testing.
+	oneFunc func(arch, release, stream, ftype string, src func() simplestreams.DataSource) (*imagedownloads.Metadata, error)
 }
 
 // One implements Oner.
@@ -48,11 +64,28 @@ func (p syncParams) One() (*imagedownloads.Metadata, error) {
 	if err := p.exists(); err != nil {
 		return nil, errors.Trace(err)
 	}
-	return imagedownloads.One(p.arch, p.series, p.stream, p.fType, p.srcFunc)
+	one := p.oneFunc
+	if one == nil {
+		one = imagedownloads.One
+	}
+	md, err := one(p.arch, p.series, p.stream, p.fType, p.srcFunc)
+	if err == nil || p.fType != UEFIFType || p.arch == jujuarch.ARM64 {
+		return md, errors.Trace(err)
+	}
+	logger.Debugf("no UEFI image for %s %s, using the BIOS image: %v", p.series, p.arch, err)
+	md, err = one(p.arch, p.series, p.stream, BIOSFType, p.srcFunc)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	// The image is cached as the UEFI backing file, so that it is
+	// found by the machines requesting UEFI firmware.
+	uefi := *md
+	uefi.FType = UEFIFType
+	return &uefi, nil
 }
 
 func (p syncParams) exists() error {
-	fName := backingFileName(p.series, p.arch)
+	fName := backingFileName(p.series, p.arch, p.fType)
 	baseDir, err := paths.DataDir(series.MustHostSeries())
 	if err != nil {
 		return errors.Trace(err)
@@ -304,14 +337,19 @@ func newImage(md *imagedownloads.Metadata, imageDownloadURL string, pathfinder f
 
 	return &Image{
 		FilePath: filepath.Join(
-			baseDir, kvm, guestDir, backingFileName(md.Release, md.Arch)),
+			baseDir, kvm, guestDir, backingFileName(md.Release, md.Arch, md.FType)),
 		tmpFile:  fh,
 		runCmd:   run,
 		progress: callback,
 	}, nil
 }
 
-func backingFileName(series, arch string) string {
+func backingFileName(series, arch, fType string) string {
 	// TODO(ro) validate series and arch to be sure they are in the right order.
+	if fType == UEFIFType && arch != jujuarch.ARM64 {
+		// UEFI images are only the default for ARM64, so they are
+		// cached separately from the BIOS images of other arches.
+		return fmt.Sprintf("%s-%s-uefi-backing-file.qcow", series, arch)
+	}
 	return fmt.Sprintf("%s-%s-backing-file.qcow", series, arch)
 }
@@ -21,6 +21,7 @@ import (
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/environs/imagedownloads"
+	"github.com/juju/juju/environs/simplestreams"
 )
 
 // syncInternalSuite is gocheck boilerplate.
@@ -32,6 +33,58 @@ var _ = gc.Suite(&syncInternalSuite{})
 
 const imageContents = "fake img file"
 
+func (syncInternalSuite) TestBackingFileName(c *gc.C) {
+	for i, test := range []struct {
+		arch, firmware, want string
+	}{
+		{"amd64", "", "focal-amd64-backing-file.qcow"},
+		{"amd64", BIOSFirmware, "focal-amd64-backing-file.qcow"},
+		{"amd64", UEFIFirmware, "focal-amd64-uefi-backing-file.qcow"},
+		{"arm64", "", "focal-arm64-backing-file.qcow"},
+		{"arm64", BIOSFirmware, "focal-arm64-backing-file.qcow"},
+	} {
+		c.Logf("test %d: %s %q", i, test.arch, test.firmware)
+		got := backingFileName("focal", test.arch, imageFType(test.arch, test.firmware))
+		c.Check(got, gc.Equals, test.want)
+	}
+}
+
+func (syncInternalSuite) TestOneUEFIFallsBackToBIOSImage(c *gc.C) {
+	var requested []string
+	p := syncParams{
+		arch:   "amd64",
+		series: "focal",
+		stream: "released",
+		fType:  UEFIFType,
+		oneFunc: func(arch, release, stream, ftype string, _ func() simplestreams.DataSource) (*imagedownloads.Metadata, error) {
+			requested = append(requested, ftype)
+			if ftype == UEFIFType {
+				return nil, errors.Errorf("no results")
+			}
+			return &imagedownloads.Metadata{Arch: arch, Release: release, FType: ftype, Path: "focal-amd64.img"}, nil
+		},
+	}
+	md, err := p.One()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(requested, jc.DeepEquals, []string{UEFIFType, BIOSFType})
+	c.Check(md.Path, gc.Equals, "focal-amd64.img")
+	c.Check(backingFileName(md.Release, md.Arch, md.FType), gc.Equals, "focal-amd64-uefi-backing-file.qcow")
+}
+
+func (syncInternalSuite) TestOneARM64NoUEFIImage(c *gc.C) {
+	p := syncParams{
+		arch:   "arm64",
+		series: "focal",
+		stream: "released",
+		fType:  UEFIFType,
+		oneFunc: func(arch, release, stream, ftype string, _ func() simplestreams.DataSource) (*imagedownloads.Metadata, error) {
+			return nil, errors.Errorf("no results for %q", ftype)
+		},
+	}
+	_, err := p.One()
+	c.Assert(err, gc.ErrorMatches, `no results for "uefi1.img"`)
+}
+
 func (syncInternalSuite) TestFetcher(c *gc.C) {
 	ts := newTestServer()
 	defer ts.Close()
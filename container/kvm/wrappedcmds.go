@@ -48,6 +48,17 @@ const (
 	// as necessary if so. It seems it will require some serious acrobatics to
 	// get trusty to work properly and that may be out of scope for juju.
 	nvramCode = "/usr/share/AAVMF/AAVMF_CODE.fd"
+
+	// ovmfCode is the UEFI firmware used by architectures other than
+	// ARM64, which boot with BIOS unless UEFI firmware is requested.
+	ovmfCode = "/usr/share/OVMF/OVMF_CODE.fd"
+
+	// BIOSFirmware requests that a machine boot using a legacy BIOS.
+	// This is the default for all architectures but ARM64.
+	BIOSFirmware = "bios"
+
+	// UEFIFirmware requests that a machine boot using UEFI.
+	UEFIFirmware = "uefi"
 )
 
 var (
@@ -70,6 +81,15 @@ type CreateMachineParams struct {
 	RootDisk          uint64
 	Interfaces        []libvirt.InterfaceInfo
 
+	// StoragePool is the name of the libvirt storage pool from which
+	// the root disk and the cloud-init data source disk are allocated.
+	// If empty, they are created in the juju pool.
+	StoragePool string
+
+	// Firmware is the firmware to boot the machine with, either
+	// BIOSFirmware or UEFIFirmware. ARM64 machines always use UEFI.
+	Firmware string
+
 	disks    []libvirt.DiskInfo
 	findPath func(string) (string, error)
 
@@ -86,10 +106,17 @@ func (p CreateMachineParams) Arch() string {
 	return arch.HostArch()
 }
 
-// Loader is the path to the binary firmware blob used in UEFI booting. At the
-// time of this writing only ARM64 requires this to run.
+// Loader is the path to the binary firmware blob used in UEFI booting. ARM64
+// always boots with UEFI; other architectures only do so if UEFI firmware was
+// requested, and otherwise boot with BIOS, for which the loader is empty.
 func (p CreateMachineParams) Loader() string {
-	return nvramCode
+	if p.Arch() == arch.ARM64 {
+		return nvramCode
+	}
+	if p.Firmware == UEFIFirmware {
+		return ovmfCode
+	}
+	return ""
 }
 
 // Host implements libvirt.domainParams.
@@ -173,6 +200,12 @@ func CreateMachine(params CreateMachineParams) error {
 
 	setDefaults(&params)
 
+	if params.StoragePool != "" {
+		if err := checkStoragePool(params.runCmdAsRoot, params.StoragePool); err != nil {
+			return errors.Trace(err)
+		}
+	}
+
 	templateDir := filepath.Dir(params.UserDataFile)
 
 	err := writeMetadata(templateDir)
@@ -184,6 +217,11 @@ func CreateMachine(params CreateMachineParams) error {
 	if err != nil {
 		return errors.Annotatef(err, "failed to write data source volume for %q", params.Host())
 	}
+	if params.StoragePool != "" {
+		if dsPath, err = writePoolDataSourceVolume(params, dsPath); err != nil {
+			return errors.Annotatef(err, "failed to write data source volume for %q", params.Host())
+		}
+	}
 
 	imgPath, err := writeRootDisk(params)
 	if err != nil {
@@ -231,6 +269,9 @@ func DestroyMachine(c *kvmContainer) error {
 	if c.runCmd == nil {
 		c.runCmd = run
 	}
+	if c.runCmdAsRoot == nil {
+		c.runCmdAsRoot = run
+	}
 	if c.pathfinder == nil {
 		c.pathfinder = paths.DataDir
 	}
@@ -247,6 +288,18 @@ func DestroyMachine(c *kvmContainer) error {
 		logger.Infof("`%s destroy %s` failed: %q", virsh, c.Name(), err)
 	}
 
+	// Disks allocated from storage pools other than the juju pool are
+	// found before the domain is undefined, so that they can be removed
+	// afterwards.
+	guestBase, err := guestPath(c.pathfinder)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	poolDisks, err := domainDisks(c.runCmdAsRoot, c.Name())
+	if err != nil {
+		logger.Infof("listing disks of %s failed: %q", c.Name(), err)
+	}
+
 	// The nvram flag here removes the pflash drive for us. There is also a
 	// `remove-all-storage` flag, but it is unclear if that would also remove
 	// the backing store which we don't want to do. So we remove those manually
@@ -255,16 +308,20 @@ func DestroyMachine(c *kvmContainer) error {
 	if err != nil {
 		logger.Infof("`%s undefine --nvram %s` failed: %q", virsh, c.Name(), err)
 	}
-	guestBase, err := guestPath(c.pathfinder)
-	if err != nil {
-		return errors.Trace(err)
+	for _, disk := range poolDisks {
+		if strings.HasPrefix(disk, guestBase+string(filepath.Separator)) {
+			continue
+		}
+		if _, err := c.runCmdAsRoot("", virsh, "vol-delete", disk); err != nil {
+			logger.Errorf("failed to remove disk %q for %q: %s", disk, c.Name(), err)
+		}
 	}
 	err = os.Remove(filepath.Join(guestBase, fmt.Sprintf("%s.qcow", c.Name())))
-	if err != nil {
+	if err != nil && !os.IsNotExist(err) {
 		logger.Errorf("failed to remove system disk for %q: %s", c.Name(), err)
 	}
 	err = os.Remove(filepath.Join(guestBase, fmt.Sprintf("%s-ds.iso", c.Name())))
-	if err != nil {
+	if err != nil && !os.IsNotExist(err) {
 		logger.Errorf("failed to remove cloud-init data disk for %q: %s", c.Name(), err)
 	}
 
@@ -458,7 +515,11 @@ func writeRootDisk(params CreateMachineParams) (string, error) {
 	imgPath := filepath.Join(guestBase, fmt.Sprintf("%s.qcow", params.Host()))
 	backingPath := filepath.Join(
 		guestBase,
-		backingFileName(params.Series, params.Arch()))
+		backingFileName(params.Series, params.Arch(), imageFType(params.Arch(), params.Firmware)))
+
+	if params.StoragePool != "" {
+		return writePoolRootDisk(params, backingPath)
+	}
 
 	out, err := params.runCmd(
 		"",
@@ -476,11 +537,110 @@ func writeRootDisk(params CreateMachineParams) (string, error) {
 	return imgPath, nil
 }
 
+// writePoolRootDisk allocates the root disk for the container from the
+// storage pool named in the params, backed by the shared series/arch
+// backing store. It returns the path of the allocated volume.
+func writePoolRootDisk(params CreateMachineParams, backingPath string) (string, error) {
+	volName := fmt.Sprintf("%s.qcow", params.Host())
+	out, err := params.runCmdAsRoot(
+		"",
+		virsh,
+		"vol-create-as",
+		params.StoragePool,
+		volName,
+		fmt.Sprintf("%dG", params.RootDisk),
+		"--format", "qcow2",
+		"--backing-vol", backingPath,
+		"--backing-vol-format", "qcow2")
+	logger.Debugf("create root volume: %s", out)
+	if err != nil {
+		return "", errors.Annotatef(err, "creating volume in storage pool %q", params.StoragePool)
+	}
+	return poolVolumePath(params, volName)
+}
+
+// writePoolDataSourceVolume moves the data source volume at dsPath into
+// the storage pool named in the params. It returns the path of the
+// allocated volume.
+func writePoolDataSourceVolume(params CreateMachineParams, dsPath string) (string, error) {
+	info, err := os.Stat(dsPath)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	volName := filepath.Base(dsPath)
+	out, err := params.runCmdAsRoot(
+		"",
+		virsh,
+		"vol-create-as",
+		params.StoragePool,
+		volName,
+		fmt.Sprint(info.Size()),
+		"--format", "raw")
+	logger.Debugf("create data source volume: %s", out)
+	if err != nil {
+		return "", errors.Annotatef(err, "creating volume in storage pool %q", params.StoragePool)
+	}
+	out, err = params.runCmdAsRoot("", virsh, "vol-upload", "--pool", params.StoragePool, volName, dsPath)
+	logger.Debugf("upload data source volume: %s", out)
+	if err != nil {
+		return "", errors.Annotatef(err, "uploading volume %q to storage pool %q", volName, params.StoragePool)
+	}
+	if err := os.Remove(dsPath); err != nil {
+		logger.Warningf("failed to remove %q: %v", dsPath, err)
+	}
+	return poolVolumePath(params, volName)
+}
+
+// poolVolumePath returns the path of the named volume in the storage
+// pool named in the params.
+func poolVolumePath(params CreateMachineParams, volName string) (string, error) {
+	out, err := params.runCmdAsRoot("", virsh, "vol-path", "--pool", params.StoragePool, volName)
+	if err != nil {
+		return "", errors.Annotatef(err, "finding volume %q in storage pool %q", volName, params.StoragePool)
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// checkStoragePool returns an error if the named storage pool does not
+// exist or is not running.
+func checkStoragePool(runCmd runFunc, name string) error {
+	pool, err := poolInfo(runCmd, name)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if pool == nil {
+		return errors.NotFoundf("storage pool %q", name)
+	}
+	if pool.State != "running" {
+		return errors.Errorf("storage pool %q is not running", name)
+	}
+	return nil
+}
+
+// domainDisks returns the sources of the file backed disks of the named
+// domain, as reported by `virsh domblklist`.
+func domainDisks(runCmd runFunc, name string) ([]string, error) {
+	output, err := runCmd("", virsh, "domblklist", "--details", name)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	// The output is a table of the type, device, target and source of
+	// each block device, after a header and a separator line.
+	var disks []string
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 4 && fields[0] == "file" && fields[1] == "disk" {
+			disks = append(disks, fields[3])
+		}
+	}
+	return disks, nil
+}
+
 // pool info parses and returns the output of `virsh pool-info <poolname>`.
-func poolInfo(runCmd runFunc) (*libvirtPool, error) {
-	output, err := runCmd("", virsh, "pool-info", poolName)
+func poolInfo(runCmd runFunc, name string) (*libvirtPool, error) {
+	output, err := runCmd("", virsh, "pool-info", name)
 	if err != nil {
-		logger.Debugf("pool %q doesn't appear to exist: %s", poolName, err)
+		logger.Debugf("pool %q doesn't appear to exist: %s", name, err)
 		return nil, nil
 	}
 
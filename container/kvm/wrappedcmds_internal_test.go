@@ -147,7 +147,7 @@ Allocation:     3.54 GiB
 Available:      31.77 GiB
 `
 	stub := runStub{output: output}
-	got, err := poolInfo(stub.Run)
+	got, err := poolInfo(stub.Run, poolName)
 	c.Check(err, jc.ErrorIsNil)
 	c.Assert(got, jc.DeepEquals, &libvirtPool{Name: "juju-pool", Autostart: "yes", State: "running"})
 
@@ -155,7 +155,7 @@ Available:      31.77 GiB
 
 func (libvirtInternalSuite) TestPoolInfoNoPool(c *gc.C) {
 	stub := runStub{err: errors.New("boom")}
-	got, err := poolInfo(stub.Run)
+	got, err := poolInfo(stub.Run, poolName)
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(got, gc.IsNil)
 }
@@ -4,6 +4,7 @@
 package kvm_test
 
 import (
+	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
@@ -156,6 +157,88 @@ func (commandWrapperSuite) TestCreateMachineSuccess(c *gc.C) {
 	}
 }
 
+func (commandWrapperSuite) TestCreateMachineStoragePoolUEFI(c *gc.C) {
+	poolInfo := `
+Name:           fast-pool
+State:          running
+Autostart:      yes
+`
+	stub := NewRunStubWithOutputs(
+		poolInfo, "success",
+		"success", "success", "/srv/fast-pool/host00-ds.iso\n",
+		"success", "/srv/fast-pool/host00.qcow\n",
+		"success",
+	)
+
+	tmpDir, err := ioutil.TempDir("", "juju-libvirtSuite-")
+	c.Check(err, jc.ErrorIsNil)
+	defer os.RemoveAll(tmpDir)
+	err = os.MkdirAll(filepath.Join(tmpDir, "kvm", "guests"), 0755)
+	c.Check(err, jc.ErrorIsNil)
+	cloudInitPath := filepath.Join(tmpDir, "cloud-init")
+	err = ioutil.WriteFile(cloudInitPath, []byte("#cloud-init\nEOF\n"), 0755)
+	c.Assert(err, jc.ErrorIsNil)
+	// The stubbed genisoimage doesn't write the data source volume.
+	dsPath := filepath.Join(tmpDir, "kvm", "guests", "host00-ds.iso")
+	err = ioutil.WriteFile(dsPath, []byte("cidata"), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+
+	pathfinder := func(s string) (string, error) {
+		return tmpDir, nil
+	}
+
+	params := CreateMachineParams{
+		Hostname:     "host00",
+		Series:       "focal",
+		UserDataFile: cloudInitPath,
+		CpuCores:     1,
+		RootDisk:     8,
+		StoragePool:  "fast-pool",
+		Firmware:     UEFIFirmware,
+	}
+
+	MakeCreateMachineParamsTestable(&params, pathfinder, stub.Run, "amd64")
+	c.Check(params.Loader(), gc.Equals, "/usr/share/OVMF/OVMF_CODE.fd")
+	err = CreateMachine(params)
+	c.Assert(err, jc.ErrorIsNil)
+
+	want := []string{
+		` virsh pool-info fast-pool`,
+		tmpDir + ` genisoimage .*`,
+		` virsh vol-create-as fast-pool host00-ds.iso 6 --format raw`,
+		` virsh vol-upload --pool fast-pool host00-ds.iso \/tmp/juju-libvirtSuite-\d+\/kvm\/guests\/host00-ds.iso`,
+		` virsh vol-path --pool fast-pool host00-ds.iso`,
+		` virsh vol-create-as fast-pool host00.qcow 8G --format qcow2 --backing-vol \/tmp/juju-libvirtSuite-\d+\/kvm\/guests\/focal-amd64-uefi-backing-file.qcow --backing-vol-format qcow2`,
+		` virsh vol-path --pool fast-pool host00.qcow`,
+		` virsh define \/tmp\/juju-libvirtSuite-\d+\/host00.xml`,
+		" virsh start host00",
+	}
+	c.Assert(stub.Calls(), gc.HasLen, len(want))
+	for i, cmd := range stub.Calls() {
+		c.Check(cmd, gc.Matches, want[i])
+	}
+
+	domain, err := ioutil.ReadFile(filepath.Join(tmpDir, "host00.xml"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(string(domain), jc.Contains, `<source file="/srv/fast-pool/host00.qcow">`)
+	c.Check(string(domain), jc.Contains, `<source file="/srv/fast-pool/host00-ds.iso">`)
+	_, err = os.Stat(dsPath)
+	c.Check(os.IsNotExist(err), jc.IsTrue)
+	c.Check(string(domain), jc.Contains, `<loader readonly="yes" type="pflash">/usr/share/OVMF/OVMF_CODE.fd</loader>`)
+}
+
+func (commandWrapperSuite) TestCreateMachineStoragePoolNotFound(c *gc.C) {
+	stub := NewRunStub("", errors.New("boom"))
+	params := CreateMachineParams{
+		Hostname:    "host00",
+		StoragePool: "missing",
+	}
+	MakeCreateMachineParamsTestable(&params, nil, stub.Run, "amd64")
+	err := CreateMachine(params)
+	c.Assert(err, gc.ErrorMatches, `storage pool "missing" not found`)
+	c.Assert(stub.Calls(), jc.DeepEquals, []string{" virsh pool-info missing"})
+}
+
 func (commandWrapperSuite) TestDestroyMachineSuccess(c *gc.C) {
 	tmpDir, err := ioutil.TempDir("", "juju-libvirtSuite-")
 	c.Check(err, jc.ErrorIsNil)
@@ -178,8 +261,40 @@ func (commandWrapperSuite) TestDestroyMachineSuccess(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(stub.Calls(), jc.DeepEquals, []string{
 		" virsh destroy aname",
+		" virsh domblklist --details aname",
+		" virsh undefine --nvram aname",
+	})
+}
+
+func (commandWrapperSuite) TestDestroyMachineRemovesPoolDisks(c *gc.C) {
+	tmpDir, err := ioutil.TempDir("", "juju-libvirtSuite-")
+	c.Check(err, jc.ErrorIsNil)
+	defer os.RemoveAll(tmpDir)
+	guestBase := filepath.Join(tmpDir, "kvm", "guests")
+
+	pathfinder := func(_ string) (string, error) {
+		return tmpDir, nil
+	}
+
+	output := fmt.Sprintf(`
+ Type   Device   Target   Source
+------------------------------------------------
+ file   disk     vda      /srv/fast-pool/aname.qcow
+ file   disk     vdb      /srv/fast-pool/aname-ds.iso
+ file   disk     vdc      %s/aname-extra.qcow
+`, guestBase)
+	stub := NewRunStub(output, nil)
+	container := NewTestContainer("aname", stub.Run, pathfinder)
+	err = DestroyMachine(container)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(stub.Calls(), jc.DeepEquals, []string{
+		" virsh destroy aname",
+		" virsh domblklist --details aname",
 		" virsh undefine --nvram aname",
+		" virsh vol-delete /srv/fast-pool/aname.qcow",
+		" virsh vol-delete /srv/fast-pool/aname-ds.iso",
 	})
+	c.Check(c.GetTestLog(), gc.Not(jc.Contains), "failed to remove cloud-init data disk")
 }
 
 func (commandWrapperSuite) TestDestroyMachineFails(c *gc.C) {
@@ -188,6 +303,7 @@ func (commandWrapperSuite) TestDestroyMachineFails(c *gc.C) {
 	err := DestroyMachine(container)
 	c.Check(stub.Calls(), jc.DeepEquals, []string{
 		" virsh destroy aname",
+		" virsh domblklist --details aname",
 		" virsh undefine --nvram aname",
 	})
 	log := c.GetTestLog()
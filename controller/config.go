@@ -4,9 +4,12 @@
 package controller
 
 import (
+	"crypto/x509"
+	"encoding/base64"
 	"fmt"
 	"net/url"
 	"regexp"
+	"strings"
 	"time"
 
 	"github.com/juju/charmrepo/v5/csclient"
@@ -108,6 +111,95 @@ const (
 	// IdentityPublicKey sets the public key of the identity manager.
 	IdentityPublicKey = "identity-public-key"
 
+	// OIDCIssuerURL sets the URL of an OpenID Connect provider whose
+	// ID tokens users may log in with. It may not be used together
+	// with IdentityURL.
+	OIDCIssuerURL = "oidc-issuer-url"
+
+	// OIDCClientID is the client ID registered for Juju with the
+	// OpenID Connect provider. ID tokens must be issued to it.
+	OIDCClientID = "oidc-client-id"
+
+	// OIDCUsernameClaim is the ID token claim holding the Juju user
+	// name of the logged in user.
+	OIDCUsernameClaim = "oidc-username-claim"
+
+	// OIDCGroupsClaim is the ID token claim holding the groups the
+	// logged in user is a member of.
+	OIDCGroupsClaim = "oidc-groups-claim"
+
+	// OIDCGroupAccess is a list of "group=access" entries mapping
+	// provider groups to the controller access granted to their
+	// members when they first log in.
+	OIDCGroupAccess = "oidc-group-access"
+
+	// LDAPURL sets the ldap:// or ldaps:// URL of an LDAP directory
+	// from which users' group memberships are read, so that access
+	// granted to groups applies to their members.
+	LDAPURL = "ldap-url"
+
+	// LDAPBindDN is the distinguished name used to search the LDAP
+	// directory. If it is not set, searches are anonymous.
+	LDAPBindDN = "ldap-bind-dn"
+
+	// LDAPBindPassword is the password for LDAPBindDN. It is only
+	// sent over ldap:// URLs when LDAPStartTLS is set.
+	LDAPBindPassword = "ldap-bind-password"
+
+	// LDAPStartTLS, if true, upgrades connections to ldap:// URLs to
+	// TLS before binding.
+	LDAPStartTLS = "ldap-start-tls"
+
+	// LDAPUserDNTemplate is the distinguished name of users' LDAP
+	// entries, with "{user}" standing in for the Juju user name.
+	LDAPUserDNTemplate = "ldap-user-dn-template"
+
+	// LDAPGroupBaseDN is the distinguished name beneath which
+	// groups are found in the LDAP directory.
+	LDAPGroupBaseDN = "ldap-group-base-dn"
+
+	// LDAPMemberAttribute is the group attribute holding the
+	// distinguished names of the group's members.
+	LDAPMemberAttribute = "ldap-member-attribute"
+
+	// LDAPGroupNameAttribute is the group attribute holding the
+	// name of the group.
+	LDAPGroupNameAttribute = "ldap-group-name-attribute"
+
+	// LDAPUserDomain is the domain of the Juju users whose groups are
+	// looked up in the LDAP directory. If it is not set, the groups of
+	// local users are looked up.
+	LDAPUserDomain = "ldap-user-domain"
+
+	// PasswordMinLength is the minimum number of characters in the
+	// passwords of local users. Zero means no minimum.
+	PasswordMinLength = "password-min-length"
+
+	// PasswordMinCharClasses is the minimum number of character
+	// classes (lower case, upper case, digits and other characters)
+	// that the passwords of local users must contain.
+	PasswordMinCharClasses = "password-min-character-classes"
+
+	// PasswordMaxAge is how long the password of a local user may be
+	// used after it is set, or after the setting is enabled for older
+	// passwords. Users with expired passwords may log in only to change
+	// them. Zero means passwords do not expire.
+	PasswordMaxAge = "password-max-age"
+
+	// LoginMaxFailedAttempts is the number of consecutive failed
+	// password logins after which a local user is locked out. Zero
+	// means users are never locked out.
+	LoginMaxFailedAttempts = "login-max-failed-attempts"
+
+	// LoginLockoutDuration is how long a local user is locked out
+	// for after LoginMaxFailedAttempts consecutive failed logins.
+	// The lockout doubles with each further failed login.
+	LoginLockoutDuration = "login-lockout-duration"
+
+	// LoginMaxLockoutDuration is the longest a local user is locked
+	// out for.
+	LoginMaxLockoutDuration = "login-max-lockout-duration"
+
 	// SetNUMAControlPolicyKey stores the value for this setting
 	SetNUMAControlPolicyKey = "set-numa-control-policy"
 
@@ -124,11 +216,49 @@ const (
 	// "https://acme-staging.api.letsencrypt.org/directory".
 	AutocertURLKey = "autocert-url"
 
+	// AutocertCACertKey holds the PEM encoded certificates of the CAs
+	// trusted when connecting to the ACME server at AutocertURLKey,
+	// for internal ACME servers that do not have a publicly trusted
+	// certificate.
+	AutocertCACertKey = "autocert-ca-cert"
+
+	// AutocertEABKeyIDKey is the key identifier of the external
+	// account binding used when registering with the ACME server.
+	// Internal ACME servers commonly require accounts to be bound to
+	// an account that they already know about.
+	AutocertEABKeyIDKey = "autocert-eab-kid"
+
+	// AutocertEABHMACKeyKey is the base64url encoded MAC key of the
+	// external account binding used when registering with the ACME
+	// server.
+	AutocertEABHMACKeyKey = "autocert-eab-hmac-key"
+
+	// AutocertChallengeKey sets the type of ACME challenge used to
+	// prove control of the controller's DNS name, either
+	// "tls-alpn-01" (the default), which requires the ACME server to
+	// be able to connect to the API port, or "dns-01", which publishes
+	// a DNS record using AutocertDNSProviderKey.
+	AutocertChallengeKey = "autocert-challenge"
+
+	// AutocertDNSProviderKey names the DNS provider used to publish
+	// the records for dns-01 challenges.
+	AutocertDNSProviderKey = "autocert-dns-provider"
+
+	// AutocertDNSConfigKey holds the configuration of the DNS
+	// provider, as a list of "key=value" entries. These commonly
+	// include credentials for the DNS service.
+	AutocertDNSConfigKey = "autocert-dns-provider-config"
+
 	// AllowModelAccessKey sets whether the controller will allow users to
 	// connect to models they have been authorized for even when
 	// they don't have any access rights to the controller itself.
 	AllowModelAccessKey = "allow-model-access"
 
+	// RESTGatewayKey sets whether the controller serves the REST
+	// gateway, which exposes selected client facade methods over
+	// plain HTTP requests alongside the websocket API.
+	RESTGatewayKey = "rest-gateway"
+
 	// MongoMemoryProfile sets whether mongo uses the least possible memory or the
 	// detault
 	MongoMemoryProfile = "mongo-memory-profile"
@@ -284,6 +414,33 @@ const (
 	// state data that agents can store to the controller.
 	DefaultMaxAgentStateSize = 512 * 1024
 
+	// DefaultAutocertChallenge is the default type of ACME challenge
+	// used to prove control of the controller's DNS name.
+	DefaultAutocertChallenge = "tls-alpn-01"
+
+	// DefaultOIDCUsernameClaim is the default ID token claim holding
+	// the user name.
+	DefaultOIDCUsernameClaim = "email"
+
+	// DefaultOIDCGroupsClaim is the default ID token claim holding the
+	// user's groups.
+	DefaultOIDCGroupsClaim = "groups"
+
+	// DefaultLDAPMemberAttribute is the default group attribute
+	// holding the group's members.
+	DefaultLDAPMemberAttribute = "member"
+
+	// DefaultLDAPGroupNameAttribute is the default group attribute
+	// holding the name of the group.
+	DefaultLDAPGroupNameAttribute = "cn"
+
+	// DefaultLoginLockoutDuration is the default lockout after the
+	// maximum number of failed logins.
+	DefaultLoginLockoutDuration = time.Minute
+
+	// DefaultLoginMaxLockoutDuration is the default longest lockout.
+	DefaultLoginMaxLockoutDuration = 24 * time.Hour
+
 	// JujuHASpace is the network space within which the MongoDB replica-set
 	// should communicate.
 	JujuHASpace = "juju-ha-space"
@@ -313,12 +470,19 @@ var (
 	// for a controller, never a model.
 	ControllerOnlyConfigAttributes = []string{
 		AllowModelAccessKey,
+		RESTGatewayKey,
 		AgentRateLimitMax,
 		AgentRateLimitRate,
 		APIPort,
 		APIPortOpenDelay,
 		AutocertDNSNameKey,
 		AutocertURLKey,
+		AutocertCACertKey,
+		AutocertEABKeyIDKey,
+		AutocertEABHMACKeyKey,
+		AutocertChallengeKey,
+		AutocertDNSProviderKey,
+		AutocertDNSConfigKey,
 		CACertKey,
 		CharmStoreURL,
 		ControllerAPIPort,
@@ -326,6 +490,26 @@ var (
 		ControllerUUIDKey,
 		IdentityPublicKey,
 		IdentityURL,
+		OIDCIssuerURL,
+		OIDCClientID,
+		OIDCUsernameClaim,
+		OIDCGroupsClaim,
+		OIDCGroupAccess,
+		LDAPURL,
+		LDAPBindDN,
+		LDAPBindPassword,
+		LDAPStartTLS,
+		LDAPUserDNTemplate,
+		LDAPGroupBaseDN,
+		LDAPMemberAttribute,
+		LDAPGroupNameAttribute,
+		LDAPUserDomain,
+		PasswordMinLength,
+		PasswordMinCharClasses,
+		PasswordMaxAge,
+		LoginMaxFailedAttempts,
+		LoginLockoutDuration,
+		LoginMaxLockoutDuration,
 		SetNUMAControlPolicyKey,
 		StatePort,
 		MongoMemoryProfile,
@@ -396,6 +580,27 @@ var (
 		Features,
 		MaxCharmStateSize,
 		MaxAgentStateSize,
+		OIDCIssuerURL,
+		OIDCClientID,
+		OIDCUsernameClaim,
+		OIDCGroupsClaim,
+		OIDCGroupAccess,
+		LDAPURL,
+		LDAPBindDN,
+		LDAPBindPassword,
+		LDAPStartTLS,
+		LDAPUserDNTemplate,
+		LDAPGroupBaseDN,
+		LDAPMemberAttribute,
+		LDAPGroupNameAttribute,
+		LDAPUserDomain,
+		PasswordMinLength,
+		PasswordMinCharClasses,
+		PasswordMaxAge,
+		LoginMaxFailedAttempts,
+		LoginLockoutDuration,
+		LoginMaxLockoutDuration,
+		RESTGatewayKey,
 	)
 
 	// DefaultAuditLogExcludeMethods is the default list of methods to
@@ -406,6 +611,15 @@ var (
 		ReadOnlyMethodsWildcard,
 	}
 
+	// secretAttributes holds the controller config attributes that
+	// are only read by the controller itself, and are never handed
+	// out over the API.
+	secretAttributes = set.NewStrings(
+		LDAPBindPassword,
+		AutocertEABHMACKeyKey,
+		AutocertDNSConfigKey,
+	)
+
 	methodNameRE = regexp.MustCompile(`[[:alpha:]][[:alnum:]]*\.[[:alpha:]][[:alnum:]]*`)
 )
 
@@ -420,9 +634,27 @@ func ControllerOnlyAttribute(attr string) bool {
 	return false
 }
 
+// IsSecretAttribute returns true if the specified attribute name holds
+// a secret that must not be handed out over the API.
+func IsSecretAttribute(attr string) bool {
+	return secretAttributes.Contains(attr)
+}
+
 // Config is a string-keyed map of controller configuration attributes.
 type Config map[string]interface{}
 
+// WithoutSecrets returns a copy of the config without the attributes
+// holding secrets.
+func (c Config) WithoutSecrets() Config {
+	result := make(Config, len(c))
+	for name, value := range c {
+		if !IsSecretAttribute(name) {
+			result[name] = value
+		}
+	}
+	return result
+}
+
 // Validate validates the controller configuration.
 func (c Config) Validate() error {
 	return Validate(c)
@@ -672,6 +904,53 @@ func (c Config) AutocertDNSName() string {
 	return c.asString(AutocertDNSNameKey)
 }
 
+// AutocertCACert returns the PEM encoded certificates of the CAs
+// trusted when connecting to the ACME server.
+func (c Config) AutocertCACert() string {
+	return c.asString(AutocertCACertKey)
+}
+
+// AutocertEABKeyID returns the key identifier of the external account
+// binding used to register with the ACME server.
+func (c Config) AutocertEABKeyID() string {
+	return c.asString(AutocertEABKeyIDKey)
+}
+
+// AutocertEABHMACKey returns the MAC key of the external account
+// binding used to register with the ACME server.
+func (c Config) AutocertEABHMACKey() []byte {
+	// The key is checked by Validate.
+	key, _ := base64.RawURLEncoding.DecodeString(strings.TrimRight(c.asString(AutocertEABHMACKeyKey), "="))
+	return key
+}
+
+// AutocertChallenge returns the type of ACME challenge used to prove
+// control of the controller's DNS name.
+func (c Config) AutocertChallenge() string {
+	if v := c.asString(AutocertChallengeKey); v != "" {
+		return v
+	}
+	return DefaultAutocertChallenge
+}
+
+// AutocertDNSProvider returns the name of the DNS provider used for
+// dns-01 challenges.
+func (c Config) AutocertDNSProvider() string {
+	return c.asString(AutocertDNSProviderKey)
+}
+
+// AutocertDNSProviderConfig returns the "key=value" configuration
+// entries of the DNS provider used for dns-01 challenges.
+func (c Config) AutocertDNSProviderConfig() []string {
+	var result []string
+	if value, ok := c[AutocertDNSConfigKey]; ok {
+		for _, entry := range value.([]interface{}) {
+			result = append(result, entry.(string))
+		}
+	}
+	return result
+}
+
 // IdentityPublicKey returns the public key of the identity manager.
 func (c Config) IdentityPublicKey() *bakery.PublicKey {
 	key := c.asString(IdentityPublicKey)
@@ -688,6 +967,146 @@ func (c Config) IdentityPublicKey() *bakery.PublicKey {
 	return &pubKey
 }
 
+// OIDCIssuerURL returns the URL of the OpenID Connect provider users
+// may log in with, or "" if OpenID Connect login is not enabled.
+func (c Config) OIDCIssuerURL() string {
+	return c.asString(OIDCIssuerURL)
+}
+
+// OIDCClientID returns the client ID registered for Juju with the
+// OpenID Connect provider.
+func (c Config) OIDCClientID() string {
+	return c.asString(OIDCClientID)
+}
+
+// OIDCUsernameClaim returns the ID token claim holding the user name.
+func (c Config) OIDCUsernameClaim() string {
+	if v := c.asString(OIDCUsernameClaim); v != "" {
+		return v
+	}
+	return DefaultOIDCUsernameClaim
+}
+
+// OIDCGroupsClaim returns the ID token claim holding the user's groups.
+func (c Config) OIDCGroupsClaim() string {
+	if v := c.asString(OIDCGroupsClaim); v != "" {
+		return v
+	}
+	return DefaultOIDCGroupsClaim
+}
+
+// OIDCGroupAccess returns the controller access level granted to
+// members of each OpenID Connect provider group.
+func (c Config) OIDCGroupAccess() map[string]string {
+	result := make(map[string]string)
+	if value, ok := c[OIDCGroupAccess]; ok {
+		for _, entry := range value.([]interface{}) {
+			if parts := strings.SplitN(entry.(string), "=", 2); len(parts) == 2 {
+				result[parts[0]] = parts[1]
+			}
+		}
+	}
+	return result
+}
+
+// LDAPURL returns the URL of the LDAP directory holding users' group
+// memberships, or "" if group memberships are not looked up.
+func (c Config) LDAPURL() string {
+	return c.asString(LDAPURL)
+}
+
+// LDAPBindDN returns the distinguished name used to search the LDAP
+// directory.
+func (c Config) LDAPBindDN() string {
+	return c.asString(LDAPBindDN)
+}
+
+// LDAPBindPassword returns the password used to search the LDAP
+// directory.
+func (c Config) LDAPBindPassword() string {
+	return c.asString(LDAPBindPassword)
+}
+
+// LDAPStartTLS returns whether connections to an ldap:// URL are
+// upgraded to TLS before binding.
+func (c Config) LDAPStartTLS() bool {
+	v, _ := c[LDAPStartTLS].(bool)
+	return v
+}
+
+// LDAPUserDNTemplate returns the template for the distinguished names
+// of users' LDAP entries.
+func (c Config) LDAPUserDNTemplate() string {
+	return c.asString(LDAPUserDNTemplate)
+}
+
+// LDAPGroupBaseDN returns the distinguished name beneath which groups
+// are found.
+func (c Config) LDAPGroupBaseDN() string {
+	return c.asString(LDAPGroupBaseDN)
+}
+
+// LDAPMemberAttribute returns the group attribute holding the
+// group's members.
+func (c Config) LDAPMemberAttribute() string {
+	if v := c.asString(LDAPMemberAttribute); v != "" {
+		return v
+	}
+	return DefaultLDAPMemberAttribute
+}
+
+// LDAPGroupNameAttribute returns the group attribute holding the name
+// of the group.
+func (c Config) LDAPGroupNameAttribute() string {
+	if v := c.asString(LDAPGroupNameAttribute); v != "" {
+		return v
+	}
+	return DefaultLDAPGroupNameAttribute
+}
+
+// LDAPUserDomain returns the domain of the users whose groups are
+// looked up in the LDAP directory; "" means local users.
+func (c Config) LDAPUserDomain() string {
+	return c.asString(LDAPUserDomain)
+}
+
+// PasswordMinLength returns the minimum length of local users'
+// passwords.
+func (c Config) PasswordMinLength() int {
+	return c.intOrDefault(PasswordMinLength, 0)
+}
+
+// PasswordMinCharClasses returns the minimum number of character
+// classes in local users' passwords.
+func (c Config) PasswordMinCharClasses() int {
+	return c.intOrDefault(PasswordMinCharClasses, 0)
+}
+
+// PasswordMaxAge returns how long local users' passwords may be used
+// after they are set, or zero if they do not expire.
+func (c Config) PasswordMaxAge() time.Duration {
+	return c.durationOrDefault(PasswordMaxAge, 0)
+}
+
+// LoginMaxFailedAttempts returns the number of consecutive failed
+// logins after which a local user is locked out, or zero if users are
+// never locked out.
+func (c Config) LoginMaxFailedAttempts() int {
+	return c.intOrDefault(LoginMaxFailedAttempts, 0)
+}
+
+// LoginLockoutDuration returns how long a local user is first locked
+// out for.
+func (c Config) LoginLockoutDuration() time.Duration {
+	return c.durationOrDefault(LoginLockoutDuration, DefaultLoginLockoutDuration)
+}
+
+// LoginMaxLockoutDuration returns the longest a local user is locked
+// out for.
+func (c Config) LoginMaxLockoutDuration() time.Duration {
+	return c.durationOrDefault(LoginMaxLockoutDuration, DefaultLoginMaxLockoutDuration)
+}
+
 // MongoMemoryProfile returns the selected profile or low.
 func (c Config) MongoMemoryProfile() string {
 	if profile, ok := c[MongoMemoryProfile]; ok {
@@ -717,6 +1136,13 @@ func (c Config) AllowModelAccess() bool {
 	return value
 }
 
+// RESTGatewayEnabled reports whether the controller serves the REST
+// gateway over the client facades.
+func (c Config) RESTGatewayEnabled() bool {
+	value, _ := c[RESTGatewayKey].(bool)
+	return value
+}
+
 // ModelLogfileMaxBackups is the number of old model log files to keep (compressed).
 func (c Config) ModelLogfileMaxBackups() int {
 	return c.intOrDefault(ModelLogfileMaxBackups, DefaultModelLogfileMaxBackups)
@@ -847,6 +1273,22 @@ func Validate(c Config) error {
 		}
 	}
 
+	if err := c.validateOIDCConfig(); err != nil {
+		return errors.Trace(err)
+	}
+
+	if err := c.validateLDAPConfig(); err != nil {
+		return errors.Trace(err)
+	}
+
+	if err := c.validateAutocertConfig(); err != nil {
+		return errors.Trace(err)
+	}
+
+	if err := c.validatePasswordPolicy(); err != nil {
+		return errors.Trace(err)
+	}
+
 	caCert, caCertOK := c.CACert()
 	if !caCertOK {
 		return errors.Errorf("missing CA certificate")
@@ -1030,6 +1472,132 @@ func Validate(c Config) error {
 	return nil
 }
 
+// oidcControllerAccess holds the controller access levels which may be
+// granted to OpenID Connect provider groups.
+var oidcControllerAccess = set.NewStrings("login", "add-model", "superuser")
+
+func (c Config) validateOIDCConfig() error {
+	issuer, _ := c[OIDCIssuerURL].(string)
+	if issuer == "" {
+		return nil
+	}
+	u, err := url.Parse(issuer)
+	if err != nil {
+		return errors.Annotate(err, "invalid OIDC issuer URL")
+	}
+	if u.Scheme != "https" {
+		return errors.Errorf("%s must be an https URL", OIDCIssuerURL)
+	}
+	if v, _ := c[IdentityURL].(string); v != "" {
+		return errors.Errorf("%s and %s cannot both be set", OIDCIssuerURL, IdentityURL)
+	}
+	if v, _ := c[OIDCClientID].(string); v == "" {
+		return errors.Errorf("%s must be set when %s is set", OIDCClientID, OIDCIssuerURL)
+	}
+	if v, ok := c[OIDCGroupAccess].([]interface{}); ok {
+		for i, entry := range v {
+			parts := strings.SplitN(entry.(string), "=", 2)
+			if len(parts) != 2 || parts[0] == "" || !oidcControllerAccess.Contains(parts[1]) {
+				return errors.Errorf(
+					`invalid %s: should be a list of "group=access" entries, where access is one of %s, got %q at position %d`,
+					OIDCGroupAccess,
+					strings.Join(oidcControllerAccess.SortedValues(), ", "),
+					entry,
+					i+1,
+				)
+			}
+		}
+	}
+	return nil
+}
+
+// autocertChallenges holds the supported ACME challenge types.
+var autocertChallenges = set.NewStrings("tls-alpn-01", "dns-01")
+
+func (c Config) validateAutocertConfig() error {
+	if caCert, _ := c[AutocertCACertKey].(string); caCert != "" {
+		if !x509.NewCertPool().AppendCertsFromPEM([]byte(caCert)) {
+			return errors.Errorf("%s must hold PEM encoded certificates", AutocertCACertKey)
+		}
+	}
+	kid, _ := c[AutocertEABKeyIDKey].(string)
+	hmacKey, _ := c[AutocertEABHMACKeyKey].(string)
+	if (kid == "") != (hmacKey == "") {
+		return errors.Errorf("%s and %s must be set together", AutocertEABKeyIDKey, AutocertEABHMACKeyKey)
+	}
+	if hmacKey != "" {
+		if _, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(hmacKey, "=")); err != nil {
+			return errors.Errorf("%s must be base64url encoded", AutocertEABHMACKeyKey)
+		}
+	}
+	challenge := c.AutocertChallenge()
+	if !autocertChallenges.Contains(challenge) {
+		return errors.Errorf("%s must be one of %s, got %q",
+			AutocertChallengeKey, strings.Join(autocertChallenges.SortedValues(), ", "), challenge)
+	}
+	if challenge == "dns-01" && c.AutocertDNSProvider() == "" {
+		return errors.Errorf("%s must be set when %s is dns-01", AutocertDNSProviderKey, AutocertChallengeKey)
+	}
+	for i, entry := range c.AutocertDNSProviderConfig() {
+		if parts := strings.SplitN(entry, "=", 2); len(parts) != 2 || parts[0] == "" {
+			return errors.Errorf(`invalid %s: should be a list of "key=value" entries, got %q at position %d`,
+				AutocertDNSConfigKey, entry, i+1)
+		}
+	}
+	return nil
+}
+
+func (c Config) validateLDAPConfig() error {
+	ldapURL, _ := c[LDAPURL].(string)
+	if ldapURL == "" {
+		return nil
+	}
+	u, err := url.Parse(ldapURL)
+	if err != nil {
+		return errors.Annotate(err, "invalid LDAP URL")
+	}
+	if u.Scheme != "ldap" && u.Scheme != "ldaps" {
+		return errors.Errorf("%s must be an ldap or ldaps URL", LDAPURL)
+	}
+	startTLS, _ := c[LDAPStartTLS].(bool)
+	if startTLS && u.Scheme != "ldap" {
+		return errors.Errorf("%s can only be used with an ldap URL", LDAPStartTLS)
+	}
+	if password, _ := c[LDAPBindPassword].(string); password != "" && u.Scheme == "ldap" && !startTLS {
+		return errors.Errorf("%s must be set to send %s to an ldap URL", LDAPStartTLS, LDAPBindPassword)
+	}
+	if v, _ := c[LDAPUserDNTemplate].(string); !strings.Contains(v, "{user}") {
+		return errors.Errorf("%s must be set, and contain {user}, when %s is set", LDAPUserDNTemplate, LDAPURL)
+	}
+	if v, _ := c[LDAPGroupBaseDN].(string); v == "" {
+		return errors.Errorf("%s must be set when %s is set", LDAPGroupBaseDN, LDAPURL)
+	}
+	return nil
+}
+
+func (c Config) validatePasswordPolicy() error {
+	for _, key := range []string{PasswordMinLength, PasswordMinCharClasses, LoginMaxFailedAttempts} {
+		if v, ok := c[key].(int); ok && v < 0 {
+			return errors.NotValidf("negative %s (%d)", key, v)
+		}
+	}
+	if v, ok := c[PasswordMinCharClasses].(int); ok && v > 4 {
+		return errors.Errorf("%s must be between 0 and 4", PasswordMinCharClasses)
+	}
+	if v, ok := c[PasswordMaxAge].(time.Duration); ok && v < 0 {
+		return errors.Errorf("%s cannot be negative", PasswordMaxAge)
+	}
+	for _, key := range []string{LoginLockoutDuration, LoginMaxLockoutDuration} {
+		if v, ok := c[key].(time.Duration); ok && v <= 0 {
+			return errors.Errorf("%s must be positive", key)
+		}
+	}
+	if c.LoginLockoutDuration() > c.LoginMaxLockoutDuration() {
+		return errors.Errorf("%s cannot be longer than %s", LoginLockoutDuration, LoginMaxLockoutDuration)
+	}
+	return nil
+}
+
 func (c Config) validateSpaceConfig(key, topic string) error {
 	val := c[key]
 	if val == nil {
@@ -1097,7 +1665,14 @@ var configChecker = schema.FieldMap(schema.Fields{
 	SetNUMAControlPolicyKey: schema.Bool(),
 	AutocertURLKey:          schema.String(),
 	AutocertDNSNameKey:      schema.String(),
+	AutocertCACertKey:       schema.String(),
+	AutocertEABKeyIDKey:     schema.String(),
+	AutocertEABHMACKeyKey:   schema.String(),
+	AutocertChallengeKey:    schema.String(),
+	AutocertDNSProviderKey:  schema.String(),
+	AutocertDNSConfigKey:    schema.List(schema.String()),
 	AllowModelAccessKey:     schema.Bool(),
+	RESTGatewayKey:          schema.Bool(),
 	MongoMemoryProfile:      schema.String(),
 	JujuDBSnapChannel:       schema.String(),
 	MaxDebugLogDuration:     schema.TimeDuration(),
@@ -1118,6 +1693,26 @@ var configChecker = schema.FieldMap(schema.Fields{
 	MeteringURL:             schema.String(),
 	MaxCharmStateSize:       schema.ForceInt(),
 	MaxAgentStateSize:       schema.ForceInt(),
+	OIDCIssuerURL:           schema.String(),
+	OIDCClientID:            schema.String(),
+	OIDCUsernameClaim:       schema.String(),
+	OIDCGroupsClaim:         schema.String(),
+	OIDCGroupAccess:         schema.List(schema.String()),
+	LDAPURL:                 schema.String(),
+	LDAPBindDN:              schema.String(),
+	LDAPBindPassword:        schema.String(),
+	LDAPStartTLS:            schema.Bool(),
+	LDAPUserDNTemplate:      schema.String(),
+	LDAPGroupBaseDN:         schema.String(),
+	LDAPMemberAttribute:     schema.String(),
+	LDAPGroupNameAttribute:  schema.String(),
+	LDAPUserDomain:          schema.String(),
+	PasswordMinLength:       schema.ForceInt(),
+	PasswordMinCharClasses:  schema.ForceInt(),
+	PasswordMaxAge:          schema.TimeDuration(),
+	LoginMaxFailedAttempts:  schema.ForceInt(),
+	LoginLockoutDuration:    schema.TimeDuration(),
+	LoginMaxLockoutDuration: schema.TimeDuration(),
 }, schema.Defaults{
 	AgentRateLimitMax:       schema.Omit,
 	AgentRateLimitRate:      schema.Omit,
@@ -1136,7 +1731,14 @@ var configChecker = schema.FieldMap(schema.Fields{
 	SetNUMAControlPolicyKey: DefaultNUMAControlPolicy,
 	AutocertURLKey:          schema.Omit,
 	AutocertDNSNameKey:      schema.Omit,
+	AutocertCACertKey:       schema.Omit,
+	AutocertEABKeyIDKey:     schema.Omit,
+	AutocertEABHMACKeyKey:   schema.Omit,
+	AutocertChallengeKey:    schema.Omit,
+	AutocertDNSProviderKey:  schema.Omit,
+	AutocertDNSConfigKey:    schema.Omit,
 	AllowModelAccessKey:     schema.Omit,
+	RESTGatewayKey:          false,
 	MongoMemoryProfile:      DefaultMongoMemoryProfile,
 	JujuDBSnapChannel:       DefaultJujuDBSnapChannel,
 	MaxDebugLogDuration:     DefaultMaxDebugLogDuration,
@@ -1157,6 +1759,26 @@ var configChecker = schema.FieldMap(schema.Fields{
 	MeteringURL:             romulus.DefaultAPIRoot,
 	MaxCharmStateSize:       DefaultMaxCharmStateSize,
 	MaxAgentStateSize:       DefaultMaxAgentStateSize,
+	OIDCIssuerURL:           schema.Omit,
+	OIDCClientID:            schema.Omit,
+	OIDCUsernameClaim:       schema.Omit,
+	OIDCGroupsClaim:         schema.Omit,
+	OIDCGroupAccess:         schema.Omit,
+	LDAPURL:                 schema.Omit,
+	LDAPBindDN:              schema.Omit,
+	LDAPBindPassword:        schema.Omit,
+	LDAPStartTLS:            schema.Omit,
+	LDAPUserDNTemplate:      schema.Omit,
+	LDAPGroupBaseDN:         schema.Omit,
+	LDAPMemberAttribute:     schema.Omit,
+	LDAPGroupNameAttribute:  schema.Omit,
+	LDAPUserDomain:          schema.Omit,
+	PasswordMinLength:       schema.Omit,
+	PasswordMinCharClasses:  schema.Omit,
+	PasswordMaxAge:          schema.Omit,
+	LoginMaxFailedAttempts:  schema.Omit,
+	LoginLockoutDuration:    schema.Omit,
+	LoginMaxLockoutDuration: schema.Omit,
 })
 
 // ConfigSchema holds information on all the fields defined by
@@ -1234,12 +1856,40 @@ set, the api-port isn't opened until the controllers have started properly.`,
 		Type:        environschema.Tstring,
 		Description: `The DNS name of the controller`,
 	},
+	AutocertCACertKey: {
+		Type:        environschema.Tstring,
+		Description: `The PEM encoded certificates of the CAs trusted when connecting to the ACME server`,
+	},
+	AutocertEABKeyIDKey: {
+		Type:        environschema.Tstring,
+		Description: `The key identifier of the external account binding used to register with the ACME server`,
+	},
+	AutocertEABHMACKeyKey: {
+		Type:        environschema.Tstring,
+		Description: `The base64url encoded MAC key of the external account binding used to register with the ACME server`,
+	},
+	AutocertChallengeKey: {
+		Type:        environschema.Tstring,
+		Description: `The type of ACME challenge used to prove control of the controller's DNS name (tls-alpn-01 or dns-01)`,
+	},
+	AutocertDNSProviderKey: {
+		Type:        environschema.Tstring,
+		Description: `The DNS provider used to publish the records for dns-01 challenges`,
+	},
+	AutocertDNSConfigKey: {
+		Type:        environschema.FieldType("list of strings"),
+		Description: `The list of "key=value" entries configuring the DNS provider used for dns-01 challenges`,
+	},
 	AllowModelAccessKey: {
 		Type: environschema.Tbool,
 		Description: `Determines if the controller allows users to 
 connect to models they have been authorized for even when 
 they don't have any access rights to the controller itself`,
 	},
+	RESTGatewayKey: {
+		Type:        environschema.Tbool,
+		Description: `Determines if the controller serves the REST gateway, which exposes selected client API methods over HTTP`,
+	},
 	MongoMemoryProfile: {
 		Type:        environschema.Tstring,
 		Description: `Sets mongo memory profile`,
@@ -1321,4 +1971,84 @@ Use "caas-image-repo" instead.`,
 		Type:        environschema.Tint,
 		Description: `The maximum size (in bytes) of internal state data that agents can store to the controller`,
 	},
+	OIDCIssuerURL: {
+		Type:        environschema.Tstring,
+		Description: `The https URL of an OpenID Connect provider whose ID tokens users may log in with`,
+	},
+	OIDCClientID: {
+		Type:        environschema.Tstring,
+		Description: `The client ID registered for Juju with the OpenID Connect provider`,
+	},
+	OIDCUsernameClaim: {
+		Type:        environschema.Tstring,
+		Description: `The ID token claim holding the user name (defaults to "email")`,
+	},
+	OIDCGroupsClaim: {
+		Type:        environschema.Tstring,
+		Description: `The ID token claim holding the user's groups (defaults to "groups")`,
+	},
+	OIDCGroupAccess: {
+		Type:        environschema.FieldType("list of strings"),
+		Description: `The list of "group=access" entries granting controller access to members of OpenID Connect provider groups`,
+	},
+	LDAPURL: {
+		Type:        environschema.Tstring,
+		Description: `The ldap:// or ldaps:// URL of a directory from which users' group memberships are read`,
+	},
+	LDAPBindDN: {
+		Type:        environschema.Tstring,
+		Description: `The distinguished name used to search the LDAP directory (searches are anonymous if not set)`,
+	},
+	LDAPBindPassword: {
+		Type:        environschema.Tstring,
+		Description: `The password used to search the LDAP directory`,
+	},
+	LDAPStartTLS: {
+		Type:        environschema.Tbool,
+		Description: `Whether to upgrade connections to an ldap:// URL with StartTLS (required to send a bind password)`,
+	},
+	LDAPUserDNTemplate: {
+		Type:        environschema.Tstring,
+		Description: `The distinguished name of users' LDAP entries, with {user} standing in for the user name`,
+	},
+	LDAPGroupBaseDN: {
+		Type:        environschema.Tstring,
+		Description: `The distinguished name beneath which groups are found in the LDAP directory`,
+	},
+	LDAPMemberAttribute: {
+		Type:        environschema.Tstring,
+		Description: `The group attribute holding the distinguished names of its members (defaults to "member")`,
+	},
+	LDAPGroupNameAttribute: {
+		Type:        environschema.Tstring,
+		Description: `The group attribute holding the name of the group (defaults to "cn")`,
+	},
+	LDAPUserDomain: {
+		Type:        environschema.Tstring,
+		Description: `The domain of the users whose groups are looked up in the LDAP directory (local users if not set)`,
+	},
+	PasswordMinLength: {
+		Type:        environschema.Tint,
+		Description: `The minimum number of characters in local users' passwords`,
+	},
+	PasswordMinCharClasses: {
+		Type:        environschema.Tint,
+		Description: `The minimum number of character classes (lower case, upper case, digits, others) in local users' passwords`,
+	},
+	PasswordMaxAge: {
+		Type:        environschema.Tstring,
+		Description: `How long local users' passwords may be used after they are set (passwords do not expire if not set)`,
+	},
+	LoginMaxFailedAttempts: {
+		Type:        environschema.Tint,
+		Description: `The number of consecutive failed logins after which a local user is locked out (users are not locked out if not set)`,
+	},
+	LoginLockoutDuration: {
+		Type:        environschema.Tstring,
+		Description: `How long a local user is first locked out for, doubling with each further failed login`,
+	},
+	LoginMaxLockoutDuration: {
+		Type:        environschema.Tstring,
+		Description: `The longest a local user is locked out for`,
+	},
 }
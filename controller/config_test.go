@@ -100,6 +100,166 @@ var newConfigTests = []struct {
 		controller.IdentityPublicKey: `xxxx`,
 	},
 	expectError: `invalid identity public key: wrong length for key, got 3 want 32`,
+}, {
+	about: "OIDC issuer OK",
+	config: controller.Config{
+		controller.OIDCIssuerURL:   "https://login.example.com",
+		controller.OIDCClientID:    "juju",
+		controller.OIDCGroupAccess: []interface{}{"ops=superuser", "devs=login"},
+	},
+}, {
+	about: "HTTP OIDC issuer",
+	config: controller.Config{
+		controller.OIDCIssuerURL: "http://login.example.com",
+		controller.OIDCClientID:  "juju",
+	},
+	expectError: `oidc-issuer-url must be an https URL`,
+}, {
+	about: "OIDC issuer without client ID",
+	config: controller.Config{
+		controller.OIDCIssuerURL: "https://login.example.com",
+	},
+	expectError: `oidc-client-id must be set when oidc-issuer-url is set`,
+}, {
+	about: "OIDC issuer with identity URL",
+	config: controller.Config{
+		controller.OIDCIssuerURL: "https://login.example.com",
+		controller.OIDCClientID:  "juju",
+		controller.IdentityURL:   "https://0.1.2.3/foo",
+	},
+	expectError: `oidc-issuer-url and identity-url cannot both be set`,
+}, {
+	about: "invalid OIDC group access",
+	config: controller.Config{
+		controller.OIDCIssuerURL:   "https://login.example.com",
+		controller.OIDCClientID:    "juju",
+		controller.OIDCGroupAccess: []interface{}{"ops=superuser", "devs=admin"},
+	},
+	expectError: `invalid oidc-group-access: should be a list of "group=access" entries, where access is one of add-model, login, superuser, got "devs=admin" at position 2`,
+}, {
+	about: "LDAP directory OK",
+	config: controller.Config{
+		controller.LDAPURL:            "ldaps://ldap.example.com",
+		controller.LDAPUserDNTemplate: "uid={user},ou=people,dc=example,dc=com",
+		controller.LDAPGroupBaseDN:    "ou=groups,dc=example,dc=com",
+	},
+}, {
+	about: "HTTPS LDAP URL",
+	config: controller.Config{
+		controller.LDAPURL:            "https://ldap.example.com",
+		controller.LDAPUserDNTemplate: "uid={user},ou=people,dc=example,dc=com",
+		controller.LDAPGroupBaseDN:    "ou=groups,dc=example,dc=com",
+	},
+	expectError: `ldap-url must be an ldap or ldaps URL`,
+}, {
+	about: "LDAP user DN template without user",
+	config: controller.Config{
+		controller.LDAPURL:            "ldap://ldap.example.com",
+		controller.LDAPUserDNTemplate: "ou=people,dc=example,dc=com",
+		controller.LDAPGroupBaseDN:    "ou=groups,dc=example,dc=com",
+	},
+	expectError: `ldap-user-dn-template must be set, and contain {user}, when ldap-url is set`,
+}, {
+	about: "LDAP URL without group base DN",
+	config: controller.Config{
+		controller.LDAPURL:            "ldap://ldap.example.com",
+		controller.LDAPUserDNTemplate: "uid={user},ou=people,dc=example,dc=com",
+	},
+	expectError: `ldap-group-base-dn must be set when ldap-url is set`,
+}, {
+	about: "LDAP bind password over ldap URL",
+	config: controller.Config{
+		controller.LDAPURL:            "ldap://ldap.example.com",
+		controller.LDAPBindDN:         "cn=juju,dc=example,dc=com",
+		controller.LDAPBindPassword:   "sekrit",
+		controller.LDAPUserDNTemplate: "uid={user},ou=people,dc=example,dc=com",
+		controller.LDAPGroupBaseDN:    "ou=groups,dc=example,dc=com",
+	},
+	expectError: `ldap-start-tls must be set to send ldap-bind-password to an ldap URL`,
+}, {
+	about: "LDAP bind password over ldap URL with StartTLS",
+	config: controller.Config{
+		controller.LDAPURL:            "ldap://ldap.example.com",
+		controller.LDAPBindDN:         "cn=juju,dc=example,dc=com",
+		controller.LDAPBindPassword:   "sekrit",
+		controller.LDAPStartTLS:       true,
+		controller.LDAPUserDNTemplate: "uid={user},ou=people,dc=example,dc=com",
+		controller.LDAPGroupBaseDN:    "ou=groups,dc=example,dc=com",
+	},
+}, {
+	about: "LDAP StartTLS with ldaps URL",
+	config: controller.Config{
+		controller.LDAPURL:            "ldaps://ldap.example.com",
+		controller.LDAPStartTLS:       true,
+		controller.LDAPUserDNTemplate: "uid={user},ou=people,dc=example,dc=com",
+		controller.LDAPGroupBaseDN:    "ou=groups,dc=example,dc=com",
+	},
+	expectError: `ldap-start-tls can only be used with an ldap URL`,
+}, {
+	about: "autocert CA certificate not PEM",
+	config: controller.Config{
+		controller.AutocertCACertKey: "not a certificate",
+	},
+	expectError: `autocert-ca-cert must hold PEM encoded certificates`,
+}, {
+	about: "autocert EAB key ID without MAC key",
+	config: controller.Config{
+		controller.AutocertEABKeyIDKey: "kid-1",
+	},
+	expectError: `autocert-eab-kid and autocert-eab-hmac-key must be set together`,
+}, {
+	about: "autocert EAB MAC key not base64url",
+	config: controller.Config{
+		controller.AutocertEABKeyIDKey:   "kid-1",
+		controller.AutocertEABHMACKeyKey: "not+base64/",
+	},
+	expectError: `autocert-eab-hmac-key must be base64url encoded`,
+}, {
+	about: "unknown autocert challenge",
+	config: controller.Config{
+		controller.AutocertChallengeKey: "http-01",
+	},
+	expectError: `autocert-challenge must be one of dns-01, tls-alpn-01, got "http-01"`,
+}, {
+	about: "autocert dns-01 challenge without provider",
+	config: controller.Config{
+		controller.AutocertChallengeKey: "dns-01",
+	},
+	expectError: `autocert-dns-provider must be set when autocert-challenge is dns-01`,
+}, {
+	about: "invalid autocert DNS provider config",
+	config: controller.Config{
+		controller.AutocertChallengeKey:   "dns-01",
+		controller.AutocertDNSProviderKey: "exec",
+		controller.AutocertDNSConfigKey:   []interface{}{"timeout=1m", "timeout"},
+	},
+	expectError: `invalid autocert-dns-provider-config: should be a list of "key=value" entries, got "timeout" at position 2`,
+}, {
+	about: "negative password-min-length",
+	config: controller.Config{
+		controller.PasswordMinLength: -1,
+	},
+	expectError: `negative password-min-length \(-1\) not valid`,
+}, {
+	about: "too many password character classes",
+	config: controller.Config{
+		controller.PasswordMinCharClasses: 5,
+	},
+	expectError: `password-min-character-classes must be between 0 and 4`,
+}, {
+	about: "zero login-lockout-duration",
+	config: controller.Config{
+		controller.LoginMaxFailedAttempts: 5,
+		controller.LoginLockoutDuration:   "0s",
+	},
+	expectError: `login-lockout-duration must be positive`,
+}, {
+	about: "lockout longer than max lockout",
+	config: controller.Config{
+		controller.LoginLockoutDuration:    "2h",
+		controller.LoginMaxLockoutDuration: "1h",
+	},
+	expectError: `login-lockout-duration cannot be longer than login-max-lockout-duration`,
 }, {
 	about: "invalid management space name - whitespace",
 	config: controller.Config{
@@ -491,6 +651,131 @@ func (s *ConfigSuite) TestAuditLogValues(c *gc.C) {
 	))
 }
 
+func (s *ConfigSuite) TestOIDCConfig(c *gc.C) {
+	cfg, err := controller.NewConfig(
+		testing.ControllerTag.Id(),
+		testing.CACert,
+		map[string]interface{}{
+			"oidc-issuer-url":   "https://login.example.com",
+			"oidc-client-id":    "juju",
+			"oidc-group-access": []string{"ops=superuser", "devs=login"},
+		},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.OIDCIssuerURL(), gc.Equals, "https://login.example.com")
+	c.Assert(cfg.OIDCClientID(), gc.Equals, "juju")
+	c.Assert(cfg.OIDCUsernameClaim(), gc.Equals, "email")
+	c.Assert(cfg.OIDCGroupsClaim(), gc.Equals, "groups")
+	c.Assert(cfg.OIDCGroupAccess(), jc.DeepEquals, map[string]string{
+		"ops":  "superuser",
+		"devs": "login",
+	})
+}
+
+func (s *ConfigSuite) TestLDAPConfig(c *gc.C) {
+	cfg, err := controller.NewConfig(
+		testing.ControllerTag.Id(),
+		testing.CACert,
+		map[string]interface{}{
+			"ldap-url":              "ldaps://ldap.example.com",
+			"ldap-bind-dn":          "cn=juju,dc=example,dc=com",
+			"ldap-bind-password":    "sekrit",
+			"ldap-user-dn-template": "uid={user},ou=people,dc=example,dc=com",
+			"ldap-group-base-dn":    "ou=groups,dc=example,dc=com",
+			"ldap-user-domain":      "example",
+		},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.LDAPURL(), gc.Equals, "ldaps://ldap.example.com")
+	c.Assert(cfg.LDAPBindDN(), gc.Equals, "cn=juju,dc=example,dc=com")
+	c.Assert(cfg.LDAPBindPassword(), gc.Equals, "sekrit")
+	c.Assert(cfg.LDAPStartTLS(), jc.IsFalse)
+	c.Assert(cfg.LDAPUserDNTemplate(), gc.Equals, "uid={user},ou=people,dc=example,dc=com")
+	c.Assert(cfg.LDAPGroupBaseDN(), gc.Equals, "ou=groups,dc=example,dc=com")
+	c.Assert(cfg.LDAPMemberAttribute(), gc.Equals, "member")
+	c.Assert(cfg.LDAPGroupNameAttribute(), gc.Equals, "cn")
+	c.Assert(cfg.LDAPUserDomain(), gc.Equals, "example")
+
+	withoutSecrets := cfg.WithoutSecrets()
+	c.Assert(withoutSecrets.LDAPBindDN(), gc.Equals, "cn=juju,dc=example,dc=com")
+	c.Assert(withoutSecrets.LDAPBindPassword(), gc.Equals, "")
+	c.Assert(cfg.LDAPBindPassword(), gc.Equals, "sekrit")
+	c.Assert(controller.IsSecretAttribute(controller.LDAPBindPassword), jc.IsTrue)
+	c.Assert(controller.IsSecretAttribute(controller.LDAPBindDN), jc.IsFalse)
+	c.Assert(controller.IsSecretAttribute(controller.AutocertEABHMACKeyKey), jc.IsTrue)
+	c.Assert(controller.IsSecretAttribute(controller.AutocertDNSConfigKey), jc.IsTrue)
+	c.Assert(controller.IsSecretAttribute(controller.AutocertEABKeyIDKey), jc.IsFalse)
+}
+
+func (s *ConfigSuite) TestAutocertConfig(c *gc.C) {
+	cfg, err := controller.NewConfig(
+		testing.ControllerTag.Id(),
+		testing.CACert,
+		map[string]interface{}{},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.AutocertChallenge(), gc.Equals, "tls-alpn-01")
+	c.Assert(cfg.AutocertEABHMACKey(), gc.HasLen, 0)
+	c.Assert(cfg.AutocertDNSProviderConfig(), gc.HasLen, 0)
+
+	cfg, err = controller.NewConfig(
+		testing.ControllerTag.Id(),
+		testing.CACert,
+		map[string]interface{}{
+			"autocert-dns-name":            "controller.example.com",
+			"autocert-url":                 "https://ca.example.com/acme/directory",
+			"autocert-ca-cert":             testing.OtherCACert,
+			"autocert-eab-kid":             "kid-1",
+			"autocert-eab-hmac-key":        "c2VjcmV0",
+			"autocert-challenge":           "dns-01",
+			"autocert-dns-provider":        "exec",
+			"autocert-dns-provider-config": []string{"command=/bin/dns-hook", "timeout=1m"},
+		},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.AutocertCACert(), gc.Equals, testing.OtherCACert)
+	c.Assert(cfg.AutocertEABKeyID(), gc.Equals, "kid-1")
+	c.Assert(cfg.AutocertEABHMACKey(), jc.DeepEquals, []byte("secret"))
+	c.Assert(cfg.AutocertChallenge(), gc.Equals, "dns-01")
+	c.Assert(cfg.AutocertDNSProvider(), gc.Equals, "exec")
+	c.Assert(cfg.AutocertDNSProviderConfig(), jc.DeepEquals, []string{"command=/bin/dns-hook", "timeout=1m"})
+}
+
+func (s *ConfigSuite) TestPasswordPolicyConfig(c *gc.C) {
+	cfg, err := controller.NewConfig(
+		testing.ControllerTag.Id(),
+		testing.CACert,
+		map[string]interface{}{},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.PasswordMinLength(), gc.Equals, 0)
+	c.Assert(cfg.PasswordMinCharClasses(), gc.Equals, 0)
+	c.Assert(cfg.PasswordMaxAge(), gc.Equals, time.Duration(0))
+	c.Assert(cfg.LoginMaxFailedAttempts(), gc.Equals, 0)
+	c.Assert(cfg.LoginLockoutDuration(), gc.Equals, time.Minute)
+	c.Assert(cfg.LoginMaxLockoutDuration(), gc.Equals, 24*time.Hour)
+
+	cfg, err = controller.NewConfig(
+		testing.ControllerTag.Id(),
+		testing.CACert,
+		map[string]interface{}{
+			"password-min-length":            12,
+			"password-min-character-classes": 3,
+			"password-max-age":               "2160h",
+			"login-max-failed-attempts":      5,
+			"login-lockout-duration":         "5m",
+			"login-max-lockout-duration":     "2h",
+		},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.PasswordMinLength(), gc.Equals, 12)
+	c.Assert(cfg.PasswordMinCharClasses(), gc.Equals, 3)
+	c.Assert(cfg.PasswordMaxAge(), gc.Equals, 90*24*time.Hour)
+	c.Assert(cfg.LoginMaxFailedAttempts(), gc.Equals, 5)
+	c.Assert(cfg.LoginLockoutDuration(), gc.Equals, 5*time.Minute)
+	c.Assert(cfg.LoginMaxLockoutDuration(), gc.Equals, 2*time.Hour)
+}
+
 func (s *ConfigSuite) TestAuditLogExcludeMethodsType(c *gc.C) {
 	_, err := controller.NewConfig(
 		testing.ControllerTag.Id(),
@@ -31,8 +31,9 @@ type Conversation struct {
 	When           string `json:"when"`       // ISO 8601 to second precision
 	ModelName      string `json:"model-name"` // full representation "user/name"
 	ModelUUID      string `json:"model-uuid"`
-	ConversationID string `json:"conversation-id"` // uint64 in hex
-	ConnectionID   string `json:"connection-id"`   // uint64 in hex (using %X to match the value in log files)
+	ConversationID string `json:"conversation-id"`    // uint64 in hex
+	ConnectionID   string `json:"connection-id"`      // uint64 in hex (using %X to match the value in log files)
+	TokenID        string `json:"token-id,omitempty"` // API token used to log in, if any
 }
 
 // ConversationArgs is the information needed to create a method recorder.
@@ -42,6 +43,7 @@ type ConversationArgs struct {
 	ModelName    string
 	ModelUUID    string
 	ConnectionID uint64
+	TokenID      string
 }
 
 // Request represents a call to an API facade made as part of
@@ -128,6 +130,7 @@ func NewRecorder(log AuditLog, clock clock.Clock, c ConversationArgs) (*Recorder
 		When:           clock.Now().Format(time.RFC3339),
 		ModelName:      c.ModelName,
 		ModelUUID:      c.ModelUUID,
+		TokenID:        c.TokenID,
 	})
 	if err != nil {
 		return nil, errors.Trace(err)
@@ -87,6 +87,7 @@ func (s *AuditLogSuite) TestRecorder(c *gc.C) {
 		What:         "Doubt/Hope",
 		ModelName:    "admin/default",
 		ConnectionID: 687,
+		TokenID:      "4b1d9e2c",
 	})
 	c.Assert(err, jc.ErrorIsNil)
 	clock.Advance(time.Second)
@@ -119,6 +120,7 @@ func (s *AuditLogSuite) TestRecorder(c *gc.C) {
 		ModelName:      "admin/default",
 		ConnectionID:   "2AF",
 		ConversationID: callID,
+		TokenID:        "4b1d9e2c",
 	})
 	c.Assert(calls[1].Args[0], gc.DeepEquals, auditlog.Request{
 		ConversationID: callID,
@@ -58,6 +58,19 @@ func (b *Branch) AppConfig(appName string) settings.ItemChanges {
 	return b.details.Config[appName]
 }
 
+// AppCharmURL returns the charm URL that the application is upgraded to
+// under the branch, or an empty string if the charm is not changed.
+func (b *Branch) AppCharmURL(appName string) string {
+	return b.details.CharmURLs[appName]
+}
+
+// PromoteAt returns a Unix timestamp indicating when the branch is due
+// to be automatically committed or aborted, based on the health of its
+// tracking units. Zero indicates that there is no promotion policy.
+func (b *Branch) PromoteAt() int64 {
+	return b.details.PromoteAt
+}
+
 // Created returns a Unix timestamp indicating when this generation
 // was created.
 func (b *Branch) Created() int64 {
@@ -310,6 +310,8 @@ type BranchChange struct {
 	Name          string
 	AssignedUnits map[string][]string
 	Config        map[string]settings.ItemChanges
+	CharmURLs     map[string]string
+	PromoteAt     int64
 	Created       int64
 	CreatedBy     string
 	Completed     int64
@@ -351,6 +353,15 @@ func (b BranchChange) copy() BranchChange {
 	}
 	b.Config = cConfig
 
+	var cCharmURLs map[string]string
+	if b.CharmURLs != nil {
+		cCharmURLs = make(map[string]string, len(b.CharmURLs))
+		for k, v := range b.CharmURLs {
+			cCharmURLs[k] = v
+		}
+	}
+	b.CharmURLs = cCharmURLs
+
 	return b
 }
 
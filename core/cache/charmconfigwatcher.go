@@ -48,6 +48,8 @@ type charmConfigWatcherConfig struct {
 // to the unit's effective configuration:
 // - Changes to the charm config settings for the unit's application.
 // - Changes to a model branch being tracked by the unit.
+// Upgrading the application's charm under a tracked branch counts as a
+// change to the branch.
 type CharmConfigWatcher struct {
 	*stringsWatcherBase
 
@@ -55,10 +57,11 @@ type CharmConfigWatcher struct {
 	// watcher is fully constructed and ready to handle events.
 	initComplete chan struct{}
 
-	unitName   string
-	appName    string
-	charmURL   string
-	branchName string
+	unitName       string
+	appName        string
+	charmURL       string
+	branchName     string
+	branchCharmURL string
 
 	CharmConfigHashCacheHitInc  func()
 	CharmConfigHashCacheMissInc func()
@@ -118,6 +121,7 @@ func (w *CharmConfigWatcher) init(model charmConfigModel) error {
 		if w.isTracking(b) {
 			w.branchName = b.Name()
 			w.branchDeltas = b.AppConfig(w.appName)
+			w.branchCharmURL = b.AppCharmURL(w.appName)
 			break
 		}
 	}
@@ -172,7 +176,18 @@ func (w *CharmConfigWatcher) branchChanged(_ string, msg interface{}) {
 		return
 	}
 
+	// If the unit was removed from the branch,
+	// it reverts to the master generation.
+	if w.isUnassigned(b) {
+		w.branchName = ""
+		w.branchDeltas = nil
+		w.branchCharmURL = ""
+		w.checkConfig()
+		return
+	}
+
 	w.branchDeltas = b.AppConfig(w.appName)
+	w.branchCharmURL = b.AppCharmURL(w.appName)
 	w.checkConfig()
 }
 
@@ -197,15 +212,24 @@ func (w *CharmConfigWatcher) branchRemoved(topic string, msg interface{}) {
 
 	// The branch we are tracking was deleted.
 	// Since we know that a branch with tracking units can not be aborted,
+	// and removal of units from a branch is handled by branchChanged,
 	// the branch must have been committed.
 	// This means that we can anticipate a message for a master settings change
 	// (it may even have preceded this event), so just clear the branch info
 	// without reevaluating the hash.
 	w.branchName = ""
 	w.branchDeltas = nil
+	w.branchCharmURL = ""
 }
 
 // isTracking returns true if this watcher's unit is tracking the input branch.
+// isUnassigned returns true if the branch has changes for the watcher's
+// application, but the watcher's unit is not tracking it.
+func (w *CharmConfigWatcher) isUnassigned(b Branch) bool {
+	units, ok := b.AssignedUnits()[w.appName]
+	return ok && !set.NewStrings(units...).Contains(w.unitName)
+}
+
 func (w *CharmConfigWatcher) isTracking(b Branch) bool {
 	units := b.AssignedUnits()[w.appName]
 	if len(units) == 0 {
@@ -241,7 +265,13 @@ func (w *CharmConfigWatcher) setConfigHash() (bool, error) {
 		}
 	}
 
-	newHash, err := hashSettings(cfg, w.charmURL)
+	// A charm upgrade made under the tracked branch changes the hash,
+	// so that the unit learns of the charm it should now run.
+	charmURL := w.charmURL
+	if w.branchCharmURL != "" {
+		charmURL = w.branchCharmURL
+	}
+	newHash, err := hashSettings(cfg, charmURL)
 	if err != nil {
 		return false, errors.Trace(err)
 	}
@@ -54,6 +54,24 @@ func (s *charmConfigWatcherSuite) TestTrackingBranchChangedNotified(c *gc.C) {
 	w.AssertStops()
 }
 
+func (s *charmConfigWatcherSuite) TestTrackingBranchCharmUpgradeNotified(c *gc.C) {
+	w := s.newWatcher(c, defaultUnitName, defaultCharmURL)
+	s.assertOneChange(c, w, map[string]interface{}{"password": defaultPassword}, defaultCharmURL)
+
+	// Publish a tracked branch change that upgrades the charm.
+	b := Branch{
+		details: BranchChange{
+			Name:      branchName,
+			Config:    map[string]settings.ItemChanges{"redis": {settings.MakeAddition("password", defaultPassword)}},
+			CharmURLs: map[string]string{"redis": "branch-charm-url"},
+		},
+	}
+	s.Hub.Publish(branchChange, b)
+
+	s.assertOneChange(c, w, map[string]interface{}{"password": defaultPassword}, "branch-charm-url")
+	w.AssertStops()
+}
+
 func (s *charmConfigWatcherSuite) TestNotTrackingBranchChangedNotNotified(c *gc.C) {
 	// This will initialise the watcher without branch info.
 	w := s.newWatcher(c, "redis/9", defaultCharmURL)
@@ -112,6 +130,25 @@ func (s *charmConfigWatcherSuite) TestTrackingBranchCommittedNotNotified(c *gc.C
 	w.AssertStops()
 }
 
+func (s *charmConfigWatcherSuite) TestTrackingBranchUnitRemovedNotified(c *gc.C) {
+	w := s.newWatcher(c, defaultUnitName, defaultCharmURL)
+	s.assertOneChange(c, w, map[string]interface{}{"password": defaultPassword}, defaultCharmURL)
+
+	// Publish a branch change that removes the unit from the branch.
+	b := Branch{
+		details: BranchChange{
+			Name:          branchName,
+			AssignedUnits: map[string][]string{"redis": {}},
+			Config:        map[string]settings.ItemChanges{"redis": {settings.MakeAddition("password", defaultPassword)}},
+		},
+	}
+	s.Hub.Publish(branchChange, b)
+
+	// The unit reverts to the master configuration.
+	s.assertOneChange(c, w, map[string]interface{}{}, defaultCharmURL)
+	w.AssertStops()
+}
+
 func (s *charmConfigWatcherSuite) TestNotTrackedBranchSeesMasterConfig(c *gc.C) {
 	// Watcher is for a unit not tracking the branch.
 	w := s.newWatcher(c, "redis/9", defaultCharmURL)
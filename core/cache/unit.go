@@ -13,6 +13,7 @@ import (
 	"github.com/juju/juju/core/life"
 	"github.com/juju/juju/core/network"
 	"github.com/juju/juju/core/settings"
+	"github.com/juju/juju/core/status"
 )
 
 // Unit represents a unit in a cached model.
@@ -76,6 +77,16 @@ func (u *Unit) Ports() []network.Port {
 	return u.details.Ports
 }
 
+// WorkloadStatus returns the current workload status of the unit.
+func (u *Unit) WorkloadStatus() status.StatusInfo {
+	return u.details.WorkloadStatus
+}
+
+// AgentStatus returns the current status of the unit's agent.
+func (u *Unit) AgentStatus() status.StatusInfo {
+	return u.details.AgentStatus
+}
+
 // Config settings returns the effective charm configuration for this unit
 // taking into account whether it is tracking a model branch.
 func (u *Unit) ConfigSettings() (charm.Settings, error) {
@@ -26,6 +26,7 @@ const (
 	cpuCores       = "cpu-cores"
 	Cores          = "cores"
 	CpuPower       = "cpu-power"
+	Firmware       = "firmware"
 	Mem            = "mem"
 	RootDisk       = "root-disk"
 	RootDiskSource = "root-disk-source"
@@ -58,6 +59,11 @@ type Value struct {
 	// equivalent to 1 Amazon ECU (or, roughly, a single 2007-era Xeon).
 	CpuPower *uint64 `json:"cpu-power,omitempty" yaml:"cpu-power,omitempty"`
 
+	// Firmware, if not nil or empty, indicates that a machine must boot
+	// using the named firmware, either "bios" or "uefi". Only valid for
+	// machines whose firmware can be chosen, such as KVM containers.
+	Firmware *string `json:"firmware,omitempty" yaml:"firmware,omitempty"`
+
 	// Mem, if not nil, indicates that a machine must have at least that many
 	// megabytes of RAM.
 	Mem *uint64 `json:"mem,omitempty" yaml:"mem,omitempty"`
@@ -140,6 +146,11 @@ func (v *Value) HasCpuCores() bool {
 	return v.CpuCores != nil && *v.CpuCores > 0
 }
 
+// HasFirmware returns true if the constraints.Value specifies a firmware.
+func (v *Value) HasFirmware() bool {
+	return v.Firmware != nil && *v.Firmware != ""
+}
+
 // HasRootDisk returns true if the contraints.Value specifies a RootDisk size.
 func (v *Value) HasRootDisk() bool {
 	return v.RootDisk != nil && *v.RootDisk > 0
@@ -223,6 +234,9 @@ func (v Value) String() string {
 	if v.CpuPower != nil {
 		strs = append(strs, "cpu-power="+uintStr(*v.CpuPower))
 	}
+	if v.Firmware != nil {
+		strs = append(strs, "firmware="+(*v.Firmware))
+	}
 	if v.InstanceType != nil {
 		strs = append(strs, "instance-type="+(*v.InstanceType))
 	}
@@ -281,6 +295,9 @@ func (v Value) GoString() string {
 	if v.CpuPower != nil {
 		values = append(values, fmt.Sprintf("CpuPower: %v", *v.CpuPower))
 	}
+	if v.Firmware != nil {
+		values = append(values, fmt.Sprintf("Firmware: %q", *v.Firmware))
+	}
 	if v.Mem != nil {
 		values = append(values, fmt.Sprintf("Mem: %v", *v.Mem))
 	}
@@ -460,6 +477,8 @@ func (v *Value) setRaw(name, str string) error {
 		err = v.setCpuCores(str)
 	case CpuPower:
 		err = v.setCpuPower(str)
+	case Firmware:
+		err = v.setFirmware(str)
 	case Mem:
 		err = v.setMem(str)
 	case RootDisk:
@@ -521,6 +540,8 @@ func (v *Value) UnmarshalYAML(unmarshal func(interface{}) error) error {
 			v.CpuCores, err = parseUint64(vstr)
 		case CpuPower:
 			v.CpuPower, err = parseUint64(vstr)
+		case Firmware:
+			err = v.setFirmware(vstr)
 		case Mem:
 			v.Mem, err = parseUint64(vstr)
 		case RootDisk:
@@ -602,6 +623,19 @@ func (v *Value) setCpuPower(str string) (err error) {
 	return
 }
 
+func (v *Value) setFirmware(str string) error {
+	if v.Firmware != nil {
+		return errors.Errorf("already set")
+	}
+	switch str {
+	case "", "bios", "uefi":
+	default:
+		return errors.Errorf("%q not recognized", str)
+	}
+	v.Firmware = &str
+	return nil
+}
+
 func (v *Value) setInstanceType(str string) error {
 	if v.InstanceType != nil {
 		return errors.Errorf("already set")
@@ -272,6 +272,26 @@ var parseConstraintsTests = []struct {
 		err:     `bad "root-disk-source" constraint: already set`,
 	},
 
+	// firmware in detail.
+	{
+		summary: "set firmware empty",
+		args:    []string{"firmware="},
+	}, {
+		summary: "set firmware bios",
+		args:    []string{"firmware=bios"},
+	}, {
+		summary: "set firmware uefi",
+		args:    []string{"firmware=uefi"},
+	}, {
+		summary: "set nonsense firmware",
+		args:    []string{"firmware=coreboot"},
+		err:     `bad "firmware" constraint: "coreboot" not recognized`,
+	}, {
+		summary: "double set firmware together",
+		args:    []string{"firmware=bios firmware=uefi"},
+		err:     `bad "firmware" constraint: already set`,
+	},
+
 	// tags
 	{
 		summary: "single tag",
@@ -628,6 +648,8 @@ var constraintsRoundtripTests = []roundTrip{
 	{"CpuPower1", constraints.Value{CpuPower: nil}},
 	{"CpuPower2", constraints.Value{CpuPower: uint64p(0)}},
 	{"CpuPower3", constraints.Value{CpuPower: uint64p(250)}},
+	{"Firmware1", constraints.Value{Firmware: nil}},
+	{"Firmware2", constraints.Value{Firmware: strp("uefi")}},
 	{"Mem1", constraints.Value{Mem: nil}},
 	{"Mem2", constraints.Value{Mem: uint64p(0)}},
 	{"Mem3", constraints.Value{Mem: uint64p(98765)}},
@@ -652,6 +674,7 @@ var constraintsRoundtripTests = []roundTrip{
 		Container:      ctypep("lxd"),
 		CpuCores:       uint64p(4096),
 		CpuPower:       uint64p(9001),
+		Firmware:       strp("bios"),
 		Mem:            uint64p(18000000000),
 		RootDisk:       uint64p(24000000000),
 		RootDiskSource: strp("cave"),
@@ -29,6 +29,9 @@ type ApplicationOffer struct {
 	// Endpoints is the collection of endpoint names offered (internal->published).
 	// The map allows for advertised endpoint names to be aliased.
 	Endpoints map[string]charm.Relation
+
+	// Limits are the restrictions placed on consumers of the offer.
+	Limits OfferLimits
 }
 
 // AddApplicationOfferArgs contains parameters used to create an application offer.
@@ -56,6 +59,9 @@ type AddApplicationOfferArgs struct {
 	// Icon is an icon to display when browsing the ApplicationOffers, which by default
 	// comes from the charm.
 	Icon []byte
+
+	// Limits are the restrictions placed on consumers of the offer.
+	Limits OfferLimits
 }
 
 // ConsumeApplicationArgs contains parameters used to consume an offer.
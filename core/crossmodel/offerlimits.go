@@ -0,0 +1,69 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package crossmodel
+
+import (
+	"github.com/juju/collections/set"
+	"github.com/juju/errors"
+	"github.com/juju/utils"
+)
+
+// OfferLimits holds the restrictions an offer owner places on how an
+// offer may be consumed. The zero value places no restrictions.
+type OfferLimits struct {
+	// MaxConnections is the maximum number of relations which may be
+	// made to the offer. Zero means unlimited.
+	MaxConnections int
+
+	// AllowedConsumerModels holds the UUIDs of the models which may
+	// relate to the offer. If empty, any model may relate.
+	AllowedConsumerModels []string
+
+	// SettingsChangesPerMinute is the maximum rate at which a consumer
+	// may publish relation settings changes over a single relation.
+	// Zero means unlimited.
+	SettingsChangesPerMinute int
+}
+
+// IsZero returns true if the limits place no restrictions on the offer.
+func (l OfferLimits) IsZero() bool {
+	return l.MaxConnections == 0 &&
+		len(l.AllowedConsumerModels) == 0 &&
+		l.SettingsChangesPerMinute == 0
+}
+
+// Validate returns an error if the limits are not valid.
+func (l OfferLimits) Validate() error {
+	if l.MaxConnections < 0 {
+		return errors.NotValidf("negative max connections %d", l.MaxConnections)
+	}
+	if l.SettingsChangesPerMinute < 0 {
+		return errors.NotValidf("negative settings change rate limit %d", l.SettingsChangesPerMinute)
+	}
+	for _, uuid := range l.AllowedConsumerModels {
+		if !utils.IsValidUUIDString(uuid) {
+			return errors.NotValidf("allowed consumer model UUID %q", uuid)
+		}
+	}
+	return nil
+}
+
+// CheckConsumer returns an error satisfying errors.IsUnauthorized if
+// the specified model may not relate to the offer.
+func (l OfferLimits) CheckConsumer(modelUUID string) error {
+	if len(l.AllowedConsumerModels) > 0 && !set.NewStrings(l.AllowedConsumerModels...).Contains(modelUUID) {
+		return errors.Unauthorizedf("model %q may not consume the offer", modelUUID)
+	}
+	return nil
+}
+
+// CheckConnections returns an error satisfying errors.IsQuotaLimitExceeded
+// if making another connection to an offer which already has the
+// specified number of connections would exceed the limits.
+func (l OfferLimits) CheckConnections(existing int) error {
+	if l.MaxConnections > 0 && existing >= l.MaxConnections {
+		return errors.QuotaLimitExceededf("offer already has the maximum of %d connection(s)", l.MaxConnections)
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package crossmodel_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/crossmodel"
+)
+
+type OfferLimitsSuite struct{}
+
+var _ = gc.Suite(&OfferLimitsSuite{})
+
+const (
+	modelUUID      = "deadbeef-0bad-400d-8000-4b1d0d06f00d"
+	otherModelUUID = "deadbeef-0bad-400d-8000-4b1d0d06f00e"
+)
+
+func (*OfferLimitsSuite) TestIsZero(c *gc.C) {
+	c.Assert(crossmodel.OfferLimits{}.IsZero(), jc.IsTrue)
+	c.Assert(crossmodel.OfferLimits{MaxConnections: 1}.IsZero(), jc.IsFalse)
+	c.Assert(crossmodel.OfferLimits{AllowedConsumerModels: []string{modelUUID}}.IsZero(), jc.IsFalse)
+}
+
+func (*OfferLimitsSuite) TestValidate(c *gc.C) {
+	for i, t := range []struct {
+		limits crossmodel.OfferLimits
+		err    string
+	}{{
+		limits: crossmodel.OfferLimits{},
+	}, {
+		limits: crossmodel.OfferLimits{
+			MaxConnections:           2,
+			AllowedConsumerModels:    []string{modelUUID},
+			SettingsChangesPerMinute: 10,
+		},
+	}, {
+		limits: crossmodel.OfferLimits{MaxConnections: -1},
+		err:    "negative max connections -1 not valid",
+	}, {
+		limits: crossmodel.OfferLimits{SettingsChangesPerMinute: -1},
+		err:    "negative settings change rate limit -1 not valid",
+	}, {
+		limits: crossmodel.OfferLimits{AllowedConsumerModels: []string{"foo"}},
+		err:    `allowed consumer model UUID "foo" not valid`,
+	}} {
+		c.Logf("test %d", i)
+		err := t.limits.Validate()
+		if t.err == "" {
+			c.Check(err, jc.ErrorIsNil)
+		} else {
+			c.Check(err, gc.ErrorMatches, t.err)
+			c.Check(err, jc.Satisfies, errors.IsNotValid)
+		}
+	}
+}
+
+func (*OfferLimitsSuite) TestCheckConsumerUnrestricted(c *gc.C) {
+	err := crossmodel.OfferLimits{}.CheckConsumer(modelUUID)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (*OfferLimitsSuite) TestCheckConsumerModels(c *gc.C) {
+	limits := crossmodel.OfferLimits{AllowedConsumerModels: []string{modelUUID}}
+	c.Assert(limits.CheckConsumer(modelUUID), jc.ErrorIsNil)
+	err := limits.CheckConsumer(otherModelUUID)
+	c.Assert(err, gc.ErrorMatches, `model "deadbeef-0bad-400d-8000-4b1d0d06f00e" may not consume the offer`)
+	c.Assert(err, jc.Satisfies, errors.IsUnauthorized)
+}
+
+func (*OfferLimitsSuite) TestCheckConnections(c *gc.C) {
+	c.Assert(crossmodel.OfferLimits{}.CheckConnections(100), jc.ErrorIsNil)
+	limits := crossmodel.OfferLimits{MaxConnections: 2}
+	c.Assert(limits.CheckConnections(1), jc.ErrorIsNil)
+	err := limits.CheckConnections(2)
+	c.Assert(err, gc.ErrorMatches, `offer already has the maximum of 2 connection\(s\)`)
+	c.Assert(err, jc.Satisfies, errors.IsQuotaLimitExceeded)
+}
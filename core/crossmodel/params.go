@@ -41,6 +41,9 @@ type ApplicationOfferDetails struct {
 
 	// Users are the users able to access the offer.
 	Users []OfferUserDetails
+
+	// Limits are the restrictions placed on consumers of the offer.
+	Limits OfferLimits
 }
 
 // OfferUserDetails holds the details about a user's access to an offer.
@@ -57,6 +57,14 @@ type GenerationApplication struct {
 	// TODO (manadart 2018-02-22) This data-type will evolve as more aspects
 	// of the application are made generational.
 	ConfigChanges map[string]interface{} `yaml:"config"`
+
+	// CharmURL is the charm that the application is upgraded to
+	// under the generation, if it differs from the current.
+	CharmURL string `yaml:"charm,omitempty"`
+
+	// Constraints are the application constraints set under the
+	// generation, if they differ from the current.
+	Constraints string `yaml:"constraints,omitempty"`
 }
 
 // Generation represents detail of a model generation including config changes.
@@ -67,6 +75,10 @@ type Generation struct {
 	// Created is the user who created the generation.
 	CreatedBy string `yaml:"created-by"`
 
+	// PromoteAt is the formatted time at which the generation is due to be
+	// automatically committed or aborted, if it has a promotion policy.
+	PromoteAt string `yaml:"promote-at,omitempty"`
+
 	// Applications is a collection of applications with changes in this
 	// generation including advanced units and modified configuration.
 	Applications []GenerationApplication `yaml:"applications"`
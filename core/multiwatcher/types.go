@@ -624,6 +624,8 @@ type BranchInfo struct {
 	Name          string
 	AssignedUnits map[string][]string
 	Config        map[string][]ItemChange
+	CharmURLs     map[string]string
+	PromoteAt     int64
 	Created       int64
 	CreatedBy     string
 	Completed     int64
@@ -663,5 +665,11 @@ func (i *BranchInfo) Clone() EntityInfo {
 			clone.Config[k] = cItems
 		}
 	}
+	if len(i.CharmURLs) > 0 {
+		clone.CharmURLs = make(map[string]string, len(i.CharmURLs))
+		for k, v := range i.CharmURLs {
+			clone.CharmURLs[k] = v
+		}
+	}
 	return &clone
 }
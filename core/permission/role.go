@@ -0,0 +1,168 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package permission
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/juju/errors"
+)
+
+// Operation classes group the facade methods that make a particular
+// kind of change to a model, so that roles need not list them all.
+const (
+	// DeployOperation covers deploying and scaling applications,
+	// and relating and exposing them.
+	DeployOperation = "deploy"
+
+	// ConfigOperation covers changing application and model config
+	// and constraints.
+	ConfigOperation = "config"
+
+	// RunActionOperation covers running and cancelling actions.
+	RunActionOperation = "run-action"
+
+	// ExecOperation covers running arbitrary commands on machines
+	// and units.
+	ExecOperation = "exec"
+
+	// RemoveOperation covers removing applications, units,
+	// relations and machines.
+	RemoveOperation = "remove"
+)
+
+// operationClasses maps facade methods, in the form "Facade.Method",
+// to the operation class they belong to.
+var operationClasses = map[string]string{
+	"Application.AddRelation":             DeployOperation,
+	"Application.AddUnits":                DeployOperation,
+	"Application.Consume":                 DeployOperation,
+	"Application.Deploy":                  DeployOperation,
+	"Application.Expose":                  DeployOperation,
+	"Application.ScaleApplications":       DeployOperation,
+	"Application.SetCharm":                DeployOperation,
+	"Application.SetRelationsSuspended":   DeployOperation,
+	"Application.Unexpose":                DeployOperation,
+	"Application.UpdateApplicationSeries": DeployOperation,
+	"Client.AddCharm":                     DeployOperation,
+	"Client.AddCharmWithAuthorization":    DeployOperation,
+	"Client.AddMachines":                  DeployOperation,
+	"Client.AddMachinesV2":                DeployOperation,
+	"MachineManager.AddMachines":          DeployOperation,
+
+	"Application.Set":                     ConfigOperation,
+	"Application.SetApplicationsConfig":   ConfigOperation,
+	"Application.SetConstraints":          ConfigOperation,
+	"Application.Unset":                   ConfigOperation,
+	"Application.UnsetApplicationsConfig": ConfigOperation,
+	"Application.Update":                  ConfigOperation,
+	"Client.SetModelConstraints":          ConfigOperation,
+	"ModelConfig.ModelSet":                ConfigOperation,
+	"ModelConfig.ModelUnset":              ConfigOperation,
+
+	"Action.Cancel":           RunActionOperation,
+	"Action.Enqueue":          RunActionOperation,
+	"Action.EnqueueOperation": RunActionOperation,
+
+	"Action.Run":              ExecOperation,
+	"Action.RunOnAllMachines": ExecOperation,
+
+	"Application.Destroy":                     RemoveOperation,
+	"Application.DestroyApplication":          RemoveOperation,
+	"Application.DestroyConsumedApplications": RemoveOperation,
+	"Application.DestroyRelation":             RemoveOperation,
+	"Application.DestroyUnit":                 RemoveOperation,
+	"Application.DestroyUnits":                RemoveOperation,
+	"Client.DestroyMachines":                  RemoveOperation,
+	"MachineManager.DestroyMachine":           RemoveOperation,
+	"MachineManager.DestroyMachineWithParams": RemoveOperation,
+	"MachineManager.ForceDestroyMachine":      RemoveOperation,
+}
+
+// OperationClasses returns the sorted names of the operation classes.
+func OperationClasses() []string {
+	return []string{
+		ConfigOperation,
+		DeployOperation,
+		ExecOperation,
+		RemoveOperation,
+		RunActionOperation,
+	}
+}
+
+// OperationClass returns the operation class that the given facade
+// method belongs to, or "" if it belongs to none.
+func OperationClass(facadeName, methodName string) string {
+	return operationClasses[facadeName+"."+methodName]
+}
+
+var (
+	validRoleName        = regexp.MustCompile(`^[a-z][a-z0-9]*(-[a-z0-9]+)*$`)
+	validFacadeOperation = regexp.MustCompile(`^[A-Z][A-Za-z0-9]*\.[A-Z][A-Za-z0-9]*$`)
+)
+
+// Role is a custom role: a named set of operations that may be
+// assigned to users of a model, in addition to their access level.
+type Role struct {
+	// Name is the name of the role.
+	Name string
+
+	// Access is the greatest level of access to the model that the
+	// role grants for the calls it allows. A role never grants more
+	// than this, even if a call would otherwise require more.
+	Access Access
+
+	// Operations holds the operation classes, and the individual
+	// facade methods in the form "Facade.Method", that the role
+	// allows.
+	Operations []string
+}
+
+// ValidateRoleName returns an error if name is not a valid role name.
+func ValidateRoleName(name string) error {
+	if !validRoleName.MatchString(name) {
+		return errors.NotValidf("role name %q", name)
+	}
+	return nil
+}
+
+// Validate returns an error if the role is not valid.
+func (r Role) Validate() error {
+	if err := ValidateRoleName(r.Name); err != nil {
+		return errors.Trace(err)
+	}
+	if err := ValidateModelAccess(r.Access); err != nil {
+		return errors.Annotatef(err, "role %q", r.Name)
+	}
+	if len(r.Operations) == 0 {
+		return errors.NotValidf("role %q without operations", r.Name)
+	}
+	classes := OperationClasses()
+	for _, op := range r.Operations {
+		if validFacadeOperation.MatchString(op) {
+			continue
+		}
+		i := sort.SearchStrings(classes, op)
+		if i == len(classes) || classes[i] != op {
+			return errors.NotValidf("operation %q (expected one of %s, or Facade.Method)",
+				op, strings.Join(classes, ", "))
+		}
+	}
+	return nil
+}
+
+// Allows reports whether the role allows calls to the given facade
+// method, either by naming it or by naming its operation class.
+func (r Role) Allows(facadeName, methodName string) bool {
+	method := facadeName + "." + methodName
+	class := OperationClass(facadeName, methodName)
+	for _, op := range r.Operations {
+		if op == method || (class != "" && op == class) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,72 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package permission_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/permission"
+)
+
+type roleSuite struct{}
+
+var _ = gc.Suite(&roleSuite{})
+
+func (*roleSuite) TestValidateRoleName(c *gc.C) {
+	for _, name := range []string{"app-team", "ops", "team2"} {
+		c.Check(permission.ValidateRoleName(name), jc.ErrorIsNil)
+	}
+	for _, name := range []string{"", "App", "2team", "app--team", "app-", "app team"} {
+		c.Check(permission.ValidateRoleName(name), gc.ErrorMatches, `role name ".*" not valid`)
+	}
+}
+
+func (*roleSuite) TestValidate(c *gc.C) {
+	role := permission.Role{
+		Name:       "app-team",
+		Access:     permission.WriteAccess,
+		Operations: []string{"run-action", "config", "Application.Expose"},
+	}
+	c.Assert(role.Validate(), jc.ErrorIsNil)
+
+	role.Access = ""
+	c.Assert(role.Validate(), gc.ErrorMatches, `role "app-team": "" model access not valid`)
+
+	role.Access = permission.SuperuserAccess
+	c.Assert(role.Validate(), gc.ErrorMatches, `role "app-team": "superuser" model access not valid`)
+	role.Access = permission.ReadAccess
+
+	role.Operations = nil
+	c.Assert(role.Validate(), gc.ErrorMatches, `role "app-team" without operations not valid`)
+
+	role.Operations = []string{"destroy"}
+	c.Assert(role.Validate(), gc.ErrorMatches,
+		`operation "destroy" \(expected one of config, deploy, exec, remove, run-action, or Facade.Method\) not valid`)
+
+	role.Operations = []string{"application.expose"}
+	c.Assert(role.Validate(), gc.ErrorMatches, `operation "application.expose" .* not valid`)
+}
+
+func (*roleSuite) TestAllows(c *gc.C) {
+	role := permission.Role{
+		Name:       "app-team",
+		Operations: []string{"run-action", "Application.Expose"},
+	}
+	c.Check(role.Allows("Action", "Enqueue"), jc.IsTrue)
+	c.Check(role.Allows("Action", "Cancel"), jc.IsTrue)
+	c.Check(role.Allows("Application", "Expose"), jc.IsTrue)
+	c.Check(role.Allows("Action", "Run"), jc.IsFalse)
+	c.Check(role.Allows("Application", "DestroyApplication"), jc.IsFalse)
+	c.Check(role.Allows("Application", "Unexpose"), jc.IsFalse)
+	c.Check(role.Allows("Pinger", "Ping"), jc.IsFalse)
+}
+
+func (*roleSuite) TestOperationClass(c *gc.C) {
+	c.Check(permission.OperationClass("Application", "Deploy"), gc.Equals, permission.DeployOperation)
+	c.Check(permission.OperationClass("ModelConfig", "ModelSet"), gc.Equals, permission.ConfigOperation)
+	c.Check(permission.OperationClass("Action", "Run"), gc.Equals, permission.ExecOperation)
+	c.Check(permission.OperationClass("MachineManager", "DestroyMachineWithParams"), gc.Equals, permission.RemoveOperation)
+	c.Check(permission.OperationClass("Client", "FullStatus"), gc.Equals, "")
+}
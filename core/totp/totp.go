@@ -0,0 +1,116 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package totp implements the time-based one-time password algorithm
+// described in RFC 6238, as used by authenticator apps, with the
+// common parameters: HMAC-SHA1, 30 second steps and 6 digit codes.
+package totp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+)
+
+const (
+	// Period is how long each code is valid for.
+	Period = 30 * time.Second
+
+	// Digits is the number of digits in a code.
+	Digits = 6
+
+	// Skew is the number of steps either side of the current one
+	// that codes are accepted from, to allow for clock drift and
+	// slow typists.
+	Skew = 1
+
+	secretSize = 20
+)
+
+var encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateSecret returns a new random shared secret, base32 encoded
+// so that it may be entered into an authenticator app by hand.
+func GenerateSecret() (string, error) {
+	var buf [secretSize]byte
+	if _, err := rand.Read(buf[:]); err != nil {
+		return "", errors.Annotate(err, "cannot generate secret")
+	}
+	return encoding.EncodeToString(buf[:]), nil
+}
+
+// Step returns the time step that contains t.
+func Step(t time.Time) int64 {
+	return t.Unix() / int64(Period/time.Second)
+}
+
+// Code returns the code for the given secret at time t.
+func Code(secret string, t time.Time) (string, error) {
+	key, err := decodeSecret(secret)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return code(key, Step(t)), nil
+}
+
+// Validate checks the code against the given secret at time t,
+// accepting codes from up to Skew steps either side. It returns the
+// step the code matched, which callers should record to refuse the
+// same code being used twice.
+func Validate(secret, value string, t time.Time) (int64, error) {
+	key, err := decodeSecret(secret)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	value = strings.Replace(value, " ", "", -1)
+	if len(value) != Digits {
+		return 0, errors.NotValidf("code")
+	}
+	now := Step(t)
+	for step := now - Skew; step <= now+Skew; step++ {
+		if subtle.ConstantTimeCompare([]byte(code(key, step)), []byte(value)) == 1 {
+			return step, nil
+		}
+	}
+	return 0, errors.NotValidf("code")
+}
+
+// URI returns the otpauth URI for the secret, which authenticator apps
+// accept, usually as a QR code, to enrol the account.
+func URI(issuer, account, secret string) string {
+	label := url.PathEscape(issuer + ":" + account)
+	query := url.Values{
+		"secret": {secret},
+		"issuer": {issuer},
+	}
+	return "otpauth://totp/" + label + "?" + query.Encode()
+}
+
+func decodeSecret(secret string) ([]byte, error) {
+	secret = strings.ToUpper(strings.TrimRight(strings.Replace(secret, " ", "", -1), "="))
+	key, err := encoding.DecodeString(secret)
+	if err != nil {
+		return nil, errors.NotValidf("secret")
+	}
+	return key, nil
+}
+
+func code(key []byte, step int64) string {
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], uint64(step))
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	return fmt.Sprintf("%0*d", Digits, value%1000000)
+}
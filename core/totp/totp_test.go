@@ -0,0 +1,80 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package totp_test
+
+import (
+	"time"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/totp"
+)
+
+type totpSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&totpSuite{})
+
+// rfcSecret is the base32 encoding of the SHA1 key used by the test
+// vectors in RFC 6238, "12345678901234567890".
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func (s *totpSuite) TestCode(c *gc.C) {
+	for _, test := range []struct {
+		unix int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+	} {
+		code, err := totp.Code(rfcSecret, time.Unix(test.unix, 0))
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(code, gc.Equals, test.code, gc.Commentf("time %d", test.unix))
+	}
+}
+
+func (s *totpSuite) TestValidate(c *gc.C) {
+	now := time.Unix(1234567890, 0)
+	step, err := totp.Validate(rfcSecret, "005924", now)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(step, gc.Equals, totp.Step(now))
+
+	// Codes from the neighbouring steps are accepted.
+	step, err = totp.Validate(rfcSecret, "005 924", now.Add(totp.Period))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(step, gc.Equals, totp.Step(now))
+
+	_, err = totp.Validate(rfcSecret, "005924", now.Add(2*totp.Period))
+	c.Assert(err, gc.ErrorMatches, "code not valid")
+	_, err = totp.Validate(rfcSecret, "123", now)
+	c.Assert(err, gc.ErrorMatches, "code not valid")
+	_, err = totp.Validate("not-base32!", "005924", now)
+	c.Assert(err, gc.ErrorMatches, "secret not valid")
+}
+
+func (s *totpSuite) TestGenerateSecret(c *gc.C) {
+	secret, err := totp.GenerateSecret()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(secret, gc.Matches, "[A-Z2-7]{32}")
+	other, err := totp.GenerateSecret()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(other, gc.Not(gc.Equals), secret)
+
+	now := time.Now()
+	code, err := totp.Code(secret, now)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = totp.Validate(secret, code, now)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *totpSuite) TestURI(c *gc.C) {
+	c.Assert(totp.URI("Juju ctrl", "bob", rfcSecret), gc.Equals,
+		"otpauth://totp/Juju%20ctrl:bob?issuer=Juju+ctrl&secret="+rfcSecret)
+}
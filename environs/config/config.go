@@ -6,6 +6,7 @@ package config
 import (
 	"fmt"
 	"net"
+	"net/url"
 	"os"
 	"strings"
 	"time"
@@ -87,6 +88,15 @@ const (
 	// of OS image metadata for containers.
 	ContainerImageMetadataURLKey = "container-image-metadata-url"
 
+	// CharmHubURLKey is the key used to specify the location of the
+	// charmhub-compatible store queried by the controller.
+	CharmHubURLKey = "charmhub-url"
+
+	// CharmRepositoryURLKey is the key used to specify the location of
+	// a charm repository index that charm store URLs are resolved
+	// against instead of the charm store.
+	CharmRepositoryURLKey = "charm-repository-url"
+
 	// GUIStreamKey stores the key used to specify the stream
 	// to used when fetching a gui tarball.
 	GUIStreamKey = "gui-stream"
@@ -236,6 +246,11 @@ const (
 	// provisioning machines.
 	CloudInitUserDataKey = "cloudinit-userdata"
 
+	// CloudInitUserDataOverlaysKey is the key to specify a yaml list of
+	// cloud-init user data overlays, each merged into the cloud-config
+	// data of new machines whose constraints match those of the overlay.
+	CloudInitUserDataOverlaysKey = "cloudinit-userdata-overlays"
+
 	// BackupDirKey specifies the backup working directory.
 	BackupDirKey = "backup-dir"
 
@@ -418,6 +433,9 @@ const (
 	// DefaultUpdateStatusHookInterval is the default value for UpdateStatusHookInterval
 	DefaultUpdateStatusHookInterval = "5m"
 
+	// DefaultCharmHubURL is the default value for CharmHubURLKey.
+	DefaultCharmHubURL = "https://api.charmhub.io"
+
 	DefaultActionResultsAge = "336h" // 2 weeks
 
 	DefaultActionResultsSize = "5G"
@@ -465,6 +483,7 @@ var defaultConfigValues = map[string]interface{}{
 	EgressSubnets:                 "",
 	FanConfig:                     "",
 	CloudInitUserDataKey:          "",
+	CloudInitUserDataOverlaysKey:  "",
 	ContainerInheritPropertiesKey: "",
 	BackupDirKey:                  "",
 	LXDSnapChannel:                "latest/stable",
@@ -476,6 +495,8 @@ var defaultConfigValues = map[string]interface{}{
 	AgentMetadataURLKey:          "",
 	ContainerImageStreamKey:      "released",
 	ContainerImageMetadataURLKey: "",
+	CharmHubURLKey:               "",
+	CharmRepositoryURLKey:        "",
 
 	// Log forward settings.
 	LogForwardEnabled: false,
@@ -692,30 +713,43 @@ func Validate(cfg, old *Config) error {
 		if err != nil {
 			return errors.Annotate(err, "cloudinit-userdata")
 		}
+		if err := validateCloudInitUserData(userDataMap); err != nil {
+			return errors.Annotate(err, "cloudinit-userdata")
+		}
+	}
 
-		// if there packages, ensure they are strings
-		if packages, ok := userDataMap["packages"].([]interface{}); ok {
-			for _, v := range packages {
-				checker := schema.String()
-				if _, err := checker.Coerce(v, nil); err != nil {
-					return errors.Annotate(err, "cloudinit-userdata: packages must be a list of strings")
-				}
+	if raw, ok := cfg.defined[CloudInitUserDataOverlaysKey].(string); ok && raw != "" {
+		overlays, err := parseUserDataOverlays(raw)
+		if err != nil {
+			return errors.Annotate(err, "cloudinit-userdata-overlays")
+		}
+		for i, overlay := range overlays {
+			if err := validateCloudInitUserData(overlay.UserData); err != nil {
+				return errors.Annotatef(err, "cloudinit-userdata-overlays: overlay %d", i+1)
 			}
 		}
+	}
 
-		// error if users is specified
-		if _, ok := userDataMap["users"]; ok {
-			return errors.New("cloudinit-userdata: users not allowed")
+	if raw, ok := cfg.defined[CharmHubURLKey].(string); ok && raw != "" {
+		u, err := url.Parse(raw)
+		if err != nil {
+			return errors.Annotate(err, "charmhub-url")
 		}
-
-		// error if runcmd is specified
-		if _, ok := userDataMap["runcmd"]; ok {
-			return errors.New("cloudinit-userdata: runcmd not allowed, use preruncmd or postruncmd instead")
+		if u.Scheme == "" || u.Host == "" {
+			return errors.NotValidf("charmhub-url %q", raw)
 		}
+	}
 
-		// error if bootcmd is specified
-		if _, ok := userDataMap["bootcmd"]; ok {
-			return errors.New("cloudinit-userdata: bootcmd not allowed")
+	if raw, ok := cfg.defined[CharmRepositoryURLKey].(string); ok && raw != "" {
+		u, err := url.Parse(raw)
+		if err != nil {
+			return errors.Annotate(err, "charm-repository-url")
+		}
+		switch {
+		case u.Scheme == "file" && u.Path != "":
+		case (u.Scheme == "http" || u.Scheme == "https") && u.Host != "":
+		default:
+			return errors.NotValidf("charm-repository-url %q", raw)
 		}
 	}
 
@@ -1159,6 +1193,24 @@ func (c *Config) ContainerImageMetadataURL() (string, bool) {
 	return "", false
 }
 
+// CharmHubURL returns the URL of the charmhub-compatible store queried
+// by the controller, which is DefaultCharmHubURL unless set.
+func (c *Config) CharmHubURL() string {
+	if url, ok := c.defined[CharmHubURLKey].(string); ok && url != "" {
+		return url
+	}
+	return DefaultCharmHubURL
+}
+
+// CharmRepositoryURL returns the location of the charm repository index
+// that charm store URLs are resolved against, and whether it is set.
+func (c *Config) CharmRepositoryURL() (string, bool) {
+	if url, ok := c.defined[CharmRepositoryURLKey].(string); ok && url != "" {
+		return url, true
+	}
+	return "", false
+}
+
 // Development returns whether the environment is in development mode.
 func (c *Config) Development() bool {
 	value, _ := c.defined["development"].(bool)
@@ -1531,6 +1583,8 @@ var alwaysOptional = schema.Defaults{
 	AgentMetadataURLKey:           schema.Omit,
 	ContainerImageStreamKey:       schema.Omit,
 	ContainerImageMetadataURLKey:  schema.Omit,
+	CharmHubURLKey:                schema.Omit,
+	CharmRepositoryURLKey:         schema.Omit,
 	"default-series":              schema.Omit,
 	"development":                 schema.Omit,
 	"ssl-hostname-verification":   schema.Omit,
@@ -1550,6 +1604,7 @@ var alwaysOptional = schema.Defaults{
 	EgressSubnets:                 schema.Omit,
 	FanConfig:                     schema.Omit,
 	CloudInitUserDataKey:          schema.Omit,
+	CloudInitUserDataOverlaysKey:  schema.Omit,
 	ContainerInheritPropertiesKey: schema.Omit,
 	BackupDirKey:                  schema.Omit,
 	DefaultSpace:                  schema.Omit,
@@ -1888,6 +1943,16 @@ global or per instance security groups.`,
 		Type:        environschema.Tstring,
 		Group:       environschema.EnvironGroup,
 	},
+	CharmHubURLKey: {
+		Description: "The URL of the charmhub-compatible store queried by the controller",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	CharmRepositoryURLKey: {
+		Description: "The file://, http:// or https:// URL of a charm repository index used to resolve and fetch charm store charms in place of the charm store",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
 	ContainerImageStreamKey: {
 		Description: `The simplestreams stream used to identify which image ids to search when starting a container.`,
 		Type:        environschema.Tstring,
@@ -2043,6 +2108,11 @@ data of the store. (default false)`,
 		Type:        environschema.Tstring,
 		Group:       environschema.EnvironGroup,
 	},
+	CloudInitUserDataOverlaysKey: {
+		Description: "List of cloud-init user-data overlays (in yaml format), each with constraints to match and user-data to be merged into the userdata of new machines with matching constraints",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
 	ContainerInheritPropertiesKey: {
 		Description: "List of properties to be copied from the host machine to new containers created in this model (comma-separated)",
 		Type:        environschema.Tstring,
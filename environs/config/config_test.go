@@ -19,6 +19,7 @@ import (
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/environschema.v1"
 
+	"github.com/juju/juju/core/constraints"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/juju/osenv"
 	"github.com/juju/juju/testing"
@@ -954,6 +955,124 @@ func (test configValidateCloudInitUserDataTest) checkNew(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 }
 
+func (s *ConfigSuite) TestValidateCloudInitUserDataOverlays(c *gc.C) {
+	for i, test := range []struct {
+		about string
+		value string
+		err   string
+	}{{
+		about: "valid overlays",
+		value: validCloudInitUserDataOverlays,
+	}, {
+		about: "not a list",
+		value: "packages: [foo]",
+		err:   `(?s)cloudinit-userdata-overlays: must be a valid YAML list of overlays: .*cannot unmarshal !!map.*`,
+	}, {
+		about: "unknown field",
+		value: "- match: arch=amd64\n  user-data: {packages: [foo]}",
+		err:   `(?s)cloudinit-userdata-overlays: must be a valid YAML list of overlays: .*field user-data not found.*`,
+	}, {
+		about: "invalid match",
+		value: "- match: colour=blue\n  userdata: {packages: [foo]}",
+		err:   `cloudinit-userdata-overlays: overlay 1: invalid match: unknown constraint "colour"`,
+	}, {
+		about: "no userdata",
+		value: "- match: arch=amd64",
+		err:   `cloudinit-userdata-overlays: overlay 1: no userdata specified`,
+	}, {
+		about: "runcmd",
+		value: "- userdata: {packages: [foo]}\n- userdata: {runcmd: [ls]}",
+		err:   `cloudinit-userdata-overlays: overlay 2: runcmd not allowed, use preruncmd or postruncmd instead`,
+	}, {
+		about: "postruncmd not strings",
+		value: "- userdata: {postruncmd: [[ls]]}",
+		err:   `cloudinit-userdata-overlays: overlay 1: postruncmd must be a list of strings: .*`,
+	}, {
+		about: "write_files without path",
+		value: "- userdata: {write_files: [{content: foo}]}",
+		err:   `cloudinit-userdata-overlays: overlay 1: write_files entry 1: path not specified`,
+	}} {
+		c.Logf("test %d. %s", i, test.about)
+		_, err := config.New(config.UseDefaults, testing.Attrs{
+			"type": "my-type", "name": "my-name",
+			"uuid":                              testing.ModelTag.Id(),
+			config.CloudInitUserDataOverlaysKey: test.value,
+		})
+		if test.err != "" {
+			c.Check(err, gc.ErrorMatches, test.err)
+		} else {
+			c.Check(err, jc.ErrorIsNil)
+		}
+	}
+}
+
+func (s *ConfigSuite) TestCharmHubURL(c *gc.C) {
+	for i, test := range []struct {
+		value string
+		want  string
+		err   string
+	}{{
+		want: config.DefaultCharmHubURL,
+	}, {
+		value: "http://10.0.0.1:8080",
+		want:  "http://10.0.0.1:8080",
+	}, {
+		value: "charmhub.local",
+		err:   `charmhub-url "charmhub.local" not valid`,
+	}, {
+		value: "http://[::1",
+		err:   `charmhub-url: parse .*`,
+	}} {
+		c.Logf("test %d. %q", i, test.value)
+		cfg, err := config.New(config.UseDefaults, testing.Attrs{
+			"type": "my-type", "name": "my-name",
+			"uuid":                testing.ModelTag.Id(),
+			config.CharmHubURLKey: test.value,
+		})
+		if test.err != "" {
+			c.Check(err, gc.ErrorMatches, test.err)
+			continue
+		}
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(cfg.CharmHubURL(), gc.Equals, test.want)
+	}
+}
+
+func (s *ConfigSuite) TestCharmRepositoryURL(c *gc.C) {
+	for i, test := range []struct {
+		value string
+		err   string
+	}{{}, {
+		value: "file:///srv/charms",
+	}, {
+		value: "https://charms.example.com/repo",
+	}, {
+		value: "/srv/charms",
+		err:   `charm-repository-url "/srv/charms" not valid`,
+	}, {
+		value: "ftp://charms.example.com",
+		err:   `charm-repository-url "ftp://charms.example.com" not valid`,
+	}, {
+		value: "http://[::1",
+		err:   `charm-repository-url: parse .*`,
+	}} {
+		c.Logf("test %d. %q", i, test.value)
+		cfg, err := config.New(config.UseDefaults, testing.Attrs{
+			"type": "my-type", "name": "my-name",
+			"uuid":                       testing.ModelTag.Id(),
+			config.CharmRepositoryURLKey: test.value,
+		})
+		if test.err != "" {
+			c.Check(err, gc.ErrorMatches, test.err)
+			continue
+		}
+		c.Assert(err, jc.ErrorIsNil)
+		url, ok := cfg.CharmRepositoryURL()
+		c.Check(url, gc.Equals, test.value)
+		c.Check(ok, gc.Equals, test.value != "")
+	}
+}
+
 func (s *ConfigSuite) addJujuFiles(c *gc.C) {
 	s.FakeHomeSuite.Home.AddFiles(c, []gitjujutesting.TestFile{
 		{".ssh/id_rsa.pub", "rsa\n"},
@@ -1349,6 +1468,39 @@ func (s *ConfigSuite) TestCloudInitUserDataFromEnvironment(c *gc.C) {
 	)
 }
 
+func (s *ConfigSuite) TestCloudInitUserDataFor(c *gc.C) {
+	cfg := newTestConfig(c, testing.Attrs{
+		config.CloudInitUserDataKey:         validCloudInitUserData,
+		config.CloudInitUserDataOverlaysKey: validCloudInitUserDataOverlays,
+	})
+	c.Assert(cfg.CloudInitUserDataOverlays(), gc.HasLen, 2)
+
+	// No overlay matches.
+	c.Assert(cfg.CloudInitUserDataFor(constraints.MustParse("arch=amd64 mem=4G")), gc.DeepEquals, cfg.CloudInitUserData())
+
+	// The arm64 overlay matches.
+	c.Assert(cfg.CloudInitUserDataFor(constraints.MustParse("arch=arm64")), gc.DeepEquals, map[string]interface{}{
+		"packages":        []interface{}{"python-keystoneclient", "python-glanceclient", "arm-tools"},
+		"preruncmd":       []interface{}{"mkdir /tmp/preruncmd", "mkdir /tmp/preruncmd2"},
+		"postruncmd":      []interface{}{"mkdir /tmp/postruncmd", "mkdir /tmp/postruncmd2"},
+		"package_upgrade": true,
+	})
+
+	// Both overlays match, and are merged in order.
+	c.Assert(cfg.CloudInitUserDataFor(constraints.MustParse("arch=arm64 mem=32G tags=gpu,fast")), gc.DeepEquals, map[string]interface{}{
+		"packages":        []interface{}{"python-keystoneclient", "python-glanceclient", "arm-tools", "nvidia-driver"},
+		"preruncmd":       []interface{}{"mkdir /tmp/preruncmd", "mkdir /tmp/preruncmd2", "modprobe nvidia"},
+		"postruncmd":      []interface{}{"mkdir /tmp/postruncmd", "mkdir /tmp/postruncmd2"},
+		"package_upgrade": true,
+		"write_files": []interface{}{
+			map[string]interface{}{"path": "/etc/gpu.conf", "content": "enabled"},
+		},
+	})
+
+	// Not enough memory for the gpu overlay.
+	c.Assert(cfg.CloudInitUserDataFor(constraints.MustParse("mem=8G tags=gpu")), gc.DeepEquals, cfg.CloudInitUserData())
+}
+
 func (s *ConfigSuite) TestContainerInheritProperties(c *gc.C) {
 	cfg := newTestConfig(c, testing.Attrs{
 		"container-inherit-properties": "ca-certs,apt-primary",
@@ -1460,6 +1612,24 @@ postruncmd:
 package_upgrade: false
 `[1:]
 
+var validCloudInitUserDataOverlays = `
+- match: arch=arm64
+  userdata:
+    packages:
+      - arm-tools
+      - python-glanceclient
+    package_upgrade: true
+- match: mem=16G tags=gpu
+  userdata:
+    packages:
+      - nvidia-driver
+    preruncmd:
+      - modprobe nvidia
+    write_files:
+      - path: /etc/gpu.conf
+        content: enabled
+`[1:]
+
 var invalidCloudInitUserDataPackageInt = `
 packages:
     - 76
@@ -0,0 +1,235 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package config
+
+import (
+	"fmt"
+
+	"github.com/juju/collections/set"
+	"github.com/juju/errors"
+	"github.com/juju/schema"
+	"github.com/juju/utils"
+	"gopkg.in/yaml.v2"
+
+	"github.com/juju/juju/core/constraints"
+)
+
+// UserDataOverlay holds cloud-init user data that is merged into the
+// model's cloudinit-userdata for machines whose constraints match.
+type UserDataOverlay struct {
+	// Match holds the constraints a machine must satisfy for the
+	// overlay to apply. String attributes must be equal, numeric
+	// attributes must be at least the given value, and list
+	// attributes must contain all of the given values. An empty
+	// match applies to all machines.
+	Match constraints.Value
+
+	// UserData holds the cloud-init user data to be merged.
+	UserData map[string]interface{}
+}
+
+// rawUserDataOverlay is the YAML representation of an overlay.
+type rawUserDataOverlay struct {
+	Match    string                 `yaml:"match"`
+	UserData map[string]interface{} `yaml:"userdata"`
+}
+
+// parseUserDataOverlays parses the YAML list of overlays held in
+// the cloudinit-userdata-overlays attribute.
+func parseUserDataOverlays(in string) ([]UserDataOverlay, error) {
+	var raw []rawUserDataOverlay
+	if err := yaml.UnmarshalStrict([]byte(in), &raw); err != nil {
+		return nil, errors.Annotate(err, "must be a valid YAML list of overlays")
+	}
+	overlays := make([]UserDataOverlay, len(raw))
+	for i, r := range raw {
+		match, err := constraints.Parse(r.Match)
+		if err != nil {
+			return nil, errors.Annotatef(err, "overlay %d: invalid match", i+1)
+		}
+		if len(r.UserData) == 0 {
+			return nil, errors.Errorf("overlay %d: no userdata specified", i+1)
+		}
+		userData, err := utils.ConformYAML(r.UserData)
+		if err != nil {
+			return nil, errors.Annotatef(err, "overlay %d", i+1)
+		}
+		overlays[i] = UserDataOverlay{
+			Match:    match,
+			UserData: userData.(map[string]interface{}),
+		}
+	}
+	return overlays, nil
+}
+
+// validateCloudInitUserData checks that user data supplied by the user
+// only contains attributes that Juju is able to merge with its own.
+func validateCloudInitUserData(userDataMap map[string]interface{}) error {
+	// if there are packages or pre/post run commands, ensure they are strings
+	for _, key := range []string{"packages", "preruncmd", "postruncmd"} {
+		if err := validateStringList(userDataMap, key); err != nil {
+			return errors.Trace(err)
+		}
+	}
+
+	if files, ok := userDataMap["write_files"]; ok {
+		list, ok := files.([]interface{})
+		if !ok {
+			return errors.New("write_files must be a list")
+		}
+		for i, f := range list {
+			file, ok := f.(map[string]interface{})
+			if !ok {
+				return errors.Errorf("write_files entry %d must be a map", i+1)
+			}
+			if path, _ := file["path"].(string); path == "" {
+				return errors.Errorf("write_files entry %d: path not specified", i+1)
+			}
+		}
+	}
+
+	// error if users is specified
+	if _, ok := userDataMap["users"]; ok {
+		return errors.New("users not allowed")
+	}
+
+	// error if runcmd is specified
+	if _, ok := userDataMap["runcmd"]; ok {
+		return errors.New("runcmd not allowed, use preruncmd or postruncmd instead")
+	}
+
+	// error if bootcmd is specified
+	if _, ok := userDataMap["bootcmd"]; ok {
+		return errors.New("bootcmd not allowed")
+	}
+	return nil
+}
+
+func validateStringList(userDataMap map[string]interface{}, key string) error {
+	value, ok := userDataMap[key]
+	if !ok {
+		return nil
+	}
+	list, ok := value.([]interface{})
+	if !ok {
+		return errors.Errorf("%s must be a list of strings", key)
+	}
+	checker := schema.String()
+	for _, v := range list {
+		if _, err := checker.Coerce(v, nil); err != nil {
+			return errors.Annotatef(err, "%s must be a list of strings", key)
+		}
+	}
+	return nil
+}
+
+// CloudInitUserDataOverlays returns the overlays, in the order they
+// were specified, that are merged into the cloud-init user data of
+// machines with matching constraints.
+func (c *Config) CloudInitUserDataOverlays() []UserDataOverlay {
+	raw := c.asString(CloudInitUserDataOverlaysKey)
+	if raw == "" {
+		return nil
+	}
+	// The raw data has already passed Validate()
+	overlays, _ := parseUserDataOverlays(raw)
+	return overlays
+}
+
+// CloudInitUserDataFor returns the cloud-init user data for a machine
+// with the given constraints: the model's cloudinit-userdata with each
+// matching overlay merged in, in the order the overlays were specified.
+//
+// List attributes (packages, preruncmd, postruncmd and write_files)
+// are appended to, with duplicate packages dropped; any other
+// attribute set by an overlay replaces the existing value.
+func (c *Config) CloudInitUserDataFor(cons constraints.Value) map[string]interface{} {
+	userData := c.CloudInitUserData()
+	for _, overlay := range c.CloudInitUserDataOverlays() {
+		if !overlay.Matches(cons) {
+			continue
+		}
+		userData = MergeCloudInitUserData(userData, overlay.UserData)
+	}
+	return userData
+}
+
+// Matches reports whether a machine with the given constraints
+// satisfies the overlay's match constraints.
+func (o UserDataOverlay) Matches(cons constraints.Value) bool {
+	m := o.Match
+	return matchString(m.Arch, cons.Arch) &&
+		matchString(m.InstanceType, cons.InstanceType) &&
+		matchString(m.RootDiskSource, cons.RootDiskSource) &&
+		matchString(m.VirtType, cons.VirtType) &&
+		(m.Container == nil || (cons.Container != nil && *m.Container == *cons.Container)) &&
+		matchAtLeast(m.CpuCores, cons.CpuCores) &&
+		matchAtLeast(m.CpuPower, cons.CpuPower) &&
+		matchAtLeast(m.Mem, cons.Mem) &&
+		matchAtLeast(m.RootDisk, cons.RootDisk) &&
+		matchAll(m.Tags, cons.Tags) &&
+		matchAll(m.Spaces, cons.Spaces) &&
+		matchAll(m.Zones, cons.Zones)
+}
+
+func matchString(want, have *string) bool {
+	return want == nil || (have != nil && *want == *have)
+}
+
+func matchAtLeast(want, have *uint64) bool {
+	return want == nil || (have != nil && *have >= *want)
+}
+
+func matchAll(want, have *[]string) bool {
+	if want == nil {
+		return true
+	}
+	if have == nil {
+		return len(*want) == 0
+	}
+	return set.NewStrings(*want...).Difference(set.NewStrings(*have...)).IsEmpty()
+}
+
+// MergeCloudInitUserData returns the result of merging the overlay
+// user data into base. Neither argument is modified.
+func MergeCloudInitUserData(base, overlay map[string]interface{}) map[string]interface{} {
+	if len(base) == 0 && len(overlay) == 0 {
+		return base
+	}
+	result := make(map[string]interface{})
+	for k, v := range base {
+		result[k] = v
+	}
+	for k, v := range overlay {
+		switch k {
+		case "packages":
+			result[k] = appendUnique(asList(result[k]), asList(v))
+		case "preruncmd", "postruncmd", "write_files":
+			result[k] = append(append([]interface{}{}, asList(result[k])...), asList(v)...)
+		default:
+			result[k] = v
+		}
+	}
+	return result
+}
+
+func asList(v interface{}) []interface{} {
+	list, _ := v.([]interface{})
+	return list
+}
+
+func appendUnique(base, extra []interface{}) []interface{} {
+	result := append([]interface{}{}, base...)
+	seen := make(map[string]bool)
+	for _, v := range base {
+		seen[fmt.Sprint(v)] = true
+	}
+	for _, v := range extra {
+		if key := fmt.Sprint(v); !seen[key] {
+			seen[key] = true
+			result = append(result, v)
+		}
+	}
+	return result
+}
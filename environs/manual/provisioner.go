@@ -12,6 +12,7 @@ import (
 	"github.com/juju/utils/winrm"
 
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/instance"
 )
 
 var (
@@ -57,6 +58,28 @@ type ProvisionMachineArgs struct {
 	*params.UpdateBehavior
 }
 
+// ReenlistMachineFunc re-enlists a manually provisioned machine
+// whose host has been reinstalled.
+type ReenlistMachineFunc func(ReenlistMachineArgs) error
+
+// ReenlistMachineArgs holds the arguments for re-enlisting a manually
+// provisioned machine under its existing machine ID.
+type ReenlistMachineArgs struct {
+	ProvisionMachineArgs
+
+	// MachineId is the ID of the machine to re-enlist.
+	MachineId string
+
+	// Reenlister records the re-enlistment with the controller.
+	Reenlister MachineReenlister
+}
+
+// MachineReenlister defines the method needed to prepare an existing
+// manually provisioned machine to be provisioned again.
+type MachineReenlister interface {
+	ReenlistMachine(machineId, nonce, series string, hc instance.HardwareCharacteristics) error
+}
+
 // WinRMArgs used for providing special context
 // on how we interface with the windows machine
 type WinRMArgs struct {
@@ -5,9 +5,12 @@
 package sshprovisioner
 
 import (
+	"github.com/juju/errors"
 	"github.com/juju/loggo"
+	"github.com/juju/utils"
 
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/instance"
 	"github.com/juju/juju/environs/manual"
 )
 
@@ -68,3 +71,50 @@ func ProvisionMachine(args manual.ProvisionMachineArgs) (machineId string, err e
 	logger.Infof("Provisioned machine %v", machineId)
 	return machineId, nil
 }
+
+// ReenlistMachine provisions a machine agent for an existing manually
+// provisioned machine on its reinstalled host, using SSH. The machine
+// keeps its ID, and so the units assigned to it; the series and hardware
+// detected on the host must match those recorded for the machine.
+func ReenlistMachine(args manual.ReenlistMachineArgs) error {
+	if err := InitUbuntuUser(args.Host, args.User,
+		args.AuthorizedKeys, args.Stdin, args.Stdout); err != nil {
+		return err
+	}
+
+	provisioned, err := checkProvisioned(args.Host)
+	if err != nil {
+		return errors.Annotatef(err, "error checking if provisioned")
+	}
+	if provisioned {
+		return manual.ErrProvisioned
+	}
+	hc, series, err := DetectSeriesAndHardwareCharacteristics(args.Host)
+	if err != nil {
+		return errors.Annotatef(err, "error detecting linux hardware characteristics")
+	}
+
+	uuid, err := utils.NewUUID()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	nonce := manualNonce(instance.Id(manual.ManualInstancePrefix+args.Host), uuid)
+	if err := args.Reenlister.ReenlistMachine(args.MachineId, nonce, series, hc); err != nil {
+		return errors.Annotatef(err, "cannot re-enlist machine %s", args.MachineId)
+	}
+
+	provisioningScript, err := args.Client.ProvisioningScript(params.ProvisioningScriptParams{
+		MachineId:              args.MachineId,
+		Nonce:                  nonce,
+		DisablePackageCommands: !args.EnableOSRefreshUpdate && !args.EnableOSUpgrade,
+	})
+	if err != nil {
+		logger.Errorf("cannot obtain provisioning script")
+		return err
+	}
+	if err := runProvisionScript(provisioningScript, args.Host, args.Stderr); err != nil {
+		return err
+	}
+	logger.Infof("Re-enlisted machine %v", args.MachineId)
+	return nil
+}
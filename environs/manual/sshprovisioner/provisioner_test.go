@@ -15,6 +15,7 @@ import (
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/agent"
+	"github.com/juju/juju/api/machinemanager"
 	"github.com/juju/juju/apiserver/facades/client/client"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cloudconfig"
@@ -127,6 +128,64 @@ func (s *provisionerSuite) TestProvisionMachine(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "error checking if provisioned: subprocess encountered error code 255")
 }
 
+type recordingReenlister struct {
+	manual.MachineReenlister
+	nonce string
+}
+
+func (r *recordingReenlister) ReenlistMachine(machineId, nonce, series string, hc instance.HardwareCharacteristics) error {
+	r.nonce = nonce
+	return r.MachineReenlister.ReenlistMachine(machineId, nonce, series, hc)
+}
+
+func (s *provisionerSuite) TestReenlistMachine(c *gc.C) {
+	var series = series.DefaultSupportedLTS()
+	const arch = "amd64"
+	args := s.getArgs(c)
+	restore := fakeSSH{
+		Series:         series,
+		Arch:           arch,
+		InitUbuntuUser: true,
+	}.install(c)
+	machineId, err := sshprovisioner.ProvisionMachine(args)
+	restore.Restore()
+	c.Assert(err, jc.ErrorIsNil)
+
+	reenlister := &recordingReenlister{MachineReenlister: machinemanager.NewClient(s.APIState)}
+	reenlistArgs := manual.ReenlistMachineArgs{
+		ProvisionMachineArgs: args,
+		MachineId:            machineId,
+		Reenlister:           reenlister,
+	}
+
+	// A host which still runs the machine agent must not be re-enlisted.
+	restore = fakeSSH{
+		Provisioned:        true,
+		InitUbuntuUser:     true,
+		SkipDetection:      true,
+		SkipProvisionAgent: true,
+	}.install(c)
+	err = sshprovisioner.ReenlistMachine(reenlistArgs)
+	restore.Restore()
+	c.Assert(err, gc.Equals, manual.ErrProvisioned)
+
+	restore = fakeSSH{
+		Series:         series,
+		Arch:           arch,
+		InitUbuntuUser: true,
+	}.install(c)
+	err = sshprovisioner.ReenlistMachine(reenlistArgs)
+	restore.Restore()
+	c.Assert(err, jc.ErrorIsNil)
+
+	m, err := s.State.Machine(machineId)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m.CheckProvisioned(reenlister.nonce), jc.IsTrue)
+	instanceId, err := m.InstanceId()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(instanceId, gc.Equals, instance.Id("manual:"+args.Host))
+}
+
 func (s *provisionerSuite) TestFinishInstancConfig(c *gc.C) {
 	var series = series.DefaultSupportedLTS()
 	const arch = "amd64"
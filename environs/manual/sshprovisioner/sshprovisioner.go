@@ -242,7 +242,7 @@ func gatherMachineParams(hostname string) (*params.AddMachineParams, error) {
 	// machines from state, but will ignore the associated instance ID
 	// if it isn't one that the environment provider knows about.
 	instanceId := instance.Id(manual.ManualInstancePrefix + hostname)
-	nonce := fmt.Sprintf("%s:%s", instanceId, uuid.String())
+	nonce := manualNonce(instanceId, uuid)
 	machineParams := &params.AddMachineParams{
 		Series:                  series,
 		HardwareCharacteristics: hc,
@@ -254,6 +254,12 @@ func gatherMachineParams(hostname string) (*params.AddMachineParams, error) {
 	return machineParams, nil
 }
 
+// manualNonce returns the nonce used by the agent of a manually
+// provisioned machine to log in.
+func manualNonce(instanceId instance.Id, uuid utils.UUID) string {
+	return fmt.Sprintf("%s:%s", instanceId, uuid.String())
+}
+
 func runProvisionScript(script, host string, progressWriter io.Writer) error {
 	params := sshinit.ConfigureParams{
 		Host:           "ubuntu@" + host,
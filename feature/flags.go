@@ -57,3 +57,8 @@ const ActionsV2 = "actions-v2"
 // CharmHubIntegration enables the new commands and functionality related to
 // charm's in CharmHub.
 const CharmHubIntegration = "charm-hub"
+
+// BinaryAPICodec tells API clients, including agents, to ask the
+// controller at login to use the more compact CBOR encoding for API
+// messages instead of JSON.
+const BinaryAPICodec = "cbor-api-codec"
@@ -89,7 +89,7 @@ func (s *cmdUpgradeSuite) run(c *gc.C, args ...string) *cmd.Context {
 }
 
 func (s *cmdUpgradeSuite) assertHostModelAgentVersion(c *gc.C, desiredAgentVersion string) {
-	modelUUIDs, err := s.State.ModelUUIDsForUser(s.hostedModelUserTag)
+	modelUUIDs, err := s.State.ModelUUIDsForUser(s.hostedModelUserTag, nil)
 	c.Assert(err, jc.ErrorIsNil)
 
 	var desiredModel *state.Model
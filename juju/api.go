@@ -4,6 +4,7 @@
 package juju
 
 import (
+	"context"
 	"net"
 	"reflect"
 
@@ -12,6 +13,7 @@ import (
 	"github.com/juju/names/v4"
 
 	"github.com/juju/juju/api"
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/core/network"
 	"github.com/juju/juju/jujuclient"
 )
@@ -64,6 +66,18 @@ func NewAPIConnection(args NewAPIConnectionParams) (_ api.Connection, err error)
 	// we'll update the entry correctly.
 	dnsCache := dnsCacheMap(controller.DNSCache).copy()
 	args.DialOpts.DNSCache = dnsCache
+	// Remember any ID token obtained during login so that
+	// it can be used for subsequent connections.
+	idToken := apiInfo.IDToken
+	if oidcLogin := args.DialOpts.OIDCLogin; oidcLogin != nil {
+		args.DialOpts.OIDCLogin = func(ctx context.Context, info params.OIDCLoginInfo) (string, error) {
+			token, err := oidcLogin(ctx, info)
+			if err == nil {
+				idToken = token
+			}
+			return token, err
+		}
+	}
 	logger.Infof("connecting to API addresses: %v", apiInfo.Addrs)
 	st, err := args.OpenAPI(apiInfo, args.DialOpts)
 	if err != nil {
@@ -126,7 +140,8 @@ func NewAPIConnection(args NewAPIConnectionParams) (_ api.Connection, err error)
 	// Process the account details obtained from login.
 	var accountDetails *jujuclient.AccountDetails
 	user, ok := st.AuthTag().(names.UserTag)
-	if !apiInfo.SkipLogin {
+	// Logins with API tokens leave the recorded account untouched.
+	if !apiInfo.SkipLogin && apiInfo.Token == "" {
 		if ok {
 			if accountDetails, err = args.Store.AccountDetails(args.ControllerName); err != nil {
 				if !errors.IsNotFound(err) {
@@ -137,11 +152,12 @@ func NewAPIConnection(args NewAPIConnectionParams) (_ api.Connection, err error)
 			}
 		}
 		if ok && !user.IsLocal() && apiInfo.Tag == nil {
-			// We used macaroon auth to login; save the username
-			// that we've logged in as.
+			// We used macaroon or ID token auth to login; save the
+			// username that we've logged in as.
 			accountDetails = &jujuclient.AccountDetails{
 				User:            user.Id(),
 				LastKnownAccess: st.ControllerAccess(),
+				IDToken:         idToken,
 			}
 		} else if apiInfo.Tag == nil {
 			logger.Errorf("unexpected logged-in username %v", st.AuthTag())
@@ -193,6 +209,15 @@ func connectionInfo(args NewAPIConnectionParams) (*api.Info, *jujuclient.Control
 		// authenticate using macaroons.
 		apiInfo.Password = account.Password
 	}
+	if apiInfo.Tag == nil && account.IDToken != "" {
+		apiInfo.IDToken = account.IDToken
+	}
+	if account.Token != "" {
+		// API tokens take the place of the user's password.
+		apiInfo.Tag = nil
+		apiInfo.Password = ""
+		apiInfo.Token = account.Token
+	}
 	return apiInfo, controller, nil
 }
 
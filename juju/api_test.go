@@ -138,6 +138,30 @@ func (s *NewAPIClientSuite) TestUpdatesLastKnownAccess(c *gc.C) {
 	)
 }
 
+func (s *NewAPIClientSuite) TestWithAPIToken(c *gc.C) {
+	store := newClientStore(c, "noconfig")
+	err := store.UpdateAccount("noconfig", jujuclient.AccountDetails{
+		User:     "admin",
+		Password: "hunter2",
+		Token:    "jujutoken-0a1b.key",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	expectState := mockedAPIState(mockedHostPort | mockedModelTag)
+	apiOpen := func(apiInfo *api.Info, opts api.DialOpts) (api.Connection, error) {
+		c.Check(apiInfo.Tag, gc.IsNil)
+		c.Check(apiInfo.Password, gc.Equals, "")
+		c.Check(apiInfo.Token, gc.Equals, "jujutoken-0a1b.key")
+		return expectState, nil
+	}
+	stubStore := jujuclienttesting.WrapClientStore(store)
+	_, err = newAPIConnectionFromNames(c, "noconfig", "admin/admin", stubStore, apiOpen)
+	c.Assert(err, jc.ErrorIsNil)
+	// The recorded account is not updated after logging in with
+	// an API token.
+	stubStore.CheckCallNames(c, "AccountDetails", "ModelByName", "ControllerByName", "UpdateController")
+}
+
 func (s *NewAPIClientSuite) TestUpdatesPublicDNSName(c *gc.C) {
 	apiOpen := func(apiInfo *api.Info, opts api.DialOpts) (api.Connection, error) {
 		conn := mockedAPIState(noFlags)
@@ -54,6 +54,11 @@ const (
 	// timestamps to be written in RFC3339 format.
 	JujuStatusIsoTimeEnvKey = "JUJU_STATUS_ISO_TIME"
 
+	// JujuAPITokenEnvKey is the env var holding the secret of an API
+	// token, which the client logs in with instead of the account
+	// recorded for the controller.
+	JujuAPITokenEnvKey = "JUJU_API_TOKEN"
+
 	// XDGDataHome is a path where data for the running user
 	// should be stored according to the xdg standard.
 	XDGDataHome = "XDG_DATA_HOME"
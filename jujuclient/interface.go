@@ -90,6 +90,14 @@ type AccountDetails struct {
 
 	// LastKnownAccess is the last known access level for the account.
 	LastKnownAccess string `yaml:"last-known-access,omitempty"`
+
+	// IDToken is the OpenID Connect ID token last used to log in
+	// to the controller as an external user.
+	IDToken string `yaml:"id-token,omitempty"`
+
+	// Token is the secret of an API token used to log in to the
+	// controller instead of a password, as automation users do.
+	Token string `yaml:"token,omitempty"`
 }
 
 // BootstrapConfig holds the configuration used to bootstrap a controller.
@@ -0,0 +1,262 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package ldap looks up the groups that users belong to in an LDAP
+// directory. The LDAP protocol itself is handled by go-ldap.
+package ldap
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	goldap "github.com/go-ldap/ldap/v3"
+	"github.com/juju/clock"
+	"github.com/juju/errors"
+)
+
+// UserPlaceholder is replaced by the user name in a user DN template.
+const UserPlaceholder = "{user}"
+
+// DefaultCacheTTL is the time for which group memberships are cached
+// if the directory config does not specify otherwise.
+const DefaultCacheTTL = 5 * time.Minute
+
+const (
+	// dialTimeout bounds the time taken to connect to a server.
+	dialTimeout = 10 * time.Second
+
+	// requestTimeout bounds the time taken by each request.
+	requestTimeout = 30 * time.Second
+)
+
+// DirectoryConfig holds the parameters for a Directory.
+type DirectoryConfig struct {
+	// URL is the ldap:// or ldaps:// URL of the server.
+	URL string
+
+	// BindDN and BindPassword are the credentials used to search
+	// the directory. If BindDN is empty, searches are anonymous.
+	// A password is only ever sent over an encrypted connection, so
+	// ldap:// URLs require StartTLS when BindPassword is set.
+	BindDN       string
+	BindPassword string
+
+	// StartTLS, if true, upgrades ldap:// connections to TLS before
+	// binding (RFC 4511, section 4.14).
+	StartTLS bool
+
+	// UserDNTemplate is the distinguished name of users' entries,
+	// with UserPlaceholder standing in for the user name, for
+	// example "uid={user},ou=people,dc=example,dc=com".
+	UserDNTemplate string
+
+	// GroupBaseDN is the distinguished name beneath which groups
+	// are found.
+	GroupBaseDN string
+
+	// MemberAttribute is the group attribute holding the
+	// distinguished names of the group's members.
+	MemberAttribute string
+
+	// NameAttribute is the group attribute holding the name
+	// of the group.
+	NameAttribute string
+
+	// TLSConfig is used to connect to ldaps:// URLs, and for
+	// StartTLS. If it is nil, the system's root certificates are
+	// trusted.
+	TLSConfig *tls.Config
+
+	// Clock is used to expire cached group memberships.
+	Clock clock.Clock
+
+	// CacheTTL is the time for which group memberships are cached.
+	CacheTTL time.Duration
+}
+
+// Validate checks that the config is usable.
+func (c DirectoryConfig) Validate() error {
+	plain := strings.HasPrefix(c.URL, "ldap://")
+	if !plain && !strings.HasPrefix(c.URL, "ldaps://") {
+		return errors.NotValidf("LDAP URL %q", c.URL)
+	}
+	if c.StartTLS && !plain {
+		return errors.NotValidf("StartTLS with LDAP URL %q", c.URL)
+	}
+	if plain && c.BindPassword != "" && !c.StartTLS {
+		return errors.NotValidf("bind password over ldap:// without StartTLS")
+	}
+	if !strings.Contains(c.UserDNTemplate, UserPlaceholder) {
+		return errors.NotValidf("user DN template %q without %s", c.UserDNTemplate, UserPlaceholder)
+	}
+	if c.GroupBaseDN == "" {
+		return errors.NotValidf("empty group base DN")
+	}
+	if c.MemberAttribute == "" {
+		return errors.NotValidf("empty member attribute")
+	}
+	if c.NameAttribute == "" {
+		return errors.NotValidf("empty name attribute")
+	}
+	if c.Clock == nil {
+		return errors.NotValidf("nil Clock")
+	}
+	return nil
+}
+
+// Directory looks up the groups users belong to in an LDAP directory,
+// caching the results. It is safe for concurrent use.
+type Directory struct {
+	config DirectoryConfig
+
+	mu    sync.Mutex
+	cache map[string]cachedGroups
+}
+
+type cachedGroups struct {
+	groups  []string
+	expires time.Time
+}
+
+// NewDirectory returns a new Directory with the given config.
+func NewDirectory(config DirectoryConfig) (*Directory, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if config.CacheTTL == 0 {
+		config.CacheTTL = DefaultCacheTTL
+	}
+	return &Directory{
+		config: config,
+		cache:  make(map[string]cachedGroups),
+	}, nil
+}
+
+// Config returns the directory's config.
+func (d *Directory) Config() DirectoryConfig {
+	return d.config
+}
+
+// UserGroups returns the sorted names of the groups that the user with
+// the given name is a member of.
+func (d *Directory) UserGroups(user string) ([]string, error) {
+	now := d.config.Clock.Now()
+	d.mu.Lock()
+	cached, ok := d.cache[user]
+	d.mu.Unlock()
+	if ok && now.Before(cached.expires) {
+		return cached.groups, nil
+	}
+
+	groups, err := d.search(user)
+	if err != nil {
+		return nil, errors.Annotatef(err, "looking up groups for %q", user)
+	}
+	d.mu.Lock()
+	d.cache[user] = cachedGroups{
+		groups:  groups,
+		expires: now.Add(d.config.CacheTTL),
+	}
+	d.mu.Unlock()
+	return groups, nil
+}
+
+func (d *Directory) search(user string) ([]string, error) {
+	conn, err := d.dial()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer conn.Close()
+	if d.config.BindDN != "" {
+		if d.config.BindPassword == "" {
+			err = conn.UnauthenticatedBind(d.config.BindDN)
+		} else {
+			err = conn.Bind(d.config.BindDN, d.config.BindPassword)
+		}
+		if err != nil {
+			return nil, errors.Annotate(err, "binding to LDAP server")
+		}
+	}
+	memberDN := strings.Replace(d.config.UserDNTemplate, UserPlaceholder, EscapeDNValue(user), -1)
+	result, err := conn.Search(goldap.NewSearchRequest(
+		d.config.GroupBaseDN,
+		goldap.ScopeWholeSubtree,
+		goldap.NeverDerefAliases,
+		0, // no size limit
+		int(requestTimeout/time.Second),
+		false,
+		fmt.Sprintf("(%s=%s)", d.config.MemberAttribute, goldap.EscapeFilter(memberDN)),
+		[]string{d.config.NameAttribute},
+		nil,
+	))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	groups := []string{}
+	for _, entry := range result.Entries {
+		if name := entry.GetEqualFoldAttributeValue(d.config.NameAttribute); name != "" {
+			groups = append(groups, name)
+		}
+	}
+	sort.Strings(groups)
+	return groups, nil
+}
+
+// dial connects to the directory, upgrading the connection to TLS
+// if configured to.
+func (d *Directory) dial() (*goldap.Conn, error) {
+	conn, err := goldap.DialURL(d.config.URL,
+		goldap.DialWithDialer(&net.Dialer{Timeout: dialTimeout}),
+		goldap.DialWithTLSConfig(d.config.TLSConfig),
+	)
+	if err != nil {
+		return nil, errors.Annotatef(err, "connecting to %s", d.config.URL)
+	}
+	conn.SetTimeout(requestTimeout)
+	if !d.config.StartTLS {
+		return conn, nil
+	}
+	u, err := url.Parse(d.config.URL)
+	if err != nil {
+		conn.Close()
+		return nil, errors.Trace(err)
+	}
+	tlsConfig := &tls.Config{}
+	if d.config.TLSConfig != nil {
+		tlsConfig = d.config.TLSConfig.Clone()
+	}
+	if tlsConfig.ServerName == "" {
+		tlsConfig.ServerName = u.Hostname()
+	}
+	if err := conn.StartTLS(tlsConfig); err != nil {
+		conn.Close()
+		return nil, errors.Annotate(err, "starting TLS")
+	}
+	return conn, nil
+}
+
+// EscapeDNValue escapes the special characters in an attribute value
+// for use in a distinguished name (RFC 4514, section 2.4).
+func EscapeDNValue(value string) string {
+	var b strings.Builder
+	for i, r := range value {
+		switch {
+		case strings.ContainsRune(`,+"\<>;=`, r),
+			i == 0 && (r == ' ' || r == '#'),
+			i == len(value)-1 && r == ' ':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r == 0:
+			b.WriteString(`\00`)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,178 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package ldap_test
+
+import (
+	"time"
+
+	goldap "github.com/go-ldap/ldap/v3"
+	"github.com/juju/clock/testclock"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/ldap"
+	"github.com/juju/juju/ldap/ldaptesting"
+)
+
+const (
+	groupBaseDN = "ou=groups,dc=example,dc=com"
+	bindDN      = "cn=juju,dc=example,dc=com"
+)
+
+type directorySuite struct {
+	server *ldaptesting.Server
+	clock  *testclock.Clock
+}
+
+var _ = gc.Suite(&directorySuite{})
+
+func (s *directorySuite) SetUpTest(c *gc.C) {
+	s.server = ldaptesting.NewServer(groupBaseDN)
+	s.server.SetBindCredentials(bindDN, "secret")
+	s.server.SetGroup("ops", "uid=bob,ou=people,dc=example,dc=com", "uid=mary,ou=people,dc=example,dc=com")
+	s.server.SetGroup("devs", "uid=bob,ou=people,dc=example,dc=com")
+	s.clock = testclock.NewClock(time.Now())
+}
+
+func (s *directorySuite) TearDownTest(c *gc.C) {
+	s.server.Close()
+}
+
+func (s *directorySuite) config() ldap.DirectoryConfig {
+	return ldap.DirectoryConfig{
+		URL:             s.server.URL,
+		BindDN:          bindDN,
+		BindPassword:    "secret",
+		StartTLS:        true,
+		TLSConfig:       s.server.ClientTLSConfig(),
+		UserDNTemplate:  "uid={user},ou=people,dc=example,dc=com",
+		GroupBaseDN:     groupBaseDN,
+		MemberAttribute: "member",
+		NameAttribute:   "cn",
+		Clock:           s.clock,
+	}
+}
+
+func (s *directorySuite) TestUserGroups(c *gc.C) {
+	dir, err := ldap.NewDirectory(s.config())
+	c.Assert(err, jc.ErrorIsNil)
+
+	groups, err := dir.UserGroups("bob")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(groups, jc.DeepEquals, []string{"devs", "ops"})
+
+	groups, err = dir.UserGroups("mary")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(groups, jc.DeepEquals, []string{"ops"})
+
+	groups, err = dir.UserGroups("fred")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(groups, gc.HasLen, 0)
+}
+
+func (s *directorySuite) TestUserGroupsCached(c *gc.C) {
+	dir, err := ldap.NewDirectory(s.config())
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = dir.UserGroups("mary")
+	c.Assert(err, jc.ErrorIsNil)
+	s.server.SetGroup("devs", "uid=mary,ou=people,dc=example,dc=com")
+
+	// Until the cached membership expires, changes in the
+	// directory are not seen.
+	groups, err := dir.UserGroups("mary")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(groups, jc.DeepEquals, []string{"ops"})
+	c.Assert(s.server.Searches(), gc.Equals, 1)
+
+	s.clock.Advance(ldap.DefaultCacheTTL)
+	groups, err = dir.UserGroups("mary")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(groups, jc.DeepEquals, []string{"devs", "ops"})
+	c.Assert(s.server.Searches(), gc.Equals, 2)
+}
+
+func (s *directorySuite) TestUserGroupsInvalidCredentials(c *gc.C) {
+	config := s.config()
+	config.BindPassword = "wrong"
+	dir, err := ldap.NewDirectory(config)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = dir.UserGroups("bob")
+	c.Assert(err, gc.ErrorMatches, `looking up groups for "bob": binding to LDAP server: LDAP Result Code 49 .*`)
+	c.Assert(goldap.IsErrorWithCode(errors.Cause(err), goldap.LDAPResultInvalidCredentials), jc.IsTrue)
+}
+
+func (s *directorySuite) TestUserGroupsUntrustedServer(c *gc.C) {
+	config := s.config()
+	config.TLSConfig = nil
+	dir, err := ldap.NewDirectory(config)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = dir.UserGroups("bob")
+	c.Assert(err, gc.ErrorMatches, `looking up groups for "bob": starting TLS: .*certificate.*`)
+	c.Assert(s.server.Searches(), gc.Equals, 0)
+}
+
+func (s *directorySuite) TestUserGroupsAnonymous(c *gc.C) {
+	s.server.SetBindCredentials("", "")
+	config := s.config()
+	config.BindDN, config.BindPassword = "", ""
+	config.StartTLS, config.TLSConfig = false, nil
+	dir, err := ldap.NewDirectory(config)
+	c.Assert(err, jc.ErrorIsNil)
+
+	groups, err := dir.UserGroups("mary")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(groups, jc.DeepEquals, []string{"ops"})
+}
+
+func (s *directorySuite) TestUserGroupsNoSuchBaseDN(c *gc.C) {
+	config := s.config()
+	config.GroupBaseDN = "ou=teams,dc=example,dc=com"
+	dir, err := ldap.NewDirectory(config)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = dir.UserGroups("bob")
+	c.Assert(err, gc.ErrorMatches, `looking up groups for "bob": LDAP Result Code 32 .*`)
+	c.Assert(goldap.IsErrorWithCode(errors.Cause(err), goldap.LDAPResultNoSuchObject), jc.IsTrue)
+}
+
+func (s *directorySuite) TestValidate(c *gc.C) {
+	for i, test := range []struct {
+		mutate func(*ldap.DirectoryConfig)
+		err    string
+	}{{
+		mutate: func(c *ldap.DirectoryConfig) { c.URL = "http://example.com" },
+		err:    `LDAP URL "http://example.com" not valid`,
+	}, {
+		mutate: func(c *ldap.DirectoryConfig) { c.UserDNTemplate = "uid=bob" },
+		err:    `user DN template "uid=bob" without {user} not valid`,
+	}, {
+		mutate: func(c *ldap.DirectoryConfig) { c.GroupBaseDN = "" },
+		err:    "empty group base DN not valid",
+	}, {
+		mutate: func(c *ldap.DirectoryConfig) { c.MemberAttribute = "" },
+		err:    "empty member attribute not valid",
+	}, {
+		mutate: func(c *ldap.DirectoryConfig) { c.StartTLS = false },
+		err:    "bind password over ldap:// without StartTLS not valid",
+	}, {
+		mutate: func(c *ldap.DirectoryConfig) { c.URL = "ldaps://example.com" },
+		err:    `StartTLS with LDAP URL "ldaps://example.com" not valid`,
+	}} {
+		c.Logf("test %d", i)
+		config := s.config()
+		test.mutate(&config)
+		_, err := ldap.NewDirectory(config)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *directorySuite) TestEscapeDNValue(c *gc.C) {
+	c.Assert(ldap.EscapeDNValue("bob"), gc.Equals, "bob")
+	c.Assert(ldap.EscapeDNValue("bob,ou=admins"), gc.Equals, `bob\,ou\=admins`)
+	c.Assert(ldap.EscapeDNValue(" #bob "), gc.Equals, `\ #bob\ `)
+}
@@ -0,0 +1,293 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package ldaptesting provides an in-process LDAP server for use in
+// tests. It supports StartTLS, simple binds and equality searches for
+// groups.
+package ldaptesting
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+	goldap "github.com/go-ldap/ldap/v3"
+)
+
+// startTLSOID is the name of the StartTLS extended operation
+// (RFC 4511, section 4.14.1).
+const startTLSOID = "1.3.6.1.4.1.1466.20037"
+
+// Server is an in-process LDAP server holding groups beneath a single
+// base DN. Each group has a "cn" attribute holding its name and a
+// "member" attribute holding the DNs of its members.
+//
+// Like a well configured directory, the server refuses password binds
+// until the connection has been upgraded with StartTLS.
+type Server struct {
+	// URL is the ldap:// URL of the server.
+	URL string
+
+	listener  net.Listener
+	baseDN    string
+	tlsConfig *tls.Config
+	roots     *x509.CertPool
+	wg        sync.WaitGroup
+
+	mu           sync.Mutex
+	bindDN       string
+	bindPassword string
+	groups       map[string][]string
+	searches     int
+}
+
+// NewServer starts and returns a new server holding groups beneath
+// the given base DN. The caller is responsible for calling Close.
+func NewServer(baseDN string) *Server {
+	cert, roots := newCertificate()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		panic(err)
+	}
+	s := &Server{
+		URL:       "ldap://" + listener.Addr().String(),
+		listener:  listener,
+		baseDN:    baseDN,
+		tlsConfig: &tls.Config{Certificates: []tls.Certificate{cert}},
+		roots:     roots,
+		groups:    make(map[string][]string),
+	}
+	s.wg.Add(1)
+	go s.serve()
+	return s
+}
+
+// ClientTLSConfig returns a TLS config that trusts the certificate
+// the server presents after StartTLS.
+func (s *Server) ClientTLSConfig() *tls.Config {
+	return &tls.Config{RootCAs: s.roots}
+}
+
+// Close stops the server.
+func (s *Server) Close() {
+	s.listener.Close()
+	s.wg.Wait()
+}
+
+// SetBindCredentials requires clients to bind with the given DN and
+// password. By default, anonymous searches are allowed.
+func (s *Server) SetBindCredentials(dn, password string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.bindDN, s.bindPassword = dn, password
+}
+
+// SetGroup sets the members of the named group, replacing any
+// existing members. A group without members is removed.
+func (s *Server) SetGroup(name string, memberDNs ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(memberDNs) == 0 {
+		delete(s.groups, name)
+		return
+	}
+	s.groups[name] = memberDNs
+}
+
+// Searches returns the number of searches the server has handled.
+func (s *Server) Searches() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.searches
+}
+
+func (s *Server) serve() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.serveConn(conn)
+		}()
+	}
+}
+
+func (s *Server) serveConn(conn net.Conn) {
+	defer func() {
+		conn.Close()
+	}()
+	secure := false
+	bound := false
+	for {
+		msg, err := ber.ReadPacket(conn)
+		if err != nil || len(msg.Children) < 2 {
+			return
+		}
+		id, ok := msg.Children[0].Value.(int64)
+		if !ok {
+			return
+		}
+		op := msg.Children[1]
+		if op.ClassType != ber.ClassApplication {
+			return
+		}
+		var responses []*ber.Packet
+		startTLS := false
+		switch op.Tag {
+		case goldap.ApplicationBindRequest:
+			var code uint16
+			code, bound = s.bind(op, secure)
+			responses = append(responses, result(goldap.ApplicationBindResponse, code))
+		case goldap.ApplicationSearchRequest:
+			responses = s.search(op, bound)
+		case goldap.ApplicationExtendedRequest:
+			code := uint16(goldap.LDAPResultProtocolError)
+			if !secure && len(op.Children) > 0 && op.Children[0].Data.String() == startTLSOID {
+				code, startTLS = goldap.LDAPResultSuccess, true
+			}
+			responses = append(responses, result(goldap.ApplicationExtendedResponse, code))
+		default:
+			// Unbind requests, and anything unexpected, end the
+			// session.
+			return
+		}
+		for _, response := range responses {
+			envelope := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "")
+			envelope.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, id, ""))
+			envelope.AppendChild(response)
+			if _, err := conn.Write(envelope.Bytes()); err != nil {
+				return
+			}
+		}
+		if startTLS {
+			tlsConn := tls.Server(conn, s.tlsConfig)
+			if err := tlsConn.Handshake(); err != nil {
+				return
+			}
+			conn, secure = tlsConn, true
+		}
+	}
+}
+
+func (s *Server) bind(op *ber.Packet, secure bool) (uint16, bool) {
+	if len(op.Children) != 3 {
+		return goldap.LDAPResultProtocolError, false
+	}
+	dn := stringValue(op.Children[1])
+	password := op.Children[2].Data.String()
+	if password != "" && !secure {
+		return goldap.LDAPResultConfidentialityRequired, false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if dn != s.bindDN || password != s.bindPassword {
+		return goldap.LDAPResultInvalidCredentials, false
+	}
+	return goldap.LDAPResultSuccess, true
+}
+
+func (s *Server) search(op *ber.Packet, bound bool) []*ber.Packet {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.searches++
+	if s.bindDN != "" && !bound {
+		return []*ber.Packet{result(goldap.ApplicationSearchResultDone, goldap.LDAPResultInsufficientAccessRights)}
+	}
+	if len(op.Children) != 8 {
+		return []*ber.Packet{result(goldap.ApplicationSearchResultDone, goldap.LDAPResultProtocolError)}
+	}
+	filter := op.Children[6]
+	if filter.ClassType != ber.ClassContext || filter.Tag != goldap.FilterEqualityMatch || len(filter.Children) != 2 {
+		return []*ber.Packet{result(goldap.ApplicationSearchResultDone, goldap.LDAPResultProtocolError)}
+	}
+	if !strings.EqualFold(stringValue(op.Children[0]), s.baseDN) {
+		return []*ber.Packet{result(goldap.ApplicationSearchResultDone, goldap.LDAPResultNoSuchObject)}
+	}
+	var responses []*ber.Packet
+	if strings.EqualFold(stringValue(filter.Children[0]), "member") {
+		memberDN := stringValue(filter.Children[1])
+		for name, members := range s.groups {
+			for _, member := range members {
+				if strings.EqualFold(member, memberDN) {
+					responses = append(responses, entry("cn="+name+","+s.baseDN, name))
+					break
+				}
+			}
+		}
+	}
+	return append(responses, result(goldap.ApplicationSearchResultDone, goldap.LDAPResultSuccess))
+}
+
+func stringValue(p *ber.Packet) string {
+	if value, ok := p.Value.(string); ok {
+		return value
+	}
+	return p.Data.String()
+}
+
+func entry(dn, name string) *ber.Packet {
+	values := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSet, nil, "")
+	values.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, name, ""))
+	attribute := ber.NewSequence("")
+	attribute.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "cn", ""))
+	attribute.AppendChild(values)
+	attributes := ber.NewSequence("")
+	attributes.AppendChild(attribute)
+
+	p := ber.Encode(ber.ClassApplication, ber.TypeConstructed, goldap.ApplicationSearchResultEntry, nil, "")
+	p.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, dn, ""))
+	p.AppendChild(attributes)
+	return p
+}
+
+func result(tag ber.Tag, code uint16) *ber.Packet {
+	p := ber.Encode(ber.ClassApplication, ber.TypeConstructed, tag, nil, "")
+	p.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, int64(code), ""))
+	p.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", ""))
+	p.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", ""))
+	return p
+}
+
+// newCertificate returns a self-signed certificate for 127.0.0.1,
+// and a pool holding it.
+func newCertificate() (tls.Certificate, *x509.CertPool) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "ldaptesting"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		IPAddresses:           []net.IP{net.IPv4(127, 0, 0, 1)},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		panic(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		panic(err)
+	}
+	roots := x509.NewCertPool()
+	roots.AddCert(cert)
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: cert}, roots
+}
@@ -58,14 +58,19 @@ type StateImporter interface {
 // model UUID passed.
 type ClaimerFunc func(string) (leadership.Claimer, error)
 
-// ImportModel deserializes a model description from the bytes, transforms
-// the model config based on information from the controller model, and then
+// ImportModel deserializes a model description from the bytes, upconverting
+// it if it was exported in an older format, transforms the model config based on information from the controller model, and then
 // imports that as a new database model.
 func ImportModel(importer StateImporter, getClaimer ClaimerFunc, bytes []byte) (*state.Model, *state.State, error) {
-	model, err := description.Deserialize(bytes)
+	// Models exported by older controllers are upconverted to the
+	// current description format before they are imported.
+	model, converted, err := UpconvertDescription(bytes)
 	if err != nil {
 		return nil, nil, errors.Trace(err)
 	}
+	for _, u := range converted {
+		logger.Infof("importing model %s: %s", model.Tag().Id(), u)
+	}
 
 	dbModel, dbState, err := importer.Import(model)
 	if err != nil {
@@ -118,40 +118,106 @@ func SourcePrecheck(
 	modelPresence ModelPresence,
 	controllerPresence ModelPresence,
 ) error {
-	ctx := precheckContext{backend, modelPresence}
-	if err := ctx.checkModel(); err != nil {
-		return errors.Trace(err)
-	}
+	return errors.Trace(firstProblem(sourcePrechecks(backend, modelPresence, controllerPresence)))
+}
 
-	if err := ctx.checkMachines(); err != nil {
-		return errors.Trace(err)
-	}
+// SourcePrecheckProblems runs all of the checks made by SourcePrecheck,
+// returning every problem found rather than stopping at the first.
+func SourcePrecheckProblems(
+	backend PrecheckBackend,
+	modelPresence ModelPresence,
+	controllerPresence ModelPresence,
+) []error {
+	return allProblems(sourcePrechecks(backend, modelPresence, controllerPresence))
+}
 
-	appUnits, err := ctx.checkApplications()
-	if err != nil {
-		return errors.Trace(err)
+// precheck is a migration precheck. It returns every problem found,
+// which may be with a number of entities.
+type precheck func() []error
+
+// singlePrecheck returns a precheck that reports the single problem
+// found by the check, if any.
+func singlePrecheck(check func() error) precheck {
+	return func() []error {
+		if err := check(); err != nil {
+			return []error{err}
+		}
+		return nil
 	}
+}
 
-	if err := ctx.checkRelations(appUnits); err != nil {
-		return errors.Trace(err)
+func sourcePrechecks(
+	backend PrecheckBackend,
+	modelPresence ModelPresence,
+	controllerPresence ModelPresence,
+) []precheck {
+	ctx := precheckContext{backend, modelPresence}
+	var (
+		appUnits    map[string][]PrecheckUnit
+		appProblems []error
+	)
+	return []precheck{
+		singlePrecheck(ctx.checkModel),
+		ctx.checkMachines,
+		func() []error {
+			appUnits, appProblems = ctx.checkApplications()
+			return appProblems
+		},
+		func() []error {
+			// Relations are checked against the units of each
+			// application, which aren't known when the applications
+			// couldn't be checked.
+			if len(appProblems) > 0 {
+				return nil
+			}
+			return ctx.checkRelations(appUnits)
+		},
+		singlePrecheck(func() error {
+			if cleanupNeeded, err := backend.NeedsCleanup(); err != nil {
+				return errors.Annotate(err, "checking cleanups")
+			} else if cleanupNeeded {
+				return errors.New("cleanup needed")
+			}
+			return nil
+		}),
+		func() []error {
+			// Check the source controller.
+			controllerBackend, err := backend.ControllerBackend()
+			if err != nil {
+				return []error{errors.Trace(err)}
+			}
+			controllerCtx := precheckContext{controllerBackend, controllerPresence}
+			return annotateProblems(controllerCtx.checkController(), "controller")
+		},
 	}
+}
 
-	if cleanupNeeded, err := backend.NeedsCleanup(); err != nil {
-		return errors.Annotate(err, "checking cleanups")
-	} else if cleanupNeeded {
-		return errors.New("cleanup needed")
+// firstProblem runs the checks in turn, returning the first problem
+// found.
+func firstProblem(checks []precheck) error {
+	for _, check := range checks {
+		if problems := check(); len(problems) > 0 {
+			return problems[0]
+		}
 	}
+	return nil
+}
 
-	// Check the source controller.
-	controllerBackend, err := backend.ControllerBackend()
-	if err != nil {
-		return errors.Trace(err)
+// allProblems runs each of the checks, returning every problem found.
+func allProblems(checks []precheck) []error {
+	var problems []error
+	for _, check := range checks {
+		problems = append(problems, check()...)
 	}
-	controllerCtx := precheckContext{controllerBackend, controllerPresence}
-	if err := controllerCtx.checkController(); err != nil {
-		return errors.Annotate(err, "controller")
+	return problems
+}
+
+// annotateProblems annotates each of the problems with the message.
+func annotateProblems(problems []error, message string) []error {
+	for i, err := range problems {
+		problems[i] = errors.Annotate(err, message)
 	}
-	return nil
+	return problems
 }
 
 type precheckContext struct {
@@ -189,41 +255,70 @@ func TargetPrecheck(backend PrecheckBackend, pool Pool, modelInfo coremigration.
 	if err := modelInfo.Validate(); err != nil {
 		return errors.Trace(err)
 	}
+	return errors.Trace(firstProblem(targetPrechecks(backend, pool, modelInfo, presence)))
+}
+
+// TargetPrecheckProblems runs all of the checks made by TargetPrecheck,
+// returning every problem found rather than stopping at the first.
+func TargetPrecheckProblems(backend PrecheckBackend, pool Pool, modelInfo coremigration.ModelInfo, presence ModelPresence) []error {
+	if err := modelInfo.Validate(); err != nil {
+		return []error{errors.Trace(err)}
+	}
+	return allProblems(targetPrechecks(backend, pool, modelInfo, presence))
+}
 
-	// This check is necessary because there is a window between the
-	// REAP phase and then end of the DONE phase where a model's
-	// documents have been deleted but the migration isn't quite done
-	// yet. Migrating a model back into the controller during this
-	// window can upset the migrationmaster worker.
-	//
-	// See also https://lpad.tv/1611391
-	if migrating, err := backend.IsMigrationActive(modelInfo.UUID); err != nil {
-		return errors.Annotate(err, "checking for active migration")
-	} else if migrating {
-		return errors.New("model is being migrated out of target controller")
+func targetPrechecks(backend PrecheckBackend, pool Pool, modelInfo coremigration.ModelInfo, presence ModelPresence) []precheck {
+	controllerCtx := precheckContext{backend, presence}
+	return []precheck{
+		singlePrecheck(func() error {
+			// This check is necessary because there is a window between the
+			// REAP phase and then end of the DONE phase where a model's
+			// documents have been deleted but the migration isn't quite done
+			// yet. Migrating a model back into the controller during this
+			// window can upset the migrationmaster worker.
+			//
+			// See also https://lpad.tv/1611391
+			if migrating, err := backend.IsMigrationActive(modelInfo.UUID); err != nil {
+				return errors.Annotate(err, "checking for active migration")
+			} else if migrating {
+				return errors.New("model is being migrated out of target controller")
+			}
+			return nil
+		}),
+		singlePrecheck(func() error {
+			return checkTargetVersions(backend, modelInfo)
+		}),
+		controllerCtx.checkController,
+		singlePrecheck(func() error {
+			return checkModelConflicts(backend, pool, modelInfo)
+		}),
 	}
+}
 
+func checkTargetVersions(backend PrecheckBackend, modelInfo coremigration.ModelInfo) error {
 	controllerVersion, err := backend.AgentVersion()
 	if err != nil {
 		return errors.Annotate(err, "retrieving model version")
 	}
 
-	if controllerVersion.Compare(modelInfo.AgentVersion) < 0 {
+	// Models are accepted from older source controllers, and at newer
+	// patch versions, as the target controller upconverts models
+	// exported in an earlier description format on import, and the
+	// model's agent binaries are transferred with it. Models in a newer
+	// format can't be downconverted.
+	if !controllerVersionCompatible(modelInfo.AgentVersion, controllerVersion) {
 		return errors.Errorf("model has higher version than target controller (%s > %s)",
 			modelInfo.AgentVersion, controllerVersion)
 	}
-
 	if !controllerVersionCompatible(modelInfo.ControllerAgentVersion, controllerVersion) {
 		return errors.Errorf("source controller has higher version than target controller (%s > %s)",
 			modelInfo.ControllerAgentVersion, controllerVersion)
 	}
+	return nil
+}
 
-	controllerCtx := precheckContext{backend, presence}
-	if err := controllerCtx.checkController(); err != nil {
-		return errors.Trace(err)
-	}
-
-	// Check for conflicts with existing models
+// checkModelConflicts checks for conflicts with existing models.
+func checkModelConflicts(backend PrecheckBackend, pool Pool, modelInfo coremigration.ModelInfo) error {
 	modelUUIDs, err := backend.AllModelUUIDs()
 	if err != nil {
 		return errors.Annotate(err, "retrieving models")
@@ -266,125 +361,141 @@ func versionToMajMin(ver version.Number) version.Number {
 	return ver
 }
 
-func (ctx *precheckContext) checkController() error {
+func (ctx *precheckContext) checkController() []error {
 	model, err := ctx.backend.Model()
 	if err != nil {
-		return errors.Annotate(err, "retrieving model")
+		return []error{errors.Annotate(err, "retrieving model")}
 	}
 	if model.Life() != state.Alive {
-		return errors.Errorf("model is %s", model.Life())
+		return []error{errors.Errorf("model is %s", model.Life())}
 	}
 
 	if upgrading, err := ctx.backend.IsUpgrading(); err != nil {
-		return errors.Annotate(err, "checking for upgrades")
+		return []error{errors.Annotate(err, "checking for upgrades")}
 	} else if upgrading {
-		return errors.New("upgrade in progress")
+		return []error{errors.New("upgrade in progress")}
 	}
 
-	return errors.Trace(ctx.checkMachines())
+	return ctx.checkMachines()
 }
 
-func (ctx *precheckContext) checkMachines() error {
+// checkMachines returns the first problem found with each machine.
+func (ctx *precheckContext) checkMachines() []error {
 	modelVersion, err := ctx.backend.AgentVersion()
 	if err != nil {
-		return errors.Annotate(err, "retrieving model version")
+		return []error{errors.Annotate(err, "retrieving model version")}
 	}
 
 	machines, err := ctx.backend.AllMachines()
 	if err != nil {
-		return errors.Annotate(err, "retrieving machines")
+		return []error{errors.Annotate(err, "retrieving machines")}
 	}
-	modelPresenceContext := common.ModelPresenceContext{Presence: ctx.presence}
+	var problems []error
 	for _, machine := range machines {
-		if machine.Life() != state.Alive {
-			return errors.Errorf("machine %s is %s", machine.Id(), machine.Life())
+		if err := ctx.checkMachine(machine, modelVersion); err != nil {
+			problems = append(problems, err)
 		}
+	}
+	return problems
+}
 
-		if statusInfo, err := machine.InstanceStatus(); err != nil {
-			return errors.Annotatef(err, "retrieving machine %s instance status", machine.Id())
-		} else if statusInfo.Status != status.Running {
-			return newStatusError("machine %s not running", machine.Id(), statusInfo.Status)
-		}
+func (ctx *precheckContext) checkMachine(machine PrecheckMachine, modelVersion version.Number) error {
+	if machine.Life() != state.Alive {
+		return errors.Errorf("machine %s is %s", machine.Id(), machine.Life())
+	}
 
-		if statusInfo, err := modelPresenceContext.MachineStatus(machine); err != nil {
-			return errors.Annotatef(err, "retrieving machine %s status", machine.Id())
-		} else if statusInfo.Status != status.Started {
-			return newStatusError("machine %s agent not functioning at this time",
-				machine.Id(), statusInfo.Status)
-		}
+	if statusInfo, err := machine.InstanceStatus(); err != nil {
+		return errors.Annotatef(err, "retrieving machine %s instance status", machine.Id())
+	} else if statusInfo.Status != status.Running {
+		return newStatusError("machine %s not running", machine.Id(), statusInfo.Status)
+	}
 
-		if rebootAction, err := machine.ShouldRebootOrShutdown(); err != nil {
-			return errors.Annotatef(err, "retrieving machine %s reboot status", machine.Id())
-		} else if rebootAction != state.ShouldDoNothing {
-			return errors.Errorf("machine %s is scheduled to %s", machine.Id(), rebootAction)
-		}
+	modelPresenceContext := common.ModelPresenceContext{Presence: ctx.presence}
+	if statusInfo, err := modelPresenceContext.MachineStatus(machine); err != nil {
+		return errors.Annotatef(err, "retrieving machine %s status", machine.Id())
+	} else if statusInfo.Status != status.Started {
+		return newStatusError("machine %s agent not functioning at this time",
+			machine.Id(), statusInfo.Status)
+	}
 
-		if err := checkAgentTools(modelVersion, machine, "machine "+machine.Id()); err != nil {
-			return errors.Trace(err)
-		}
+	if rebootAction, err := machine.ShouldRebootOrShutdown(); err != nil {
+		return errors.Annotatef(err, "retrieving machine %s reboot status", machine.Id())
+	} else if rebootAction != state.ShouldDoNothing {
+		return errors.Errorf("machine %s is scheduled to %s", machine.Id(), rebootAction)
 	}
-	return nil
+
+	return errors.Trace(checkAgentTools(modelVersion, machine, "machine "+machine.Id()))
 }
 
-func (ctx *precheckContext) checkApplications() (map[string][]PrecheckUnit, error) {
+// checkApplications returns the first problem found with each
+// application and each of their units, along with the units of each
+// application.
+func (ctx *precheckContext) checkApplications() (map[string][]PrecheckUnit, []error) {
 	modelVersion, err := ctx.backend.AgentVersion()
 	if err != nil {
-		return nil, errors.Annotate(err, "retrieving model version")
+		return nil, []error{errors.Annotate(err, "retrieving model version")}
 	}
 	apps, err := ctx.backend.AllApplications()
 	if err != nil {
-		return nil, errors.Annotate(err, "retrieving applications")
+		return nil, []error{errors.Annotate(err, "retrieving applications")}
 	}
 
 	model, err := ctx.backend.Model()
 	if err != nil {
-		return nil, errors.Annotate(err, "retrieving model")
+		return nil, []error{errors.Annotate(err, "retrieving model")}
 	}
 	appUnits := make(map[string][]PrecheckUnit, len(apps))
+	var problems []error
 	for _, app := range apps {
 		if app.Life() != state.Alive {
-			return nil, errors.Errorf("application %s is %s", app.Name(), app.Life())
+			problems = append(problems, errors.Errorf("application %s is %s", app.Name(), app.Life()))
+			continue
 		}
 		units, err := app.AllUnits()
 		if err != nil {
-			return nil, errors.Annotatef(err, "retrieving units for %s", app.Name())
-		}
-		err = ctx.checkUnits(app, units, modelVersion, model.Type())
-		if err != nil {
-			return nil, errors.Trace(err)
+			problems = append(problems, errors.Annotatef(err, "retrieving units for %s", app.Name()))
+			continue
 		}
+		problems = append(problems, ctx.checkUnits(app, units, modelVersion, model.Type())...)
 		appUnits[app.Name()] = units
 	}
-	return appUnits, nil
+	return appUnits, problems
 }
 
-func (ctx *precheckContext) checkUnits(app PrecheckApplication, units []PrecheckUnit, modelVersion version.Number, modelType state.ModelType) error {
+func (ctx *precheckContext) checkUnits(app PrecheckApplication, units []PrecheckUnit, modelVersion version.Number, modelType state.ModelType) []error {
+	var problems []error
 	if len(units) < app.MinUnits() {
-		return errors.Errorf("application %s is below its minimum units threshold", app.Name())
+		problems = append(problems, errors.Errorf("application %s is below its minimum units threshold", app.Name()))
 	}
 
 	appCharmURL, _ := app.CharmURL()
-
 	for _, unit := range units {
-		if unit.Life() != state.Alive {
-			return errors.Errorf("unit %s is %s", unit.Name(), unit.Life())
+		if err := ctx.checkUnit(unit, appCharmURL, modelVersion, modelType); err != nil {
+			problems = append(problems, err)
 		}
+	}
+	return problems
+}
 
-		if err := ctx.checkUnitAgentStatus(unit); err != nil {
-			return errors.Trace(err)
-		}
+func (ctx *precheckContext) checkUnit(unit PrecheckUnit, appCharmURL *charm.URL, modelVersion version.Number, modelType state.ModelType) error {
+	if unit.Life() != state.Alive {
+		return errors.Errorf("unit %s is %s", unit.Name(), unit.Life())
+	}
 
-		if modelType == state.ModelTypeIAAS {
-			if err := checkAgentTools(modelVersion, unit, "unit "+unit.Name()); err != nil {
-				return errors.Trace(err)
-			}
-		}
+	if err := ctx.checkUnitAgentStatus(unit); err != nil {
+		return errors.Trace(err)
+	}
 
-		unitCharmURL, _ := unit.CharmURL()
-		if appCharmURL.String() != unitCharmURL.String() {
-			return errors.Errorf("unit %s is upgrading", unit.Name())
+	if modelType == state.ModelTypeIAAS {
+		if err := checkAgentTools(modelVersion, unit, "unit "+unit.Name()); err != nil {
+			return errors.Trace(err)
 		}
 	}
+
+	unitCharmURL, _ := unit.CharmURL()
+	if appCharmURL.String() != unitCharmURL.String() {
+		return errors.Errorf("unit %s is upgrading", unit.Name())
+	}
 	return nil
 }
 
@@ -429,42 +540,51 @@ func newStatusError(format, id string, s status.Status) error {
 	return errors.New(msg)
 }
 
-func (ctx *precheckContext) checkRelations(appUnits map[string][]PrecheckUnit) error {
+// checkRelations returns the first problem found with each relation.
+func (ctx *precheckContext) checkRelations(appUnits map[string][]PrecheckUnit) []error {
 	relations, err := ctx.backend.AllRelations()
 	if err != nil {
-		return errors.Annotate(err, "retrieving model relations")
+		return []error{errors.Annotate(err, "retrieving model relations")}
 	}
+	var problems []error
 	for _, rel := range relations {
-		// We expect a relationScope and settings for each of the
-		// units of the specified application, unless it is a
-		// remote application.
-		crossModel, err := rel.IsCrossModel()
-		if err != nil {
-			return errors.Annotatef(err, "checking whether relation %s is cross-model", rel)
+		if err := checkRelation(rel, appUnits); err != nil {
+			problems = append(problems, err)
 		}
-		if crossModel {
-			continue
-		}
-		for _, ep := range rel.Endpoints() {
-			for _, unit := range appUnits[ep.ApplicationName] {
-				ru, err := rel.Unit(unit)
-				if err != nil {
-					return errors.Trace(err)
-				}
-				valid, err := ru.Valid()
-				if err != nil {
-					return errors.Trace(err)
-				}
-				if !valid {
-					continue
-				}
-				inScope, err := ru.InScope()
-				if err != nil {
-					return errors.Trace(err)
-				}
-				if !inScope {
-					return errors.Errorf("unit %s hasn't joined relation %s yet", unit.Name(), rel)
-				}
+	}
+	return problems
+}
+
+func checkRelation(rel PrecheckRelation, appUnits map[string][]PrecheckUnit) error {
+	// We expect a relationScope and settings for each of the
+	// units of the specified application, unless it is a
+	// remote application.
+	crossModel, err := rel.IsCrossModel()
+	if err != nil {
+		return errors.Annotatef(err, "checking whether relation %s is cross-model", rel)
+	}
+	if crossModel {
+		return nil
+	}
+	for _, ep := range rel.Endpoints() {
+		for _, unit := range appUnits[ep.ApplicationName] {
+			ru, err := rel.Unit(unit)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			valid, err := ru.Valid()
+			if err != nil {
+				return errors.Trace(err)
+			}
+			if !valid {
+				continue
+			}
+			inScope, err := ru.InScope()
+			if err != nil {
+				return errors.Trace(err)
+			}
+			if !inScope {
+				return errors.Errorf("unit %s hasn't joined relation %s yet", unit.Name(), rel)
 			}
 		}
 	}
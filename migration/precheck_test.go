@@ -107,6 +107,64 @@ func (*SourcePrecheckSuite) TestCleanupsNeeded(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "cleanup needed")
 }
 
+func (*SourcePrecheckSuite) TestProblemsReportsAll(c *gc.C) {
+	backend := newFakeBackend()
+	backend.model.life = state.Dying
+	backend.cleanupNeeded = true
+	backend.controllerBackend.isUpgrading = true
+	problems := migration.SourcePrecheckProblems(backend, allAlivePresence(), allAlivePresence())
+	c.Assert(problems, gc.HasLen, 3)
+	c.Check(problems[0], gc.ErrorMatches, "model is dying")
+	c.Check(problems[1], gc.ErrorMatches, "cleanup needed")
+	c.Check(problems[2], gc.ErrorMatches, "controller: upgrade in progress")
+}
+
+func (*SourcePrecheckSuite) TestProblemsReportsEveryEntity(c *gc.C) {
+	backend := newHappyBackend()
+	backend.controllerBackend = newHappyBackend()
+	backend.machines = []migration.PrecheckMachine{
+		&fakeMachine{id: "0", life: state.Dying},
+		&fakeMachine{id: "1", rebootAction: state.ShouldReboot},
+	}
+	backend.apps = []migration.PrecheckApplication{
+		&fakeApp{name: "foo", life: state.Dying},
+		&fakeApp{
+			name: "bar",
+			units: []migration.PrecheckUnit{
+				&fakeUnit{name: "bar/0", life: state.Dying},
+				&fakeUnit{name: "bar/1", charmURL: "cs:foo-2"},
+			},
+		},
+	}
+	problems := migration.SourcePrecheckProblems(backend, allAlivePresence(), allAlivePresence())
+	c.Assert(problems, gc.HasLen, 5)
+	c.Check(problems[0], gc.ErrorMatches, "machine 0 is dying")
+	c.Check(problems[1], gc.ErrorMatches, "machine 1 is scheduled to reboot")
+	c.Check(problems[2], gc.ErrorMatches, "application foo is dying")
+	c.Check(problems[3], gc.ErrorMatches, "unit bar/0 is dying")
+	c.Check(problems[4], gc.ErrorMatches, "unit bar/1 is upgrading")
+}
+
+func (*SourcePrecheckSuite) TestProblemsSkipsRelationsWhenApplicationsFail(c *gc.C) {
+	backend := newHappyBackend()
+	backend.controllerBackend = newHappyBackend()
+	backend.allAppsErr = errors.New("boom")
+	backend.relations = []migration.PrecheckRelation{&fakeRelation{
+		key:           "foo:db bar:db",
+		crossModelErr: errors.New("relations checked"),
+	}}
+	problems := migration.SourcePrecheckProblems(backend, allAlivePresence(), allAlivePresence())
+	c.Assert(problems, gc.HasLen, 1)
+	c.Check(problems[0], gc.ErrorMatches, "retrieving applications: boom")
+}
+
+func (*SourcePrecheckSuite) TestProblemsNone(c *gc.C) {
+	backend := newHappyBackend()
+	backend.controllerBackend = newHappyBackend()
+	problems := migration.SourcePrecheckProblems(backend, allAlivePresence(), allAlivePresence())
+	c.Assert(problems, gc.HasLen, 0)
+}
+
 func (s *SourcePrecheckSuite) TestIsUpgradingError(c *gc.C) {
 	backend := newFakeBackend()
 	backend.controllerBackend.isUpgradingErr = errors.New("boom")
@@ -425,12 +483,36 @@ func (s *TargetPrecheckSuite) TestModelVersionAheadOfTarget(c *gc.C) {
 	backend := newFakeBackend()
 
 	sourceVersion := backendVersion
-	sourceVersion.Patch++
+	sourceVersion.Minor++
+	sourceVersion.Patch = 0
 	s.modelInfo.AgentVersion = sourceVersion
 
 	err := s.runPrecheck(backend)
 	c.Assert(err.Error(), gc.Equals,
-		`model has higher version than target controller (1.2.4 > 1.2.3)`)
+		`model has higher version than target controller (1.3.0 > 1.2.3)`)
+}
+
+func (s *TargetPrecheckSuite) TestModelPatchVersionAheadOfTarget(c *gc.C) {
+	backend := newFakeBackend()
+
+	sourceVersion := backendVersion
+	sourceVersion.Patch++
+	s.modelInfo.AgentVersion = sourceVersion
+
+	err := s.runPrecheck(backend)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *TargetPrecheckSuite) TestModelVersionBehindTarget(c *gc.C) {
+	backend := newFakeBackend()
+
+	sourceVersion := backendVersion
+	sourceVersion.Major--
+	s.modelInfo.AgentVersion = sourceVersion
+	s.modelInfo.ControllerAgentVersion = sourceVersion
+
+	err := s.runPrecheck(backend)
+	c.Assert(err, jc.ErrorIsNil)
 }
 
 func (s *TargetPrecheckSuite) TestSourceControllerMajorAhead(c *gc.C) {
@@ -527,6 +609,23 @@ func (s *TargetPrecheckSuite) TestIsMigrationActiveError(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "checking for active migration: boom")
 }
 
+func (s *TargetPrecheckSuite) TestProblemsReportsAll(c *gc.C) {
+	backend := newFakeBackend()
+	backend.migrationActive = true
+	s.modelInfo.AgentVersion.Minor++
+	problems := migration.TargetPrecheckProblems(backend, nil, s.modelInfo, allAlivePresence())
+	c.Assert(problems, gc.HasLen, 2)
+	c.Check(problems[0], gc.ErrorMatches, "model is being migrated out of target controller")
+	c.Check(problems[1], gc.ErrorMatches, `model has higher version than target controller \(1.3.3 > 1.2.3\)`)
+}
+
+func (s *TargetPrecheckSuite) TestProblemsInvalidModelInfo(c *gc.C) {
+	s.modelInfo.UUID = ""
+	problems := migration.TargetPrecheckProblems(newFakeBackend(), nil, s.modelInfo, allAlivePresence())
+	c.Assert(problems, gc.HasLen, 1)
+	c.Check(problems[0], gc.ErrorMatches, "empty UUID not valid")
+}
+
 func (s *TargetPrecheckSuite) TestIsMigrationActive(c *gc.C) {
 	backend := &fakeBackend{migrationActive: true}
 	err := s.runPrecheck(backend)
@@ -0,0 +1,183 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package migration
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/juju/description/v2"
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+	"gopkg.in/yaml.v2"
+)
+
+// Upconversion describes a collection of entities in a serialized model
+// description that is in an older format than that written by this
+// controller, and which is converted to the current format on import.
+type Upconversion struct {
+	// Entity is the name of the converted collection,
+	// such as "applications", or "model" for the model itself.
+	Entity string
+
+	// From is the format version in the serialized description.
+	From int
+
+	// To is the format version that the entities are converted to.
+	To int
+
+	// Count is the number of entities converted.
+	Count int
+}
+
+// String returns a description of the conversion
+// suitable for reporting to the user.
+func (u Upconversion) String() string {
+	if u.Entity == modelEntity {
+		return fmt.Sprintf("model upconverted from version %d to %d", u.From, u.To)
+	}
+	return fmt.Sprintf("%d %s upconverted from version %d to %d", u.Count, u.Entity, u.From, u.To)
+}
+
+const modelEntity = "model"
+
+// UpconvertDescription converts the serialized model description, which
+// may have been exported by an older controller in an earlier format, to
+// the format written by this controller. Known defects of earlier formats
+// are corrected, then the model is deserialized, which converts each
+// collection of entities to its current format. The converted model is
+// returned along with the conversions made.
+func UpconvertDescription(bytes []byte) (description.Model, []Upconversion, error) {
+	var source map[string]interface{}
+	if err := yaml.Unmarshal(bytes, &source); err != nil {
+		return nil, nil, errors.Annotate(err, "cannot import model description")
+	}
+	sourceVersions := descriptionVersions(source)
+	for _, fixup := range descriptionFixups {
+		if v, ok := sourceVersions[fixup.entity]; ok && v.version < fixup.before {
+			fixup.apply(source)
+		}
+	}
+	fixed, err := yaml.Marshal(source)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	model, err := description.Deserialize(fixed)
+	if err != nil {
+		return nil, nil, errors.Annotate(err, "cannot import model description")
+	}
+	return model, upconversions(sourceVersions, latestVersions), nil
+}
+
+// descriptionFixup corrects a defect in the serialized descriptions of
+// an entity collection written by older controllers.
+type descriptionFixup struct {
+	// entity is the collection that the fixup applies to,
+	// or "model" for the model itself.
+	entity string
+
+	// before is the first format version without the defect.
+	before int
+
+	// apply corrects the serialized model description.
+	apply func(source map[string]interface{})
+}
+
+var descriptionFixups = []descriptionFixup{{
+	// A release exported version 4 models with a blank type,
+	// which is the type of IAAS models.
+	entity: modelEntity,
+	before: 5,
+	apply: func(source map[string]interface{}) {
+		if t, ok := source["type"]; ok && t == "" {
+			source["type"] = description.IAAS
+		}
+	},
+}}
+
+// latestVersions holds the format versions written when this controller
+// serializes a model description.
+var latestVersions = latestDescriptionVersions()
+
+// upconversions returns the conversions from the source format versions
+// to the converted ones.
+func upconversions(source, converted map[string]entityVersion) []Upconversion {
+	var upconversions []Upconversion
+	for entity, v := range source {
+		convertedVersion, ok := converted[entity]
+		if !ok || v.version >= convertedVersion.version {
+			continue
+		}
+		upconversions = append(upconversions, Upconversion{
+			Entity: entity,
+			From:   v.version,
+			To:     convertedVersion.version,
+			Count:  v.count,
+		})
+	}
+	sort.Slice(upconversions, func(i, j int) bool {
+		// The model itself is always reported first.
+		if upconversions[i].Entity == modelEntity {
+			return upconversions[j].Entity != modelEntity
+		}
+		if upconversions[j].Entity == modelEntity {
+			return false
+		}
+		return upconversions[i].Entity < upconversions[j].Entity
+	})
+	return upconversions
+}
+
+type entityVersion struct {
+	version int
+	count   int
+}
+
+// descriptionVersions returns the format version of the model and of each
+// of its top-level entity collections in the serialized description, along
+// with the number of entities in each collection.
+func descriptionVersions(source map[string]interface{}) map[string]entityVersion {
+	versions := make(map[string]entityVersion)
+	if v, ok := source["version"].(int); ok {
+		versions[modelEntity] = entityVersion{version: v, count: 1}
+	}
+	for key, value := range source {
+		// Entity collections are maps holding a version,
+		// and a single list of the entities themselves.
+		collection, ok := value.(map[interface{}]interface{})
+		if !ok {
+			continue
+		}
+		v, ok := collection["version"].(int)
+		if !ok {
+			continue
+		}
+		var count int
+		for field, entities := range collection {
+			if list, ok := entities.([]interface{}); ok && field != "version" {
+				count = len(list)
+			}
+		}
+		versions[key] = entityVersion{version: v, count: count}
+	}
+	return versions
+}
+
+// latestDescriptionVersions returns the format versions written when
+// this controller serializes a model description.
+func latestDescriptionVersions() map[string]entityVersion {
+	model := description.NewModel(description.ModelArgs{
+		Owner:  names.NewUserTag("admin"),
+		Config: map[string]interface{}{},
+	})
+	bytes, err := description.Serialize(model)
+	if err != nil {
+		panic(err)
+	}
+	var source map[string]interface{}
+	if err := yaml.Unmarshal(bytes, &source); err != nil {
+		panic(err)
+	}
+	return descriptionVersions(source)
+}
@@ -0,0 +1,94 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package migration_test
+
+import (
+	"strings"
+
+	"github.com/juju/description/v2"
+	"github.com/juju/names/v4"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/status"
+	"github.com/juju/juju/migration"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type UpconvertSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&UpconvertSuite{})
+
+func (s *UpconvertSuite) serializedModel(c *gc.C) string {
+	model := description.NewModel(description.ModelArgs{
+		Type:  description.IAAS,
+		Owner: names.NewUserTag("admin"),
+		Config: map[string]interface{}{
+			"name": "foo",
+			"uuid": coretesting.ModelTag.Id(),
+		},
+	})
+	model.SetStatus(description.StatusArgs{Value: string(status.Available)})
+	model.AddSpace(description.SpaceArgs{Name: "db"})
+	model.AddSpace(description.SpaceArgs{Name: "public"})
+	bytes, err := description.Serialize(model)
+	c.Assert(err, jc.ErrorIsNil)
+	return string(bytes)
+}
+
+func (s *UpconvertSuite) TestCurrentFormat(c *gc.C) {
+	model, upconversions, err := migration.UpconvertDescription([]byte(s.serializedModel(c)))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(upconversions, gc.HasLen, 0)
+	c.Assert(model.Tag(), gc.Equals, coretesting.ModelTag)
+	c.Assert(model.Spaces(), gc.HasLen, 2)
+}
+
+func (s *UpconvertSuite) TestOlderFormat(c *gc.C) {
+	// Rewrite the description as an older controller would have
+	// exported it, with an earlier model and spaces format.
+	serialized := s.serializedModel(c)
+	c.Assert(serialized, jc.Contains, "version: 8\n")
+	c.Assert(serialized, jc.Contains, "spaces:\n  version: 2\n")
+	serialized = strings.Replace(serialized, "version: 8\n", "version: 7\n", 1)
+	serialized = strings.Replace(serialized, "spaces:\n  version: 2\n", "spaces:\n  version: 1\n", 1)
+
+	model, upconversions, err := migration.UpconvertDescription([]byte(serialized))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(upconversions, jc.DeepEquals, []migration.Upconversion{
+		{Entity: "model", From: 7, To: 8, Count: 1},
+		{Entity: "spaces", From: 1, To: 2, Count: 2},
+	})
+	c.Assert(upconversions[0].String(), gc.Equals, "model upconverted from version 7 to 8")
+	c.Assert(upconversions[1].String(), gc.Equals, "2 spaces upconverted from version 1 to 2")
+	c.Assert(model.Spaces(), gc.HasLen, 2)
+
+	// The converted model serializes in the current format.
+	bytes, err := description.Serialize(model)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(bytes), jc.Contains, "version: 8\n")
+	c.Assert(string(bytes), jc.Contains, "spaces:\n  version: 2\n")
+}
+
+func (s *UpconvertSuite) TestBlankModelTypeFixed(c *gc.C) {
+	serialized := strings.Replace(s.serializedModel(c), "version: 8\n", "version: 4\n", 1)
+	c.Assert(serialized, jc.Contains, "type: iaas\n")
+	serialized = strings.Replace(serialized, "type: iaas\n", "type: \"\"\n", 1)
+
+	model, upconversions, err := migration.UpconvertDescription([]byte(serialized))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(upconversions[0], jc.DeepEquals, migration.Upconversion{
+		Entity: "model", From: 4, To: 8, Count: 1,
+	})
+	c.Assert(model.Type(), gc.Equals, description.IAAS)
+}
+
+func (s *UpconvertSuite) TestUnsupportedFormat(c *gc.C) {
+	serialized := strings.Replace(s.serializedModel(c), "version: 8\n", "version: 99\n", 1)
+	_, _, err := migration.UpconvertDescription([]byte(serialized))
+	c.Assert(err, gc.ErrorMatches, "cannot import model description: .*")
+}
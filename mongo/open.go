@@ -15,8 +15,9 @@ import (
 	"github.com/juju/errors"
 	"github.com/juju/names/v4"
 	"github.com/juju/utils"
-	"github.com/juju/utils/cert"
 	"gopkg.in/mgo.v2"
+
+	"github.com/juju/juju/pki"
 )
 
 // SocketTimeout should be long enough that even a slow mongo server
@@ -125,12 +126,19 @@ func DialInfo(info Info, opts DialOpts) (*mgo.DialInfo, error) {
 		if len(info.CACert) == 0 {
 			return nil, stderrors.New("missing CA certificate")
 		}
-		xcert, err := cert.ParseCert(info.CACert)
+		// Trust all the certificates in the bundle, as there
+		// will be more than one while the CA is rotated.
+		xcerts, _, err := pki.UnmarshalPemData([]byte(info.CACert))
+		if err == nil && len(xcerts) == 0 {
+			err = stderrors.New("no certificates found")
+		}
 		if err != nil {
 			return nil, fmt.Errorf("cannot parse CA certificate: %v", err)
 		}
 		pool := x509.NewCertPool()
-		pool.AddCert(xcert)
+		for _, xcert := range xcerts {
+			pool.AddCert(xcert)
+		}
 
 		tlsConfig = utils.SecureTLSConfig()
 		tlsConfig.RootCAs = pool
@@ -0,0 +1,189 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/juju/clock"
+	"github.com/juju/errors"
+)
+
+const (
+	// DeviceCodeGrantType is the grant type used to exchange a device
+	// code for tokens (RFC 8628, section 3.4).
+	DeviceCodeGrantType = "urn:ietf:params:oauth:grant-type:device_code"
+
+	// defaultPollInterval is the interval to use when polling the
+	// token endpoint if the provider does not specify one.
+	defaultPollInterval = 5 * time.Second
+
+	// slowDownIncrement is added to the polling interval each time
+	// the provider asks the client to slow down.
+	slowDownIncrement = 5 * time.Second
+)
+
+// DeviceAuthorization holds the response to a device authorization
+// request (RFC 8628, section 3.2).
+type DeviceAuthorization struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete,omitempty"`
+	ExpiresIn               int    `json:"expires_in"`
+	Interval                int    `json:"interval,omitempty"`
+}
+
+// Token holds the tokens returned by a provider's token endpoint.
+type Token struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	IDToken      string `json:"id_token"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	ExpiresIn    int    `json:"expires_in,omitempty"`
+}
+
+// tokenError is the error response from a token endpoint
+// (RFC 6749, section 5.2).
+type tokenError struct {
+	Code        string `json:"error"`
+	Description string `json:"error_description,omitempty"`
+}
+
+func (e *tokenError) Error() string {
+	if e.Description != "" {
+		return e.Code + ": " + e.Description
+	}
+	return e.Code
+}
+
+// DeviceLoginParams holds the parameters for DeviceLogin.
+type DeviceLoginParams struct {
+	// Issuer is the URL of the OpenID provider.
+	Issuer string
+
+	// ClientID is the client ID registered with the provider.
+	ClientID string
+
+	// Scopes holds any scopes to request in addition to "openid".
+	Scopes []string
+
+	// HTTPClient is used to talk to the provider. If it is nil,
+	// http.DefaultClient is used.
+	HTTPClient *http.Client
+
+	// Clock is used to wait between polls of the token endpoint.
+	Clock clock.Clock
+
+	// Prompt is called once the device has been authorized, and
+	// should ask the user to visit the verification URI and enter
+	// the user code.
+	Prompt func(*DeviceAuthorization) error
+}
+
+// DeviceLogin logs in using the OAuth 2.0 device authorization grant,
+// returning the tokens issued once the user has approved the login.
+func DeviceLogin(ctx context.Context, p DeviceLoginParams) (*Token, error) {
+	metadata, err := Discover(ctx, p.HTTPClient, p.Issuer)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if metadata.DeviceAuthorizationEndpoint == "" {
+		return nil, errors.NotSupportedf("device login with OpenID provider %q", p.Issuer)
+	}
+	scopes := append([]string{"openid"}, p.Scopes...)
+	var auth DeviceAuthorization
+	if err := postForm(ctx, p.HTTPClient, metadata.DeviceAuthorizationEndpoint, url.Values{
+		"client_id": {p.ClientID},
+		"scope":     {strings.Join(scopes, " ")},
+	}, &auth); err != nil {
+		return nil, errors.Annotate(err, "requesting device authorization")
+	}
+	if auth.DeviceCode == "" || auth.UserCode == "" || auth.VerificationURI == "" {
+		return nil, errors.New("incomplete device authorization response")
+	}
+	if err := p.Prompt(&auth); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	interval := time.Duration(auth.Interval) * time.Second
+	if interval <= 0 {
+		interval = defaultPollInterval
+	}
+	var expired <-chan time.Time
+	if auth.ExpiresIn > 0 {
+		expired = p.Clock.After(time.Duration(auth.ExpiresIn) * time.Second)
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, errors.Trace(ctx.Err())
+		case <-expired:
+			return nil, errors.New("device login expired before it was approved")
+		case <-p.Clock.After(interval):
+		}
+		var token Token
+		err := postForm(ctx, p.HTTPClient, metadata.TokenEndpoint, url.Values{
+			"grant_type":  {DeviceCodeGrantType},
+			"device_code": {auth.DeviceCode},
+			"client_id":   {p.ClientID},
+		}, &token)
+		if tokenErr, ok := errors.Cause(err).(*tokenError); ok {
+			switch tokenErr.Code {
+			case "authorization_pending":
+				continue
+			case "slow_down":
+				interval += slowDownIncrement
+				continue
+			case "access_denied":
+				return nil, errors.New("device login was denied")
+			case "expired_token":
+				return nil, errors.New("device login expired before it was approved")
+			}
+		}
+		if err != nil {
+			return nil, errors.Annotate(err, "requesting token")
+		}
+		if token.IDToken == "" {
+			return nil, errors.New("OpenID provider did not return an ID token")
+		}
+		return &token, nil
+	}
+}
+
+// postForm posts the form to the given URL and decodes the JSON
+// response into v. Token endpoint error responses are returned
+// as *tokenError.
+func postForm(ctx context.Context, client *http.Client, endpoint string, form url.Values, v interface{}) error {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	req, err := http.NewRequest("POST", endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		var tokenErr tokenError
+		if err := json.NewDecoder(resp.Body).Decode(&tokenErr); err == nil && tokenErr.Code != "" {
+			return &tokenErr
+		}
+		return errors.Errorf("unexpected HTTP response %q", resp.Status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return errors.Annotate(err, "cannot decode response")
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package oidc_test
+
+import (
+	"context"
+	"time"
+
+	"github.com/juju/clock/testclock"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/oidc"
+	"github.com/juju/juju/oidc/oidctesting"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type deviceSuite struct {
+	issuer *oidctesting.Issuer
+	clock  *testclock.Clock
+}
+
+var _ = gc.Suite(&deviceSuite{})
+
+func (s *deviceSuite) SetUpTest(c *gc.C) {
+	s.issuer = oidctesting.NewIssuer("juju")
+	s.clock = testclock.NewClock(time.Now())
+}
+
+func (s *deviceSuite) TearDownTest(c *gc.C) {
+	s.issuer.Close()
+}
+
+func (s *deviceSuite) deviceLogin(prompt func(*oidc.DeviceAuthorization) error) (*oidc.Token, error) {
+	type result struct {
+		token *oidc.Token
+		err   error
+	}
+	done := make(chan result, 1)
+	go func() {
+		token, err := oidc.DeviceLogin(context.Background(), oidc.DeviceLoginParams{
+			Issuer:     s.issuer.URL,
+			ClientID:   "juju",
+			Scopes:     []string{"email", "groups"},
+			HTTPClient: s.issuer.Client(),
+			Clock:      s.clock,
+			Prompt:     prompt,
+		})
+		done <- result{token, err}
+	}()
+	for {
+		select {
+		case r := <-done:
+			return r.token, r.err
+		case <-time.After(coretesting.LongWait):
+			panic("timed out waiting for device login")
+		case <-s.clock.Alarms():
+			s.clock.Advance(time.Second)
+		}
+	}
+}
+
+func (s *deviceSuite) TestDeviceLoginExpires(c *gc.C) {
+	var prompted *oidc.DeviceAuthorization
+	token, err := s.deviceLogin(func(auth *oidc.DeviceAuthorization) error {
+		prompted = auth
+		return nil
+	})
+	c.Assert(err, gc.ErrorMatches, "device login expired before it was approved")
+	c.Assert(token, gc.IsNil)
+	c.Assert(prompted, gc.NotNil)
+	c.Assert(prompted.VerificationURI, gc.Equals, s.issuer.URL+"/activate")
+}
+
+func (s *deviceSuite) TestDeviceLoginApproved(c *gc.C) {
+	token, err := s.deviceLogin(func(auth *oidc.DeviceAuthorization) error {
+		s.issuer.Approve(auth.UserCode, map[string]interface{}{"sub": "1234"})
+		return nil
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	verifier := oidc.NewVerifier(oidc.VerifierConfig{
+		Issuer:     s.issuer.URL,
+		ClientID:   "juju",
+		HTTPClient: s.issuer.Client(),
+		Clock:      s.clock,
+	})
+	idToken, err := verifier.Verify(context.Background(), token.IDToken)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(idToken.Subject, gc.Equals, "1234")
+}
+
+func (s *deviceSuite) TestDeviceLoginDenied(c *gc.C) {
+	_, err := s.deviceLogin(func(auth *oidc.DeviceAuthorization) error {
+		s.issuer.Deny(auth.UserCode)
+		return nil
+	})
+	c.Assert(err, gc.ErrorMatches, "device login was denied")
+}
@@ -0,0 +1,45 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package oidc implements the parts of OpenID Connect needed by Juju:
+// provider discovery, ID token verification and the OAuth 2.0 device
+// authorization grant (RFC 8628) used by command line clients.
+// Discovery, key sets and token signatures are handled by go-oidc.
+package oidc
+
+import (
+	"context"
+	"net/http"
+
+	gooidc "github.com/coreos/go-oidc"
+	"github.com/juju/errors"
+)
+
+// DiscoveryPath is the path, relative to the issuer URL, of the OpenID
+// provider configuration document.
+const DiscoveryPath = "/.well-known/openid-configuration"
+
+// ProviderMetadata holds the parts of an OpenID provider configuration
+// document that Juju uses.
+type ProviderMetadata struct {
+	Issuer                      string `json:"issuer"`
+	JWKSURI                     string `json:"jwks_uri"`
+	TokenEndpoint               string `json:"token_endpoint"`
+	DeviceAuthorizationEndpoint string `json:"device_authorization_endpoint,omitempty"`
+}
+
+// Discover fetches the provider configuration document for the given
+// issuer. go-oidc checks that the document names exactly the issuer we
+// asked for, otherwise tokens from one provider could be passed off as
+// coming from another (OpenID Connect Discovery, section 4.3).
+func Discover(ctx context.Context, client *http.Client, issuer string) (*ProviderMetadata, error) {
+	provider, err := gooidc.NewProvider(clientContext(ctx, client), issuer)
+	if err != nil {
+		return nil, errors.Annotatef(err, "discovering OpenID provider %q", issuer)
+	}
+	var metadata ProviderMetadata
+	if err := provider.Claims(&metadata); err != nil {
+		return nil, errors.Annotatef(err, "discovering OpenID provider %q", issuer)
+	}
+	return &metadata, nil
+}
@@ -0,0 +1,215 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package oidctesting provides a fake OpenID provider for use in tests.
+package oidctesting
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+
+	jose "gopkg.in/square/go-jose.v2"
+
+	"github.com/juju/juju/oidc"
+)
+
+const keyID = "test-key"
+
+// Issuer is a fake OpenID provider serving discovery, keys, device
+// authorization and token endpoints over TLS.
+type Issuer struct {
+	*httptest.Server
+
+	// ClientID is the client ID that tokens are issued to.
+	ClientID string
+
+	// TokenLifetime is the lifetime of ID tokens issued through
+	// device logins. It defaults to an hour.
+	TokenLifetime time.Duration
+
+	key *rsa.PrivateKey
+
+	mu      sync.Mutex
+	devices map[string]*device
+	nextID  int
+}
+
+type device struct {
+	userCode string
+	claims   map[string]interface{}
+	denied   bool
+}
+
+// NewIssuer starts and returns a new fake provider which issues tokens
+// to the given client ID. The caller is responsible for calling Close.
+func NewIssuer(clientID string) *Issuer {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		panic(err)
+	}
+	i := &Issuer{
+		ClientID:      clientID,
+		TokenLifetime: time.Hour,
+		key:           key,
+		devices:       make(map[string]*device),
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc(oidc.DiscoveryPath, i.serveDiscovery)
+	mux.HandleFunc("/keys", i.serveKeys)
+	mux.HandleFunc("/device", i.serveDeviceAuthorization)
+	mux.HandleFunc("/token", i.serveToken)
+	i.Server = httptest.NewTLSServer(mux)
+	return i
+}
+
+// IDToken returns an ID token for the given claims, signed by the
+// issuer. The iss and aud claims are added, as are exp and iat based
+// on the given expiry, unless already present.
+func (i *Issuer) IDToken(claims map[string]interface{}, expiry time.Time) string {
+	all := map[string]interface{}{
+		"iss": i.URL,
+		"aud": i.ClientID,
+		"exp": expiry.Unix(),
+		"iat": expiry.Add(-i.TokenLifetime).Unix(),
+	}
+	for k, v := range claims {
+		all[k] = v
+	}
+	return i.sign(all)
+}
+
+// SignedBy returns an ID token for the given claims signed with the
+// given key, which the issuer does not publish.
+func (i *Issuer) SignedBy(key *rsa.PrivateKey, claims map[string]interface{}) string {
+	return sign(key, claims)
+}
+
+// Approve approves the pending device login with the given user code,
+// so that the next poll of the token endpoint returns an ID token with
+// the given claims.
+func (i *Issuer) Approve(userCode string, claims map[string]interface{}) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	for _, d := range i.devices {
+		if d.userCode == userCode {
+			d.claims = claims
+		}
+	}
+}
+
+// Deny denies the pending device login with the given user code.
+func (i *Issuer) Deny(userCode string) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	for _, d := range i.devices {
+		if d.userCode == userCode {
+			d.denied = true
+		}
+	}
+}
+
+func (i *Issuer) serveDiscovery(w http.ResponseWriter, req *http.Request) {
+	writeJSON(w, http.StatusOK, oidc.ProviderMetadata{
+		Issuer:                      i.URL,
+		JWKSURI:                     i.URL + "/keys",
+		TokenEndpoint:               i.URL + "/token",
+		DeviceAuthorizationEndpoint: i.URL + "/device",
+	})
+}
+
+func (i *Issuer) serveKeys(w http.ResponseWriter, req *http.Request) {
+	writeJSON(w, http.StatusOK, jose.JSONWebKeySet{Keys: []jose.JSONWebKey{{
+		Key:       &i.key.PublicKey,
+		KeyID:     keyID,
+		Algorithm: oidc.RS256,
+		Use:       "sig",
+	}}})
+}
+
+func (i *Issuer) serveDeviceAuthorization(w http.ResponseWriter, req *http.Request) {
+	if req.PostFormValue("client_id") != i.ClientID {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_client"})
+		return
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.nextID++
+	deviceCode := fmt.Sprintf("device-code-%d", i.nextID)
+	userCode := fmt.Sprintf("USER-%04d", i.nextID)
+	i.devices[deviceCode] = &device{userCode: userCode}
+	writeJSON(w, http.StatusOK, oidc.DeviceAuthorization{
+		DeviceCode:              deviceCode,
+		UserCode:                userCode,
+		VerificationURI:         i.URL + "/activate",
+		VerificationURIComplete: i.URL + "/activate?user_code=" + userCode,
+		ExpiresIn:               600,
+		Interval:                1,
+	})
+}
+
+func (i *Issuer) serveToken(w http.ResponseWriter, req *http.Request) {
+	if req.PostFormValue("grant_type") != oidc.DeviceCodeGrantType {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "unsupported_grant_type"})
+		return
+	}
+	i.mu.Lock()
+	d, ok := i.devices[req.PostFormValue("device_code")]
+	i.mu.Unlock()
+	switch {
+	case !ok:
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "expired_token"})
+	case d.denied:
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "access_denied"})
+	case d.claims == nil:
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "authorization_pending"})
+	default:
+		i.mu.Lock()
+		delete(i.devices, req.PostFormValue("device_code"))
+		i.mu.Unlock()
+		writeJSON(w, http.StatusOK, oidc.Token{
+			AccessToken: "access-token",
+			TokenType:   "Bearer",
+			IDToken:     i.IDToken(d.claims, time.Now().Add(i.TokenLifetime)),
+			ExpiresIn:   int(i.TokenLifetime / time.Second),
+		})
+	}
+}
+
+func (i *Issuer) sign(claims map[string]interface{}) string {
+	return sign(i.key, claims)
+}
+
+func sign(key *rsa.PrivateKey, claims map[string]interface{}) string {
+	signer, err := jose.NewSigner(
+		jose.SigningKey{Algorithm: jose.RS256, Key: jose.JSONWebKey{Key: key, KeyID: keyID}},
+		(&jose.SignerOptions{}).WithType("JWT"),
+	)
+	if err != nil {
+		panic(err)
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		panic(err)
+	}
+	jws, err := signer.Sign(payload)
+	if err != nil {
+		panic(err)
+	}
+	token, err := jws.CompactSerialize()
+	if err != nil {
+		panic(err)
+	}
+	return token
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
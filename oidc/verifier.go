@@ -0,0 +1,213 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package oidc
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	gooidc "github.com/coreos/go-oidc"
+	"github.com/juju/clock"
+	"github.com/juju/errors"
+)
+
+// Supported JSON Web Signature algorithms. Unsigned tokens
+// (algorithm "none") are never accepted.
+const (
+	RS256 = gooidc.RS256
+	ES256 = gooidc.ES256
+)
+
+// clockSkew is the leeway allowed when checking token times against
+// the local clock.
+const clockSkew = time.Minute
+
+// ErrTokenExpired is the cause of errors returned by Verifier.Verify
+// for tokens that were valid, but have expired.
+var ErrTokenExpired = errors.New("ID token expired")
+
+// VerifierConfig holds the parameters for a Verifier.
+type VerifierConfig struct {
+	// Issuer is the URL of the OpenID provider.
+	Issuer string
+
+	// ClientID is the client ID that tokens must have been
+	// issued to.
+	ClientID string
+
+	// HTTPClient is used to talk to the provider. If it is nil,
+	// http.DefaultClient is used.
+	HTTPClient *http.Client
+
+	// Clock is used to check token validity times.
+	Clock clock.Clock
+}
+
+// Verifier verifies ID tokens issued by an OpenID provider to a
+// single client. Signatures, the issuer and the audience are checked
+// by go-oidc; the token times are checked against our own clock.
+type Verifier struct {
+	issuer   string
+	clientID string
+	client   *http.Client
+	clock    clock.Clock
+
+	// mu guards verifier, which is created on first use.
+	mu       sync.Mutex
+	verifier *gooidc.IDTokenVerifier
+}
+
+// NewVerifier returns a new Verifier. The provider is not contacted
+// until the first token is verified.
+func NewVerifier(config VerifierConfig) *Verifier {
+	return &Verifier{
+		issuer:   config.Issuer,
+		clientID: config.ClientID,
+		client:   config.HTTPClient,
+		clock:    config.Clock,
+	}
+}
+
+// Issuer returns the URL of the provider whose tokens are verified.
+func (v *Verifier) Issuer() string {
+	return v.issuer
+}
+
+// ClientID returns the client ID that tokens must be issued to.
+func (v *Verifier) ClientID() string {
+	return v.clientID
+}
+
+// IDToken holds the claims of a verified ID token.
+type IDToken struct {
+	Subject string
+	Expiry  time.Time
+	Claims  map[string]interface{}
+}
+
+// StringClaim returns the value of the named string claim.
+func (t *IDToken) StringClaim(name string) (string, bool) {
+	s, ok := t.Claims[name].(string)
+	return s, ok && s != ""
+}
+
+// BoolClaim reports whether the named claim is present and true.
+func (t *IDToken) BoolClaim(name string) bool {
+	b, _ := t.Claims[name].(bool)
+	return b
+}
+
+// StringsClaim returns the value of the named claim, which may be
+// either a string or an array of strings. Groups claims in
+// particular come in both forms.
+func (t *IDToken) StringsClaim(name string) []string {
+	switch v := t.Claims[name].(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		var values []string
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+	return nil
+}
+
+// Verify checks the signature and standard claims of the raw ID token
+// and returns its claims. An expired token results in an error with
+// an ErrTokenExpired cause.
+func (v *Verifier) Verify(ctx context.Context, rawToken string) (*IDToken, error) {
+	verifier, err := v.idTokenVerifier(ctx)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot verify ID token")
+	}
+	token, err := verifier.Verify(clientContext(ctx, v.client), rawToken)
+	if err != nil {
+		return nil, errors.NewUnauthorized(err, "invalid ID token")
+	}
+	var claims map[string]interface{}
+	if err := token.Claims(&claims); err != nil {
+		return nil, errors.Annotate(err, "invalid ID token claims")
+	}
+	return v.checkClaims(token, claims)
+}
+
+// idTokenVerifier returns the go-oidc verifier for the provider,
+// discovering the provider configuration the first time it is called.
+func (v *Verifier) idTokenVerifier(ctx context.Context) (*gooidc.IDTokenVerifier, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.verifier != nil {
+		return v.verifier, nil
+	}
+	// The provider's key set fetches keys with the context the provider
+	// was created with, so that must outlive this request.
+	provider, err := gooidc.NewProvider(clientContext(context.Background(), v.client), v.issuer)
+	if err != nil {
+		return nil, errors.Annotatef(err, "discovering OpenID provider %q", v.issuer)
+	}
+	v.verifier = provider.Verifier(&gooidc.Config{
+		ClientID:             v.clientID,
+		SupportedSigningAlgs: []string{RS256, ES256},
+		// Expiry is checked in checkClaims, against our clock and
+		// with an error callers can recognise.
+		SkipExpiryCheck: true,
+	})
+	return v.verifier, nil
+}
+
+// checkClaims checks the claims that go-oidc leaves to its callers.
+func (v *Verifier) checkClaims(token *gooidc.IDToken, claims map[string]interface{}) (*IDToken, error) {
+	// go-oidc checks that we are in the audience, but not that the
+	// token was issued to us when there are several audiences.
+	if azp, ok := claims["azp"].(string); ok && azp != v.clientID {
+		return nil, errors.Unauthorizedf("ID token authorized for client %q", azp)
+	}
+
+	now := v.clock.Now()
+	if _, ok := numericDate(claims["exp"]); !ok {
+		return nil, errors.NotValidf("ID token without expiry")
+	}
+	if now.After(token.Expiry.Add(clockSkew)) {
+		return nil, errors.Annotatef(ErrTokenExpired, "token expired at %s", token.Expiry.UTC().Format(time.RFC3339))
+	}
+	if nbf, ok := numericDate(claims["nbf"]); ok && now.Add(clockSkew).Before(nbf) {
+		return nil, errors.Unauthorizedf("ID token not valid until %s", nbf.UTC().Format(time.RFC3339))
+	}
+	if !token.IssuedAt.IsZero() && now.Add(clockSkew).Before(token.IssuedAt) {
+		return nil, errors.Unauthorizedf("ID token issued in the future")
+	}
+	if token.Subject == "" {
+		return nil, errors.NotValidf("ID token without subject")
+	}
+	return &IDToken{
+		Subject: token.Subject,
+		Expiry:  token.Expiry,
+		Claims:  claims,
+	}, nil
+}
+
+// clientContext returns a context that makes go-oidc use the given
+// HTTP client, if there is one.
+func clientContext(ctx context.Context, client *http.Client) context.Context {
+	if client == nil {
+		return ctx
+	}
+	return gooidc.ClientContext(ctx, client)
+}
+
+// numericDate converts a JWT NumericDate claim value, decoded from
+// JSON, into a time.
+func numericDate(v interface{}) (time.Time, bool) {
+	f, ok := v.(float64)
+	if !ok {
+		return time.Time{}, false
+	}
+	return time.Unix(int64(f), 0), true
+}
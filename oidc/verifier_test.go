@@ -0,0 +1,146 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package oidc_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"time"
+
+	"github.com/juju/clock/testclock"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/oidc"
+	"github.com/juju/juju/oidc/oidctesting"
+)
+
+type verifierSuite struct {
+	issuer   *oidctesting.Issuer
+	clock    *testclock.Clock
+	verifier *oidc.Verifier
+}
+
+var _ = gc.Suite(&verifierSuite{})
+
+func (s *verifierSuite) SetUpTest(c *gc.C) {
+	s.issuer = oidctesting.NewIssuer("juju")
+	s.clock = testclock.NewClock(time.Now())
+	s.verifier = oidc.NewVerifier(oidc.VerifierConfig{
+		Issuer:     s.issuer.URL,
+		ClientID:   "juju",
+		HTTPClient: s.issuer.Client(),
+		Clock:      s.clock,
+	})
+}
+
+func (s *verifierSuite) TearDownTest(c *gc.C) {
+	s.issuer.Close()
+}
+
+func (s *verifierSuite) TestVerify(c *gc.C) {
+	raw := s.issuer.IDToken(map[string]interface{}{
+		"sub":    "1234",
+		"email":  "bob@example.com",
+		"groups": []string{"ops", "dev"},
+	}, s.clock.Now().Add(time.Hour))
+	token, err := s.verifier.Verify(context.Background(), raw)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(token.Subject, gc.Equals, "1234")
+	c.Assert(token.Expiry.Unix(), gc.Equals, s.clock.Now().Add(time.Hour).Unix())
+	email, ok := token.StringClaim("email")
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(email, gc.Equals, "bob@example.com")
+	c.Assert(token.StringsClaim("groups"), jc.DeepEquals, []string{"ops", "dev"})
+	c.Assert(token.StringsClaim("email"), jc.DeepEquals, []string{"bob@example.com"})
+	c.Assert(token.StringsClaim("missing"), gc.HasLen, 0)
+}
+
+func (s *verifierSuite) TestVerifyExpired(c *gc.C) {
+	raw := s.issuer.IDToken(map[string]interface{}{
+		"sub": "1234",
+	}, s.clock.Now().Add(-2*time.Minute))
+	_, err := s.verifier.Verify(context.Background(), raw)
+	c.Assert(err, gc.ErrorMatches, "token expired at .*: ID token expired")
+	c.Assert(errors.Cause(err), gc.Equals, oidc.ErrTokenExpired)
+}
+
+func (s *verifierSuite) TestVerifyWrongAudience(c *gc.C) {
+	raw := s.issuer.IDToken(map[string]interface{}{
+		"sub": "1234",
+		"aud": []string{"other"},
+	}, s.clock.Now().Add(time.Hour))
+	_, err := s.verifier.Verify(context.Background(), raw)
+	c.Assert(err, gc.ErrorMatches, `invalid ID token: oidc: expected audience "juju" got \["other"\]`)
+	c.Assert(err, jc.Satisfies, errors.IsUnauthorized)
+}
+
+func (s *verifierSuite) TestVerifyWrongIssuer(c *gc.C) {
+	raw := s.issuer.IDToken(map[string]interface{}{
+		"sub": "1234",
+		"iss": "https://elsewhere.example.com",
+	}, s.clock.Now().Add(time.Hour))
+	_, err := s.verifier.Verify(context.Background(), raw)
+	c.Assert(err, gc.ErrorMatches, `invalid ID token: oidc: id token issued by a different provider, expected ".*" got "https://elsewhere.example.com"`)
+	c.Assert(err, jc.Satisfies, errors.IsUnauthorized)
+}
+
+func (s *verifierSuite) TestVerifyBadSignature(c *gc.C) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	c.Assert(err, jc.ErrorIsNil)
+	raw := s.issuer.SignedBy(key, map[string]interface{}{
+		"iss": s.issuer.URL,
+		"aud": "juju",
+		"sub": "1234",
+		"exp": s.clock.Now().Add(time.Hour).Unix(),
+	})
+	_, err = s.verifier.Verify(context.Background(), raw)
+	c.Assert(err, gc.ErrorMatches, "invalid ID token: failed to verify signature: .*")
+	c.Assert(err, jc.Satisfies, errors.IsUnauthorized)
+}
+
+func (s *verifierSuite) TestVerifyUnsigned(c *gc.C) {
+	claims, err := json.Marshal(map[string]interface{}{
+		"iss": s.issuer.URL,
+		"aud": "juju",
+		"sub": "1234",
+		"exp": s.clock.Now().Add(time.Hour).Unix(),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	raw := encodeSegment([]byte(`{"alg":"none"}`)) + "." + encodeSegment(claims) + "."
+	_, err = s.verifier.Verify(context.Background(), raw)
+	c.Assert(err, gc.ErrorMatches, `invalid ID token: oidc: id token signed with unsupported algorithm, .* got "none"`)
+}
+
+func (s *verifierSuite) TestVerifyUnauthorizedParty(c *gc.C) {
+	raw := s.issuer.IDToken(map[string]interface{}{
+		"sub": "1234",
+		"aud": []string{"juju", "other"},
+		"azp": "other",
+	}, s.clock.Now().Add(time.Hour))
+	_, err := s.verifier.Verify(context.Background(), raw)
+	c.Assert(err, gc.ErrorMatches, `ID token authorized for client "other"`)
+}
+
+func (s *verifierSuite) TestVerifyNotYetValid(c *gc.C) {
+	raw := s.issuer.IDToken(map[string]interface{}{
+		"sub": "1234",
+		"nbf": s.clock.Now().Add(10 * time.Minute).Unix(),
+	}, s.clock.Now().Add(time.Hour))
+	_, err := s.verifier.Verify(context.Background(), raw)
+	c.Assert(err, gc.ErrorMatches, `ID token not valid until .*`)
+}
+
+func encodeSegment(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func (s *verifierSuite) TestVerifyMalformed(c *gc.C) {
+	_, err := s.verifier.Verify(context.Background(), "not-a-token")
+	c.Assert(err, gc.ErrorMatches, "invalid ID token: oidc: malformed jwt: .*")
+}
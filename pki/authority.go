@@ -192,6 +192,49 @@ func NewCA(commonName string, signer crypto.Signer) (*x509.Certificate, error) {
 	return caCert, nil
 }
 
+// CrossSignCA issues a certificate for the supplied CA, with the same subject
+// and public key, signed by another authority. Clients that only trust the
+// other authority can then verify certificates issued by the CA, as is needed
+// when replacing one CA with another.
+func CrossSignCA(ca *x509.Certificate, authority *x509.Certificate,
+	signer crypto.Signer) (*x509.Certificate, error) {
+	if !ca.IsCA {
+		return nil, errors.NotValidf("%s is not a certificate authority",
+			ca.Subject)
+	}
+	template := &x509.Certificate{}
+	if err := assetTagCertificate(template); err != nil {
+		return nil, errors.Annotate(err, "failed tagging cross signed certificate")
+	}
+
+	// Keep the subject, including the serial number attribute
+	// set when the CA was made, so that certificates issued
+	// by the CA chain to this certificate.
+	template.RawSubject = ca.RawSubject
+	template.SubjectKeyId = ca.SubjectKeyId
+	template.NotBefore = ca.NotBefore
+	template.NotAfter = ca.NotAfter
+	if template.NotAfter.After(authority.NotAfter) {
+		template.NotAfter = authority.NotAfter
+	}
+	template.KeyUsage = ca.KeyUsage
+	template.BasicConstraintsValid = true
+	template.IsCA = true
+
+	der, err := x509.CreateCertificate(rand.Reader, template, authority,
+		ca.PublicKey, signer)
+	if err != nil {
+		return nil, errors.Annotate(err, "failed creating cross signed CA certificate")
+	}
+
+	crossCert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	return crossCert, nil
+}
+
 // NewDefaultAuthority generates a new DefaultAuthority for the supplied CA
 // cert and keys. Error is returned when the supplied certificate is not a CA.
 func NewDefaultAuthority(authority *x509.Certificate, signer crypto.Signer,
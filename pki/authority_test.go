@@ -128,3 +128,49 @@ func (a *AuthoritySuite) TestAuthorityFromPemCAKey(c *gc.C) {
 	_, err = pki.NewDefaultAuthorityPemCAKey(caBytes.Bytes(), keyBytes.Bytes())
 	c.Assert(err, jc.ErrorIsNil)
 }
+
+func (a *AuthoritySuite) TestCrossSignCA(c *gc.C) {
+	newSigner, err := pki.DefaultKeyProfile()
+	c.Assert(err, jc.ErrorIsNil)
+	newCA, err := pki.NewCA("juju-test-new-ca", newSigner)
+	c.Assert(err, jc.ErrorIsNil)
+
+	crossCert, err := pki.CrossSignCA(newCA, a.ca, a.signer)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(crossCert.RawSubject, jc.DeepEquals, newCA.RawSubject)
+	c.Assert(crossCert.IsCA, jc.IsTrue)
+	c.Assert(crossCert.CheckSignatureFrom(a.ca), jc.ErrorIsNil)
+
+	authority, err := pki.NewDefaultAuthority(newCA, newSigner, crossCert)
+	c.Assert(err, jc.ErrorIsNil)
+	leaf, err := authority.LeafRequestForGroup("testgroup").
+		AddDNSNames("juju-apiserver").
+		Commit()
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The leaf can be verified by those trusting either CA.
+	intermediates := x509.NewCertPool()
+	for _, cert := range leaf.Chain() {
+		intermediates.AddCert(cert)
+	}
+	for _, root := range []*x509.Certificate{a.ca, newCA} {
+		roots := x509.NewCertPool()
+		roots.AddCert(root)
+		_, err = leaf.Certificate().Verify(x509.VerifyOptions{
+			DNSName:       "juju-apiserver",
+			Roots:         roots,
+			Intermediates: intermediates,
+		})
+		c.Check(err, jc.ErrorIsNil)
+	}
+}
+
+func (a *AuthoritySuite) TestCrossSignNotCA(c *gc.C) {
+	authority, err := pki.NewDefaultAuthority(a.ca, a.signer)
+	c.Assert(err, jc.ErrorIsNil)
+	leaf, err := authority.LeafRequestForGroup("testgroup").Commit()
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = pki.CrossSignCA(leaf.Certificate(), a.ca, a.signer)
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
@@ -245,6 +245,10 @@ func (env *environ) getContainerSpec(
 // getTargetServer checks to see if a valid zone was passed as a placement
 // directive in the start-up start-up arguments. If so, a server for the
 // specific node is returned.
+// Without a placement directive, the availability zone chosen by the
+// provisioner is used to target a cluster node. The provisioner spreads
+// machines hosting units of the same application across zones, so this
+// keeps those units on different cluster members.
 func (env *environ) getTargetServer(
 	ctx context.ProviderCallContext, args environs.StartInstanceParams,
 ) (Server, error) {
@@ -253,10 +257,13 @@ func (env *environ) getTargetServer(
 		return nil, errors.Trace(err)
 	}
 
-	if p.nodeName == "" {
-		return env.server(), nil
+	if p.nodeName != "" {
+		return env.server().UseTargetServer(p.nodeName)
 	}
-	return env.server().UseTargetServer(p.nodeName)
+	if args.AvailabilityZone != "" && env.server().IsClustered() {
+		return env.server().UseTargetServer(args.AvailabilityZone)
+	}
+	return env.server(), nil
 }
 
 type lxdPlacement struct {
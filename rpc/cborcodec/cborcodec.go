@@ -0,0 +1,60 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// The cborcodec package provides a CBOR (RFC 8949) encoding for RPC
+// messages, which clients may negotiate at login as a more compact and
+// cheaper to produce alternative to JSON.
+//
+// Values are encoded following the rules of encoding/json: struct
+// fields are named by their json tags (honouring "omitempty" and "-"),
+// embedded structs are flattened, map keys are strings, and types
+// implementing json.Marshaler or encoding.TextMarshaler are encoded
+// as the JSON or text they produce. This means that every params type
+// can be sent in either encoding, and that decoding a CBOR message
+// yields the same value as decoding the equivalent JSON message.
+// Types that are frequently sent may implement Marshaler and
+// Unmarshaler to avoid the cost of going through JSON.
+//
+// Data is encoded and decoded by the github.com/fxamacker/cbor package
+// wherever it treats values as encoding/json does, which it does for
+// most params types. When decoding, it limits the nesting of the data
+// and checks lengths against the size of the message before allocating
+// anything.
+package cborcodec
+
+// Name is the name by which the encoding is negotiated at login.
+const Name = "cbor"
+
+// Marshaler is implemented by types that can encode themselves
+// as a single CBOR data item.
+type Marshaler interface {
+	MarshalCBOR() ([]byte, error)
+}
+
+// Unmarshaler is implemented by types that can decode a CBOR data
+// item representing themselves. The data must be copied if it is
+// to be retained after returning.
+type Unmarshaler interface {
+	UnmarshalCBOR(data []byte) error
+}
+
+// RawMessage is a raw encoded CBOR data item. It can be used to delay
+// decoding, or to precompute an encoding.
+type RawMessage []byte
+
+// MarshalCBOR implements Marshaler.
+func (m RawMessage) MarshalCBOR() ([]byte, error) {
+	if len(m) == 0 {
+		return []byte{simpleNull}, nil
+	}
+	return m, nil
+}
+
+// UnmarshalCBOR implements Unmarshaler.
+func (m *RawMessage) UnmarshalCBOR(data []byte) error {
+	*m = append((*m)[0:0], data...)
+	return nil
+}
+
+// simpleNull is the encoding of null.
+const simpleNull = 0xf6
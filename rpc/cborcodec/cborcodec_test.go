@@ -0,0 +1,512 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package cborcodec_test
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"math"
+	"reflect"
+	"strings"
+	stdtesting "testing"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/version"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/rpc/cborcodec"
+)
+
+type suite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&suite{})
+
+func TestPackage(t *stdtesting.T) {
+	gc.TestingT(t)
+}
+
+// Test vectors from RFC 8949 appendix A.
+var encodingTests = []struct {
+	value interface{}
+	hex   string
+}{
+	{0, "00"},
+	{23, "17"},
+	{24, "1818"},
+	{100, "1864"},
+	{1000, "1903e8"},
+	{1000000, "1a000f4240"},
+	{uint64(18446744073709551615), "1bffffffffffffffff"},
+	{-1, "20"},
+	{-100, "3863"},
+	{-1000, "3903e7"},
+	{1.1, "fb3ff199999999999a"},
+	{float32(100000), "fa47c35000"},
+	{false, "f4"},
+	{true, "f5"},
+	{nil, "f6"},
+	{"", "60"},
+	{"a", "6161"},
+	{"ü", "62c3bc"},
+	{[]byte{1, 2, 3, 4}, "4401020304"},
+	{[]int{}, "80"},
+	{[]int{1, 2, 3}, "83010203"},
+	{[]interface{}{1, []int{2, 3}, []int{4, 5}}, "8301820203820405"},
+	{map[string]int{}, "a0"},
+	{map[string]string{"a": "A"}, "a161616141"},
+	{[]interface{}{"a", map[string]string{"b": "c"}}, "826161a161626163"},
+}
+
+func (*suite) TestEncoding(c *gc.C) {
+	for i, test := range encodingTests {
+		c.Logf("test %d: %#v", i, test.value)
+		data, err := cborcodec.Marshal(test.value)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(hex.EncodeToString(data), gc.Equals, test.hex)
+	}
+}
+
+var decodingTests = []struct {
+	about  string
+	hex    string
+	expect interface{}
+}{{
+	about:  "half precision float",
+	hex:    "f93c00",
+	expect: 1.0,
+}, {
+	about:  "negative half precision float",
+	hex:    "f9c400",
+	expect: -4.0,
+}, {
+	about:  "half precision subnormal",
+	hex:    "f90001",
+	expect: 5.960464477539063e-08,
+}, {
+	about:  "indefinite length array",
+	hex:    "9f018202039f0405ffff",
+	expect: []interface{}{1.0, []interface{}{2.0, 3.0}, []interface{}{4.0, 5.0}},
+}, {
+	about:  "indefinite length map",
+	hex:    "bf61610161629f0203ffff",
+	expect: map[string]interface{}{"a": 1.0, "b": []interface{}{2.0, 3.0}},
+}, {
+	about:  "indefinite length text string",
+	hex:    "7f657374726561646d696e67ff",
+	expect: "streaming",
+}, {
+	about:  "tagged date string",
+	hex:    "c074323031332d30332d32315432303a30343a30305a",
+	expect: "2013-03-21T20:04:00Z",
+}, {
+	about:  "byte string",
+	hex:    "4401020304",
+	expect: "AQIDBA==",
+}, {
+	about:  "undefined",
+	hex:    "f7",
+	expect: nil,
+}}
+
+func (*suite) TestDecodingGeneric(c *gc.C) {
+	for i, test := range decodingTests {
+		c.Logf("test %d: %s", i, test.about)
+		data, err := hex.DecodeString(test.hex)
+		c.Assert(err, jc.ErrorIsNil)
+		var v interface{}
+		err = cborcodec.Unmarshal(data, &v)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(v, jc.DeepEquals, test.expect)
+	}
+}
+
+type inner struct {
+	A string `json:"a"`
+	B int    `json:"b,omitempty"`
+}
+
+type Embedded struct {
+	E string `json:"e"`
+}
+
+type Shadowed struct {
+	S string `json:"s"`
+	E string `json:"e"`
+}
+
+type outer struct {
+	Embedded
+	*Shadowed  `json:"shadowed,omitempty"`
+	Inner      inner                  `json:"inner"`
+	InnerPtr   *inner                 `json:"inner-ptr,omitempty"`
+	Slice      []inner                `json:"slice"`
+	Map        map[string]*inner      `json:"map"`
+	IntMap     map[int]string         `json:"int-map"`
+	Any        interface{}            `json:"any"`
+	Config     map[string]interface{} `json:"config"`
+	Bytes      []byte                 `json:"bytes"`
+	Array      [2]int                 `json:"array"`
+	Float      float64                `json:"float"`
+	Skipped    string                 `json:"-"`
+	Untagged   string
+	Time       time.Time       `json:"time"`
+	TimePtr    *time.Time      `json:"time-ptr,omitempty"`
+	Version    version.Number  `json:"version"`
+	Binary     version.Binary  `json:"binary"`
+	Duration   time.Duration   `json:"duration"`
+	Delta      delta           `json:"delta"`
+	Deltas     []delta         `json:"deltas"`
+	Raw        json.RawMessage `json:"raw"`
+	unexported string
+}
+
+func newOuter() outer {
+	t := time.Date(2020, 9, 1, 12, 30, 0, 500, time.UTC)
+	return outer{
+		Embedded: Embedded{E: "embedded"},
+		Inner:    inner{A: "a"},
+		InnerPtr: &inner{A: "b", B: 2},
+		Slice:    []inner{{A: "x"}, {B: -5}},
+		Map: map[string]*inner{
+			"one": {A: "1"},
+			"nil": nil,
+		},
+		IntMap: map[int]string{-1: "minus one", 1000: "thousand"},
+		Any:    []interface{}{"x", 1.5, true, nil, map[string]interface{}{"y": 2.0}},
+		Config: map[string]interface{}{
+			"name":  "foo",
+			"count": 3.0,
+			"list":  []interface{}{"a"},
+		},
+		Bytes:    []byte("hello"),
+		Array:    [2]int{4, 5},
+		Float:    math.Pi,
+		Skipped:  "skipped",
+		Untagged: "untagged",
+		Time:     t,
+		TimePtr:  &t,
+		Version:  version.MustParse("2.9.0"),
+		Binary:   version.MustParseBinary("2.9.0-focal-amd64"),
+		Duration: time.Minute,
+		Delta:    delta{Kind: "unit", Removed: true},
+		Deltas:   []delta{{Kind: "machine"}, {Kind: "application"}},
+		Raw:      json.RawMessage(`{"x":[1,2]}`),
+	}
+}
+
+// delta encodes itself as JSON, as params.Delta does.
+type delta struct {
+	Kind    string
+	Removed bool
+}
+
+func (d *delta) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]interface{}{d.Kind, d.Removed})
+}
+
+func (d *delta) UnmarshalJSON(data []byte) error {
+	var x []interface{}
+	if err := json.Unmarshal(data, &x); err != nil {
+		return err
+	}
+	if len(x) != 2 {
+		return errors.New("bad delta")
+	}
+	d.Kind, d.Removed = x[0].(string), x[1].(bool)
+	return nil
+}
+
+// roundTripJSON returns the value that results from encoding *v as
+// JSON and decoding it again.
+func roundTripJSON(c *gc.C, v interface{}) interface{} {
+	data, err := json.Marshal(v)
+	c.Assert(err, jc.ErrorIsNil)
+	out := reflect.New(reflect.TypeOf(v).Elem())
+	err = json.Unmarshal(data, out.Interface())
+	c.Assert(err, jc.ErrorIsNil)
+	return out.Elem().Interface()
+}
+
+func roundTripCBOR(c *gc.C, v interface{}) interface{} {
+	data, err := cborcodec.Marshal(v)
+	c.Assert(err, jc.ErrorIsNil)
+	out := reflect.New(reflect.TypeOf(v).Elem())
+	err = cborcodec.Unmarshal(data, out.Interface())
+	c.Assert(err, jc.ErrorIsNil)
+	return out.Elem().Interface()
+}
+
+func (*suite) TestRoundTripLikeJSON(c *gc.C) {
+	in := newOuter()
+	viaJSON := roundTripJSON(c, &in)
+	viaCBOR := roundTripCBOR(c, &in)
+	c.Assert(viaCBOR, jc.DeepEquals, viaJSON)
+
+	out := viaCBOR.(outer)
+	c.Assert(out.Embedded.E, gc.Equals, "embedded")
+	c.Assert(out.Skipped, gc.Equals, "")
+	c.Assert(out.Untagged, gc.Equals, "untagged")
+	c.Assert(out.Time.Equal(in.Time), jc.IsTrue)
+	c.Assert(out.Deltas, jc.DeepEquals, in.Deltas)
+}
+
+func (*suite) TestDecodesLikeJSON(c *gc.C) {
+	// Decoding CBOR into an empty interface gives the same value as
+	// decoding JSON.
+	in := newOuter()
+	in.Shadowed = &Shadowed{S: "s", E: "shadowed"}
+	jsonData, err := json.Marshal(in)
+	c.Assert(err, jc.ErrorIsNil)
+	var viaJSON interface{}
+	err = json.Unmarshal(jsonData, &viaJSON)
+	c.Assert(err, jc.ErrorIsNil)
+
+	cborData, err := cborcodec.Marshal(in)
+	c.Assert(err, jc.ErrorIsNil)
+	var viaCBOR interface{}
+	err = cborcodec.Unmarshal(cborData, &viaCBOR)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Byte strings decode as base64, as JSON encodes them.
+	c.Assert(viaCBOR, jc.DeepEquals, viaJSON)
+	c.Assert(len(cborData) < len(jsonData), jc.IsTrue)
+}
+
+type allOmitted struct {
+	A string `json:"a,omitempty"`
+	B *int   `json:"b,omitempty"`
+}
+
+type quoted struct {
+	N int `json:"n,string"`
+}
+
+type conflicting struct {
+	Embedded
+	*Shadowed
+	S string `json:"s"`
+}
+
+var encodesLikeJSONTests = []struct {
+	about string
+	value interface{}
+}{{
+	about: "time",
+	value: struct {
+		T time.Time `json:"t"`
+	}{time.Date(2020, 9, 1, 12, 30, 0, 500, time.FixedZone("x", 3600))},
+}, {
+	about: "zero time",
+	value: struct {
+		T time.Time `json:"t"`
+	}{},
+}, {
+	about: "zero time in interface",
+	value: []interface{}{time.Time{}},
+}, {
+	about: "omitempty struct with empty fields",
+	value: struct {
+		S allOmitted `json:"s,omitempty"`
+	}{},
+}, {
+	about: "omitempty empty raw message",
+	value: struct {
+		R cborcodec.RawMessage `json:"r,omitempty"`
+	}{},
+}, {
+	about: "string option",
+	value: quoted{N: 42},
+}, {
+	about: "conflicting field names",
+	value: conflicting{
+		Embedded: Embedded{E: "embedded"},
+		Shadowed: &Shadowed{S: "hidden", E: "ambiguous"},
+		S:        "s",
+	},
+}, {
+	about: "byte array",
+	value: [2]byte{1, 2},
+}, {
+	about: "integer keys",
+	value: map[int]string{1: "one"},
+}}
+
+func (*suite) TestEncodesLikeJSON(c *gc.C) {
+	for i, test := range encodesLikeJSONTests {
+		c.Logf("test %d: %s", i, test.about)
+		jsonData, err := json.Marshal(test.value)
+		c.Assert(err, jc.ErrorIsNil)
+		var viaJSON interface{}
+		err = json.Unmarshal(jsonData, &viaJSON)
+		c.Assert(err, jc.ErrorIsNil)
+
+		cborData, err := cborcodec.Marshal(test.value)
+		c.Assert(err, jc.ErrorIsNil)
+		var viaCBOR interface{}
+		err = cborcodec.Unmarshal(cborData, &viaCBOR)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(viaCBOR, jc.DeepEquals, viaJSON)
+	}
+}
+
+type selfEncoded struct {
+	n int
+}
+
+func (s selfEncoded) MarshalCBOR() ([]byte, error) {
+	return cborcodec.Marshal([]int{s.n})
+}
+
+func (s *selfEncoded) UnmarshalCBOR(data []byte) error {
+	var x []int
+	if err := cborcodec.Unmarshal(data, &x); err != nil {
+		return err
+	}
+	s.n = x[0]
+	return nil
+}
+
+func (*suite) TestMarshaler(c *gc.C) {
+	data, err := cborcodec.Marshal(map[string]selfEncoded{"x": {n: 7}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(hex.EncodeToString(data), gc.Equals, "a161788107")
+
+	var out map[string]*selfEncoded
+	err = cborcodec.Unmarshal(data, &out)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out["x"].n, gc.Equals, 7)
+}
+
+func (*suite) TestRawMessage(c *gc.C) {
+	var msg struct {
+		Params cborcodec.RawMessage `json:"params"`
+		Other  cborcodec.RawMessage `json:"other"`
+	}
+	data, err := cborcodec.Marshal(map[string]interface{}{
+		"params": []int{1, 2},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = cborcodec.Unmarshal(data, &msg)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(hex.EncodeToString(msg.Params), gc.Equals, "820102")
+	c.Assert(msg.Other, gc.IsNil)
+
+	data, err = cborcodec.Marshal(msg)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(hex.EncodeToString(data), gc.Equals, "a266706172616d7382010265"+hex.EncodeToString([]byte("other"))+"f6")
+}
+
+func (*suite) TestNull(c *gc.C) {
+	v := struct {
+		P *int              `json:"p"`
+		M map[string]string `json:"m"`
+		S string            `json:"s"`
+	}{
+		P: new(int),
+		M: map[string]string{"a": "b"},
+		S: "unchanged",
+	}
+	data, err := hex.DecodeString("a36170f6616df66173f6")
+	c.Assert(err, jc.ErrorIsNil)
+	err = cborcodec.Unmarshal(data, &v)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(v.P, gc.IsNil)
+	c.Assert(v.M, gc.IsNil)
+	c.Assert(v.S, gc.Equals, "unchanged")
+}
+
+func (*suite) TestIntegralFloats(c *gc.C) {
+	// Other encoders may encode integers as floats.
+	var n int
+	err := cborcodec.Unmarshal([]byte{0xf9, 0x3c, 0x00}, &n)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(n, gc.Equals, 1)
+
+	err = cborcodec.Unmarshal([]byte{0xfb, 0x3f, 0xf1, 0x99, 0x99, 0x99, 0x99, 0x99, 0x9a}, &n)
+	c.Assert(err, gc.ErrorMatches, `json: cannot unmarshal number 1.1 into Go value of type int`)
+}
+
+var decodeErrorTests = []struct {
+	about  string
+	hex    string
+	into   interface{}
+	expect string
+}{{
+	about:  "truncated",
+	hex:    "8301",
+	into:   new(interface{}),
+	expect: "unexpected EOF",
+}, {
+	about:  "truncated string",
+	hex:    "6561",
+	into:   new(string),
+	expect: "unexpected EOF",
+}, {
+	about:  "huge length",
+	hex:    "9bffffffffffffffff",
+	into:   new([]int),
+	expect: "cbor: array length 18446744073709551615 is too large, .*",
+}, {
+	about:  "trailing data",
+	hex:    "0000",
+	into:   new(int),
+	expect: "cbor: 1 bytes of extraneous data starting at index 1",
+}, {
+	about:  "overflow",
+	hex:    "190100",
+	into:   new(int8),
+	expect: "json: cannot unmarshal number 256 into Go value of type int8",
+}, {
+	about:  "negative into unsigned",
+	hex:    "20",
+	into:   new(uint),
+	expect: "json: cannot unmarshal number -1 into Go value of type uint",
+}, {
+	about:  "wrong type",
+	hex:    "6161",
+	into:   new(int),
+	expect: "json: cannot unmarshal string into Go value of type int",
+}, {
+	about:  "non-text key",
+	hex:    "a10102",
+	into:   new(map[string]int),
+	expect: "cbor: cannot unmarshal positive integer into Go value of type string",
+}, {
+	about:  "reserved initial byte",
+	hex:    "1c",
+	into:   new(int),
+	expect: "cbor: invalid additional information 28 for type positive integer",
+}, {
+	about:  "field error",
+	hex:    "a1616101",
+	into:   new(inner),
+	expect: "json: cannot unmarshal number into Go struct field inner.a of type string",
+}}
+
+func (*suite) TestDecodeErrors(c *gc.C) {
+	for i, test := range decodeErrorTests {
+		c.Logf("test %d: %s", i, test.about)
+		data, err := hex.DecodeString(test.hex)
+		c.Assert(err, jc.ErrorIsNil)
+		err = cborcodec.Unmarshal(data, test.into)
+		c.Assert(err, gc.ErrorMatches, test.expect)
+	}
+}
+
+func (*suite) TestNestingLimit(c *gc.C) {
+	data := []byte(strings.Repeat("\x81", 20000) + "\x00")
+	var v interface{}
+	err := cborcodec.Unmarshal(data, &v)
+	c.Assert(err, gc.ErrorMatches, "cbor: exceeded max nested level 10000")
+}
+
+func (*suite) TestUnsupportedType(c *gc.C) {
+	_, err := cborcodec.Marshal(map[string]interface{}{"f": func() {}})
+	c.Assert(err, gc.ErrorMatches, `encoding type func\(\) not supported`)
+}
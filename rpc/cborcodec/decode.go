@@ -0,0 +1,147 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package cborcodec
+
+import (
+	"encoding"
+	"encoding/json"
+	"math"
+	"reflect"
+	"sync"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/juju/errors"
+)
+
+// maxDepth limits the nesting of arrays, maps and tags, so that
+// malicious input cannot exhaust the stack.
+const maxDepth = 10000
+
+// decMode decodes CBOR data. Lengths are checked against the data
+// before anything is allocated, so the element counts are only
+// limited by the size of the message, as they are for JSON.
+var decMode = func() cbor.DecMode {
+	dm, err := cbor.DecOptions{
+		MaxNestedLevels:  maxDepth,
+		MaxArrayElements: math.MaxInt32,
+		MaxMapPairs:      math.MaxInt32,
+		DefaultMapType:   reflect.TypeOf(map[string]interface{}(nil)),
+	}.DecMode()
+	if err != nil {
+		panic(err)
+	}
+	return dm
+}()
+
+// Unmarshal decodes the CBOR data item in data into the value pointed
+// to by v, following the rules encoding/json uses to decode JSON.
+// In particular, numbers decoded into an empty interface value are
+// float64, and byte strings are base64 encoded strings.
+//
+// Types that can be decoded in the same way by the cbor package are
+// decoded directly; others, such as those implementing json.Unmarshaler,
+// are decoded by way of the equivalent JSON.
+func Unmarshal(data []byte, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return errors.Errorf("cannot unmarshal into %T", v)
+	}
+	if decodesDirectly(rv.Type().Elem()) {
+		err := decMode.Unmarshal(data, v)
+		if _, ok := err.(*cbor.UnmarshalTypeError); !ok {
+			return errors.Trace(err)
+		}
+		// Other encoders may send integral values as floats, or
+		// byte slices as base64 text, which JSON allows.
+	}
+	return unmarshalJSON(data, v)
+}
+
+// unmarshalJSON decodes data into v as encoding/json would decode
+// the equivalent JSON.
+func unmarshalJSON(data []byte, v interface{}) error {
+	var x interface{}
+	if err := decMode.Unmarshal(data, &x); err != nil {
+		return errors.Trace(err)
+	}
+	jsonData, err := json.Marshal(untag(x))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(json.Unmarshal(jsonData, v))
+}
+
+// untag replaces the tagged data items in a generic CBOR value with
+// their content, which is all that JSON can represent.
+func untag(x interface{}) interface{} {
+	switch x := x.(type) {
+	case cbor.Tag:
+		return untag(x.Content)
+	case []interface{}:
+		for i, e := range x {
+			x[i] = untag(e)
+		}
+	case map[string]interface{}:
+		for k, e := range x {
+			x[k] = untag(e)
+		}
+	}
+	return x
+}
+
+var (
+	unmarshalerType     = reflect.TypeOf((*Unmarshaler)(nil)).Elem()
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+var directCache sync.Map // map[reflect.Type]bool
+
+// decodesDirectly reports whether the cbor package decodes values of
+// type t as encoding/json decodes the equivalent JSON. It does not for
+// empty interface values, which would hold integers rather than
+// float64, nor for types that decode themselves from JSON or text.
+// Types that implement Unmarshaler are decoded directly, as are times,
+// which are encoded as RFC 3339 text.
+func decodesDirectly(t reflect.Type) bool {
+	if direct, ok := directCache.Load(t); ok {
+		return direct.(bool)
+	}
+	direct := typeDecodesDirectly(t, make(map[reflect.Type]bool))
+	directCache.Store(t, direct)
+	return direct
+}
+
+// typeDecodesDirectly implements decodesDirectly. Types in seen are
+// already being checked, so recursive types are only visited once.
+func typeDecodesDirectly(t reflect.Type, seen map[reflect.Type]bool) bool {
+	if seen[t] {
+		return true
+	}
+	seen[t] = true
+	pt := reflect.PtrTo(t)
+	switch {
+	case pt.Implements(unmarshalerType), t == timeType:
+		return true
+	case pt.Implements(jsonUnmarshalerType), pt.Implements(textUnmarshalerType):
+		return false
+	}
+	switch t.Kind() {
+	case reflect.Interface:
+		return false
+	case reflect.Ptr, reflect.Slice, reflect.Array:
+		return typeDecodesDirectly(t.Elem(), seen)
+	case reflect.Map:
+		return t.Key().Kind() == reflect.String &&
+			!reflect.PtrTo(t.Key()).Implements(textUnmarshalerType) &&
+			typeDecodesDirectly(t.Elem(), seen)
+	case reflect.Struct:
+		for _, f := range cachedFields(t).list {
+			if !typeDecodesDirectly(f.typ, seen) {
+				return false
+			}
+		}
+	}
+	return true
+}
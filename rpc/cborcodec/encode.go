@@ -0,0 +1,324 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package cborcodec
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/juju/errors"
+)
+
+// encMode encodes CBOR data in the form that decMode decodes, with
+// times encoded as RFC 3339 text as encoding/json encodes them.
+var encMode = func() cbor.EncMode {
+	em, err := cbor.EncOptions{
+		Time: cbor.TimeRFC3339Nano,
+	}.EncMode()
+	if err != nil {
+		panic(err)
+	}
+	return em
+}()
+
+var (
+	marshalerType       = reflect.TypeOf((*Marshaler)(nil)).Elem()
+	jsonMarshalerType   = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	binaryMarshalerType = reflect.TypeOf((*encoding.BinaryMarshaler)(nil)).Elem()
+	timeType            = reflect.TypeOf(time.Time{})
+)
+
+// Marshal returns the CBOR encoding of v, following the rules
+// encoding/json uses to encode JSON.
+//
+// Values that the cbor package encodes in the same way are encoded
+// directly; others, such as those of types implementing json.Marshaler,
+// are encoded by way of the equivalent JSON.
+func Marshal(v interface{}) ([]byte, error) {
+	direct, err := encodesDirectly(reflect.ValueOf(v))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if !direct {
+		return marshalJSON(v)
+	}
+	data, err := encMode.Marshal(v)
+	return data, errors.Trace(err)
+}
+
+// marshalJSON encodes v as the JSON that encoding/json produces for it.
+func marshalJSON(v interface{}) ([]byte, error) {
+	jsonData, err := json.Marshal(v)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(jsonData))
+	dec.UseNumber()
+	var x interface{}
+	if err := dec.Decode(&x); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if x, err = fromJSON(x); err != nil {
+		return nil, errors.Trace(err)
+	}
+	data, err := encMode.Marshal(x)
+	return data, errors.Trace(err)
+}
+
+// fromJSON replaces the numbers in a value decoded from JSON with
+// UseNumber by integers, where they are integral, or floats.
+func fromJSON(x interface{}) (interface{}, error) {
+	switch x := x.(type) {
+	case json.Number:
+		if i, err := x.Int64(); err == nil {
+			return i, nil
+		}
+		if u, err := strconv.ParseUint(string(x), 10, 64); err == nil {
+			return u, nil
+		}
+		f, err := x.Float64()
+		return f, errors.Trace(err)
+	case []interface{}:
+		for i, e := range x {
+			e, err := fromJSON(e)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			x[i] = e
+		}
+	case map[string]interface{}:
+		for k, e := range x {
+			e, err := fromJSON(e)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			x[k] = e
+		}
+	}
+	return x, nil
+}
+
+// encodingKind describes how the values of a type are encoded.
+type encodingKind int
+
+const (
+	// encodeDirect means that the cbor package encodes all values
+	// of the type as encoding/json does.
+	encodeDirect encodingKind = iota
+
+	// encodeChecked means that whether the cbor package encodes a
+	// value of the type as encoding/json does depends on the value,
+	// so each value must be checked.
+	encodeChecked
+
+	// encodeViaJSON means that values of the type must be encoded
+	// by way of the equivalent JSON.
+	encodeViaJSON
+
+	// encodeUnsupported means that values of the type cannot be
+	// encoded.
+	encodeUnsupported
+)
+
+var encodingCache sync.Map // map[reflect.Type]encodingKind
+
+// encodesDirectly reports whether the cbor package encodes v as
+// encoding/json would.
+func encodesDirectly(v reflect.Value) (bool, error) {
+	if !v.IsValid() {
+		return true, nil
+	}
+	switch typeEncoding(v.Type()) {
+	case encodeDirect:
+		return true, nil
+	case encodeViaJSON:
+		return false, nil
+	case encodeUnsupported:
+		return false, errors.NotSupportedf("encoding type %s", v.Type())
+	}
+	switch v.Kind() {
+	case reflect.Interface, reflect.Ptr:
+		if v.IsNil() {
+			return true, nil
+		}
+		return encodesDirectly(v.Elem())
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if direct, err := encodesDirectly(v.Index(i)); !direct || err != nil {
+				return direct, err
+			}
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			if direct, err := encodesDirectly(iter.Value()); !direct || err != nil {
+				return direct, err
+			}
+		}
+	case reflect.Struct:
+		if v.Type() == timeType {
+			// The cbor package encodes zero times as null, and
+			// encoding/json refuses to encode years that RFC 3339
+			// cannot represent.
+			if !v.CanInterface() {
+				return false, nil
+			}
+			t := v.Interface().(time.Time)
+			return !t.IsZero() && t.Year() >= 0 && t.Year() < 10000, nil
+		}
+		fields := cachedFields(v.Type()).list
+		for i := range fields {
+			f := &fields[i]
+			fv, ok := fieldValue(v, f)
+			if !ok {
+				continue
+			}
+			if f.omitEmpty && omittedByCBOR(fv) != isEmptyValue(fv) {
+				return false, nil
+			}
+			if direct, err := encodesDirectly(fv); !direct || err != nil {
+				return direct, err
+			}
+		}
+	}
+	return true, nil
+}
+
+// typeEncoding returns how values of type t are encoded.
+func typeEncoding(t reflect.Type) encodingKind {
+	if enc, ok := encodingCache.Load(t); ok {
+		return enc.(encodingKind)
+	}
+	enc := newTypeEncoding(t, make(map[reflect.Type]bool))
+	encodingCache.Store(t, enc)
+	return enc
+}
+
+// newTypeEncoding implements typeEncoding. Types in seen are already
+// being checked, so recursive types are only visited once.
+func newTypeEncoding(t reflect.Type, seen map[reflect.Type]bool) encodingKind {
+	if seen[t] {
+		return encodeDirect
+	}
+	seen[t] = true
+	pt := reflect.PtrTo(t)
+	switch {
+	case t == timeType:
+		return encodeChecked
+	case pt.Implements(marshalerType):
+		return encodeDirect
+	case pt.Implements(jsonMarshalerType),
+		pt.Implements(textMarshalerType),
+		pt.Implements(binaryMarshalerType):
+		return encodeViaJSON
+	}
+	switch t.Kind() {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return encodeDirect
+	case reflect.Uintptr:
+		return encodeViaJSON
+	case reflect.Interface:
+		return encodeChecked
+	case reflect.Ptr:
+		return newTypeEncoding(t.Elem(), seen)
+	case reflect.Slice:
+		enc := newTypeEncoding(t.Elem(), seen)
+		if t.Elem().Kind() == reflect.Uint8 && enc != encodeDirect {
+			// The cbor package encodes these as byte strings,
+			// but encoding/json encodes each byte.
+			return encodeViaJSON
+		}
+		return enc
+	case reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			// Byte arrays are encoded as byte strings by the cbor
+			// package, but as arrays of numbers by encoding/json.
+			return encodeViaJSON
+		}
+		return newTypeEncoding(t.Elem(), seen)
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			// encoding/json encodes other keys as text.
+			return encodeViaJSON
+		}
+		return newTypeEncoding(t.Elem(), seen)
+	case reflect.Struct:
+		fs := cachedFields(t)
+		if fs.jsonOnly {
+			return encodeViaJSON
+		}
+		enc := encodeDirect
+		for _, f := range fs.list {
+			fenc := newTypeEncoding(f.typ, seen)
+			if f.omitEmpty && fenc == encodeDirect && f.typ.Kind() != reflect.Ptr &&
+				(f.typ.Kind() == reflect.Struct || reflect.PtrTo(f.typ).Implements(marshalerType)) {
+				// Whether the field is omitted depends on its value.
+				fenc = encodeChecked
+			}
+			if fenc > enc {
+				enc = fenc
+			}
+		}
+		return enc
+	}
+	return encodeUnsupported
+}
+
+// omittedByCBOR reports whether the cbor package omits v from a field
+// tagged "omitempty". Unlike encoding/json, it omits structs whose
+// fields would all be omitted, but never omits values of types that
+// implement Marshaler.
+func omittedByCBOR(v reflect.Value) bool {
+	t := v.Type()
+	if t.Kind() == reflect.Ptr {
+		return v.IsNil()
+	}
+	if t == timeType || reflect.PtrTo(t).Implements(marshalerType) {
+		return false
+	}
+	if t.Kind() != reflect.Struct {
+		return isEmptyValue(v)
+	}
+	fields := cachedFields(t).list
+	for i := range fields {
+		f := &fields[i]
+		if !f.omitEmpty {
+			return false
+		}
+		if fv, ok := fieldValue(v, f); ok && !omittedByCBOR(fv) {
+			return false
+		}
+	}
+	return true
+}
+
+// isEmptyValue reports whether encoding/json omits v from a field
+// tagged "omitempty".
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	}
+	return false
+}
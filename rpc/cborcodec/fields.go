@@ -0,0 +1,118 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package cborcodec
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// field describes a struct field as encoding/json encodes it.
+type field struct {
+	name      string
+	index     []int
+	typ       reflect.Type
+	omitEmpty bool
+}
+
+// structFields holds the fields that encoding/json encodes for a
+// struct type, including those promoted from embedded structs.
+type structFields struct {
+	list []field
+
+	// jsonOnly is set when the cbor package cannot be relied on to
+	// map the fields as encoding/json does: when several fields have
+	// the same name, as the packages choose between them slightly
+	// differently, or when a field is quoted with the ",string"
+	// option, which only encoding/json understands.
+	jsonOnly bool
+}
+
+var fieldCache sync.Map // map[reflect.Type]*structFields
+
+// cachedFields returns the fields of the struct type t.
+func cachedFields(t reflect.Type) *structFields {
+	if fs, ok := fieldCache.Load(t); ok {
+		return fs.(*structFields)
+	}
+	fs := &structFields{}
+	fs.add(t, nil, make(map[string]bool), make(map[reflect.Type]bool))
+	actual, _ := fieldCache.LoadOrStore(t, fs)
+	return actual.(*structFields)
+}
+
+// add adds the fields of the struct type t, which is found at the
+// given index, to fs. The names of the fields already added are in
+// names, and the types of the structs already walked are in visited.
+func (fs *structFields) add(t reflect.Type, index []int, names map[string]bool, visited map[reflect.Type]bool) {
+	if visited[t] {
+		// The type is embedded more than once, so its fields
+		// are hidden or ambiguous.
+		fs.jsonOnly = true
+		return
+	}
+	visited[t] = true
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		ft := sf.Type
+		if ft.Name() == "" && ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if sf.Anonymous {
+			if sf.PkgPath != "" && ft.Kind() != reflect.Struct {
+				// Ignore embedded fields of unexported
+				// non-struct types.
+				continue
+			}
+		} else if sf.PkgPath != "" {
+			// Ignore unexported fields.
+			continue
+		}
+		tag := sf.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts := tag, ""
+		if j := strings.Index(tag, ","); j >= 0 {
+			name, opts = tag[:j], tag[j:]
+		}
+		fieldIndex := make([]int, len(index)+1)
+		copy(fieldIndex, index)
+		fieldIndex[len(index)] = i
+
+		if name == "" && sf.Anonymous && ft.Kind() == reflect.Struct {
+			fs.add(ft, fieldIndex, names, visited)
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		if names[name] || strings.Contains(opts, ",string") || sf.Tag.Get("cbor") != "" {
+			fs.jsonOnly = true
+		}
+		names[name] = true
+		fs.list = append(fs.list, field{
+			name:      name,
+			index:     fieldIndex,
+			typ:       sf.Type,
+			omitEmpty: strings.Contains(opts, ",omitempty"),
+		})
+	}
+}
+
+// fieldValue returns the value of the field in the struct v. It
+// returns false if the field is within a nil embedded struct pointer.
+func fieldValue(v reflect.Value, f *field) (reflect.Value, bool) {
+	for i, x := range f.index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
@@ -2,6 +2,10 @@
 // Licensed under the AGPLv3, see LICENCE file for details.
 
 // The jsoncodec package provides a JSON codec for the rpc package.
+// Connections that can carry binary messages may additionally
+// negotiate the more compact CBOR encoding provided by the cborcodec
+// package at login, after which both encodings are accepted on the
+// same connection.
 package jsoncodec
 
 import (
@@ -14,6 +18,7 @@ import (
 	"github.com/juju/loggo"
 
 	"github.com/juju/juju/rpc"
+	"github.com/juju/juju/rpc/cborcodec"
 )
 
 var logger = loggo.GetLogger("juju.rpc.jsoncodec")
@@ -28,17 +33,41 @@ type JSONConn interface {
 	Close() error
 }
 
+// BinaryConn is implemented by connections that can carry binary
+// messages as well as JSON ones.
+type BinaryConn interface {
+	JSONConn
+	// SendBinary sends data as a single binary message.
+	SendBinary(data []byte) error
+	// ReceiveMessage receives the next message, reporting
+	// whether it was sent as a binary message.
+	ReceiveMessage() (data []byte, binary bool, err error)
+}
+
 // Codec implements rpc.Codec for a connection.
 type Codec struct {
 	// msg holds the message that's just been read by ReadHeader, so
 	// that the body can be read by ReadBody.
-	msg         inMsgV1
+	msg inMsgV1
+	// msgBinary records whether msg was received as CBOR, in which
+	// case its params and response hold CBOR rather than JSON.
+	msgBinary   bool
 	conn        JSONConn
 	logMessages int32
 	mu          sync.Mutex
 	closing     bool
+	// binary records whether CBOR has been negotiated, in which
+	// case outgoing requests are sent as CBOR and CBOR messages
+	// are accepted.
+	binary bool
 }
 
+// binaryVersion is the version reported for messages received as
+// CBOR, which otherwise have the same structure as version 1 messages.
+// Replies carry the version of the request they answer, so requests
+// received as CBOR are answered with CBOR.
+const binaryVersion = 2
+
 // New returns an rpc codec that uses conn to send and receive
 // messages.
 func New(conn JSONConn) *Codec {
@@ -62,6 +91,21 @@ type inMsgV0 struct {
 	Response  json.RawMessage
 }
 
+// inMsgCBOR holds an incoming CBOR message. Its fields are named
+// as for inMsgV1.
+type inMsgCBOR struct {
+	RequestId uint64               `json:"request-id"`
+	Type      string               `json:"type"`
+	Version   int                  `json:"version"`
+	Id        string               `json:"id"`
+	Request   string               `json:"request"`
+	Params    cborcodec.RawMessage `json:"params"`
+	Error     string               `json:"error"`
+	ErrorCode string               `json:"error-code"`
+	ErrorInfo cborcodec.RawMessage `json:"error-info"`
+	Response  cborcodec.RawMessage `json:"response"`
+}
+
 type inMsgV1 struct {
 	RequestId uint64                 `json:"request-id"`
 	Type      string                 `json:"type"`
@@ -114,13 +158,62 @@ func (c *Codec) isClosing() bool {
 	return c.closing
 }
 
+// SupportsBinary reports whether the underlying connection can carry
+// CBOR encoded messages.
+func (c *Codec) SupportsBinary() bool {
+	_, ok := c.conn.(BinaryConn)
+	return ok
+}
+
+// EnableBinary causes subsequent requests to be sent encoded as CBOR,
+// and CBOR messages to be accepted. Until it is called, a binary
+// message is an error. It should only be called once the other side
+// of the connection is known to understand CBOR, which is negotiated
+// at login. Responses are always encoded in the same way as the
+// request they answer.
+func (c *Codec) EnableBinary() error {
+	if !c.SupportsBinary() {
+		return errors.NotSupportedf("binary messages on %T", c.conn)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.binary = true
+	return nil
+}
+
+func (c *Codec) binaryEnabled() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.binary
+}
+
+func (c *Codec) receive() (m json.RawMessage, binary bool, err error) {
+	if conn, ok := c.conn.(BinaryConn); ok {
+		data, binary, err := conn.ReceiveMessage()
+		return data, binary, err
+	}
+	err = c.conn.Receive(&m)
+	return m, false, err
+}
+
 func (c *Codec) ReadHeader(hdr *rpc.Header) error {
-	var m json.RawMessage
 	var version int
-	err := c.conn.Receive(&m)
+	m, binary, err := c.receive()
 	if err == nil {
-		logger.Tracef("<- %s", m)
-		c.msg, version, err = c.readMessage(m)
+		if binary {
+			if !c.binaryEnabled() {
+				// Don't decode anything that arrives before
+				// CBOR has been negotiated.
+				err = errors.New("unexpected binary message before CBOR was negotiated")
+			} else {
+				traceBinary("<-", m)
+				c.msg, version, err = c.readBinaryMessage(m)
+			}
+		} else {
+			logger.Tracef("<- %s", m)
+			c.msg, version, err = c.readMessage(m)
+		}
+		c.msgBinary = binary
 	} else {
 		logger.Tracef("<- error: %v (closing %v)", err, c.isClosing())
 	}
@@ -146,6 +239,33 @@ func (c *Codec) ReadHeader(hdr *rpc.Header) error {
 	return nil
 }
 
+// readBinaryMessage reads a CBOR message. The params and response of
+// the returned message hold CBOR.
+func (c *Codec) readBinaryMessage(m []byte) (inMsgV1, int, error) {
+	var msg inMsgCBOR
+	if err := cborcodec.Unmarshal(m, &msg); err != nil {
+		return inMsgV1{}, -1, errors.Trace(err)
+	}
+	var errorInfo map[string]interface{}
+	if len(msg.ErrorInfo) > 0 {
+		if err := cborcodec.Unmarshal(msg.ErrorInfo, &errorInfo); err != nil {
+			return inMsgV1{}, -1, errors.Trace(err)
+		}
+	}
+	return inMsgV1{
+		RequestId: msg.RequestId,
+		Type:      msg.Type,
+		Version:   msg.Version,
+		Id:        msg.Id,
+		Request:   msg.Request,
+		Params:    json.RawMessage(msg.Params),
+		Error:     msg.Error,
+		ErrorCode: msg.ErrorCode,
+		ErrorInfo: errorInfo,
+		Response:  json.RawMessage(msg.Response),
+	}, binaryVersion, nil
+}
+
 func (c *Codec) readMessage(m json.RawMessage) (inMsgV1, int, error) {
 	var msg inMsgV1
 	if err := json.Unmarshal(m, &msg); err != nil {
@@ -194,6 +314,9 @@ func (c *Codec) ReadBody(body interface{}, isRequest bool) error {
 		// equivalent to an empty object.
 		return nil
 	}
+	if c.msgBinary {
+		return cborcodec.Unmarshal(rawBody, body)
+	}
 	return json.Unmarshal(rawBody, body)
 }
 
@@ -219,6 +342,15 @@ func (c *Codec) WriteMessage(hdr *rpc.Header, body interface{}) error {
 	if err != nil {
 		return errors.Trace(err)
 	}
+	if c.sendBinary(hdr) {
+		data, err := cborcodec.Marshal(msg)
+		if err != nil {
+			logger.Tracef("-> marshal error: %v", err)
+			return errors.Trace(err)
+		}
+		traceBinary("->", data)
+		return c.conn.(BinaryConn).SendBinary(data)
+	}
 	if logger.IsTraceEnabled() {
 		data, err := json.Marshal(msg)
 		if err != nil {
@@ -230,11 +362,41 @@ func (c *Codec) WriteMessage(hdr *rpc.Header, body interface{}) error {
 	return c.conn.Send(msg)
 }
 
+// sendBinary reports whether the message with the given header
+// should be sent encoded as CBOR.
+func (c *Codec) sendBinary(hdr *rpc.Header) bool {
+	switch {
+	case hdr.Version == binaryVersion:
+		return true
+	case hdr.IsRequest() && hdr.Version == 1:
+		return c.binaryEnabled()
+	}
+	return false
+}
+
+// traceBinary logs the CBOR message in data as the equivalent JSON.
+func traceBinary(prefix string, data []byte) {
+	if !logger.IsTraceEnabled() {
+		return
+	}
+	var v interface{}
+	if err := cborcodec.Unmarshal(data, &v); err != nil {
+		logger.Tracef("%s (cbor, %d bytes) unmarshal error: %v", prefix, len(data), err)
+		return
+	}
+	jsonData, err := json.Marshal(v)
+	if err != nil {
+		logger.Tracef("%s (cbor, %d bytes) marshal error: %v", prefix, len(data), err)
+		return
+	}
+	logger.Tracef("%s (cbor, %d bytes) %s", prefix, len(data), jsonData)
+}
+
 func response(hdr *rpc.Header, body interface{}) (interface{}, error) {
 	switch hdr.Version {
 	case 0:
 		return newOutMsgV0(hdr, body), nil
-	case 1:
+	case 1, binaryVersion:
 		return newOutMsgV1(hdr, body), nil
 	default:
 		return nil, errors.Errorf("unsupported version %d", hdr.Version)
@@ -10,11 +10,13 @@ import (
 	"reflect"
 	stdtesting "testing"
 
+	jujuerrors "github.com/juju/errors"
 	"github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/rpc"
+	"github.com/juju/juju/rpc/cborcodec"
 	"github.com/juju/juju/rpc/jsoncodec"
 )
 
@@ -283,6 +285,147 @@ func (*suite) TestWrite(c *gc.C) {
 	}
 }
 
+func (*suite) TestEnableBinaryNotSupported(c *gc.C) {
+	codec := jsoncodec.New(&testConn{})
+	c.Assert(codec.SupportsBinary(), jc.IsFalse)
+	err := codec.EnableBinary()
+	c.Assert(err, jc.Satisfies, jujuerrors.IsNotSupported)
+}
+
+func (*suite) TestBinaryRequestGetsBinaryResponse(c *gc.C) {
+	req, err := cborcodec.Marshal(map[string]interface{}{
+		"request-id": 5,
+		"type":       "foo",
+		"version":    2,
+		"request":    "frob",
+		"params":     map[string]string{"X": "param"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	conn := &testBinaryConn{
+		testConn: testConn{
+			readMsgs: []string{`{"request-id": 6, "type": "foo", "request": "frob"}`},
+		},
+		readBinary: [][]byte{req},
+	}
+	codec := jsoncodec.New(conn)
+	c.Assert(codec.SupportsBinary(), jc.IsTrue)
+	err = codec.EnableBinary()
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Messages received as CBOR are reported with version 2.
+	var hdr rpc.Header
+	err = codec.ReadHeader(&hdr)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(hdr, gc.DeepEquals, rpc.Header{
+		RequestId: 5,
+		Request: rpc.Request{
+			Type:    "foo",
+			Version: 2,
+			Action:  "frob",
+		},
+		Version: 2,
+	})
+	var body value
+	err = codec.ReadBody(&body, true)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(body, gc.Equals, value{X: "param"})
+
+	// A request received as JSON is answered with JSON.
+	err = codec.ReadHeader(&hdr)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(hdr.RequestId, gc.Equals, uint64(6))
+	err = codec.WriteMessage(&rpc.Header{RequestId: 6, Version: 1}, &value{X: "json"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(conn.writeMsgs, gc.HasLen, 1)
+	assertJSONEqual(c, conn.writeMsgs[0], `{"request-id": 6, "response": {"X": "json"}}`)
+
+	err = codec.WriteMessage(&rpc.Header{RequestId: 5, Version: 2}, &value{X: "result"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(conn.writeBinary, gc.HasLen, 1)
+	var resp map[string]interface{}
+	err = cborcodec.Unmarshal(conn.writeBinary[0], &resp)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(resp, jc.DeepEquals, map[string]interface{}{
+		"request-id": 5.0,
+		"response":   map[string]interface{}{"X": "result"},
+	})
+}
+
+func (*suite) TestBinaryBeforeEnableBinary(c *gc.C) {
+	req, err := cborcodec.Marshal(map[string]interface{}{
+		"request-id": 1,
+		"type":       "foo",
+		"request":    "frob",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	codec := jsoncodec.New(&testBinaryConn{readBinary: [][]byte{req}})
+	var hdr rpc.Header
+	err = codec.ReadHeader(&hdr)
+	c.Assert(err, gc.ErrorMatches, "error receiving message: unexpected binary message before CBOR was negotiated")
+}
+
+func (*suite) TestBinaryErrorInfo(c *gc.C) {
+	resp, err := cborcodec.Marshal(map[string]interface{}{
+		"request-id": 1,
+		"error":      "boom",
+		"error-code": "bad",
+		"error-info": map[string]interface{}{"retry": 2},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	codec := jsoncodec.New(&testBinaryConn{readBinary: [][]byte{resp}})
+	err = codec.EnableBinary()
+	c.Assert(err, jc.ErrorIsNil)
+	var hdr rpc.Header
+	err = codec.ReadHeader(&hdr)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(hdr, jc.DeepEquals, rpc.Header{
+		RequestId: 1,
+		Error:     "boom",
+		ErrorCode: "bad",
+		ErrorInfo: map[string]interface{}{"retry": 2.0},
+		Version:   2,
+	})
+}
+
+func (*suite) TestEnableBinary(c *gc.C) {
+	conn := &testBinaryConn{}
+	codec := jsoncodec.New(conn)
+	hdr := &rpc.Header{
+		RequestId: 1,
+		Request: rpc.Request{
+			Type:   "foo",
+			Action: "frob",
+		},
+		Version: 1,
+	}
+	err := codec.WriteMessage(hdr, &value{X: "before"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(conn.writeMsgs, gc.HasLen, 1)
+	c.Assert(conn.writeBinary, gc.HasLen, 0)
+
+	err = codec.EnableBinary()
+	c.Assert(err, jc.ErrorIsNil)
+	hdr.RequestId = 2
+	err = codec.WriteMessage(hdr, &value{X: "after"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(conn.writeMsgs, gc.HasLen, 1)
+	c.Assert(conn.writeBinary, gc.HasLen, 1)
+
+	// The message can be read back by a codec on the other side.
+	other := jsoncodec.New(&testBinaryConn{readBinary: conn.writeBinary})
+	var readHdr rpc.Header
+	err = other.EnableBinary()
+	c.Assert(err, jc.ErrorIsNil)
+	err = other.ReadHeader(&readHdr)
+	c.Assert(err, jc.ErrorIsNil)
+	hdr.Version = 2
+	c.Assert(readHdr, gc.DeepEquals, *hdr)
+	var body value
+	err = other.ReadBody(&body, true)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(body, gc.Equals, value{X: "after"})
+}
+
 func (*suite) TestDumpRequest(c *gc.C) {
 	for i, test := range []struct {
 		hdr    rpc.Header
@@ -442,3 +585,30 @@ func (c *testConn) Close() error {
 	c.closed = true
 	return nil
 }
+
+// testBinaryConn is a testConn that also implements
+// jsoncodec.BinaryConn. Binary messages are read before
+// JSON ones.
+type testBinaryConn struct {
+	testConn
+	readBinary  [][]byte
+	writeBinary [][]byte
+}
+
+func (c *testBinaryConn) SendBinary(data []byte) error {
+	c.writeBinary = append(c.writeBinary, data)
+	return nil
+}
+
+func (c *testBinaryConn) ReceiveMessage() ([]byte, bool, error) {
+	if len(c.readBinary) > 0 {
+		data := c.readBinary[0]
+		c.readBinary = c.readBinary[1:]
+		return data, true, nil
+	}
+	var m json.RawMessage
+	if err := c.Receive(&m); err != nil {
+		return nil, false, err
+	}
+	return m, false, nil
+}
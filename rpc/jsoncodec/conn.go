@@ -27,7 +27,8 @@ type wsJSONConn struct {
 }
 
 // NewWebsocketConn returns a JSONConn implementation
-// that uses the given connection for transport.
+// that uses the given connection for transport. The
+// returned connection also implements BinaryConn.
 func NewWebsocketConn(conn *websocket.Conn) JSONConn {
 	return &wsJSONConn{conn: conn}
 }
@@ -41,9 +42,31 @@ func (conn *wsJSONConn) Send(msg interface{}) error {
 func (conn *wsJSONConn) Receive(msg interface{}) error {
 	conn.readMutex.Lock()
 	defer conn.readMutex.Unlock()
-	// When receiving a message, if error has been closed from the other
-	// side, wrap with io.EOF as this is the expected error.
-	err := conn.conn.ReadJSON(msg)
+	return wrapCloseError(conn.conn.ReadJSON(msg))
+}
+
+// SendBinary implements BinaryConn.
+func (conn *wsJSONConn) SendBinary(data []byte) error {
+	conn.writeMutex.Lock()
+	defer conn.writeMutex.Unlock()
+	return conn.conn.WriteMessage(websocket.BinaryMessage, data)
+}
+
+// ReceiveMessage implements BinaryConn.
+func (conn *wsJSONConn) ReceiveMessage() ([]byte, bool, error) {
+	conn.readMutex.Lock()
+	defer conn.readMutex.Unlock()
+	messageType, data, err := conn.conn.ReadMessage()
+	if err != nil {
+		return nil, false, wrapCloseError(err)
+	}
+	return data, messageType == websocket.BinaryMessage, nil
+}
+
+// wrapCloseError wraps err with io.EOF if it reports that the
+// connection has been closed from the other side, as this is the
+// expected error.
+func wrapCloseError(err error) error {
 	if err != nil {
 		if websocket.IsCloseError(err,
 			websocket.CloseNormalClosure,
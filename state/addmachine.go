@@ -114,6 +114,39 @@ func (st *State) AddMachineInsideNewMachine(template, parentTemplate MachineTemp
 	return st.addMachine(mdoc, ops)
 }
 
+// PrecheckMachine checks, without changing the model, whether a new
+// top-level machine could be added with the given template. The
+// constraints are validated and merged with the model constraints, and
+// the provider is asked to precheck the instance. Any constraint
+// attributes that are not supported by the provider are returned.
+func (st *State) PrecheckMachine(template MachineTemplate) (_ []string, err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot add a new machine")
+	if template.InstanceId != "" {
+		return nil, errors.New("cannot precheck a machine with an instance id")
+	}
+	unsupported, err := st.validateConstraints(template.Constraints)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	template, err = st.effectiveMachineTemplate(template, false)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	volumeAttachments, err := st.machineTemplateVolumeAttachmentParams(template)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := st.precheckInstance(
+		template.Series,
+		template.Constraints,
+		template.Placement,
+		volumeAttachments,
+	); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return unsupported, nil
+}
+
 // AddMachineInsideMachine adds a machine inside a container of the
 // given type on the existing machine with id=parentId.
 func (st *State) AddMachineInsideMachine(template MachineTemplate, parentId string, containerType instance.ContainerType) (*Machine, error) {
@@ -166,6 +166,14 @@ func allCollections() CollectionSchema {
 		// This collection holds cloud definitions.
 		cloudsC: {global: true},
 
+		// This collection holds custom roles, which are assigned to
+		// model users through permissionsC.
+		rolesC: {global: true},
+
+		// This collection holds API tokens, with which automation
+		// users log in to a single model.
+		apiTokensC: {global: true},
+
 		// This collection holds users' cloud credentials.
 		cloudCredentialsC: {
 			global: true,
@@ -602,6 +610,8 @@ const (
 	relationScopesC            = "relationscopes"
 	relationsC                 = "relations"
 	restoreInfoC               = "restoreInfo"
+	rolesC                     = "roles"
+	apiTokensC                 = "apiTokens"
 	sequenceC                  = "sequence"
 	applicationsC              = "applications"
 	endpointBindingsC          = "endpointbindings"
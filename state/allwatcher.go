@@ -1451,12 +1451,27 @@ func (g *backingGeneration) updated(ctx *allWatcherContext) error {
 		assigned[k] = units
 	}
 
+	var charmURLs map[string]string
+	if len(g.CharmURLs) > 0 {
+		charmURLs = make(map[string]string, len(g.CharmURLs))
+		for k, v := range g.CharmURLs {
+			charmURLs[k] = v
+		}
+	}
+
+	var promoteAt int64
+	if g.Promotion != nil {
+		promoteAt = g.Promotion.Started + g.Promotion.SoakTime
+	}
+
 	info := &multiwatcher.BranchInfo{
 		ModelUUID:     g.ModelUUID,
 		ID:            ctx.id, // Id not stored on the doc.
 		Name:          g.Name,
 		AssignedUnits: assigned,
 		Config:        cfg,
+		CharmURLs:     charmURLs,
+		PromoteAt:     promoteAt,
 		Created:       g.Created,
 		CreatedBy:     g.CreatedBy,
 		Completed:     g.Completed,
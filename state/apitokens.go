@@ -0,0 +1,255 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+	"github.com/juju/utils"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/core/permission"
+)
+
+// APITokenPrefix is the prefix of the secrets of API tokens, which
+// distinguishes them from other bearer credentials such as OpenID
+// Connect ID tokens. A secret has the form "jujutoken-<id>.<key>".
+const APITokenPrefix = "jujutoken-"
+
+// IsAPIToken reports whether the bearer credential is the secret of an
+// API token.
+func IsAPIToken(credential string) bool {
+	return strings.HasPrefix(credential, APITokenPrefix)
+}
+
+// APIToken describes a token that allows its owner to log in to a
+// single model without a password, with at most the given access.
+type APIToken struct {
+	// ID identifies the token. It is not secret, and is recorded in
+	// the audit log for logins made with the token.
+	ID string
+
+	// Owner is the user the token logs in as.
+	Owner names.UserTag
+
+	// Model is the only model the token may be used to log in to.
+	Model names.ModelTag
+
+	// Access is the highest access to the model granted to logins
+	// made with the token. The owner's own access to the model may
+	// further limit it.
+	Access permission.Access
+
+	// CreatedBy is the user who minted the token.
+	CreatedBy names.UserTag
+
+	// Created is when the token was minted.
+	Created time.Time
+
+	// Expires is when the token stops being accepted.
+	Expires time.Time
+}
+
+// AddAPITokenArgs holds the arguments to AddAPIToken.
+type AddAPITokenArgs struct {
+	Owner     names.UserTag
+	Model     names.ModelTag
+	Access    permission.Access
+	CreatedBy names.UserTag
+	Expires   time.Time
+}
+
+// apiTokenDoc is the persistent representation of an API token. Only
+// a hash of the token's key is stored.
+type apiTokenDoc struct {
+	ID        string    `bson:"_id"`
+	KeyHash   string    `bson:"key-hash"`
+	Owner     string    `bson:"owner"`
+	ModelUUID string    `bson:"model-uuid"`
+	Access    string    `bson:"access"`
+	CreatedBy string    `bson:"created-by"`
+	Created   time.Time `bson:"created"`
+	Expires   time.Time `bson:"expires"`
+}
+
+func (doc apiTokenDoc) token() APIToken {
+	return APIToken{
+		ID:        doc.ID,
+		Owner:     names.NewUserTag(doc.Owner),
+		Model:     names.NewModelTag(doc.ModelUUID),
+		Access:    permission.Access(doc.Access),
+		CreatedBy: names.NewUserTag(doc.CreatedBy),
+		Created:   doc.Created.UTC(),
+		Expires:   doc.Expires.UTC(),
+	}
+}
+
+func apiTokenKeyHash(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// AddAPIToken mints a new API token, returning it along with its
+// secret. The secret cannot be retrieved again.
+func (st *State) AddAPIToken(args AddAPITokenArgs) (APIToken, string, error) {
+	if err := permission.ValidateModelAccess(args.Access); err != nil {
+		return APIToken{}, "", errors.Trace(err)
+	}
+	now := st.nowToTheSecond()
+	if !args.Expires.After(now) {
+		return APIToken{}, "", errors.NotValidf("expiry time %s in the past", args.Expires.Format(time.RFC3339))
+	}
+	if args.Owner.IsLocal() {
+		if _, err := st.User(args.Owner); err != nil {
+			return APIToken{}, "", errors.Annotate(err, "cannot add API token")
+		}
+	}
+	idBytes, err := utils.RandomBytes(8)
+	if err != nil {
+		return APIToken{}, "", errors.Trace(err)
+	}
+	keyBytes, err := utils.RandomBytes(24)
+	if err != nil {
+		return APIToken{}, "", errors.Trace(err)
+	}
+	id, key := hex.EncodeToString(idBytes), hex.EncodeToString(keyBytes)
+	doc := apiTokenDoc{
+		ID:        id,
+		KeyHash:   apiTokenKeyHash(key),
+		Owner:     args.Owner.Id(),
+		ModelUUID: args.Model.Id(),
+		Access:    string(args.Access),
+		CreatedBy: args.CreatedBy.Id(),
+		Created:   now,
+		Expires:   args.Expires.UTC(),
+	}
+	ops := []txn.Op{{
+		C:      modelsC,
+		Id:     args.Model.Id(),
+		Assert: txn.DocExists,
+	}, {
+		C:      apiTokensC,
+		Id:     id,
+		Assert: txn.DocMissing,
+		Insert: &doc,
+	}}
+	if err := st.db().RunTransaction(ops); err == txn.ErrAborted {
+		return APIToken{}, "", errors.NotFoundf("model %q", args.Model.Id())
+	} else if err != nil {
+		return APIToken{}, "", errors.Annotate(err, "cannot add API token")
+	}
+	return doc.token(), APITokenPrefix + id + "." + key, nil
+}
+
+// APIToken returns the API token with the given ID.
+func (st *State) APIToken(id string) (APIToken, error) {
+	doc, err := st.apiTokenDoc(id)
+	if err != nil {
+		return APIToken{}, errors.Trace(err)
+	}
+	return doc.token(), nil
+}
+
+func (st *State) apiTokenDoc(id string) (apiTokenDoc, error) {
+	tokens, closer := st.db().GetCollection(apiTokensC)
+	defer closer()
+
+	var doc apiTokenDoc
+	err := tokens.FindId(id).One(&doc)
+	if err == mgo.ErrNotFound {
+		return apiTokenDoc{}, errors.NotFoundf("API token %q", id)
+	}
+	if err != nil {
+		return apiTokenDoc{}, errors.Annotatef(err, "cannot get API token %q", id)
+	}
+	return doc, nil
+}
+
+// AllAPITokens returns all the API tokens in the controller, including
+// expired ones, in the order they were minted.
+func (st *State) AllAPITokens() ([]APIToken, error) {
+	tokens, closer := st.db().GetCollection(apiTokensC)
+	defer closer()
+
+	var docs []apiTokenDoc
+	if err := tokens.Find(nil).Sort("created", "_id").All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get API tokens")
+	}
+	result := make([]APIToken, len(docs))
+	for i, doc := range docs {
+		result[i] = doc.token()
+	}
+	return result, nil
+}
+
+// RemoveAPIToken revokes the API token with the given ID. Connections
+// already made with the token are not affected.
+func (st *State) RemoveAPIToken(id string) error {
+	ops := []txn.Op{{
+		C:      apiTokensC,
+		Id:     id,
+		Assert: txn.DocExists,
+		Remove: true,
+	}}
+	err := st.db().RunTransaction(ops)
+	if err == txn.ErrAborted {
+		err = errors.NotFoundf("API token %q", id)
+	}
+	return errors.Trace(err)
+}
+
+// removeUserAPITokensOps returns operations that remove the API tokens
+// owned by the user.
+func (st *State) removeUserAPITokensOps(user names.UserTag) ([]txn.Op, error) {
+	// User names are not case sensitive.
+	owner := bson.D{
+		{"$regex", "^" + regexp.QuoteMeta(user.Id()) + "$"},
+		{"$options", "i"},
+	}
+	ops, err := st.removeInCollectionOps(apiTokensC, bson.D{{"owner", owner}})
+	return ops, errors.Trace(err)
+}
+
+// CheckAPIToken returns the API token with the given secret, if the
+// token has not expired or been revoked. Otherwise it returns an
+// Unauthorized error.
+func (st *State) CheckAPIToken(secret string) (APIToken, error) {
+	id, key, ok := splitAPITokenSecret(secret)
+	if !ok {
+		return APIToken{}, errors.Unauthorizedf("malformed API token")
+	}
+	doc, err := st.apiTokenDoc(id)
+	if errors.IsNotFound(err) {
+		return APIToken{}, errors.Unauthorizedf("API token %q not found", id)
+	} else if err != nil {
+		return APIToken{}, errors.Trace(err)
+	}
+	if subtle.ConstantTimeCompare([]byte(apiTokenKeyHash(key)), []byte(doc.KeyHash)) != 1 {
+		return APIToken{}, errors.Unauthorizedf("invalid key for API token %q", id)
+	}
+	if !st.clock().Now().Before(doc.Expires) {
+		return APIToken{}, errors.Unauthorizedf("API token %q expired", id)
+	}
+	return doc.token(), nil
+}
+
+func splitAPITokenSecret(secret string) (id, key string, ok bool) {
+	if !IsAPIToken(secret) {
+		return "", "", false
+	}
+	parts := strings.SplitN(strings.TrimPrefix(secret, APITokenPrefix), ".", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", false
+	}
+	return parts[0], parts[1], true
+}
@@ -0,0 +1,153 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/permission"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing/factory"
+)
+
+type APITokensSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&APITokensSuite{})
+
+func (s *APITokensSuite) addToken(c *gc.C, owner names.UserTag) (state.APIToken, string) {
+	token, secret, err := s.State.AddAPIToken(state.AddAPITokenArgs{
+		Owner:     owner,
+		Model:     s.Model.ModelTag(),
+		Access:    permission.ReadAccess,
+		CreatedBy: s.Owner,
+		Expires:   s.Clock.Now().Add(time.Hour),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	return token, secret
+}
+
+func (s *APITokensSuite) TestAddAPIToken(c *gc.C) {
+	bot := s.Factory.MakeUser(c, &factory.UserParams{Name: "bot"})
+	token, secret := s.addToken(c, bot.UserTag())
+	c.Assert(token.Owner, gc.Equals, bot.UserTag())
+	c.Assert(token.Model, gc.Equals, s.Model.ModelTag())
+	c.Assert(token.Access, gc.Equals, permission.ReadAccess)
+	c.Assert(token.CreatedBy, gc.Equals, s.Owner)
+	c.Assert(state.IsAPIToken(secret), jc.IsTrue)
+	c.Assert(strings.HasPrefix(secret, state.APITokenPrefix+token.ID+"."), jc.IsTrue)
+
+	got, err := s.State.APIToken(token.ID)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(got, jc.DeepEquals, token)
+
+	tokens, err := s.State.AllAPITokens()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(tokens, jc.DeepEquals, []state.APIToken{token})
+}
+
+func (s *APITokensSuite) TestAddAPITokenInvalid(c *gc.C) {
+	args := state.AddAPITokenArgs{
+		Owner:     names.NewUserTag("nobody"),
+		Model:     s.Model.ModelTag(),
+		Access:    permission.ReadAccess,
+		CreatedBy: s.Owner,
+		Expires:   s.Clock.Now().Add(time.Hour),
+	}
+	_, _, err := s.State.AddAPIToken(args)
+	c.Assert(err, gc.ErrorMatches, `cannot add API token: user "nobody" not found`)
+
+	args.Owner = s.Owner
+	args.Access = permission.SuperuserAccess
+	_, _, err = s.State.AddAPIToken(args)
+	c.Assert(err, gc.ErrorMatches, `"superuser" model access not valid`)
+
+	args.Access = permission.ReadAccess
+	args.Expires = s.Clock.Now().Add(-time.Hour)
+	_, _, err = s.State.AddAPIToken(args)
+	c.Assert(err, gc.ErrorMatches, `expiry time .* in the past not valid`)
+
+	args.Expires = s.Clock.Now().Add(time.Hour)
+	args.Model = names.NewModelTag("deadbeef-0bad-400d-8000-4b1d0d06f00d")
+	_, _, err = s.State.AddAPIToken(args)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *APITokensSuite) TestCheckAPIToken(c *gc.C) {
+	token, secret := s.addToken(c, s.Owner)
+	got, err := s.State.CheckAPIToken(secret)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(got, jc.DeepEquals, token)
+
+	_, err = s.State.CheckAPIToken(secret + "0")
+	c.Assert(err, gc.ErrorMatches, `invalid key for API token ".*"`)
+	_, err = s.State.CheckAPIToken("jujutoken-nodot")
+	c.Assert(err, gc.ErrorMatches, `malformed API token`)
+
+	s.Clock.Advance(time.Hour)
+	_, err = s.State.CheckAPIToken(secret)
+	c.Assert(err, gc.ErrorMatches, `API token ".*" expired`)
+	c.Assert(err, jc.Satisfies, errors.IsUnauthorized)
+}
+
+func (s *APITokensSuite) TestRemoveAPIToken(c *gc.C) {
+	token, secret := s.addToken(c, s.Owner)
+	err := s.State.RemoveAPIToken(token.ID)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.RemoveAPIToken(token.ID)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	_, err = s.State.CheckAPIToken(secret)
+	c.Assert(err, gc.ErrorMatches, `API token ".*" not found`)
+	c.Assert(err, jc.Satisfies, errors.IsUnauthorized)
+}
+
+func (s *APITokensSuite) TestRemoveUserRemovesAPITokens(c *gc.C) {
+	bot := s.Factory.MakeUser(c, &factory.UserParams{Name: "Bot"})
+	token, secret := s.addToken(c, bot.UserTag())
+	other, _ := s.addToken(c, s.Owner)
+
+	err := s.State.RemoveUser(names.NewUserTag("bot"))
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.APIToken(token.ID)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	_, err = s.State.CheckAPIToken(secret)
+	c.Assert(err, jc.Satisfies, errors.IsUnauthorized)
+
+	_, err = s.State.APIToken(other.ID)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *APITokensSuite) TestRemoveModelRemovesAPITokens(c *gc.C) {
+	st := s.Factory.MakeModel(c, nil)
+	defer st.Close()
+	model, err := st.Model()
+	c.Assert(err, jc.ErrorIsNil)
+	token, _, err := s.State.AddAPIToken(state.AddAPITokenArgs{
+		Owner:     s.Owner,
+		Model:     model.ModelTag(),
+		Access:    permission.ReadAccess,
+		CreatedBy: s.Owner,
+		Expires:   s.Clock.Now().Add(time.Hour),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	other, _ := s.addToken(c, s.Owner)
+
+	err = model.Destroy(state.DestroyModelParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = st.RemoveDyingModel()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.APIToken(token.ID)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	_, err = s.State.APIToken(other.ID)
+	c.Assert(err, jc.ErrorIsNil)
+}
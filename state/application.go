@@ -535,7 +535,11 @@ func (op *DestroyApplicationOperation) unassignBranchOps() ([]txn.Op, error) {
 		// assumption: branches from applicationBranches will
 		// ALWAYS have the appName in assigned-units, but not
 		// always in config.
-		ops = append(ops, b.unassignAppOps(appName)...)
+		unassignOps, err := b.unassignAppOps(appName)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		ops = append(ops, unassignOps...)
 	}
 	return ops, nil
 }
@@ -890,7 +894,9 @@ func (a *Application) checkStorageUpgrade(newMeta, oldMeta *charm.Meta, units []
 }
 
 // changeCharmOps returns the operations necessary to set a application's
-// charm URL to a new value.
+// charm URL to a new value. If branch is not nil, the upgrade is applied
+// by committing a branch, whose references to the new charm, settings
+// and storage constraints are handed over to the application.
 func (a *Application) changeCharmOps(
 	ch *Charm,
 	channel string,
@@ -898,11 +904,17 @@ func (a *Application) changeCharmOps(
 	forceUnits bool,
 	resourceIDs map[string]string,
 	updatedStorageConstraints map[string]StorageConstraints,
+	branch *branchCharmUpgrade,
 ) ([]txn.Op, error) {
 	// Build the new application config from what can be used of the old one.
 	var newSettings charm.Settings
 	oldKey, err := readSettings(a.st.db(), settingsC, a.charmConfigKey())
 	if err == nil {
+		if branch != nil {
+			// Only the version of the old settings is used below,
+			// to assert that they are unchanged.
+			oldKey.applyChanges(branch.configChanges)
+		}
 		// Filter the old settings through to get the new settings.
 		newSettings = ch.Config().FilterSettings(oldKey.Map())
 		for k, v := range updatedSettings {
@@ -961,9 +973,12 @@ func (a *Application) changeCharmOps(
 
 	// Add or create a reference to the new charm, settings,
 	// and storage constraints docs.
-	incOps, err := appCharmIncRefOps(a.st, a.doc.Name, ch.URL(), true)
-	if err != nil {
-		return nil, errors.Trace(err)
+	var incOps []txn.Op
+	if branch == nil {
+		incOps, err = appCharmIncRefOps(a.st, a.doc.Name, ch.URL(), true)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
 	}
 	var decOps []txn.Op
 	// Drop the references to the old settings, storage constraints,
@@ -1276,22 +1291,67 @@ func (a *Application) SetCharm(cfg SetCharmConfig) (err error) {
 	defer errors.DeferredAnnotatef(
 		&err, "cannot upgrade application %q to charm %q", a, cfg.Charm,
 	)
+	updatedSettings, err := a.validateSetCharm(cfg)
+	if err != nil {
+		return err
+	}
+
+	var newCharmModifiedVersion int
+	acopy := &Application{a.st, a.doc}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		a := acopy
+		if attempt > 0 {
+			if err := a.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+
+		// NOTE: We're explicitly allowing SetCharm to succeed
+		// when the application is Dying, because application/charm
+		// upgrades should still be allowed to apply to dying
+		// applications and units, so that bugs in departed/broken
+		// hooks can be addressed at runtime.
+		if a.Life() == Dead {
+			return nil, ErrDead
+		}
+
+		ops, version, err := a.setCharmOps(cfg, updatedSettings, nil)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		newCharmModifiedVersion = version
+		return ops, nil
+	}
+
+	if err := a.st.db().Run(buildTxn); err != nil {
+		return err
+	}
+	a.doc.CharmURL = cfg.Charm.URL()
+	a.doc.Channel = string(cfg.Channel)
+	a.doc.ForceCharm = cfg.ForceUnits
+	a.doc.CharmModifiedVersion = newCharmModifiedVersion
+	return nil
+}
+
+// validateSetCharm checks that the application can be upgraded as
+// described by cfg, returning the validated config settings to apply.
+func (a *Application) validateSetCharm(cfg SetCharmConfig) (charm.Settings, error) {
 	if cfg.Charm.Meta().Subordinate != a.doc.Subordinate {
-		return errors.Errorf("cannot change an application's subordinacy")
+		return nil, errors.Errorf("cannot change an application's subordinacy")
 	}
 	currentCharm, err := a.st.Charm(a.doc.CharmURL)
 	if err != nil {
-		return errors.Trace(err)
+		return nil, errors.Trace(err)
 	}
 	if cfg.Charm.Meta().Deployment != currentCharm.Meta().Deployment {
 		if currentCharm.Meta().Deployment == nil || currentCharm.Meta().Deployment == nil {
-			return errors.New("cannot change a charm's deployment info")
+			return nil, errors.New("cannot change a charm's deployment info")
 		}
 		if cfg.Charm.Meta().Deployment.DeploymentType != currentCharm.Meta().Deployment.DeploymentType {
-			return errors.New("cannot change a charm's deployment type")
+			return nil, errors.New("cannot change a charm's deployment type")
 		}
 		if cfg.Charm.Meta().Deployment.DeploymentMode != currentCharm.Meta().Deployment.DeploymentMode {
-			return errors.New("cannot change a charm's deployment mode")
+			return nil, errors.New("cannot change a charm's deployment mode")
 		}
 	}
 	// For old style charms written for only one series, we still retain
@@ -1299,7 +1359,7 @@ func (a *Application) SetCharm(cfg SetCharmConfig) (err error) {
 	// with series = "".
 	if cfg.Charm.URL().Series != "" {
 		if cfg.Charm.URL().Series != a.doc.Series {
-			return errors.Errorf("cannot change an application's series")
+			return nil, errors.Errorf("cannot change an application's series")
 		}
 	} else if !cfg.ForceSeries {
 		supported := false
@@ -1314,7 +1374,7 @@ func (a *Application) SetCharm(cfg SetCharmConfig) (err error) {
 			if len(cfg.Charm.Meta().Series) > 0 {
 				supportedSeries = strings.Join(cfg.Charm.Meta().Series, ", ")
 			}
-			return errors.Errorf("only these series are supported: %v", supportedSeries)
+			return nil, errors.Errorf("only these series are supported: %v", supportedSeries)
 		}
 	} else {
 		// Even with forceSeries=true, we do not allow a charm to be used which is for
@@ -1324,14 +1384,14 @@ func (a *Application) SetCharm(cfg SetCharmConfig) (err error) {
 		if err != nil {
 			// We don't expect an error here but there's not much we can
 			// do to recover.
-			return err
+			return nil, err
 		}
 		supportedOS := false
 		supportedSeries := cfg.Charm.Meta().Series
 		for _, chSeries := range supportedSeries {
 			charmSeriesOS, err := series.GetOSFromSeries(chSeries)
 			if err != nil {
-				return nil
+				return nil, errors.Trace(err)
 			}
 			if currentOS == charmSeriesOS {
 				supportedOS = true
@@ -1339,13 +1399,13 @@ func (a *Application) SetCharm(cfg SetCharmConfig) (err error) {
 			}
 		}
 		if !supportedOS && len(supportedSeries) > 0 {
-			return errors.Errorf("OS %q not supported by charm", currentOS)
+			return nil, errors.Errorf("OS %q not supported by charm", currentOS)
 		}
 	}
 
 	updatedSettings, err := cfg.Charm.Config().ValidateSettings(cfg.ConfigSettings)
 	if err != nil {
-		return errors.Annotate(err, "validating config settings")
+		return nil, errors.Annotate(err, "validating config settings")
 	}
 
 	// we don't need to check that this is a charm.LXDProfiler, as we can
@@ -1354,114 +1414,95 @@ func (a *Application) SetCharm(cfg SetCharmConfig) (err error) {
 		// Validate the config devices, to ensure we don't apply an invalid
 		// profile, if we know it's never going to work.
 		if err := profile.ValidateConfigDevices(); err != nil && !cfg.Force {
-			return errors.Annotate(err, "validating lxd profile")
+			return nil, errors.Annotate(err, "validating lxd profile")
 		}
 	}
+	return updatedSettings, nil
+}
 
-	var newCharmModifiedVersion int
+// setCharmOps returns the operations to upgrade the application as
+// described by cfg, along with the application's resulting charm
+// modified version. The branch is nil unless the upgrade is applied
+// by committing a branch.
+func (a *Application) setCharmOps(
+	cfg SetCharmConfig, updatedSettings charm.Settings, branch *branchCharmUpgrade,
+) ([]txn.Op, int, error) {
+	// Record the current value of charmModifiedVersion, so we can
+	// set the value on the method receiver's in-memory document
+	// structure. We increment the version only when we change the
+	// charm URL.
+	newCharmModifiedVersion := a.doc.CharmModifiedVersion
 	channel := string(cfg.Channel)
-	acopy := &Application{a.st, a.doc}
-	buildTxn := func(attempt int) ([]txn.Op, error) {
-		a := acopy
-		if attempt > 0 {
-			if err := a.Refresh(); err != nil {
-				return nil, errors.Trace(err)
-			}
-		}
 
-		// NOTE: We're explicitly allowing SetCharm to succeed
-		// when the application is Dying, because application/charm
-		// upgrades should still be allowed to apply to dying
-		// applications and units, so that bugs in departed/broken
-		// hooks can be addressed at runtime.
-		if a.Life() == Dead {
-			return nil, ErrDead
-		}
-
-		// Record the current value of charmModifiedVersion, so we can
-		// set the value on the method receiver's in-memory document
-		// structure. We increment the version only when we change the
-		// charm URL.
-		newCharmModifiedVersion = a.doc.CharmModifiedVersion
+	ops := []txn.Op{{
+		C:  applicationsC,
+		Id: a.doc.DocID,
+		Assert: append(notDeadDoc, bson.DocElem{
+			"charmmodifiedversion", a.doc.CharmModifiedVersion,
+		}),
+	}}
 
-		ops := []txn.Op{{
+	if a.doc.CharmURL.String() == cfg.Charm.URL().String() {
+		// Charm URL already set; just update the force flag and channel.
+		ops = append(ops, txn.Op{
 			C:  applicationsC,
 			Id: a.doc.DocID,
-			Assert: append(notDeadDoc, bson.DocElem{
-				"charmmodifiedversion", a.doc.CharmModifiedVersion,
-			}),
-		}}
-
-		if a.doc.CharmURL.String() == cfg.Charm.URL().String() {
-			// Charm URL already set; just update the force flag and channel.
-			ops = append(ops, txn.Op{
-				C:  applicationsC,
-				Id: a.doc.DocID,
-				Update: bson.D{{"$set", bson.D{
-					{"cs-channel", channel},
-					{"forcecharm", cfg.ForceUnits},
-				}}},
-			})
-		} else {
-			// Check if the new charm specifies a relation max limit
-			// that cannot be satisfied by the currently established
-			// relation count.
-			quotaErr := a.preUpgradeRelationLimitCheck(cfg.Charm)
-
-			// If the operator specified --force, we still allow
-			// the upgrade to continue with a warning.
-			if errors.IsQuotaLimitExceeded(quotaErr) && cfg.Force {
-				logger.Warningf("%v; allowing upgrade to proceed as the operator specified --force", quotaErr)
-			} else if quotaErr != nil {
-				return nil, errors.Trace(quotaErr)
-			}
-
-			chng, err := a.changeCharmOps(
-				cfg.Charm,
-				channel,
-				updatedSettings,
-				cfg.ForceUnits,
-				cfg.ResourceIDs,
-				cfg.StorageConstraints,
-			)
-			if err != nil {
-				return nil, errors.Trace(err)
-			}
-			ops = append(ops, chng...)
-			newCharmModifiedVersion++
-		}
-
-		// Always update bindings regardless of whether we upgrade to a
-		// new version or stay at the previous version.
-		currentMap, txnRevno, err := readEndpointBindings(a.st, a.globalKey())
-		if err != nil && !errors.IsNotFound(err) {
-			return ops, errors.Trace(err)
-		}
-		b, err := a.bindingsForOps(currentMap)
+			Update: bson.D{{"$set", bson.D{
+				{"cs-channel", channel},
+				{"forcecharm", cfg.ForceUnits},
+			}}},
+		})
+	} else {
+		// Check if the new charm specifies a relation max limit
+		// that cannot be satisfied by the currently established
+		// relation count.
+		quotaErr := a.preUpgradeRelationLimitCheck(cfg.Charm)
+
+		// If the operator specified --force, we still allow
+		// the upgrade to continue with a warning.
+		if errors.IsQuotaLimitExceeded(quotaErr) && cfg.Force {
+			logger.Warningf("%v; allowing upgrade to proceed as the operator specified --force", quotaErr)
+		} else if quotaErr != nil {
+			return nil, 0, errors.Trace(quotaErr)
+		}
+
+		chng, err := a.changeCharmOps(
+			cfg.Charm,
+			channel,
+			updatedSettings,
+			cfg.ForceUnits,
+			cfg.ResourceIDs,
+			cfg.StorageConstraints,
+			branch,
+		)
 		if err != nil {
-			return nil, errors.Trace(err)
-		}
-		endpointBindingsOps, err := b.updateOps(txnRevno, cfg.EndpointBindings, cfg.Charm.Meta(), cfg.Force)
-		if err == nil {
-			ops = append(ops, endpointBindingsOps...)
-		} else if !errors.IsNotFound(err) && err != jujutxn.ErrNoOperations {
-			// If endpoint bindings do not exist this most likely means the application
-			// itself no longer exists, which will be caught soon enough anyway.
-			// ErrNoOperations on the other hand means there's nothing to update.
-			return nil, errors.Trace(err)
+			return nil, 0, errors.Trace(err)
 		}
-
-		return ops, nil
+		ops = append(ops, chng...)
+		newCharmModifiedVersion++
 	}
 
-	if err := a.st.db().Run(buildTxn); err != nil {
-		return err
+	// Always update bindings regardless of whether we upgrade to a
+	// new version or stay at the previous version.
+	currentMap, txnRevno, err := readEndpointBindings(a.st, a.globalKey())
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, 0, errors.Trace(err)
 	}
-	a.doc.CharmURL = cfg.Charm.URL()
-	a.doc.Channel = channel
-	a.doc.ForceCharm = cfg.ForceUnits
-	a.doc.CharmModifiedVersion = newCharmModifiedVersion
-	return nil
+	b, err := a.bindingsForOps(currentMap)
+	if err != nil {
+		return nil, 0, errors.Trace(err)
+	}
+	endpointBindingsOps, err := b.updateOps(txnRevno, cfg.EndpointBindings, cfg.Charm.Meta(), cfg.Force)
+	if err == nil {
+		ops = append(ops, endpointBindingsOps...)
+	} else if !errors.IsNotFound(err) && err != jujutxn.ErrNoOperations {
+		// If endpoint bindings do not exist this most likely means the application
+		// itself no longer exists, which will be caught soon enough anyway.
+		// ErrNoOperations on the other hand means there's nothing to update.
+		return nil, 0, errors.Trace(err)
+	}
+
+	return ops, newCharmModifiedVersion, nil
 }
 
 // preUpgradeRelationLimitCheck ensures that the already established relation
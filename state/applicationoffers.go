@@ -53,6 +53,18 @@ type applicationOfferDoc struct {
 
 	// Endpoints are the charm endpoints supported by the application.
 	Endpoints map[string]string `bson:"endpoints"`
+
+	// MaxConnections is the maximum number of relations which may be
+	// made to the offer, or zero for no limit.
+	MaxConnections int `bson:"max-connections,omitempty"`
+
+	// AllowedConsumerModels are the UUIDs of models which may relate
+	// to the offer; if empty, any model may relate.
+	AllowedConsumerModels []string `bson:"allowed-consumer-models,omitempty"`
+
+	// SettingsChangesPerMinute limits the rate at which consumers may
+	// publish relation settings changes, or zero for no limit.
+	SettingsChangesPerMinute int `bson:"settings-changes-per-minute,omitempty"`
 }
 
 var _ crossmodel.ApplicationOffers = (*applicationOffers)(nil)
@@ -402,7 +414,7 @@ func (s *applicationOffers) validateOfferArgs(offer crossmodel.AddApplicationOff
 			return errors.NotValidf("offer reader %q", readUser)
 		}
 	}
-	return nil
+	return offer.Limits.Validate()
 }
 
 // AddOffer adds a new application offering to the directory.
@@ -531,7 +543,7 @@ func (s *applicationOffers) UpdateOffer(offerArgs crossmodel.AddApplicationOffer
 				C:      applicationOffersC,
 				Id:     doc.DocID,
 				Assert: txn.DocExists,
-				Update: bson.M{"$set": doc},
+				Update: updateApplicationOfferDoc(doc),
 			},
 		}
 		ops = append(ops, refOps...)
@@ -544,6 +556,27 @@ func (s *applicationOffers) UpdateOffer(offerArgs crossmodel.AddApplicationOffer
 	return s.makeApplicationOffer(doc)
 }
 
+// updateApplicationOfferDoc returns the update which replaces an offer
+// document's contents with doc. Limits not set in doc are removed, since
+// they are omitted from doc when empty.
+func updateApplicationOfferDoc(doc applicationOfferDoc) bson.D {
+	var unset bson.D
+	if doc.MaxConnections == 0 {
+		unset = append(unset, bson.DocElem{"max-connections", nil})
+	}
+	if len(doc.AllowedConsumerModels) == 0 {
+		unset = append(unset, bson.DocElem{"allowed-consumer-models", nil})
+	}
+	if doc.SettingsChangesPerMinute == 0 {
+		unset = append(unset, bson.DocElem{"settings-changes-per-minute", nil})
+	}
+	update := bson.D{{"$set", doc}}
+	if len(unset) > 0 {
+		update = append(update, bson.DocElem{"$unset", unset})
+	}
+	return update
+}
+
 func (s *applicationOffers) makeApplicationOfferDoc(mb modelBackend, uuid string, offer crossmodel.AddApplicationOfferArgs) applicationOfferDoc {
 	doc := applicationOfferDoc{
 		DocID:                  mb.docID(offer.OfferName),
@@ -552,6 +585,10 @@ func (s *applicationOffers) makeApplicationOfferDoc(mb modelBackend, uuid string
 		ApplicationName:        offer.ApplicationName,
 		ApplicationDescription: offer.ApplicationDescription,
 		Endpoints:              offer.Endpoints,
+
+		MaxConnections:           offer.Limits.MaxConnections,
+		AllowedConsumerModels:    offer.Limits.AllowedConsumerModels,
+		SettingsChangesPerMinute: offer.Limits.SettingsChangesPerMinute,
 	}
 	return doc
 }
@@ -736,6 +773,11 @@ func (s *applicationOffers) makeApplicationOffer(doc applicationOfferDoc) (*cros
 		OfferUUID:              doc.OfferUUID,
 		ApplicationName:        doc.ApplicationName,
 		ApplicationDescription: doc.ApplicationDescription,
+		Limits: crossmodel.OfferLimits{
+			MaxConnections:           doc.MaxConnections,
+			AllowedConsumerModels:    doc.AllowedConsumerModels,
+			SettingsChangesPerMinute: doc.SettingsChangesPerMinute,
+		},
 	}
 	app, err := s.st.Application(doc.ApplicationName)
 	if err != nil {
@@ -9,6 +9,7 @@ import (
 	"github.com/juju/names/v4"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/txn"
+	"github.com/juju/utils"
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/core/crossmodel"
@@ -119,6 +120,41 @@ func (s *applicationOffersSuite) TestAddApplicationOffer(c *gc.C) {
 	c.Assert(access, gc.Equals, permission.ReadAccess)
 }
 
+func (s *applicationOffersSuite) TestAddApplicationOfferWithLimits(c *gc.C) {
+	sd := state.NewApplicationOffers(s.State)
+	owner := s.Factory.MakeUser(c, nil)
+	limits := crossmodel.OfferLimits{
+		MaxConnections:           2,
+		AllowedConsumerModels:    []string{utils.MustNewUUID().String()},
+		SettingsChangesPerMinute: 30,
+	}
+	offer, err := sd.AddOffer(crossmodel.AddApplicationOfferArgs{
+		OfferName:       "hosted-mysql",
+		ApplicationName: "mysql",
+		Endpoints:       map[string]string{"db": "server"},
+		Owner:           owner.Name(),
+		Limits:          limits,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(offer.Limits, jc.DeepEquals, limits)
+
+	expectedOffer, err := sd.ApplicationOfferForUUID(offer.OfferUUID)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(expectedOffer.Limits, jc.DeepEquals, limits)
+}
+
+func (s *applicationOffersSuite) TestAddApplicationOfferInvalidLimits(c *gc.C) {
+	sd := state.NewApplicationOffers(s.State)
+	owner := s.Factory.MakeUser(c, nil)
+	_, err := sd.AddOffer(crossmodel.AddApplicationOfferArgs{
+		OfferName:       "hosted-mysql",
+		ApplicationName: "mysql",
+		Owner:           owner.Name(),
+		Limits:          crossmodel.OfferLimits{MaxConnections: -1},
+	})
+	c.Assert(err, gc.ErrorMatches, `cannot add application offer "hosted-mysql": negative max connections -1 not valid`)
+}
+
 func (s *applicationOffersSuite) TestAddApplicationOfferBadEndpoints(c *gc.C) {
 	eps := map[string]string{"db": "server", "db-admin": "admin"}
 	sd := state.NewApplicationOffers(s.State)
@@ -434,6 +470,33 @@ func (s *applicationOffersSuite) TestUpdateApplicationOffer(c *gc.C) {
 	assertOffersRef(c, s.State, "mysql", 1)
 }
 
+func (s *applicationOffersSuite) TestUpdateApplicationOfferRemovesLimits(c *gc.C) {
+	sd := state.NewApplicationOffers(s.State)
+	owner := s.Factory.MakeUser(c, nil)
+	_, err := sd.AddOffer(crossmodel.AddApplicationOfferArgs{
+		OfferName:       "hosted-mysql",
+		ApplicationName: "mysql",
+		Owner:           owner.Name(),
+		Limits: crossmodel.OfferLimits{
+			MaxConnections:           2,
+			SettingsChangesPerMinute: 30,
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	offer, err := sd.UpdateOffer(crossmodel.AddApplicationOfferArgs{
+		OfferName:       "hosted-mysql",
+		ApplicationName: "mysql",
+		Owner:           owner.Name(),
+		Limits:          crossmodel.OfferLimits{MaxConnections: 3},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(offer.Limits, jc.DeepEquals, crossmodel.OfferLimits{MaxConnections: 3})
+
+	offer, err = sd.ApplicationOffer("hosted-mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(offer.Limits, jc.DeepEquals, crossmodel.OfferLimits{MaxConnections: 3})
+}
+
 func (s *applicationOffersSuite) TestUpdateApplicationOfferDifferentApp(c *gc.C) {
 	sd := state.NewApplicationOffers(s.State)
 	owner := s.Factory.MakeUser(c, nil)
@@ -5,10 +5,14 @@ package state
 
 import (
 	"context"
+	"time"
 
 	"github.com/juju/errors"
+	jujutxn "github.com/juju/txn"
 	"golang.org/x/crypto/acme/autocert"
 	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
 
 	"github.com/juju/juju/mongo"
 )
@@ -72,3 +76,95 @@ func (cache autocertCache) coll() (mongo.WriteCollection, func()) {
 	coll, closer := cache.st.db().GetCollection(autocertCacheC)
 	return coll.Writeable(), closer
 }
+
+const autocertFailureKey = "autocertFailure"
+
+// autocertFailureDoc records the most recent failure to obtain a
+// certificate for the controller's DNS name. It is removed once a
+// certificate is obtained.
+type autocertFailureDoc struct {
+	Id      string    `bson:"_id"`
+	Message string    `bson:"message"`
+	Time    time.Time `bson:"time"`
+}
+
+// AutocertFailure describes the most recent failure to obtain a
+// certificate for the controller's DNS name.
+type AutocertFailure struct {
+	Message string
+	Time    time.Time
+}
+
+// AutocertFailure returns the most recent failure to obtain a
+// certificate for the controller's DNS name, or an error satisfying
+// errors.IsNotFound if the last attempt succeeded.
+func (st *State) AutocertFailure() (AutocertFailure, error) {
+	controllers, closer := st.db().GetCollection(controllersC)
+	defer closer()
+
+	var doc autocertFailureDoc
+	err := controllers.FindId(autocertFailureKey).One(&doc)
+	if err == mgo.ErrNotFound {
+		return AutocertFailure{}, errors.NotFoundf("autocert failure")
+	}
+	if err != nil {
+		return AutocertFailure{}, errors.Annotate(err, "cannot get autocert failure")
+	}
+	return AutocertFailure{
+		Message: doc.Message,
+		Time:    doc.Time,
+	}, nil
+}
+
+// SetAutocertFailure records a failure to obtain a certificate for the
+// controller's DNS name.
+func (st *State) SetAutocertFailure(message string) error {
+	now := st.nowToTheSecond()
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		_, err := st.AutocertFailure()
+		if errors.IsNotFound(err) {
+			return []txn.Op{{
+				C:      controllersC,
+				Id:     autocertFailureKey,
+				Assert: txn.DocMissing,
+				Insert: &autocertFailureDoc{
+					Id:      autocertFailureKey,
+					Message: message,
+					Time:    now,
+				},
+			}}, nil
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return []txn.Op{{
+			C:      controllersC,
+			Id:     autocertFailureKey,
+			Assert: txn.DocExists,
+			Update: bson.D{{"$set", bson.D{
+				{"message", message},
+				{"time", now},
+			}}},
+		}}, nil
+	}
+	return errors.Annotate(st.db().Run(buildTxn), "cannot set autocert failure")
+}
+
+// ClearAutocertFailure removes any record of a failure to obtain a
+// certificate for the controller's DNS name.
+func (st *State) ClearAutocertFailure() error {
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		_, err := st.AutocertFailure()
+		if errors.IsNotFound(err) {
+			return nil, jujutxn.ErrNoOperations
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return []txn.Op{{
+			C:      controllersC,
+			Id:     autocertFailureKey,
+			Assert: txn.DocExists,
+			Remove: true,
+		}}, nil
+	}
+	return errors.Annotate(st.db().Run(buildTxn), "cannot clear autocert failure")
+}
@@ -4,6 +4,7 @@
 package state_test
 
 import (
+	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	"golang.org/x/crypto/acme/autocert"
 	"golang.org/x/net/context"
@@ -92,3 +93,30 @@ func (s *autocertCacheSuite) TestPutExistingEntry(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(string(data), gc.Equals, "aval2")
 }
+
+func (s *autocertCacheSuite) TestAutocertFailure(c *gc.C) {
+	_, err := s.State.AutocertFailure()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	err = s.State.SetAutocertFailure("no DNS")
+	c.Assert(err, jc.ErrorIsNil)
+	failure, err := s.State.AutocertFailure()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(failure.Message, gc.Equals, "no DNS")
+	c.Assert(failure.Time.IsZero(), jc.IsFalse)
+
+	err = s.State.SetAutocertFailure("rate limited")
+	c.Assert(err, jc.ErrorIsNil)
+	failure, err = s.State.AutocertFailure()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(failure.Message, gc.Equals, "rate limited")
+
+	err = s.State.ClearAutocertFailure()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AutocertFailure()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	// Clearing again is a no-op.
+	err = s.State.ClearAutocertFailure()
+	c.Assert(err, jc.ErrorIsNil)
+}
@@ -114,7 +114,7 @@ func (st *State) CloudsForUser(user names.UserTag, all bool) ([]CloudInfo, error
 	isControllerSuperuser := false
 	if all {
 		var err error
-		isControllerSuperuser, err = st.isUserSuperuser(user)
+		isControllerSuperuser, err = st.isUserSuperuser(user, nil)
 		if err != nil {
 			return nil, errors.Trace(err)
 		}
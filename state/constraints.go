@@ -22,6 +22,7 @@ type constraintsDoc struct {
 	Arch           *string
 	CpuCores       *uint64
 	CpuPower       *uint64
+	Firmware       *string
 	Mem            *uint64
 	RootDisk       *uint64
 	RootDiskSource *string
@@ -39,6 +40,7 @@ func newConstraintsDoc(cons constraints.Value, id string) constraintsDoc {
 		Arch:           cons.Arch,
 		CpuCores:       cons.CpuCores,
 		CpuPower:       cons.CpuPower,
+		Firmware:       cons.Firmware,
 		Mem:            cons.Mem,
 		RootDisk:       cons.RootDisk,
 		RootDiskSource: cons.RootDiskSource,
@@ -57,6 +59,7 @@ func (doc constraintsDoc) value() constraints.Value {
 		Arch:           doc.Arch,
 		CpuCores:       doc.CpuCores,
 		CpuPower:       doc.CpuPower,
+		Firmware:       doc.Firmware,
 		Mem:            doc.Mem,
 		RootDisk:       doc.RootDisk,
 		RootDiskSource: doc.RootDiskSource,
@@ -103,6 +103,7 @@ func (st *State) UpdateControllerConfig(updateAttrs map[string]interface{}, remo
 	if err != nil {
 		return errors.Annotatef(err, "controller %q", st.ControllerUUID())
 	}
+	oldMaxAge := jujucontroller.Config(settings.Map()).PasswordMaxAge()
 	for _, r := range removeAttrs {
 		settings.Delete(r)
 	}
@@ -110,7 +111,7 @@ func (st *State) UpdateControllerConfig(updateAttrs map[string]interface{}, remo
 
 	// Ensure the resulting config is still valid.
 	newValues := settings.Map()
-	_, err = jujucontroller.NewConfig(
+	newConfig, err := jujucontroller.NewConfig(
 		newValues[jujucontroller.ControllerUUIDKey].(string),
 		newValues[jujucontroller.CACertKey].(string),
 		newValues,
@@ -118,9 +119,13 @@ func (st *State) UpdateControllerConfig(updateAttrs map[string]interface{}, remo
 	if err != nil {
 		return errors.Trace(err)
 	}
+	policyOps, err := st.passwordMaxAgeOps(oldMaxAge, newConfig.PasswordMaxAge())
+	if err != nil {
+		return errors.Trace(err)
+	}
 
 	_, ops := settings.settingsUpdateOps()
-	return errors.Trace(settings.write(ops))
+	return errors.Trace(settings.write(append(ops, policyOps...)))
 }
 
 func (st *State) checkValidControllerConfig(updateAttrs map[string]interface{}, removeAttrs []string) error {
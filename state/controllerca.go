@@ -0,0 +1,339 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"strings"
+	"time"
+
+	"github.com/juju/collections/set"
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+
+	jujucontroller "github.com/juju/juju/controller"
+	"github.com/juju/juju/pki"
+)
+
+const caRotationKey = "caRotation"
+
+// CARotationMinTrustPeriod is how long agents are given to trust the new
+// CA, from the start of a rotation, before the old CA can be retired.
+const CARotationMinTrustPeriod = 24 * time.Hour
+
+// CARotationPhase describes how far a rotation of the controller's CA
+// has progressed.
+type CARotationPhase string
+
+const (
+	// CARotationTrusting is the first phase of a CA rotation. Agents
+	// are told to trust both the old and new CAs, while certificates
+	// are still issued by the old CA.
+	CARotationTrusting CARotationPhase = "trusting"
+
+	// CARotationReissued is the second phase of a CA rotation, in which
+	// certificates are issued by the new CA. Controllers present the
+	// new CA cross-signed by the old one along with their certificates,
+	// so that agents that only trust the old CA can still connect.
+	CARotationReissued CARotationPhase = "reissued"
+)
+
+// caRotationDoc records a rotation of the controller's CA that is in
+// progress. There is at most one, and it is removed once the rotation
+// is finished or aborted.
+type caRotationDoc struct {
+	Id              string    `bson:"_id"`
+	Phase           string    `bson:"phase"`
+	OldCACert       string    `bson:"old-ca-cert"`
+	NewCACert       string    `bson:"new-ca-cert"`
+	NewCAKey        string    `bson:"new-ca-key,omitempty"`
+	CrossSignedCert string    `bson:"cross-signed-cert"`
+	Started         time.Time `bson:"started"`
+	Reissued        time.Time `bson:"reissued,omitempty"`
+
+	// ReissuedControllers holds the ids of the controllers that
+	// have issued their certificates from the new CA.
+	ReissuedControllers []string `bson:"reissued-controllers,omitempty"`
+}
+
+// CARotation describes a rotation of the controller's CA that is in
+// progress.
+type CARotation struct {
+	doc caRotationDoc
+}
+
+// Phase returns the phase the rotation has reached.
+func (r *CARotation) Phase() CARotationPhase {
+	return CARotationPhase(r.doc.Phase)
+}
+
+// OldCACert returns the PEM encoded certificate of the CA being
+// replaced.
+func (r *CARotation) OldCACert() string {
+	return r.doc.OldCACert
+}
+
+// NewCACert returns the PEM encoded certificate of the replacement CA.
+func (r *CARotation) NewCACert() string {
+	return r.doc.NewCACert
+}
+
+// CrossSignedCert returns the PEM encoded certificate for the new CA
+// issued by the old CA.
+func (r *CARotation) CrossSignedCert() string {
+	return r.doc.CrossSignedCert
+}
+
+// Started returns when the rotation was started.
+func (r *CARotation) Started() time.Time {
+	return r.doc.Started
+}
+
+// Reissued returns when certificates started to be issued by the new
+// CA, or the zero time if they are still issued by the old one.
+func (r *CARotation) Reissued() time.Time {
+	return r.doc.Reissued
+}
+
+// ReissuedControllers returns the ids of the controllers that have
+// issued their certificates from the new CA.
+func (r *CARotation) ReissuedControllers() []string {
+	return r.doc.ReissuedControllers
+}
+
+// CARotation returns the rotation of the controller's CA that is in
+// progress, or an error satisfying errors.IsNotFound if there is none.
+func (st *State) CARotation() (*CARotation, error) {
+	controllers, closer := st.db().GetCollection(controllersC)
+	defer closer()
+
+	var doc caRotationDoc
+	err := controllers.FindId(caRotationKey).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("CA rotation")
+	}
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot get CA rotation")
+	}
+	return &CARotation{doc: doc}, nil
+}
+
+// StartCARotation starts replacing the controller's CA with the one
+// given, along with its private key and its certificate cross-signed
+// by the current CA. The controller's CA certificate becomes a bundle
+// of both CAs, which agents are told to trust.
+func (st *State) StartCARotation(newCACert, newCAKey, crossSignedCert string) error {
+	cfg, err := st.ControllerConfig()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	oldCACert, _ := cfg.CACert()
+	certs, _, err := pki.UnmarshalPemData([]byte(oldCACert))
+	if err != nil {
+		return errors.Annotate(err, "cannot parse controller CA certificate")
+	}
+	if len(certs) != 1 {
+		return errors.Errorf("controller CA certificate is a bundle of %d certificates", len(certs))
+	}
+	doc := caRotationDoc{
+		Id:              caRotationKey,
+		Phase:           string(CARotationTrusting),
+		OldCACert:       oldCACert,
+		NewCACert:       newCACert,
+		NewCAKey:        newCAKey,
+		CrossSignedCert: crossSignedCert,
+		Started:         st.nowToTheSecond(),
+	}
+	ops, err := st.setCACertOps(oldCACert, newCACert)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	ops = append(ops, txn.Op{
+		C:      controllersC,
+		Id:     caRotationKey,
+		Assert: txn.DocMissing,
+		Insert: &doc,
+	})
+	if err := st.db().RunTransaction(ops); err == txn.ErrAborted {
+		return errors.AlreadyExistsf("CA rotation")
+	} else if err != nil {
+		return errors.Annotate(err, "cannot start CA rotation")
+	}
+	return nil
+}
+
+// ReissueCARotation moves the CA rotation in progress on to its second
+// phase, in which the new CA's private key is used to issue the
+// controllers' certificates.
+func (st *State) ReissueCARotation() error {
+	rotation, err := st.CARotation()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if rotation.Phase() != CARotationTrusting {
+		return errors.Errorf("CA rotation is already %s", rotation.Phase())
+	}
+	ops, err := st.setCACertOps(rotation.doc.NewCACert, rotation.doc.CrossSignedCert, rotation.doc.OldCACert)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	ops = append(ops, txn.Op{
+		C:      controllersC,
+		Id:     caRotationKey,
+		Assert: bson.D{{"phase", string(CARotationTrusting)}},
+		Update: bson.D{
+			{"$set", bson.D{
+				{"phase", string(CARotationReissued)},
+				{"reissued", st.nowToTheSecond()},
+			}},
+			{"$unset", bson.D{{"new-ca-key", ""}}},
+		},
+	}, txn.Op{
+		C:      controllersC,
+		Id:     stateServingInfoKey,
+		Assert: txn.DocExists,
+		Update: bson.D{{"$set", bson.D{{"caprivatekey", rotation.doc.NewCAKey}}}},
+	})
+	if err := st.db().RunTransaction(ops); err == txn.ErrAborted {
+		return errors.New("CA rotation changed concurrently")
+	} else if err != nil {
+		return errors.Annotate(err, "cannot reissue certificates from new CA")
+	}
+	return nil
+}
+
+// RecordControllerCAReissued records that the controller with the given
+// id has issued its certificates from the given CA. It does nothing
+// unless the CA is the new CA of a rotation whose certificates are
+// being reissued.
+func (st *State) RecordControllerCAReissued(controllerId, caCert string) error {
+	rotation, err := st.CARotation()
+	if errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	if rotation.Phase() != CARotationReissued {
+		return nil
+	}
+	same, err := sameCACert(caCert, rotation.doc.NewCACert)
+	if err != nil || !same {
+		return errors.Trace(err)
+	}
+	for _, id := range rotation.doc.ReissuedControllers {
+		if id == controllerId {
+			return nil
+		}
+	}
+	ops := []txn.Op{{
+		C:      controllersC,
+		Id:     caRotationKey,
+		Assert: bson.D{{"phase", string(CARotationReissued)}},
+		Update: bson.D{{"$addToSet", bson.D{{"reissued-controllers", controllerId}}}},
+	}}
+	if err := st.db().RunTransaction(ops); err == txn.ErrAborted {
+		return errors.New("CA rotation changed concurrently")
+	} else if err != nil {
+		return errors.Annotatef(err, "cannot record certificates of controller %q reissued", controllerId)
+	}
+	return nil
+}
+
+// FinishCARotation completes the CA rotation in progress by retiring
+// the old CA, so that only the new CA is trusted. The rotation cannot
+// be finished until agents have had CARotationMinTrustPeriod to trust
+// the new CA, and every controller has issued its certificates from it.
+func (st *State) FinishCARotation() error {
+	rotation, err := st.CARotation()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if rotation.Phase() != CARotationReissued {
+		return errors.Errorf("cannot finish CA rotation before certificates are reissued")
+	}
+	trusted := rotation.doc.Started.Add(CARotationMinTrustPeriod)
+	if st.clock().Now().Before(trusted) {
+		return errors.Errorf("cannot finish CA rotation before %s, to give agents time to trust the new CA",
+			trusted.UTC().Format(time.RFC3339))
+	}
+	controllerIds, err := st.ControllerIds()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	reissued := set.NewStrings(rotation.doc.ReissuedControllers...)
+	if pending := set.NewStrings(controllerIds...).Difference(reissued); !pending.IsEmpty() {
+		return errors.Errorf("cannot finish CA rotation before controllers %s reissue their certificates",
+			strings.Join(pending.SortedValues(), ", "))
+	}
+	return errors.Annotate(st.endCARotation(rotation, rotation.doc.NewCACert), "cannot finish CA rotation")
+}
+
+// AbortCARotation abandons the CA rotation in progress, so that only the
+// old CA is trusted again. Rotations cannot be aborted once certificates
+// have been reissued by the new CA.
+func (st *State) AbortCARotation() error {
+	rotation, err := st.CARotation()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if rotation.Phase() != CARotationTrusting {
+		return errors.Errorf("cannot abort CA rotation after certificates are reissued")
+	}
+	return errors.Annotate(st.endCARotation(rotation, rotation.doc.OldCACert), "cannot abort CA rotation")
+}
+
+func (st *State) endCARotation(rotation *CARotation, caCert string) error {
+	ops, err := st.setCACertOps(caCert)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	ops = append(ops, txn.Op{
+		C:      controllersC,
+		Id:     caRotationKey,
+		Assert: bson.D{{"phase", rotation.doc.Phase}},
+		Remove: true,
+	})
+	if err := st.db().RunTransaction(ops); err == txn.ErrAborted {
+		return errors.New("CA rotation changed concurrently")
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	return nil
+}
+
+// sameCACert reports whether the first of the PEM encoded certificates
+// in a is the same as the first in b.
+func sameCACert(a, b string) (bool, error) {
+	aCerts, _, err := pki.UnmarshalPemData([]byte(a))
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	bCerts, _, err := pki.UnmarshalPemData([]byte(b))
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	if len(aCerts) == 0 || len(bCerts) == 0 {
+		return false, nil
+	}
+	return aCerts[0].Equal(bCerts[0]), nil
+}
+
+// setCACertOps returns the operations needed to set the controller's CA
+// certificate to a bundle of the given certificates. The first
+// certificate is the one whose key issues new certificates.
+func (st *State) setCACertOps(certs ...string) ([]txn.Op, error) {
+	settings, err := readSettings(st.db(), controllersC, ControllerSettingsGlobalKey)
+	if err != nil {
+		return nil, errors.Annotatef(err, "controller %q", st.ControllerUUID())
+	}
+	var bundle strings.Builder
+	for _, cert := range certs {
+		bundle.WriteString(strings.TrimSpace(cert))
+		bundle.WriteString("\n")
+	}
+	settings.Set(jujucontroller.CACertKey, bundle.String())
+	_, ops := settings.settingsUpdateOps()
+	return ops, nil
+}
@@ -0,0 +1,159 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/pki"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing"
+)
+
+type ControllerCASuite struct {
+	ConnSuite
+	crossSignedCert string
+}
+
+var _ = gc.Suite(&ControllerCASuite{})
+
+func (s *ControllerCASuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	crossCert, err := pki.CrossSignCA(testing.OtherCACertX509, testing.CACertX509, testing.CAKeyRSA)
+	c.Assert(err, jc.ErrorIsNil)
+	s.crossSignedCert, err = pki.CertificateToPemString(nil, crossCert)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *ControllerCASuite) assertCACerts(c *gc.C, expected ...string) {
+	cfg, err := s.State.ControllerConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	caCert, _ := cfg.CACert()
+	certs, _, err := pki.UnmarshalPemData([]byte(caCert))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(certs, gc.HasLen, len(expected))
+	for i, pem := range expected {
+		expectedCerts, _, err := pki.UnmarshalPemData([]byte(pem))
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(certs[i].Equal(expectedCerts[0]), jc.IsTrue, gc.Commentf("certificate %d", i))
+	}
+}
+
+func (s *ControllerCASuite) start(c *gc.C) {
+	err := s.State.StartCARotation(testing.OtherCACert, testing.OtherCAKey, s.crossSignedCert)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *ControllerCASuite) TestNoRotation(c *gc.C) {
+	_, err := s.State.CARotation()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	err = s.State.ReissueCARotation()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *ControllerCASuite) TestStart(c *gc.C) {
+	s.start(c)
+	rotation, err := s.State.CARotation()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rotation.Phase(), gc.Equals, state.CARotationTrusting)
+	c.Assert(strings.TrimSpace(rotation.OldCACert()), gc.Equals, strings.TrimSpace(testing.CACert))
+	c.Assert(rotation.NewCACert(), gc.Equals, testing.OtherCACert)
+	c.Assert(rotation.Started().IsZero(), jc.IsFalse)
+	c.Assert(rotation.Reissued().IsZero(), jc.IsTrue)
+	s.assertCACerts(c, testing.CACert, testing.OtherCACert)
+
+	err = s.State.StartCARotation(testing.OtherCACert, testing.OtherCAKey, s.crossSignedCert)
+	c.Assert(err, jc.Satisfies, errors.IsAlreadyExists)
+}
+
+func (s *ControllerCASuite) TestReissueAndFinish(c *gc.C) {
+	s.start(c)
+	err := s.State.ReissueCARotation()
+	c.Assert(err, jc.ErrorIsNil)
+	rotation, err := s.State.CARotation()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rotation.Phase(), gc.Equals, state.CARotationReissued)
+	c.Assert(rotation.Reissued().IsZero(), jc.IsFalse)
+	s.assertCACerts(c, testing.OtherCACert, s.crossSignedCert, testing.CACert)
+
+	info, err := s.State.StateServingInfo()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info.CAPrivateKey, gc.Equals, testing.OtherCAKey)
+
+	err = s.State.AbortCARotation()
+	c.Assert(err, gc.ErrorMatches, "cannot abort CA rotation after certificates are reissued")
+
+	s.Clock.Advance(state.CARotationMinTrustPeriod)
+	err = s.State.FinishCARotation()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.CARotation()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	s.assertCACerts(c, testing.OtherCACert)
+}
+
+func (s *ControllerCASuite) TestFinishBeforeReissue(c *gc.C) {
+	s.start(c)
+	err := s.State.FinishCARotation()
+	c.Assert(err, gc.ErrorMatches, "cannot finish CA rotation before certificates are reissued")
+}
+
+func (s *ControllerCASuite) TestFinishBeforeTrustPeriod(c *gc.C) {
+	s.start(c)
+	err := s.State.ReissueCARotation()
+	c.Assert(err, jc.ErrorIsNil)
+	s.Clock.Advance(state.CARotationMinTrustPeriod - time.Second)
+	err = s.State.FinishCARotation()
+	c.Assert(err, gc.ErrorMatches, "cannot finish CA rotation before .*, to give agents time to trust the new CA")
+}
+
+func (s *ControllerCASuite) TestFinishBeforeControllersReissue(c *gc.C) {
+	m0, err := s.State.AddMachine("bionic", state.JobHostUnits, state.JobManageModel)
+	c.Assert(err, jc.ErrorIsNil)
+	m1, err := s.State.AddMachine("bionic", state.JobHostUnits, state.JobManageModel)
+	c.Assert(err, jc.ErrorIsNil)
+	s.start(c)
+
+	// Certificates issued by the old CA, or before reissuing,
+	// are not recorded.
+	err = s.State.RecordControllerCAReissued(m0.Id(), testing.OtherCACert)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.ReissueCARotation()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.RecordControllerCAReissued(m1.Id(), testing.CACert)
+	c.Assert(err, jc.ErrorIsNil)
+	rotation, err := s.State.CARotation()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rotation.ReissuedControllers(), gc.HasLen, 0)
+
+	s.Clock.Advance(state.CARotationMinTrustPeriod)
+	err = s.State.RecordControllerCAReissued(m0.Id(), testing.OtherCACert)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.RecordControllerCAReissued(m0.Id(), testing.OtherCACert)
+	c.Assert(err, jc.ErrorIsNil)
+	rotation, err = s.State.CARotation()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rotation.ReissuedControllers(), jc.DeepEquals, []string{m0.Id()})
+	err = s.State.FinishCARotation()
+	c.Assert(err, gc.ErrorMatches, "cannot finish CA rotation before controllers "+m1.Id()+" reissue their certificates")
+
+	err = s.State.RecordControllerCAReissued(m1.Id(), testing.OtherCACert)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.FinishCARotation()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertCACerts(c, testing.OtherCACert)
+}
+
+func (s *ControllerCASuite) TestAbort(c *gc.C) {
+	s.start(c)
+	err := s.State.AbortCARotation()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.CARotation()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	s.assertCACerts(c, testing.CACert)
+}
@@ -52,12 +52,14 @@ const (
 	GUISettingsC      = guisettingsC
 	GlobalSettingsC   = globalSettingsC
 	SettingsC         = settingsC
+	RolesC            = rolesC
 )
 
 var (
 	BinarystorageNew              = &binarystorageNew
 	ImageStorageNewStorage        = &imageStorageNewStorage
 	MachineIdLessThan             = machineIdLessThan
+	LockoutDuration               = lockoutDuration
 	GetOrCreatePorts              = getOrCreatePorts
 	GetPorts                      = getPorts
 	CombineMeterStatus            = combineMeterStatus
@@ -891,11 +893,11 @@ func ModelBackendFromStorageBackend(sb *StorageBackend) modelBackend {
 }
 
 func (st *State) IsUserSuperuser(user names.UserTag) (bool, error) {
-	return st.isUserSuperuser(user)
+	return st.isUserSuperuser(user, nil)
 }
 
 func (st *State) ModelQueryForUser(user names.UserTag, isSuperuser bool) (mongo.Query, SessionCloser, error) {
-	return st.modelQueryForUser(user, isSuperuser)
+	return st.modelQueryForUser(user, nil, isSuperuser)
 }
 
 func UnitsHaveChanged(m *Machine, unitNames []string) (bool, error) {
@@ -0,0 +1,197 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/core/permission"
+)
+
+var validGroupName = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._ -]*$`)
+
+// IsValidGroupName reports whether name may be used as the name of a
+// group that is granted access.
+func IsValidGroupName(name string) bool {
+	return validGroupName.MatchString(name)
+}
+
+// groupGlobalKey returns the global key of the named group. Group names
+// are case insensitive, as they are in most directories.
+func groupGlobalKey(name string) string {
+	return "gr#" + strings.ToLower(name)
+}
+
+// groupAccessObjectKey returns the global key of the target of a group
+// permission, after checking that the access level is valid for it.
+func (st *State) groupAccessObjectKey(target names.Tag, access permission.Access) (string, error) {
+	switch target.Kind() {
+	case names.ModelTagKind:
+		if access != "" {
+			if err := permission.ValidateModelAccess(access); err != nil {
+				return "", errors.Trace(err)
+			}
+		}
+		return modelKey(target.Id()), nil
+	case names.ControllerTagKind:
+		if access != "" {
+			if err := permission.ValidateControllerAccess(access); err != nil {
+				return "", errors.Trace(err)
+			}
+		}
+		return controllerKey(st.ControllerUUID()), nil
+	default:
+		return "", errors.NotValidf("%q as a target", target.Kind())
+	}
+}
+
+// CreateGroupAccess grants the members of the named group access to
+// the given model or controller.
+func (st *State) CreateGroupAccess(group string, target names.Tag, access permission.Access) error {
+	if !IsValidGroupName(group) {
+		return errors.NotValidf("group name %q", group)
+	}
+	if access == "" {
+		return errors.NotValidf("empty access")
+	}
+	objectKey, err := st.groupAccessObjectKey(target, access)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	op := createPermissionOp(objectKey, groupGlobalKey(group), access)
+	err = st.db().RunTransaction([]txn.Op{op})
+	if err == txn.ErrAborted {
+		err = errors.AlreadyExistsf("permission for group %q on %s", group, names.ReadableString(target))
+	}
+	return errors.Trace(err)
+}
+
+// GroupAccess returns the access granted to the members of the named
+// group on the given model or controller.
+func (st *State) GroupAccess(group string, target names.Tag) (permission.Access, error) {
+	objectKey, err := st.groupAccessObjectKey(target, "")
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	perm, err := st.userPermission(objectKey, groupGlobalKey(group))
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return perm.access(), nil
+}
+
+// GroupsAccess returns the access granted to groups on the given model
+// or controller, keyed by lower-cased group name.
+func (st *State) GroupsAccess(target names.Tag) (map[string]permission.Access, error) {
+	objectKey, err := st.groupAccessObjectKey(target, "")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	perms, err := st.usersPermissions(objectKey)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	result := make(map[string]permission.Access)
+	for _, p := range perms {
+		if strings.HasPrefix(p.doc.SubjectGlobalKey, "gr#") {
+			result[strings.TrimPrefix(p.doc.SubjectGlobalKey, "gr#")] = p.access()
+		}
+	}
+	return result, nil
+}
+
+// UpdateGroupAccess changes the access granted to the members of the
+// named group on the given model or controller.
+func (st *State) UpdateGroupAccess(group string, target names.Tag, access permission.Access) error {
+	if access == "" {
+		return errors.NotValidf("empty access")
+	}
+	objectKey, err := st.groupAccessObjectKey(target, access)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	buildTxn := func(int) ([]txn.Op, error) {
+		if _, err := st.GroupAccess(group, target); err != nil {
+			return nil, errors.Trace(err)
+		}
+		return []txn.Op{updatePermissionOp(objectKey, groupGlobalKey(group), access)}, nil
+	}
+	return errors.Trace(st.db().Run(buildTxn))
+}
+
+// RemoveGroupAccess removes the access granted to the members of the
+// named group on the given model or controller.
+func (st *State) RemoveGroupAccess(group string, target names.Tag) error {
+	objectKey, err := st.groupAccessObjectKey(target, "")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	buildTxn := func(int) ([]txn.Op, error) {
+		if _, err := st.GroupAccess(group, target); err != nil {
+			return nil, errors.Trace(err)
+		}
+		return []txn.Op{removePermissionOp(objectKey, groupGlobalKey(group))}, nil
+	}
+	return errors.Trace(st.db().Run(buildTxn))
+}
+
+// groupPermissions returns the permissions granted to any of the named
+// groups. If objectPrefix is not empty, only permissions on objects
+// whose global keys start with it are returned.
+func (st *State) groupPermissions(groups []string, objectPrefix string) ([]permissionDoc, error) {
+	if len(groups) == 0 {
+		return nil, nil
+	}
+	subjectKeys := make([]string, len(groups))
+	for i, group := range groups {
+		subjectKeys[i] = groupGlobalKey(group)
+	}
+	query := bson.D{{"subject-global-key", bson.D{{"$in", subjectKeys}}}}
+	if objectPrefix != "" {
+		query = append(query, bson.DocElem{"object-global-key", bson.D{{"$regex", "^" + objectPrefix}}})
+	}
+	// permissionsC is a global collection, so can be accessed from any state.
+	permissions, closer := st.db().GetCollection(permissionsC)
+	defer closer()
+	var docs []permissionDoc
+	if err := permissions.Find(query).All(&docs); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return docs, nil
+}
+
+// groupsAreSuperuser reports whether any of the named groups has been
+// granted superuser access on the controller.
+func (st *State) groupsAreSuperuser(groups []string) (bool, error) {
+	docs, err := st.groupPermissions(groups, controllerKey(st.ControllerUUID()))
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	for _, doc := range docs {
+		if permission.Access(doc.Access) == permission.SuperuserAccess {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// groupModelUUIDs returns the UUIDs of the models on which any of the
+// named groups has been granted access.
+func (st *State) groupModelUUIDs(groups []string) ([]string, error) {
+	docs, err := st.groupPermissions(groups, modelGlobalKey+"#")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	uuids := make([]string, len(docs))
+	for i, doc := range docs {
+		uuids[i] = strings.TrimPrefix(doc.ObjectGlobalKey, modelGlobalKey+"#")
+	}
+	return uuids, nil
+}
@@ -0,0 +1,95 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/permission"
+	"github.com/juju/juju/state"
+)
+
+type GroupAccessSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&GroupAccessSuite{})
+
+func (s *GroupAccessSuite) TestModelGroupAccess(c *gc.C) {
+	modelTag := s.Model.ModelTag()
+	_, err := s.State.GroupAccess("ops", modelTag)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	err = s.State.CreateGroupAccess("ops", modelTag, permission.WriteAccess)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.CreateGroupAccess("Ops", modelTag, permission.ReadAccess)
+	c.Assert(err, jc.Satisfies, errors.IsAlreadyExists)
+
+	access, err := s.State.GroupAccess("OPS", modelTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(access, gc.Equals, permission.WriteAccess)
+
+	err = s.State.UpdateGroupAccess("ops", modelTag, permission.AdminAccess)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.CreateGroupAccess("dev", modelTag, permission.ReadAccess)
+	c.Assert(err, jc.ErrorIsNil)
+
+	groups, err := s.State.GroupsAccess(modelTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(groups, jc.DeepEquals, map[string]permission.Access{
+		"ops": permission.AdminAccess,
+		"dev": permission.ReadAccess,
+	})
+
+	// Group permissions are not user permissions.
+	users, err := s.Model.Users()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(users, gc.HasLen, 1)
+
+	err = s.State.RemoveGroupAccess("ops", modelTag)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.RemoveGroupAccess("ops", modelTag)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *GroupAccessSuite) TestControllerGroupAccess(c *gc.C) {
+	controllerTag := names.NewControllerTag(s.State.ControllerUUID())
+	err := s.State.CreateGroupAccess("ops", controllerTag, permission.SuperuserAccess)
+	c.Assert(err, jc.ErrorIsNil)
+
+	access, err := s.State.GroupAccess("ops", controllerTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(access, gc.Equals, permission.SuperuserAccess)
+
+	groups, err := s.State.GroupsAccess(s.Model.ModelTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(groups, gc.HasLen, 0)
+}
+
+func (s *GroupAccessSuite) TestInvalidGroupAccess(c *gc.C) {
+	modelTag := s.Model.ModelTag()
+	err := s.State.CreateGroupAccess("ops", modelTag, permission.SuperuserAccess)
+	c.Assert(err, gc.ErrorMatches, `"superuser" model access not valid`)
+	err = s.State.CreateGroupAccess("#ops", modelTag, permission.ReadAccess)
+	c.Assert(err, gc.ErrorMatches, `group name "#ops" not valid`)
+	err = s.State.CreateGroupAccess("ops", names.NewCloudTag("dummy"), permission.AdminAccess)
+	c.Assert(err, gc.ErrorMatches, `"cloud" as a target not valid`)
+}
+
+func (s *GroupAccessSuite) TestIsValidGroupName(c *gc.C) {
+	for name, valid := range map[string]bool{
+		"ops":          true,
+		"Domain Users": true,
+		"team.dev-1_a": true,
+		"":             false,
+		" ops":         false,
+		"ops#1":        false,
+		"ops/dev":      false,
+	} {
+		c.Check(state.IsValidGroupName(name), gc.Equals, valid, gc.Commentf("%q", name))
+	}
+}
@@ -258,6 +258,9 @@ func Initialize(args InitializeParams) (_ *Controller, err error) {
 		createSettingsOp(controllersC, ControllerSettingsGlobalKey, args.ControllerConfig),
 		createSettingsOp(globalSettingsC, cloudGlobalKey(args.Cloud.Name), args.ControllerInheritedConfig),
 	)
+	if args.ControllerConfig.PasswordMaxAge() > 0 {
+		ops = append(ops, passwordPolicyInsertOp(dateCreated))
+	}
 	for k, v := range args.Cloud.RegionConfig {
 		// Create an entry keyed on cloudname#<key>, value for each region in
 		// region-config. The values here are themselves
@@ -1761,6 +1761,35 @@ func (m *Machine) CheckProvisioned(nonce string) bool {
 	return nonce == m.doc.Nonce && nonce != ""
 }
 
+// ResetManualNonce replaces the nonce of a manually provisioned machine,
+// so that the agent installed when re-enlisting the machine's reinstalled
+// host can log in. Agents using the previous nonce can no longer log in.
+func (m *Machine) ResetManualNonce(nonce string) error {
+	if !strings.HasPrefix(nonce, manualMachinePrefix) {
+		return errors.NotValidf("nonce %q for manually provisioned machine", nonce)
+	}
+	if !strings.HasPrefix(m.doc.Nonce, manualMachinePrefix) {
+		return errors.Errorf("machine %v was not manually provisioned", m)
+	}
+	ops := []txn.Op{{
+		C:      machinesC,
+		Id:     m.doc.DocID,
+		Assert: append(isAliveDoc, bson.DocElem{Name: "nonce", Value: m.doc.Nonce}),
+		Update: bson.D{{"$set", bson.D{{"nonce", nonce}}}},
+	}}
+	if err := m.st.db().RunTransaction(ops); err == nil {
+		m.doc.Nonce = nonce
+		return nil
+	} else if err != txn.ErrAborted {
+		return errors.Annotatef(err, "cannot reset nonce of machine %v", m)
+	} else if alive, err := isAlive(m.st, machinesC, m.doc.DocID); err != nil {
+		return errors.Trace(err)
+	} else if !alive {
+		return errors.Annotatef(machineNotAliveErr, "cannot reset nonce of machine %v", m)
+	}
+	return errors.Errorf("cannot reset nonce of machine %v: nonce changed concurrently", m)
+}
+
 // String returns a unique description of this machine.
 func (m *Machine) String() string {
 	return m.doc.Id
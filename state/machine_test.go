@@ -963,6 +963,37 @@ func (s *MachineSuite) TestMachineSetProvisionedWhenNotAlive(c *gc.C) {
 	})
 }
 
+func (s *MachineSuite) TestResetManualNonce(c *gc.C) {
+	err := s.machine.SetProvisioned("manual:10.0.0.1", "", "manual:10.0.0.1:old", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.machine.ResetManualNonce("manual:10.0.0.1:new")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.machine.CheckProvisioned("manual:10.0.0.1:new"), jc.IsTrue)
+
+	err = s.machine.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.machine.CheckProvisioned("manual:10.0.0.1:old"), jc.IsFalse)
+	c.Assert(s.machine.CheckProvisioned("manual:10.0.0.1:new"), jc.IsTrue)
+}
+
+func (s *MachineSuite) TestResetManualNonceNotManual(c *gc.C) {
+	err := s.machine.SetProvisioned("umbrella/0", "", "fake_nonce", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.ResetManualNonce("manual:10.0.0.1:new")
+	c.Assert(err, gc.ErrorMatches, `machine 1 was not manually provisioned`)
+	err = s.machine.ResetManualNonce("fake_nonce2")
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *MachineSuite) TestResetManualNonceWhenNotAlive(c *gc.C) {
+	err := s.machine.SetProvisioned("manual:10.0.0.1", "", "manual:10.0.0.1:old", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	testWhenDying(c, s.machine, notAliveErr, notAliveErr, func() error {
+		return s.machine.ResetManualNonce("manual:10.0.0.1:new")
+	})
+}
+
 func (s *MachineSuite) TestMachineSetInstanceStatus(c *gc.C) {
 	// Machine needs to be provisioned first.
 	err := s.machine.SetProvisioned("umbrella/0", "", "fake_nonce", nil)
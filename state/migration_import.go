@@ -15,6 +15,7 @@ import (
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"github.com/juju/names/v4"
+	"github.com/juju/utils"
 	"github.com/juju/version"
 	"gopkg.in/mgo.v2/bson"
 	"gopkg.in/mgo.v2/txn"
@@ -235,6 +236,80 @@ func (ctrl *Controller) Import(model description.Model) (_ *Model, _ *State, err
 	return dbModel, newSt, nil
 }
 
+// ImportDryRun checks that the model can be imported, by importing it
+// with Import as a throwaway model with a new UUID and name. The
+// throwaway model is removed again before returning, whether or not the
+// import succeeded, along with its cloud credential if that was added by
+// the import. The config of the model passed in is updated with the
+// throwaway UUID and name.
+func (ctrl *Controller) ImportDryRun(model description.Model) (err error) {
+	st := ctrl.pool.SystemState()
+	modelUUID, err := utils.NewUUID()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	model.UpdateConfig(map[string]interface{}{
+		"name": fmt.Sprintf("%s-dry-run-%s", model.Config()["name"], modelUUID.String()[:8]),
+		"uuid": modelUUID.String(),
+	})
+
+	var addedCredential *names.CloudCredentialTag
+	if creds := model.CloudCredential(); creds != nil {
+		credID := fmt.Sprintf("%s/%s/%s", creds.Cloud(), creds.Owner(), creds.Name())
+		if names.IsValidCloudCredential(credID) {
+			credTag := names.NewCloudCredentialTag(credID)
+			if _, err := st.CloudCredential(credTag); errors.IsNotFound(err) {
+				addedCredential = &credTag
+			} else if err != nil {
+				return errors.Trace(err)
+			}
+		}
+	}
+
+	defer func() {
+		removeErr := ctrl.removeDryRunModel(modelUUID.String(), addedCredential)
+		if removeErr != nil && err == nil {
+			err = errors.Annotate(removeErr, "removing dry run model")
+		}
+	}()
+	_, newSt, err := ctrl.Import(model)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	newSt.Close()
+	return nil
+}
+
+// removeDryRunModel removes the documents of a model imported by
+// ImportDryRun. The cloud credential added by the import, if any, is
+// removed in the same transaction as the model. A failed import may not
+// have created the model.
+func (ctrl *Controller) removeDryRunModel(modelUUID string, addedCredential *names.CloudCredentialTag) error {
+	var credentialOps []txn.Op
+	if addedCredential != nil {
+		credentialOps = removeCloudCredentialOps(*addedCredential)
+	}
+	st, err := ctrl.pool.Get(modelUUID)
+	if errors.IsNotFound(err) {
+		if len(credentialOps) == 0 {
+			return nil
+		}
+		err := ctrl.pool.SystemState().db().RunTransaction(credentialOps)
+		if err == txn.ErrAborted {
+			// The import failed before adding the credential.
+			return nil
+		}
+		return errors.Trace(err)
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	defer st.Release()
+	if err := st.removeAllModelDocs(bson.D{{"migration-mode", MigrationModeImporting}}, credentialOps...); err != nil {
+		return errors.Trace(err)
+	}
+	return nil
+}
+
 // ImportStateMigration defines a migration for importing various entities from
 // a source description model to the destination state.
 // It accumulates a series of migrations to Run at a later time.
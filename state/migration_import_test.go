@@ -244,6 +244,20 @@ func (s *MigrationImportSuite) AssertUserEqual(c *gc.C, newUser, oldUser permiss
 	}
 }
 
+func (s *MigrationImportSuite) TestImportDryRun(c *gc.C) {
+	out, err := s.State.Export()
+	c.Assert(err, jc.ErrorIsNil)
+	in := newModel(out, utils.MustNewUUID().String(), "new")
+
+	err = s.Controller.ImportDryRun(in)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Only the original model remains.
+	uuids, err := s.State.AllModelUUIDs()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(uuids, jc.DeepEquals, []string{s.State.ModelUUID()})
+}
+
 func (s *MigrationImportSuite) TestModelUsers(c *gc.C) {
 	// To be sure with this test, we create three env users, and remove
 	// the owner.
@@ -113,6 +113,10 @@ func (s *MigrationSuite) TestKnownCollections(c *gc.C) {
 		guimetadataC,
 		// This is controller global, not migrated.
 		guisettingsC,
+		// Custom roles are defined per controller, and aren't migrated.
+		rolesC,
+		// API tokens are controller global, and aren't migrated.
+		apiTokensC,
 		// Users aren't migrated.
 		usersC,
 		userLastLoginC,
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/juju/charm/v7"
@@ -18,6 +19,7 @@ import (
 	"gopkg.in/mgo.v2/bson"
 	"gopkg.in/mgo.v2/txn"
 
+	"github.com/juju/juju/core/constraints"
 	"github.com/juju/juju/core/settings"
 	"github.com/juju/juju/mongo/utils"
 )
@@ -70,7 +72,22 @@ type generationDoc struct {
 	// Config is all changes made to charm configuration under this branch.
 	Config map[string][]itemChange `bson:"charm-config"`
 
-	// TODO (manadart 2019-04-02): CharmURLs, Resources.
+	// CharmURLs holds the charm that each application is upgraded to
+	// under this branch, keyed by application name.
+	CharmURLs map[string]string `bson:"charm-urls,omitempty"`
+
+	// Resources holds the IDs of pending resources to activate with the
+	// charm upgrades made under this branch, keyed by application name
+	// and then by resource name.
+	Resources map[string]map[string]string `bson:"resources,omitempty"`
+
+	// Constraints holds the constraints set for applications under
+	// this branch, keyed by application name.
+	Constraints map[string]constraintsDoc `bson:"constraints,omitempty"`
+
+	// Promotion, if set, is the policy by which this branch is
+	// automatically committed or aborted.
+	Promotion *promotionPolicyDoc `bson:"promotion,omitempty"`
 
 	// Created is a Unix timestamp indicating when this generation was created.
 	Created int64 `bson:"created"`
@@ -86,6 +103,37 @@ type generationDoc struct {
 	CompletedBy string `bson:"completed-by"`
 }
 
+// promotionPolicyDoc is the state representation of a PromotionPolicy.
+type promotionPolicyDoc struct {
+	SoakTime int64  `bson:"soak-time"`
+	Started  int64  `bson:"started"`
+	SetBy    string `bson:"set-by"`
+}
+
+// PromotionPolicy describes how a branch is automatically promoted.
+// The branch is committed once all of the units tracking it have been
+// healthy for the soak time. If they are not healthy once the soak time
+// has elapsed from when the policy was set, the branch is aborted, with
+// the tracking units reverted to the master generation.
+type PromotionPolicy struct {
+	// SoakTime is how long the units tracking the branch
+	// must be healthy before it is committed.
+	SoakTime time.Duration
+
+	// Started is when the policy was set.
+	Started time.Time
+
+	// SetBy is the user who set the policy.
+	// The branch is committed or aborted on behalf of this user.
+	SetBy string
+}
+
+// Deadline returns the time by which the units tracking the branch must
+// be healthy, after which the branch is aborted if they are not.
+func (p PromotionPolicy) Deadline() time.Time {
+	return p.Started.Add(p.SoakTime)
+}
+
 // Generation represents the state of a model generation.
 type Generation struct {
 	st  *State
@@ -128,6 +176,55 @@ func (g *Generation) Config() map[string]settings.ItemChanges {
 	return changes
 }
 
+// CharmURLs returns the charm URLs that applications are upgraded to
+// under this branch, keyed by application name.
+func (g *Generation) CharmURLs() map[string]string {
+	return g.doc.CharmURLs
+}
+
+// CharmURL returns the charm URL that the input application is upgraded
+// to under this branch. A NotFound error is returned if the branch does
+// not upgrade the application's charm.
+func (g *Generation) CharmURL(appName string) (*charm.URL, error) {
+	url, ok := g.doc.CharmURLs[appName]
+	if !ok {
+		return nil, errors.NotFoundf("charm upgrade for application %q in branch %q", appName, g.BranchName())
+	}
+	curl, err := charm.ParseURL(url)
+	return curl, errors.Annotatef(err, "charm upgrade for application %q", appName)
+}
+
+// Resources returns the IDs of the pending resources activated by the
+// charm upgrades under this branch, keyed by application name and then
+// by resource name.
+func (g *Generation) Resources() map[string]map[string]string {
+	return g.doc.Resources
+}
+
+// Constraints returns the constraints set for applications under this
+// branch, keyed by application name.
+func (g *Generation) Constraints() map[string]constraints.Value {
+	cons := make(map[string]constraints.Value, len(g.doc.Constraints))
+	for appName, doc := range g.doc.Constraints {
+		cons[appName] = doc.value()
+	}
+	return cons
+}
+
+// PromotionPolicy returns the policy by which the branch is automatically
+// promoted. The boolean return is false if the branch has no such policy.
+func (g *Generation) PromotionPolicy() (PromotionPolicy, bool) {
+	p := g.doc.Promotion
+	if p == nil {
+		return PromotionPolicy{}, false
+	}
+	return PromotionPolicy{
+		SoakTime: time.Duration(p.SoakTime) * time.Second,
+		Started:  time.Unix(p.Started, 0),
+		SetBy:    p.SetBy,
+	}, true
+}
+
 // Created returns the Unix timestamp at generation creation.
 func (g *Generation) Created() int64 {
 	return g.doc.Created
@@ -371,8 +468,219 @@ func (g *Generation) UpdateCharmConfig(appName string, master *Settings, validCh
 	return errors.Trace(g.st.db().Run(buildTxn))
 }
 
+// UpdateCharmURL sets the charm that the input application is upgraded to
+// under this branch, along with the IDs of pending resources to activate
+// with it. The application's settings and storage constraints for the new
+// charm are created from the current ones, so that units tracking the
+// branch can run the new charm before the branch is committed.
+func (g *Generation) UpdateCharmURL(appName string, curl *charm.URL, resourceIDs map[string]string) error {
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := g.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		if err := g.CheckNotComplete(); err != nil {
+			return nil, errors.Trace(err)
+		}
+
+		app, err := g.st.Application(appName)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if current, _ := app.CharmURL(); current.String() == curl.String() {
+			return nil, errors.Errorf("application %q already uses charm %q", appName, curl)
+		}
+
+		ops := []txn.Op{{
+			C:  generationsC,
+			Id: g.doc.DocId,
+			Assert: bson.D{{"$and", []bson.D{
+				{{"completed", 0}},
+				{{"txn-revno", g.doc.TxnRevno}},
+			}}},
+			Update: bson.D{
+				{"$set", bson.D{
+					{"charm-urls." + appName, curl.String()},
+					{"resources." + appName, resourceIDs},
+				}},
+			},
+		}}
+
+		previous, err := g.CharmURL(appName)
+		if err != nil && !errors.IsNotFound(err) {
+			return nil, errors.Trace(err)
+		}
+		if err == nil && previous.String() == curl.String() {
+			// Only the resources are changing.
+			return ops, nil
+		}
+		refOps, err := g.charmRefOps(app, curl)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		ops = append(ops, refOps...)
+		releaseOps, err := g.charmReleaseOps(appName)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return append(ops, releaseOps...), nil
+	}
+
+	return errors.Trace(g.st.db().Run(buildTxn))
+}
+
+// charmRefOps returns the operations to add a reference from this branch
+// to the input charm for the input application. The application's settings
+// and storage constraints for the charm are created from the current ones
+// if they do not yet exist.
+func (g *Generation) charmRefOps(app *Application, curl *charm.URL) ([]txn.Op, error) {
+	ch, err := g.st.Charm(curl)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	var ops []txn.Op
+	settingsKey := applicationCharmConfigKey(app.Name(), curl)
+	if _, err := readSettings(g.st.db(), settingsC, settingsKey); errors.IsNotFound(err) {
+		current, err := readSettings(g.st.db(), settingsC, app.charmConfigKey())
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		ops = append(ops, createSettingsOp(settingsC, settingsKey, ch.Config().FilterSettings(current.Map())))
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	storageKey := applicationStorageConstraintsKey(app.Name(), curl)
+	if _, err := readStorageConstraints(g.st, storageKey); errors.IsNotFound(err) {
+		current, err := readStorageConstraints(g.st, app.storageConstraintsKey())
+		if err != nil && !errors.IsNotFound(err) {
+			return nil, errors.Trace(err)
+		}
+		// Only keep the constraints for storage that the new charm uses.
+		cons := make(map[string]StorageConstraints)
+		for name, c := range current {
+			if _, ok := ch.Meta().Storage[name]; ok {
+				cons[name] = c
+			}
+		}
+		ops = append(ops, createStorageConstraintsOp(storageKey, cons))
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	incOps, err := appCharmIncRefOps(g.st, app.Name(), curl, true)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return append(ops, incOps...), nil
+}
+
+// charmReleaseOps returns the operations to drop this branch's reference
+// to the charm that the input application is upgraded to, if any.
+func (g *Generation) charmReleaseOps(appName string) ([]txn.Op, error) {
+	curl, err := g.CharmURL(appName)
+	if errors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	ops, err := appCharmDecRefOps(g.st, appName, curl, true, &ForcedOperation{})
+	return ops, errors.Trace(err)
+}
+
+// UpdateConstraints sets the constraints for the input application under
+// this branch. They are applied to the application when the branch is
+// committed.
+func (g *Generation) UpdateConstraints(appName string, cons constraints.Value) error {
+	app, err := g.st.Application(appName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !app.IsPrincipal() {
+		return ErrSubordinateConstraints
+	}
+	unsupported, err := g.st.validateConstraints(cons)
+	if len(unsupported) > 0 {
+		logger.Warningf(
+			"setting constraints on application %q: unsupported constraints: %v", appName, strings.Join(unsupported, ","))
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := g.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		if err := g.CheckNotComplete(); err != nil {
+			return nil, errors.Trace(err)
+		}
+		return []txn.Op{{
+			C:  generationsC,
+			Id: g.doc.DocId,
+			Assert: bson.D{{"$and", []bson.D{
+				{{"completed", 0}},
+				{{"txn-revno", g.doc.TxnRevno}},
+			}}},
+			Update: bson.D{
+				{"$set", bson.D{{"constraints." + appName, newConstraintsDoc(cons, "")}}},
+			},
+		}}, nil
+	}
+
+	return errors.Trace(g.st.db().Run(buildTxn))
+}
+
+// SetPromotionPolicy sets the policy by which the branch is automatically
+// promoted, once units tracking it have been healthy for the input soak
+// time. A zero soak time removes any policy, leaving promotion of the
+// branch to the operator.
+func (g *Generation) SetPromotionPolicy(soakTime time.Duration, userName string) error {
+	if soakTime < 0 {
+		return errors.NotValidf("negative soak time %v", soakTime)
+	}
+	if soakTime > 0 && soakTime < time.Second {
+		return errors.NotValidf("soak time %v less than a second", soakTime)
+	}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := g.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		if err := g.CheckNotComplete(); err != nil {
+			return nil, errors.Trace(err)
+		}
+
+		update := bson.D{{"$unset", bson.D{{"promotion", nil}}}}
+		if soakTime > 0 {
+			now, err := g.st.ControllerTimestamp()
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			update = bson.D{{"$set", bson.D{{"promotion", promotionPolicyDoc{
+				SoakTime: int64(soakTime / time.Second),
+				Started:  now.Unix(),
+				SetBy:    userName,
+			}}}}}
+		}
+		return []txn.Op{{
+			C:      generationsC,
+			Id:     g.doc.DocId,
+			Assert: bson.D{{"txn-revno", g.doc.TxnRevno}},
+			Update: update,
+		}}, nil
+	}
+	return errors.Trace(g.st.db().Run(buildTxn))
+}
+
 // Commit marks the generation as completed and assigns it the next value from
-// the generation sequence. The new generation ID is returned.
+// the generation sequence. The new generation ID is returned. The branch's
+// config changes, charm upgrades and constraints are applied to the
+// applications in the same transaction.
 func (g *Generation) Commit(userName string) (int, error) {
 	var newGenId int
 
@@ -398,10 +706,15 @@ func (g *Generation) Commit(userName string) (int, error) {
 		if err != nil {
 			return nil, errors.Trace(err)
 		}
-		ops, err := g.commitConfigTxnOps()
+		ops, upgraded, err := g.commitApplicationOps()
 		if err != nil {
 			return nil, errors.Trace(err)
 		}
+		configOps, err := g.commitConfigTxnOps(upgraded)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		ops = append(ops, configOps...)
 
 		// Get the new sequence as late as we can.
 		// If assigned is empty, indicating no changes under this branch,
@@ -455,14 +768,106 @@ func (g *Generation) assignedWithAllUnits() (map[string][]string, error) {
 	return assigned, nil
 }
 
+// branchCharmUpgrade describes a charm upgrade applied to an application
+// by committing a branch, which already holds references to the new charm.
+type branchCharmUpgrade struct {
+	// configChanges are the branch's changes to the application's charm
+	// config, which are carried over to the new charm.
+	configChanges settings.ItemChanges
+}
+
+// commitApplicationOps returns the operations to upgrade the charms and
+// set the constraints of applications changed under this branch, so that
+// they are applied in the commit transaction. The branch's reference to
+// each charm is handed over to the application upgraded to it, or
+// released if the application already uses the charm. The names of the
+// upgraded applications, whose config changes are applied along with
+// their new charm, are also returned.
+func (g *Generation) commitApplicationOps() ([]txn.Op, set.Strings, error) {
+	var ops []txn.Op
+	upgraded := set.NewStrings()
+	for appName := range g.doc.CharmURLs {
+		curl, err := g.CharmURL(appName)
+		if err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+		app, err := g.st.Application(appName)
+		if err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+		if current, _ := app.CharmURL(); current.String() == curl.String() {
+			releaseOps, err := g.charmReleaseOps(appName)
+			if err != nil {
+				return nil, nil, errors.Trace(err)
+			}
+			ops = append(ops, releaseOps...)
+			continue
+		}
+		if app.Life() == Dead {
+			return nil, nil, errors.Annotatef(ErrDead, "upgrading charm for application %q", appName)
+		}
+		ch, err := g.st.Charm(curl)
+		if err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+		cfg := SetCharmConfig{
+			Charm:       ch,
+			Channel:     app.Channel(),
+			ResourceIDs: g.doc.Resources[appName],
+		}
+		updatedSettings, err := app.validateSetCharm(cfg)
+		if err != nil {
+			return nil, nil, errors.Annotatef(err, "upgrading charm for application %q", appName)
+		}
+		charmOps, _, err := app.setCharmOps(cfg, updatedSettings, &branchCharmUpgrade{
+			configChanges: g.Config()[appName],
+		})
+		if err != nil {
+			return nil, nil, errors.Annotatef(err, "upgrading charm for application %q", appName)
+		}
+		ops = append(ops, charmOps...)
+		upgraded.Add(appName)
+	}
+	for appName, cons := range g.Constraints() {
+		app, err := g.st.Application(appName)
+		if err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+		if app.Life() != Alive {
+			return nil, nil, errors.Annotatef(applicationNotAliveErr, "setting constraints for application %q", appName)
+		}
+		ops = append(ops, txn.Op{
+			C:      applicationsC,
+			Id:     app.doc.DocID,
+			Assert: isAliveDoc,
+		}, setConstraintsOp(app.globalKey(), cons))
+	}
+	return ops, upgraded, nil
+}
+
+// releaseAllCharmsOps returns the operations to drop this branch's
+// references to the charms that applications are upgraded to.
+func (g *Generation) releaseAllCharmsOps() ([]txn.Op, error) {
+	var ops []txn.Op
+	for appName := range g.doc.CharmURLs {
+		releaseOps, err := g.charmReleaseOps(appName)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		ops = append(ops, releaseOps...)
+	}
+	return ops, nil
+}
+
 // commitConfigTxnOps iterates over all the applications with configuration
 // deltas, determines their effective new settings, then gathers the
 // operations representing the changes so that they can all be applied in a
-// single transaction.
-func (g *Generation) commitConfigTxnOps() ([]txn.Op, error) {
+// single transaction. Applications upgraded to a new charm by the commit
+// are skipped; their deltas are applied along with the upgrade.
+func (g *Generation) commitConfigTxnOps(upgraded set.Strings) ([]txn.Op, error) {
 	var ops []txn.Op
 	for appName, delta := range g.Config() {
-		if len(delta) == 0 {
+		if len(delta) == 0 || upgraded.Contains(appName) {
 			continue
 		}
 		app, err := g.st.Application(appName)
@@ -513,10 +918,13 @@ func (g *Generation) Abort(userName string) error {
 			}
 		}
 
-		// Must not have upgraded charm of tracked application.
-		// TODO (hml) 2019-06-26
-		// Implement cannot abort branch where tracked application has
-		// been upgraded.
+		// With no units tracking the branch, no unit can be running a
+		// charm that the branch upgrades to, so the references to those
+		// charms can be dropped.
+		ops, err := g.releaseAllCharmsOps()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
 
 		now, err := g.st.ControllerTimestamp()
 		if err != nil {
@@ -525,7 +933,7 @@ func (g *Generation) Abort(userName string) error {
 		// As a proxy for checking that the generation has not changed,
 		// Assert that the txn rev-no has not changed since we materialised
 		// this generation object.
-		ops := []txn.Op{{
+		ops = append(ops, txn.Op{
 			C:      generationsC,
 			Id:     g.doc.DocId,
 			Assert: bson.D{{"txn-revno", g.doc.TxnRevno}},
@@ -535,13 +943,46 @@ func (g *Generation) Abort(userName string) error {
 					{"completed-by", userName},
 				}},
 			},
-		}}
+		})
 		return ops, nil
 	}
 
 	return errors.Trace(g.st.db().Run(buildTxn))
 }
 
+// UnassignAllUnits removes all units from the branch, returning them to the
+// master generation's charm and configuration. The applications remain in
+// the branch, so that the branch can then be aborted.
+func (g *Generation) UnassignAllUnits() error {
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := g.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		if err := g.CheckNotComplete(); err != nil {
+			return nil, errors.Trace(err)
+		}
+
+		assigned := make(map[string][]string, len(g.doc.AssignedUnits))
+		var tracked bool
+		for appName, units := range g.doc.AssignedUnits {
+			tracked = tracked || len(units) > 0
+			assigned[appName] = []string{}
+		}
+		if !tracked {
+			return nil, jujutxn.ErrNoOperations
+		}
+		return []txn.Op{{
+			C:      generationsC,
+			Id:     g.doc.DocId,
+			Assert: bson.D{{"txn-revno", g.doc.TxnRevno}},
+			Update: bson.D{{"$set", bson.D{{"assigned-units", assigned}}}},
+		}}, nil
+	}
+	return errors.Trace(g.st.db().Run(buildTxn))
+}
+
 // CheckNotComplete returns an error if this
 // generation was committed or aborted.
 func (g *Generation) CheckNotComplete() error {
@@ -597,16 +1038,22 @@ func (g *Generation) unassignUnitOps(unitName, appName string) []txn.Op {
 	}}
 }
 
-// HasChangesFor returns true when the generation has config changes for
-// the provided application.
+// HasChangesFor returns true when the generation has config, charm or
+// constraints changes for the provided application.
 func (g *Generation) HasChangesFor(appName string) bool {
-	_, ok := g.doc.Config[appName]
+	if _, ok := g.doc.Config[appName]; ok {
+		return true
+	}
+	if _, ok := g.doc.CharmURLs[appName]; ok {
+		return true
+	}
+	_, ok := g.doc.Constraints[appName]
 	return ok
 }
 
-// unassignAppOps returns operations to remove the tracking and config data
-// for the application from the generation.
-func (g *Generation) unassignAppOps(appName string) []txn.Op {
+// unassignAppOps returns operations to remove the tracking, config, charm
+// and constraints data for the application from the generation.
+func (g *Generation) unassignAppOps(appName string) ([]txn.Op, error) {
 	assigned := g.doc.AssignedUnits
 	delete(assigned, appName)
 	ops := []txn.Op{{
@@ -635,7 +1082,26 @@ func (g *Generation) unassignAppOps(appName string) []txn.Op {
 			},
 		})
 	}
-	return ops
+	if g.HasChangesFor(appName) {
+		releaseOps, err := g.charmReleaseOps(appName)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		ops = append(ops, releaseOps...)
+		ops = append(ops, txn.Op{
+			C:      generationsC,
+			Id:     g.doc.DocId,
+			Assert: bson.D{{"txn-revno", g.doc.TxnRevno}},
+			Update: bson.D{
+				{"$unset", bson.D{
+					{"charm-urls." + appName, nil},
+					{"resources." + appName, nil},
+					{"constraints." + appName, nil},
+				}},
+			},
+		})
+	}
+	return ops, nil
 }
 
 // AddBranch creates a new branch in the current model.
@@ -704,6 +1170,31 @@ func (m *Model) Branches() ([]*Generation, error) {
 	return b, errors.Trace(err)
 }
 
+// BranchCharmURL returns the charm URL that the unit with the input name
+// should run because it is tracking an in-flight branch that upgrades the
+// charm of its application. A NotFound error is returned if it is not.
+func (st *State) BranchCharmURL(unitName string) (*charm.URL, error) {
+	appName, err := names.UnitApplication(unitName)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	branches, err := st.Branches()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for _, b := range branches {
+		if !b.IsTracking(unitName) {
+			continue
+		}
+		curl, err := b.CharmURL(appName)
+		if errors.IsNotFound(err) {
+			continue
+		}
+		return curl, errors.Trace(err)
+	}
+	return nil, errors.NotFoundf("branch charm for unit %q", unitName)
+}
+
 // Branches returns all "in-flight" branches.
 func (st *State) Branches() ([]*Generation, error) {
 	col, closer := st.db().GetCollection(generationsC)
@@ -12,6 +12,7 @@ import (
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
+	"github.com/juju/juju/core/constraints"
 	"github.com/juju/juju/core/model"
 	"github.com/juju/juju/core/settings"
 	"github.com/juju/juju/state"
@@ -284,6 +285,161 @@ func (s *generationSuite) TestCommitAppliesConfigDeltas(c *gc.C) {
 	c.Check(cfg, gc.DeepEquals, charm.Settings(newCfg))
 }
 
+func (s *generationSuite) TestUpdateCharmURL(c *gc.C) {
+	gen := s.setupAssignAllUnits(c)
+	c.Assert(gen.AssignUnit("riak/0"), jc.ErrorIsNil)
+	c.Assert(gen.Refresh(), jc.ErrorIsNil)
+
+	newCh := s.addNewRiakRevision(c)
+	err := gen.UpdateCharmURL("riak", newCh.URL(), map[string]string{"data": "pending-id"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(gen.Refresh(), jc.ErrorIsNil)
+
+	curl, err := gen.CharmURL("riak")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(curl, gc.DeepEquals, newCh.URL())
+	c.Check(gen.Resources(), gc.DeepEquals, map[string]map[string]string{"riak": {"data": "pending-id"}})
+	c.Check(gen.HasChangesFor("riak"), jc.IsTrue)
+
+	// Only the unit tracking the branch runs the new charm.
+	curl, err = s.State.BranchCharmURL("riak/0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(curl, gc.DeepEquals, newCh.URL())
+	_, err = s.State.BranchCharmURL("riak/1")
+	c.Check(err, jc.Satisfies, errors.IsNotFound)
+
+	// The tracking unit can be set to run the new charm.
+	unit, err := s.State.Unit("riak/0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(unit.SetCharmURL(newCh.URL()), jc.ErrorIsNil)
+	cfg, err := unit.ConfigSettings()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cfg["http_port"], gc.Equals, int64(8089))
+}
+
+func (s *generationSuite) TestUpdateCharmURLCurrentCharmError(c *gc.C) {
+	gen := s.setupAssignAllUnits(c)
+
+	err := gen.UpdateCharmURL("riak", s.ch.URL(), nil)
+	c.Assert(err, gc.ErrorMatches, `application "riak" already uses charm ".*riak-666"`)
+}
+
+func (s *generationSuite) TestCommitUpgradesCharm(c *gc.C) {
+	s.setupTestingClock(c)
+	gen := s.setupAssignAllUnits(c)
+	c.Assert(gen.AssignApplication("riak"), jc.ErrorIsNil)
+	c.Assert(gen.Refresh(), jc.ErrorIsNil)
+
+	newCh := s.addNewRiakRevision(c)
+	c.Assert(gen.UpdateCharmURL("riak", newCh.URL(), nil), jc.ErrorIsNil)
+	c.Assert(gen.Refresh(), jc.ErrorIsNil)
+
+	_, err := gen.Commit(branchCommitter)
+	c.Assert(err, jc.ErrorIsNil)
+
+	app, err := s.State.Application("riak")
+	c.Assert(err, jc.ErrorIsNil)
+	curl, _ := app.CharmURL()
+	c.Check(curl, gc.DeepEquals, newCh.URL())
+}
+
+func (s *generationSuite) TestCommitUpgradesCharmWithConfigDeltas(c *gc.C) {
+	s.setupTestingClock(c)
+	gen := s.setupAssignAllUnits(c)
+	c.Assert(gen.AssignApplication("riak"), jc.ErrorIsNil)
+	c.Assert(gen.Refresh(), jc.ErrorIsNil)
+
+	app, err := s.State.Application("riak")
+	c.Assert(err, jc.ErrorIsNil)
+	newCfg := map[string]interface{}{"http_port": int64(9999)}
+	c.Assert(app.UpdateCharmConfig(newBranchName, newCfg), jc.ErrorIsNil)
+
+	newCh := s.addNewRiakRevision(c)
+	c.Assert(gen.UpdateCharmURL("riak", newCh.URL(), nil), jc.ErrorIsNil)
+	c.Assert(gen.Refresh(), jc.ErrorIsNil)
+
+	_, err = gen.Commit(branchCommitter)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Assert(app.Refresh(), jc.ErrorIsNil)
+	curl, _ := app.CharmURL()
+	c.Check(curl, gc.DeepEquals, newCh.URL())
+	cfg, err := app.CharmConfig(model.GenerationMaster)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cfg, gc.DeepEquals, charm.Settings(newCfg))
+}
+
+func (s *generationSuite) TestCommitUpgradeFailureAppliesNothing(c *gc.C) {
+	s.setupTestingClock(c)
+	gen := s.setupAssignAllUnits(c)
+	c.Assert(gen.AssignApplication("riak"), jc.ErrorIsNil)
+	c.Assert(gen.Refresh(), jc.ErrorIsNil)
+
+	c.Assert(gen.UpdateConstraints("riak", constraints.MustParse("mem=4G")), jc.ErrorIsNil)
+	subordinate := s.AddTestingCharm(c, "logging")
+	c.Assert(gen.UpdateCharmURL("riak", subordinate.URL(), nil), jc.ErrorIsNil)
+	c.Assert(gen.Refresh(), jc.ErrorIsNil)
+
+	_, err := gen.Commit(branchCommitter)
+	c.Assert(err, gc.ErrorMatches, `upgrading charm for application "riak": cannot change an application's subordinacy`)
+
+	c.Assert(gen.Refresh(), jc.ErrorIsNil)
+	c.Check(gen.IsCompleted(), jc.IsFalse)
+	app, err := s.State.Application("riak")
+	c.Assert(err, jc.ErrorIsNil)
+	curl, _ := app.CharmURL()
+	c.Check(curl, gc.DeepEquals, s.ch.URL())
+	appCons, err := app.Constraints()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(appCons, gc.DeepEquals, constraints.Value{})
+}
+
+func (s *generationSuite) TestCommitAppliesConstraints(c *gc.C) {
+	s.setupTestingClock(c)
+	gen := s.setupAssignAllUnits(c)
+	c.Assert(gen.AssignApplication("riak"), jc.ErrorIsNil)
+	c.Assert(gen.Refresh(), jc.ErrorIsNil)
+
+	cons := constraints.MustParse("mem=4G")
+	c.Assert(gen.UpdateConstraints("riak", cons), jc.ErrorIsNil)
+	c.Assert(gen.Refresh(), jc.ErrorIsNil)
+	c.Check(gen.Constraints(), gc.DeepEquals, map[string]constraints.Value{"riak": cons})
+
+	app, err := s.State.Application("riak")
+	c.Assert(err, jc.ErrorIsNil)
+	appCons, err := app.Constraints()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(appCons, gc.DeepEquals, constraints.Value{})
+
+	_, err = gen.Commit(branchCommitter)
+	c.Assert(err, jc.ErrorIsNil)
+
+	appCons, err = app.Constraints()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(appCons, gc.DeepEquals, cons)
+}
+
+func (s *generationSuite) TestAbortReleasesBranchCharm(c *gc.C) {
+	s.setupTestingClock(c)
+	gen := s.setupAssignAllUnits(c)
+	c.Assert(gen.AssignApplication("riak"), jc.ErrorIsNil)
+	c.Assert(gen.Refresh(), jc.ErrorIsNil)
+
+	newCh := s.addNewRiakRevision(c)
+	c.Assert(gen.UpdateCharmURL("riak", newCh.URL(), nil), jc.ErrorIsNil)
+	c.Assert(gen.Refresh(), jc.ErrorIsNil)
+
+	count, err := state.ApplicationSettingsRefCount(s.State, "riak", newCh.URL())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(count, gc.Equals, 1)
+
+	c.Assert(gen.Abort(branchCommitter), jc.ErrorIsNil)
+
+	count, err = state.ApplicationSettingsRefCount(s.State, "riak", newCh.URL())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(count, gc.Equals, 0)
+}
+
 func (s *generationSuite) TestAbortSuccess(c *gc.C) {
 	s.setupTestingClock(c)
 
@@ -343,6 +499,73 @@ func (s *generationSuite) TestAbortCommittedBranch(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "branch was already committed")
 }
 
+func (s *generationSuite) TestUnassignAllUnits(c *gc.C) {
+	s.setupTestingClock(c)
+
+	gen := s.setupAssignAllUnits(c)
+	c.Assert(gen.AssignUnit("riak/0"), jc.ErrorIsNil)
+	c.Assert(gen.AssignUnit("riak/1"), jc.ErrorIsNil)
+	c.Assert(gen.Refresh(), jc.ErrorIsNil)
+
+	err := gen.UnassignAllUnits()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(gen.Refresh(), jc.ErrorIsNil)
+	c.Check(gen.AssignedUnits(), gc.DeepEquals, map[string][]string{"riak": {}})
+
+	// Idempotent.
+	c.Assert(gen.UnassignAllUnits(), jc.ErrorIsNil)
+
+	// The branch can now be aborted.
+	c.Assert(gen.Abort(branchCommitter), jc.ErrorIsNil)
+}
+
+func (s *generationSuite) TestSetPromotionPolicy(c *gc.C) {
+	s.setupTestingClock(c)
+
+	gen := s.addBranch(c)
+	_, ok := gen.PromotionPolicy()
+	c.Assert(ok, jc.IsFalse)
+
+	err := gen.SetPromotionPolicy(time.Hour, branchCommitter)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(gen.Refresh(), jc.ErrorIsNil)
+
+	policy, ok := gen.PromotionPolicy()
+	c.Assert(ok, jc.IsTrue)
+	c.Check(policy.SoakTime, gc.Equals, time.Hour)
+	c.Check(policy.SetBy, gc.Equals, branchCommitter)
+	now, err := s.State.ControllerTimestamp()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(policy.Started.Unix(), gc.Equals, now.Unix())
+	c.Check(policy.Deadline().Unix(), gc.Equals, now.Add(time.Hour).Unix())
+
+	// A zero soak time cancels the policy.
+	err = gen.SetPromotionPolicy(0, branchCommitter)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(gen.Refresh(), jc.ErrorIsNil)
+	_, ok = gen.PromotionPolicy()
+	c.Assert(ok, jc.IsFalse)
+}
+
+func (s *generationSuite) TestSetPromotionPolicyInvalidSoakTime(c *gc.C) {
+	gen := s.addBranch(c)
+	err := gen.SetPromotionPolicy(time.Millisecond, branchCommitter)
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+	err = gen.SetPromotionPolicy(-time.Hour, branchCommitter)
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *generationSuite) TestSetPromotionPolicyCompletedError(c *gc.C) {
+	s.setupTestingClock(c)
+
+	gen := s.addBranch(c)
+	c.Assert(gen.Abort(branchCommitter), jc.ErrorIsNil)
+	c.Assert(gen.Refresh(), jc.ErrorIsNil)
+
+	err := gen.SetPromotionPolicy(time.Hour, branchCommitter)
+	c.Assert(err, gc.ErrorMatches, "branch was already aborted")
+}
+
 func (s *generationSuite) TestBranchCharmConfigDeltas(c *gc.C) {
 	gen := s.setupAssignAllUnits(c)
 	c.Assert(gen.Config(), gc.HasLen, 0)
@@ -536,6 +759,14 @@ options:
 	return s.addBranch(c)
 }
 
+func (s *generationSuite) addNewRiakRevision(c *gc.C) *state.Charm {
+	var cfgYAML = `
+options:
+  http_port: {default: 8089, description: HTTP Port, type: int}
+`
+	return s.AddConfigCharm(c, "riak", cfgYAML, 667)
+}
+
 func (s *generationSuite) addBranch(c *gc.C) *state.Generation {
 	c.Assert(s.Model.AddBranch(newBranchName, newBranchCreator), jc.ErrorIsNil)
 	branch, err := s.Model.Branch(newBranchName)
@@ -82,6 +82,7 @@ type modelSummaryProcessor struct {
 	st          *State
 	summaries   []ModelSummary
 	user        names.UserTag
+	groups      []string
 	isSuperuser bool
 	indexByUUID map[string]int
 	modelUUIDs  []string
@@ -94,10 +95,11 @@ type modelSummaryProcessor struct {
 	incompleteUUIDs set.Strings
 }
 
-func newProcessorFromModelDocs(st *State, modelDocs []modelDoc, user names.UserTag, isSuperuser bool) *modelSummaryProcessor {
+func newProcessorFromModelDocs(st *State, modelDocs []modelDoc, user names.UserTag, groups []string, isSuperuser bool) *modelSummaryProcessor {
 	p := &modelSummaryProcessor{
 		st:          st,
 		user:        user,
+		groups:      groups,
 		isSuperuser: isSuperuser,
 	}
 	p.summaries = make([]ModelSummary, len(modelDocs))
@@ -224,8 +226,10 @@ func (p *modelSummaryProcessor) fillInPermissions(permissionIds []string) error
 			continue
 		}
 		details := &p.summaries[modelIdx]
+		// The user may have access both directly and through their
+		// groups, in which case the greatest access applies.
 		access := permission.Access(doc.Access)
-		if err := access.Validate(); err == nil {
+		if err := access.Validate(); err == nil && (details.Access == "" || access.GreaterModelAccessThan(details.Access)) {
 			details.Access = access
 		}
 	}
@@ -401,7 +405,8 @@ func (p *modelSummaryProcessor) fillInMigration() error {
 	return nil
 }
 
-// fillInJustUser fills in the Access rights for this user on every model (but not other users).
+// fillInJustUser fills in the Access rights for this user, including those granted to their groups, on every model
+// (but not other users).
 // We will use this information later to determine whether it is reasonable to include the information from other models.
 func (p *modelSummaryProcessor) fillInJustUser() error {
 	// Note: Even for Superuser we track the individual Access for each model.
@@ -412,6 +417,9 @@ func (p *modelSummaryProcessor) fillInJustUser() error {
 	for _, modelUUID := range p.modelUUIDs {
 		permId := permissionID(modelKey(modelUUID), userGlobalKey(username))
 		permissionIds = append(permissionIds, permId)
+		for _, group := range p.groups {
+			permissionIds = append(permissionIds, permissionID(modelKey(modelUUID), groupGlobalKey(group)))
+		}
 	}
 	if err := p.fillInPermissions(permissionIds); err != nil {
 		return errors.Trace(err)
@@ -152,7 +152,7 @@ func (s *ModelSummariesSuite) TestModelsForUserAdmin(c *gc.C) {
 
 func (s *ModelSummariesSuite) TestModelsForSuperuserWithoutAll(c *gc.C) {
 	s.Setup4Models(c)
-	summaries, err := s.State.ModelSummariesForUser(s.Model.Owner(), false)
+	summaries, err := s.State.ModelSummariesForUser(s.Model.Owner(), nil, false)
 	c.Assert(err, jc.ErrorIsNil)
 	names := make([]string, len(summaries))
 	for i, summary := range summaries {
@@ -164,7 +164,7 @@ func (s *ModelSummariesSuite) TestModelsForSuperuserWithoutAll(c *gc.C) {
 
 func (s *ModelSummariesSuite) TestModelsForSuperuserWithAll(c *gc.C) {
 	s.Setup4Models(c)
-	summaries, err := s.State.ModelSummariesForUser(s.Model.Owner(), true)
+	summaries, err := s.State.ModelSummariesForUser(s.Model.Owner(), nil, true)
 	c.Assert(err, jc.ErrorIsNil)
 	names := make([]string, len(summaries))
 	access := make(map[string]string)
@@ -243,9 +243,49 @@ func (s *ModelSummariesSuite) TestModelsForIgnoresImportingModels(c *gc.C) {
 	c.Check(names, gc.DeepEquals, []string{"shared", "testmodel", "user1model", "user2model", "user3model"})
 }
 
+func (s *ModelSummariesSuite) TestModelsForGroupMember(c *gc.C) {
+	modelUUIDs := s.Setup4Models(c)
+	err := s.State.CreateGroupAccess("ops", names.NewModelTag(modelUUIDs["shared"]), permission.AdminAccess)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.CreateGroupAccess("ops", names.NewModelTag(modelUUIDs["user3model"]), permission.ReadAccess)
+	c.Assert(err, jc.ErrorIsNil)
+
+	summaries, err := s.State.ModelSummariesForUser(names.NewUserTag("user1write"), []string{"OPS"}, false)
+	c.Assert(err, jc.ErrorIsNil)
+	access := make(map[string]permission.Access)
+	for _, summary := range summaries {
+		access[summary.Name] = summary.Access
+	}
+	// The greater of the user's and the group's access applies.
+	c.Check(access, jc.DeepEquals, map[string]permission.Access{
+		"shared":     permission.AdminAccess,
+		"user1model": permission.AdminAccess,
+		"user3model": permission.ReadAccess,
+	})
+
+	info, err := s.State.ModelBasicInfoForUser(names.NewUserTag("user1write"), []string{"ops"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(info, gc.HasLen, 3)
+}
+
+func (s *ModelSummariesSuite) TestModelsForGroupSuperuser(c *gc.C) {
+	s.Setup4Models(c)
+	err := s.State.CreateGroupAccess("ops", names.NewControllerTag(s.State.ControllerUUID()), permission.SuperuserAccess)
+	c.Assert(err, jc.ErrorIsNil)
+
+	summaries, err := s.State.ModelSummariesForUser(names.NewUserTag("user2read"), []string{"ops"}, true)
+	c.Assert(err, jc.ErrorIsNil)
+	names := make([]string, len(summaries))
+	for i, summary := range summaries {
+		names[i] = summary.Name
+	}
+	sort.Strings(names)
+	c.Check(names, gc.DeepEquals, []string{"shared", "testmodel", "user1model", "user2model", "user3model"})
+}
+
 func (s *ModelSummariesSuite) TestContainsConfigInformation(c *gc.C) {
 	s.Setup4Models(c)
-	summaries, err := s.State.ModelSummariesForUser(names.NewUserTag("user1write"), false)
+	summaries, err := s.State.ModelSummariesForUser(names.NewUserTag("user1write"), nil, false)
 	c.Assert(err, jc.ErrorIsNil)
 	c.Check(summaries, gc.HasLen, 2)
 	// We don't guarantee the order of the summaries, but the data for each model should match the same
@@ -268,7 +308,7 @@ func (s *ModelSummariesSuite) TestContainsConfigInformation(c *gc.C) {
 
 func (s *ModelSummariesSuite) TestContainsProviderType(c *gc.C) {
 	s.Setup4Models(c)
-	summaries, err := s.State.ModelSummariesForUser(names.NewUserTag("user1write"), false)
+	summaries, err := s.State.ModelSummariesForUser(names.NewUserTag("user1write"), nil, false)
 	c.Assert(err, jc.ErrorIsNil)
 	c.Check(summaries, gc.HasLen, 2)
 	// We don't guarantee the order of the summaries, but both should have the same ProviderType
@@ -303,7 +343,7 @@ func (s *ModelSummariesSuite) TestContainsModelStatus(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	err = user1.SetStatus(expectedStatus["user1model"])
 	c.Assert(err, jc.ErrorIsNil)
-	summaries, err := s.State.ModelSummariesForUser(names.NewUserTag("user1write"), false)
+	summaries, err := s.State.ModelSummariesForUser(names.NewUserTag("user1write"), nil, false)
 	c.Assert(err, jc.ErrorIsNil)
 	c.Check(summaries, gc.HasLen, 2)
 	statuses := make(map[string]status.StatusInfo)
@@ -341,7 +381,7 @@ func (s *ModelSummariesSuite) TestContainsModelStatusSuspended(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	err = user1.SetStatus(expectedStatus["user1model"])
 	c.Assert(err, jc.ErrorIsNil)
-	summaries, err := s.State.ModelSummariesForUser(names.NewUserTag("user1write"), false)
+	summaries, err := s.State.ModelSummariesForUser(names.NewUserTag("user1write"), nil, false)
 	c.Assert(err, jc.ErrorIsNil)
 	c.Check(summaries, gc.HasLen, 2)
 	statuses := make(map[string]status.StatusInfo)
@@ -378,7 +418,7 @@ func (s *ModelSummariesSuite) TestContainsAccessInformation(c *gc.C) {
 	err = user1.UpdateLastModelConnection(names.NewUserTag("user1write"))
 	c.Assert(err, jc.ErrorIsNil)
 
-	summaries, err := s.State.ModelSummariesForUser(names.NewUserTag("user1write"), false)
+	summaries, err := s.State.ModelSummariesForUser(names.NewUserTag("user1write"), nil, false)
 	c.Assert(err, jc.ErrorIsNil)
 	c.Check(summaries, gc.HasLen, 2)
 	times := make(map[string]time.Time)
@@ -446,7 +486,7 @@ func (s *ModelSummariesSuite) TestContainsMachineInformation(c *gc.C) {
 	}, nil, nil, nil, nil, nil)
 	c.Assert(err, jc.ErrorIsNil)
 
-	summaries, err := s.State.ModelSummariesForUser(names.NewUserTag("user1write"), false)
+	summaries, err := s.State.ModelSummariesForUser(names.NewUserTag("user1write"), nil, false)
 	c.Assert(err, jc.ErrorIsNil)
 	c.Check(summaries, gc.HasLen, 2)
 	summaryMap := make(map[string]*state.ModelSummary)
@@ -469,7 +509,7 @@ func (s *ModelSummariesSuite) TestContainsMigrationInformation(c *gc.C) {
 }
 
 func (s *ModelSummariesSuite) namedSummariesForUser(c *gc.C, user string) map[string]*state.ModelSummary {
-	summaries, err := s.State.ModelSummariesForUser(names.NewUserTag(user), false)
+	summaries, err := s.State.ModelSummariesForUser(names.NewUserTag(user), nil, false)
 	c.Assert(err, jc.ErrorIsNil)
 	summaryMap := make(map[string]*state.ModelSummary, len(summaries))
 	for i := range summaries {
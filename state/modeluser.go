@@ -150,6 +150,7 @@ func createModelUserOps(modelUUID string, user, createdBy names.UserTag, display
 func removeModelUserOps(modelUUID string, user names.UserTag) []txn.Op {
 	return []txn.Op{
 		removePermissionOp(modelKey(modelUUID), userGlobalKey(userAccessID(user))),
+		removeModelUserRoleOp(modelUUID, user),
 		{
 			C:      modelUsersC,
 			Id:     userAccessID(user),
@@ -171,30 +172,37 @@ func (st *State) removeModelUser(user names.UserTag) error {
 	return nil
 }
 
-// isUserSuperuser if this user has the Superuser access on the controller.
-func (st *State) isUserSuperuser(user names.UserTag) (bool, error) {
+// isUserSuperuser if this user, or any of the groups they belong to,
+// has the Superuser access on the controller.
+func (st *State) isUserSuperuser(user names.UserTag, groups []string) (bool, error) {
 	access, err := st.UserAccess(user, st.controllerTag)
-	if err != nil {
+	if err != nil && (!errors.IsNotFound(err) || len(groups) == 0) {
 		// TODO(jam): 2017-11-27 We weren't suppressing NotFound here so that we would know when someone asked for
 		// the list of models of a user that doesn't exist.
 		// However, now we will not even check if its a known user if they aren't asking for all=true.
 		return false, errors.Trace(err)
 	}
-	isControllerSuperuser := (access.Access == permission.SuperuserAccess)
-	return isControllerSuperuser, nil
+	if err == nil && access.Access == permission.SuperuserAccess {
+		return true, nil
+	}
+	isSuperuser, err := st.groupsAreSuperuser(groups)
+	return isSuperuser, errors.Trace(err)
 }
 
-func (st *State) ModelSummariesForUser(user names.UserTag, all bool) ([]ModelSummary, error) {
+// ModelSummariesForUser returns summaries of the models that the user,
+// or any of the groups they belong to, has access to. If all is true
+// and the user is a controller superuser, every model is summarised.
+func (st *State) ModelSummariesForUser(user names.UserTag, groups []string, all bool) ([]ModelSummary, error) {
 	// We only treat the user as a superuser if they pass --all
 	isControllerSuperuser := false
 	if all {
 		var err error
-		isControllerSuperuser, err = st.isUserSuperuser(user)
+		isControllerSuperuser, err = st.isUserSuperuser(user, groups)
 		if err != nil {
 			return nil, errors.Trace(err)
 		}
 	}
-	modelQuery, closer, err := st.modelQueryForUser(user, isControllerSuperuser)
+	modelQuery, closer, err := st.modelQueryForUser(user, groups, isControllerSuperuser)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -203,7 +211,7 @@ func (st *State) ModelSummariesForUser(user names.UserTag, all bool) ([]ModelSum
 	if err := modelQuery.All(&modelDocs); err != nil {
 		return nil, errors.Trace(err)
 	}
-	p := newProcessorFromModelDocs(st, modelDocs, user, isControllerSuperuser)
+	p := newProcessorFromModelDocs(st, modelDocs, user, groups, isControllerSuperuser)
 	modelDocs = nil
 	if err := p.fillInFromConfig(); err != nil {
 		return nil, errors.Trace(err)
@@ -232,9 +240,9 @@ func (st *State) ModelSummariesForUser(user names.UserTag, all bool) ([]ModelSum
 	return p.summaries, nil
 }
 
-// modelsForUser gives you the information about all models that a user has access to.
+// modelsForUser gives you the information about all models that a user, or any of their groups, has access to.
 // This includes the name and UUID, as well as the last time the user connected to that model.
-func (st *State) modelQueryForUser(user names.UserTag, isSuperuser bool) (mongo.Query, SessionCloser, error) {
+func (st *State) modelQueryForUser(user names.UserTag, groups []string, isSuperuser bool) (mongo.Query, SessionCloser, error) {
 	var modelQuery mongo.Query
 	models, closer := st.db().GetCollection(modelsC)
 	if isSuperuser {
@@ -260,6 +268,12 @@ func (st *State) modelQueryForUser(user names.UserTag, isSuperuser bool) (mongo.
 			closer()
 			return nil, nil, errors.Trace(err)
 		}
+		groupModelUUIDs, err := st.groupModelUUIDs(groups)
+		if err != nil {
+			closer()
+			return nil, nil, errors.Trace(err)
+		}
+		modelUUIDs = append(modelUUIDs, groupModelUUIDs...)
 		modelQuery = models.Find(bson.M{
 			"_id":            bson.M{"$in": modelUUIDs},
 			"migration-mode": bson.M{"$ne": MigrationModeImporting},
@@ -277,14 +291,15 @@ type ModelAccessInfo struct {
 	LastConnection time.Time
 }
 
-// ModelBasicInfoForUser gives you the information about all models that a user has access to.
+// ModelBasicInfoForUser gives you the information about all models that a user, or any of the groups they
+// belong to, has access to.
 // This includes the name and UUID, as well as the last time the user connected to that model.
-func (st *State) ModelBasicInfoForUser(user names.UserTag) ([]ModelAccessInfo, error) {
-	isSuperuser, err := st.isUserSuperuser(user)
+func (st *State) ModelBasicInfoForUser(user names.UserTag, groups []string) ([]ModelAccessInfo, error) {
+	isSuperuser, err := st.isUserSuperuser(user, groups)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	modelQuery, closer1, err := st.modelQueryForUser(user, isSuperuser)
+	modelQuery, closer1, err := st.modelQueryForUser(user, groups, isSuperuser)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -321,10 +336,10 @@ func (st *State) ModelBasicInfoForUser(user names.UserTag) ([]ModelAccessInfo, e
 	return accessInfo, nil
 }
 
-// ModelUUIDsForUser returns a list of models that the user is able to
-// access.
+// ModelUUIDsForUser returns a list of models that the user, or any of
+// the groups they belong to, is able to access.
 // Results are sorted by (name, owner).
-func (st *State) ModelUUIDsForUser(user names.UserTag) ([]string, error) {
+func (st *State) ModelUUIDsForUser(user names.UserTag, groups []string) ([]string, error) {
 	// Consider the controller permissions overriding Model permission, for
 	// this case the only relevant one is superuser.
 	// The mgo query below wont work for superuser case because it needs at
@@ -334,18 +349,25 @@ func (st *State) ModelUUIDsForUser(user names.UserTag) ([]string, error) {
 		return nil, errors.Trace(err)
 	}
 
+	isSuperuser := access.Access == permission.SuperuserAccess
+	if !isSuperuser {
+		if isSuperuser, err = st.groupsAreSuperuser(groups); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+
 	var modelUUIDs []string
-	if access.Access == permission.SuperuserAccess {
+	if isSuperuser {
 		var err error
 		modelUUIDs, err = st.AllModelUUIDs()
 		if err != nil {
 			return nil, errors.Trace(err)
 		}
 	} else {
-		// Since there are no groups at this stage, the simplest way to get all
-		// the models that a particular user can see is to look through the
-		// model user collection. A raw collection is required to support
-		// queries across multiple models.
+		// The models that a particular user can see directly are found by
+		// looking through the model user collection. A raw collection is
+		// required to support queries across multiple models. Models that
+		// their groups can see are found from the groups' permissions.
 		modelUsers, userCloser := st.db().GetRawCollection(modelUsersC)
 		defer userCloser()
 
@@ -357,6 +379,11 @@ func (st *State) ModelUUIDsForUser(user names.UserTag) ([]string, error) {
 		for _, doc := range userSlice {
 			modelUUIDs = append(modelUUIDs, doc.ObjectUUID)
 		}
+		groupModelUUIDs, err := st.groupModelUUIDs(groups)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		modelUUIDs = append(modelUUIDs, groupModelUUIDs...)
 	}
 
 	modelsColl, close := st.db().GetCollection(modelsC)
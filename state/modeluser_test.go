@@ -365,21 +365,21 @@ func (s *ModelUserSuite) TestUpdateLastConnectionTwoModelUsers(c *gc.C) {
 
 func (s *ModelUserSuite) TestModelUUIDsForUserNone(c *gc.C) {
 	tag := names.NewUserTag("non-existent@remote")
-	models, err := s.State.ModelUUIDsForUser(tag)
+	models, err := s.State.ModelUUIDsForUser(tag, nil)
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(models, gc.HasLen, 0)
 }
 
 func (s *ModelUserSuite) TestModelUUIDsForUserNewLocalUser(c *gc.C) {
 	user := s.Factory.MakeUser(c, &factory.UserParams{NoModelUser: true})
-	models, err := s.State.ModelUUIDsForUser(user.UserTag())
+	models, err := s.State.ModelUUIDsForUser(user.UserTag(), nil)
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(models, gc.HasLen, 0)
 }
 
 func (s *ModelUserSuite) TestModelUUIDsForUser(c *gc.C) {
 	user := s.Factory.MakeUser(c, nil)
-	models, err := s.State.ModelUUIDsForUser(user.UserTag())
+	models, err := s.State.ModelUUIDsForUser(user.UserTag(), nil)
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(models, jc.DeepEquals, []string{s.State.ModelUUID()})
 
@@ -393,7 +393,7 @@ func (s *ModelUserSuite) TestModelUUIDsForUser(c *gc.C) {
 
 func (s *ModelUserSuite) TestImportingModelUUIDsForUser(c *gc.C) {
 	user := s.Factory.MakeUser(c, nil)
-	models, err := s.State.ModelUUIDsForUser(user.UserTag())
+	models, err := s.State.ModelUUIDsForUser(user.UserTag(), nil)
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(models, jc.DeepEquals, []string{s.State.ModelUUID()})
 
@@ -402,7 +402,7 @@ func (s *ModelUserSuite) TestImportingModelUUIDsForUser(c *gc.C) {
 	err = model.SetMigrationMode(state.MigrationModeImporting)
 	c.Assert(err, jc.ErrorIsNil)
 
-	models, err = s.State.ModelUUIDsForUser(user.UserTag())
+	models, err = s.State.ModelUUIDsForUser(user.UserTag(), nil)
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(models, gc.HasLen, 0)
 }
@@ -411,7 +411,7 @@ func (s *ModelUserSuite) TestModelUUIDsForUserModelOwner(c *gc.C) {
 	owner := names.NewUserTag("external@remote")
 	model := s.newModelWithOwner(c, owner)
 
-	models, err := s.State.ModelUUIDsForUser(owner)
+	models, err := s.State.ModelUUIDsForUser(owner, nil)
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(models, jc.DeepEquals, []string{model.UUID()})
 }
@@ -420,7 +420,7 @@ func (s *ModelUserSuite) TestModelUUIDsForUserOfNewModel(c *gc.C) {
 	userTag := names.NewUserTag("external@remote")
 	model := s.newModelWithUser(c, userTag, state.ModelTypeIAAS)
 
-	models, err := s.State.ModelUUIDsForUser(userTag)
+	models, err := s.State.ModelUUIDsForUser(userTag, nil)
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(models, jc.DeepEquals, []string{model.UUID()})
 }
@@ -435,17 +435,34 @@ func (s *ModelUserSuite) TestModelUUIDsForUserMultiple(c *gc.C) {
 		s.newModelWithOwner(c, userTag).UUID(),
 	}
 
-	models, err := s.State.ModelUUIDsForUser(userTag)
+	models, err := s.State.ModelUUIDsForUser(userTag, nil)
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(models, jc.SameContents, expected)
 }
 
+func (s *ModelUserSuite) TestModelUUIDsForGroupMember(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{NoModelUser: true})
+	models, err := s.State.ModelUUIDsForUser(user.UserTag(), []string{"ops"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(models, gc.HasLen, 0)
+
+	err = s.State.CreateGroupAccess("ops", s.Model.ModelTag(), permission.ReadAccess)
+	c.Assert(err, jc.ErrorIsNil)
+	models, err = s.State.ModelUUIDsForUser(user.UserTag(), []string{"ops"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(models, jc.DeepEquals, []string{s.State.ModelUUID()})
+
+	models, err = s.State.ModelUUIDsForUser(user.UserTag(), []string{"dev"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(models, gc.HasLen, 0)
+}
+
 func (s *ModelUserSuite) TestModelBasicInfoForUser(c *gc.C) {
 	user := s.Factory.MakeUser(c, &factory.UserParams{NoModelUser: true})
 	model := s.newModelWithUser(c, user.UserTag(), state.ModelTypeIAAS)
 	model2 := s.newModelWithUser(c, user.UserTag(), state.ModelTypeCAAS)
 
-	models, err := s.State.ModelBasicInfoForUser(user.UserTag())
+	models, err := s.State.ModelBasicInfoForUser(user.UserTag(), nil)
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(models, jc.SameContents, []state.ModelAccessInfo{
 		{
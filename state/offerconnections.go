@@ -5,10 +5,13 @@ package state
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/juju/errors"
+	"github.com/juju/juju/core/crossmodel"
 	"github.com/juju/juju/core/status"
 	"github.com/juju/names/v4"
+	jujutxn "github.com/juju/txn"
 	"gopkg.in/mgo.v2"
 	"gopkg.in/mgo.v2/bson"
 	"gopkg.in/mgo.v2/txn"
@@ -29,6 +32,13 @@ type offerConnectionDoc struct {
 	OfferUUID       string `bson:"offer-uuid"`
 	UserName        string `bson:"username"`
 	SourceModelUUID string `bson:"source-model-uuid"`
+	TxnRevno        int64  `bson:"txn-revno"`
+
+	// SettingsWindowStart and SettingsWindowCount record the minute,
+	// as a unix time, in which relation settings changes were last
+	// published over the connection, and how many were published.
+	SettingsWindowStart int64 `bson:"settings-window-start,omitempty"`
+	SettingsWindowCount int   `bson:"settings-window-count,omitempty"`
 }
 
 func newOfferConnection(st *State, doc *offerConnectionDoc) *OfferConnection {
@@ -64,6 +74,59 @@ func (oc *OfferConnection) RelationKey() string {
 	return oc.doc.RelationKey
 }
 
+// RecordSettingsChange records that a relation settings change is being
+// published over the connection, and reports whether the change keeps
+// within the limit of perMinute changes in the current minute. Changes
+// over the limit are not recorded. A perMinute of zero or less means
+// there is no limit.
+func (oc *OfferConnection) RecordSettingsChange(perMinute int) (allowed bool, err error) {
+	if perMinute <= 0 {
+		return true, nil
+	}
+	defer errors.DeferredAnnotatef(&err, "cannot record settings change for %s", oc)
+
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := oc.refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		windowStart := oc.st.clock().Now().Truncate(time.Minute).Unix()
+		count := 0
+		if oc.doc.SettingsWindowStart == windowStart {
+			count = oc.doc.SettingsWindowCount
+		}
+		allowed = count < perMinute
+		if !allowed {
+			return nil, jujutxn.ErrNoOperations
+		}
+		return []txn.Op{{
+			C:      offerConnectionsC,
+			Id:     oc.doc.DocID,
+			Assert: bson.D{{"txn-revno", oc.doc.TxnRevno}},
+			Update: bson.D{{"$set", bson.D{
+				{"settings-window-start", windowStart},
+				{"settings-window-count", count + 1},
+			}}},
+		}}, nil
+	}
+	if err := oc.st.db().Run(buildTxn); err != nil {
+		return false, errors.Trace(err)
+	}
+	return allowed, nil
+}
+
+func (oc *OfferConnection) refresh() error {
+	offerConnectionCollection, closer := oc.st.db().GetCollection(offerConnectionsC)
+	defer closer()
+
+	err := offerConnectionCollection.FindId(oc.doc.DocID).One(&oc.doc)
+	if err == mgo.ErrNotFound {
+		return errors.NotFoundf("offer connection for relation %q", oc.doc.RelationKey)
+	}
+	return errors.Trace(err)
+}
+
 func removeOfferConnectionsForRelationOps(relId int) []txn.Op {
 	op := txn.Op{
 		C:      offerConnectionsC,
@@ -142,6 +205,15 @@ func (st *State) AddOfferConnection(args AddOfferConnectionParams) (_ *OfferConn
 			}
 			return nil, errors.AlreadyExistsf("offer connection for relation id %d", args.RelationId)
 		}
+		if exists, err := st.offerConnectionExists(offerConnectionDoc.DocID); err != nil {
+			return nil, errors.Trace(err)
+		} else if exists {
+			return nil, errors.AlreadyExistsf("offer connection for relation id %d", args.RelationId)
+		}
+		limitOps, err := st.offerConnectionLimitOps(args.OfferUUID)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
 		ops := []txn.Op{
 			model.assertActiveOp(),
 			{
@@ -151,12 +223,57 @@ func (st *State) AddOfferConnection(args AddOfferConnectionParams) (_ *OfferConn
 				Insert: &offerConnectionDoc,
 			},
 		}
-		return ops, nil
+		return append(ops, limitOps...), nil
 	}
 	if err = st.db().Run(buildTxn); err != nil {
 		return nil, errors.Trace(err)
 	}
-	return &OfferConnection{doc: offerConnectionDoc}, nil
+	return newOfferConnection(st, &offerConnectionDoc), nil
+}
+
+func (st *State) offerConnectionExists(docID string) (bool, error) {
+	offerConnectionCollection, closer := st.db().GetCollection(offerConnectionsC)
+	defer closer()
+
+	n, err := offerConnectionCollection.FindId(docID).Count()
+	return n > 0, errors.Trace(err)
+}
+
+// offerConnectionLimitOps returns an error if the offer with the given
+// UUID already has as many connections as it allows, or else the ops
+// needed to ensure that remains true when the connection is added.
+// The offer document is touched so that its txn-revno changes, which
+// serialises concurrent connections to the offer; connections which
+// are removed meanwhile can only lower the count.
+func (st *State) offerConnectionLimitOps(offerUUID string) ([]txn.Op, error) {
+	applicationOffersCollection, closer := st.db().GetCollection(applicationOffersC)
+	defer closer()
+
+	var doc struct {
+		DocID          string `bson:"_id"`
+		MaxConnections int    `bson:"max-connections"`
+		TxnRevno       int64  `bson:"txn-revno"`
+	}
+	err := applicationOffersCollection.Find(bson.D{{"offer-uuid", offerUUID}}).One(&doc)
+	if err == mgo.ErrNotFound || (err == nil && doc.MaxConnections <= 0) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	conns, err := st.OfferConnections(offerUUID)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	limits := crossmodel.OfferLimits{MaxConnections: doc.MaxConnections}
+	if err := limits.CheckConnections(len(conns)); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return []txn.Op{{
+		C:      applicationOffersC,
+		Id:     doc.DocID,
+		Assert: bson.D{{"txn-revno", doc.TxnRevno}},
+		Update: bson.D{{"$set", bson.D{{"max-connections", doc.MaxConnections}}}},
+	}}, nil
 }
 
 // AllOfferConnections returns all offer connections in the model.
@@ -5,11 +5,13 @@ package state_test
 
 import (
 	"fmt"
+	"time"
 
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/errors"
+	"github.com/juju/juju/core/crossmodel"
 	"github.com/juju/juju/core/status"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/testing"
@@ -187,3 +189,70 @@ func (s *offerConnectionsSuite) TestAllOfferConnections(c *gc.C) {
 	c.Assert(obtainedStr, jc.SameContents, []string{oc1.String(), oc2.String()})
 
 }
+
+func (s *offerConnectionsSuite) TestAddOfferConnectionMaxConnections(c *gc.C) {
+	owner := s.Factory.MakeUser(c, nil)
+	offer, err := state.NewApplicationOffers(s.State).AddOffer(crossmodel.AddApplicationOfferArgs{
+		OfferName:       "hosted-mysql",
+		ApplicationName: "mysql",
+		Endpoints:       map[string]string{"server": "server"},
+		Owner:           owner.Name(),
+		Limits:          crossmodel.OfferLimits{MaxConnections: 1},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.State.AddOfferConnection(state.AddOfferConnectionParams{
+		SourceModelUUID: testing.ModelTag.Id(),
+		RelationId:      s.activeRel.Id(),
+		RelationKey:     s.activeRel.Tag().Id(),
+		Username:        "fred",
+		OfferUUID:       offer.OfferUUID,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.State.AddOfferConnection(state.AddOfferConnectionParams{
+		SourceModelUUID: testing.ModelTag.Id(),
+		RelationId:      s.suspendedRel.Id(),
+		RelationKey:     s.suspendedRel.Tag().Id(),
+		Username:        "fred",
+		OfferUUID:       offer.OfferUUID,
+	})
+	c.Assert(err, jc.Satisfies, errors.IsQuotaLimitExceeded)
+
+	// Adding a connection which already exists reports that instead.
+	_, err = s.State.AddOfferConnection(state.AddOfferConnectionParams{
+		SourceModelUUID: testing.ModelTag.Id(),
+		RelationId:      s.activeRel.Id(),
+		RelationKey:     s.activeRel.Tag().Id(),
+		Username:        "fred",
+		OfferUUID:       offer.OfferUUID,
+	})
+	c.Assert(err, jc.Satisfies, errors.IsAlreadyExists)
+}
+
+func (s *offerConnectionsSuite) TestRecordSettingsChange(c *gc.C) {
+	_, err := s.State.AddOfferConnection(state.AddOfferConnectionParams{
+		SourceModelUUID: testing.ModelTag.Id(),
+		RelationId:      s.activeRel.Id(),
+		RelationKey:     s.activeRel.Tag().Id(),
+		Username:        "fred",
+		OfferUUID:       "offer-uuid",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Each API server loads the connection separately; the limit
+	// applies to them all.
+	record := func() bool {
+		oc, err := s.State.OfferConnectionForRelation(s.activeRel.Tag().Id())
+		c.Assert(err, jc.ErrorIsNil)
+		allowed, err := oc.RecordSettingsChange(2)
+		c.Assert(err, jc.ErrorIsNil)
+		return allowed
+	}
+	c.Assert(record(), jc.IsTrue)
+	c.Assert(record(), jc.IsTrue)
+	c.Assert(record(), jc.IsFalse)
+
+	s.Clock.Advance(time.Minute)
+	c.Assert(record(), jc.IsTrue)
+}
@@ -113,6 +113,43 @@ func (s *PrecheckerSuite) TestPrecheckNoPolicy(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 }
 
+func (s *PrecheckerSuite) TestPrecheckMachine(c *gc.C) {
+	err := s.State.SetModelConstraints(constraints.MustParse("mem=4G"))
+	c.Assert(err, jc.ErrorIsNil)
+	template := state.MachineTemplate{
+		Series:      "precise",
+		Constraints: constraints.MustParse("cores=4"),
+		Jobs:        []state.MachineJob{state.JobHostUnits},
+	}
+	unsupported, err := s.State.PrecheckMachine(template)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(unsupported, gc.HasLen, 0)
+	c.Assert(s.prechecker.precheckInstanceArgs.Series, gc.Equals, "precise")
+	c.Assert(s.prechecker.precheckInstanceArgs.Constraints, gc.DeepEquals, constraints.MustParse("mem=4G cores=4"))
+
+	// No machine is added.
+	machines, err := s.State.AllMachines()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machines, gc.HasLen, 0)
+}
+
+func (s *PrecheckerSuite) TestPrecheckMachineErrors(c *gc.C) {
+	s.prechecker.precheckInstanceError = fmt.Errorf("no instance for you")
+	_, err := s.State.PrecheckMachine(state.MachineTemplate{
+		Series: "precise",
+		Jobs:   []state.MachineJob{state.JobHostUnits},
+	})
+	c.Assert(err, gc.ErrorMatches, "cannot add a new machine: no instance for you")
+
+	_, err = s.State.PrecheckMachine(state.MachineTemplate{
+		Series:     "precise",
+		Jobs:       []state.MachineJob{state.JobHostUnits},
+		InstanceId: "i-manual",
+		Nonce:      "nonce",
+	})
+	c.Assert(err, gc.ErrorMatches, "cannot add a new machine: cannot precheck a machine with an instance id")
+}
+
 func (s *PrecheckerSuite) addOneMachine(c *gc.C, modelCons constraints.Value, placement string) (state.MachineTemplate, error) {
 	_, template, err := s.addMachine(c, modelCons, placement)
 	return template, err
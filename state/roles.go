@@ -0,0 +1,241 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/core/permission"
+)
+
+// roleSubjectPrefix is the prefix of the subject global keys of the
+// permission docs that assign custom roles to model users. The access
+// field of such a doc holds the name of the role.
+const roleSubjectPrefix = "ro#"
+
+// roleDoc is the persistent representation of a custom role.
+type roleDoc struct {
+	Name       string   `bson:"_id"`
+	Access     string   `bson:"access,omitempty"`
+	Operations []string `bson:"operations"`
+}
+
+func (doc roleDoc) role() permission.Role {
+	access := permission.Access(doc.Access)
+	if access == "" {
+		// Roles added before access levels were recorded granted
+		// write access to the model.
+		access = permission.WriteAccess
+	}
+	return permission.Role{
+		Name:       doc.Name,
+		Access:     access,
+		Operations: doc.Operations,
+	}
+}
+
+// roleSubjectKey returns the subject global key of the permission doc
+// assigning a role to the given user.
+func roleSubjectKey(user names.UserTag) string {
+	return roleSubjectPrefix + strings.ToLower(user.Id())
+}
+
+// AddRole adds a custom role to the controller.
+func (st *State) AddRole(role permission.Role) error {
+	if err := role.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+	ops := []txn.Op{{
+		C:      rolesC,
+		Id:     role.Name,
+		Assert: txn.DocMissing,
+		Insert: &roleDoc{
+			Name:       role.Name,
+			Access:     string(role.Access),
+			Operations: role.Operations,
+		},
+	}}
+	err := st.db().RunTransaction(ops)
+	if err == txn.ErrAborted {
+		err = errors.AlreadyExistsf("role %q", role.Name)
+	}
+	return errors.Trace(err)
+}
+
+// Role returns the custom role with the given name.
+func (st *State) Role(name string) (permission.Role, error) {
+	roles, closer := st.db().GetCollection(rolesC)
+	defer closer()
+
+	var doc roleDoc
+	err := roles.FindId(name).One(&doc)
+	if err == mgo.ErrNotFound {
+		return permission.Role{}, errors.NotFoundf("role %q", name)
+	}
+	if err != nil {
+		return permission.Role{}, errors.Annotatef(err, "cannot get role %q", name)
+	}
+	return doc.role(), nil
+}
+
+// AllRoles returns the custom roles defined in the controller, sorted
+// by name.
+func (st *State) AllRoles() ([]permission.Role, error) {
+	roles, closer := st.db().GetCollection(rolesC)
+	defer closer()
+
+	var docs []roleDoc
+	if err := roles.Find(nil).Sort("_id").All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get roles")
+	}
+	result := make([]permission.Role, len(docs))
+	for i, doc := range docs {
+		result[i] = doc.role()
+	}
+	return result, nil
+}
+
+// RemoveRole removes the custom role with the given name. A role that
+// is assigned to any model user cannot be removed.
+func (st *State) RemoveRole(name string) error {
+	permissions, closer := st.db().GetCollection(permissionsC)
+	defer closer()
+
+	buildTxn := func(int) ([]txn.Op, error) {
+		if _, err := st.Role(name); err != nil {
+			return nil, errors.Trace(err)
+		}
+		assigned, err := permissions.Find(bson.D{
+			{"subject-global-key", bson.D{{"$regex", "^" + roleSubjectPrefix}}},
+			{"access", name},
+		}).Count()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if assigned > 0 {
+			return nil, errors.Errorf("role %q is assigned to %d model user(s)", name, assigned)
+		}
+		return []txn.Op{{
+			C:      rolesC,
+			Id:     name,
+			Assert: txn.DocExists,
+			Remove: true,
+		}}, nil
+	}
+	return errors.Annotatef(st.db().Run(buildTxn), "cannot remove role %q", name)
+}
+
+// SetModelUserRole assigns the named custom role to the user on the
+// given model, replacing any role already assigned. The role allows
+// the user to make the calls it lists, in addition to those allowed
+// by the user's access level.
+func (st *State) SetModelUserRole(model names.ModelTag, user names.UserTag, role string) error {
+	objectKey, subjectKey := modelKey(model.Id()), roleSubjectKey(user)
+	buildTxn := func(int) ([]txn.Op, error) {
+		if _, err := st.Role(role); err != nil {
+			return nil, errors.Trace(err)
+		}
+		ops := []txn.Op{{
+			C:      rolesC,
+			Id:     role,
+			Assert: txn.DocExists,
+		}}
+		_, err := st.modelUserRoleName(objectKey, subjectKey)
+		switch {
+		case errors.IsNotFound(err):
+			ops = append(ops, createPermissionOp(objectKey, subjectKey, permission.Access(role)))
+		case err != nil:
+			return nil, errors.Trace(err)
+		default:
+			ops = append(ops, updatePermissionOp(objectKey, subjectKey, permission.Access(role)))
+		}
+		return ops, nil
+	}
+	return errors.Annotatef(st.db().Run(buildTxn), "cannot assign role %q to %q", role, user.Id())
+}
+
+// ModelUserRole returns the custom role assigned to the user on the
+// given model. It returns a NotFound error if no role is assigned.
+func (st *State) ModelUserRole(model names.ModelTag, user names.UserTag) (permission.Role, error) {
+	name, err := st.modelUserRoleName(modelKey(model.Id()), roleSubjectKey(user))
+	if err != nil {
+		return permission.Role{}, errors.Trace(err)
+	}
+	return st.Role(name)
+}
+
+// RemoveModelUserRole removes the custom role assigned to the user on
+// the given model.
+func (st *State) RemoveModelUserRole(model names.ModelTag, user names.UserTag) error {
+	objectKey, subjectKey := modelKey(model.Id()), roleSubjectKey(user)
+	buildTxn := func(int) ([]txn.Op, error) {
+		if _, err := st.modelUserRoleName(objectKey, subjectKey); err != nil {
+			return nil, errors.Trace(err)
+		}
+		return []txn.Op{removePermissionOp(objectKey, subjectKey)}, nil
+	}
+	return errors.Trace(st.db().Run(buildTxn))
+}
+
+// WatchModelUserRole returns a watcher that notifies when the custom
+// role assigned to the user on the given model changes, or is removed.
+// Roles themselves cannot change once added, so the watcher only
+// observes the assignment.
+func (st *State) WatchModelUserRole(model names.ModelTag, user names.UserTag) NotifyWatcher {
+	return newEntityWatcher(st, permissionsC, permissionID(modelKey(model.Id()), roleSubjectKey(user)))
+}
+
+// removeModelUserRoleOp returns an operation that removes any custom
+// role assigned to the user on the model with the given UUID.
+func removeModelUserRoleOp(modelUUID string, user names.UserTag) txn.Op {
+	// Without an assertion, removing a missing doc does nothing.
+	return txn.Op{
+		C:      permissionsC,
+		Id:     permissionID(modelKey(modelUUID), roleSubjectKey(user)),
+		Remove: true,
+	}
+}
+
+// removeUserRolesOps returns operations that remove the custom roles
+// assigned to the user on every model.
+func (st *State) removeUserRolesOps(user names.UserTag) ([]txn.Op, error) {
+	permissions, closer := st.db().GetCollection(permissionsC)
+	defer closer()
+
+	var docs []permissionDoc
+	err := permissions.Find(bson.D{{"subject-global-key", roleSubjectKey(user)}}).All(&docs)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	ops := make([]txn.Op, len(docs))
+	for i, doc := range docs {
+		ops[i] = txn.Op{
+			C:      permissionsC,
+			Id:     doc.ID,
+			Remove: true,
+		}
+	}
+	return ops, nil
+}
+
+func (st *State) modelUserRoleName(objectKey, subjectKey string) (string, error) {
+	permissions, closer := st.db().GetCollection(permissionsC)
+	defer closer()
+
+	var doc permissionDoc
+	err := permissions.FindId(permissionID(objectKey, subjectKey)).One(&doc)
+	if err == mgo.ErrNotFound {
+		return "", errors.NotFoundf("role for %q", strings.TrimPrefix(subjectKey, roleSubjectPrefix))
+	}
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return doc.Access, nil
+}
@@ -0,0 +1,194 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/mgo.v2/bson"
+
+	"github.com/juju/juju/core/permission"
+	"github.com/juju/juju/state"
+	statetesting "github.com/juju/juju/state/testing"
+	"github.com/juju/juju/testing/factory"
+)
+
+type RolesSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&RolesSuite{})
+
+var appTeamRole = permission.Role{
+	Name:       "app-team",
+	Access:     permission.WriteAccess,
+	Operations: []string{permission.RunActionOperation, "Application.Expose"},
+}
+
+func (s *RolesSuite) TestAddRole(c *gc.C) {
+	err := s.State.AddRole(appTeamRole)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.AddRole(appTeamRole)
+	c.Assert(err, jc.Satisfies, errors.IsAlreadyExists)
+	err = s.State.AddRole(permission.Role{Name: "Ops", Access: permission.ReadAccess, Operations: []string{"exec"}})
+	c.Assert(err, gc.ErrorMatches, `role name "Ops" not valid`)
+	err = s.State.AddRole(permission.Role{Name: "ops", Operations: []string{"exec"}})
+	c.Assert(err, gc.ErrorMatches, `role "ops": "" model access not valid`)
+	err = s.State.AddRole(permission.Role{Name: "ops", Access: permission.ReadAccess, Operations: []string{"exec"}})
+	c.Assert(err, jc.ErrorIsNil)
+
+	role, err := s.State.Role("app-team")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(role, jc.DeepEquals, appTeamRole)
+
+	roles, err := s.State.AllRoles()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(roles, jc.DeepEquals, []permission.Role{
+		appTeamRole,
+		{Name: "ops", Access: permission.ReadAccess, Operations: []string{"exec"}},
+	})
+
+	_, err = s.State.Role("missing")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *RolesSuite) TestModelUserRole(c *gc.C) {
+	modelTag := s.Model.ModelTag()
+	user := names.NewUserTag("bob")
+	err := s.State.SetModelUserRole(modelTag, user, "app-team")
+	c.Assert(err, gc.ErrorMatches, `cannot assign role "app-team" to "bob": role "app-team" not found`)
+
+	err = s.State.AddRole(appTeamRole)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.AddRole(permission.Role{Name: "ops", Access: permission.ReadAccess, Operations: []string{"exec"}})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.ModelUserRole(modelTag, user)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	err = s.State.SetModelUserRole(modelTag, user, "app-team")
+	c.Assert(err, jc.ErrorIsNil)
+	role, err := s.State.ModelUserRole(modelTag, names.NewUserTag("Bob"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(role, jc.DeepEquals, appTeamRole)
+
+	err = s.State.SetModelUserRole(modelTag, user, "ops")
+	c.Assert(err, jc.ErrorIsNil)
+	role, err = s.State.ModelUserRole(modelTag, user)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(role.Name, gc.Equals, "ops")
+
+	// Role assignments are not user permissions.
+	users, err := s.Model.Users()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(users, gc.HasLen, 1)
+
+	err = s.State.RemoveModelUserRole(modelTag, user)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.RemoveModelUserRole(modelTag, user)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *RolesSuite) TestRemoveRole(c *gc.C) {
+	err := s.State.AddRole(appTeamRole)
+	c.Assert(err, jc.ErrorIsNil)
+	modelTag := s.Model.ModelTag()
+	user := names.NewUserTag("bob")
+	err = s.State.SetModelUserRole(modelTag, user, "app-team")
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.RemoveRole("app-team")
+	c.Assert(err, gc.ErrorMatches, `cannot remove role "app-team": role "app-team" is assigned to 1 model user\(s\)`)
+
+	err = s.State.RemoveModelUserRole(modelTag, user)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.RemoveRole("app-team")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.RemoveRole("app-team")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *RolesSuite) TestRoleWithoutAccess(c *gc.C) {
+	// Roles added before access levels were recorded grant write
+	// access.
+	roles := s.State.MongoSession().DB("juju").C(state.RolesC)
+	err := roles.Insert(bson.M{"_id": "legacy", "operations": []string{"exec"}})
+	c.Assert(err, jc.ErrorIsNil)
+	role, err := s.State.Role("legacy")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(role.Access, gc.Equals, permission.WriteAccess)
+}
+
+func (s *RolesSuite) TestWatchModelUserRole(c *gc.C) {
+	err := s.State.AddRole(appTeamRole)
+	c.Assert(err, jc.ErrorIsNil)
+	modelTag := s.Model.ModelTag()
+	user := names.NewUserTag("bob")
+
+	w := s.State.WatchModelUserRole(modelTag, user)
+	defer statetesting.AssertStop(c, w)
+	wc := statetesting.NewNotifyWatcherC(c, s.State, w)
+	wc.AssertOneChange()
+
+	err = s.State.SetModelUserRole(modelTag, user, "app-team")
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	// Roles of other users are not reported.
+	err = s.State.SetModelUserRole(modelTag, names.NewUserTag("mary"), "app-team")
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertNoChange()
+
+	err = s.State.RemoveModelUserRole(modelTag, user)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	statetesting.AssertStop(c, w)
+	wc.AssertClosed()
+}
+
+func (s *RolesSuite) TestRemoveUserAccessRemovesRole(c *gc.C) {
+	err := s.State.AddRole(appTeamRole)
+	c.Assert(err, jc.ErrorIsNil)
+	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob"}).UserTag()
+	modelTag := s.Model.ModelTag()
+	err = s.State.SetModelUserRole(modelTag, user, "app-team")
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.RemoveUserAccess(user, modelTag)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.ModelUserRole(modelTag, user)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	// Removing access from a user without a role still works.
+	other := s.Factory.MakeUser(c, &factory.UserParams{Name: "mary"}).UserTag()
+	err = s.State.RemoveUserAccess(other, modelTag)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *RolesSuite) TestRemoveUserRemovesRoles(c *gc.C) {
+	err := s.State.AddRole(appTeamRole)
+	c.Assert(err, jc.ErrorIsNil)
+	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob"}).UserTag()
+	modelTag := s.Model.ModelTag()
+	err = s.State.SetModelUserRole(modelTag, user, "app-team")
+	c.Assert(err, jc.ErrorIsNil)
+	otherModel := s.Factory.MakeModel(c, nil)
+	defer otherModel.Close()
+	otherTag := names.NewModelTag(otherModel.ModelUUID())
+	err = s.State.SetModelUserRole(otherTag, user, "app-team")
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.RemoveUser(user)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.ModelUserRole(modelTag, user)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	_, err = s.State.ModelUserRole(otherTag, user)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	// With the roles gone, the role itself can be removed.
+	err = s.State.RemoveRole("app-team")
+	c.Assert(err, jc.ErrorIsNil)
+}
@@ -242,7 +242,10 @@ func (st *State) RemoveExportingModelDocs() error {
 	return errors.Trace(err)
 }
 
-func (st *State) removeAllModelDocs(modelAssertion bson.D) error {
+// removeAllModelDocs removes all documents for the current model, gated
+// on the model assertion. Any extra ops are added to the final
+// transaction, which removes the model itself.
+func (st *State) removeAllModelDocs(modelAssertion bson.D, extraOps ...txn.Op) error {
 	modelUUID := st.ModelUUID()
 
 	// Remove each collection in its own transaction.
@@ -296,6 +299,16 @@ func (st *State) removeAllModelDocs(modelAssertion bson.D) error {
 		return errors.Trace(err)
 	}
 
+	// Remove the API tokens that log in to the model.
+	ops, err = st.removeInCollectionOps(apiTokensC, bson.D{{"model-uuid", modelUUID}})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	err = st.db().RunTransaction(ops)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
 	// Now remove the model.
 	model, err := st.Model()
 	if err != nil {
@@ -327,6 +340,7 @@ func (st *State) removeAllModelDocs(modelAssertion bson.D) error {
 	if !st.IsController() {
 		ops = append(ops, decHostedModelCountOp())
 	}
+	ops = append(ops, extraOps...)
 	return st.db().RunTransaction(ops)
 }
 
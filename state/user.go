@@ -96,6 +96,7 @@ func (st *State) addUser(name, displayName, password, creator string, secretKey
 		}
 		user.doc.PasswordHash = utils.UserPasswordHash(password, salt)
 		user.doc.PasswordSalt = salt
+		user.doc.PasswordChanged = dateCreated
 	}
 
 	ops := []txn.Op{{
@@ -151,7 +152,18 @@ func (st *State) RemoveUser(tag names.UserTag) error {
 			Assert: txn.DocExists,
 			Update: bson.M{"$set": bson.M{"deleted": true}},
 		}}
-		return ops, nil
+		// Custom roles assigned to the user must not outlive them,
+		// or they would apply to a new user of the same name.
+		roleOps, err := st.removeUserRolesOps(tag)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		tokenOps, err := st.removeUserAPITokensOps(tag)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		ops = append(ops, roleOps...)
+		return append(ops, tokenOps...), nil
 	}
 	return st.db().Run(buildTxn)
 }
@@ -159,13 +171,14 @@ func (st *State) RemoveUser(tag names.UserTag) error {
 func createInitialUserOps(controllerUUID string, user names.UserTag, password, salt string, dateCreated time.Time) []txn.Op {
 	lowercaseName := strings.ToLower(user.Name())
 	doc := userDoc{
-		DocID:        lowercaseName,
-		Name:         user.Name(),
-		DisplayName:  user.Name(),
-		PasswordHash: utils.UserPasswordHash(password, salt),
-		PasswordSalt: salt,
-		CreatedBy:    user.Name(),
-		DateCreated:  dateCreated,
+		DocID:           lowercaseName,
+		Name:            user.Name(),
+		DisplayName:     user.Name(),
+		PasswordHash:    utils.UserPasswordHash(password, salt),
+		PasswordSalt:    salt,
+		PasswordChanged: dateCreated,
+		CreatedBy:       user.Name(),
+		DateCreated:     dateCreated,
 	}
 	ops := []txn.Op{{
 		C:      usersC,
@@ -284,6 +297,19 @@ type userDoc struct {
 	PasswordSalt string    `bson:"passwordsalt"`
 	CreatedBy    string    `bson:"createdby"`
 	DateCreated  time.Time `bson:"datecreated"`
+
+	// The following fields hold the user's multi-factor authentication
+	// enrolment, if any; see usermfa.go.
+	TOTPSecret        string   `bson:"totp-secret,omitempty"`
+	TOTPPendingSecret string   `bson:"totp-pending-secret,omitempty"`
+	TOTPLastStep      int64    `bson:"totp-last-step,omitempty"`
+	RecoveryCodes     []string `bson:"mfa-recovery-codes,omitempty"`
+
+	// The following fields hold the state used to enforce the
+	// controller's password policy; see userlockout.go.
+	PasswordChanged time.Time `bson:"password-changed,omitempty"`
+	FailedLogins    int       `bson:"failed-logins,omitempty"`
+	LockedUntil     time.Time `bson:"locked-until,omitempty"`
 }
 
 type userLastLoginDoc struct {
@@ -414,21 +440,28 @@ func (u *User) SetPassword(password string) error {
 
 // SetPasswordHash stores the hash and the salt of the
 // password. If the User has a secret key set then it
-// will be cleared.
+// will be cleared, as will any failed logins and lockout.
 func (u *User) SetPasswordHash(pwHash string, pwSalt string) error {
 	if err := u.ensureNotDeleted(); err != nil {
 		// If we do get a late set of the password this is fine b/c we have an
 		// explicit check before login.
 		return errors.Annotate(err, "cannot set password hash")
 	}
-	update := bson.D{{"$set", bson.D{
-		{"passwordhash", pwHash},
-		{"passwordsalt", pwSalt},
-	}}}
+	now := u.st.nowToTheSecond()
+	unset := bson.D{
+		{"failed-logins", ""},
+		{"locked-until", ""},
+	}
 	if u.doc.SecretKey != nil {
-		update = append(update,
-			bson.DocElem{"$unset", bson.D{{"secretkey", ""}}},
-		)
+		unset = append(unset, bson.DocElem{"secretkey", ""})
+	}
+	update := bson.D{
+		{"$set", bson.D{
+			{"passwordhash", pwHash},
+			{"passwordsalt", pwSalt},
+			{"password-changed", now},
+		}},
+		{"$unset", unset},
 	}
 	lowercaseName := strings.ToLower(u.Name())
 	ops := []txn.Op{{
@@ -443,6 +476,9 @@ func (u *User) SetPasswordHash(pwHash string, pwSalt string) error {
 	u.doc.PasswordHash = pwHash
 	u.doc.PasswordSalt = pwSalt
 	u.doc.SecretKey = nil
+	u.doc.PasswordChanged = now
+	u.doc.FailedLogins = 0
+	u.doc.LockedUntil = time.Time{}
 	return nil
 }
 
@@ -488,7 +524,8 @@ func (u *User) Disable() error {
 	return errors.Annotatef(u.setDeactivated(true), "cannot disable user %q", u.Name())
 }
 
-// Enable reactivates the user, setting disabled to false.
+// Enable reactivates the user, setting disabled to false. Any lockout
+// due to failed logins is also cleared.
 func (u *User) Enable() error {
 	if err := u.ensureNotDeleted(); err != nil {
 		return errors.Annotate(err, "cannot enable")
@@ -498,11 +535,18 @@ func (u *User) Enable() error {
 
 func (u *User) setDeactivated(value bool) error {
 	lowercaseName := strings.ToLower(u.Name())
+	update := bson.D{{"$set", bson.D{{"deactivated", value}}}}
+	if !value {
+		update = append(update, bson.DocElem{"$unset", bson.D{
+			{"failed-logins", ""},
+			{"locked-until", ""},
+		}})
+	}
 	ops := []txn.Op{{
 		C:      usersC,
 		Id:     lowercaseName,
 		Assert: txn.DocExists,
-		Update: bson.D{{"$set", bson.D{{"deactivated", value}}}},
+		Update: update,
 	}}
 	if err := u.st.db().RunTransaction(ops); err != nil {
 		if err == txn.ErrAborted {
@@ -511,6 +555,10 @@ func (u *User) setDeactivated(value bool) error {
 		return err
 	}
 	u.doc.Deactivated = value
+	if !value {
+		u.doc.FailedLogins = 0
+		u.doc.LockedUntil = time.Time{}
+	}
 	return nil
 }
 
@@ -0,0 +1,229 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	jujutxn "github.com/juju/txn"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// FailedLogins returns the number of consecutive failed password
+// logins for the user since they last logged in with their password.
+func (u *User) FailedLogins() int {
+	return u.doc.FailedLogins
+}
+
+// LockedUntil returns the time at which the user's lockout, due to
+// failed logins, ends. The bool result reports whether the user is
+// currently locked out.
+func (u *User) LockedUntil() (time.Time, bool) {
+	until := u.doc.LockedUntil
+	if until.IsZero() {
+		return until, false
+	}
+	return until, u.st.clock().Now().Before(until)
+}
+
+// RecordFailedLogin records a failed password login for the user. Once
+// the controller's login-max-failed-attempts is reached, the user is
+// locked out for login-lockout-duration, doubling with each further
+// failed login up to login-max-lockout-duration. Failed logins are not
+// recorded if the controller does not lock users out.
+func (u *User) RecordFailedLogin() error {
+	cfg, err := u.st.ControllerConfig()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	maxAttempts := cfg.LoginMaxFailedAttempts()
+	if maxAttempts == 0 {
+		return nil
+	}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := u.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		if err := u.ensureNotDeleted(); err != nil {
+			return nil, errors.Trace(err)
+		}
+		failed := u.doc.FailedLogins + 1
+		set := bson.D{{"failed-logins", failed}}
+		if failed >= maxAttempts {
+			lockout := lockoutDuration(
+				failed, maxAttempts,
+				cfg.LoginLockoutDuration(), cfg.LoginMaxLockoutDuration(),
+			)
+			set = append(set, bson.DocElem{"locked-until", u.st.nowToTheSecond().Add(lockout)})
+		}
+		var assert bson.D
+		if u.doc.FailedLogins == 0 {
+			assert = bson.D{{"failed-logins", bson.D{{"$exists", false}}}}
+		} else {
+			assert = bson.D{{"failed-logins", u.doc.FailedLogins}}
+		}
+		return []txn.Op{{
+			C:      usersC,
+			Id:     strings.ToLower(u.Name()),
+			Assert: assert,
+			Update: bson.D{{"$set", set}},
+		}}, nil
+	}
+	if err := u.st.db().Run(buildTxn); err != nil {
+		return errors.Annotatef(err, "cannot record failed login for user %q", u.Name())
+	}
+	return errors.Trace(u.Refresh())
+}
+
+// ResetFailedLogins clears the user's failed logins, and any lockout.
+// It is called when the user logs in with their password.
+func (u *User) ResetFailedLogins() error {
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := u.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		if u.doc.FailedLogins == 0 && u.doc.LockedUntil.IsZero() {
+			return nil, jujutxn.ErrNoOperations
+		}
+		return []txn.Op{{
+			C:      usersC,
+			Id:     strings.ToLower(u.Name()),
+			Assert: txn.DocExists,
+			Update: bson.D{{"$unset", bson.D{
+				{"failed-logins", ""},
+				{"locked-until", ""},
+			}}},
+		}}, nil
+	}
+	if err := u.st.db().Run(buildTxn); err != nil {
+		return errors.Annotatef(err, "cannot reset failed logins for user %q", u.Name())
+	}
+	u.doc.FailedLogins = 0
+	u.doc.LockedUntil = time.Time{}
+	return nil
+}
+
+// PasswordExpires returns the time at which the user's password expires,
+// according to the controller's password-max-age. The bool result is
+// false if passwords do not expire.
+func (u *User) PasswordExpires() (time.Time, bool, error) {
+	cfg, err := u.st.ControllerConfig()
+	if err != nil {
+		return time.Time{}, false, errors.Trace(err)
+	}
+	maxAge := cfg.PasswordMaxAge()
+	if maxAge == 0 {
+		return time.Time{}, false, nil
+	}
+	// Passwords set before their changes were recorded are treated
+	// as being set when password-max-age was enabled, so enabling the
+	// policy does not expire existing passwords straight away.
+	changed := u.doc.PasswordChanged
+	if changed.IsZero() {
+		since, err := u.st.passwordMaxAgeSince()
+		if err != nil {
+			return time.Time{}, false, errors.Trace(err)
+		}
+		if since.IsZero() {
+			return time.Time{}, false, nil
+		}
+		changed = since
+	}
+	return changed.Add(maxAge).UTC(), true, nil
+}
+
+// PasswordExpired reports whether the user's password has expired, and
+// so must be changed before the user can do anything else.
+func (u *User) PasswordExpired() (bool, error) {
+	expires, ok, err := u.PasswordExpires()
+	if err != nil || !ok {
+		return false, errors.Trace(err)
+	}
+	return !u.st.clock().Now().Before(expires), nil
+}
+
+const passwordPolicyKey = "passwordPolicy"
+
+// passwordPolicyDoc records when the controller's password-max-age was
+// enabled.
+type passwordPolicyDoc struct {
+	MaxAgeSince time.Time `bson:"max-age-since"`
+}
+
+// passwordMaxAgeSince returns when password-max-age was enabled, or
+// the zero time if that was not recorded.
+func (st *State) passwordMaxAgeSince() (time.Time, error) {
+	controllers, closer := st.db().GetCollection(controllersC)
+	defer closer()
+
+	var doc passwordPolicyDoc
+	err := controllers.FindId(passwordPolicyKey).One(&doc)
+	if err == mgo.ErrNotFound {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, errors.Annotate(err, "cannot read password policy")
+	}
+	return doc.MaxAgeSince.UTC(), nil
+}
+
+// passwordMaxAgeOps returns the operations needed to record when
+// password-max-age is enabled, and to forget it when it is disabled.
+func (st *State) passwordMaxAgeOps(oldMaxAge, newMaxAge time.Duration) ([]txn.Op, error) {
+	switch {
+	case oldMaxAge == 0 && newMaxAge > 0:
+		since, err := st.passwordMaxAgeSince()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		now := st.nowToTheSecond()
+		if since.IsZero() {
+			return []txn.Op{passwordPolicyInsertOp(now)}, nil
+		}
+		return []txn.Op{{
+			C:      controllersC,
+			Id:     passwordPolicyKey,
+			Assert: txn.DocExists,
+			Update: bson.D{{"$set", bson.D{{"max-age-since", now}}}},
+		}}, nil
+	case oldMaxAge > 0 && newMaxAge == 0:
+		return []txn.Op{{
+			C:      controllersC,
+			Id:     passwordPolicyKey,
+			Remove: true,
+		}}, nil
+	}
+	return nil, nil
+}
+
+func passwordPolicyInsertOp(since time.Time) txn.Op {
+	return txn.Op{
+		C:      controllersC,
+		Id:     passwordPolicyKey,
+		Assert: txn.DocMissing,
+		Insert: &passwordPolicyDoc{MaxAgeSince: since},
+	}
+}
+
+// lockoutDuration returns how long a user is locked out for after the
+// given number of consecutive failed logins: base once maxAttempts is
+// reached, doubling with each further failure, up to max.
+func lockoutDuration(failed, maxAttempts int, base, max time.Duration) time.Duration {
+	lockout := base
+	for i := maxAttempts; i < failed && lockout < max; i++ {
+		lockout *= 2
+	}
+	if lockout > max {
+		lockout = max
+	}
+	return lockout
+}
@@ -0,0 +1,222 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"time"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/mgo.v2/bson"
+
+	"github.com/juju/juju/controller"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing/factory"
+)
+
+type UserLockoutSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&UserLockoutSuite{})
+
+func (s *UserLockoutSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	err := s.State.UpdateControllerConfig(map[string]interface{}{
+		controller.LoginMaxFailedAttempts:  3,
+		controller.LoginLockoutDuration:    "1m",
+		controller.LoginMaxLockoutDuration: "3m",
+	}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *UserLockoutSuite) TestRecordFailedLogin(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob"})
+	for i := 0; i < 2; i++ {
+		c.Assert(user.RecordFailedLogin(), jc.ErrorIsNil)
+	}
+	c.Assert(user.FailedLogins(), gc.Equals, 2)
+	_, locked := user.LockedUntil()
+	c.Assert(locked, jc.IsFalse)
+
+	now := s.Clock.Now().Round(time.Second).UTC()
+	c.Assert(user.RecordFailedLogin(), jc.ErrorIsNil)
+	until, locked := user.LockedUntil()
+	c.Assert(locked, jc.IsTrue)
+	c.Assert(until, gc.Equals, now.Add(time.Minute))
+
+	// Further failures double the lockout, up to the maximum.
+	c.Assert(user.RecordFailedLogin(), jc.ErrorIsNil)
+	until, _ = user.LockedUntil()
+	c.Assert(until, gc.Equals, now.Add(2*time.Minute))
+	c.Assert(user.RecordFailedLogin(), jc.ErrorIsNil)
+	until, _ = user.LockedUntil()
+	c.Assert(until, gc.Equals, now.Add(3*time.Minute))
+
+	user, err := s.State.User(user.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(user.FailedLogins(), gc.Equals, 5)
+
+	s.Clock.Advance(3 * time.Minute)
+	_, locked = user.LockedUntil()
+	c.Assert(locked, jc.IsFalse)
+}
+
+func (s *UserLockoutSuite) TestRecordFailedLoginNoLockout(c *gc.C) {
+	err := s.State.UpdateControllerConfig(nil, []string{controller.LoginMaxFailedAttempts})
+	c.Assert(err, jc.ErrorIsNil)
+	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob"})
+	c.Assert(user.RecordFailedLogin(), jc.ErrorIsNil)
+	c.Assert(user.FailedLogins(), gc.Equals, 0)
+}
+
+func (s *UserLockoutSuite) TestResetFailedLogins(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob"})
+	for i := 0; i < 3; i++ {
+		c.Assert(user.RecordFailedLogin(), jc.ErrorIsNil)
+	}
+	c.Assert(user.ResetFailedLogins(), jc.ErrorIsNil)
+	user, err := s.State.User(user.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(user.FailedLogins(), gc.Equals, 0)
+	_, locked := user.LockedUntil()
+	c.Assert(locked, jc.IsFalse)
+
+	// Resetting again is a no-op.
+	c.Assert(user.ResetFailedLogins(), jc.ErrorIsNil)
+}
+
+func (s *UserLockoutSuite) TestSetPasswordAndEnableClearLockout(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob"})
+	for i := 0; i < 3; i++ {
+		c.Assert(user.RecordFailedLogin(), jc.ErrorIsNil)
+	}
+	c.Assert(user.SetPassword("new-password"), jc.ErrorIsNil)
+	_, locked := user.LockedUntil()
+	c.Assert(locked, jc.IsFalse)
+
+	for i := 0; i < 3; i++ {
+		c.Assert(user.RecordFailedLogin(), jc.ErrorIsNil)
+	}
+	c.Assert(user.Enable(), jc.ErrorIsNil)
+	user, err := s.State.User(user.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(user.FailedLogins(), gc.Equals, 0)
+	_, locked = user.LockedUntil()
+	c.Assert(locked, jc.IsFalse)
+}
+
+func (s *UserLockoutSuite) TestPasswordExpires(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob"})
+	_, ok, err := user.PasswordExpires()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ok, jc.IsFalse)
+
+	err = s.State.UpdateControllerConfig(map[string]interface{}{
+		controller.PasswordMaxAge: "720h",
+	}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	now := s.Clock.Now().Round(time.Second).UTC()
+	c.Assert(user.SetPassword("new-password"), jc.ErrorIsNil)
+	expires, ok, err := user.PasswordExpires()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(expires, gc.Equals, now.Add(720*time.Hour))
+
+	expired, err := user.PasswordExpired()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(expired, jc.IsFalse)
+	s.Clock.Advance(720 * time.Hour)
+	expired, err = user.PasswordExpired()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(expired, jc.IsTrue)
+}
+
+func (s *UserLockoutSuite) TestPasswordExpiresFromPolicyEnabled(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob"})
+	// Passwords set before their changes were recorded have no
+	// change time.
+	users := s.State.MongoSession().DB("juju").C(state.UsersC)
+	err := users.UpdateId("bob", bson.D{{"$unset", bson.D{{"password-changed", ""}}}})
+	c.Assert(err, jc.ErrorIsNil)
+	user, err = s.State.User(user.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.Clock.Advance(time.Hour)
+	enabled := s.Clock.Now().Round(time.Second).UTC()
+	err = s.State.UpdateControllerConfig(map[string]interface{}{
+		controller.PasswordMaxAge: "720h",
+	}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	expires, ok, err := user.PasswordExpires()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(expires, gc.Equals, enabled.Add(720*time.Hour))
+
+	// Changing the max age keeps the time it was enabled.
+	s.Clock.Advance(time.Hour)
+	err = s.State.UpdateControllerConfig(map[string]interface{}{
+		controller.PasswordMaxAge: "24h",
+	}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	expires, ok, err = user.PasswordExpires()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(expires, gc.Equals, enabled.Add(24*time.Hour))
+
+	// Disabling it forgets the time.
+	err = s.State.UpdateControllerConfig(nil, []string{controller.PasswordMaxAge})
+	c.Assert(err, jc.ErrorIsNil)
+	_, ok, err = user.PasswordExpires()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ok, jc.IsFalse)
+	s.Clock.Advance(time.Hour)
+	reenabled := s.Clock.Now().Round(time.Second).UTC()
+	err = s.State.UpdateControllerConfig(map[string]interface{}{
+		controller.PasswordMaxAge: "24h",
+	}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	expires, ok, err = user.PasswordExpires()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(expires, gc.Equals, reenabled.Add(24*time.Hour))
+}
+
+func (s *UserLockoutSuite) TestPasswordExpiresFromUserCreation(c *gc.C) {
+	err := s.State.UpdateControllerConfig(map[string]interface{}{
+		controller.PasswordMaxAge: "720h",
+	}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.Clock.Advance(time.Hour)
+	created := s.Clock.Now().Round(time.Second).UTC()
+	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob"})
+	expires, ok, err := user.PasswordExpires()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(expires, gc.Equals, created.Add(720*time.Hour))
+}
+
+type lockoutDurationSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&lockoutDurationSuite{})
+
+func (*lockoutDurationSuite) TestLockoutDuration(c *gc.C) {
+	for i, test := range []struct {
+		failed   int
+		expected time.Duration
+	}{
+		{5, time.Minute},
+		{6, 2 * time.Minute},
+		{7, 4 * time.Minute},
+		{12, 128 * time.Minute},
+		{13, 4 * time.Hour},
+		{1000, 4 * time.Hour},
+	} {
+		c.Logf("test %d: %d failed logins", i, test.failed)
+		c.Check(state.LockoutDuration(test.failed, 5, time.Minute, 4*time.Hour), gc.Equals, test.expected)
+	}
+}
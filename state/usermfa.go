@@ -0,0 +1,214 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/juju/errors"
+	jujutxn "github.com/juju/txn"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/core/totp"
+)
+
+// MFARecoveryCodeCount is the number of recovery codes issued when a
+// user enrols for multi-factor authentication.
+const MFARecoveryCodeCount = 10
+
+// MFAEnabled returns whether the user must present a one-time code
+// from their authenticator, or a recovery code, to log in with a
+// password.
+func (u *User) MFAEnabled() bool {
+	return u.doc.TOTPSecret != ""
+}
+
+// MFARecoveryCodesRemaining returns the number of unused recovery codes
+// the user has.
+func (u *User) MFARecoveryCodesRemaining() int {
+	return len(u.doc.RecoveryCodes)
+}
+
+// BeginMFAEnrolment generates a new shared secret for the user's
+// authenticator and returns it. Multi-factor authentication is not
+// enforced until the enrolment is confirmed with a code generated from
+// the secret, by calling ConfirmMFAEnrolment.
+func (u *User) BeginMFAEnrolment() (string, error) {
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if err := u.ensureNotDeleted(); err != nil {
+			return nil, errors.Trace(err)
+		}
+		if u.MFAEnabled() {
+			return nil, errors.AlreadyExistsf("multi-factor authentication for user %q", u.Name())
+		}
+		return []txn.Op{{
+			C:      usersC,
+			Id:     strings.ToLower(u.Name()),
+			Assert: bson.D{{"totp-secret", bson.D{{"$exists", false}}}},
+			Update: bson.D{{"$set", bson.D{{"totp-pending-secret", secret}}}},
+		}}, nil
+	}
+	if err := u.st.db().Run(buildTxn); err != nil {
+		return "", errors.Annotatef(err, "cannot enrol user %q for multi-factor authentication", u.Name())
+	}
+	u.doc.TOTPPendingSecret = secret
+	return secret, nil
+}
+
+// ConfirmMFAEnrolment checks the code against the secret returned by
+// BeginMFAEnrolment and, if it is valid, enables multi-factor
+// authentication for the user. It returns a new set of single-use
+// recovery codes, which may be used instead of a one-time code if the
+// user loses their authenticator. Only hashes of the recovery codes are
+// stored, so they cannot be retrieved again.
+func (u *User) ConfirmMFAEnrolment(code string) ([]string, error) {
+	var codes []string
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if err := u.ensureNotDeleted(); err != nil {
+			return nil, errors.Trace(err)
+		}
+		if u.MFAEnabled() {
+			return nil, errors.AlreadyExistsf("multi-factor authentication for user %q", u.Name())
+		}
+		pending := u.doc.TOTPPendingSecret
+		if pending == "" {
+			return nil, errors.NotFoundf("pending multi-factor authentication enrolment")
+		}
+		step, err := totp.Validate(pending, code, u.st.clock().Now())
+		if err != nil {
+			return nil, errors.Unauthorizedf("invalid multi-factor authentication code")
+		}
+		var hashes []string
+		codes, hashes, err = generateRecoveryCodes(MFARecoveryCodeCount)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return []txn.Op{{
+			C:      usersC,
+			Id:     strings.ToLower(u.Name()),
+			Assert: bson.D{{"totp-pending-secret", pending}},
+			Update: bson.D{
+				{"$set", bson.D{
+					{"totp-secret", pending},
+					{"totp-last-step", step},
+					{"mfa-recovery-codes", hashes},
+				}},
+				{"$unset", bson.D{{"totp-pending-secret", ""}}},
+			},
+		}}, nil
+	}
+	if err := u.st.db().Run(buildTxn); err != nil {
+		return nil, errors.Annotatef(err, "cannot enable multi-factor authentication for user %q", u.Name())
+	}
+	return codes, errors.Trace(u.Refresh())
+}
+
+// CheckMFACode checks a one-time code from the user's authenticator, or
+// one of their recovery codes. Each one-time code, and each recovery
+// code, is only accepted once. An error satisfying errors.IsUnauthorized
+// is returned if the code is not accepted.
+func (u *User) CheckMFACode(code string) error {
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if err := u.ensureNotDeleted(); err != nil {
+			return nil, errors.Trace(err)
+		}
+		if !u.MFAEnabled() {
+			return nil, errors.NotFoundf("multi-factor authentication enrolment for user %q", u.Name())
+		}
+		id := strings.ToLower(u.Name())
+		step, err := totp.Validate(u.doc.TOTPSecret, code, u.st.clock().Now())
+		if err == nil {
+			if step <= u.doc.TOTPLastStep {
+				return nil, errors.Unauthorizedf("multi-factor authentication code already used")
+			}
+			return []txn.Op{{
+				C:      usersC,
+				Id:     id,
+				Assert: bson.D{{"totp-last-step", u.doc.TOTPLastStep}},
+				Update: bson.D{{"$set", bson.D{{"totp-last-step", step}}}},
+			}}, nil
+		}
+		hash := recoveryCodeHash(code)
+		for _, stored := range u.doc.RecoveryCodes {
+			if stored != hash {
+				continue
+			}
+			return []txn.Op{{
+				C:      usersC,
+				Id:     id,
+				Assert: bson.D{{"mfa-recovery-codes", hash}},
+				Update: bson.D{{"$pull", bson.D{{"mfa-recovery-codes", hash}}}},
+			}}, nil
+		}
+		return nil, errors.Unauthorizedf("invalid multi-factor authentication code")
+	}
+	if err := u.st.db().Run(buildTxn); err != nil {
+		if errors.IsUnauthorized(err) {
+			return err
+		}
+		return errors.Annotatef(err, "cannot check multi-factor authentication code for user %q", u.Name())
+	}
+	return errors.Trace(u.Refresh())
+}
+
+// ResetMFA removes the user's multi-factor authentication enrolment,
+// including any pending enrolment and unused recovery codes. Once reset,
+// the user can log in with their password alone.
+func (u *User) ResetMFA() error {
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if err := u.ensureNotDeleted(); err != nil {
+			return nil, errors.Trace(err)
+		}
+		if !u.MFAEnabled() && u.doc.TOTPPendingSecret == "" {
+			return nil, jujutxn.ErrNoOperations
+		}
+		return []txn.Op{{
+			C:      usersC,
+			Id:     strings.ToLower(u.Name()),
+			Assert: txn.DocExists,
+			Update: bson.D{{"$unset", bson.D{
+				{"totp-secret", ""},
+				{"totp-pending-secret", ""},
+				{"totp-last-step", ""},
+				{"mfa-recovery-codes", ""},
+			}}},
+		}}, nil
+	}
+	if err := u.st.db().Run(buildTxn); err != nil {
+		return errors.Annotatef(err, "cannot reset multi-factor authentication for user %q", u.Name())
+	}
+	return errors.Trace(u.Refresh())
+}
+
+// generateRecoveryCodes returns n random recovery codes, and their
+// hashes for storing.
+func generateRecoveryCodes(n int) (codes, hashes []string, _ error) {
+	for i := 0; i < n; i++ {
+		var buf [5]byte
+		if _, err := rand.Read(buf[:]); err != nil {
+			return nil, nil, errors.Annotate(err, "cannot generate recovery code")
+		}
+		raw := hex.EncodeToString(buf[:])
+		codes = append(codes, fmt.Sprintf("%s-%s", raw[:5], raw[5:]))
+		hashes = append(hashes, recoveryCodeHash(raw))
+	}
+	return codes, hashes, nil
+}
+
+// recoveryCodeHash returns the hash stored for a recovery code, ignoring
+// case and any separators.
+func recoveryCodeHash(code string) string {
+	code = strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,116 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/totp"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing/factory"
+)
+
+type UserMFASuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&UserMFASuite{})
+
+func (s *UserMFASuite) enrol(c *gc.C, user *state.User) (string, []string) {
+	secret, err := user.BeginMFAEnrolment()
+	c.Assert(err, jc.ErrorIsNil)
+	code, err := totp.Code(secret, s.Clock.Now())
+	c.Assert(err, jc.ErrorIsNil)
+	recovery, err := user.ConfirmMFAEnrolment(code)
+	c.Assert(err, jc.ErrorIsNil)
+	return secret, recovery
+}
+
+func (s *UserMFASuite) TestEnrol(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob"})
+	c.Assert(user.MFAEnabled(), jc.IsFalse)
+
+	secret, err := user.BeginMFAEnrolment()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(user.MFAEnabled(), jc.IsFalse)
+
+	_, err = user.ConfirmMFAEnrolment("000000")
+	c.Assert(errors.Cause(err), jc.Satisfies, errors.IsUnauthorized)
+	c.Assert(user.MFAEnabled(), jc.IsFalse)
+
+	code, err := totp.Code(secret, s.Clock.Now())
+	c.Assert(err, jc.ErrorIsNil)
+	recovery, err := user.ConfirmMFAEnrolment(code)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(recovery, gc.HasLen, state.MFARecoveryCodeCount)
+	c.Assert(recovery[0], gc.Matches, "[0-9a-f]{5}-[0-9a-f]{5}")
+
+	user, err = s.State.User(user.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(user.MFAEnabled(), jc.IsTrue)
+	c.Assert(user.MFARecoveryCodesRemaining(), gc.Equals, state.MFARecoveryCodeCount)
+
+	_, err = user.BeginMFAEnrolment()
+	c.Assert(err, gc.ErrorMatches, `cannot enrol user "bob" for multi-factor authentication: multi-factor authentication for user "bob" already exists`)
+}
+
+func (s *UserMFASuite) TestConfirmWithoutBegin(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob"})
+	_, err := user.ConfirmMFAEnrolment("000000")
+	c.Assert(errors.Cause(err), jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *UserMFASuite) TestCheckMFACode(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob"})
+	secret, _ := s.enrol(c, user)
+
+	// The code used to confirm enrolment cannot be used again.
+	code, err := totp.Code(secret, s.Clock.Now())
+	c.Assert(err, jc.ErrorIsNil)
+	err = user.CheckMFACode(code)
+	c.Assert(err, gc.ErrorMatches, "multi-factor authentication code already used")
+
+	s.Clock.Advance(totp.Period)
+	code, err = totp.Code(secret, s.Clock.Now())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(user.CheckMFACode(code), jc.ErrorIsNil)
+	c.Assert(user.CheckMFACode(code), jc.Satisfies, errors.IsUnauthorized)
+
+	err = user.CheckMFACode("123456")
+	c.Assert(err, gc.ErrorMatches, "invalid multi-factor authentication code")
+}
+
+func (s *UserMFASuite) TestCheckMFACodeRecovery(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob"})
+	_, recovery := s.enrol(c, user)
+
+	c.Assert(user.CheckMFACode(recovery[3]), jc.ErrorIsNil)
+	c.Assert(user.MFARecoveryCodesRemaining(), gc.Equals, state.MFARecoveryCodeCount-1)
+	c.Assert(user.CheckMFACode(recovery[3]), jc.Satisfies, errors.IsUnauthorized)
+
+	// Recovery codes are accepted without the separator.
+	c.Assert(user.CheckMFACode(recovery[4][:5]+recovery[4][6:]), jc.ErrorIsNil)
+}
+
+func (s *UserMFASuite) TestCheckMFACodeNotEnrolled(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob"})
+	err := user.CheckMFACode("123456")
+	c.Assert(errors.Cause(err), jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *UserMFASuite) TestResetMFA(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob"})
+	s.enrol(c, user)
+
+	c.Assert(user.ResetMFA(), jc.ErrorIsNil)
+	c.Assert(user.MFAEnabled(), jc.IsFalse)
+	c.Assert(user.MFARecoveryCodesRemaining(), gc.Equals, 0)
+
+	// Resetting again is a no-op, and the user may enrol again.
+	c.Assert(user.ResetMFA(), jc.ErrorIsNil)
+	s.enrol(c, user)
+	c.Assert(user.MFAEnabled(), jc.IsTrue)
+}
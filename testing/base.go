@@ -51,6 +51,7 @@ func (s *JujuOSEnvSuite) SetUpTest(c *gc.C) {
 		osenv.JujuXDGDataHomeEnvKey,
 		osenv.JujuControllerEnvKey,
 		osenv.JujuModelEnvKey,
+		osenv.JujuAPITokenEnvKey,
 		osenv.JujuLoggingConfigEnvKey,
 		osenv.JujuFeatureFlagEnvKey,
 		osenv.JujuFeatures,
@@ -4,6 +4,9 @@
 package agentconfigupdater
 
 import (
+	"bytes"
+	"crypto/x509"
+
 	"github.com/juju/errors"
 	"github.com/juju/pubsub"
 	"github.com/juju/worker/v2"
@@ -13,6 +16,7 @@ import (
 	apiagent "github.com/juju/juju/api/agent"
 	"github.com/juju/juju/api/base"
 	"github.com/juju/juju/mongo"
+	"github.com/juju/juju/pki"
 	jworker "github.com/juju/juju/worker"
 )
 
@@ -103,6 +107,20 @@ func Manifold(config ManifoldConfig) dependency.Manifold {
 			if err != nil {
 				return nil, errors.Annotate(err, "getting state serving info")
 			}
+
+			// The CA certificate changes, along with the CA private key in
+			// the state serving info, when the controller's CA is rotated.
+			// The first certificate must match the key, or the agent will
+			// be unable to issue its certificate when it restarts, so make
+			// sure we didn't read them either side of a rotation step.
+			configCACert, _ := controllerConfig.CACert()
+			caCertChanged := agent.CurrentConfig().CACert() != configCACert
+			if caCertChanged {
+				if err := checkCAKey(configCACert, info.CAPrivateKey); err != nil {
+					return nil, errors.Annotate(err, "checking CA certificate against CA private key")
+				}
+			}
+
 			err = agent.ChangeConfig(func(config coreagent.ConfigSetter) error {
 				existing, hasInfo := config.StateServingInfo()
 				if hasInfo {
@@ -117,6 +135,10 @@ func Manifold(config ManifoldConfig) dependency.Manifold {
 					info.PrivateKey = existing.PrivateKey
 				}
 				config.SetStateServingInfo(info)
+				if caCertChanged {
+					logger.Debugf("setting agent config CA certificate")
+					config.SetCACert(configCACert)
+				}
 				if mongoProfileChanged {
 					logger.Debugf("setting agent config mongo memory profile: %q => %q", agentsMongoMemoryProfile, configMongoMemoryProfile)
 					config.SetMongoMemoryProfile(configMongoMemoryProfile)
@@ -137,6 +159,9 @@ func Manifold(config ManifoldConfig) dependency.Manifold {
 			} else if jujuDBSnapChannelChanged {
 				logger.Infof("restarting agent for new mongo snap channel")
 				return nil, jworker.ErrRestartAgent
+			} else if caCertChanged {
+				logger.Infof("restarting agent for new CA certificate")
+				return nil, jworker.ErrRestartAgent
 			}
 
 			// Only get the hub if we are a controller and we haven't updated
@@ -152,8 +177,33 @@ func Manifold(config ManifoldConfig) dependency.Manifold {
 				Hub:               hub,
 				MongoProfile:      configMongoMemoryProfile,
 				JujuDBSnapChannel: configJujuDBSnapChannel,
+				CACert:            configCACert,
 				Logger:            config.Logger,
 			})
 		},
 	}
 }
+
+// checkCAKey returns an error if the first certificate in the PEM encoded
+// CA certificates is not for the PEM encoded CA private key.
+func checkCAKey(caCert, caKey string) error {
+	certs, _, err := pki.UnmarshalPemData([]byte(caCert))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	_, signers, err := pki.UnmarshalPemData([]byte(caKey))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(certs) == 0 || len(signers) != 1 {
+		return errors.New("expected CA certificate and private key")
+	}
+	publicKey, err := x509.MarshalPKIXPublicKey(signers[0].Public())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !bytes.Equal(publicKey, certs[0].RawSubjectPublicKeyInfo) {
+		return errors.New("CA private key does not match CA certificate")
+	}
+	return nil
+}
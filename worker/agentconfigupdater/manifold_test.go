@@ -20,6 +20,8 @@ import (
 	"github.com/juju/juju/controller"
 	"github.com/juju/juju/core/model"
 	"github.com/juju/juju/mongo"
+	"github.com/juju/juju/pki"
+	pkitest "github.com/juju/juju/pki/test"
 	"github.com/juju/juju/testing"
 	jworker "github.com/juju/juju/worker"
 	"github.com/juju/juju/worker/agentconfigupdater"
@@ -295,6 +297,75 @@ func (s *AgentConfigUpdaterSuite) TestJobManageEnvironNotOverwriteCert(c *gc.C)
 	c.Assert(a.conf.ssi.PrivateKey, gc.Equals, existingKey)
 }
 
+func (s *AgentConfigUpdaterSuite) startManifoldWithCA(c *gc.C, a agent.Agent, caCert, caKey string) (worker.Worker, error) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string, version int, id, request string, args, response interface{}) error {
+			c.Assert(objType, gc.Equals, "Agent")
+			switch request {
+			case "GetEntities":
+				result := response.(*params.AgentGetEntitiesResults)
+				result.Entities = []params.AgentGetEntitiesResult{{
+					Jobs: []model.MachineJob{model.JobManageModel},
+				}}
+			case "StateServingInfo":
+				result := response.(*params.StateServingInfo)
+				*result = params.StateServingInfo{
+					Cert:         "cert",
+					PrivateKey:   "key",
+					CAPrivateKey: caKey,
+					APIPort:      1234,
+				}
+			case "ControllerConfig":
+				result := response.(*params.ControllerConfigResult)
+				*result = params.ControllerConfigResult{
+					Config: map[string]interface{}{
+						"ca-cert":              caCert,
+						"mongo-memory-profile": "default",
+						"juju-db-snap-channel": controller.DefaultJujuDBSnapChannel,
+					},
+				}
+			default:
+				c.Fatalf("not sure how to handle: %q", request)
+			}
+			return nil
+		},
+	)
+	context := dt.StubContext(nil, map[string]interface{}{
+		"agent":       a,
+		"api-caller":  apiCaller,
+		"central-hub": s.hub,
+	})
+	return s.manifold.Start(context)
+}
+
+func (s *AgentConfigUpdaterSuite) TestCACertDifferenceRestarts(c *gc.C) {
+	a := &mockAgent{}
+	a.conf.caCert = testing.OtherCACert
+	w, err := s.startManifoldWithCA(c, a, testing.CACert, testing.CAKey)
+	c.Assert(w, gc.IsNil)
+	c.Assert(err, gc.Equals, jworker.ErrRestartAgent)
+
+	c.Assert(a.conf.caCertSet, jc.IsTrue)
+	c.Assert(a.conf.caCert, gc.Equals, testing.CACert)
+	c.Assert(a.conf.ssi.CAPrivateKey, gc.Equals, testing.CAKey)
+}
+
+func (s *AgentConfigUpdaterSuite) TestCACertKeyMismatch(c *gc.C) {
+	authority, err := pkitest.NewTestAuthority()
+	c.Assert(err, jc.ErrorIsNil)
+	caCert, err := pki.CertificateToPemString(pki.DefaultPemHeaders, authority.Certificate())
+	c.Assert(err, jc.ErrorIsNil)
+
+	a := &mockAgent{}
+	a.conf.caCert = testing.CACert
+	w, err := s.startManifoldWithCA(c, a, caCert, testing.CAKey)
+	c.Assert(w, gc.IsNil)
+	c.Assert(err, gc.ErrorMatches, "checking CA certificate against CA private key: CA private key does not match CA certificate")
+
+	c.Assert(a.conf.caCertSet, jc.IsFalse)
+	c.Assert(a.conf.ssiSet, jc.IsFalse)
+}
+
 func (s *AgentConfigUpdaterSuite) TestJobHostUnits(c *gc.C) {
 	// State serving info should not be set for JobHostUnits.
 	s.checkNotController(c, model.JobHostUnits)
@@ -354,6 +425,9 @@ type mockConfig struct {
 
 	snapChannel    string
 	snapChannelSet bool
+
+	caCert    string
+	caCertSet bool
 }
 
 func (mc *mockConfig) Tag() names.Tag {
@@ -400,6 +474,15 @@ func (mc *mockConfig) SetJujuDBSnapChannel(snapChannel string) {
 	mc.snapChannelSet = true
 }
 
+func (mc *mockConfig) CACert() string {
+	return mc.caCert
+}
+
+func (mc *mockConfig) SetCACert(caCert string) {
+	mc.caCert = caCert
+	mc.caCertSet = true
+}
+
 func (mc *mockConfig) LogDir() string {
 	return "log-dir"
 }
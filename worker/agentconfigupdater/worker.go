@@ -24,6 +24,7 @@ type WorkerConfig struct {
 	Hub               *pubsub.StructuredHub
 	MongoProfile      mongo.MemoryProfile
 	JujuDBSnapChannel string
+	CACert            string
 	Logger            Logger
 }
 
@@ -47,6 +48,7 @@ type agentConfigUpdater struct {
 	tomb              tomb.Tomb
 	mongoProfile      mongo.MemoryProfile
 	jujuDBSnapChannel string
+	caCert            string
 }
 
 // NewWorker creates a new agent config updater worker.
@@ -60,6 +62,7 @@ func NewWorker(config WorkerConfig) (worker.Worker, error) {
 		config:            config,
 		mongoProfile:      config.MongoProfile,
 		jujuDBSnapChannel: config.JujuDBSnapChannel,
+		caCert:            config.CACert,
 	}
 	w.tomb.Go(func() error {
 		return w.loop(started)
@@ -99,7 +102,16 @@ func (w *agentConfigUpdater) onConfigChanged(topic string, data controllermsg.Co
 	jujuDBSnapChannel := data.Config.JujuDBSnapChannel()
 	jujuDBSnapChannelChanged := jujuDBSnapChannel != w.jujuDBSnapChannel
 
+	// The CA certificate is written to the agent config along with
+	// the matching CA private key when the agent restarts.
+	caCert, _ := data.Config.CACert()
+	caCertChanged := caCert != w.caCert
+
 	if !mongoProfileChanged && !jujuDBSnapChannelChanged {
+		if caCertChanged {
+			w.config.Logger.Debugf("controller CA certificate changed")
+			w.tomb.Kill(jworker.ErrRestartAgent)
+		}
 		// Nothing to do, all good.
 		return
 	}
@@ -16,6 +16,7 @@ import (
 
 	"github.com/juju/juju/controller"
 	controllermsg "github.com/juju/juju/pubsub/controller"
+	coretesting "github.com/juju/juju/testing"
 	jworker "github.com/juju/juju/worker"
 	"github.com/juju/juju/worker/agentconfigupdater"
 )
@@ -183,3 +184,44 @@ func (s *WorkerSuite) TestUpdateJujuDBSnapChannel(c *gc.C) {
 
 	c.Assert(err, gc.Equals, jworker.ErrRestartAgent)
 }
+
+func (s *WorkerSuite) TestUpdateCACert(c *gc.C) {
+	s.config.CACert = coretesting.CACert
+	w, err := agentconfigupdater.NewWorker(s.config)
+	c.Assert(w, gc.NotNil)
+	c.Check(err, jc.ErrorIsNil)
+
+	newConfig := controllermsg.ConfigChangedMessage{
+		Config: controller.Config{
+			controller.MongoMemoryProfile: controller.DefaultMongoMemoryProfile,
+			controller.JujuDBSnapChannel:  controller.DefaultJujuDBSnapChannel,
+			controller.CACertKey:          coretesting.CACert,
+		},
+	}
+	handled, err := s.hub.Publish(controllermsg.ConfigChanged, newConfig)
+	c.Assert(err, jc.ErrorIsNil)
+	select {
+	case <-handled:
+	case <-time.After(testing.LongWait):
+		c.Fatalf("event not handled")
+	}
+
+	// CA certificate is the same, worker still alive.
+	workertest.CheckAlive(c, w)
+
+	newConfig.Config[controller.CACertKey] = coretesting.CACert + coretesting.OtherCACert
+	handled, err = s.hub.Publish(controllermsg.ConfigChanged, newConfig)
+	c.Assert(err, jc.ErrorIsNil)
+	select {
+	case <-handled:
+	case <-time.After(testing.LongWait):
+		c.Fatalf("event not handled")
+	}
+
+	err = workertest.CheckKilled(c, w)
+
+	c.Assert(err, gc.Equals, jworker.ErrRestartAgent)
+	// The CA certificate is written by the manifold once the agent
+	// restarts, along with the matching private key.
+	c.Assert(s.agent.conf.caCertSet, jc.IsFalse)
+}
@@ -0,0 +1,126 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package branchpromoter
+
+import (
+	"time"
+
+	"github.com/juju/clock"
+	"github.com/juju/errors"
+	"github.com/juju/worker/v2"
+	"github.com/juju/worker/v2/dependency"
+
+	"github.com/juju/juju/core/cache"
+	"github.com/juju/juju/state"
+	workerstate "github.com/juju/juju/worker/state"
+)
+
+// ManifoldConfig holds the information necessary to run a branch promoter
+// worker in a dependency.Engine.
+type ManifoldConfig struct {
+	ClockName      string
+	ModelCacheName string
+	StateName      string
+
+	Logger    Logger
+	Interval  time.Duration
+	NewWorker func(Config) (worker.Worker, error)
+}
+
+// Validate returns an error if the config cannot be used to start a worker.
+func (config ManifoldConfig) Validate() error {
+	if config.ClockName == "" {
+		return errors.NotValidf("empty ClockName")
+	}
+	if config.ModelCacheName == "" {
+		return errors.NotValidf("empty ModelCacheName")
+	}
+	if config.StateName == "" {
+		return errors.NotValidf("empty StateName")
+	}
+	if config.Logger == nil {
+		return errors.NotValidf("nil Logger")
+	}
+	if config.Interval <= 0 {
+		return errors.NotValidf("non-positive Interval")
+	}
+	if config.NewWorker == nil {
+		return errors.NotValidf("nil NewWorker")
+	}
+	return nil
+}
+
+// Manifold returns a dependency.Manifold that will run a branch promoter
+// worker.
+func Manifold(config ManifoldConfig) dependency.Manifold {
+	return dependency.Manifold{
+		Inputs: []string{
+			config.ClockName,
+			config.ModelCacheName,
+			config.StateName,
+		},
+		Start: config.start,
+	}
+}
+
+// start is a method on ManifoldConfig because it's more readable than a closure.
+func (config ManifoldConfig) start(context dependency.Context) (worker.Worker, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	var clock clock.Clock
+	if err := context.Get(config.ClockName, &clock); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	var controller *cache.Controller
+	if err := context.Get(config.ModelCacheName, &controller); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	var stTracker workerstate.StateTracker
+	if err := context.Get(config.StateName, &stTracker); err != nil {
+		return nil, errors.Trace(err)
+	}
+	statePool, err := stTracker.Use()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	w, err := config.NewWorker(Config{
+		Controller: controller,
+		StatePool:  statePoolShim{statePool},
+		Clock:      clock,
+		Logger:     config.Logger,
+		Interval:   config.Interval,
+	})
+	if err != nil {
+		_ = stTracker.Done()
+		return nil, errors.Trace(err)
+	}
+	go func() {
+		_ = w.Wait()
+		_ = stTracker.Done()
+	}()
+	return w, nil
+}
+
+type statePoolShim struct {
+	pool *state.StatePool
+}
+
+// Branch is part of the StatePool interface.
+func (s statePoolShim) Branch(modelUUID, branchName string) (Branch, func(), error) {
+	model, ph, err := s.pool.GetModel(modelUUID)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	branch, err := model.Branch(branchName)
+	if err != nil {
+		ph.Release()
+		return nil, nil, errors.Trace(err)
+	}
+	return branch, func() { ph.Release() }, nil
+}
@@ -0,0 +1,73 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package branchpromoter_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/worker/v2"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/worker/branchpromoter"
+)
+
+type ManifoldSuite struct {
+	testing.IsolationSuite
+	config branchpromoter.ManifoldConfig
+}
+
+var _ = gc.Suite(&ManifoldSuite{})
+
+func (s *ManifoldSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.config = branchpromoter.ManifoldConfig{
+		ClockName:      "clock",
+		ModelCacheName: "model-cache",
+		StateName:      "state",
+		Logger:         loggo.GetLogger("test"),
+		Interval:       time.Minute,
+		NewWorker: func(branchpromoter.Config) (worker.Worker, error) {
+			return nil, errors.New("unexpected")
+		},
+	}
+}
+
+func (s *ManifoldSuite) TestValid(c *gc.C) {
+	c.Check(s.config.Validate(), jc.ErrorIsNil)
+}
+
+func (s *ManifoldSuite) TestInputs(c *gc.C) {
+	manifold := branchpromoter.Manifold(s.config)
+	c.Check(manifold.Inputs, jc.SameContents, []string{"clock", "model-cache", "state"})
+}
+
+func (s *ManifoldSuite) TestMissingModelCacheName(c *gc.C) {
+	s.config.ModelCacheName = ""
+	s.checkNotValid(c, "empty ModelCacheName not valid")
+}
+
+func (s *ManifoldSuite) TestMissingStateName(c *gc.C) {
+	s.config.StateName = ""
+	s.checkNotValid(c, "empty StateName not valid")
+}
+
+func (s *ManifoldSuite) TestZeroInterval(c *gc.C) {
+	s.config.Interval = 0
+	s.checkNotValid(c, "non-positive Interval not valid")
+}
+
+func (s *ManifoldSuite) TestMissingNewWorker(c *gc.C) {
+	s.config.NewWorker = nil
+	s.checkNotValid(c, "nil NewWorker not valid")
+}
+
+func (s *ManifoldSuite) checkNotValid(c *gc.C, expect string) {
+	err := s.config.Validate()
+	c.Check(err, gc.ErrorMatches, expect)
+	c.Check(err, jc.Satisfies, errors.IsNotValid)
+}
@@ -0,0 +1,250 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package branchpromoter provides a worker that completes model branches
+// with a promotion policy. A branch is committed once all of the units
+// tracking it have been healthy for its soak time. If the units are not
+// healthy once the soak time has elapsed from when the policy was set,
+// they are reverted to the master generation and the branch is aborted.
+//
+// The time from which units have been healthy is held in memory, so the
+// soak time restarts if the worker is restarted.
+package branchpromoter
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/juju/clock"
+	"github.com/juju/errors"
+	"github.com/juju/worker/v2"
+
+	"github.com/juju/juju/core/cache"
+	"github.com/juju/juju/core/status"
+	"github.com/juju/juju/state"
+	jworker "github.com/juju/juju/worker"
+)
+
+// Logger represents the methods used by the worker to log details.
+type Logger interface {
+	Infof(string, ...interface{})
+	Warningf(string, ...interface{})
+}
+
+// Controller describes the model cache used to determine which branches
+// are due for promotion, and the health of the units tracking them.
+type Controller interface {
+	ModelUUIDs() []string
+	Model(uuid string) (*cache.Model, error)
+}
+
+// StatePool provides access to the branches that the worker completes.
+type StatePool interface {
+	// Branch returns the named active branch of the model with the
+	// input UUID, along with a function to call when it is no longer
+	// required.
+	Branch(modelUUID, branchName string) (Branch, func(), error)
+}
+
+// Branch describes the state methods used to complete a branch.
+type Branch interface {
+	PromotionPolicy() (state.PromotionPolicy, bool)
+	Commit(userName string) (int, error)
+	UnassignAllUnits() error
+	Abort(userName string) error
+}
+
+// Config holds the configuration and dependencies for the worker.
+type Config struct {
+	Controller Controller
+	StatePool  StatePool
+	Clock      clock.Clock
+	Logger     Logger
+	Interval   time.Duration
+}
+
+// Validate returns an error if the config cannot be expected
+// to drive a functional worker.
+func (config Config) Validate() error {
+	if config.Controller == nil {
+		return errors.NotValidf("nil Controller")
+	}
+	if config.StatePool == nil {
+		return errors.NotValidf("nil StatePool")
+	}
+	if config.Clock == nil {
+		return errors.NotValidf("nil Clock")
+	}
+	if config.Logger == nil {
+		return errors.NotValidf("nil Logger")
+	}
+	if config.Interval <= 0 {
+		return errors.NotValidf("non-positive Interval")
+	}
+	return nil
+}
+
+// NewWorker returns a worker that periodically checks every model's
+// branches, completing those due for promotion.
+func NewWorker(config Config) (worker.Worker, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	p := &promoter{
+		config:       config,
+		healthySince: make(map[string]time.Time),
+	}
+	return jworker.NewSimpleWorker(p.loop), nil
+}
+
+type promoter struct {
+	config Config
+
+	// healthySince records when all of the units tracking each branch
+	// with a promotion policy were first observed to be healthy, keyed
+	// by the model, branch and policy.
+	healthySince map[string]time.Time
+}
+
+func (p *promoter) loop(stopCh <-chan struct{}) error {
+	for {
+		select {
+		case <-p.config.Clock.After(p.config.Interval):
+			p.promoteBranches()
+		case <-stopCh:
+			return nil
+		}
+	}
+}
+
+// promoteBranches completes all of the branches that are due for
+// promotion. Failure to complete a branch is logged rather than stopping
+// the worker, so that other branches are still completed; the branch is
+// retried on the next pass.
+func (p *promoter) promoteBranches() {
+	now := p.config.Clock.Now()
+	seen := make(map[string]bool)
+	for _, modelUUID := range p.config.Controller.ModelUUIDs() {
+		model, err := p.config.Controller.Model(modelUUID)
+		if err != nil {
+			// The model has been removed since the UUIDs were retrieved.
+			continue
+		}
+		for _, branch := range model.Branches() {
+			promoteAt := branch.PromoteAt()
+			if promoteAt == 0 {
+				continue
+			}
+			// Setting the policy again changes when it is due,
+			// which restarts the soak time.
+			key := fmt.Sprintf("%s:%s:%d", modelUUID, branch.Name(), promoteAt)
+			seen[key] = true
+
+			tracked, healthy := unitHealth(model, branch)
+			var since time.Time
+			if tracked && healthy {
+				if _, ok := p.healthySince[key]; !ok {
+					p.healthySince[key] = now
+				}
+				since = p.healthySince[key]
+			} else {
+				delete(p.healthySince, key)
+				if now.Unix() < promoteAt {
+					// The units have until the policy is due
+					// to become healthy.
+					continue
+				}
+			}
+			if err := p.promoteBranch(modelUUID, branch.Name(), tracked, since); err != nil {
+				p.config.Logger.Warningf("cannot promote branch %q in model %s: %v", branch.Name(), modelUUID, err)
+			}
+		}
+	}
+	for key := range p.healthySince {
+		if !seen[key] {
+			delete(p.healthySince, key)
+		}
+	}
+}
+
+// unitHealth returns whether any units are tracking the branch, and
+// whether all of those units are healthy. A unit is healthy unless its
+// workload or agent reports a problem; units that are busy running hooks
+// or in maintenance are healthy. A unit that is no longer in the model
+// is not healthy.
+func unitHealth(model *cache.Model, branch cache.Branch) (bool, bool) {
+	tracked, healthy := false, true
+	for _, unitNames := range branch.AssignedUnits() {
+		for _, unitName := range unitNames {
+			tracked = true
+			unit, err := model.Unit(unitName)
+			if err != nil {
+				healthy = false
+				continue
+			}
+			switch unit.WorkloadStatus().Status {
+			case status.Active, status.Maintenance, status.Waiting:
+			default:
+				healthy = false
+			}
+			switch unit.AgentStatus().Status {
+			case status.Idle, status.Executing:
+			default:
+				healthy = false
+			}
+		}
+	}
+	return tracked, healthy
+}
+
+// promoteBranch completes a single branch. If units track the branch and
+// they have all been healthy since the input time for the policy's soak
+// time, the branch is committed. A zero time indicates that the units are
+// not healthy, in which case they are first reverted, and the branch
+// aborted on a later pass once the cache reflects that no units track it,
+// so that the units observe the reversion.
+func (p *promoter) promoteBranch(modelUUID, branchName string, tracked bool, healthySince time.Time) error {
+	branch, release, err := p.config.StatePool.Branch(modelUUID, branchName)
+	if errors.IsNotFound(err) {
+		// The branch has already been completed.
+		return nil
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	defer release()
+
+	// The cache may lag behind state, so check that
+	// the policy still applies before acting on it.
+	policy, ok := branch.PromotionPolicy()
+	if !ok {
+		return nil
+	}
+	now := p.config.Clock.Now()
+	healthy := !healthySince.IsZero()
+	if healthy && now.Before(healthySince.Add(policy.SoakTime)) {
+		return nil
+	}
+	if !healthy && now.Before(policy.Deadline()) {
+		return nil
+	}
+
+	switch {
+	case tracked && healthy:
+		genId, err := branch.Commit(policy.SetBy)
+		if err != nil {
+			return errors.Annotate(err, "committing branch")
+		}
+		p.config.Logger.Infof("committed branch %q in model %s as generation %d", branchName, modelUUID, genId)
+	case tracked:
+		if err := branch.UnassignAllUnits(); err != nil {
+			return errors.Annotate(err, "reverting tracking units")
+		}
+		p.config.Logger.Infof("reverted units tracking unhealthy branch %q in model %s", branchName, modelUUID)
+	default:
+		if err := branch.Abort(policy.SetBy); err != nil {
+			return errors.Annotate(err, "aborting branch")
+		}
+		p.config.Logger.Infof("aborted branch %q in model %s", branchName, modelUUID)
+	}
+	return nil
+}
@@ -0,0 +1,331 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package branchpromoter_test
+
+import (
+	"time"
+
+	"github.com/juju/clock/testclock"
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/worker/v2/workertest"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/cache"
+	"github.com/juju/juju/core/status"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/branchpromoter"
+)
+
+const modelUUID = "deadbeef-0bad-400d-8000-4b1d0d06f00d"
+
+type WorkerSuite struct {
+	testing.IsolationSuite
+
+	clock      *testclock.Clock
+	controller *cache.Controller
+	changes    chan interface{}
+	processed  chan interface{}
+	pool       *fakeStatePool
+}
+
+var _ = gc.Suite(&WorkerSuite{})
+
+func (s *WorkerSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.clock = testclock.NewClock(time.Unix(1000000, 0))
+	s.changes = make(chan interface{})
+	s.processed = make(chan interface{})
+
+	controller, err := cache.NewController(cache.ControllerConfig{
+		Changes: s.changes,
+		Notify: func(change interface{}) {
+			s.processed <- change
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.controller = controller
+	s.AddCleanup(func(c *gc.C) { workertest.CleanKill(c, controller) })
+
+	s.pool = &fakeStatePool{
+		branch: &fakeBranch{
+			policy: state.PromotionPolicy{
+				SoakTime: time.Hour,
+				Started:  s.clock.Now().Add(-time.Hour),
+				SetBy:    "bob",
+			},
+			done: make(chan struct{}, 1),
+		},
+	}
+	s.sendChange(c, cache.ModelChange{ModelUUID: modelUUID, Name: "test"})
+}
+
+func (s *WorkerSuite) sendChange(c *gc.C, change interface{}) {
+	select {
+	case s.changes <- change:
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("change not sent")
+	}
+	select {
+	case <-s.processed:
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("change not processed")
+	}
+}
+
+func (s *WorkerSuite) addUnit(c *gc.C, name string, workload, agent status.Status) {
+	s.sendChange(c, cache.UnitChange{
+		ModelUUID:      modelUUID,
+		Name:           name,
+		Application:    "redis",
+		WorkloadStatus: status.StatusInfo{Status: workload},
+		AgentStatus:    status.StatusInfo{Status: agent},
+	})
+}
+
+func (s *WorkerSuite) addBranch(c *gc.C, promoteAt time.Time, units ...string) {
+	s.sendChange(c, cache.BranchChange{
+		ModelUUID:     modelUUID,
+		Id:            "0",
+		Name:          "experiment",
+		AssignedUnits: map[string][]string{"redis": units},
+		PromoteAt:     promoteAt.Unix(),
+	})
+}
+
+func (s *WorkerSuite) startWorker(c *gc.C) {
+	w, err := branchpromoter.NewWorker(branchpromoter.Config{
+		Controller: s.controller,
+		StatePool:  s.pool,
+		Clock:      s.clock,
+		Logger:     loggo.GetLogger("test"),
+		Interval:   time.Minute,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.AddCleanup(func(c *gc.C) { workertest.CleanKill(c, w) })
+	c.Assert(s.clock.WaitAdvance(time.Minute, coretesting.LongWait, 1), jc.ErrorIsNil)
+}
+
+func (s *WorkerSuite) waitForAction(c *gc.C) {
+	select {
+	case <-s.pool.branch.done:
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("branch not promoted")
+	}
+}
+
+func (s *WorkerSuite) checkNoAction(c *gc.C) {
+	// Wait for the next pass to start, ensuring that the first has completed.
+	c.Assert(s.clock.WaitAdvance(0, coretesting.LongWait, 1), jc.ErrorIsNil)
+	select {
+	case <-s.pool.branch.done:
+		c.Fatalf("unexpected branch promotion")
+	case <-time.After(coretesting.ShortWait):
+	}
+}
+
+func (s *WorkerSuite) TestValidateConfig(c *gc.C) {
+	config := branchpromoter.Config{
+		Controller: s.controller,
+		StatePool:  s.pool,
+		Clock:      s.clock,
+		Logger:     loggo.GetLogger("test"),
+	}
+	_, err := branchpromoter.NewWorker(config)
+	c.Check(err, gc.ErrorMatches, "non-positive Interval not valid")
+
+	config.Interval = time.Minute
+	config.StatePool = nil
+	_, err = branchpromoter.NewWorker(config)
+	c.Check(err, gc.ErrorMatches, "nil StatePool not valid")
+}
+
+func (s *WorkerSuite) TestHealthyBranchCommitted(c *gc.C) {
+	s.addUnit(c, "redis/0", status.Active, status.Idle)
+	s.addUnit(c, "redis/1", status.Active, status.Idle)
+	s.addBranch(c, s.clock.Now(), "redis/0", "redis/1")
+
+	// The soak time starts when the units are first seen to be healthy.
+	s.startWorker(c)
+	s.checkNoAction(c)
+	s.pool.branch.CheckCallNames(c, "PromotionPolicy")
+
+	c.Assert(s.clock.WaitAdvance(time.Hour, coretesting.LongWait, 1), jc.ErrorIsNil)
+	s.waitForAction(c)
+	s.pool.CheckCall(c, 0, "Branch", modelUUID, "experiment")
+	s.pool.branch.CheckCallNames(c, "PromotionPolicy", "PromotionPolicy", "Commit")
+	s.pool.branch.CheckCall(c, 2, "Commit", "bob")
+}
+
+func (s *WorkerSuite) TestBusyUnitsHealthy(c *gc.C) {
+	s.addUnit(c, "redis/0", status.Active, status.Executing)
+	s.addUnit(c, "redis/1", status.Maintenance, status.Executing)
+	s.addUnit(c, "redis/2", status.Waiting, status.Idle)
+	s.addBranch(c, s.clock.Now(), "redis/0", "redis/1", "redis/2")
+
+	s.startWorker(c)
+	s.checkNoAction(c)
+	c.Assert(s.clock.WaitAdvance(time.Hour, coretesting.LongWait, 1), jc.ErrorIsNil)
+	s.waitForAction(c)
+	s.pool.branch.CheckCallNames(c, "PromotionPolicy", "PromotionPolicy", "Commit")
+}
+
+func (s *WorkerSuite) TestSoakStartsWhenUnitsHealthy(c *gc.C) {
+	s.addUnit(c, "redis/0", status.Error, status.Idle)
+	s.addBranch(c, s.clock.Now().Add(time.Hour), "redis/0")
+
+	// Units have until the policy is due to become healthy.
+	s.startWorker(c)
+	s.checkNoAction(c)
+	s.pool.CheckNoCalls(c)
+
+	s.addUnit(c, "redis/0", status.Active, status.Idle)
+	c.Assert(s.clock.WaitAdvance(30*time.Minute, coretesting.LongWait, 1), jc.ErrorIsNil)
+	s.checkNoAction(c)
+
+	// The policy is now due, but the units have not
+	// yet been healthy for the soak time.
+	c.Assert(s.clock.WaitAdvance(30*time.Minute, coretesting.LongWait, 1), jc.ErrorIsNil)
+	s.checkNoAction(c)
+	c.Assert(s.clock.WaitAdvance(30*time.Minute, coretesting.LongWait, 1), jc.ErrorIsNil)
+	s.waitForAction(c)
+	s.pool.branch.CheckCallNames(c, "PromotionPolicy", "PromotionPolicy", "PromotionPolicy", "Commit")
+}
+
+func (s *WorkerSuite) TestUnhealthyUnitRestartsSoak(c *gc.C) {
+	s.addUnit(c, "redis/0", status.Active, status.Idle)
+	s.addBranch(c, s.clock.Now().Add(time.Hour), "redis/0")
+
+	s.startWorker(c)
+	s.checkNoAction(c)
+
+	s.addUnit(c, "redis/0", status.Blocked, status.Idle)
+	c.Assert(s.clock.WaitAdvance(30*time.Minute, coretesting.LongWait, 1), jc.ErrorIsNil)
+	s.checkNoAction(c)
+
+	s.addUnit(c, "redis/0", status.Active, status.Idle)
+	c.Assert(s.clock.WaitAdvance(50*time.Minute, coretesting.LongWait, 1), jc.ErrorIsNil)
+	s.checkNoAction(c)
+	s.pool.branch.CheckCallNames(c, "PromotionPolicy", "PromotionPolicy")
+}
+
+func (s *WorkerSuite) TestUnhealthyUnitsReverted(c *gc.C) {
+	s.addUnit(c, "redis/0", status.Active, status.Idle)
+	s.addUnit(c, "redis/1", status.Error, status.Idle)
+	s.addBranch(c, s.clock.Now(), "redis/0", "redis/1")
+
+	s.startWorker(c)
+	s.waitForAction(c)
+	s.pool.branch.CheckCallNames(c, "PromotionPolicy", "UnassignAllUnits")
+}
+
+func (s *WorkerSuite) TestFailedAgentReverted(c *gc.C) {
+	s.addUnit(c, "redis/0", status.Active, status.Lost)
+	s.addBranch(c, s.clock.Now(), "redis/0")
+
+	s.startWorker(c)
+	s.waitForAction(c)
+	s.pool.branch.CheckCallNames(c, "PromotionPolicy", "UnassignAllUnits")
+}
+
+func (s *WorkerSuite) TestMissingUnitReverted(c *gc.C) {
+	s.addBranch(c, s.clock.Now(), "redis/0")
+
+	s.startWorker(c)
+	s.waitForAction(c)
+	s.pool.branch.CheckCallNames(c, "PromotionPolicy", "UnassignAllUnits")
+}
+
+func (s *WorkerSuite) TestUntrackedBranchAborted(c *gc.C) {
+	s.addBranch(c, s.clock.Now())
+
+	s.startWorker(c)
+	s.waitForAction(c)
+	s.pool.branch.CheckCallNames(c, "PromotionPolicy", "Abort")
+	s.pool.branch.CheckCall(c, 1, "Abort", "bob")
+}
+
+func (s *WorkerSuite) TestBranchNotDue(c *gc.C) {
+	s.addUnit(c, "redis/0", status.Active, status.Idle)
+	s.addBranch(c, s.clock.Now().Add(time.Hour), "redis/0")
+
+	s.startWorker(c)
+	s.checkNoAction(c)
+	s.pool.branch.CheckCallNames(c, "PromotionPolicy")
+}
+
+func (s *WorkerSuite) TestBranchWithoutPolicyIgnored(c *gc.C) {
+	s.addUnit(c, "redis/0", status.Active, status.Idle)
+	s.addBranch(c, time.Unix(0, 0), "redis/0")
+
+	s.startWorker(c)
+	s.checkNoAction(c)
+	s.pool.CheckNoCalls(c)
+}
+
+func (s *WorkerSuite) TestStatePolicyNotDue(c *gc.C) {
+	// The policy has been changed since the cache was updated.
+	s.pool.branch.policy.Started = s.clock.Now()
+	s.addUnit(c, "redis/0", status.Error, status.Idle)
+	s.addBranch(c, s.clock.Now(), "redis/0")
+
+	s.startWorker(c)
+	s.checkNoAction(c)
+	s.pool.branch.CheckCallNames(c, "PromotionPolicy")
+}
+
+func (s *WorkerSuite) TestBranchAlreadyCompleted(c *gc.C) {
+	s.pool.SetErrors(errors.NotFoundf("branch"))
+	s.addUnit(c, "redis/0", status.Active, status.Idle)
+	s.addBranch(c, s.clock.Now(), "redis/0")
+
+	s.startWorker(c)
+	s.checkNoAction(c)
+	s.pool.branch.CheckNoCalls(c)
+}
+
+type fakeStatePool struct {
+	testing.Stub
+	branch *fakeBranch
+}
+
+func (p *fakeStatePool) Branch(modelUUID, branchName string) (branchpromoter.Branch, func(), error) {
+	p.MethodCall(p, "Branch", modelUUID, branchName)
+	if err := p.NextErr(); err != nil {
+		return nil, nil, err
+	}
+	return p.branch, func() {}, nil
+}
+
+type fakeBranch struct {
+	testing.Stub
+	policy state.PromotionPolicy
+	done   chan struct{}
+}
+
+func (b *fakeBranch) PromotionPolicy() (state.PromotionPolicy, bool) {
+	b.MethodCall(b, "PromotionPolicy")
+	return b.policy, true
+}
+
+func (b *fakeBranch) Commit(userName string) (int, error) {
+	b.MethodCall(b, "Commit", userName)
+	b.done <- struct{}{}
+	return 1, b.NextErr()
+}
+
+func (b *fakeBranch) UnassignAllUnits() error {
+	b.MethodCall(b, "UnassignAllUnits")
+	b.done <- struct{}{}
+	return b.NextErr()
+}
+
+func (b *fakeBranch) Abort(userName string) error {
+	b.MethodCall(b, "Abort", userName)
+	b.done <- struct{}{}
+	return b.NextErr()
+}
@@ -0,0 +1,106 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package cacertupdater
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/worker/v2"
+
+	"github.com/juju/juju/controller"
+	"github.com/juju/juju/core/watcher"
+)
+
+// ControllerConfigWatcher is an interface that is provided to
+// NewCACertUpdater which can be used to watch for changes to the
+// controller's CA certificates.
+type ControllerConfigWatcher interface {
+	ControllerConfig() (controller.Config, error)
+	WatchControllerConfig() (watcher.NotifyWatcher, error)
+}
+
+// CACertSetter is an interface that is provided to NewCACertUpdater
+// whose SetCACert method will be invoked whenever the controller's CA
+// certificates change.
+type CACertSetter interface {
+	CACert() string
+	SetCACert(caCert string) error
+}
+
+// Config defines the operation of a Worker.
+type Config struct {
+	Facade ControllerConfigWatcher
+	Setter CACertSetter
+	Logger Logger
+}
+
+// Validate returns an error if config cannot drive a Worker.
+func (config Config) Validate() error {
+	if config.Facade == nil {
+		return errors.NotValidf("nil Facade")
+	}
+	if config.Setter == nil {
+		return errors.NotValidf("nil Setter")
+	}
+	if config.Logger == nil {
+		return errors.NotValidf("nil Logger")
+	}
+	return nil
+}
+
+// CACertUpdater is responsible for propagating the controller's CA
+// certificates to an agent.
+//
+// In practice, CACertUpdater is used by machine and unit agents to
+// watch the controller's CA certificates, which change when the CA is
+// rotated, and write the changes to the agent's config file so that the
+// agent can still connect to the controller once its certificate has
+// been reissued by the new CA.
+type CACertUpdater struct {
+	config Config
+}
+
+// NewCACertUpdater returns a worker.Worker that watches for changes to
+// the controller's CA certificates and then sets them on the CACertSetter.
+func NewCACertUpdater(config Config) (worker.Worker, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	handler := &CACertUpdater{
+		config: config,
+	}
+	w, err := watcher.NewNotifyWorker(watcher.NotifyConfig{
+		Handler: handler,
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return w, nil
+}
+
+// SetUp is part of the watcher.NotifyHandler interface.
+func (c *CACertUpdater) SetUp() (watcher.NotifyWatcher, error) {
+	return c.config.Facade.WatchControllerConfig()
+}
+
+// Handle is part of the watcher.NotifyHandler interface.
+func (c *CACertUpdater) Handle(_ <-chan struct{}) error {
+	cfg, err := c.config.Facade.ControllerConfig()
+	if err != nil {
+		return errors.Annotate(err, "getting controller config")
+	}
+	caCert, ok := cfg.CACert()
+	if !ok || caCert == "" || caCert == c.config.Setter.CACert() {
+		return nil
+	}
+	c.config.Logger.Infof("updating CA certificate")
+	if err := c.config.Setter.SetCACert(caCert); err != nil {
+		return errors.Annotate(err, "setting CA certificate")
+	}
+	return nil
+}
+
+// TearDown is part of the watcher.NotifyHandler interface.
+func (c *CACertUpdater) TearDown() error {
+	return nil
+}
@@ -0,0 +1,135 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package cacertupdater_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/worker/v2/workertest"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/controller"
+	"github.com/juju/juju/core/watcher"
+	"github.com/juju/juju/core/watcher/watchertest"
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/cacertupdater"
+)
+
+type CACertUpdaterSuite struct {
+	testing.BaseSuite
+	facade *fakeFacade
+	setter *fakeSetter
+	config cacertupdater.Config
+}
+
+var _ = gc.Suite(&CACertUpdaterSuite{})
+
+func (s *CACertUpdaterSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.facade = &fakeFacade{
+		changes: make(chan struct{}, 1),
+		caCert:  testing.CACert,
+	}
+	s.setter = &fakeSetter{
+		caCert: testing.CACert,
+		set:    make(chan string, 1),
+	}
+	s.config = cacertupdater.Config{
+		Facade: s.facade,
+		Setter: s.setter,
+		Logger: loggo.GetLogger("test"),
+	}
+}
+
+func (s *CACertUpdaterSuite) TestValidate(c *gc.C) {
+	config := s.config
+	config.Facade = nil
+	_, err := cacertupdater.NewCACertUpdater(config)
+	c.Check(err, jc.Satisfies, errors.IsNotValid)
+	c.Check(err, gc.ErrorMatches, "nil Facade not valid")
+
+	config = s.config
+	config.Setter = nil
+	_, err = cacertupdater.NewCACertUpdater(config)
+	c.Check(err, gc.ErrorMatches, "nil Setter not valid")
+
+	config = s.config
+	config.Logger = nil
+	_, err = cacertupdater.NewCACertUpdater(config)
+	c.Check(err, gc.ErrorMatches, "nil Logger not valid")
+}
+
+func (s *CACertUpdaterSuite) TestUnchanged(c *gc.C) {
+	w, err := cacertupdater.NewCACertUpdater(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.CleanKill(c, w)
+
+	s.facade.changes <- struct{}{}
+	select {
+	case caCert := <-s.setter.set:
+		c.Fatalf("unexpected CA certificate set: %q", caCert)
+	case <-time.After(testing.ShortWait):
+	}
+}
+
+func (s *CACertUpdaterSuite) TestChanged(c *gc.C) {
+	w, err := cacertupdater.NewCACertUpdater(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.CleanKill(c, w)
+
+	bundle := testing.CACert + testing.OtherCACert
+	s.facade.caCert = bundle
+	s.facade.changes <- struct{}{}
+	select {
+	case caCert := <-s.setter.set:
+		c.Assert(caCert, gc.Equals, bundle)
+	case <-time.After(testing.LongWait):
+		c.Fatalf("CA certificate not set")
+	}
+}
+
+func (s *CACertUpdaterSuite) TestControllerConfigError(c *gc.C) {
+	s.facade.err = errors.New("boom")
+	w, err := cacertupdater.NewCACertUpdater(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.facade.changes <- struct{}{}
+	err = workertest.CheckKilled(c, w)
+	c.Assert(err, gc.ErrorMatches, "getting controller config: boom")
+}
+
+type fakeFacade struct {
+	changes chan struct{}
+	caCert  string
+	err     error
+}
+
+func (f *fakeFacade) ControllerConfig() (controller.Config, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return controller.Config{controller.CACertKey: f.caCert}, nil
+}
+
+func (f *fakeFacade) WatchControllerConfig() (watcher.NotifyWatcher, error) {
+	return watchertest.NewMockNotifyWatcher(f.changes), nil
+}
+
+type fakeSetter struct {
+	caCert string
+	set    chan string
+}
+
+func (f *fakeSetter) CACert() string {
+	return f.caCert
+}
+
+func (f *fakeSetter) SetCACert(caCert string) error {
+	f.caCert = caCert
+	f.set <- caCert
+	return nil
+}
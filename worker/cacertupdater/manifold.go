@@ -0,0 +1,64 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package cacertupdater
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/worker/v2"
+	"github.com/juju/worker/v2/dependency"
+
+	"github.com/juju/juju/agent"
+	apiagent "github.com/juju/juju/api/agent"
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/cmd/jujud/agent/engine"
+)
+
+// Logger represents the methods used for logging messages.
+type Logger interface {
+	Infof(string, ...interface{})
+	Debugf(string, ...interface{})
+}
+
+// ManifoldConfig defines the names of the manifolds on which a Manifold will depend.
+type ManifoldConfig struct {
+	AgentName     string
+	APICallerName string
+	Logger        Logger
+}
+
+// Manifold returns a dependency manifold that runs a CA certificate
+// updater worker, using the resource names defined in the supplied config.
+func Manifold(config ManifoldConfig) dependency.Manifold {
+	typedConfig := engine.AgentAPIManifoldConfig{
+		AgentName:     config.AgentName,
+		APICallerName: config.APICallerName,
+	}
+	return engine.AgentAPIManifold(typedConfig, config.newWorker)
+}
+
+// newWorker trivially wraps NewCACertUpdater for use in a engine.AgentAPIManifold.
+func (config ManifoldConfig) newWorker(a agent.Agent, apiCaller base.APICaller) (worker.Worker, error) {
+	// Controller agents write the CA certificates along with the
+	// matching CA private key; see the agentconfigupdater worker.
+	if _, ok := a.CurrentConfig().StateServingInfo(); ok {
+		return nil, dependency.ErrUninstall
+	}
+	// Older controllers cannot rotate their CA.
+	if apiCaller.BestFacadeVersion("Agent") < 3 {
+		return nil, dependency.ErrUninstall
+	}
+	facade, err := apiagent.NewState(apiCaller)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	w, err := NewCACertUpdater(Config{
+		Facade: facade,
+		Setter: agent.CACertSetter{Agent: a},
+		Logger: config.Logger,
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return w, nil
+}
@@ -34,6 +34,8 @@ type CertificateUpdater struct {
 	addressWatcher  AddressWatcher
 	authority       pki.Authority
 	hostPortsGetter APIHostPortsGetter
+	caRecorder      CARotationRecorder
+	controllerId    string
 	addresses       network.SpaceAddresses
 }
 
@@ -56,11 +58,21 @@ type APIHostPortsGetter interface {
 	APIHostPortsForClients() ([]network.SpaceHostPorts, error)
 }
 
+// CARotationRecorder is an interface that is provided to
+// NewCertificateUpdater. It is told when the controller has issued its
+// certificate from the CA, so that a rotation of the controller's CA
+// can be finished once every controller has done so.
+type CARotationRecorder interface {
+	RecordControllerCAReissued(controllerId, caCert string) error
+}
+
 // Config holds the configuration for the certificate updater worker.
 type Config struct {
 	AddressWatcher     AddressWatcher
 	Authority          pki.Authority
 	APIHostPortsGetter APIHostPortsGetter
+	CARotationRecorder CARotationRecorder
+	ControllerId       string
 }
 
 // NewCertificateUpdater returns a worker.Worker that watches for changes to
@@ -71,6 +83,8 @@ func NewCertificateUpdater(config Config) worker.Worker {
 		addressWatcher:  config.AddressWatcher,
 		authority:       config.Authority,
 		hostPortsGetter: config.APIHostPortsGetter,
+		caRecorder:      config.CARotationRecorder,
+		controllerId:    config.ControllerId,
 	})
 }
 
@@ -93,6 +107,13 @@ func (c *CertificateUpdater) SetUp() (state.NotifyWatcher, error) {
 	if err := c.updateCertificate(initialSANAddresses); err != nil {
 		return nil, errors.Annotate(err, "setting initial certificate SAN list")
 	}
+	caCert, err := pki.CertificateToPemString(pki.DefaultPemHeaders, c.authority.Certificate())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := c.caRecorder.RecordControllerCAReissued(c.controllerId, caCert); err != nil {
+		return nil, errors.Annotate(err, "recording certificate issued from CA")
+	}
 	return c.addressWatcher.WatchAddresses(), nil
 }
 
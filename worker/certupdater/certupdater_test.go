@@ -13,6 +13,7 @@ import (
 
 	jujucontroller "github.com/juju/juju/controller"
 	"github.com/juju/juju/core/network"
+	"github.com/juju/juju/pki"
 	pkitest "github.com/juju/juju/pki/test"
 	"github.com/juju/juju/state"
 	coretesting "github.com/juju/juju/testing"
@@ -93,6 +94,17 @@ func (g *mockAPIHostGetter) APIHostPortsForClients() ([]network.SpaceHostPorts,
 	}}, nil
 }
 
+type mockCARotationRecorder struct {
+	controllerId string
+	caCert       string
+}
+
+func (r *mockCARotationRecorder) RecordControllerCAReissued(controllerId, caCert string) error {
+	r.controllerId = controllerId
+	r.caCert = caCert
+	return nil
+}
+
 func (s *CertUpdaterSuite) TestStartStop(c *gc.C) {
 	authority, err := pkitest.NewTestAuthority()
 	c.Assert(err, jc.ErrorIsNil)
@@ -102,6 +114,8 @@ func (s *CertUpdaterSuite) TestStartStop(c *gc.C) {
 		AddressWatcher:     &mockMachine{changes},
 		APIHostPortsGetter: &mockAPIHostGetter{},
 		Authority:          authority,
+		CARotationRecorder: &mockCARotationRecorder{},
+		ControllerId:       "0",
 	})
 	workertest.CleanKill(c, worker)
 
@@ -111,6 +125,27 @@ func (s *CertUpdaterSuite) TestStartStop(c *gc.C) {
 		[]net.IP{net.ParseIP("192.168.1.1")})
 }
 
+func (s *CertUpdaterSuite) TestRecordsCAReissued(c *gc.C) {
+	authority, err := pkitest.NewTestAuthority()
+	c.Assert(err, jc.ErrorIsNil)
+
+	recorder := &mockCARotationRecorder{}
+	worker := certupdater.NewCertificateUpdater(certupdater.Config{
+		AddressWatcher:     &mockMachine{make(chan struct{})},
+		APIHostPortsGetter: &mockAPIHostGetter{},
+		Authority:          authority,
+		CARotationRecorder: recorder,
+		ControllerId:       "2",
+	})
+	workertest.CleanKill(c, worker)
+
+	c.Assert(recorder.controllerId, gc.Equals, "2")
+	certs, _, err := pki.UnmarshalPemData([]byte(recorder.caCert))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(certs, gc.HasLen, 1)
+	c.Assert(certs[0].Equal(authority.Certificate()), jc.IsTrue)
+}
+
 func (s *CertUpdaterSuite) TestAddressChange(c *gc.C) {
 	authority, err := pkitest.NewTestAuthority()
 	c.Assert(err, jc.ErrorIsNil)
@@ -120,6 +155,8 @@ func (s *CertUpdaterSuite) TestAddressChange(c *gc.C) {
 		AddressWatcher:     &mockMachine{changes},
 		APIHostPortsGetter: &mockAPIHostGetter{},
 		Authority:          authority,
+		CARotationRecorder: &mockCARotationRecorder{},
+		ControllerId:       "0",
 	})
 
 	changes <- struct{}{}
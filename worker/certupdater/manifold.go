@@ -94,6 +94,8 @@ func (config ManifoldConfig) start(context dependency.Context) (worker.Worker, e
 		AddressWatcher:     addressWatcher,
 		Authority:          authority,
 		APIHostPortsGetter: st,
+		CARotationRecorder: st,
+		ControllerId:       agentConfig.Tag().Id(),
 	})
 	return common.NewCleanupWorker(w, func() { _ = stTracker.Done() }), nil
 }
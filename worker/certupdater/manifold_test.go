@@ -121,6 +121,8 @@ func (s *ManifoldSuite) TestStart(c *gc.C) {
 		AddressWatcher:     &s.addressWatcher,
 		Authority:          s.authority,
 		APIHostPortsGetter: s.State,
+		CARotationRecorder: s.State,
+		ControllerId:       "123",
 	})
 }
 
@@ -4,16 +4,24 @@
 package httpserver_test
 
 import (
+	"context"
 	"crypto/tls"
+	"crypto/x509"
 	"net/http"
 	"net/url"
 	"runtime"
+	"sync"
 
+	"github.com/juju/clock"
 	"github.com/juju/loggo"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/worker/v2/workertest"
+	"golang.org/x/crypto/acme"
+	acmeautocert "golang.org/x/crypto/acme/autocert"
 	gc "gopkg.in/check.v1"
 
+	"github.com/juju/juju/autocert"
+	"github.com/juju/juju/autocert/autocerttesting"
 	coretesting "github.com/juju/juju/testing"
 	"github.com/juju/juju/worker/httpserver"
 )
@@ -36,6 +44,7 @@ func (s *certSuite) SetUpTest(c *gc.C) {
 		"",
 		"https://0.1.2.3/no-autocert-here",
 		nil,
+		nil,
 		testSNIGetter(coretesting.ServerTLSCert),
 	)
 	// Copy the root CAs across.
@@ -73,6 +82,7 @@ func (s *certSuite) TestAutocertFailure(c *gc.C) {
 		"somewhere.example",
 		"https://0.1.2.3/no-autocert-here",
 		nil,
+		nil,
 		testSNIGetter(coretesting.ServerTLSCert),
 	)
 	s.config.TLSConfig = tlsConfig
@@ -108,11 +118,88 @@ func (s *certSuite) TestAutocertFailure(c *gc.C) {
 	}})
 }
 
+func (s *certSuite) TestAutocertFailureReported(c *gc.C) {
+	var results []error
+	tlsConfig := httpserver.InternalNewTLSConfig(
+		"somewhere.example",
+		"https://0.1.2.3/no-autocert-here",
+		nil,
+		func(err error) { results = append(results, err) },
+		testSNIGetter(coretesting.ServerTLSCert),
+	)
+	s.config.TLSConfig = tlsConfig
+
+	worker, err := httpserver.NewWorker(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.CleanKill(c, worker)
+
+	parsed, err := url.Parse(worker.URL())
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Failures to get a certificate for other names aren't reported.
+	for _, serverName := range []string{"somewhere.example", "somewhere.else"} {
+		_, err := tls.Dial("tcp", parsed.Host, &tls.Config{
+			ServerName: serverName,
+		})
+		c.Assert(err, gc.NotNil)
+	}
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0], gc.ErrorMatches, `.*https://0\.1\.2\.3/no-autocert-here.*`)
+}
+
+func (s *certSuite) TestAutocertExternalAccountBindingDNS01(c *gc.C) {
+	dns := &fakeDNS{records: make(map[string]string)}
+	server := autocerttesting.NewServer(autocerttesting.ServerConfig{
+		EABKeyID:  "kid-1",
+		EABKey:    []byte("secret"),
+		LookupTXT: dns.lookup,
+	})
+	defer server.Close()
+
+	var results []error
+	tlsConfig, err := httpserver.InternalNewACMETLSConfig(autocert.Config{
+		DNSName:      "somewhere.example",
+		DirectoryURL: server.DirectoryURL(),
+		ExternalAccountBinding: &acme.ExternalAccountBinding{
+			KID: "kid-1",
+			Key: []byte("secret"),
+		},
+		ChallengeType: autocert.ChallengeDNS01,
+		DNSProvider:   dns,
+		Cache:         acmeautocert.DirCache(c.MkDir()),
+		Clock:         clock.WallClock,
+		HTTPClient:    server.Client(),
+		ReportResult:  func(err error) { results = append(results, err) },
+	}, testSNIGetter(coretesting.ServerTLSCert))
+	c.Assert(err, jc.ErrorIsNil)
+	s.config.TLSConfig = tlsConfig
+
+	worker, err := httpserver.NewWorker(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.CleanKill(c, worker)
+
+	parsed, err := url.Parse(worker.URL())
+	c.Assert(err, jc.ErrorIsNil)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(server.CACert)
+	conn, err := tls.Dial("tcp", parsed.Host, &tls.Config{
+		ServerName: "somewhere.example",
+		RootCAs:    roots,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	defer conn.Close()
+	c.Assert(conn.ConnectionState().PeerCertificates[0].DNSNames, jc.DeepEquals, []string{"somewhere.example"})
+	c.Assert(results, jc.DeepEquals, []error{nil})
+	c.Assert(server.Issued(), gc.HasLen, 1)
+}
+
 func (s *certSuite) TestAutocertNameMismatch(c *gc.C) {
 	tlsConfig := httpserver.InternalNewTLSConfig(
 		"somewhere.example",
 		"https://0.1.2.3/no-autocert-here",
 		nil,
+		nil,
 		testSNIGetter(coretesting.ServerTLSCert),
 	)
 	s.config.TLSConfig = tlsConfig
@@ -174,6 +261,35 @@ func (s *certSuite) TestAutocertNoAutocertDNSName(c *gc.C) {
 	}})
 }
 
+// fakeDNS is an autocert.DNSProvider holding records in memory.
+type fakeDNS struct {
+	mu      sync.Mutex
+	records map[string]string
+}
+
+func (d *fakeDNS) Present(_ context.Context, fqdn, value string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.records[fqdn] = value
+	return nil
+}
+
+func (d *fakeDNS) CleanUp(_ context.Context, fqdn, value string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.records, fqdn)
+	return nil
+}
+
+func (d *fakeDNS) lookup(fqdn string) []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if value, ok := d.records[fqdn]; ok {
+		return []string{value}
+	}
+	return nil
+}
+
 func gatherLog(f func()) []loggo.Entry {
 	var tw loggo.TestWriter
 	err := loggo.RegisterWriter("test", &tw)
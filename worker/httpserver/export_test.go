@@ -4,3 +4,5 @@
 package httpserver
 
 var InternalNewTLSConfig = newTLSConfig
+
+var InternalNewACMETLSConfig = newACMETLSConfig
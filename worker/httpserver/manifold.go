@@ -40,10 +40,11 @@ type ManifoldConfig struct {
 	Clock                clock.Clock
 	MuxShutdownWait      time.Duration
 	LogDir               string
+	DataDir              string
 	PrometheusRegisterer prometheus.Registerer
 
 	GetControllerConfig func(*state.State) (controller.Config, error)
-	NewTLSConfig        func(*state.State, SNIGetter) (*tls.Config, error)
+	NewTLSConfig        func(*state.State, string, SNIGetter) (*tls.Config, error)
 	NewWorker           func(Config) (worker.Worker, error)
 }
 
@@ -91,6 +92,9 @@ func (config ManifoldConfig) Validate() error {
 	if config.LogDir == "" {
 		return errors.NotValidf("empty LogDir")
 	}
+	if config.DataDir == "" {
+		return errors.NotValidf("empty DataDir")
+	}
 	return nil
 }
 
@@ -156,7 +160,7 @@ func (config ManifoldConfig) start(context dependency.Context) (_ worker.Worker,
 	}()
 
 	systemState := statePool.SystemState()
-	tlsConfig, err := config.NewTLSConfig(systemState, authoritySNIGetter(authority))
+	tlsConfig, err := config.NewTLSConfig(systemState, config.DataDir, authoritySNIGetter(authority))
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -79,6 +79,7 @@ func (s *ManifoldSuite) SetUpTest(c *gc.C) {
 		PrometheusRegisterer: &s.prometheusRegisterer,
 		MuxShutdownWait:      1 * time.Minute,
 		LogDir:               "log-dir",
+		DataDir:              "data-dir",
 		GetControllerConfig:  s.getControllerConfig,
 		NewTLSConfig:         s.newTLSConfig,
 		NewWorker:            s.newWorker,
@@ -111,9 +112,10 @@ func (s *ManifoldSuite) getControllerConfig(st *state.State) (controller.Config,
 
 func (s *ManifoldSuite) newTLSConfig(
 	st *state.State,
+	dataDir string,
 	_ httpserver.SNIGetter,
 ) (*tls.Config, error) {
-	s.stub.MethodCall(s, "NewTLSConfig", st)
+	s.stub.MethodCall(s, "NewTLSConfig", st, dataDir)
 	if err := s.stub.NextErr(); err != nil {
 		return nil, err
 	}
@@ -199,6 +201,9 @@ func (s *ManifoldSuite) TestValidate(c *gc.C) {
 	}, {
 		func(cfg *httpserver.ManifoldConfig) { cfg.LogDir = "" },
 		"empty LogDir not valid",
+	}, {
+		func(cfg *httpserver.ManifoldConfig) { cfg.DataDir = "" },
+		"empty DataDir not valid",
 	}, {
 		func(cfg *httpserver.ManifoldConfig) { cfg.RaftTransportName = "" },
 		"empty RaftTransportName not valid",
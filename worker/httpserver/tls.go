@@ -5,12 +5,19 @@ package httpserver
 
 import (
 	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"strings"
+	"sync"
 
+	"github.com/juju/clock"
 	"github.com/juju/errors"
 	"github.com/juju/utils"
 	"golang.org/x/crypto/acme"
 	"golang.org/x/crypto/acme/autocert"
 
+	jujuautocert "github.com/juju/juju/autocert"
+	"github.com/juju/juju/controller"
 	"github.com/juju/juju/pki"
 	"github.com/juju/juju/state"
 )
@@ -74,28 +81,108 @@ func authoritySNIGetter(authority pki.Authority) SNIGetter {
 }
 
 // NewTLSConfig returns the TLS configuration for the HTTP server to use
-// based on controller configuration stored in the state database.
-func NewTLSConfig(st *state.State, defaultSNI SNIGetter) (*tls.Config, error) {
+// based on controller configuration stored in the state database. The
+// controller agent's data directory holds any DNS provider hook.
+func NewTLSConfig(st *state.State, dataDir string, defaultSNI SNIGetter) (*tls.Config, error) {
 	controllerConfig, err := st.ControllerConfig()
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	return newTLSConfig(
-		controllerConfig.AutocertDNSName(),
-		controllerConfig.AutocertURL(),
-		st.AutocertCache(),
-		defaultSNI,
-	), nil
+	if !needsACMEManager(controllerConfig) {
+		return newTLSConfig(
+			controllerConfig.AutocertDNSName(),
+			controllerConfig.AutocertURL(),
+			st.AutocertCache(),
+			autocertResultRecorder(st),
+			defaultSNI,
+		), nil
+	}
+	managerConfig, err := acmeManagerConfig(controllerConfig, dataDir)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	managerConfig.Cache = st.AutocertCache()
+	managerConfig.ReportResult = autocertResultRecorder(st)
+	return newACMETLSConfig(managerConfig, defaultSNI)
+}
+
+// needsACMEManager reports whether the controller configuration uses
+// ACME features that golang.org/x/crypto/acme/autocert doesn't support,
+// so that certificates must be obtained with Juju's autocert package.
+func needsACMEManager(controllerConfig controller.Config) bool {
+	if controllerConfig.AutocertDNSName() == "" {
+		return false
+	}
+	return controllerConfig.AutocertEABKeyID() != "" ||
+		controllerConfig.AutocertCACert() != "" ||
+		controllerConfig.AutocertChallenge() != jujuautocert.ChallengeTLSALPN01
+}
+
+// acmeManagerConfig returns the configuration for a Juju autocert
+// manager from the controller configuration, without a cache.
+func acmeManagerConfig(controllerConfig controller.Config, dataDir string) (jujuautocert.Config, error) {
+	config := jujuautocert.Config{
+		DNSName:       controllerConfig.AutocertDNSName(),
+		DirectoryURL:  controllerConfig.AutocertURL(),
+		ChallengeType: controllerConfig.AutocertChallenge(),
+		Clock:         clock.WallClock,
+	}
+	if kid := controllerConfig.AutocertEABKeyID(); kid != "" {
+		config.ExternalAccountBinding = &acme.ExternalAccountBinding{
+			KID: kid,
+			Key: controllerConfig.AutocertEABHMACKey(),
+		}
+	}
+	if caCert := controllerConfig.AutocertCACert(); caCert != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		pool.AppendCertsFromPEM([]byte(caCert))
+		tlsConfig := utils.SecureTLSConfig()
+		tlsConfig.RootCAs = pool
+		config.HTTPClient = &http.Client{
+			Transport: utils.NewHttpTLSTransport(tlsConfig),
+		}
+	}
+	if config.ChallengeType == jujuautocert.ChallengeDNS01 {
+		attrs, err := jujuautocert.ParseDNSProviderConfig(controllerConfig.AutocertDNSProviderConfig())
+		if err != nil {
+			return jujuautocert.Config{}, errors.Trace(err)
+		}
+		config.DNSProvider, err = jujuautocert.NewDNSProvider(controllerConfig.AutocertDNSProvider(), dataDir, attrs)
+		if err != nil {
+			return jujuautocert.Config{}, errors.Trace(err)
+		}
+	}
+	return config, nil
+}
+
+// autocertResultRecorder returns a function that records the outcome
+// of attempts to obtain a certificate, for reporting to users.
+func autocertResultRecorder(st *state.State) func(error) {
+	return func(err error) {
+		var recordErr error
+		if err == nil {
+			recordErr = st.ClearAutocertFailure()
+		} else {
+			recordErr = st.SetAutocertFailure(err.Error())
+		}
+		if recordErr != nil {
+			logger.Warningf("cannot record autocert result: %v", recordErr)
+		}
+	}
 }
 
 func newTLSConfig(
 	autocertDNSName, autocertURL string,
 	autocertCache autocert.Cache,
+	reportResult func(error),
 	defaultSNI SNIGetter,
 ) *tls.Config {
-	tlsConfig := utils.SecureTLSConfig()
 	if autocertDNSName == "" {
 		// No official DNS name, no certificate.
+		tlsConfig := utils.SecureTLSConfig()
 		tlsConfig.GetCertificate = defaultSNI.GetCertificate
 		return tlsConfig
 	}
@@ -110,13 +197,62 @@ func newTLSConfig(
 			DirectoryURL: autocertURL,
 		}
 	}
+	var getter SNIGetter = SNIGetterFn(m.GetCertificate)
+	if reportResult != nil {
+		getter = reportingSNIGetter(autocertDNSName, getter, reportResult)
+	}
+	return autocertTLSConfig(getter, defaultSNI)
+}
+
+// newACMETLSConfig returns a TLS configuration that obtains
+// certificates using Juju's autocert package.
+func newACMETLSConfig(config jujuautocert.Config, defaultSNI SNIGetter) (*tls.Config, error) {
+	m, err := jujuautocert.NewManager(config)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return autocertTLSConfig(SNIGetterFn(m.GetCertificate), defaultSNI), nil
+}
+
+// reportingSNIGetter reports the result of getting a certificate for
+// the autocert DNS name whenever it changes. The autocert package
+// tries to obtain a certificate on each handshake until one is
+// obtained, so failures are reported only once.
+func reportingSNIGetter(dnsName string, getter SNIGetter, reportResult func(error)) SNIGetter {
+	var (
+		mu       sync.Mutex
+		reported bool
+		lastErr  string
+	)
+	return SNIGetterFn(func(h *tls.ClientHelloInfo) (*tls.Certificate, error) {
+		cert, err := getter.GetCertificate(h)
+		if strings.TrimSuffix(strings.ToLower(h.ServerName), ".") != dnsName {
+			return cert, err
+		}
+		var errMsg string
+		if err != nil {
+			errMsg = err.Error()
+		}
+		mu.Lock()
+		changed := !reported || errMsg != lastErr
+		reported, lastErr = true, errMsg
+		mu.Unlock()
+		if changed {
+			reportResult(err)
+		}
+		return cert, err
+	})
+}
+
+func autocertTLSConfig(autocertGetter, defaultSNI SNIGetter) *tls.Config {
+	tlsConfig := utils.SecureTLSConfig()
 	certLogger := SNIGetterFn(func(h *tls.ClientHelloInfo) (*tls.Certificate, error) {
 		logger.Infof("getting certificate for server name %q", h.ServerName)
 		return nil, nil
 	})
 
 	autoCertGetter := SNIGetterFn(func(h *tls.ClientHelloInfo) (*tls.Certificate, error) {
-		c, err := m.GetCertificate(h)
+		c, err := autocertGetter.GetCertificate(h)
 		if err != nil {
 			logger.Errorf("cannot get autocert certificate for %q: %v",
 				h.ServerName, err)
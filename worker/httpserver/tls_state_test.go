@@ -40,7 +40,7 @@ type TLSStateSuite struct {
 var _ = gc.Suite(&TLSStateSuite{})
 
 func (s *TLSStateSuite) TestNewTLSConfig(c *gc.C) {
-	tlsConfig, err := httpserver.NewTLSConfig(s.State, testSNIGetter(s.cert))
+	tlsConfig, err := httpserver.NewTLSConfig(s.State, c.MkDir(), testSNIGetter(s.cert))
 	c.Assert(err, jc.ErrorIsNil)
 
 	cert, err := tlsConfig.GetCertificate(&tls.ClientHelloInfo{
@@ -76,7 +76,7 @@ func (s *TLSStateAutocertSuite) SetUpTest(c *gc.C) {
 }
 
 func (s *TLSStateAutocertSuite) TestAutocertExceptions(c *gc.C) {
-	tlsConfig, err := httpserver.NewTLSConfig(s.State, testSNIGetter(s.cert))
+	tlsConfig, err := httpserver.NewTLSConfig(s.State, c.MkDir(), testSNIGetter(s.cert))
 	c.Assert(err, jc.ErrorIsNil)
 	s.testGetCertificate(c, tlsConfig, "127.0.0.1")
 	s.testGetCertificate(c, tlsConfig, "juju-apiserver")
@@ -85,7 +85,7 @@ func (s *TLSStateAutocertSuite) TestAutocertExceptions(c *gc.C) {
 }
 
 func (s *TLSStateAutocertSuite) TestAutocert(c *gc.C) {
-	tlsConfig, err := httpserver.NewTLSConfig(s.State, testSNIGetter(s.cert))
+	tlsConfig, err := httpserver.NewTLSConfig(s.State, c.MkDir(), testSNIGetter(s.cert))
 	c.Assert(err, jc.ErrorIsNil)
 	s.testGetCertificate(c, tlsConfig, "public.invalid")
 	c.Assert(s.autocertQueried, jc.IsTrue)
@@ -93,7 +93,7 @@ func (s *TLSStateAutocertSuite) TestAutocert(c *gc.C) {
 }
 
 func (s *TLSStateAutocertSuite) TestAutocertHostPolicy(c *gc.C) {
-	tlsConfig, err := httpserver.NewTLSConfig(s.State, testSNIGetter(s.cert))
+	tlsConfig, err := httpserver.NewTLSConfig(s.State, c.MkDir(), testSNIGetter(s.cert))
 	c.Assert(err, jc.ErrorIsNil)
 	s.testGetCertificate(c, tlsConfig, "always.invalid")
 	c.Assert(s.autocertQueried, jc.IsFalse)
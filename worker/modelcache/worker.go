@@ -627,6 +627,8 @@ func (c *cacheWorker) translateBranch(d multiwatcher.Delta) interface{} {
 		Id:            value.ID,
 		AssignedUnits: value.AssignedUnits,
 		Config:        coreItemChanges(value.Config),
+		CharmURLs:     value.CharmURLs,
+		PromoteAt:     value.PromoteAt,
 		Created:       value.Created,
 		CreatedBy:     value.CreatedBy,
 		Completed:     value.Completed,
@@ -4,6 +4,9 @@
 package remoterelations
 
 import (
+	"time"
+
+	"github.com/juju/clock"
 	"github.com/juju/errors"
 	"github.com/juju/names/v4"
 	"github.com/juju/worker/v2"
@@ -49,6 +52,7 @@ type remoteApplicationWorker struct {
 
 	newRemoteModelRelationsFacadeFunc newRemoteRelationsFacadeFunc
 
+	clock  clock.Clock
 	logger Logger
 }
 
@@ -187,7 +191,7 @@ func (w *remoteApplicationWorker) loop() (err error) {
 			w.logger.Debugf("local relation units changed -> publishing: %#v", change)
 			// TODO(babbageclunk): add macaroons to event here instead
 			// of in the relation units worker.
-			if err := w.remoteModelFacade.PublishRelationChange(change); err != nil {
+			if err := w.publishRelationChange(change); err != nil {
 				w.checkOfferPermissionDenied(err, change.ApplicationToken, change.RelationToken)
 				if params.IsCodeNotFound(err) || params.IsCodeCannotEnterScope(err) {
 					return w.remoteOfferRemoved()
@@ -210,6 +214,37 @@ func (w *remoteApplicationWorker) loop() (err error) {
 	}
 }
 
+const (
+	// publishRetryMinDelay and publishRetryMaxDelay bound how long to
+	// wait before publishing a relation change again when the offering
+	// model asks us to try again later.
+	publishRetryMinDelay = time.Second
+	publishRetryMaxDelay = time.Minute
+)
+
+// publishRelationChange publishes the change to the remote model. If the
+// offering model asks us to try again, which it does when the change
+// would exceed the offer's rate limit, the change is published again
+// after backing off rather than being dropped.
+func (w *remoteApplicationWorker) publishRelationChange(change params.RemoteRelationChangeEvent) error {
+	delay := publishRetryMinDelay
+	for {
+		err := w.remoteModelFacade.PublishRelationChange(change)
+		if !params.IsCodeTryAgain(err) {
+			return err
+		}
+		w.logger.Debugf("publishing relation change to remote model %v: %v; retrying in %v", w.remoteModelUUID, err, delay)
+		select {
+		case <-w.catacomb.Dying():
+			return w.catacomb.ErrDying()
+		case <-w.clock.After(delay):
+		}
+		if delay *= 2; delay > publishRetryMaxDelay {
+			delay = publishRetryMaxDelay
+		}
+	}
+}
+
 // newRemoteRelationsFacadeWithRedirect attempts to open an API connection to
 // the remote model for the watcher's application.
 // If a redirect error is returned, we attempt to open a connection to the new
@@ -263,6 +263,7 @@ func (w *Worker) handleApplicationChanges(applicationIds []string) error {
 				remoteRelationChanges:             make(chan params.RemoteRelationChangeEvent),
 				localModelFacade:                  w.config.RelationsFacade,
 				newRemoteModelRelationsFacadeFunc: w.config.NewRemoteModelFacadeFunc,
+				clock:                             w.config.Clock,
 				logger:                            logger,
 			}
 			if err := catacomb.Invoke(catacomb.Plan{
@@ -621,6 +621,45 @@ func (s *remoteRelationsSuite) TestRemoteNotFoundTerminatesOnPublish(c *gc.C) {
 	s.waitForWorkerStubCalls(c, expected)
 }
 
+func (s *remoteRelationsSuite) TestPublishRetriesWhenAskedToTryAgain(c *gc.C) {
+	w := s.assertRemoteRelationsWorkers(c)
+	defer workertest.CleanKill(c, w)
+	s.stub.ResetCalls()
+
+	s.stub.SetErrors(params.Error{Code: params.CodeTryAgain})
+
+	unitsWatcher, _ := s.relationsFacade.remoteRelationWatcher("db2:db django:db")
+	unitsWatcher.changes <- params.RemoteRelationChangeEvent{
+		ApplicationToken: "token-django",
+		RelationToken:    "token-db2:db django:db",
+		ChangedUnits: []params.RemoteRelationUnitChange{{
+			UnitId:   1,
+			Settings: map[string]interface{}{"foo": "bar"},
+		}},
+	}
+
+	mac, err := apitesting.NewMacaroon("apimac")
+	c.Assert(err, jc.ErrorIsNil)
+	publish := jujutesting.StubCall{"PublishRelationChange", []interface{}{
+		params.RemoteRelationChangeEvent{
+			ApplicationToken: "token-django",
+			RelationToken:    "token-db2:db django:db",
+			ChangedUnits: []params.RemoteRelationUnitChange{{
+				UnitId:   1,
+				Settings: map[string]interface{}{"foo": "bar"},
+			}},
+			Macaroons:     macaroon.Slice{mac},
+			BakeryVersion: bakery.LatestVersion,
+		},
+	}}
+	s.waitForWorkerStubCalls(c, []jujutesting.StubCall{publish})
+
+	// The change is published again, rather than the worker restarting.
+	s.config.Clock.(*testclock.Clock).WaitAdvance(time.Second, coretesting.LongWait, 1)
+	s.waitForWorkerStubCalls(c, []jujutesting.StubCall{publish, publish})
+	workertest.CheckAlive(c, w)
+}
+
 func (s *remoteRelationsSuite) TestRemoteRelationsChangedConsumes(c *gc.C) {
 	w := s.assertRemoteRelationsWorkers(c)
 	defer workertest.CleanKill(c, w)
@@ -491,6 +491,15 @@ func (w *RemoteStateWatcher) loop(unitTag names.UnitTag) (err error) {
 				return errors.New("expected one hash in config change")
 			}
 			w.configHashChanged(hashes[0])
+			// The config hash also changes when the charm is upgraded
+			// under a branch the unit is tracking, so the charm URL the
+			// unit should run is refreshed. Before the first application
+			// change there is nothing to refresh.
+			if w.charmURLKnown() {
+				if err := w.applicationChanged(); err != nil {
+					return errors.Trace(err)
+				}
+			}
 			observedEvent(&seenConfigChange)
 
 		case hashes, ok := <-trustConfigw.Changes():
@@ -720,6 +729,14 @@ func (w *RemoteStateWatcher) applicationChanged() error {
 	return nil
 }
 
+// charmURLKnown returns true if the charm URL
+// of the unit's application has been observed.
+func (w *RemoteStateWatcher) charmURLKnown() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.current.CharmURL != nil
+}
+
 func (w *RemoteStateWatcher) configHashChanged(value string) {
 	w.mu.Lock()
 	w.current.ConfigHash = value
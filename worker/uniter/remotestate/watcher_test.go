@@ -378,6 +378,21 @@ func (s *WatcherSuite) TestRemoteStateChanged(c *gc.C) {
 	assertOneChange()
 }
 
+func (s *WatcherSuiteIAAS) TestConfigHashChangeRefreshesCharmURL(c *gc.C) {
+	s.signalAll()
+	assertNotifyEvent(c, s.watcher.RemoteStateChanged(), "waiting for remote state change")
+	c.Assert(s.watcher.Snapshot().CharmURL, gc.DeepEquals, charm.MustParseURL("cs:trusty/mysql"))
+
+	// Upgrading the charm in a branch tracked by the unit changes
+	// the config hash, and the charm URL served to the unit.
+	s.st.unit.application.curl = charm.MustParseURL("cs:trusty/mysql-2")
+	s.st.unit.configSettingsWatcher.changes <- []string{"confighash2"}
+	assertNotifyEvent(c, s.watcher.RemoteStateChanged(), "waiting for remote state change")
+	snapshot := s.watcher.Snapshot()
+	c.Assert(snapshot.ConfigHash, gc.Equals, "confighash2")
+	c.Assert(snapshot.CharmURL, gc.DeepEquals, charm.MustParseURL("cs:trusty/mysql-2"))
+}
+
 func (s *WatcherSuite) TestActionsReceived(c *gc.C) {
 	s.signalAll()
 	assertNotifyEvent(c, s.watcher.RemoteStateChanged(), "waiting for remote state change")